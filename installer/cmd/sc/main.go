@@ -24,14 +24,19 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/cmd"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/trace"
 )
 
 func main() {
+	// sc runs the commands it traces itself, see --trace
+	trace.Main()
 	defer glog.Flush()
 
 	c := NewCommand()
-	if err := c.Execute(); err != nil {
-		os.Exit(1)
+	err := c.Execute()
+	cmd.FinishTrace(err)
+	if err != nil {
+		os.Exit(cmd.ExitCode(err))
 	}
 }
 
@@ -46,6 +51,27 @@ Service Broker in a Kubernets Cluster.`,
 
 		// turn off the usage by default on any error
 		SilenceUsage: true,
+
+		// flags not set on the command line are read from the
+		// environment and the config file
+		PersistentPreRunE: func(c *cobra.Command, args []string) error {
+			if err := cmd.SetFlagsFromEnvAndConfig(c); err != nil {
+				return err
+			}
+			if err := cmd.LoadMessages(c); err != nil {
+				return err
+			}
+			if err := cmd.SetKubectlRateLimit(c); err != nil {
+				return err
+			}
+			if err := cmd.SetWorkspaces(c); err != nil {
+				return err
+			}
+			if err := cmd.SetTrace(c); err != nil {
+				return err
+			}
+			return cmd.SetColor(c)
+		},
 	}
 
 	advanced := &cobra.Command{
@@ -61,17 +87,55 @@ Service Broker in a Kubernets Cluster.`,
 	c.AddCommand(
 		cmd.NewCheckDependenciesCmd(),
 		cmd.NewServiceCatalogInstallCmd(),
+		cmd.NewFootprintCmd(),
+		cmd.NewManifestCmd(),
 		cmd.NewServiceCatalogUnInstallCmd(),
+		cmd.NewVerifyInstallCmd(),
+		cmd.NewDoctorCmd(),
+		cmd.NewStatusCmd(),
+		cmd.NewDashboardCmd(),
+		cmd.NewEventsCmd(),
+		cmd.NewRotateCertsCmd(),
+		cmd.NewBackupCmd(),
+		cmd.NewDRRestoreCmd(),
+		cmd.NewHistoryCmd(),
+		cmd.NewRollbackCmd(),
+		cmd.NewAdoptCmd(),
+		cmd.NewPauseCmd(),
+		cmd.NewResumeCmd(),
+		cmd.NewTopCmd(),
+		cmd.NewLoadTestCmd(),
 		cmd.NewAddGCPBrokerCmd(),
 		cmd.NewRemoveGCPBrokerCmd(),
+		cmd.NewBrokerCmd(),
+		cmd.NewEnableGCPAPIsCmd(),
+		cmd.NewMarketplaceCmd(),
+		cmd.NewInstanceCmd(),
+		cmd.NewBindingCmd(),
+		cmd.NewPruneCmd(),
+		cmd.NewUnstickNamespaceCmd(),
+		cmd.NewOnboardNamespaceCmd(),
 		cmd.NewUpdateCmd(),
+		cmd.NewReconfigureCmd(),
+		cmd.NewGenerateCmd(),
 		cmd.NewVersionCmd(),
+		cmd.NewSelfUpdateCmd(),
+		cmd.NewMessagesCmd(),
 		advanced,
 	)
 
 	// Add any globals flags here
+	c.PersistentFlags().String(cmd.ConfigFlagName, "", "YAML file of flag names to values, used for flags set neither on the command line nor with "+cmd.EnvPrefix+"* environment variables")
+	c.PersistentFlags().String(cmd.MessagesFlagName, "", "YAML file of message codes to texts overriding the messages of sc, see `sc messages`")
+	c.PersistentFlags().Float32(cmd.KubectlQPSFlagName, cmd.DefaultKubectlQPS, "Maximum number of kubectl calls started per second, to stay within the API Priority and Fairness limits of the cluster, 0 disables the limit. Each call makes one or more API requests")
+	c.PersistentFlags().Int(cmd.KubectlBurstFlagName, cmd.DefaultKubectlBurst, "Maximum burst of kubectl calls over --"+cmd.KubectlQPSFlagName)
+	c.PersistentFlags().String(cmd.WorkspaceDirFlagName, "", "Directory of the workspaces the operations generate their files in, under <context>/<namespace>. Defaults to sc-workspaces in the temporary directory")
+	c.PersistentFlags().String(cmd.WorkspaceCleanupFlagName, "", "Whether to remove the workspaces of the operations: always, on-success or never. Defaults to the policy of each operation, e.g. install keeps its workspace. Workspaces holding credentials are always removed")
+	c.PersistentFlags().String(cmd.TraceFlagName, "", "File to record the external commands and API calls in, with their duration, exit code and truncated output, as JSON lines. A timing breakdown is printed at the end")
+	c.PersistentFlags().String(cmd.TraceOTLPEndpointFlagName, "", "OpenTelemetry collector, e.g. http://localhost:4318, to export the phases of install, upgrade and uninstall to with OTLP/HTTP, and the --"+cmd.TraceFlagName+" spans if set")
 
 	// add the glog flags
+	c.PersistentFlags().Bool(cmd.NoColorFlagName, false, "Disable the colors of the output. Output which is not to a terminal, or with the NO_COLOR environment variable set, is never colored")
 	c.PersistentFlags().AddGoFlagSet(flag.CommandLine)
 
 	return c
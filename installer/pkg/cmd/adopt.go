@@ -0,0 +1,480 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// adoptedFromAnnotation is set on resources adopted from a Helm
+	// release, its value is "helm/<release>".
+	adoptedFromAnnotation = "servicecatalog.k8s.io/sc-adopted-from"
+
+	// adoptionConfigMap records an adoption in the release namespace.
+	adoptionConfigMap = "sc-adoption"
+)
+
+// helmReleaseKinds are the kinds of resources of the Service Catalog Helm
+// chart that are adopted.
+var helmReleaseKinds = []string{
+	"deployments", "services", "serviceaccounts", "secrets", "configmaps",
+	"roles", "rolebindings", "clusterroles", "clusterrolebindings", "apiservices",
+}
+
+// helmRelease is a Helm release of Service Catalog.
+type helmRelease struct {
+	Name      string
+	Namespace string
+	// Heritage is the Helm component that installed the release, Tiller
+	// for Helm 2 and Helm for Helm 3.
+	Heritage string
+}
+
+func (r *helmRelease) String() string {
+	return fmt.Sprintf("%s/%s", r.Namespace, r.Name)
+}
+
+// apiServiceInfo is the subset of the Service Catalog APIService read to
+// find out who installed Service Catalog and whether it is available.
+type apiServiceInfo struct {
+	Metadata struct {
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		Service struct {
+			Namespace string `json:"namespace"`
+		} `json:"service"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+func NewAdoptCmd() *cobra.Command {
+	var dryRun bool
+	c := &cobra.Command{
+		Use:   "adopt",
+		Short: "takes over a Service Catalog installed with Helm",
+		Long: `takes over the management of a Service Catalog installed with Helm.
+The resources of the Helm release are relabeled as managed by sc, the
+adoption is recorded in the sc-adoption ConfigMap of the release namespace
+and the Helm release records are removed, so that Helm no longer manages
+or deletes the resources. A copy of the records is kept in the workspace
+to restore the release with kubectl apply. sc install in the release
+namespace then replaces the adopted resources with its own.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := adoptHelmCatalog(dryRun); err != nil {
+				messages.Println(messages.AdoptFailed)
+				return err
+			}
+			if dryRun {
+				messages.Println(messages.DryRunSucceeded)
+				return nil
+			}
+			messages.Println(messages.Adopted)
+			return nil
+		},
+	}
+	c.Flags().BoolVar(&dryRun, "dry-run", false, "Only print the resources that would be adopted and the Helm release records that would be removed")
+	return c
+}
+
+// getAPIServiceInfo returns the Service Catalog APIService, or nil if it does
+// not exist.
+func getAPIServiceInfo() (*apiServiceInfo, error) {
+	output, err := kubectlCommand("get", "apiservice", scAPIService, "-o", "json").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "NotFound") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting APIService %s: %s", scAPIService, string(output))
+	}
+	var info apiServiceInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("error unmarshalling APIService %s: %v", scAPIService, err)
+	}
+	return &info, nil
+}
+
+// detectHelmCatalog returns the Helm release Service Catalog was installed
+// with, or nil if it was not installed with Helm.
+func detectHelmCatalog() (*helmRelease, error) {
+	info, err := getAPIServiceInfo()
+	if err != nil || info == nil {
+		return nil, err
+	}
+
+	labels := info.Metadata.Labels
+	heritage := labels["heritage"]
+	if (heritage != "Tiller" && heritage != "Helm") || labels["release"] == "" {
+		return nil, nil
+	}
+	return &helmRelease{
+		Name:      labels["release"],
+		Namespace: info.Spec.Service.Namespace,
+		Heritage:  heritage,
+	}, nil
+}
+
+// checkPreexistingCatalog returns an error if Service Catalog was installed
+// with Helm, or adopted from Helm into another namespace than ns, since
+// installing would create a duplicate control plane. If it was adopted in
+// ns, it returns the adoption, whose resources the install replaces.
+func checkPreexistingCatalog(ns string) (*adoption, error) {
+	info, err := getAPIServiceInfo()
+	if err != nil || info == nil {
+		return nil, err
+	}
+	if from := info.Metadata.Annotations[adoptedFromAnnotation]; from != "" {
+		if adopted := info.Spec.Service.Namespace; adopted != ns {
+			return nil, fmt.Errorf("Service Catalog was adopted from %s and runs in namespace %s, "+
+				"install it with --namespace %s to replace the adopted resources", from, adopted, adopted)
+		}
+		return readAdoption(ns)
+	}
+
+	r, err := detectHelmCatalog()
+	if err != nil {
+		return nil, err
+	}
+	if r != nil {
+		return nil, fmt.Errorf("Service Catalog is already installed by Helm release %s. "+
+			"Run `sc adopt` to manage it with sc instead of installing a duplicate", r)
+	}
+	return nil, nil
+}
+
+// adoptHelmCatalog adopts the Service Catalog Helm release, or only prints
+// what it would change if dryRun is set.
+func adoptHelmCatalog(dryRun bool) (err error) {
+	r, err := detectHelmCatalog()
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return fmt.Errorf("no Service Catalog installed by Helm found")
+	}
+
+	resources, err := helmReleaseResources(r)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		for _, res := range resources {
+			fmt.Printf("would adopt %s\n", res)
+		}
+		records, err := helmReleaseRecords(r)
+		if err != nil {
+			return err
+		}
+		for _, rec := range records {
+			fmt.Printf("would remove the Helm release record %s\n", rec)
+		}
+		return nil
+	}
+	fmt.Printf("adopting Helm release %s\n", r)
+
+	// The copy of the records is all that is left of the release.
+	ws, err := createWorkspace(r.Namespace, "adopt", workspace.Options{Exclusive: true, Cleanup: workspace.Never})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+	backup, err := backupHelmRelease(r, ws.Dir)
+	if err != nil {
+		return err
+	}
+
+	from := "helm/" + r.Name
+	for _, res := range resources {
+		if err := relabelAdopted(res, from); err != nil {
+			return err
+		}
+		fmt.Printf("adopted %s\n", res)
+	}
+
+	if err := recordAdoption(r, resources); err != nil {
+		return err
+	}
+	if err := forgetHelmRelease(r); err != nil {
+		return err
+	}
+	fmt.Printf("kept a copy of the Helm release records in %s\n", backup)
+	return nil
+}
+
+// releaseResource is a resource of a Helm release.
+type releaseResource struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+func (r releaseResource) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s/%s", r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", r.Namespace, r.Kind, r.Name)
+}
+
+// helmReleaseResources lists the resources labeled as part of release r.
+func helmReleaseResources(r *helmRelease) ([]releaseResource, error) {
+	output, err := kubectlCommand("get", strings.Join(helmReleaseKinds, ","),
+		"--all-namespaces", "-l", "release="+r.Name, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the resources of Helm release %s: %s", r, string(output))
+	}
+
+	var list struct {
+		Items []struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the resources of Helm release %s: %v", r, err)
+	}
+
+	var resources []releaseResource
+	for _, i := range list.Items {
+		resources = append(resources, releaseResource{Kind: i.Kind, Name: i.Metadata.Name, Namespace: i.Metadata.Namespace})
+	}
+	return resources, nil
+}
+
+// relabelAdopted marks res as managed by sc instead of Helm.
+func relabelAdopted(res releaseResource, from string) error {
+	target := []string{res.Kind, res.Name}
+	if res.Namespace != "" {
+		target = append(target, "--namespace", res.Namespace)
+	}
+
+	args := append([]string{"label"}, target...)
+	args = append(args, "heritage-", "app.kubernetes.io/managed-by=sc", "--overwrite")
+	if output, err := kubectlCommand(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("error relabeling %s: %s", res, string(output))
+	}
+
+	args = append([]string{"annotate"}, target...)
+	args = append(args, adoptedFromAnnotation+"="+from,
+		"meta.helm.sh/release-name-", "meta.helm.sh/release-namespace-", "--overwrite")
+	if output, err := kubectlCommand(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("error annotating %s: %s", res, string(output))
+	}
+	return nil
+}
+
+// recordAdoption records the adopted release and resources in a ConfigMap in
+// the release namespace.
+func recordAdoption(r *helmRelease, resources []releaseResource) error {
+	var names []string
+	for _, res := range resources {
+		names = append(names, res.String())
+	}
+	cm := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      adoptionConfigMap,
+			"namespace": r.Namespace,
+			"labels":    map[string]string{"app.kubernetes.io/managed-by": "sc"},
+		},
+		"data": map[string]string{
+			"release":   r.Name,
+			"heritage":  r.Heritage,
+			"adoptedAt": time.Now().UTC().Format(time.RFC3339),
+			"resources": strings.Join(names, "\n"),
+		},
+	}
+	b, err := json.Marshal(cm)
+	if err != nil {
+		return err
+	}
+
+	cmd := kubectlCommand("apply", "-f", "-")
+	cmd.Stdin = bytes.NewReader(b)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error recording the adoption: %s", string(output))
+	}
+	return nil
+}
+
+// adoption is a Helm release adopted by sc, as recorded in the adoption
+// ConfigMap.
+type adoption struct {
+	Release   string
+	Resources []releaseResource
+}
+
+// readAdoption returns the adoption recorded in namespace ns, or nil if
+// there is none.
+func readAdoption(ns string) (*adoption, error) {
+	output, err := kubectlCommand("get", "configmap", adoptionConfigMap, "--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "NotFound") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting the adoption record: %s", strings.TrimSpace(string(output)))
+	}
+	var cm struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(output, &cm); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the adoption record: %v", err)
+	}
+	a := &adoption{Release: cm.Data["release"]}
+	for _, line := range strings.Split(cm.Data["resources"], "\n") {
+		parts := strings.Split(strings.TrimSpace(line), "/")
+		switch len(parts) {
+		case 2:
+			a.Resources = append(a.Resources, releaseResource{Kind: parts[0], Name: parts[1]})
+		case 3:
+			a.Resources = append(a.Resources, releaseResource{Namespace: parts[0], Kind: parts[1], Name: parts[2]})
+		}
+	}
+	return a, nil
+}
+
+// replaceAdopted deletes the resources of adoption a in namespace ns the
+// install did not apply the manifests in dir of, e.g. the deployments of
+// the chart named differently from those of sc, and removes the record of
+// the adoption, the install manages the catalog from now on.
+func replaceAdopted(dir, ns string, a *adoption) error {
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		return err
+	}
+	applied := make(map[releaseResource]bool)
+	for _, o := range objs {
+		res := releaseResource{Kind: o.Kind, Name: o.Name}
+		if !o.ClusterScoped() {
+			res.Namespace = o.Namespace
+			if res.Namespace == "" {
+				res.Namespace = ns
+			}
+		}
+		applied[res] = true
+	}
+	for _, res := range a.Resources {
+		// The APIService is left alone even if the install skipped it.
+		if applied[res] || res.Kind == "APIService" {
+			continue
+		}
+		args := []string{"delete", res.Kind, res.Name, "--ignore-not-found"}
+		if res.Namespace != "" {
+			args = append(args, "--namespace", res.Namespace)
+		}
+		if output, err := kubectlCommand(args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("error deleting the adopted %s: %s", res, strings.TrimSpace(string(output)))
+		}
+		fmt.Printf("replaced the adopted %s\n", res)
+	}
+
+	if output, err := kubectlCommand("annotate", "apiservice", scAPIService, adoptedFromAnnotation+"-").CombinedOutput(); err != nil {
+		return fmt.Errorf("error annotating APIService %s: %s", scAPIService, strings.TrimSpace(string(output)))
+	}
+	output, err := kubectlCommand("delete", "configmap", adoptionConfigMap, "--namespace", ns, "--ignore-not-found").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deleting the adoption record: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// helmRecordSelectors returns the kubectl arguments selecting the records
+// Helm keeps of release r. Helm 2 keeps them as ConfigMaps in the Tiller
+// namespace, Helm 3 as Secrets in the release namespace. Older kubectl
+// versions can't select across namespaces, fallback selects the records
+// of the default Tiller namespace instead.
+func helmRecordSelectors(r *helmRelease, fallback bool) []string {
+	switch {
+	case r.Heritage != "Tiller":
+		return []string{"secrets", "--namespace", r.Namespace, "-l", "owner=helm,name=" + r.Name}
+	case fallback:
+		return []string{"configmaps", "--namespace", "kube-system", "-l", "OWNER=TILLER,NAME=" + r.Name}
+	}
+	return []string{"configmaps", "--all-namespaces", "-l", "OWNER=TILLER,NAME=" + r.Name}
+}
+
+// getHelmRecords runs kubectl get with args on the records of release r.
+func getHelmRecords(r *helmRelease, args ...string) ([]byte, error) {
+	output, err := kubectlCommand(append(append([]string{"get"}, helmRecordSelectors(r, false)...), args...)...).Output()
+	if err != nil && r.Heritage == "Tiller" {
+		output, err = kubectlCommand(append(append([]string{"get"}, helmRecordSelectors(r, true)...), args...)...).Output()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting the records of Helm release %s: %v", r, err)
+	}
+	return output, nil
+}
+
+// helmReleaseRecords returns the names of the records of release r.
+func helmReleaseRecords(r *helmRelease) ([]string, error) {
+	output, err := getHelmRecords(r, "-o", "name")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// backupHelmRelease writes the records of release r to a file in dir, only
+// readable by the user since they hold the values of the release, and
+// returns its path.
+func backupHelmRelease(r *helmRelease, dir string) (string, error) {
+	output, err := getHelmRecords(r, "-o", "yaml")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "helm-release-"+r.Name+".yaml")
+	if err := ioutil.WriteFile(path, output, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// forgetHelmRelease deletes the records Helm keeps of release r, so that Helm
+// stops managing the adopted resources.
+func forgetHelmRelease(r *helmRelease) error {
+	output, err := kubectlCommand(append([]string{"delete"}, helmRecordSelectors(r, false)...)...).CombinedOutput()
+	if err != nil && r.Heritage == "Tiller" {
+		output, err = kubectlCommand(append([]string{"delete"}, helmRecordSelectors(r, true)...)...).CombinedOutput()
+	}
+	if err != nil {
+		return fmt.Errorf("error removing the records of Helm release %s: %s", r, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// helmAPIService is the APIService of a Service Catalog Helm 3 release.
+const helmAPIService = `{"metadata": {"labels": {"heritage": "Helm", "release": "catalog"}}, "spec": {"service": {"namespace": "catalog"}}}`
+
+// helmCatalog answers the kubectl calls of adopting the Helm release
+// catalog.
+func helmCatalog(name string, args []string) execx.Response {
+	switch {
+	case args[0] == "get" && args[1] == "apiservice":
+		return execx.Response{Stdout: helmAPIService}
+	case args[0] == "get" && args[1] == "secrets" && containsString(args, "name"):
+		return execx.Response{Stdout: "secret/sh.helm.release.v1.catalog.v1\n"}
+	case args[0] == "get" && args[1] == "secrets":
+		return execx.Response{Stdout: "apiVersion: v1\nkind: List\nitems: []\n"}
+	case args[0] == "get":
+		return execx.Response{Stdout: `{"items": [{"kind": "Deployment", "metadata": {"name": "catalog-apiserver", "namespace": "catalog"}}]}`}
+	}
+	return execx.Response{}
+}
+
+// TestAdoptHelmCatalogDryRun tests that a dry run changes nothing.
+func TestAdoptHelmCatalogDryRun(t *testing.T) {
+	s, restore := stubExecutor(helmCatalog)
+	defer restore()
+
+	if err := adoptHelmCatalog(true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := kubectlCalls(s, "label", "annotate", "apply", "delete"); len(got) > 0 {
+		t.Fatalf("Expected no changes, got %v", got)
+	}
+}
+
+// TestAdoptHelmCatalog tests that the resources are relabeled and the
+// release records are deleted after a copy was kept.
+func TestAdoptHelmCatalog(t *testing.T) {
+	s, restore := stubExecutor(helmCatalog)
+	defer restore()
+	defer tempWorkspaces(t)()
+
+	if err := adoptHelmCatalog(false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{
+		"label Deployment catalog-apiserver --namespace catalog heritage- app.kubernetes.io/managed-by=sc --overwrite",
+		"delete secrets --namespace catalog -l owner=helm,name=catalog",
+	}
+	if got := kubectlCalls(s, "label", "delete"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Calls do not match: got %v; want %v", got, want)
+	}
+	backups, err := filepath.Glob(filepath.Join(workspaces.Root, "*", "catalog", "adopt-*", "helm-release-catalog.yaml"))
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("Expected a copy of the release records, got %v, %v", backups, err)
+	}
+	b, err := ioutil.ReadFile(backups[0])
+	if err != nil || !strings.Contains(string(b), "kind: List") {
+		t.Fatalf("Unexpected copy of the release records %q: %v", b, err)
+	}
+}
+
+// TestCheckPreexistingCatalog tests that installs are refused next to a
+// Helm release or an adoption in another namespace, and replace the
+// adoption in its namespace.
+func TestCheckPreexistingCatalog(t *testing.T) {
+	for _, tc := range []struct {
+		apiService string
+		ns         string
+		adopted    bool
+		err        string
+	}{
+		{helmAPIService, "catalog", false, "sc adopt"},
+		{`{"metadata": {"annotations": {"servicecatalog.k8s.io/sc-adopted-from": "helm/catalog"}}, "spec": {"service": {"namespace": "catalog"}}}`, "service-catalog", false, "--namespace catalog"},
+		{`{"metadata": {"annotations": {"servicecatalog.k8s.io/sc-adopted-from": "helm/catalog"}}, "spec": {"service": {"namespace": "catalog"}}}`, "catalog", true, ""},
+		{`{"metadata": {"labels": {"app.kubernetes.io/managed-by": "sc"}}, "spec": {"service": {"namespace": "catalog"}}}`, "catalog", false, ""},
+	} {
+		_, restore := stubExecutor(func(name string, args []string) execx.Response {
+			if args[1] == "apiservice" {
+				return execx.Response{Stdout: tc.apiService}
+			}
+			return execx.Response{Stdout: `{"data": {"release": "catalog", "resources": "catalog/Deployment/catalog-apiserver\nClusterRole/catalog-controller"}}`}
+		})
+		a, err := checkPreexistingCatalog(tc.ns)
+		restore()
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("%s: expected an error containing %q, got %v", tc.apiService, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.apiService, err)
+		}
+		if (a != nil) != tc.adopted {
+			t.Fatalf("%s: expected adopted %v, got %+v", tc.apiService, tc.adopted, a)
+		}
+		if a != nil && len(a.Resources) != 2 {
+			t.Fatalf("Unexpected adopted resources %+v", a.Resources)
+		}
+	}
+}
+
+// TestReplaceAdopted tests that the adopted resources the install did not
+// apply are deleted, but for the APIService, and the adoption record is
+// removed.
+func TestReplaceAdopted(t *testing.T) {
+	dir := writeManifests(t, map[string]string{
+		"apiserver-deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: apiserver\n  namespace: catalog\n",
+	})
+	defer os.RemoveAll(dir)
+	s, restore := stubExecutor(nil)
+	defer restore()
+
+	a := &adoption{Release: "catalog", Resources: []releaseResource{
+		{Kind: "Deployment", Name: "apiserver", Namespace: "catalog"},
+		{Kind: "Deployment", Name: "catalog-controller-manager", Namespace: "catalog"},
+		{Kind: "APIService", Name: scAPIService},
+	}}
+	if err := replaceAdopted(dir, "catalog", a); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{
+		"delete Deployment catalog-controller-manager --ignore-not-found --namespace catalog",
+		"annotate apiservice " + scAPIService + " servicecatalog.k8s.io/sc-adopted-from-",
+		"delete configmap sc-adoption --namespace catalog --ignore-not-found",
+	}
+	if got := kubectlCalls(s, "delete", "annotate"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Calls do not match: got %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Masterminds/semver"
+)
+
+// apiVersions are the API versions the manifests are rendered with. They
+// depend on the Kubernetes version of the cluster, since newer versions
+// stop serving the beta APIs older versions only have.
+type apiVersions struct {
+	Deployment          string
+	RBAC                string
+	APIRegistration     string
+	PodDisruptionBudget string
+	PriorityClass       string
+	Autoscaling         string
+	CronJob             string
+	StatefulSet         string
+
+	// ArchLabel is the node label of the architecture, for the node
+	// selectors that can't match either label like archAffinity.
+	ArchLabel string
+}
+
+// apiVersionsFor returns the API versions to use for a cluster running
+// Kubernetes version v.
+func apiVersionsFor(v *semver.Version) apiVersions {
+	av := apiVersions{
+		Deployment:          "extensions/v1beta1",
+		RBAC:                "rbac.authorization.k8s.io/v1beta1",
+		APIRegistration:     "apiregistration.k8s.io/v1beta1",
+		PodDisruptionBudget: "policy/v1beta1",
+		PriorityClass:       "scheduling.k8s.io/v1alpha1",
+		Autoscaling:         "autoscaling/v2alpha1",
+		CronJob:             "batch/v2alpha1",
+		StatefulSet:         "apps/v1beta1",
+		ArchLabel:           "beta.kubernetes.io/arch",
+	}
+	if atLeast(v, 1, 8) {
+		av.RBAC = "rbac.authorization.k8s.io/v1"
+		av.CronJob = "batch/v1beta1"
+		av.StatefulSet = "apps/v1beta2"
+		av.Autoscaling = "autoscaling/v2beta1"
+	}
+	if atLeast(v, 1, 9) {
+		av.Deployment = "apps/v1"
+		av.StatefulSet = "apps/v1"
+	}
+	if atLeast(v, 1, 10) {
+		av.APIRegistration = "apiregistration.k8s.io/v1"
+	}
+	if atLeast(v, 1, 11) {
+		av.PriorityClass = "scheduling.k8s.io/v1beta1"
+	}
+	if atLeast(v, 1, 12) {
+		av.Autoscaling = "autoscaling/v2beta2"
+	}
+	if atLeast(v, 1, 14) {
+		av.PriorityClass = "scheduling.k8s.io/v1"
+		av.ArchLabel = "kubernetes.io/arch"
+	}
+	if atLeast(v, 1, 21) {
+		av.PodDisruptionBudget = "policy/v1"
+		av.CronJob = "batch/v1"
+	}
+	if atLeast(v, 1, 23) {
+		av.Autoscaling = "autoscaling/v2"
+	}
+	return av
+}
+
+// atLeast returns whether v is major.minor or later. Pre-release and build
+// suffixes of v, e.g. "-gke.0", are ignored.
+func atLeast(v *semver.Version, major, minor int64) bool {
+	if v.Major() != major {
+		return v.Major() > major
+	}
+	return v.Minor() >= minor
+}
+
+// clusterAPIVersions returns the API versions to use for the cluster
+// kubectl is configured for. If the cluster version cannot be determined,
+// the versions supported by the oldest supported Kubernetes version are
+// returned.
+func clusterAPIVersions() apiVersions {
+	v, err := getServerVersion()
+	if err != nil {
+		// Warn on stderr, generated manifests may be printed to
+		// stdout.
+		fmt.Fprintf(os.Stderr, "WARNING: using API versions for Kubernetes v1.7, could not determine the cluster version: %v\n", err)
+		v = semver.MustParse("1.7.0")
+	}
+	return apiVersionsFor(v)
+}
+
+// installAPIVersions returns the API versions of the install of ic: those
+// of the cluster, but the autoscaling one the cluster was detected to serve.
+func installAPIVersions(ic *InstallConfig) apiVersions {
+	av := clusterAPIVersions()
+	if ic.AutoscalingAPIVersion != "" {
+		av.Autoscaling = ic.AutoscalingAPIVersion
+	}
+	return av
+}
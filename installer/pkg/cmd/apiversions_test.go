@@ -0,0 +1,46 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver"
+)
+
+// TestAPIVersionsFor tests that API versions are selected by the minor
+// version of the cluster, ignoring pre-release suffixes.
+func TestAPIVersionsFor(t *testing.T) {
+	cases := []struct {
+		version string
+		want    apiVersions
+	}{
+		{"v1.7.12", apiVersions{"extensions/v1beta1", "rbac.authorization.k8s.io/v1beta1", "apiregistration.k8s.io/v1beta1", "policy/v1beta1", "scheduling.k8s.io/v1alpha1", "autoscaling/v2alpha1", "batch/v2alpha1", "apps/v1beta1", "beta.kubernetes.io/arch"}},
+		{"v1.8.0", apiVersions{"extensions/v1beta1", "rbac.authorization.k8s.io/v1", "apiregistration.k8s.io/v1beta1", "policy/v1beta1", "scheduling.k8s.io/v1alpha1", "autoscaling/v2beta1", "batch/v1beta1", "apps/v1beta2", "beta.kubernetes.io/arch"}},
+		{"v1.9.7-gke.0", apiVersions{"apps/v1", "rbac.authorization.k8s.io/v1", "apiregistration.k8s.io/v1beta1", "policy/v1beta1", "scheduling.k8s.io/v1alpha1", "autoscaling/v2beta1", "batch/v1beta1", "apps/v1", "beta.kubernetes.io/arch"}},
+		{"v1.10.0-gke.1", apiVersions{"apps/v1", "rbac.authorization.k8s.io/v1", "apiregistration.k8s.io/v1", "policy/v1beta1", "scheduling.k8s.io/v1alpha1", "autoscaling/v2beta1", "batch/v1beta1", "apps/v1", "beta.kubernetes.io/arch"}},
+		{"v1.11.2", apiVersions{"apps/v1", "rbac.authorization.k8s.io/v1", "apiregistration.k8s.io/v1", "policy/v1beta1", "scheduling.k8s.io/v1beta1", "autoscaling/v2beta1", "batch/v1beta1", "apps/v1", "beta.kubernetes.io/arch"}},
+		{"v1.14.0", apiVersions{"apps/v1", "rbac.authorization.k8s.io/v1", "apiregistration.k8s.io/v1", "policy/v1beta1", "scheduling.k8s.io/v1", "autoscaling/v2beta2", "batch/v1beta1", "apps/v1", "kubernetes.io/arch"}},
+		{"v1.21.3", apiVersions{"apps/v1", "rbac.authorization.k8s.io/v1", "apiregistration.k8s.io/v1", "policy/v1", "scheduling.k8s.io/v1", "autoscaling/v2beta2", "batch/v1", "apps/v1", "kubernetes.io/arch"}},
+		{"v1.23.0", apiVersions{"apps/v1", "rbac.authorization.k8s.io/v1", "apiregistration.k8s.io/v1", "policy/v1", "scheduling.k8s.io/v1", "autoscaling/v2", "batch/v1", "apps/v1", "kubernetes.io/arch"}},
+	}
+	for _, c := range cases {
+		if got := apiVersionsFor(semver.MustParse(c.version)); got != c.want {
+			t.Errorf("API versions for %s do not match: got %+v; want %+v", c.version, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/registry"
+)
+
+// Images of etcd. The etcd operator runs the etcd image of the version of
+// the EtcdCluster.
+const (
+	etcdOperatorImage  = "quay.io/coreos/etcd-operator:v0.6.1"
+	etcdClusterVersion = "3.1.8"
+	etcdClusterImage   = "quay.io/coreos/etcd:v" + etcdClusterVersion
+	etcdImage          = "gcr.io/google-containers/etcd:3.0.17"
+)
+
+// architectureRE matches the architectures of the nodes, as in the
+// kubernetes.io/arch and beta.kubernetes.io/arch labels.
+var architectureRE = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// serviceCatalogImage returns the image of the api server and the
+// controller manager of ic.
+func serviceCatalogImage(ic *InstallConfig) string {
+	// The "latest"-tagged Service Catalog version may not be compatible
+	// with the templates, install the version of the catalog version set.
+	image := "gcr.io/gcp-services/service-catalog:v" + catalogVersion(ic)
+	if ic.FIPS {
+		image = fipsImage(image)
+	}
+	return image
+}
+
+// catalogImages returns the images the pods of ic run.
+func catalogImages(ic *InstallConfig) []string {
+	images := []string{serviceCatalogImage(ic)}
+	switch {
+	case ic.SkipEtcd:
+	case ic.IPFamily == ipFamilyIPv6:
+		images = append(images, etcdImage)
+	default:
+		images = append(images, etcdOperatorImage, etcdClusterImage)
+	}
+	if ic.SecretSync {
+		images = append(images, secretSyncData(ic)["SecretSyncImage"].(string))
+	}
+	if ic.EtcdEncryption == encryptionKMS {
+		images = append(images, kmsPluginImage(ic))
+	}
+	if ic.EtcdMaintenance && !containsString(images, etcdMaintenanceImage(ic)) {
+		images = append(images, etcdMaintenanceImage(ic))
+	}
+	if ic.EtcdMaintenance && ic.EtcdMaintenanceBucket != "" {
+		images = append(images, etcdMaintenanceUploadImage)
+	}
+	return images
+}
+
+// nodeArchitectures returns the architectures of the nodes of the cluster,
+// e.g. [amd64 arm64].
+func nodeArchitectures() ([]string, error) {
+	output, err := kubectlCommand("get", "nodes", "-o",
+		`jsonpath={range .items[*]}{.status.nodeInfo.architecture}{"\n"}{end}`).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the nodes: %s", strings.TrimSpace(string(output)))
+	}
+	return uniqueSorted(strings.Fields(string(output))), nil
+}
+
+// planArchitectures returns the architectures to schedule the pods on, given
+// those of the nodes and those each image is built for, or nil if the images
+// run on all the nodes. It fails if no node can run all the images.
+func planArchitectures(nodes []string, images map[string][]string) ([]string, error) {
+	usable := nodes
+	var names []string
+	for image := range images {
+		names = append(names, image)
+	}
+	sort.Strings(names)
+	var unsupported []string
+	for _, image := range names {
+		var kept []string
+		for _, a := range usable {
+			if containsString(images[image], a) {
+				kept = append(kept, a)
+			}
+		}
+		if len(kept) < len(usable) {
+			unsupported = append(unsupported, fmt.Sprintf("   %s is built for %s", image, strings.Join(images[image], ", ")))
+		}
+		usable = kept
+	}
+	if len(usable) == 0 {
+		return nil, messages.Errorf(messages.UnsupportedArchitectures, strings.Join(nodes, ", "), strings.Join(unsupported, "\n"))
+	}
+	if len(usable) == len(nodes) {
+		return nil, nil
+	}
+	return usable, nil
+}
+
+// detectArchitectures checks that the images of ic are built for the
+// architectures of the nodes, e.g. arm64 on GKE Tau T2A or AWS Graviton
+// nodes. On clusters mixing architectures it schedules the pods on the
+// nodes the images run on. The architectures of the images the registries
+// can't tell are not checked.
+func detectArchitectures(ic *InstallConfig) error {
+	if !ic.CheckArchitectures || len(ic.NodeArchitectures) > 0 {
+		return nil
+	}
+	nodes, err := nodeArchitectures()
+	if err != nil || len(nodes) == 0 {
+		// Warn on stderr, generated manifests may be printed to
+		// stdout.
+		fmt.Fprintf(os.Stderr, "WARNING: not checking the architectures of the images, the nodes are unknown: %v\n", err)
+		return nil
+	}
+
+	client := &registry.Client{HTTP: &http.Client{Timeout: 30 * time.Second}}
+	images := make(map[string][]string)
+	for _, image := range catalogImages(ic) {
+		platforms, err := client.Platforms(image)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: not checking the architectures of image %s: %v\n", image, err)
+			continue
+		}
+		var archs []string
+		for _, p := range platforms {
+			if p.OS == "linux" {
+				archs = append(archs, p.Architecture)
+			}
+		}
+		images[image] = uniqueSorted(archs)
+	}
+
+	archs, err := planArchitectures(nodes, images)
+	if err != nil {
+		return err
+	}
+	if archs != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: the nodes run %s, scheduling the service catalog pods on the %s nodes the images are built for\n",
+			strings.Join(nodes, ", "), strings.Join(archs, ", "))
+		ic.NodeArchitectures = archs
+	}
+	return nil
+}
+
+func uniqueSorted(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if !containsString(result, v) {
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestPlanArchitectures tests that the pods are only pinned when some nodes
+// can't run all the images, and that no node being able to fails.
+func TestPlanArchitectures(t *testing.T) {
+	multi := []string{"amd64", "arm64"}
+	for _, tc := range []struct {
+		name   string
+		nodes  []string
+		images map[string][]string
+		want   []string
+	}{
+		{"multi-arch images", multi, map[string][]string{"sc": multi, "etcd": multi}, nil},
+		{"amd64 nodes", []string{"amd64"}, map[string][]string{"sc": multi, "etcd": {"amd64"}}, nil},
+		{"mixed nodes", multi, map[string][]string{"sc": multi, "etcd": {"amd64"}}, []string{"amd64"}},
+		{"unknown images", multi, map[string][]string{}, nil},
+	} {
+		got, err := planArchitectures(tc.nodes, tc.images)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: architectures do not match: got %v; want %v", tc.name, got, tc.want)
+		}
+	}
+
+	_, err := planArchitectures([]string{"arm64"}, map[string][]string{"sc": multi, "etcd": {"amd64"}})
+	if err == nil || !strings.Contains(err.Error(), "etcd is built for amd64") {
+		t.Fatalf("Expected an error naming the amd64-only image, got %v", err)
+	}
+}
+
+// TestCatalogImages tests that the images of the optional sidecars and jobs
+// are checked too.
+func TestCatalogImages(t *testing.T) {
+	ic := &InstallConfig{
+		EtcdEncryption:               encryptionKMS,
+		EtcdEncryptionKMSPluginImage: "registry.example.com/kms-plugin:v1",
+		EtcdMaintenance:              true,
+		EtcdMaintenanceBucket:        "gs://backups",
+	}
+	want := []string{serviceCatalogImage(ic), etcdOperatorImage, etcdClusterImage, "registry.example.com/kms-plugin:v1", etcdMaintenanceUploadImage}
+	if got := catalogImages(ic); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Images do not match: got %v; want %v", got, want)
+	}
+}
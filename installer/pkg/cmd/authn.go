@@ -0,0 +1,95 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// authnCADir is where the secret apiserver-authn-ca is mounted, holding the
+// CA bundles overriding those the api server reads from the
+// extension-apiserver-authentication ConfigMap of kube-system.
+const authnCADir = "/var/run/kubernetes-service-catalog-authn"
+
+// validateAuthn checks the delegated authentication options of ic.
+func validateAuthn(ic *InstallConfig) error {
+	if len(ic.RequestHeaderAllowedNames) > 0 && ic.RequestHeaderCAFile == "" {
+		return fmt.Errorf("--requestheader-allowed-names requires --requestheader-client-ca-file, the api server otherwise reads both from the extension-apiserver-authentication ConfigMap")
+	}
+	if ic.AuthenticationSkipLookup && ic.RequestHeaderCAFile == "" {
+		return fmt.Errorf("--authentication-skip-lookup requires --requestheader-client-ca-file, the api server can't authenticate the requests proxied by the main API server otherwise")
+	}
+	return nil
+}
+
+// authnData returns the template data of the delegated authentication
+// options of ic, reading the CA bundles.
+func authnData(ic *InstallConfig) (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"AuthnCADir":                authnCADir,
+		"RequestHeaderAllowedNames": strings.Join(ic.RequestHeaderAllowedNames, ","),
+		"AuthenticationSkipLookup":  ic.AuthenticationSkipLookup,
+	}
+	for _, ca := range []struct {
+		key, flag, path string
+	}{
+		{"RequestHeaderCA", "--requestheader-client-ca-file", ic.RequestHeaderCAFile},
+		{"ClientCA", "--client-ca-file", ic.ClientCAFile},
+	} {
+		if ca.path == "" {
+			data[ca.key] = ""
+			continue
+		}
+		b, err := readCABundle(ca.path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", ca.flag, err)
+		}
+		data[ca.key] = base64.StdEncoding.EncodeToString(b)
+	}
+	return data, nil
+}
+
+// readCABundle reads the PEM encoded CA certificates in path.
+func readCABundle(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	for rest := b; ; n++ {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("%s holds a %s, not only certificates", path, block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, fmt.Errorf("error parsing certificate %s: %v", path, err)
+		}
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("no PEM certificate found in %s", path)
+	}
+	return b, nil
+}
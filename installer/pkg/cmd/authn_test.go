@@ -0,0 +1,98 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAuthnData tests that the CA bundles are read and checked, and that
+// the api server keeps reading the ConfigMap without them.
+func TestAuthnData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authn")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca1, key, _, _ := testCert(t, nil, time.Now().Add(time.Hour), nil, nil)
+	ca2, _, _, _ := testCert(t, nil, time.Now().Add(time.Hour), nil, nil)
+	bundle := append(ca1, ca2...)
+	for name, b := range map[string][]byte{"bundle.crt": bundle, "key.pem": append(ca1, key...), "empty.crt": nil} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatalf("Unexpected error writing %s: %v", name, err)
+		}
+	}
+
+	ic := validInstallConfig()
+	data, err := authnData(ic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data["RequestHeaderCA"] != "" || data["ClientCA"] != "" {
+		t.Fatalf("Unexpected CA bundles without the flags: %v", data)
+	}
+
+	ic.RequestHeaderCAFile = filepath.Join(dir, "bundle.crt")
+	ic.RequestHeaderAllowedNames = []string{"front-proxy-client", "aggregator"}
+	if data, err = authnData(ic); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, want := data["RequestHeaderCA"], base64.StdEncoding.EncodeToString(bundle); got != want {
+		t.Fatalf("Request header CA does not match: got %v; want %v", got, want)
+	}
+	if got, want := data["RequestHeaderAllowedNames"], "front-proxy-client,aggregator"; got != want {
+		t.Fatalf("Allowed names do not match: got %v; want %v", got, want)
+	}
+
+	for file, want := range map[string]string{
+		"key.pem":   "not only certificates",
+		"empty.crt": "no PEM certificate found",
+		"missing":   "no such file",
+	} {
+		ic.ClientCAFile = filepath.Join(dir, file)
+		if _, err := authnData(ic); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Error of %s does not match: got %v; want %q", file, err, want)
+		}
+	}
+}
+
+// TestValidateAuthn tests that the options only relying on a request header
+// CA require it.
+func TestValidateAuthn(t *testing.T) {
+	ic := validInstallConfig()
+	ic.AuthenticationSkipLookup = true
+	if err := validateAuthn(ic); err == nil || !strings.Contains(err.Error(), "--authentication-skip-lookup requires") {
+		t.Fatalf("Error does not match: got %v; want --authentication-skip-lookup requires ...", err)
+	}
+	ic.AuthenticationSkipLookup = false
+	ic.RequestHeaderAllowedNames = []string{"front-proxy-client"}
+	if err := validateAuthn(ic); err == nil || !strings.Contains(err.Error(), "--requestheader-allowed-names requires") {
+		t.Fatalf("Error does not match: got %v; want --requestheader-allowed-names requires ...", err)
+	}
+	ic.AuthenticationSkipLookup = true
+	ic.RequestHeaderCAFile = "front-proxy-ca.crt"
+	if err := validateAuthn(ic); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
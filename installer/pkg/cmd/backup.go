@@ -0,0 +1,467 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// backupSnapshotPath is where the snapshot of a backup is saved in the
+	// etcd pod before it is uploaded.
+	backupSnapshotPath = "/tmp/sc-backup-snapshot.db"
+
+	// backupPrefix starts the object names of the backups sc creates.
+	backupPrefix = "sc-backup-"
+
+	// backupCertsExt replaces the .db extension of a backup in the name of
+	// the object holding its certificates.
+	backupCertsExt = ".certs"
+
+	// The metadata of the backup objects.
+	backupCatalogVersionKey = "sc-catalog-version"
+	backupEtcdVersionKey    = "sc-etcd-version"
+	backupNamespaceKey      = "sc-namespace"
+	backupCertsKMSKeyKey    = "sc-certs-kms-key"
+)
+
+// backupConfig contains the backup configuration.
+type backupConfig struct {
+	Namespace string
+
+	// GCSBucket is the gs://<bucket>[/<path>] URL the backups are
+	// uploaded to.
+	GCSBucket string
+
+	// Keep is the number of newest backups kept after creating one, 0
+	// keeps all.
+	Keep int
+
+	// MaxAge is the age of the backups deleted after creating one, 0
+	// keeps all.
+	MaxAge time.Duration
+
+	// KMSKey is the Cloud KMS key the certificates of the backup are
+	// encrypted with, if set.
+	KMSKey string
+
+	// AllowPlaintextKeys uploads the private keys of the backup
+	// unencrypted when KMSKey is not set.
+	AllowPlaintextKeys bool
+}
+
+// backupCerts are the certificates and keys of the api server, saved next
+// to the snapshot so that a restored installation keeps the APIService
+// caBundle and the certificates its clients trust.
+type backupCerts struct {
+	// CABundle is the caBundle of the APIService.
+	CABundle []byte `json:"caBundle"`
+
+	// Cert and Key are the serving certificate and key of the api server.
+	Cert []byte `json:"cert"`
+	Key  []byte `json:"key"`
+
+	// StoredCA is the data of the secret of the CA stored with
+	// --store-ca-key, if any.
+	StoredCA map[string][]byte `json:"storedCA,omitempty"`
+
+	// EncryptionConfig is the encryption configuration of
+	// --etcd-encryption, if any, with the keys the snapshot needs.
+	EncryptionConfig []byte `json:"encryptionConfig,omitempty"`
+}
+
+// backup is an etcd snapshot in GCS, created by sc backup create or by the
+// etcd maintenance CronJob.
+type backup struct {
+	Name           string
+	Created        time.Time
+	Size           int64
+	CatalogVersion string
+	EtcdVersion    string
+}
+
+func NewBackupCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "backup",
+		Short: "commands for the etcd backups of Service Catalog",
+	}
+	c.AddCommand(newBackupCreateCmd(), newBackupListCmd())
+	return c
+}
+
+func newBackupCreateCmd() *cobra.Command {
+	bc := &backupConfig{}
+	c := &cobra.Command{
+		Use:   "create",
+		Short: "backs up the etcd of Service Catalog to GCS",
+		Long: `saves a snapshot of the etcd of Service Catalog and uploads it to
+--gcs-bucket with the Application Default Credentials, recording the
+Service Catalog and etcd versions, along with the api server certificates
+and keys, encrypted with --kms-key. Without --kms-key the private keys are
+only uploaded with --allow-plaintext-keys, and the keys of
+--etcd-encryption never. Then the backups beyond the
+--keep newest ones and those older than --max-age are deleted.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return createBackup(bc)
+		},
+	}
+	addBackupFlags(c, bc)
+	c.Flags().IntVar(&bc.Keep, "keep", 0, "Number of newest backups to keep, 0 keeps all")
+	c.Flags().DurationVar(&bc.MaxAge, "max-age", 0, "Delete the backups older than this, e.g. 720h, 0 keeps all")
+	c.Flags().StringVar(&bc.KMSKey, "kms-key", "", "Cloud KMS key (projects/.../cryptoKeys/...) the certificates and keys of the backup are encrypted with")
+	c.Flags().BoolVar(&bc.AllowPlaintextKeys, "allow-plaintext-keys", false, "Upload the private keys of the CA and the api server unencrypted if --kms-key is not set")
+	return c
+}
+
+func newBackupListCmd() *cobra.Command {
+	bc := &backupConfig{}
+	c := &cobra.Command{
+		Use:   "list",
+		Short: "lists the etcd backups of Service Catalog in GCS",
+		Long: `lists the etcd snapshots in --gcs-bucket, newest first, with their
+creation time, size and the Service Catalog and etcd versions they were
+taken of. The snapshots of --enable-etcd-maintenance are listed too.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listBackups(bc)
+		},
+	}
+	addBackupFlags(c, bc)
+	return c
+}
+
+func addBackupFlags(c *cobra.Command, bc *backupConfig) {
+	c.Flags().StringVar(&bc.Namespace, "namespace", "service-catalog", "Namespace Service Catalog is installed in")
+	c.Flags().StringVar(&bc.GCSBucket, "gcs-bucket", "", "gs://<bucket>[/<path>] the backups are stored in")
+	addGCPContextFlags(c)
+}
+
+func createBackup(bc *backupConfig) error {
+	if bc.Keep < 0 || bc.MaxAge < 0 {
+		return fmt.Errorf("--keep and --max-age must not be negative")
+	}
+	bucket, prefix, err := gcp.ParseGCSURL(bc.GCSBucket)
+	if err != nil {
+		return fmt.Errorf("--gcs-bucket: %v", err)
+	}
+	if bc.KMSKey != "" {
+		if err := gcp.ValidateKMSKeyName(bc.KMSKey); err != nil {
+			return err
+		}
+	}
+	client, err := gcpClient()
+	if err != nil {
+		return err
+	}
+
+	certs, err := readBackupCerts(bc.Namespace)
+	if err != nil {
+		return err
+	}
+	if err := checkBackupKeys(certs, bc); err != nil {
+		return err
+	}
+	d, err := getAPIServerDeployment(bc.Namespace)
+	if err != nil {
+		return err
+	}
+	servers, _, err := apiServerEtcdServers(d)
+	if err != nil {
+		return err
+	}
+	member, err := currentEtcdMember(bc.Namespace, servers)
+	if err != nil {
+		return err
+	}
+	catalogVersion, err := apiServerVersion(bc.Namespace)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("saving a snapshot of etcd %s\n", member.Pod)
+	if err := etcdExec(bc.Namespace, member.Pod, "etcdctl snapshot save "+backupSnapshotPath); err != nil {
+		return err
+	}
+	defer func() {
+		if err := etcdExec(bc.Namespace, member.Pod, "rm -f "+backupSnapshotPath); err != nil {
+			fmt.Printf("WARNING: %v\n", err)
+		}
+	}()
+
+	name := prefix + backupName(time.Now())
+	metadata := map[string]string{
+		backupCatalogVersionKey: catalogVersion,
+		backupEtcdVersionKey:    member.Version,
+		backupNamespaceKey:      bc.Namespace,
+	}
+	if bc.KMSKey != "" {
+		metadata[backupCertsKMSKeyKey] = bc.KMSKey
+	}
+	if err := uploadBackupCerts(client, bucket, backupCertsName(name), certs, bc.KMSKey); err != nil {
+		return err
+	}
+	o, err := uploadEtcdSnapshot(client, bc.Namespace, member.Pod, bucket, name, metadata)
+	if err != nil {
+		if derr := gcp.DeleteObject(client, bucket, backupCertsName(name)); derr != nil {
+			fmt.Printf("WARNING: %v\n", derr)
+		}
+		return err
+	}
+	fmt.Printf("backed up etcd %s of Service Catalog %s to gs://%s/%s (%s)\n",
+		member.Version, catalogVersion, bucket, o.Name, formatMemory(float64(o.Size)))
+
+	if bc.Keep == 0 && bc.MaxAge == 0 {
+		return nil
+	}
+	objects, err := gcp.ListObjects(client, bucket, prefix)
+	if err != nil {
+		return err
+	}
+	for _, b := range expiredBackups(backupsIn(objects, prefix), bc.Keep, bc.MaxAge, time.Now()) {
+		if b.Name == o.Name {
+			continue
+		}
+		if err := deleteBackup(client, bucket, b.Name); err != nil {
+			return err
+		}
+		fmt.Printf("deleted backup gs://%s/%s of %s\n", bucket, b.Name, b.Created.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// backupName returns the object name, without prefix, of a backup created
+// at t.
+func backupName(t time.Time) string {
+	return backupPrefix + t.UTC().Format("20060102-150405") + ".db"
+}
+
+// backupCertsName returns the name of the object holding the certificates
+// of the backup name.
+func backupCertsName(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name)) + backupCertsExt
+}
+
+// deleteBackup deletes the backup name of bucket and its certificates.
+func deleteBackup(client *http.Client, bucket, name string) error {
+	if err := gcp.DeleteObject(client, bucket, name); err != nil {
+		return err
+	}
+	return gcp.DeleteObject(client, bucket, backupCertsName(name))
+}
+
+// readBackupCerts reads the certificates of the api server installed in
+// namespace ns.
+func readBackupCerts(ns string) (*backupCerts, error) {
+	data, found, err := getSecretData(ns, apiServerCertSecretName)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("Service Catalog is not installed in namespace %s, secret %s does not exist", ns, apiServerCertSecretName)
+	}
+	output, err := kubectlCommand("get", "apiservice", scAPIService,
+		"-o", "jsonpath={.spec.caBundle}").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting APIService %s: %s", scAPIService, strings.TrimSpace(string(output)))
+	}
+	caBundle, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding caBundle of APIService %s: %v", scAPIService, err)
+	}
+	ca, _, err := getSecretData(ns, caSecretName)
+	if err != nil {
+		return nil, err
+	}
+	encryption, _, err := getSecretData(ns, encryptionConfigSecretName)
+	if err != nil {
+		return nil, err
+	}
+	return &backupCerts{CABundle: caBundle, Cert: data["tls.crt"], Key: data["tls.key"], StoredCA: ca, EncryptionConfig: encryption[encryptionConfigKey]}, nil
+}
+
+// checkBackupKeys checks that the keys of certs are uploaded encrypted:
+// the private keys of the CA and the api server are only backed up without
+// --kms-key if --allow-plaintext-keys is set, and the encryption keys of the
+// etcd data never, they would make the encryption at rest pointless.
+func checkBackupKeys(certs *backupCerts, bc *backupConfig) error {
+	if bc.KMSKey != "" {
+		return nil
+	}
+	if len(certs.EncryptionConfig) > 0 {
+		return fmt.Errorf("the etcd data of Service Catalog in namespace %s is encrypted, use --kms-key to back up its encryption keys encrypted with Cloud KMS", bc.Namespace)
+	}
+	if !bc.AllowPlaintextKeys {
+		return fmt.Errorf("the backup includes the private keys of the CA and the api server, use --kms-key to encrypt them with Cloud KMS, or --allow-plaintext-keys to upload them unencrypted")
+	}
+	fmt.Println("WARNING: uploading the private keys of the CA and the api server unencrypted")
+	return nil
+}
+
+// uploadBackupCerts uploads certs to the object name of bucket, encrypted
+// with the Cloud KMS key kmsKey if set.
+func uploadBackupCerts(client *http.Client, bucket, name string, certs *backupCerts, kmsKey string) error {
+	b, err := json.Marshal(certs)
+	if err != nil {
+		return err
+	}
+	if kmsKey != "" {
+		if b, err = gcp.KMSEncrypt(client, kmsKey, b); err != nil {
+			return fmt.Errorf("error encrypting the certificates: %v", err)
+		}
+	}
+	_, err = gcp.UploadObject(client, bucket, name, nil, bytes.NewReader(b))
+	return err
+}
+
+// apiServerVersion returns the Service Catalog version of the api server
+// in namespace ns, from its image tag.
+func apiServerVersion(ns string) (string, error) {
+	output, err := kubectlCommand("get", "deployment", "apiserver", "--namespace", ns,
+		"-o", `jsonpath={.spec.template.spec.containers[?(@.name=="apiserver")].image}`).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting api server image: %s", strings.TrimSpace(string(output)))
+	}
+	image := strings.TrimSpace(string(output))
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return "", fmt.Errorf("api server image %q has no version tag", image)
+	}
+	return strings.TrimPrefix(image[i+1:], "v"), nil
+}
+
+// uploadEtcdSnapshot streams the snapshot saved in the etcd container of
+// pod to the object name of bucket.
+func uploadEtcdSnapshot(client *http.Client, ns, pod, bucket, name string, metadata map[string]string) (*gcp.Object, error) {
+	cmd := kubectlCommand("exec", pod, "--namespace", ns, "-c", "etcd", "--", "cat", backupSnapshotPath)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error reading the etcd snapshot: %v", err)
+	}
+	o, err := gcp.UploadObject(client, bucket, name, metadata, stdout)
+	if err != nil {
+		execx.Kill(cmd)
+		cmd.Wait()
+		return nil, err
+	}
+	if err := cmd.Wait(); err != nil {
+		// The upload ended early with a truncated snapshot.
+		if derr := gcp.DeleteObject(client, bucket, name); derr != nil {
+			fmt.Printf("WARNING: %v\n", derr)
+		}
+		return nil, fmt.Errorf("error reading the etcd snapshot: %s", strings.TrimSpace(stderr.String()))
+	}
+	return o, nil
+}
+
+// backupNameRE matches the names of the snapshots of sc backup create and
+// of the etcd maintenance CronJob.
+var backupNameRE = regexp.MustCompile(`^(` + backupPrefix + `|etcd-)[0-9]{8}-[0-9]{6}\.db$`)
+
+// backupsIn returns the etcd snapshots among objects, those directly below
+// prefix named by sc backup create or the etcd maintenance CronJob, newest
+// first. Retention only deletes those, never other objects of the bucket.
+func backupsIn(objects []gcp.Object, prefix string) []backup {
+	var backups []backup
+	for _, o := range objects {
+		if !strings.HasPrefix(o.Name, prefix) || !backupNameRE.MatchString(o.Name[len(prefix):]) {
+			continue
+		}
+		backups = append(backups, backup{
+			Name:           o.Name,
+			Created:        o.Created,
+			Size:           o.Size,
+			CatalogVersion: o.Metadata[backupCatalogVersionKey],
+			EtcdVersion:    o.Metadata[backupEtcdVersionKey],
+		})
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Created.After(backups[j].Created)
+	})
+	return backups
+}
+
+// expiredBackups returns the backups, newest first, beyond the keep newest
+// ones or older than maxAge at now. A zero keep or maxAge disables that
+// limit.
+func expiredBackups(backups []backup, keep int, maxAge time.Duration, now time.Time) []backup {
+	var expired []backup
+	for i, b := range backups {
+		if (keep > 0 && i >= keep) || (maxAge > 0 && now.Sub(b.Created) > maxAge) {
+			expired = append(expired, b)
+		}
+	}
+	return expired
+}
+
+func listBackups(bc *backupConfig) error {
+	bucket, prefix, err := gcp.ParseGCSURL(bc.GCSBucket)
+	if err != nil {
+		return fmt.Errorf("--gcs-bucket: %v", err)
+	}
+	client, err := gcpClient()
+	if err != nil {
+		return err
+	}
+	objects, err := gcp.ListObjects(client, bucket, prefix)
+	if err != nil {
+		return err
+	}
+	backups := backupsIn(objects, prefix)
+	if len(backups) == 0 {
+		fmt.Printf("no backups found in gs://%s/%s\n", bucket, prefix)
+		return nil
+	}
+	printBackups(os.Stdout, backups)
+	return nil
+}
+
+func printBackups(out io.Writer, backups []backup) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCREATED\tSIZE\tCATALOG\tETCD")
+	for _, b := range backups {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", path.Base(b.Name), b.Created.Format(time.RFC3339),
+			formatMemory(float64(b.Size)), orDash(b.CatalogVersion), orDash(b.EtcdVersion))
+	}
+	w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+)
+
+var backupNow = time.Date(2018, 6, 30, 12, 0, 0, 0, time.UTC)
+
+// TestBackupsIn tests that only the snapshots named by sc directly below the
+// prefix are listed, newest first, with the versions of their metadata.
+func TestBackupsIn(t *testing.T) {
+	objects := []gcp.Object{
+		{Name: "sc/sc-backup-20180628-120000.db", Size: 1 << 20, Created: backupNow.Add(-48 * time.Hour),
+			Metadata: map[string]string{backupCatalogVersionKey: "0.1.11-gke.0", backupEtcdVersionKey: "3.2.24"}},
+		{Name: "sc/etcd-20180630-030000.db", Created: backupNow.Add(-9 * time.Hour)},
+		{Name: "sc/README.txt", Created: backupNow},
+		{Name: "sc/customers.db", Created: backupNow},
+		{Name: "sc/sc-backup-latest.db", Created: backupNow},
+		{Name: "sc/etcd-20180630-030000.db.bak", Created: backupNow},
+		{Name: "sc/old/sc-backup-20170101-000000.db", Created: backupNow},
+	}
+	var got []string
+	for _, b := range backupsIn(objects, "sc/") {
+		got = append(got, b.Name)
+	}
+	want := []string{"sc/etcd-20180630-030000.db", "sc/sc-backup-20180628-120000.db"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Backups do not match: got %v; want %v", got, want)
+	}
+
+	var out bytes.Buffer
+	printBackups(&out, backupsIn(objects, "sc/"))
+	for _, s := range []string{"etcd-20180630-030000.db       2018-06-30T03:00:00Z  -     -             -", "sc-backup-20180628-120000.db  2018-06-28T12:00:00Z  1Mi   0.1.11-gke.0  3.2.24"} {
+		if !strings.Contains(out.String(), s) {
+			t.Fatalf("Listing does not contain %q:\n%s", s, out.String())
+		}
+	}
+}
+
+// TestExpiredBackups tests the count and age retention limits.
+func TestExpiredBackups(t *testing.T) {
+	var backups []backup
+	for i := 0; i < 4; i++ {
+		backups = append(backups, backup{Name: backupName(backupNow.Add(-time.Duration(i) * 24 * time.Hour)), Created: backupNow.Add(-time.Duration(i) * 24 * time.Hour)})
+	}
+	for _, tc := range []struct {
+		name   string
+		keep   int
+		maxAge time.Duration
+		want   []string
+	}{
+		{"no limits", 0, 0, nil},
+		{"keep", 2, 0, []string{"sc-backup-20180628-120000.db", "sc-backup-20180627-120000.db"}},
+		{"max age", 0, 36 * time.Hour, []string{"sc-backup-20180628-120000.db", "sc-backup-20180627-120000.db"}},
+		{"both", 3, 60 * time.Hour, []string{"sc-backup-20180627-120000.db"}},
+		{"keep more than exist", 10, 0, nil},
+	} {
+		var got []string
+		for _, b := range expiredBackups(backups, tc.keep, tc.maxAge, backupNow) {
+			got = append(got, b.Name)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: expired backups do not match: got %v; want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestCheckBackupKeys tests that the etcd encryption keys are only backed up
+// encrypted with Cloud KMS, and the private keys only unencrypted when
+// allowed.
+func TestCheckBackupKeys(t *testing.T) {
+	encrypted := &backupCerts{EncryptionConfig: []byte("config")}
+	if err := checkBackupKeys(encrypted, &backupConfig{Namespace: "catalog"}); err == nil || !strings.Contains(err.Error(), "--kms-key") {
+		t.Fatalf("Expected an error requiring --kms-key, got %v", err)
+	}
+	if err := checkBackupKeys(encrypted, &backupConfig{Namespace: "catalog", KMSKey: "projects/p/locations/global/keyRings/r/cryptoKeys/k"}); err != nil {
+		t.Fatalf("Unexpected error with --kms-key: %v", err)
+	}
+	if err := checkBackupKeys(encrypted, &backupConfig{Namespace: "catalog", AllowPlaintextKeys: true}); err == nil || !strings.Contains(err.Error(), "--kms-key") {
+		t.Fatalf("Expected an error requiring --kms-key with --allow-plaintext-keys, got %v", err)
+	}
+
+	plain := &backupCerts{Key: []byte("key")}
+	if err := checkBackupKeys(plain, &backupConfig{Namespace: "catalog"}); err == nil || !strings.Contains(err.Error(), "--allow-plaintext-keys") {
+		t.Fatalf("Expected an error requiring --kms-key or --allow-plaintext-keys, got %v", err)
+	}
+	if err := checkBackupKeys(plain, &backupConfig{Namespace: "catalog", AllowPlaintextKeys: true}); err != nil {
+		t.Fatalf("Unexpected error with --allow-plaintext-keys: %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// bindingMountDir is the directory binding secrets are mounted under, each
+// in a subdirectory named after the binding.
+const bindingMountDir = "/var/run/secrets/bindings"
+
+// bindingInjectConfig contains the binding inject configuration.
+type bindingInjectConfig struct {
+	Namespace  string
+	Deployment string
+	Binding    string
+
+	// Container is the container to inject the binding into, all the
+	// containers if empty.
+	Container string
+
+	// Mode is env, volume or both.
+	Mode string
+}
+
+func NewBindingCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "binding",
+		Short: "commands for ServiceBindings",
+	}
+	c.AddCommand(newBindingInjectCmd())
+	return c
+}
+
+func newBindingInjectCmd() *cobra.Command {
+	bc := &bindingInjectConfig{}
+	c := &cobra.Command{
+		Use:   "inject",
+		Short: "makes the secret of a ServiceBinding available to a Deployment",
+		Long: `makes the secret of a ServiceBinding available to the containers of a
+Deployment, as environment variables prefixed with the binding name, e.g.
+MY_DB_PASSWORD for the password key of binding my-db, and/or as files in
+` + bindingMountDir + `/<binding>. Running it again changes nothing.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := injectBinding(bc); err != nil {
+				messages.Println(messages.BindingInjectFailed)
+				return err
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVar(&bc.Namespace, "namespace", "default", "Namespace of the Deployment and the ServiceBinding")
+	c.Flags().StringVar(&bc.Deployment, "deployment", "", "Deployment to inject the binding into")
+	c.Flags().StringVar(&bc.Binding, "binding", "", "ServiceBinding to inject")
+	c.Flags().StringVar(&bc.Container, "container", "", "Container to inject the binding into, defaults to all containers")
+	c.Flags().StringVar(&bc.Mode, "mode", "env", "How to inject the binding: env, volume or both")
+	return c
+}
+
+func injectBinding(bc *bindingInjectConfig) error {
+	if bc.Deployment == "" || bc.Binding == "" {
+		return fmt.Errorf("--deployment and --binding are required")
+	}
+	if bc.Mode != "env" && bc.Mode != "volume" && bc.Mode != "both" {
+		return fmt.Errorf("invalid --mode %q: expected env, volume or both", bc.Mode)
+	}
+
+	secret, err := bindingSecretName(bc.Namespace, bc.Binding)
+	if err != nil {
+		return err
+	}
+
+	output, err := kubectlCommand("get", "deployment", bc.Deployment,
+		"--namespace", bc.Namespace, "-o", "json").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error getting deployment %s/%s: %s", bc.Namespace, bc.Deployment, strings.TrimSpace(string(output)))
+	}
+	var d struct {
+		Spec struct {
+			Template struct {
+				Spec map[string]interface{} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(output, &d); err != nil {
+		return fmt.Errorf("error unmarshalling deployment %s/%s: %v", bc.Namespace, bc.Deployment, err)
+	}
+
+	podSpec := d.Spec.Template.Spec
+	changed, err := injectBindingSecret(podSpec, bc, secret)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Printf("binding %s is already injected into deployment %s\n", bc.Binding, bc.Deployment)
+		return nil
+	}
+
+	// A merge patch replaces the lists, which were read with their
+	// current content.
+	specPatch := map[string]interface{}{"containers": podSpec["containers"]}
+	if volumes, ok := podSpec["volumes"]; ok {
+		specPatch["volumes"] = volumes
+	}
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{"spec": specPatch},
+		},
+	}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	output, err = kubectlCommand("patch", "deployment", bc.Deployment,
+		"--namespace", bc.Namespace, "--type", "merge", "-p", string(b)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error patching deployment %s/%s: %s", bc.Namespace, bc.Deployment, strings.TrimSpace(string(output)))
+	}
+	fmt.Printf("injected secret %s of binding %s into deployment %s\n", secret, bc.Binding, bc.Deployment)
+	return nil
+}
+
+// bindingSecretName returns the name of the secret of a ServiceBinding, or
+// an error if the binding is not ready.
+func bindingSecretName(ns, name string) (string, error) {
+	output, err := kubectlCommand("get", "servicebindings.servicecatalog.k8s.io", name,
+		"--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting binding %s/%s: %s", ns, name, strings.TrimSpace(string(output)))
+	}
+	var sb struct {
+		Spec struct {
+			SecretName string `json:"secretName"`
+		} `json:"spec"`
+		Status struct {
+			Conditions []struct {
+				Type    string `json:"type"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"conditions"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(output, &sb); err != nil {
+		return "", fmt.Errorf("error unmarshalling binding %s/%s: %v", ns, name, err)
+	}
+
+	for _, c := range sb.Status.Conditions {
+		if c.Type == "Ready" && c.Status != "True" {
+			return "", fmt.Errorf("binding %s/%s is not ready: %s", ns, name, c.Message)
+		}
+	}
+	if sb.Spec.SecretName == "" {
+		return name, nil
+	}
+	return sb.Spec.SecretName, nil
+}
+
+var nonEnvChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// bindingEnvPrefix returns the prefix of the environment variables of a
+// binding, e.g. MY_DB_ for my-db.
+func bindingEnvPrefix(binding string) string {
+	return nonEnvChars.ReplaceAllString(strings.ToUpper(binding), "_") + "_"
+}
+
+// injectBindingSecret adds secret to the containers of podSpec as
+// configured by bc. It returns false if podSpec already had it.
+func injectBindingSecret(podSpec map[string]interface{}, bc *bindingInjectConfig, secret string) (bool, error) {
+	containers, _ := podSpec["containers"].([]interface{})
+	volumeName := "binding-" + bc.Binding
+	env := bc.Mode == "env" || bc.Mode == "both"
+	volume := bc.Mode == "volume" || bc.Mode == "both"
+
+	changed, found := false, false
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok || (bc.Container != "" && container["name"] != bc.Container) {
+			continue
+		}
+		found = true
+
+		if env {
+			envFrom := map[string]interface{}{
+				"prefix":    bindingEnvPrefix(bc.Binding),
+				"secretRef": map[string]interface{}{"name": secret},
+			}
+			if appendUnique(container, "envFrom", envFrom, func(e map[string]interface{}) bool {
+				ref, _ := e["secretRef"].(map[string]interface{})
+				return ref != nil && ref["name"] == secret
+			}) {
+				changed = true
+			}
+		}
+		if volume {
+			mount := map[string]interface{}{
+				"name":      volumeName,
+				"mountPath": bindingMountDir + "/" + bc.Binding,
+				"readOnly":  true,
+			}
+			if appendUnique(container, "volumeMounts", mount, func(m map[string]interface{}) bool {
+				return m["name"] == volumeName
+			}) {
+				changed = true
+			}
+		}
+	}
+	if !found {
+		if bc.Container != "" {
+			return false, fmt.Errorf("deployment %s has no container %s", bc.Deployment, bc.Container)
+		}
+		return false, fmt.Errorf("deployment %s has no containers", bc.Deployment)
+	}
+
+	if volume {
+		v := map[string]interface{}{
+			"name":   volumeName,
+			"secret": map[string]interface{}{"secretName": secret},
+		}
+		if appendUnique(podSpec, "volumes", v, func(v map[string]interface{}) bool {
+			return v["name"] == volumeName
+		}) {
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// appendUnique appends item to the list obj[key] unless an element matches
+// exists. It returns whether item was appended.
+func appendUnique(obj map[string]interface{}, key string, item map[string]interface{}, exists func(map[string]interface{}) bool) bool {
+	list, _ := obj[key].([]interface{})
+	for _, e := range list {
+		if m, ok := e.(map[string]interface{}); ok && exists(m) {
+			return false
+		}
+	}
+	obj[key] = append(list, item)
+	return true
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+)
+
+// gatekeeperMutationAPIVersion is the API version of the OPA Gatekeeper
+// mutators, which are only served with mutation enabled.
+const gatekeeperMutationAPIVersion = "mutations.gatekeeper.sh/v1beta1"
+
+// bindingSecretPolicy sets the secretTransforms of the ServiceBindings,
+// shaping the secrets holding their credentials. It is read from the file
+// passed with --binding-secret-policy, e.g.
+//
+//	rules:
+//	- name: env
+//	  transforms:
+//	  - prefixKeys: {prefix: DB_, keys: [host, port, username, password]}
+//	  - removeKey: {key: privateKeyData}
+//	- name: payments
+//	  namespaceSelector:
+//	    team: payments
+//	  override: true
+//	  transforms:
+//	  - renameKey: {from: password, to: secret}
+//
+// Rules without namespaces apply to the bindings of every namespace.
+type bindingSecretPolicy struct {
+	Rules []bindingSecretRule `json:"rules"`
+}
+
+// bindingSecretRule sets the secretTransforms of the bindings in the
+// matching namespaces.
+type bindingSecretRule struct {
+	// Name names the Gatekeeper mutator of the rule.
+	Name string `json:"name"`
+
+	// Namespaces are the namespaces the rule applies to.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector selects the namespaces the rule applies to by
+	// label, e.g. the team owning them.
+	NamespaceSelector map[string]string `json:"namespaceSelector,omitempty"`
+
+	// Override replaces the secretTransforms set by the bindings, instead
+	// of only defaulting those of the bindings setting none.
+	Override bool `json:"override,omitempty"`
+
+	// Transforms are applied to the credentials in order.
+	Transforms []secretTransform `json:"transforms"`
+}
+
+// secretTransform is a ServiceBinding secretTransform, or prefixKeys which
+// renames each of Keys to Prefix followed by the key. It sets one field.
+type secretTransform struct {
+	RenameKey   *renameKeyTransform   `json:"renameKey,omitempty"`
+	AddKey      *addKeyTransform      `json:"addKey,omitempty"`
+	AddKeysFrom *addKeysFromTransform `json:"addKeysFrom,omitempty"`
+	RemoveKey   *removeKeyTransform   `json:"removeKey,omitempty"`
+	PrefixKeys  *prefixKeysTransform  `json:"prefixKeys,omitempty"`
+}
+
+type renameKeyTransform struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type addKeyTransform struct {
+	Key                string `json:"key"`
+	StringValue        string `json:"stringValue,omitempty"`
+	JSONPathExpression string `json:"jsonPathExpression,omitempty"`
+}
+
+type addKeysFromTransform struct {
+	SecretRef struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"secretRef"`
+}
+
+type removeKeyTransform struct {
+	Key string `json:"key"`
+}
+
+type prefixKeysTransform struct {
+	Prefix string   `json:"prefix"`
+	Keys   []string `json:"keys"`
+}
+
+// loadBindingSecretPolicy reads and validates the binding secret policy in
+// file.
+func loadBindingSecretPolicy(file string) (*bindingSecretPolicy, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading binding secret policy: %v", err)
+	}
+	p := &bindingSecretPolicy{}
+	if err := yaml.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("error parsing binding secret policy %s: %v", file, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid binding secret policy %s: %v", file, err)
+	}
+	return p, nil
+}
+
+func (p *bindingSecretPolicy) validate() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("no rules")
+	}
+	names := make(map[string]bool)
+	for i, r := range p.Rules {
+		if !dns1123LabelRE.MatchString(r.Name) {
+			return fmt.Errorf("rule %d: name %q must be a lowercase DNS label", i, r.Name)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("rule %s is defined twice", r.Name)
+		}
+		names[r.Name] = true
+		if len(r.Transforms) == 0 {
+			return fmt.Errorf("rule %s: transforms is required", r.Name)
+		}
+		for j, t := range r.Transforms {
+			if err := t.validate(); err != nil {
+				return fmt.Errorf("rule %s: transform %d: %v", r.Name, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *secretTransform) validate() error {
+	set := 0
+	for _, f := range []bool{t.RenameKey != nil, t.AddKey != nil, t.AddKeysFrom != nil, t.RemoveKey != nil, t.PrefixKeys != nil} {
+		if f {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of renameKey, addKey, addKeysFrom, removeKey and prefixKeys must be set")
+	}
+	switch {
+	case t.RenameKey != nil:
+		if t.RenameKey.From == "" || t.RenameKey.To == "" {
+			return fmt.Errorf("renameKey requires from and to")
+		}
+	case t.AddKey != nil:
+		if t.AddKey.Key == "" || (t.AddKey.StringValue == "") == (t.AddKey.JSONPathExpression == "") {
+			return fmt.Errorf("addKey requires key and one of stringValue and jsonPathExpression")
+		}
+	case t.AddKeysFrom != nil:
+		if t.AddKeysFrom.SecretRef.Namespace == "" || t.AddKeysFrom.SecretRef.Name == "" {
+			return fmt.Errorf("addKeysFrom requires secretRef.namespace and secretRef.name")
+		}
+	case t.RemoveKey != nil:
+		if t.RemoveKey.Key == "" {
+			return fmt.Errorf("removeKey requires key")
+		}
+	case t.PrefixKeys != nil:
+		if t.PrefixKeys.Prefix == "" || len(t.PrefixKeys.Keys) == 0 {
+			return fmt.Errorf("prefixKeys requires prefix and keys")
+		}
+	}
+	return nil
+}
+
+// expand returns the ServiceBinding secretTransforms of t, expanding
+// prefixKeys to a renameKey per key.
+func (t secretTransform) expand() []secretTransform {
+	if t.PrefixKeys == nil {
+		return []secretTransform{t}
+	}
+	var result []secretTransform
+	for _, k := range t.PrefixKeys.Keys {
+		result = append(result, secretTransform{RenameKey: &renameKeyTransform{From: k, To: t.PrefixKeys.Prefix + k}})
+	}
+	return result
+}
+
+// bindingSecretRuleData is the template data of a rule.
+type bindingSecretRuleData struct {
+	Name              string
+	Namespaces        []string
+	NamespaceSelector map[string]string
+	Override          bool
+
+	// Transforms are the expanded secretTransforms as JSON, which is
+	// YAML too.
+	Transforms template.HTML
+}
+
+// bindingSecretPolicyData returns the template data rendering the rules of
+// the binding secret policy of ic, if any.
+func bindingSecretPolicyData(ic *InstallConfig) (map[string]interface{}, error) {
+	p := ic.BindingSecretPolicy
+	if p == nil && ic.BindingSecretPolicyFile != "" {
+		var err error
+		if p, err = loadBindingSecretPolicy(ic.BindingSecretPolicyFile); err != nil {
+			return nil, err
+		}
+	}
+	data := map[string]interface{}{"BindingSecretPolicy": p != nil}
+	if p == nil {
+		return data, nil
+	}
+	var rules []bindingSecretRuleData
+	for _, r := range p.Rules {
+		var transforms []secretTransform
+		for _, t := range r.Transforms {
+			transforms = append(transforms, t.expand()...)
+		}
+		b, err := json.Marshal(transforms)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, bindingSecretRuleData{
+			Name:              r.Name,
+			Namespaces:        r.Namespaces,
+			NamespaceSelector: r.NamespaceSelector,
+			Override:          r.Override,
+			Transforms:        template.HTML(b),
+		})
+	}
+	data["BindingSecretRules"] = rules
+	return data, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestBindingSecretPolicy tests that the rules of a binding secret policy
+// file are rendered as Gatekeeper mutators, with prefixKeys expanded and
+// only the rules overriding them replacing the transforms of the bindings.
+func TestBindingSecretPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "binding-secret-policy")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "policy.yaml")
+	if err := ioutil.WriteFile(file, []byte(`
+rules:
+- name: env
+  transforms:
+  - prefixKeys: {prefix: DB_, keys: [host, password]}
+  - removeKey: {key: privateKeyData}
+- name: payments
+  namespaceSelector:
+    team: payments
+  override: true
+  transforms:
+  - addKey: {key: engine, stringValue: postgres}
+`), 0644); err != nil {
+		t.Fatalf("Unexpected error writing policy: %v", err)
+	}
+
+	ic := validInstallConfig()
+	ic.BindingSecretPolicyFile = file
+	if err := ic.Validate(); err != nil {
+		t.Fatalf("Unexpected error validating config: %v", err)
+	}
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "binding-secret-policy.yaml"))
+	if err != nil {
+		t.Fatalf("Unexpected error reading binding-secret-policy.yaml: %v", err)
+	}
+	objs, err := manifest.Parse("binding-secret-policy.yaml", b)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing binding-secret-policy.yaml: %v\n%s", err, b)
+	}
+
+	got := make(map[string]interface{})
+	for _, o := range objs {
+		var a struct {
+			Spec struct {
+				Match      map[string]interface{} `json:"match"`
+				Parameters map[string]interface{} `json:"parameters"`
+			} `json:"spec"`
+		}
+		if err := json.Unmarshal(o.JSON, &a); err != nil {
+			t.Fatalf("Unexpected error unmarshalling %s: %v", o.Name, err)
+		}
+		got[o.Kind+"/"+o.Name] = map[string]interface{}{
+			"namespaceSelector": a.Spec.Match["namespaceSelector"],
+			"parameters":        a.Spec.Parameters,
+		}
+	}
+	want := map[string]interface{}{
+		"Assign/service-catalog-binding-secret-env": map[string]interface{}{
+			"namespaceSelector": nil,
+			"parameters": map[string]interface{}{
+				"pathTests": []interface{}{
+					map[string]interface{}{"subPath": "spec.secretTransforms", "condition": "MustNotExist"},
+				},
+				"assign": map[string]interface{}{"value": []interface{}{
+					map[string]interface{}{"renameKey": map[string]interface{}{"from": "host", "to": "DB_host"}},
+					map[string]interface{}{"renameKey": map[string]interface{}{"from": "password", "to": "DB_password"}},
+					map[string]interface{}{"removeKey": map[string]interface{}{"key": "privateKeyData"}},
+				}},
+			},
+		},
+		"Assign/service-catalog-binding-secret-payments": map[string]interface{}{
+			"namespaceSelector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"team": "payments"},
+			},
+			"parameters": map[string]interface{}{
+				"assign": map[string]interface{}{"value": []interface{}{
+					map[string]interface{}{"addKey": map[string]interface{}{"key": "engine", "stringValue": "postgres"}},
+				}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Mutators do not match:\ngot  %v\nwant %v", got, want)
+	}
+
+	d, err := ioutil.ReadFile(filepath.Join(dir, "apiserver-deployment.yaml"))
+	if err != nil {
+		t.Fatalf("Unexpected error reading apiserver-deployment.yaml: %v", err)
+	}
+	if !strings.Contains(string(d), "MutatingAdmissionWebhook") {
+		t.Fatalf("Expected the api server to enable MutatingAdmissionWebhook:\n%s", d)
+	}
+	rbac, err := ioutil.ReadFile(filepath.Join(dir, "rbac.yaml"))
+	if err != nil {
+		t.Fatalf("Unexpected error reading rbac.yaml: %v", err)
+	}
+	if !strings.Contains(string(rbac), `resources: ["mutatingwebhookconfigurations", "validatingwebhookconfigurations"]`) {
+		t.Fatalf("Expected the api server role to read the webhook configurations:\n%s", rbac)
+	}
+}
+
+// TestBindingSecretPolicyValidate tests that invalid rules are rejected.
+func TestBindingSecretPolicyValidate(t *testing.T) {
+	remove := []secretTransform{{RemoveKey: &removeKeyTransform{Key: "uri"}}}
+	for _, c := range []struct {
+		policy bindingSecretPolicy
+		want   string
+	}{
+		{bindingSecretPolicy{}, "no rules"},
+		{bindingSecretPolicy{Rules: []bindingSecretRule{{Name: "Env", Transforms: remove}}}, "DNS label"},
+		{bindingSecretPolicy{Rules: []bindingSecretRule{{Name: "env"}}}, "transforms is required"},
+		{bindingSecretPolicy{Rules: []bindingSecretRule{{Name: "env", Transforms: remove}, {Name: "env", Transforms: remove}}}, "defined twice"},
+		{bindingSecretPolicy{Rules: []bindingSecretRule{{Name: "env", Transforms: []secretTransform{{}}}}}, "exactly one"},
+		{bindingSecretPolicy{Rules: []bindingSecretRule{{Name: "env", Transforms: []secretTransform{{
+			RemoveKey: &removeKeyTransform{Key: "uri"},
+			RenameKey: &renameKeyTransform{From: "a", To: "b"},
+		}}}}}, "exactly one"},
+		{bindingSecretPolicy{Rules: []bindingSecretRule{{Name: "env", Transforms: []secretTransform{{
+			AddKey: &addKeyTransform{Key: "engine"},
+		}}}}}, "one of stringValue and jsonPathExpression"},
+		{bindingSecretPolicy{Rules: []bindingSecretRule{{Name: "env", Transforms: []secretTransform{{
+			PrefixKeys: &prefixKeysTransform{Prefix: "DB_"},
+		}}}}}, "prefixKeys requires prefix and keys"},
+	} {
+		err := c.policy.validate()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("Error for %+v does not match: got %v; want %q", c.policy, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestInjectBindingSecret tests that the binding secret is added as envFrom
+// and volume to the selected containers, and that injecting twice changes
+// nothing.
+func TestInjectBindingSecret(t *testing.T) {
+	var podSpec map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"containers": [
+		{"name": "app", "image": "app"},
+		{"name": "sidecar", "image": "proxy"}
+	]}`), &podSpec); err != nil {
+		t.Fatalf("Unexpected error unmarshalling pod spec: %v", err)
+	}
+	bc := &bindingInjectConfig{Deployment: "my-app", Binding: "my-db", Container: "app", Mode: "both"}
+
+	changed, err := injectBindingSecret(podSpec, bc, "my-db-secret")
+	if err != nil {
+		t.Fatalf("Unexpected error injecting binding: %v", err)
+	}
+	if !changed {
+		t.Fatalf("Expected the pod spec to change")
+	}
+
+	b, err := json.Marshal(podSpec)
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling pod spec: %v", err)
+	}
+	want := `{"containers":[` +
+		`{"envFrom":[{"prefix":"MY_DB_","secretRef":{"name":"my-db-secret"}}],"image":"app","name":"app",` +
+		`"volumeMounts":[{"mountPath":"/var/run/secrets/bindings/my-db","name":"binding-my-db","readOnly":true}]},` +
+		`{"image":"proxy","name":"sidecar"}],` +
+		`"volumes":[{"name":"binding-my-db","secret":{"secretName":"my-db-secret"}}]}`
+	if got := string(b); got != want {
+		t.Fatalf("Pod spec does not match: got %s; want %s", got, want)
+	}
+
+	if changed, err := injectBindingSecret(podSpec, bc, "my-db-secret"); err != nil || changed {
+		t.Fatalf("Injecting twice must not change the pod spec: got changed %v, error %v", changed, err)
+	}
+
+	bc.Container = "missing"
+	if _, err := injectBindingSecret(podSpec, bc, "my-db-secret"); err == nil {
+		t.Fatalf("Expected an error injecting into a missing container")
+	}
+}
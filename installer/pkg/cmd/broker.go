@@ -0,0 +1,548 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+// brokerFile declares the brokers registered by sc broker apply, e.g.
+//
+//	brokers:
+//	- name: gcp-broker
+//	  url: https://servicebroker.googleapis.com/v1beta1/projects/p/brokers/default
+//	  auth:
+//	    bearer:
+//	      secretRef: {name: gcp-svc-account-secret, namespace: google-oauth}
+//	- name: team-broker
+//	  namespace: team
+//	  priority: 10
+//	  url: https://broker.team.example.com
+//	  auth:
+//	    basic:
+//	      secretRef: {name: broker-credentials}
+//	  restrictions:
+//	    serviceClass: ["spec.externalName in (mysql, redis)"]
+//
+// Brokers are registered by ascending priority, then in file order.
+type brokerFile struct {
+	Brokers []brokerSpec `json:"brokers"`
+}
+
+// brokerSpec declares a broker.
+type brokerSpec struct {
+	Name string `json:"name"`
+
+	// Namespace registers a namespaced ServiceBroker, a
+	// ClusterServiceBroker if empty.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Priority orders the registration, lower first.
+	Priority int `json:"priority,omitempty"`
+
+	URL string `json:"url"`
+
+	// Auth references the secret with the credentials of the broker.
+	Auth *brokerAuth `json:"auth,omitempty"`
+
+	// CABundle is the base64 encoded PEM CA bundle of the broker TLS
+	// certificate.
+	CABundle              string `json:"caBundle,omitempty"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify,omitempty"`
+
+	// Restrictions are the catalog restrictions of the broker, lists of
+	// requirements on the service classes and plans it offers.
+	Restrictions *brokerRestrictions `json:"restrictions,omitempty"`
+}
+
+// brokerAuth is the authInfo of a broker, either basic or bearer.
+type brokerAuth struct {
+	Basic  *brokerSecretAuth `json:"basic,omitempty"`
+	Bearer *brokerSecretAuth `json:"bearer,omitempty"`
+}
+
+type brokerSecretAuth struct {
+	SecretRef brokerSecretRef `json:"secretRef"`
+}
+
+// brokerSecretRef references a secret. The secrets of namespaced brokers
+// are in the namespace of the broker.
+type brokerSecretRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type brokerRestrictions struct {
+	ServiceClass []string `json:"serviceClass,omitempty"`
+	ServicePlan  []string `json:"servicePlan,omitempty"`
+}
+
+// brokerApplyConfig contains the broker apply configuration.
+type brokerApplyConfig struct {
+	// File declares the brokers.
+	File string
+
+	// Timeout is how long to wait for each broker to be ready.
+	Timeout time.Duration
+
+	// PollInterval is how often the brokers are read while waiting.
+	PollInterval time.Duration
+}
+
+func NewBrokerCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "broker",
+		Short: "commands for service brokers",
+	}
+	c.AddCommand(newBrokerAddCmd(), newBrokerApplyCmd(), newBrokerLintCmd())
+	return c
+}
+
+func newBrokerApplyCmd() *cobra.Command {
+	ac := &brokerApplyConfig{}
+	c := &cobra.Command{
+		Use:   "apply -f <file>",
+		Short: "registers the brokers declared in a file",
+		Long: `registers the brokers declared in a file one after the other, by
+ascending priority, waiting for each to be ready, i.e. for its catalog to be
+fetched, before registering the next one. Brokers already registered are
+updated.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyBrokers(ac); err != nil {
+				messages.Println(messages.BrokerApplyFailed)
+				return err
+			}
+			messages.Println(messages.BrokersApplied)
+			return nil
+		},
+	}
+	c.Flags().StringVarP(&ac.File, "filename", "f", "", "YAML file declaring the brokers")
+	c.Flags().DurationVar(&ac.Timeout, "timeout", 5*time.Minute, "How long to wait for each broker to be ready")
+	c.Flags().DurationVar(&ac.PollInterval, "poll-interval", 2*time.Second, "How often to read the brokers while waiting")
+	return c
+}
+
+// brokerAddConfig contains the broker add configuration.
+type brokerAddConfig struct {
+	// Namespace registers a namespaced ServiceBroker, a
+	// ClusterServiceBroker if empty.
+	Namespace string
+
+	URL string
+
+	// AuthFromGCPSecret is the Secret Manager secret holding the
+	// credentials of the broker, a JSON object with the keys of the secret
+	// of AuthType.
+	AuthFromGCPSecret string
+
+	// AuthType is the auth of the broker, basic or bearer.
+	AuthType string
+
+	// AuthSecretNamespace is the namespace of the auth secret of a
+	// ClusterServiceBroker.
+	AuthSecretNamespace string
+
+	// Timeout is how long to wait for the broker to be ready.
+	Timeout time.Duration
+
+	// PollInterval is how often the broker is read while waiting.
+	PollInterval time.Duration
+}
+
+// brokerAuthKeys are the keys of the auth secrets of the broker auth types.
+var brokerAuthKeys = map[string][]string{
+	"basic":  {"username", "password"},
+	"bearer": {"token"},
+}
+
+func newBrokerAddCmd() *cobra.Command {
+	bc := &brokerAddConfig{}
+	c := &cobra.Command{
+		Use:   "add <name> --url <url>",
+		Short: "registers a broker",
+		Long: `registers a broker, or updates it, and waits for it to be ready. With
+--auth-from-gcp-secret the credentials of the broker are read from a Google
+Secret Manager secret into the secret <name>-auth the broker authenticates
+with, keeping them out of the shell history and of files.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var client *http.Client
+			if bc.AuthFromGCPSecret != "" {
+				var err error
+				if client, err = gcpClient(); err != nil {
+					return err
+				}
+			}
+			if err := addBroker(client, args[0], bc); err != nil {
+				messages.Println(messages.BrokerAddFailed)
+				return err
+			}
+			messages.Println(messages.BrokerAdded)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&bc.URL, "url", "", "URL of the broker")
+	c.Flags().StringVar(&bc.Namespace, "namespace", "", "Namespace of the broker, a ClusterServiceBroker is registered if empty")
+	c.Flags().StringVar(&bc.AuthFromGCPSecret, "auth-from-gcp-secret", "",
+		"Secret Manager secret (projects/<project>/secrets/<secret>[/versions/<version>]) holding the credentials of the broker as a JSON object, e.g. {\"username\": ..., \"password\": ...}")
+	c.Flags().StringVar(&bc.AuthType, "auth-type", "basic", "Auth of the broker with --auth-from-gcp-secret: basic (username and password) or bearer (token)")
+	c.Flags().StringVar(&bc.AuthSecretNamespace, "auth-secret-namespace", defaultNamespace, "Namespace of the auth secret of a ClusterServiceBroker")
+	c.Flags().DurationVar(&bc.Timeout, "timeout", 5*time.Minute, "How long to wait for the broker to be ready")
+	c.Flags().DurationVar(&bc.PollInterval, "poll-interval", 2*time.Second, "How often to read the broker while waiting")
+	return c
+}
+
+// addBroker registers the broker name, with the auth secret read with
+// client from Secret Manager if bc has one.
+func addBroker(client *http.Client, name string, bc *brokerAddConfig) (err error) {
+	b := brokerSpec{Name: name, Namespace: bc.Namespace, URL: bc.URL}
+	var secretRef brokerSecretRef
+	if bc.AuthFromGCPSecret != "" {
+		secretRef.Name = name + "-auth"
+		if bc.Namespace == "" {
+			secretRef.Namespace = bc.AuthSecretNamespace
+		}
+		switch bc.AuthType {
+		case "basic":
+			b.Auth = &brokerAuth{Basic: &brokerSecretAuth{SecretRef: secretRef}}
+		case "bearer":
+			b.Auth = &brokerAuth{Bearer: &brokerSecretAuth{SecretRef: secretRef}}
+		default:
+			return fmt.Errorf("--auth-type must be basic or bearer, got %q", bc.AuthType)
+		}
+	}
+	bf := brokerFile{Brokers: []brokerSpec{b}}
+	if err := bf.validate(); err != nil {
+		return err
+	}
+
+	objects := []map[string]interface{}{b.object()}
+	if bc.AuthFromGCPSecret != "" {
+		data, err := brokerCredentialsFromGCPSecret(client, bc.AuthFromGCPSecret, bc.AuthType)
+		if err != nil {
+			return err
+		}
+		ns := secretRef.Namespace
+		if ns == "" {
+			ns = b.Namespace
+		}
+		objects = append(objects, brokerAuthSecret(secretRef.Name, ns, data))
+		fmt.Printf("using the %s auth credentials from %s\n", bc.AuthType, bc.AuthFromGCPSecret)
+	}
+
+	// The workspace holds the credentials of the broker, if any.
+	ws, err := createWorkspace("", "add-broker", workspace.Options{
+		Cleanup:   workspace.Always,
+		Sensitive: bc.AuthFromGCPSecret != "",
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+
+	for i, o := range objects {
+		y, err := yaml.Marshal(o)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(ws.Dir, fmt.Sprintf("%02d.yaml", i)), y, 0600); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("registering %s %s\n", b.kind(), brokerName(&b))
+	if err := deployConfig(ws.Dir, applyOptions{}); err != nil {
+		return fmt.Errorf("error registering broker %s: %v", brokerName(&b), err)
+	}
+	return waitForBroker(&b, &brokerApplyConfig{Timeout: bc.Timeout, PollInterval: bc.PollInterval})
+}
+
+// brokerCredentialsFromGCPSecret reads the credentials of the broker auth
+// type authType stored as a JSON object in the Secret Manager secret name.
+// It returns the data of the auth secret.
+func brokerCredentialsFromGCPSecret(client *http.Client, name, authType string) (map[string][]byte, error) {
+	b, err := gcp.AccessSecret(client, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds map[string]string
+	if err := json.Unmarshal(b, &creds); err != nil {
+		return nil, fmt.Errorf("secret %s does not contain a JSON object of the broker credentials", name)
+	}
+	data := make(map[string][]byte)
+	for _, k := range brokerAuthKeys[authType] {
+		if creds[k] == "" {
+			return nil, fmt.Errorf("secret %s has no %s, %s auth requires %s", name, k, authType, strings.Join(brokerAuthKeys[authType], " and "))
+		}
+		data[k] = []byte(creds[k])
+	}
+	return data, nil
+}
+
+// brokerAuthSecret returns the auth secret ns/name of a broker.
+func brokerAuthSecret(name, ns string, data map[string][]byte) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": name, "namespace": ns},
+		"type":       "Opaque",
+		"data":       data,
+	}
+}
+
+// loadBrokerFile reads the brokers declared in file, in registration order.
+func loadBrokerFile(file string) ([]brokerSpec, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading broker file: %v", err)
+	}
+	var bf brokerFile
+	if err := yaml.Unmarshal(b, &bf); err != nil {
+		return nil, fmt.Errorf("error parsing broker file %s: %v", file, err)
+	}
+	if err := bf.validate(); err != nil {
+		return nil, fmt.Errorf("invalid broker file %s: %v", file, err)
+	}
+	brokers := bf.Brokers
+	sort.SliceStable(brokers, func(i, j int) bool { return brokers[i].Priority < brokers[j].Priority })
+	return brokers, nil
+}
+
+func (bf *brokerFile) validate() error {
+	if len(bf.Brokers) == 0 {
+		return fmt.Errorf("no brokers declared")
+	}
+	seen := make(map[string]bool)
+	for i, b := range bf.Brokers {
+		if !dns1123LabelRE.MatchString(b.Name) {
+			return fmt.Errorf("broker %d: name %q must be a DNS label", i+1, b.Name)
+		}
+		if b.Namespace != "" && !dns1123LabelRE.MatchString(b.Namespace) {
+			return fmt.Errorf("broker %s: namespace %q must be a DNS label", b.Name, b.Namespace)
+		}
+		if seen[brokerName(&b)] {
+			return fmt.Errorf("broker %s is declared twice", brokerName(&b))
+		}
+		seen[brokerName(&b)] = true
+		if b.URL == "" {
+			return fmt.Errorf("broker %s: url is required", b.Name)
+		}
+		if b.Auth == nil {
+			continue
+		}
+		var refs []brokerSecretRef
+		for _, a := range []*brokerSecretAuth{b.Auth.Basic, b.Auth.Bearer} {
+			if a != nil {
+				refs = append(refs, a.SecretRef)
+			}
+		}
+		if len(refs) != 1 {
+			return fmt.Errorf("broker %s: auth must be either basic or bearer", b.Name)
+		}
+		switch r := refs[0]; {
+		case r.Name == "":
+			return fmt.Errorf("broker %s: auth secretRef requires a name", b.Name)
+		case b.Namespace == "" && r.Namespace == "":
+			return fmt.Errorf("broker %s: auth secretRef of a cluster broker requires a namespace", b.Name)
+		case b.Namespace != "" && r.Namespace != "":
+			return fmt.Errorf("broker %s: auth secretRef of a namespaced broker must not have a namespace, the secret is in the namespace of the broker", b.Name)
+		}
+	}
+	return nil
+}
+
+// kind returns the kind of the broker object.
+func (b *brokerSpec) kind() string {
+	if b.Namespace == "" {
+		return "ClusterServiceBroker"
+	}
+	return "ServiceBroker"
+}
+
+// resource returns the resource the broker is read as with kubectl.
+func (b *brokerSpec) resource() string {
+	return strings.ToLower(b.kind()) + "s.servicecatalog.k8s.io"
+}
+
+// object returns the broker object.
+func (b *brokerSpec) object() map[string]interface{} {
+	metadata := map[string]interface{}{"name": b.Name}
+	if b.Namespace != "" {
+		metadata["namespace"] = b.Namespace
+	}
+	spec := map[string]interface{}{"url": b.URL}
+	if b.Auth != nil {
+		spec["authInfo"] = b.Auth
+	}
+	if b.CABundle != "" {
+		spec["caBundle"] = b.CABundle
+	}
+	if b.InsecureSkipTLSVerify {
+		spec["insecureSkipTLSVerify"] = true
+	}
+	if b.Restrictions != nil {
+		spec["catalogRestrictions"] = b.Restrictions
+	}
+	return map[string]interface{}{
+		"apiVersion": "servicecatalog.k8s.io/v1beta1",
+		"kind":       b.kind(),
+		"metadata":   metadata,
+		"spec":       spec,
+	}
+}
+
+func applyBrokers(ac *brokerApplyConfig) (err error) {
+	if ac.File == "" {
+		return fmt.Errorf("-f is required")
+	}
+	brokers, err := loadBrokerFile(ac.File)
+	if err != nil {
+		return err
+	}
+
+	ws, err := createWorkspace("", "apply-brokers", workspace.Options{Cleanup: workspace.Always})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+	dir := ws.Dir
+
+	for i, b := range brokers {
+		// Each broker gets a directory of its own to be deployed alone.
+		bdir := filepath.Join(dir, fmt.Sprintf("%02d-%s", i, b.Name))
+		if err := os.Mkdir(bdir, 0755); err != nil {
+			return err
+		}
+		y, err := yaml.Marshal(b.object())
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(bdir, "broker.yaml"), y, 0644); err != nil {
+			return err
+		}
+
+		fmt.Printf("registering %s %s\n", b.kind(), brokerName(&b))
+		if err := deployConfig(bdir, applyOptions{}); err != nil {
+			return fmt.Errorf("error registering broker %s: %v", brokerName(&b), err)
+		}
+		if err := waitForBroker(&b, ac); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// brokerName returns the name of b, with its namespace if it has one.
+func brokerName(b *brokerSpec) string {
+	if b.Namespace == "" {
+		return b.Name
+	}
+	return b.Namespace + "/" + b.Name
+}
+
+// brokerStatus is the subset of a broker read to wait for it.
+type brokerStatus struct {
+	Metadata struct {
+		Generation int64 `json:"generation"`
+	} `json:"metadata"`
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+		ReconciledGeneration int64 `json:"reconciledGeneration"`
+	} `json:"status"`
+}
+
+// brokerReady returns whether the current generation of the broker fetched
+// its catalog, and an error if it failed for good.
+func brokerReady(bs *brokerStatus) (bool, error) {
+	ready := false
+	for _, c := range bs.Status.Conditions {
+		switch c.Type {
+		case "Ready":
+			ready = c.Status == "True"
+		case "Failed":
+			if c.Status == "True" {
+				return false, fmt.Errorf("%s", c.Message)
+			}
+		}
+	}
+	return ready && bs.Status.ReconciledGeneration >= bs.Metadata.Generation, nil
+}
+
+// waitForBroker waits for b to be ready, printing why it is not.
+func waitForBroker(b *brokerSpec, ac *brokerApplyConfig) error {
+	args := []string{"get", b.resource(), b.Name, "-o", "json"}
+	if b.Namespace != "" {
+		args = append(args, "--namespace", b.Namespace)
+	}
+	deadline := time.Now().Add(ac.Timeout)
+	last := ""
+	for {
+		output, err := kubectlCommand(args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error getting broker %s: %s", brokerName(b), strings.TrimSpace(string(output)))
+		}
+		var bs brokerStatus
+		if err := json.Unmarshal(output, &bs); err != nil {
+			return fmt.Errorf("error unmarshalling broker %s: %v", brokerName(b), err)
+		}
+		ready, err := brokerReady(&bs)
+		if err != nil {
+			if brokerVersionRejected(err.Error()) {
+				return fmt.Errorf("broker %s failed: %v\n%s", brokerName(b), err, brokerVersionGuidance(brokerName(b)))
+			}
+			return fmt.Errorf("broker %s failed: %v", brokerName(b), err)
+		}
+		if ready {
+			fmt.Printf("broker %s is ready\n", brokerName(b))
+			return nil
+		}
+		for _, c := range bs.Status.Conditions {
+			if c.Type == "Ready" && c.Message != "" && c.Message != last {
+				fmt.Printf("%s %s\n", time.Now().Format("15:04:05"), c.Message)
+				if brokerVersionRejected(c.Message) {
+					fmt.Printf("WARNING: %s\n", brokerVersionGuidance(brokerName(b)))
+				}
+				last = c.Message
+			}
+		}
+
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("broker %s is not ready after %v: %s", brokerName(b), ac.Timeout, last)
+		}
+		time.Sleep(ac.PollInterval)
+	}
+}
@@ -0,0 +1,478 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// jsonSchemaDraft4 is the JSON schema version of the parameters schemas.
+const jsonSchemaDraft4 = "http://json-schema.org/draft-04/schema#"
+
+var (
+	// osbNameRE matches the CLI-friendly names of services and plans.
+	osbNameRE = regexp.MustCompile(`^[a-zA-Z0-9.-]+$`)
+
+	// osbIDRE matches the IDs Service Catalog accepts, it names the
+	// classes and plans after them.
+	osbIDRE = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9.]*[a-zA-Z0-9])?$`)
+
+	// semverRE matches semantic versions 2.0.0.
+	semverRE = regexp.MustCompile(`^(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)` +
+		`(-(0|[1-9][0-9]*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*)(\.(0|[1-9][0-9]*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*))*)?` +
+		`(\+[0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*)?$`)
+)
+
+// brokerLintConfig contains the broker lint configuration.
+type brokerLintConfig struct {
+	// URL is the URL of the broker, the catalog is read from
+	// <URL>/v2/catalog.
+	URL string
+
+	// File is a file holding the catalog, instead of URL.
+	File string
+
+	Username string
+	Password string
+	Token    string
+
+	// APIVersion is sent in the X-Broker-API-Version header.
+	APIVersion string
+
+	InsecureSkipTLSVerify bool
+
+	// Strict fails on warnings too.
+	Strict bool
+}
+
+// lintFinding is a violation of the Open Service Broker API by a catalog.
+type lintFinding struct {
+	// Error is false for warnings, which Service Catalog tolerates.
+	Error bool
+
+	// Path is the JSON path of the violating field, e.g.
+	// services[0].plans[1].id.
+	Path    string
+	Message string
+}
+
+func (f lintFinding) String() string {
+	severity := "warning"
+	if f.Error {
+		severity = "error"
+	}
+	return fmt.Sprintf("%s: %s: %s", severity, f.Path, f.Message)
+}
+
+func newBrokerLintCmd() *cobra.Command {
+	lc := &brokerLintConfig{}
+	c := &cobra.Command{
+		Use:   "lint <url>",
+		Short: "checks the catalog of a broker for Open Service Broker API violations",
+		Long: `reads the catalog of the broker at <url>, or of --file, and checks it
+for violations of the Open Service Broker API that the controller manager
+otherwise reports as opaque errors: missing or invalid fields, duplicate
+service and plan IDs or names, IDs Service Catalog can't name objects after,
+invalid maintenance_info and invalid or missing parameters schemas. It fails
+if the catalog has errors, or warnings with --strict.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				lc.URL = args[0]
+			}
+			if err := lintBroker(lc); err != nil {
+				messages.Println(messages.BrokerLintFailed)
+				return err
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVar(&lc.File, "file", "", "JSON file holding the catalog, instead of reading it from the broker")
+	c.Flags().StringVar(&lc.Username, "username", "", "Basic auth username of the broker")
+	c.Flags().StringVar(&lc.Password, "password", "", "Basic auth password of the broker")
+	c.Flags().StringVar(&lc.Token, "token", "", "Bearer token of the broker")
+	c.Flags().StringVar(&lc.APIVersion, "api-version", defaultOSBAPIVersion, "Open Service Broker API version to request the catalog with, that of the controller manager by default")
+	c.Flags().BoolVar(&lc.InsecureSkipTLSVerify, "insecure-skip-tls-verify", false, "Do not verify the certificate of the broker")
+	c.Flags().BoolVar(&lc.Strict, "strict", false, "Also fail on warnings")
+	return c
+}
+
+func lintBroker(lc *brokerLintConfig) error {
+	var catalog []byte
+	var advertised string
+	var err error
+	switch {
+	case (lc.URL == "") == (lc.File == ""):
+		return fmt.Errorf("pass either the URL of the broker or --file")
+	case lc.File != "":
+		if catalog, err = ioutil.ReadFile(lc.File); err != nil {
+			return fmt.Errorf("error reading catalog file: %v", err)
+		}
+	default:
+		if catalog, advertised, err = fetchCatalog(lc); err != nil {
+			return err
+		}
+	}
+
+	findings, err := lintCatalog(catalog)
+	if err != nil {
+		return err
+	}
+	if advertised != "" && !osbVersionCompatible(lc.APIVersion, advertised) {
+		findings = append([]lintFinding{{Path: "X-Broker-API-Version", Message: fmt.Sprintf(
+			"the broker advertises version %s, which may not accept the %s requests of the controller manager, "+
+				"install Service Catalog with --osb-api-version %s if the broker rejects them", advertised, lc.APIVersion, advertised)}}, findings...)
+	}
+	errs, warnings := 0, 0
+	for _, f := range findings {
+		fmt.Println(f)
+		if f.Error {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	fmt.Printf("%d errors, %d warnings\n", errs, warnings)
+	if errs > 0 || (lc.Strict && warnings > 0) {
+		return fmt.Errorf("the catalog has %d errors and %d warnings", errs, warnings)
+	}
+	return nil
+}
+
+// fetchCatalog reads the catalog of the broker of lc, and the API version
+// the broker advertises in its X-Broker-API-Version response header, if
+// any.
+func fetchCatalog(lc *brokerLintConfig) ([]byte, string, error) {
+	u := strings.TrimSuffix(lc.URL, "/") + "/v2/catalog"
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid broker URL %q: %v", lc.URL, err)
+	}
+	req.Header.Set("X-Broker-API-Version", lc.APIVersion)
+	switch {
+	case lc.Token != "":
+		req.Header.Set("Authorization", "Bearer "+lc.Token)
+	case lc.Username != "":
+		req.SetBasicAuth(lc.Username, lc.Password)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	if lc.InsecureSkipTLSVerify {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching the catalog: %v", err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching the catalog: %v", err)
+	}
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return nil, "", fmt.Errorf("the broker rejects Open Service Broker API version %s with %s: %s\n"+
+			"lint it with the --api-version it accepts and install Service Catalog with the same --osb-api-version",
+			lc.APIVersion, resp.Status, strings.TrimSpace(string(b)))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("error fetching the catalog from %s: %s: %s", u, resp.Status, strings.TrimSpace(string(b)))
+	}
+	return b, resp.Header.Get("X-Broker-API-Version"), nil
+}
+
+// catalogLinter collects the findings of a catalog.
+type catalogLinter struct {
+	findings []lintFinding
+}
+
+func (l *catalogLinter) errorf(path, format string, args ...interface{}) {
+	l.findings = append(l.findings, lintFinding{Error: true, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *catalogLinter) warnf(path, format string, args ...interface{}) {
+	l.findings = append(l.findings, lintFinding{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// lintCatalog returns the findings of the JSON catalog, in order.
+func lintCatalog(catalog []byte) ([]lintFinding, error) {
+	var root interface{}
+	if err := json.Unmarshal(catalog, &root); err != nil {
+		return nil, fmt.Errorf("the catalog is not valid JSON: %v", err)
+	}
+	l := &catalogLinter{}
+	obj, ok := root.(map[string]interface{})
+	if !ok {
+		l.errorf("catalog", "must be an object")
+		return l.findings, nil
+	}
+	services, ok := obj["services"].([]interface{})
+	if !ok {
+		l.errorf("services", "must be an array")
+		return l.findings, nil
+	}
+
+	serviceIDs := make(map[string]string)
+	serviceNames := make(map[string]string)
+	planIDs := make(map[string]string)
+	for i, s := range services {
+		path := fmt.Sprintf("services[%d]", i)
+		service, ok := s.(map[string]interface{})
+		if !ok {
+			l.errorf(path, "must be an object")
+			continue
+		}
+		l.lintID(service, path, "service", serviceIDs)
+		l.lintName(service, path, "service", serviceNames)
+		l.requireString(service, path, "description")
+		l.lintBool(service, path, "bindable", true)
+		for _, key := range []string{"plan_updateable", "instances_retrievable", "bindings_retrievable", "allow_context_updates"} {
+			l.lintBool(service, path, key, false)
+		}
+		for _, key := range []string{"tags", "requires"} {
+			l.lintStrings(service, path, key)
+		}
+		l.lintObject(service, path, "metadata")
+
+		plans, ok := service["plans"].([]interface{})
+		if !ok || len(plans) == 0 {
+			l.errorf(path+".plans", "must be a non-empty array")
+			continue
+		}
+		planNames := make(map[string]string)
+		for j, p := range plans {
+			ppath := fmt.Sprintf("%s.plans[%d]", path, j)
+			plan, ok := p.(map[string]interface{})
+			if !ok {
+				l.errorf(ppath, "must be an object")
+				continue
+			}
+			l.lintPlan(plan, ppath, planIDs, planNames)
+		}
+	}
+	return l.findings, nil
+}
+
+func (l *catalogLinter) lintPlan(plan map[string]interface{}, path string, ids, names map[string]string) {
+	l.lintID(plan, path, "plan", ids)
+	l.lintName(plan, path, "plan", names)
+	l.requireString(plan, path, "description")
+	for _, key := range []string{"free", "bindable", "plan_updateable"} {
+		l.lintBool(plan, path, key, false)
+	}
+	l.lintObject(plan, path, "metadata")
+
+	if mi, ok := plan["maintenance_info"]; ok {
+		mpath := path + ".maintenance_info"
+		obj, ok := mi.(map[string]interface{})
+		if !ok {
+			l.errorf(mpath, "must be an object")
+		} else if v, ok := obj["version"].(string); !ok {
+			l.errorf(mpath+".version", "must be a string")
+		} else if !semverRE.MatchString(v) {
+			l.errorf(mpath+".version", "%q is not a semantic version 2.0, e.g. 1.2.0", v)
+		}
+		if obj != nil {
+			if d, ok := obj["description"]; ok {
+				if _, ok := d.(string); !ok {
+					l.errorf(mpath+".description", "must be a string")
+				}
+			}
+		}
+	}
+
+	schemas, ok := plan["schemas"]
+	if !ok {
+		l.warnf(path, "has no schemas, the parameters of its instances and bindings can't be validated or documented")
+		return
+	}
+	l.lintSchemas(schemas, path+".schemas")
+}
+
+// lintSchemas checks the parameters schemas of a plan, at
+// service_instance.create, service_instance.update and
+// service_binding.create.
+func (l *catalogLinter) lintSchemas(schemas interface{}, path string) {
+	allowed := map[string][]string{
+		"service_instance": {"create", "update"},
+		"service_binding":  {"create"},
+	}
+	obj, ok := schemas.(map[string]interface{})
+	if !ok {
+		l.errorf(path, "must be an object")
+		return
+	}
+	for _, kind := range sortedKeys(obj) {
+		v := obj[kind]
+		kpath := path + "." + kind
+		ops, known := allowed[kind]
+		if !known {
+			l.warnf(kpath, "is not a schema the Open Service Broker API defines")
+			continue
+		}
+		kobj, ok := v.(map[string]interface{})
+		if !ok {
+			l.errorf(kpath, "must be an object")
+			continue
+		}
+		for _, op := range sortedKeys(kobj) {
+			v := kobj[op]
+			opath := kpath + "." + op
+			if !containsString(ops, op) {
+				l.warnf(opath, "is not a schema the Open Service Broker API defines")
+				continue
+			}
+			oobj, ok := v.(map[string]interface{})
+			if !ok {
+				l.errorf(opath, "must be an object")
+				continue
+			}
+			params, ok := oobj["parameters"]
+			if !ok {
+				l.errorf(opath+".parameters", "is missing")
+				continue
+			}
+			l.lintParametersSchema(params, opath+".parameters")
+		}
+	}
+}
+
+func (l *catalogLinter) lintParametersSchema(schema interface{}, path string) {
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		l.errorf(path, "must be a JSON schema object")
+		return
+	}
+	if s, ok := obj["$schema"]; !ok {
+		l.warnf(path, "has no $schema, the Open Service Broker API requires JSON schema draft 4")
+	} else if s != jsonSchemaDraft4 {
+		l.warnf(path+".$schema", "is %v, the Open Service Broker API requires %s", s, jsonSchemaDraft4)
+	}
+	if t, ok := obj["type"]; ok && t != "object" {
+		l.errorf(path+".type", "must be object, the parameters are a JSON object, got %v", t)
+	}
+	if p, ok := obj["properties"]; ok {
+		if _, ok := p.(map[string]interface{}); !ok {
+			l.errorf(path+".properties", "must be an object")
+		}
+	}
+}
+
+// lintID checks the id of a service or plan, unique in seen, which maps the
+// IDs to the paths of the objects using them.
+func (l *catalogLinter) lintID(obj map[string]interface{}, path, what string, seen map[string]string) {
+	id, ok := l.requireString(obj, path, "id")
+	if !ok {
+		return
+	}
+	if other, dup := seen[id]; dup {
+		l.errorf(path+".id", "duplicate %s id %q, also used by %s", what, id, other)
+		return
+	}
+	seen[id] = path
+	if len(id) > 63 || !osbIDRE.MatchString(id) {
+		l.errorf(path+".id", "%q is not a valid %s id for Service Catalog, which names objects after it: at most 63 alphanumeric characters, '-' or '.', starting and ending with an alphanumeric character", id, what)
+	}
+}
+
+// lintName checks the name of a service or plan, unique in seen.
+func (l *catalogLinter) lintName(obj map[string]interface{}, path, what string, seen map[string]string) {
+	name, ok := l.requireString(obj, path, "name")
+	if !ok {
+		return
+	}
+	if other, dup := seen[name]; dup {
+		l.errorf(path+".name", "duplicate %s name %q, also used by %s", what, name, other)
+		return
+	}
+	seen[name] = path
+	if !osbNameRE.MatchString(name) {
+		l.errorf(path+".name", "%q must only contain alphanumeric characters, periods and hyphens", name)
+	}
+}
+
+// requireString checks that obj[key] is a non-empty string and returns it.
+func (l *catalogLinter) requireString(obj map[string]interface{}, path, key string) (string, bool) {
+	v, ok := obj[key]
+	if !ok {
+		l.errorf(path+"."+key, "is missing")
+		return "", false
+	}
+	s, ok := v.(string)
+	if !ok || s == "" {
+		l.errorf(path+"."+key, "must be a non-empty string")
+		return "", false
+	}
+	return s, true
+}
+
+func (l *catalogLinter) lintBool(obj map[string]interface{}, path, key string, required bool) {
+	v, ok := obj[key]
+	if !ok {
+		if required {
+			l.errorf(path+"."+key, "is missing")
+		}
+		return
+	}
+	if _, ok := v.(bool); !ok {
+		l.errorf(path+"."+key, "must be a boolean, got %v", v)
+	}
+}
+
+func (l *catalogLinter) lintStrings(obj map[string]interface{}, path, key string) {
+	v, ok := obj[key]
+	if !ok {
+		return
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		l.errorf(path+"."+key, "must be an array of strings")
+		return
+	}
+	for _, e := range list {
+		if _, ok := e.(string); !ok {
+			l.errorf(path+"."+key, "must be an array of strings, got %v", e)
+			return
+		}
+	}
+}
+
+func (l *catalogLinter) lintObject(obj map[string]interface{}, path, key string) {
+	if v, ok := obj[key]; ok {
+		if _, ok := v.(map[string]interface{}); !ok {
+			l.errorf(path+"."+key, "must be an object")
+		}
+	}
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	var keys []string
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
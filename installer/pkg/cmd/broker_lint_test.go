@@ -0,0 +1,121 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLintCatalog tests that the violations of a catalog are found, in
+// order, and that a valid catalog has none.
+func TestLintCatalog(t *testing.T) {
+	valid := `{"services": [{"id": "svc-1", "name": "db", "description": "A database", "bindable": true,
+		"plans": [{"id": "plan-1", "name": "small", "description": "Small", "free": true,
+			"maintenance_info": {"version": "1.2.0-beta.1+build.5"},
+			"schemas": {"service_instance": {"create": {"parameters": {
+				"$schema": "http://json-schema.org/draft-04/schema#", "type": "object", "properties": {}}}}}}]}]}`
+	findings, err := lintCatalog([]byte(valid))
+	if err != nil {
+		t.Fatalf("Unexpected error linting the valid catalog: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("Unexpected findings for the valid catalog: %v", findings)
+	}
+
+	invalid := `{"services": [
+		{"id": "svc-1", "name": "db", "description": "A database", "bindable": "yes",
+			"plans": [
+				{"id": "plan-1", "name": "small", "description": "Small",
+					"maintenance_info": {"version": "v1"},
+					"schemas": {"service_instance": {"create": {"parameters": {"type": "string"}}, "delete": {}}}},
+				{"id": "plan-1", "name": "small", "description": ""}]},
+		{"id": "Svc_2", "name": "my db", "description": "Another", "bindable": false, "plans": []}]}`
+	findings, err = lintCatalog([]byte(invalid))
+	if err != nil {
+		t.Fatalf("Unexpected error linting the invalid catalog: %v", err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"error: services[0].bindable: must be a boolean, got yes",
+		"error: services[0].plans[0].maintenance_info.version: \"v1\" is not a semantic version 2.0, e.g. 1.2.0",
+		"warning: services[0].plans[0].schemas.service_instance.create.parameters: has no $schema, the Open Service Broker API requires JSON schema draft 4",
+		"error: services[0].plans[0].schemas.service_instance.create.parameters.type: must be object, the parameters are a JSON object, got string",
+		"warning: services[0].plans[0].schemas.service_instance.delete: is not a schema the Open Service Broker API defines",
+		"error: services[0].plans[1].id: duplicate plan id \"plan-1\", also used by services[0].plans[0]",
+		"error: services[0].plans[1].name: duplicate plan name \"small\", also used by services[0].plans[0]",
+		"error: services[0].plans[1].description: must be a non-empty string",
+		"warning: services[0].plans[1]: has no schemas, the parameters of its instances and bindings can't be validated or documented",
+		"error: services[1].id: \"Svc_2\" is not a valid service id for Service Catalog, which names objects after it: at most 63 alphanumeric characters, '-' or '.', starting and ending with an alphanumeric character",
+		"error: services[1].name: \"my db\" must only contain alphanumeric characters, periods and hyphens",
+		"error: services[1].plans: must be a non-empty array",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Findings do not match:\ngot  %q\nwant %q", got, want)
+	}
+
+	if _, err := lintCatalog([]byte("not json")); err == nil {
+		t.Fatalf("Expected an error linting invalid JSON")
+	}
+}
+
+// TestFetchCatalog tests that the catalog is requested with the API version
+// header and the credentials of the broker.
+func TestFetchCatalog(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		if r.Header.Get("X-Broker-API-Version") != osbAPIVersion213 {
+			http.Error(w, "unsupported version", http.StatusPreconditionFailed)
+			return
+		}
+		if r.URL.Path != "/v2/catalog" || user != "admin" || password != "secret" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("X-Broker-API-Version", osbAPIVersion213)
+		w.Write([]byte(`{"services": []}`))
+	}))
+	defer s.Close()
+
+	lc := &brokerLintConfig{URL: s.URL + "/", Username: "admin", Password: "secret", APIVersion: defaultOSBAPIVersion}
+	if _, _, err := fetchCatalog(lc); err == nil || !strings.Contains(err.Error(), "rejects Open Service Broker API version 2.14") {
+		t.Fatalf("Expected an error naming the rejected version, got %v", err)
+	}
+
+	lc.APIVersion = osbAPIVersion213
+	b, advertised, err := fetchCatalog(lc)
+	if err != nil {
+		t.Fatalf("Unexpected error fetching the catalog: %v", err)
+	}
+	if got, want := string(b), `{"services": []}`; got != want {
+		t.Fatalf("Catalog does not match: got %s; want %s", got, want)
+	}
+	if advertised != osbAPIVersion213 {
+		t.Fatalf("Advertised version does not match: got %q; want %q", advertised, osbAPIVersion213)
+	}
+
+	lc.Password = "wrong"
+	if _, _, err := fetchCatalog(lc); err == nil {
+		t.Fatalf("Expected an error fetching the catalog with wrong credentials")
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestLoadBrokerFile tests that brokers are ordered by priority, then by
+// their position in the file, and are rendered as cluster or namespaced
+// brokers.
+func TestLoadBrokerFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "brokers")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "brokers.yaml")
+	if err := ioutil.WriteFile(file, []byte(`
+brokers:
+- name: late
+  priority: 10
+  url: https://late.example.com
+- name: first
+  url: https://first.example.com
+  auth:
+    bearer:
+      secretRef: {name: token, namespace: brokers}
+- name: team
+  namespace: team
+  url: https://team.example.com
+  auth:
+    basic:
+      secretRef: {name: credentials}
+  restrictions:
+    serviceClass: ["spec.externalName in (mysql)"]
+`), 0644); err != nil {
+		t.Fatalf("Unexpected error writing brokers: %v", err)
+	}
+
+	brokers, err := loadBrokerFile(file)
+	if err != nil {
+		t.Fatalf("Unexpected error loading brokers: %v", err)
+	}
+	var names []string
+	for _, b := range brokers {
+		names = append(names, brokerName(&b))
+	}
+	if want := []string{"first", "team/team", "late"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Broker order does not match: got %v; want %v", names, want)
+	}
+
+	b, err := json.Marshal(brokers[1].object())
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling broker: %v", err)
+	}
+	want := `{"apiVersion":"servicecatalog.k8s.io/v1beta1","kind":"ServiceBroker","metadata":{"name":"team","namespace":"team"},` +
+		`"spec":{"authInfo":{"basic":{"secretRef":{"name":"credentials"}}},"catalogRestrictions":{"serviceClass":["spec.externalName in (mysql)"]},"url":"https://team.example.com"}}`
+	if string(b) != want {
+		t.Fatalf("Broker does not match: got %s; want %s", b, want)
+	}
+	if got := brokers[0].resource(); got != "clusterservicebrokers.servicecatalog.k8s.io" {
+		t.Fatalf("Resource does not match: got %s; want clusterservicebrokers.servicecatalog.k8s.io", got)
+	}
+}
+
+// TestBrokerFileValidate tests that invalid broker declarations are
+// rejected.
+func TestBrokerFileValidate(t *testing.T) {
+	ref := func(name, ns string) *brokerAuth {
+		return &brokerAuth{Bearer: &brokerSecretAuth{SecretRef: brokerSecretRef{Name: name, Namespace: ns}}}
+	}
+	for _, tc := range []struct {
+		brokers []brokerSpec
+		want    string
+	}{
+		{nil, "no brokers declared"},
+		{[]brokerSpec{{Name: "Bad_Name", URL: "https://b"}}, "must be a DNS label"},
+		{[]brokerSpec{{Name: "b"}}, "url is required"},
+		{[]brokerSpec{{Name: "b", URL: "https://b"}, {Name: "b", URL: "https://c"}}, "declared twice"},
+		{[]brokerSpec{{Name: "b", URL: "https://b", Auth: &brokerAuth{}}}, "either basic or bearer"},
+		{[]brokerSpec{{Name: "b", URL: "https://b", Auth: ref("s", "")}}, "requires a namespace"},
+		{[]brokerSpec{{Name: "b", Namespace: "n", URL: "https://b", Auth: ref("s", "other")}}, "must not have a namespace"},
+	} {
+		bf := &brokerFile{Brokers: tc.brokers}
+		if err := bf.validate(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("Error does not match: got %v; want %q", err, tc.want)
+		}
+	}
+	bf := &brokerFile{Brokers: []brokerSpec{
+		{Name: "b", URL: "https://b", Auth: ref("s", "ns")},
+		{Name: "b", Namespace: "n", URL: "https://b", Auth: ref("s", "")},
+	}}
+	if err := bf.validate(); err != nil {
+		t.Fatalf("Unexpected error validating brokers: %v", err)
+	}
+}
+
+// TestBrokerReady tests that a broker is ready once its current generation
+// fetched its catalog, and failed if the Failed condition is set.
+func TestBrokerReady(t *testing.T) {
+	for _, tc := range []struct {
+		status string
+		ready  bool
+		failed bool
+	}{
+		{`{"metadata":{"generation":2},"status":{"reconciledGeneration":2,"conditions":[{"type":"Ready","status":"True"}]}}`, true, false},
+		{`{"metadata":{"generation":2},"status":{"reconciledGeneration":1,"conditions":[{"type":"Ready","status":"True"}]}}`, false, false},
+		{`{"metadata":{"generation":1},"status":{"conditions":[{"type":"Ready","status":"False","message":"Error fetching catalog"}]}}`, false, false},
+		{`{"metadata":{"generation":1},"status":{"conditions":[{"type":"Failed","status":"True","message":"invalid catalog"}]}}`, false, true},
+	} {
+		var bs brokerStatus
+		if err := json.Unmarshal([]byte(tc.status), &bs); err != nil {
+			t.Fatalf("Unexpected error unmarshalling status: %v", err)
+		}
+		ready, err := brokerReady(&bs)
+		if ready != tc.ready || (err != nil) != tc.failed {
+			t.Fatalf("Readiness of %s does not match: got %v, %v; want %v, failed %v", tc.status, ready, err, tc.ready, tc.failed)
+		}
+	}
+}
+
+// TestBrokerCredentialsFromGCPSecret tests that the credentials of the auth
+// type are read from the secret, and that missing ones are reported.
+func TestBrokerCredentialsFromGCPSecret(t *testing.T) {
+	client, stop := gcpTestClient(t, secretManagerHandler(map[string]string{
+		"projects/p/secrets/basic":  `{"username": "admin", "password": "hunter2"}`,
+		"projects/p/secrets/bearer": `{"token": "abc"}`,
+		"projects/p/secrets/plain":  "hunter2",
+	}))
+	defer stop()
+
+	for _, tc := range []struct {
+		secret, authType string
+		want             map[string][]byte
+		wantErr          string
+	}{
+		{secret: "projects/p/secrets/basic", authType: "basic", want: map[string][]byte{"username": []byte("admin"), "password": []byte("hunter2")}},
+		{secret: "projects/p/secrets/bearer", authType: "bearer", want: map[string][]byte{"token": []byte("abc")}},
+		{secret: "projects/p/secrets/bearer", authType: "basic", wantErr: "secret projects/p/secrets/bearer has no username, basic auth requires username and password"},
+		{secret: "projects/p/secrets/basic", authType: "bearer", wantErr: "secret projects/p/secrets/basic has no token, bearer auth requires token"},
+		{secret: "projects/p/secrets/plain", authType: "basic", wantErr: "secret projects/p/secrets/plain does not contain a JSON object of the broker credentials"},
+		{secret: "projects/p/secrets/missing", authType: "basic", wantErr: "404 Not Found"},
+	} {
+		got, err := brokerCredentialsFromGCPSecret(client, tc.secret, tc.authType)
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("%s %s: unexpected error: %v", tc.secret, tc.authType, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("%s %s: expected an error containing %q, got %v", tc.secret, tc.authType, tc.wantErr, err)
+		case !reflect.DeepEqual(got, tc.want):
+			t.Fatalf("%s %s: expected %q, got %q", tc.secret, tc.authType, tc.want, got)
+		}
+	}
+}
+
+// addBrokerStub returns the responses of a cluster serving the catalog API
+// in which the brokers are ready once applied.
+func addBrokerStub(name string, args []string) execx.Response {
+	switch args[0] {
+	case "api-versions":
+		return execx.Response{Stdout: "v1\nservicecatalog.k8s.io/v1beta1\n"}
+	case "get":
+		return execx.Response{Stdout: `{"metadata":{"generation":1},"status":{"reconciledGeneration":1,"conditions":[{"type":"Ready","status":"True"}]}}`}
+	}
+	return execx.Response{}
+}
+
+// TestAddBroker tests that the auth secret read from Secret Manager is
+// applied with the broker, in the namespace of a namespaced broker or in
+// --auth-secret-namespace for a cluster broker.
+func TestAddBroker(t *testing.T) {
+	defer tempWorkspaces(t)()
+	client, stop := gcpTestClient(t, secretManagerHandler(map[string]string{
+		"projects/p/secrets/team": `{"username": "admin", "password": "hunter2"}`,
+	}))
+	defer stop()
+
+	for _, tc := range []struct {
+		namespace string
+		wantGet   string
+	}{
+		{"", "get clusterservicebrokers.servicecatalog.k8s.io team-broker -o json"},
+		{"team", "get servicebrokers.servicecatalog.k8s.io team-broker -o json --namespace team"},
+	} {
+		s, restore := stubExecutor(addBrokerStub)
+		err := addBroker(client, "team-broker", &brokerAddConfig{
+			Namespace:           tc.namespace,
+			URL:                 "https://broker.team.example.com",
+			AuthFromGCPSecret:   "projects/p/secrets/team",
+			AuthType:            "basic",
+			AuthSecretNamespace: "catalog",
+			Timeout:             time.Minute,
+		})
+		restore()
+		if err != nil {
+			t.Fatalf("%q: unexpected error adding the broker: %v", tc.namespace, err)
+		}
+		want := []string{"apply -f -", "apply -f -", tc.wantGet}
+		if got := kubectlCalls(s, "apply", "get"); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%q: expected the calls\n%s\ngot\n%s", tc.namespace, strings.Join(want, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
+
+// TestAddBrokerInvalid tests that invalid brokers are rejected before
+// reading the secret and registering them.
+func TestAddBrokerInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		bc      brokerAddConfig
+		wantErr string
+	}{
+		{"team-broker", brokerAddConfig{}, "broker team-broker: url is required"},
+		{"Team", brokerAddConfig{URL: "https://broker"}, `name "Team" must be a DNS label`},
+		{"team-broker", brokerAddConfig{URL: "https://broker", AuthFromGCPSecret: "projects/p/secrets/s", AuthType: "digest"}, `--auth-type must be basic or bearer, got "digest"`},
+		{"team-broker", brokerAddConfig{URL: "https://broker", AuthFromGCPSecret: "projects/p/secrets/s", AuthType: "basic"}, "auth secretRef of a cluster broker requires a namespace"},
+	} {
+		s, restore := stubExecutor(addBrokerStub)
+		err := addBroker(nil, tc.name, &tc.bc)
+		restore()
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Fatalf("%s: expected an error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+		if len(s.Calls()) != 0 {
+			t.Fatalf("%s: expected no kubectl calls, got %v", tc.name, s.Calls())
+		}
+	}
+}
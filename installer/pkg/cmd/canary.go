@@ -0,0 +1,234 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// canaryDeploymentName is the name of the deployment running the canary api
+// server next to the stable one.
+const canaryDeploymentName = "apiserver-canary"
+
+var (
+	// canaryPollInterval is how often the canary health is checked while
+	// baking.
+	canaryPollInterval = 10 * time.Second
+
+	// canaryReadyTimeout is how long the canary replicas may take to
+	// become ready before the bake starts.
+	canaryReadyTimeout = 5 * time.Minute
+)
+
+// deploymentStatus is the subset of a Deployment read while baking the
+// canary.
+type deploymentStatus struct {
+	Spec struct {
+		Replicas int32 `json:"replicas"`
+	} `json:"spec"`
+	Status struct {
+		ReadyReplicas int32 `json:"readyReplicas"`
+	} `json:"status"`
+}
+
+// podList is the subset of a pod list read while baking the canary.
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Status struct {
+			ContainerStatuses []struct {
+				RestartCount int32 `json:"restartCount"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// canaryUpgradeAPIServer runs image as a canary deployment of the api server
+// in namespace ns, behind the same service as the stable deployment. The
+// canary must stay healthy for bakeTime, otherwise it is deleted and an error
+// is returned. If the canary is healthy it is left running, the caller
+// promotes the new version and then deletes the canary with
+// deleteCanaryDeployment.
+func canaryUpgradeAPIServer(ns, image string, replicas int32, bakeTime time.Duration) error {
+	canary, err := canaryDeployment(ns, image, replicas)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("deploying %d canary api server replica(s) with image %s\n", replicas, image)
+	cmd := kubectlCommand("apply", "-f", "-")
+	cmd.Stdin = bytes.NewReader(canary)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		deleteCanaryDeployment(ns)
+		return fmt.Errorf("error deploying canary api server: %s", string(output))
+	}
+
+	fmt.Printf("baking the canary api server for %v\n", bakeTime)
+	if err := bakeCanary(ns, bakeTime); err != nil {
+		fmt.Println("canary api server is unhealthy, rolling back")
+		deleteCanaryDeployment(ns)
+		return fmt.Errorf("canary api server failed: %v", err)
+	}
+	fmt.Println("canary api server is healthy")
+	return nil
+}
+
+// canaryDeployment returns the canary deployment, a copy of the stable api
+// server deployment running image with replicas replicas. Its pods carry
+// the label track=canary in addition to the labels selected by the service.
+func canaryDeployment(ns, image string, replicas int32) ([]byte, error) {
+	output, err := kubectlCommand("get", "deployment", "apiserver", "--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting api server deployment: %s", string(output))
+	}
+
+	var d map[string]interface{}
+	if err := json.Unmarshal(output, &d); err != nil {
+		return nil, fmt.Errorf("error unmarshalling api server deployment: %v", err)
+	}
+	delete(d, "status")
+
+	meta, _ := d["metadata"].(map[string]interface{})
+	labels, _ := meta["labels"].(map[string]interface{})
+	if labels == nil {
+		labels = map[string]interface{}{}
+	}
+	labels["track"] = "canary"
+	d["metadata"] = map[string]interface{}{
+		"name":      canaryDeploymentName,
+		"namespace": ns,
+		"labels":    labels,
+	}
+
+	spec, _ := d["spec"].(map[string]interface{})
+	template, _ := spec["template"].(map[string]interface{})
+	templateMeta, _ := template["metadata"].(map[string]interface{})
+	podSpec, _ := template["spec"].(map[string]interface{})
+	containers, _ := podSpec["containers"].([]interface{})
+	if templateMeta == nil || containers == nil {
+		return nil, fmt.Errorf("api server deployment has no pod template")
+	}
+
+	podLabels, _ := templateMeta["labels"].(map[string]interface{})
+	if podLabels == nil {
+		podLabels = map[string]interface{}{}
+	}
+	podLabels["track"] = "canary"
+	templateMeta["labels"] = podLabels
+
+	selector := map[string]interface{}{}
+	for k, v := range podLabels {
+		selector[k] = v
+	}
+	spec["selector"] = map[string]interface{}{"matchLabels": selector}
+	spec["replicas"] = replicas
+
+	found := false
+	for _, c := range containers {
+		if c, ok := c.(map[string]interface{}); ok && c["name"] == "apiserver" {
+			c["image"] = image
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("api server deployment has no apiserver container")
+	}
+
+	return json.Marshal(d)
+}
+
+// bakeCanary waits for the canary replicas to become ready, then checks the
+// canary health until bakeTime has passed. The canary is healthy if all its
+// replicas are ready, none of its containers restarted and the APIService is
+// available.
+func bakeCanary(ns string, bakeTime time.Duration) error {
+	readyDeadline := time.Now().Add(canaryReadyTimeout)
+	for {
+		ready, err := checkCanaryPods(ns)
+		if err != nil {
+			return err
+		}
+		if ready {
+			break
+		}
+		if !time.Now().Before(readyDeadline) {
+			return fmt.Errorf("canary replicas did not become ready within %v", canaryReadyTimeout)
+		}
+		time.Sleep(canaryPollInterval)
+	}
+
+	deadline := time.Now().Add(bakeTime)
+	for {
+		ready, err := checkCanaryPods(ns)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return fmt.Errorf("canary replicas are no longer ready")
+		}
+		if err := checkAPIServiceAvailable(); err != nil {
+			return err
+		}
+		if !time.Now().Before(deadline) {
+			return nil
+		}
+		time.Sleep(canaryPollInterval)
+	}
+}
+
+// checkCanaryPods returns whether all canary replicas are ready and an error
+// if any of the canary containers restarted.
+func checkCanaryPods(ns string) (bool, error) {
+	output, err := kubectlCommand("get", "deployment", canaryDeploymentName, "--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("error getting canary deployment: %s", string(output))
+	}
+	var d deploymentStatus
+	if err := json.Unmarshal(output, &d); err != nil {
+		return false, fmt.Errorf("error unmarshalling canary deployment: %v", err)
+	}
+
+	output, err = kubectlCommand("get", "pods", "--namespace", ns, "-l", "app=service-catalog-apiserver,track=canary", "-o", "json").CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("error getting canary pods: %s", string(output))
+	}
+	var pods podList
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return false, fmt.Errorf("error unmarshalling canary pods: %v", err)
+	}
+	for _, p := range pods.Items {
+		for _, c := range p.Status.ContainerStatuses {
+			if c.RestartCount > 0 {
+				return false, fmt.Errorf("canary pod %s restarted %d time(s)", p.Metadata.Name, c.RestartCount)
+			}
+		}
+	}
+
+	return d.Status.ReadyReplicas >= d.Spec.Replicas, nil
+}
+
+func deleteCanaryDeployment(ns string) {
+	output, err := kubectlCommand("delete", "deployment", canaryDeploymentName, "--namespace", ns, "--ignore-not-found").CombinedOutput()
+	if err != nil {
+		fmt.Printf("WARNING: error deleting canary deployment %s/%s: %s\n", ns, canaryDeploymentName, string(output))
+	}
+}
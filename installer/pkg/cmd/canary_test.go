@@ -0,0 +1,153 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// canaryStableDeployment is the stable api server deployment the canary is
+// copied from.
+const canaryStableDeployment = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {"name": "apiserver", "namespace": "catalog", "labels": {"app": "service-catalog-apiserver"}, "resourceVersion": "42"},
+  "spec": {
+    "replicas": 3,
+    "selector": {"matchLabels": {"app": "service-catalog-apiserver"}},
+    "template": {
+      "metadata": {"labels": {"app": "service-catalog-apiserver"}},
+      "spec": {"containers": [
+        {"name": "apiserver", "image": "quay.io/kubernetes-service-catalog/service-catalog:v0.1.11"},
+        {"name": "etcd", "image": "quay.io/coreos/etcd:v3.2.13"}
+      ]}
+    }
+  },
+  "status": {"readyReplicas": 3}
+}`
+
+// canaryStub returns the responses of a cluster whose canary has ready of
+// its replicas ready and whose canary containers restarted restarts times.
+func canaryStub(ready, restarts int) func(name string, args []string) execx.Response {
+	return func(name string, args []string) execx.Response {
+		switch {
+		case args[0] == "get" && args[1] == "deployment" && args[2] == "apiserver":
+			return execx.Response{Stdout: canaryStableDeployment}
+		case args[0] == "get" && args[1] == "deployment":
+			return execx.Response{Stdout: `{"spec": {"replicas": 1}, "status": {"readyReplicas": ` + strconv.Itoa(ready) + `}}`}
+		case args[0] == "get" && args[1] == "pods":
+			return execx.Response{Stdout: `{"items": [{"metadata": {"name": "apiserver-canary-x"},
+"status": {"containerStatuses": [{"restartCount": ` + strconv.Itoa(restarts) + `}]}}]}`}
+		case args[0] == "get" && args[1] == "apiservice":
+			return execx.Response{Stdout: "True"}
+		}
+		return execx.Response{}
+	}
+}
+
+// TestCanaryDeployment tests that the canary is a copy of the stable
+// deployment selecting only its own pods and running the new image.
+func TestCanaryDeployment(t *testing.T) {
+	_, restore := stubExecutor(canaryStub(1, 0))
+	defer restore()
+
+	b, err := canaryDeployment("catalog", "service-catalog:v0.1.12", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error creating the canary: %v", err)
+	}
+	var d struct {
+		Metadata struct {
+			Name      string            `json:"name"`
+			Namespace string            `json:"namespace"`
+			Labels    map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			Replicas int32 `json:"replicas"`
+			Selector struct {
+				MatchLabels map[string]string `json:"matchLabels"`
+			} `json:"selector"`
+			Template struct {
+				Metadata struct {
+					Labels map[string]string `json:"labels"`
+				} `json:"metadata"`
+				Spec struct {
+					Containers []struct {
+						Name  string `json:"name"`
+						Image string `json:"image"`
+					} `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+		Status interface{} `json:"status"`
+	}
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatalf("Unexpected error unmarshalling the canary: %v", err)
+	}
+	labels := map[string]string{"app": "service-catalog-apiserver", "track": "canary"}
+	if d.Metadata.Name != canaryDeploymentName || d.Metadata.Namespace != "catalog" || !reflect.DeepEqual(d.Metadata.Labels, labels) {
+		t.Fatalf("Unexpected canary metadata: %+v", d.Metadata)
+	}
+	if d.Spec.Replicas != 2 || !reflect.DeepEqual(d.Spec.Selector.MatchLabels, labels) || !reflect.DeepEqual(d.Spec.Template.Metadata.Labels, labels) {
+		t.Fatalf("Expected 2 replicas selecting the canary pods, got %+v", d.Spec)
+	}
+	if c := d.Spec.Template.Spec.Containers; len(c) != 2 || c[0].Image != "service-catalog:v0.1.12" || c[1].Image != "quay.io/coreos/etcd:v3.2.13" {
+		t.Fatalf("Expected only the apiserver container to run the new image, got %+v", c)
+	}
+	if d.Status != nil || strings.Contains(string(b), "resourceVersion") {
+		t.Fatalf("Expected the status and the metadata of the stable deployment to be dropped:\n%s", b)
+	}
+}
+
+// TestCanaryUpgradeAPIServer tests that a healthy canary is left running
+// and that a canary restarting or not becoming ready is rolled back.
+func TestCanaryUpgradeAPIServer(t *testing.T) {
+	defer func(interval, timeout time.Duration) {
+		canaryPollInterval, canaryReadyTimeout = interval, timeout
+	}(canaryPollInterval, canaryReadyTimeout)
+	canaryPollInterval, canaryReadyTimeout = time.Millisecond, 10*time.Millisecond
+
+	for _, tc := range []struct {
+		name            string
+		ready, restarts int
+		wantErr         string
+	}{
+		{"healthy", 1, 0, ""},
+		{"restarted", 1, 2, "canary pod apiserver-canary-x restarted 2 time(s)"},
+		{"not ready", 0, 0, "canary replicas did not become ready"},
+	} {
+		s, restore := stubExecutor(canaryStub(tc.ready, tc.restarts))
+		err := canaryUpgradeAPIServer("catalog", "service-catalog:v0.1.12", 1, 0)
+		restore()
+		if (err == nil) != (tc.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Fatalf("%s: expected error %q, got %v", tc.name, tc.wantErr, err)
+		}
+		if calls := kubectlCalls(s, "apply"); len(calls) != 1 {
+			t.Fatalf("%s: expected the canary to be applied once, got %q", tc.name, calls)
+		}
+		deleted := kubectlCalls(s, "delete")
+		if (len(deleted) == 1) != (tc.wantErr != "") {
+			t.Fatalf("%s: expected the canary to be deleted only when unhealthy, got %q", tc.name, deleted)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// capability is an optional feature of the install that needs an API the
+// cluster may not serve.
+type capability struct {
+	// kind is the kind of the objects the feature creates.
+	kind string
+
+	// flag enables the feature.
+	flag string
+
+	// apiVersions are the API versions, in group/version form, serving
+	// kind. The feature is available if any of them is served.
+	apiVersions []string
+
+	// enabled returns the setting of ic enabling the feature.
+	enabled func(ic *InstallConfig) *bool
+}
+
+// capabilities are the optional features depending on the APIs the cluster
+// serves.
+var capabilities = []capability{
+	{"PodDisruptionBudget", "enable-pdb", []string{"policy/v1", "policy/v1beta1"},
+		func(ic *InstallConfig) *bool { return &ic.PodDisruptionBudgets }},
+	{"PriorityClass", "enable-priority-class", []string{"scheduling.k8s.io/v1", "scheduling.k8s.io/v1beta1", "scheduling.k8s.io/v1alpha1"},
+		func(ic *InstallConfig) *bool { return &ic.PriorityClass }},
+	{"NetworkPolicy", "enable-network-policies", []string{"networking.k8s.io/v1"},
+		func(ic *InstallConfig) *bool { return &ic.NetworkPolicies }},
+	{"ServiceMonitor", "enable-service-monitor", []string{"monitoring.coreos.com/v1"},
+		func(ic *InstallConfig) *bool { return &ic.ServiceMonitor }},
+	{"HorizontalPodAutoscaler", "enable-apiserver-hpa", autoscalingAPIVersions,
+		func(ic *InstallConfig) *bool { return &ic.APIServerHPA }},
+}
+
+// autoscalingAPIVersions are the API versions of the HorizontalPodAutoscaler
+// with metric targets, the preferred first.
+var autoscalingAPIVersions = []string{"autoscaling/v2", "autoscaling/v2beta2", "autoscaling/v2beta1", "autoscaling/v2alpha1"}
+
+// disableUnsupported disables the features of ic whose APIs are not in
+// served, a set of API versions, and returns a line describing each
+// disabled feature.
+func disableUnsupported(ic *InstallConfig, served map[string]bool) []string {
+	var report []string
+	for _, c := range capabilities {
+		enabled := c.enabled(ic)
+		if !*enabled {
+			continue
+		}
+		available := false
+		for _, v := range c.apiVersions {
+			available = available || served[v]
+		}
+		if !available {
+			*enabled = false
+			report = append(report, fmt.Sprintf("--%s disabled, the cluster serves no %s API (%s)",
+				c.flag, c.kind, strings.Join(c.apiVersions, ", ")))
+		}
+	}
+	return report
+}
+
+// detectCapabilities disables the features of ic that the cluster kubectl
+// is configured for does not support, printing what was disabled. Nothing
+// is disabled if ic.DetectCapabilities is false or the APIs the cluster
+// serves cannot be listed.
+func detectCapabilities(ic *InstallConfig) {
+	if !ic.DetectCapabilities {
+		return
+	}
+	served, err := servedAPIVersions()
+	if err != nil {
+		// Warn on stderr, generated manifests may be printed to
+		// stdout.
+		fmt.Fprintf(os.Stderr, "WARNING: not detecting the cluster capabilities, error listing the API versions: %v\n", err)
+		return
+	}
+	for _, line := range disableUnsupported(ic, served) {
+		fmt.Fprintf(os.Stderr, "WARNING: %s\n", line)
+	}
+	if ic.APIServerHPA {
+		ic.AutoscalingAPIVersion = preferredServed(autoscalingAPIVersions, served)
+	}
+}
+
+// preferredServed returns the first of versions in served, empty if none
+// is.
+func preferredServed(versions []string, served map[string]bool) string {
+	for _, v := range versions {
+		if served[v] {
+			return v
+		}
+	}
+	return ""
+}
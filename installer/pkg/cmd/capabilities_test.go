@@ -0,0 +1,71 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestDisableUnsupported tests that only the enabled features whose APIs
+// are not served are disabled and reported.
+func TestDisableUnsupported(t *testing.T) {
+	ic := &InstallConfig{PodDisruptionBudgets: true, PriorityClass: true, ServiceMonitor: true}
+	served := map[string]bool{
+		"policy/v1beta1":           true,
+		"networking.k8s.io/v1":     true,
+		"scheduling.k8s.io/v1":     false,
+		"monitoring.coreos.com/v1": false,
+	}
+
+	report := disableUnsupported(ic, served)
+	want := []string{
+		"--enable-priority-class disabled, the cluster serves no PriorityClass API (scheduling.k8s.io/v1, scheduling.k8s.io/v1beta1, scheduling.k8s.io/v1alpha1)",
+		"--enable-service-monitor disabled, the cluster serves no ServiceMonitor API (monitoring.coreos.com/v1)",
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("Report does not match: got %q; want %q", report, want)
+	}
+	if !ic.PodDisruptionBudgets || ic.PriorityClass || ic.NetworkPolicies || ic.ServiceMonitor {
+		t.Fatalf("Features do not match: got pdb %v, priority class %v, network policies %v, service monitor %v; want true, false, false, false",
+			ic.PodDisruptionBudgets, ic.PriorityClass, ic.NetworkPolicies, ic.ServiceMonitor)
+	}
+}
+
+// TestDetectAutoscalingAPIVersion tests that the api server autoscaler uses
+// the preferred autoscaling API version the cluster serves.
+func TestDetectAutoscalingAPIVersion(t *testing.T) {
+	for _, tc := range []struct {
+		served, want string
+	}{
+		{"apps/v1\nautoscaling/v1\nautoscaling/v2beta1\n", "autoscaling/v2beta1"},
+		{"autoscaling/v1\nautoscaling/v2beta1\nautoscaling/v2beta2\n", "autoscaling/v2beta2"},
+		{"autoscaling/v1\nautoscaling/v2\nautoscaling/v2beta2\n", "autoscaling/v2"},
+	} {
+		_, restore := stubExecutor(func(name string, args []string) execx.Response {
+			return execx.Response{Stdout: tc.served}
+		})
+		ic := &InstallConfig{DetectCapabilities: true, APIServerHPA: true}
+		detectCapabilities(ic)
+		restore()
+		if !ic.APIServerHPA || ic.AutoscalingAPIVersion != tc.want {
+			t.Fatalf("Autoscaling API version for %q does not match: got %q, enabled %v; want %q", tc.served, ic.AutoscalingAPIVersion, ic.APIServerHPA, tc.want)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/color"
+	"github.com/spf13/cobra"
+)
+
+// conditionKinds are the catalog kinds whose conditions events reports, by
+// resource.
+var conditionKinds = []struct{ kind, resource string }{
+	{"ClusterServiceBroker", "clusterservicebrokers"},
+	{"ServiceBroker", "servicebrokers"},
+	{"ServiceInstance", "serviceinstances"},
+	{"ServiceBinding", "servicebindings"},
+}
+
+// reportedConditions are the condition types whose changes are reported.
+var reportedConditions = []string{"Ready", "Failed"}
+
+// eventsConfig configures the events feed.
+type eventsConfig struct {
+	// Namespace limits the feed to a namespace, all if empty.
+	Namespace string
+
+	// Kinds limits the feed to the objects of these kinds, all catalog
+	// kinds if empty.
+	Kinds []string
+
+	// CatalogNamespace is the namespace Service Catalog is installed in,
+	// whose events are part of the feed.
+	CatalogNamespace string
+
+	// Follow streams the feed until interrupted instead of printing the
+	// current events and conditions.
+	Follow bool
+}
+
+func NewEventsCmd() *cobra.Command {
+	ec := &eventsConfig{}
+	c := &cobra.Command{
+		Use:   "events",
+		Short: "shows the catalog events and condition changes in one feed",
+		Long: `shows the events of the catalog objects and of the Service Catalog
+components, and the Ready and Failed conditions of the brokers, instances
+and bindings, e.g. a broker becoming ready or an instance failing to
+provision, cluster-wide in one feed. With --follow it watches them and
+prints every new event and condition change until interrupted, e.g. while
+bootstrapping a cluster.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showCatalogEvents(ec, os.Stdout)
+		},
+	}
+	c.Flags().BoolVarP(&ec.Follow, "follow", "f", false, "Watch the events and conditions until interrupted")
+	c.Flags().StringVarP(&ec.Namespace, "namespace", "n", "", "Only show the objects of this namespace and the cluster-scoped ones. Defaults to all namespaces")
+	c.Flags().StringSliceVar(&ec.Kinds, "kind", nil, "Only show the objects of these kinds, e.g. ServiceInstance,ClusterServiceBroker")
+	c.Flags().StringVar(&ec.CatalogNamespace, "catalog-namespace", defaultNamespace, "Namespace Service Catalog is installed in, whose events are shown")
+	return c
+}
+
+// matchesKind returns whether the feed of ec shows the objects of kind.
+func (ec *eventsConfig) matchesKind(kind string) bool {
+	if len(ec.Kinds) == 0 {
+		return true
+	}
+	for _, k := range ec.Kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesEvent returns whether the feed of ec shows e: the events of the
+// catalog objects of the namespace and the cluster-scoped ones and, unless
+// limited to kinds, of the catalog namespace.
+func (ec *eventsConfig) matchesEvent(e *kubeEvent) bool {
+	o := e.InvolvedObject
+	if strings.HasPrefix(o.APIVersion, strings.Split(scAPIVersion, "/")[0]+"/") {
+		if ec.Namespace != "" && o.Namespace != "" && o.Namespace != ec.Namespace {
+			return false
+		}
+		return ec.matchesKind(o.Kind)
+	}
+	return len(ec.Kinds) == 0 && o.Namespace == ec.CatalogNamespace
+}
+
+// scopeArgs returns the kubectl arguments selecting the namespace of ec.
+func (ec *eventsConfig) scopeArgs() []string {
+	if ec.Namespace == "" {
+		return []string{"--all-namespaces"}
+	}
+	return []string{"--namespace", ec.Namespace}
+}
+
+// eventScopes returns the kubectl arguments selecting the namespaces whose
+// events the feed of ec shows. Besides the namespace of ec these are the
+// default namespace, where the events of cluster-scoped objects such as
+// ClusterServiceBrokers are recorded, and the catalog namespace.
+func (ec *eventsConfig) eventScopes() [][]string {
+	if ec.Namespace == "" {
+		return [][]string{ec.scopeArgs()}
+	}
+	namespaces := []string{ec.Namespace, "default"}
+	if len(ec.Kinds) == 0 {
+		namespaces = append(namespaces, ec.CatalogNamespace)
+	}
+	var scopes [][]string
+	for i, ns := range namespaces {
+		if ns == "" || containsString(namespaces[:i], ns) {
+			continue
+		}
+		scopes = append(scopes, []string{"--namespace", ns})
+	}
+	return scopes
+}
+
+// feedObject is the subset of a catalog object read for its conditions.
+type feedObject struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"metadata"`
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+func (o *feedObject) name() string {
+	if o.Metadata.Namespace == "" {
+		return o.Kind + " " + o.Metadata.Name
+	}
+	return o.Kind + " " + o.Metadata.Namespace + "/" + o.Metadata.Name
+}
+
+// conditionTracker remembers the reported conditions of the objects, to
+// report their changes. It is safe for concurrent use.
+type conditionTracker struct {
+	mu    sync.Mutex
+	state map[string]string
+}
+
+func newConditionTracker() *conditionTracker {
+	return &conditionTracker{state: make(map[string]string)}
+}
+
+// changes returns the lines of the conditions of o that changed since it
+// was last seen, colored green when ready, red when failed and yellow
+// otherwise.
+func (t *conditionTracker) changes(o *feedObject) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var lines []string
+	for _, c := range o.Status.Conditions {
+		if !containsString(reportedConditions, c.Type) {
+			continue
+		}
+		key := o.name() + "/" + c.Type
+		state := c.Status + " " + c.Reason
+		if t.state[key] == state {
+			continue
+		}
+		t.state[key] = state
+		line := fmt.Sprintf("%s: %s=%s %s: %s", o.name(), c.Type, c.Status, c.Reason, strings.TrimSpace(c.Message))
+		switch {
+		case c.Type == "Ready" && c.Status == "True":
+			line = color.Green(line)
+		case c.Type == "Failed" && c.Status == "True":
+			line = color.Red(line)
+		case c.Type == "Failed":
+			continue
+		default:
+			line = color.Yellow(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// decodeObjects decodes the objects in the JSON stream r, as written by
+// kubectl get -o json, expanding Lists into their items, and calls fn with
+// each of them.
+func decodeObjects(r io.Reader, fn func(raw json.RawMessage) error) error {
+	d := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := d.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		var list struct {
+			Kind  string            `json:"kind"`
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return err
+		}
+		if list.Kind != "List" && !strings.HasSuffix(list.Kind, "List") {
+			if err := fn(raw); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, item := range list.Items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// eventLine formats e as a line of the feed, colored yellow for warnings.
+func eventLine(e *kubeEvent) string {
+	o := e.InvolvedObject
+	name := o.Name
+	if o.Namespace != "" {
+		name = o.Namespace + "/" + name
+	}
+	line := fmt.Sprintf("%s %s: event %s %s: %s", o.Kind, name, e.Type, e.Reason, strings.TrimSpace(e.Message))
+	if e.Count > 1 {
+		line += fmt.Sprintf(" (x%d)", e.Count)
+	}
+	if e.Type == "Warning" {
+		return color.Yellow(line)
+	}
+	return line
+}
+
+// feedLine is a line of the feed at the time of its event or observation.
+type feedLine struct {
+	time time.Time
+	text string
+}
+
+// showCatalogEvents writes the feed of ec to w: the current events, oldest
+// first, and conditions, or with Follow the stream of their changes.
+func showCatalogEvents(ec *eventsConfig, w io.Writer) error {
+	var (
+		mu      sync.Mutex
+		lines   []feedLine
+		tracker = newConditionTracker()
+		seen    = make(map[string]bool)
+	)
+	emit := func(t time.Time, text string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ec.Follow {
+			fmt.Fprintf(w, "%s %s\n", t.Local().Format("15:04:05"), text)
+			return
+		}
+		lines = append(lines, feedLine{t, text})
+	}
+
+	type source struct {
+		args   []string
+		decode func(raw json.RawMessage) error
+	}
+	decodeEvent := func(raw json.RawMessage) error {
+		var e kubeEvent
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return err
+		}
+		if !ec.matchesEvent(&e) {
+			return nil
+		}
+		// Watches send the updates of the counts of repeated events,
+		// report each count once.
+		key := fmt.Sprintf("%s/%s/%s/%s/%s/%d", e.InvolvedObject.Kind, e.InvolvedObject.Namespace, e.InvolvedObject.Name, e.Reason, e.Message, e.Count)
+		mu.Lock()
+		dup := seen[key]
+		seen[key] = true
+		mu.Unlock()
+		if !dup {
+			emit(e.time(), eventLine(&e))
+		}
+		return nil
+	}
+	var sources []source
+	for _, scope := range ec.eventScopes() {
+		sources = append(sources, source{args: append([]string{"get", "events"}, scope...), decode: decodeEvent})
+	}
+	for _, k := range conditionKinds {
+		if !ec.matchesKind(k.kind) {
+			continue
+		}
+		sources = append(sources, source{
+			args: append([]string{"get", k.resource + "." + strings.Split(scAPIVersion, "/")[0]}, ec.scopeArgs()...),
+			decode: func(raw json.RawMessage) error {
+				var o feedObject
+				if err := json.Unmarshal(raw, &o); err != nil {
+					return err
+				}
+				now := time.Now()
+				for _, l := range tracker.changes(&o) {
+					emit(now, l)
+				}
+				return nil
+			},
+		})
+	}
+
+	errs := make(chan error, len(sources))
+	for _, s := range sources {
+		args := append(s.args, "-o", "json")
+		if ec.Follow {
+			args = append(args, "--watch")
+		}
+		go func(args []string, decode func(raw json.RawMessage) error) {
+			cmd := kubectlCommand(args...)
+			stdout, err := cmd.StdoutPipe()
+			if err != nil {
+				errs <- err
+				return
+			}
+			var stderr strings.Builder
+			cmd.Stderr = &stderr
+			if err := cmd.Start(); err != nil {
+				errs <- fmt.Errorf("error running kubectl %s: %v", strings.Join(args, " "), err)
+				return
+			}
+			derr := decodeObjects(stdout, decode)
+			if err := cmd.Wait(); err != nil {
+				// Clusters running versions without namespaced
+				// brokers don't serve them.
+				if strings.Contains(stderr.String(), "doesn't have a resource type") {
+					errs <- nil
+					return
+				}
+				errs <- fmt.Errorf("error running kubectl %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+				return
+			}
+			errs <- derr
+		}(args, s.decode)
+	}
+	var failed []string
+	for range sources {
+		if err := <-errs; err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+
+	sort.SliceStable(lines, func(a, b int) bool { return lines[a].time.Before(lines[b].time) })
+	for _, l := range lines {
+		fmt.Fprintf(w, "%s %s\n", l.time.Local().Format("15:04:05"), l.text)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/color"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestShowCatalogEvents tests that the feed shows the events of the catalog
+// objects and namespace, oldest first, and the conditions of the objects of
+// the selected kinds, skipping the kinds the cluster does not serve.
+func TestShowCatalogEvents(t *testing.T) {
+	defer color.SetEnabled(color.Enabled())
+	color.SetEnabled(false)
+
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch args[1] {
+		case "events":
+			return execx.Response{Stdout: `{"kind": "List", "items": [
+{"involvedObject": {"apiVersion": "servicecatalog.k8s.io/v1beta1", "kind": "ServiceInstance", "namespace": "prod", "name": "db"},
+ "type": "Warning", "reason": "ProvisionCallFailed", "message": "quota exceeded", "count": 3, "lastTimestamp": "2018-05-01T12:00:02Z"},
+{"involvedObject": {"apiVersion": "v1", "kind": "Pod", "namespace": "service-catalog", "name": "apiserver-x"},
+ "type": "Normal", "reason": "Started", "message": "Started container", "lastTimestamp": "2018-05-01T12:00:01Z"},
+{"involvedObject": {"apiVersion": "v1", "kind": "Pod", "namespace": "prod", "name": "web"},
+ "type": "Normal", "reason": "Started", "message": "Started container", "lastTimestamp": "2018-05-01T12:00:00Z"}
+]}`}
+		case "servicebrokers.servicecatalog.k8s.io":
+			return execx.Response{Stderr: `error: the server doesn't have a resource type "servicebrokers"`, ExitCode: 1}
+		case "clusterservicebrokers.servicecatalog.k8s.io":
+			return execx.Response{Stdout: `{"kind": "List", "items": [{"kind": "ClusterServiceBroker", "metadata": {"name": "gcp"},
+ "status": {"conditions": [{"type": "Ready", "status": "True", "reason": "FetchedCatalog", "message": "Successfully fetched catalog"}]}}]}`}
+		}
+		return execx.Response{Stdout: `{"kind": "List", "items": []}`}
+	})
+	defer restore()
+
+	var b bytes.Buffer
+	if err := showCatalogEvents(&eventsConfig{CatalogNamespace: "service-catalog"}, &b); err != nil {
+		t.Fatalf("Unexpected error showing events: %v", err)
+	}
+	var got []string
+	for _, l := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		// Strip the time.
+		got = append(got, l[9:])
+	}
+	want := []string{
+		"Pod service-catalog/apiserver-x: event Normal Started: Started container",
+		"ServiceInstance prod/db: event Warning ProvisionCallFailed: quota exceeded (x3)",
+		"ClusterServiceBroker gcp: Ready=True FetchedCatalog: Successfully fetched catalog",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Feed does not match:\ngot  %q\nwant %q", got, want)
+	}
+	// The sources are read concurrently.
+	if calls := kubectlCalls(s, "get"); len(calls) != 5 || !containsString(calls, "get events --all-namespaces -o json") {
+		t.Fatalf("Unexpected calls: %q", calls)
+	}
+
+	s, restore = stubExecutor(nil)
+	defer restore()
+	ec := &eventsConfig{Namespace: "prod", Kinds: []string{"serviceinstance"}, CatalogNamespace: "service-catalog", Follow: true}
+	if err := showCatalogEvents(ec, &b); err != nil {
+		t.Fatalf("Unexpected error following events: %v", err)
+	}
+	calls := kubectlCalls(s, "get")
+	if len(calls) != 3 || !containsString(calls, "get serviceinstances.servicecatalog.k8s.io --namespace prod -o json --watch") ||
+		!containsString(calls, "get events --namespace default -o json --watch") {
+		t.Fatalf("Unexpected calls: %q", calls)
+	}
+}
+
+// TestShowNamespaceEvents tests that the feed of a namespace keeps the
+// events of the cluster-scoped brokers, recorded in the default namespace,
+// and of the catalog namespace.
+func TestShowNamespaceEvents(t *testing.T) {
+	defer color.SetEnabled(color.Enabled())
+	color.SetEnabled(false)
+
+	events := map[string]string{
+		"prod": `{"involvedObject": {"apiVersion": "servicecatalog.k8s.io/v1beta1", "kind": "ServiceInstance", "namespace": "prod", "name": "db"},
+ "type": "Normal", "reason": "Provisioning", "message": "provisioning", "lastTimestamp": "2018-05-01T12:00:02Z"}`,
+		"default": `{"involvedObject": {"apiVersion": "servicecatalog.k8s.io/v1beta1", "kind": "ClusterServiceBroker", "name": "gcp"},
+ "type": "Warning", "reason": "ErrorFetchingCatalog", "message": "unauthorized", "lastTimestamp": "2018-05-01T12:00:00Z"},
+{"involvedObject": {"apiVersion": "v1", "kind": "Pod", "namespace": "default", "name": "web"},
+ "type": "Normal", "reason": "Started", "message": "Started container", "lastTimestamp": "2018-05-01T12:00:00Z"}`,
+		"service-catalog": `{"involvedObject": {"apiVersion": "v1", "kind": "Pod", "namespace": "service-catalog", "name": "apiserver-x"},
+ "type": "Normal", "reason": "Started", "message": "Started container", "lastTimestamp": "2018-05-01T12:00:01Z"}`,
+	}
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		if args[1] == "events" && args[2] == "--namespace" {
+			return execx.Response{Stdout: `{"kind": "List", "items": [` + events[args[3]] + `]}`}
+		}
+		return execx.Response{Stdout: `{"kind": "List", "items": []}`}
+	})
+	defer restore()
+
+	var b bytes.Buffer
+	if err := showCatalogEvents(&eventsConfig{Namespace: "prod", CatalogNamespace: "service-catalog"}, &b); err != nil {
+		t.Fatalf("Unexpected error showing events: %v", err)
+	}
+	var got []string
+	for _, l := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		got = append(got, l[9:])
+	}
+	want := []string{
+		"ClusterServiceBroker gcp: event Warning ErrorFetchingCatalog: unauthorized",
+		"Pod service-catalog/apiserver-x: event Normal Started: Started container",
+		"ServiceInstance prod/db: event Normal Provisioning: provisioning",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Feed does not match:\ngot  %q\nwant %q", got, want)
+	}
+	if calls := kubectlCalls(s, "get"); len(calls) != 7 {
+		t.Fatalf("Expected the events of three namespaces and four kinds, got %q", calls)
+	}
+}
+
+// TestConditionTracker tests that only the changes of the Ready and Failed
+// conditions are reported.
+func TestConditionTracker(t *testing.T) {
+	defer color.SetEnabled(color.Enabled())
+	color.SetEnabled(false)
+
+	tr := newConditionTracker()
+	o := &feedObject{Kind: "ServiceInstance"}
+	o.Metadata.Namespace, o.Metadata.Name = "prod", "db"
+	set := func(typ, status, reason string) {
+		o.Status.Conditions = append(o.Status.Conditions[:0], struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		}{typ, status, reason, "m"})
+	}
+
+	set("Ready", "False", "Provisioning")
+	if got := tr.changes(o); len(got) != 1 || got[0] != "ServiceInstance prod/db: Ready=False Provisioning: m" {
+		t.Fatalf("Unexpected changes: %q", got)
+	}
+	if got := tr.changes(o); len(got) != 0 {
+		t.Fatalf("Expected no changes for the same condition, got %q", got)
+	}
+	set("Failed", "True", "ProvisionCallFailed")
+	if got := tr.changes(o); len(got) != 1 || !strings.Contains(got[0], "Failed=True") {
+		t.Fatalf("Unexpected changes: %q", got)
+	}
+	set("Orphaned", "True", "x")
+	if got := tr.changes(o); len(got) != 0 {
+		t.Fatalf("Expected other conditions to be ignored, got %q", got)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"html/template"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver"
+)
+
+// catalogVersionSet is the settings of the templates installing a minor
+// version of Service Catalog.
+type catalogVersionSet struct {
+	// Name is the --catalog-version selecting the set, e.g. v0.2.
+	Name string
+
+	// DefaultVersion is the version installed without --version.
+	DefaultVersion string
+
+	// AdmissionFlag is the api server flag enabling AdmissionPlugins.
+	AdmissionFlag string
+
+	// AdmissionPlugins are the admission plugins of the api server, before
+	// the webhooks enabled by the install flags.
+	AdmissionPlugins []string
+
+	// EncryptionConfigFlag is the api server flag of the encryption
+	// provider config.
+	EncryptionConfigFlag string
+
+	// ClusterIDConfigMap makes the controller manager store its cluster ID
+	// in its namespace.
+	ClusterIDConfigMap bool
+
+	// MinKubernetes is the oldest Kubernetes version the set runs on.
+	MinKubernetes string
+
+	// StorageTypes are the --storage-type values of the api server.
+	StorageTypes []string
+
+	// FeatureGates are the feature gates of the api server and the
+	// controller manager.
+	FeatureGates []string
+}
+
+// catalogVersionSets is the manifest of the catalog version sets, the
+// first is the default.
+var catalogVersionSets = []catalogVersionSet{
+	{
+		Name:                 "v0.1",
+		DefaultVersion:       "0.1.11-gke.0",
+		AdmissionFlag:        "--admission-control",
+		AdmissionPlugins:     []string{"KubernetesNamespaceLifecycle"},
+		EncryptionConfigFlag: "--experimental-encryption-provider-config",
+		MinKubernetes:        "1.7.0",
+		StorageTypes:         []string{"etcd", "crd"},
+		FeatureGates: []string{"AsyncBindingOperations", "CatalogRestrictions", "NamespacedServiceBroker",
+			"OriginatingIdentity", "PodPreset", "ResponseSchema", "ServicePlanDefaults", "UpdateDashboardURL"},
+	},
+	{
+		Name:           "v0.2",
+		DefaultVersion: "0.2.3",
+		AdmissionFlag:  "--enable-admission-plugins",
+		AdmissionPlugins: []string{"KubernetesNamespaceLifecycle", "DefaultServicePlan", "ServiceBindingsLifecycle",
+			"ServicePlanChangeValidator", "BrokerAuthSarCheck"},
+		EncryptionConfigFlag: "--encryption-provider-config",
+		ClusterIDConfigMap:   true,
+		MinKubernetes:        "1.10.0",
+		// The alpha CRD storage and PodPresets are dropped.
+		StorageTypes: []string{"etcd"},
+		FeatureGates: []string{"AsyncBindingOperations", "CascadingDeletion", "CatalogRestrictions", "NamespacedServiceBroker",
+			"OriginatingIdentity", "ResponseSchema", "ServicePlanDefaults", "UpdateDashboardURL"},
+	},
+}
+
+func catalogVersionNames() []string {
+	var names []string
+	for _, s := range catalogVersionSets {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+// catalogVersionSetOf returns the catalog version set of ic: the one
+// selected by --catalog-version, or the one of the minor version of
+// --version, or the default one.
+func catalogVersionSetOf(ic *InstallConfig) (*catalogVersionSet, error) {
+	name := ic.CatalogVersion
+	if name == "" && ic.Version != "" {
+		v, err := semver.NewVersion(ic.Version)
+		if err != nil {
+			return nil, fmt.Errorf("--version %q is not a semantic version such as 0.1.11-gke.0", ic.Version)
+		}
+		name = fmt.Sprintf("v%d.%d", v.Major(), v.Minor())
+		if findCatalogVersionSet(name) == nil {
+			return nil, fmt.Errorf("sc has no templates for Service Catalog %s, use --catalog-version %s with a version they support", ic.Version, strings.Join(catalogVersionNames(), ", "))
+		}
+	}
+	if name == "" {
+		return &catalogVersionSets[0], nil
+	}
+
+	s := findCatalogVersionSet(name)
+	if s == nil {
+		return nil, fmt.Errorf("unknown --catalog-version %q, use one of %s", name, strings.Join(catalogVersionNames(), ", "))
+	}
+	if ic.Version != "" {
+		if v, err := semver.NewVersion(ic.Version); err == nil && fmt.Sprintf("v%d.%d", v.Major(), v.Minor()) != s.Name {
+			return nil, fmt.Errorf("--version %s is not a %s version", ic.Version, s.Name)
+		}
+	}
+	return s, nil
+}
+
+func findCatalogVersionSet(name string) *catalogVersionSet {
+	for i := range catalogVersionSets {
+		if catalogVersionSets[i].Name == name {
+			return &catalogVersionSets[i]
+		}
+	}
+	return nil
+}
+
+// catalogVersion returns the Service Catalog version ic installs.
+func catalogVersion(ic *InstallConfig) string {
+	if ic.Version != "" {
+		return ic.Version
+	}
+	s, err := catalogVersionSetOf(ic)
+	if err != nil {
+		s = &catalogVersionSets[0]
+	}
+	return s.DefaultVersion
+}
+
+// templateData returns the template data of the settings of s.
+func (s *catalogVersionSet) templateData() map[string]interface{} {
+	return map[string]interface{}{
+		"AdmissionFlag":        s.AdmissionFlag,
+		"AdmissionPlugins":     strings.Join(s.AdmissionPlugins, ","),
+		"EncryptionConfigFlag": s.EncryptionConfigFlag,
+		"ClusterIDConfigMap":   s.ClusterIDConfigMap,
+	}
+}
+
+// parsedTemplates caches the parsed templates by the checksum of their
+// content, so that the templates rendered again by reruns in the same
+// process are parsed once.
+var parsedTemplates = struct {
+	sync.Mutex
+	m map[[sha256.Size]byte]*template.Template
+}{m: make(map[[sha256.Size]byte]*template.Template)}
+
+// sharedTemplates is the asset defining the templates the others share,
+// e.g. archAffinity.
+const sharedTemplates = "templates/sc/affinity.tmpl"
+
+// parseTemplate returns the parsed template of asset src, with the shared
+// templates.
+func parseTemplate(src string) (*template.Template, error) {
+	b, err := Asset(src)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+
+	parsedTemplates.Lock()
+	defer parsedTemplates.Unlock()
+	if tp, ok := parsedTemplates.m[sum]; ok {
+		return tp, nil
+	}
+	tp, err := template.New("").Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", src, err)
+	}
+	shared, err := Asset(sharedTemplates)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tp.New(sharedTemplates).Parse(string(shared)); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", sharedTemplates, err)
+	}
+	parsedTemplates.m[sum] = tp
+	return tp, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+)
+
+// TestCatalogVersionSetOf tests how the catalog version set is selected
+// from --catalog-version and --version.
+func TestCatalogVersionSetOf(t *testing.T) {
+	for _, tc := range []struct {
+		catalogVersion, version string
+		want                    string
+		wantErr                 bool
+	}{
+		{"", "", "v0.1", false},
+		{"", "0.1.11-gke.0", "v0.1", false},
+		{"", "0.2.1", "v0.2", false},
+		{"v0.2", "", "v0.2", false},
+		{"v0.2", "0.2.3", "v0.2", false},
+		{"v0.2", "0.1.11-gke.0", "", true},
+		{"v9.9", "", "", true},
+		{"", "9.9.0", "", true},
+	} {
+		ic := &InstallConfig{CatalogVersion: tc.catalogVersion, Version: tc.version}
+		s, err := catalogVersionSetOf(ic)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("--catalog-version %q --version %q: expected an error", tc.catalogVersion, tc.version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("--catalog-version %q --version %q: unexpected error: %v", tc.catalogVersion, tc.version, err)
+		}
+		if s.Name != tc.want {
+			t.Fatalf("--catalog-version %q --version %q: set does not match: got %s; want %s", tc.catalogVersion, tc.version, s.Name, tc.want)
+		}
+	}
+}
+
+// TestCatalogVersion tests the default version of a set.
+func TestCatalogVersion(t *testing.T) {
+	ic := &InstallConfig{CatalogVersion: "v0.2"}
+	if got, want := catalogVersion(ic), "0.2.3"; got != want {
+		t.Fatalf("Version does not match: got %s; want %s", got, want)
+	}
+}
+
+// TestParseTemplateCache tests that templates are parsed once.
+func TestParseTemplateCache(t *testing.T) {
+	a, err := parseTemplate("templates/sc/service.yaml.tmpl")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing template: %v", err)
+	}
+	b, err := parseTemplate("templates/sc/service.yaml.tmpl")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing template: %v", err)
+	}
+	if a != b {
+		t.Fatalf("Template was parsed again")
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/broker-cli/auth"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+)
+
+// caSecretName is the name of the secret the CA is stored in when
+// installing with --store-ca-key.
+const caSecretName = "service-catalog-ca"
+
+// apiServerCertSecretName is the name of the secret holding the api server
+// serving certificate.
+const apiServerCertSecretName = "apiserver-cert"
+
+// minReuseValidity is how long a certificate must remain valid to be reused
+// with --reuse-certs.
+const minReuseValidity = 30 * 24 * time.Hour
+
+// k8sSecret is the subset of a Kubernetes Secret read by the installer.
+type k8sSecret struct {
+	Data map[string]string `json:"data"`
+}
+
+// getSecretData returns the decoded data of secret name in namespace ns.
+// found is false if the secret does not exist.
+func getSecretData(ns, name string) (data map[string][]byte, found bool, err error) {
+	output, err := kubectlCommand("get", "secret", name, "--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "NotFound") {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("error getting secret %s/%s: %s", ns, name, string(output))
+	}
+
+	var s k8sSecret
+	if err = json.Unmarshal(output, &s); err != nil {
+		return nil, false, fmt.Errorf("error unmarshalling secret %s/%s: %v", ns, name, err)
+	}
+
+	data = make(map[string][]byte, len(s.Data))
+	for k, v := range s.Data {
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, false, fmt.Errorf("error decoding key %s of secret %s/%s: %v", k, ns, name, err)
+		}
+		data[k] = b
+	}
+	return data, true, nil
+}
+
+// restoreStoredCA writes the CA stored in the cluster, if any, to
+// caFilePath.pem and caFilePath-key.pem, the files cfssljson would have
+// generated. A CA private key encrypted with Cloud KMS is decrypted with
+// kmsKey. It returns false if no CA is stored.
+func restoreStoredCA(ns, caFilePath, kmsKey string) (bool, error) {
+	data, found, err := getSecretData(ns, caSecretName)
+	if err != nil || !found {
+		return false, err
+	}
+
+	crt, key := data["ca.crt"], data["ca.key"]
+	if encrypted := data["ca.key.kms"]; len(encrypted) > 0 {
+		if kmsKey == "" {
+			return false, fmt.Errorf("the private key in secret %s/%s is encrypted with Cloud KMS, use --kms-key to decrypt it", ns, caSecretName)
+		}
+		client, err := auth.HttpClientWithDefaultCredentials(getContext())
+		if err != nil {
+			return false, err
+		}
+		key, err = gcp.KMSDecrypt(client, kmsKey, encrypted)
+		if err != nil {
+			return false, err
+		}
+	}
+	if len(crt) == 0 || len(key) == 0 {
+		return false, fmt.Errorf("secret %s/%s must contain ca.crt and ca.key or ca.key.kms", ns, caSecretName)
+	}
+
+	if err := ioutil.WriteFile(caFilePath+".pem", crt, 0644); err != nil {
+		return false, err
+	}
+	if err := ioutil.WriteFile(caFilePath+"-key.pem", key, 0600); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// encryptCAKey encrypts the CA private key in keyFile with the Cloud KMS
+// key kmsKey and removes keyFile. It returns the file holding the encrypted
+// key.
+func encryptCAKey(kmsKey, keyFile string) (string, error) {
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(keyFile)
+
+	client, err := auth.HttpClientWithDefaultCredentials(getContext())
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := gcp.KMSEncrypt(client, kmsKey, key)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedFile := keyFile + ".kms"
+	if err := ioutil.WriteFile(encryptedFile, encrypted, 0600); err != nil {
+		return "", err
+	}
+	return encryptedFile, nil
+}
+
+// reuseExistingCerts writes the api server certificate and key of a previous
+// install, and the CA from the caBundle of its APIService, to dir. It returns
+// nil if there is no previous install, and an error if the certificate can't
+// be reused because it doesn't match hosts, expires soon or wasn't signed by
+// the CA trusted by the APIService.
+func reuseExistingCerts(dir, ns string, hosts []string) (*sslArtifacts, error) {
+	data, found, err := getSecretData(ns, apiServerCertSecretName)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	output, err := kubectlCommand("get", "apiservice", scAPIService,
+		"-o", "jsonpath={.spec.caBundle}").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "NotFound") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting APIService %s: %s", scAPIService, string(output))
+	}
+	caPEM, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding caBundle of APIService %s: %v", scAPIService, err)
+	}
+
+	crt, key := data["tls.crt"], data["tls.key"]
+	if err := validateReusableCert(crt, key, caPEM, hosts, time.Now()); err != nil {
+		return nil, fmt.Errorf("can't reuse secret %s/%s: %v", ns, apiServerCertSecretName, err)
+	}
+
+	a := &sslArtifacts{
+		CAFile:                  filepath.Join(dir, "ca.pem"),
+		APIServerCertFile:       filepath.Join(dir, "apiserver.pem"),
+		APIServerPrivateKeyFile: filepath.Join(dir, "apiserver-key.pem"),
+	}
+	for _, f := range []struct {
+		path string
+		b    []byte
+		mode os.FileMode
+	}{
+		{a.CAFile, caPEM, 0644},
+		{a.APIServerCertFile, crt, 0644},
+		{a.APIServerPrivateKeyFile, key, 0600},
+	} {
+		if err := ioutil.WriteFile(f.path, f.b, f.mode); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// validateReusableCert checks that the PEM encoded certificate crt and key
+// key form a pair, that crt is valid for hosts for at least minReuseValidity
+// after now and that it was signed by the CA in caPEM.
+func validateReusableCert(crt, key, caPEM []byte, hosts []string, now time.Time) error {
+	if _, err := tls.X509KeyPair(crt, key); err != nil {
+		return fmt.Errorf("invalid certificate and key: %v", err)
+	}
+	block, _ := pem.Decode(crt)
+	if block == nil {
+		return fmt.Errorf("no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("error parsing certificate: %v", err)
+	}
+
+	if now.Add(minReuseValidity).After(cert.NotAfter) {
+		return fmt.Errorf("certificate expires on %s", cert.NotAfter.Format(time.RFC3339))
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no CA certificate found in the APIService caBundle")
+	}
+	for _, h := range hosts {
+		if _, err := cert.Verify(x509.VerifyOptions{
+			DNSName:     h,
+			Roots:       roots,
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}); err != nil {
+			return fmt.Errorf("certificate is not valid for %s: %v", h, err)
+		}
+	}
+	return nil
+}
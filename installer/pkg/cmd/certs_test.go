@@ -0,0 +1,244 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// testCert returns a PEM encoded certificate and key for hosts, valid until
+// notAfter and signed by parent, or self-signed CA if parent is nil.
+func testCert(t *testing.T, hosts []string, notAfter time.Time, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (crtPEM, keyPEM []byte, cert *x509.Certificate, key *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error generating key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     hosts,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("Unexpected error creating certificate: %v", err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), cert, key
+}
+
+// TestValidateReusableCert tests that only certificates matching the hosts,
+// signed by the APIService CA and not about to expire are reused.
+func TestValidateReusableCert(t *testing.T) {
+	hosts := []string{"service-catalog-api.service-catalog", "service-catalog-api.service-catalog.svc"}
+	now := time.Now()
+	year := now.Add(365 * 24 * time.Hour)
+
+	caPEM, _, ca, caKey := testCert(t, nil, year, nil, nil)
+	otherCAPEM, _, _, _ := testCert(t, nil, year, nil, nil)
+	crt, key, _, _ := testCert(t, hosts, year, ca, caKey)
+	expiring, expiringKey, _, _ := testCert(t, hosts, now.Add(24*time.Hour), ca, caKey)
+	wrongHost, wrongHostKey, _, _ := testCert(t, []string{"other.service-catalog.svc"}, year, ca, caKey)
+	_, otherKey, _, _ := testCert(t, hosts, year, ca, caKey)
+
+	for _, tc := range []struct {
+		name     string
+		crt, key []byte
+		ca       []byte
+		wantErr  bool
+	}{
+		{"valid", crt, key, caPEM, false},
+		{"expiring", expiring, expiringKey, caPEM, true},
+		{"wrong host", wrongHost, wrongHostKey, caPEM, true},
+		{"other CA", crt, key, otherCAPEM, true},
+		{"mismatched key", crt, otherKey, caPEM, true},
+		{"empty", nil, nil, caPEM, true},
+	} {
+		err := validateReusableCert(tc.crt, tc.key, tc.ca, hosts, now)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Fatalf("%s: error does not match: got %v; want error %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+// storedCASecret returns the kubectl response of the CA secret holding data.
+func storedCASecret(data map[string][]byte) execx.Response {
+	var fields []string
+	for k, v := range data {
+		fields = append(fields, fmt.Sprintf("%q: %q", k, base64.StdEncoding.EncodeToString(v)))
+	}
+	return execx.Response{Stdout: `{"data": {` + strings.Join(fields, ", ") + `}}`}
+}
+
+// TestRestoreStoredCA tests that the stored CA is written like cfssljson
+// would, and that incomplete or encrypted secrets are rejected.
+func TestRestoreStoredCA(t *testing.T) {
+	crt, key, _, _ := testCert(t, nil, time.Now().Add(time.Hour), nil, nil)
+	for _, tc := range []struct {
+		name     string
+		secret   execx.Response
+		restored bool
+		wantErr  string
+	}{
+		{"stored", storedCASecret(map[string][]byte{"ca.crt": crt, "ca.key": key}), true, ""},
+		{"not stored", execx.Response{Stderr: `Error from server (NotFound): secrets "service-catalog-ca" not found`, ExitCode: 1}, false, ""},
+		{"no key", storedCASecret(map[string][]byte{"ca.crt": crt}), false, "must contain ca.crt and ca.key"},
+		{"encrypted", storedCASecret(map[string][]byte{"ca.crt": crt, "ca.key.kms": []byte("ciphertext")}), false, "use --kms-key"},
+	} {
+		dir, err := ioutil.TempDir("", "ca")
+		if err != nil {
+			t.Fatalf("Unexpected error creating temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		_, restore := stubExecutor(func(name string, args []string) execx.Response { return tc.secret })
+		restored, err := restoreStoredCA("catalog", filepath.Join(dir, "ca"), "")
+		restore()
+		if restored != tc.restored || (err == nil) != (tc.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Fatalf("%s: got restored %v, error %v; want restored %v, error %q", tc.name, restored, err, tc.restored, tc.wantErr)
+		}
+		if !tc.restored {
+			continue
+		}
+		for file, want := range map[string][]byte{"ca.pem": crt, "ca-key.pem": key} {
+			if got, err := ioutil.ReadFile(filepath.Join(dir, file)); err != nil || !bytes.Equal(got, want) {
+				t.Fatalf("%s: %s does not hold the stored CA: %v", tc.name, file, err)
+			}
+		}
+	}
+}
+
+// TestGenerateStoredCAArtifacts tests that with --store-ca-key the serving
+// certificate is signed by the stored CA, and that a new CA is generated and
+// returned for storing when none is stored.
+func TestGenerateStoredCAArtifacts(t *testing.T) {
+	crt, key, _, _ := testCert(t, nil, time.Now().Add(time.Hour), nil, nil)
+	for _, stored := range []bool{true, false} {
+		dir, err := ioutil.TempDir("", "ssl")
+		if err != nil {
+			t.Fatalf("Unexpected error creating temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		s, restore := stubExecutor(func(name string, args []string) execx.Response {
+			switch {
+			case name == "cfssljson":
+				// cfssljson -bare <prefix> writes the certificate and key.
+				ioutil.WriteFile(args[1]+".pem", []byte("generated"), 0644)
+				ioutil.WriteFile(args[1]+"-key.pem", []byte("generated"), 0600)
+			case stored && name == KubectlBinaryName && args[1] == "secret":
+				return storedCASecret(map[string][]byte{"ca.crt": crt, "ca.key": key})
+			case name == KubectlBinaryName:
+				return execx.Response{Stderr: "NotFound", ExitCode: 1}
+			}
+			return execx.Response{}
+		})
+		ic := validInstallConfig()
+		ic.StoreCAKey = true
+		ssl, err := generateSSLArtifacts(dir, ic)
+		restore()
+		if err != nil {
+			t.Fatalf("stored %v: unexpected error generating the SSL artifacts: %v", stored, err)
+		}
+
+		var genkey bool
+		for _, c := range s.Calls() {
+			genkey = genkey || (c.Name == "cfssl" && c.Args[0] == "genkey")
+		}
+		if genkey == stored {
+			t.Fatalf("stored %v: expected a new CA to be generated only without a stored CA, generated %v", stored, genkey)
+		}
+		wantCA := []byte("generated")
+		if stored {
+			wantCA = crt
+		}
+		if got, err := ioutil.ReadFile(ssl.CAFile); err != nil || !bytes.Equal(got, wantCA) {
+			t.Fatalf("stored %v: unexpected CA %q: %v", stored, got, err)
+		}
+		if ssl.CAPrivateKeyFile != filepath.Join(dir, "ca-key.pem") {
+			t.Fatalf("stored %v: expected the CA private key to be returned for storing, got %q", stored, ssl.CAPrivateKeyFile)
+		}
+	}
+}
+
+// TestRenderCASecret tests that the CA secret is only rendered with the CA
+// private key, stored as ca.key.kms when encrypted with Cloud KMS.
+func TestRenderCASecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ca-secret")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		data map[string]interface{}
+		want []string
+	}{
+		{map[string]interface{}{"Namespace": "catalog", "CAPublicKey": "Y3J0"}, nil},
+		{map[string]interface{}{"Namespace": "catalog", "CAPublicKey": "Y3J0", "CAPrivateKey": "a2V5"},
+			[]string{"name: service-catalog-ca", "namespace: catalog", "ca.crt: Y3J0", "ca.key: a2V5"}},
+		{map[string]interface{}{"Namespace": "catalog", "CAPublicKey": "Y3J0", "CAPrivateKey": "ZW5j", "CAKMSKey": "projects/p/locations/global/keyRings/sc/cryptoKeys/ca"},
+			[]string{"ca.crt: Y3J0", "ca.key.kms: ZW5j"}},
+	} {
+		file := filepath.Join(dir, "ca-secret.yaml")
+		if err := generateFileFromTmpl(file, "templates/sc/ca-secret.yaml.tmpl", tc.data); err != nil {
+			t.Fatalf("Unexpected error rendering the CA secret: %v", err)
+		}
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Unexpected error reading the CA secret: %v", err)
+		}
+		if tc.want == nil && strings.Contains(string(b), "kind: Secret") {
+			t.Fatalf("Expected no secret without the CA private key, got:\n%s", b)
+		}
+		for _, w := range tc.want {
+			if !strings.Contains(string(b), w) {
+				t.Fatalf("CA secret does not contain %q:\n%s", w, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/color"
+	"github.com/spf13/cobra"
+)
+
+// NoColorFlagName is the name of the flag disabling the colors of the
+// output, which are only used on terminals.
+const NoColorFlagName = "no-color"
+
+// SetColor disables the colors of the output if the no-color flag of c is
+// set.
+func SetColor(c *cobra.Command) error {
+	noColor, err := c.Flags().GetBool(NoColorFlagName)
+	if err != nil {
+		return nil
+	}
+	if noColor {
+		color.SetEnabled(false)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+)
+
+// ownerLabels are the labels and annotations other tools mark the objects
+// they manage with, reported with conflicts.
+var ownerLabels = []string{
+	"app.kubernetes.io/managed-by",
+	"heritage",
+	"release",
+	"meta.helm.sh/release-name",
+	"meta.helm.sh/release-namespace",
+}
+
+// ownedObject is the subset of an existing object read to find out who
+// manages it.
+type ownedObject struct {
+	Metadata struct {
+		Labels          map[string]string `json:"labels"`
+		Annotations     map[string]string `json:"annotations"`
+		OwnerReferences []struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+}
+
+// conflict is an existing object, with the name of an object sc creates,
+// managed by another tool.
+type conflict struct {
+	resource releaseResource
+
+	// owner describes the tool managing the object.
+	owner string
+
+	// from is recorded as the origin of the object when it is adopted.
+	from string
+
+	// ownerReferenced is true if the object has owner references, which
+	// would have the garbage collector delete it along with its owner.
+	ownerReferenced bool
+}
+
+// foreignOwner returns the conflict of obj, or nil if obj is unmarked,
+// which previous installs left it, managed by sc or adopted.
+func foreignOwner(obj *ownedObject) *conflict {
+	labels, annotations := obj.Metadata.Labels, obj.Metadata.Annotations
+	if annotations[adoptedFromAnnotation] != "" {
+		return nil
+	}
+
+	var marks []string
+	for _, l := range ownerLabels {
+		if v, ok := labels[l]; ok {
+			marks = append(marks, l+"="+v)
+		} else if v, ok := annotations[l]; ok {
+			marks = append(marks, l+"="+v)
+		}
+	}
+	for _, r := range obj.Metadata.OwnerReferences {
+		marks = append(marks, "owner "+r.Kind+"/"+r.Name)
+	}
+	managedBy := labels["app.kubernetes.io/managed-by"]
+	if len(marks) == 0 || (managedBy == "sc" && len(obj.Metadata.OwnerReferences) == 0) {
+		return nil
+	}
+
+	c := &conflict{owner: strings.Join(marks, ", "), ownerReferenced: len(obj.Metadata.OwnerReferences) > 0}
+	release := annotations["meta.helm.sh/release-name"]
+	if release == "" {
+		release = labels["release"]
+	}
+	switch {
+	case release != "":
+		c.from = "helm/" + release
+	case managedBy != "":
+		c.from = managedBy
+	case labels["heritage"] != "":
+		c.from = strings.ToLower(labels["heritage"])
+	default:
+		r := obj.Metadata.OwnerReferences[0]
+		c.from = strings.ToLower(r.Kind) + "/" + r.Name
+	}
+	return c
+}
+
+// resourceType returns the kubectl resource type of o, its kind qualified
+// by its API group.
+func resourceType(o *manifest.Object) string {
+	if i := strings.Index(o.APIVersion, "/"); i >= 0 {
+		return o.Kind + "." + o.APIVersion[:i]
+	}
+	return o.Kind
+}
+
+// checkConflicts returns an error listing the cluster-scoped objects of the
+// manifests in dir that already exist and are managed by other tools, which
+// install would overwrite. With forceAdopt, the objects are adopted by sc
+// instead.
+func checkConflicts(dir string, forceAdopt bool) error {
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var conflicts []*conflict
+	for _, o := range objs {
+		if !o.ClusterScoped() {
+			continue
+		}
+		output, err := kubectlCommand("get", resourceType(o), o.Name,
+			"--ignore-not-found", "-o", "json").CombinedOutput()
+		if err != nil {
+			// The kind may not be served yet, e.g. before its
+			// CustomResourceDefinition is created.
+			if strings.Contains(string(output), "the server doesn't have a resource type") {
+				continue
+			}
+			return fmt.Errorf("error getting %s %s: %s", o.Kind, o.Name, strings.TrimSpace(string(output)))
+		}
+		if len(strings.TrimSpace(string(output))) == 0 {
+			continue
+		}
+		var obj ownedObject
+		if err := json.Unmarshal(output, &obj); err != nil {
+			return fmt.Errorf("error unmarshalling %s %s: %v", o.Kind, o.Name, err)
+		}
+		if c := foreignOwner(&obj); c != nil {
+			c.resource = releaseResource{Kind: resourceType(o), Name: o.Name}
+			conflicts = append(conflicts, c)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	if !forceAdopt {
+		var lines []string
+		for _, c := range conflicts {
+			lines = append(lines, fmt.Sprintf("   %s (%s)", c.resource, c.owner))
+		}
+		sort.Strings(lines)
+		return messages.Errorf(messages.ObjectConflict, strings.Join(lines, "\n"))
+	}
+	for _, c := range conflicts {
+		fmt.Printf("adopting %s from %s\n", c.resource, c.from)
+		if err := adoptConflict(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adoptConflict marks the object of c as managed by sc.
+func adoptConflict(c *conflict) error {
+	if err := relabelAdopted(c.resource, c.from); err != nil {
+		return err
+	}
+	if !c.ownerReferenced {
+		return nil
+	}
+	output, err := kubectlCommand("patch", c.resource.Kind, c.resource.Name,
+		"--type", "json", "-p", `[{"op": "remove", "path": "/metadata/ownerReferences"}]`).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error removing the owner references of %s: %s", c.resource, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
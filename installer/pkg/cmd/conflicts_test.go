@@ -0,0 +1,62 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestForeignOwner tests that objects marked by other tools conflict, with
+// their owner labels, and that unmarked, sc managed and adopted objects do
+// not.
+func TestForeignOwner(t *testing.T) {
+	for _, tc := range []struct {
+		metadata  string
+		owner     string
+		from      string
+		ownerRefs bool
+	}{
+		{`{}`, "", "", false},
+		{`{"labels": {"app.kubernetes.io/managed-by": "sc"}}`, "", "", false},
+		{`{"labels": {"heritage": "Tiller"}, "annotations": {"servicecatalog.k8s.io/sc-adopted-from": "helm/catalog"}}`, "", "", false},
+		{`{"labels": {"app.kubernetes.io/managed-by": "Helm"}, "annotations": {"meta.helm.sh/release-name": "catalog", "meta.helm.sh/release-namespace": "kube-system"}}`,
+			"app.kubernetes.io/managed-by=Helm, meta.helm.sh/release-name=catalog, meta.helm.sh/release-namespace=kube-system", "helm/catalog", false},
+		{`{"labels": {"heritage": "Tiller", "release": "old"}}`, "heritage=Tiller, release=old", "helm/old", false},
+		{`{"labels": {"app.kubernetes.io/managed-by": "kustomize"}}`, "app.kubernetes.io/managed-by=kustomize", "kustomize", false},
+		{`{"ownerReferences": [{"kind": "Addon", "name": "catalog"}]}`, "owner Addon/catalog", "addon/catalog", true},
+	} {
+		var obj ownedObject
+		if err := json.Unmarshal([]byte(`{"metadata": `+tc.metadata+`}`), &obj); err != nil {
+			t.Fatalf("Unexpected error unmarshalling %s: %v", tc.metadata, err)
+		}
+		c := foreignOwner(&obj)
+		if tc.owner == "" {
+			if c != nil {
+				t.Fatalf("Object %s must not conflict: got owner %q", tc.metadata, c.owner)
+			}
+			continue
+		}
+		if c == nil {
+			t.Fatalf("Object %s must conflict", tc.metadata)
+		}
+		if c.owner != tc.owner || c.from != tc.from || c.ownerReferenced != tc.ownerRefs {
+			t.Fatalf("Conflict of %s does not match: got %q, %q, %v; want %q, %q, %v",
+				tc.metadata, c.owner, c.from, c.ownerReferenced, tc.owner, tc.from, tc.ownerRefs)
+		}
+	}
+}
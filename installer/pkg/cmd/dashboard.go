@@ -0,0 +1,344 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// dashboardEvents is the number of recent events the dashboard shows.
+const dashboardEvents = 8
+
+// maxDashboardBackoff caps the wait between the retries of a dashboard that
+// could not be read, unless the refresh interval is longer.
+const maxDashboardBackoff = time.Minute
+
+// clearScreen moves the cursor to the top left of the terminal and clears
+// it, with ANSI escape codes.
+const clearScreen = "\033[H\033[2J"
+
+// dashboardConfig contains the dashboard configuration.
+type dashboardConfig struct {
+	// Namespace Service Catalog is installed in.
+	Namespace string
+
+	// Interval is how often the dashboard is refreshed.
+	Interval time.Duration
+
+	// Once prints the dashboard once, without clearing the terminal.
+	Once bool
+}
+
+// dashboard is a snapshot of an installation.
+type dashboard struct {
+	Namespace string
+	Time      time.Time
+
+	// Health and Lines are the health of the installation and a line
+	// for each component, as shown by sc status.
+	Health string
+	Lines  []string
+
+	Brokers   []catalogObjectStatus
+	Instances catalogCounts
+	Bindings  catalogCounts
+	Events    []kubeEvent
+
+	// Errors are the sections that could not be read, e.g. when the
+	// catalog API is unavailable.
+	Errors []string
+}
+
+// catalogObjectStatus is the readiness of a broker, instance or binding.
+type catalogObjectStatus struct {
+	Name   string
+	Ready  string
+	Reason string
+	Failed bool
+}
+
+// catalogCounts counts catalog objects by readiness.
+type catalogCounts struct {
+	Ready, InProgress, Failed int
+}
+
+func (c catalogCounts) String() string {
+	return fmt.Sprintf("%d ready, %d in progress, %d failed", c.Ready, c.InProgress, c.Failed)
+}
+
+func (c *catalogCounts) add(s catalogObjectStatus) {
+	switch {
+	case s.Failed:
+		c.Failed++
+	case s.Ready == "True":
+		c.Ready++
+	default:
+		c.InProgress++
+	}
+}
+
+func NewDashboardCmd() *cobra.Command {
+	dc := &dashboardConfig{}
+	c := &cobra.Command{
+		Use:   "dashboard",
+		Short: "shows a live dashboard of a Service Catalog installation",
+		Long: `shows the health of the components of a Service Catalog installation,
+the status of its brokers, the number of instances and bindings by
+readiness and the recent events of its namespace in the terminal, refreshed
+every --interval until interrupted. Errors reading the installation are
+shown and retried with backoff.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDashboard(dc)
+		},
+	}
+	c.Flags().StringVar(&dc.Namespace, "namespace", "service-catalog", "Namespace Service Catalog is installed in")
+	c.Flags().DurationVar(&dc.Interval, "interval", 5*time.Second, "How often to refresh the dashboard")
+	c.Flags().BoolVar(&dc.Once, "once", false, "Print the dashboard once, e.g. into a file")
+	return c
+}
+
+func runDashboard(dc *dashboardConfig) error {
+	if dc.Interval <= 0 {
+		return fmt.Errorf("--interval must be positive, got %v", dc.Interval)
+	}
+	if dc.Once {
+		d, err := collectDashboard(dc.Namespace)
+		if err != nil {
+			return err
+		}
+		renderDashboard(os.Stdout, d, dc.Interval)
+		return nil
+	}
+
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	defer signal.Stop(interrupted)
+	refreshDashboard(dc, os.Stdout, interrupted, time.After)
+	return nil
+}
+
+// refreshDashboard renders the dashboard of dc to w every interval until
+// stop receives, waiting with after. The errors reading the installation,
+// e.g. a transient failure of the API server, are shown below the last
+// dashboard read and retried with exponential backoff.
+func refreshDashboard(dc *dashboardConfig, w io.Writer, stop <-chan os.Signal, after func(time.Duration) <-chan time.Time) {
+	var last *dashboard
+	backoff := dc.Interval
+	for {
+		d, err := collectDashboard(dc.Namespace)
+		wait := dc.Interval
+		if err == nil {
+			last, backoff = d, dc.Interval
+		} else {
+			wait = backoff
+			if backoff *= 2; backoff > maxDashboardBackoff {
+				backoff = maxDashboardBackoff
+			}
+			if backoff < dc.Interval {
+				backoff = dc.Interval
+			}
+		}
+		fmt.Fprint(w, clearScreen)
+		if last != nil {
+			renderDashboard(w, last, dc.Interval)
+		}
+		if err != nil {
+			fmt.Fprintf(w, "\nERROR: %v, retrying in %v\n", err, wait)
+		}
+		select {
+		case <-stop:
+			return
+		case <-after(wait):
+		}
+	}
+}
+
+// collectDashboard reads the snapshot of the installation in namespace ns.
+// Only the errors reading the installation itself are returned, those of
+// the catalog objects are shown on the dashboard.
+func collectDashboard(ns string) (*dashboard, error) {
+	d := &dashboard{Namespace: ns, Time: time.Now()}
+	output, err := kubectlCommand("get", "namespace", ns, "--ignore-not-found", "-o", "name").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting namespace %s: %s", ns, strings.TrimSpace(string(output)))
+	}
+	info, err := getAPIServiceInfo()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		if info == nil {
+			d.Health = healthNotInstalled
+		} else {
+			d.Health, d.Lines = catalogHealth(nil, info)
+		}
+		return d, nil
+	}
+	components, err := catalogComponents(ns)
+	if err != nil {
+		return nil, err
+	}
+	d.Health, d.Lines = catalogHealth(components, info)
+
+	if d.Brokers, err = catalogObjects("clusterservicebrokers.servicecatalog.k8s.io,servicebrokers.servicecatalog.k8s.io"); err != nil {
+		d.Errors = append(d.Errors, err.Error())
+	}
+	for _, c := range []struct {
+		resource string
+		counts   *catalogCounts
+	}{
+		{"serviceinstances.servicecatalog.k8s.io", &d.Instances},
+		{"servicebindings.servicecatalog.k8s.io", &d.Bindings},
+	} {
+		objs, err := catalogObjects(c.resource)
+		if err != nil {
+			d.Errors = append(d.Errors, err.Error())
+		}
+		for _, o := range objs {
+			c.counts.add(o)
+		}
+	}
+	if d.Events, err = recentEvents(ns, dashboardEvents); err != nil {
+		d.Errors = append(d.Errors, err.Error())
+	}
+	return d, nil
+}
+
+// catalogObjects returns the readiness of the catalog objects of resources,
+// a comma separated list, in all namespaces.
+func catalogObjects(resources string) ([]catalogObjectStatus, error) {
+	output, err := kubectlCommand("get", resources, "--all-namespaces", "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %s", resources, strings.TrimSpace(string(output)))
+	}
+	return parseCatalogObjects(output)
+}
+
+func parseCatalogObjects(list []byte) ([]catalogObjectStatus, error) {
+	var l struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Status struct {
+				Conditions []struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+					Reason string `json:"reason"`
+				} `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(list, &l); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the catalog objects: %v", err)
+	}
+	var result []catalogObjectStatus
+	for _, item := range l.Items {
+		s := catalogObjectStatus{Name: item.Metadata.Name, Ready: "Unknown"}
+		if item.Metadata.Namespace != "" {
+			s.Name = item.Metadata.Namespace + "/" + s.Name
+		}
+		for _, c := range item.Status.Conditions {
+			switch c.Type {
+			case "Ready":
+				s.Ready, s.Reason = c.Status, c.Reason
+			case "Failed":
+				s.Failed = c.Status == "True"
+			}
+		}
+		result = append(result, s)
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Name < result[b].Name })
+	return result, nil
+}
+
+// recentEvents returns the last n events of namespace ns, oldest first.
+func recentEvents(ns string, n int) ([]kubeEvent, error) {
+	output, err := kubectlCommand("get", "events", "--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the events of %s: %s", ns, strings.TrimSpace(string(output)))
+	}
+	var l struct {
+		Items []kubeEvent `json:"items"`
+	}
+	if err := json.Unmarshal(output, &l); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the events of %s: %v", ns, err)
+	}
+	events := l.Items
+	sort.SliceStable(events, func(a, b int) bool { return events[a].time().Before(events[b].time()) })
+	if len(events) > n {
+		events = events[len(events)-n:]
+	}
+	return events, nil
+}
+
+// renderDashboard writes d to w, refreshed every interval.
+func renderDashboard(w io.Writer, d *dashboard, interval time.Duration) {
+	fmt.Fprintf(w, "Service Catalog in %s, %s, refreshed %s every %v\n\n",
+		d.Namespace, d.Health, d.Time.Format("15:04:05"), interval)
+	if d.Health == healthNotInstalled {
+		return
+	}
+
+	fmt.Fprintln(w, "COMPONENTS")
+	for _, l := range d.Lines {
+		fmt.Fprintf(w, "  %s\n", l)
+	}
+
+	fmt.Fprintln(w, "\nBROKERS")
+	if len(d.Brokers) == 0 {
+		fmt.Fprintln(w, "  none")
+	} else {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "  NAME\tREADY\tREASON")
+		for _, b := range d.Brokers {
+			ready := b.Ready
+			if b.Failed {
+				ready = "Failed"
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", b.Name, ready, b.Reason)
+		}
+		tw.Flush()
+	}
+
+	fmt.Fprintf(w, "\nINSTANCES  %s\n", d.Instances)
+	fmt.Fprintf(w, "BINDINGS   %s\n", d.Bindings)
+
+	fmt.Fprintln(w, "\nRECENT EVENTS")
+	if len(d.Events) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for i := range d.Events {
+		e := &d.Events[i]
+		fmt.Fprintf(w, "  %s %-7s %s\n", e.time().Format("15:04:05"), e.Type, e)
+	}
+
+	for _, e := range d.Errors {
+		fmt.Fprintf(w, "\nERROR: %s\n", e)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestParseCatalogObjects tests that the readiness of catalog objects is read
+// from their conditions, named with their namespace if they have one.
+func TestParseCatalogObjects(t *testing.T) {
+	list := `{"items": [
+		{"metadata": {"name": "sql", "namespace": "prod"}, "status": {"conditions": [
+			{"type": "Ready", "status": "False", "reason": "ProvisionCallFailed"},
+			{"type": "Failed", "status": "True"}]}},
+		{"metadata": {"name": "gcp"}, "status": {"conditions": [{"type": "Ready", "status": "True", "reason": "FetchedCatalog"}]}},
+		{"metadata": {"name": "new", "namespace": "dev"}}]}`
+	got, err := parseCatalogObjects([]byte(list))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the objects: %v", err)
+	}
+	want := []catalogObjectStatus{
+		{Name: "dev/new", Ready: "Unknown"},
+		{Name: "gcp", Ready: "True", Reason: "FetchedCatalog"},
+		{Name: "prod/sql", Ready: "False", Reason: "ProvisionCallFailed", Failed: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Objects do not match: got %+v; want %+v", got, want)
+	}
+
+	var counts catalogCounts
+	for _, o := range got {
+		counts.add(o)
+	}
+	if got, want := counts.String(), "1 ready, 1 in progress, 1 failed"; got != want {
+		t.Fatalf("Counts do not match: got %q; want %q", got, want)
+	}
+}
+
+// TestRenderDashboard tests that the sections of the dashboard are rendered.
+func TestRenderDashboard(t *testing.T) {
+	d := &dashboard{
+		Namespace: "service-catalog",
+		Time:      time.Date(2018, 1, 2, 15, 4, 5, 0, time.UTC),
+		Health:    healthDegraded,
+		Lines:     []string{"apiserver: 1/1 ready", "controller-manager: 0/1 ready"},
+		Brokers:   []catalogObjectStatus{{Name: "gcp", Ready: "False", Reason: "ErrorFetchingCatalog", Failed: true}},
+		Instances: catalogCounts{Ready: 2},
+		Errors:    []string{"error listing servicebindings"},
+	}
+	d.Events = make([]kubeEvent, 1)
+	d.Events[0].Type, d.Events[0].Reason, d.Events[0].Message = "Warning", "BackOff", "Back-off restarting failed container"
+	d.Events[0].InvolvedObject.Kind, d.Events[0].InvolvedObject.Name = "Pod", "controller-manager-x"
+	d.Events[0].LastTimestamp = d.Time
+
+	var b bytes.Buffer
+	renderDashboard(&b, d, 5*time.Second)
+	for _, want := range []string{
+		"Service Catalog in service-catalog, degraded, refreshed 15:04:05 every 5s\n",
+		"  controller-manager: 0/1 ready\n",
+		"  gcp   Failed  ErrorFetchingCatalog\n",
+		"INSTANCES  2 ready, 0 in progress, 0 failed\n",
+		"  15:04:05 Warning Pod/controller-manager-x: BackOff: Back-off restarting failed container\n",
+		"ERROR: error listing servicebindings\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("Dashboard does not contain %q:\n%s", want, b.String())
+		}
+	}
+}
+
+// TestRefreshDashboardRetries tests that errors reading the installation are
+// shown and retried with exponential backoff, capped, instead of ending the
+// dashboard, and that the backoff is reset once it is read again.
+func TestRefreshDashboardRetries(t *testing.T) {
+	failures := 0
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		if args[0] == "get" && args[1] == "namespace" {
+			if failures++; failures <= 4 {
+				return execx.Response{Stderr: "The connection to the server was refused", ExitCode: 1}
+			}
+			return execx.Response{}
+		}
+		return execx.Response{Stderr: "NotFound", ExitCode: 1}
+	})
+	defer restore()
+
+	stop := make(chan os.Signal, 1)
+	var waits []time.Duration
+	after := func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		if len(waits) == 6 {
+			stop <- os.Interrupt
+			return nil
+		}
+		c := make(chan time.Time, 1)
+		c <- time.Time{}
+		return c
+	}
+	var b bytes.Buffer
+	refreshDashboard(&dashboardConfig{Namespace: "catalog", Interval: 20 * time.Second}, &b, stop, after)
+
+	want := []time.Duration{20 * time.Second, 40 * time.Second, time.Minute, time.Minute, 20 * time.Second, 20 * time.Second}
+	if !reflect.DeepEqual(waits, want) {
+		t.Fatalf("Waits do not match: got %v; want %v", waits, want)
+	}
+	if !strings.Contains(b.String(), "The connection to the server was refused, retrying in 40s") {
+		t.Fatalf("Expected the error to be shown with the retry, got:\n%s", b.String())
+	}
+	if screen := b.String()[strings.LastIndex(b.String(), clearScreen):]; !strings.Contains(screen, healthNotInstalled) || strings.Contains(screen, "ERROR") {
+		t.Fatalf("Expected the dashboard once it is read again, got:\n%s", screen)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// lastAppliedAnnotation holds the manifest kubectl apply last applied to an
+// object, which the pipelines of users apply again.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// deprecatedAPIVersions are the catalog API versions which are no longer
+// served, with the version to migrate to.
+var deprecatedAPIVersions = map[string]string{
+	"servicecatalog.k8s.io/v1alpha1": scAPIVersion,
+}
+
+// deprecatedField is a field of a catalog kind which is deprecated and
+// dropped by later versions of Service Catalog.
+type deprecatedField struct {
+	Kind string
+
+	// Path is the dotted path of the field, e.g. spec.authInfo.basicAuthSecret.
+	Path string
+
+	// Migration tells how to migrate off the field.
+	Migration string
+}
+
+// deprecatedFields are the deprecated fields of the catalog kinds.
+var deprecatedFields = []deprecatedField{
+	{"ClusterServiceBroker", "spec.authInfo.basicAuthSecret", "use spec.authInfo.basic.secretRef"},
+	{"ServiceInstance", "spec.externalClusterServiceClassName", "use spec.clusterServiceClassExternalName"},
+	{"ServiceInstance", "spec.externalClusterServicePlanName", "use spec.clusterServicePlanExternalName"},
+	{"ServiceBinding", "spec.alphaPodPresetTemplate", "PodPresets are removed in Kubernetes v1.20, mount the secret of the binding in the pods"},
+}
+
+// deprecationKinds are the kinds of the objects scanned for deprecated API
+// usage.
+var deprecationKinds = []string{"clusterservicebrokers", "serviceinstances", "servicebindings"}
+
+// deprecationFinding is a use of a deprecated API version or field by an
+// object.
+type deprecationFinding struct {
+	// Object is the kind and name of the object, e.g.
+	// ServiceInstance prod/orders-db.
+	Object string
+
+	// Problem tells what is deprecated and how to migrate off it.
+	Problem string
+
+	// LastApplied is true if the use is in the manifest last applied to
+	// the object rather than in the object.
+	LastApplied bool
+}
+
+func (f deprecationFinding) String() string {
+	s := f.Object + ": " + f.Problem
+	if f.LastApplied {
+		s += " (in the last applied manifest)"
+	}
+	return s
+}
+
+// findDeprecations lists the brokers, instances and bindings of all
+// namespaces using deprecated API versions or fields.
+func findDeprecations() ([]deprecationFinding, error) {
+	var resources []string
+	for _, k := range deprecationKinds {
+		resources = append(resources, k+"."+strings.Split(scAPIVersion, "/")[0])
+	}
+	output, err := kubectlCommand("get", strings.Join(resources, ","),
+		"--all-namespaces", "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing catalog objects: %s", strings.TrimSpace(string(output)))
+	}
+	return parseDeprecations(output)
+}
+
+// parseDeprecations returns the deprecated API usage of the objects of list,
+// in the objects and in their last applied manifests, sorted by object.
+func parseDeprecations(list []byte) ([]deprecationFinding, error) {
+	var l struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(list, &l); err != nil {
+		return nil, fmt.Errorf("error unmarshalling catalog objects: %v", err)
+	}
+	var result []deprecationFinding
+	for _, o := range l.Items {
+		kind, _ := o["kind"].(string)
+		name := kind + " " + objectName(o)
+		result = append(result, objectDeprecations(name, kind, o, false)...)
+
+		if s, ok := fieldValue(o, "metadata.annotations").(map[string]interface{}); ok {
+			applied, _ := s[lastAppliedAnnotation].(string)
+			var a map[string]interface{}
+			if applied != "" && json.Unmarshal([]byte(applied), &a) == nil {
+				result = append(result, objectDeprecations(name, kind, a, true)...)
+			}
+		}
+	}
+	sort.SliceStable(result, func(a, b int) bool { return result[a].Object < result[b].Object })
+	return result, nil
+}
+
+// objectDeprecations returns the deprecated API usage of object o of kind.
+func objectDeprecations(name, kind string, o map[string]interface{}, lastApplied bool) []deprecationFinding {
+	var result []deprecationFinding
+	v, _ := o["apiVersion"].(string)
+	if to, ok := deprecatedAPIVersions[v]; ok {
+		result = append(result, deprecationFinding{
+			Object:      name,
+			Problem:     fmt.Sprintf("apiVersion %s is no longer served, use %s", v, to),
+			LastApplied: lastApplied,
+		})
+	}
+	for _, d := range deprecatedFields {
+		if d.Kind != kind || fieldValue(o, d.Path) == nil {
+			continue
+		}
+		result = append(result, deprecationFinding{
+			Object:      name,
+			Problem:     fmt.Sprintf("%s is deprecated, %s", d.Path, d.Migration),
+			LastApplied: lastApplied,
+		})
+	}
+	return result
+}
+
+// objectName returns the namespace/name of object o, or its name if it is
+// cluster-scoped.
+func objectName(o map[string]interface{}) string {
+	name, _ := fieldValue(o, "metadata.name").(string)
+	if ns, _ := fieldValue(o, "metadata.namespace").(string); ns != "" {
+		return ns + "/" + name
+	}
+	return name
+}
+
+// fieldValue returns the field of o at the dotted path, nil if it is not
+// set.
+func fieldValue(o map[string]interface{}, path string) interface{} {
+	var v interface{} = o
+	for _, p := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[p]
+	}
+	return v
+}
+
+// deprecationReport returns the lines of the migration report of findings.
+func deprecationReport(findings []deprecationFinding) []string {
+	if len(findings) == 0 {
+		return []string{"deprecated API usage: none"}
+	}
+	objects := make(map[string]bool)
+	for _, f := range findings {
+		objects[f.Object] = true
+	}
+	lines := []string{fmt.Sprintf("deprecated API usage: %d catalog objects, migrate them before upgrading to a version dropping these APIs:", len(objects))}
+	for _, f := range findings {
+		lines = append(lines, "  "+f.String())
+	}
+	return lines
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseDeprecations tests that deprecated API versions and fields are
+// found in the objects and in their last applied manifests.
+func TestParseDeprecations(t *testing.T) {
+	list := []byte(`{"items": [
+{"apiVersion": "servicecatalog.k8s.io/v1beta1", "kind": "ServiceInstance",
+ "metadata": {"name": "orders-db", "namespace": "prod", "annotations": {
+  "kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"servicecatalog.k8s.io/v1alpha1\",\"kind\":\"ServiceInstance\",\"spec\":{\"externalClusterServiceClassName\":\"cloud-sql\"}}"}},
+ "spec": {"clusterServiceClassExternalName": "cloud-sql"}},
+{"apiVersion": "servicecatalog.k8s.io/v1beta1", "kind": "ClusterServiceBroker",
+ "metadata": {"name": "gcp"},
+ "spec": {"authInfo": {"basicAuthSecret": {"name": "gcp-auth"}}}},
+{"apiVersion": "servicecatalog.k8s.io/v1beta1", "kind": "ServiceBinding",
+ "metadata": {"name": "orders-db", "namespace": "prod"},
+ "spec": {"secretName": "orders-db"}}
+]}`)
+	findings, err := parseDeprecations(list)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing objects: %v", err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"ClusterServiceBroker gcp: spec.authInfo.basicAuthSecret is deprecated, use spec.authInfo.basic.secretRef",
+		"ServiceInstance prod/orders-db: apiVersion servicecatalog.k8s.io/v1alpha1 is no longer served, use servicecatalog.k8s.io/v1beta1 (in the last applied manifest)",
+		"ServiceInstance prod/orders-db: spec.externalClusterServiceClassName is deprecated, use spec.clusterServiceClassExternalName (in the last applied manifest)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Findings do not match:\ngot  %q\nwant %q", got, want)
+	}
+
+	if report := deprecationReport(findings); len(report) != 4 || report[0] != "deprecated API usage: 2 catalog objects, migrate them before upgrading to a version dropping these APIs:" {
+		t.Fatalf("Unexpected report: %q", report)
+	}
+	if report := deprecationReport(nil); !reflect.DeepEqual(report, []string{"deprecated API usage: none"}) {
+		t.Fatalf("Unexpected report without findings: %q", report)
+	}
+}
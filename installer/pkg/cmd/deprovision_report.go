@@ -0,0 +1,248 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// externalResource is a provisioned ServiceInstance with the external
+// resources its broker destroys when it is deprovisioned.
+type externalResource struct {
+	Instance string `json:"instance"`
+	Service  string `json:"service"`
+	Plan     string `json:"plan"`
+
+	// Resources name the external resources, e.g. "database orders", as
+	// far as the parameters of the instance tell.
+	Resources []string `json:"resources,omitempty"`
+
+	DashboardURL string `json:"dashboardURL,omitempty"`
+}
+
+func (r externalResource) String() string {
+	s := fmt.Sprintf("%s: %s, plan %s", r.Instance, r.Service, r.Plan)
+	if len(r.Resources) > 0 {
+		s += ": " + strings.Join(r.Resources, ", ")
+	}
+	if r.DashboardURL != "" {
+		s += " (" + r.DashboardURL + ")"
+	}
+	return s
+}
+
+// resourceParameters returns the parameters of the create parameter schema
+// of a plan naming the external resources its instances create, e.g. the
+// CloudSQL instance and database or the Pub/Sub topic, sorted, with what
+// they name. Those are the string properties whose names end in name or
+// id, e.g. instanceId or database_name, described by their titles.
+func resourceParameters(schema json.RawMessage) []resourceParameter {
+	var s struct {
+		Properties map[string]struct {
+			Type  string `json:"type"`
+			Title string `json:"title"`
+		} `json:"properties"`
+	}
+	if len(schema) == 0 || json.Unmarshal(schema, &s) != nil {
+		return nil
+	}
+	var params []resourceParameter
+	for key, p := range s.Properties {
+		name := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+		if p.Type != "string" || !(strings.HasSuffix(name, "name") || strings.HasSuffix(name, "id")) {
+			continue
+		}
+		params = append(params, resourceParameter{key: key, what: firstNonEmpty(p.Title, key)})
+	}
+	sort.Slice(params, func(a, b int) bool { return params[a].key < params[b].key })
+	return params
+}
+
+// resourceParameter is an instance parameter naming an external resource.
+type resourceParameter struct {
+	key, what string
+}
+
+// deprovisionInstance is the subset of a ServiceInstance read for the
+// deprovision report.
+type deprovisionInstance struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		ClassExternalName      string `json:"clusterServiceClassExternalName"`
+		PlanExternalName       string `json:"clusterServicePlanExternalName"`
+		NamespacedClassName    string `json:"serviceClassExternalName"`
+		NamespacedPlanName     string `json:"servicePlanExternalName"`
+		ClusterServiceClassRef struct {
+			Name string `json:"name"`
+		} `json:"clusterServiceClassRef"`
+		ClusterServicePlanRef struct {
+			Name string `json:"name"`
+		} `json:"clusterServicePlanRef"`
+		Parameters     map[string]interface{} `json:"parameters"`
+		ParametersFrom []struct {
+			SecretKeyRef struct {
+				Name string `json:"name"`
+			} `json:"secretKeyRef"`
+		} `json:"parametersFrom"`
+	} `json:"spec"`
+	Status struct {
+		ProvisionStatus string `json:"provisionStatus"`
+		DashboardURL    string `json:"dashboardURL"`
+	} `json:"status"`
+}
+
+// listExternalResources lists the provisioned instances of all namespaces
+// with the external resources deprovisioning them destroys.
+func listExternalResources() ([]externalResource, error) {
+	output, err := kubectlCommand("get", "serviceinstances.servicecatalog.k8s.io",
+		"--all-namespaces", "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing instances: %s", strings.TrimSpace(string(output)))
+	}
+	classes, err := kubectlCommand("get", "clusterserviceclasses.servicecatalog.k8s.io",
+		"-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing service classes: %s", strings.TrimSpace(string(classes)))
+	}
+	plans, err := kubectlCommand("get", "clusterserviceplans.servicecatalog.k8s.io",
+		"-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing service plans: %s", strings.TrimSpace(string(plans)))
+	}
+	return parseExternalResources(output, classes, plans)
+}
+
+// parseExternalResources returns the provisioned instances of the list
+// instances, naming their services after the display names of the
+// ClusterServiceClasses of the list classes, and their resources after the
+// parameter schemas of the ClusterServicePlans of the list plans.
+func parseExternalResources(instances, classes, plans []byte) ([]externalResource, error) {
+	var il struct {
+		Items []deprovisionInstance `json:"items"`
+	}
+	if err := json.Unmarshal(instances, &il); err != nil {
+		return nil, fmt.Errorf("error unmarshalling instances: %v", err)
+	}
+	var cl struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Spec struct {
+				ExternalMetadata struct {
+					DisplayName string `json:"displayName"`
+				} `json:"externalMetadata"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(classes, &cl); err != nil {
+		return nil, fmt.Errorf("error unmarshalling service classes: %v", err)
+	}
+	displayNames := make(map[string]string)
+	for _, c := range cl.Items {
+		displayNames[c.Metadata.Name] = c.Spec.ExternalMetadata.DisplayName
+	}
+	var pl struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Spec struct {
+				InstanceCreateParameterSchema json.RawMessage `json:"instanceCreateParameterSchema"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(plans, &pl); err != nil {
+		return nil, fmt.Errorf("error unmarshalling service plans: %v", err)
+	}
+	planParameters := make(map[string][]resourceParameter)
+	for _, p := range pl.Items {
+		planParameters[p.Metadata.Name] = resourceParameters(p.Spec.InstanceCreateParameterSchema)
+	}
+
+	var result []externalResource
+	for _, i := range il.Items {
+		if i.Status.ProvisionStatus != "Provisioned" {
+			continue
+		}
+		r := externalResource{
+			Instance:     i.Metadata.Namespace + "/" + i.Metadata.Name,
+			Service:      firstNonEmpty(i.Spec.ClassExternalName, i.Spec.NamespacedClassName),
+			Plan:         firstNonEmpty(i.Spec.PlanExternalName, i.Spec.NamespacedPlanName),
+			DashboardURL: i.Status.DashboardURL,
+		}
+		if name := displayNames[i.Spec.ClusterServiceClassRef.Name]; name != "" {
+			r.Service = name
+		}
+		for _, p := range planParameters[i.Spec.ClusterServicePlanRef.Name] {
+			if v, ok := i.Spec.Parameters[p.key].(string); ok && v != "" {
+				r.Resources = append(r.Resources, p.what+" "+v)
+			}
+		}
+		for _, f := range i.Spec.ParametersFrom {
+			r.Resources = append(r.Resources, "parameters in secret "+f.SecretKeyRef.Name)
+		}
+		result = append(result, r)
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Instance < result[b].Instance })
+	return result, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// confirmDeprovision shows resources and asks on in to confirm that they are
+// destroyed, unless yes is set. Without a terminal to ask on, it requires
+// yes.
+func confirmDeprovision(resources []externalResource, yes bool, in io.Reader, out io.Writer) error {
+	if len(resources) == 0 {
+		return nil
+	}
+	fmt.Fprintf(out, "deprovisioning destroys the external resources of %d instances:\n", len(resources))
+	for _, r := range resources {
+		fmt.Fprintf(out, "  %s\n", r)
+	}
+	if yes {
+		return nil
+	}
+	if f, ok := in.(*os.File); ok {
+		if fi, err := f.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+			return fmt.Errorf("not asking to confirm the deprovisioning without a terminal, use --yes")
+		}
+	}
+	fmt.Fprintf(out, "type yes to deprovision them: ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	if strings.TrimSpace(answer) != "yes" {
+		return fmt.Errorf("deprovisioning not confirmed, nothing was deleted")
+	}
+	return nil
+}
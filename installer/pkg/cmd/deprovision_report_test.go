@@ -0,0 +1,103 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseExternalResources tests that the provisioned instances are
+// listed with their service display names and the resources named by their
+// parameters, as the parameter schemas of their plans tell.
+func TestParseExternalResources(t *testing.T) {
+	instances := []byte(`{"items": [
+  {"metadata": {"name": "topic", "namespace": "team-b"},
+   "spec": {"clusterServiceClassExternalName": "pubsub", "clusterServicePlanExternalName": "beta",
+            "clusterServiceClassRef": {"name": "class-pubsub"}, "clusterServicePlanRef": {"name": "plan-pubsub"},
+            "parameters": {"topicId": "events", "labels": "team-b"}},
+   "status": {"provisionStatus": "Provisioned"}},
+  {"metadata": {"name": "db", "namespace": "team-a"},
+   "spec": {"clusterServiceClassExternalName": "cloud-sql-mysql", "clusterServicePlanExternalName": "small",
+            "clusterServiceClassRef": {"name": "class-sql"}, "clusterServicePlanRef": {"name": "plan-sql"},
+            "parameters": {"instanceId": "orders-sql", "database_name": "orders", "tier": "db-n1", "replicas_id": 2},
+            "parametersFrom": [{"secretKeyRef": {"name": "db-params", "key": "params"}}]},
+   "status": {"provisionStatus": "Provisioned", "dashboardURL": "https://console.example.com/sql"}},
+  {"metadata": {"name": "pending", "namespace": "team-a"},
+   "spec": {"clusterServiceClassExternalName": "pubsub", "clusterServicePlanExternalName": "beta"},
+   "status": {"provisionStatus": ""}}
+]}`)
+	classes := []byte(`{"items": [{"metadata": {"name": "class-sql"}, "spec": {"externalMetadata": {"displayName": "Cloud SQL for MySQL"}}}]}`)
+	plans := []byte(`{"items": [
+  {"metadata": {"name": "plan-sql"}, "spec": {"instanceCreateParameterSchema": {"type": "object", "properties": {
+    "instanceId": {"type": "string", "title": "CloudSQL instance"},
+    "database_name": {"type": "string", "title": "database"},
+    "tier": {"type": "string", "title": "Machine type"},
+    "replicas_id": {"type": "integer"}}}}},
+  {"metadata": {"name": "plan-pubsub"}, "spec": {"instanceCreateParameterSchema": {"type": "object", "properties": {
+    "topicId": {"type": "string"},
+    "labels": {"type": "string", "title": "Labels"}}}}}
+]}`)
+
+	got, err := parseExternalResources(instances, classes, plans)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []externalResource{
+		{
+			Instance:     "team-a/db",
+			Service:      "Cloud SQL for MySQL",
+			Plan:         "small",
+			Resources:    []string{"database orders", "CloudSQL instance orders-sql", "parameters in secret db-params"},
+			DashboardURL: "https://console.example.com/sql",
+		},
+		{Instance: "team-b/topic", Service: "pubsub", Plan: "beta", Resources: []string{"topicId events"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("External resources do not match: got %+v; want %+v", got, want)
+	}
+}
+
+// TestConfirmDeprovision tests that deprovisioning requires typing yes,
+// unless --yes is given.
+func TestConfirmDeprovision(t *testing.T) {
+	resources := []externalResource{{Instance: "team-b/topic", Service: "pubsub", Plan: "beta", Resources: []string{"topic events"}}}
+	for _, tc := range []struct {
+		answer string
+		yes    bool
+		ok     bool
+	}{
+		{"yes\n", false, true},
+		{"no\n", false, false},
+		{"", false, false},
+		{"", true, true},
+	} {
+		var out bytes.Buffer
+		err := confirmDeprovision(resources, tc.yes, strings.NewReader(tc.answer), &out)
+		if (err == nil) != tc.ok {
+			t.Fatalf("Unexpected result for answer %q and --yes=%v: %v", tc.answer, tc.yes, err)
+		}
+		if !strings.Contains(out.String(), "team-b/topic: pubsub, plan beta: topic events") {
+			t.Fatalf("Output must list the resources: got %q", out.String())
+		}
+	}
+	if err := confirmDeprovision(nil, false, strings.NewReader(""), &bytes.Buffer{}); err != nil {
+		t.Fatalf("Unexpected error without resources: %v", err)
+	}
+}
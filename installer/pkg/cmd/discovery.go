@@ -0,0 +1,192 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// catalogKinds are the kinds the OpenAPI spec of the catalog API defines.
+var catalogKinds = []string{"ClusterServiceBroker", "ClusterServiceClass", "ClusterServicePlan", "ServiceInstance", "ServiceBinding"}
+
+// discoveryFailureRE matches the error of discovery clients when aggregated
+// APIs are registered but not served, e.g. unable to retrieve the complete
+// list of server APIs: servicecatalog.k8s.io/v1beta1: the server is
+// currently unable to handle the request.
+var discoveryFailureRE = regexp.MustCompile(`unable to retrieve the complete list of server APIs: (.*)`)
+
+// klogErrorRE matches the error lines of the kube-apiserver logs, e.g.
+// E1014 10:00:00.000000       1 controller.go:114] ...
+var klogErrorRE = regexp.MustCompile(`^E[0-9]{4} `)
+
+// discoveryVerifyChecks returns the checks that the catalog API is
+// discovered and aggregated into the OpenAPI spec of the cluster.
+func discoveryVerifyChecks() []verifyCheck {
+	return []verifyCheck{
+		{name: "kubectl api-resources lists the catalog resources", run: checkAPIResources},
+		{name: "the OpenAPI spec of the cluster defines the catalog kinds", run: checkOpenAPIAggregation},
+		{name: "kube-apiserver logs show no OpenAPI aggregation errors", run: checkOpenAPILogs},
+	}
+}
+
+// checkAPIResources checks that the discovery client of kubectl lists the
+// catalog resources. Discovery clients fail on all the groups when one
+// registered APIService is not served, which breaks kubectl, namespace
+// deletion and garbage collection cluster-wide.
+func checkAPIResources() error {
+	output, err := kubectlCommand("api-resources", "-o", "name").CombinedOutput()
+	return checkAPIResourcesOutput(string(output), err)
+}
+
+func checkAPIResourcesOutput(output string, err error) error {
+	listed := make(map[string]bool)
+	var broken []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if m := discoveryFailureRE.FindStringSubmatch(line); m != nil {
+			broken = append(broken, m[1])
+			continue
+		}
+		listed[line] = true
+	}
+	if len(broken) > 0 {
+		return fmt.Errorf("discovery is broken, the api server cannot reach %s. "+
+			"Discovery clients, namespace deletion and garbage collection fail until these APIServices are served or deleted, "+
+			"run `sc doctor` to diagnose the catalog APIService", strings.Join(broken, "; "))
+	}
+	if err != nil {
+		return fmt.Errorf("error listing the API resources: %s", strings.TrimSpace(output))
+	}
+
+	group := strings.Split(scAPIVersion, "/")[0]
+	var missing []string
+	for _, r := range catalogResources {
+		if !listed[r+"."+group] {
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("kubectl api-resources does not list %s of %s", strings.Join(missing, ", "), group)
+	}
+	return nil
+}
+
+// checkOpenAPIAggregation checks that the main API server merged the OpenAPI
+// spec of the catalog api server into its own. kubectl explain and the
+// client-side validation of kubectl apply use it.
+func checkOpenAPIAggregation() error {
+	output, err := kubectlCommand("get", "--raw", "/openapi/v2").Output()
+	if err != nil {
+		return fmt.Errorf("error getting the OpenAPI spec: %v", err)
+	}
+	return checkOpenAPIDefinitions(output)
+}
+
+// checkOpenAPIDefinitions checks that the OpenAPI spec defines the catalog
+// kinds, as tagged by their group, version and kind, or named after the
+// catalog API package.
+func checkOpenAPIDefinitions(spec []byte) error {
+	var s struct {
+		Definitions map[string]struct {
+			GVKs []struct {
+				Group   string `json:"group"`
+				Version string `json:"version"`
+				Kind    string `json:"kind"`
+			} `json:"x-kubernetes-group-version-kind"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(spec, &s); err != nil {
+		return fmt.Errorf("error unmarshalling the OpenAPI spec: %v", err)
+	}
+	defined := make(map[string]bool)
+	for name, d := range s.Definitions {
+		for _, gvk := range d.GVKs {
+			if gvk.Group+"/"+gvk.Version == scAPIVersion {
+				defined[gvk.Kind] = true
+			}
+		}
+		if strings.Contains(name, "servicecatalog") {
+			defined[name[strings.LastIndex(name, ".")+1:]] = true
+		}
+	}
+	var missing []string
+	for _, k := range catalogKinds {
+		if !defined[k] {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the OpenAPI spec does not define %s of %s, the main API server failed to aggregate the catalog spec, "+
+			"see the kube-apiserver logs for OpenAPI errors", strings.Join(missing, ", "), scAPIVersion)
+	}
+	return nil
+}
+
+// checkOpenAPILogs checks the logs of the kube-apiserver pods for errors
+// downloading or merging the OpenAPI spec of the catalog. It is skipped if
+// the control plane pods are not visible, e.g. on GKE.
+func checkOpenAPILogs() error {
+	selector := "component=kube-apiserver"
+	output, err := kubectlCommand("get", "pods", "--namespace", "kube-system",
+		"-l", selector, "-o", "name").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error listing the kube-apiserver pods: %s", strings.TrimSpace(string(output)))
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return skipCheck("the kube-apiserver pods are not visible, e.g. on a managed control plane")
+	}
+	output, err = kubectlCommand("logs", "--namespace", "kube-system",
+		"-l", selector, "--tail", "2000").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error reading the kube-apiserver logs: %s", strings.TrimSpace(string(output)))
+	}
+	if errs := openAPILogErrors(string(output)); len(errs) > 0 {
+		return fmt.Errorf("the kube-apiserver failed to aggregate the OpenAPI spec of %s:\n   %s", scAPIService, strings.Join(errs, "\n   "))
+	}
+	return nil
+}
+
+// openAPILogErrors returns the last distinct errors of the kube-apiserver
+// logs about the OpenAPI spec of the catalog, without their klog header.
+func openAPILogErrors(logs string) []string {
+	seen := make(map[string]bool)
+	var errs []string
+	for _, line := range strings.Split(logs, "\n") {
+		if !klogErrorRE.MatchString(line) || !strings.Contains(line, "servicecatalog") {
+			continue
+		}
+		lower := strings.ToLower(line)
+		if !strings.Contains(lower, "openapi") && !strings.Contains(lower, "merge") {
+			continue
+		}
+		msg := line
+		if i := strings.Index(line, "] "); i >= 0 {
+			msg = line[i+2:]
+		}
+		if !seen[msg] {
+			seen[msg] = true
+			errs = append(errs, msg)
+		}
+	}
+	if len(errs) > 5 {
+		errs = errs[len(errs)-5:]
+	}
+	return errs
+}
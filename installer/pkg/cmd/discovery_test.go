@@ -0,0 +1,83 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestCheckAPIResourcesOutput tests that missing catalog resources and the
+// broken discovery of unserved APIServices are reported.
+func TestCheckAPIResourcesOutput(t *testing.T) {
+	var all []string
+	for _, r := range catalogResources {
+		all = append(all, r+".servicecatalog.k8s.io")
+	}
+	listed := "pods\n" + strings.Join(all, "\n") + "\n"
+	if err := checkAPIResourcesOutput(listed, nil); err != nil {
+		t.Fatalf("Unexpected error checking the listed resources: %v", err)
+	}
+
+	err := checkAPIResourcesOutput("pods\nserviceinstances.servicecatalog.k8s.io\n", nil)
+	if err == nil || !strings.Contains(err.Error(), "clusterservicebrokers") {
+		t.Fatalf("Expected an error naming the missing resources, got %v", err)
+	}
+
+	broken := "pods\nerror: unable to retrieve the complete list of server APIs: servicecatalog.k8s.io/v1beta1: the server is currently unable to handle the request\n"
+	err = checkAPIResourcesOutput(broken, errors.New("exit status 1"))
+	if err == nil || !strings.Contains(err.Error(), "discovery is broken, the api server cannot reach servicecatalog.k8s.io/v1beta1:") {
+		t.Fatalf("Expected an error flagging the broken discovery, got %v", err)
+	}
+}
+
+// TestCheckOpenAPIDefinitions tests that the catalog kinds are found by
+// their group, version and kind extension or by their definition name.
+func TestCheckOpenAPIDefinitions(t *testing.T) {
+	spec := `{"definitions": {
+		"io.k8s.api.core.v1.Pod": {"x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "Pod"}]},
+		"io.k8s.servicecatalog.v1beta1.ClusterServiceBroker": {"x-kubernetes-group-version-kind": [{"group": "servicecatalog.k8s.io", "version": "v1beta1", "kind": "ClusterServiceBroker"}]},
+		"github.com/kubernetes-incubator/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterServiceClass": {},
+		"github.com/kubernetes-incubator/service-catalog/pkg/apis/servicecatalog/v1beta1.ClusterServicePlan": {},
+		"github.com/kubernetes-incubator/service-catalog/pkg/apis/servicecatalog/v1beta1.ServiceInstance": {}}}`
+	err := checkOpenAPIDefinitions([]byte(spec))
+	if err == nil || !strings.HasPrefix(err.Error(), "the OpenAPI spec does not define ServiceBinding of") {
+		t.Fatalf("Expected an error naming ServiceBinding, got %v", err)
+	}
+
+	spec = strings.Replace(spec, "}}}", `}, "io.k8s.servicecatalog.v1beta1.ServiceBinding": {}}}`, 1)
+	if err := checkOpenAPIDefinitions([]byte(spec)); err != nil {
+		t.Fatalf("Unexpected error checking the definitions: %v", err)
+	}
+}
+
+// TestOpenAPILogErrors tests that only the distinct errors about the OpenAPI
+// spec of the catalog are kept from the kube-apiserver logs.
+func TestOpenAPILogErrors(t *testing.T) {
+	logs := `I1014 10:00:00.000000       1 aggregator.go:109] OpenAPI AggregationController: action for item v1beta1.servicecatalog.k8s.io: Rate Limited Requeue.
+E1014 10:00:01.000000       1 controller.go:114] loading OpenAPI spec for "v1beta1.servicecatalog.k8s.io" failed with: failed to retrieve openAPI spec, http error: ResponseCode: 503
+E1014 10:00:02.000000       1 controller.go:114] loading OpenAPI spec for "v1beta1.servicecatalog.k8s.io" failed with: failed to retrieve openAPI spec, http error: ResponseCode: 503
+E1014 10:00:03.000000       1 controller.go:114] loading OpenAPI spec for "v1beta1.metrics.k8s.io" failed with: failed to retrieve openAPI spec
+E1014 10:00:04.000000       1 available_controller.go:420] v1beta1.servicecatalog.k8s.io failed with: failing or missing response
+`
+	want := []string{`loading OpenAPI spec for "v1beta1.servicecatalog.k8s.io" failed with: failed to retrieve openAPI spec, http error: ResponseCode: 503`}
+	if got := openAPILogErrors(logs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Errors do not match: got %q; want %q", got, want)
+	}
+}
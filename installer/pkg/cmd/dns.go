@@ -0,0 +1,149 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Limits of the pod DNS config enforced by Kubernetes.
+const (
+	maxDNSNameservers = 3
+	maxDNSSearches    = 6
+)
+
+// hostAlias is an /etc/hosts entry of the controller manager pod.
+type hostAlias struct {
+	IP        string
+	Hostnames []string
+}
+
+// dnsOption is a resolver option of the controller manager pod, e.g. ndots.
+type dnsOption struct {
+	Name  string
+	Value string
+}
+
+// dnsConfig is the DNS config of the controller manager pod, merged with the
+// one of its DNS policy.
+type dnsConfig struct {
+	Nameservers []string
+	Searches    []string
+	Options     []dnsOption
+}
+
+// parseHostAliases parses aliases given as hostname=ip, grouping the
+// hostnames by IP in the order given.
+func parseHostAliases(aliases []string) ([]hostAlias, error) {
+	var result []hostAlias
+	index := make(map[string]int)
+	for _, a := range aliases {
+		parts := strings.SplitN(a, "=", 2)
+		if len(parts) != 2 || !dns1123SubdomainRE.MatchString(parts[0]) || net.ParseIP(parts[1]) == nil {
+			return nil, fmt.Errorf("invalid --controller-manager-host-alias %q: expected <hostname>=<ip>, e.g. broker.corp.example.com=10.0.0.5", a)
+		}
+		ip := net.ParseIP(parts[1]).String()
+		i, ok := index[ip]
+		if !ok {
+			i = len(result)
+			index[ip] = i
+			result = append(result, hostAlias{IP: ip})
+		}
+		result[i].Hostnames = append(result[i].Hostnames, parts[0])
+	}
+	return result, nil
+}
+
+// parseDNSConfig returns the DNS config of the controller manager of ic, nil
+// if it sets none.
+func parseDNSConfig(ic *InstallConfig) (*dnsConfig, error) {
+	if len(ic.ControllerManagerDNSNameservers) == 0 && len(ic.ControllerManagerDNSSearches) == 0 && len(ic.ControllerManagerDNSOptions) == 0 {
+		return nil, nil
+	}
+	c := &dnsConfig{Searches: ic.ControllerManagerDNSSearches}
+	if len(ic.ControllerManagerDNSNameservers) > maxDNSNameservers {
+		return nil, fmt.Errorf("--controller-manager-dns-nameserver accepts at most %d nameservers, got %d", maxDNSNameservers, len(ic.ControllerManagerDNSNameservers))
+	}
+	for _, ns := range ic.ControllerManagerDNSNameservers {
+		ip := net.ParseIP(ns)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid --controller-manager-dns-nameserver %q: expected an IP address", ns)
+		}
+		c.Nameservers = append(c.Nameservers, ip.String())
+	}
+	if len(c.Searches) > maxDNSSearches {
+		return nil, fmt.Errorf("--controller-manager-dns-search accepts at most %d domains, got %d", maxDNSSearches, len(c.Searches))
+	}
+	for _, s := range c.Searches {
+		if !dns1123SubdomainRE.MatchString(s) {
+			return nil, fmt.Errorf("invalid --controller-manager-dns-search %q: expected a DNS domain", s)
+		}
+	}
+	for _, o := range ic.ControllerManagerDNSOptions {
+		parts := strings.SplitN(o, "=", 2)
+		if parts[0] == "" {
+			return nil, fmt.Errorf("invalid --controller-manager-dns-option %q: expected <name>[=<value>], e.g. ndots=2", o)
+		}
+		opt := dnsOption{Name: parts[0]}
+		if len(parts) == 2 {
+			opt.Value = parts[1]
+		}
+		c.Options = append(c.Options, opt)
+	}
+	return c, nil
+}
+
+// validateDNS checks the name resolution options of the controller manager
+// of ic.
+func validateDNS(ic *InstallConfig) error {
+	if _, err := parseHostAliases(ic.ControllerManagerHostAliases); err != nil {
+		return err
+	}
+	if _, err := parseDNSConfig(ic); err != nil {
+		return err
+	}
+	switch ic.ControllerManagerDNSPolicy {
+	case "", "ClusterFirst", "Default":
+	case "None":
+		if len(ic.ControllerManagerDNSNameservers) == 0 {
+			return fmt.Errorf("--controller-manager-dns-policy=None requires --controller-manager-dns-nameserver")
+		}
+	default:
+		return fmt.Errorf("--controller-manager-dns-policy must be ClusterFirst, Default or None, got %q", ic.ControllerManagerDNSPolicy)
+	}
+	return nil
+}
+
+// dnsData returns the template data of the name resolution of the
+// controller manager.
+func dnsData(ic *InstallConfig) (map[string]interface{}, error) {
+	aliases, err := parseHostAliases(ic.ControllerManagerHostAliases)
+	if err != nil {
+		return nil, err
+	}
+	config, err := parseDNSConfig(ic)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"ControllerManagerHostAliases": aliases,
+		"ControllerManagerDNSPolicy":   ic.ControllerManagerDNSPolicy,
+		"ControllerManagerDNSConfig":   config,
+	}, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseHostAliases tests that the hostnames are grouped by IP in the
+// order given.
+func TestParseHostAliases(t *testing.T) {
+	got, err := parseHostAliases([]string{"a.example.com=10.0.0.5", "b.example.com=fd00::1", "c.example.com=10.0.0.5"})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing host aliases: %v", err)
+	}
+	want := []hostAlias{
+		{IP: "10.0.0.5", Hostnames: []string{"a.example.com", "c.example.com"}},
+		{IP: "fd00::1", Hostnames: []string{"b.example.com"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Host aliases do not match: got %+v; want %+v", got, want)
+	}
+
+	for _, a := range []string{"a.example.com", "a.example.com=broker", "A_B=10.0.0.5"} {
+		if _, err := parseHostAliases([]string{a}); err == nil {
+			t.Fatalf("Expected an error parsing %q", a)
+		}
+	}
+}
+
+// TestValidateDNS tests the checks of the DNS settings of the controller
+// manager.
+func TestValidateDNS(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		modify  func(ic *InstallConfig)
+		wantErr string
+	}{
+		{"none set", func(ic *InstallConfig) {}, ""},
+		{"valid", func(ic *InstallConfig) {
+			ic.ControllerManagerDNSPolicy = "None"
+			ic.ControllerManagerDNSNameservers = []string{"10.0.0.2"}
+			ic.ControllerManagerDNSSearches = []string{"corp.example.com"}
+			ic.ControllerManagerDNSOptions = []string{"ndots=2", "edns0"}
+		}, ""},
+		{"None without nameservers", func(ic *InstallConfig) { ic.ControllerManagerDNSPolicy = "None" }, "requires --controller-manager-dns-nameserver"},
+		{"unknown policy", func(ic *InstallConfig) { ic.ControllerManagerDNSPolicy = "ClusterFirstWithHostNet" }, "--controller-manager-dns-policy must be"},
+		{"invalid nameserver", func(ic *InstallConfig) { ic.ControllerManagerDNSNameservers = []string{"dns.example.com"} }, "expected an IP address"},
+		{"too many nameservers", func(ic *InstallConfig) {
+			ic.ControllerManagerDNSNameservers = []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}
+		}, "at most 3"},
+		{"invalid search", func(ic *InstallConfig) { ic.ControllerManagerDNSSearches = []string{"Corp_Example"} }, "expected a DNS domain"},
+		{"invalid option", func(ic *InstallConfig) { ic.ControllerManagerDNSOptions = []string{"=2"} }, "expected <name>[=<value>]"},
+		{"invalid host alias", func(ic *InstallConfig) { ic.ControllerManagerHostAliases = []string{"10.0.0.5"} }, "expected <hostname>=<ip>"},
+	} {
+		ic := &InstallConfig{}
+		tc.modify(ic)
+		err := validateDNS(ic)
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("%s: expected an error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// doctorConfig contains the doctor configuration.
+type doctorConfig struct {
+	// Namespace Service Catalog is installed in.
+	Namespace string
+
+	// Fix applies the automated fixes of the problems found.
+	Fix bool
+}
+
+// finding is a known failure fingerprint found by doctor.
+type finding struct {
+	problem    string
+	suggestion string
+
+	// fix remediates the problem, nil if it must be fixed manually.
+	fix func() error
+}
+
+// doctorCheck looks for the failure fingerprints of one component.
+type doctorCheck struct {
+	name string
+	run  func(ns string) ([]finding, error)
+}
+
+func NewDoctorCmd() *cobra.Command {
+	dc := &doctorConfig{}
+	c := &cobra.Command{
+		Use:   "doctor",
+		Short: "diagnoses common Service Catalog failures",
+		Long: `diagnoses common Service Catalog failures, such as expired
+certificates, an APIService caBundle not matching the api server
+certificate, OOM killed etcd members and brokers rejecting their
+credentials, and suggests how to fix them. With --fix, the problems that
+can be fixed automatically are.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := runDoctor(dc); err != nil {
+				messages.Println(messages.DoctorFailed)
+				return err
+			}
+			messages.Println(messages.Healthy)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&dc.Namespace, "namespace", "service-catalog", "Namespace Service Catalog is installed in")
+	c.Flags().BoolVar(&dc.Fix, "fix", false, "Apply the automated fixes of the problems found")
+	return c
+}
+
+// doctorChecks returns the checks run by doctor, in order.
+func doctorChecks() []doctorCheck {
+	return []doctorCheck{
+		{name: "api server certificate", run: checkAPIServerCert},
+		{name: "APIService", run: checkAPIService},
+		{name: "pods", run: checkOOMKilled},
+		{name: "brokers", run: checkBrokerAuth},
+	}
+}
+
+func runDoctor(dc *doctorConfig) error {
+	unresolved := 0
+	for _, c := range doctorChecks() {
+		findings, err := c.run(dc.Namespace)
+		if err != nil {
+			fmt.Printf("ERROR: checking the %s: %v\n", c.name, err)
+			unresolved++
+			continue
+		}
+		for _, f := range findings {
+			fmt.Printf("PROBLEM: %s\n  suggestion: %s\n", f.problem, f.suggestion)
+			switch {
+			case f.fix == nil:
+				unresolved++
+			case !dc.Fix:
+				fmt.Println("  fix: available, run sc doctor --fix to apply it")
+				unresolved++
+			default:
+				if err := f.fix(); err != nil {
+					fmt.Printf("  fix: failed: %v\n", err)
+					unresolved++
+				} else {
+					fmt.Println("  fix: applied")
+				}
+			}
+		}
+	}
+	if unresolved > 0 {
+		return fmt.Errorf("%d problems remain", unresolved)
+	}
+	return nil
+}
+
+func checkAPIServerCert(ns string) ([]finding, error) {
+	certs, err := getAPIServerCerts(ns)
+	if err != nil || certs == nil {
+		return nil, err
+	}
+	return certFindings(ns, certs.Cert, certs.CABundle, certs.StoredCA, time.Now()), nil
+}
+
+// certFindings returns the problems of the PEM encoded api server
+// certificate crt at now: expiry, and not being signed by the CA in the
+// caBundle of the APIService. The stored CA, if any, is offered as the fix of
+// the latter if it signed crt.
+func certFindings(ns string, crt, caBundle, storedCA []byte, now time.Time) []finding {
+	block, _ := pem.Decode(crt)
+	if block == nil {
+		return []finding{{
+			problem:    fmt.Sprintf("secret %s/%s holds no PEM certificate", ns, apiServerCertSecretName),
+			suggestion: "run sc rotate-certs to issue a new certificate",
+		}}
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return []finding{{
+			problem:    fmt.Sprintf("error parsing the certificate of secret %s/%s: %v", ns, apiServerCertSecretName, err),
+			suggestion: "run sc rotate-certs to issue a new certificate",
+		}}
+	}
+
+	var findings []finding
+	switch {
+	case now.After(cert.NotAfter):
+		findings = append(findings, finding{
+			problem:    fmt.Sprintf("the api server certificate expired on %s, the main API server can't reach Service Catalog", cert.NotAfter.Format(time.RFC3339)),
+			suggestion: "run sc rotate-certs to issue a new certificate",
+		})
+	case now.Add(minReuseValidity).After(cert.NotAfter):
+		findings = append(findings, finding{
+			problem:    fmt.Sprintf("the api server certificate expires on %s", cert.NotAfter.Format(time.RFC3339)),
+			suggestion: "run sc rotate-certs to issue a new certificate before it expires",
+		})
+	}
+
+	if signedBy(cert, caBundle, cert.NotBefore) {
+		return findings
+	}
+	f := finding{
+		problem:    fmt.Sprintf("the caBundle of APIService %s is not the CA of the api server certificate, the aggregation layer rejects Service Catalog", scAPIService),
+		suggestion: "run sc rotate-certs to issue a new certificate and caBundle",
+	}
+	if signedBy(cert, storedCA, cert.NotBefore) {
+		f.suggestion = fmt.Sprintf("set the caBundle to the CA stored in secret %s/%s", ns, caSecretName)
+		f.fix = func() error { return setAPIServiceCABundle(storedCA) }
+	}
+	return append(findings, f)
+}
+
+// signedBy returns whether cert was signed by a CA in the PEM bundle caPEM,
+// verifying at time at so that expiry is not considered.
+func signedBy(cert *x509.Certificate, caPEM []byte, at time.Time) bool {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return false
+	}
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: at,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+func setAPIServiceCABundle(caPEM []byte) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"caBundle": base64.StdEncoding.EncodeToString(caPEM)},
+	})
+	if err != nil {
+		return err
+	}
+	output, err := kubectlCommand("patch", "apiservice", scAPIService,
+		"--type", "merge", "-p", string(patch)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error patching APIService %s: %s", scAPIService, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func checkAPIService(ns string) ([]finding, error) {
+	info, err := getAPIServiceInfo()
+	if err != nil || info == nil {
+		return nil, err
+	}
+	reason := apiServiceUnavailable(info)
+	if reason == "" {
+		return nil, nil
+	}
+	return []finding{{
+		problem:    fmt.Sprintf("APIService %s is unavailable: %s", scAPIService, reason),
+		suggestion: fmt.Sprintf("check the logs of the api server with kubectl logs --namespace %s deployment/apiserver", ns),
+	}}, nil
+}
+
+// podStatusList is the subset of a list of pods read to find OOM killed
+// containers.
+type podStatusList struct {
+	Items []struct {
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Status struct {
+			ContainerStatuses []struct {
+				Name         string `json:"name"`
+				RestartCount int    `json:"restartCount"`
+				LastState    struct {
+					Terminated *struct {
+						Reason string `json:"reason"`
+					} `json:"terminated"`
+				} `json:"lastState"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+func checkOOMKilled(ns string) ([]finding, error) {
+	output, err := kubectlCommand("get", "pods", "--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the pods of namespace %s: %s", ns, strings.TrimSpace(string(output)))
+	}
+	var pods podStatusList
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return nil, fmt.Errorf("error unmarshalling pods: %v", err)
+	}
+	return oomFindings(ns, &pods), nil
+}
+
+// oomFindings returns the containers of pods last terminated for running out
+// of memory.
+func oomFindings(ns string, pods *podStatusList) []finding {
+	var findings []finding
+	for _, p := range pods.Items {
+		for _, c := range p.Status.ContainerStatuses {
+			if c.LastState.Terminated == nil || c.LastState.Terminated.Reason != "OOMKilled" {
+				continue
+			}
+			f := finding{
+				problem:    fmt.Sprintf("container %s of pod %s was OOM killed, it restarted %d times", c.Name, p.Metadata.Name, c.RestartCount),
+				suggestion: fmt.Sprintf("raise the memory limit of the container, see the usage with sc top --namespace %s", ns),
+			}
+			if p.Metadata.Labels["app"] == "etcd" {
+				f.suggestion = "the etcd database may have outgrown the memory of its members: compact and defragment it, " +
+					"or give the members more memory with spec.pod.resources of EtcdCluster " + p.Metadata.Labels["etcd_cluster"]
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// brokerStatusList is the subset of a list of brokers read to find the
+// ones rejecting their credentials.
+type brokerStatusList struct {
+	Items []struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Status struct {
+			Conditions []struct {
+				Type    string `json:"type"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"conditions"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+func checkBrokerAuth(ns string) ([]finding, error) {
+	output, err := kubectlCommand("get",
+		"clusterservicebrokers.servicecatalog.k8s.io,servicebrokers.servicecatalog.k8s.io",
+		"--all-namespaces", "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing brokers: %s", strings.TrimSpace(string(output)))
+	}
+	var brokers brokerStatusList
+	if err := json.Unmarshal(output, &brokers); err != nil {
+		return nil, fmt.Errorf("error unmarshalling brokers: %v", err)
+	}
+	return brokerFindings(&brokers), nil
+}
+
+// brokerFindings returns the brokers whose catalog requests are rejected
+// as unauthorized. The token of the GCP broker is refreshed by restarting
+// the google-oauth deployment.
+func brokerFindings(brokers *brokerStatusList) []finding {
+	var findings []finding
+	for _, b := range brokers.Items {
+		for _, c := range b.Status.Conditions {
+			if c.Type != "Ready" || c.Status == "True" ||
+				!(strings.Contains(c.Message, "401") || strings.Contains(c.Message, "Unauthorized")) {
+				continue
+			}
+			name := b.Metadata.Name
+			if b.Metadata.Namespace != "" {
+				name = b.Metadata.Namespace + "/" + name
+			}
+			f := finding{
+				problem:    fmt.Sprintf("%s %s rejects its credentials: %s", b.Kind, name, c.Message),
+				suggestion: "check the secret referenced by spec.authInfo of the broker",
+			}
+			if b.Kind == "ClusterServiceBroker" && name == "gcp-broker" {
+				f.suggestion = "restart google-oauth/google-oauth to refresh the token of the broker, " +
+					"if it persists run sc add-gcp-broker again to create a new service account key"
+				f.fix = func() error { return restartDeployment("google-oauth", "google-oauth") }
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// problems returns the problems of findings and whether each can be fixed
+// automatically.
+func problems(findings []finding) []string {
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.problem+" fixable="+map[bool]string{true: "yes", false: "no"}[f.fix != nil])
+	}
+	return got
+}
+
+// TestCertFindings tests that expired certificates and caBundles not
+// matching the api server certificate are found, and that the stored CA is
+// offered as the fix of the latter.
+func TestCertFindings(t *testing.T) {
+	now := time.Now()
+	caPEM, _, ca, caKey := testCert(t, nil, now.Add(365*24*time.Hour), nil, nil)
+	otherCAPEM, _, _, _ := testCert(t, nil, now.Add(365*24*time.Hour), nil, nil)
+	valid, _, _, _ := testCert(t, []string{"host"}, now.Add(90*24*time.Hour), ca, caKey)
+	expiring, _, _, _ := testCert(t, []string{"host"}, now.Add(24*time.Hour), ca, caKey)
+
+	for _, tc := range []struct {
+		name               string
+		crt, bundle, store []byte
+		at                 time.Time
+		want               []string
+	}{
+		{"healthy", valid, caPEM, nil, now, nil},
+		{"expiring", expiring, caPEM, nil, now, []string{"expires on"}},
+		{"expired", expiring, caPEM, nil, now.Add(48 * time.Hour), []string{"expired on"}},
+		{"mismatch", valid, otherCAPEM, nil, now, []string{"rejects Service Catalog fixable=no"}},
+		{"mismatch with stored CA", valid, otherCAPEM, caPEM, now, []string{"rejects Service Catalog fixable=yes"}},
+		{"no certificate", []byte("garbage"), caPEM, nil, now, []string{"no PEM certificate"}},
+	} {
+		got := problems(certFindings("service-catalog", tc.crt, tc.bundle, tc.store, tc.at))
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: findings do not match: got %q; want %q", tc.name, got, tc.want)
+		}
+		for i, want := range tc.want {
+			if !strings.Contains(got[i], want) {
+				t.Fatalf("%s: finding %d does not match: got %q; want %q", tc.name, i, got[i], want)
+			}
+		}
+	}
+}
+
+// TestOOMFindings tests that OOM killed containers are found, with an etcd
+// specific suggestion for etcd members.
+func TestOOMFindings(t *testing.T) {
+	var pods podStatusList
+	if err := json.Unmarshal([]byte(`{"items": [
+  {"metadata": {"name": "etcd-cluster-0001", "labels": {"app": "etcd", "etcd_cluster": "etcd-cluster"}},
+   "status": {"containerStatuses": [{"name": "etcd", "restartCount": 4, "lastState": {"terminated": {"reason": "OOMKilled"}}}]}},
+  {"metadata": {"name": "apiserver-1"},
+   "status": {"containerStatuses": [{"name": "apiserver", "restartCount": 1, "lastState": {"terminated": {"reason": "Error"}}}]}},
+  {"metadata": {"name": "controller-manager-1"},
+   "status": {"containerStatuses": [{"name": "controller-manager", "restartCount": 2, "lastState": {"terminated": {"reason": "OOMKilled"}}}]}}
+]}`), &pods); err != nil {
+		t.Fatalf("Unexpected error unmarshalling pods: %v", err)
+	}
+	findings := oomFindings("service-catalog", &pods)
+	if len(findings) != 2 {
+		t.Fatalf("Number of findings does not match: got %q; want 2", problems(findings))
+	}
+	for i, want := range []struct{ problem, suggestion string }{
+		{"container etcd of pod etcd-cluster-0001 was OOM killed, it restarted 4 times", "EtcdCluster etcd-cluster"},
+		{"container controller-manager of pod controller-manager-1 was OOM killed", "sc top"},
+	} {
+		f := findings[i]
+		if !strings.HasPrefix(f.problem, want.problem) || !strings.Contains(f.suggestion, want.suggestion) {
+			t.Fatalf("Finding %d does not match: got %q, %q; want %q, %q", i, f.problem, f.suggestion, want.problem, want.suggestion)
+		}
+	}
+}
+
+// TestBrokerFindings tests that brokers rejecting their credentials are
+// found and that the GCP broker can be fixed automatically.
+func TestBrokerFindings(t *testing.T) {
+	var brokers brokerStatusList
+	if err := json.Unmarshal([]byte(`{"items": [
+  {"kind": "ClusterServiceBroker", "metadata": {"name": "gcp-broker"},
+   "status": {"conditions": [{"type": "Ready", "status": "False", "message": "Error fetching catalog: Status: 401; ErrorMessage: <nil>"}]}},
+  {"kind": "ServiceBroker", "metadata": {"name": "mine", "namespace": "dev"},
+   "status": {"conditions": [{"type": "Ready", "status": "False", "message": "Unauthorized"}]}},
+  {"kind": "ClusterServiceBroker", "metadata": {"name": "down"},
+   "status": {"conditions": [{"type": "Ready", "status": "False", "message": "connection refused"}]}},
+  {"kind": "ClusterServiceBroker", "metadata": {"name": "ok"},
+   "status": {"conditions": [{"type": "Ready", "status": "True"}]}}
+]}`), &brokers); err != nil {
+		t.Fatalf("Unexpected error unmarshalling brokers: %v", err)
+	}
+	got := problems(brokerFindings(&brokers))
+	want := []string{
+		"ClusterServiceBroker gcp-broker rejects its credentials: Error fetching catalog: Status: 401; ErrorMessage: <nil> fixable=yes",
+		"ServiceBroker dev/mine rejects its credentials: Unauthorized fixable=no",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Findings do not match: got %q; want %q", got, want)
+	}
+}
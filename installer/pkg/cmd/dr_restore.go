@@ -0,0 +1,414 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/Masterminds/semver"
+	"github.com/spf13/cobra"
+)
+
+// drRestoreConfig contains the disaster recovery configuration.
+type drRestoreConfig struct {
+	// From is the gs://<bucket>/<path> URL of the backup to restore.
+	From string
+
+	// EtcdVersion is the etcd version the snapshot is restored into, by
+	// default the one the backup was taken of.
+	EtcdVersion string
+
+	// Timeout bounds the etcd restore and waiting for each broker.
+	Timeout time.Duration
+
+	// PollInterval is how often the brokers are read while waiting.
+	PollInterval time.Duration
+
+	// Etcd sets the storage of the etcd the snapshot is restored into.
+	Etcd etcdMigrationConfig
+}
+
+func NewDRRestoreCmd() *cobra.Command {
+	ic := newInstallConfig()
+	dc := &drRestoreConfig{}
+	c := &cobra.Command{
+		Use:   "dr-restore",
+		Short: "rebuilds Service Catalog from a backup",
+		Long: `rebuilds Service Catalog from a backup of sc backup create, e.g. in a new
+cluster after losing the old one: it installs Service Catalog with the
+certificates and keys of the backup, so that the APIService caBundle and the
+clients trusting it keep working, restores the etcd snapshot, re-registers
+the brokers by making them fetch their catalogs again and verifies the
+conditions of the instances. Pass the install flags of the original
+installation; the Service Catalog version defaults to the one of the backup.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyProfile(ic, cmd.Flags()); err != nil {
+				return err
+			}
+			if err := applyNameAffixes(ic); err != nil {
+				return err
+			}
+			return drRestore(ic, dc)
+		},
+	}
+	addInstallFlags(c, ic)
+	c.Flags().DurationVar(&ic.ReadyTimeout, "ready-timeout", defaultReadyTimeout, "How long to wait for the components to be ready")
+	c.Flags().StringVar(&dc.From, "from", "", "gs://<bucket>/<path> of the backup to restore, as listed by sc backup list")
+	c.Flags().StringVar(&dc.EtcdVersion, "etcd-version", "", "etcd version to restore the snapshot into, by default the one of the backup")
+	c.Flags().DurationVar(&dc.Timeout, "restore-timeout", 30*time.Minute, "How long to wait for the etcd restore and for each broker")
+	c.Flags().DurationVar(&dc.PollInterval, "poll-interval", 5*time.Second, "How often to read the brokers")
+	addEtcdStorageFlags(c, &dc.Etcd)
+	addGCPContextFlags(c)
+	return c
+}
+
+func drRestore(ic *InstallConfig, dc *drRestoreConfig) (err error) {
+	bucket, name, err := gcp.ParseGCSURL(dc.From)
+	if err != nil {
+		return fmt.Errorf("--from: %v", err)
+	}
+	name = strings.TrimSuffix(name, "/")
+	if name == "" {
+		return fmt.Errorf("--from must name a backup, e.g. gs://%s/%s", bucket, backupName(time.Now()))
+	}
+	if ic.SkipEtcd {
+		return fmt.Errorf("dr-restore restores the etcd sc deploys, it does not support --skip-etcd")
+	}
+	if ic.ReadyTimeout <= 0 {
+		return fmt.Errorf("--ready-timeout must be positive, the restore waits for the installation")
+	}
+	client, err := gcpClient()
+	if err != nil {
+		return err
+	}
+
+	o, err := gcp.GetObject(client, bucket, name)
+	if err != nil {
+		return err
+	}
+	etcdVersion := dc.EtcdVersion
+	if etcdVersion == "" {
+		etcdVersion = o.Metadata[backupEtcdVersionKey]
+	}
+	if etcdVersion == "" {
+		return fmt.Errorf("backup gs://%s/%s does not record its etcd version, use --etcd-version", bucket, name)
+	}
+	if _, err := semver.NewVersion(etcdVersion); err != nil {
+		return fmt.Errorf("invalid etcd version %q: %v", etcdVersion, err)
+	}
+	dc.Etcd.Version, dc.Etcd.Timeout = etcdVersion, dc.Timeout
+	if err := dc.Etcd.validate(); err != nil {
+		return err
+	}
+	if ic.Version == "" && ic.CatalogVersion == "" {
+		ic.Version = o.Metadata[backupCatalogVersionKey]
+	}
+
+	certs, err := downloadBackupCerts(client, bucket, backupCertsName(name), o.Metadata[backupCertsKMSKeyKey])
+	if err != nil {
+		return err
+	}
+	// The workspace holds the restored private keys, and the lock of the
+	// namespace, which the install below shares.
+	ws, err := createWorkspace(ic.Namespace, "dr-restore", workspace.Options{Exclusive: true, Sensitive: true})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+	// The install below shares the lease too.
+	unlock, err := lockCluster(ic.Namespace, "dr-restore", ic.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := writeRestoredCerts(ws.Dir, certs, ic); err != nil {
+		return err
+	}
+
+	fmt.Printf("reinstalling Service Catalog %s with the certificates of the backup\n", catalogVersion(ic))
+	if err := installServiceCatalog(ic); err != nil {
+		return err
+	}
+
+	fmt.Printf("restoring the etcd snapshot gs://%s/%s\n", bucket, name)
+	if err := restoreEtcdBackup(ic, client, bucket, name, &dc.Etcd); err != nil {
+		return err
+	}
+	// The controller manager resyncs its informers from the restored
+	// data.
+	if err := restartServiceCatalogPods(ic); err != nil {
+		return err
+	}
+	if err := waitForCatalogReady(ic.Namespace, ic.ReadyTimeout, !ic.SkipAPIRegistration); err != nil {
+		return err
+	}
+
+	fmt.Println("re-registering the brokers")
+	brokersErr := relistBrokers(&brokerApplyConfig{Timeout: dc.Timeout, PollInterval: dc.PollInterval})
+	fmt.Println("verifying the instances")
+	if err := verifyRestoredInstances(); err != nil {
+		return err
+	}
+	if brokersErr != nil {
+		return brokersErr
+	}
+	fmt.Printf("restored Service Catalog from gs://%s/%s\n", bucket, name)
+	return nil
+}
+
+// downloadBackupCerts downloads the certificates in the object name of
+// bucket, decrypting them with the Cloud KMS key kmsKey if set.
+func downloadBackupCerts(client *http.Client, bucket, name, kmsKey string) (*backupCerts, error) {
+	r, err := gcp.DownloadObject(client, bucket, name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the certificates of the backup, only backups of sc backup create can be restored: %v", err)
+	}
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading gs://%s/%s: %v", bucket, name, err)
+	}
+	if kmsKey != "" {
+		if b, err = gcp.KMSDecrypt(client, kmsKey, b); err != nil {
+			return nil, fmt.Errorf("error decrypting the certificates with %s: %v", kmsKey, err)
+		}
+	}
+	var certs backupCerts
+	if err := json.Unmarshal(b, &certs); err != nil {
+		return nil, fmt.Errorf("error unmarshalling gs://%s/%s: %v", bucket, name, err)
+	}
+	if len(certs.CABundle) == 0 || len(certs.Cert) == 0 || len(certs.Key) == 0 {
+		return nil, fmt.Errorf("gs://%s/%s lacks the caBundle, certificate or key of the api server", bucket, name)
+	}
+	return &certs, nil
+}
+
+// writeRestoredCerts writes certs to dir, the files restoredCerts returns,
+// and makes ic install them. A stored CA is stored again, its private key
+// encrypted with Cloud KMS requires --kms-key. The etcd encryption of the
+// backup is configured again with its keys.
+func writeRestoredCerts(dir string, certs *backupCerts, ic *InstallConfig) error {
+	files := map[string][]byte{
+		"ca.pem":            certs.CABundle,
+		"apiserver.pem":     certs.Cert,
+		"apiserver-key.pem": certs.Key,
+	}
+	if len(certs.StoredCA) > 0 {
+		key, encrypted := certs.StoredCA["ca.key"], certs.StoredCA["ca.key.kms"]
+		switch {
+		case len(encrypted) > 0 && ic.KMSKey == "":
+			return fmt.Errorf("the CA private key of the backup is encrypted with Cloud KMS, use --kms-key to store it again")
+		case len(encrypted) > 0:
+			files["ca-key.pem.kms"] = encrypted
+		case len(key) > 0:
+			files["ca-key.pem"] = key
+		}
+		ic.StoreCAKey = true
+	}
+	if len(certs.EncryptionConfig) > 0 {
+		provider, keys, err := parseEncryptionConfig(certs.EncryptionConfig)
+		if err != nil {
+			return fmt.Errorf("backup: %v", err)
+		}
+		if ic.EtcdEncryption == "" {
+			ic.EtcdEncryption = provider
+		}
+		ic.EtcdEncryptionKeys = keys
+	}
+	for name, b := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0600); err != nil {
+			return err
+		}
+	}
+	ic.RestoredCertsDir = dir
+	return nil
+}
+
+// restoredCerts returns the certificates writeRestoredCerts wrote to dir.
+func restoredCerts(dir string) *sslArtifacts {
+	a := &sslArtifacts{
+		CAFile:                  filepath.Join(dir, "ca.pem"),
+		APIServerCertFile:       filepath.Join(dir, "apiserver.pem"),
+		APIServerPrivateKeyFile: filepath.Join(dir, "apiserver-key.pem"),
+	}
+	for _, f := range []string{"ca-key.pem", "ca-key.pem.kms"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			a.CAPrivateKeyFile = filepath.Join(dir, f)
+		}
+	}
+	return a
+}
+
+// restoreEtcdBackup restores the etcd snapshot name of bucket into a new
+// etcd running version mc.Version, with the resources of the etcd ic
+// installed, and switches the api server of ic over to it. The etcd ic
+// installed is left empty.
+func restoreEtcdBackup(ic *InstallConfig, client *http.Client, bucket, name string, mc *etcdMigrationConfig) error {
+	version := mc.Version
+	d, err := getAPIServerDeployment(ic.Namespace)
+	if err != nil {
+		return err
+	}
+	servers, argPath, err := apiServerEtcdServers(d)
+	if err != nil {
+		return err
+	}
+	installed, err := currentEtcdMember(ic.Namespace, servers)
+	if err != nil {
+		return err
+	}
+	m := &etcdMigration{
+		ns:           ic.Namespace,
+		name:         etcdMigrationName(version),
+		config:       mc,
+		resources:    installed.Resources,
+		affinity:     installed.Affinity,
+		nodeSelector: installed.NodeSelector,
+		replicas:     d.Spec.Replicas,
+		oldServers:   servers,
+		argPath:      argPath,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mc.Timeout)
+	defer cancel()
+
+	// The snapshot exists already, its key count is unknown.
+	snapshot := func() (int64, error) { return -1, nil }
+	copySnapshot := func(pod string) error {
+		return downloadEtcdSnapshot(client, bucket, name, ic.Namespace, pod)
+	}
+	if err := m.restore(ctx, ic.IPFamily == ipFamilyIPv6, version, snapshot, copySnapshot); err != nil {
+		fmt.Printf("etcd restore failed, rolling back: %v\n", err)
+		m.rollback()
+		return fmt.Errorf("error restoring etcd from gs://%s/%s: %v", bucket, name, err)
+	}
+	return nil
+}
+
+// downloadEtcdSnapshot streams the snapshot name of bucket into the data
+// volume of the new etcd pod.
+func downloadEtcdSnapshot(client *http.Client, bucket, name, ns, pod string) error {
+	r, err := gcp.DownloadObject(client, bucket, name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	cmd := kubectlCommand("exec", "-i", pod, "--namespace", ns, "-c", "etcd", "--",
+		"/bin/sh", "-c", "cat > "+etcdMigrationDataDir+"/snapshot.db")
+	cmd.Stdin = r
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error copying the etcd snapshot: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// restoredBrokers is the subset of the brokers read to re-register them.
+type restoredBrokers struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Spec struct {
+			RelistRequests int64 `json:"relistRequests"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// relistBrokers makes the restored brokers fetch their catalogs again and
+// waits for them, so that the catalog matches the brokers rather than the
+// backup. It returns an error listing the brokers that are not ready.
+func relistBrokers(ac *brokerApplyConfig) error {
+	var failed []string
+	for _, resource := range []string{"clusterservicebrokers.servicecatalog.k8s.io", "servicebrokers.servicecatalog.k8s.io"} {
+		output, err := kubectlCommand("get", resource, "--all-namespaces", "-o", "json").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error listing %s: %s", resource, strings.TrimSpace(string(output)))
+		}
+		var brokers restoredBrokers
+		if err := json.Unmarshal(output, &brokers); err != nil {
+			return fmt.Errorf("error unmarshalling %s: %v", resource, err)
+		}
+		for _, item := range brokers.Items {
+			b := &brokerSpec{Name: item.Metadata.Name, Namespace: item.Metadata.Namespace}
+			patch := fmt.Sprintf(`{"spec":{"relistRequests":%d}}`, item.Spec.RelistRequests+1)
+			args := []string{"patch", resource, b.Name, "--type", "merge", "-p", patch}
+			if b.Namespace != "" {
+				args = append(args, "--namespace", b.Namespace)
+			}
+			fmt.Printf("re-registering %s %s\n", b.kind(), brokerName(b))
+			if output, err := kubectlCommand(args...).CombinedOutput(); err != nil {
+				fmt.Printf("WARNING: error relisting broker %s: %s\n", brokerName(b), strings.TrimSpace(string(output)))
+				failed = append(failed, brokerName(b))
+				continue
+			}
+			if err := waitForBroker(b, ac); err != nil {
+				fmt.Printf("WARNING: %v\n", err)
+				failed = append(failed, brokerName(b))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("brokers not ready after the restore: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// verifyRestoredInstances prints the instances whose operation is not done
+// and returns an error listing the failed ones.
+func verifyRestoredInstances() error {
+	output, err := kubectlCommand("get", "serviceinstances.servicecatalog.k8s.io", "--all-namespaces", "-o", "json").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error listing instances: %s", strings.TrimSpace(string(output)))
+	}
+	var list struct {
+		Items []serviceInstance `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return fmt.Errorf("error unmarshalling instances: %v", err)
+	}
+	ready := 0
+	var failed []string
+	for i := range list.Items {
+		si := &list.Items[i]
+		name := si.Metadata.Namespace + "/" + si.Metadata.Name
+		line, done, err := instanceProgress(si)
+		switch {
+		case err != nil:
+			fmt.Printf("instance %s: %s\n", name, line)
+			failed = append(failed, name)
+		case done:
+			ready++
+		default:
+			fmt.Printf("instance %s: %s\n", name, line)
+		}
+	}
+	fmt.Printf("%d of %d instances are ready\n", ready, len(list.Items))
+	if len(failed) > 0 {
+		return fmt.Errorf("instances failed after the restore: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
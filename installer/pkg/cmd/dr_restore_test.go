@@ -0,0 +1,85 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestRelistBrokers tests that every broker is asked to relist once, and
+// that the brokers which are not ready are reported without stopping.
+func TestRelistBrokers(t *testing.T) {
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch {
+		case args[0] == "get" && args[1] == "clusterservicebrokers.servicecatalog.k8s.io" && len(args) > 3 && args[2] == "--all-namespaces":
+			return execx.Response{Stdout: `{"items":[{"metadata":{"name":"gcp"},"spec":{"relistRequests":2}}]}`}
+		case args[0] == "get" && args[1] == "servicebrokers.servicecatalog.k8s.io" && len(args) > 3 && args[2] == "--all-namespaces":
+			return execx.Response{Stdout: `{"items":[{"metadata":{"name":"mine","namespace":"team"},"spec":{}}]}`}
+		case args[0] == "get" && args[2] == "gcp":
+			return execx.Response{Stdout: `{"status":{"conditions":[{"type":"Ready","status":"True"}]}}`}
+		case args[0] == "get" && args[2] == "mine":
+			return execx.Response{Stdout: `{"status":{"conditions":[{"type":"Failed","status":"True","message":"unreachable"}]}}`}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	err := relistBrokers(&brokerApplyConfig{Timeout: time.Second, PollInterval: time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "team/mine") || strings.Contains(err.Error(), "gcp") {
+		t.Fatalf("Expected an error listing only team/mine, got %v", err)
+	}
+
+	want := []string{
+		`patch clusterservicebrokers.servicecatalog.k8s.io gcp --type merge -p {"spec":{"relistRequests":3}}`,
+		`patch servicebrokers.servicecatalog.k8s.io mine --type merge -p {"spec":{"relistRequests":1}} --namespace team`,
+	}
+	if got := kubectlCalls(s, "patch"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Patches do not match:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+// TestVerifyRestoredInstances tests that only failed instances fail the
+// verification.
+func TestVerifyRestoredInstances(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		items   string
+		wantErr string
+	}{
+		{"ready and in progress", `{"metadata":{"namespace":"team","name":"db"},"status":{"conditions":[{"type":"Ready","status":"True","reason":"ProvisionedSuccessfully"}]}},` +
+			`{"metadata":{"namespace":"team","name":"queue"},"status":{"conditions":[{"type":"Ready","status":"False","reason":"Provisioning"}]}}`, ""},
+		{"failed", `{"metadata":{"namespace":"team","name":"db"},"status":{"conditions":[{"type":"Failed","status":"True","message":"quota"}]}}`, "team/db"},
+		{"none", ``, ""},
+	} {
+		_, restore := stubExecutor(func(name string, args []string) execx.Response {
+			return execx.Response{Stdout: `{"items":[` + tc.items + `]}`}
+		})
+		err := verifyRestoredInstances()
+		restore()
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("%s: expected an error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/Masterminds/semver"
+)
+
+// Dry run modes of install.
+const (
+	// dryRunNone deploys the objects.
+	dryRunNone = "none"
+
+	// dryRunClient only generates the YAML files.
+	dryRunClient = "client"
+
+	// dryRunServer also submits every object to the api server with
+	// server-side dry run, which runs validation and admission, e.g.
+	// quotas, PodSecurityPolicies or OPA policies, without persisting
+	// anything.
+	dryRunServer = "server"
+)
+
+// namespaceNotFoundRE matches the error of objects submitted to a namespace
+// that does not exist.
+var namespaceNotFoundRE = regexp.MustCompile(`namespaces? "([^"]+)" not found`)
+
+// dryRunSkipReason returns why the server-side dry run of an object failing
+// with output is inconclusive, or "" if the api server rejected the object.
+// An object cannot be checked before the objects it depends on exist, which
+// a dry run does not create.
+func dryRunSkipReason(output string) string {
+	if m := namespaceNotFoundRE.FindStringSubmatch(output); m != nil {
+		return fmt.Sprintf("namespace %s does not exist yet, create it to dry run its objects", m[1])
+	}
+	if strings.Contains(output, "no matches for kind") || strings.Contains(output, "the server could not find the requested resource") {
+		return "its API is not served yet, it is registered during the install"
+	}
+	return ""
+}
+
+// dryRunManifests submits the objects of the manifests in dir to the api
+// server with server-side dry run, as configured by opts, and returns an
+// error listing all the objects the api server rejected.
+func dryRunManifests(dir string, opts applyOptions) error {
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var rejections []string
+	for _, o := range objs {
+		cmd := kubectlCommand(append(opts.args(), "--dry-run=server")...)
+		cmd.Stdin = bytes.NewReader(o.JSON)
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			fmt.Printf("dry run: %s accepted\n", o)
+			continue
+		}
+		out := strings.TrimSpace(string(output))
+		if reason := dryRunSkipReason(out); reason != "" {
+			fmt.Printf("dry run: %s skipped, %s\n", o, reason)
+			continue
+		}
+		rejections = append(rejections, fmt.Sprintf("%s (%s): %s", o, o.Location(), out))
+	}
+	if len(rejections) > 0 {
+		return messages.Errorf(messages.DryRunRejected, len(rejections), strings.Join(rejections, "\n  "))
+	}
+	return nil
+}
+
+// checkServerDryRun returns an error if the cluster does not support
+// server-side dry run.
+func checkServerDryRun() error {
+	v, err := getServerVersion()
+	if err != nil {
+		return err
+	}
+	if v.LessThan(semver.MustParse("1.13.0")) {
+		return messages.Errorf(messages.ServerDryRunTooOld, v)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDryRunSkipReason tests that objects depending on objects created by
+// the install are skipped and that admission rejections are not.
+func TestDryRunSkipReason(t *testing.T) {
+	for _, tc := range []struct {
+		output string
+		want   string
+	}{
+		{`Error from server (NotFound): error when creating "STDIN": namespaces "service-catalog" not found`, "namespace service-catalog does not exist yet"},
+		{`error: unable to recognize "STDIN": no matches for kind "EtcdCluster" in version "etcd.database.coreos.com/v1beta2"`, "its API is not served yet"},
+		{`Error from server (Forbidden): error when creating "STDIN": pods "etcd" is forbidden: exceeded quota: compute-resources`, ""},
+		{`Error from server (Forbidden): admission webhook "validation.gatekeeper.sh" denied the request: [denied by required-labels]`, ""},
+	} {
+		got := dryRunSkipReason(tc.output)
+		if (tc.want == "") != (got == "") || !strings.HasPrefix(got, tc.want) {
+			t.Fatalf("Skip reason of %q does not match: got %q; want %q", tc.output, got, tc.want)
+		}
+	}
+}
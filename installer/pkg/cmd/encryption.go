@@ -0,0 +1,239 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+	"github.com/ghodss/yaml"
+)
+
+// Providers of --etcd-encryption.
+const (
+	encryptionAESCBC = "aescbc"
+	encryptionKMS    = "kms"
+)
+
+const (
+	// encryptionConfigSecretName is the secret holding the encryption
+	// configuration of the api server, with its keys.
+	encryptionConfigSecretName = "apiserver-encryption-config"
+	encryptionConfigKey        = "encryption-config.yaml"
+
+	// encryptionConfigDir is where encryptionConfigSecretName is mounted.
+	encryptionConfigDir = "/var/run/kubernetes-service-catalog-encryption"
+
+	// kmsPluginSocketDir holds the socket of the KMS plugin sidecar of the
+	// api server.
+	kmsPluginSocketDir = "/var/run/kmsplugin"
+
+	defaultKMSPluginImage = "gcr.io/cloud-kms-encryption/k8s-cloud-kms-plugin:v0.2.2"
+)
+
+// encryptedResources are the resources encrypted at rest, those holding the
+// parameters of the instances and bindings, which may be credentials.
+var encryptedResources = []string{"serviceinstances.servicecatalog.k8s.io", "servicebindings.servicecatalog.k8s.io"}
+
+// encryptionKey is an AES-CBC key of the encryption configuration.
+type encryptionKey struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+// encryptionConfig is the EncryptionConfig of the api server, in the v1
+// format both catalog version sets read.
+type encryptionConfig struct {
+	Kind       string                `json:"kind"`
+	APIVersion string                `json:"apiVersion"`
+	Resources  []encryptionResources `json:"resources"`
+}
+
+type encryptionResources struct {
+	Resources []string             `json:"resources"`
+	Providers []encryptionProvider `json:"providers"`
+}
+
+// encryptionProvider sets one of its fields.
+type encryptionProvider struct {
+	KMS      *kmsProvider    `json:"kms,omitempty"`
+	AESCBC   *aescbcProvider `json:"aescbc,omitempty"`
+	Identity *struct{}       `json:"identity,omitempty"`
+}
+
+type kmsProvider struct {
+	Name      string `json:"name"`
+	Endpoint  string `json:"endpoint"`
+	CacheSize int    `json:"cachesize"`
+}
+
+type aescbcProvider struct {
+	Keys []encryptionKey `json:"keys"`
+}
+
+// validateEncryption checks the encryption at rest options of ic.
+func validateEncryption(ic *InstallConfig) error {
+	switch ic.EtcdEncryption {
+	case "", encryptionAESCBC:
+		if ic.EtcdEncryptionKMSKey != "" {
+			return fmt.Errorf("--etcd-encryption-kms-key requires --etcd-encryption=kms")
+		}
+	case encryptionKMS:
+		if ic.EtcdEncryptionKMSKey == "" {
+			return fmt.Errorf("--etcd-encryption=kms requires --etcd-encryption-kms-key")
+		}
+		if err := gcp.ValidateKMSKeyName(ic.EtcdEncryptionKMSKey); err != nil {
+			return fmt.Errorf("--etcd-encryption-kms-key: %v", err)
+		}
+		// The KMS plugin sidecar runs alongside the api server, --fips
+		// verifies its image like the Service Catalog images.
+		if ic.FIPS && !strings.HasSuffix(kmsPluginImage(ic), fipsImageSuffix) {
+			return fmt.Errorf("--fips requires a FIPS variant of the KMS plugin, set --etcd-encryption-kms-plugin-image to an image tagged %s", fipsImageSuffix)
+		}
+	default:
+		return fmt.Errorf("--etcd-encryption must be aescbc, kms or empty, got %q", ic.EtcdEncryption)
+	}
+	return nil
+}
+
+// parseEncryptionConfig returns the provider writing the data of the
+// encryption configuration b and its AES-CBC keys.
+func parseEncryptionConfig(b []byte) (provider string, keys []encryptionKey, err error) {
+	var c encryptionConfig
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return "", nil, fmt.Errorf("error parsing the encryption configuration: %v", err)
+	}
+	for _, r := range c.Resources {
+		for _, p := range r.Providers {
+			switch {
+			case p.KMS != nil && provider == "":
+				provider = encryptionKMS
+			case p.AESCBC != nil:
+				if provider == "" {
+					provider = encryptionAESCBC
+				}
+				for _, k := range p.AESCBC.Keys {
+					if !containsKey(keys, k.Name) {
+						keys = append(keys, k)
+					}
+				}
+			}
+		}
+	}
+	return provider, keys, nil
+}
+
+func containsKey(keys []encryptionKey, name string) bool {
+	for _, k := range keys {
+		if k.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// loadEncryptionKeys sets the AES-CBC keys of ic to those of the encryption
+// configuration of a previous install, so that the api server can still
+// read the data they encrypted, or to a new key if ic encrypts with
+// AES-CBC. It fails if ic could not read the data of the previous install,
+// i.e. does not enable encryption or drops the kms provider.
+func loadEncryptionKeys(ic *InstallConfig) error {
+	if len(ic.EtcdEncryptionKeys) > 0 {
+		return nil
+	}
+	data, found, err := getSecretData(ic.Namespace, encryptionConfigSecretName)
+	if err != nil {
+		return err
+	}
+	if found {
+		provider, keys, err := parseEncryptionConfig(data[encryptionConfigKey])
+		if err != nil {
+			return fmt.Errorf("secret %s/%s: %v", ic.Namespace, encryptionConfigSecretName, err)
+		}
+		if ic.EtcdEncryption == "" || (provider == encryptionKMS && ic.EtcdEncryption != encryptionKMS) {
+			return fmt.Errorf("the data of Service Catalog is encrypted with the %s provider configured in secret %s/%s, keep --etcd-encryption=%s", provider, ic.Namespace, encryptionConfigSecretName, provider)
+		}
+		ic.EtcdEncryptionKeys = keys
+	}
+	if ic.EtcdEncryption == encryptionAESCBC && len(ic.EtcdEncryptionKeys) == 0 {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("error generating the encryption key: %v", err)
+		}
+		ic.EtcdEncryptionKeys = []encryptionKey{{Name: "key1", Secret: base64.StdEncoding.EncodeToString(key)}}
+	}
+	return nil
+}
+
+// renderEncryptionConfig returns the encryption configuration of ic:
+// writing with the provider of ic, reading with it, then with the AES-CBC
+// keys of ic, then the data written before encryption was enabled.
+func renderEncryptionConfig(ic *InstallConfig) ([]byte, error) {
+	var providers []encryptionProvider
+	if ic.EtcdEncryption == encryptionKMS {
+		providers = append(providers, encryptionProvider{KMS: &kmsProvider{
+			Name:      "cloudkms",
+			Endpoint:  "unix://" + kmsPluginSocketDir + "/socket.sock",
+			CacheSize: 1000,
+		}})
+	}
+	if len(ic.EtcdEncryptionKeys) > 0 {
+		providers = append(providers, encryptionProvider{AESCBC: &aescbcProvider{Keys: ic.EtcdEncryptionKeys}})
+	}
+	providers = append(providers, encryptionProvider{Identity: &struct{}{}})
+	return yaml.Marshal(encryptionConfig{
+		Kind:       "EncryptionConfig",
+		APIVersion: "v1",
+		Resources:  []encryptionResources{{Resources: encryptedResources, Providers: providers}},
+	})
+}
+
+// kmsPluginImage returns the image of the KMS plugin sidecar of ic.
+func kmsPluginImage(ic *InstallConfig) string {
+	if ic.EtcdEncryptionKMSPluginImage != "" {
+		return ic.EtcdEncryptionKMSPluginImage
+	}
+	return defaultKMSPluginImage
+}
+
+// encryptionData returns the template data of the encryption at rest of
+// the api server.
+func encryptionData(ic *InstallConfig) (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"EtcdEncryption":               ic.EtcdEncryption,
+		"EtcdEncryptionConfig":         template.HTML(""),
+		"EncryptionConfigDir":          encryptionConfigDir,
+		"EncryptionConfigKey":          encryptionConfigKey,
+		"EtcdEncryptionKMSKey":         ic.EtcdEncryptionKMSKey,
+		"EtcdEncryptionKMSPluginImage": kmsPluginImage(ic),
+		"KMSPluginSocketDir":           kmsPluginSocketDir,
+	}
+	if ic.EtcdEncryption == "" {
+		return data, nil
+	}
+	b, err := renderEncryptionConfig(ic)
+	if err != nil {
+		return nil, err
+	}
+	// The templates escape HTML, which would escape the + of base64.
+	data["EtcdEncryptionConfig"] = template.HTML(base64.StdEncoding.EncodeToString(b))
+	return data, nil
+}
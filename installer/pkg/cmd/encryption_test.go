@@ -0,0 +1,165 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// encryptionSecretStub answers the get of the encryption config secret with
+// config, or NotFound if empty.
+func encryptionSecretStub(config []byte) func(name string, args []string) execx.Response {
+	return func(name string, args []string) execx.Response {
+		if len(config) == 0 {
+			return execx.Response{Stderr: `Error from server (NotFound): secrets "apiserver-encryption-config" not found`, ExitCode: 1}
+		}
+		return execx.Response{Stdout: `{"data":{"` + encryptionConfigKey + `":"` + base64.StdEncoding.EncodeToString(config) + `"}}`}
+	}
+}
+
+// TestLoadEncryptionKeys tests that the keys of a previous install are kept,
+// that a key is generated for a new AES-CBC install and that the data of a
+// previous install stays readable.
+func TestLoadEncryptionKeys(t *testing.T) {
+	oldKeys := []encryptionKey{{Name: "key1", Secret: "b2xk"}}
+	aescbc, err := renderEncryptionConfig(&InstallConfig{EtcdEncryption: encryptionAESCBC, EtcdEncryptionKeys: oldKeys})
+	if err != nil {
+		t.Fatalf("Unexpected error rendering the config: %v", err)
+	}
+	kms, err := renderEncryptionConfig(&InstallConfig{EtcdEncryption: encryptionKMS})
+	if err != nil {
+		t.Fatalf("Unexpected error rendering the config: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		existing   []byte
+		encryption string
+		wantKeys   []encryptionKey
+		wantErr    string
+	}{
+		{"no encryption", nil, "", nil, ""},
+		{"new aescbc", nil, encryptionAESCBC, nil, ""},
+		{"new kms", nil, encryptionKMS, nil, ""},
+		{"kept aescbc", aescbc, encryptionAESCBC, oldKeys, ""},
+		{"aescbc to kms", aescbc, encryptionKMS, oldKeys, ""},
+		{"aescbc disabled", aescbc, "", nil, "keep --etcd-encryption=aescbc"},
+		{"kms to aescbc", kms, encryptionAESCBC, nil, "keep --etcd-encryption=kms"},
+	} {
+		_, restore := stubExecutor(encryptionSecretStub(tc.existing))
+		ic := &InstallConfig{Namespace: "catalog", EtcdEncryption: tc.encryption}
+		err := loadEncryptionKeys(ic)
+		restore()
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		case tc.wantErr != "":
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("%s: expected an error containing %q, got %v", tc.name, tc.wantErr, err)
+			}
+			continue
+		}
+		if tc.name == "new aescbc" {
+			if len(ic.EtcdEncryptionKeys) != 1 {
+				t.Fatalf("%s: expected a generated key, got %v", tc.name, ic.EtcdEncryptionKeys)
+			}
+			if b, err := base64.StdEncoding.DecodeString(ic.EtcdEncryptionKeys[0].Secret); err != nil || len(b) != 32 {
+				t.Fatalf("%s: expected a 32 byte key, got %q", tc.name, ic.EtcdEncryptionKeys[0].Secret)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(ic.EtcdEncryptionKeys, tc.wantKeys) {
+			t.Fatalf("%s: keys do not match: got %v; want %v", tc.name, ic.EtcdEncryptionKeys, tc.wantKeys)
+		}
+	}
+}
+
+// TestUninstallEncryptedInstall tests that the manifests an uninstall
+// deletes are generated without reading the keys of an encrypted install.
+func TestUninstallEncryptedInstall(t *testing.T) {
+	aescbc, err := renderEncryptionConfig(&InstallConfig{EtcdEncryption: encryptionAESCBC, EtcdEncryptionKeys: []encryptionKey{{Name: "key1", Secret: "b2xk"}}})
+	if err != nil {
+		t.Fatalf("Unexpected error rendering the config: %v", err)
+	}
+	s, restore := stubExecutor(encryptionSecretStub(aescbc))
+	defer restore()
+	dir, err := ioutil.TempDir("", "uninstall")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The certificates are those of a restore, not generated.
+	for _, f := range []string{"ca.pem", "apiserver.pem", "apiserver-key.pem"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), []byte("pem"), 0600); err != nil {
+			t.Fatalf("Unexpected error writing %s: %v", f, err)
+		}
+	}
+	ic := uninstallConfig("", "")
+	ic.RestoredCertsDir = dir
+	if err := generateDeploymentConfigs(dir, ic); err != nil {
+		t.Fatalf("Unexpected error generating the uninstall manifests: %v", err)
+	}
+	for _, c := range s.Calls() {
+		if containsString(c.Args, encryptionConfigSecretName) {
+			t.Fatalf("Expected the encryption config not to be read, got %v", c.Args)
+		}
+	}
+}
+
+// TestValidateEncryption tests the provider and Cloud KMS key checks.
+func TestValidateEncryption(t *testing.T) {
+	key := "projects/p/locations/global/keyRings/sc/cryptoKeys/etcd"
+	for _, tc := range []struct {
+		encryption, kmsKey, pluginImage string
+		fips                            bool
+		wantErr                         string
+	}{
+		{"", "", "", false, ""},
+		{encryptionAESCBC, "", "", false, ""},
+		{encryptionAESCBC, "", "", true, ""},
+		{encryptionKMS, key, "", false, ""},
+		{encryptionKMS, "", "", false, "requires --etcd-encryption-kms-key"},
+		{encryptionKMS, "etcd", "", false, "invalid Cloud KMS key"},
+		{encryptionAESCBC, key, "", false, "requires --etcd-encryption=kms"},
+		{"secretbox", "", "", false, "--etcd-encryption must be"},
+		{encryptionKMS, key, "", true, "--fips requires a FIPS variant of the KMS plugin"},
+		{encryptionKMS, key, defaultKMSPluginImage, true, "--fips requires a FIPS variant of the KMS plugin"},
+		{encryptionKMS, key, defaultKMSPluginImage + fipsImageSuffix, true, ""},
+	} {
+		err := validateEncryption(&InstallConfig{
+			EtcdEncryption:               tc.encryption,
+			EtcdEncryptionKMSKey:         tc.kmsKey,
+			EtcdEncryptionKMSPluginImage: tc.pluginImage,
+			FIPS:                         tc.fips,
+		})
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("%q: unexpected error: %v", tc.encryption, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("%q: expected an error containing %q, got %v", tc.encryption, tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+)
+
+const (
+	defaultEtcdMaintenanceSchedule = "0 3 * * *"
+	defaultEtcdMaintenanceKeep     = 7
+
+	// etcdMaintenanceUploadImage uploads the snapshots to GCS.
+	etcdMaintenanceUploadImage = "gcr.io/google.com/cloudsdktool/cloud-sdk:alpine"
+)
+
+// validateEtcdMaintenance checks the etcd maintenance options of ic.
+func validateEtcdMaintenance(ic *InstallConfig) error {
+	if !ic.EtcdMaintenance {
+		return nil
+	}
+	if ic.SkipEtcd {
+		return fmt.Errorf("--enable-etcd-maintenance maintains the etcd sc deploys, it does not support --skip-etcd")
+	}
+	if !validCronSchedule(ic.EtcdMaintenanceSchedule) {
+		return fmt.Errorf("--etcd-maintenance-schedule must be a cron schedule such as %q, got %q", defaultEtcdMaintenanceSchedule, ic.EtcdMaintenanceSchedule)
+	}
+	if ic.EtcdMaintenanceBucket != "" {
+		if _, _, err := gcp.ParseGCSURL(ic.EtcdMaintenanceBucket); err != nil {
+			return fmt.Errorf("--etcd-maintenance-bucket must be a gs://<bucket>[/<path>] URL, got %q", ic.EtcdMaintenanceBucket)
+		}
+	} else {
+		if ic.EtcdMaintenanceKeep < 1 {
+			return fmt.Errorf("--etcd-maintenance-keep must be at least 1, got %d", ic.EtcdMaintenanceKeep)
+		}
+		if ic.EtcdBackupStorageClass == "" {
+			return fmt.Errorf("--enable-etcd-maintenance without --etcd-maintenance-bucket requires --etcd-backup-storageclass")
+		}
+	}
+	if ic.EtcdMaintenanceGCPServiceAccount != "" && ic.EtcdMaintenanceBucket == "" {
+		return fmt.Errorf("--etcd-maintenance-gcp-service-account requires --etcd-maintenance-bucket")
+	}
+	return nil
+}
+
+// validCronSchedule returns whether s is a five field cron schedule or a
+// predefined one such as @daily.
+func validCronSchedule(s string) bool {
+	switch s {
+	case "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly":
+		return true
+	}
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return false
+	}
+	for _, f := range fields {
+		if strings.Trim(f, "0123456789*/,-?ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// etcdMaintenanceImage returns the image of the etcd ic deploys, which
+// also runs etcdctl for the maintenance.
+func etcdMaintenanceImage(ic *InstallConfig) string {
+	if ic.IPFamily == ipFamilyIPv6 {
+		return etcdImage
+	}
+	return etcdClusterImage
+}
+
+// etcdMaintenanceData returns the template data rendering the etcd
+// maintenance CronJob of ic, if enabled.
+func etcdMaintenanceData(ic *InstallConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"EtcdMaintenance":                  ic.EtcdMaintenance,
+		"EtcdMaintenanceSchedule":          ic.EtcdMaintenanceSchedule,
+		"EtcdMaintenanceBucket":            strings.TrimSuffix(ic.EtcdMaintenanceBucket, "/"),
+		"EtcdMaintenanceGCPServiceAccount": ic.EtcdMaintenanceGCPServiceAccount,
+		"EtcdMaintenancePruneFrom":         ic.EtcdMaintenanceKeep + 1,
+		"EtcdMaintenanceImage":             etcdMaintenanceImage(ic),
+		"EtcdMaintenanceUploadImage":       etcdMaintenanceUploadImage,
+	}
+}
+
+// setEtcdMaintenanceServers points the etcd maintenance CronJob of
+// namespace ns, if any, at the etcd servers, e.g. after an etcd migration.
+func setEtcdMaintenanceServers(ns, servers string) error {
+	output, err := kubectlCommand("get", "cronjob", "etcd-maintenance", "--namespace", ns,
+		"--ignore-not-found", "-o", "name").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error getting the etcd maintenance CronJob: %s", strings.TrimSpace(string(output)))
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return nil
+	}
+	// A strategic merge patch merges the init containers and their env
+	// by name.
+	patch := fmt.Sprintf(`{"spec":{"jobTemplate":{"spec":{"template":{"spec":{"initContainers":[{"name":"defrag-snapshot","env":[{"name":"ETCD_ENDPOINT","value":%q}]}]}}}}}}`, servers)
+	output, err = kubectlCommand("patch", "cronjob", "etcd-maintenance", "--namespace", ns, "-p", patch).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error pointing the etcd maintenance CronJob at %s: %s", servers, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestValidateEtcdMaintenance tests the schedule and snapshot target checks.
+func TestValidateEtcdMaintenance(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		configure func(ic *InstallConfig)
+		want      string
+	}{
+		{"disabled", func(ic *InstallConfig) { ic.EtcdMaintenance = false }, ""},
+		{"volume", func(ic *InstallConfig) {}, ""},
+		{"predefined schedule", func(ic *InstallConfig) { ic.EtcdMaintenanceSchedule = "@weekly" }, ""},
+		{"bucket", func(ic *InstallConfig) {
+			ic.EtcdMaintenanceBucket = "gs://sc-etcd-snapshots/prod/"
+			ic.EtcdMaintenanceGCPServiceAccount = "etcd@my-project.iam.gserviceaccount.com"
+		}, ""},
+		{"skip etcd", func(ic *InstallConfig) { ic.SkipEtcd = true }, "does not support --skip-etcd"},
+		{"bad schedule", func(ic *InstallConfig) { ic.EtcdMaintenanceSchedule = "daily" }, "--etcd-maintenance-schedule must be a cron schedule"},
+		{"bad bucket", func(ic *InstallConfig) { ic.EtcdMaintenanceBucket = "s3://snapshots" }, "--etcd-maintenance-bucket must be a gs://"},
+		{"keep none", func(ic *InstallConfig) { ic.EtcdMaintenanceKeep = 0 }, "--etcd-maintenance-keep must be at least 1"},
+		{"service account without bucket", func(ic *InstallConfig) {
+			ic.EtcdMaintenanceGCPServiceAccount = "etcd@my-project.iam.gserviceaccount.com"
+		}, "requires --etcd-maintenance-bucket"},
+	} {
+		ic := validInstallConfig()
+		ic.EtcdMaintenance = true
+		ic.EtcdMaintenanceSchedule = defaultEtcdMaintenanceSchedule
+		ic.EtcdMaintenanceKeep = defaultEtcdMaintenanceKeep
+		tc.configure(ic)
+
+		err := validateEtcdMaintenance(ic)
+		if tc.want == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: error does not match: got %v; want it to contain %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+// TestSetEtcdMaintenanceServers tests that an existing maintenance CronJob
+// is pointed at the new etcd, and that a missing one is left alone.
+func TestSetEtcdMaintenanceServers(t *testing.T) {
+	for _, found := range []bool{true, false} {
+		s, restore := stubExecutor(func(name string, args []string) execx.Response {
+			if found && strings.HasPrefix(strings.Join(args, " "), "get cronjob etcd-maintenance") {
+				return execx.Response{Stdout: "cronjob.batch/etcd-maintenance\n"}
+			}
+			return execx.Response{}
+		})
+		err := setEtcdMaintenanceServers("catalog", "http://etcd-v3-2:2379")
+		restore()
+		if err != nil {
+			t.Fatalf("Unexpected error updating the CronJob: %v", err)
+		}
+		patches := kubectlCalls(s, "patch")
+		if !found {
+			if len(patches) != 0 {
+				t.Fatalf("Unexpected patches of a missing CronJob: %q", patches)
+			}
+			continue
+		}
+		if len(patches) != 1 || !strings.Contains(patches[0], `{"name":"ETCD_ENDPOINT","value":"http://etcd-v3-2:2379"}`) {
+			t.Fatalf("Patch does not match: got %q", patches)
+		}
+	}
+}
@@ -0,0 +1,702 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/Masterminds/semver"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// etcdMigrationDataDir is the mount path of the data volume of the
+	// migrated etcd.
+	etcdMigrationDataDir = "/var/etcd"
+
+	// etcdSnapshotPath is where the snapshot of the current etcd is saved
+	// in its pod.
+	etcdSnapshotPath = "/tmp/sc-migration-snapshot.db"
+
+	// defaultEtcdStorageSize is the default size of the data volume of a
+	// migrated etcd.
+	defaultEtcdStorageSize = "10Gi"
+
+	// etcdServersAnnotation is the annotation of the catalog namespace
+	// recording the etcd an etcd migration or restore switched the api
+	// server over to, which later installs keep.
+	etcdServersAnnotation = "servicecatalog.k8s.io/sc-etcd-servers"
+)
+
+// etcdMigrationConfig contains the settings of the etcd the data is
+// migrated or restored to.
+type etcdMigrationConfig struct {
+	// Version is the etcd version to migrate to.
+	Version string
+
+	// Timeout bounds the migration.
+	Timeout time.Duration
+
+	// StorageSize is the size of the data volume.
+	StorageSize string
+
+	// StorageClass is the storage class of the data volume, the default
+	// class of the cluster if empty.
+	StorageClass string
+}
+
+// addEtcdStorageFlags adds the flags of the data volume of the new etcd to
+// c.
+func addEtcdStorageFlags(c *cobra.Command, mc *etcdMigrationConfig) {
+	c.Flags().StringVar(&mc.StorageSize, "etcd-storage-size", defaultEtcdStorageSize, "Size of the data volume of the new etcd")
+	c.Flags().StringVar(&mc.StorageClass, "etcd-storage-class", "", "StorageClass of the data volume of the new etcd, the default class of the cluster if not set")
+}
+
+// validate checks the storage settings of mc, and that its storage class
+// exists.
+func (mc *etcdMigrationConfig) validate() error {
+	if v, err := parseQuantity(mc.StorageSize); err != nil || v <= 0 {
+		return fmt.Errorf("invalid --etcd-storage-size %q, expected a quantity such as 10Gi", mc.StorageSize)
+	}
+	if mc.StorageClass == "" {
+		return nil
+	}
+	found, err := storageClassExists(mc.StorageClass)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return messages.Errorf(messages.StorageClassNotFound, mc.StorageClass)
+	}
+	return nil
+}
+
+// etcdMigrationPollInterval is how often the migrated etcd pod is read
+// while waiting for it.
+var etcdMigrationPollInterval = 5 * time.Second
+
+// etcdMember is the etcd pod the api server uses.
+type etcdMember struct {
+	// Pod is the name of the pod.
+	Pod string
+
+	// Version is the etcd version, from the image tag.
+	Version string
+
+	// IPv6 is true if the pod has an IPv6 address.
+	IPv6 bool
+
+	// Resources are the resources of the etcd container, which the
+	// migrated etcd gets too.
+	Resources map[string]map[string]string
+
+	// Affinity and NodeSelector schedule the pod, e.g. on the nodes of the
+	// architectures of the images. The migrated etcd gets them too.
+	Affinity     json.RawMessage
+	NodeSelector map[string]string
+}
+
+// etcdMigration is the state of an etcd data migration, used to roll it
+// back.
+type etcdMigration struct {
+	ns string
+
+	// name is the name of the StatefulSet and Service of the new etcd.
+	name string
+
+	// config sets the storage of the new etcd, and resources, affinity
+	// and nodeSelector its resources and scheduling, if any.
+	config       *etcdMigrationConfig
+	resources    map[string]map[string]string
+	affinity     json.RawMessage
+	nodeSelector map[string]string
+
+	// replicas is the number of api server replicas before the apiserver
+	// was stopped, and scaled is true once it was.
+	replicas int32
+	scaled   bool
+
+	// oldServers is the --etcd-servers of the api server, and argPath the
+	// JSON pointer to its value, set once the apiserver was switched over.
+	oldServers string
+	argPath    string
+	switched   bool
+}
+
+// etcdMigrationNeeded returns whether going from etcd version current to
+// target needs a data migration, which is the case for minor version
+// bumps of etcd 3. Patch updates keep the data format.
+func etcdMigrationNeeded(current, target string) (bool, error) {
+	cv, err := semver.NewVersion(current)
+	if err != nil {
+		return false, fmt.Errorf("invalid current etcd version %q: %v", current, err)
+	}
+	tv, err := semver.NewVersion(target)
+	if err != nil {
+		return false, fmt.Errorf("invalid etcd version %q: %v", target, err)
+	}
+	if cv.Major() != 3 || tv.Major() != 3 {
+		return false, fmt.Errorf("only migrations between etcd 3.x versions are supported, not from %s to %s", current, target)
+	}
+	if tv.LessThan(cv) {
+		return false, fmt.Errorf("etcd %s is older than the current version %s, downgrades are not supported", target, current)
+	}
+	return tv.Minor() != cv.Minor(), nil
+}
+
+// etcdMigrationName returns the name of the etcd StatefulSet and Service
+// for etcd version target, e.g. etcd-v3-2 for 3.2.24.
+func etcdMigrationName(target string) string {
+	v := semver.MustParse(target)
+	return fmt.Sprintf("etcd-v%d-%d", v.Major(), v.Minor())
+}
+
+// migrateEtcd migrates the data of the etcd the api server in namespace ns
+// uses to a new etcd running version mc.Version, if it is a new minor
+// version. The api server is stopped while a snapshot of the current etcd
+// is restored into the new one, and is switched over once the new etcd
+// holds as many keys as the snapshot. If any step fails, the api server is
+// switched back and restarted and the new etcd is deleted. The current
+// etcd is left untouched.
+func migrateEtcd(ns string, mc *etcdMigrationConfig) error {
+	target := mc.Version
+	if _, err := semver.NewVersion(target); err != nil {
+		return fmt.Errorf("invalid --etcd-version %q: %v", target, err)
+	}
+	if err := mc.validate(); err != nil {
+		return err
+	}
+	d, err := getAPIServerDeployment(ns)
+	if err != nil {
+		return err
+	}
+	servers, argPath, err := apiServerEtcdServers(d)
+	if err != nil {
+		return err
+	}
+	current, err := currentEtcdMember(ns, servers)
+	if err != nil {
+		return err
+	}
+	needed, err := etcdMigrationNeeded(current.Version, target)
+	if err != nil {
+		return err
+	}
+	if !needed {
+		fmt.Printf("etcd %s does not need a data migration to %s\n", current.Version, target)
+		return nil
+	}
+
+	m := &etcdMigration{
+		ns:           ns,
+		name:         etcdMigrationName(target),
+		config:       mc,
+		resources:    current.Resources,
+		affinity:     current.Affinity,
+		nodeSelector: current.NodeSelector,
+		replicas:     d.Spec.Replicas,
+		oldServers:   servers,
+		argPath:      argPath,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mc.Timeout)
+	defer cancel()
+	if err := m.run(ctx, current, target); err != nil {
+		fmt.Printf("etcd migration failed, rolling back: %v\n", err)
+		m.rollback()
+		return fmt.Errorf("error migrating etcd from %s to %s: %v", current.Version, target, err)
+	}
+	fmt.Printf("migrated etcd from %s to %s, the old etcd can be deleted once the catalog works\n", current.Version, target)
+	return nil
+}
+
+// upgradeEtcdVersion returns the etcd version an upgrade of the
+// installation in namespace ns migrates the data to: the etcd version of
+// sc, if the etcd sc runs for the installation has an older minor version,
+// else "".
+func upgradeEtcdVersion(ns string) (string, error) {
+	d, err := getAPIServerDeployment(ns)
+	if err != nil {
+		return "", err
+	}
+	servers, _, err := apiServerEtcdServers(d)
+	if err != nil {
+		return "", err
+	}
+	current, err := currentEtcdMember(ns, servers)
+	if err != nil {
+		// An external etcd is upgraded by its owners.
+		fmt.Printf("not checking the etcd version: %v\n", err)
+		return "", nil
+	}
+	cv, err := semver.NewVersion(current.Version)
+	if err != nil {
+		return "", fmt.Errorf("invalid current etcd version %q: %v", current.Version, err)
+	}
+	if !cv.LessThan(semver.MustParse(etcdClusterVersion)) {
+		return "", nil
+	}
+	needed, err := etcdMigrationNeeded(current.Version, etcdClusterVersion)
+	if err != nil || !needed {
+		return "", err
+	}
+	return etcdClusterVersion, nil
+}
+
+// installedEtcdServers returns the etcd servers recorded by the last etcd
+// migration or restore of the installation in namespace ns, if any.
+func installedEtcdServers(ns string) (string, error) {
+	output, err := kubectlCommand("get", "namespace", ns, "--ignore-not-found",
+		"-o", "jsonpath={.metadata.annotations."+strings.Replace(etcdServersAnnotation, ".", `\.`, -1)+"}").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting namespace %s: %s", ns, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// recordEtcdServers records servers as the etcd of the installation in
+// namespace ns.
+func recordEtcdServers(ns, servers string) error {
+	output, err := kubectlCommand("annotate", "namespace", ns, "--overwrite", etcdServersAnnotation+"="+servers).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error recording the etcd servers %s in namespace %s: %s", servers, ns, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (m *etcdMigration) run(ctx context.Context, current *etcdMember, target string) error {
+	snapshot := func() (int64, error) {
+		fmt.Printf("saving a snapshot of etcd %s\n", current.Pod)
+		want, err := etcdKeyCount(m.ns, current.Pod, "")
+		if err != nil {
+			return 0, err
+		}
+		return want, etcdExec(m.ns, current.Pod, "etcdctl snapshot save "+etcdSnapshotPath)
+	}
+	copySnapshot := func(pod string) error {
+		return copyEtcdSnapshot(ctx, m.ns, current.Pod, pod)
+	}
+	return m.restore(ctx, current.IPv6, target, snapshot, copySnapshot)
+}
+
+// restore deploys etcd version target, stops the api server, takes the
+// snapshot, restores the snapshot copied by copySnapshot into the new etcd
+// pod and switches the api server over once the new etcd holds the number
+// of keys snapshot returned. A negative number only requires some keys.
+func (m *etcdMigration) restore(ctx context.Context, ipv6 bool, target string, snapshot func() (int64, error), copySnapshot func(pod string) error) error {
+	fmt.Printf("deploying etcd %s as %s\n", target, m.name)
+	if err := m.deploy(ipv6, target); err != nil {
+		return err
+	}
+
+	fmt.Println("stopping the api server")
+	if err := scaleAPIServer(m.ns, 0); err != nil {
+		return err
+	}
+	m.scaled = true
+
+	want, err := snapshot()
+	if err != nil {
+		return err
+	}
+
+	pod := m.name + "-0"
+	if err := m.waitForPod(ctx, pod); err != nil {
+		return err
+	}
+	fmt.Printf("restoring the snapshot into %s\n", pod)
+	if err := copySnapshot(pod); err != nil {
+		return err
+	}
+	restore := fmt.Sprintf("etcdctl snapshot restore %[1]s/snapshot.db --data-dir %[1]s/data && touch %[1]s/restored", etcdMigrationDataDir)
+	if err := etcdExec(m.ns, pod, restore); err != nil {
+		return err
+	}
+	if err := m.waitForReady(ctx); err != nil {
+		return err
+	}
+
+	got, err := etcdKeyCount(m.ns, pod, "")
+	if err != nil {
+		return err
+	}
+	switch {
+	case want >= 0 && got != want:
+		return fmt.Errorf("etcd %s has %d keys after the restore, the snapshot has %d", m.name, got, want)
+	case want < 0 && got == 0:
+		return fmt.Errorf("etcd %s holds no keys after the restore", m.name)
+	}
+	fmt.Printf("verified the %d keys of the restored etcd\n", got)
+
+	fmt.Println("switching the api server over to the new etcd")
+	servers := fmt.Sprintf("http://%s:2379", m.name)
+	if err := setAPIServerEtcdServers(m.ns, m.argPath, servers); err != nil {
+		return err
+	}
+	m.switched = true
+	if err := scaleAPIServer(m.ns, m.replicas); err != nil {
+		return err
+	}
+	m.scaled = false
+	if err := waitForAPIServerRollout(ctx, m.ns); err != nil {
+		return err
+	}
+	if err := setEtcdMaintenanceServers(m.ns, servers); err != nil {
+		return err
+	}
+	// Later installs render the api server with the new etcd.
+	return recordEtcdServers(m.ns, servers)
+}
+
+// deploy applies the StatefulSet and Service of the new etcd.
+func (m *etcdMigration) deploy(ipv6 bool, target string) (err error) {
+	ws, err := createWorkspace(m.ns, "etcd-migration", workspace.Options{Exclusive: true, Cleanup: workspace.Always})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+	dir := ws.Dir
+
+	listenAddress := "0.0.0.0"
+	if ipv6 {
+		listenAddress = "[::]"
+	}
+	resources, err := jsonTemplateValue(m.resources, len(m.resources) > 0)
+	if err != nil {
+		return err
+	}
+	nodeSelector, err := jsonTemplateValue(m.nodeSelector, len(m.nodeSelector) > 0)
+	if err != nil {
+		return err
+	}
+	affinity := template.HTML("")
+	if len(m.affinity) > 0 && string(m.affinity) != "null" {
+		affinity = template.HTML(m.affinity)
+	}
+	data := map[string]interface{}{
+		"Namespace":     m.ns,
+		"Name":          m.name,
+		"Image":         "quay.io/coreos/etcd:v" + target,
+		"DataDir":       etcdMigrationDataDir,
+		"ListenAddress": listenAddress,
+		"Resources":     resources,
+		"Affinity":      affinity,
+		"NodeSelector":  nodeSelector,
+		"StorageSize":   m.config.StorageSize,
+		"StorageClass":  m.config.StorageClass,
+		"APIVersions":   clusterAPIVersions(),
+	}
+	f := filepath.Join(dir, "etcd-migration.yaml")
+	if err := generateFileFromTmpl(f, "templates/sc/etcd-migration.yaml.tmpl", data); err != nil {
+		return err
+	}
+	output, err := kubectlCommand("apply", "-f", f).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deploying etcd %s: %s", m.name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// jsonTemplateValue returns v as JSON to render into a template, or empty
+// if it is not set.
+func jsonTemplateValue(v interface{}, set bool) (template.HTML, error) {
+	if !set {
+		return "", nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return template.HTML(b), nil
+}
+
+// waitForPod waits for pod to run. It is not ready until the snapshot is
+// restored.
+func (m *etcdMigration) waitForPod(ctx context.Context, pod string) error {
+	for {
+		output, err := kubectlCommand("get", "pod", pod, "--namespace", m.ns,
+			"-o", "jsonpath={.status.phase}").CombinedOutput()
+		if err == nil && strings.TrimSpace(string(output)) == "Running" {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("etcd pod %s is not running: %s", pod, strings.TrimSpace(string(output)))
+		case <-time.After(etcdMigrationPollInterval):
+		}
+	}
+}
+
+// waitForReady waits for the new etcd to serve the restored data.
+func (m *etcdMigration) waitForReady(ctx context.Context) error {
+	args := []string{"rollout", "status", "statefulset/" + m.name, "--namespace", m.ns}
+	if deadline, ok := ctx.Deadline(); ok {
+		args = append(args, "--timeout", time.Until(deadline).Round(time.Second).String())
+	}
+	output, err := kubectlCommand(args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error waiting for etcd %s: %s", m.name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// rollback switches the api server back to the current etcd, restarts it
+// and deletes the new etcd. Errors are printed, so that every step runs.
+func (m *etcdMigration) rollback() {
+	if m.switched {
+		if err := setAPIServerEtcdServers(m.ns, m.argPath, m.oldServers); err != nil {
+			fmt.Printf("WARNING: %v\n", err)
+		}
+	}
+	if m.scaled {
+		if err := scaleAPIServer(m.ns, m.replicas); err != nil {
+			fmt.Printf("WARNING: %v\n", err)
+		}
+	}
+	for _, r := range []string{"statefulset", "service"} {
+		output, err := kubectlCommand("delete", r, m.name, "--namespace", m.ns, "--ignore-not-found").CombinedOutput()
+		if err != nil {
+			fmt.Printf("WARNING: error deleting %s %s/%s: %s\n", r, m.ns, m.name, strings.TrimSpace(string(output)))
+		}
+	}
+}
+
+// apiServerDeployment is the subset of the api server Deployment read to
+// migrate etcd.
+type apiServerDeployment struct {
+	Spec struct {
+		Replicas int32 `json:"replicas"`
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Name string   `json:"name"`
+					Args []string `json:"args"`
+				} `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+func getAPIServerDeployment(ns string) (*apiServerDeployment, error) {
+	output, err := kubectlCommand("get", "deployment", "apiserver", "--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting api server deployment: %s", strings.TrimSpace(string(output)))
+	}
+	var d apiServerDeployment
+	if err := json.Unmarshal(output, &d); err != nil {
+		return nil, fmt.Errorf("error unmarshalling api server deployment: %v", err)
+	}
+	return &d, nil
+}
+
+// apiServerEtcdServers returns the --etcd-servers of the apiserver
+// container of d, and the JSON pointer to its value.
+func apiServerEtcdServers(d *apiServerDeployment) (string, string, error) {
+	for i, c := range d.Spec.Template.Spec.Containers {
+		if c.Name != "apiserver" {
+			continue
+		}
+		for j, a := range c.Args {
+			if a == "--etcd-servers" && j+1 < len(c.Args) {
+				return c.Args[j+1], fmt.Sprintf("/spec/template/spec/containers/%d/args/%d", i, j+1), nil
+			}
+			if strings.HasPrefix(a, "--etcd-servers=") {
+				return "", "", fmt.Errorf("api server sets %s, expected --etcd-servers followed by its value", a)
+			}
+		}
+	}
+	return "", "", fmt.Errorf("api server deployment has no --etcd-servers")
+}
+
+// etcdPodSelector returns the label selector of the etcd pods behind
+// servers, the first --etcd-servers URL of the api server. It fails for
+// etcd sc does not manage, which has no service in ns.
+func etcdPodSelector(ns, servers string) (string, error) {
+	u, err := url.Parse(strings.Split(servers, ",")[0])
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid --etcd-servers %q of the api server", servers)
+	}
+	svc := strings.Split(u.Hostname(), ".")[0]
+	output, err := kubectlCommand("get", "service", svc, "--namespace", ns,
+		"-o", "jsonpath={.spec.selector}").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("etcd %s is not managed by sc in namespace %s: %s", servers, ns, strings.TrimSpace(string(output)))
+	}
+	var selector map[string]string
+	if err := json.Unmarshal(output, &selector); err != nil || len(selector) == 0 {
+		return "", fmt.Errorf("etcd service %s has no selector", svc)
+	}
+	var labels []string
+	for k, v := range selector {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ","), nil
+}
+
+// catalogEtcdSelector returns the label selector of the pods of the etcd
+// the api server in namespace ns uses, e.g. after an etcd migration, or of
+// the etcd sc installs if there is no api server.
+func catalogEtcdSelector(ns string) (string, error) {
+	output, err := kubectlCommand("get", "deployment", "apiserver", "--namespace", ns,
+		"--ignore-not-found", "-o", "json").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting api server deployment: %s", strings.TrimSpace(string(output)))
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return "app=etcd", nil
+	}
+	var d apiServerDeployment
+	if err := json.Unmarshal(output, &d); err != nil {
+		return "", fmt.Errorf("error unmarshalling api server deployment: %v", err)
+	}
+	servers, _, err := apiServerEtcdServers(&d)
+	if err != nil {
+		return "", err
+	}
+	return etcdPodSelector(ns, servers)
+}
+
+// currentEtcdMember returns the etcd pod behind servers, the first
+// --etcd-servers URL of the api server. It fails for etcd sc does not
+// manage, which has no service in ns.
+func currentEtcdMember(ns, servers string) (*etcdMember, error) {
+	selector, err := etcdPodSelector(ns, servers)
+	if err != nil {
+		return nil, err
+	}
+	output, err := kubectlCommand("get", "pods", "--namespace", ns, "-l", selector, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting etcd pods: %s", strings.TrimSpace(string(output)))
+	}
+	var pods struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Spec struct {
+				Containers []struct {
+					Name      string                       `json:"name"`
+					Image     string                       `json:"image"`
+					Resources map[string]map[string]string `json:"resources"`
+				} `json:"containers"`
+				Affinity     json.RawMessage   `json:"affinity"`
+				NodeSelector map[string]string `json:"nodeSelector"`
+			} `json:"spec"`
+			Status struct {
+				Phase string `json:"phase"`
+				PodIP string `json:"podIP"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return nil, fmt.Errorf("error unmarshalling etcd pods: %v", err)
+	}
+	for _, p := range pods.Items {
+		if p.Status.Phase != "Running" {
+			continue
+		}
+		for _, c := range p.Spec.Containers {
+			if c.Name != "etcd" {
+				continue
+			}
+			i := strings.LastIndex(c.Image, ":")
+			if i < 0 {
+				return nil, fmt.Errorf("etcd image %s of pod %s has no version tag", c.Image, p.Metadata.Name)
+			}
+			return &etcdMember{
+				Pod:          p.Metadata.Name,
+				Version:      strings.TrimPrefix(c.Image[i+1:], "v"),
+				IPv6:         strings.Contains(p.Status.PodIP, ":"),
+				Resources:    c.Resources,
+				Affinity:     p.Spec.Affinity,
+				NodeSelector: p.Spec.NodeSelector,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("no running etcd pod matches %s", selector)
+}
+
+// etcdExec runs script with the etcd v3 API in the etcd container of pod.
+func etcdExec(ns, pod, script string) error {
+	output, err := kubectlCommand("exec", pod, "--namespace", ns, "-c", "etcd", "--",
+		"/bin/sh", "-c", "ETCDCTL_API=3 "+script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error running %q in etcd pod %s: %s", script, pod, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// copyEtcdSnapshot streams the snapshot of the current etcd pod from into
+// the data volume of the new etcd pod to.
+func copyEtcdSnapshot(ctx context.Context, ns, from, to string) error {
+	_, err := execx.Run(ctx,
+		kubectlCommand("exec", from, "--namespace", ns, "-c", "etcd", "--", "cat", etcdSnapshotPath),
+		kubectlCommand("exec", "-i", to, "--namespace", ns, "-c", "etcd", "--",
+			"/bin/sh", "-c", "cat > "+etcdMigrationDataDir+"/snapshot.db"))
+	if err != nil {
+		return fmt.Errorf("error copying the etcd snapshot: %v", err)
+	}
+	return nil
+}
+
+func scaleAPIServer(ns string, replicas int32) error {
+	output, err := kubectlCommand("scale", "deployment", "apiserver", "--namespace", ns,
+		fmt.Sprintf("--replicas=%d", replicas)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error scaling the api server to %d replicas: %s", replicas, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// setAPIServerEtcdServers sets the --etcd-servers value at argPath of the
+// api server deployment.
+func setAPIServerEtcdServers(ns, argPath, servers string) error {
+	patch, err := json.Marshal([]map[string]string{{"op": "replace", "path": argPath, "value": servers}})
+	if err != nil {
+		return err
+	}
+	output, err := kubectlCommand("patch", "deployment", "apiserver", "--namespace", ns,
+		"--type", "json", "-p", string(patch)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error setting the etcd servers of the api server to %s: %s", servers, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func waitForAPIServerRollout(ctx context.Context, ns string) error {
+	args := []string{"rollout", "status", "deployment/apiserver", "--namespace", ns}
+	if deadline, ok := ctx.Deadline(); ok {
+		args = append(args, "--timeout", time.Until(deadline).Round(time.Second).String())
+	}
+	output, err := kubectlCommand(args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error waiting for the api server on the new etcd: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
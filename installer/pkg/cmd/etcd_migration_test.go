@@ -0,0 +1,296 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestEtcdMigrationNeeded tests that only minor version upgrades of etcd 3
+// migrate the data.
+func TestEtcdMigrationNeeded(t *testing.T) {
+	for _, tc := range []struct {
+		current, target string
+		want            bool
+		wantErr         bool
+	}{
+		{current: "3.1.8", target: "3.2.24", want: true},
+		{current: "3.0.17", target: "3.3.10", want: true},
+		{current: "3.1.8", target: "3.1.20", want: false},
+		{current: "3.2.24", target: "3.2.24", want: false},
+		{current: "3.2.24", target: "3.1.8", wantErr: true},
+		{current: "3.3.10", target: "4.0.0", wantErr: true},
+		{current: "2.3.8", target: "3.0.17", wantErr: true},
+		{current: "latest", target: "3.2.24", wantErr: true},
+	} {
+		got, err := etcdMigrationNeeded(tc.current, tc.target)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("Expected an error migrating etcd %s to %s", tc.current, tc.target)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error migrating etcd %s to %s: %v", tc.current, tc.target, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Migration of etcd %s to %s does not match: got %v; want %v", tc.current, tc.target, got, tc.want)
+		}
+	}
+}
+
+// TestAPIServerEtcdServers tests that the --etcd-servers value and its JSON
+// pointer are found in the apiserver container.
+func TestAPIServerEtcdServers(t *testing.T) {
+	d := &apiServerDeployment{}
+	d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers,
+		struct {
+			Name string   `json:"name"`
+			Args []string `json:"args"`
+		}{Name: "sidecar"},
+		struct {
+			Name string   `json:"name"`
+			Args []string `json:"args"`
+		}{Name: "apiserver", Args: []string{"apiserver", "--etcd-servers", "http://etcd-svc:2379"}})
+
+	servers, path, err := apiServerEtcdServers(d)
+	if err != nil {
+		t.Fatalf("Unexpected error finding the etcd servers: %v", err)
+	}
+	if want := "http://etcd-svc:2379"; servers != want {
+		t.Fatalf("Etcd servers do not match: got %q; want %q", servers, want)
+	}
+	if want := "/spec/template/spec/containers/1/args/2"; path != want {
+		t.Fatalf("Path does not match: got %q; want %q", path, want)
+	}
+}
+
+// etcdMigrationStub responds to the commands of an etcd migration from etcd
+// 3.1.8 holding 42 keys. The new etcd holds restoredKeys keys.
+func etcdMigrationStub(restoredKeys string) func(name string, args []string) execx.Response {
+	return func(name string, args []string) execx.Response {
+		cmd := strings.Join(args, " ")
+		switch {
+		case strings.HasPrefix(cmd, "get deployment apiserver"):
+			return execx.Response{Stdout: `{"spec":{"replicas":2,"template":{"spec":{"containers":[` +
+				`{"name":"apiserver","args":["apiserver","--etcd-servers","http://etcd-cluster-client:2379"]}]}}}}`}
+		case strings.HasPrefix(cmd, "get service etcd-cluster-client"):
+			return execx.Response{Stdout: `{"app":"etcd","etcd_cluster":"etcd-cluster"}`}
+		case strings.HasPrefix(cmd, "get pods"):
+			return execx.Response{Stdout: `{"items":[{"metadata":{"name":"etcd-cluster-0000"},` +
+				`"spec":{"containers":[{"name":"etcd","image":"quay.io/coreos/etcd:v3.1.8"}]},` +
+				`"status":{"phase":"Running","podIP":"10.0.0.5"}}]}`}
+		case strings.HasPrefix(cmd, "get pod etcd-v3-2-0"):
+			return execx.Response{Stdout: "Running"}
+		case strings.HasPrefix(cmd, "exec etcd-cluster-0000") && strings.Contains(cmd, "--keys-only"):
+			return execx.Response{Stdout: `{"count":42}`}
+		case strings.HasPrefix(cmd, "exec etcd-v3-2-0") && strings.Contains(cmd, "--keys-only"):
+			return execx.Response{Stdout: `{"count":` + restoredKeys + `}`}
+		}
+		return execx.Response{}
+	}
+}
+
+// kubectlCalls returns the kubectl commands of s starting with one of the
+// verbs, e.g. "scale" or "patch".
+func kubectlCalls(s *execx.Stub, verbs ...string) []string {
+	var calls []string
+	for _, c := range s.Calls() {
+		for _, v := range verbs {
+			if c.Name == KubectlBinaryName && len(c.Args) > 0 && c.Args[0] == v {
+				calls = append(calls, strings.Join(c.Args, " "))
+			}
+		}
+	}
+	return calls
+}
+
+// TestMigrateEtcd tests that the api server is stopped during the
+// migration and switched over to the new etcd with its replicas restored.
+func TestMigrateEtcd(t *testing.T) {
+	s, restore := stubExecutor(etcdMigrationStub("42"))
+	defer restore()
+
+	if err := migrateEtcd("catalog", &etcdMigrationConfig{Version: "3.2.24", Timeout: time.Minute, StorageSize: defaultEtcdStorageSize}); err != nil {
+		t.Fatalf("Unexpected error migrating etcd: %v", err)
+	}
+
+	want := []string{
+		"scale deployment apiserver --namespace catalog --replicas=0",
+		`patch deployment apiserver --namespace catalog --type json -p [{"op":"replace","path":"/spec/template/spec/containers/0/args/2","value":"http://etcd-v3-2:2379"}]`,
+		"scale deployment apiserver --namespace catalog --replicas=2",
+		"annotate namespace catalog --overwrite servicecatalog.k8s.io/sc-etcd-servers=http://etcd-v3-2:2379",
+	}
+	if got := kubectlCalls(s, "scale", "patch", "delete", "annotate"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Api server commands do not match: got %q; want %q", got, want)
+	}
+}
+
+// TestMigrateEtcdRollback tests that a key count mismatch restarts the api
+// server on the current etcd and deletes the new etcd.
+func TestMigrateEtcdRollback(t *testing.T) {
+	s, restore := stubExecutor(etcdMigrationStub("41"))
+	defer restore()
+
+	err := migrateEtcd("catalog", &etcdMigrationConfig{Version: "3.2.24", Timeout: time.Minute, StorageSize: defaultEtcdStorageSize})
+	if err == nil || !strings.Contains(err.Error(), "has 41 keys after the restore, the snapshot has 42") {
+		t.Fatalf("Error does not match: got %v; want a key count mismatch", err)
+	}
+
+	want := []string{
+		"scale deployment apiserver --namespace catalog --replicas=0",
+		"scale deployment apiserver --namespace catalog --replicas=2",
+		"delete statefulset etcd-v3-2 --namespace catalog --ignore-not-found",
+		"delete service etcd-v3-2 --namespace catalog --ignore-not-found",
+	}
+	if got := kubectlCalls(s, "scale", "patch", "delete"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Rollback commands do not match: got %q; want %q", got, want)
+	}
+}
+
+// TestMigrateEtcdSameMinor tests that a patch update of etcd changes
+// nothing.
+func TestMigrateEtcdSameMinor(t *testing.T) {
+	s, restore := stubExecutor(etcdMigrationStub("42"))
+	defer restore()
+
+	if err := migrateEtcd("catalog", &etcdMigrationConfig{Version: "3.1.20", Timeout: time.Minute, StorageSize: defaultEtcdStorageSize}); err != nil {
+		t.Fatalf("Unexpected error migrating etcd: %v", err)
+	}
+	if got := kubectlCalls(s, "apply", "scale", "patch", "exec"); len(got) != 0 {
+		t.Fatalf("Unexpected commands for a patch update: %q", got)
+	}
+}
+
+// TestEtcdMigrationManifest tests that the new etcd is a StatefulSet of the
+// API version of the cluster with the resources of the current etcd and
+// the configured storage.
+func TestEtcdMigrationManifest(t *testing.T) {
+	var manifest string
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch {
+		case len(args) > 0 && args[0] == "version":
+			return execx.Response{Stdout: `{"serverVersion":{"gitVersion":"v1.9.6"}}`}
+		case len(args) > 2 && args[0] == "apply":
+			b, err := ioutil.ReadFile(args[2])
+			if err != nil {
+				t.Fatalf("Unexpected error reading the manifest: %v", err)
+			}
+			manifest = string(b)
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	m := &etcdMigration{
+		ns:           "catalog",
+		name:         etcdMigrationName("3.2.24"),
+		config:       &etcdMigrationConfig{StorageSize: "20Gi", StorageClass: "ssd"},
+		resources:    map[string]map[string]string{"requests": {"cpu": "100m"}},
+		affinity:     json.RawMessage(`{"nodeAffinity":{}}`),
+		nodeSelector: map[string]string{"beta.kubernetes.io/arch": "arm64"},
+	}
+	if err := m.deploy(false, "3.2.24"); err != nil {
+		t.Fatalf("Unexpected error deploying etcd: %v", err)
+	}
+	for _, want := range []string{
+		"apiVersion: apps/v1\nkind: StatefulSet",
+		`resources: {"requests":{"cpu":"100m"}}`,
+		`storageClassName: "ssd"`,
+		`storage: "20Gi"`,
+		`nodeSelector: {"beta.kubernetes.io/arch":"arm64"}`,
+		`affinity: {"nodeAffinity":{}}`,
+	} {
+		if !strings.Contains(manifest, want) {
+			t.Fatalf("Manifest does not contain %q:\n%s", want, manifest)
+		}
+	}
+}
+
+// TestUpgradeEtcdVersion tests that upgrades migrate an etcd of sc older
+// than the etcd sc runs, and keep external etcds.
+func TestUpgradeEtcdVersion(t *testing.T) {
+	for _, tc := range []struct {
+		image string
+		want  string
+	}{
+		{image: "quay.io/coreos/etcd:v3.0.17", want: etcdClusterVersion},
+		{image: "quay.io/coreos/etcd:v" + etcdClusterVersion, want: ""},
+		{image: "", want: ""},
+	} {
+		_, restore := stubExecutor(func(name string, args []string) execx.Response {
+			cmd := strings.Join(args, " ")
+			switch {
+			case strings.HasPrefix(cmd, "get service etcd-cluster-client") && tc.image == "":
+				return execx.Response{Stderr: `services "etcd-cluster-client" not found`, ExitCode: 1}
+			case strings.HasPrefix(cmd, "get pods"):
+				return execx.Response{Stdout: `{"items":[{"metadata":{"name":"etcd-cluster-0000"},` +
+					`"spec":{"containers":[{"name":"etcd","image":"` + tc.image + `"}]},` +
+					`"status":{"phase":"Running","podIP":"10.0.0.5"}}]}`}
+			}
+			return etcdMigrationStub("42")(name, args)
+		})
+		got, err := upgradeEtcdVersion("catalog")
+		restore()
+		if err != nil {
+			t.Fatalf("Unexpected error checking etcd %q: %v", tc.image, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Etcd version for %q does not match: got %q; want %q", tc.image, got, tc.want)
+		}
+	}
+}
+
+// TestCatalogEtcdSelector tests that the etcd pods are those behind the
+// --etcd-servers of the api server, e.g. after an etcd migration, and
+// those of the etcd sc installs without an api server.
+func TestCatalogEtcdSelector(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		deployment string
+		want       string
+	}{
+		{"migrated", `{"spec":{"template":{"spec":{"containers":[` +
+			`{"name":"apiserver","args":["apiserver","--etcd-servers","http://etcd-v3-2:2379"]}]}}}}`, "app=etcd-v3-2,etcd_cluster=etcd-cluster"},
+		{"no api server", "", "app=etcd"},
+	} {
+		_, restore := stubExecutor(func(name string, args []string) execx.Response {
+			cmd := strings.Join(args, " ")
+			switch {
+			case strings.HasPrefix(cmd, "get deployment apiserver"):
+				return execx.Response{Stdout: tc.deployment}
+			case strings.HasPrefix(cmd, "get service etcd-v3-2"):
+				return execx.Response{Stdout: `{"etcd_cluster":"etcd-cluster","app":"etcd-v3-2"}`}
+			}
+			return execx.Response{}
+		})
+		got, err := catalogEtcdSelector("catalog")
+		restore()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: selector does not match: got %q; want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// catalogEtcdPrefix is the etcd key prefix of the catalog resources,
+	// for the default --etcd-prefix of the api server.
+	catalogEtcdPrefix = "/registry/servicecatalog.k8s.io/"
+
+	// defaultEtcdQuota is the default --quota-backend-bytes of etcd, its
+	// members raise the NOSPACE alarm and stop accepting writes above it.
+	defaultEtcdQuota = 2 << 30
+)
+
+// etcdUsage is the storage usage of an etcd member.
+type etcdUsage struct {
+	Pod string
+
+	// DBSize is the size of the database file and DBSizeInUse the part
+	// of it holding data, unknown before etcd 3.4. Defragmenting
+	// reclaims the rest.
+	DBSize, DBSizeInUse int64
+
+	// Quota is the --quota-backend-bytes of the member.
+	Quota int64
+
+	// Revision is the current revision. Compacted is true if old
+	// revisions were compacted, which the api server does periodically.
+	Revision  int64
+	Compacted bool
+
+	// Alarms are the raised alarms, e.g. NOSPACE.
+	Alarms []string
+
+	// Objects are the number of objects of each catalog resource, in
+	// the order of catalogEtcdResources.
+	Objects []int64
+}
+
+// catalogEtcdResources returns the catalog resources, e.g.
+// serviceinstances, as named in the etcd keys.
+func catalogEtcdResources() []string {
+	var resources []string
+	for _, r := range append(append([]string{}, namespacedCatalogResources...), clusterCatalogResources...) {
+		resources = append(resources, strings.TrimSuffix(r, ".servicecatalog.k8s.io"))
+	}
+	return resources
+}
+
+// getEtcdUsage returns the usage of a running member of the etcd of the
+// api server in namespace ns, or nil if there is none.
+func getEtcdUsage(ns string) (*etcdUsage, error) {
+	selector, err := catalogEtcdSelector(ns)
+	if err != nil {
+		return nil, err
+	}
+	output, err := kubectlCommand("get", "pods", "--namespace", ns, "-l", selector,
+		"--field-selector", "status.phase=Running", "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing etcd pods: %s", strings.TrimSpace(string(output)))
+	}
+	var pods struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Spec struct {
+				Containers []etcdContainer `json:"containers"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return nil, fmt.Errorf("error unmarshalling etcd pods: %v", err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, nil
+	}
+	pod := pods.Items[0]
+	u := &etcdUsage{Pod: pod.Metadata.Name, Quota: defaultEtcdQuota}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == "etcd" {
+			if u.Quota, err = c.quota(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	output, err = etcdctl(ns, u.Pod, "endpoint status -w json")
+	if err != nil {
+		return nil, err
+	}
+	var status []struct {
+		Status struct {
+			Header struct {
+				Revision int64 `json:"revision"`
+			} `json:"header"`
+			DBSize      int64 `json:"dbSize"`
+			DBSizeInUse int64 `json:"dbSizeInUse"`
+		} `json:"Status"`
+	}
+	if err := json.Unmarshal(output, &status); err != nil || len(status) == 0 {
+		return nil, fmt.Errorf("error unmarshalling the status of etcd pod %s: %s", u.Pod, strings.TrimSpace(string(output)))
+	}
+	u.DBSize, u.DBSizeInUse = status[0].Status.DBSize, status[0].Status.DBSizeInUse
+	u.Revision = status[0].Status.Header.Revision
+
+	if output, err = etcdctl(ns, u.Pod, "alarm list"); err != nil {
+		return nil, err
+	}
+	u.Alarms = parseEtcdAlarms(string(output))
+
+	// Reading the first revision fails once it was compacted.
+	if _, err := etcdctl(ns, u.Pod, `get "" --prefix --keys-only --limit 1 --rev 1`); err != nil {
+		if !strings.Contains(err.Error(), "compacted") {
+			return nil, err
+		}
+		u.Compacted = true
+	}
+
+	for _, r := range catalogEtcdResources() {
+		n, err := etcdKeyCount(ns, u.Pod, catalogEtcdPrefix+r+"/")
+		if err != nil {
+			return nil, err
+		}
+		u.Objects = append(u.Objects, n)
+	}
+	return u, nil
+}
+
+// etcdContainer is the subset of the etcd container read for its quota.
+type etcdContainer struct {
+	Name    string   `json:"name"`
+	Command []string `json:"command"`
+	Args    []string `json:"args"`
+	Env     []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"env"`
+}
+
+// quota returns the --quota-backend-bytes of the container, from its
+// command line or environment.
+func (c *etcdContainer) quota() (int64, error) {
+	value := ""
+	for _, e := range c.Env {
+		if e.Name == "ETCD_QUOTA_BACKEND_BYTES" {
+			value = e.Value
+		}
+	}
+	args := append(append([]string{}, c.Command...), c.Args...)
+	for i, a := range args {
+		switch {
+		case strings.HasPrefix(a, "--quota-backend-bytes="):
+			value = strings.TrimPrefix(a, "--quota-backend-bytes=")
+		case a == "--quota-backend-bytes" && i+1 < len(args):
+			value = args[i+1]
+		}
+	}
+	if value == "" {
+		return defaultEtcdQuota, nil
+	}
+	q, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid etcd --quota-backend-bytes %q", value)
+	}
+	if q <= 0 {
+		return defaultEtcdQuota, nil
+	}
+	return q, nil
+}
+
+// parseEtcdAlarms returns the alarms of the output of etcdctl alarm list,
+// lines like "memberID:8e9e05c52164694d alarm:NOSPACE".
+func parseEtcdAlarms(output string) []string {
+	var alarms []string
+	for _, l := range strings.Split(output, "\n") {
+		if i := strings.Index(l, "alarm:"); i >= 0 {
+			alarms = append(alarms, strings.TrimSpace(l[i+len("alarm:"):]))
+		}
+	}
+	return alarms
+}
+
+// lines describes u. degraded is true if etcd no longer accepts writes,
+// near its quota u is only flagged.
+func (u *etcdUsage) lines(nearQuota float64) (lines []string, degraded bool) {
+	size := fmt.Sprintf("etcd database: %s of %s quota (%.0f%%)", formatMemory(float64(u.DBSize)),
+		formatMemory(float64(u.Quota)), 100*float64(u.DBSize)/float64(u.Quota))
+	if u.DBSizeInUse > 0 {
+		size += fmt.Sprintf(", %s in use", formatMemory(float64(u.DBSizeInUse)))
+	}
+	lines = append(lines, size)
+
+	compaction := "never compacted"
+	if u.Compacted {
+		compaction = "compacted"
+	}
+	lines = append(lines, fmt.Sprintf("etcd revision: %d, %s", u.Revision, compaction))
+
+	var objects []string
+	for i, r := range catalogEtcdResources() {
+		if i < len(u.Objects) {
+			objects = append(objects, fmt.Sprintf("%s %d", r, u.Objects[i]))
+		}
+	}
+	lines = append(lines, "etcd objects: "+strings.Join(objects, ", "))
+
+	for _, a := range u.Alarms {
+		if a == "NOSPACE" {
+			lines = append(lines, "etcd alarm NOSPACE: the api server is read-only, compact and defragment etcd, then disarm the alarm")
+		} else {
+			lines = append(lines, "etcd alarm "+a)
+		}
+		degraded = true
+	}
+	if len(u.Alarms) == 0 && float64(u.DBSize) >= nearQuota*float64(u.Quota) {
+		lines = append(lines, "WARNING: the etcd database is near its quota, compact and defragment etcd before the api server goes read-only")
+	}
+	if u.DBSizeInUse > 0 && u.DBSize-u.DBSizeInUse > u.DBSize/2 {
+		lines = append(lines, fmt.Sprintf("WARNING: %s of the etcd database is free, defragment etcd to reclaim it",
+			formatMemory(float64(u.DBSize-u.DBSizeInUse))))
+	}
+	return lines, degraded
+}
+
+// etcdctl runs etcdctl with the etcd v3 API and args, a shell command line,
+// in the etcd container of pod and returns its output.
+func etcdctl(ns, pod, args string) ([]byte, error) {
+	cmd := kubectlCommand("exec", pod, "--namespace", ns, "-c", "etcd", "--",
+		"/bin/sh", "-c", "ETCDCTL_API=3 etcdctl "+args)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running etcdctl %s in etcd pod %s: %s", args, pod, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// etcdKeyCount returns the number of keys with prefix in the etcd of pod.
+// The count of a range response is the number of keys in the range,
+// whatever the limit.
+func etcdKeyCount(ns, pod, prefix string) (int64, error) {
+	output, err := etcdctl(ns, pod, "get '"+prefix+"' --prefix --keys-only --limit 1 -w json")
+	if err != nil {
+		return 0, err
+	}
+	var resp struct {
+		Count int64 `json:"count"`
+	}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return 0, fmt.Errorf("error unmarshalling the keys of etcd pod %s: %v", pod, err)
+	}
+	return resp.Count, nil
+}
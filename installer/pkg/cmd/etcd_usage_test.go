@@ -0,0 +1,145 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestEtcdContainerQuota tests that the quota is read from the command
+// line, then the environment, and defaults to the etcd default.
+func TestEtcdContainerQuota(t *testing.T) {
+	for _, tc := range []struct {
+		c    etcdContainer
+		want int64
+	}{
+		{etcdContainer{Command: []string{"/usr/local/bin/etcd"}}, defaultEtcdQuota},
+		{etcdContainer{Command: []string{"etcd", "--quota-backend-bytes=4294967296"}}, 4 << 30},
+		{etcdContainer{Args: []string{"--quota-backend-bytes", "1073741824"}}, 1 << 30},
+		{etcdContainer{Env: []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		}{{Name: "ETCD_QUOTA_BACKEND_BYTES", Value: "536870912"}}}, 512 << 20},
+	} {
+		got, err := tc.c.quota()
+		if err != nil {
+			t.Fatalf("Unexpected error reading the quota of %+v: %v", tc.c, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Quota of %+v does not match: got %d; want %d", tc.c, got, tc.want)
+		}
+	}
+
+	if _, err := (&etcdContainer{Args: []string{"--quota-backend-bytes=8G"}}).quota(); err == nil {
+		t.Fatalf("Expected an error reading an invalid quota")
+	}
+}
+
+// TestParseEtcdAlarms tests that the alarm names are read from etcdctl
+// alarm list.
+func TestParseEtcdAlarms(t *testing.T) {
+	got := parseEtcdAlarms("memberID:8e9e05c52164694d alarm:NOSPACE\nmemberID:91bc3c398fb3c146 alarm:CORRUPT\n")
+	if want := []string{"NOSPACE", "CORRUPT"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Alarms do not match: got %q; want %q", got, want)
+	}
+	if got := parseEtcdAlarms(""); len(got) != 0 {
+		t.Fatalf("Unexpected alarms: %q", got)
+	}
+}
+
+// TestEtcdUsageLines tests that a database near its quota or mostly free is
+// flagged, and that only alarms degrade the installation.
+func TestEtcdUsageLines(t *testing.T) {
+	objects := make([]int64, len(catalogEtcdResources()))
+	objects[0] = 12
+
+	u := &etcdUsage{DBSize: 100 << 20, DBSizeInUse: 80 << 20, Quota: 2 << 30, Revision: 1234, Compacted: true, Objects: objects}
+	lines, degraded := u.lines(defaultNearLimit)
+	if degraded {
+		t.Fatalf("Unexpected degraded etcd: %q", lines)
+	}
+	want := []string{
+		"etcd database: 100Mi of 2048Mi quota (5%), 80Mi in use",
+		"etcd revision: 1234, compacted",
+	}
+	if !reflect.DeepEqual(lines[:2], want) {
+		t.Fatalf("Lines do not match: got %q; want %q", lines[:2], want)
+	}
+	if !strings.HasPrefix(lines[2], "etcd objects: "+catalogEtcdResources()[0]+" 12, ") || len(lines) != 3 {
+		t.Fatalf("Objects do not match: got %q", lines[2:])
+	}
+
+	u = &etcdUsage{DBSize: 1700 << 20, DBSizeInUse: 600 << 20, Quota: 2 << 30}
+	lines, degraded = u.lines(defaultNearLimit)
+	if degraded || len(lines) != 5 ||
+		!strings.Contains(lines[3], "near its quota") || !strings.Contains(lines[4], "1100Mi of the etcd database is free") {
+		t.Fatalf("Warnings do not match: got %q", lines)
+	}
+
+	u = &etcdUsage{DBSize: 2 << 30, Quota: 2 << 30, Alarms: []string{"NOSPACE"}}
+	lines, degraded = u.lines(defaultNearLimit)
+	if !degraded || !strings.Contains(lines[len(lines)-1], "the api server is read-only") {
+		t.Fatalf("NOSPACE alarm does not match: got %q, degraded %v", lines, degraded)
+	}
+}
+
+// TestGetEtcdUsage tests that the usage is read from the etcd member with
+// etcdctl.
+func TestGetEtcdUsage(t *testing.T) {
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		cmd := strings.Join(args, " ")
+		switch {
+		case strings.HasPrefix(cmd, "get pods"):
+			return execx.Response{Stdout: `{"items":[{"metadata":{"name":"etcd-0"},` +
+				`"spec":{"containers":[{"name":"etcd","command":["etcd","--quota-backend-bytes=1073741824"]}]}}]}`}
+		case strings.HasSuffix(cmd, "endpoint status -w json"):
+			return execx.Response{Stdout: `[{"Endpoint":"127.0.0.1:2379","Status":{"header":{"revision":77},"dbSize":2097152}}]`}
+		case strings.HasSuffix(cmd, "alarm list"):
+			return execx.Response{Stdout: "memberID:1 alarm:NOSPACE\n"}
+		case strings.HasSuffix(cmd, "--rev 1"):
+			return execx.Response{Stderr: "Error: etcdserver: mvcc: required revision has been compacted", ExitCode: 1}
+		case strings.Contains(cmd, catalogEtcdPrefix+"serviceinstances/"):
+			return execx.Response{Stdout: `{"count":3}`}
+		case strings.Contains(cmd, "--keys-only"):
+			return execx.Response{Stdout: `{"header":{}}`}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	u, err := getEtcdUsage("catalog")
+	if err != nil {
+		t.Fatalf("Unexpected error getting the etcd usage: %v", err)
+	}
+	if u.Pod != "etcd-0" || u.Quota != 1<<30 || u.DBSize != 2<<20 || u.Revision != 77 || !u.Compacted {
+		t.Fatalf("Usage does not match: got %+v", u)
+	}
+	if !reflect.DeepEqual(u.Alarms, []string{"NOSPACE"}) {
+		t.Fatalf("Alarms do not match: got %q; want [NOSPACE]", u.Alarms)
+	}
+	var total int64
+	for _, n := range u.Objects {
+		total += n
+	}
+	if len(u.Objects) != len(catalogEtcdResources()) || total != 3 {
+		t.Fatalf("Objects do not match: got %v", u.Objects)
+	}
+}
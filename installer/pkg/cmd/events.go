@@ -0,0 +1,198 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/color"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+)
+
+// kubeEvent is the subset of a Kubernetes Event surfaced to users.
+type kubeEvent struct {
+	InvolvedObject struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Namespace  string `json:"namespace"`
+		Name       string `json:"name"`
+	} `json:"involvedObject"`
+	Type          string    `json:"type"`
+	Reason        string    `json:"reason"`
+	Message       string    `json:"message"`
+	Count         int32     `json:"count"`
+	LastTimestamp time.Time `json:"lastTimestamp"`
+	EventTime     time.Time `json:"eventTime"`
+}
+
+// time returns when the event last occurred.
+func (e *kubeEvent) time() time.Time {
+	if e.LastTimestamp.IsZero() {
+		return e.EventTime
+	}
+	return e.LastTimestamp
+}
+
+// String formats e on a single line, e.g.
+// "Pod/apiserver-x: FailedScheduling: 0/3 nodes are available".
+func (e *kubeEvent) String() string {
+	s := fmt.Sprintf("%s/%s: %s: %s", e.InvolvedObject.Kind, e.InvolvedObject.Name, e.Reason, strings.TrimSpace(e.Message))
+	if e.Count > 1 {
+		s += fmt.Sprintf(" (x%d)", e.Count)
+	}
+	return s
+}
+
+// UnmarshalJSON tolerates the null and empty timestamps of events.
+func (e *kubeEvent) UnmarshalJSON(b []byte) error {
+	type plain kubeEvent
+	var raw struct {
+		plain
+		LastTimestamp *string `json:"lastTimestamp"`
+		EventTime     *string `json:"eventTime"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*e = kubeEvent(raw.plain)
+	for _, t := range []struct {
+		s   *string
+		dst *time.Time
+	}{
+		{raw.LastTimestamp, &e.LastTimestamp},
+		{raw.EventTime, &e.EventTime},
+	} {
+		if t.s == nil || *t.s == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, *t.s)
+		if err != nil {
+			return err
+		}
+		*t.dst = parsed
+	}
+	return nil
+}
+
+// streamWarningEvents decodes the events in the JSON stream r, as written by
+// kubectl get events --watch -o json, and calls print with each Warning
+// event that occurred at or after since, e.g. FailedScheduling or the back
+// offs of ImagePullBackOff and CrashLoopBackOff. Repeats of an event are
+// reported once.
+func streamWarningEvents(r io.Reader, since time.Time, print func(e *kubeEvent)) error {
+	seen := make(map[string]bool)
+	d := json.NewDecoder(r)
+	for {
+		var e kubeEvent
+		if err := d.Decode(&e); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if e.Type != "Warning" || e.time().Before(since) {
+			continue
+		}
+		key := e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name + "/" + e.Reason + "/" + e.Message
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		print(&e)
+	}
+}
+
+// watchWarningEvents prints the Warning events of namespace ns from now on
+// until the returned function is called.
+func watchWarningEvents(ns string) (stop func(), err error) {
+	cmd := kubectlCommand("get", "events", "--namespace", ns, "--watch", "-o", "json")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error watching the events of namespace %s: %v", ns, err)
+	}
+
+	// Events are recorded with a precision of seconds.
+	since := time.Now().Truncate(time.Second)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		streamWarningEvents(stdout, since, func(e *kubeEvent) {
+			fmt.Printf("  event: %s\n", e)
+		})
+	}()
+
+	return func() {
+		execx.Kill(cmd)
+		wg.Wait()
+		cmd.Wait()
+	}, nil
+}
+
+// waitForCatalogReady waits up to timeout for the api server and controller
+// manager deployments in namespace ns to roll out and, with apiService, for
+// the APIService to become available.
+func waitForCatalogReady(ns string, timeout time.Duration, apiService bool) error {
+	deadline := time.Now().Add(timeout)
+	for _, d := range []string{"apiserver", "controller-manager"} {
+		fmt.Println(color.Yellow("waiting for deployment " + d + " to be ready"))
+		remaining := time.Until(deadline).Round(time.Second)
+		if remaining <= 0 {
+			return messages.Errorf(messages.NotReady, "deployment "+d, timeout, "timed out")
+		}
+		output, err := kubectlCommand("rollout", "status", "deployment/"+d,
+			"--namespace", ns, "--timeout="+remaining.String()).CombinedOutput()
+		if err != nil {
+			return messages.Errorf(messages.NotReady, "deployment "+d, timeout, strings.TrimSpace(string(output)))
+		}
+	}
+
+	if !apiService {
+		return nil
+	}
+	// A caBundle not matching the certificate never becomes available.
+	if err := checkCABundle(ns); err != nil {
+		return err
+	}
+	fmt.Println(color.Yellow("waiting for APIService " + scAPIService + " to be available"))
+	for {
+		info, err := getAPIServiceInfo()
+		if err != nil {
+			return err
+		}
+		reason := "APIService not found"
+		if info != nil {
+			reason = apiServiceUnavailable(info)
+		}
+		if reason == "" {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return messages.Errorf(messages.NotReady, "APIService "+scAPIService, timeout, reason)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStreamWarningEvents tests that only new Warning events are reported,
+// once each.
+func TestStreamWarningEvents(t *testing.T) {
+	stream := `{"involvedObject":{"kind":"Pod","name":"apiserver-1"},"type":"Warning","reason":"FailedScheduling","message":"0/3 nodes are available","lastTimestamp":"2018-06-01T10:00:05Z"}
+{"involvedObject":{"kind":"Pod","name":"apiserver-1"},"type":"Normal","reason":"Scheduled","message":"assigned","lastTimestamp":"2018-06-01T10:00:06Z"}
+{"involvedObject":{"kind":"Pod","name":"old"},"type":"Warning","reason":"BackOff","message":"old","lastTimestamp":"2018-06-01T09:00:00Z"}
+{"involvedObject":{"kind":"Pod","name":"apiserver-1"},"type":"Warning","reason":"FailedScheduling","message":"0/3 nodes are available","lastTimestamp":"2018-06-01T10:00:10Z","count":2}
+{"involvedObject":{"kind":"Pod","name":"cm-1"},"type":"Warning","reason":"Failed","message":"Error: ImagePullBackOff","lastTimestamp":null,"eventTime":"2018-06-01T10:00:07.000000Z","count":3}
+`
+	since := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	var got []string
+	err := streamWarningEvents(strings.NewReader(stream), since, func(e *kubeEvent) {
+		got = append(got, e.String())
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error streaming events: %v", err)
+	}
+	want := []string{
+		"Pod/apiserver-1: FailedScheduling: 0/3 nodes are available",
+		"Pod/cm-1: Failed: Error: ImagePullBackOff (x3)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Events do not match: got %q; want %q", got, want)
+	}
+}
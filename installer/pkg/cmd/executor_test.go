@@ -0,0 +1,165 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+)
+
+func TestMain(m *testing.M) {
+	execx.StubMain()
+	os.Exit(m.Run())
+}
+
+// stubExecutor makes the commands run by sc respond with respond, and
+// returns the stub and a function restoring the executor.
+func stubExecutor(respond func(name string, args []string) execx.Response) (*execx.Stub, func()) {
+	s := &execx.Stub{Respond: respond}
+	old := execx.DefaultExecutor
+	execx.DefaultExecutor = s
+	return s, func() { execx.DefaultExecutor = old }
+}
+
+// tempWorkspaces makes sc create its workspaces under a temporary dir, and
+// returns a function removing it and restoring the workspace manager.
+func tempWorkspaces(t *testing.T) func() {
+	root, err := ioutil.TempDir("", "sc-workspaces")
+	if err != nil {
+		t.Fatalf("Unexpected error creating the workspace root: %v", err)
+	}
+	old := workspaces
+	workspaces = workspace.NewManager(root, "")
+	return func() {
+		workspaces = old
+		os.RemoveAll(root)
+	}
+}
+
+// availableAPIService is the APIService of a served catalog API.
+const availableAPIService = `{"status":{"conditions":[{"type":"Available","status":"True"}]}}`
+
+// TestForceDeleteCatalogResources tests that the fast uninstall stops the
+// controller manager, then deletes the resources and removes the catalog
+// finalizer, and only it, from those holding it.
+func TestForceDeleteCatalogResources(t *testing.T) {
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch {
+		case args[0] == "get" && args[1] == "apiservice":
+			return execx.Response{Stdout: availableAPIService}
+		case args[0] == "get" && args[1] == "serviceinstances.servicecatalog.k8s.io":
+			return execx.Response{Stdout: `{"items":[{"metadata":{"namespace":"team","name":"db","finalizers":["other/finalizer","` + catalogFinalizer + `"]}},` +
+				`{"metadata":{"namespace":"team","name":"done"}}]}`}
+		case args[0] == "get":
+			return execx.Response{Stdout: `{"items":[]}`}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	if err := forceDeleteCatalogResources("catalog"); err != nil {
+		t.Fatalf("Unexpected error deleting catalog resources: %v", err)
+	}
+
+	var calls []string
+	for _, c := range s.Calls() {
+		if c.Name != KubectlBinaryName {
+			t.Fatalf("Unexpected command: %v", c)
+		}
+		calls = append(calls, strings.Join(c.Args[:2], " "))
+	}
+	if got, want := calls[0], "scale deployment"; got != want {
+		t.Fatalf("First command does not match: got %q; want %q", got, want)
+	}
+	resources := len(namespacedCatalogResources) + len(clusterCatalogResources)
+	if got, want := len(calls), 2+2*resources+1; got != want {
+		t.Fatalf("Number of commands does not match: got %d; want %d (%v)", got, want, calls)
+	}
+	patch := strings.Join(kubectlCalls(s, "patch"), " ")
+	want := `patch serviceinstances.servicecatalog.k8s.io db --namespace team --type json -p ` +
+		`[{"op":"test","path":"/metadata/finalizers/1","value":"kubernetes-incubator/service-catalog"},{"op":"remove","path":"/metadata/finalizers/1"}]`
+	if patch != want {
+		t.Fatalf("Patch does not match:\ngot  %s\nwant %s", patch, want)
+	}
+}
+
+// TestForceDeleteCatalogResourcesUnavailable tests that the fast uninstall
+// has nothing to delete when the catalog API is not served.
+func TestForceDeleteCatalogResourcesUnavailable(t *testing.T) {
+	for _, apiService := range []execx.Response{
+		{Stderr: `Error from server (NotFound): apiservices.apiregistration.k8s.io "v1beta1.servicecatalog.k8s.io" not found`, ExitCode: 1},
+		{Stdout: `{"status":{"conditions":[{"type":"Available","status":"False","reason":"ServiceNotFound"}]}}`},
+	} {
+		apiService := apiService
+		s, restore := stubExecutor(func(name string, args []string) execx.Response {
+			switch args[0] {
+			case "get":
+				return apiService
+			case "scale":
+				return execx.Response{}
+			}
+			return execx.Response{Stderr: "the server is currently unable to handle the request", ExitCode: 1}
+		})
+		err := forceDeleteCatalogResources("catalog")
+		restore()
+		if err != nil {
+			t.Fatalf("Unexpected error deleting catalog resources: %v", err)
+		}
+		if calls := kubectlCalls(s, "delete", "patch"); len(calls) > 0 {
+			t.Fatalf("Unexpected calls: %v", calls)
+		}
+	}
+}
+
+// TestForceDeleteCatalogResourcesError tests that the fast uninstall stops
+// at the first failed deletion, with the output of kubectl.
+func TestForceDeleteCatalogResourcesError(t *testing.T) {
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch {
+		case args[0] == "get" && args[1] == "apiservice":
+			return execx.Response{Stdout: availableAPIService}
+		case args[0] == "delete":
+			return execx.Response{Stderr: "forbidden", ExitCode: 1}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	err := forceDeleteCatalogResources("catalog")
+	if err == nil || !strings.HasSuffix(err.Error(), ": forbidden") {
+		t.Fatalf("Error does not match: got %v; want the kubectl output", err)
+	}
+}
+
+// TestCheckCommandsStubbed tests that the missing commands are listed.
+func TestCheckCommandsStubbed(t *testing.T) {
+	s, restore := stubExecutor(nil)
+	defer restore()
+	s.Missing = []string{CfsslBinaryName}
+
+	if err := checkCommands(KubectlBinaryName, CfssljsonBinaryName); err != nil {
+		t.Fatalf("Unexpected error checking present commands: %v", err)
+	}
+	if err := checkCommands(KubectlBinaryName, CfsslBinaryName); err == nil || !strings.Contains(err.Error(), CfsslBinaryName) {
+		t.Fatalf("Error does not match: got %v; want %s missing", err, CfsslBinaryName)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+// ExitError is an error making sc exit with Code instead of 1, e.g. to
+// tell apart the results of sc status --exit-code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// ExitCode returns the exit code of sc for the error err returned by a
+// command.
+func ExitCode(err error) int {
+	if e, ok := err.(*ExitError); ok {
+		return e.Code
+	}
+	return 1
+}
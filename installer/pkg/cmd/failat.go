@@ -0,0 +1,111 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// Phases of the install at which --fail-at injects a failure, to test
+// retries, reruns and rollbacks deterministically.
+const (
+	// phaseGenerate fails after the manifests are generated, before
+	// anything is changed.
+	phaseGenerate = "generate"
+
+	// phaseAfterRBAC fails after the roles and bindings are created.
+	phaseAfterRBAC = "after-rbac"
+
+	// phaseBeforeAPIRegistration fails after everything but the
+	// APIService is created.
+	phaseBeforeAPIRegistration = "before-api-registration"
+
+	// phaseAfterDeploy fails after all the objects are created, before
+	// the pods are restarted and waited for.
+	phaseAfterDeploy = "after-deploy"
+)
+
+var failPhases = []string{phaseGenerate, phaseAfterRBAC, phaseBeforeAPIRegistration, phaseAfterDeploy}
+
+// rbacKinds are the kinds created in the after-rbac phase.
+var rbacKinds = map[string]bool{
+	"ClusterRole":        true,
+	"ClusterRoleBinding": true,
+	"Role":               true,
+	"RoleBinding":        true,
+}
+
+// validFailPhase returns whether phase is empty or a phase of failPhases.
+func validFailPhase(phase string) bool {
+	if phase == "" {
+		return true
+	}
+	for _, p := range failPhases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// injectFailure returns the injected failure of phase if at is phase.
+func injectFailure(at, phase string) error {
+	if at != phase {
+		return nil
+	}
+	return injectedFailure(phase)
+}
+
+func injectedFailure(phase string) error {
+	return fmt.Errorf("injected failure at phase %s (--fail-at)", phase)
+}
+
+// failureIndex returns the index of the object of objs, in creation order,
+// before which the deploy fails for phase at, len(objs) to fail after the
+// last object, or -1 if the deploy does not fail.
+func failureIndex(objs []*manifest.Object, at string) int {
+	switch at {
+	case phaseAfterRBAC:
+		// Without RBAC objects, e.g. with --skip-rbac, the phase ends
+		// right away.
+		i := 0
+		for j, o := range objs {
+			if rbacKinds[o.Kind] {
+				i = j + 1
+			}
+		}
+		return i
+	case phaseBeforeAPIRegistration:
+		for i, o := range objs {
+			if o.Kind == "APIService" {
+				return i
+			}
+		}
+		return len(objs)
+	case phaseAfterDeploy:
+		return len(objs)
+	}
+	return -1
+}
+
+// failPhasesUsage describes the phases for the help of --fail-at.
+func failPhasesUsage() string {
+	return strings.Join(failPhases, ", ")
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestFailureIndex tests that the deploy fails before the first object
+// following the phase.
+func TestFailureIndex(t *testing.T) {
+	objs := []*manifest.Object{
+		{Kind: "Namespace"},
+		{Kind: "ServiceAccount"},
+		{Kind: "ClusterRole"},
+		{Kind: "RoleBinding"},
+		{Kind: "Secret"},
+		{Kind: "Deployment"},
+		{Kind: "APIService"},
+		{Kind: "EtcdCluster"},
+	}
+	for _, tc := range []struct {
+		at   string
+		objs []*manifest.Object
+		want int
+	}{
+		{"", objs, -1},
+		{phaseGenerate, objs, -1},
+		{phaseAfterRBAC, objs, 4},
+		{phaseAfterRBAC, objs[4:], 0},
+		{phaseBeforeAPIRegistration, objs, 6},
+		{phaseBeforeAPIRegistration, objs[:6], 6},
+		{phaseAfterDeploy, objs, 8},
+	} {
+		if got := failureIndex(tc.objs, tc.at); got != tc.want {
+			t.Fatalf("Failure index of %q does not match: got %d; want %d", tc.at, got, tc.want)
+		}
+	}
+}
+
+// TestValidFailPhase tests that only the known phases are accepted.
+func TestValidFailPhase(t *testing.T) {
+	for _, p := range append([]string{""}, failPhases...) {
+		if !validFailPhase(p) {
+			t.Fatalf("Phase %q must be valid", p)
+		}
+	}
+	if validFailPhase("after-everything") {
+		t.Fatalf("Phase after-everything must be invalid")
+	}
+}
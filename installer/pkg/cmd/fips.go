@@ -0,0 +1,142 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// fipsImageSuffix is the tag suffix of the FIPS variants of the Service
+// Catalog images, which are built with BoringCrypto.
+const fipsImageSuffix = "-fips"
+
+// fipsKeySizes lists the key sizes allowed for each key algorithm in FIPS
+// mode, the ones crypto/tls/fipsonly accepts. The first size is the default.
+var fipsKeySizes = map[string][]int{
+	"rsa":   {2048, 3072},
+	"ecdsa": {256, 384},
+}
+
+// fipsSignatureAlgorithms are the certificate signature algorithms allowed
+// in FIPS mode.
+var fipsSignatureAlgorithms = map[x509.SignatureAlgorithm]bool{
+	x509.SHA256WithRSA:    true,
+	x509.SHA384WithRSA:    true,
+	x509.SHA512WithRSA:    true,
+	x509.SHA256WithRSAPSS: true,
+	x509.SHA384WithRSAPSS: true,
+	x509.SHA512WithRSAPSS: true,
+	x509.ECDSAWithSHA256:  true,
+	x509.ECDSAWithSHA384:  true,
+	x509.ECDSAWithSHA512:  true,
+}
+
+// keySizes returns the key sizes allowed for each key algorithm.
+func keySizes(ic *InstallConfig) map[string][]int {
+	if ic.FIPS {
+		return fipsKeySizes
+	}
+	return supportedKeySizes
+}
+
+// fipsImage returns the FIPS variant of image.
+func fipsImage(image string) string {
+	return image + fipsImageSuffix
+}
+
+// checkFIPSCert returns an error if cert uses a key or signature algorithm
+// that isn't FIPS-approved.
+func checkFIPSCert(cert *x509.Certificate) error {
+	if !fipsSignatureAlgorithms[cert.SignatureAlgorithm] {
+		return fmt.Errorf("signature algorithm %v is not FIPS-approved", cert.SignatureAlgorithm)
+	}
+
+	var algo string
+	var size int
+	switch k := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		algo, size = "rsa", k.N.BitLen()
+	case *ecdsa.PublicKey:
+		algo, size = "ecdsa", k.Curve.Params().BitSize
+	default:
+		return fmt.Errorf("public key type %T is not FIPS-approved", cert.PublicKey)
+	}
+	for _, s := range fipsKeySizes[algo] {
+		if s == size {
+			return nil
+		}
+	}
+	return fmt.Errorf("%d bit %s key is not FIPS-approved, must be one of %v", size, algo, fipsKeySizes[algo])
+}
+
+// checkFIPSCertFile checks the PEM encoded certificate in path with
+// checkFIPSCert.
+func checkFIPSCertFile(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return fmt.Errorf("no PEM certificate found in %s", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("error parsing certificate %s: %v", path, err)
+	}
+	return checkFIPSCert(cert)
+}
+
+// checkFIPSImages returns an error if the deployed Service Catalog
+// components in namespace ns don't run the FIPS variants of their images.
+func checkFIPSImages(ns string) error {
+	output, err := kubectlCommand("get", "deployment", "apiserver", "controller-manager",
+		"--namespace", ns, "-o",
+		`jsonpath={range .items[*]}{.metadata.name}{range .spec.template.spec.containers[*]}{" "}{.image}{end}{"\n"}{end}`).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error getting the service catalog deployments: %s", strings.TrimSpace(string(output)))
+	}
+	if bad := nonFIPSImages(string(output)); len(bad) > 0 {
+		return fmt.Errorf("deployments run images that are not FIPS variants: %s", strings.Join(bad, ", "))
+	}
+	return nil
+}
+
+// nonFIPSImages returns the deployment=image pairs of the images without
+// the FIPS tag suffix in output, which lists a deployment followed by its
+// images per line.
+func nonFIPSImages(output string) []string {
+	var bad []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, image := range fields[1:] {
+			if !strings.HasSuffix(image, fipsImageSuffix) {
+				bad = append(bad, fields[0]+"="+image)
+			}
+		}
+	}
+	return bad
+}
@@ -0,0 +1,27 @@
+//go:build boringcrypto
+// +build boringcrypto
+
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+// Restrict the TLS connections of sc itself, e.g. to the Google Cloud APIs,
+// to FIPS-approved settings.
+import _ "crypto/tls/fipsonly"
+
+// boringCrypto is true if sc is built with BoringCrypto.
+const boringCrypto = true
@@ -0,0 +1,91 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCheckFIPSCert tests that certificates with keys outside of the FIPS
+// key sizes are rejected.
+func TestCheckFIPSCert(t *testing.T) {
+	_, _, cert, _ := testCert(t, []string{"a"}, time.Now().Add(time.Hour), nil, nil)
+	if err := checkFIPSCert(cert); err != nil {
+		t.Fatalf("Unexpected error checking a P-256 certificate: %v", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error generating key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unexpected error creating certificate: %v", err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing certificate: %v", err)
+	}
+	if err := checkFIPSCert(cert); err == nil || !strings.Contains(err.Error(), "521 bit ecdsa key") {
+		t.Fatalf("Error does not match: got %v; want a 521 bit ecdsa key error", err)
+	}
+}
+
+// TestKeySpecFIPS tests that --fips restricts the key sizes.
+func TestKeySpecFIPS(t *testing.T) {
+	ic := &InstallConfig{KeyAlgorithm: "rsa", KeySize: 4096}
+	if _, _, err := keySpec(ic); err != nil {
+		t.Fatalf("Unexpected error for 4096 bit rsa keys: %v", err)
+	}
+	ic.FIPS = true
+	if _, _, err := keySpec(ic); err == nil {
+		t.Fatalf("Expected an error for 4096 bit rsa keys with --fips")
+	}
+
+	ic.KeyAlgorithm, ic.KeySize = "ecdsa", 0
+	algo, size, err := keySpec(ic)
+	if err != nil || algo != "ecdsa" || size != 256 {
+		t.Fatalf("Key spec does not match: got %s %d %v; want ecdsa 256", algo, size, err)
+	}
+}
+
+// TestNonFIPSImages tests that images without the FIPS tag suffix are
+// reported with their deployment.
+func TestNonFIPSImages(t *testing.T) {
+	output := "apiserver gcr.io/gcp-services/service-catalog:v0.1.11-fips\n" +
+		"controller-manager gcr.io/gcp-services/service-catalog:v0.1.11 sidecar:1.0-fips\n"
+	got := nonFIPSImages(output)
+	want := []string{"controller-manager=gcr.io/gcp-services/service-catalog:v0.1.11"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Non FIPS images do not match: got %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// EnvPrefix is the prefix of the environment variables setting flags,
+	// e.g. SC_INSTALLER_ETCD_CLUSTER_SIZE sets --etcd-cluster-size.
+	EnvPrefix = "SC_INSTALLER_"
+
+	// ConfigFlagName is the name of the flag selecting the config file.
+	ConfigFlagName = "config"
+)
+
+// EnvVarName returns the environment variable setting flag name.
+func EnvVarName(name string) string {
+	r := strings.NewReplacer("-", "_", ".", "_")
+	return EnvPrefix + strings.ToUpper(r.Replace(name))
+}
+
+// SetFlagsFromEnvAndConfig sets the flags of c that are not set on the
+// command line from SC_INSTALLER_* environment variables and then from the
+// config file, if any. The precedence is thus flags, environment, config
+// file and defaults.
+//
+// The config file is a YAML map of flag names to values, lists are joined
+// with commas. Flags not defined by c are ignored, so that one file can
+// configure several commands.
+func SetFlagsFromEnvAndConfig(c *cobra.Command) error {
+	flags := c.Flags()
+
+	var setErr error
+	flags.VisitAll(func(f *pflag.Flag) {
+		if setErr != nil || f.Changed {
+			return
+		}
+		if v, ok := os.LookupEnv(EnvVarName(f.Name)); ok {
+			if err := flags.Set(f.Name, v); err != nil {
+				setErr = fmt.Errorf("invalid value %q of %s: %v", v, EnvVarName(f.Name), err)
+			}
+		}
+	})
+	if setErr != nil {
+		return setErr
+	}
+
+	path, err := flags.GetString(ConfigFlagName)
+	if err != nil || path == "" {
+		return nil
+	}
+	config, err := readFlagsConfig(path)
+	if err != nil {
+		return err
+	}
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		if setErr != nil || f.Changed {
+			return
+		}
+		if v, ok := config[f.Name]; ok {
+			if err := flags.Set(f.Name, v); err != nil {
+				setErr = fmt.Errorf("invalid value %q of %s in %s: %v", v, f.Name, path, err)
+			}
+		}
+	})
+	return setErr
+}
+
+// readFlagsConfig reads the config file at path into a map of flag names to
+// flag values.
+func readFlagsConfig(path string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %v", err)
+	}
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+
+	config := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch v := v.(type) {
+		case []interface{}:
+			var items []string
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			config[k] = strings.Join(items, ",")
+		case map[string]interface{}:
+			return nil, fmt.Errorf("error parsing config file %s: value of %s must not be a map", path, k)
+		default:
+			config[k] = fmt.Sprint(v)
+		}
+	}
+	return config, nil
+}
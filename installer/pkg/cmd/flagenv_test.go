@@ -0,0 +1,70 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestSetFlagsFromEnvAndConfig tests that command line flags take precedence
+// over environment variables, which take precedence over the config file.
+func TestSetFlagsFromEnvAndConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flagenv")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := filepath.Join(dir, "config.yaml")
+	err = ioutil.WriteFile(config, []byte("version: 0.1.9\netcd-cluster-size: 5\nprofile: production\nunknown-flag: x\n"), 0644)
+	if err != nil {
+		t.Fatalf("Unexpected error writing config: %v", err)
+	}
+
+	os.Setenv("SC_INSTALLER_ETCD_CLUSTER_SIZE", "1")
+	os.Setenv("SC_INSTALLER_KEY_ALGORITHM", "ecdsa")
+	defer os.Unsetenv("SC_INSTALLER_ETCD_CLUSTER_SIZE")
+	defer os.Unsetenv("SC_INSTALLER_KEY_ALGORITHM")
+
+	c := &cobra.Command{}
+	c.Flags().String(ConfigFlagName, "", "")
+	version := c.Flags().String("version", "", "")
+	size := c.Flags().Int32("etcd-cluster-size", 3, "")
+	algo := c.Flags().String("key-algorithm", "rsa", "")
+	profile := c.Flags().String("profile", "default", "")
+	if err := c.Flags().Parse([]string{"--config", config, "--key-algorithm", "rsa"}); err != nil {
+		t.Fatalf("Unexpected error parsing flags: %v", err)
+	}
+
+	if err := SetFlagsFromEnvAndConfig(c); err != nil {
+		t.Fatalf("Unexpected error from SetFlagsFromEnvAndConfig: %v", err)
+	}
+	if *algo != "rsa" {
+		t.Errorf("Flag not taking precedence: got %q; want %q", *algo, "rsa")
+	}
+	if *size != 1 {
+		t.Errorf("Environment not taking precedence: got %d; want %d", *size, 1)
+	}
+	if *version != "0.1.9" || *profile != "production" {
+		t.Errorf("Config file not applied: got %q, %q; want %q, %q", *version, *profile, "0.1.9", "production")
+	}
+}
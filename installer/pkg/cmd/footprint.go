@@ -0,0 +1,189 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+func NewFootprintCmd() *cobra.Command {
+	ic := newInstallConfig()
+	c := &cobra.Command{
+		Use:   "footprint",
+		Short: "lists the objects an install creates",
+		Long: `lists the cluster-scoped and the namespaced objects an install with the
+same flags creates, with the permissions granted by its roles and bindings, so
+that the blast radius can be reviewed before granting the installer
+credentials. Nothing is created.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyProfile(ic, cmd.Flags()); err != nil {
+				return err
+			}
+			if err := applyNameAffixes(ic); err != nil {
+				return err
+			}
+			if err := printInstallFootprint(os.Stdout, ic); err != nil {
+				messages.Println(messages.FootprintFailed)
+				return err
+			}
+			return nil
+		},
+	}
+	addInstallFlags(c, ic)
+	return c
+}
+
+func printInstallFootprint(w io.Writer, ic *InstallConfig) (err error) {
+	if err := ic.Validate(); err != nil {
+		return err
+	}
+	detectCapabilities(ic)
+	ws, err := createWorkspace(ic.Namespace, "footprint", workspace.Options{Cleanup: workspace.Always})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+	dir := ws.Dir
+
+	// The certificates don't change the objects created.
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		return fmt.Errorf("error generating YAML files: %v", err)
+	}
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		return err
+	}
+	printFootprint(w, objs)
+	return nil
+}
+
+// printFootprint writes objs to w, the cluster-scoped ones first, then the
+// namespaced ones grouped by namespace.
+func printFootprint(w io.Writer, objs []*manifest.Object) {
+	var cluster []*manifest.Object
+	namespaced := make(map[string][]*manifest.Object)
+	for _, o := range objs {
+		if o.ClusterScoped() {
+			cluster = append(cluster, o)
+			continue
+		}
+		ns := o.Namespace
+		if ns == "" {
+			ns = "<kubectl default namespace>"
+		}
+		namespaced[ns] = append(namespaced[ns], o)
+	}
+
+	printObjects := func(title string, objs []*manifest.Object) {
+		fmt.Fprintf(w, "%s (%d):\n", title, len(objs))
+		for _, o := range objs {
+			fmt.Fprintf(w, "  %s\n", o)
+			for _, d := range permissionDetails(o) {
+				fmt.Fprintf(w, "      %s\n", d)
+			}
+		}
+	}
+	printObjects("Cluster-scoped objects", cluster)
+
+	var namespaces []string
+	for ns := range namespaced {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		fmt.Fprintln(w)
+		printObjects("Objects in namespace "+ns, namespaced[ns])
+	}
+}
+
+// rbacObject is the subset of roles and role bindings described by
+// permissionDetails.
+type rbacObject struct {
+	Rules []struct {
+		APIGroups       []string `json:"apiGroups"`
+		Resources       []string `json:"resources"`
+		ResourceNames   []string `json:"resourceNames"`
+		NonResourceURLs []string `json:"nonResourceURLs"`
+		Verbs           []string `json:"verbs"`
+	} `json:"rules"`
+	RoleRef struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"roleRef"`
+	Subjects []struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"subjects"`
+}
+
+// permissionDetails describes the rules of roles and what role bindings
+// grant to whom. It returns nil for other objects.
+func permissionDetails(o *manifest.Object) []string {
+	switch o.Kind {
+	case "ClusterRole", "Role", "ClusterRoleBinding", "RoleBinding":
+	default:
+		return nil
+	}
+	var r rbacObject
+	if err := json.Unmarshal(o.JSON, &r); err != nil {
+		return []string{fmt.Sprintf("error reading %s: %v", o, err)}
+	}
+
+	var details []string
+	for _, rule := range r.Rules {
+		var what string
+		if len(rule.NonResourceURLs) > 0 {
+			what = strings.Join(rule.NonResourceURLs, ",")
+		} else {
+			groups := make([]string, len(rule.APIGroups))
+			for i, g := range rule.APIGroups {
+				if g == "" {
+					g = "core"
+				}
+				groups[i] = g
+			}
+			what = strings.Join(rule.Resources, ",") + " (" + strings.Join(groups, ",") + ")"
+			if len(rule.ResourceNames) > 0 {
+				what += " named " + strings.Join(rule.ResourceNames, ",")
+			}
+		}
+		details = append(details, strings.Join(rule.Verbs, ",")+" on "+what)
+	}
+	if r.RoleRef.Name != "" {
+		var subjects []string
+		for _, s := range r.Subjects {
+			name := s.Name
+			if s.Namespace != "" {
+				name = s.Namespace + "/" + name
+			}
+			subjects = append(subjects, s.Kind+" "+name)
+		}
+		details = append(details, fmt.Sprintf("grants %s/%s to %s", r.RoleRef.Kind, r.RoleRef.Name, strings.Join(subjects, ", ")))
+	}
+	return details
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestPrintFootprint tests that cluster-scoped objects are listed apart from
+// the namespaced ones, with the permissions of roles and bindings.
+func TestPrintFootprint(t *testing.T) {
+	objs, err := manifest.Parse("rbac.yaml", []byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+rules:
+- apiGroups: [""]
+  resources: ["namespaces"]
+  verbs: ["get", "list"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: reader
+  namespace: kube-system
+roleRef:
+  kind: ClusterRole
+  name: reader
+subjects:
+- kind: ServiceAccount
+  name: apiserver
+  namespace: service-catalog
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: apiserver
+  namespace: service-catalog
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing manifest: %v", err)
+	}
+
+	var b bytes.Buffer
+	printFootprint(&b, objs)
+	want := `Cluster-scoped objects (1):
+  ClusterRole/reader
+      get,list on namespaces (core)
+
+Objects in namespace kube-system (1):
+  RoleBinding/reader
+      grants ClusterRole/reader to ServiceAccount service-catalog/apiserver
+
+Objects in namespace service-catalog (1):
+  ServiceAccount/apiserver
+`
+	if got := b.String(); got != want {
+		t.Fatalf("Footprint does not match: got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// gcpServiceAPIs maps short names to the Google APIs commonly needed by the
+// services the GCP broker provisions.
+var gcpServiceAPIs = map[string]string{
+	"servicebroker":     "servicebroker.googleapis.com",
+	"deploymentmanager": "deploymentmanager.googleapis.com",
+	"bigquery":          "bigquery-json.googleapis.com",
+	"bigtable":          "bigtableadmin.googleapis.com",
+	"ml":                "ml.googleapis.com",
+	"pubsub":            "pubsub.googleapis.com",
+	"spanner":           "spanner.googleapis.com",
+	"sqladmin":          "sqladmin.googleapis.com",
+	"storage":           "storage-api.googleapis.com",
+}
+
+func NewEnableGCPAPIsCmd() *cobra.Command {
+	var apis []string
+	c := &cobra.Command{
+		Use:   "enable-gcp-apis",
+		Short: "enables the Google APIs needed by the GCP broker services",
+		Long: `enables the Google APIs commonly needed by the services the GCP
+broker provisions in the configured project. APIs that are already enabled
+are skipped, so the command can be run repeatedly.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selected, err := selectGCPAPIs(apis)
+			if err != nil {
+				return err
+			}
+			if err := enableGCPAPIs(selected); err != nil {
+				messages.Println(messages.EnableAPIsFailed)
+				return err
+			}
+			return nil
+		},
+	}
+	c.Flags().StringSliceVar(&apis, "apis", nil, "APIs to enable, by short name ("+strings.Join(gcpServiceAPINames(), ", ")+") or full name. Defaults to all of them")
+	addGCPContextFlags(c)
+	return c
+}
+
+func gcpServiceAPINames() []string {
+	var names []string
+	for n := range gcpServiceAPIs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectGCPAPIs returns the full names of apis, given by short or full
+// name, or all the gcpServiceAPIs if apis is empty.
+func selectGCPAPIs(apis []string) ([]string, error) {
+	if len(apis) == 0 {
+		apis = gcpServiceAPINames()
+	}
+
+	seen := make(map[string]bool)
+	var selected []string
+	for _, a := range apis {
+		a = strings.TrimSpace(a)
+		name, ok := gcpServiceAPIs[a]
+		if !ok {
+			if !strings.HasSuffix(a, ".googleapis.com") {
+				return nil, fmt.Errorf("unknown API %q, use one of %s or a full API name", a, strings.Join(gcpServiceAPINames(), ", "))
+			}
+			name = a
+		}
+		if !seen[name] {
+			seen[name] = true
+			selected = append(selected, name)
+		}
+	}
+	return selected, nil
+}
+
+func enableGCPAPIs(apis []string) error {
+	projectID, err := gcpProject()
+	if err != nil {
+		return err
+	}
+	client, err := gcpClient()
+	if err != nil {
+		return err
+	}
+	fmt.Println("using project: ", projectID)
+
+	enabled, err := gcp.EnabledAPIs(client, projectID)
+	if err != nil {
+		return err
+	}
+
+	newlyEnabled := 0
+	for i, api := range apis {
+		prefix := fmt.Sprintf("[%d/%d] %s:", i+1, len(apis), api)
+		if enabled[api] {
+			fmt.Println(prefix, "already enabled")
+			continue
+		}
+
+		// Enabling an API can take more than a minute.
+		fmt.Println(prefix, "enabling...")
+		start := time.Now()
+		if err := gcp.EnableAPI(client, projectID, api); err != nil {
+			return fmt.Errorf("%v, enable it at https://console.cloud.google.com/apis/library/%s/?project=%s", err, api, projectID)
+		}
+		fmt.Printf("%s enabled in %s\n", prefix, time.Since(start).Round(time.Second))
+		newlyEnabled++
+	}
+
+	fmt.Printf("%d APIs enabled, %d were already enabled.\n", newlyEnabled, len(apis)-newlyEnabled)
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSelectGCPAPIs tests that APIs are selected by short or full name,
+// without duplicates, and that unknown short names are rejected.
+func TestSelectGCPAPIs(t *testing.T) {
+	got, err := selectGCPAPIs([]string{"pubsub", "spanner.googleapis.com", "spanner", "vision.googleapis.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error selecting APIs: %v", err)
+	}
+	want := []string{"pubsub.googleapis.com", "spanner.googleapis.com", "vision.googleapis.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Selected APIs do not match: got %v; want %v", got, want)
+	}
+
+	all, err := selectGCPAPIs(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error selecting all APIs: %v", err)
+	}
+	if len(all) != len(gcpServiceAPIs) {
+		t.Fatalf("Number of APIs does not match: got %d; want %d", len(all), len(gcpServiceAPIs))
+	}
+
+	if _, err := selectGCPAPIs([]string{"pubsbu"}); err == nil {
+		t.Fatalf("Expected an error selecting an unknown API")
+	}
+}
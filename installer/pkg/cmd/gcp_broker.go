@@ -25,16 +25,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
-	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/broker-cli/auth"
 	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/broker-cli/client/adapter"
 	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -44,6 +43,7 @@ const (
 	brokerSARole                   = "roles/servicebroker.operator"
 	gcpBrokerTemplateDir           = "templates/gcp/"
 	gcpBrokerDeprecatedTemplateDir = "templates/gcp-deprecated/"
+	gcpBrokerNamespace             = "google-oauth"
 )
 
 var (
@@ -52,6 +52,7 @@ var (
 
 	requiredAPIs = []string{
 		"servicebroker.googleapis.com",
+		"deploymentmanager.googleapis.com",
 		// In the future, the APIs below will be enabled on-demand.
 		"bigquery-json.googleapis.com",
 		"bigtableadmin.googleapis.com",
@@ -95,100 +96,266 @@ var (
 	}
 )
 
+// addBrokerConfig contains the add-gcp-broker configuration.
+type addBrokerConfig struct {
+	// AuthFromGCPSecret is the Secret Manager secret holding the service
+	// account key the broker authenticates with. If it is empty, a new
+	// key is created for the broker service account.
+	AuthFromGCPSecret string
+
+	// SkipPreflight skips the project, billing and permission checks.
+	SkipPreflight bool
+
+	// OnlyServices restricts the service classes of the broker to these
+	// services, given by short name or class external name.
+	OnlyServices []string
+
+	// ExcludeServices hides the service classes of these services.
+	ExcludeServices []string
+}
+
+// gcpServiceClasses maps short names to the external names of the service
+// classes the GCP broker offers for them.
+var gcpServiceClasses = map[string][]string{
+	"bigquery": {"bigquery"},
+	"bigtable": {"cloud-bigtable"},
+	"cloudsql": {"cloudsql-mysql", "cloudsql-postgres"},
+	"iam":      {"cloud-iam-service-account"},
+	"ml":       {"cloud-ml-apis"},
+	"pubsub":   {"cloud-pubsub"},
+	"spanner":  {"cloud-spanner"},
+	"storage":  {"cloud-storage"},
+}
+
+// serviceClassNames returns the external names of the service classes of
+// services, given by short name or external name, sorted and without
+// duplicates.
+func serviceClassNames(services []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, s := range services {
+		s = strings.TrimSpace(s)
+		classes, ok := gcpServiceClasses[s]
+		if !ok {
+			if !dns1123LabelRE.MatchString(s) {
+				return nil, fmt.Errorf("invalid service %q, use one of %s or the external name of a service class", s, strings.Join(gcpServiceClassNames(), ", "))
+			}
+			classes = []string{s}
+		}
+		for _, c := range classes {
+			if !seen[c] {
+				seen[c] = true
+				names = append(names, c)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func gcpServiceClassNames() []string {
+	var names []string
+	for n := range gcpServiceClasses {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// serviceClassRestrictions returns the catalogRestrictions requirements of
+// the broker letting only the classes of only, if any, and none of those of
+// exclude through.
+func serviceClassRestrictions(only, exclude []string) ([]string, error) {
+	var restrictions []string
+	for _, r := range []struct {
+		services []string
+		operator string
+	}{
+		{only, "in"},
+		{exclude, "notin"},
+	} {
+		if len(r.services) == 0 {
+			continue
+		}
+		names, err := serviceClassNames(r.services)
+		if err != nil {
+			return nil, err
+		}
+		restrictions = append(restrictions, fmt.Sprintf("spec.externalName %s (%s)", r.operator, strings.Join(names, ", ")))
+	}
+	return restrictions, nil
+}
+
 func NewAddGCPBrokerCmd() *cobra.Command {
-	return &cobra.Command{
+	bc := &addBrokerConfig{}
+	c := &cobra.Command{
 		Use:   "add-gcp-broker",
 		Short: "Adds the Service Broker",
 		Long:  `Adds Google Cloud Platfrom Service Broker to Service Catalog`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := addGCPBroker(); err != nil {
-				fmt.Println("Failed to configure the Service Broker")
+			if err := addGCPBroker(bc); err != nil {
+				messages.Println(messages.BrokerAddFailed)
 				return err
 			}
-			fmt.Println("The Service Broker has been added successfully.")
+			messages.Println(messages.BrokerAdded)
 			return nil
 		},
 	}
+	c.Flags().StringVar(&bc.AuthFromGCPSecret, "auth-from-gcp-secret", "",
+		"Secret Manager secret (projects/<project>/secrets/<secret>[/versions/<version>]) holding the service account key for the broker")
+	c.Flags().BoolVar(&bc.SkipPreflight, "skip-preflight", false, "Skip checking the project, its billing and your permissions on it")
+	c.Flags().StringSliceVar(&bc.OnlyServices, "only-services", nil, "Only offer these services, by short name ("+strings.Join(gcpServiceClassNames(), ", ")+") or service class external name")
+	c.Flags().StringSliceVar(&bc.ExcludeServices, "exclude-services", nil, "Do not offer these services, by short name or service class external name")
+	addGCPContextFlags(c)
+	return c
 }
 
-func addGCPBroker() error {
-	projectID, err := gcp.GetConfigValue("core", "project")
+func addGCPBroker(bc *addBrokerConfig) (err error) {
+	restrictions, err := serviceClassRestrictions(bc.OnlyServices, bc.ExcludeServices)
 	if err != nil {
-		return fmt.Errorf("error getting configured project value : %v", err)
-	}
-
-	fmt.Println("using project: ", projectID)
-
-	if err := enableRequiredAPIs(projectID); err != nil {
 		return err
 	}
 
-	brokerSAName, err := constructSAName()
+	projectID, err := gcpProject()
 	if err != nil {
-		return fmt.Errorf("error constructing service account name: %v", err)
+		return err
 	}
-
-	brokerSAEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com", brokerSAName, projectID)
-	err = getOrCreateGCPServiceAccount(brokerSAName, brokerSAEmail)
+	client, err := gcpClient()
 	if err != nil {
 		return err
 	}
 
-	err = gcp.AddServiceAccountPerms(projectID, brokerSAEmail, brokerSARole)
-	if err != nil {
-		return err
+	fmt.Println("using project: ", projectID)
+
+	if !bc.SkipPreflight {
+		if err := gcpPreflight(client, projectID, addBrokerPermissions); err != nil {
+			return err
+		}
 	}
 
-	// create temporary directory for k8s artifacts and other temporary files
-	dir, err := ioutil.TempDir("/tmp", "service-catalog-gcp")
-	if err != nil {
-		return fmt.Errorf("error creating temporary dir: %v", err)
+	if err := enableRequiredAPIs(client, projectID); err != nil {
+		return err
 	}
 
-	keyFile := filepath.Join(dir, "key.json")
-	err = gcp.CreateServiceAccountKey(brokerSAEmail, keyFile)
+	// The workspace keeps the generated key, the rendered configs contain
+	// the key of the secret, don't leave them behind.
+	ws, err := createWorkspace(gcpBrokerNamespace, "add-gcp-broker", workspace.Options{
+		Cleanup:   workspace.Never,
+		Sensitive: bc.AuthFromGCPSecret != "",
+	})
 	if err != nil {
-		return fmt.Errorf("error creating service account key: %v", err)
+		return err
 	}
-	fmt.Println("generated the key at: ", keyFile)
+	defer func() { ws.Done(err) }()
+	dir := ws.Dir
 
-	key, err := base64FileContent(keyFile)
-	if err != nil {
-		return fmt.Errorf("error reading content of the key file : %v", err)
+	var brokerSAEmail, key string
+	// cleanup removes the service account key if adding the broker fails.
+	cleanup := func() {}
+	if bc.AuthFromGCPSecret != "" {
+		brokerSAEmail, key, err = serviceAccountKeyFromGCPSecret(client, bc.AuthFromGCPSecret)
+		if err != nil {
+			return err
+		}
+		fmt.Println("using the service account key of", brokerSAEmail, "from", bc.AuthFromGCPSecret)
+
+		if err := gcp.AddServiceAccountPerms(client, projectID, brokerSAEmail, brokerSARole); err != nil {
+			return err
+		}
+	} else {
+		brokerSAEmail, key, err = createBrokerServiceAccountKey(client, projectID, dir)
+		if err != nil {
+			return err
+		}
+		cleanup = func() { cleanupNewKey(client, brokerSAEmail, key) }
 	}
 
-	vb, err := getOrCreateVirtualBroker(projectID, "default", "Default Broker")
+	vb, err := getOrCreateVirtualBroker(client, projectID, "default", "Default Broker")
 	if err != nil {
 		// Clean up the newly generated key if the command failed.
-		cleanupNewKey(brokerSAEmail, key)
+		cleanup()
 		return fmt.Errorf("error retrieving or creating default broker: %v", err)
 	}
 
 	data := map[string]interface{}{
-		"SvcAccountKey": key,
-		"GCPBrokerURL":  vb.URL,
+		"SvcAccountKey":            key,
+		"GCPBrokerURL":             vb.URL,
+		"ServiceClassRestrictions": restrictions,
 	}
 
 	// generate config files and deploy the GCP broker resources
 	err = generateConfigs(dir, gcpBrokerTemplateDir, gcpBrokerFileNames, data)
 	if err != nil {
 		// Clean up the newly generated key if the command failed.
-		cleanupNewKey(brokerSAEmail, key)
+		cleanup()
 		return fmt.Errorf("error generating configs for the Service Broker: %v", err)
 	}
 
-	err = deployConfigs(dir, gcpBrokerFileNames)
+	err = deployConfig(dir, applyOptions{})
 	if err != nil {
 		// Clean up the newly generated key if the command failed.
-		cleanupNewKey(brokerSAEmail, key)
+		cleanup()
 		return fmt.Errorf("error deploying the Service Broker configs: %v", err)
 	}
 
 	return err
 }
 
-func enableRequiredAPIs(projectID string) error {
-	if err := gcp.EnableAPIs(requiredAPIs); err != nil {
+// createBrokerServiceAccountKey creates the broker service account, if it
+// does not exist yet, and a new key for it in dir. It returns the service
+// account email and the base64 encoded key.
+func createBrokerServiceAccountKey(client *http.Client, projectID, dir string) (string, string, error) {
+	brokerSAName, err := constructSAName()
+	if err != nil {
+		return "", "", fmt.Errorf("error constructing service account name: %v", err)
+	}
+
+	brokerSAEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com", brokerSAName, projectID)
+	err = getOrCreateGCPServiceAccount(client, projectID, brokerSAName, brokerSAEmail)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = gcp.AddServiceAccountPerms(client, projectID, brokerSAEmail, brokerSARole)
+	if err != nil {
+		return "", "", err
+	}
+
+	keyFile := filepath.Join(dir, "key.json")
+	err = gcp.CreateServiceAccountKey(client, brokerSAEmail, keyFile)
+	if err != nil {
+		return "", "", fmt.Errorf("error creating service account key: %v", err)
+	}
+	fmt.Println("generated the key at: ", keyFile)
+
+	key, err := base64FileContent(keyFile)
+	if err != nil {
+		return "", "", fmt.Errorf("error reading content of the key file : %v", err)
+	}
+	return brokerSAEmail, key, nil
+}
+
+// serviceAccountKeyFromGCPSecret reads the service account key stored in
+// the Secret Manager secret name. It returns the service account email and
+// the base64 encoded key.
+func serviceAccountKeyFromGCPSecret(client *http.Client, name string) (string, string, error) {
+	b, err := gcp.AccessSecret(client, name)
+	if err != nil {
+		return "", "", err
+	}
+
+	var sa struct {
+		Type        string `json:"type"`
+		ClientEmail string `json:"client_email"`
+	}
+	if err := json.Unmarshal(b, &sa); err != nil || sa.Type != "service_account" || sa.ClientEmail == "" {
+		return "", "", fmt.Errorf("secret %s does not contain a service account JSON key", name)
+	}
+	return sa.ClientEmail, base64.StdEncoding.EncodeToString(b), nil
+}
+
+func enableRequiredAPIs(client *http.Client, projectID string) error {
+	if err := gcp.EnableAPIs(client, projectID, requiredAPIs); err != nil {
 		var b bytes.Buffer
 		fmt.Fprintln(&b, "error enabling APIs. To make sure all APIs are correctly enabled, use links below:")
 		for _, a := range requiredAPIs {
@@ -206,7 +373,7 @@ func enableRequiredAPIs(projectID string) error {
 }
 
 func constructSAName() (string, error) {
-	bout, err := exec.Command("kubectl", "config", "view", "--output", "json").CombinedOutput()
+	bout, err := kubectlCommand("config", "view", "--output", "json").CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("error retriving kubernetes config: %s : %v", string(bout), err)
 	}
@@ -249,24 +416,19 @@ func constructSAName() (string, error) {
 	return strings.ToLower(res), nil
 }
 
-func getOrCreateGCPServiceAccount(name, email string) error {
-	_, err := gcp.GetServiceAccount(email)
+func getOrCreateGCPServiceAccount(client *http.Client, projectID, name, email string) error {
+	sa, err := gcp.GetServiceAccount(client, email)
 	if err != nil {
-		// TODO(droot): distinguish between real error and NOT_FOUND error
-		err = gcp.CreateServiceAccount(name, "Google Cloud Platform Service Broker")
-		if err != nil {
-			return err
-		}
+		return err
+	}
+	if sa == nil {
+		return gcp.CreateServiceAccount(client, projectID, name, "Google Cloud Platform Service Broker")
 	}
 	return nil
 }
 
-func getOrCreateVirtualBroker(projectID, brokerName, brokerTitle string) (*virtualBroker, error) {
-	// use the application default credentials
-	brokerClient, err := httpAdapterFromAuthKey("")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create broker client. You might want to run 'gcloud auth application-default login'")
-	}
+func getOrCreateVirtualBroker(client *http.Client, projectID, brokerName, brokerTitle string) (*virtualBroker, error) {
+	brokerClient := adapter.NewHttpAdapter(client)
 
 	host := "https://servicebroker.googleapis.com"
 	errCode, respBody, err := brokerClient.CreateBroker(&adapter.CreateBrokerParams{
@@ -333,27 +495,8 @@ func getContext() context.Context {
 	return context.Background()
 }
 
-// httpAdapterFromAuthKey returns an http adapter with credentials to gcloud if
-// keyFile is not set and to a service account if it is set.
-func httpAdapterFromAuthKey(keyFile string) (*adapter.HttpAdapter, error) {
-	var client *http.Client
-	var err error
-	if keyFile != "" {
-		client, err = auth.HttpClientFromFile(getContext(), keyFile)
-		if err != nil {
-			return nil, fmt.Errorf("error creating http client from service account file %s: %v", keyFile, err)
-		}
-	} else {
-		client, err = auth.HttpClientWithDefaultCredentials(getContext())
-		if err != nil {
-			return nil, fmt.Errorf("Error creating http client using default gcloud credentials: %v", err)
-		}
-	}
-	return adapter.NewHttpAdapter(client), nil
-}
-
 // cleanupNewKey removes the newly generated service account key.
-func cleanupNewKey(email, key string) {
+func cleanupNewKey(client *http.Client, email, key string) {
 	keyBytes, err := base64.StdEncoding.DecodeString(key)
 	if err != nil {
 		// Silently return if there is an error decoding the newly generated key.
@@ -368,33 +511,36 @@ func cleanupNewKey(email, key string) {
 	}
 
 	keyID := keyJson["private_key_id"].(string)
-	gcp.RemoveServiceAccountKey(email, keyID)
+	gcp.RemoveServiceAccountKey(client, email, keyID)
 }
 
 func NewRemoveGCPBrokerCmd() *cobra.Command {
-	return &cobra.Command{
+	var ns string
+	c := &cobra.Command{
 		Use:   "remove-gcp-broker",
 		Short: "Remove the Service Broker",
 		Long:  `Removes Google Cloud Platform Service Broker from service catalog`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := removeGCPBroker(); err != nil {
-				fmt.Println("Failed to remove the Service Broker")
+			if err := removeGCPBroker(ns); err != nil {
+				messages.Println(messages.BrokerRemoveFailed)
 				return err
 			}
-			fmt.Println("The Service Broker removed successfully.")
+			messages.Println(messages.BrokerRemoved)
 			return nil
 		},
 	}
+	addGCPContextFlags(c)
+	c.Flags().StringVar(&ns, "namespace", defaultNamespace, "Namespace Service Catalog is installed in, holding the broker resources of previous versions of sc")
+	return c
 }
 
-func removeGCPBroker() error {
-	// Create temporary directory for k8s artifacts and other temporary files.
-	dir, err := ioutil.TempDir("/tmp", "service-catalog-gcp")
+func removeGCPBroker(ns string) (err error) {
+	ws, err := createWorkspace(gcpBrokerNamespace, "remove-gcp-broker", workspace.Options{Cleanup: workspace.Always})
 	if err != nil {
-		return fmt.Errorf("error creating temporary dir: %v", err)
+		return err
 	}
-
-	defer os.RemoveAll(dir)
+	defer func() { ws.Done(err) }()
+	dir := ws.Dir
 
 	// remove GCP Broker k8s resources
 	err = generateConfigs(dir, gcpBrokerTemplateDir, gcpBrokerFileNames, nil)
@@ -402,7 +548,7 @@ func removeGCPBroker() error {
 		return fmt.Errorf("error generating configs for the Service Broker: %v", err)
 	}
 
-	err = removeConfigs(dir, gcpBrokerFileNames)
+	err = deleteConfig(dir)
 	if err != nil {
 		return fmt.Errorf("error deleting broker resources: %v", err)
 	}
@@ -410,14 +556,18 @@ func removeGCPBroker() error {
 	// due to moving the google-oauth resources to a separate namespace, we
 	// must also remove deprecated Service Broker k8s resources for backwards
 	// compatibility
-	err = removeDeprecatedGCPBrokerResources()
+	err = removeDeprecatedGCPBrokerResources(ns)
 	if err != nil {
 		return fmt.Errorf("error deleting broker resources : %v", err)
 	}
 
-	projectID, err := gcp.GetConfigValue("core", "project")
+	projectID, err := gcpProject()
 	if err != nil {
-		return fmt.Errorf("error getting configured project value : %v", err)
+		return err
+	}
+	client, err := gcpClient()
+	if err != nil {
+		return err
 	}
 
 	brokerSAName, err := constructSAName()
@@ -426,29 +576,29 @@ func removeGCPBroker() error {
 	}
 
 	brokerSAEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com", brokerSAName, projectID)
-	_, err = gcp.GetServiceAccount(brokerSAEmail)
+	sa, err := gcp.GetServiceAccount(client, brokerSAEmail)
 	if err != nil {
-		// TODO(maqiuyujoyce): distinguish between real error and NOT_FOUND error
+		return err
+	}
+	if sa == nil {
 		oldBrokerSAEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com", oldBrokerSAName, projectID)
-		_, err := gcp.GetServiceAccount(oldBrokerSAEmail)
-		if err == nil {
+		if old, err := gcp.GetServiceAccount(client, oldBrokerSAEmail); err == nil && old != nil {
 			fmt.Printf("WARNING: Service account %s is deprecated now. Please clean it up from your GCP project.\n", oldBrokerSAEmail)
 		}
 
-		// If we can't retrieve any of the service accounts, then it means either there is
-		// something wrong with IAM server, or both accounts are invalid/nonexistent. And
-		// we should safely assume the remove process is done.
+		// The broker service account does not exist, there is nothing
+		// left to remove.
 		return nil
 	}
 
 	// Remove the Service Broker Operator role.
-	err = gcp.RemoveServiceAccountPerms(projectID, brokerSAEmail, brokerSARole)
+	err = gcp.RemoveServiceAccountPerms(client, projectID, brokerSAEmail, brokerSARole)
 	if err != nil {
 		return err
 	}
 
 	// Clean up all the associated keys.
-	err = gcp.RemoveAllServiceAccountKeys(brokerSAEmail)
+	err = gcp.RemoveAllServiceAccountKeys(client, brokerSAEmail)
 	if err != nil {
 		return err
 	}
@@ -457,22 +607,22 @@ func removeGCPBroker() error {
 }
 
 // removeDeprecatedGCPBrokerResources removes GCP broker-related k8s resources
-// that were created by a previous version of this tool in a different namespace,
-// for backwards compatibility
-func removeDeprecatedGCPBrokerResources() error {
-	dir, err := ioutil.TempDir("/tmp", "service-catalog-gcp-deprecated")
+// that were created by a previous version of this tool in the catalog
+// namespace ns, for backwards compatibility
+func removeDeprecatedGCPBrokerResources(ns string) (err error) {
+	ws, err := createWorkspace(ns, "remove-deprecated-gcp-broker", workspace.Options{Cleanup: workspace.Always})
 	if err != nil {
-		return fmt.Errorf("error creating temporary dir: %v", err)
+		return err
 	}
+	defer func() { ws.Done(err) }()
+	dir := ws.Dir
 
-	defer os.RemoveAll(dir)
-
-	err = generateConfigs(dir, gcpBrokerDeprecatedTemplateDir, gcpBrokerDeprecatedFileNames, nil)
+	err = generateConfigs(dir, gcpBrokerDeprecatedTemplateDir, gcpBrokerDeprecatedFileNames, map[string]interface{}{"Namespace": ns})
 	if err != nil {
 		return fmt.Errorf("error generating configs: %v", err)
 	}
 
-	err = removeConfigs(dir, gcpBrokerDeprecatedFileNames)
+	err = deleteConfig(dir)
 	if err != nil {
 		return err
 	}
@@ -481,39 +631,23 @@ func removeDeprecatedGCPBrokerResources() error {
 }
 
 func generateConfigs(genDir, templateDir string, filenames []string, data map[string]interface{}) error {
-	for _, f := range filenames {
-		if err := generateFileFromTmpl(filepath.Join(genDir, f+".yaml"), templateDir+f+".yaml.tmpl", data); err != nil {
-			return err
-		}
+	if data == nil {
+		data = map[string]interface{}{}
 	}
-	return nil
-}
+	data["APIVersions"] = clusterAPIVersions()
 
-func deployConfigs(dir string, filenames []string) error {
 	for _, f := range filenames {
-		output, err := exec.Command("kubectl", "apply", "-f", filepath.Join(dir, f+".yaml")).CombinedOutput()
-		// TODO: cleanup
-		if err != nil {
-			return fmt.Errorf("deploy failed with output: %s: %v", err, string(output))
-		}
-	}
-	return nil
-}
-
-func removeConfigs(dir string, filenames []string) error {
-	for _, f := range filenames {
-		output, err := exec.Command("kubectl", "delete", "-f", filepath.Join(dir, f+".yaml"), "--ignore-not-found").CombinedOutput()
-		// TODO: cleanup
-		if err != nil {
-			return fmt.Errorf("failed to delete resources output: %s: %v", err, string(output))
+		if err := generateFileFromTmpl(filepath.Join(genDir, f+".yaml"), templateDir+f+".yaml.tmpl", data); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
 type createBrokerConfig struct {
-	name  string // name of the broker
-	title string // title of the broker
+	name          string // name of the broker
+	title         string // title of the broker
+	skipPreflight bool   // skip the project and permission checks
 }
 
 // NewCreateGCPBrokerCmd returns a cobra command which creates a new GCP service
@@ -530,22 +664,34 @@ func NewCreateGCPBrokerCmd() *cobra.Command {
 	}
 	cmd.Flags().StringVar(&cfg.name, "name", "default", "Broker name, lowercase, hyphens allowed")
 	cmd.Flags().StringVar(&cfg.title, "title", "Default Broker", "A title of the broker for display")
+	cmd.Flags().BoolVar(&cfg.skipPreflight, "skip-preflight", false, "Skip checking the project, its billing and your permissions on it")
+	addGCPContextFlags(cmd)
 	return cmd
 }
 
 func createGCPBroker(cfg *createBrokerConfig) error {
-	projectID, err := gcp.GetConfigValue("core", "project")
+	projectID, err := gcpProject()
 	if err != nil {
-		return fmt.Errorf("error getting configured project value : %v", err)
+		return err
+	}
+	client, err := gcpClient()
+	if err != nil {
+		return err
 	}
 
 	fmt.Println("using project: ", projectID)
 
-	if err := enableRequiredAPIs(projectID); err != nil {
+	if !cfg.skipPreflight {
+		if err := gcpPreflight(client, projectID, createBrokerPermissions); err != nil {
+			return err
+		}
+	}
+
+	if err := enableRequiredAPIs(client, projectID); err != nil {
 		return err
 	}
 
-	vb, err := getOrCreateVirtualBroker(projectID, cfg.name, cfg.title)
+	vb, err := getOrCreateVirtualBroker(client, projectID, cfg.name, cfg.title)
 	if err != nil {
 		return fmt.Errorf("error retrieving or creating default broker : %v", err)
 	}
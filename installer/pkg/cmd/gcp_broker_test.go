@@ -0,0 +1,134 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// gcpTestClient returns a client sending the requests of the GCP endpoints
+// to handler instead, and the function stopping the server.
+func gcpTestClient(t *testing.T, handler http.Handler) (*http.Client, func()) {
+	s := httptest.NewServer(handler)
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing server URL: %v", err)
+	}
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		redirected := *r
+		ru := *r.URL
+		ru.Scheme, ru.Host = u.Scheme, u.Host
+		redirected.URL = &ru
+		return http.DefaultTransport.RoundTrip(&redirected)
+	})}, s.Close
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// secretManagerHandler serves the latest versions of the Secret Manager
+// secrets in payloads, the others are not found.
+func secretManagerHandler(payloads map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/"), "/versions/latest:access")
+		payload, ok := payloads[secret]
+		if !ok {
+			http.Error(w, "Secret ["+secret+"] not found", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte(payload))},
+		})
+	})
+}
+
+// TestServiceAccountKeyFromGCPSecret tests that the service account key
+// stored in the secret is returned base64 encoded with its email.
+func TestServiceAccountKeyFromGCPSecret(t *testing.T) {
+	key := `{"type": "service_account", "client_email": "broker@p.iam.gserviceaccount.com"}`
+	client, stop := gcpTestClient(t, secretManagerHandler(map[string]string{
+		"projects/p/secrets/key":      key,
+		"projects/p/secrets/password": "hunter2",
+		"projects/p/secrets/user-key": `{"type": "authorized_user", "client_id": "x"}`,
+	}))
+	defer stop()
+
+	email, encoded, err := serviceAccountKeyFromGCPSecret(client, "projects/p/secrets/key")
+	if err != nil {
+		t.Fatalf("Unexpected error reading the key: %v", err)
+	}
+	if email != "broker@p.iam.gserviceaccount.com" {
+		t.Fatalf("Unexpected service account %q", email)
+	}
+	if b, _ := base64.StdEncoding.DecodeString(encoded); string(b) != key {
+		t.Fatalf("Unexpected key %q", b)
+	}
+
+	for _, tc := range []struct {
+		name, wantErr string
+	}{
+		{"projects/p/secrets/password", "secret projects/p/secrets/password does not contain a service account JSON key"},
+		{"projects/p/secrets/user-key", "secret projects/p/secrets/user-key does not contain a service account JSON key"},
+		{"projects/p/secrets/missing", "404 Not Found"},
+	} {
+		if _, _, err := serviceAccountKeyFromGCPSecret(client, tc.name); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Fatalf("%s: expected an error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+// TestServiceClassRestrictions tests that the service filters are
+// translated into catalogRestrictions requirements on the external names of
+// the service classes.
+func TestServiceClassRestrictions(t *testing.T) {
+	for _, c := range []struct {
+		only, exclude []string
+		want          []string
+	}{
+		{nil, nil, nil},
+		{[]string{"pubsub", "cloudsql"}, nil,
+			[]string{"spec.externalName in (cloud-pubsub, cloudsql-mysql, cloudsql-postgres)"}},
+		{nil, []string{"bigquery", "my-class", "bigquery"},
+			[]string{"spec.externalName notin (bigquery, my-class)"}},
+		{[]string{"cloudsql"}, []string{"cloudsql-postgres"},
+			[]string{"spec.externalName in (cloudsql-mysql, cloudsql-postgres)", "spec.externalName notin (cloudsql-postgres)"}},
+	} {
+		got, err := serviceClassRestrictions(c.only, c.exclude)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v, %v: %v", c.only, c.exclude, err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Restrictions for %v, %v do not match: got %q; want %q", c.only, c.exclude, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"", "Cloud SQL", "pubsub)"} {
+		if _, err := serviceClassRestrictions([]string{bad}, nil); err == nil {
+			t.Fatalf("Expected an error for service %q", bad)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+	"github.com/spf13/cobra"
+)
+
+// addGCPContextFlags adds the flags overriding the project and account of
+// the GCP API calls to a command calling GCP APIs.
+func addGCPContextFlags(c *cobra.Command) {
+	r := gcp.DefaultResolver
+	c.Flags().StringVar(&r.Project, "project", "", "GCP project to use instead of the one of the gcloud config or of the Application Default Credentials")
+	c.Flags().StringVar(&r.Account, "account", "", "gcloud account to authenticate as instead of the Application Default Credentials")
+}
+
+// gcpProject returns the project of the GCP API calls.
+func gcpProject() (string, error) {
+	projectID, err := gcp.DefaultResolver.ProjectID()
+	if err != nil {
+		return "", fmt.Errorf("error getting configured project value : %v", err)
+	}
+	return projectID, nil
+}
+
+// gcpClient returns the client of the GCP API calls.
+func gcpClient() (*http.Client, error) {
+	client, err := gcp.DefaultResolver.Client(getContext())
+	if err != nil {
+		return nil, fmt.Errorf("error creating GCP client, run `gcloud auth application-default login` or use --account: %v", err)
+	}
+	return client, nil
+}
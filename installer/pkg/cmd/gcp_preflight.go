@@ -0,0 +1,112 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+)
+
+// gcpPermission is an IAM permission a broker command needs on the project.
+type gcpPermission struct {
+	Name string
+	// Purpose tells what the permission is needed for.
+	Purpose string
+}
+
+var (
+	// createBrokerPermissions are needed to create the broker.
+	createBrokerPermissions = []gcpPermission{
+		{"serviceusage.services.list", "list the enabled APIs"},
+		{"serviceusage.services.enable", "enable the required APIs"},
+	}
+
+	// addBrokerPermissions are needed to create the broker and its
+	// service account and add it to Service Catalog.
+	addBrokerPermissions = append([]gcpPermission{
+		{"iam.serviceAccounts.get", "look up the broker service account"},
+		{"iam.serviceAccounts.create", "create the broker service account"},
+		{"iam.serviceAccountKeys.create", "create a key for the broker service account"},
+		{"resourcemanager.projects.getIamPolicy", "read the project IAM policy"},
+		{"resourcemanager.projects.setIamPolicy", "grant " + brokerSARole + " to the broker service account"},
+	}, createBrokerPermissions...)
+)
+
+// gcpPreflight checks that project projectID exists, that billing is
+// enabled for it and that the caller has permissions on it, before any
+// broker resource is created. It reports all the failed checks at once.
+func gcpPreflight(client *http.Client, projectID string, permissions []gcpPermission) error {
+	p, err := gcp.GetProject(client, projectID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		// The other checks would fail on the missing project too.
+		return fmt.Errorf("project %s does not exist or you are missing permission resourcemanager.projects.get on it", projectID)
+	}
+
+	var problems []string
+	if p.LifecycleState != "ACTIVE" {
+		problems = append(problems, fmt.Sprintf("project %s is %s, it must be ACTIVE", projectID, p.LifecycleState))
+	}
+
+	billing, err := gcp.BillingEnabled(client, projectID)
+	switch {
+	case err != nil:
+		problems = append(problems, err.Error())
+	case !billing:
+		problems = append(problems, fmt.Sprintf("billing is not enabled for project %s, enable it at https://console.cloud.google.com/billing/linkedaccount?project=%s", projectID, projectID))
+	}
+
+	var names []string
+	for _, perm := range permissions {
+		names = append(names, perm.Name)
+	}
+	missing, err := gcp.MissingPermissions(client, projectID, names)
+	switch {
+	case err != nil:
+		problems = append(problems, err.Error())
+	case len(missing) > 0:
+		problems = append(problems, missingPermissionsError(projectID, permissions, missing).Error())
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("preflight checks failed for project %s:\n  - %s", projectID, strings.Join(problems, "\n  - "))
+	}
+	fmt.Printf("preflight checks passed for project %s\n", projectID)
+	return nil
+}
+
+func missingPermissionsError(projectID string, permissions []gcpPermission, missing []string) error {
+	purposes := make(map[string]string, len(permissions))
+	for _, perm := range permissions {
+		purposes[perm.Name] = perm.Purpose
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "missing permissions on project %s:\n", projectID)
+	for _, m := range missing {
+		fmt.Fprintf(&b, "   %s, needed to %s\n", m, purposes[m])
+	}
+	fmt.Fprint(&b, "ask a project owner to grant you a role with these permissions, e.g. roles/owner")
+	return errors.New(b.String())
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// preflightHandler serves the project p in state, its billing info and
+// the permissions testing, granting the permissions in granted.
+func preflightHandler(state string, billing bool, granted ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/projects/p":
+			if state == "" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"projectId": "p", "lifecycleState": state})
+		case "/v1/projects/p/billingInfo":
+			json.NewEncoder(w).Encode(map[string]bool{"billingEnabled": billing})
+		case "/v1/projects/p:testIamPermissions":
+			json.NewEncoder(w).Encode(map[string][]string{"permissions": granted})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+}
+
+// TestGCPPreflight tests that all the failed checks are reported at once.
+func TestGCPPreflight(t *testing.T) {
+	all := []string{"serviceusage.services.list", "serviceusage.services.enable"}
+	for _, tc := range []struct {
+		name     string
+		handler  http.Handler
+		wantErrs []string
+		notWant  []string
+	}{
+		{
+			name:    "ok",
+			handler: preflightHandler("ACTIVE", true, all...),
+		},
+		{
+			name:     "missing project",
+			handler:  preflightHandler("", true, all...),
+			wantErrs: []string{"project p does not exist or you are missing permission resourcemanager.projects.get on it"},
+		},
+		{
+			name:     "billing disabled",
+			handler:  preflightHandler("ACTIVE", false, all...),
+			wantErrs: []string{"billing is not enabled for project p"},
+			notWant:  []string{"missing permissions"},
+		},
+		{
+			name:     "missing permissions",
+			handler:  preflightHandler("ACTIVE", true, "serviceusage.services.list"),
+			wantErrs: []string{"missing permissions on project p:\n   serviceusage.services.enable, needed to enable the required APIs"},
+			notWant:  []string{"billing", "serviceusage.services.list"},
+		},
+		{
+			name:    "all failed",
+			handler: preflightHandler("DELETE_REQUESTED", false),
+			wantErrs: []string{
+				"project p is DELETE_REQUESTED, it must be ACTIVE",
+				"billing is not enabled for project p",
+				"serviceusage.services.list, needed to list the enabled APIs",
+				"serviceusage.services.enable, needed to enable the required APIs",
+			},
+		},
+	} {
+		client, stop := gcpTestClient(t, tc.handler)
+		err := gcpPreflight(client, "p", createBrokerPermissions)
+		stop()
+		if len(tc.wantErrs) == 0 {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Fatalf("%s: expected an error", tc.name)
+		}
+		for _, want := range tc.wantErrs {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: expected the error to contain %q, got %v", tc.name, want, err)
+			}
+		}
+		for _, unwanted := range tc.notWant {
+			if strings.Contains(err.Error(), unwanted) {
+				t.Errorf("%s: expected the error not to contain %q, got %v", tc.name, unwanted, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/version"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+func NewGenerateCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "generate",
+		Short: "generates manifests for running the installer",
+		Long:  "generates manifests for running the installer and prints them to stdout",
+		Args:  cobra.MinimumNArgs(1),
+	}
+	c.AddCommand(
+		newGenerateBootstrapJobCmd(),
+		newGenerateInstallerRBACCmd(),
+		newGenerateGitOpsCmd(),
+		newGenerateSBOMCmd(),
+	)
+	return c
+}
+
+// bootstrapJobConfig contains the generate bootstrap-job configuration.
+type bootstrapJobConfig struct {
+	// Namespace is the namespace the installer job runs in.
+	Namespace string
+
+	// Image is the installer image.
+	Image string
+
+	// Args are the arguments passed to the installer.
+	Args []string
+
+	// ClusterAdmin binds the cluster-admin role to the installer service
+	// account instead of the roles generate installer-rbac computes for the
+	// install of Args.
+	ClusterAdmin bool
+}
+
+func newGenerateBootstrapJobCmd() *cobra.Command {
+	bc := &bootstrapJobConfig{}
+	c := &cobra.Command{
+		Use:   "bootstrap-job",
+		Short: "generates a Job installing Service Catalog from inside the cluster",
+		Long: `generates a Job installing Service Catalog from inside the cluster,
+along with the namespace, service account and RBAC it needs. Apply the output
+with kubectl apply -f to install Service Catalog without external CLI access.
+The job is granted the roles of generate installer-rbac for the install
+flags of --args, scoped to the objects the install creates.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return generateBootstrapJob(bc)
+		},
+	}
+	c.Flags().StringVar(&bc.Namespace, "namespace", "sc-installer", "Namespace the installer job runs in")
+	c.Flags().StringVar(&bc.Image, "image", "gcr.io/gcp-services/sc-installer:v"+version.Version(), "Installer image")
+	c.Flags().StringSliceVar(&bc.Args, "args", []string{"install"}, "Arguments passed to the installer")
+	c.Flags().BoolVar(&bc.ClusterAdmin, "cluster-admin", false, "Make the installer a cluster admin instead of granting it the roles the install of --args needs, e.g. to run other commands than install")
+	return c
+}
+
+func generateBootstrapJob(bc *bootstrapJobConfig) error {
+	data := map[string]interface{}{
+		"Namespace":    bc.Namespace,
+		"Image":        bc.Image,
+		"Args":         bc.Args,
+		"ClusterAdmin": bc.ClusterAdmin,
+	}
+	if !bc.ClusterAdmin {
+		rbac, err := bootstrapInstallerRBAC(bc)
+		if err != nil {
+			return err
+		}
+		data["RBAC"] = rbac
+	}
+	return renderTemplate("templates/installer/bootstrap-job.yaml.tmpl", data)
+}
+
+// bootstrapInstallerRBAC returns the manifests of the roles the installer
+// service account needs to run the install of bc.Args.
+func bootstrapInstallerRBAC(bc *bootstrapJobConfig) (string, error) {
+	if len(bc.Args) == 0 || bc.Args[0] != "install" {
+		return "", fmt.Errorf("the roles of the installer are computed for --args install only, use --cluster-admin to run %q", strings.Join(bc.Args, " "))
+	}
+	ic := newInstallConfig()
+	c := newServiceCatalogInstallCmd(ic)
+	if err := c.ParseFlags(bc.Args[1:]); err != nil {
+		return "", fmt.Errorf("error parsing --args: %v", err)
+	}
+	if err := applyProfile(ic, c.Flags()); err != nil {
+		return "", err
+	}
+	if err := applyNameAffixes(ic); err != nil {
+		return "", err
+	}
+	objs, err := installerRBACObjects(ic, &installerRBACConfig{Namespace: bc.Namespace})
+	if err != nil {
+		return "", err
+	}
+	var docs []string
+	for _, o := range objs {
+		b, err := yaml.Marshal(o)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, string(b))
+	}
+	return strings.TrimSuffix(strings.Join(docs, "---\n"), "\n"), nil
+}
+
+// renderTemplate renders the template src as text to stdout.
+func renderTemplate(src string, data map[string]interface{}) error {
+	b, err := Asset(src)
+	if err != nil {
+		return err
+	}
+	tp, err := template.New("").Parse(string(b))
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := tp.Execute(&out, data); err != nil {
+		return fmt.Errorf("error rendering %s: %v", src, err)
+	}
+	_, err = out.WriteTo(os.Stdout)
+	return err
+}
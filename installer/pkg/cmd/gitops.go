@@ -0,0 +1,530 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+// GitOps tools sc generates the syncing resources of.
+const (
+	gitOpsToolNone   = "none"
+	gitOpsToolArgoCD = "argocd"
+	gitOpsToolFlux   = "flux"
+)
+
+var gitOpsTools = []string{gitOpsToolNone, gitOpsToolArgoCD, gitOpsToolFlux}
+
+// gitOpsAppNamespaces are the default namespaces of the syncing resources
+// of the tools.
+var gitOpsAppNamespaces = map[string]string{
+	gitOpsToolArgoCD: "argocd",
+	gitOpsToolFlux:   "flux-system",
+}
+
+// gitOpsConfig configures the handoff of an install to a GitOps tool: the
+// install renders the manifests into a Git repository, or a directory, and
+// exits, leaving the apply to the tool.
+type gitOpsConfig struct {
+	// Repo is the URL of the Git repository the manifests are pushed to,
+	// and the tool syncs them from.
+	Repo string
+
+	// Branch is the branch of the manifests.
+	Branch string
+
+	// Dir is a checkout of Repo, or any directory, the manifests are
+	// written to instead of pushing them.
+	Dir string
+
+	// Path is the directory of the manifests in Repo or Dir, replaced by
+	// every handoff.
+	Path string
+
+	// Tool is the GitOps tool to generate the syncing resources of, an
+	// Argo CD Application or Flux Kustomization, or none.
+	Tool string
+
+	// AppNamespace is the namespace of the syncing resources, that of the
+	// tool if empty.
+	AppNamespace string
+
+	// IncludeSecrets commits the Secrets, holding private keys, with the
+	// other manifests. Otherwise they are left in the workspace, to be
+	// applied, or sealed, separately.
+	IncludeSecrets bool
+}
+
+// addGitOpsFlags adds the flags of the GitOps handoff to c.
+func addGitOpsFlags(c *cobra.Command, g *gitOpsConfig) {
+	c.Flags().StringVar(&g.Repo, "gitops-repo", "", "Git repository to commit and push the manifests to instead of applying them, leaving the apply to a GitOps tool")
+	c.Flags().StringVar(&g.Branch, "gitops-branch", "main", "Branch of the --gitops-repo manifests")
+	c.Flags().StringVar(&g.Dir, "gitops-dir", "", "Directory, e.g. a checkout of --gitops-repo, to write the manifests to instead of applying them, without committing them")
+	c.Flags().StringVar(&g.Path, "gitops-path", "service-catalog", "Directory of the manifests in the repository or --gitops-dir, replaced by every install")
+	c.Flags().StringVar(&g.Tool, "gitops-tool", gitOpsToolNone, "GitOps tool to generate the resource syncing the manifests of: "+strings.Join(gitOpsTools, ", ")+". Requires --gitops-repo")
+	c.Flags().StringVar(&g.AppNamespace, "gitops-app-namespace", "", "Namespace of the Application or Kustomization, argocd or flux-system by default")
+	c.Flags().BoolVar(&g.IncludeSecrets, "gitops-include-secrets", false, "Write the Secrets, e.g. the private key of the API server certificate, with the other manifests instead of leaving them in the workspace to apply or seal separately")
+}
+
+func (g gitOpsConfig) isSet() bool {
+	return g.Repo != "" || g.Dir != ""
+}
+
+// validate returns the problems of g.
+func (g gitOpsConfig) validate() []string {
+	var problems []string
+	if !containsString(gitOpsTools, g.Tool) {
+		problems = append(problems, fmt.Sprintf("--gitops-tool must be one of %s, got %q", strings.Join(gitOpsTools, ", "), g.Tool))
+	}
+	if g.Tool != gitOpsToolNone && g.Repo == "" {
+		problems = append(problems, "--gitops-tool requires --gitops-repo, the repository the tool syncs")
+	}
+	if g.Repo != "" && g.Branch == "" {
+		problems = append(problems, "--gitops-branch must not be empty")
+	}
+	// The directory is replaced, it must be one below the root.
+	if p := filepath.Clean(g.Path); g.Path == "" || filepath.IsAbs(g.Path) || p == "." || p == ".." || strings.HasPrefix(p, ".."+string(filepath.Separator)) {
+		problems = append(problems, fmt.Sprintf("--gitops-path must be a directory in the repository, got %q", g.Path))
+	}
+	return problems
+}
+
+// handOffGitOps writes the manifests of the install of ic rendered in dir,
+// its workspace, to the GitOps repository or directory of ic, pushing them
+// to the repository unless a directory was given.
+func handOffGitOps(dir string, ic *InstallConfig) error {
+	g := ic.GitOps
+	root := g.Dir
+	if root == "" {
+		if err := checkCommands("git"); err != nil {
+			return err
+		}
+		root = filepath.Join(dir, "gitops")
+		if output, err := execx.Command("git", "clone", "--depth", "1", "--branch", g.Branch, g.Repo, root).CombinedOutput(); err != nil {
+			return fmt.Errorf("error cloning %s: %s", g.Repo, strings.TrimSpace(string(output)))
+		}
+	}
+
+	path := filepath.Clean(g.Path)
+	waves, secrets, err := writeGitOpsManifests(dir, filepath.Join(root, path), g.Tool, g.IncludeSecrets)
+	if err != nil {
+		return err
+	}
+	if g.Tool != gitOpsToolNone {
+		app := filepath.Join(root, path+"-"+g.Tool+".yaml")
+		if err := generateFileFromTmpl(app, "templates/installer/gitops-"+g.Tool+".yaml.tmpl", gitOpsAppData(g, ic.Namespace, waves)); err != nil {
+			return err
+		}
+		fmt.Printf("apply %s of the repository once to let %s sync the manifests\n", filepath.ToSlash(path)+"-"+g.Tool+".yaml", gitOpsToolName(g.Tool))
+	}
+	if g.IncludeSecrets {
+		fmt.Println("WARNING: the manifests hold the private key of the api server certificate in a Secret, restrict the access to the repository")
+	} else if len(secrets) > 0 {
+		sd := filepath.Join(dir, gitOpsSecretsDir)
+		if err := os.Mkdir(sd, 0700); err != nil {
+			return err
+		}
+		if err := writeObjects(sd, groupByFile(secrets), ""); err != nil {
+			return err
+		}
+		fmt.Printf("left the Secrets holding private keys out of the manifests, apply them with `kubectl apply -k %s`, or seal them, e.g. with Sealed Secrets, and commit the sealed ones\n", sd)
+	}
+
+	if g.Dir != "" {
+		fmt.Printf("wrote the manifests to %s\n", filepath.Join(g.Dir, path))
+		return nil
+	}
+	return pushGitOps(root, ic)
+}
+
+// gitOpsSecretsDir is the directory of the workspace the Secrets left out
+// of the GitOps manifests are written to.
+const gitOpsSecretsDir = "gitops-secrets"
+
+// argoCDSyncWaveAnnotation orders the objects Argo CD syncs, lower waves
+// first, each wave healthy before the next.
+const argoCDSyncWaveAnnotation = "argocd.argoproj.io/sync-wave"
+
+// fluxWave is the Flux Kustomization of a sync wave.
+type fluxWave struct {
+	Name      string
+	Path      string
+	DependsOn string
+}
+
+// writeGitOpsManifests replaces the manifests in out with those rendered
+// in dir, listed in a kustomization.yaml, and returns
+// their sync waves, the creation priorities of their kinds, e.g. the
+// APIService before the brokers it serves. For Argo CD, each object is
+// annotated with its wave; for Flux, the objects of each wave are moved
+// to the wave-<n> subdirectory, synced by a Kustomization of its own
+// depending on the one of the previous wave. Unless secrets is set, the
+// Secrets are left out and returned.
+func writeGitOpsManifests(dir, out, tool string, secrets bool) ([]int, []*manifest.Object, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, nil, err
+	}
+	raw := make(map[string][]byte)
+	var objs []*manifest.Object
+	for _, n := range names {
+		base := filepath.Base(n)
+		if base == "kustomization.yaml" {
+			continue
+		}
+		b, err := ioutil.ReadFile(n)
+		if err != nil {
+			return nil, nil, err
+		}
+		o, err := manifest.Parse(base, b)
+		if err != nil {
+			return nil, nil, err
+		}
+		raw[base] = b
+		objs = append(objs, o...)
+	}
+	var left []*manifest.Object
+	if !secrets {
+		kept := objs[:0]
+		for _, o := range objs {
+			if o.Kind == "Secret" {
+				left = append(left, o)
+				// The file is written from its remaining objects.
+				delete(raw, o.File)
+				continue
+			}
+			kept = append(kept, o)
+		}
+		objs = kept
+	}
+	if len(objs) == 0 {
+		return nil, nil, fmt.Errorf("no manifests found in %s", dir)
+	}
+
+	// Components no longer installed are pruned by the tools.
+	if err := os.RemoveAll(out); err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return nil, nil, err
+	}
+
+	// files are the objects of each file of each wave.
+	files := make(map[int]map[string][]*manifest.Object)
+	var waves []int
+	for _, o := range objs {
+		w := manifest.Priority(o.Kind)
+		if files[w] == nil {
+			files[w] = make(map[string][]*manifest.Object)
+			waves = append(waves, w)
+		}
+		files[w][o.File] = append(files[w][o.File], o)
+	}
+	sort.Ints(waves)
+
+	switch tool {
+	case gitOpsToolFlux:
+		var dirs []string
+		for _, w := range waves {
+			wd := fmt.Sprintf("wave-%d", w)
+			if err := os.Mkdir(filepath.Join(out, wd), 0755); err != nil {
+				return nil, nil, err
+			}
+			if err := writeObjects(filepath.Join(out, wd), files[w], ""); err != nil {
+				return nil, nil, err
+			}
+			dirs = append(dirs, wd)
+		}
+		return waves, left, writeKustomization(out, dirs)
+	case gitOpsToolArgoCD:
+		return waves, left, writeObjects(out, groupByFile(objs), argoCDSyncWaveAnnotation)
+	}
+	// The files are copied as rendered, but those Secrets were left out of.
+	rewritten := make(map[string][]*manifest.Object)
+	for _, o := range objs {
+		if _, ok := raw[o.File]; !ok {
+			rewritten[o.File] = append(rewritten[o.File], o)
+		}
+	}
+	if err := writeObjects(out, rewritten, ""); err != nil {
+		return nil, nil, err
+	}
+	var resources []string
+	for base := range rewritten {
+		resources = append(resources, base)
+	}
+	for base, b := range raw {
+		if err := ioutil.WriteFile(filepath.Join(out, base), b, 0644); err != nil {
+			return nil, nil, err
+		}
+		resources = append(resources, base)
+	}
+	return waves, left, writeKustomization(out, resources)
+}
+
+// groupByFile returns objs by the file they were read from.
+func groupByFile(objs []*manifest.Object) map[string][]*manifest.Object {
+	byFile := make(map[string][]*manifest.Object)
+	for _, o := range objs {
+		byFile[o.File] = append(byFile[o.File], o)
+	}
+	return byFile
+}
+
+// writeObjects writes the objects of each file of byFile to the file in
+// dir, annotated with their sync wave if annotation is set, and lists the
+// files in a kustomization.yaml.
+func writeObjects(dir string, byFile map[string][]*manifest.Object, annotation string) error {
+	var resources []string
+	for file, objs := range byFile {
+		var docs []string
+		for _, o := range objs {
+			j := o.JSON
+			if annotation != "" {
+				var err error
+				if j, err = annotate(j, annotation, strconv.Itoa(manifest.Priority(o.Kind))); err != nil {
+					return fmt.Errorf("error annotating %s: %v", o, err)
+				}
+			}
+			y, err := yaml.JSONToYAML(j)
+			if err != nil {
+				return fmt.Errorf("error writing %s: %v", o, err)
+			}
+			docs = append(docs, string(y))
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(strings.Join(docs, "---\n")), 0644); err != nil {
+			return err
+		}
+		resources = append(resources, file)
+	}
+	return writeKustomization(dir, resources)
+}
+
+// annotate returns the object j with annotation key set to value.
+func annotate(j []byte, key, value string) ([]byte, error) {
+	var o map[string]interface{}
+	if err := json.Unmarshal(j, &o); err != nil {
+		return nil, err
+	}
+	metadata, _ := o["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+		o["metadata"] = metadata
+	}
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	if annotations == nil {
+		annotations = make(map[string]interface{})
+		metadata["annotations"] = annotations
+	}
+	annotations[key] = value
+	return json.Marshal(o)
+}
+
+// writeKustomization writes the kustomization.yaml of dir listing
+// resources.
+func writeKustomization(dir string, resources []string) error {
+	sort.Strings(resources)
+	k := "# Generated by sc, do not edit.\napiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n"
+	for _, r := range resources {
+		k += "- " + r + "\n"
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(k), 0644)
+}
+
+// gitOpsAppData returns the data of the template of the resources of g
+// syncing the manifests of the catalog namespace ns in the sync waves.
+func gitOpsAppData(g gitOpsConfig, ns string, waves []int) map[string]interface{} {
+	appNamespace := g.AppNamespace
+	if appNamespace == "" {
+		appNamespace = gitOpsAppNamespaces[g.Tool]
+	}
+	path := filepath.ToSlash(filepath.Clean(g.Path))
+	var fluxWaves []fluxWave
+	for i, w := range waves {
+		fw := fluxWave{Name: fmt.Sprintf("%s-wave-%d", ns, w), Path: fmt.Sprintf("%s/wave-%d", path, w)}
+		if i > 0 {
+			fw.DependsOn = fluxWaves[i-1].Name
+		}
+		fluxWaves = append(fluxWaves, fw)
+	}
+	return map[string]interface{}{
+		"Name":         ns,
+		"AppNamespace": appNamespace,
+		"Repo":         g.Repo,
+		"Branch":       g.Branch,
+		"Path":         path,
+		"Namespace":    ns,
+		"Waves":        fluxWaves,
+	}
+}
+
+// The identity of the handoff commits where git has none configured.
+const (
+	gitOpsCommitterName  = "sc"
+	gitOpsCommitterEmail = "sc@localhost"
+)
+
+// pushGitOps commits the changes in root, the clone of the GitOps
+// repository of ic, and pushes them.
+func pushGitOps(root string, ic *InstallConfig) error {
+	g := ic.GitOps
+	var identity []string
+	git := func(args ...string) (string, error) {
+		output, err := execx.Command("git", append(append([]string{"-C", root}, identity...), args...)...).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("error running git %s: %s", args[0], strings.TrimSpace(string(output)))
+		}
+		return strings.TrimSpace(string(output)), nil
+	}
+	// Commits need an identity, and not every machine running sc, e.g. a
+	// CI runner, has one configured.
+	if email, _ := git("config", "user.email"); email == "" {
+		identity = []string{"-c", "user.name=" + gitOpsCommitterName, "-c", "user.email=" + gitOpsCommitterEmail}
+	}
+	if _, err := git("add", "-A"); err != nil {
+		return err
+	}
+	status, err := git("status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if status == "" {
+		fmt.Printf("the manifests in %s branch %s are up to date\n", g.Repo, g.Branch)
+		return nil
+	}
+	if _, err := git("commit", "-m", fmt.Sprintf("Install Service Catalog in namespace %s", ic.Namespace)); err != nil {
+		return err
+	}
+	if _, err := git("push", "origin", "HEAD:"+g.Branch); err != nil {
+		return err
+	}
+	commit, err := git("rev-parse", "--short", "HEAD")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("pushed the manifests to %s branch %s at %s\n", g.Repo, g.Branch, commit)
+	return nil
+}
+
+// gitOpsToolName returns the name of tool for humans.
+func gitOpsToolName(tool string) string {
+	if tool == gitOpsToolFlux {
+		return "Flux"
+	}
+	return "Argo CD"
+}
+
+// gitOpsGenerateConfig contains the generate gitops configuration.
+type gitOpsGenerateConfig struct {
+	gitOpsConfig
+
+	// Namespace is the catalog namespace of the manifests.
+	Namespace string
+
+	// ManifestsDir is the directory of the rendered manifests, which is
+	// left as is.
+	ManifestsDir string
+
+	// OutputDir is the directory the manifests are written to, ordered
+	// in sync waves, replaced by every run.
+	OutputDir string
+}
+
+func newGenerateGitOpsCmd() *cobra.Command {
+	gc := &gitOpsGenerateConfig{}
+	c := &cobra.Command{
+		Use:   "gitops",
+		Short: "generates the Argo CD Application or Flux Kustomizations syncing the manifests",
+		Long: `generates the Argo CD Application, or Flux GitRepository and Kustomizations,
+syncing the rendered manifests in --manifests-dir, e.g. those written by
+install --dry-run, from --path of the Git repository, and prints them.
+The manifests are written to --output-dir, the checkout of --path, in
+sync waves, the order the installer applies them in, e.g. the APIService
+before the brokers it serves: for Argo CD each object is annotated with
+its wave, for Flux the objects of each wave are written to a wave-<n>
+directory synced by a Kustomization depending on the previous one. The
+Secrets, holding private keys, are left out unless --include-secrets is
+set, to apply or seal separately. --manifests-dir is not modified.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return generateGitOps(gc)
+		},
+	}
+	c.Flags().StringVar(&gc.Tool, "tool", "", "GitOps tool: argocd or flux")
+	c.Flags().StringVar(&gc.Repo, "repo", "", "Git repository the tool syncs the manifests from")
+	c.Flags().StringVar(&gc.Branch, "branch", "main", "Branch of the manifests")
+	c.Flags().StringVar(&gc.Path, "path", "service-catalog", "Directory of the manifests in the repository")
+	c.Flags().StringVar(&gc.AppNamespace, "app-namespace", "", "Namespace of the Application or Kustomizations, argocd or flux-system by default")
+	c.Flags().StringVar(&gc.Namespace, "namespace", "service-catalog", "Namespace Service Catalog is installed in")
+	c.Flags().StringVar(&gc.ManifestsDir, "manifests-dir", "", "Directory of the rendered manifests")
+	c.Flags().StringVar(&gc.OutputDir, "output-dir", "", "Directory, e.g. the checkout of --path, to write the manifests ordered in sync waves to, replaced by every run")
+	c.Flags().BoolVar(&gc.IncludeSecrets, "include-secrets", false, "Write the Secrets, e.g. the private key of the API server certificate, to --output-dir with the other manifests")
+	return c
+}
+
+func generateGitOps(gc *gitOpsGenerateConfig) error {
+	if gc.Tool != gitOpsToolArgoCD && gc.Tool != gitOpsToolFlux {
+		return fmt.Errorf("--tool must be %s or %s, got %q", gitOpsToolArgoCD, gitOpsToolFlux, gc.Tool)
+	}
+	if gc.Repo == "" {
+		return fmt.Errorf("--repo is required")
+	}
+	if gc.ManifestsDir == "" {
+		return fmt.Errorf("--manifests-dir is required")
+	}
+	if gc.OutputDir == "" {
+		return fmt.Errorf("--output-dir is required")
+	}
+	// The output directory is replaced, it must not hold the manifests.
+	if rel, err := filepath.Rel(gc.OutputDir, gc.ManifestsDir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("--output-dir %s must not contain --manifests-dir %s", gc.OutputDir, gc.ManifestsDir)
+	}
+	if problems := gc.validate(); len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	waves, secrets, err := writeGitOpsManifests(gc.ManifestsDir, gc.OutputDir, gc.Tool, gc.IncludeSecrets)
+	if err != nil {
+		return err
+	}
+	// The resources are printed to stdout, the notes go to stderr.
+	if gc.IncludeSecrets {
+		fmt.Fprintf(os.Stderr, "WARNING: %s holds the private key of the api server certificate in a Secret, restrict the access to the repository\n", gc.OutputDir)
+	} else if len(secrets) > 0 {
+		// Like the workspace of an install, the directory is only
+		// readable by the user.
+		sd, err := ioutil.TempDir("", gitOpsSecretsDir)
+		if err != nil {
+			return err
+		}
+		if err := writeObjects(sd, groupByFile(secrets), ""); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "left the Secrets holding private keys out of %s, apply them with `kubectl apply -k %s`, or seal them, e.g. with Sealed Secrets, and commit the sealed ones\n", gc.OutputDir, sd)
+	}
+	return renderTemplate("templates/installer/gitops-"+gc.Tool+".yaml.tmpl", gitOpsAppData(gc.gitOpsConfig, gc.Namespace, waves))
+}
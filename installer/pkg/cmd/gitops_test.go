@@ -0,0 +1,310 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// gitOpsInstallConfig returns an install handing off to g.
+func gitOpsInstallConfig(g gitOpsConfig) *InstallConfig {
+	ic := validInstallConfig()
+	ic.GitOps = g
+	return ic
+}
+
+// TestGitOpsValidate tests that the handoff requires a repository for the
+// syncing resources, a directory below the root and no dry run.
+func TestGitOpsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		g      gitOpsConfig
+		dryRun string
+		valid  bool
+	}{
+		{gitOpsConfig{Repo: "https://git.example.com/infra", Branch: "main", Path: "clusters/prod/catalog", Tool: gitOpsToolArgoCD}, dryRunNone, true},
+		{gitOpsConfig{Dir: "/tmp/infra", Path: "catalog", Tool: gitOpsToolNone}, dryRunNone, true},
+		{gitOpsConfig{Dir: "/tmp/infra", Path: "catalog", Tool: gitOpsToolFlux}, dryRunNone, false},
+		{gitOpsConfig{Repo: "https://git.example.com/infra", Branch: "main", Path: "catalog", Tool: "jenkins"}, dryRunNone, false},
+		{gitOpsConfig{Repo: "https://git.example.com/infra", Branch: "", Path: "catalog", Tool: gitOpsToolNone}, dryRunNone, false},
+		{gitOpsConfig{Dir: "/tmp/infra", Path: ".", Tool: gitOpsToolNone}, dryRunNone, false},
+		{gitOpsConfig{Dir: "/tmp/infra", Path: "../etc", Tool: gitOpsToolNone}, dryRunNone, false},
+		{gitOpsConfig{Dir: "/tmp/infra", Path: "/etc", Tool: gitOpsToolNone}, dryRunNone, false},
+		{gitOpsConfig{Dir: "/tmp/infra", Path: "catalog", Tool: gitOpsToolNone}, dryRunClient, false},
+	} {
+		ic := gitOpsInstallConfig(tc.g)
+		ic.DryRun = tc.dryRun
+		if err := ic.Validate(); (err == nil) != tc.valid {
+			t.Fatalf("%+v, dry run %s: expected valid %v, got %v", tc.g, tc.dryRun, tc.valid, err)
+		}
+	}
+}
+
+// gitOpsManifests are rendered manifests of objects of several waves.
+var gitOpsManifests = map[string]string{
+	"namespace.yaml":                "# Namespace\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: service-catalog\n",
+	"apiserver-deployment.yaml":     "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: apiserver\n  namespace: service-catalog\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: apiserver\n  namespace: service-catalog\n",
+	"api-registration.yaml":         "apiVersion: apiregistration.k8s.io/v1\nkind: APIService\nmetadata:\n  name: v1beta1.servicecatalog.k8s.io\n  annotations:\n    owner: sc\n",
+	"ca.pem":                        "certificate",
+	"skipped/api-registration.yaml": "skipped",
+}
+
+// writeManifests writes the files of contents in a new temp dir.
+func writeManifests(t *testing.T, contents map[string]string) string {
+	dir, err := ioutil.TempDir("", "gitops")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	for n, c := range contents {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, n)), 0755); err != nil {
+			t.Fatalf("Unexpected error creating dir: %v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, n), []byte(c), 0644); err != nil {
+			t.Fatalf("Unexpected error writing %s: %v", n, err)
+		}
+	}
+	return dir
+}
+
+func readFile(t *testing.T, path string) string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading %s: %v", path, err)
+	}
+	return string(b)
+}
+
+func listDir(t *testing.T, dir string) []string {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error reading %s: %v", dir, err)
+	}
+	var names []string
+	for _, fi := range fis {
+		names = append(names, fi.Name())
+	}
+	return names
+}
+
+// TestHandOffGitOpsDir tests that the manifests replace those of the path
+// in the directory, annotated with their sync waves and listed in a
+// kustomization.yaml, without the certificates of the workspace, next to
+// the Argo CD Application.
+func TestHandOffGitOpsDir(t *testing.T) {
+	ws := writeManifests(t, gitOpsManifests)
+	defer os.RemoveAll(ws)
+	repo := writeManifests(t, map[string]string{"clusters/prod/catalog/etcd.yaml": "stale", "README.md": "infra"})
+	defer os.RemoveAll(repo)
+
+	ic := gitOpsInstallConfig(gitOpsConfig{Repo: "https://git.example.com/infra", Branch: "prod", Dir: repo, Path: "clusters/prod/catalog", Tool: gitOpsToolArgoCD})
+	if err := handOffGitOps(ws, ic); err != nil {
+		t.Fatalf("Unexpected error handing off: %v", err)
+	}
+
+	out := filepath.Join(repo, "clusters", "prod", "catalog")
+	if got, want := listDir(t, out), []string{"api-registration.yaml", "apiserver-deployment.yaml", "kustomization.yaml", "namespace.yaml"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Manifests do not match: got %v; want %v", got, want)
+	}
+	if k := readFile(t, filepath.Join(out, "kustomization.yaml")); !strings.Contains(k, "resources:\n- api-registration.yaml\n- apiserver-deployment.yaml\n- namespace.yaml\n") {
+		t.Fatalf("Unexpected kustomization.yaml:\n%s", k)
+	}
+	for file, waves := range map[string][]string{
+		"namespace.yaml":            {"0"},
+		"apiserver-deployment.yaml": {"5", "4"},
+		"api-registration.yaml":     {"6"},
+	} {
+		docs := strings.Split(readFile(t, filepath.Join(out, file)), "---\n")
+		if len(docs) != len(waves) {
+			t.Fatalf("%s: expected %d objects, got %d", file, len(waves), len(docs))
+		}
+		for i, w := range waves {
+			if want := argoCDSyncWaveAnnotation + `: "` + w + `"`; !strings.Contains(docs[i], want) {
+				t.Fatalf("%s: expected object %d to be annotated with %s, got\n%s", file, i, want, docs[i])
+			}
+		}
+	}
+	if m := readFile(t, filepath.Join(out, "api-registration.yaml")); !strings.Contains(m, "owner: sc") {
+		t.Fatalf("Expected the annotations of the object to be kept, got\n%s", m)
+	}
+	if got, want := listDir(t, repo), []string{"README.md", "clusters"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected the rest of the directory to be kept, got %v", got)
+	}
+	app, err := ioutil.ReadFile(filepath.Join(repo, "clusters", "prod", "catalog-argocd.yaml"))
+	if err != nil {
+		t.Fatalf("Unexpected error reading the Application: %v", err)
+	}
+	for _, want := range []string{"kind: Application", "namespace: argocd", "repoURL: https://git.example.com/infra", "targetRevision: prod", "path: clusters/prod/catalog", "namespace: service-catalog"} {
+		if !strings.Contains(string(app), want) {
+			t.Fatalf("Expected the Application to contain %q, got\n%s", want, app)
+		}
+	}
+}
+
+// TestHandOffGitOpsSecrets tests that the Secrets are left in the
+// workspace unless included, keeping the other objects of their files.
+func TestHandOffGitOpsSecrets(t *testing.T) {
+	manifests := map[string]string{
+		"namespace.yaml": "# Namespace\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: service-catalog\n",
+		"apiserver-certs.yaml": "apiVersion: v1\nkind: Secret\nmetadata:\n  name: apiserver-cert\n  namespace: service-catalog\ndata:\n  tls.key: a2V5\n" +
+			"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: apiserver-ca\n  namespace: service-catalog\n",
+	}
+	for _, include := range []bool{false, true} {
+		ws := writeManifests(t, manifests)
+		defer os.RemoveAll(ws)
+		repo := writeManifests(t, nil)
+		defer os.RemoveAll(repo)
+
+		ic := gitOpsInstallConfig(gitOpsConfig{Dir: repo, Path: "catalog", Tool: gitOpsToolNone, IncludeSecrets: include})
+		if err := handOffGitOps(ws, ic); err != nil {
+			t.Fatalf("Unexpected error handing off: %v", err)
+		}
+		certs := readFile(t, filepath.Join(repo, "catalog", "apiserver-certs.yaml"))
+		if !strings.Contains(certs, "kind: ConfigMap") || strings.Contains(certs, "kind: Secret") == !include {
+			t.Fatalf("include %v: unexpected manifest\n%s", include, certs)
+		}
+		if ns := readFile(t, filepath.Join(repo, "catalog", "namespace.yaml")); ns != manifests["namespace.yaml"] {
+			t.Fatalf("Expected the manifest without Secrets to be copied, got\n%s", ns)
+		}
+		_, err := os.Stat(filepath.Join(ws, gitOpsSecretsDir, "apiserver-certs.yaml"))
+		if include != os.IsNotExist(err) {
+			t.Fatalf("include %v: unexpected Secrets in the workspace: %v", include, err)
+		}
+		if !include {
+			if s := readFile(t, filepath.Join(ws, gitOpsSecretsDir, "apiserver-certs.yaml")); !strings.Contains(s, "tls.key") || strings.Contains(s, "ConfigMap") {
+				t.Fatalf("Unexpected Secrets left in the workspace\n%s", s)
+			}
+		}
+	}
+}
+
+// TestHandOffGitOpsRepo tests that the manifests are committed to a clone
+// of the branch and pushed, in a directory per sync wave, next to the Flux
+// resources.
+func TestHandOffGitOpsRepo(t *testing.T) {
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		if containsString(args, "status") {
+			return execx.Response{Stdout: " M catalog/apiserver-deployment.yaml\n"}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+	ws := writeManifests(t, gitOpsManifests)
+	defer os.RemoveAll(ws)
+
+	ic := gitOpsInstallConfig(gitOpsConfig{Repo: "git@git.example.com:infra.git", Branch: "main", Path: "catalog", Tool: gitOpsToolFlux, AppNamespace: "gitops"})
+	if err := handOffGitOps(ws, ic); err != nil {
+		t.Fatalf("Unexpected error handing off: %v", err)
+	}
+
+	clone := filepath.Join(ws, "gitops")
+	var calls []string
+	for _, c := range s.Calls() {
+		calls = append(calls, strings.Replace(strings.Join(c.Args, " "), clone, "CLONE", -1))
+	}
+	want := []string{
+		"clone --depth 1 --branch main git@git.example.com:infra.git CLONE",
+		"-C CLONE config user.email",
+		"-C CLONE -c user.name=sc -c user.email=sc@localhost add -A",
+		"-C CLONE -c user.name=sc -c user.email=sc@localhost status --porcelain",
+		"-C CLONE -c user.name=sc -c user.email=sc@localhost commit -m Install Service Catalog in namespace service-catalog",
+		"-C CLONE -c user.name=sc -c user.email=sc@localhost push origin HEAD:main",
+		"-C CLONE -c user.name=sc -c user.email=sc@localhost rev-parse --short HEAD",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("git calls do not match: got\n%s\nwant\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+	out := filepath.Join(clone, "catalog")
+	if got, want := listDir(t, out), []string{"kustomization.yaml", "wave-0", "wave-4", "wave-5", "wave-6"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Wave directories do not match: got %v; want %v", got, want)
+	}
+	if got, want := listDir(t, filepath.Join(out, "wave-4")), []string{"apiserver-deployment.yaml", "kustomization.yaml"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Manifests of wave 4 do not match: got %v; want %v", got, want)
+	}
+	if m := readFile(t, filepath.Join(out, "wave-4", "apiserver-deployment.yaml")); !strings.Contains(m, "kind: Service") || strings.Contains(m, "kind: Deployment") {
+		t.Fatalf("Expected only the Service in wave 4, got\n%s", m)
+	}
+
+	flux := readFile(t, filepath.Join(clone, "catalog-flux.yaml"))
+	for _, want := range []string{
+		"kind: GitRepository", "url: git@git.example.com:infra.git", "namespace: gitops",
+		"name: service-catalog-wave-0\n", "path: ./catalog/wave-0\n  prune: true",
+		"name: service-catalog-wave-6\n", "path: ./catalog/wave-6\n  dependsOn:\n  - name: service-catalog-wave-5\n",
+		"wait: true",
+	} {
+		if !strings.Contains(flux, want) {
+			t.Fatalf("Expected the Flux resources to contain %q, got\n%s", want, flux)
+		}
+	}
+}
+
+// TestGenerateGitOps tests that the manifests are written to the output
+// directory in sync waves, without the Secrets unless included, leaving
+// the rendered manifests as they are.
+func TestGenerateGitOps(t *testing.T) {
+	manifests := map[string]string{
+		"apiserver-certs.yaml": "apiVersion: v1\nkind: Secret\nmetadata:\n  name: apiserver-cert\n  namespace: service-catalog\ndata:\n  tls.key: a2V5\n",
+	}
+	for n, c := range gitOpsManifests {
+		manifests[n] = c
+	}
+	dir := writeManifests(t, manifests)
+	defer os.RemoveAll(dir)
+	out := writeManifests(t, map[string]string{"stale.yaml": "stale"})
+	defer os.RemoveAll(out)
+
+	gc := &gitOpsGenerateConfig{
+		gitOpsConfig: gitOpsConfig{Tool: gitOpsToolFlux, Repo: "https://git.example.com/infra", Branch: "main", Path: "catalog"},
+		Namespace:    "service-catalog",
+		ManifestsDir: dir,
+		OutputDir:    out,
+	}
+	for _, include := range []bool{false, true} {
+		gc.IncludeSecrets = include
+		if err := generateGitOps(gc); err != nil {
+			t.Fatalf("Unexpected error generating: %v", err)
+		}
+		if got, want := listDir(t, out), []string{"kustomization.yaml", "wave-0", "wave-3", "wave-4", "wave-5", "wave-6"}; include && !reflect.DeepEqual(got, want) {
+			t.Fatalf("Output directory does not match: got %v; want %v", got, want)
+		}
+		if got, want := listDir(t, out), []string{"kustomization.yaml", "wave-0", "wave-4", "wave-5", "wave-6"}; !include && !reflect.DeepEqual(got, want) {
+			t.Fatalf("Output directory does not match: got %v; want %v", got, want)
+		}
+		for n, c := range manifests {
+			if got := readFile(t, filepath.Join(dir, n)); got != c {
+				t.Fatalf("Manifest %s was modified: got\n%s", n, got)
+			}
+		}
+	}
+
+	for _, bad := range []string{dir, filepath.Dir(dir)} {
+		gc.OutputDir = bad
+		if err := generateGitOps(gc); err == nil || !strings.Contains(err.Error(), "must not contain --manifests-dir") {
+			t.Fatalf("Expected an error writing to %s, got %v", bad, err)
+		}
+	}
+	gc.OutputDir = out
+	gc.Tool = "helm"
+	if err := generateGitOps(gc); err == nil {
+		t.Fatalf("Expected an error for an unknown tool")
+	}
+}
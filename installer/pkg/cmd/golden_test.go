@@ -0,0 +1,328 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/version"
+)
+
+// Run go test ./pkg/cmd -run TestGolden -update to regenerate the golden
+// files after changing the templates, and review the diff.
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// goldenKubernetesVersion is the cluster version reported by the fake
+// kubectl of the golden tests, which selects the API versions.
+const goldenKubernetesVersion = "v1.23.0"
+
+// goldenCerts are placeholders for the generated certificates and keys.
+var goldenCerts = map[string]string{
+	"CAPublicKey":          "Q0EgQ0VSVElGSUNBVEU=",
+	"CAPrivateKey":         "",
+	"APIServicePublicKey":  "QVBJIFNFUlZFUiBDRVJUSUZJQ0FURQ==",
+	"APIServicePrivateKey": "QVBJIFNFUlZFUiBLRVk=",
+}
+
+// goldenConfigs are representative install configurations, by golden file
+// name.
+var goldenConfigs = map[string]func(ic *InstallConfig){
+	"default": func(ic *InstallConfig) {},
+	"minimal": func(ic *InstallConfig) {
+		ic.EtcdClusterSize = 1
+		ic.EtcdBackup = false
+	},
+	"production": func(ic *InstallConfig) {
+		ic.EtcdClusterSize = 5
+		ic.APIServerReplicas = 2
+		ic.ControllerManagerReplicas = 2
+		ic.PodDisruptionBudgets = true
+		ic.PDBMaxUnavailable = defaultPDBMaxUnavailable
+		ic.Monitoring = true
+		ic.NetworkPolicies = true
+		ic.PriorityClass = true
+		ic.ServiceMonitor = true
+		ic.APIServerHPA = true
+		ic.APIServerMaxReplicas = defaultAPIServerMaxReplicas
+		ic.APIServerCPUUtilization = defaultAPIServerCPUUtilization
+		ic.MaxInstancesPerNamespace = 10
+		ic.SecretSync = true
+		ic.SecretSyncBackend = secretSyncVault
+		ic.SecretSyncMode = secretSyncMirror
+		ic.SecretSyncVaultPath = defaultSecretSyncVaultPath
+		ic.SecretSyncVaultAddress = "https://vault.example.com:8200"
+		ic.SecretSyncVaultRole = "service-catalog"
+		ic.SecretSyncImage = "registry.example.com/service-catalog-secret-sync:v0.1.0"
+		ic.SecretSyncNamespaces = []string{"payments", "payments-staging"}
+		ic.EtcdMaintenance = true
+		ic.EtcdMaintenanceSchedule = defaultEtcdMaintenanceSchedule
+		ic.EtcdMaintenanceBucket = "gs://sc-etcd-snapshots/production"
+		ic.EtcdMaintenanceGCPServiceAccount = "etcd-maintenance@my-project.iam.gserviceaccount.com"
+	},
+	"etcd-maintenance": func(ic *InstallConfig) {
+		ic.EtcdMaintenance = true
+		ic.EtcdMaintenanceSchedule = defaultEtcdMaintenanceSchedule
+		ic.EtcdMaintenanceKeep = defaultEtcdMaintenanceKeep
+	},
+	"istio": func(ic *InstallConfig) {
+		ic.Mesh = meshIstio
+		ic.Monitoring = true
+		ic.EtcdMaintenance = true
+		ic.EtcdMaintenanceSchedule = defaultEtcdMaintenanceSchedule
+		ic.EtcdMaintenanceKeep = defaultEtcdMaintenanceKeep
+	},
+	"broker-dns": func(ic *InstallConfig) {
+		ic.ControllerManagerHostAliases = []string{"broker.corp.example.com=10.0.0.5", "catalog.corp.example.com=10.0.0.5"}
+		ic.ControllerManagerDNSPolicy = "None"
+		ic.ControllerManagerDNSNameservers = []string{"10.0.0.2"}
+		ic.ControllerManagerDNSSearches = []string{"corp.example.com"}
+		ic.ControllerManagerDNSOptions = []string{"ndots=2", "edns0"}
+	},
+	"egress-proxy": func(ic *InstallConfig) {
+		ic.ControllerManagerProxy = "http://proxy.corp.example.com:3128"
+		ic.ControllerManagerNoProxy = []string{".corp.example.com", "10.0.0.0/8"}
+	},
+	"etcd-encryption": func(ic *InstallConfig) {
+		ic.EtcdEncryption = encryptionKMS
+		ic.EtcdEncryptionKMSKey = "projects/my-project/locations/global/keyRings/sc/cryptoKeys/etcd"
+		ic.EtcdEncryptionKeys = []encryptionKey{{Name: "key1", Secret: "c2VydmljZS1jYXRhbG9nLWV0Y2Qta2V5LTEyMzQ1Njc="}}
+	},
+	"watch-cache": func(ic *InstallConfig) {
+		ic.APIServerRequestTimeout = 3 * time.Minute
+		ic.APIServerDefaultWatchCacheSize = 500
+		ic.APIServerWatchCacheSizes = []string{"serviceinstances#5000", "servicebindings#5000"}
+	},
+	"arm64": func(ic *InstallConfig) {
+		ic.NodeArchitectures = []string{"arm64"}
+	},
+	"ipv6": func(ic *InstallConfig) {
+		ic.IPFamily = ipFamilyIPv6
+		ic.EtcdClusterSize = 1
+		ic.EtcdBackup = false
+	},
+	"skip-components": func(ic *InstallConfig) {
+		ic.SkipEtcd = true
+		ic.EtcdServers = "https://etcd.example.com:2379"
+		ic.SkipRBAC = true
+		ic.SkipAPIRegistration = true
+	},
+	"precreated-service-accounts": func(ic *InstallConfig) {
+		ic.APIServerServiceAccount = "sc-apiserver"
+		ic.ControllerManagerServiceAccount = "sc-controller-manager"
+	},
+	"catalog-v0.2": func(ic *InstallConfig) {
+		ic.Version = ""
+		ic.CatalogVersion = "v0.2"
+	},
+	"name-affixes": func(ic *InstallConfig) {
+		ic.NamePrefix = "blue-"
+		ic.NameSuffix = "-v2"
+		ic.PriorityClass = true
+		ic.Namespace = catalogNamespace(ic.NamePrefix, ic.NameSuffix)
+	},
+}
+
+// fakeKubectl puts a kubectl reporting goldenKubernetesVersion, and failing
+// otherwise, first in the PATH, so that the rendering does not depend on the
+// cluster of the developer. It returns a function restoring the PATH.
+func fakeKubectl(t *testing.T, dir string) func() {
+	script := "#!/bin/sh\n" +
+		"if [ \"$1\" = version ]; then echo '{\"serverVersion\": {\"gitVersion\": \"" + goldenKubernetesVersion + "\"}}'; exit 0; fi\n" +
+		"exit 1\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, KubectlBinaryName), []byte(script), 0755); err != nil {
+		t.Fatalf("Unexpected error writing fake kubectl: %v", err)
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir)
+	return func() { os.Setenv("PATH", path) }
+}
+
+// renderGolden returns the YAML files rendered in dir, each preceded by its
+// name, with the version of sc, which depends on the build, replaced.
+func renderGolden(t *testing.T, dir string) []byte {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && strings.HasSuffix(path, ".yaml") {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error listing %s: %v", dir, err)
+	}
+	sort.Strings(files)
+
+	var out bytes.Buffer
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatalf("Unexpected error reading %s: %v", f, err)
+		}
+		rel, _ := filepath.Rel(dir, f)
+		out.WriteString("# Source: " + filepath.ToSlash(rel) + "\n")
+		out.Write(bytes.Replace(b, []byte(version.GetVersion()), []byte("sc version <version>"), -1))
+		out.WriteString("\n")
+	}
+	return out.Bytes()
+}
+
+// checkGolden compares got with the golden file name, or updates it with
+// -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	file := filepath.Join("testdata", "golden", name+".yaml")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatalf("Unexpected error creating %s: %v", filepath.Dir(file), err)
+		}
+		if err := ioutil.WriteFile(file, got, 0644); err != nil {
+			t.Fatalf("Unexpected error writing %s: %v", file, err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Unexpected error reading golden file, run with -update to create it: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Rendered manifests do not match %s, run go test ./pkg/cmd -run TestGolden -update and review the diff: got\n%s", file, firstDiff(got, want))
+	}
+}
+
+// firstDiff returns the first differing line of got and want, with its
+// line number.
+func firstDiff(got, want []byte) string {
+	g, w := strings.Split(string(got), "\n"), strings.Split(string(want), "\n")
+	for i := 0; i < len(g) || i < len(w); i++ {
+		var gl, wl string
+		if i < len(g) {
+			gl = g[i]
+		}
+		if i < len(w) {
+			wl = w[i]
+		}
+		if gl != wl {
+			return fmt.Sprintf("line %d: %s\nwant: %s", i+1, gl, wl)
+		}
+	}
+	return ""
+}
+
+// checkObjects checks that the rendered objects are complete and, if
+// SC_GOLDEN_OPENAPI_SCHEMA names an OpenAPI v2 schema, e.g. saved with
+// kubectl get --raw /openapi/v2, that they are valid according to it.
+func checkObjects(t *testing.T, name string, objs []*manifest.Object) {
+	var schema *manifest.Schema
+	if file := os.Getenv("SC_GOLDEN_OPENAPI_SCHEMA"); file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Unexpected error reading schema: %v", err)
+		}
+		if schema, err = manifest.LoadSchema(b); err != nil {
+			t.Fatalf("Unexpected error loading schema: %v", err)
+		}
+	}
+	for _, o := range objs {
+		if o.APIVersion == "" || o.Name == "" {
+			t.Fatalf("%s: object %s of %s has no apiVersion or name", name, o, o.File)
+		}
+		if !o.ClusterScoped() && o.Namespace == "" {
+			t.Fatalf("%s: namespaced object %s of %s has no namespace", name, o, o.File)
+		}
+		if schema == nil {
+			continue
+		}
+		if errs := schema.Validate(o); len(errs) > 0 {
+			t.Fatalf("%s: object %s of %s is invalid: %v", name, o, o.File, errs)
+		}
+	}
+}
+
+// TestGoldenServiceCatalog tests that the service catalog manifests
+// rendered for representative configurations match their golden files.
+func TestGoldenServiceCatalog(t *testing.T) {
+	bin, err := ioutil.TempDir("", "golden-bin")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(bin)
+	defer fakeKubectl(t, bin)()
+
+	for name, configure := range goldenConfigs {
+		dir, err := ioutil.TempDir("", "golden")
+		if err != nil {
+			t.Fatalf("Unexpected error creating temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		ic := validInstallConfig()
+		configure(ic)
+		if err := ic.Validate(); err != nil {
+			t.Fatalf("%s: unexpected error validating config: %v", name, err)
+		}
+		if err := renderServiceCatalog(dir, ic, goldenCerts); err != nil {
+			t.Fatalf("%s: unexpected error rendering manifests: %v", name, err)
+		}
+		objs, err := manifest.ParseDir(dir)
+		if err != nil {
+			t.Fatalf("%s: unexpected error parsing manifests: %v", name, err)
+		}
+		checkObjects(t, name, objs)
+		checkGolden(t, name, renderGolden(t, dir))
+	}
+}
+
+// TestGoldenGCPBroker tests that the GCP broker manifests match their
+// golden file.
+func TestGoldenGCPBroker(t *testing.T) {
+	bin, err := ioutil.TempDir("", "golden-bin")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(bin)
+	defer fakeKubectl(t, bin)()
+
+	dir, err := ioutil.TempDir("", "golden")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = generateConfigs(dir, gcpBrokerTemplateDir, gcpBrokerFileNames, map[string]interface{}{
+		"SvcAccountKey":            "U0VSVklDRSBBQ0NPVU5UIEtFWQ==",
+		"GCPBrokerURL":             "https://servicebroker.googleapis.com/v1beta1/projects/my-project/brokers/default",
+		"ServiceClassRestrictions": []string{"spec.externalName in (cloud-pubsub)"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing manifests: %v", err)
+	}
+	checkObjects(t, "gcp-broker", objs)
+	checkGolden(t, "gcp-broker", renderGolden(t, dir))
+}
@@ -0,0 +1,487 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// Labels of the secrets holding the revisions of an installation.
+const (
+	revisionLabel       = "sc-revision"
+	revisionStatusLabel = "sc-revision-status"
+)
+
+// revisionSecretPrefix prefixes the names of the revision secrets, e.g.
+// sc-revision.v3.
+const revisionSecretPrefix = "sc-revision.v"
+
+// defaultHistoryMax is the number of revisions kept by default.
+const defaultHistoryMax = 10
+
+// Statuses of a revision.
+const (
+	// revisionDeployed is the revision the installation runs.
+	revisionDeployed = "deployed"
+
+	// revisionSuperseded revisions were deployed before the current one,
+	// and can be rolled back to.
+	revisionSuperseded = "superseded"
+
+	// revisionFailed revisions failed to deploy or to become ready.
+	revisionFailed = "failed"
+)
+
+// revision is a set of manifests an install or a rollback applied, stored
+// in a secret of the catalog namespace.
+type revision struct {
+	// Number and Status are the labels of the secret.
+	Number int    `json:"-"`
+	Status string `json:"-"`
+
+	// Version is the Service Catalog version of the manifests.
+	Version string `json:"version"`
+
+	// Images are the images the manifests run.
+	Images []string `json:"images"`
+
+	// Description tells what made the revision, e.g. install.
+	Description string `json:"description"`
+
+	Time time.Time `json:"time"`
+
+	// Manifests are the contents of the manifest files, by name.
+	Manifests map[string]string `json:"manifests"`
+}
+
+func revisionSecretName(number int) string {
+	return revisionSecretPrefix + strconv.Itoa(number)
+}
+
+// listRevisions returns the revisions of the installation in namespace ns,
+// oldest first.
+func listRevisions(ns string) ([]*revision, error) {
+	output, err := kubectlCommand("get", "secrets", "--namespace", ns,
+		"-l", "app.kubernetes.io/managed-by=sc,"+revisionLabel, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the revisions: %s", strings.TrimSpace(string(output)))
+	}
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name   string            `json:"name"`
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Data map[string]string `json:"data"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the revisions: %v", err)
+	}
+	var result []*revision
+	for _, s := range list.Items {
+		n, err := strconv.Atoi(s.Metadata.Labels[revisionLabel])
+		if err != nil {
+			return nil, fmt.Errorf("secret %s has an invalid %s label", s.Metadata.Name, revisionLabel)
+		}
+		r, err := decodeRevision(s.Data["revision"])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding secret %s: %v", s.Metadata.Name, err)
+		}
+		r.Number, r.Status = n, s.Metadata.Labels[revisionStatusLabel]
+		result = append(result, r)
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Number < result[b].Number })
+	return result, nil
+}
+
+// encodeRevision returns r as the gzipped JSON of the revision secret,
+// base64 encoded, which keeps the manifests of a revision well below the
+// size limit of secrets.
+func encodeRevision(r *revision) (string, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func decodeRevision(data string) (*revision, error) {
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var rev revision
+	if err := json.NewDecoder(r).Decode(&rev); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// readManifests returns the contents of the manifest files in dir, by name.
+func readManifests(dir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	manifests := make(map[string]string, len(files))
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		manifests[filepath.Base(f)] = string(b)
+	}
+	return manifests, nil
+}
+
+// manifestImages returns the images of the containers of the objects of
+// manifests.
+func manifestImages(manifests map[string]string) ([]string, error) {
+	var images []string
+	for name, m := range manifests {
+		objs, err := manifest.Parse(name, []byte(m))
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range objs {
+			var v interface{}
+			if err := json.Unmarshal(o.JSON, &v); err != nil {
+				return nil, err
+			}
+			images = append(images, containerImages(v)...)
+		}
+	}
+	return uniqueSorted(images), nil
+}
+
+// containerImages returns the images of the containers and init containers
+// found anywhere in v, e.g. in the pod template of a deployment.
+func containerImages(v interface{}) []string {
+	var images []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if k == "containers" || k == "initContainers" {
+				cs, _ := e.([]interface{})
+				for _, c := range cs {
+					c, _ := c.(map[string]interface{})
+					if image, ok := c["image"].(string); ok {
+						images = append(images, image)
+					}
+				}
+				continue
+			}
+			images = append(images, containerImages(e)...)
+		}
+	case []interface{}:
+		for _, e := range v {
+			images = append(images, containerImages(e)...)
+		}
+	}
+	return images
+}
+
+// recordRevision stores manifests as a new revision of the installation in
+// namespace ns, deployed unless it failed with deployErr, superseding the
+// deployed one, and deletes the oldest revisions beyond max.
+func recordRevision(ns string, manifests map[string]string, version, description string, deployErr error, max int) (*revision, error) {
+	revisions, err := listRevisions(ns)
+	if err != nil {
+		return nil, err
+	}
+	images, err := manifestImages(manifests)
+	if err != nil {
+		return nil, err
+	}
+	r := &revision{
+		Number:      1,
+		Status:      revisionDeployed,
+		Version:     version,
+		Images:      images,
+		Description: description,
+		Time:        time.Now().UTC(),
+		Manifests:   manifests,
+	}
+	if len(revisions) > 0 {
+		r.Number = revisions[len(revisions)-1].Number + 1
+	}
+	if deployErr != nil {
+		r.Status = revisionFailed
+	}
+	data, err := encodeRevision(r)
+	if err != nil {
+		return nil, err
+	}
+	s, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":      revisionSecretName(r.Number),
+			"namespace": ns,
+			"labels": map[string]string{
+				"app.kubernetes.io/managed-by": "sc",
+				revisionLabel:                  strconv.Itoa(r.Number),
+				revisionStatusLabel:            r.Status,
+			},
+		},
+		"data": map[string]string{"revision": data},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Revisions never change but for their status label, create rather
+	// than apply them, which would copy them into an annotation too large
+	// for the manifests.
+	cmd := kubectlCommand("create", "-f", "-")
+	cmd.Stdin = bytes.NewReader(s)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("error recording revision %d: %s", r.Number, strings.TrimSpace(string(output)))
+	}
+
+	if r.Status == revisionDeployed {
+		for _, old := range revisions {
+			if old.Status != revisionDeployed {
+				continue
+			}
+			output, err := kubectlCommand("label", "secret", revisionSecretName(old.Number), "--namespace", ns,
+				revisionStatusLabel+"="+revisionSuperseded, "--overwrite").CombinedOutput()
+			if err != nil {
+				return nil, fmt.Errorf("error superseding revision %d: %s", old.Number, strings.TrimSpace(string(output)))
+			}
+		}
+	}
+	revisions = append(revisions, r)
+	for len(revisions) > max {
+		output, err := kubectlCommand("delete", "secret", revisionSecretName(revisions[0].Number), "--namespace", ns,
+			"--ignore-not-found").CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("error deleting revision %d: %s", revisions[0].Number, strings.TrimSpace(string(output)))
+		}
+		revisions = revisions[1:]
+	}
+	return r, nil
+}
+
+// recordInstallRevision records the manifests in dir applied by the
+// install of ic, which failed with err if not nil, as a revision. Failing
+// to record it only warns, the install itself is done.
+func recordInstallRevision(ic *InstallConfig, dir string, err error) {
+	if ic.HistoryMax <= 0 {
+		return
+	}
+	manifests, rerr := readManifests(dir)
+	if rerr == nil {
+		var r *revision
+		if r, rerr = recordRevision(ic.Namespace, manifests, catalogVersion(ic), "install", err, ic.HistoryMax); rerr == nil {
+			fmt.Printf("recorded revision %d\n", r.Number)
+			return
+		}
+	}
+	fmt.Printf("WARNING: the manifests could not be recorded for sc rollback: %v\n", rerr)
+}
+
+// rollbackConfig configures a rollback.
+type rollbackConfig struct {
+	// Namespace Service Catalog is installed in.
+	Namespace string
+
+	// Revision is the revision to roll back to, 0 for the last
+	// superseded one.
+	Revision int
+
+	// ReadyTimeout is how long to wait for the components to be ready.
+	// Zero skips waiting.
+	ReadyTimeout time.Duration
+
+	// HistoryMax is the number of revisions kept.
+	HistoryMax int
+
+	// ForceUnlock takes over the lease of the namespace another sc
+	// operation holds instead of failing.
+	ForceUnlock bool
+}
+
+func NewRollbackCmd() *cobra.Command {
+	rc := &rollbackConfig{}
+	c := &cobra.Command{
+		Use:   "rollback [revision]",
+		Short: "rolls Service Catalog back to a previous revision",
+		Long: `re-applies the manifests of a previous revision of the installation, as
+listed by sc history, including the images and certificates it ran, and
+waits for the components to be ready. Without a revision, it rolls back to
+the revision deployed before the current one. The rollback is recorded as
+a new revision. Objects added by later revisions are left in place.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n < 1 {
+					return fmt.Errorf("invalid revision %q, must be a positive number", args[0])
+				}
+				rc.Revision = n
+			}
+			r, err := rollback(rc)
+			if err != nil {
+				messages.Println(messages.RollbackFailed)
+				return err
+			}
+			messages.Println(messages.RolledBack, r)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&rc.Namespace, "namespace", defaultNamespace, "Namespace Service Catalog is installed in")
+	c.Flags().DurationVar(&rc.ReadyTimeout, "ready-timeout", defaultReadyTimeout, "How long to wait for the components to be ready. 0 skips waiting")
+	c.Flags().IntVar(&rc.HistoryMax, "history-max", defaultHistoryMax, "Number of revisions to keep")
+	c.Flags().BoolVar(&rc.ForceUnlock, "force-unlock", false, "Take over the lock of the namespace held by another sc operation, e.g. a stale one, instead of failing")
+	return c
+}
+
+// rollbackTarget returns the revision of revisions to roll back to: number,
+// or the last superseded one if number is 0.
+func rollbackTarget(revisions []*revision, number int) (*revision, error) {
+	for i := len(revisions) - 1; i >= 0; i-- {
+		r := revisions[i]
+		switch {
+		case number == 0 && r.Status == revisionSuperseded:
+			return r, nil
+		case number != 0 && r.Number == number && r.Status == revisionFailed:
+			return nil, fmt.Errorf("revision %d failed, roll back to a deployed or superseded revision", number)
+		case number != 0 && r.Number == number:
+			return r, nil
+		}
+	}
+	if number == 0 {
+		return nil, fmt.Errorf("there is no previous revision to roll back to")
+	}
+	return nil, fmt.Errorf("revision %d not found, see sc history", number)
+}
+
+// rollback re-applies the manifests of the revision of rc and returns its
+// number.
+func rollback(rc *rollbackConfig) (n int, err error) {
+	revisions, err := listRevisions(rc.Namespace)
+	if err != nil {
+		return 0, err
+	}
+	target, err := rollbackTarget(revisions, rc.Revision)
+	if err != nil {
+		return 0, err
+	}
+
+	// The manifests hold the keys of the revision.
+	ws, err := createWorkspace(rc.Namespace, "rollback", workspace.Options{Exclusive: true, Sensitive: true})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { ws.Done(err) }()
+	unlock, err := lockCluster(rc.Namespace, "rollback", rc.ForceUnlock)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	for name, m := range target.Manifests {
+		if err := ioutil.WriteFile(filepath.Join(ws.Dir, name), []byte(m), 0600); err != nil {
+			return 0, err
+		}
+	}
+
+	fmt.Printf("rolling back to revision %d, Service Catalog %s\n", target.Number, target.Version)
+	err = deployConfig(ws.Dir, applyOptions{})
+	if err == nil {
+		err = restartServiceCatalogPods(&InstallConfig{Namespace: rc.Namespace})
+	}
+	if err == nil && rc.ReadyTimeout > 0 {
+		_, apiService := target.Manifests["api-registration.yaml"]
+		err = waitForCatalogReady(rc.Namespace, rc.ReadyTimeout, apiService)
+	}
+	r, rerr := recordRevision(rc.Namespace, target.Manifests, target.Version,
+		fmt.Sprintf("rollback to %d", target.Number), err, rc.HistoryMax)
+	if err != nil {
+		return 0, err
+	}
+	if rerr != nil {
+		return 0, rerr
+	}
+	fmt.Printf("recorded revision %d\n", r.Number)
+	return target.Number, nil
+}
+
+func NewHistoryCmd() *cobra.Command {
+	var ns string
+	c := &cobra.Command{
+		Use:   "history",
+		Short: "lists the revisions of the installation",
+		Long: `lists the revisions of the installation recorded by sc install and sc
+rollback, with their status, Service Catalog version and images, to pick
+the revision to roll back to.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			revisions, err := listRevisions(ns)
+			if err != nil {
+				messages.Println(messages.HistoryFailed)
+				return err
+			}
+			printHistory(os.Stdout, revisions)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&ns, "namespace", defaultNamespace, "Namespace Service Catalog is installed in")
+	return c
+}
+
+// printHistory prints a table of revisions to w.
+func printHistory(w io.Writer, revisions []*revision) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "REVISION\tUPDATED\tSTATUS\tVERSION\tDESCRIPTION\tIMAGES")
+	for _, r := range revisions {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", r.Number, r.Time.Format(time.RFC3339), r.Status,
+			r.Version, r.Description, strings.Join(r.Images, ","))
+	}
+	tw.Flush()
+}
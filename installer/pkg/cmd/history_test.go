@@ -0,0 +1,148 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+const revisionManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: apiserver
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: wait
+        image: busybox:1.28
+      containers:
+      - name: apiserver
+        image: gcr.io/gcp-services/service-catalog:v0.1.11-gke.0
+`
+
+// TestRecordRevision tests that a new revision supersedes the deployed one
+// and the oldest revisions beyond the maximum are deleted.
+func TestRecordRevision(t *testing.T) {
+	var items []interface{}
+	for i, status := range []string{revisionSuperseded, revisionDeployed} {
+		data, err := encodeRevision(&revision{Version: "0.1.10", Manifests: map[string]string{"a.yaml": ""}})
+		if err != nil {
+			t.Fatalf("Unexpected error encoding revision: %v", err)
+		}
+		n := string('1' + rune(i))
+		items = append(items, map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":   "sc-revision.v" + n,
+				"labels": map[string]string{revisionLabel: n, revisionStatusLabel: status},
+			},
+			"data": map[string]string{"revision": data},
+		})
+	}
+	list, _ := json.Marshal(map[string]interface{}{"items": items})
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		if args[0] == "get" {
+			return execx.Response{Stdout: string(list)}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	r, err := recordRevision("service-catalog", map[string]string{"apiserver-deployment.yaml": revisionManifest}, "0.1.11-gke.0", "install", nil, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error recording revision: %v", err)
+	}
+	if r.Number != 3 || r.Status != revisionDeployed {
+		t.Fatalf("Revision does not match: got %d %s; want 3 deployed", r.Number, r.Status)
+	}
+	want := []string{"busybox:1.28", "gcr.io/gcp-services/service-catalog:v0.1.11-gke.0"}
+	if !reflect.DeepEqual(r.Images, want) {
+		t.Fatalf("Images do not match: got %v; want %v", r.Images, want)
+	}
+	calls := kubectlCalls(s, "create", "label", "delete")
+	wantCalls := []string{
+		"create -f -",
+		"label secret sc-revision.v2 --namespace service-catalog sc-revision-status=superseded --overwrite",
+		"delete secret sc-revision.v1 --namespace service-catalog --ignore-not-found",
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Fatalf("Calls do not match:\ngot  %q\nwant %q", calls, wantCalls)
+	}
+
+	// A failed revision supersedes nothing.
+	s, restore = stubExecutor(func(name string, args []string) execx.Response {
+		if args[0] == "get" {
+			return execx.Response{Stdout: string(list)}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+	if r, err = recordRevision("service-catalog", nil, "0.2.3", "install", errors.New("failed"), 10); err != nil || r.Status != revisionFailed {
+		t.Fatalf("Expected a failed revision, got %+v, %v", r, err)
+	}
+	if calls := kubectlCalls(s, "label", "delete"); len(calls) != 0 {
+		t.Fatalf("Unexpected calls for a failed revision: %q", calls)
+	}
+}
+
+// TestRevisionEncoding tests that revisions survive the round trip through
+// their secret.
+func TestRevisionEncoding(t *testing.T) {
+	r := &revision{Version: "0.2.3", Images: []string{"etcd:3.3"}, Description: "rollback to 2", Manifests: map[string]string{"a.yaml": revisionManifest}}
+	data, err := encodeRevision(r)
+	if err != nil {
+		t.Fatalf("Unexpected error encoding revision: %v", err)
+	}
+	got, err := decodeRevision(data)
+	if err != nil {
+		t.Fatalf("Unexpected error decoding revision: %v", err)
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Fatalf("Revision does not match: got %+v; want %+v", got, r)
+	}
+}
+
+// TestRollbackTarget tests that the last superseded revision is the
+// default target and failed revisions are refused.
+func TestRollbackTarget(t *testing.T) {
+	revisions := []*revision{
+		{Number: 1, Status: revisionSuperseded},
+		{Number: 2, Status: revisionSuperseded},
+		{Number: 3, Status: revisionFailed},
+		{Number: 4, Status: revisionDeployed},
+	}
+	if r, err := rollbackTarget(revisions, 0); err != nil || r.Number != 2 {
+		t.Fatalf("Expected revision 2, got %+v, %v", r, err)
+	}
+	if r, err := rollbackTarget(revisions, 1); err != nil || r.Number != 1 {
+		t.Fatalf("Expected revision 1, got %+v, %v", r, err)
+	}
+	for n, want := range map[int]string{3: "revision 3 failed", 7: "revision 7 not found"} {
+		if _, err := rollbackTarget(revisions, n); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Error for revision %d does not match: got %v; want %q", n, err, want)
+		}
+	}
+	if _, err := rollbackTarget(revisions[3:], 0); err == nil {
+		t.Fatalf("Expected an error without a previous revision")
+	}
+}
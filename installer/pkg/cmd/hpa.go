@@ -0,0 +1,67 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	defaultAPIServerMaxReplicas    = 5
+	defaultAPIServerCPUUtilization = 80
+)
+
+// quantityRE matches the Kubernetes quantities accepted as metric targets,
+// e.g. 100, 0.5, 500m or 1k.
+var quantityRE = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(m|k|M|G|T|Ki|Mi|Gi|Ti)?$`)
+
+// podsMetric is a custom metric of the api server pods the autoscaler keeps
+// at an average value per pod.
+type podsMetric struct {
+	Name         string
+	AverageValue string
+}
+
+// parsePodsMetrics parses metrics given as name=averageValue, e.g.
+// http_requests_per_second=50.
+func parsePodsMetrics(metrics []string) ([]podsMetric, error) {
+	var result []podsMetric
+	for _, m := range metrics {
+		parts := strings.SplitN(m, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || !quantityRE.MatchString(parts[1]) {
+			return nil, fmt.Errorf("invalid --apiserver-hpa-pods-metric %q: expected <metric>=<average value per pod>, e.g. http_requests_per_second=50", m)
+		}
+		result = append(result, podsMetric{Name: parts[0], AverageValue: parts[1]})
+	}
+	return result, nil
+}
+
+// hpaData returns the template data of the api server autoscaler.
+func hpaData(ic *InstallConfig) (map[string]interface{}, error) {
+	metrics, err := parsePodsMetrics(ic.APIServerPodsMetrics)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"APIServerHPA":            ic.APIServerHPA,
+		"APIServerMaxReplicas":    ic.APIServerMaxReplicas,
+		"APIServerCPUUtilization": ic.APIServerCPUUtilization,
+		"APIServerPodsMetrics":    metrics,
+	}, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestParsePodsMetrics tests that metrics are parsed from name=value and
+// that targets that are not quantities are rejected.
+func TestParsePodsMetrics(t *testing.T) {
+	got, err := parsePodsMetrics([]string{"http_requests_per_second=50", "queue_depth=500m"})
+	if err != nil {
+		t.Fatalf("Unexpected error parsing metrics: %v", err)
+	}
+	want := []podsMetric{{"http_requests_per_second", "50"}, {"queue_depth", "500m"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Metrics do not match: got %v; want %v", got, want)
+	}
+	for _, m := range []string{"http_requests_per_second", "=50", "rate=fast", "rate=-1"} {
+		if _, err := parsePodsMetrics([]string{m}); err == nil {
+			t.Fatalf("Expected an error for %q", m)
+		}
+	}
+}
+
+// TestValidateHPA tests that the replica bounds and targets of the
+// autoscaler are checked.
+func TestValidateHPA(t *testing.T) {
+	ic := validInstallConfig()
+	ic.APIServerHPA = true
+	ic.APIServerReplicas = 2
+	ic.APIServerMaxReplicas = 1
+	ic.APIServerCPUUtilization = 0
+	err := ic.Validate()
+	if err == nil {
+		t.Fatalf("Expected an error validating the autoscaler")
+	}
+	for _, want := range []string{"--apiserver-max-replicas must be at least --apiserver-replicas (2)", "requires a CPU utilization or pods metric target"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Error %q does not contain %q", err, want)
+		}
+	}
+}
+
+// TestRenderHPA tests that the autoscaler targets the rendered metrics and
+// that the api server deployment leaves its replica count to it.
+func TestRenderHPA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hpa")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ic := validInstallConfig()
+	ic.APIServerHPA = true
+	ic.APIServerReplicas = 2
+	ic.APIServerMaxReplicas = 6
+	ic.APIServerCPUUtilization = 70
+	ic.APIServerPodsMetrics = []string{"http_requests_per_second=50"}
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing manifests: %v", err)
+	}
+
+	var hpa struct {
+		Spec struct {
+			MinReplicas int32 `json:"minReplicas"`
+			MaxReplicas int32 `json:"maxReplicas"`
+			Metrics     []struct {
+				Type string `json:"type"`
+			} `json:"metrics"`
+		} `json:"spec"`
+	}
+	found := false
+	for _, o := range objs {
+		switch {
+		case o.Kind == "HorizontalPodAutoscaler":
+			found = true
+			if err := json.Unmarshal(o.JSON, &hpa); err != nil {
+				t.Fatalf("Unexpected error unmarshalling %s: %v", o, err)
+			}
+		case o.Kind == "Deployment" && o.Name == "apiserver":
+			if strings.Contains(string(o.JSON), `"replicas"`) {
+				t.Fatalf("Deployment %s must not set replicas with an autoscaler", o)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("No HorizontalPodAutoscaler rendered")
+	}
+	if hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 6 {
+		t.Fatalf("Replica bounds do not match: got %d-%d; want 2-6", hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+	if len(hpa.Spec.Metrics) != 2 || hpa.Spec.Metrics[0].Type != "Resource" || hpa.Spec.Metrics[1].Type != "Pods" {
+		t.Fatalf("Metrics do not match: got %+v; want Resource and Pods", hpa.Spec.Metrics)
+	}
+}
+
+// TestRenderHPAMetrics tests that the metric targets have the shape of the
+// autoscaling API version of the cluster.
+func TestRenderHPAMetrics(t *testing.T) {
+	for _, tc := range []struct {
+		apiVersion string
+		want       []string
+	}{
+		{"autoscaling/v2beta1", []string{`"targetAverageUtilization":70`, `"metricName":"http_requests_per_second"`, `"targetAverageValue":"50"`}},
+		{"autoscaling/v2", []string{`"averageUtilization":70`, `"metric":{"name":"http_requests_per_second"}`, `"averageValue":"50"`}},
+	} {
+		dir, err := ioutil.TempDir("", "hpa")
+		if err != nil {
+			t.Fatalf("Unexpected error creating temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		ic := validInstallConfig()
+		ic.APIServerHPA = true
+		ic.APIServerMaxReplicas = 6
+		ic.APIServerCPUUtilization = 70
+		ic.APIServerPodsMetrics = []string{"http_requests_per_second=50"}
+		ic.AutoscalingAPIVersion = tc.apiVersion
+		if err := renderServiceCatalog(dir, ic, nil); err != nil {
+			t.Fatalf("Unexpected error rendering manifests: %v", err)
+		}
+		objs, err := manifest.ParseDir(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing manifests: %v", err)
+		}
+		found := false
+		for _, o := range objs {
+			if o.Kind != "HorizontalPodAutoscaler" {
+				continue
+			}
+			found = true
+			if o.APIVersion != tc.apiVersion {
+				t.Fatalf("API version does not match: got %s; want %s", o.APIVersion, tc.apiVersion)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(string(o.JSON), w) {
+					t.Fatalf("%s autoscaler %s does not contain %s", tc.apiVersion, o.JSON, w)
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("No HorizontalPodAutoscaler rendered")
+		}
+	}
+}
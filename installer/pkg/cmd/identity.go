@@ -0,0 +1,198 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// verifyIdentity is the identity verification runs as when it is not the
+// one sc applies with, e.g. read-only credentials validating an install done
+// by a CI service account.
+type verifyIdentity struct {
+	// Kubeconfig is the kubeconfig file of the identity, the current
+	// kubeconfig if empty.
+	Kubeconfig string
+
+	// Context is the context of the identity, the current context of
+	// Kubeconfig if empty.
+	Context string
+}
+
+// addVerifyIdentityFlags adds the flags selecting the verification identity
+// to c.
+func addVerifyIdentityFlags(c *cobra.Command, v *verifyIdentity) {
+	c.Flags().StringVar(&v.Kubeconfig, "verify-kubeconfig", "", "Kubeconfig of the identity to also verify the installation as, e.g. read-only credentials")
+	c.Flags().StringVar(&v.Context, "verify-context", "", "Kubeconfig context of the identity to also verify the installation as")
+}
+
+func (v verifyIdentity) isSet() bool {
+	return v.Kubeconfig != "" || v.Context != ""
+}
+
+func (v verifyIdentity) String() string {
+	switch {
+	case v.Context != "" && v.Kubeconfig != "":
+		return fmt.Sprintf("context %s of %s", v.Context, v.Kubeconfig)
+	case v.Context != "":
+		return "context " + v.Context
+	default:
+		return v.Kubeconfig
+	}
+}
+
+// use switches the kubectl commands run by sc to the identity, by pointing
+// KUBECONFIG to a copy of its kubeconfig holding only its context. It
+// returns the function switching back.
+func (v verifyIdentity) use() (func(), error) {
+	args := []string{"config", "view", "--minify", "--flatten", "-o", "json"}
+	if v.Kubeconfig != "" {
+		args = append(args, "--kubeconfig", v.Kubeconfig)
+	}
+	if v.Context != "" {
+		args = append(args, "--context", v.Context)
+	}
+	output, err := kubectlCommand(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading the kubeconfig of %s: %v", v, err)
+	}
+	var k struct {
+		Contexts []interface{} `json:"contexts"`
+	}
+	if err := json.Unmarshal(output, &k); err != nil || len(k.Contexts) == 0 {
+		return nil, fmt.Errorf("no context %s found", v)
+	}
+
+	// The copy holds credentials, only the user may read it.
+	f, err := ioutil.TempFile("", "sc-verify-kubeconfig")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := f.Write(output); err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	previous, wasSet := os.LookupEnv("KUBECONFIG")
+	os.Setenv("KUBECONFIG", f.Name())
+	return func() {
+		if wasSet {
+			os.Setenv("KUBECONFIG", previous)
+		} else {
+			os.Unsetenv("KUBECONFIG")
+		}
+		os.Remove(f.Name())
+	}, nil
+}
+
+// identityVerifyChecks returns the checks run as a verification identity,
+// which need no more than read access: that aggregated API discovery works
+// for normal users and that they can read the catalog.
+func identityVerifyChecks(v verifyIdentity) []verifyCheck {
+	as := " as " + v.String()
+	return []verifyCheck{
+		{name: "discovery lists the servicecatalog.k8s.io group" + as, run: checkCatalogDiscovery},
+		{name: "discovery lists the catalog resources" + as, run: checkCatalogResourceDiscovery},
+		{name: "cluster service classes can be listed" + as, run: checkCatalogReadable},
+	}
+}
+
+// runIdentityVerifyChecks runs the identity checks as v.
+func runIdentityVerifyChecks(v verifyIdentity) ([]verifyResult, error) {
+	restore, err := v.use()
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	return runVerifyChecks(identityVerifyChecks(v)), nil
+}
+
+// verifyAsIdentity runs the identity checks as v after an install, failing
+// if any does.
+func verifyAsIdentity(v verifyIdentity) error {
+	fmt.Printf("verifying the installation as %s\n", v)
+	results, err := runIdentityVerifyChecks(v)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed as %s", failed, len(results), v)
+	}
+	return nil
+}
+
+// checkCatalogResourceDiscovery checks that the aggregated api server
+// answers discovery, which the main API server proxies to it with the
+// identity of the caller.
+func checkCatalogResourceDiscovery() error {
+	output, err := kubectlCommand("get", "--raw", "/apis/"+scAPIVersion).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error getting the discovery of %s: %s", scAPIVersion, strings.TrimSpace(string(output)))
+	}
+	return checkDiscoveredResources(output)
+}
+
+// catalogResources are resources the discovery of the catalog API lists.
+var catalogResources = []string{"clusterservicebrokers", "clusterserviceclasses", "clusterserviceplans", "serviceinstances", "servicebindings"}
+
+// checkDiscoveredResources checks that the APIResourceList discovery lists
+// the catalog resources.
+func checkDiscoveredResources(discovery []byte) error {
+	var list struct {
+		Resources []struct {
+			Name string `json:"name"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(discovery, &list); err != nil {
+		return fmt.Errorf("error unmarshalling the discovery of %s: %v", scAPIVersion, err)
+	}
+	listed := make(map[string]bool)
+	for _, r := range list.Resources {
+		listed[r.Name] = true
+	}
+	var missing []string
+	for _, r := range catalogResources {
+		if !listed[r] {
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the discovery of %s does not list %s", scAPIVersion, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func checkCatalogReadable() error {
+	output, err := kubectlCommand("get", "clusterserviceclasses.servicecatalog.k8s.io", "-o", "name").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error listing cluster service classes: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestVerifyIdentityUse tests that KUBECONFIG points to the kubeconfig of
+// the identity while it is used, and is restored afterwards.
+func TestVerifyIdentityUse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sc-identity-test")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temporary dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	// The fake kubectl records its arguments in the kubeconfig.
+	script := "#!/bin/sh\necho \"{\\\"contexts\\\": [{}], \\\"args\\\": \\\"$*\\\"}\"\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, KubectlBinaryName), []byte(script), 0755); err != nil {
+		t.Fatalf("Unexpected error writing fake kubectl: %v", err)
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir)
+	defer os.Setenv("PATH", path)
+	previous, wasSet := os.LookupEnv("KUBECONFIG")
+	os.Setenv("KUBECONFIG", "/original")
+	defer func() {
+		if wasSet {
+			os.Setenv("KUBECONFIG", previous)
+		} else {
+			os.Unsetenv("KUBECONFIG")
+		}
+	}()
+
+	restore, err := verifyIdentity{Kubeconfig: "/reader", Context: "reader"}.use()
+	if err != nil {
+		t.Fatalf("Unexpected error using identity: %v", err)
+	}
+	copied := os.Getenv("KUBECONFIG")
+	b, err := ioutil.ReadFile(copied)
+	if err != nil {
+		t.Fatalf("Unexpected error reading the kubeconfig of the identity: %v", err)
+	}
+	if want := "--minify --flatten -o json --kubeconfig /reader --context reader"; !strings.Contains(string(b), want) {
+		t.Fatalf("Kubeconfig does not match: got %s; want it to be read with %q", b, want)
+	}
+
+	restore()
+	if got := os.Getenv("KUBECONFIG"); got != "/original" {
+		t.Fatalf("KUBECONFIG was not restored: got %q; want %q", got, "/original")
+	}
+	if _, err := os.Stat(copied); !os.IsNotExist(err) {
+		t.Fatalf("Kubeconfig of the identity was not removed: %v", err)
+	}
+}
+
+// TestCheckDiscoveredResources tests that the catalog resources missing from
+// the discovery are reported.
+func TestCheckDiscoveredResources(t *testing.T) {
+	all := `{"resources": [{"name": "clusterservicebrokers"}, {"name": "clusterserviceclasses"}, {"name": "clusterserviceplans"}, {"name": "serviceinstances"}, {"name": "servicebindings"}]}`
+	if err := checkDiscoveredResources([]byte(all)); err != nil {
+		t.Fatalf("Unexpected error checking a complete discovery: %v", err)
+	}
+	err := checkDiscoveredResources([]byte(`{"resources": [{"name": "clusterservicebrokers"}, {"name": "serviceinstances"}]}`))
+	if err == nil || !strings.HasSuffix(err.Error(), "does not list clusterserviceclasses, clusterserviceplans, servicebindings") {
+		t.Fatalf("Error does not match: got %v; want the missing resources", err)
+	}
+}
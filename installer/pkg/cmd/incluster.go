@@ -0,0 +1,186 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+)
+
+// serviceAccountDir is where Kubernetes mounts the service account
+// credentials into pods.
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// inClusterTimeout bounds each request of the in-cluster client.
+const inClusterTimeout = 15 * time.Second
+
+// inClusterConfig is the in-cluster config of the pod the installer runs
+// in: the API server of its environment and the credentials of its service
+// account.
+type inClusterConfig struct {
+	// Server is the URL of the API server.
+	Server string
+
+	// Token is the service account token.
+	Token string
+
+	// RootCAs holds the CA of the API server.
+	RootCAs *x509.CertPool
+}
+
+// loadInClusterConfig returns the in-cluster config and true if the
+// installer runs in a pod without a kubeconfig.
+func loadInClusterConfig() (*inClusterConfig, bool) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || os.Getenv("KUBECONFIG") != "" {
+		return nil, false
+	}
+	token, err := ioutil.ReadFile(filepath.Join(serviceAccountDir, "token"))
+	if err != nil {
+		return nil, false
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, false
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, false
+	}
+	if port == "" {
+		port = "443"
+	}
+	return &inClusterConfig{
+		Server:  "https://" + net.JoinHostPort(host, port),
+		Token:   strings.TrimSpace(string(token)),
+		RootCAs: pool,
+	}, true
+}
+
+// serviceAccount returns the service account of the token, e.g.
+// "system:serviceaccount:sc-installer:sc-installer".
+func (c *inClusterConfig) serviceAccount() string {
+	// The token is a JWT whose subject is the service account.
+	if parts := strings.Split(c.Token, "."); len(parts) == 3 {
+		var claims struct {
+			Sub string `json:"sub"`
+		}
+		if b, err := base64.RawURLEncoding.DecodeString(parts[1]); err == nil && json.Unmarshal(b, &claims) == nil && claims.Sub != "" {
+			return claims.Sub
+		}
+	}
+	return "unknown"
+}
+
+// call sends the JSON encoded req, if any, to the API path and decodes the
+// JSON response into resp.
+func (c *inClusterConfig) call(method, path string, req, resp interface{}) error {
+	var body io.Reader
+	if req != nil {
+		b, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	r, err := http.NewRequest(method, c.Server+path, body)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Authorization", "Bearer "+c.Token)
+	r.Header.Set("Accept", "application/json")
+	if req != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{
+		Timeout: inClusterTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: c.RootCAs},
+		},
+	}
+	res, err := client.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %v", err)
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return fmt.Errorf("%s %s failed with status %s: %s", method, path, res.Status, strings.TrimSpace(string(b)))
+	}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return fmt.Errorf("error unmarshalling the response of %s %s: %v", method, path, err)
+	}
+	return nil
+}
+
+// serverVersion returns the version of the API server.
+func (c *inClusterConfig) serverVersion() (*semver.Version, error) {
+	var v k8sVersion
+	if err := c.call("GET", "/version", nil, &v); err != nil {
+		return nil, fmt.Errorf("error fetching Kubernetes version :%v", err)
+	}
+	return semver.NewVersion(v.GitVersion)
+}
+
+// canI returns whether the service account may perform verb on resource,
+// e.g. deployments.apps, in namespace ns, cluster-wide if ns is empty.
+func (c *inClusterConfig) canI(verb, resource, ns string) (bool, error) {
+	group := ""
+	if i := strings.Index(resource, "."); i >= 0 {
+		resource, group = resource[:i], resource[i+1:]
+	}
+	review := map[string]interface{}{
+		"apiVersion": "authorization.k8s.io/v1",
+		"kind":       "SelfSubjectAccessReview",
+		"spec": map[string]interface{}{
+			"resourceAttributes": map[string]interface{}{
+				"verb":      verb,
+				"group":     group,
+				"resource":  resource,
+				"namespace": ns,
+			},
+		},
+	}
+	var result struct {
+		Status struct {
+			Allowed bool `json:"allowed"`
+		} `json:"status"`
+	}
+	if err := c.call("POST", "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", review, &result); err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
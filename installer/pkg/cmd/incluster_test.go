@@ -0,0 +1,135 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// inClusterServer starts a TLS API server serving handler and points the
+// in-cluster config at it, with the token of the sc-installer service
+// account. The returned func stops the server and restores the config.
+func inClusterServer(t *testing.T, handler http.HandlerFunc) (string, func()) {
+	s := httptest.NewTLSServer(handler)
+	dir, err := ioutil.TempDir("", "serviceaccount")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"system:serviceaccount:sc-installer:sc-installer"}`))
+	token := "e30." + claims + ".sig"
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+	for name, b := range map[string][]byte{"token": []byte(token + "\n"), "ca.crt": ca} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0600); err != nil {
+			t.Fatalf("Unexpected error writing %s: %v", name, err)
+		}
+	}
+
+	host, port, _ := net.SplitHostPort(s.Listener.Addr().String())
+	oldDir, oldKubeconfig := serviceAccountDir, os.Getenv("KUBECONFIG")
+	oldHost, oldPort := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	serviceAccountDir = dir
+	os.Unsetenv("KUBECONFIG")
+	os.Setenv("KUBERNETES_SERVICE_HOST", host)
+	os.Setenv("KUBERNETES_SERVICE_PORT", port)
+	return token, func() {
+		s.Close()
+		os.RemoveAll(dir)
+		serviceAccountDir = oldDir
+		os.Setenv("KUBECONFIG", oldKubeconfig)
+		os.Setenv("KUBERNETES_SERVICE_HOST", oldHost)
+		os.Setenv("KUBERNETES_SERVICE_PORT", oldPort)
+	}
+}
+
+// TestInClusterConfig tests that the installer calls the API server with
+// the in-cluster config of its pod, without kubectl.
+func TestInClusterConfig(t *testing.T) {
+	var token string
+	var reviews []map[string]interface{}
+	token, restore := inClusterServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/version":
+			w.Write([]byte(`{"gitVersion": "v1.10.4-gke.2"}`))
+		case "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews":
+			var review struct {
+				Spec struct {
+					ResourceAttributes map[string]interface{} `json:"resourceAttributes"`
+				} `json:"spec"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			attrs := review.Spec.ResourceAttributes
+			reviews = append(reviews, attrs)
+			w.WriteHeader(http.StatusCreated)
+			allowed := attrs["resource"] != "apiservices"
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": map[string]interface{}{"allowed": allowed}})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer restore()
+	// Fail any kubectl call.
+	s, restoreExecutor := stubExecutor(func(name string, args []string) execx.Response {
+		return execx.Response{ExitCode: 1}
+	})
+	defer restoreExecutor()
+
+	c, ok := loadInClusterConfig()
+	if !ok {
+		t.Fatalf("Expected the in-cluster config to be loaded")
+	}
+	if got, want := c.serviceAccount(), "system:serviceaccount:sc-installer:sc-installer"; got != want {
+		t.Fatalf("Service account does not match: got %s; want %s", got, want)
+	}
+	v, err := getServerVersion()
+	if err != nil {
+		t.Fatalf("Unexpected error getting the server version: %v", err)
+	}
+	if got, want := v.String(), "1.10.4-gke.2"; got != want {
+		t.Fatalf("Server version does not match: got %s; want %s", got, want)
+	}
+
+	err = probeCluster("create", "service-catalog")
+	if err == nil || !strings.Contains(err.Error(), "not allowed to create apiservices.apiregistration.k8s.io") {
+		t.Fatalf("Expected the apiservices to be denied, got %v", err)
+	}
+	if got, want := reviews[len(reviews)-1], map[string]interface{}{"verb": "create", "group": "apiregistration.k8s.io", "resource": "apiservices", "namespace": ""}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Review does not match: got %v; want %v", got, want)
+	}
+	if len(s.Calls()) != 0 {
+		t.Fatalf("Unexpected kubectl calls: %v", s.Calls())
+	}
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+// installerRBACName is the name of the generated roles and bindings, and of
+// the service account of the bootstrap job.
+const installerRBACName = "sc-installer"
+
+// installerRBACConfig contains the generate installer-rbac configuration.
+type installerRBACConfig struct {
+	// Namespace is the namespace of the sc-installer service account the
+	// roles are granted to.
+	Namespace string
+
+	// User is the user the roles are granted to instead of the service
+	// account, if set.
+	User string
+
+	// Uninstall also grants what uninstall needs.
+	Uninstall bool
+}
+
+// installVerbs are the verbs kubectl apply needs on the installed objects.
+var installVerbs = []string{"get", "list", "create", "patch", "update"}
+
+// uninstallVerbs are the verbs needed to both install and uninstall.
+var uninstallVerbs = append(append([]string{}, installVerbs...), "delete")
+
+func newGenerateInstallerRBACCmd() *cobra.Command {
+	ic := newInstallConfig()
+	rc := &installerRBACConfig{}
+	c := &cobra.Command{
+		Use:   "installer-rbac",
+		Short: "generates the least privilege RBAC the installer needs",
+		Long: `generates the ClusterRole and Roles, and their bindings, the installer
+needs to install Service Catalog with the same flags, computed from the
+objects it will create. Grant them to a user with --user, generate
+bootstrap-job includes them for the service account of its job.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyProfile(ic, cmd.Flags()); err != nil {
+				return err
+			}
+			if err := applyNameAffixes(ic); err != nil {
+				return err
+			}
+			return generateInstallerRBAC(ic, rc)
+		},
+	}
+	addInstallFlags(c, ic)
+	c.Flags().StringVar(&rc.Namespace, "namespace", "sc-installer", "Namespace of the sc-installer service account")
+	c.Flags().StringVar(&rc.User, "user", "", "User to grant the roles to instead of the service account")
+	c.Flags().BoolVar(&rc.Uninstall, "uninstall", false, "Also grant the permissions uninstall needs")
+	return c
+}
+
+func generateInstallerRBAC(ic *InstallConfig, rc *installerRBACConfig) error {
+	objs, err := installerRBACObjects(ic, rc)
+	if err != nil {
+		return err
+	}
+	fmt.Println("# RBAC for running the Service Catalog installer, generated by sc generate installer-rbac.")
+	for _, o := range objs {
+		b, err := yaml.Marshal(o)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("---\n%s", b)
+	}
+	return nil
+}
+
+// installerRBACObjects renders the objects an install with ic creates and
+// returns the roles and bindings of the installer creating them.
+func installerRBACObjects(ic *InstallConfig, rc *installerRBACConfig) (result []map[string]interface{}, err error) {
+	if err := ic.Validate(); err != nil {
+		return nil, err
+	}
+	detectCapabilities(ic)
+	if rc.Uninstall {
+		// Uninstall deletes all the objects an install may create,
+		// except the skipped components.
+		skipped := skippedComponents(ic)
+		ic = uninstallConfig(ic.NamePrefix, ic.NameSuffix)
+		setSkippedComponents(ic, skipped)
+	}
+
+	ws, err := createWorkspace(ic.Namespace, "installer-rbac", workspace.Options{Cleanup: workspace.Always})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { ws.Done(err) }()
+	dir := ws.Dir
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		return nil, fmt.Errorf("error generating YAML files: %v", err)
+	}
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return installerRBAC(objs, ic, rc), nil
+}
+
+// ruleSet collects the verbs allowed on resources, by API group.
+type ruleSet map[string]map[string][]string
+
+// add allows verbs on resource in group.
+func (rs ruleSet) add(group, resource string, verbs ...string) {
+	if rs[group] == nil {
+		rs[group] = make(map[string][]string)
+	}
+	for _, v := range verbs {
+		if !containsString(rs[group][resource], v) {
+			rs[group][resource] = append(rs[group][resource], v)
+		}
+	}
+}
+
+// rules returns the rules of rs, one per group and set of verbs, sorted.
+func (rs ruleSet) rules() []interface{} {
+	var groups []string
+	for g := range rs {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+
+	var rules []interface{}
+	for _, g := range groups {
+		byVerbs := make(map[string][]string)
+		var verbSets []string
+		for r, verbs := range rs[g] {
+			sorted := append([]string{}, verbs...)
+			sort.Strings(sorted)
+			key := strings.Join(sorted, ",")
+			if byVerbs[key] == nil {
+				verbSets = append(verbSets, key)
+			}
+			byVerbs[key] = append(byVerbs[key], r)
+		}
+		sort.Strings(verbSets)
+		for _, key := range verbSets {
+			resources := byVerbs[key]
+			sort.Strings(resources)
+			rules = append(rules, map[string]interface{}{
+				"apiGroups": []string{g},
+				"resources": resources,
+				"verbs":     strings.Split(key, ","),
+			})
+		}
+	}
+	return rules
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceName returns the resource name of kind, e.g. networkpolicies for
+// NetworkPolicy.
+func resourceName(kind string) string {
+	r := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(r, "s"):
+		return r + "es"
+	case strings.HasSuffix(r, "y"):
+		return strings.TrimSuffix(r, "y") + "ies"
+	}
+	return r + "s"
+}
+
+// apiGroup returns the API group of apiVersion, "" for the core group.
+func apiGroup(apiVersion string) string {
+	if i := strings.Index(apiVersion, "/"); i >= 0 {
+		return apiVersion[:i]
+	}
+	return ""
+}
+
+// installerRBAC returns the ClusterRole, the Roles and the bindings allowing
+// the installer to create objs, and to run the other calls of install, and
+// uninstall if configured.
+func installerRBAC(objs []*manifest.Object, ic *InstallConfig, rc *installerRBACConfig) []map[string]interface{} {
+	verbs := installVerbs
+	if rc.Uninstall {
+		verbs = uninstallVerbs
+	}
+
+	cluster := make(ruleSet)
+	namespaced := make(map[string]ruleSet)
+	nsRules := func(ns string) ruleSet {
+		if namespaced[ns] == nil {
+			namespaced[ns] = make(ruleSet)
+		}
+		return namespaced[ns]
+	}
+	for _, o := range objs {
+		ns := o.Namespace
+		if ns == "" {
+			ns = ic.Namespace
+		}
+		rs := cluster
+		if !o.ClusterScoped() {
+			rs = nsRules(ns)
+		}
+		rs.add(apiGroup(o.APIVersion), resourceName(o.Kind), verbs...)
+
+		// Granting permissions the installer doesn't hold itself
+		// requires escalate on roles and bind on the roles referenced
+		// by bindings.
+		switch o.Kind {
+		case "ClusterRole", "ClusterRoleBinding":
+			cluster.add("rbac.authorization.k8s.io", "clusterroles", "bind", "escalate")
+		case "Role", "RoleBinding":
+			nsRules(ns).add("rbac.authorization.k8s.io", "roles", "bind", "escalate")
+		}
+	}
+
+	// The API server and controller manager pods are restarted to load
+	// new certificates.
+	nsRules(ic.Namespace).add("", "pods", "list", "delete")
+	// Install and uninstall hold the lock of the catalog namespace, a
+	// Lease in kube-system or a ConfigMap on clusters not serving Leases.
+	nsRules(leaseNamespace).add("coordination.k8s.io", "leases", "get", "create", "update", "patch", "delete")
+	nsRules(leaseNamespace).add("", "configmaps", "get", "create", "update", "patch", "delete")
+	if ic.EtcdBackup || (ic.EtcdMaintenance && ic.EtcdMaintenanceBucket == "") {
+		cluster.add("storage.k8s.io", "storageclasses", "get")
+	}
+	if ic.HistoryMax > 0 {
+		// The revisions are stored as secrets, the superseded ones are
+		// relabeled and those beyond --history-max deleted.
+		nsRules(ic.Namespace).add("", "secrets", "get", "list", "create", "patch", "delete")
+	}
+	if ic.ReadyTimeout > 0 {
+		// Install watches the rollouts and the events meanwhile.
+		nsRules(ic.Namespace).add("apps", "deployments", "watch")
+		nsRules(ic.Namespace).add("", "events", "list", "watch")
+	}
+	if rc.Uninstall {
+		// Uninstall checks for instances losing their resources, and
+		// deletes them with --strategy graceful. The brokers are listed
+		// in the uninstall report. --strategy fast stops the controller
+		// manager, deletes all the catalog resources and strips their
+		// finalizers.
+		cluster.add("servicecatalog.k8s.io", "serviceinstances", "get", "list", "delete", "patch")
+		cluster.add("servicecatalog.k8s.io", "servicebindings", "get", "list", "delete", "patch")
+		cluster.add("servicecatalog.k8s.io", "clusterservicebrokers", "list", "delete", "patch")
+		cluster.add("servicecatalog.k8s.io", "servicebrokers", "list", "delete", "patch")
+		cluster.add("servicecatalog.k8s.io", "clusterserviceclasses", "list", "delete", "patch")
+		cluster.add("servicecatalog.k8s.io", "clusterserviceplans", "list", "delete", "patch")
+		nsRules(ic.Namespace).add("apps", "deployments/scale", "get", "patch", "update")
+	}
+
+	subject := map[string]interface{}{"kind": "ServiceAccount", "name": installerRBACName, "namespace": rc.Namespace}
+	if rc.User != "" {
+		subject = map[string]interface{}{"apiGroup": "rbac.authorization.k8s.io", "kind": "User", "name": rc.User}
+	}
+	binding := func(kind, roleKind, ns string) map[string]interface{} {
+		metadata := map[string]interface{}{"name": installerRBACName}
+		if ns != "" {
+			metadata["namespace"] = ns
+		}
+		return map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       kind,
+			"metadata":   metadata,
+			"roleRef":    map[string]interface{}{"apiGroup": "rbac.authorization.k8s.io", "kind": roleKind, "name": installerRBACName},
+			"subjects":   []interface{}{subject},
+		}
+	}
+
+	result := []map[string]interface{}{
+		{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRole",
+			"metadata":   map[string]interface{}{"name": installerRBACName},
+			"rules":      cluster.rules(),
+		},
+		binding("ClusterRoleBinding", "ClusterRole", ""),
+	}
+
+	var namespaces []string
+	for ns := range namespaced {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		// The Roles are created before the installer creates the
+		// namespaces.
+		if ns != "kube-system" && ns != "default" {
+			result = append(result, map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+				"metadata":   map[string]interface{}{"name": ns},
+			})
+		}
+		result = append(result, map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "Role",
+			"metadata":   map[string]interface{}{"name": installerRBACName, "namespace": ns},
+			"rules":      namespaced[ns].rules(),
+		}, binding("RoleBinding", "Role", ns))
+	}
+	return result
+}
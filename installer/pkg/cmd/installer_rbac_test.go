@@ -0,0 +1,174 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestInstallerRBAC tests that the installer is granted the objects it
+// creates, cluster-scoped ones by the ClusterRole and namespaced ones by a
+// Role per namespace, and the lock of the namespace.
+func TestInstallerRBAC(t *testing.T) {
+	objs := []*manifest.Object{
+		{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "a"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "apiserver", Namespace: "service-catalog"},
+		{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy", Name: "etcd", Namespace: "service-catalog"},
+	}
+	ic := &InstallConfig{Namespace: "service-catalog"}
+	rc := &installerRBACConfig{Namespace: "sc-installer", Uninstall: true}
+
+	result := installerRBAC(objs, ic, rc)
+	var kinds []string
+	for _, o := range result {
+		kinds = append(kinds, o["kind"].(string))
+	}
+	if want := []string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding", "Namespace", "Role", "RoleBinding"}; !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("Kinds do not match: got %v; want %v", kinds, want)
+	}
+
+	wantCluster := []interface{}{
+		map[string]interface{}{
+			"apiGroups": []string{"rbac.authorization.k8s.io"},
+			"resources": []string{"clusterroles"},
+			"verbs":     []string{"bind", "create", "delete", "escalate", "get", "list", "patch", "update"},
+		},
+		map[string]interface{}{
+			"apiGroups": []string{"servicecatalog.k8s.io"},
+			"resources": []string{"servicebindings", "serviceinstances"},
+			"verbs":     []string{"delete", "get", "list", "patch"},
+		},
+		map[string]interface{}{
+			"apiGroups": []string{"servicecatalog.k8s.io"},
+			"resources": []string{"clusterservicebrokers", "clusterserviceclasses", "clusterserviceplans", "servicebrokers"},
+			"verbs":     []string{"delete", "list", "patch"},
+		},
+	}
+	if got := result[0]["rules"]; !reflect.DeepEqual(got, wantCluster) {
+		t.Fatalf("ClusterRole rules do not match: got %v; want %v", got, wantCluster)
+	}
+
+	wantNamespaced := []interface{}{
+		map[string]interface{}{
+			"apiGroups": []string{""},
+			"resources": []string{"pods"},
+			"verbs":     []string{"delete", "list"},
+		},
+		map[string]interface{}{
+			"apiGroups": []string{"apps"},
+			"resources": []string{"deployments"},
+			"verbs":     []string{"create", "delete", "get", "list", "patch", "update"},
+		},
+		map[string]interface{}{
+			"apiGroups": []string{"apps"},
+			"resources": []string{"deployments/scale"},
+			"verbs":     []string{"get", "patch", "update"},
+		},
+		map[string]interface{}{
+			"apiGroups": []string{"networking.k8s.io"},
+			"resources": []string{"networkpolicies"},
+			"verbs":     []string{"create", "delete", "get", "list", "patch", "update"},
+		},
+	}
+	if got := result[5]["rules"]; !reflect.DeepEqual(got, wantNamespaced) {
+		t.Fatalf("Role rules do not match: got %v; want %v", got, wantNamespaced)
+	}
+
+	// The lock of the namespace is held in kube-system.
+	wantLock := []interface{}{
+		map[string]interface{}{
+			"apiGroups": []string{""},
+			"resources": []string{"configmaps"},
+			"verbs":     []string{"create", "delete", "get", "patch", "update"},
+		},
+		map[string]interface{}{
+			"apiGroups": []string{"coordination.k8s.io"},
+			"resources": []string{"leases"},
+			"verbs":     []string{"create", "delete", "get", "patch", "update"},
+		},
+	}
+	if meta := result[2]["metadata"].(map[string]interface{}); meta["namespace"] != leaseNamespace {
+		t.Fatalf("Expected the lock Role in %s, got %v", leaseNamespace, meta)
+	}
+	if got := result[2]["rules"]; !reflect.DeepEqual(got, wantLock) {
+		t.Fatalf("Lock Role rules do not match: got %v; want %v", got, wantLock)
+	}
+}
+
+// TestInstallerRBACHistory tests that the installer may prune the revision
+// secrets beyond --history-max even without --uninstall.
+func TestInstallerRBACHistory(t *testing.T) {
+	objs := []*manifest.Object{{APIVersion: "apps/v1", Kind: "Deployment", Name: "apiserver", Namespace: "service-catalog"}}
+	rc := &installerRBACConfig{Namespace: "sc-installer"}
+
+	secretVerbs := func(ic *InstallConfig) []string {
+		for _, o := range installerRBAC(objs, ic, rc) {
+			if o["kind"] != "Role" || o["metadata"].(map[string]interface{})["namespace"] != ic.Namespace {
+				continue
+			}
+			for _, r := range o["rules"].([]interface{}) {
+				r := r.(map[string]interface{})
+				if containsString(r["resources"].([]string), "secrets") {
+					return r["verbs"].([]string)
+				}
+			}
+		}
+		return nil
+	}
+	if got, want := secretVerbs(&InstallConfig{Namespace: "service-catalog", HistoryMax: 10}), []string{"create", "delete", "get", "list", "patch"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Secret verbs do not match: got %v; want %v", got, want)
+	}
+	if got := secretVerbs(&InstallConfig{Namespace: "service-catalog"}); got != nil {
+		t.Fatalf("Expected no access to secrets without history, got %v", got)
+	}
+}
+
+// TestBootstrapInstallerRBAC tests that the bootstrap job is granted the
+// roles of the install of its arguments rather than cluster-admin.
+func TestBootstrapInstallerRBAC(t *testing.T) {
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		if args[0] == "version" {
+			return execx.Response{Stdout: `{"serverVersion": {"gitVersion": "v1.10.4"}}`}
+		}
+		return execx.Response{ExitCode: 1}
+	})
+	defer restore()
+
+	bc := &bootstrapJobConfig{Namespace: "sc-installer", Args: []string{"install", "--profile=minimal", "--name-prefix=blue-"}}
+	rbac, err := bootstrapInstallerRBAC(bc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"kind: ClusterRole\n", "kind: Role\n", "namespace: blue-service-catalog\n", "namespace: sc-installer\n"} {
+		if !strings.Contains(rbac, want) {
+			t.Fatalf("RBAC does not contain %q:\n%s", want, rbac)
+		}
+	}
+	if strings.Contains(rbac, "cluster-admin") {
+		t.Fatalf("RBAC grants cluster-admin:\n%s", rbac)
+	}
+
+	bc.Args = []string{"uninstall"}
+	if _, err := bootstrapInstallerRBAC(bc); err == nil {
+		t.Fatalf("Expected an error for --args uninstall")
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// instanceWaitConfig contains the instance wait configuration.
+type instanceWaitConfig struct {
+	// Timeout is how long to wait for the instance.
+	Timeout time.Duration
+
+	// PollInterval is how often the instance is read.
+	PollInterval time.Duration
+}
+
+// serviceInstance is the subset of a ServiceInstance read to follow its
+// provisioning.
+type serviceInstance struct {
+	Metadata struct {
+		Name       string `json:"name"`
+		Namespace  string `json:"namespace"`
+		Generation int64  `json:"generation"`
+	} `json:"metadata"`
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+		AsyncOpInProgress          bool   `json:"asyncOpInProgress"`
+		OrphanMitigationInProgress bool   `json:"orphanMitigationInProgress"`
+		CurrentOperation           string `json:"currentOperation"`
+		ObservedGeneration         int64  `json:"observedGeneration"`
+	} `json:"status"`
+}
+
+// instanceReasons translates the reasons the controller manager sets on the
+// Ready condition of instances into progress descriptions.
+var instanceReasons = map[string]string{
+	"ProvisionRequestInFlight":          "sending the provision request to the broker",
+	"Provisioning":                      "the broker is provisioning the instance",
+	"ProvisionedSuccessfully":           "provisioned",
+	"ProvisionCallFailed":               "the provision request failed, retrying",
+	"UpdateInstanceRequestInFlight":     "sending the update request to the broker",
+	"UpdatingInstance":                  "the broker is updating the instance",
+	"InstanceUpdatedSuccessfully":       "updated",
+	"UpdateInstanceCallFailed":          "the update request failed, retrying",
+	"DeprovisionRequestInFlight":        "sending the deprovision request to the broker",
+	"Deprovisioning":                    "the broker is deprovisioning the instance",
+	"DeprovisionedSuccessfully":         "deprovisioned",
+	"DeprovisionCallFailed":             "the deprovision request failed, retrying",
+	"ErrorPollingLastOperation":         "error polling the last operation of the broker, retrying",
+	"ReferencesNonexistentServiceClass": "waiting for the service class to exist",
+	"ReferencesNonexistentServicePlan":  "waiting for the service plan to exist",
+	"ErrorWithParameters":               "invalid parameters",
+}
+
+func NewInstanceCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "instance",
+		Short: "commands for ServiceInstances",
+	}
+	c.AddCommand(newInstanceWaitCmd())
+	return c
+}
+
+func newInstanceWaitCmd() *cobra.Command {
+	wc := &instanceWaitConfig{}
+	c := &cobra.Command{
+		Use:   "wait <namespace>/<name>",
+		Short: "waits for a ServiceInstance to be provisioned",
+		Long: `waits for the current operation on a ServiceInstance, e.g. provisioning,
+to complete, printing its progress, and fails with the error of the broker
+if the operation fails.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parts := strings.Split(args[0], "/")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("invalid instance %q: expected <namespace>/<name>", args[0])
+			}
+			return waitForInstance(parts[0], parts[1], wc)
+		},
+	}
+	c.Flags().DurationVar(&wc.Timeout, "timeout", 30*time.Minute, "How long to wait for the instance")
+	c.Flags().DurationVar(&wc.PollInterval, "poll-interval", 5*time.Second, "How often to read the instance")
+	return c
+}
+
+func waitForInstance(ns, name string, wc *instanceWaitConfig) error {
+	deadline := time.Now().Add(wc.Timeout)
+	last := ""
+	for {
+		output, err := kubectlCommand("get", "serviceinstances.servicecatalog.k8s.io", name,
+			"--namespace", ns, "-o", "json").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error getting instance %s/%s: %s", ns, name, strings.TrimSpace(string(output)))
+		}
+		var si serviceInstance
+		if err := json.Unmarshal(output, &si); err != nil {
+			return fmt.Errorf("error unmarshalling instance %s/%s: %v", ns, name, err)
+		}
+
+		line, done, err := instanceProgress(&si)
+		if line != last {
+			fmt.Printf("%s %s\n", time.Now().Format("15:04:05"), line)
+			last = line
+		}
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("instance %s/%s is not ready after %v", ns, name, wc.Timeout)
+		}
+		time.Sleep(wc.PollInterval)
+	}
+}
+
+// instanceProgress describes the progress of the current operation on si.
+// done is true once the operation succeeded, and the error is set if it
+// failed.
+func instanceProgress(si *serviceInstance) (line string, done bool, err error) {
+	op := si.Status.CurrentOperation
+	if op == "" {
+		op = "instance"
+	}
+
+	var ready, failedMsg, reason, message string
+	for _, c := range si.Status.Conditions {
+		switch c.Type {
+		case "Ready":
+			ready, reason, message = c.Status, c.Reason, c.Message
+		case "Failed":
+			if c.Status == "True" {
+				failedMsg = c.Message
+			}
+		}
+	}
+
+	if failedMsg != "" {
+		return fmt.Sprintf("%s failed: %s", op, failedMsg), false,
+			fmt.Errorf("instance %s/%s failed: %s", si.Metadata.Namespace, si.Metadata.Name, failedMsg)
+	}
+
+	if reason == "" {
+		return "waiting for the controller manager to process the instance", false, nil
+	}
+	desc, ok := instanceReasons[reason]
+	if !ok {
+		desc = reason
+	}
+	line = op + ": " + desc
+	switch {
+	case si.Status.OrphanMitigationInProgress:
+		line += " (cleaning up after a failed provision)"
+	case si.Status.AsyncOpInProgress:
+		line += " (polling the broker)"
+	}
+	// Show the details of unknown and failed states.
+	if message != "" && (!ok || strings.Contains(reason, "Fail") || strings.HasPrefix(reason, "Error")) {
+		line += ": " + message
+	}
+
+	done = ready == "True" && si.Status.CurrentOperation == "" &&
+		si.Status.ObservedGeneration >= si.Metadata.Generation
+	return line, done, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestInstanceProgress tests that instance conditions are translated into
+// progress lines, and that success and failure are detected.
+func TestInstanceProgress(t *testing.T) {
+	for _, tc := range []struct {
+		instance string
+		wantLine string
+		wantDone bool
+		wantErr  bool
+	}{
+		{
+			instance: `{"metadata": {"generation": 1}, "status": {}}`,
+			wantLine: "waiting for the controller manager to process the instance",
+		},
+		{
+			instance: `{"metadata": {"generation": 1}, "status": {"currentOperation": "Provision", "asyncOpInProgress": true,
+				"conditions": [{"type": "Ready", "status": "False", "reason": "Provisioning", "message": "The instance is being provisioned asynchronously"}]}}`,
+			wantLine: "Provision: the broker is provisioning the instance (polling the broker)",
+		},
+		{
+			instance: `{"metadata": {"generation": 1}, "status": {"currentOperation": "Provision",
+				"conditions": [{"type": "Ready", "status": "False", "reason": "ProvisionCallFailed", "message": "broker returned 500"}]}}`,
+			wantLine: "Provision: the provision request failed, retrying: broker returned 500",
+		},
+		{
+			instance: `{"metadata": {"generation": 2}, "status": {"observedGeneration": 2,
+				"conditions": [{"type": "Ready", "status": "True", "reason": "ProvisionedSuccessfully"}]}}`,
+			wantLine: "instance: provisioned",
+			wantDone: true,
+		},
+		{
+			instance: `{"metadata": {"generation": 2}, "status": {"observedGeneration": 1,
+				"conditions": [{"type": "Ready", "status": "True", "reason": "ProvisionedSuccessfully"}]}}`,
+			wantLine: "instance: provisioned",
+		},
+		{
+			instance: `{"metadata": {"namespace": "ns", "name": "db", "generation": 1}, "status": {"currentOperation": "Provision",
+				"conditions": [
+					{"type": "Ready", "status": "False", "reason": "ProvisionCallFailed"},
+					{"type": "Failed", "status": "True", "reason": "ProvisionCallFailed", "message": "quota exceeded"}
+				]}}`,
+			wantLine: "Provision failed: quota exceeded",
+			wantErr:  true,
+		},
+	} {
+		var si serviceInstance
+		if err := json.Unmarshal([]byte(tc.instance), &si); err != nil {
+			t.Fatalf("Unexpected error unmarshalling instance: %v", err)
+		}
+		line, done, err := instanceProgress(&si)
+		if line != tc.wantLine {
+			t.Fatalf("Progress does not match: got %q; want %q", line, tc.wantLine)
+		}
+		if done != tc.wantDone {
+			t.Fatalf("Done does not match for %q: got %v; want %v", line, done, tc.wantDone)
+		}
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Fatalf("Error does not match for %q: got %v; want error %v", line, err, tc.wantErr)
+		}
+	}
+}
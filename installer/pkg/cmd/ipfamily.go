@@ -0,0 +1,80 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+// IP families of clusters.
+const (
+	ipFamilyIPv4 = "ipv4"
+	ipFamilyIPv6 = "ipv6"
+	ipFamilyDual = "dual"
+)
+
+// svcCatalogFiles returns the templates to render for ic, without those of
+// the components it skips. etcd-operator only listens on IPv4, so IPv6-only
+// clusters get a single member etcd StatefulSet instead.
+func svcCatalogFiles(ic *InstallConfig) []string {
+	if ic.IPFamily != ipFamilyIPv6 {
+		return skipFiles(ic, svcCatalogFileNames)
+	}
+
+	var files []string
+	for _, f := range svcCatalogFileNames {
+		switch f {
+		case "etcd-operator":
+			files = append(files, "etcd-svc", "etcd")
+		case "etcd-cluster-with-backup":
+		default:
+			files = append(files, f)
+		}
+	}
+	return skipFiles(ic, files)
+}
+
+// etcdServers returns the URL of etcd for the api server.
+func etcdServers(ic *InstallConfig) string {
+	if ic.SkipEtcd {
+		return ic.EtcdServers
+	}
+	if ic.MigratedEtcdServers != "" {
+		return ic.MigratedEtcdServers
+	}
+	if ic.IPFamily == ipFamilyIPv6 {
+		return "http://etcd-svc:2379"
+	}
+	return "http://etcd-cluster-client:2379"
+}
+
+// listenAddress returns the wildcard address servers listen on. On Linux
+// "::" also accepts IPv4 connections.
+func listenAddress(ic *InstallConfig) string {
+	if ic.IPFamily == ipFamilyIPv4 {
+		return "0.0.0.0"
+	}
+	return "[::]"
+}
+
+// loopbackIPs returns the IP SANs of the api server certificate, so that it
+// is also valid for in-pod connections to the loopback address.
+func loopbackIPs(ic *InstallConfig) []string {
+	switch ic.IPFamily {
+	case ipFamilyIPv6:
+		return []string{"::1"}
+	case ipFamilyDual:
+		return []string{"127.0.0.1", "::1"}
+	}
+	return []string{"127.0.0.1"}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestSvcCatalogFiles tests that IPv6-only clusters get the standalone etcd
+// instead of etcd-operator.
+func TestSvcCatalogFiles(t *testing.T) {
+	contains := func(files []string, f string) bool {
+		for _, file := range files {
+			if file == f {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, fam := range []string{ipFamilyIPv4, ipFamilyDual} {
+		files := svcCatalogFiles(&InstallConfig{IPFamily: fam})
+		if !contains(files, "etcd-operator") || contains(files, "etcd") {
+			t.Fatalf("Files for %s must use etcd-operator: got %v", fam, files)
+		}
+	}
+
+	files := svcCatalogFiles(&InstallConfig{IPFamily: ipFamilyIPv6})
+	if contains(files, "etcd-operator") || contains(files, "etcd-cluster-with-backup") || !contains(files, "etcd") || !contains(files, "etcd-svc") {
+		t.Fatalf("Files for ipv6 must use the standalone etcd: got %v", files)
+	}
+}
+
+// TestRenderIPv6Etcd tests that the standalone etcd is rendered with the
+// StatefulSet API version of the cluster, not the Deployment one.
+func TestRenderIPv6Etcd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ipv6")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ic := validInstallConfig()
+	ic.IPFamily = ipFamilyIPv6
+	ic.EtcdBackup = false
+	ic.EtcdClusterSize = 1
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing manifests: %v", err)
+	}
+	want := clusterAPIVersions().StatefulSet
+	for _, o := range objs {
+		if o.Kind == "StatefulSet" && o.Name == "etcd" {
+			if o.APIVersion != want {
+				t.Fatalf("API version of %s does not match: got %s; want %s", o, o.APIVersion, want)
+			}
+			return
+		}
+	}
+	t.Fatalf("No etcd StatefulSet rendered")
+}
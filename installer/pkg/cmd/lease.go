@@ -0,0 +1,398 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The operations changing a catalog hold a coordination.k8s.io Lease of its
+// namespace in the cluster, so that two operators or CI jobs, which do not
+// share the workspace locks of a machine, do not change it at once. The
+// lease is renewed while the operation runs and expires if the process
+// dies without releasing it. Clusters before 1.14 do not serve Leases, the
+// lease is then kept in an annotation of a ConfigMap, as the leader
+// election of client-go did.
+const (
+	// leaseNamespace holds the leases, it exists before the catalog
+	// namespace is created and after it is deleted.
+	leaseNamespace = "kube-system"
+
+	// leaseDuration is how long a lease lasts without being renewed.
+	leaseDuration = 60 * time.Second
+
+	// leaseOperationAnnotation names the operation holding a lease.
+	leaseOperationAnnotation = "servicecatalog.k8s.io/operation"
+
+	// leaseRecordAnnotation holds the spec of a lease kept in a ConfigMap,
+	// as JSON.
+	leaseRecordAnnotation = "servicecatalog.k8s.io/lease"
+
+	// leaseTimeFormat is the format of the MicroTime of the leases.
+	leaseTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+	// leaseAPIVersion is the API version of the Lease objects.
+	leaseAPIVersion = "coordination.k8s.io/v1"
+)
+
+// Kinds of the objects holding the leases.
+const (
+	leaseKindLease     = "lease"
+	leaseKindConfigMap = "configmap"
+)
+
+// leaseRenewInterval is how often a held lease is renewed.
+var leaseRenewInterval = leaseDuration / 3
+
+// leaseLost aborts the process when a lease it holds was taken over or
+// could not be renewed before it expired, so that the operation does not
+// go on next to the one holding it now. Tests replace it.
+var leaseLost = func(ns, reason string) {
+	fmt.Fprintf(os.Stderr, "ERROR: lost the lock of namespace %s: %s; aborting so that two sc operations do not change it at once\n", ns, reason)
+	os.Exit(1)
+}
+
+// clusterLocks are the leases held by the process, by catalog namespace,
+// so that operations nest, e.g. the install of a restore.
+var clusterLocks = struct {
+	sync.Mutex
+	held map[string]*clusterLock
+}{held: make(map[string]*clusterLock)}
+
+// clusterLock is a lease held by the process.
+type clusterLock struct {
+	ns     string
+	kind   string
+	name   string
+	holder string
+	count  int
+	stop   chan struct{}
+	done   chan struct{}
+
+	// renewed is when the lease was last renewed.
+	renewed time.Time
+}
+
+// leaseSpec is the part of the spec of a Lease sc uses.
+type leaseSpec struct {
+	HolderIdentity       string `json:"holderIdentity"`
+	LeaseDurationSeconds int    `json:"leaseDurationSeconds"`
+	AcquireTime          string `json:"acquireTime"`
+	RenewTime            string `json:"renewTime"`
+}
+
+// lease is the part of a Lease, or of the ConfigMap holding it, sc uses.
+type lease struct {
+	Metadata struct {
+		ResourceVersion string            `json:"resourceVersion"`
+		Annotations     map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec leaseSpec `json:"spec"`
+}
+
+// expires returns when l expires, the zero time if it is not valid.
+func (l *lease) expires() time.Time {
+	t, err := time.Parse(time.RFC3339Nano, l.Spec.RenewTime)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.Add(time.Duration(l.Spec.LeaseDurationSeconds) * time.Second)
+}
+
+// leaseName returns the name of the lease of catalog namespace ns.
+func leaseName(ns string) string {
+	return "sc-lock-" + ns
+}
+
+// leaseHolder returns the holder identity of the leases of the process.
+func leaseHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// leaseKind returns the kind of the objects holding the leases: Leases if
+// the cluster serves them, ConfigMaps otherwise.
+func leaseKind() (string, error) {
+	served, err := servedAPIVersions()
+	if err != nil {
+		return "", fmt.Errorf("error listing the API versions: %v", err)
+	}
+	if served[leaseAPIVersion] {
+		return leaseKindLease, nil
+	}
+	return leaseKindConfigMap, nil
+}
+
+// leaseObject returns the lease name of kind held by holder for operation
+// since now, replacing the version resourceVersion if not empty.
+func leaseObject(kind, name, holder, operation string, now time.Time, resourceVersion string) ([]byte, error) {
+	annotations := map[string]string{
+		leaseOperationAnnotation: operation,
+	}
+	metadata := map[string]interface{}{
+		"name":      name,
+		"namespace": leaseNamespace,
+		"labels": map[string]string{
+			"app.kubernetes.io/managed-by": "sc",
+		},
+		"annotations": annotations,
+	}
+	if resourceVersion != "" {
+		metadata["resourceVersion"] = resourceVersion
+	}
+	t := now.UTC().Format(leaseTimeFormat)
+	spec := leaseSpec{
+		HolderIdentity:       holder,
+		LeaseDurationSeconds: int(leaseDuration / time.Second),
+		AcquireTime:          t,
+		RenewTime:            t,
+	}
+	if kind == leaseKindConfigMap {
+		record, err := json.Marshal(spec)
+		if err != nil {
+			return nil, err
+		}
+		annotations[leaseRecordAnnotation] = string(record)
+		return json.Marshal(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   metadata,
+		})
+	}
+	return json.Marshal(map[string]interface{}{
+		"apiVersion": leaseAPIVersion,
+		"kind":       "Lease",
+		"metadata":   metadata,
+		"spec":       spec,
+	})
+}
+
+// getLease returns the lease name of kind, nil if it does not exist.
+func getLease(kind, name string) (*lease, error) {
+	output, err := kubectlCommand("get", kind, name, "--namespace", leaseNamespace,
+		"--ignore-not-found", "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting lease %s: %s", name, strings.TrimSpace(string(output)))
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+	l := &lease{}
+	if err := json.Unmarshal(output, l); err != nil {
+		return nil, fmt.Errorf("error parsing lease %s: %v", name, err)
+	}
+	if kind == leaseKindConfigMap {
+		// A ConfigMap without a valid record is an expired lease.
+		if record := l.Metadata.Annotations[leaseRecordAnnotation]; record != "" {
+			if err := json.Unmarshal([]byte(record), &l.Spec); err != nil {
+				return nil, fmt.Errorf("error parsing lease %s: %v", name, err)
+			}
+		}
+	}
+	return l, nil
+}
+
+// lockCluster acquires the lease of catalog namespace ns for operation,
+// taking it over from its holder if it expired or with force, and returns
+// the function releasing it. It fails if another process holds the lease.
+func lockCluster(ns, operation string, force bool) (unlock func(), err error) {
+	clusterLocks.Lock()
+	defer clusterLocks.Unlock()
+	unlock = func() { unlockCluster(ns) }
+	if l, ok := clusterLocks.held[ns]; ok {
+		l.count++
+		return unlock, nil
+	}
+
+	kind, err := leaseKind()
+	if err != nil {
+		return nil, err
+	}
+	name, holder := leaseName(ns), leaseHolder()
+	now := time.Now()
+	b, err := leaseObject(kind, name, holder, operation, now, "")
+	if err != nil {
+		return nil, err
+	}
+	cmd := kubectlCommand("create", "-f", "-")
+	cmd.Stdin = bytes.NewReader(b)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if !strings.Contains(string(output), "AlreadyExists") {
+			return nil, fmt.Errorf("error creating lease %s/%s: %s", leaseNamespace, name, strings.TrimSpace(string(output)))
+		}
+		if err := takeOverLease(kind, name, holder, operation, ns, now, force); err != nil {
+			return nil, err
+		}
+	}
+
+	l := &clusterLock{ns: ns, kind: kind, name: name, holder: holder, count: 1, stop: make(chan struct{}), done: make(chan struct{}), renewed: now}
+	go l.renew()
+	clusterLocks.held[ns] = l
+	return unlock, nil
+}
+
+// takeOverLease takes the existing lease name of kind of catalog namespace
+// ns over for holder if it expired or with force.
+func takeOverLease(kind, name, holder, operation, ns string, now time.Time, force bool) error {
+	existing, err := getLease(kind, name)
+	if err != nil {
+		return err
+	}
+	var resourceVersion string
+	if existing != nil {
+		resourceVersion = existing.Metadata.ResourceVersion
+		if expires := existing.expires(); now.Before(expires) {
+			if !force {
+				return fmt.Errorf("another sc operation (%s by %s, since %s) holds the lock of namespace %s until %s unless renewed; if it is stale, rerun with --force-unlock",
+					existing.Metadata.Annotations[leaseOperationAnnotation], existing.Spec.HolderIdentity,
+					existing.Spec.AcquireTime, ns, expires.Format(time.RFC3339))
+			}
+			fmt.Printf("WARNING: taking over the lock of namespace %s held by %s (--force-unlock)\n", ns, existing.Spec.HolderIdentity)
+		}
+	}
+
+	// Replacing the version read fails if another process took the lease
+	// meanwhile, creating it if it was released.
+	b, err := leaseObject(kind, name, holder, operation, now, resourceVersion)
+	if err != nil {
+		return err
+	}
+	verb := "replace"
+	if existing == nil {
+		verb = "create"
+	}
+	cmd := kubectlCommand(verb, "-f", "-")
+	cmd.Stdin = bytes.NewReader(b)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error taking over lease %s/%s, another sc operation may have taken it: %s", leaseNamespace, name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// renew renews the lease of l until it is stopped, aborting the process if
+// the lease is lost.
+func (l *clusterLock) renew() {
+	defer close(l.done)
+	t := time.NewTicker(leaseRenewInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-t.C:
+		}
+		now := time.Now()
+		lost, err := l.renewOnce(now)
+		switch {
+		case lost != "":
+			leaseLost(l.ns, lost)
+			return
+		case err != nil && now.Sub(l.renewed) >= leaseDuration:
+			leaseLost(l.ns, fmt.Sprintf("it expired without being renewed: %v", err))
+			return
+		case err != nil:
+			fmt.Printf("WARNING: %v\n", err)
+		default:
+			l.renewed = now
+		}
+	}
+}
+
+// renewOnce renews the lease of l at now if l still holds it. It returns
+// why the lease is lost, if it is, or the error renewing it.
+func (l *clusterLock) renewOnce(now time.Time) (lost string, err error) {
+	existing, err := getLease(l.kind, l.name)
+	if err != nil {
+		return "", err
+	}
+	if existing == nil {
+		return fmt.Sprintf("lease %s/%s was deleted", leaseNamespace, l.name), nil
+	}
+	if existing.Spec.HolderIdentity != l.holder {
+		return fmt.Sprintf("%s took lease %s/%s over", existing.Spec.HolderIdentity, leaseNamespace, l.name), nil
+	}
+
+	// The resource version makes the patch fail if the lease changed
+	// since it was read.
+	spec := existing.Spec
+	spec.RenewTime = now.UTC().Format(leaseTimeFormat)
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{"resourceVersion": existing.Metadata.ResourceVersion},
+	}
+	if l.kind == leaseKindConfigMap {
+		record, err := json.Marshal(spec)
+		if err != nil {
+			return "", err
+		}
+		patch["metadata"].(map[string]interface{})["annotations"] = map[string]string{leaseRecordAnnotation: string(record)}
+	} else {
+		patch["spec"] = map[string]string{"renewTime": spec.RenewTime}
+	}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	output, err := kubectlCommand("patch", l.kind, l.name, "--namespace", leaseNamespace,
+		"--type", "merge", "-p", string(b)).CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "Conflict") {
+			return fmt.Sprintf("lease %s/%s was changed by another process", leaseNamespace, l.name), nil
+		}
+		return "", fmt.Errorf("error renewing lease %s/%s: %s", leaseNamespace, l.name, strings.TrimSpace(string(output)))
+	}
+	return "", nil
+}
+
+// unlockCluster releases a hold of the lease of catalog namespace ns,
+// deleting it with the last one unless another process took it over.
+func unlockCluster(ns string) {
+	clusterLocks.Lock()
+	defer clusterLocks.Unlock()
+	l, ok := clusterLocks.held[ns]
+	if !ok {
+		return
+	}
+	if l.count--; l.count > 0 {
+		return
+	}
+	delete(clusterLocks.held, ns)
+	close(l.stop)
+	<-l.done
+
+	existing, err := getLease(l.kind, l.name)
+	if err != nil {
+		fmt.Printf("WARNING: %v\n", err)
+		return
+	}
+	if existing == nil || existing.Spec.HolderIdentity != l.holder {
+		return
+	}
+	output, err := kubectlCommand("delete", l.kind, l.name, "--namespace", leaseNamespace, "--ignore-not-found").CombinedOutput()
+	if err != nil {
+		fmt.Printf("WARNING: error releasing lease %s/%s: %s\n", leaseNamespace, l.name, strings.TrimSpace(string(output)))
+	}
+}
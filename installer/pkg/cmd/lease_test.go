@@ -0,0 +1,202 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// leaseJSON returns a lease of holder renewed at renewed.
+func leaseJSON(holder string, renewed time.Time) string {
+	return fmt.Sprintf(`{"metadata":{"resourceVersion":"7","annotations":{%q:"install"}},"spec":{"holderIdentity":%q,"leaseDurationSeconds":60,"acquireTime":%q,"renewTime":%q}}`,
+		leaseOperationAnnotation, holder, renewed.UTC().Format(leaseTimeFormat), renewed.UTC().Format(leaseTimeFormat))
+}
+
+// leaseStub responds to the lease commands as if the lease were held by
+// holder, renewed at renewed, or did not exist if holder is empty.
+func leaseStub(holder string, renewed time.Time) func(name string, args []string) execx.Response {
+	return func(name string, args []string) execx.Response {
+		switch args[0] {
+		case "api-versions":
+			return execx.Response{Stdout: "v1\n" + leaseAPIVersion + "\n"}
+		case "create":
+			if holder != "" {
+				return execx.Response{Stderr: `Error from server (AlreadyExists): error when creating "STDIN": leases.coordination.k8s.io "sc-lock-catalog" already exists`, ExitCode: 1}
+			}
+		case "get":
+			if holder != "" {
+				return execx.Response{Stdout: leaseJSON(holder, renewed)}
+			}
+		}
+		return execx.Response{}
+	}
+}
+
+// TestLockCluster tests that the lease is created, shared by nested
+// operations and deleted with the last one.
+func TestLockCluster(t *testing.T) {
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch args[0] {
+		case "api-versions":
+			return execx.Response{Stdout: leaseAPIVersion + "\n"}
+		case "get":
+			return execx.Response{Stdout: leaseJSON(leaseHolder(), time.Now())}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	outer, err := lockCluster("catalog", "dr-restore", false)
+	if err != nil {
+		t.Fatalf("Unexpected error locking: %v", err)
+	}
+	inner, err := lockCluster("catalog", "install", false)
+	if err != nil {
+		t.Fatalf("Unexpected error locking again: %v", err)
+	}
+	inner()
+	if calls := kubectlCalls(s, "create", "delete"); len(calls) != 1 || calls[0] != "create -f -" {
+		t.Fatalf("Expected the lease to be created once and kept, got %v", calls)
+	}
+	outer()
+	want := "delete lease sc-lock-catalog --namespace kube-system --ignore-not-found"
+	if calls := kubectlCalls(s, "delete"); len(calls) != 1 || calls[0] != want {
+		t.Fatalf("Expected the lease to be deleted, got %v", calls)
+	}
+}
+
+// TestLockClusterHeld tests that a lease held by another process fails the
+// operation unless it expired or --force-unlock is set, and is then
+// replaced.
+func TestLockClusterHeld(t *testing.T) {
+	for _, tc := range []struct {
+		renewed time.Time
+		force   bool
+		locked  bool
+	}{
+		{time.Now(), false, true},
+		{time.Now(), true, false},
+		{time.Now().Add(-2 * leaseDuration), false, false},
+	} {
+		s, restore := stubExecutor(leaseStub("ci-runner-42", tc.renewed))
+		unlock, err := lockCluster("catalog", "install", tc.force)
+		if tc.locked {
+			if err == nil || !strings.Contains(err.Error(), "install by ci-runner-42") || !strings.Contains(err.Error(), "--force-unlock") {
+				t.Fatalf("Expected an error naming the holder, got %v", err)
+			}
+		} else {
+			if err != nil {
+				t.Fatalf("Unexpected error taking over the lease: %v", err)
+			}
+			if calls := kubectlCalls(s, "replace"); len(calls) != 1 {
+				t.Fatalf("Expected the lease to be replaced, got %v", calls)
+			}
+			unlock()
+			// The stub still reports the other holder.
+			if calls := kubectlCalls(s, "delete"); len(calls) != 0 {
+				t.Fatalf("Expected the lease of another holder to be kept, got %v", calls)
+			}
+		}
+		restore()
+	}
+}
+
+// TestLockClusterRenew tests that a held lease is renewed only while it is
+// held, conditionally on its version, and that the operation is aborted
+// once another process took it over.
+func TestLockClusterRenew(t *testing.T) {
+	var mu sync.Mutex
+	holder := leaseHolder()
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		switch args[0] {
+		case "api-versions":
+			return execx.Response{Stdout: leaseAPIVersion + "\n"}
+		case "get":
+			return execx.Response{Stdout: leaseJSON(holder, time.Now())}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+	interval := leaseRenewInterval
+	leaseRenewInterval = 10 * time.Millisecond
+	defer func() { leaseRenewInterval = interval }()
+	lost := make(chan string, 1)
+	oldLost := leaseLost
+	leaseLost = func(ns, reason string) { lost <- reason }
+	defer func() { leaseLost = oldLost }()
+
+	unlock, err := lockCluster("catalog", "upgrade", false)
+	if err != nil {
+		t.Fatalf("Unexpected error locking: %v", err)
+	}
+	defer unlock()
+	time.Sleep(50 * time.Millisecond)
+	calls := kubectlCalls(s, "patch")
+	want := `patch lease sc-lock-catalog --namespace kube-system --type merge -p {"metadata":{"resourceVersion":"7"},"spec":{"renewTime":`
+	if len(calls) == 0 || !strings.HasPrefix(calls[0], want) {
+		t.Fatalf("Expected the lease to be renewed, got %v", calls)
+	}
+
+	mu.Lock()
+	holder = "ci-runner-42"
+	mu.Unlock()
+	select {
+	case reason := <-lost:
+		if !strings.Contains(reason, "ci-runner-42 took lease kube-system/sc-lock-catalog over") {
+			t.Fatalf("Unexpected reason %q", reason)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the lease to be lost")
+	}
+}
+
+// TestLockClusterConfigMap tests that the lease is kept in a ConfigMap on
+// clusters not serving Leases.
+func TestLockClusterConfigMap(t *testing.T) {
+	record := fmt.Sprintf(`{"holderIdentity":"ci-runner-42","leaseDurationSeconds":60,"renewTime":%q}`, time.Now().UTC().Format(leaseTimeFormat))
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch args[0] {
+		case "api-versions":
+			return execx.Response{Stdout: "v1\napps/v1beta1\n"}
+		case "create":
+			return execx.Response{Stderr: `Error from server (AlreadyExists): configmaps "sc-lock-catalog" already exists`, ExitCode: 1}
+		case "get":
+			return execx.Response{Stdout: fmt.Sprintf(`{"metadata":{"resourceVersion":"3","annotations":{%q:%q}}}`, leaseRecordAnnotation, record)}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	if _, err := lockCluster("catalog", "install", false); err == nil || !strings.Contains(err.Error(), "ci-runner-42") {
+		t.Fatalf("Expected an error naming the holder, got %v", err)
+	}
+	b, err := leaseObject(leaseKindConfigMap, "sc-lock-catalog", "me", "install", time.Now(), "")
+	if err != nil || !strings.Contains(string(b), `"kind":"ConfigMap"`) || !strings.Contains(string(b), leaseRecordAnnotation) {
+		t.Fatalf("Expected a ConfigMap holding the lease, got %s, %v", b, err)
+	}
+	if calls := kubectlCalls(s, "get"); len(calls) != 1 || !strings.HasPrefix(calls[0], "get configmap sc-lock-catalog") {
+		t.Fatalf("Expected the ConfigMap to be read, got %v", calls)
+	}
+}
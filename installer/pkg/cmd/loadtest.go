@@ -0,0 +1,534 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// loadtestBroker names the no-op broker of the load test, its
+	// Deployment and Service.
+	loadtestBroker = "sc-loadtest"
+
+	// loadtestBrokerImage is the user-provided service broker of the
+	// service catalog project, which provisions and binds without
+	// creating anything.
+	loadtestBrokerImage = "quay.io/kubernetes-service-catalog/user-broker:v0.1.11"
+
+	// loadtestClass and loadtestPlan are the class and plan of the
+	// user-provided service broker.
+	loadtestClass = "user-provided-service"
+	loadtestPlan  = "default"
+
+	// loadtestLabel labels the objects the load test creates, so that
+	// only those are deleted afterwards, not those of the namespace.
+	loadtestLabel    = "servicecatalog.k8s.io/loadtest"
+	loadtestSelector = loadtestLabel + "=true"
+)
+
+// loadtestPollInterval is how often the load test checks that its
+// instances are deleted, and waits before watching an object again.
+var loadtestPollInterval = time.Second
+
+// loadtestConfig contains the load test configuration.
+type loadtestConfig struct {
+	// Namespace is the namespace of the broker and of the instances and
+	// bindings of the load test.
+	Namespace string
+
+	// CatalogNamespace is the namespace Service Catalog is installed in,
+	// whose pods are sampled.
+	CatalogNamespace string
+
+	// Instances is the number of instances provisioned and bound.
+	Instances int
+
+	// Concurrency is the number of instances provisioned at once.
+	Concurrency int
+
+	// Timeout is how long to wait for an instance or binding, and for the
+	// broker, to be ready.
+	Timeout time.Duration
+
+	// SampleInterval is how often the resource usage of the Service
+	// Catalog pods is sampled.
+	SampleInterval time.Duration
+
+	// BrokerImage is the image of the no-op broker, e.g. a mirror of
+	// loadtestBrokerImage.
+	BrokerImage string
+
+	// Keep keeps the broker, instances and bindings once the load test is
+	// done.
+	Keep bool
+}
+
+// loadtestResult holds the measurements of a load test.
+type loadtestResult struct {
+	// Provision and Bind are the latencies of the instances and bindings
+	// that became ready.
+	Provision []time.Duration
+	Bind      []time.Duration
+
+	// Failures are the errors of the instances that failed.
+	Failures []string
+
+	// Elapsed is the duration of the load test.
+	Elapsed time.Duration
+
+	// Peak is the peak resource usage of the Service Catalog containers,
+	// nil if the usage could not be sampled.
+	Peak []*containerUsage
+}
+
+func NewLoadTestCmd() *cobra.Command {
+	lc := &loadtestConfig{}
+	c := &cobra.Command{
+		Use:   "loadtest",
+		Short: "measures the provision and bind latencies of Service Catalog under load",
+		Long: `deploys a no-op broker, provisions and binds --instances instances,
+--concurrency at a time, and reports the percentiles of the provision and
+bind latencies, which are those of the catalog control plane since the
+broker returns at once, and the peak CPU and memory usage of the api server,
+etcd and controller manager, as reported by metrics-server. Use it to size
+the control plane before a production rollout. The broker, instances and
+bindings are deleted afterwards unless --keep is given.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := runLoadTest(os.Stdout, lc); err != nil {
+				messages.Println(messages.LoadTestFailed)
+				return err
+			}
+			messages.Println(messages.LoadTestCompleted, lc.Instances)
+			return nil
+		},
+	}
+	c.Flags().IntVar(&lc.Instances, "instances", 100, "Number of instances to provision and bind")
+	c.Flags().IntVar(&lc.Concurrency, "concurrency", 10, "Number of instances provisioned at once")
+	c.Flags().StringVar(&lc.Namespace, "namespace", loadtestBroker, "Namespace of the broker, instances and bindings of the load test")
+	c.Flags().StringVar(&lc.CatalogNamespace, "catalog-namespace", defaultNamespace, "Namespace Service Catalog is installed in")
+	c.Flags().DurationVar(&lc.Timeout, "timeout", 5*time.Minute, "How long to wait for an instance or binding to be ready")
+	c.Flags().DurationVar(&lc.SampleInterval, "sample-interval", 10*time.Second, "How often the resource usage of the Service Catalog pods is sampled")
+	c.Flags().StringVar(&lc.BrokerImage, "broker-image", loadtestBrokerImage, "Image of the no-op broker, e.g. a mirror in a private registry")
+	c.Flags().BoolVar(&lc.Keep, "keep", false, "Keep the broker, instances and bindings after the load test")
+	return c
+}
+
+func (lc *loadtestConfig) validate() error {
+	if !dns1123LabelRE.MatchString(lc.Namespace) {
+		return fmt.Errorf("--namespace %q must be a lowercase DNS label", lc.Namespace)
+	}
+	if lc.Instances < 1 {
+		return fmt.Errorf("--instances must be positive, got %d", lc.Instances)
+	}
+	if lc.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be positive, got %d", lc.Concurrency)
+	}
+	if lc.Timeout <= 0 {
+		return fmt.Errorf("--timeout must be positive, got %v", lc.Timeout)
+	}
+	if lc.SampleInterval <= 0 {
+		return fmt.Errorf("--sample-interval must be positive, got %v", lc.SampleInterval)
+	}
+	return nil
+}
+
+// runLoadTest runs the load test of lc and writes its report to w. It fails
+// if an instance failed, after the report.
+func runLoadTest(w io.Writer, lc *loadtestConfig) (err error) {
+	if err := lc.validate(); err != nil {
+		return err
+	}
+	ws, err := createWorkspace(lc.Namespace, "loadtest", workspace.Options{Cleanup: workspace.OnSuccess})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+
+	// Only a namespace the load test creates is deleted afterwards.
+	output, err := kubectlCommand("get", "namespace", lc.Namespace, "--ignore-not-found", "-o", "name").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error getting namespace %s: %s", lc.Namespace, strings.TrimSpace(string(output)))
+	}
+	createNamespace := strings.TrimSpace(string(output)) == ""
+	if err := generateConfigs(ws.Dir, "templates/installer/", []string{"loadtest-broker"}, map[string]interface{}{
+		"Namespace":       lc.Namespace,
+		"CreateNamespace": createNamespace,
+		"Broker":          loadtestBroker,
+		"Image":           lc.BrokerImage,
+		"Label":           loadtestLabel,
+	}); err != nil {
+		return fmt.Errorf("error generating manifests: %v", err)
+	}
+	fmt.Fprintf(w, "deploying the no-op broker %s\n", loadtestBroker)
+	if err := deployConfig(ws.Dir, applyOptions{}); err != nil {
+		return err
+	}
+	if !lc.Keep {
+		defer func() {
+			if cerr := cleanupLoadTest(w, lc, createNamespace); cerr != nil {
+				fmt.Fprintf(w, "WARNING: %v\n", cerr)
+			}
+		}()
+	}
+	if err := waitForCatalogObject("clusterservicebrokers.servicecatalog.k8s.io", "", loadtestBroker, lc.Timeout); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "provisioning and binding %d instances, %d at a time\n", lc.Instances, lc.Concurrency)
+	stop := make(chan struct{})
+	peak := make(chan []*containerUsage)
+	go func() { peak <- samplePeakUsage(w, lc.CatalogNamespace, lc.SampleInterval, stop) }()
+
+	r := runLoadTestInstances(lc)
+	close(stop)
+	r.Peak = <-peak
+
+	printLoadTestReport(w, lc, r)
+	if len(r.Failures) > 0 {
+		return fmt.Errorf("%d of %d instances failed", len(r.Failures), lc.Instances)
+	}
+	return nil
+}
+
+// runLoadTestInstances provisions and binds the instances of lc,
+// lc.Concurrency at a time.
+func runLoadTestInstances(lc *loadtestConfig) *loadtestResult {
+	r := &loadtestResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	next := make(chan int)
+	start := time.Now()
+	for i := 0; i < lc.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range next {
+				provision, bind, err := loadtestInstance(lc, n)
+				mu.Lock()
+				if provision > 0 {
+					r.Provision = append(r.Provision, provision)
+				}
+				if bind > 0 {
+					r.Bind = append(r.Bind, bind)
+				}
+				if err != nil {
+					r.Failures = append(r.Failures, err.Error())
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for n := 0; n < lc.Instances; n++ {
+		next <- n
+	}
+	close(next)
+	wg.Wait()
+	r.Elapsed = time.Since(start)
+	sort.Strings(r.Failures)
+	return r
+}
+
+// loadtestInstance provisions and binds instance n of the load test and
+// returns the latencies of the steps that succeeded.
+func loadtestInstance(lc *loadtestConfig, n int) (provision, bind time.Duration, err error) {
+	name := fmt.Sprintf("%s-%d", loadtestBroker, n)
+	start := time.Now()
+	if err := createObject(fmt.Sprintf(`apiVersion: %s
+kind: ServiceInstance
+metadata:
+  name: %s
+  namespace: %s
+  labels:
+    %s: "true"
+spec:
+  clusterServiceClassExternalName: %s
+  clusterServicePlanExternalName: %s
+`, scAPIVersion, name, lc.Namespace, loadtestLabel, loadtestClass, loadtestPlan)); err != nil {
+		return 0, 0, err
+	}
+	if err := waitForCatalogObject("serviceinstances.servicecatalog.k8s.io", lc.Namespace, name, lc.Timeout); err != nil {
+		return 0, 0, err
+	}
+	provision = time.Since(start)
+
+	start = time.Now()
+	if err := createObject(fmt.Sprintf(`apiVersion: %s
+kind: ServiceBinding
+metadata:
+  name: %s
+  namespace: %s
+  labels:
+    %s: "true"
+spec:
+  instanceRef:
+    name: %s
+`, scAPIVersion, name, lc.Namespace, loadtestLabel, name)); err != nil {
+		return provision, 0, err
+	}
+	if err := waitForCatalogObject("servicebindings.servicecatalog.k8s.io", lc.Namespace, name, lc.Timeout); err != nil {
+		return provision, 0, err
+	}
+	return provision, time.Since(start), nil
+}
+
+// createObject creates the object of manifest m.
+func createObject(m string) error {
+	cmd := kubectlCommand("create", "-f", "-")
+	cmd.Stdin = strings.NewReader(m)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error creating object: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// waitForCatalogObject waits for the catalog object name of resource in
+// namespace ns, empty for cluster-scoped ones, to be ready. It watches the
+// object, so that the latencies measured are not rounded to a poll
+// interval, and fails once the object failed or after timeout.
+func waitForCatalogObject(resource, ns, name string, timeout time.Duration) error {
+	args := []string{"get", resource, name, "-o", "json", "--watch"}
+	if ns != "" {
+		args = append(args, "--namespace", ns)
+		name = ns + "/" + name
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		ready, err := watchCatalogObject(args, resource, name, deadline)
+		if err != nil || ready {
+			return err
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("%s %s is not ready after %v", resource, name, timeout)
+		}
+		// The watch ended, e.g. at the server timeout, watch again.
+		time.Sleep(loadtestPollInterval)
+	}
+}
+
+// watchCatalogObject runs the kubectl watch args of the catalog object name
+// of resource until it is ready, failed, the watch ends or deadline. It
+// returns whether the object is ready.
+func watchCatalogObject(args []string, resource, name string, deadline time.Time) (bool, error) {
+	cmd := kubectlCommand(args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return false, err
+	}
+	if err := cmd.Start(); err != nil {
+		return false, fmt.Errorf("error watching %s %s: %v", resource, name, err)
+	}
+	timer := time.AfterFunc(time.Until(deadline), func() { execx.Kill(cmd) })
+	defer timer.Stop()
+
+	ready, err := readCatalogConditions(json.NewDecoder(stdout), resource, name)
+	// Stop the watch once the object is ready or failed.
+	execx.Kill(cmd)
+	werr := cmd.Wait()
+	if err != nil || ready {
+		return ready, err
+	}
+	if werr != nil && time.Now().Before(deadline) && stderr.Len() > 0 {
+		return false, fmt.Errorf("error getting %s %s: %s", resource, name, strings.TrimSpace(stderr.String()))
+	}
+	return false, nil
+}
+
+// readCatalogConditions reads the versions of the catalog object name of
+// resource from d until it is ready or failed, or d ends.
+func readCatalogConditions(d *json.Decoder, resource, name string) (bool, error) {
+	for {
+		var o serviceInstance
+		if err := d.Decode(&o); err != nil {
+			// The watch ended, possibly in the middle of an object.
+			return false, nil
+		}
+		for _, c := range o.Status.Conditions {
+			switch {
+			case c.Type == "Ready" && c.Status == "True":
+				return true, nil
+			case c.Type == "Failed" && c.Status == "True":
+				return false, fmt.Errorf("%s %s failed: %s", resource, name, c.Message)
+			}
+		}
+	}
+}
+
+// samplePeakUsage samples the resource usage of the pods in ns every
+// interval until stop is closed, and returns the peak usage of each
+// container, sorted by pod and container. It returns nil if the usage could
+// not be sampled, e.g. without metrics-server.
+func samplePeakUsage(w io.Writer, ns string, interval time.Duration, stop <-chan struct{}) []*containerUsage {
+	peak := make(map[string]*containerUsage)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		usage, err := serviceCatalogUsage(ns)
+		if err != nil {
+			fmt.Fprintf(w, "WARNING: not reporting the resource usage: %v\n", err)
+			return nil
+		}
+		for _, u := range usage {
+			key := u.Pod + "/" + u.Container
+			p, ok := peak[key]
+			if !ok {
+				peak[key] = u
+				continue
+			}
+			p.CPU = math.Max(p.CPU, u.CPU)
+			p.Memory = math.Max(p.Memory, u.Memory)
+		}
+		select {
+		case <-stop:
+			var peaks []*containerUsage
+			for _, u := range peak {
+				peaks = append(peaks, u)
+			}
+			sort.Slice(peaks, func(i, j int) bool {
+				if peaks[i].Pod != peaks[j].Pod {
+					return peaks[i].Pod < peaks[j].Pod
+				}
+				return peaks[i].Container < peaks[j].Container
+			})
+			return peaks
+		case <-t.C:
+		}
+	}
+}
+
+// percentile returns the p-th percentile, 0 < p <= 100, of the sorted
+// latencies d by the nearest-rank method, 0 if d is empty.
+func percentile(d []time.Duration, p float64) time.Duration {
+	if len(d) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(d))))
+	if rank < 1 {
+		rank = 1
+	}
+	return d[rank-1]
+}
+
+func printLoadTestReport(out io.Writer, lc *loadtestConfig, r *loadtestResult) {
+	fmt.Fprintf(out, "provisioned %d and bound %d of %d instances in %v (%.1f instances/s)\n",
+		len(r.Provision), len(r.Bind), lc.Instances, r.Elapsed.Round(time.Second),
+		float64(len(r.Bind))/r.Elapsed.Seconds())
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "\tP50\tP90\tP99\tMAX")
+	for _, l := range []struct {
+		name string
+		d    []time.Duration
+	}{
+		{"provision", r.Provision},
+		{"bind", r.Bind},
+	} {
+		sorted := append([]time.Duration(nil), l.d...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", l.name,
+			formatLatency(percentile(sorted, 50)), formatLatency(percentile(sorted, 90)),
+			formatLatency(percentile(sorted, 99)), formatLatency(percentile(sorted, 100)))
+	}
+	w.Flush()
+
+	if len(r.Peak) > 0 {
+		fmt.Fprintln(out, "peak resource usage:")
+		w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "POD\tCONTAINER\tCPU\tLIMIT\tMEMORY\tLIMIT")
+		for _, u := range r.Peak {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", u.Pod, u.Container,
+				formatCPU(u.CPU), formatCPU(u.CPULimit), formatMemory(u.Memory), formatMemory(u.MemoryLimit))
+		}
+		w.Flush()
+	}
+
+	for _, f := range r.Failures {
+		fmt.Fprintf(out, "failed: %s\n", f)
+	}
+}
+
+// formatLatency formats d to the millisecond, "-" if there is none.
+func formatLatency(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// cleanupLoadTest deletes the bindings and instances of the load test,
+// waits for the broker to deprovision them, and deletes the broker, and
+// its namespace if the load test created it. Only the objects labeled by
+// the load test are deleted.
+func cleanupLoadTest(w io.Writer, lc *loadtestConfig, createdNamespace bool) error {
+	fmt.Fprintf(w, "deleting the instances and bindings of the load test\n")
+	output, err := kubectlCommand("delete", "servicebindings.servicecatalog.k8s.io,serviceinstances.servicecatalog.k8s.io",
+		"-l", loadtestSelector, "--namespace", lc.Namespace, "--wait=false").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deleting the load test instances: %s", strings.TrimSpace(string(output)))
+	}
+	// The broker must outlive the instances to deprovision them.
+	deadline := time.Now().Add(lc.Timeout)
+	for {
+		output, err := kubectlCommand("get", "serviceinstances.servicecatalog.k8s.io",
+			"-l", loadtestSelector, "--namespace", lc.Namespace, "-o", "name").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error listing the load test instances: %s", strings.TrimSpace(string(output)))
+		}
+		if strings.TrimSpace(string(output)) == "" {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("the load test instances in %s are not deleted after %v, delete the objects labeled %s once they are", lc.Namespace, lc.Timeout, loadtestSelector)
+		}
+		time.Sleep(loadtestPollInterval)
+	}
+
+	output, err = kubectlCommand("delete", "clusterservicebrokers.servicecatalog.k8s.io", loadtestBroker, "--ignore-not-found").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deleting broker %s: %s", loadtestBroker, strings.TrimSpace(string(output)))
+	}
+	if !createdNamespace {
+		output, err = kubectlCommand("delete", "deployments,services", "-l", loadtestSelector,
+			"--namespace", lc.Namespace, "--wait=false").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error deleting the broker %s: %s", loadtestBroker, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+	output, err = kubectlCommand("delete", "namespace", lc.Namespace, "--ignore-not-found", "--wait=false").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deleting namespace %s: %s", lc.Namespace, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
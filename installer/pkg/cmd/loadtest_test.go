@@ -0,0 +1,155 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestPercentile tests the nearest-rank percentiles of latencies.
+func TestPercentile(t *testing.T) {
+	var d []time.Duration
+	for i := 1; i <= 10; i++ {
+		d = append(d, time.Duration(i)*time.Second)
+	}
+	for _, c := range []struct {
+		p    float64
+		want time.Duration
+	}{
+		{50, 5 * time.Second},
+		{90, 9 * time.Second},
+		{99, 10 * time.Second},
+		{100, 10 * time.Second},
+		{1, time.Second},
+	} {
+		if got := percentile(d, c.p); got != c.want {
+			t.Fatalf("Percentile %v does not match: got %v; want %v", c.p, got, c.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Fatalf("Expected 0 without latencies, got %v", got)
+	}
+}
+
+// TestRunLoadTestInstances tests that every instance is provisioned and
+// bound, the instance pending before ready is waited for, and the failed
+// bindings are reported without a bind latency.
+func TestRunLoadTestInstances(t *testing.T) {
+	defer func(d time.Duration) { loadtestPollInterval = d }(loadtestPollInterval)
+	loadtestPollInterval = 0
+
+	ready := `{"status": {"conditions": [{"type": "Ready", "status": "True"}]}}`
+	pending := `{"status": {"conditions": [{"type": "Ready", "status": "False", "reason": "ProvisionRequestInFlight"}]}}`
+	failed := `{"status": {"conditions": [{"type": "Ready", "status": "False"}, {"type": "Failed", "status": "True", "message": "quota"}]}}`
+	gets := 0
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		if args[0] != "get" {
+			return execx.Response{}
+		}
+		switch {
+		case args[1] == "serviceinstances.servicecatalog.k8s.io" && args[2] == "sc-loadtest-0":
+			// The watch ends before the instance is ready, the
+			// second one sees it ready.
+			if gets++; gets == 1 {
+				return execx.Response{Stdout: pending}
+			}
+			return execx.Response{Stdout: pending + "\n" + ready}
+		case args[1] == "servicebindings.servicecatalog.k8s.io" && args[2] == "sc-loadtest-2":
+			return execx.Response{Stdout: failed}
+		}
+		return execx.Response{Stdout: ready}
+	})
+	defer restore()
+
+	lc := &loadtestConfig{Namespace: "sc-loadtest", Instances: 3, Concurrency: 2, Timeout: time.Minute}
+	r := runLoadTestInstances(lc)
+	if len(r.Provision) != 3 || len(r.Bind) != 2 {
+		t.Fatalf("Expected 3 provision and 2 bind latencies, got %d and %d", len(r.Provision), len(r.Bind))
+	}
+	want := []string{"servicebindings.servicecatalog.k8s.io sc-loadtest/sc-loadtest-2 failed: quota"}
+	if !reflect.DeepEqual(r.Failures, want) {
+		t.Fatalf("Failures do not match:\ngot  %q\nwant %q", r.Failures, want)
+	}
+	if creates := kubectlCalls(s, "create"); len(creates) != 6 {
+		t.Fatalf("Expected 6 creates, got %q", creates)
+	}
+
+	var b bytes.Buffer
+	r.Peak = []*containerUsage{{Pod: "apiserver-0", Container: "etcd", CPU: 0.25, Memory: 128 << 20}}
+	printLoadTestReport(&b, lc, r)
+	for _, line := range []string{"provisioned 3 and bound 2 of 3 instances", "provision", "apiserver-0  etcd       250m", "failed: servicebindings"} {
+		if !strings.Contains(b.String(), line) {
+			t.Fatalf("Report does not contain %q:\n%s", line, b.String())
+		}
+	}
+}
+
+// TestLoadTestConfigValidate tests that invalid load tests are rejected.
+func TestLoadTestConfigValidate(t *testing.T) {
+	valid := loadtestConfig{Namespace: "sc-loadtest", Instances: 10, Concurrency: 2, Timeout: time.Minute, SampleInterval: time.Second}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("Unexpected error validating config: %v", err)
+	}
+	for _, c := range []struct {
+		change func(lc *loadtestConfig)
+		want   string
+	}{
+		{func(lc *loadtestConfig) { lc.Namespace = "Load" }, "--namespace"},
+		{func(lc *loadtestConfig) { lc.Instances = 0 }, "--instances"},
+		{func(lc *loadtestConfig) { lc.Concurrency = 0 }, "--concurrency"},
+		{func(lc *loadtestConfig) { lc.SampleInterval = 0 }, "--sample-interval"},
+	} {
+		lc := valid
+		c.change(&lc)
+		if err := lc.validate(); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("Error does not match: got %v; want %q", err, c.want)
+		}
+	}
+}
+
+// TestCleanupLoadTest tests that only the objects labeled by the load test
+// are deleted, and its namespace only if the load test created it.
+func TestCleanupLoadTest(t *testing.T) {
+	for _, created := range []bool{true, false} {
+		s, restore := stubExecutor(func(name string, args []string) execx.Response {
+			return execx.Response{}
+		})
+		lc := &loadtestConfig{Namespace: "default", Timeout: time.Minute}
+		if err := cleanupLoadTest(ioutil.Discard, lc, created); err != nil {
+			t.Fatalf("Unexpected error cleaning up: %v", err)
+		}
+		restore()
+		calls := kubectlCalls(s, "delete")
+		if want := "delete servicebindings.servicecatalog.k8s.io,serviceinstances.servicecatalog.k8s.io -l " + loadtestSelector + " --namespace default --wait=false"; calls[0] != want {
+			t.Fatalf("Expected the labeled instances to be deleted, got %v", calls)
+		}
+		last := calls[len(calls)-1]
+		if created && last != "delete namespace default --ignore-not-found --wait=false" {
+			t.Fatalf("Expected the created namespace to be deleted, got %v", calls)
+		}
+		if !created && (strings.HasPrefix(last, "delete namespace") || !strings.Contains(last, "-l "+loadtestSelector)) {
+			t.Fatalf("Expected only the labeled broker to be deleted, got %v", calls)
+		}
+	}
+}
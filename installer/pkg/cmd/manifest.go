@@ -0,0 +1,108 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+func NewManifestCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "manifest",
+		Short: "inspects the manifests of an install",
+		Long:  "inspects the manifests an install with the same flags applies",
+		Args:  cobra.MinimumNArgs(1),
+	}
+	c.AddCommand(newManifestShowCmd())
+	return c
+}
+
+func newManifestShowCmd() *cobra.Command {
+	ic := newInstallConfig()
+	c := &cobra.Command{
+		Use:   "show <component>",
+		Short: "prints the manifest of a component",
+		Long: `prints the manifest of a component, e.g. apiserver-deployment, rendered
+with the flags given as an install with the same flags would apply it. The
+certificates and keys are not generated, their data is left empty. Nothing is
+created.
+
+Components: ` + strings.Join(manifestComponents(), ", "),
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: manifestComponents(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyProfile(ic, cmd.Flags()); err != nil {
+				return err
+			}
+			if err := applyNameAffixes(ic); err != nil {
+				return err
+			}
+			if err := showManifest(os.Stdout, ic, args[0]); err != nil {
+				messages.Println(messages.ManifestFailed)
+				return err
+			}
+			return nil
+		},
+	}
+	addInstallFlags(c, ic)
+	return c
+}
+
+// manifestComponents returns the components whose manifests manifest show
+// prints, the names of the service catalog templates.
+func manifestComponents() []string {
+	var names []string
+	for _, a := range AssetNames() {
+		if strings.HasPrefix(a, "templates/sc/") && strings.HasSuffix(a, ".yaml.tmpl") {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(a, "templates/sc/"), ".yaml.tmpl"))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// showManifest writes the manifest of component rendered for ic to w.
+func showManifest(w io.Writer, ic *InstallConfig, component string) error {
+	if !containsString(manifestComponents(), component) {
+		return fmt.Errorf("unknown component %q, use one of %s", component, strings.Join(manifestComponents(), ", "))
+	}
+	if err := ic.Validate(); err != nil {
+		return err
+	}
+	detectCapabilities(ic)
+	if !containsString(svcCatalogFiles(ic), component) {
+		// Warn on stderr, the manifest is printed to stdout.
+		fmt.Fprintf(os.Stderr, "WARNING: an install with these flags does not apply %s\n", component)
+	}
+
+	data, err := catalogTemplateData(ic, nil)
+	if err != nil {
+		return err
+	}
+	tp, err := parseTemplate("templates/sc/" + component + ".yaml.tmpl")
+	if err != nil {
+		return err
+	}
+	return tp.Execute(w, data)
+}
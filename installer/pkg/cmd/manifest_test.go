@@ -0,0 +1,58 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestShowManifest tests that a component is rendered with the flags
+// applied, as in the manifests of an install, and that unknown components
+// are rejected.
+func TestShowManifest(t *testing.T) {
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		return execx.Response{}
+	})
+	defer restore()
+
+	ic := validInstallConfig()
+	ic.APIServerReplicas = 3
+	var b bytes.Buffer
+	if err := showManifest(&b, ic, "apiserver-deployment"); err != nil {
+		t.Fatalf("Unexpected error showing manifest: %v", err)
+	}
+	objs, err := manifest.Parse("apiserver-deployment.yaml", b.Bytes())
+	if err != nil {
+		t.Fatalf("Unexpected error parsing manifest: %v\n%s", err, b.String())
+	}
+	if len(objs) != 1 || objs[0].Kind != "Deployment" || objs[0].Namespace != ic.Namespace {
+		t.Fatalf("Expected the api server Deployment in %s, got %v", ic.Namespace, objs)
+	}
+	if !strings.Contains(b.String(), "replicas: 3") {
+		t.Fatalf("Expected 3 replicas:\n%s", b.String())
+	}
+
+	err = showManifest(&b, ic, "apiserver")
+	if err == nil || !strings.Contains(err.Error(), "apiserver-deployment") {
+		t.Fatalf("Expected an error listing the components, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// marketplaceCacheFile is the name of the marketplace cache file in the
+// installer state directory.
+const marketplaceCacheFile = "marketplace-cache.json"
+
+// marketplaceConfig contains the marketplace configuration.
+type marketplaceConfig struct {
+	// Refresh fetches the classes and plans from the cluster even if a
+	// cache exists, failing if the cluster is unreachable.
+	Refresh bool
+
+	// Cached lists the cached classes and plans without contacting the
+	// cluster.
+	Cached bool
+}
+
+// marketplace is the merged list of service classes and plans, as cached.
+type marketplace struct {
+	FetchedAt time.Time          `json:"fetchedAt"`
+	Entries   []marketplaceEntry `json:"entries"`
+}
+
+// marketplaceEntry is a single plan of a service class.
+type marketplaceEntry struct {
+	Class            string `json:"class"`
+	ClassDescription string `json:"classDescription,omitempty"`
+	Broker           string `json:"broker,omitempty"`
+	Plan             string `json:"plan"`
+	PlanDescription  string `json:"planDescription,omitempty"`
+	Free             bool   `json:"free"`
+}
+
+// catalogObjectList is the subset of a list of ClusterServiceClasses or
+// ClusterServicePlans read by the installer.
+type catalogObjectList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			ExternalName             string `json:"externalName"`
+			Description              string `json:"description"`
+			ClusterServiceBrokerName string `json:"clusterServiceBrokerName"`
+			Free                     bool   `json:"free"`
+			ClusterServiceClassRef   struct {
+				Name string `json:"name"`
+			} `json:"clusterServiceClassRef"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+func NewMarketplaceCmd() *cobra.Command {
+	mc := &marketplaceConfig{}
+	c := &cobra.Command{
+		Use:   "marketplace",
+		Short: "lists the service classes and plans offered by the brokers",
+		Long: `lists the service classes and plans offered by the brokers registered
+with Service Catalog. The list is cached locally and the cache is used when
+the cluster is unreachable.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := listMarketplace(mc); err != nil {
+				messages.Println(messages.MarketplaceFailed)
+				return err
+			}
+			return nil
+		},
+	}
+	c.Flags().BoolVar(&mc.Refresh, "refresh", false, "Fetch the classes and plans from the cluster, do not fall back to the cache")
+	c.Flags().BoolVar(&mc.Cached, "cached", false, "List the cached classes and plans without contacting the cluster")
+	return c
+}
+
+func listMarketplace(mc *marketplaceConfig) error {
+	if mc.Refresh && mc.Cached {
+		return fmt.Errorf("--refresh and --cached are mutually exclusive")
+	}
+
+	cacheFile, err := stateFilePath(marketplaceCacheFile)
+	if err != nil {
+		return err
+	}
+
+	var m *marketplace
+	if mc.Cached {
+		if m, err = readMarketplaceCache(cacheFile); err != nil {
+			return err
+		}
+	} else {
+		m, err = fetchMarketplace()
+		if err != nil {
+			if mc.Refresh {
+				return err
+			}
+			fmt.Printf("WARNING: using the cached marketplace, error fetching it from the cluster: %v\n", err)
+			if m, err = readMarketplaceCache(cacheFile); err != nil {
+				return err
+			}
+		} else if err := writeMarketplaceCache(cacheFile, m); err != nil {
+			fmt.Printf("WARNING: error caching the marketplace: %v\n", err)
+		}
+	}
+
+	printMarketplace(m)
+	return nil
+}
+
+// fetchMarketplace lists the classes and plans in the cluster.
+func fetchMarketplace() (*marketplace, error) {
+	classes, err := getCatalogObjects("clusterserviceclasses")
+	if err != nil {
+		return nil, err
+	}
+	plans, err := getCatalogObjects("clusterserviceplans")
+	if err != nil {
+		return nil, err
+	}
+	return &marketplace{
+		FetchedAt: time.Now().UTC(),
+		Entries:   mergeMarketplace(classes, plans),
+	}, nil
+}
+
+func getCatalogObjects(resource string) (*catalogObjectList, error) {
+	output, err := kubectlCommand("get", resource, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %s", resource, string(output))
+	}
+	var l catalogObjectList
+	if err := json.Unmarshal(output, &l); err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %v", resource, err)
+	}
+	return &l, nil
+}
+
+// mergeMarketplace joins plans with the classes they belong to. The result
+// is sorted by class and plan name. Plans of unknown classes are skipped.
+func mergeMarketplace(classes, plans *catalogObjectList) []marketplaceEntry {
+	type class struct{ name, description, broker string }
+	byName := make(map[string]class)
+	for _, c := range classes.Items {
+		byName[c.Metadata.Name] = class{c.Spec.ExternalName, c.Spec.Description, c.Spec.ClusterServiceBrokerName}
+	}
+
+	var entries []marketplaceEntry
+	for _, p := range plans.Items {
+		c, ok := byName[p.Spec.ClusterServiceClassRef.Name]
+		if !ok {
+			continue
+		}
+		entries = append(entries, marketplaceEntry{
+			Class:            c.name,
+			ClassDescription: c.description,
+			Broker:           c.broker,
+			Plan:             p.Spec.ExternalName,
+			PlanDescription:  p.Spec.Description,
+			Free:             p.Spec.Free,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Class != entries[j].Class {
+			return entries[i].Class < entries[j].Class
+		}
+		return entries[i].Plan < entries[j].Plan
+	})
+	return entries
+}
+
+func readMarketplaceCache(path string) (*marketplace, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no cached marketplace, run `sc marketplace --refresh` while the cluster is reachable")
+		}
+		return nil, fmt.Errorf("error reading the marketplace cache: %v", err)
+	}
+	var m marketplace
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the marketplace cache %s: %v", path, err)
+	}
+	fmt.Printf("cached %s ago\n", time.Since(m.FetchedAt).Round(time.Second))
+	return &m, nil
+}
+
+func writeMarketplaceCache(path string, m *marketplace) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+func printMarketplace(m *marketplace) {
+	if len(m.Entries) == 0 {
+		fmt.Println("no service classes found")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CLASS\tPLAN\tFREE\tBROKER\tDESCRIPTION")
+	for _, e := range m.Entries {
+		fmt.Fprintf(w, "%s\t%s\t%v\t%s\t%s\n", e.Class, e.Plan, e.Free, e.Broker, e.PlanDescription)
+	}
+	w.Flush()
+}
+
+// stateFilePath returns the path of file name in the installer state
+// directory, .sc in the home directory of the user, e.g. $HOME or
+// %USERPROFILE%, creating the directory if needed.
+func stateFilePath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating the installer state directory: %v", err)
+	}
+	dir := filepath.Join(home, ".sc")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating the installer state directory: %v", err)
+	}
+	return filepath.Join(dir, name), nil
+}
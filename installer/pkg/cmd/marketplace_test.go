@@ -0,0 +1,52 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestMergeMarketplace tests that plans are joined with their classes,
+// sorted, and that plans of unknown classes are skipped.
+func TestMergeMarketplace(t *testing.T) {
+	var classes, plans catalogObjectList
+	if err := json.Unmarshal([]byte(`{"items": [
+		{"metadata": {"name": "c1"}, "spec": {"externalName": "pubsub", "clusterServiceBrokerName": "gcp-broker"}},
+		{"metadata": {"name": "c2"}, "spec": {"externalName": "bigquery", "clusterServiceBrokerName": "gcp-broker"}}
+	]}`), &classes); err != nil {
+		t.Fatalf("Unexpected error unmarshalling classes: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"items": [
+		{"metadata": {"name": "p1"}, "spec": {"externalName": "beta", "clusterServiceClassRef": {"name": "c1"}}},
+		{"metadata": {"name": "p2"}, "spec": {"externalName": "default", "free": true, "clusterServiceClassRef": {"name": "c2"}}},
+		{"metadata": {"name": "p3"}, "spec": {"externalName": "alpha", "clusterServiceClassRef": {"name": "c1"}}},
+		{"metadata": {"name": "p4"}, "spec": {"externalName": "orphan", "clusterServiceClassRef": {"name": "gone"}}}
+	]}`), &plans); err != nil {
+		t.Fatalf("Unexpected error unmarshalling plans: %v", err)
+	}
+
+	want := []marketplaceEntry{
+		{Class: "bigquery", Broker: "gcp-broker", Plan: "default", Free: true},
+		{Class: "pubsub", Broker: "gcp-broker", Plan: "alpha"},
+		{Class: "pubsub", Broker: "gcp-broker", Plan: "beta"},
+	}
+	if got := mergeMarketplace(&classes, &plans); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Entries do not match: got %+v; want %+v", got, want)
+	}
+}
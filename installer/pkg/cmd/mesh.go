@@ -0,0 +1,76 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// meshIstio is the --mesh of the Istio service mesh.
+const meshIstio = "istio"
+
+// validateMesh checks the service mesh option of ic.
+func validateMesh(ic *InstallConfig) error {
+	switch ic.Mesh {
+	case "", meshIstio:
+		return nil
+	}
+	return fmt.Errorf("--mesh must be istio or empty, got %q", ic.Mesh)
+}
+
+// meshEtcdPorts returns the comma separated ports of the etcd the api
+// server calls, which the sidecar of the api server does not intercept:
+// etcd runs without a sidecar, and the external etcd of --skip-etcd is
+// usually outside the mesh.
+func meshEtcdPorts(ic *InstallConfig) string {
+	if !ic.SkipEtcd {
+		return "2379"
+	}
+	seen := make(map[string]bool)
+	for _, s := range strings.Split(ic.EtcdServers, ",") {
+		u, err := url.Parse(strings.TrimSpace(s))
+		if err != nil || u.Host == "" {
+			continue
+		}
+		port := u.Port()
+		if port == "" {
+			port = "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+		}
+		seen[port] = true
+	}
+	var ports []string
+	for p := range seen {
+		ports = append(ports, p)
+	}
+	sort.Strings(ports)
+	return strings.Join(ports, ",")
+}
+
+// meshData returns the template data annotating the pods of ic for its
+// service mesh, if any.
+func meshData(ic *InstallConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"Istio":         ic.Mesh == meshIstio,
+		"MeshEtcdPorts": meshEtcdPorts(ic),
+	}
+}
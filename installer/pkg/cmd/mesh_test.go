@@ -0,0 +1,52 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "testing"
+
+// TestMeshEtcdPorts tests the etcd ports bypassing the api server sidecar,
+// for the etcd sc deploys and external ones.
+func TestMeshEtcdPorts(t *testing.T) {
+	for _, tc := range []struct {
+		skipEtcd bool
+		servers  string
+		want     string
+	}{
+		{false, "", "2379"},
+		{true, "https://etcd-0.example.com:2379,https://etcd-1.example.com:2379", "2379"},
+		{true, "https://etcd.example.com, http://etcd.example.com:4001", "4001,443"},
+		{true, "http://etcd.example.com", "80"},
+		{true, "etcd:2379", ""},
+	} {
+		ic := &InstallConfig{SkipEtcd: tc.skipEtcd, EtcdServers: tc.servers}
+		if got := meshEtcdPorts(ic); got != tc.want {
+			t.Fatalf("meshEtcdPorts(%q) = %q; want %q", tc.servers, got, tc.want)
+		}
+	}
+}
+
+// TestValidateMesh tests that only the supported meshes are accepted.
+func TestValidateMesh(t *testing.T) {
+	for _, mesh := range []string{"", meshIstio} {
+		if err := validateMesh(&InstallConfig{Mesh: mesh}); err != nil {
+			t.Fatalf("Unexpected error validating --mesh=%s: %v", mesh, err)
+		}
+	}
+	if err := validateMesh(&InstallConfig{Mesh: "linkerd"}); err == nil {
+		t.Fatalf("Expected an error validating --mesh=linkerd")
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// MessagesFlagName is the name of the flag selecting the file overriding the
+// message texts.
+const MessagesFlagName = "messages"
+
+// LoadMessages loads the message overrides selected by the messages flag of
+// c, if any. It must run after SetFlagsFromEnvAndConfig so that the file can
+// also be set with SC_INSTALLER_MESSAGES or in the config file.
+func LoadMessages(c *cobra.Command) error {
+	path, err := c.Flags().GetString(MessagesFlagName)
+	if err != nil || path == "" {
+		return nil
+	}
+	return messages.Load(path)
+}
+
+func NewMessagesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "messages",
+		Short: "prints the message catalog",
+		Long: `prints the codes and texts of all the messages of sc as YAML, including
+the overrides of --` + MessagesFlagName + `. The output is a valid messages file, a starting
+point to translate or rebrand the messages.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return messages.Write(os.Stdout)
+		},
+	}
+}
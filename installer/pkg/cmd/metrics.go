@@ -0,0 +1,177 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// controllerManagerMetricsPort is the HTTPS port the controller manager
+// serves its metrics on.
+const controllerManagerMetricsPort = 8444
+
+// reconcileMetrics summarizes the work queue metrics of the controller
+// manager, which has a queue per reconciled resource.
+type reconcileMetrics struct {
+	// Adds is the number of objects queued for reconciliation.
+	Adds float64
+
+	// Retries is the number of failed reconciliations that were
+	// requeued, by queue.
+	Retries map[string]float64
+}
+
+func (m *reconcileMetrics) String() string {
+	return fmt.Sprintf("%.0f reconciles, %.0f retries", m.Adds, m.totalRetries())
+}
+
+func (m *reconcileMetrics) totalRetries() float64 {
+	total := 0.0
+	for _, r := range m.Retries {
+		total += r
+	}
+	return total
+}
+
+// problem returns why the reconcile loops are not healthy, or "" if they
+// run without errors.
+func (m *reconcileMetrics) problem() string {
+	if m.Adds == 0 {
+		return "no reconcile loop has run"
+	}
+	return m.retried()
+}
+
+// retried returns which queues requeued failed reconciles, or "" if none
+// did.
+func (m *reconcileMetrics) retried() string {
+	var failing []string
+	for q, r := range m.Retries {
+		if r > 0 {
+			failing = append(failing, fmt.Sprintf("%s (%.0f)", q, r))
+		}
+	}
+	if len(failing) > 0 {
+		sort.Strings(failing)
+		return "reconciles failed and were retried in queues " + strings.Join(failing, ", ")
+	}
+	return ""
+}
+
+// since returns the metrics counted after old was scraped. A counter lower
+// than in old was reset by a restart of the controller manager, all of it
+// was counted since.
+func (m *reconcileMetrics) since(old *reconcileMetrics) *reconcileMetrics {
+	d := &reconcileMetrics{Adds: delta(m.Adds, old.Adds), Retries: make(map[string]float64)}
+	for q, r := range m.Retries {
+		d.Retries[q] = delta(r, old.Retries[q])
+	}
+	return d
+}
+
+func delta(now, old float64) float64 {
+	if now < old {
+		return now
+	}
+	return now - old
+}
+
+// parseReconcileMetrics adds up the work queue metrics in the Prometheus
+// text format b to m. Newer controller managers export them as
+// workqueue_adds_total{name="<queue>"}, older ones as <queue>_adds.
+func parseReconcileMetrics(b []byte, m *reconcileMetrics) error {
+	if m.Retries == nil {
+		m.Retries = make(map[string]float64)
+	}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return fmt.Errorf("invalid metrics line %q", line)
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return fmt.Errorf("invalid value of metrics line %q", line)
+		}
+		name, labels := fields[0], ""
+		if i := strings.Index(name, "{"); i >= 0 {
+			name, labels = name[:i], name[i:]
+		}
+
+		switch {
+		case name == "workqueue_adds_total":
+			m.Adds += value
+		case name == "workqueue_retries_total":
+			m.Retries[labelValue(labels, "name")] += value
+		case strings.HasSuffix(name, "_adds") && !strings.HasPrefix(name, "workqueue_"):
+			m.Adds += value
+		case strings.HasSuffix(name, "_retries") && !strings.HasPrefix(name, "workqueue_"):
+			m.Retries[strings.TrimSuffix(name, "_retries")] += value
+		}
+	}
+	return s.Err()
+}
+
+// labelValue returns the value of label name in labels, e.g.
+// {name="service-instance"}.
+func labelValue(labels, name string) string {
+	for _, l := range strings.Split(strings.Trim(labels, "{}"), ",") {
+		parts := strings.SplitN(l, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == name {
+			return strings.Trim(parts[1], `"`)
+		}
+	}
+	return ""
+}
+
+// controllerManagerMetrics scrapes the metrics of the controller manager
+// pods in namespace ns through the API server proxy and adds them up. Only
+// the leader runs the reconcile loops.
+func controllerManagerMetrics(ns string) (*reconcileMetrics, error) {
+	output, err := kubectlCommand("get", "pods", "--namespace", ns,
+		"-l", "app=service-catalog-controller-manager", "--field-selector=status.phase=Running",
+		"-o", "jsonpath={.items[*].metadata.name}").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing controller manager pods: %s", strings.TrimSpace(string(output)))
+	}
+	pods := strings.Fields(string(output))
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no controller manager pod is running")
+	}
+
+	m := &reconcileMetrics{}
+	for _, p := range pods {
+		path := fmt.Sprintf("/api/v1/namespaces/%s/pods/https:%s:%d/proxy/metrics", ns, p, controllerManagerMetricsPort)
+		output, err := kubectlCommand("get", "--raw", path).CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("error scraping the metrics of pod %s: %s", p, strings.TrimSpace(string(output)))
+		}
+		if err := parseReconcileMetrics(output, m); err != nil {
+			return nil, fmt.Errorf("error parsing the metrics of pod %s: %v", p, err)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestParseReconcileMetrics tests that the work queue metrics of both
+// naming schemes are added up by queue.
+func TestParseReconcileMetrics(t *testing.T) {
+	m := &reconcileMetrics{}
+	if err := parseReconcileMetrics([]byte(`# HELP workqueue_adds_total Total number of adds handled by workqueue
+# TYPE workqueue_adds_total counter
+workqueue_adds_total{name="cluster-service-broker"} 3
+workqueue_adds_total{name="service-instance"} 5
+workqueue_retries_total{name="cluster-service-broker"} 0
+workqueue_retries_total{name="service-instance"} 2
+workqueue_depth{name="service-instance"} 1
+`), m); err != nil {
+		t.Fatalf("Unexpected error parsing metrics: %v", err)
+	}
+	if err := parseReconcileMetrics([]byte(`service_binding_adds 4
+service_binding_retries 0
+service_binding_depth 0
+`), m); err != nil {
+		t.Fatalf("Unexpected error parsing metrics: %v", err)
+	}
+	if m.Adds != 12 {
+		t.Fatalf("Adds do not match: got %v; want 12", m.Adds)
+	}
+	want := map[string]float64{"cluster-service-broker": 0, "service-instance": 2, "service_binding": 0}
+	if !reflect.DeepEqual(m.Retries, want) {
+		t.Fatalf("Retries do not match: got %v; want %v", m.Retries, want)
+	}
+	if got, want := m.problem(), "reconciles failed and were retried in queues service-instance (2)"; got != want {
+		t.Fatalf("Problem does not match: got %q; want %q", got, want)
+	}
+
+	if err := parseReconcileMetrics([]byte("workqueue_adds_total{name=\"x\"} many\n"), m); err == nil {
+		t.Fatalf("Expected an error for an invalid value")
+	}
+}
+
+// TestReconcileMetricsProblem tests that loops that never ran are reported
+// and that loops without retries are healthy.
+func TestReconcileMetricsProblem(t *testing.T) {
+	if got := (&reconcileMetrics{}).problem(); got != "no reconcile loop has run" {
+		t.Fatalf("Problem does not match: got %q; want no reconcile loop has run", got)
+	}
+	m := &reconcileMetrics{Adds: 3, Retries: map[string]float64{"service-instance": 0}}
+	if got := m.problem(); got != "" {
+		t.Fatalf("Unexpected problem: %s", got)
+	}
+}
+
+// TestReconcileMetricsSince tests that the counters are diffed by queue and
+// that reset counters count whole.
+func TestReconcileMetricsSince(t *testing.T) {
+	old := &reconcileMetrics{Adds: 10, Retries: map[string]float64{"cluster-service-broker": 4, "service-instance": 5}}
+	m := &reconcileMetrics{Adds: 12, Retries: map[string]float64{"cluster-service-broker": 4, "service-instance": 2, "service-binding": 1}}
+	got := m.since(old)
+	want := &reconcileMetrics{Adds: 2, Retries: map[string]float64{"cluster-service-broker": 0, "service-instance": 2, "service-binding": 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Metrics do not match: got %+v; want %+v", got, want)
+	}
+}
+
+// TestCheckReconcileMetrics tests that verify-install only fails on the
+// retries between its two scrapes, not on the earlier ones, e.g. of its
+// probe broker.
+func TestCheckReconcileMetrics(t *testing.T) {
+	defer func(old time.Duration) { reconcileMetricsInterval = old }(reconcileMetricsInterval)
+	reconcileMetricsInterval = 0
+
+	for _, tc := range []struct {
+		scrapes []string
+		want    string
+	}{
+		{[]string{"workqueue_adds_total{name=\"cluster-service-broker\"} 1\nworkqueue_retries_total{name=\"cluster-service-broker\"} 3\n",
+			"workqueue_adds_total{name=\"cluster-service-broker\"} 1\nworkqueue_retries_total{name=\"cluster-service-broker\"} 3\n"}, ""},
+		{[]string{"workqueue_adds_total{name=\"service-instance\"} 2\nworkqueue_retries_total{name=\"service-instance\"} 1\n",
+			"workqueue_adds_total{name=\"service-instance\"} 3\nworkqueue_retries_total{name=\"service-instance\"} 2\n"}, "retried in queues service-instance (1)"},
+		{[]string{"", ""}, "no reconcile loop has run"},
+	} {
+		scrapes := tc.scrapes
+		_, restore := stubExecutor(func(name string, args []string) execx.Response {
+			if args[0] == "get" && args[1] == "--raw" {
+				out := scrapes[0]
+				scrapes = scrapes[1:]
+				return execx.Response{Stdout: out}
+			}
+			return execx.Response{Stdout: "controller-manager-0"}
+		})
+		err := checkReconcileMetrics("catalog")
+		restore()
+		if tc.want == "" && err != nil {
+			t.Fatalf("Unexpected error for %q: %v", tc.scrapes, err)
+		}
+		if tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)) {
+			t.Fatalf("Error for %q does not match: got %v; want %q", tc.scrapes, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// onboardConfig configures the onboarding of the namespace of a developer
+// team.
+type onboardConfig struct {
+	// Namespace is the namespace to onboard.
+	Namespace string
+
+	// Groups, Users and ServiceAccounts are the subjects granted the use
+	// of the service catalog in Namespace. Service accounts are given as
+	// namespace:name.
+	Groups          []string
+	Users           []string
+	ServiceAccounts []string
+
+	// Plans restricts the instances of Namespace to these plans, by
+	// external or Kubernetes name, if not empty.
+	Plans []string
+
+	// MaxInstances limits the number of instances of Namespace, if not 0.
+	MaxInstances int
+}
+
+// onboardSubject is a subject of the RBAC of an onboarded namespace.
+type onboardSubject struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+func NewOnboardNamespaceCmd() *cobra.Command {
+	oc := &onboardConfig{}
+	c := &cobra.Command{
+		Use:   "onboard-namespace <namespace>",
+		Short: "prepares a namespace for a developer team to use Service Catalog",
+		Long: `creates the namespace, if it does not exist, and lets the given groups,
+users and service accounts create ServiceInstances and ServiceBindings in
+it and browse the brokers, classes and plans. With --plans, the instances
+of the namespace are restricted to these plans, and with --max-instances
+their number is limited, both enforced by OPA Gatekeeper. Running it again
+updates the namespace, and removes the restriction or the limit without
+--plans or --max-instances.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oc.Namespace = args[0]
+			if err := onboardNamespace(oc); err != nil {
+				messages.Println(messages.OnboardFailed)
+				return err
+			}
+			messages.Println(messages.NamespaceOnboarded, oc.Namespace)
+			return nil
+		},
+	}
+	c.Flags().StringSliceVar(&oc.Groups, "group", nil, "Groups to grant the use of Service Catalog in the namespace")
+	c.Flags().StringSliceVar(&oc.Users, "user", nil, "Users to grant the use of Service Catalog in the namespace")
+	c.Flags().StringSliceVar(&oc.ServiceAccounts, "service-account", nil, "Service accounts, as namespace:name, to grant the use of Service Catalog in the namespace")
+	c.Flags().StringSliceVar(&oc.Plans, "plans", nil, "Plans the instances of the namespace may use, by external or Kubernetes name. Requires OPA Gatekeeper")
+	c.Flags().IntVar(&oc.MaxInstances, "max-instances", 0, "Maximum number of instances in the namespace, 0 for no limit. Requires OPA Gatekeeper")
+	return c
+}
+
+// subjects returns the RBAC subjects of oc.
+func (oc *onboardConfig) subjects() ([]onboardSubject, error) {
+	var s []onboardSubject
+	for _, g := range oc.Groups {
+		s = append(s, onboardSubject{Kind: "Group", Name: g})
+	}
+	for _, u := range oc.Users {
+		s = append(s, onboardSubject{Kind: "User", Name: u})
+	}
+	for _, sa := range oc.ServiceAccounts {
+		parts := strings.Split(sa, ":")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid service account %q, must be namespace:name", sa)
+		}
+		s = append(s, onboardSubject{Kind: "ServiceAccount", Name: parts[1], Namespace: parts[0]})
+	}
+	return s, nil
+}
+
+func (oc *onboardConfig) validate() error {
+	if !dns1123LabelRE.MatchString(oc.Namespace) {
+		return fmt.Errorf("namespace %q must be a lowercase DNS label", oc.Namespace)
+	}
+	if len(oc.Groups)+len(oc.Users)+len(oc.ServiceAccounts) == 0 {
+		return fmt.Errorf("at least one of --group, --user and --service-account is required")
+	}
+	for _, p := range oc.Plans {
+		if p == "" {
+			return fmt.Errorf("--plans must not be empty")
+		}
+	}
+	if oc.MaxInstances < 0 {
+		return fmt.Errorf("--max-instances must not be negative")
+	}
+	_, err := oc.subjects()
+	return err
+}
+
+// renderOnboarding generates the manifests onboarding the namespace of oc in
+// dir.
+func renderOnboarding(dir string, oc *onboardConfig) error {
+	subjects, err := oc.subjects()
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"Namespace": oc.Namespace,
+		"Subjects":  subjects,
+	}
+	if err := generateConfigs(dir, "templates/onboard/", []string{"namespace"}, data); err != nil {
+		return err
+	}
+
+	// The plan restriction and the limit are the Gatekeeper constraints of
+	// an install, matching the namespace only.
+	name := onboardConstraintName(oc.Namespace)
+	if len(oc.Plans) > 0 {
+		rules := []planRule{{Name: name, Namespaces: []string{oc.Namespace}, Plans: oc.Plans}}
+		if err := generateFileFromTmpl(filepath.Join(dir, "plan-policy.yaml"), "templates/sc/plan-policy.yaml.tmpl", map[string]interface{}{
+			"PlanPolicy": true,
+			"PlanRules":  rules,
+			"NamePrefix": "",
+			"NameSuffix": "",
+		}); err != nil {
+			return err
+		}
+	}
+	if oc.MaxInstances > 0 {
+		if err := generateFileFromTmpl(filepath.Join(dir, "instance-quota.yaml"), "templates/sc/instance-quota.yaml.tmpl", map[string]interface{}{
+			"MaxInstancesPerNamespace": oc.MaxInstances,
+			"InstanceLimitName":        name,
+			"InstanceLimitNamespaces":  []string{oc.Namespace},
+			"NamePrefix":               "",
+			"NameSuffix":               "",
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onboardConstraintName is the name of the Gatekeeper constraints of
+// namespace ns, after the prefix of their kind.
+func onboardConstraintName(ns string) string {
+	return "ns-" + ns
+}
+
+// staleOnboardConstraints returns the resources and names of the Gatekeeper
+// constraints a previous onboarding of the namespace of oc may have created
+// that oc does not ask for.
+func staleOnboardConstraints(oc *onboardConfig) [][2]string {
+	name := onboardConstraintName(oc.Namespace)
+	var stale [][2]string
+	if len(oc.Plans) == 0 {
+		stale = append(stale, [2]string{"serviceinstanceallowedplans.constraints.gatekeeper.sh", "service-catalog-plans-" + name})
+	}
+	if oc.MaxInstances == 0 {
+		stale = append(stale, [2]string{"serviceinstancelimit.constraints.gatekeeper.sh", "service-catalog-instance-limit-" + name})
+	}
+	return stale
+}
+
+// removeStaleOnboardConstraints deletes the constraints of a previous
+// onboarding of the namespace of oc, e.g. the plan restriction when run
+// again without --plans. Clusters without Gatekeeper have none.
+func removeStaleOnboardConstraints(oc *onboardConfig) error {
+	for _, c := range staleOnboardConstraints(oc) {
+		output, err := kubectlCommand("delete", c[0], c[1], "--ignore-not-found").CombinedOutput()
+		if err != nil && !strings.Contains(string(output), "doesn't have a resource type") {
+			return fmt.Errorf("error deleting %s %s: %s", c[0], c[1], strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// onboardNamespace applies the namespace, RBAC and constraints of oc, and
+// removes those of a previous onboarding it no longer asks for.
+func onboardNamespace(oc *onboardConfig) (err error) {
+	if err := oc.validate(); err != nil {
+		return err
+	}
+	if len(oc.Plans) > 0 || oc.MaxInstances > 0 {
+		if err := checkGatekeeperInstalled(oc.MaxInstances > 0, false); err != nil {
+			return err
+		}
+	}
+
+	ws, err := createWorkspace(oc.Namespace, "onboard-namespace", workspace.Options{Cleanup: workspace.OnSuccess})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+	if err := renderOnboarding(ws.Dir, oc); err != nil {
+		return fmt.Errorf("error generating manifests: %v", err)
+	}
+	if err := deployConfig(ws.Dir, applyOptions{}); err != nil {
+		return err
+	}
+	return removeStaleOnboardConstraints(oc)
+}
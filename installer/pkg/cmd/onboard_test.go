@@ -0,0 +1,145 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestRenderOnboarding tests that an onboarded namespace gets its RBAC and
+// Gatekeeper constraints matching it only.
+func TestRenderOnboarding(t *testing.T) {
+	dir, err := ioutil.TempDir("", "onboard")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	oc := &onboardConfig{
+		Namespace:       "payments",
+		Groups:          []string{"payments-devs"},
+		ServiceAccounts: []string{"ci:deployer"},
+		Plans:           []string{"small", "dev"},
+		MaxInstances:    10,
+	}
+	if err := oc.validate(); err != nil {
+		t.Fatalf("Unexpected error validating config: %v", err)
+	}
+	if err := renderOnboarding(dir, oc); err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing manifests: %v", err)
+	}
+
+	var got []string
+	specs := make(map[string]map[string]interface{})
+	for _, o := range objs {
+		got = append(got, o.Kind+"/"+o.Name)
+		var a map[string]interface{}
+		if err := json.Unmarshal(o.JSON, &a); err != nil {
+			t.Fatalf("Unexpected error unmarshalling %s: %v", o.Name, err)
+		}
+		specs[o.Kind] = a
+	}
+	want := []string{
+		"Namespace/payments",
+		"ClusterRole/servicecatalog.k8s.io:edit",
+		"ClusterRole/servicecatalog.k8s.io:browse",
+		"ClusterRoleBinding/servicecatalog.k8s.io:browse:payments",
+		"RoleBinding/servicecatalog.k8s.io:edit",
+		"ConstraintTemplate/serviceinstancelimit",
+		"ConstraintTemplate/serviceinstanceallowedplans",
+		"ServiceInstanceLimit/service-catalog-instance-limit-ns-payments",
+		"ServiceInstanceAllowedPlans/service-catalog-plans-ns-payments",
+	}
+	if !reflect.DeepEqual(uniqueSorted(got), uniqueSorted(want)) {
+		t.Fatalf("Objects do not match:\ngot  %q\nwant %q", got, want)
+	}
+
+	wantSubjects := []interface{}{
+		map[string]interface{}{"kind": "Group", "name": "payments-devs", "apiGroup": "rbac.authorization.k8s.io"},
+		map[string]interface{}{"kind": "ServiceAccount", "name": "deployer", "namespace": "ci"},
+	}
+	if s := specs["RoleBinding"]["subjects"]; !reflect.DeepEqual(s, wantSubjects) {
+		t.Fatalf("Subjects do not match:\ngot  %v\nwant %v", s, wantSubjects)
+	}
+	for _, kind := range []string{"ServiceInstanceLimit", "ServiceInstanceAllowedPlans"} {
+		match := specs[kind]["spec"].(map[string]interface{})["match"].(map[string]interface{})
+		if ns := match["namespaces"]; !reflect.DeepEqual(ns, []interface{}{"payments"}) {
+			t.Fatalf("%s namespaces do not match: got %v; want [payments]", kind, ns)
+		}
+	}
+}
+
+// TestOnboardConfigValidate tests that invalid onboardings are rejected.
+func TestOnboardConfigValidate(t *testing.T) {
+	for _, c := range []struct {
+		oc   onboardConfig
+		want string
+	}{
+		{onboardConfig{Namespace: "Payments", Groups: []string{"devs"}}, "DNS label"},
+		{onboardConfig{Namespace: "payments"}, "at least one of"},
+		{onboardConfig{Namespace: "payments", ServiceAccounts: []string{"deployer"}}, "namespace:name"},
+		{onboardConfig{Namespace: "payments", Users: []string{"jo"}, Plans: []string{""}}, "--plans"},
+		{onboardConfig{Namespace: "payments", Users: []string{"jo"}, MaxInstances: -1}, "--max-instances"},
+	} {
+		err := c.oc.validate()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("Error for %+v does not match: got %v; want %q", c.oc, err, c.want)
+		}
+	}
+}
+
+// TestRemoveStaleOnboardConstraints tests that onboarding a namespace again
+// without --plans or --max-instances deletes the constraints of the
+// previous onboarding, and that clusters without Gatekeeper are fine.
+func TestRemoveStaleOnboardConstraints(t *testing.T) {
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		if strings.HasPrefix(args[1], "serviceinstancelimit") {
+			return execx.Response{Stderr: `error: the server doesn't have a resource type "serviceinstancelimit"`, ExitCode: 1}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	oc := &onboardConfig{Namespace: "payments", Users: []string{"jo"}}
+	if err := removeStaleOnboardConstraints(oc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{
+		"delete serviceinstanceallowedplans.constraints.gatekeeper.sh service-catalog-plans-ns-payments --ignore-not-found",
+		"delete serviceinstancelimit.constraints.gatekeeper.sh service-catalog-instance-limit-ns-payments --ignore-not-found",
+	}
+	if got := kubectlCalls(s, "delete"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Calls do not match: got %v; want %v", got, want)
+	}
+
+	oc.Plans, oc.MaxInstances = []string{"small"}, 10
+	if got := staleOnboardConstraints(oc); len(got) != 0 {
+		t.Fatalf("Constraints asked for must be kept, got %v", got)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Open Service Broker API versions the controller manager can send in the
+// X-Broker-API-Version header. It sends 2.14 when the AsyncBindingOperations
+// feature gate is enabled, since asynchronous bindings require it, and 2.13
+// otherwise.
+const (
+	osbAPIVersion213     = "2.13"
+	osbAPIVersion214     = "2.14"
+	defaultOSBAPIVersion = osbAPIVersion214
+)
+
+var osbAPIVersions = []string{osbAPIVersion213, osbAPIVersion214}
+
+// validateOSBAPIVersion checks the OSB API version of ic.
+func validateOSBAPIVersion(ic *InstallConfig) error {
+	if !containsString(osbAPIVersions, ic.OSBAPIVersion) {
+		return fmt.Errorf("--osb-api-version must be one of %s, got %q", strings.Join(osbAPIVersions, ", "), ic.OSBAPIVersion)
+	}
+	return nil
+}
+
+// osbVersionCompatible returns whether a broker advertising version, e.g.
+// in the X-Broker-API-Version header of its responses, accepts requests of
+// version requested: the major versions match and the broker supports the
+// minor version.
+func osbVersionCompatible(requested, advertised string) bool {
+	rMajor, rMinor, err := parseOSBVersion(requested)
+	if err != nil {
+		return false
+	}
+	aMajor, aMinor, err := parseOSBVersion(advertised)
+	if err != nil {
+		return false
+	}
+	return rMajor == aMajor && aMinor >= rMinor
+}
+
+func parseOSBVersion(v string) (major, minor int, err error) {
+	parts := strings.Split(strings.TrimSpace(v), ".")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid OSB API version %q", v)
+	}
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid OSB API version %q", v)
+	}
+	if minor, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, fmt.Errorf("invalid OSB API version %q", v)
+	}
+	return major, minor, nil
+}
+
+// brokerVersionRejected returns whether the error message of a broker
+// condition says the broker rejected the API version of the controller
+// manager, with 412 Precondition Failed.
+func brokerVersionRejected(message string) bool {
+	return strings.Contains(message, "Status: 412") || strings.Contains(message, "412 Precondition Failed")
+}
+
+// brokerVersionGuidance explains how to make the controller manager send a
+// version an incompatible broker, or the gateway in front of it, accepts.
+func brokerVersionGuidance(broker string) string {
+	return fmt.Sprintf("broker %s rejects the Open Service Broker API version the controller manager sends. "+
+		"If it, or a gateway in front of it, only accepts another version, reinstall with --osb-api-version set to that version, e.g. %s, "+
+		"and check it with sc broker lint --api-version", broker, osbAPIVersion213)
+}
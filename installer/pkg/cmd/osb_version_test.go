@@ -0,0 +1,50 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "testing"
+
+// TestOSBVersionCompatible tests that brokers are compatible with the
+// versions up to the one they advertise, of the same major version.
+func TestOSBVersionCompatible(t *testing.T) {
+	for _, tc := range []struct {
+		requested, advertised string
+		want                  bool
+	}{
+		{"2.14", "2.14", true},
+		{"2.13", "2.15", true},
+		{"2.14", "2.13", false},
+		{"2.13", "3.0", false},
+		{"2.13", "latest", false},
+	} {
+		if got := osbVersionCompatible(tc.requested, tc.advertised); got != tc.want {
+			t.Fatalf("Compatibility of %s with a broker advertising %s does not match: got %v; want %v", tc.requested, tc.advertised, got, tc.want)
+		}
+	}
+}
+
+// TestBrokerVersionRejected tests that the errors of brokers rejecting the
+// API version of the controller manager are recognized.
+func TestBrokerVersionRejected(t *testing.T) {
+	msg := "Error fetching catalog. Error getting broker catalog: Status: 412; ErrorMessage: <nil>; Description: <nil>; ResponseError: <nil>"
+	if !brokerVersionRejected(msg) {
+		t.Fatalf("Expected %q to be recognized", msg)
+	}
+	if brokerVersionRejected("Error fetching catalog. Status: 401") {
+		t.Fatalf("Unexpected recognition of an authentication error")
+	}
+}
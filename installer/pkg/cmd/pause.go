@@ -0,0 +1,192 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// pausedReplicasAnnotation records the replica count of a paused deployment.
+const pausedReplicasAnnotation = "servicecatalog.k8s.io/sc-paused-replicas"
+
+// pauseConfig contains the pause configuration.
+type pauseConfig struct {
+	// Namespace is the namespace Service Catalog is installed in.
+	Namespace string
+
+	// NamePrefix and NameSuffix are the name affixes Service Catalog was
+	// installed with, which determine its namespace.
+	NamePrefix string
+	NameSuffix string
+
+	// IncludeAPIServer also pauses the api server, making the catalog API
+	// unavailable.
+	IncludeAPIServer bool
+}
+
+func NewPauseCmd() *cobra.Command {
+	pc := &pauseConfig{}
+	c := &cobra.Command{
+		Use:   "pause",
+		Short: "pauses the Service Catalog control plane",
+		Long: `pauses the Service Catalog control plane by scaling the controller
+manager, and optionally the api server, to zero replicas. The replica counts
+are recorded on the deployments and restored by resume. Useful to stop
+reconciliation during etcd maintenance or broker migrations.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns, err := affixedNamespace(pc.Namespace, pc.NamePrefix, pc.NameSuffix)
+			if err != nil {
+				return err
+			}
+			if err := pauseServiceCatalog(ns, pc); err != nil {
+				messages.Println(messages.PauseFailed)
+				return err
+			}
+			messages.Println(messages.Paused)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&pc.Namespace, "namespace", defaultNamespace, "Namespace Service Catalog is installed in")
+	addNameAffixFlags(c, &pc.NamePrefix, &pc.NameSuffix)
+	c.Flags().BoolVar(&pc.IncludeAPIServer, "include-apiserver", false, "Also scale the api server to zero replicas")
+	return c
+}
+
+func NewResumeCmd() *cobra.Command {
+	var ns, prefix, suffix string
+	c := &cobra.Command{
+		Use:   "resume",
+		Short: "resumes a paused Service Catalog control plane",
+		Long:  `resumes a Service Catalog control plane paused with pause by restoring the recorded replica counts.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns, err := affixedNamespace(ns, prefix, suffix)
+			if err != nil {
+				return err
+			}
+			if err := resumeServiceCatalog(ns); err != nil {
+				messages.Println(messages.ResumeFailed)
+				return err
+			}
+			messages.Println(messages.Resumed)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&ns, "namespace", defaultNamespace, "Namespace Service Catalog is installed in")
+	addNameAffixFlags(c, &prefix, &suffix)
+	return c
+}
+
+func pauseServiceCatalog(ns string, pc *pauseConfig) error {
+	// Stop the controller manager first so it doesn't fail reconciling
+	// against a missing api server.
+	deployments := []string{"controller-manager"}
+	if pc.IncludeAPIServer {
+		deployments = append(deployments, "apiserver")
+	}
+
+	for _, d := range deployments {
+		if err := pauseDeployment(ns, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resumeServiceCatalog(ns string) error {
+	for _, d := range []string{"apiserver", "controller-manager"} {
+		if err := resumeDeployment(ns, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pauseDeployment records the replica count of deployment name in an
+// annotation and scales it to zero. Paused deployments are left alone.
+func pauseDeployment(ns, name string) error {
+	paused, err := deploymentJSONPath(ns, name, pausedReplicasJSONPath())
+	if err != nil {
+		return err
+	}
+	if paused != "" {
+		fmt.Printf("deployment %s is already paused\n", name)
+		return nil
+	}
+
+	replicas, err := deploymentJSONPath(ns, name, "{.spec.replicas}")
+	if err != nil {
+		return err
+	}
+
+	output, err := kubectlCommand("annotate", "deployment", name, "--namespace", ns,
+		pausedReplicasAnnotation+"="+replicas, "--overwrite").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error annotating deployment %s: %s", name, string(output))
+	}
+
+	output, err = kubectlCommand("scale", "deployment", name, "--namespace", ns, "--replicas=0").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error scaling deployment %s: %s", name, string(output))
+	}
+	fmt.Printf("paused deployment %s, scaled from %s to 0 replicas\n", name, replicas)
+	return nil
+}
+
+// resumeDeployment scales deployment name back to the replica count
+// recorded by pauseDeployment. Deployments that are not paused are left
+// alone.
+func resumeDeployment(ns, name string) error {
+	paused, err := deploymentJSONPath(ns, name, pausedReplicasJSONPath())
+	if err != nil {
+		return err
+	}
+	if paused == "" {
+		return nil
+	}
+	if _, err := strconv.Atoi(paused); err != nil {
+		return fmt.Errorf("invalid %s annotation on deployment %s: %q", pausedReplicasAnnotation, name, paused)
+	}
+
+	output, err := kubectlCommand("scale", "deployment", name, "--namespace", ns, "--replicas="+paused).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error scaling deployment %s: %s", name, string(output))
+	}
+
+	output, err = kubectlCommand("annotate", "deployment", name, "--namespace", ns, pausedReplicasAnnotation+"-").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error removing annotation from deployment %s: %s", name, string(output))
+	}
+	fmt.Printf("resumed deployment %s with %s replicas\n", name, paused)
+	return nil
+}
+
+func pausedReplicasJSONPath() string {
+	return "{.metadata.annotations." + strings.Replace(pausedReplicasAnnotation, ".", `\.`, -1) + "}"
+}
+
+func deploymentJSONPath(ns, name, path string) (string, error) {
+	output, err := kubectlCommand("get", "deployment", name, "--namespace", ns, "-o", "jsonpath="+path).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting deployment %s: %s", name, string(output))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
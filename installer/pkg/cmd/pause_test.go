@@ -0,0 +1,205 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// pauseStub returns the responses of a cluster whose deployments have the
+// replicas and paused replicas annotations of the maps, failing the
+// kubectl commands with the verb fail.
+func pauseStub(replicas, paused map[string]string, fail string) func(name string, args []string) execx.Response {
+	return func(name string, args []string) execx.Response {
+		if args[0] == fail {
+			return execx.Response{Stderr: "forbidden", ExitCode: 1}
+		}
+		if args[0] != "get" {
+			return execx.Response{}
+		}
+		if strings.HasSuffix(args[len(args)-1], "{.spec.replicas}") {
+			return execx.Response{Stdout: replicas[args[2]]}
+		}
+		return execx.Response{Stdout: paused[args[2]]}
+	}
+}
+
+// TestPauseServiceCatalog tests that pause records the replica counts
+// before scaling to zero, the controller manager first.
+func TestPauseServiceCatalog(t *testing.T) {
+	s, restore := stubExecutor(pauseStub(map[string]string{"controller-manager": "1", "apiserver": "2"}, nil, ""))
+	defer restore()
+
+	if err := pauseServiceCatalog("catalog", &pauseConfig{IncludeAPIServer: true}); err != nil {
+		t.Fatalf("Unexpected error pausing: %v", err)
+	}
+	want := []string{
+		"annotate deployment controller-manager --namespace catalog servicecatalog.k8s.io/sc-paused-replicas=1 --overwrite",
+		"scale deployment controller-manager --namespace catalog --replicas=0",
+		"annotate deployment apiserver --namespace catalog servicecatalog.k8s.io/sc-paused-replicas=2 --overwrite",
+		"scale deployment apiserver --namespace catalog --replicas=0",
+	}
+	if got := kubectlCalls(s, "annotate", "scale"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected the calls\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+// TestPauseAlreadyPaused tests that pausing a paused deployment keeps its
+// recorded replica count.
+func TestPauseAlreadyPaused(t *testing.T) {
+	s, restore := stubExecutor(pauseStub(map[string]string{"controller-manager": "0"}, map[string]string{"controller-manager": "1"}, ""))
+	defer restore()
+
+	if err := pauseServiceCatalog("catalog", &pauseConfig{}); err != nil {
+		t.Fatalf("Unexpected error pausing: %v", err)
+	}
+	if got := kubectlCalls(s, "annotate", "scale"); len(got) != 0 {
+		t.Fatalf("Expected the paused deployment to be left alone, got the calls\n%s", strings.Join(got, "\n"))
+	}
+}
+
+// TestResumeServiceCatalog tests that resume restores the recorded replica
+// counts and removes the annotations, the api server first, and leaves the
+// deployments that are not paused alone.
+func TestResumeServiceCatalog(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		paused map[string]string
+		want   []string
+	}{
+		{
+			name:   "both paused",
+			paused: map[string]string{"apiserver": "2", "controller-manager": "1"},
+			want: []string{
+				"scale deployment apiserver --namespace catalog --replicas=2",
+				"annotate deployment apiserver --namespace catalog servicecatalog.k8s.io/sc-paused-replicas-",
+				"scale deployment controller-manager --namespace catalog --replicas=1",
+				"annotate deployment controller-manager --namespace catalog servicecatalog.k8s.io/sc-paused-replicas-",
+			},
+		},
+		{
+			name:   "controller manager paused",
+			paused: map[string]string{"controller-manager": "1"},
+			want: []string{
+				"scale deployment controller-manager --namespace catalog --replicas=1",
+				"annotate deployment controller-manager --namespace catalog servicecatalog.k8s.io/sc-paused-replicas-",
+			},
+		},
+		{
+			name: "not paused",
+		},
+	} {
+		s, restore := stubExecutor(pauseStub(nil, tc.paused, ""))
+		err := resumeServiceCatalog("catalog")
+		restore()
+		if err != nil {
+			t.Fatalf("%s: unexpected error resuming: %v", tc.name, err)
+		}
+		if got := kubectlCalls(s, "annotate", "scale"); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: expected the calls\n%s\ngot\n%s", tc.name, strings.Join(tc.want, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
+
+// TestResumeInvalidAnnotation tests that resume doesn't scale a deployment
+// whose recorded replica count is not a number.
+func TestResumeInvalidAnnotation(t *testing.T) {
+	s, restore := stubExecutor(pauseStub(nil, map[string]string{"apiserver": "two"}, ""))
+	defer restore()
+
+	err := resumeServiceCatalog("catalog")
+	if err == nil || !strings.Contains(err.Error(), `invalid servicecatalog.k8s.io/sc-paused-replicas annotation on deployment apiserver: "two"`) {
+		t.Fatalf("Expected an invalid annotation error, got %v", err)
+	}
+	if got := kubectlCalls(s, "annotate", "scale"); len(got) != 0 {
+		t.Fatalf("Expected no deployment to be changed, got the calls\n%s", strings.Join(got, "\n"))
+	}
+}
+
+// TestPauseKubectlErrors tests that pause and resume stop at the first
+// failing kubectl command.
+func TestPauseKubectlErrors(t *testing.T) {
+	replicas := map[string]string{"controller-manager": "1", "apiserver": "2"}
+	for _, tc := range []struct {
+		name, fail string
+		paused     map[string]string
+		run        func() error
+		wantErr    string
+		wantCalls  []string
+	}{
+		{
+			name:    "pause get",
+			fail:    "get",
+			run:     func() error { return pauseServiceCatalog("catalog", &pauseConfig{IncludeAPIServer: true}) },
+			wantErr: "error getting deployment controller-manager: forbidden",
+		},
+		{
+			name:    "pause annotate",
+			fail:    "annotate",
+			run:     func() error { return pauseServiceCatalog("catalog", &pauseConfig{IncludeAPIServer: true}) },
+			wantErr: "error annotating deployment controller-manager: forbidden",
+			wantCalls: []string{
+				"annotate deployment controller-manager --namespace catalog servicecatalog.k8s.io/sc-paused-replicas=1 --overwrite",
+			},
+		},
+		{
+			name:    "pause scale",
+			fail:    "scale",
+			run:     func() error { return pauseServiceCatalog("catalog", &pauseConfig{IncludeAPIServer: true}) },
+			wantErr: "error scaling deployment controller-manager: forbidden",
+			wantCalls: []string{
+				"annotate deployment controller-manager --namespace catalog servicecatalog.k8s.io/sc-paused-replicas=1 --overwrite",
+				"scale deployment controller-manager --namespace catalog --replicas=0",
+			},
+		},
+		{
+			name:    "resume scale",
+			fail:    "scale",
+			paused:  replicas,
+			run:     func() error { return resumeServiceCatalog("catalog") },
+			wantErr: "error scaling deployment apiserver: forbidden",
+			wantCalls: []string{
+				"scale deployment apiserver --namespace catalog --replicas=2",
+			},
+		},
+		{
+			name:    "resume annotate",
+			fail:    "annotate",
+			paused:  replicas,
+			run:     func() error { return resumeServiceCatalog("catalog") },
+			wantErr: "error removing annotation from deployment apiserver: forbidden",
+			wantCalls: []string{
+				"scale deployment apiserver --namespace catalog --replicas=2",
+				"annotate deployment apiserver --namespace catalog servicecatalog.k8s.io/sc-paused-replicas-",
+			},
+		},
+	} {
+		s, restore := stubExecutor(pauseStub(replicas, tc.paused, tc.fail))
+		err := tc.run()
+		restore()
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Fatalf("%s: expected an error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+		if got := kubectlCalls(s, "annotate", "scale"); !reflect.DeepEqual(got, tc.wantCalls) {
+			t.Fatalf("%s: expected the calls\n%s\ngot\n%s", tc.name, strings.Join(tc.wantCalls, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
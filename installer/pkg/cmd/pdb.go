@@ -0,0 +1,79 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const defaultPDBMaxUnavailable = "1"
+
+// pdbMaxUnavailable returns how many of replicas pods a PodDisruptionBudget
+// with maxUnavailable value allows to be disrupted, a number of pods or a
+// percentage, which the disruption controller rounds up.
+func pdbMaxUnavailable(value string, replicas int32) (int32, error) {
+	if strings.HasSuffix(value, "%") {
+		p, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || p < 1 || p > 100 {
+			return 0, fmt.Errorf("--pdb-max-unavailable must be a number of pods or a percentage between 1%% and 100%%, got %q", value)
+		}
+		return (replicas*int32(p) + 99) / 100, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("--pdb-max-unavailable must be a number of pods or a percentage between 1%% and 100%%, got %q", value)
+	}
+	return int32(n), nil
+}
+
+// etcdPDB returns whether ic creates a multi member etcd cluster protected
+// by a PodDisruptionBudget. The single member etcd StatefulSet of IPv6
+// clusters is not, a budget would block the drain of its node.
+func etcdPDB(ic *InstallConfig) bool {
+	return ic.PodDisruptionBudgets && !ic.SkipEtcd && ic.IPFamily != ipFamilyIPv6 && ic.EtcdClusterSize > 1
+}
+
+// validatePDB checks the PodDisruptionBudget options of ic. The etcd budget
+// must leave a quorum of members running.
+func validatePDB(ic *InstallConfig) error {
+	if !ic.PodDisruptionBudgets {
+		return nil
+	}
+	n, err := pdbMaxUnavailable(ic.PDBMaxUnavailable, ic.EtcdClusterSize)
+	if err != nil {
+		return err
+	}
+	if etcdPDB(ic) && n > ic.EtcdClusterSize/2 {
+		return fmt.Errorf("--pdb-max-unavailable %s allows %d of the %d etcd members to be disrupted, at most %d may be for etcd to keep a quorum",
+			ic.PDBMaxUnavailable, n, ic.EtcdClusterSize, ic.EtcdClusterSize/2)
+	}
+	return nil
+}
+
+// pdbData returns the template data of the PodDisruptionBudgets. Components
+// running a single replica get none, since a budget can't keep them
+// available and would only block node drains.
+func pdbData(ic *InstallConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"APIServerPDB":         ic.PodDisruptionBudgets && ic.APIServerReplicas > 1,
+		"ControllerManagerPDB": ic.PodDisruptionBudgets && ic.ControllerManagerReplicas > 1,
+		"EtcdPDB":              etcdPDB(ic),
+		"PDBMaxUnavailable":    ic.PDBMaxUnavailable,
+	}
+}
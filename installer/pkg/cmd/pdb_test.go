@@ -0,0 +1,120 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestPDBMaxUnavailable tests that numbers and rounded up percentages of
+// pods are accepted and other values rejected.
+func TestPDBMaxUnavailable(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		want  int32
+	}{
+		{"1", 1},
+		{"2", 2},
+		{"20%", 1},
+		{"40%", 2},
+		{"100%", 5},
+	} {
+		got, err := pdbMaxUnavailable(tc.value, 5)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", tc.value, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Max unavailable of %q does not match: got %d; want %d", tc.value, got, tc.want)
+		}
+	}
+	for _, value := range []string{"", "0", "-1", "0%", "101%", "one", "1.5"} {
+		if _, err := pdbMaxUnavailable(value, 5); err == nil {
+			t.Fatalf("Expected an error for %q", value)
+		}
+	}
+}
+
+// TestValidatePDB tests that a budget that lets etcd lose its quorum is
+// rejected.
+func TestValidatePDB(t *testing.T) {
+	ic := validInstallConfig()
+	ic.PodDisruptionBudgets = true
+	for _, tc := range []struct {
+		value string
+		size  int32
+		ok    bool
+	}{
+		{"1", 3, true},
+		{"2", 3, false},
+		{"2", 5, true},
+		{"50%", 3, false},
+		{"40%", 5, true},
+		{"3", 1, true},
+	} {
+		ic.PDBMaxUnavailable, ic.EtcdClusterSize = tc.value, tc.size
+		if err := validatePDB(ic); (err == nil) != tc.ok {
+			t.Fatalf("Validation of %q for %d members does not match: got %v; want ok %v", tc.value, tc.size, err, tc.ok)
+		}
+	}
+}
+
+// TestRenderPDB tests that only the components running more than one
+// replica get a PodDisruptionBudget.
+func TestRenderPDB(t *testing.T) {
+	for _, tc := range []struct {
+		apiServer, controllerManager, etcd int32
+		want                               []string
+	}{
+		{1, 1, 1, nil},
+		{2, 1, 1, []string{"apiserver"}},
+		{1, 3, 3, []string{"controller-manager", "etcd"}},
+	} {
+		dir, err := ioutil.TempDir("", "pdb")
+		if err != nil {
+			t.Fatalf("Unexpected error creating temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		ic := validInstallConfig()
+		ic.PodDisruptionBudgets = true
+		ic.PDBMaxUnavailable = "1"
+		ic.APIServerReplicas = tc.apiServer
+		ic.ControllerManagerReplicas = tc.controllerManager
+		ic.EtcdClusterSize = tc.etcd
+		if err := renderServiceCatalog(dir, ic, nil); err != nil {
+			t.Fatalf("Unexpected error rendering manifests: %v", err)
+		}
+		objs, err := manifest.ParseDir(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing manifests: %v", err)
+		}
+		var got []string
+		for _, o := range objs {
+			if o.Kind == "PodDisruptionBudget" {
+				got = append(got, o.Name)
+			}
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("PodDisruptionBudgets do not match: got %v; want %v", got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+)
+
+// planPolicy maps namespaces to the service plans their ServiceInstances may
+// use. It is read from the file passed with --plan-policy, e.g.
+//
+//	rules:
+//	- name: dev
+//	  namespaces: [dev, staging]
+//	  plans: [small, db-f1-micro]
+//	- name: frontend
+//	  namespaceSelector:
+//	    team: frontend
+//	  plans: [small, medium]
+//
+// Namespaces no rule matches may use any plan.
+type planPolicy struct {
+	Rules []planRule `json:"rules"`
+}
+
+// planRule restricts the plans of the instances in the matching namespaces.
+type planRule struct {
+	// Name names the Gatekeeper constraint of the rule.
+	Name string `json:"name"`
+
+	// Namespaces are the namespaces the rule applies to.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector selects the namespaces the rule applies to by
+	// label, e.g. the team owning them.
+	NamespaceSelector map[string]string `json:"namespaceSelector,omitempty"`
+
+	// Plans are the external names, or the Kubernetes names, of the
+	// permitted plans.
+	Plans []string `json:"plans"`
+}
+
+// loadPlanPolicy reads and validates the plan policy in file.
+func loadPlanPolicy(file string) (*planPolicy, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading plan policy: %v", err)
+	}
+	p := &planPolicy{}
+	if err := yaml.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("error parsing plan policy %s: %v", file, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid plan policy %s: %v", file, err)
+	}
+	return p, nil
+}
+
+func (p *planPolicy) validate() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("no rules")
+	}
+	names := make(map[string]bool)
+	for i, r := range p.Rules {
+		if !dns1123LabelRE.MatchString(r.Name) {
+			return fmt.Errorf("rule %d: name %q must be a lowercase DNS label", i, r.Name)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("rule %s is defined twice", r.Name)
+		}
+		names[r.Name] = true
+		if len(r.Namespaces) == 0 && len(r.NamespaceSelector) == 0 {
+			return fmt.Errorf("rule %s: namespaces or namespaceSelector is required", r.Name)
+		}
+		if len(r.Plans) == 0 {
+			return fmt.Errorf("rule %s: plans is required", r.Name)
+		}
+		for _, plan := range r.Plans {
+			if plan == "" {
+				return fmt.Errorf("rule %s: plans must not be empty", r.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// planPolicyData returns the template data rendering the rules of the plan
+// policy of ic, if any.
+func planPolicyData(ic *InstallConfig) (map[string]interface{}, error) {
+	p := ic.PlanPolicy
+	if p == nil && ic.PlanPolicyFile != "" {
+		var err error
+		if p, err = loadPlanPolicy(ic.PlanPolicyFile); err != nil {
+			return nil, err
+		}
+	}
+	data := map[string]interface{}{"PlanPolicy": p != nil}
+	if p != nil {
+		data["PlanRules"] = p.Rules
+	}
+	return data, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestPlanPolicy tests that the rules of a plan policy file are rendered as
+// Gatekeeper constraints matching their namespaces.
+func TestPlanPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plan-policy")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "policy.yaml")
+	if err := ioutil.WriteFile(file, []byte(`
+rules:
+- name: dev
+  namespaces: [dev, staging]
+  plans: [small]
+- name: frontend
+  namespaceSelector:
+    team: frontend
+  plans: [small, medium]
+`), 0644); err != nil {
+		t.Fatalf("Unexpected error writing policy: %v", err)
+	}
+
+	ic := validInstallConfig()
+	ic.PlanPolicyFile = file
+	if err := ic.Validate(); err != nil {
+		t.Fatalf("Unexpected error validating config: %v", err)
+	}
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "plan-policy.yaml"))
+	if err != nil {
+		t.Fatalf("Unexpected error reading plan-policy.yaml: %v", err)
+	}
+	objs, err := manifest.Parse("plan-policy.yaml", b)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing plan-policy.yaml: %v\n%s", err, b)
+	}
+
+	var names []string
+	matches := make(map[string]interface{})
+	for _, o := range objs {
+		names = append(names, o.Kind+"/"+o.Name)
+		var c struct {
+			Spec struct {
+				Match map[string]interface{} `json:"match"`
+			} `json:"spec"`
+		}
+		if err := json.Unmarshal(o.JSON, &c); err != nil {
+			t.Fatalf("Unexpected error unmarshalling %s: %v", o.Name, err)
+		}
+		delete(c.Spec.Match, "kinds")
+		if len(c.Spec.Match) > 0 {
+			matches[o.Name] = c.Spec.Match
+		}
+	}
+	wantNames := []string{
+		"ConstraintTemplate/serviceinstanceallowedplans",
+		"ServiceInstanceAllowedPlans/service-catalog-plans-dev",
+		"ServiceInstanceAllowedPlans/service-catalog-plans-frontend",
+	}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("Objects do not match: got %v; want %v", names, wantNames)
+	}
+	wantMatches := map[string]interface{}{
+		"service-catalog-plans-dev": map[string]interface{}{
+			"namespaces": []interface{}{"dev", "staging"},
+		},
+		"service-catalog-plans-frontend": map[string]interface{}{
+			"namespaceSelector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"team": "frontend"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(matches, wantMatches) {
+		t.Fatalf("Matches do not match: got %v; want %v", matches, wantMatches)
+	}
+}
+
+// TestPlanPolicyValidate tests that invalid rules are rejected.
+func TestPlanPolicyValidate(t *testing.T) {
+	for _, c := range []struct {
+		policy planPolicy
+		want   string
+	}{
+		{planPolicy{}, "no rules"},
+		{planPolicy{Rules: []planRule{{Name: "Dev", Namespaces: []string{"dev"}, Plans: []string{"small"}}}}, "DNS label"},
+		{planPolicy{Rules: []planRule{{Name: "dev", Plans: []string{"small"}}}}, "namespaces or namespaceSelector"},
+		{planPolicy{Rules: []planRule{{Name: "dev", Namespaces: []string{"dev"}}}}, "plans is required"},
+		{planPolicy{Rules: []planRule{
+			{Name: "dev", Namespaces: []string{"dev"}, Plans: []string{"small"}},
+			{Name: "dev", Namespaces: []string{"test"}, Plans: []string{"small"}},
+		}}, "defined twice"},
+	} {
+		err := c.policy.validate()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("Error for %+v does not match: got %v; want %q", c.policy, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+)
+
+// probeTimeout bounds each probe call, so that an unreachable cluster fails
+// fast instead of hanging.
+const probeTimeout = "--request-timeout=15s"
+
+// probedResources are the resources install and uninstall must be allowed
+// to create or delete. The namespaced ones are checked in the Service
+// Catalog namespace only.
+var probedResources = []struct {
+	resource   string
+	namespaced bool
+}{
+	{"namespaces", false},
+	{"clusterroles.rbac.authorization.k8s.io", false},
+	{"clusterrolebindings.rbac.authorization.k8s.io", false},
+	{"apiservices.apiregistration.k8s.io", false},
+	{"deployments.apps", true},
+}
+
+// clusterInfo describes the cluster kubectl talks to.
+type clusterInfo struct {
+	Context    string
+	Server     string
+	AuthMethod string
+}
+
+// kubeconfig is the subset of `kubectl config view --minify` used to
+// describe the current cluster.
+type kubeconfig struct {
+	CurrentContext string `json:"current-context"`
+	Clusters       []struct {
+		Cluster struct {
+			Server string `json:"server"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Users []struct {
+		Name string                 `json:"name"`
+		User map[string]interface{} `json:"user"`
+	} `json:"users"`
+}
+
+// info returns the cluster described by the kubeconfig.
+func (k *kubeconfig) info() *clusterInfo {
+	ci := &clusterInfo{Context: k.CurrentContext, Server: "unknown", AuthMethod: "none"}
+	if len(k.Clusters) > 0 && k.Clusters[0].Cluster.Server != "" {
+		ci.Server = k.Clusters[0].Cluster.Server
+	}
+	if len(k.Users) > 0 {
+		ci.AuthMethod = authMethod(k.Users[0].User)
+	}
+	return ci
+}
+
+// authMethod describes how a kubeconfig user authenticates.
+func authMethod(user map[string]interface{}) string {
+	if p, ok := user["auth-provider"].(map[string]interface{}); ok {
+		return fmt.Sprintf("auth provider %v", p["name"])
+	}
+	if e, ok := user["exec"].(map[string]interface{}); ok {
+		return fmt.Sprintf("exec plugin %v", e["command"])
+	}
+	for _, m := range []struct{ key, method string }{
+		{"client-certificate", "client certificate"},
+		{"client-certificate-data", "client certificate"},
+		{"token", "bearer token"},
+		{"tokenFile", "bearer token"},
+		{"username", "basic auth"},
+	} {
+		if _, ok := user[m.key]; ok {
+			return m.method
+		}
+	}
+	return "none"
+}
+
+func currentClusterInfo() *clusterInfo {
+	if c, ok := loadInClusterConfig(); ok {
+		return &clusterInfo{
+			Context:    "in-cluster",
+			Server:     c.Server,
+			AuthMethod: "service account token of " + c.serviceAccount(),
+		}
+	}
+
+	output, err := kubectlCommand("config", "view", "--minify", "-o", "json").Output()
+	var k kubeconfig
+	if err != nil || json.Unmarshal(output, &k) != nil {
+		return &clusterInfo{Context: "unknown", Server: "unknown", AuthMethod: "unknown"}
+	}
+	return k.info()
+}
+
+// probeCluster checks that the cluster is reachable and that the current
+// user may perform verb, e.g. create or delete, on the Service Catalog
+// resources in namespace ns. It is called before anything is generated or
+// applied, and its error describes the cluster and the failing call.
+func probeCluster(verb, ns string) error {
+	if c, ok := loadInClusterConfig(); ok {
+		return probeInCluster(c, verb, ns)
+	}
+
+	calls := [][]string{{"get", "--raw", "/version", probeTimeout}}
+	for _, r := range probedResources {
+		args := []string{"auth", "can-i", verb, r.resource, probeTimeout}
+		if r.namespaced {
+			args = append(args, "--namespace", ns)
+		}
+		calls = append(calls, args)
+	}
+
+	for _, args := range calls {
+		output, err := kubectlCommand(args...).CombinedOutput()
+		if err == nil {
+			continue
+		}
+
+		result := strings.TrimSpace(string(output))
+		details := probeDetails(KubectlBinaryName+" "+strings.Join(args, " "), result)
+		if args[0] == "auth" {
+			if result == "no" {
+				details += ", permission denied, run as cluster-admin or with the roles of `sc generate installer-rbac`"
+			}
+			return messages.Errorf(messages.PermissionDenied, verb, args[3], details)
+		}
+		return messages.Errorf(messages.ClusterUnreachable, details)
+	}
+	return nil
+}
+
+// probeInCluster is probeCluster calling the API server with the in-cluster
+// config c.
+func probeInCluster(c *inClusterConfig, verb, ns string) error {
+	if _, err := c.serverVersion(); err != nil {
+		return messages.Errorf(messages.ClusterUnreachable, probeDetails("GET /version", err.Error()))
+	}
+	for _, r := range probedResources {
+		rns := ""
+		if r.namespaced {
+			rns = ns
+		}
+		call := fmt.Sprintf("SelfSubjectAccessReview %s %s", verb, r.resource)
+		if rns != "" {
+			call += " in namespace " + rns
+		}
+		allowed, err := c.canI(verb, r.resource, rns)
+		if err != nil {
+			return messages.Errorf(messages.ClusterUnreachable, probeDetails(call, err.Error()))
+		}
+		if !allowed {
+			details := probeDetails(call, "no") + ", permission denied, run as cluster-admin or with the roles of `sc generate installer-rbac`"
+			return messages.Errorf(messages.PermissionDenied, verb, r.resource, details)
+		}
+	}
+	return nil
+}
+
+// probeDetails describes the cluster and the failing call of a probe with
+// its result.
+func probeDetails(call, result string) string {
+	ci := currentClusterInfo()
+	return fmt.Sprintf("\n"+
+		"  context:      %s\n"+
+		"  endpoint:     %s\n"+
+		"  auth method:  %s\n"+
+		"  failing call: %s\n"+
+		"  result:       %s",
+		ci.Context, ci.Server, ci.AuthMethod, call, result)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestKubeconfigInfo tests that the endpoint and auth method of the current
+// cluster are read from a minified kubeconfig.
+func TestKubeconfigInfo(t *testing.T) {
+	for _, tc := range []struct {
+		user string
+		want string
+	}{
+		{`{"auth-provider": {"name": "gcp", "config": {}}}`, "auth provider gcp"},
+		{`{"exec": {"command": "aws-iam-authenticator"}}`, "exec plugin aws-iam-authenticator"},
+		{`{"client-certificate-data": "REDACTED", "client-key-data": "REDACTED"}`, "client certificate"},
+		{`{"token": "REDACTED"}`, "bearer token"},
+		{`{}`, "none"},
+	} {
+		var k kubeconfig
+		if err := json.Unmarshal([]byte(`{
+			"current-context": "gke_p_z_c",
+			"clusters": [{"name": "gke_p_z_c", "cluster": {"server": "https://35.1.2.3"}}],
+			"users": [{"name": "gke_p_z_c", "user": `+tc.user+`}]
+		}`), &k); err != nil {
+			t.Fatalf("Unexpected error unmarshalling kubeconfig: %v", err)
+		}
+
+		got := k.info()
+		if got.Context != "gke_p_z_c" || got.Server != "https://35.1.2.3" {
+			t.Fatalf("Cluster does not match: got %+v; want context gke_p_z_c and server https://35.1.2.3", got)
+		}
+		if got.AuthMethod != tc.want {
+			t.Fatalf("Auth method does not match: got %q; want %q", got.AuthMethod, tc.want)
+		}
+	}
+}
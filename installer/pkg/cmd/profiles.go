@@ -0,0 +1,108 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const defaultProfile = "default"
+
+// installProfile is a named bundle of install settings.
+type installProfile struct {
+	EtcdClusterSize           int32
+	EtcdBackup                bool
+	APIServerReplicas         int32
+	ControllerManagerReplicas int32
+	PodDisruptionBudgets      bool
+	Monitoring                bool
+	NetworkPolicies           bool
+	PriorityClass             bool
+	ServiceMonitor            bool
+}
+
+// installProfiles are the profiles selectable with --profile.
+var installProfiles = map[string]installProfile{
+	// minimal runs a single replica of everything with a single member
+	// etcd cluster without backups, e.g. for development clusters. It
+	// keeps the etcd storage: the CRD storage of the api server is alpha
+	// in v0.1 and dropped in v0.2, sc does not offer it.
+	"minimal": {
+		EtcdClusterSize:           1,
+		APIServerReplicas:         1,
+		ControllerManagerReplicas: 1,
+	},
+	"default": {
+		EtcdClusterSize:           3,
+		EtcdBackup:                true,
+		APIServerReplicas:         1,
+		ControllerManagerReplicas: 1,
+	},
+	// production runs a highly available catalog protected by
+	// PodDisruptionBudgets, NetworkPolicies and a PriorityClass, with
+	// metrics scraping. The features the cluster lacks are disabled.
+	"production": {
+		EtcdClusterSize:           5,
+		EtcdBackup:                true,
+		APIServerReplicas:         2,
+		ControllerManagerReplicas: 2,
+		PodDisruptionBudgets:      true,
+		Monitoring:                true,
+		NetworkPolicies:           true,
+		PriorityClass:             true,
+		ServiceMonitor:            true,
+	},
+}
+
+// profileNames returns the names of the install profiles, sorted.
+func profileNames() []string {
+	var names []string
+	for n := range installProfiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile sets the settings of the profile ic.Profile on ic, except
+// for the settings explicitly set with a flag in flags.
+func applyProfile(ic *InstallConfig, flags *pflag.FlagSet) error {
+	p, ok := installProfiles[ic.Profile]
+	if !ok {
+		return fmt.Errorf("unknown profile %q, must be one of: %s", ic.Profile, strings.Join(profileNames(), ", "))
+	}
+
+	set := func(flag string, apply func()) {
+		if !flags.Changed(flag) {
+			apply()
+		}
+	}
+	set("etcd-cluster-size", func() { ic.EtcdClusterSize = p.EtcdClusterSize })
+	set("etcd-backup", func() { ic.EtcdBackup = p.EtcdBackup })
+	set("apiserver-replicas", func() { ic.APIServerReplicas = p.APIServerReplicas })
+	set("controller-manager-replicas", func() { ic.ControllerManagerReplicas = p.ControllerManagerReplicas })
+	set("enable-pdb", func() { ic.PodDisruptionBudgets = p.PodDisruptionBudgets })
+	set("enable-monitoring", func() { ic.Monitoring = p.Monitoring })
+	set("enable-network-policies", func() { ic.NetworkPolicies = p.NetworkPolicies })
+	set("enable-priority-class", func() { ic.PriorityClass = p.PriorityClass })
+	set("enable-service-monitor", func() { ic.ServiceMonitor = p.ServiceMonitor })
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestApplyProfile tests that the profile settings apply unless their flag
+// is set explicitly.
+func TestApplyProfile(t *testing.T) {
+	for _, tc := range []struct {
+		args                  []string
+		wantEtcdClusterSize   int32
+		wantAPIServerReplicas int32
+		wantPDB               bool
+	}{
+		{[]string{"--profile", "production"}, 5, 2, true},
+		{[]string{"--profile", "production", "--etcd-cluster-size", "3"}, 3, 2, true},
+		{[]string{"--profile", "production", "--enable-pdb=false"}, 5, 2, false},
+		{[]string{"--profile", "minimal", "--apiserver-replicas", "3"}, 1, 3, false},
+		{nil, 3, 1, false},
+	} {
+		ic := newInstallConfig()
+		c := &cobra.Command{}
+		addInstallFlags(c, ic)
+		if err := c.Flags().Parse(tc.args); err != nil {
+			t.Fatalf("%v: unexpected error parsing the flags: %v", tc.args, err)
+		}
+		if err := applyProfile(ic, c.Flags()); err != nil {
+			t.Fatalf("%v: unexpected error applying the profile: %v", tc.args, err)
+		}
+		if ic.EtcdClusterSize != tc.wantEtcdClusterSize {
+			t.Errorf("%v: expected an etcd cluster size of %d, got %d", tc.args, tc.wantEtcdClusterSize, ic.EtcdClusterSize)
+		}
+		if ic.APIServerReplicas != tc.wantAPIServerReplicas {
+			t.Errorf("%v: expected %d api server replicas, got %d", tc.args, tc.wantAPIServerReplicas, ic.APIServerReplicas)
+		}
+		if ic.PodDisruptionBudgets != tc.wantPDB {
+			t.Errorf("%v: expected PodDisruptionBudgets %v, got %v", tc.args, tc.wantPDB, ic.PodDisruptionBudgets)
+		}
+	}
+}
+
+// TestApplyUnknownProfile tests that an unknown profile is rejected.
+func TestApplyUnknownProfile(t *testing.T) {
+	ic := newInstallConfig()
+	c := &cobra.Command{}
+	addInstallFlags(c, ic)
+	if err := c.Flags().Parse([]string{"--profile", "huge"}); err != nil {
+		t.Fatalf("Unexpected error parsing the flags: %v", err)
+	}
+	err := applyProfile(ic, c.Flags())
+	if err == nil || !strings.Contains(err.Error(), `unknown profile "huge", must be one of: default, minimal, production`) {
+		t.Fatalf("Expected an unknown profile error, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// proxyCADir is where the secret controller-manager-proxy-ca is mounted,
+// holding the CA bundle of the egress proxy. Go loads the certificates of
+// SSL_CERT_DIR in addition to the CA bundle of the image.
+const proxyCADir = "/etc/ssl/proxy-ca"
+
+// defaultNoProxy are the destinations the controller manager always calls
+// directly: the Kubernetes API, expanded by the kubelet, and the services
+// of the cluster, e.g. brokers deployed in it.
+var defaultNoProxy = []string{"localhost", "127.0.0.1", "$(KUBERNETES_SERVICE_HOST)", ".svc", ".cluster.local"}
+
+// validateProxy checks the egress proxy options of ic.
+func validateProxy(ic *InstallConfig) error {
+	if ic.ControllerManagerProxy == "" {
+		if len(ic.ControllerManagerNoProxy) > 0 {
+			return fmt.Errorf("--controller-manager-no-proxy requires --controller-manager-proxy")
+		}
+		return nil
+	}
+	u, err := url.Parse(ic.ControllerManagerProxy)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("--controller-manager-proxy must be an http:// or https:// URL, e.g. http://proxy.corp.example.com:3128, got %q", ic.ControllerManagerProxy)
+	}
+	return nil
+}
+
+// proxyData returns the template data of the egress proxy of the controller
+// manager, reading its CA bundle.
+func proxyData(ic *InstallConfig) (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"ControllerManagerProxy":   ic.ControllerManagerProxy,
+		"ControllerManagerNoProxy": strings.Join(append(append([]string(nil), defaultNoProxy...), ic.ControllerManagerNoProxy...), ","),
+		"ProxyCADir":               proxyCADir,
+		"ControllerManagerProxyCA": "",
+	}
+	if ic.ControllerManagerProxyCAFile != "" {
+		b, err := readCABundle(ic.ControllerManagerProxyCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --controller-manager-proxy-ca-file: %v", err)
+		}
+		data["ControllerManagerProxyCA"] = base64.StdEncoding.EncodeToString(b)
+	}
+	return data, nil
+}
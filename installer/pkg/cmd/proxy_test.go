@@ -0,0 +1,88 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProxyData tests that the cluster destinations always bypass the
+// proxy and that its CA bundle is read.
+func TestProxyData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proxy")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	ca, _, _, _ := testCert(t, nil, time.Now().Add(time.Hour), nil, nil)
+	if err := ioutil.WriteFile(filepath.Join(dir, "proxy-ca.crt"), ca, 0644); err != nil {
+		t.Fatalf("Unexpected error writing the CA: %v", err)
+	}
+
+	ic := validInstallConfig()
+	ic.ControllerManagerProxy = "http://proxy.corp.example.com:3128"
+	ic.ControllerManagerNoProxy = []string{".corp.example.com"}
+	ic.ControllerManagerProxyCAFile = filepath.Join(dir, "proxy-ca.crt")
+	data, err := proxyData(ic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, want := data["ControllerManagerNoProxy"], "localhost,127.0.0.1,$(KUBERNETES_SERVICE_HOST),.svc,.cluster.local,.corp.example.com"; got != want {
+		t.Fatalf("NO_PROXY does not match: got %v; want %v", got, want)
+	}
+	if got, want := data["ControllerManagerProxyCA"], base64.StdEncoding.EncodeToString(ca); got != want {
+		t.Fatalf("Proxy CA does not match: got %v; want %v", got, want)
+	}
+
+	ic.ControllerManagerProxyCAFile = filepath.Join(dir, "missing")
+	if _, err := proxyData(ic); err == nil || !strings.Contains(err.Error(), "--controller-manager-proxy-ca-file") {
+		t.Fatalf("Error does not match: got %v; want invalid --controller-manager-proxy-ca-file ...", err)
+	}
+}
+
+// TestValidateProxy tests the checks of the proxy URL.
+func TestValidateProxy(t *testing.T) {
+	for _, tc := range []struct {
+		proxy   string
+		noProxy []string
+		wantErr string
+	}{
+		{"", nil, ""},
+		{"http://proxy.corp.example.com:3128", []string{"10.0.0.0/8"}, ""},
+		{"https://proxy.corp.example.com", nil, ""},
+		{"proxy.corp.example.com:3128", nil, "must be an http:// or https:// URL"},
+		{"socks5://proxy.corp.example.com:1080", nil, "must be an http:// or https:// URL"},
+		{"", []string{".corp.example.com"}, "requires --controller-manager-proxy"},
+	} {
+		ic := validInstallConfig()
+		ic.ControllerManagerProxy = tc.proxy
+		ic.ControllerManagerNoProxy = tc.noProxy
+		err := validateProxy(ic)
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("%q: unexpected error: %v", tc.proxy, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("%q: expected an error containing %q, got %v", tc.proxy, tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// pruneConfig contains the prune configuration.
+type pruneConfig struct {
+	// Namespace to prune, all namespaces if AllNamespaces is set.
+	Namespace     string
+	AllNamespaces bool
+
+	// Cascade deletes the bindings of instances and deprovisions
+	// instances holding external resources.
+	Cascade bool
+
+	// DryRun only lists what would be deleted.
+	DryRun bool
+}
+
+// instanceInfo is a ServiceInstance with the bindings referencing it.
+type instanceInfo struct {
+	Namespace string
+	Name      string
+	Bindings  []string
+	// Provisioned is true if the broker holds external resources for
+	// the instance, e.g. a database.
+	Provisioned bool
+}
+
+func (i *instanceInfo) String() string {
+	return i.Namespace + "/" + i.Name
+}
+
+// protection returns why deleting the instance risks data loss, or "" if it
+// does not.
+func (i *instanceInfo) protection() string {
+	var reasons []string
+	if len(i.Bindings) > 0 {
+		reasons = append(reasons, "has bindings "+strings.Join(i.Bindings, ", "))
+	}
+	if i.Provisioned {
+		reasons = append(reasons, "holds provisioned resources")
+	}
+	return strings.Join(reasons, " and ")
+}
+
+func NewPruneCmd() *cobra.Command {
+	pc := &pruneConfig{}
+	c := &cobra.Command{
+		Use:   "prune",
+		Short: "deletes the ServiceInstances of a namespace",
+		Long: `deletes the ServiceInstances of a namespace. Instances that have
+bindings or hold provisioned resources are only deleted, along with their
+bindings, with --cascade, since deprovisioning them deletes the data of the
+managed services.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := pruneInstances(pc); err != nil {
+				messages.Println(messages.PruneFailed)
+				return err
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVar(&pc.Namespace, "namespace", "default", "Namespace of the instances to delete")
+	c.Flags().BoolVar(&pc.AllNamespaces, "all-namespaces", false, "Delete the instances of all namespaces")
+	c.Flags().BoolVar(&pc.Cascade, "cascade", false, "Also delete instances with bindings or provisioned resources, and their bindings")
+	c.Flags().BoolVar(&pc.DryRun, "dry-run", false, "Only list the instances and bindings that would be deleted")
+	return c
+}
+
+func pruneInstances(pc *pruneConfig) error {
+	ns := pc.Namespace
+	if pc.AllNamespaces {
+		ns = ""
+	}
+	instances, err := listInstances(ns)
+	if err != nil {
+		return err
+	}
+
+	var protected []*instanceInfo
+	for _, i := range instances {
+		if reason := i.protection(); reason != "" && !pc.Cascade {
+			fmt.Printf("skipping instance %s: it %s\n", i, reason)
+			protected = append(protected, i)
+			continue
+		}
+
+		if pc.DryRun {
+			for _, b := range i.Bindings {
+				fmt.Printf("would delete binding %s/%s\n", i.Namespace, b)
+			}
+			fmt.Printf("would delete instance %s\n", i)
+			continue
+		}
+		if err := deleteInstance(i); err != nil {
+			return err
+		}
+	}
+
+	if len(protected) > 0 {
+		return fmt.Errorf("%d instances were not deleted, use --cascade to delete them and their bindings", len(protected))
+	}
+	return nil
+}
+
+// deleteInstance deletes the bindings of i, then i, waiting for the broker
+// to unbind and deprovision.
+func deleteInstance(i *instanceInfo) error {
+	for _, b := range i.Bindings {
+		fmt.Printf("deleting binding %s/%s\n", i.Namespace, b)
+		output, err := kubectlCommand("delete", "servicebindings.servicecatalog.k8s.io", b,
+			"--namespace", i.Namespace, "--wait=true").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error deleting binding %s/%s: %s", i.Namespace, b, strings.TrimSpace(string(output)))
+		}
+	}
+
+	fmt.Printf("deleting instance %s\n", i)
+	output, err := kubectlCommand("delete", "serviceinstances.servicecatalog.k8s.io", i.Name,
+		"--namespace", i.Namespace, "--wait=true").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deleting instance %s: %s", i, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// catalogResourceList is the subset of a list of ServiceInstances or
+// ServiceBindings read by prune.
+type catalogResourceList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Spec struct {
+			InstanceRef struct {
+				Name string `json:"name"`
+			} `json:"instanceRef"`
+		} `json:"spec"`
+		Status struct {
+			ProvisionStatus string `json:"provisionStatus"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// listInstances lists the instances of namespace ns, or of all namespaces if
+// ns is empty, with their bindings.
+func listInstances(ns string) ([]*instanceInfo, error) {
+	var instances, bindings catalogResourceList
+	for _, l := range []struct {
+		resource string
+		list     *catalogResourceList
+	}{
+		{"serviceinstances.servicecatalog.k8s.io", &instances},
+		{"servicebindings.servicecatalog.k8s.io", &bindings},
+	} {
+		args := []string{"get", l.resource, "-o", "json"}
+		if ns == "" {
+			args = append(args, "--all-namespaces")
+		} else {
+			args = append(args, "--namespace", ns)
+		}
+		output, err := kubectlCommand(args...).CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s: %s", l.resource, strings.TrimSpace(string(output)))
+		}
+		if err := json.Unmarshal(output, l.list); err != nil {
+			return nil, fmt.Errorf("error unmarshalling %s: %v", l.resource, err)
+		}
+	}
+	return joinInstances(&instances, &bindings), nil
+}
+
+// joinInstances joins instances with the bindings referencing them, sorted
+// by namespace and name.
+func joinInstances(instances, bindings *catalogResourceList) []*instanceInfo {
+	byName := make(map[string]*instanceInfo)
+	var result []*instanceInfo
+	for _, item := range instances.Items {
+		i := &instanceInfo{
+			Namespace:   item.Metadata.Namespace,
+			Name:        item.Metadata.Name,
+			Provisioned: item.Status.ProvisionStatus == "Provisioned",
+		}
+		byName[i.String()] = i
+		result = append(result, i)
+	}
+	for _, item := range bindings.Items {
+		if i, ok := byName[item.Metadata.Namespace+"/"+item.Spec.InstanceRef.Name]; ok {
+			i.Bindings = append(i.Bindings, item.Metadata.Name)
+		}
+	}
+
+	sort.Slice(result, func(a, b int) bool { return result[a].String() < result[b].String() })
+	for _, i := range result {
+		sort.Strings(i.Bindings)
+	}
+	return result
+}
+
+// checkInstancesBeforeUninstall returns an error listing the instances that
+// would lose their external resources or bindings if Service Catalog were
+// uninstalled. With cascade, it deletes all the instances instead.
+func checkInstancesBeforeUninstall(cascade bool) error {
+	installed, err := isServiceCatalogInstalled()
+	if err != nil || !installed {
+		return err
+	}
+	instances, err := listInstances("")
+	if err != nil {
+		return err
+	}
+
+	if cascade {
+		for _, i := range instances {
+			if err := deleteInstance(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var protected []string
+	for _, i := range instances {
+		if reason := i.protection(); reason != "" {
+			protected = append(protected, fmt.Sprintf("   %s: %s", i, reason))
+		}
+	}
+	if len(protected) > 0 {
+		return fmt.Errorf("uninstalling would orphan these instances:\n%s\n"+
+			"delete them first, e.g. with `sc prune --cascade`, or uninstall with --strategy graceful, or --strategy fast to leave their resources behind", strings.Join(protected, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestJoinInstances tests that bindings are joined with the instances of
+// their namespace and that protected instances are detected.
+func TestJoinInstances(t *testing.T) {
+	var instances, bindings catalogResourceList
+	if err := json.Unmarshal([]byte(`{"items": [
+		{"metadata": {"namespace": "b", "name": "db"}, "status": {"provisionStatus": "Provisioned"}},
+		{"metadata": {"namespace": "a", "name": "db"}, "status": {"provisionStatus": ""}},
+		{"metadata": {"namespace": "a", "name": "queue"}, "status": {"provisionStatus": "NotProvisioned"}}
+	]}`), &instances); err != nil {
+		t.Fatalf("Unexpected error unmarshalling instances: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"items": [
+		{"metadata": {"namespace": "a", "name": "db-2"}, "spec": {"instanceRef": {"name": "db"}}},
+		{"metadata": {"namespace": "a", "name": "db-1"}, "spec": {"instanceRef": {"name": "db"}}},
+		{"metadata": {"namespace": "c", "name": "db-1"}, "spec": {"instanceRef": {"name": "db"}}}
+	]}`), &bindings); err != nil {
+		t.Fatalf("Unexpected error unmarshalling bindings: %v", err)
+	}
+
+	got := joinInstances(&instances, &bindings)
+	want := []*instanceInfo{
+		{Namespace: "a", Name: "db", Bindings: []string{"db-1", "db-2"}},
+		{Namespace: "a", Name: "queue"},
+		{Namespace: "b", Name: "db", Provisioned: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Instances do not match: got %+v; want %+v", got, want)
+	}
+
+	for i, want := range []string{"has bindings db-1, db-2", "", "holds provisioned resources"} {
+		if p := got[i].protection(); p != want {
+			t.Fatalf("Protection of %s does not match: got %q; want %q", got[i], p, want)
+		}
+	}
+}
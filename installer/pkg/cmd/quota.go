@@ -0,0 +1,59 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+)
+
+// gatekeeperAPIVersion is the API version of OPA Gatekeeper constraint
+// templates.
+const gatekeeperAPIVersion = "templates.gatekeeper.sh/v1beta1"
+
+// checkGatekeeperInstalled returns an error if OPA Gatekeeper, which enforces
+// the ServiceInstance limit and plan policy, is not installed, or with
+// mutation, for the binding secret policy, runs without mutation. With sync,
+// for the limit, Gatekeeper must also be configured to sync ServiceInstances
+// so the constraint can count them, which is left to the user since the
+// sync configuration is shared.
+func checkGatekeeperInstalled(sync, mutation bool) error {
+	versions, err := servedAPIVersions()
+	if err != nil {
+		return fmt.Errorf("failed to check API availability : %v", err)
+	}
+	if !versions[gatekeeperAPIVersion] {
+		return messages.Errorf(messages.GatekeeperNotInstalled, gatekeeperAPIVersion)
+	}
+	if mutation && !versions[gatekeeperMutationAPIVersion] {
+		return messages.Errorf(messages.GatekeeperNotInstalled, gatekeeperMutationAPIVersion+", enable mutation in Gatekeeper")
+	}
+	if !sync {
+		return nil
+	}
+
+	fmt.Println(`NOTE: the ServiceInstance limit counts the instances synced by Gatekeeper.
+Make sure the Gatekeeper Config in the gatekeeper-system namespace syncs them:
+  spec:
+    sync:
+      syncOnly:
+      - group: servicecatalog.k8s.io
+        version: v1beta1
+        kind: ServiceInstance`)
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+)
+
+// TestCheckGatekeeperInstalled tests that the Gatekeeper API versions the
+// policies need must be served.
+func TestCheckGatekeeperInstalled(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		versions string
+		mutation bool
+		wantErr  string
+	}{
+		{"installed", "v1\ntemplates.gatekeeper.sh/v1beta1\n", false, ""},
+		{"installed with mutation", "v1\ntemplates.gatekeeper.sh/v1beta1\nmutations.gatekeeper.sh/v1beta1\n", true, ""},
+		{"not installed", "v1\napps/v1\n", false, "templates.gatekeeper.sh/v1beta1 is not served"},
+		{"without mutation", "v1\ntemplates.gatekeeper.sh/v1beta1\n", true, "mutations.gatekeeper.sh/v1beta1, enable mutation in Gatekeeper is not served"},
+	} {
+		_, restore := stubExecutor(func(name string, args []string) execx.Response {
+			return execx.Response{Stdout: tc.versions}
+		})
+		err := checkGatekeeperInstalled(true, tc.mutation)
+		restore()
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("%s: expected an error containing %q, got %v", tc.name, tc.wantErr, err)
+		case tc.wantErr != "" && messages.CodeOf(err) != messages.GatekeeperNotInstalled:
+			t.Fatalf("%s: expected the code %s, got %s", tc.name, messages.GatekeeperNotInstalled, messages.CodeOf(err))
+		}
+	}
+}
+
+// TestCheckGatekeeperInstalledKubectlError tests that a failing kubectl is
+// reported rather than taken for a missing Gatekeeper.
+func TestCheckGatekeeperInstalledKubectlError(t *testing.T) {
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		return execx.Response{Stderr: "Unable to connect to the server", ExitCode: 1}
+	})
+	defer restore()
+
+	err := checkGatekeeperInstalled(false, false)
+	if err == nil || !strings.Contains(err.Error(), "failed to check API availability") {
+		t.Fatalf("Expected an API availability error, got %v", err)
+	}
+	if messages.CodeOf(err) == messages.GatekeeperNotInstalled {
+		t.Fatalf("Expected the kubectl error not to be reported as a missing Gatekeeper")
+	}
+}
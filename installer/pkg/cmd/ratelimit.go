@@ -0,0 +1,126 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/spf13/cobra"
+)
+
+// Names of the flags limiting the rate of the kubectl calls of sc.
+const (
+	KubectlQPSFlagName   = "kubectl-qps"
+	KubectlBurstFlagName = "kubectl-burst"
+)
+
+// Default rate limits of the kubectl calls, so that installs run side by
+// side, e.g. into many namespaces or across a fleet of clusters, stay
+// within the API Priority and Fairness limits of the clusters. They limit
+// the kubectl processes sc starts, not the API requests each of them makes:
+// kubectl has no flag to pass a client rate limit to, so a call listing or
+// applying many objects still makes many requests.
+const (
+	DefaultKubectlQPS   = 10
+	DefaultKubectlBurst = 20
+)
+
+// kubectlLimiter limits the rate of the kubectl calls, nil means unlimited.
+var kubectlLimiter *tokenBucket
+
+// SetKubectlRateLimit sets the rate limit of the kubectl calls from the
+// rate limit flags of c. A zero QPS disables the limit.
+func SetKubectlRateLimit(c *cobra.Command) error {
+	qps, err := c.Flags().GetFloat32(KubectlQPSFlagName)
+	if err != nil {
+		return err
+	}
+	burst, err := c.Flags().GetInt(KubectlBurstFlagName)
+	if err != nil {
+		return err
+	}
+	if qps < 0 {
+		return fmt.Errorf("--%s must not be negative, got %v", KubectlQPSFlagName, qps)
+	}
+	if qps == 0 {
+		kubectlLimiter = nil
+		return nil
+	}
+	if burst < 1 {
+		return fmt.Errorf("--%s must be at least 1, got %d", KubectlBurstFlagName, burst)
+	}
+	kubectlLimiter = newTokenBucket(float64(qps), burst)
+	return nil
+}
+
+// kubectlCommand returns the kubectl command with args once the rate limit
+// allows it. Callers run it right away.
+func kubectlCommand(args ...string) *exec.Cmd {
+	if kubectlLimiter != nil {
+		kubectlLimiter.wait()
+	}
+	return execx.Command(KubectlBinaryName, args...)
+}
+
+// tokenBucket is a token bucket rate limiter, safe for concurrent use.
+type tokenBucket struct {
+	mu     sync.Mutex
+	qps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func newTokenBucket(qps float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		qps:    qps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+}
+
+// wait takes a token, waiting for it if the bucket is empty. Tokens are
+// reserved before waiting, so concurrent callers are served in turn.
+func (b *tokenBucket) wait() {
+	b.mu.Lock()
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.qps
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens--
+	var d time.Duration
+	if b.tokens < 0 {
+		d = time.Duration(-b.tokens / b.qps * float64(time.Second))
+	}
+	b.mu.Unlock()
+	if d > 0 {
+		b.sleep(d)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TestTokenBucket tests that a burst is served right away and that later
+// calls wait for their token, in turn.
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	b := newTokenBucket(2, 3)
+	b.now = func() time.Time { return now }
+	b.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	for i := 0; i < 5; i++ {
+		b.wait()
+	}
+	want := []time.Duration{500 * time.Millisecond, time.Second}
+	if len(slept) != len(want) || slept[0] != want[0] || slept[1] != want[1] {
+		t.Fatalf("Waits do not match: got %v; want %v", slept, want)
+	}
+
+	// The bucket refills at qps, up to the burst.
+	now = now.Add(time.Hour)
+	slept = nil
+	for i := 0; i < 3; i++ {
+		b.wait()
+	}
+	if len(slept) != 0 {
+		t.Fatalf("Unexpected waits after the bucket refilled: %v", slept)
+	}
+}
+
+// TestSetKubectlRateLimit tests that the flags set the limiter, that a zero
+// QPS disables it and that missing flags are an error.
+func TestSetKubectlRateLimit(t *testing.T) {
+	defer func() { kubectlLimiter = nil }()
+	newCmd := func(args ...string) *cobra.Command {
+		c := &cobra.Command{}
+		c.Flags().Float32(KubectlQPSFlagName, DefaultKubectlQPS, "")
+		c.Flags().Int(KubectlBurstFlagName, DefaultKubectlBurst, "")
+		if err := c.Flags().Parse(args); err != nil {
+			t.Fatalf("Unexpected error parsing flags: %v", err)
+		}
+		return c
+	}
+
+	if err := SetKubectlRateLimit(newCmd("--kubectl-qps", "50", "--kubectl-burst", "100")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if kubectlLimiter == nil || kubectlLimiter.qps != 50 || kubectlLimiter.burst != 100 {
+		t.Fatalf("Limiter does not match: got %+v; want 50 qps and a burst of 100", kubectlLimiter)
+	}
+	if err := SetKubectlRateLimit(newCmd("--kubectl-qps", "0")); err != nil || kubectlLimiter != nil {
+		t.Fatalf("A zero QPS must disable the limiter: got %+v, %v", kubectlLimiter, err)
+	}
+	for _, args := range [][]string{{"--kubectl-qps", "-1"}, {"--kubectl-burst", "0"}} {
+		if err := SetKubectlRateLimit(newCmd(args...)); err == nil {
+			t.Fatalf("Expected an error for %v", args)
+		}
+	}
+	if err := SetKubectlRateLimit(&cobra.Command{}); err == nil {
+		t.Fatalf("Expected an error for a command without the rate limit flags")
+	}
+}
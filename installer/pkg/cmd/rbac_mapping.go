@@ -0,0 +1,132 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// catalogRoles are the ClusterRoles granting access to the catalog
+// resources a mapping rule may bind, from the most to the least privileged:
+// admin manages everything, including the brokers, edit manages the
+// instances and bindings and reads the rest, view reads everything.
+var catalogRoles = []string{"admin", "edit", "view"}
+
+// rbacMapping maps groups to their access to the catalog resources. It is
+// read from the file passed with --rbac-mapping, e.g.
+//
+//	rules:
+//	- name: platform
+//	  groups: [platform-admins]
+//	  role: admin
+//	- name: payments
+//	  groups: [payments-devs, payments-ci]
+//	  role: edit
+//	  namespaces: [payments, payments-staging]
+//	- name: auditors
+//	  groups: [auditors]
+//	  role: view
+type rbacMapping struct {
+	Rules []rbacRule `json:"rules"`
+}
+
+// rbacRule grants groups a catalog role, in all namespaces or in some.
+type rbacRule struct {
+	// Name names the bindings of the rule, servicecatalog.k8s.io:mapping:<name>.
+	Name string `json:"name"`
+
+	// Groups are the groups granted the role.
+	Groups []string `json:"groups"`
+
+	// Role is one of catalogRoles.
+	Role string `json:"role"`
+
+	// Namespaces restricts the role to these namespaces with
+	// RoleBindings. Without namespaces the role is granted in the whole
+	// cluster with a ClusterRoleBinding.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// loadRBACMapping reads and validates the RBAC mapping in file.
+func loadRBACMapping(file string) (*rbacMapping, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading RBAC mapping: %v", err)
+	}
+	m := &rbacMapping{}
+	if err := yaml.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("error parsing RBAC mapping %s: %v", file, err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid RBAC mapping %s: %v", file, err)
+	}
+	return m, nil
+}
+
+func (m *rbacMapping) validate() error {
+	if len(m.Rules) == 0 {
+		return fmt.Errorf("no rules")
+	}
+	names := make(map[string]bool)
+	for i, r := range m.Rules {
+		if !dns1123LabelRE.MatchString(r.Name) {
+			return fmt.Errorf("rule %d: name %q must be a lowercase DNS label", i, r.Name)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("rule %s is defined twice", r.Name)
+		}
+		names[r.Name] = true
+		if !containsString(catalogRoles, r.Role) {
+			return fmt.Errorf("rule %s: role %q must be one of %s", r.Name, r.Role, strings.Join(catalogRoles, ", "))
+		}
+		if len(r.Groups) == 0 {
+			return fmt.Errorf("rule %s: groups is required", r.Name)
+		}
+		for _, g := range r.Groups {
+			if g == "" {
+				return fmt.Errorf("rule %s: groups must not be empty", r.Name)
+			}
+		}
+		for _, ns := range r.Namespaces {
+			if !dns1123LabelRE.MatchString(ns) {
+				return fmt.Errorf("rule %s: namespace %q must be a lowercase DNS label", r.Name, ns)
+			}
+		}
+	}
+	return nil
+}
+
+// rbacMappingData returns the template data rendering the catalog roles and
+// the bindings of the RBAC mapping of ic, if any.
+func rbacMappingData(ic *InstallConfig) (map[string]interface{}, error) {
+	m := ic.RBACMapping
+	if m == nil && ic.RBACMappingFile != "" {
+		var err error
+		if m, err = loadRBACMapping(ic.RBACMappingFile); err != nil {
+			return nil, err
+		}
+	}
+	data := map[string]interface{}{"RBACMapping": m != nil}
+	if m != nil {
+		data["RBACRules"] = m.Rules
+	}
+	return data, nil
+}
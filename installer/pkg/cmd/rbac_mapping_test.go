@@ -0,0 +1,174 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestRBACMapping tests that the rules of an RBAC mapping file bind their
+// groups to the catalog roles, in the whole cluster or per namespace.
+func TestRBACMapping(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbac-mapping")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "mapping.yaml")
+	if err := ioutil.WriteFile(file, []byte(`
+rules:
+- name: platform
+  groups: [platform-admins]
+  role: admin
+- name: payments
+  groups: [payments-devs, payments-ci]
+  role: edit
+  namespaces: [payments, payments-staging]
+`), 0644); err != nil {
+		t.Fatalf("Unexpected error writing mapping: %v", err)
+	}
+
+	ic := validInstallConfig()
+	ic.RBACMappingFile = file
+	if err := ic.Validate(); err != nil {
+		t.Fatalf("Unexpected error validating config: %v", err)
+	}
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "catalog-rbac.yaml"))
+	if err != nil {
+		t.Fatalf("Unexpected error reading catalog-rbac.yaml: %v", err)
+	}
+	objs, err := manifest.Parse("catalog-rbac.yaml", b)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing catalog-rbac.yaml: %v\n%s", err, b)
+	}
+
+	var names []string
+	bindings := make(map[string]interface{})
+	for _, o := range objs {
+		names = append(names, o.Kind+"/"+o.Namespace+"/"+o.Name)
+		var rb struct {
+			Metadata struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			RoleRef struct {
+				Name string `json:"name"`
+			} `json:"roleRef"`
+			Subjects []struct {
+				Name string `json:"name"`
+			} `json:"subjects"`
+		}
+		if err := json.Unmarshal(o.JSON, &rb); err != nil {
+			t.Fatalf("Unexpected error unmarshalling %s: %v", o.Name, err)
+		}
+		if got := rb.Metadata.Labels[installationLabel]; got != ic.Namespace {
+			t.Fatalf("Installation label of %s does not match: got %q; want %q", o.Name, got, ic.Namespace)
+		}
+		if rb.RoleRef.Name != "" {
+			var groups []string
+			for _, s := range rb.Subjects {
+				groups = append(groups, s.Name)
+			}
+			bindings[o.Namespace+"/"+o.Name] = rb.RoleRef.Name + " " + strings.Join(groups, ",")
+		}
+	}
+	wantNames := []string{
+		"ClusterRole//servicecatalog.k8s.io:admin",
+		"ClusterRole//servicecatalog.k8s.io:edit",
+		"ClusterRole//servicecatalog.k8s.io:view",
+		"ClusterRoleBinding//servicecatalog.k8s.io:mapping:platform",
+		"RoleBinding/payments/servicecatalog.k8s.io:mapping:payments",
+		"RoleBinding/payments-staging/servicecatalog.k8s.io:mapping:payments",
+	}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("Objects do not match:\ngot  %v\nwant %v", names, wantNames)
+	}
+	wantBindings := map[string]interface{}{
+		"/servicecatalog.k8s.io:mapping:platform":                 "servicecatalog.k8s.io:admin platform-admins",
+		"payments/servicecatalog.k8s.io:mapping:payments":         "servicecatalog.k8s.io:edit payments-devs,payments-ci",
+		"payments-staging/servicecatalog.k8s.io:mapping:payments": "servicecatalog.k8s.io:edit payments-devs,payments-ci",
+	}
+	if !reflect.DeepEqual(bindings, wantBindings) {
+		t.Fatalf("Bindings do not match:\ngot  %v\nwant %v", bindings, wantBindings)
+	}
+}
+
+// TestRBACMappingValidate tests that invalid rules are rejected.
+func TestRBACMappingValidate(t *testing.T) {
+	groups := []string{"devs"}
+	for _, c := range []struct {
+		mapping rbacMapping
+		want    string
+	}{
+		{rbacMapping{}, "no rules"},
+		{rbacMapping{Rules: []rbacRule{{Name: "Devs", Groups: groups, Role: "edit"}}}, "DNS label"},
+		{rbacMapping{Rules: []rbacRule{{Name: "devs", Groups: groups, Role: "owner"}}}, "must be one of admin, edit, view"},
+		{rbacMapping{Rules: []rbacRule{{Name: "devs", Role: "edit"}}}, "groups is required"},
+		{rbacMapping{Rules: []rbacRule{{Name: "devs", Groups: groups, Role: "edit", Namespaces: []string{"Dev"}}}}, "namespace \"Dev\""},
+		{rbacMapping{Rules: []rbacRule{
+			{Name: "devs", Groups: groups, Role: "edit"},
+			{Name: "devs", Groups: groups, Role: "view"},
+		}}, "defined twice"},
+	} {
+		err := c.mapping.validate()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("Error for %+v does not match: got %v; want %q", c.mapping, err, c.want)
+		}
+	}
+}
+
+// TestDeleteInstallationObjects tests that uninstall deletes the labeled
+// bindings before the labeled roles, and keeps the ClusterRoles other
+// bindings still reference.
+func TestDeleteInstallationObjects(t *testing.T) {
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch {
+		case args[0] == "get" && args[1] == "clusterroles":
+			return execx.Response{Stdout: "servicecatalog.k8s.io:admin servicecatalog.k8s.io:edit servicecatalog.k8s.io:view"}
+		case args[0] == "get" && args[1] == "rolebindings,clusterrolebindings":
+			return execx.Response{Stdout: "ClusterRole servicecatalog.k8s.io:edit RoleBinding/servicecatalog.k8s.io:edit\n" +
+				"Role pod-reader RoleBinding/read-pods\n"}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	if err := deleteInstallationObjects("catalog"); err != nil {
+		t.Fatalf("Unexpected error deleting the installation objects: %v", err)
+	}
+
+	want := []string{
+		"delete rolebindings,clusterrolebindings,roles --all-namespaces -l servicecatalog.k8s.io/installation=catalog --ignore-not-found",
+		"label clusterrole servicecatalog.k8s.io:edit servicecatalog.k8s.io/installation-",
+		"delete clusterroles servicecatalog.k8s.io:admin servicecatalog.k8s.io:view --ignore-not-found",
+	}
+	if got := kubectlCalls(s, "delete", "label"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Calls do not match:\ngot  %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// controllerManagerConfigMap holds the tunable settings of the
+	// controller manager.
+	controllerManagerConfigMap = "controller-manager-config"
+
+	// reconfiguredAtAnnotation is set on the pod template of the controller
+	// manager to roll it out after its settings changed.
+	reconfiguredAtAnnotation = "servicecatalog.k8s.io/reconfigured-at"
+)
+
+// reconfigureConfig contains the reconfigure configuration.
+type reconfigureConfig struct {
+	// Namespace Service Catalog is installed in.
+	Namespace string
+
+	// Timeout is how long to wait for the controller manager to roll out.
+	Timeout time.Duration
+
+	// the settings, only those set with a flag are changed
+	BrokerRelistInterval time.Duration
+	OSBAPITimeout        time.Duration
+	ResyncInterval       time.Duration
+	PollingMaxBackoff    time.Duration
+	RetryDuration        time.Duration
+	LogLevel             int
+}
+
+func NewReconfigureCmd() *cobra.Command {
+	rc := &reconfigureConfig{}
+	c := &cobra.Command{
+		Use:   "reconfigure",
+		Short: "changes the settings of the controller manager",
+		Long: `changes the settings of the controller manager set with a flag in
+the ConfigMap ` + controllerManagerConfigMap + ` and restarts the controller manager
+with a rolling update, without regenerating the other objects.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			updates, err := reconfigureUpdates(rc, cmd.Flags())
+			if err != nil {
+				return err
+			}
+			if err := reconfigureControllerManager(rc, updates); err != nil {
+				messages.Println(messages.ReconfigureFailed)
+				return err
+			}
+			messages.Println(messages.Reconfigured)
+			return nil
+		},
+	}
+	addReconfigureFlags(c.Flags(), rc)
+	return c
+}
+
+func addReconfigureFlags(flags *pflag.FlagSet, rc *reconfigureConfig) {
+	flags.StringVar(&rc.Namespace, "namespace", "service-catalog", "Namespace Service Catalog is installed in")
+	flags.DurationVar(&rc.Timeout, "timeout", 5*time.Minute, "How long to wait for the controller manager to restart")
+	flags.DurationVar(&rc.BrokerRelistInterval, "broker-relist-interval", defaultBrokerRelistInterval, "How often the controller manager fetches the catalogs of the brokers")
+	flags.DurationVar(&rc.OSBAPITimeout, "osb-api-timeout", defaultOSBAPITimeout, "Timeout of the controller manager requests to the brokers")
+	flags.DurationVar(&rc.ResyncInterval, "resync-interval", defaultResyncInterval, "How often the controller manager reconciles all the catalog resources")
+	flags.DurationVar(&rc.PollingMaxBackoff, "operation-polling-max-backoff", defaultOperationPollingMaxBackoff, "Maximum interval between the polls of asynchronous broker operations, e.g. provisioning")
+	flags.DurationVar(&rc.RetryDuration, "reconciliation-retry-duration", defaultReconciliationRetryDuration, "How long the controller manager retries operations, including polling them and the orphan mitigation of failed provisions, before giving up")
+	flags.IntVar(&rc.LogLevel, "log-level", defaultControllerManagerLogLevel, "Log verbosity of the controller manager")
+}
+
+// reconfigureUpdates returns the ConfigMap keys and values of the settings
+// of rc set with a flag in flags.
+func reconfigureUpdates(rc *reconfigureConfig, flags *pflag.FlagSet) (map[string]string, error) {
+	updates := map[string]string{}
+	for _, d := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"broker-relist-interval", rc.BrokerRelistInterval},
+		{"osb-api-timeout", rc.OSBAPITimeout},
+		{"resync-interval", rc.ResyncInterval},
+		{"operation-polling-max-backoff", rc.PollingMaxBackoff},
+		{"reconciliation-retry-duration", rc.RetryDuration},
+	} {
+		if !flags.Changed(d.key) {
+			continue
+		}
+		if d.value <= 0 {
+			return nil, fmt.Errorf("--%s must be positive, got %v", d.key, d.value)
+		}
+		updates[d.key] = d.value.String()
+	}
+	if flags.Changed("log-level") {
+		if rc.LogLevel < 0 {
+			return nil, fmt.Errorf("--log-level must not be negative, got %d", rc.LogLevel)
+		}
+		updates["log-level"] = strconv.Itoa(rc.LogLevel)
+	}
+
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("nothing to reconfigure, set at least one of --broker-relist-interval, --osb-api-timeout, --resync-interval, --operation-polling-max-backoff, --reconciliation-retry-duration or --log-level")
+	}
+	return updates, nil
+}
+
+// configChanges returns the updates that change current, and a description
+// of each change sorted by key.
+func configChanges(current, updates map[string]string) (map[string]string, []string) {
+	changed := map[string]string{}
+	var desc []string
+	for k, v := range updates {
+		if old, ok := current[k]; ok && old == v {
+			continue
+		}
+		changed[k] = v
+		desc = append(desc, fmt.Sprintf("%s: %s -> %s", k, current[k], v))
+	}
+	sort.Strings(desc)
+	return changed, desc
+}
+
+func reconfigureControllerManager(rc *reconfigureConfig, updates map[string]string) error {
+	output, err := kubectlCommand("get", "configmap", controllerManagerConfigMap,
+		"--namespace", rc.Namespace, "-o", "json").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "NotFound") {
+			return fmt.Errorf("ConfigMap %s/%s not found, Service Catalog was installed by an older sc, run sc install to create it",
+				rc.Namespace, controllerManagerConfigMap)
+		}
+		return fmt.Errorf("error getting ConfigMap %s/%s: %s", rc.Namespace, controllerManagerConfigMap, strings.TrimSpace(string(output)))
+	}
+	var cm struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(output, &cm); err != nil {
+		return fmt.Errorf("error unmarshalling ConfigMap %s/%s: %v", rc.Namespace, controllerManagerConfigMap, err)
+	}
+
+	changed, desc := configChanges(cm.Data, updates)
+	if len(changed) == 0 {
+		fmt.Println("the controller manager already has these settings")
+		return nil
+	}
+	for _, d := range desc {
+		fmt.Println(d)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"data": changed})
+	if err != nil {
+		return err
+	}
+	output, err = kubectlCommand("patch", "configmap", controllerManagerConfigMap,
+		"--namespace", rc.Namespace, "--type", "merge", "-p", string(patch)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error patching ConfigMap %s/%s: %s", rc.Namespace, controllerManagerConfigMap, strings.TrimSpace(string(output)))
+	}
+
+	// The controller manager reads its settings from its environment at
+	// startup.
+	if err := restartDeployment(rc.Namespace, "controller-manager"); err != nil {
+		return err
+	}
+
+	output, err = kubectlCommand("rollout", "status", "deployment/controller-manager",
+		"--namespace", rc.Namespace, "--timeout="+rc.Timeout.String()).CombinedOutput()
+	if err != nil {
+		return messages.Errorf(messages.NotReady, "deployment controller-manager", rc.Timeout, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// restartDeployment rolls out the pods of deployment name in namespace ns
+// again by changing an annotation of its pod template.
+func restartDeployment(ns, name string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{reconfiguredAtAnnotation: time.Now().UTC().Format(time.RFC3339)},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	output, err := kubectlCommand("patch", "deployment", name,
+		"--namespace", ns, "--type", "merge", "-p", string(patch)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error restarting deployment %s/%s: %s", ns, name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
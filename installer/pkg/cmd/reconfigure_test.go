@@ -0,0 +1,72 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// TestReconfigureUpdates tests that only the settings set with a flag are
+// updated and that invalid settings are rejected.
+func TestReconfigureUpdates(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want map[string]string
+	}{
+		{[]string{"--osb-api-timeout=2m", "--log-level=4"}, map[string]string{"osb-api-timeout": "2m0s", "log-level": "4"}},
+		{[]string{"--operation-polling-max-backoff=2m", "--reconciliation-retry-duration=48h"},
+			map[string]string{"operation-polling-max-backoff": "2m0s", "reconciliation-retry-duration": "48h0m0s"}},
+		{[]string{"--resync-interval=0s"}, nil},
+		{[]string{"--namespace=catalog"}, nil},
+	} {
+		rc := &reconfigureConfig{}
+		flags := pflag.NewFlagSet("reconfigure", pflag.ContinueOnError)
+		addReconfigureFlags(flags, rc)
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatalf("Unexpected error parsing %v: %v", tc.args, err)
+		}
+		got, err := reconfigureUpdates(rc, flags)
+		if tc.want == nil {
+			if err == nil {
+				t.Fatalf("Expected an error reconfiguring with %v", tc.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error reconfiguring with %v: %v", tc.args, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("Updates of %v do not match: got %v; want %v", tc.args, got, tc.want)
+		}
+	}
+}
+
+// TestConfigChanges tests that only the updates changing a setting are
+// applied and described.
+func TestConfigChanges(t *testing.T) {
+	current := map[string]string{"osb-api-timeout": "1m0s", "log-level": "10"}
+	changed, desc := configChanges(current, map[string]string{"osb-api-timeout": "1m0s", "log-level": "4", "resync-interval": "10m0s"})
+	if want := map[string]string{"log-level": "4", "resync-interval": "10m0s"}; !reflect.DeepEqual(changed, want) {
+		t.Fatalf("Changes do not match: got %v; want %v", changed, want)
+	}
+	if want := []string{"log-level: 10 -> 4", "resync-interval:  -> 10m0s"}; !reflect.DeepEqual(desc, want) {
+		t.Fatalf("Descriptions do not match: got %q; want %q", desc, want)
+	}
+}
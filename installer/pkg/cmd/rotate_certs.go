@@ -0,0 +1,265 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// apiServerCerts are the certificates the api server is trusted with, PEM
+// encoded.
+type apiServerCerts struct {
+	// Cert is the serving certificate of the api server.
+	Cert []byte
+
+	// CABundle is the caBundle of the APIService.
+	CABundle []byte
+
+	// StoredCA is the CA stored with --store-ca-key, nil if none is.
+	StoredCA []byte
+}
+
+// getAPIServerCerts reads the certificates of the api server installed in
+// namespace ns. It returns nil if the certificate secret or the APIService
+// does not exist.
+func getAPIServerCerts(ns string) (*apiServerCerts, error) {
+	data, found, err := getSecretData(ns, apiServerCertSecretName)
+	if err != nil || !found {
+		return nil, err
+	}
+	caBundle, found, err := getAPIServiceCABundle()
+	if err != nil || !found {
+		return nil, err
+	}
+	ca, _, err := getSecretData(ns, caSecretName)
+	if err != nil {
+		return nil, err
+	}
+	return &apiServerCerts{Cert: data["tls.crt"], CABundle: caBundle, StoredCA: ca["ca.crt"]}, nil
+}
+
+// getAPIServiceCABundle returns the caBundle of the APIService of Service
+// Catalog and whether the APIService exists.
+func getAPIServiceCABundle() ([]byte, bool, error) {
+	output, err := kubectlCommand("get", "apiservice", scAPIService,
+		"-o", "jsonpath={.spec.caBundle}").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "NotFound") {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("error getting APIService %s: %s", scAPIService, strings.TrimSpace(string(output)))
+	}
+	caBundle, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, false, fmt.Errorf("error decoding caBundle of APIService %s: %v", scAPIService, err)
+	}
+	return caBundle, true, nil
+}
+
+// caBundleMismatch returns why the caBundle of the APIService does not
+// verify the serving certificate, e.g. after the certificate secret was
+// replaced by hand, or "" if it does.
+func (c *apiServerCerts) caBundleMismatch() string {
+	block, _ := pem.Decode(c.Cert)
+	if block == nil {
+		return fmt.Sprintf("secret %s holds no PEM certificate", apiServerCertSecretName)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Sprintf("error parsing the certificate of secret %s: %v", apiServerCertSecretName, err)
+	}
+	if len(c.CABundle) == 0 {
+		return "the caBundle is empty"
+	}
+	if !signedBy(cert, c.CABundle, cert.NotBefore) {
+		return "the caBundle is not the CA that signed the api server certificate"
+	}
+	return ""
+}
+
+// checkCABundle returns a CABundleMismatch error if the caBundle of the
+// APIService does not verify the serving certificate of the api server in
+// namespace ns.
+func checkCABundle(ns string) error {
+	certs, err := getAPIServerCerts(ns)
+	if err != nil || certs == nil {
+		return err
+	}
+	if reason := certs.caBundleMismatch(); reason != "" {
+		return messages.Errorf(messages.CABundleMismatch, scAPIService, reason)
+	}
+	return nil
+}
+
+func NewRotateCertsCmd() *cobra.Command {
+	ic := &InstallConfig{
+		APIServerServiceName: "service-catalog-api",
+	}
+	c := &cobra.Command{
+		Use:   "rotate-certs",
+		Short: "issues a new api server certificate and caBundle",
+		Long: `issues a new serving certificate for the api server, signed by a new CA
+or, with --store-ca-key, by the CA stored in the cluster, stores it, adds
+its CA to the caBundle of the APIService and restarts the api server. Once
+the api server rolled out, the previous CAs are dropped from the caBundle.
+It fixes an APIService caBundle not matching the api server certificate,
+e.g. after the certificate was changed by hand, and certificates about to
+expire.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := rotateCerts(ic); err != nil {
+				messages.Println(messages.RotateCertsFailed)
+				return err
+			}
+			messages.Println(messages.CertsRotated)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&ic.Namespace, "namespace", "service-catalog", "Namespace Service Catalog is installed in")
+	c.Flags().StringVar(&ic.IPFamily, "ip-family", ipFamilyIPv4, "IP family of the cluster: ipv4, ipv6 (IPv6-only) or dual (dual-stack)")
+	c.Flags().StringVar(&ic.KeyAlgorithm, "key-algorithm", "rsa", "Algorithm of the generated private keys: rsa or ecdsa")
+	c.Flags().BoolVar(&ic.FIPS, "fips", false, "Restrict generated keys and certificates to FIPS-approved algorithms and sizes")
+	c.Flags().IntVar(&ic.KeySize, "key-size", 0, "Size in bits of generated RSA keys or curve size of ECDSA keys. Defaults to 2048 for rsa and 256 for ecdsa")
+	c.Flags().DurationVar(&ic.CertValidity, "cert-validity", defaultCertValidity, "Validity of the generated certificates")
+	c.Flags().BoolVar(&ic.StoreCAKey, "store-ca-key", false, "Sign with the CA stored in the cluster, storing a new one if there is none")
+	c.Flags().StringVar(&ic.KMSKey, "kms-key", "", "Cloud KMS key (projects/.../cryptoKeys/...) the stored CA private key is encrypted with. Requires --store-ca-key")
+	c.Flags().DurationVar(&ic.ReadyTimeout, "ready-timeout", defaultReadyTimeout, "How long to wait for the api server to roll out before dropping the previous CAs from the caBundle")
+	return c
+}
+
+func rotateCerts(ic *InstallConfig) (err error) {
+	if _, _, err := keySpec(ic); err != nil {
+		return err
+	}
+	if ic.CertValidity <= 0 {
+		return fmt.Errorf("--cert-validity must be positive, got %v", ic.CertValidity)
+	}
+	if ic.KMSKey != "" && !ic.StoreCAKey {
+		return fmt.Errorf("--kms-key requires --store-ca-key")
+	}
+	if ic.ReadyTimeout <= 0 {
+		return fmt.Errorf("--ready-timeout must be positive, got %v", ic.ReadyTimeout)
+	}
+	// The API of an installation with a broken caBundle is unavailable,
+	// the certificate secret tells whether it is installed.
+	_, found, err := getSecretData(ic.Namespace, apiServerCertSecretName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("Service Catalog is not installed in namespace %s, secret %s does not exist", ic.Namespace, apiServerCertSecretName)
+	}
+
+	// The workspace holds the new private keys.
+	ws, err := createWorkspace(ic.Namespace, "rotate-certs", workspace.Options{Exclusive: true, Sensitive: true})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+	dir := ws.Dir
+
+	ssl, err := generateSSLArtifacts(dir, ic)
+	if err != nil {
+		return fmt.Errorf("error generating SSL artifacts : %v", err)
+	}
+	data := map[string]interface{}{
+		"Namespace": ic.Namespace,
+		"CAKMSKey":  ic.KMSKey,
+	}
+	for key, file := range map[string]string{
+		"CAPublicKey":          ssl.CAFile,
+		"APIServicePublicKey":  ssl.APIServerCertFile,
+		"APIServicePrivateKey": ssl.APIServerPrivateKeyFile,
+	} {
+		if data[key], err = base64FileContent(file); err != nil {
+			return err
+		}
+	}
+	if ic.StoreCAKey && ssl.CAPrivateKeyFile != "" {
+		if data["CAPrivateKey"], err = base64FileContent(ssl.CAPrivateKeyFile); err != nil {
+			return err
+		}
+	}
+
+	manifests := filepath.Join(dir, "manifests")
+	if err := os.Mkdir(manifests, 0755); err != nil {
+		return err
+	}
+	for _, name := range []string{"tls-cert-secret", "ca-secret"} {
+		if err := generateFileFromTmpl(filepath.Join(manifests, name+".yaml"), "templates/sc/"+name+".yaml.tmpl", data); err != nil {
+			return fmt.Errorf("error generating %s: %v", name, err)
+		}
+	}
+	if err := deployConfig(manifests, applyOptions{}); err != nil {
+		return err
+	}
+
+	ca, err := ioutil.ReadFile(ssl.CAFile)
+	if err != nil {
+		return err
+	}
+	// The pods of the api server serve the previous certificate until they
+	// are replaced, the caBundle trusts both CAs meanwhile.
+	previous, _, err := getAPIServiceCABundle()
+	if err != nil {
+		return err
+	}
+	if err := setAPIServiceCABundle(rotationCABundle(ca, previous)); err != nil {
+		return err
+	}
+	fmt.Printf("added the new CA to the caBundle of APIService %s\n", scAPIService)
+	if err := restartDeployment(ic.Namespace, "apiserver"); err != nil {
+		return err
+	}
+	output, err := kubectlCommand("rollout", "status", "deployment/apiserver",
+		"--namespace", ic.Namespace, "--timeout="+ic.ReadyTimeout.String()).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("the caBundle still trusts the previous CAs, the api server did not roll out: %s", strings.TrimSpace(string(output)))
+	}
+	if err := setAPIServiceCABundle(ca); err != nil {
+		return err
+	}
+	fmt.Printf("dropped the previous CAs from the caBundle of APIService %s\n", scAPIService)
+	return nil
+}
+
+// rotationCABundle returns the caBundle trusted while the api server rolls
+// out: the new CA and the other CAs of the previous bundle.
+func rotationCABundle(ca, previous []byte) []byte {
+	bundle := append([]byte{}, ca...)
+	block, _ := pem.Decode(ca)
+	for rest := previous; ; {
+		var prev *pem.Block
+		if prev, rest = pem.Decode(rest); prev == nil {
+			return bundle
+		}
+		if prev.Type == "CERTIFICATE" && (block == nil || !bytes.Equal(prev.Bytes, block.Bytes)) {
+			bundle = append(bundle, pem.EncodeToMemory(prev)...)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestCABundleMismatch tests that a caBundle not holding the CA that signed
+// the api server certificate is reported, whether the certificate is valid
+// or not.
+func TestCABundleMismatch(t *testing.T) {
+	now := time.Now()
+	caPEM, _, ca, caKey := testCert(t, nil, now.Add(365*24*time.Hour), nil, nil)
+	otherCAPEM, _, _, _ := testCert(t, nil, now.Add(365*24*time.Hour), nil, nil)
+	crt, _, _, _ := testCert(t, []string{"host"}, now.Add(90*24*time.Hour), ca, caKey)
+	expired, _, _, _ := testCert(t, []string{"host"}, now.Add(-time.Minute), ca, caKey)
+
+	for _, tc := range []struct {
+		name        string
+		crt, bundle []byte
+		want        string
+	}{
+		{"match", crt, caPEM, ""},
+		{"match of an expired certificate", expired, caPEM, ""},
+		{"bundle with other CAs", crt, append(append([]byte{}, otherCAPEM...), caPEM...), ""},
+		{"other CA", crt, otherCAPEM, "not the CA that signed"},
+		{"empty bundle", crt, nil, "caBundle is empty"},
+		{"no certificate", []byte("garbage"), caPEM, "no PEM certificate"},
+	} {
+		got := (&apiServerCerts{Cert: tc.crt, CABundle: tc.bundle}).caBundleMismatch()
+		if (got == "") != (tc.want == "") || !strings.Contains(got, tc.want) {
+			t.Fatalf("%s: mismatch does not match: got %q; want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestRotateCerts tests that the caBundle trusts the previous and the new CA
+// while the api server rolls out, and only the new one afterwards.
+func TestRotateCerts(t *testing.T) {
+	defer tempWorkspaces(t)()
+	oldCA, _, _, _ := testCert(t, nil, time.Now().Add(365*24*time.Hour), nil, nil)
+
+	newCA, newKey, _, _ := testCert(t, nil, time.Now().Add(365*24*time.Hour), nil, nil)
+
+	var bundles [][]byte
+	rolledOut := false
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		if name == "cfssljson" {
+			// cfssljson -bare <prefix> writes the certificate and key.
+			for suffix, data := range map[string][]byte{".pem": newCA, "-key.pem": newKey} {
+				if err := ioutil.WriteFile(args[1]+suffix, data, 0600); err != nil {
+					t.Fatalf("Unexpected error writing %s: %v", args[1]+suffix, err)
+				}
+			}
+			return execx.Response{}
+		}
+		switch {
+		case args[0] == "api-versions":
+			return execx.Response{Stdout: "v1\n"}
+		case args[0] == "get" && args[1] == "secret" && args[2] == apiServerCertSecretName:
+			return execx.Response{Stdout: `{"data":{}}`}
+		case args[0] == "get" && args[1] == "secret":
+			return execx.Response{Stderr: "NotFound", ExitCode: 1}
+		case args[0] == "get" && args[1] == "apiservice":
+			return execx.Response{Stdout: base64.StdEncoding.EncodeToString(oldCA)}
+		case args[0] == "patch" && args[1] == "apiservice":
+			var patch struct {
+				Spec struct {
+					CABundle []byte `json:"caBundle"`
+				} `json:"spec"`
+			}
+			if err := json.Unmarshal([]byte(args[len(args)-1]), &patch); err != nil {
+				t.Fatalf("Unexpected caBundle patch %q: %v", args[len(args)-1], err)
+			}
+			if len(bundles) == 1 && !rolledOut {
+				t.Fatalf("Expected the previous CA to be dropped after the rollout")
+			}
+			bundles = append(bundles, patch.Spec.CABundle)
+		case args[0] == "rollout":
+			rolledOut = true
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	ic := &InstallConfig{Namespace: "catalog", APIServerServiceName: "service-catalog-api", KeyAlgorithm: "rsa", CertValidity: defaultCertValidity, ReadyTimeout: time.Minute}
+	if err := rotateCerts(ic); err != nil {
+		t.Fatalf("Unexpected error rotating the certificates: %v", err)
+	}
+	if len(bundles) != 2 {
+		t.Fatalf("Expected the caBundle to be set twice, got %d times", len(bundles))
+	}
+	if !bytes.Contains(bundles[0], oldCA) || bytes.Equal(bundles[0], oldCA) {
+		t.Fatalf("Expected the caBundle to trust the previous and the new CA during the rollout, got:\n%s", bundles[0])
+	}
+	if !bytes.Equal(bundles[1], newCA) {
+		t.Fatalf("Expected the caBundle to only trust the new CA after the rollout, got:\n%s", bundles[1])
+	}
+	if calls := kubectlCalls(s, "rollout"); len(calls) != 1 || calls[0] != "rollout status deployment/apiserver --namespace catalog --timeout=1m0s" {
+		t.Fatalf("Unexpected rollout calls: %q", calls)
+	}
+}
+
+// TestRotationCABundle tests that the new CA is not repeated when the
+// previous bundle already holds it.
+func TestRotationCABundle(t *testing.T) {
+	now := time.Now()
+	ca, _, _, _ := testCert(t, nil, now.Add(time.Hour), nil, nil)
+	other, _, _, _ := testCert(t, nil, now.Add(time.Hour), nil, nil)
+
+	bundle := rotationCABundle(ca, append(append([]byte{}, other...), ca...))
+	var n int
+	for rest := bundle; ; n++ {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+	}
+	if n != 2 || !bytes.HasPrefix(bundle, ca) || !bytes.Contains(bundle, other) {
+		t.Fatalf("Expected the new CA followed by the other CA, got %d certificates:\n%s", n, bundle)
+	}
+}
@@ -0,0 +1,499 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/registry"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/version"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// SBOM formats.
+const (
+	sbomFormatSPDX      = "spdx"
+	sbomFormatCycloneDX = "cyclonedx"
+)
+
+// installerModule is the import path of the installer, the module of its
+// build info in module builds.
+const installerModule = "github.com/GoogleCloudPlatform/k8s-service-catalog/installer"
+
+// sbomConfig contains the generate sbom configuration.
+type sbomConfig struct {
+	// Format is the format of the SBOM: spdx or cyclonedx.
+	Format string
+
+	// ResolveDigests reads the digests of the images from their
+	// registries.
+	ResolveDigests bool
+}
+
+// sbomComponent is a component of a release: an image, the installer or a
+// module it is built from.
+type sbomComponent struct {
+	Name    string
+	Version string
+	PURL    string
+
+	// Digest is the digest of an image, e.g. sha256:..., empty if it is
+	// unknown.
+	Digest string
+}
+
+// sbom is the bill of materials of a release of Service Catalog.
+type sbom struct {
+	// Release is the Service Catalog release, installed in Namespace.
+	Release   sbomComponent
+	Namespace string
+
+	// Images are the images the pods of the release run.
+	Images []sbomComponent
+
+	// Installer is the installer rendering the release, built from
+	// Modules, with the build settings of Provenance, e.g. its VCS
+	// revision.
+	Installer  sbomComponent
+	Modules    []sbomComponent
+	Provenance [][2]string
+
+	Created time.Time
+	Serial  string
+}
+
+// imageDigest returns the digest of an image. Tests replace it.
+var imageDigest = func(image string) (string, error) {
+	client := &registry.Client{HTTP: &http.Client{Timeout: 30 * time.Second}}
+	return client.Digest(image)
+}
+
+// readBuildInfo returns the build info of the installer. Tests replace it.
+var readBuildInfo = debug.ReadBuildInfo
+
+// buildRevision is the VCS revision the installer is built from, set by
+// the Makefile and cloudbuild.yaml with
+// -ldflags "-X github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/cmd.buildRevision=<revision>",
+// for the GOPATH builds without VCS build settings.
+var buildRevision string
+
+func newGenerateSBOMCmd() *cobra.Command {
+	ic := newInstallConfig()
+	sc := &sbomConfig{}
+	c := &cobra.Command{
+		Use:   "sbom",
+		Short: "generates the SBOM of the release an install deploys",
+		Long: `generates the software bill of materials of the release an install with
+the same flags deploys, as SPDX 2.3 or CycloneDX 1.5 JSON: the images its
+pods run, with their digests read from the registries, and the installer
+rendering it with the Go modules it is built from and its VCS revision.
+Nothing is created in the cluster.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyProfile(ic, cmd.Flags()); err != nil {
+				return err
+			}
+			if err := applyNameAffixes(ic); err != nil {
+				return err
+			}
+			return generateSBOM(os.Stdout, ic, sc)
+		},
+	}
+	addInstallFlags(c, ic)
+	c.Flags().StringVar(&sc.Format, "format", sbomFormatSPDX, "Format of the SBOM: spdx or cyclonedx")
+	c.Flags().BoolVar(&sc.ResolveDigests, "resolve-digests", true, "Read the digests of the images from their registries")
+	return c
+}
+
+func generateSBOM(w io.Writer, ic *InstallConfig, sc *sbomConfig) (err error) {
+	if sc.Format != sbomFormatSPDX && sc.Format != sbomFormatCycloneDX {
+		return fmt.Errorf("--format must be %s or %s, got %q", sbomFormatSPDX, sbomFormatCycloneDX, sc.Format)
+	}
+	if err := ic.Validate(); err != nil {
+		return err
+	}
+	detectCapabilities(ic)
+
+	ws, err := createWorkspace(ic.Namespace, "sbom", workspace.Options{Cleanup: workspace.Always})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+	if err := renderServiceCatalog(ws.Dir, ic, nil); err != nil {
+		return fmt.Errorf("error generating YAML files: %v", err)
+	}
+	manifests, err := readManifests(ws.Dir)
+	if err != nil {
+		return err
+	}
+	images, err := manifestImages(manifests)
+	if err != nil {
+		return err
+	}
+	// The pods of the etcd cluster are created by the operator, their
+	// image is not in the manifests.
+	images = uniqueSorted(append(images, catalogImages(ic)...))
+
+	b, err := newSBOM(ic, images, sc.ResolveDigests)
+	if err != nil {
+		return err
+	}
+	if sc.Format == sbomFormatCycloneDX {
+		return writeCycloneDX(w, b)
+	}
+	return writeSPDX(w, b)
+}
+
+// newSBOM returns the SBOM of the release of ic running images.
+func newSBOM(ic *InstallConfig, images []string, resolveDigests bool) (*sbom, error) {
+	serial, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	b := &sbom{
+		Release:   sbomComponent{Name: "service-catalog", Version: catalogVersion(ic)},
+		Namespace: ic.Namespace,
+		Installer: sbomComponent{Name: "sc", Version: version.Version()},
+		Created:   time.Now().UTC(),
+		Serial:    serial,
+	}
+	for _, image := range images {
+		digest := ""
+		if resolveDigests {
+			if digest, err = imageDigest(image); err != nil {
+				// Warn on stderr, the SBOM is printed to stdout.
+				fmt.Fprintf(os.Stderr, "WARNING: no digest for image %s: %v\n", image, err)
+				digest = ""
+			}
+		}
+		c, err := imageComponent(image, digest)
+		if err != nil {
+			return nil, err
+		}
+		b.Images = append(b.Images, c)
+	}
+
+	module := installerModule
+	info, ok := readBuildInfo()
+	if ok && len(info.Deps) > 0 {
+		if info.Main.Path != "" {
+			module = info.Main.Path
+		}
+		for _, m := range info.Deps {
+			if m.Replace != nil {
+				m = m.Replace
+			}
+			b.Modules = append(b.Modules, goModule(m.Path, m.Version))
+		}
+	} else {
+		// GOPATH builds have no modules, the vendored ones are locked.
+		if b.Modules, err = lockedModules(); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(b.Modules, func(i, j int) bool { return b.Modules[i].Name < b.Modules[j].Name })
+	if ok {
+		if info.GoVersion != "" {
+			b.Provenance = append(b.Provenance, [2]string{"go.version", info.GoVersion})
+		}
+		for _, s := range info.Settings {
+			if strings.HasPrefix(s.Key, "vcs") {
+				b.Provenance = append(b.Provenance, [2]string{s.Key, s.Value})
+			}
+		}
+	}
+	if buildRevision != "" && !hasProvenance(b, "vcs.revision") {
+		b.Provenance = append(b.Provenance, [2]string{"vcs.revision", buildRevision})
+	}
+	b.Installer.PURL = "pkg:golang/" + module + "@v" + b.Installer.Version
+	return b, nil
+}
+
+// goModule returns the component of the Go module path at version.
+func goModule(path, version string) sbomComponent {
+	return sbomComponent{Name: path, Version: version, PURL: "pkg:golang/" + path + "@" + version}
+}
+
+// lockedModules returns the modules of the Gopkg.lock the vendored
+// packages of the installer were resolved with, embedded at build time, at
+// their version or, for branches, revision.
+func lockedModules() ([]sbomComponent, error) {
+	lock, err := Asset("Gopkg.lock")
+	if err != nil {
+		return nil, err
+	}
+	var modules []sbomComponent
+	var name, version, revision string
+	flush := func() {
+		if name != "" {
+			if version == "" {
+				version = revision
+			}
+			modules = append(modules, goModule(name, version))
+		}
+		name, version, revision = "", "", ""
+	}
+	for _, line := range strings.Split(string(lock), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			flush()
+			if line == "[solve-meta]" {
+				break
+			}
+			continue
+		}
+		kv := strings.SplitN(line, " = ", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		value := strings.Trim(kv[1], `"`)
+		switch kv[0] {
+		case "name":
+			name = value
+		case "version":
+			version = value
+		case "revision":
+			revision = value
+		}
+	}
+	flush()
+	return modules, nil
+}
+
+// hasProvenance returns true if b has the build setting key.
+func hasProvenance(b *sbom, key string) bool {
+	for _, p := range b.Provenance {
+		if p[0] == key {
+			return true
+		}
+	}
+	return false
+}
+
+// imageComponent returns the component of image, whose digest is digest if
+// known, with its OCI package URL, e.g.
+// pkg:oci/etcd@sha256%3A...?repository_url=quay.io/coreos/etcd&tag=v3.1.8.
+func imageComponent(image, digest string) (sbomComponent, error) {
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return sbomComponent{}, err
+	}
+	c := sbomComponent{Name: ref.Registry + "/" + ref.Repository, Version: ref.Reference, Digest: digest}
+	q := url.Values{"repository_url": {c.Name}}
+	if strings.HasPrefix(ref.Reference, "sha256:") {
+		c.Digest = ref.Reference
+	} else {
+		q.Set("tag", ref.Reference)
+	}
+	c.PURL = "pkg:oci/" + path.Base(ref.Repository)
+	if c.Digest != "" {
+		c.PURL += "@" + strings.Replace(c.Digest, ":", "%3A", 1)
+	}
+	// The repository URL is kept readable, its slashes unescaped.
+	c.PURL += "?" + strings.Replace(q.Encode(), "%2F", "/", -1)
+	return c, nil
+}
+
+// newUUID returns a random UUID, e.g. 7c3a1b52-....
+func newUUID() (string, error) {
+	u := make([]byte, 16)
+	if _, err := rand.Read(u); err != nil {
+		return "", err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
+
+// writeJSON writes v to w as indented JSON, leaving the ampersands of the
+// package URLs unescaped.
+func writeJSON(w io.Writer, v interface{}) error {
+	e := json.NewEncoder(w)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
+
+// writeSPDX writes b to w as an SPDX 2.3 JSON document describing the
+// release, which contains the images, and the installer, which generates
+// the release and depends on its modules.
+func writeSPDX(w io.Writer, b *sbom) error {
+	type ref struct {
+		Category string `json:"referenceCategory"`
+		Type     string `json:"referenceType"`
+		Locator  string `json:"referenceLocator"`
+	}
+	type checksum struct {
+		Algorithm string `json:"algorithm"`
+		Value     string `json:"checksumValue"`
+	}
+	type pkg struct {
+		ID               string     `json:"SPDXID"`
+		Name             string     `json:"name"`
+		Version          string     `json:"versionInfo,omitempty"`
+		DownloadLocation string     `json:"downloadLocation"`
+		FilesAnalyzed    bool       `json:"filesAnalyzed"`
+		Purpose          string     `json:"primaryPackagePurpose,omitempty"`
+		Checksums        []checksum `json:"checksums,omitempty"`
+		ExternalRefs     []ref      `json:"externalRefs,omitempty"`
+		SourceInfo       string     `json:"sourceInfo,omitempty"`
+		Comment          string     `json:"comment,omitempty"`
+	}
+	type relationship struct {
+		Element string `json:"spdxElementId"`
+		Type    string `json:"relationshipType"`
+		Related string `json:"relatedSpdxElement"`
+	}
+	newPkg := func(id, purpose string, c sbomComponent) pkg {
+		p := pkg{ID: id, Name: c.Name, Version: c.Version, DownloadLocation: "NOASSERTION", Purpose: purpose}
+		if c.PURL != "" {
+			p.ExternalRefs = []ref{{"PACKAGE-MANAGER", "purl", c.PURL}}
+		}
+		if strings.HasPrefix(c.Digest, "sha256:") {
+			p.Checksums = []checksum{{"SHA256", strings.TrimPrefix(c.Digest, "sha256:")}}
+		}
+		return p
+	}
+
+	release := newPkg("SPDXRef-Release", "APPLICATION", b.Release)
+	release.Comment = "Service Catalog installed in namespace " + b.Namespace
+	installer := newPkg("SPDXRef-Installer", "APPLICATION", b.Installer)
+	var provenance []string
+	for _, p := range b.Provenance {
+		provenance = append(provenance, p[0]+"="+p[1])
+	}
+	if len(provenance) > 0 {
+		installer.SourceInfo = "built with " + strings.Join(provenance, ", ")
+	}
+	packages := []pkg{release, installer}
+	relationships := []relationship{
+		{"SPDXRef-DOCUMENT", "DESCRIBES", release.ID},
+		{"SPDXRef-DOCUMENT", "DESCRIBES", installer.ID},
+		{installer.ID, "GENERATES", release.ID},
+	}
+	for i, c := range b.Images {
+		p := newPkg(fmt.Sprintf("SPDXRef-Image-%d", i), "CONTAINER", c)
+		packages = append(packages, p)
+		relationships = append(relationships, relationship{release.ID, "CONTAINS", p.ID})
+	}
+	for i, c := range b.Modules {
+		p := newPkg(fmt.Sprintf("SPDXRef-Module-%d", i), "LIBRARY", c)
+		packages = append(packages, p)
+		relationships = append(relationships, relationship{installer.ID, "DEPENDS_ON", p.ID})
+	}
+
+	name := b.Release.Name + "-" + b.Release.Version
+	return writeJSON(w, map[string]interface{}{
+		"spdxVersion":       "SPDX-2.3",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            "SPDXRef-DOCUMENT",
+		"name":              name,
+		"documentNamespace": "https://github.com/GoogleCloudPlatform/k8s-service-catalog/spdx/" + name + "-" + b.Serial,
+		"creationInfo": map[string]interface{}{
+			"created":  b.Created.Format(time.RFC3339),
+			"creators": []string{"Tool: sc-" + b.Installer.Version},
+		},
+		"packages":      packages,
+		"relationships": relationships,
+	})
+}
+
+// writeCycloneDX writes b to w as a CycloneDX 1.5 JSON BOM of the release,
+// which depends on the images, and of the installer, which depends on its
+// modules.
+func writeCycloneDX(w io.Writer, b *sbom) error {
+	type hash struct {
+		Alg     string `json:"alg"`
+		Content string `json:"content"`
+	}
+	type property struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	type component struct {
+		Type       string     `json:"type"`
+		Ref        string     `json:"bom-ref"`
+		Name       string     `json:"name"`
+		Version    string     `json:"version,omitempty"`
+		PURL       string     `json:"purl,omitempty"`
+		Hashes     []hash     `json:"hashes,omitempty"`
+		Properties []property `json:"properties,omitempty"`
+	}
+	type dependency struct {
+		Ref       string   `json:"ref"`
+		DependsOn []string `json:"dependsOn"`
+	}
+	newComponent := func(typ string, c sbomComponent) component {
+		cc := component{Type: typ, Ref: c.PURL, Name: c.Name, Version: c.Version, PURL: c.PURL}
+		if strings.HasPrefix(c.Digest, "sha256:") {
+			cc.Hashes = []hash{{"SHA-256", strings.TrimPrefix(c.Digest, "sha256:")}}
+		}
+		return cc
+	}
+
+	release := component{
+		Type:       "application",
+		Ref:        b.Release.Name,
+		Name:       b.Release.Name,
+		Version:    b.Release.Version,
+		Properties: []property{{"sc:namespace", b.Namespace}},
+	}
+	installer := newComponent("application", b.Installer)
+	for _, p := range b.Provenance {
+		installer.Properties = append(installer.Properties, property{p[0], p[1]})
+	}
+	components := []component{installer}
+	releaseDeps := dependency{Ref: release.Ref, DependsOn: []string{}}
+	installerDeps := dependency{Ref: installer.Ref, DependsOn: []string{}}
+	for _, c := range b.Images {
+		cc := newComponent("container", c)
+		components = append(components, cc)
+		releaseDeps.DependsOn = append(releaseDeps.DependsOn, cc.Ref)
+	}
+	for _, c := range b.Modules {
+		cc := newComponent("library", c)
+		components = append(components, cc)
+		installerDeps.DependsOn = append(installerDeps.DependsOn, cc.Ref)
+	}
+
+	return writeJSON(w, map[string]interface{}{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  "1.5",
+		"serialNumber": "urn:uuid:" + b.Serial,
+		"version":      1,
+		"metadata": map[string]interface{}{
+			"timestamp": b.Created.Format(time.RFC3339),
+			"tools": map[string]interface{}{
+				"components": []component{{Type: "application", Ref: "tool", Name: "sc", Version: b.Installer.Version}},
+			},
+			"component": release,
+		},
+		"components":   components,
+		"dependencies": []dependency{releaseDeps, installerDeps},
+	})
+}
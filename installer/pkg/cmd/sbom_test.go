@@ -0,0 +1,230 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestImageComponent tests the names, versions and package URLs of images
+// referenced by tag, resolved or not, and by digest.
+func TestImageComponent(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	for _, tc := range []struct {
+		image, digest string
+		want          sbomComponent
+	}{
+		{"quay.io/coreos/etcd:v3.1.8", "", sbomComponent{
+			Name:    "quay.io/coreos/etcd",
+			Version: "v3.1.8",
+			PURL:    "pkg:oci/etcd?repository_url=quay.io/coreos/etcd&tag=v3.1.8",
+		}},
+		{"quay.io/coreos/etcd:v3.1.8", digest, sbomComponent{
+			Name:    "quay.io/coreos/etcd",
+			Version: "v3.1.8",
+			PURL:    "pkg:oci/etcd@sha256%3A" + digest[7:] + "?repository_url=quay.io/coreos/etcd&tag=v3.1.8",
+			Digest:  digest,
+		}},
+		{"busybox@" + digest, "", sbomComponent{
+			Name:    "registry-1.docker.io/library/busybox",
+			Version: digest,
+			PURL:    "pkg:oci/busybox@sha256%3A" + digest[7:] + "?repository_url=registry-1.docker.io/library/busybox",
+			Digest:  digest,
+		}},
+	} {
+		got, err := imageComponent(tc.image, tc.digest)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", tc.image, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("Component of %s does not match: got %+v; want %+v", tc.image, got, tc.want)
+		}
+	}
+}
+
+// TestGenerateSBOM tests that the SBOM of a release lists the images of its
+// pods with the digests their registries know, and the modules and VCS
+// revision of the installer, in both formats.
+func TestGenerateSBOM(t *testing.T) {
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		return execx.Response{ExitCode: 1}
+	})
+	defer restore()
+	oldDigest, oldBuildInfo := imageDigest, readBuildInfo
+	defer func() { imageDigest, readBuildInfo = oldDigest, oldBuildInfo }()
+	imageDigest = func(image string) (string, error) {
+		if strings.HasPrefix(image, "quay.io/coreos/etcd-operator:") {
+			return "", fmt.Errorf("unauthorized")
+		}
+		return "sha256:" + strings.Repeat("b", 64), nil
+	}
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.22.1",
+			Main:      debug.Module{Path: installerModule},
+			Deps: []*debug.Module{
+				{Path: "github.com/spf13/cobra", Version: "v1.8.0"},
+				{Path: "github.com/ghodss/yaml", Version: "v1.0.0", Replace: &debug.Module{Path: "sigs.k8s.io/yaml", Version: "v1.4.0"}},
+			},
+			Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "abc123"}, {Key: "CGO_ENABLED", Value: "0"}},
+		}, true
+	}
+
+	ic := validInstallConfig()
+	var out bytes.Buffer
+	if err := generateSBOM(&out, ic, &sbomConfig{Format: sbomFormatSPDX, ResolveDigests: true}); err != nil {
+		t.Fatalf("Unexpected error generating the SPDX SBOM: %v", err)
+	}
+	var spdx struct {
+		SPDXVersion string `json:"spdxVersion"`
+		Packages    []struct {
+			ID         string `json:"SPDXID"`
+			Name       string `json:"name"`
+			Version    string `json:"versionInfo"`
+			SourceInfo string `json:"sourceInfo"`
+			Checksums  []struct {
+				Value string `json:"checksumValue"`
+			} `json:"checksums"`
+		} `json:"packages"`
+		Relationships []struct {
+			Element string `json:"spdxElementId"`
+			Type    string `json:"relationshipType"`
+			Related string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &spdx); err != nil {
+		t.Fatalf("Unexpected error unmarshalling %s: %v", out.String(), err)
+	}
+	if spdx.SPDXVersion != "SPDX-2.3" {
+		t.Fatalf("Unexpected SPDX version %q", spdx.SPDXVersion)
+	}
+	var names, checksummed []string
+	for _, p := range spdx.Packages {
+		names = append(names, p.Name+"@"+p.Version)
+		if len(p.Checksums) > 0 {
+			checksummed = append(checksummed, p.Name)
+		}
+		if p.ID == "SPDXRef-Installer" && p.SourceInfo != "built with go.version=go1.22.1, vcs.revision=abc123" {
+			t.Fatalf("Source info does not match: got %q", p.SourceInfo)
+		}
+	}
+	wantNames := []string{
+		"service-catalog@0.1.11-gke.0",
+		"sc@0.1.1",
+		"gcr.io/gcp-services/service-catalog@v0.1.11-gke.0",
+		"quay.io/coreos/etcd-operator@v0.6.1",
+		"quay.io/coreos/etcd@v3.1.8",
+		"github.com/spf13/cobra@v1.8.0",
+		"sigs.k8s.io/yaml@v1.4.0",
+	}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("Packages do not match: got %v; want %v", names, wantNames)
+	}
+	if want := []string{"gcr.io/gcp-services/service-catalog", "quay.io/coreos/etcd"}; !reflect.DeepEqual(checksummed, want) {
+		t.Fatalf("Packages with checksums do not match: got %v; want %v", checksummed, want)
+	}
+	var relationships []string
+	for _, r := range spdx.Relationships {
+		relationships = append(relationships, r.Element+" "+r.Type+" "+r.Related)
+	}
+	for _, want := range []string{
+		"SPDXRef-DOCUMENT DESCRIBES SPDXRef-Release",
+		"SPDXRef-Installer GENERATES SPDXRef-Release",
+		"SPDXRef-Release CONTAINS SPDXRef-Image-2",
+		"SPDXRef-Installer DEPENDS_ON SPDXRef-Module-1",
+	} {
+		if !containsString(relationships, want) {
+			t.Fatalf("Expected relationship %q, got %v", want, relationships)
+		}
+	}
+
+	out.Reset()
+	if err := generateSBOM(&out, ic, &sbomConfig{Format: sbomFormatCycloneDX}); err != nil {
+		t.Fatalf("Unexpected error generating the CycloneDX SBOM: %v", err)
+	}
+	var cdx struct {
+		BOMFormat  string `json:"bomFormat"`
+		Components []struct {
+			Type   string        `json:"type"`
+			PURL   string        `json:"purl"`
+			Hashes []interface{} `json:"hashes"`
+		} `json:"components"`
+		Dependencies []struct {
+			Ref       string   `json:"ref"`
+			DependsOn []string `json:"dependsOn"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &cdx); err != nil {
+		t.Fatalf("Unexpected error unmarshalling %s: %v", out.String(), err)
+	}
+	if cdx.BOMFormat != "CycloneDX" || len(cdx.Components) != 6 {
+		t.Fatalf("Unexpected BOM %s", out.String())
+	}
+	for _, c := range cdx.Components {
+		// The digests were not resolved.
+		if len(c.Hashes) > 0 {
+			t.Fatalf("Expected no hashes without resolving the digests, got %+v", c)
+		}
+	}
+	if len(cdx.Dependencies) != 2 || cdx.Dependencies[0].Ref != "service-catalog" || len(cdx.Dependencies[0].DependsOn) != 3 || len(cdx.Dependencies[1].DependsOn) != 2 {
+		t.Fatalf("Unexpected dependencies %+v", cdx.Dependencies)
+	}
+
+	if err := generateSBOM(&out, ic, &sbomConfig{Format: "swid"}); err == nil {
+		t.Fatalf("Expected an error for an unknown format")
+	}
+}
+
+// TestNewSBOMGopkgLock tests that the modules of GOPATH builds, which have
+// none in their build info, are those of the embedded Gopkg.lock, and that
+// their revision is the one set at build time.
+func TestNewSBOMGopkgLock(t *testing.T) {
+	oldBuildInfo, oldRevision := readBuildInfo, buildRevision
+	defer func() { readBuildInfo, buildRevision = oldBuildInfo, oldRevision }()
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{GoVersion: "go1.10"}, true
+	}
+	buildRevision = "def456"
+
+	b, err := newSBOM(validInstallConfig(), nil, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var modules []string
+	for _, m := range b.Modules {
+		modules = append(modules, m.PURL)
+	}
+	for _, want := range []string{
+		"pkg:golang/github.com/ghodss/yaml@v1.0.0",
+		"pkg:golang/github.com/golang/glog@23def4e6c14b4da8ac2ed8007337bc5eb5007998",
+	} {
+		if !containsString(modules, want) {
+			t.Fatalf("Expected module %s, got %v", want, modules)
+		}
+	}
+	if want := [][2]string{{"go.version", "go1.10"}, {"vcs.revision", "def456"}}; !reflect.DeepEqual(b.Provenance, want) {
+		t.Fatalf("Provenance does not match: got %v; want %v", b.Provenance, want)
+	}
+}
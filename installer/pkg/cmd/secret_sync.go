@@ -0,0 +1,105 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// Backends and modes of the secret sync component, deployed with
+// --enable-secret-sync.
+const (
+	secretSyncVault            = "vault"
+	secretSyncGCPSecretManager = "gcp-secret-manager"
+
+	// secretSyncMirror copies the binding secrets to the backend and
+	// copies the changes made in the backend, e.g. rotations, back.
+	secretSyncMirror = "mirror"
+
+	// secretSyncMove moves the credentials of the binding secrets to the
+	// backend, leaving the secrets empty but for a reference to the
+	// backend entry, and restores them from the backend when asked to
+	// with an annotation.
+	secretSyncMove = "move"
+
+	defaultSecretSyncVaultPath = "secret/service-catalog"
+)
+
+// gcpProjectRE matches GCP project IDs.
+var gcpProjectRE = regexp.MustCompile(`^[a-z][-a-z0-9]{4,28}[a-z0-9]$`)
+
+// validateSecretSync checks the secret sync options of ic.
+func validateSecretSync(ic *InstallConfig) error {
+	if !ic.SecretSync {
+		return nil
+	}
+	// No secret sync image is released, it is built and pushed by the
+	// users.
+	if ic.SecretSyncImage == "" {
+		return fmt.Errorf("--enable-secret-sync requires --secret-sync-image, the image of the secret sync component")
+	}
+	if len(ic.SecretSyncNamespaces) == 0 {
+		return fmt.Errorf("--enable-secret-sync requires --secret-sync-namespaces, the namespaces of the binding secrets to sync")
+	}
+	for _, ns := range ic.SecretSyncNamespaces {
+		if !dns1123LabelRE.MatchString(ns) {
+			return fmt.Errorf("--secret-sync-namespaces: namespace %q must be a lowercase DNS label", ns)
+		}
+	}
+	if ic.SecretSyncMode != secretSyncMirror && ic.SecretSyncMode != secretSyncMove {
+		return fmt.Errorf("--secret-sync-mode must be mirror or move, got %q", ic.SecretSyncMode)
+	}
+	switch ic.SecretSyncBackend {
+	case secretSyncVault:
+		u, err := url.Parse(ic.SecretSyncVaultAddress)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("--secret-sync-backend=vault requires --secret-sync-vault-addr, an http or https URL, got %q", ic.SecretSyncVaultAddress)
+		}
+		if ic.SecretSyncVaultRole == "" {
+			return fmt.Errorf("--secret-sync-backend=vault requires --secret-sync-vault-role, the Vault Kubernetes auth role of the secret sync service account")
+		}
+		if ic.SecretSyncVaultPath == "" {
+			return fmt.Errorf("--secret-sync-vault-path must not be empty")
+		}
+	case secretSyncGCPSecretManager:
+		if !gcpProjectRE.MatchString(ic.SecretSyncProject) {
+			return fmt.Errorf("--secret-sync-backend=gcp-secret-manager requires --secret-sync-project, a GCP project ID, got %q", ic.SecretSyncProject)
+		}
+	default:
+		return fmt.Errorf("--secret-sync-backend must be vault or gcp-secret-manager, got %q", ic.SecretSyncBackend)
+	}
+	return nil
+}
+
+// secretSyncData returns the template data rendering the secret sync
+// component of ic, if enabled.
+func secretSyncData(ic *InstallConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"SecretSync":                  ic.SecretSync,
+		"SecretSyncBackend":           ic.SecretSyncBackend,
+		"SecretSyncMode":              ic.SecretSyncMode,
+		"SecretSyncImage":             ic.SecretSyncImage,
+		"SecretSyncNamespaces":        ic.SecretSyncNamespaces,
+		"SecretSyncVaultAddress":      ic.SecretSyncVaultAddress,
+		"SecretSyncVaultRole":         ic.SecretSyncVaultRole,
+		"SecretSyncVaultPath":         ic.SecretSyncVaultPath,
+		"SecretSyncProject":           ic.SecretSyncProject,
+		"SecretSyncGCPServiceAccount": ic.SecretSyncGCPServiceAccount,
+	}
+}
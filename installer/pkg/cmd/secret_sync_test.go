@@ -0,0 +1,68 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateSecretSync tests that each backend requires its settings.
+func TestValidateSecretSync(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		configure func(ic *InstallConfig)
+		want      string
+	}{
+		{"disabled", func(ic *InstallConfig) { ic.SecretSync = false }, ""},
+		{"vault", func(ic *InstallConfig) {}, ""},
+		{"gcp", func(ic *InstallConfig) {
+			ic.SecretSyncBackend = secretSyncGCPSecretManager
+			ic.SecretSyncProject = "my-project"
+		}, ""},
+		{"unknown backend", func(ic *InstallConfig) { ic.SecretSyncBackend = "aws" }, "--secret-sync-backend must be vault or gcp-secret-manager"},
+		{"unknown mode", func(ic *InstallConfig) { ic.SecretSyncMode = "copy" }, "--secret-sync-mode must be mirror or move"},
+		{"vault without address", func(ic *InstallConfig) { ic.SecretSyncVaultAddress = "vault:8200" }, "requires --secret-sync-vault-addr"},
+		{"vault without role", func(ic *InstallConfig) { ic.SecretSyncVaultRole = "" }, "requires --secret-sync-vault-role"},
+		{"gcp without project", func(ic *InstallConfig) { ic.SecretSyncBackend = secretSyncGCPSecretManager }, "requires --secret-sync-project"},
+		{"without image", func(ic *InstallConfig) { ic.SecretSyncImage = "" }, "requires --secret-sync-image"},
+		{"without namespaces", func(ic *InstallConfig) { ic.SecretSyncNamespaces = nil }, "requires --secret-sync-namespaces"},
+		{"invalid namespace", func(ic *InstallConfig) { ic.SecretSyncNamespaces = []string{"Payments"} }, "must be a lowercase DNS label"},
+	} {
+		ic := validInstallConfig()
+		ic.SecretSync = true
+		ic.SecretSyncBackend = secretSyncVault
+		ic.SecretSyncMode = secretSyncMove
+		ic.SecretSyncVaultAddress = "https://vault.example.com:8200"
+		ic.SecretSyncVaultRole = "service-catalog"
+		ic.SecretSyncVaultPath = defaultSecretSyncVaultPath
+		ic.SecretSyncImage = "registry.example.com/service-catalog-secret-sync:v0.1.0"
+		ic.SecretSyncNamespaces = []string{"payments"}
+		tc.configure(ic)
+
+		err := validateSecretSync(ic)
+		if tc.want == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: error does not match: got %v; want it to contain %q", tc.name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/version"
+	"github.com/Masterminds/semver"
+	"github.com/spf13/cobra"
+)
+
+// releaseURL is the URL the release channels are published under, set at
+// build time with
+// -ldflags "-X github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/cmd.releaseURL=<url>".
+// Builds without one need --release-url.
+var releaseURL string
+
+// releasePublicKey is the base64 encoded ed25519 public key the release
+// channels are signed with, set at build time with
+// -ldflags "-X github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/cmd.releasePublicKey=<key>".
+var releasePublicKey string
+
+// selfUpdateConfig contains the self-update configuration.
+type selfUpdateConfig struct {
+	// Channel is the release channel, e.g. stable or beta.
+	Channel string
+
+	// ReleaseURL is the URL the channel indexes are published under.
+	ReleaseURL string
+
+	// PublicKey is the base64 encoded ed25519 key the channel index is
+	// signed with, releasePublicKey if empty.
+	PublicKey string
+
+	// InsecureSkipSignature accepts unsigned channel indexes, the
+	// checksum of the binary is still verified.
+	InsecureSkipSignature bool
+
+	// CheckOnly only reports whether an update is available.
+	CheckOnly bool
+
+	// Force installs the release of the channel even if it is not newer.
+	Force bool
+}
+
+// releaseIndex is the index of the latest release of a channel, published
+// at <release url>/<channel>.json and signed in <channel>.json.sig.
+type releaseIndex struct {
+	Version string `json:"version"`
+
+	// Binaries are the binaries of the release by GOOS/GOARCH, e.g.
+	// linux/amd64.
+	Binaries map[string]releaseBinary `json:"binaries"`
+}
+
+// releaseBinary is the binary of a release for one platform.
+type releaseBinary struct {
+	// URL of the binary, relative to the index if not absolute.
+	URL string `json:"url"`
+
+	// SHA256 is the hex encoded SHA-256 checksum of the binary.
+	SHA256 string `json:"sha256"`
+}
+
+func NewSelfUpdateCmd() *cobra.Command {
+	uc := &selfUpdateConfig{}
+	c := &cobra.Command{
+		Use:   "self-update",
+		Short: "updates sc to the latest release of its channel",
+		Long: `updates sc to the latest release of its release channel: it reads the
+signed index of the channel, downloads the binary for this OS and
+architecture, verifies its checksum and that it runs, and replaces the
+current executable. With --check-only it only reports whether an update is
+available.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := selfUpdate(uc); err != nil {
+				messages.Println(messages.SelfUpdateFailed)
+				return err
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVar(&uc.Channel, "channel", "stable", "Release channel: stable or beta")
+	c.Flags().StringVar(&uc.ReleaseURL, "release-url", releaseURL, "URL the release channels are published under, defaults to the one built into sc")
+	c.Flags().StringVar(&uc.PublicKey, "release-public-key", "", "Base64 encoded ed25519 public key the channel is signed with, defaults to the key built into sc")
+	c.Flags().BoolVar(&uc.InsecureSkipSignature, "insecure-skip-signature", false, "Accept an unsigned release channel, only verifying the checksum of the binary")
+	c.Flags().BoolVar(&uc.CheckOnly, "check-only", false, "Only report whether an update is available")
+	c.Flags().BoolVar(&uc.Force, "force", false, "Install the release of the channel even if it is not newer")
+	return c
+}
+
+func selfUpdate(uc *selfUpdateConfig) error {
+	if uc.Channel != "stable" && uc.Channel != "beta" {
+		return fmt.Errorf("--channel must be stable or beta, got %q", uc.Channel)
+	}
+	if uc.ReleaseURL == "" {
+		return fmt.Errorf("sc was built without a release URL, use --release-url")
+	}
+	client := &http.Client{Timeout: 5 * time.Minute}
+	indexURL := strings.TrimSuffix(uc.ReleaseURL, "/") + "/" + uc.Channel + ".json"
+	index, err := fetchReleaseIndex(client, indexURL, uc)
+	if err != nil {
+		return err
+	}
+
+	current := version.Version()
+	newer, err := isNewerRelease(index.Version, current)
+	if err != nil {
+		return err
+	}
+	if !newer && !uc.Force {
+		fmt.Printf("sc %s is up to date, the %s channel is at %s\n", current, uc.Channel, index.Version)
+		return nil
+	}
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	binary, ok := index.Binaries[platform]
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s", index.Version, platform)
+	}
+	if uc.CheckOnly {
+		fmt.Printf("sc %s is available on the %s channel, sc is at %s, run sc self-update to update\n", index.Version, uc.Channel, current)
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error finding the sc executable: %v", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("error finding the sc executable: %v", err)
+	}
+	binaryURL, err := resolveReleaseURL(indexURL, binary.URL)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("downloading sc %s for %s\n", index.Version, platform)
+	// Download next to the executable, so that it is replaced by a
+	// rename on the same file system.
+	downloaded, err := downloadReleaseBinary(client, binaryURL, binary.SHA256, filepath.Dir(exe))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(downloaded)
+	if err := checkReleaseBinary(downloaded, index.Version); err != nil {
+		return err
+	}
+	if err := replaceExecutable(exe, downloaded); err != nil {
+		return err
+	}
+	messages.Println(messages.SelfUpdated, index.Version)
+	return nil
+}
+
+// fetchReleaseIndex reads the channel index at indexURL and verifies its
+// signature.
+func fetchReleaseIndex(client *http.Client, indexURL string, uc *selfUpdateConfig) (*releaseIndex, error) {
+	b, err := httpGet(client, indexURL)
+	if err != nil {
+		return nil, err
+	}
+	if !uc.InsecureSkipSignature {
+		key := uc.PublicKey
+		if key == "" {
+			key = releasePublicKey
+		}
+		if key == "" {
+			return nil, fmt.Errorf("no release signing key is built into sc, pass --release-public-key or --insecure-skip-signature")
+		}
+		sig, err := httpGet(client, indexURL+".sig")
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyReleaseSignature(b, sig, key); err != nil {
+			return nil, fmt.Errorf("error verifying %s: %v", indexURL, err)
+		}
+	}
+
+	index := &releaseIndex{}
+	if err := json.Unmarshal(b, index); err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %v", indexURL, err)
+	}
+	if index.Version == "" {
+		return nil, fmt.Errorf("%s has no version", indexURL)
+	}
+	return index, nil
+}
+
+// verifyReleaseSignature checks that sig, the base64 encoded ed25519
+// signature of index, was made with the base64 encoded public key.
+func verifyReleaseSignature(index, sig []byte, key string) error {
+	pub, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release public key: expected %d base64 encoded bytes", ed25519.PublicKeySize)
+	}
+	s, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), index, s) {
+		return fmt.Errorf("the signature does not match the release public key")
+	}
+	return nil
+}
+
+// isNewerRelease returns whether release is a newer version than current.
+func isNewerRelease(release, current string) (bool, error) {
+	r, err := semver.NewVersion(release)
+	if err != nil {
+		return false, fmt.Errorf("invalid release version %q: %v", release, err)
+	}
+	c, err := semver.NewVersion(current)
+	if err != nil {
+		return false, fmt.Errorf("invalid sc version %q: %v", current, err)
+	}
+	return r.GreaterThan(c), nil
+}
+
+// resolveReleaseURL resolves the URL of a binary relative to the index.
+func resolveReleaseURL(indexURL, ref string) (string, error) {
+	base, err := url.Parse(indexURL)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid binary URL %q: %v", ref, err)
+	}
+	return base.ResolveReference(u).String(), nil
+}
+
+func httpGet(client *http.Client, u string) ([]byte, error) {
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %v", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %s: %s", u, resp.Status)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %v", u, err)
+	}
+	return b, nil
+}
+
+// downloadReleaseBinary downloads the binary at u into an executable
+// temporary file in dir and verifies its hex encoded SHA-256 checksum. It
+// returns the path of the file.
+func downloadReleaseBinary(client *http.Client, u, checksum, dir string) (string, error) {
+	resp, err := client.Get(u)
+	if err != nil {
+		return "", fmt.Errorf("error downloading %s: %v", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error downloading %s: %s", u, resp.Status)
+	}
+
+	f, err := ioutil.TempFile(dir, ".sc-update")
+	if err != nil {
+		return "", fmt.Errorf("error creating the binary in %s: %v", dir, err)
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("error downloading %s: %v", u, err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, checksum) {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("checksum of %s does not match: got %s; want %s", u, got, checksum)
+	}
+	if err := os.Chmod(f.Name(), 0755); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// checkReleaseBinary checks that the downloaded binary runs on this
+// platform and is the expected version.
+func checkReleaseBinary(path, release string) error {
+	output, err := execx.Command(path, "version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("the downloaded binary does not run: %s", strings.TrimSpace(string(output)))
+	}
+	if !bytes.Contains(output, []byte("sc version "+release+" ")) {
+		return fmt.Errorf("the downloaded binary is not version %s: %s", release, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// replaceExecutable replaces the executable exe with the file at path,
+// keeping the mode of exe. Windows does not allow replacing a running
+// executable, it is renamed out of the way first.
+func replaceExecutable(exe, path string) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, info.Mode().Perm()); err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return fmt.Errorf("error replacing %s: %v", exe, err)
+		}
+	}
+	if err := os.Rename(path, exe); err != nil {
+		return fmt.Errorf("error replacing %s: %v", exe, err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFetchReleaseIndex tests that the index of a channel is only accepted
+// with a valid signature.
+func TestFetchReleaseIndex(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error generating key: %v", err)
+	}
+	index := []byte(`{"version": "0.2.0", "binaries": {"linux/amd64": {"url": "sc-linux-amd64", "sha256": "00"}}}`)
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, index))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/stable.json", "/unsigned.json":
+			w.Write(index)
+		case "/stable.json.sig":
+			w.Write([]byte(sig))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	key := base64.StdEncoding.EncodeToString(pub)
+
+	got, err := fetchReleaseIndex(srv.Client(), srv.URL+"/stable.json", &selfUpdateConfig{PublicKey: key})
+	if err != nil {
+		t.Fatalf("Unexpected error fetching a signed index: %v", err)
+	}
+	if got.Version != "0.2.0" || got.Binaries["linux/amd64"].URL != "sc-linux-amd64" {
+		t.Fatalf("Index does not match: got %+v", got)
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	otherKey := base64.StdEncoding.EncodeToString(otherPub)
+	if _, err := fetchReleaseIndex(srv.Client(), srv.URL+"/stable.json", &selfUpdateConfig{PublicKey: otherKey}); err == nil || !strings.Contains(err.Error(), "signature does not match") {
+		t.Fatalf("Error does not match: got %v; want a signature mismatch", err)
+	}
+	if _, err := fetchReleaseIndex(srv.Client(), srv.URL+"/unsigned.json", &selfUpdateConfig{PublicKey: key}); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("Error does not match: got %v; want the missing signature", err)
+	}
+	if _, err := fetchReleaseIndex(srv.Client(), srv.URL+"/unsigned.json", &selfUpdateConfig{InsecureSkipSignature: true}); err != nil {
+		t.Fatalf("Unexpected error fetching an unsigned index with --insecure-skip-signature: %v", err)
+	}
+}
+
+// TestDownloadReleaseBinary tests that the binary is replaced only if its
+// checksum matches and it runs as the expected version.
+func TestDownloadReleaseBinary(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho sc version 0.2.0 linux/amd64\n")
+	sum := sha256.Sum256(binary)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(binary)
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "sc-self-update-test")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temporary dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	exe := filepath.Join(dir, "sc")
+	if err := ioutil.WriteFile(exe, []byte("old"), 0750); err != nil {
+		t.Fatalf("Unexpected error writing the executable: %v", err)
+	}
+
+	if _, err := downloadReleaseBinary(srv.Client(), srv.URL, strings.Repeat("0", 64), dir); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("Error does not match: got %v; want a checksum mismatch", err)
+	}
+	path, err := downloadReleaseBinary(srv.Client(), srv.URL, hex.EncodeToString(sum[:]), dir)
+	if err != nil {
+		t.Fatalf("Unexpected error downloading the binary: %v", err)
+	}
+	if err := checkReleaseBinary(path, "0.3.0"); err == nil {
+		t.Fatalf("Expected an error checking the binary against another version")
+	}
+	if err := checkReleaseBinary(path, "0.2.0"); err != nil {
+		t.Fatalf("Unexpected error checking the binary: %v", err)
+	}
+	if err := replaceExecutable(exe, path); err != nil {
+		t.Fatalf("Unexpected error replacing the executable: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(exe)
+	if err != nil || string(b) != string(binary) {
+		t.Fatalf("Executable was not replaced: got %q, %v", b, err)
+	}
+	if info, err := os.Stat(exe); err != nil || info.Mode().Perm() != 0750 {
+		t.Fatalf("Mode of the executable does not match: got %v, %v; want %v", info.Mode().Perm(), err, os.FileMode(0750))
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("Temporary files were left in %s: %d files", dir, len(files))
+	}
+}
+
+// TestIsNewerRelease tests the comparison of the release and sc versions.
+func TestIsNewerRelease(t *testing.T) {
+	for _, tc := range []struct {
+		release, current string
+		want             bool
+	}{
+		{"0.2.0", "0.1.1", true},
+		{"0.1.1", "0.1.1", false},
+		{"0.1.0", "0.1.1", false},
+		{"0.2.0-beta.1", "0.1.1", true},
+	} {
+		got, err := isNewerRelease(tc.release, tc.current)
+		if err != nil || got != tc.want {
+			t.Fatalf("isNewerRelease(%q, %q) does not match: got %v, %v; want %v", tc.release, tc.current, got, err, tc.want)
+		}
+	}
+	if _, err := isNewerRelease("latest", "0.1.1"); err == nil {
+		t.Fatalf("Expected an error comparing an invalid version")
+	}
+}
+
+// TestSelfUpdateReleaseURL tests that builds without a release URL require
+// --release-url.
+func TestSelfUpdateReleaseURL(t *testing.T) {
+	err := selfUpdate(&selfUpdateConfig{Channel: "stable"})
+	if err == nil || !strings.Contains(err.Error(), "--release-url") {
+		t.Fatalf("Error does not match: got %v; want it to ask for --release-url", err)
+	}
+}
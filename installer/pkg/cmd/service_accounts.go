@@ -0,0 +1,134 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+)
+
+// Service accounts the install creates for the components unless
+// pre-created ones are configured. Each is only bound to the roles of its
+// component; etcd has none and runs without a token.
+const (
+	defaultAPIServerServiceAccount         = "apiserver"
+	defaultControllerManagerServiceAccount = "controller-manager"
+	defaultEtcdServiceAccount              = "etcd"
+)
+
+// componentServiceAccount is the service account a component runs as.
+type componentServiceAccount struct {
+	// Key is the template data key of the name.
+	Key string
+
+	// Flag is the flag naming a pre-created service account instead.
+	Flag string
+
+	// Name is the name of the service account.
+	Name string
+
+	// Created is true if the install creates the service account, false
+	// if it was pre-created.
+	Created bool
+
+	// Token is true if the pods of the component need a token to call
+	// the Kubernetes API.
+	Token bool
+}
+
+// componentServiceAccounts returns the service accounts of the components
+// ic deploys. etcd-operator runs the etcd pods it manages as the default
+// service account, so only the etcd StatefulSet of IPv6 clusters has one.
+func componentServiceAccounts(ic *InstallConfig) []componentServiceAccount {
+	sas := []componentServiceAccount{
+		{Key: "APIServerServiceAccount", Flag: "apiserver-service-account", Name: ic.APIServerServiceAccount, Token: true},
+		{Key: "ControllerManagerServiceAccount", Flag: "controller-manager-service-account", Name: ic.ControllerManagerServiceAccount, Token: true},
+	}
+	if !ic.SkipEtcd && ic.IPFamily == ipFamilyIPv6 {
+		sas = append(sas, componentServiceAccount{Key: "EtcdServiceAccount", Flag: "etcd-service-account", Name: ic.EtcdServiceAccount})
+	}
+	defaults := map[string]string{
+		"APIServerServiceAccount":         defaultAPIServerServiceAccount,
+		"ControllerManagerServiceAccount": defaultControllerManagerServiceAccount,
+		"EtcdServiceAccount":              defaultEtcdServiceAccount,
+	}
+	for i := range sas {
+		if sas[i].Name == "" {
+			sas[i].Name, sas[i].Created = defaults[sas[i].Key], true
+		}
+	}
+	return sas
+}
+
+// validateServiceAccounts checks the pre-created service accounts of ic.
+func validateServiceAccounts(ic *InstallConfig) error {
+	if ic.EtcdServiceAccount != "" && (ic.SkipEtcd || ic.IPFamily != ipFamilyIPv6) {
+		return fmt.Errorf("--etcd-service-account only applies to the etcd StatefulSet of --ip-family=ipv6, etcd-operator runs etcd as the default service account")
+	}
+	seen := make(map[string]string)
+	for _, sa := range componentServiceAccounts(ic) {
+		if len(sa.Name) > 253 || !dns1123SubdomainRE.MatchString(sa.Name) {
+			return fmt.Errorf("--%s %q must be a DNS-1123 subdomain", sa.Flag, sa.Name)
+		}
+		if flag, ok := seen[sa.Name]; ok {
+			return fmt.Errorf("--%s and --%s must not name the same service account %q, each component only gets the roles it needs", flag, sa.Flag, sa.Name)
+		}
+		seen[sa.Name] = sa.Flag
+	}
+	return nil
+}
+
+// serviceAccountData returns the template data of the service accounts of
+// ic.
+func serviceAccountData(ic *InstallConfig) map[string]interface{} {
+	var created []componentServiceAccount
+	data := make(map[string]interface{})
+	for _, sa := range componentServiceAccounts(ic) {
+		if sa.Created {
+			created = append(created, sa)
+		}
+		data[sa.Key] = sa.Name
+	}
+	data["CreatedServiceAccounts"] = created
+	return data
+}
+
+// checkServiceAccounts returns an error listing the pre-created service
+// accounts of ic that do not exist.
+func checkServiceAccounts(ic *InstallConfig) error {
+	var missing []string
+	for _, sa := range componentServiceAccounts(ic) {
+		if sa.Created {
+			continue
+		}
+		output, err := kubectlCommand("get", "serviceaccount", sa.Name,
+			"--namespace", ic.Namespace, "-o", "name").CombinedOutput()
+		if err == nil {
+			continue
+		}
+		if !strings.Contains(string(output), "NotFound") {
+			return fmt.Errorf("error getting service account %s/%s: %s", ic.Namespace, sa.Name, strings.TrimSpace(string(output)))
+		}
+		missing = append(missing, fmt.Sprintf("%s (--%s)", sa.Name, sa.Flag))
+	}
+	if len(missing) > 0 {
+		return messages.Errorf(messages.ServiceAccountsMissing, ic.Namespace, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestComponentServiceAccounts tests that the install creates the service
+// accounts that were not pre-created, and only gives etcd one when it runs
+// as a StatefulSet.
+func TestComponentServiceAccounts(t *testing.T) {
+	ic := validInstallConfig()
+	ic.ControllerManagerServiceAccount = "sc-controller-manager"
+	data := serviceAccountData(ic)
+	if got, want := data["APIServerServiceAccount"], defaultAPIServerServiceAccount; got != want {
+		t.Fatalf("Api server service account does not match: got %v; want %v", got, want)
+	}
+	if got, want := data["ControllerManagerServiceAccount"], "sc-controller-manager"; got != want {
+		t.Fatalf("Controller manager service account does not match: got %v; want %v", got, want)
+	}
+	if _, ok := data["EtcdServiceAccount"]; ok {
+		t.Fatalf("etcd-operator clusters must not have an etcd service account")
+	}
+	var created []string
+	for _, sa := range data["CreatedServiceAccounts"].([]componentServiceAccount) {
+		created = append(created, sa.Name)
+	}
+	if want := []string{defaultAPIServerServiceAccount}; !reflect.DeepEqual(created, want) {
+		t.Fatalf("Created service accounts do not match: got %v; want %v", created, want)
+	}
+
+	ic.IPFamily = ipFamilyIPv6
+	sas := componentServiceAccounts(ic)
+	if got := sas[len(sas)-1]; got.Name != defaultEtcdServiceAccount || !got.Created || got.Token {
+		t.Fatalf("etcd service account does not match: got %+v; want a created %s one without token", got, defaultEtcdServiceAccount)
+	}
+}
+
+// TestValidateServiceAccounts tests that the pre-created service accounts
+// must be valid names, distinct, and only name the etcd one when it is used.
+func TestValidateServiceAccounts(t *testing.T) {
+	for _, tc := range []struct {
+		configure func(ic *InstallConfig)
+		want      string
+	}{
+		{func(ic *InstallConfig) { ic.APIServerServiceAccount = "sc-apiserver" }, ""},
+		{func(ic *InstallConfig) { ic.APIServerServiceAccount = "SC_apiserver" }, "must be a DNS-1123 subdomain"},
+		{func(ic *InstallConfig) {
+			ic.APIServerServiceAccount = "service-catalog"
+			ic.ControllerManagerServiceAccount = "service-catalog"
+		}, "must not name the same service account"},
+		{func(ic *InstallConfig) { ic.ControllerManagerServiceAccount = defaultAPIServerServiceAccount }, "must not name the same service account"},
+		{func(ic *InstallConfig) { ic.EtcdServiceAccount = "sc-etcd" }, "only applies to the etcd StatefulSet"},
+		{func(ic *InstallConfig) {
+			ic.IPFamily = ipFamilyIPv6
+			ic.EtcdServiceAccount = "sc-etcd"
+		}, ""},
+	} {
+		ic := validInstallConfig()
+		tc.configure(ic)
+		err := validateServiceAccounts(ic)
+		if tc.want == "" {
+			if err != nil {
+				t.Fatalf("Unexpected error validating service accounts: %v", err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("Error does not match: got %v; want %q", err, tc.want)
+		}
+	}
+}
+
+// TestComponentRoles tests that the service account of each component is
+// only bound to the roles of its component, and that the controller
+// manager may only access the cluster-info ConfigMap of its namespace.
+func TestComponentRoles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "roles")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ic := validInstallConfig()
+	ic.Version = "0.2.3"
+	ic.IPFamily = ipFamilyIPv6
+	ic.EtcdBackup = false
+	ic.EtcdClusterSize = 1
+	ic.Profile = defaultProfile
+	if err := ic.Validate(); err != nil {
+		t.Fatalf("Unexpected error validating config: %v", err)
+	}
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing manifests: %v", err)
+	}
+
+	roles := make(map[string][]string)
+	for _, o := range objs {
+		if o.Kind != "RoleBinding" && o.Kind != "ClusterRoleBinding" {
+			continue
+		}
+		var b struct {
+			RoleRef struct {
+				Kind string `json:"kind"`
+				Name string `json:"name"`
+			} `json:"roleRef"`
+			Subjects []struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"subjects"`
+		}
+		if err := json.Unmarshal(o.JSON, &b); err != nil {
+			t.Fatalf("Unexpected error decoding %s: %v", o, err)
+		}
+		for _, s := range b.Subjects {
+			if s.Namespace == ic.Namespace {
+				roles[s.Name] = append(roles[s.Name], b.RoleRef.Kind+" "+o.Namespace+"/"+b.RoleRef.Name)
+			}
+		}
+	}
+	for _, tc := range []struct {
+		sa   string
+		want []string
+	}{
+		{defaultAPIServerServiceAccount, []string{
+			"ClusterRole /servicecatalog.k8s.io:apiserver",
+			"ClusterRole /system:auth-delegator",
+			"Role kube-system/extension-apiserver-authentication-reader",
+		}},
+		{defaultControllerManagerServiceAccount, []string{
+			"ClusterRole /servicecatalog.k8s.io:controller-manager",
+			"Role kube-system/servicecatalog.k8s.io:leader-locking-controller-manager",
+			"Role service-catalog/servicecatalog.k8s.io:cluster-info-configmap",
+		}},
+		{defaultEtcdServiceAccount, nil},
+	} {
+		got := roles[tc.sa]
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("Roles of %s do not match: got %v; want %v", tc.sa, got, tc.want)
+		}
+	}
+
+	for _, o := range objs {
+		if o.Kind != "Role" || o.Name != "servicecatalog.k8s.io:cluster-info-configmap" {
+			continue
+		}
+		var r struct {
+			Rules []struct {
+				ResourceNames []string `json:"resourceNames"`
+				Verbs         []string `json:"verbs"`
+			} `json:"rules"`
+		}
+		if err := json.Unmarshal(o.JSON, &r); err != nil {
+			t.Fatalf("Unexpected error decoding %s: %v", o, err)
+		}
+		for _, rule := range r.Rules {
+			if !reflect.DeepEqual(rule.Verbs, []string{"create"}) && !reflect.DeepEqual(rule.ResourceNames, []string{"cluster-info"}) {
+				t.Fatalf("Rule %+v of %s is not restricted to the cluster-info ConfigMap", rule, o)
+			}
+		}
+		return
+	}
+	t.Fatalf("No cluster-info ConfigMap Role")
+}
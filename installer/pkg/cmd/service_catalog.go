@@ -17,20 +17,23 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"html/template"
 	"io/ioutil"
 	"math"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
 	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
 	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/version"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
 	"github.com/Masterminds/semver"
 	"github.com/spf13/cobra"
 )
@@ -43,63 +46,384 @@ const (
 	CfssljsonBinaryName = "cfssljson"
 )
 
-// service catalog resources that will be created as part of deployment.
+// service catalog templates that are rendered as part of deployment. The
+// order in which the resulting objects are created is derived from their
+// kinds, see package manifest.
 var (
-	svcCatalogFileNames = []k8sResource{
-		{name: "namespace"},
-		{name: "etcd-operator-rbac"},
-		{name: "etcd-operator-service-account"},
-		{name: "etcd-operator-rbac-binding"},
-		{name: "etcd-operator-deployment"},
-		{name: "tls-cert-secret"},
-		{name: "api-registration"},
-		{name: "service-accounts"},
-		{name: "rbac"},
-		{name: "service"},
-		{name: "apiserver-deployment"},
-		{name: "controller-manager-deployment"},
-		{name: "etcd-cluster-with-backup", dependsOnAPI: "etcd.database.coreos.com/v1beta2"},
+	svcCatalogFileNames = []string{
+		"namespace",
+		"etcd-operator",
+		"tls-cert-secret",
+		"ca-secret",
+		"api-registration",
+		"service-accounts",
+		"rbac",
+		"service",
+		"apiserver-deployment",
+		"controller-manager-deployment",
+		"controller-manager-config",
+		"controller-manager-proxy-ca",
+		"apiserver-authn-ca",
+		"apiserver-encryption-config",
+		"pdb",
+		"hpa",
+		"priority-class",
+		"service-monitor",
+		"network-policy",
+		"instance-quota",
+		"plan-policy",
+		"binding-secret-policy",
+		"catalog-rbac",
+		"secret-sync",
+		"secret-sync-rbac",
+		"etcd-cluster-with-backup",
+		"etcd-maintenance",
 	}
 )
 
-type k8sResource struct {
-	name string
-	// API that this resource depends on
-	dependsOnAPI string
-}
-
 // InstallConfig contains installation configuration.
 type InstallConfig struct {
 	// namespace for service catalog
 	Namespace string
 
-	// Version of Service Catalog
+	// Version of Service Catalog, defaults to the default version of
+	// CatalogVersion.
 	Version string
 
+	// CatalogVersion is the catalog version set of the templates, e.g.
+	// v0.2. It defaults to the one of the minor version of Version.
+	CatalogVersion string
+
+	// NamePrefix and NameSuffix are added to the names of the namespace,
+	// and thereby to the DNS names of the services, and of the
+	// cluster-scoped objects and kube-system roles, so that they don't
+	// collide with those of another installation.
+	NamePrefix string
+	NameSuffix string
+
 	// APIServerServiceName refers to the API Server's service name
 	APIServerServiceName string
 
-	// whether to delete temporary files
-	CleanupTempDirOnSuccess bool
+	// DryRun is the dry run mode: none deploys the objects, client only
+	// generates the YAML files and server also submits them to the api
+	// server with server-side dry run.
+	DryRun string
 
-	// generate YAML files for deployment, do not deploy them
-	DryRun bool
+	// ValidateManifests validates the generated YAML files against the
+	// OpenAPI schema of the cluster before deploying them.
+	ValidateManifests bool
 
 	// CA options (self sign or use kubernetes root CA)
 
+	// KeyAlgorithm is the algorithm of the generated private keys, either
+	// rsa or ecdsa.
+	KeyAlgorithm string
+
+	// KeySize is the size in bits of generated RSA keys, or the curve size
+	// of generated ECDSA keys. Zero selects the default for KeyAlgorithm.
+	KeySize int
+
+	// FIPS restricts the generated keys and certificates to FIPS-approved
+	// algorithms and key sizes, and deploys the FIPS variants of the
+	// images.
+	FIPS bool
+
+	// CertValidity is how long the generated certificates are valid.
+	CertValidity time.Duration
+
+	// StoreCAKey stores the CA, including its private key, in a secret
+	// so that later installs sign new certificates with the same CA.
+	StoreCAKey bool
+
+	// ReuseCerts reuses the api server certificate of a previous install,
+	// if it is still valid, instead of generating new certificates.
+	ReuseCerts bool
+
+	// RestoredCertsDir holds the certificates of a backup restored by
+	// sc dr-restore, installed instead of generating new certificates.
+	RestoredCertsDir string
+
+	// KMSKey is the Cloud KMS crypto key the stored CA private key is
+	// encrypted with. The key is only decrypted while signing
+	// certificates.
+	KMSKey string
+
+	// Profile is the install profile the defaults of the settings below
+	// are taken from.
+	Profile string
+
+	// MaxInstancesPerNamespace limits the number of ServiceInstances per
+	// namespace with an OPA Gatekeeper constraint. Zero means unlimited.
+	MaxInstancesPerNamespace int
+
+	// PlanPolicyFile is the YAML policy mapping namespaces to the service
+	// plans they may use, enforced with OPA Gatekeeper constraints.
+	PlanPolicyFile string
+
+	// PlanPolicy is the loaded plan policy, read from PlanPolicyFile if
+	// nil.
+	PlanPolicy *planPolicy
+
+	// RBACMappingFile is the YAML mapping of groups to their access to the
+	// catalog resources, granted with RoleBindings and ClusterRoleBindings.
+	RBACMappingFile string
+
+	// RBACMapping is the loaded RBAC mapping, read from RBACMappingFile if
+	// nil.
+	RBACMapping *rbacMapping
+
+	// BindingSecretPolicyFile is the YAML policy setting the
+	// secretTransforms of the ServiceBindings, applied with OPA Gatekeeper
+	// mutators.
+	BindingSecretPolicyFile string
+
+	// BindingSecretPolicy is the loaded binding secret policy, read from
+	// BindingSecretPolicyFile if nil.
+	BindingSecretPolicy *bindingSecretPolicy
+
 	// storage options
 	EtcdClusterSize        int32
+	EtcdBackup             bool
 	EtcdBackupStorageClass string
+
+	// EtcdEncryption encrypts the instances and bindings in etcd with the
+	// aescbc or kms provider, the latter through a plugin sidecar of
+	// EtcdEncryptionKMSPluginImage using the Cloud KMS key
+	// EtcdEncryptionKMSKey. EtcdEncryptionKeys are the AES-CBC keys, read
+	// from a previous install or generated.
+	EtcdEncryption               string
+	EtcdEncryptionKMSKey         string
+	EtcdEncryptionKMSPluginImage string
+	EtcdEncryptionKeys           []encryptionKey
+
+	// SkipEtcd uses the external etcd at EtcdServers instead of creating
+	// an etcd cluster.
+	SkipEtcd    bool
+	EtcdServers string
+
+	// MigratedEtcdServers is the etcd an etcd migration or restore
+	// switched the api server over to, which the api server keeps using.
+	MigratedEtcdServers string
+
+	// EtcdMaintenance deploys a CronJob defragmenting etcd and saving a
+	// snapshot on EtcdMaintenanceSchedule, uploaded to the GCS URL
+	// EtcdMaintenanceBucket as EtcdMaintenanceGCPServiceAccount, or kept
+	// on a persistent volume of EtcdBackupStorageClass, the
+	// EtcdMaintenanceKeep newest ones.
+	EtcdMaintenance                  bool
+	EtcdMaintenanceSchedule          string
+	EtcdMaintenanceBucket            string
+	EtcdMaintenanceGCPServiceAccount string
+	EtcdMaintenanceKeep              int
+
+	// SkipRBAC skips the roles and bindings of the service accounts, which
+	// must exist already, e.g. pre-provisioned by a security team.
+	SkipRBAC bool
+
+	// SkipAPIRegistration skips the APIService, which is then registered
+	// manually.
+	SkipAPIRegistration bool
+
+	// availability options
+	APIServerReplicas         int32
+	ControllerManagerReplicas int32
+	PodDisruptionBudgets      bool
+
+	// APIServerHPA scales the api server with a HorizontalPodAutoscaler
+	// between APIServerReplicas and APIServerMaxReplicas, targeting
+	// APIServerCPUUtilization percent of the CPU requests, unless zero,
+	// and the average values of APIServerPodsMetrics, given as
+	// name=value.
+	APIServerHPA            bool
+	APIServerMaxReplicas    int32
+	APIServerCPUUtilization int32
+	APIServerPodsMetrics    []string
+
+	// RequestHeaderCAFile, RequestHeaderAllowedNames and ClientCAFile
+	// override the front-proxy and client certificate authentication of
+	// the api server, read from the extension-apiserver-authentication
+	// ConfigMap by default. AuthenticationSkipLookup does not read the
+	// ConfigMap at all.
+	RequestHeaderCAFile       string
+	RequestHeaderAllowedNames []string
+	ClientCAFile              string
+	AuthenticationSkipLookup  bool
+
+	// PDBMaxUnavailable is the maxUnavailable of the PodDisruptionBudgets,
+	// a number of pods or a percentage.
+	PDBMaxUnavailable string
+
+	// Monitoring annotates the controller manager pods for metrics
+	// scraping by Prometheus.
+	Monitoring bool
+
+	// NetworkPolicies restricts traffic to the service catalog pods.
+	NetworkPolicies bool
+
+	// PriorityClass schedules the service catalog pods with a dedicated
+	// PriorityClass.
+	PriorityClass bool
+
+	// ServiceMonitor creates a Prometheus Operator ServiceMonitor for the
+	// controller manager.
+	ServiceMonitor bool
+
+	// SecretSync deploys the component syncing the binding secrets to
+	// SecretSyncBackend, HashiCorp Vault at SecretSyncVaultAddress or GCP
+	// Secret Manager in SecretSyncProject, in SecretSyncMode, mirror or
+	// move, with SecretSyncImage. It may only read and write the secrets
+	// of SecretSyncNamespaces.
+	SecretSync                  bool
+	SecretSyncBackend           string
+	SecretSyncMode              string
+	SecretSyncImage             string
+	SecretSyncNamespaces        []string
+	SecretSyncVaultAddress      string
+	SecretSyncVaultRole         string
+	SecretSyncVaultPath         string
+	SecretSyncProject           string
+	SecretSyncGCPServiceAccount string
+
+	// APIServerServiceAccount, ControllerManagerServiceAccount and
+	// EtcdServiceAccount are pre-created service accounts the components
+	// run as, for clusters where the installer may not create service
+	// accounts. The install creates its own if empty.
+	APIServerServiceAccount         string
+	ControllerManagerServiceAccount string
+	EtcdServiceAccount              string
+
+	// DetectCapabilities disables the optional features above whose APIs
+	// the cluster does not serve.
+	DetectCapabilities bool
+
+	// AutoscalingAPIVersion is the autoscaling API version of the api
+	// server autoscaler, detected with the capabilities, instead of the
+	// one of the Kubernetes version of the cluster if set.
+	AutoscalingAPIVersion string
+
+	// CheckArchitectures checks that the images are built for the
+	// architectures of the nodes and, on clusters mixing architectures,
+	// sets NodeArchitectures to those the images run on.
+	CheckArchitectures bool
+
+	// NodeArchitectures pins the service catalog pods to the nodes of
+	// these architectures, e.g. amd64 or arm64, all if empty.
+	NodeArchitectures []string
+
+	// IPFamily is the IP family of the cluster: ipv4, ipv6 or dual.
+	IPFamily string
+
+	// Mesh is the service mesh injecting sidecars into the pods of the
+	// namespace, istio, or empty for none.
+	Mesh string
+
+	// Apply configures how the objects are applied.
+	Apply applyOptions
+
+	// ForceAdopt takes over the existing cluster-scoped objects managed by
+	// other tools instead of failing.
+	ForceAdopt bool
+
+	// ForceUnlock takes over the lease of the namespace another sc
+	// operation holds instead of failing.
+	ForceUnlock bool
+
+	// GitOps hands the manifests off to a GitOps tool instead of applying
+	// them, if set.
+	GitOps gitOpsConfig
+
+	// ReadyTimeout is how long to wait for the components to be ready,
+	// streaming the warning events of the namespace meanwhile. Zero skips
+	// waiting.
+	ReadyTimeout time.Duration
+
+	// VerifyAs is the identity to verify the installation as once it is
+	// ready, if set.
+	VerifyAs verifyIdentity
+
+	// HistoryMax is the number of revisions of the applied manifests kept
+	// for sc rollback, 0 records none.
+	HistoryMax int
+
+	// api server options, for clusters with thousands of catalog resources
+
+	// APIServerRequestTimeout is the timeout of the requests to the api
+	// server, bounding the etcd reads of LIST requests. 0 keeps the
+	// default of the api server, 1m.
+	APIServerRequestTimeout time.Duration
+
+	// APIServerDisableWatchCache disables the watch cache of the api
+	// server, so that every LIST and watch reads etcd.
+	APIServerDisableWatchCache bool
+
+	// APIServerDefaultWatchCacheSize is the watch cache size of the
+	// resources not in APIServerWatchCacheSizes. 0 keeps the default of
+	// the api server, 100.
+	APIServerDefaultWatchCacheSize int
+
+	// APIServerWatchCacheSizes are the watch cache sizes of resources, as
+	// resource[.group]#size, e.g. serviceinstances#5000.
+	APIServerWatchCacheSizes []string
+
+	// controller manager options, for slow or rate limited brokers
+
+	// BrokerRelistInterval is how often the controller manager fetches the
+	// catalogs of the brokers.
+	BrokerRelistInterval time.Duration
+
+	// OSBAPITimeout is the timeout of requests to the brokers.
+	OSBAPITimeout time.Duration
+
+	// ResyncInterval is how often the controller manager reconciles all
+	// the catalog resources.
+	ResyncInterval time.Duration
+
+	// OperationPollingMaxBackoff caps the interval between the polls of
+	// the last operation of asynchronous provisions, updates and
+	// deprovisions.
+	OperationPollingMaxBackoff time.Duration
+
+	// ReconciliationRetryDuration is how long the controller manager
+	// retries an operation, including polling it and the orphan
+	// mitigation of failed provisions, before giving up.
+	ReconciliationRetryDuration time.Duration
+
+	// ControllerManagerLogLevel is the glog verbosity of the controller
+	// manager.
+	ControllerManagerLogLevel int
+
+	// OSBAPIVersion is the Open Service Broker API version the controller
+	// manager sends to the brokers, 2.13 or 2.14.
+	OSBAPIVersion string
+
+	// ControllerManagerHostAliases, as hostname=ip, and the DNS policy,
+	// nameservers, search domains and resolver options customize how the
+	// controller manager resolves the brokers, e.g. behind split-horizon
+	// DNS.
+	ControllerManagerHostAliases    []string
+	ControllerManagerDNSPolicy      string
+	ControllerManagerDNSNameservers []string
+	ControllerManagerDNSSearches    []string
+	ControllerManagerDNSOptions     []string
+
+	// ControllerManagerProxy is the URL of the egress proxy the controller
+	// manager calls the brokers outside the cluster through, except those
+	// of ControllerManagerNoProxy. ControllerManagerProxyCAFile is the
+	// PEM file of the CAs of the proxy, trusted in addition to those of
+	// the image.
+	ControllerManagerProxy       string
+	ControllerManagerNoProxy     []string
+	ControllerManagerProxyCAFile string
 }
 
 func NewServiceCatalogInstallCmd() *cobra.Command {
-	ic := &InstallConfig{
-		Namespace:               "service-catalog",
-		APIServerServiceName:    "service-catalog-api",
-		CleanupTempDirOnSuccess: false,
-		EtcdClusterSize:         3,
-		EtcdBackupStorageClass:  "standard",
-	}
+	return newServiceCatalogInstallCmd(newInstallConfig())
+}
+
+// newServiceCatalogInstallCmd returns the install command setting ic from
+// its flags.
+func newServiceCatalogInstallCmd(ic *InstallConfig) *cobra.Command {
+	var dryrun bool
 	c := &cobra.Command{
 		Use:   "install",
 		Short: "installs Service Catalog in Kubernetes cluster",
@@ -107,165 +431,654 @@ func NewServiceCatalogInstallCmd() *cobra.Command {
 assumes kubectl is configured to connect to the Kubernetes cluster.`,
 		// Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if dryrun {
+				ic.DryRun = dryRunClient
+			}
+			if err := applyProfile(ic, cmd.Flags()); err != nil {
+				return err
+			}
+			if err := applyNameAffixes(ic); err != nil {
+				return err
+			}
 			if err := installServiceCatalog(ic); err != nil {
-				fmt.Println("Service Catalog could not be installed.")
+				messages.Println(messages.InstallFailed)
 				return err
 			}
-			fmt.Println("Service Catalog installed successfully.")
+			if ic.DryRun != dryRunNone {
+				messages.Println(messages.DryRunSucceeded)
+				return nil
+			}
+			if ic.GitOps.isSet() {
+				messages.Println(messages.HandedOff)
+				return nil
+			}
+			messages.Println(messages.Installed)
 			return nil
 		},
 	}
 	// add install command flags
-	c.Flags().Int32Var(&ic.EtcdClusterSize, "etcd-cluster-size", 3, "Etcd cluster size")
-	c.Flags().StringVar(&ic.EtcdBackupStorageClass, "etcd-backup-storageclass", "standard", "Etcd Backup StorageClass")
-	c.Flags().StringVar(&ic.Version, "version", "0.1.11-gke.0", "Service Catalog version")
-	c.Flags().BoolVar(&ic.DryRun, "dryrun", false, "Dryrun")
+	addInstallFlags(c, ic)
+	c.Flags().StringVar(&ic.DryRun, "dry-run", dryRunNone, "Dry run mode: none, client to only generate the YAML files, or server to also submit them with server-side dry run, catching objects rejected by validation or admission (quotas, PodSecurityPolicies, OPA policies) without changing the cluster")
+	c.Flags().Lookup("dry-run").NoOptDefVal = dryRunClient
+	c.Flags().BoolVar(&dryrun, "dryrun", false, "Only generate the YAML files")
+	c.Flags().MarkDeprecated("dryrun", "use --dry-run=client instead")
+	c.Flags().DurationVar(&ic.ReadyTimeout, "ready-timeout", defaultReadyTimeout, "How long to wait for the components to be ready, showing the warning events of the namespace meanwhile. 0 skips waiting")
+	addVerifyIdentityFlags(c, &ic.VerifyAs)
+	c.Flags().IntVar(&ic.HistoryMax, "history-max", defaultHistoryMax, "Number of revisions of the applied manifests to keep for sc rollback, 0 records none")
+	c.Flags().BoolVar(&ic.ValidateManifests, "validate", true, "Validate the generated YAML files against the OpenAPI schema of the cluster")
+	c.Flags().BoolVar(&ic.ForceAdopt, "force-adopt", false, "Take over the existing cluster-scoped objects managed by other tools, e.g. Helm, that have the names of the objects sc creates, instead of failing")
+	c.Flags().BoolVar(&ic.ForceUnlock, "force-unlock", false, "Take over the lock of the namespace held by another sc operation, e.g. a stale one, instead of failing")
+	addGitOpsFlags(c, &ic.GitOps)
+	// --fail-at is for developers testing how reruns recover from failures.
+	c.Flags().StringVar(&ic.Apply.FailAt, "fail-at", "", "Fail the install at a phase: "+failPhasesUsage())
+	c.Flags().MarkHidden("fail-at")
 
 	return c
 }
 
-func installServiceCatalog(ic *InstallConfig) error {
+// newInstallConfig returns an install configuration with the default
+// settings.
+func newInstallConfig() *InstallConfig {
+	return &InstallConfig{
+		Namespace:                   defaultNamespace,
+		APIServerServiceName:        "service-catalog-api",
+		DryRun:                      dryRunNone,
+		Profile:                     defaultProfile,
+		EtcdBackupStorageClass:      "standard",
+		EtcdMaintenanceSchedule:     defaultEtcdMaintenanceSchedule,
+		EtcdMaintenanceKeep:         defaultEtcdMaintenanceKeep,
+		KeyAlgorithm:                "rsa",
+		CertValidity:                defaultCertValidity,
+		BrokerRelistInterval:        defaultBrokerRelistInterval,
+		OSBAPITimeout:               defaultOSBAPITimeout,
+		ResyncInterval:              defaultResyncInterval,
+		OperationPollingMaxBackoff:  defaultOperationPollingMaxBackoff,
+		ReconciliationRetryDuration: defaultReconciliationRetryDuration,
+		ControllerManagerLogLevel:   defaultControllerManagerLogLevel,
+		OSBAPIVersion:               defaultOSBAPIVersion,
+		IPFamily:                    ipFamilyIPv4,
+		ReadyTimeout:                defaultReadyTimeout,
+		HistoryMax:                  defaultHistoryMax,
+		DetectCapabilities:          true,
+		CheckArchitectures:          true,
+		GitOps:                      gitOpsConfig{Branch: "main", Path: "service-catalog", Tool: gitOpsToolNone},
+	}
+}
+
+// addInstallFlags adds the flags of the settings affecting the installed
+// objects to c.
+func addInstallFlags(c *cobra.Command, ic *InstallConfig) {
+	c.Flags().StringVar(&ic.NamePrefix, "name-prefix", "", "Prefix of the names of the namespace, the cluster-scoped objects and the kube-system roles, to not collide with another installation")
+	c.Flags().StringVar(&ic.NameSuffix, "name-suffix", "", "Suffix of the names of the namespace, the cluster-scoped objects and the kube-system roles")
+	c.Flags().StringVar(&ic.Profile, "profile", defaultProfile, "Install profile: minimal, default or production. Individual flags override the profile settings")
+	c.Flags().Int32Var(&ic.EtcdClusterSize, "etcd-cluster-size", 3, "Etcd cluster size")
+	c.Flags().BoolVar(&ic.EtcdBackup, "etcd-backup", true, "Periodically back up etcd to a persistent volume")
+	c.Flags().StringVar(&ic.EtcdBackupStorageClass, "etcd-backup-storageclass", "standard", "Etcd Backup StorageClass")
+	c.Flags().BoolVar(&ic.EtcdMaintenance, "enable-etcd-maintenance", false, "Deploy a CronJob defragmenting etcd and saving snapshots of it")
+	c.Flags().StringVar(&ic.EtcdMaintenanceSchedule, "etcd-maintenance-schedule", defaultEtcdMaintenanceSchedule, "Cron schedule of the etcd maintenance")
+	c.Flags().StringVar(&ic.EtcdMaintenanceBucket, "etcd-maintenance-bucket", "", "gs://<bucket>[/<path>] the etcd snapshots are uploaded to, instead of a persistent volume of --etcd-backup-storageclass")
+	c.Flags().StringVar(&ic.EtcdMaintenanceGCPServiceAccount, "etcd-maintenance-gcp-service-account", "", "GCP service account the etcd maintenance impersonates with Workload Identity to upload the snapshots")
+	c.Flags().IntVar(&ic.EtcdMaintenanceKeep, "etcd-maintenance-keep", defaultEtcdMaintenanceKeep, "Number of etcd snapshots kept on the persistent volume")
+	c.Flags().StringVar(&ic.EtcdEncryption, "etcd-encryption", "", "Encrypt the instances and bindings in etcd at rest: aescbc with a key kept in a secret, or kms with --etcd-encryption-kms-key")
+	c.Flags().StringVar(&ic.EtcdEncryptionKMSKey, "etcd-encryption-kms-key", "", "Cloud KMS key (projects/.../cryptoKeys/...) encrypting the data keys of --etcd-encryption=kms")
+	c.Flags().StringVar(&ic.EtcdEncryptionKMSPluginImage, "etcd-encryption-kms-plugin-image", defaultKMSPluginImage, "Image of the Cloud KMS plugin sidecar of --etcd-encryption=kms")
+	c.Flags().BoolVar(&ic.SkipEtcd, "skip-etcd", false, "Use the external etcd at --etcd-servers instead of creating an etcd cluster")
+	c.Flags().StringVar(&ic.EtcdServers, "etcd-servers", "", "Comma separated URLs of the external etcd, requires --skip-etcd")
+	c.Flags().BoolVar(&ic.SkipRBAC, "skip-rbac", false, "Do not create the roles and bindings of the service accounts, they must exist already")
+	c.Flags().BoolVar(&ic.SkipAPIRegistration, "skip-api-registration", false, "Do not register the APIService, it is registered manually")
+	c.Flags().Int32Var(&ic.APIServerReplicas, "apiserver-replicas", 1, "Number of api server replicas")
+	c.Flags().Int32Var(&ic.ControllerManagerReplicas, "controller-manager-replicas", 1, "Number of controller manager replicas, leader election is enabled for more than one")
+	c.Flags().BoolVar(&ic.PodDisruptionBudgets, "enable-pdb", false, "Create PodDisruptionBudgets for the api server, controller manager and etcd when they run more than one replica")
+	c.Flags().BoolVar(&ic.APIServerHPA, "enable-apiserver-hpa", false, "Scale the api server with a HorizontalPodAutoscaler between --apiserver-replicas and --apiserver-max-replicas")
+	c.Flags().Int32Var(&ic.APIServerMaxReplicas, "apiserver-max-replicas", defaultAPIServerMaxReplicas, "Maximum number of api server replicas of the autoscaler")
+	c.Flags().Int32Var(&ic.APIServerCPUUtilization, "apiserver-hpa-cpu-utilization", defaultAPIServerCPUUtilization, "Target CPU utilization of the api server pods in percent of their requests, 0 to scale on the pods metrics only")
+	c.Flags().StringSliceVar(&ic.APIServerPodsMetrics, "apiserver-hpa-pods-metric", nil, "Custom metric of the api server pods to scale on, as <metric>=<average value per pod>, e.g. http_requests_per_second=50")
+	c.Flags().StringVar(&ic.RequestHeaderCAFile, "requestheader-client-ca-file", "", "PEM file of the CAs of the front-proxy client certificates the api server trusts, instead of those of the extension-apiserver-authentication ConfigMap")
+	c.Flags().StringSliceVar(&ic.RequestHeaderAllowedNames, "requestheader-allowed-names", nil, "Common names of the front-proxy client certificates the api server accepts, all those signed by --requestheader-client-ca-file if empty")
+	c.Flags().StringVar(&ic.ClientCAFile, "client-ca-file", "", "PEM file of the CAs of the client certificates the api server authenticates, instead of those of the extension-apiserver-authentication ConfigMap")
+	c.Flags().BoolVar(&ic.AuthenticationSkipLookup, "authentication-skip-lookup", false, "Do not read the extension-apiserver-authentication ConfigMap, e.g. when the api server may not read kube-system. Requires --requestheader-client-ca-file")
+	c.Flags().StringVar(&ic.PDBMaxUnavailable, "pdb-max-unavailable", defaultPDBMaxUnavailable, "Number or percentage of the pods of each component that voluntary disruptions may take down")
+	c.Flags().BoolVar(&ic.Monitoring, "enable-monitoring", false, "Annotate the controller manager for Prometheus metrics scraping")
+	c.Flags().BoolVar(&ic.NetworkPolicies, "enable-network-policies", false, "Create NetworkPolicies restricting traffic to the service catalog pods")
+	c.Flags().BoolVar(&ic.PriorityClass, "enable-priority-class", false, "Schedule the service catalog pods with a dedicated PriorityClass")
+	c.Flags().BoolVar(&ic.ServiceMonitor, "enable-service-monitor", false, "Create a Prometheus Operator ServiceMonitor for the controller manager")
+	c.Flags().BoolVar(&ic.SecretSync, "enable-secret-sync", false, "Deploy a component syncing the ServiceBinding secrets to HashiCorp Vault or GCP Secret Manager, see --secret-sync-backend")
+	c.Flags().StringVar(&ic.SecretSyncBackend, "secret-sync-backend", secretSyncGCPSecretManager, "Backend the binding secrets are synced to: vault or gcp-secret-manager")
+	c.Flags().StringVar(&ic.SecretSyncMode, "secret-sync-mode", secretSyncMirror, "mirror copies the binding secrets to the backend and back, move leaves only a reference to the backend entry in the secrets")
+	c.Flags().StringVar(&ic.SecretSyncImage, "secret-sync-image", "", "Image of the secret sync component, required with --enable-secret-sync")
+	c.Flags().StringSliceVar(&ic.SecretSyncNamespaces, "secret-sync-namespaces", nil, "Namespaces of the binding secrets to sync, the only ones the secret sync component may read and write secrets in")
+	c.Flags().StringVar(&ic.SecretSyncVaultAddress, "secret-sync-vault-addr", "", "URL of the Vault server, for --secret-sync-backend=vault")
+	c.Flags().StringVar(&ic.SecretSyncVaultRole, "secret-sync-vault-role", "", "Vault Kubernetes auth role of the secret sync service account")
+	c.Flags().StringVar(&ic.SecretSyncVaultPath, "secret-sync-vault-path", defaultSecretSyncVaultPath, "Vault KV path the binding secrets are stored under, as <path>/<namespace>/<secret>")
+	c.Flags().StringVar(&ic.SecretSyncProject, "secret-sync-project", "", "GCP project of the secrets, for --secret-sync-backend=gcp-secret-manager")
+	c.Flags().StringVar(&ic.SecretSyncGCPServiceAccount, "secret-sync-gcp-service-account", "", "GCP service account the secret sync service account impersonates with Workload Identity")
+	c.Flags().StringVar(&ic.APIServerServiceAccount, "apiserver-service-account", "", "Pre-created service account the api server runs as, instead of the "+defaultAPIServerServiceAccount+" one the install creates")
+	c.Flags().StringVar(&ic.ControllerManagerServiceAccount, "controller-manager-service-account", "", "Pre-created service account the controller manager runs as, instead of the "+defaultControllerManagerServiceAccount+" one the install creates")
+	c.Flags().StringVar(&ic.EtcdServiceAccount, "etcd-service-account", "", "Pre-created service account the etcd StatefulSet of --ip-family=ipv6 runs as, instead of the "+defaultEtcdServiceAccount+" one the install creates")
+	c.Flags().BoolVar(&ic.DetectCapabilities, "detect-capabilities", true, "Disable the optional features (PodDisruptionBudgets, PriorityClass, NetworkPolicies, ServiceMonitor) whose APIs the cluster does not serve")
+	c.Flags().BoolVar(&ic.CheckArchitectures, "check-architectures", true, "Check that the images are built for the architectures of the nodes, e.g. arm64, and schedule the pods on the nodes they run on")
+	c.Flags().StringSliceVar(&ic.NodeArchitectures, "node-architectures", nil, "Schedule the service catalog pods on the nodes of these architectures, e.g. arm64, instead of detecting them")
+	c.Flags().IntVar(&ic.MaxInstancesPerNamespace, "max-instances-per-namespace", 0, "Limit the number of ServiceInstances per namespace using an OPA Gatekeeper constraint, 0 means unlimited. Requires Gatekeeper")
+	c.Flags().StringVar(&ic.PlanPolicyFile, "plan-policy", "", "YAML file mapping namespaces to the service plans their instances may use, enforced with OPA Gatekeeper constraints. Requires Gatekeeper")
+	c.Flags().StringVar(&ic.RBACMappingFile, "rbac-mapping", "", "YAML file mapping groups to the admin, edit or view catalog role, in all or some namespaces, granted with RoleBindings and ClusterRoleBindings")
+	c.Flags().StringVar(&ic.BindingSecretPolicyFile, "binding-secret-policy", "", "YAML file setting the secretTransforms, e.g. renamed or prefixed keys, of the ServiceBindings of all or some namespaces, applied with OPA Gatekeeper mutators. Requires Gatekeeper with mutation enabled")
+	c.Flags().DurationVar(&ic.APIServerRequestTimeout, "apiserver-request-timeout", 0, "Timeout of the requests to the api server, bounding the etcd reads of LIST requests of clusters with thousands of instances. 0 keeps the default, 1m")
+	c.Flags().BoolVar(&ic.APIServerDisableWatchCache, "apiserver-disable-watch-cache", false, "Disable the watch cache of the api server, so that every LIST and watch reads etcd")
+	c.Flags().IntVar(&ic.APIServerDefaultWatchCacheSize, "apiserver-default-watch-cache-size", 0, "Watch cache size of the resources not in --apiserver-watch-cache-sizes. 0 keeps the default, 100")
+	c.Flags().StringSliceVar(&ic.APIServerWatchCacheSizes, "apiserver-watch-cache-sizes", nil, "Watch cache sizes of resources, as resource[.group]#size, e.g. serviceinstances#5000, so that the watches of the controller manager do not fall behind and relist")
+	c.Flags().DurationVar(&ic.BrokerRelistInterval, "broker-relist-interval", defaultBrokerRelistInterval, "How often the controller manager fetches the catalogs of the brokers")
+	c.Flags().DurationVar(&ic.OSBAPITimeout, "osb-api-timeout", defaultOSBAPITimeout, "Timeout of the controller manager requests to the brokers")
+	c.Flags().DurationVar(&ic.ResyncInterval, "resync-interval", defaultResyncInterval, "How often the controller manager reconciles all the catalog resources")
+	c.Flags().DurationVar(&ic.OperationPollingMaxBackoff, "operation-polling-max-backoff", defaultOperationPollingMaxBackoff, "Maximum interval between the polls of asynchronous broker operations, e.g. provisioning")
+	c.Flags().DurationVar(&ic.ReconciliationRetryDuration, "reconciliation-retry-duration", defaultReconciliationRetryDuration, "How long the controller manager retries operations, including polling them and the orphan mitigation of failed provisions, before giving up")
+	c.Flags().IntVar(&ic.ControllerManagerLogLevel, "controller-manager-log-level", defaultControllerManagerLogLevel, "Log verbosity of the controller manager")
+	c.Flags().StringVar(&ic.OSBAPIVersion, "osb-api-version", defaultOSBAPIVersion, "Open Service Broker API version the controller manager sends to the brokers: 2.14, which asynchronous bindings require, or 2.13 for brokers and gateways only accepting it")
+	c.Flags().StringSliceVar(&ic.ControllerManagerHostAliases, "controller-manager-host-alias", nil, "/etc/hosts entry of the controller manager, as <hostname>=<ip>, e.g. broker.corp.example.com=10.0.0.5, for brokers the cluster DNS does not resolve")
+	c.Flags().StringVar(&ic.ControllerManagerDNSPolicy, "controller-manager-dns-policy", "", "DNS policy of the controller manager: ClusterFirst, Default (the resolver of the node) or None (only the --controller-manager-dns-* settings). Defaults to ClusterFirst")
+	c.Flags().StringSliceVar(&ic.ControllerManagerDNSNameservers, "controller-manager-dns-nameserver", nil, "IP of a nameserver the controller manager also queries, at most 3")
+	c.Flags().StringSliceVar(&ic.ControllerManagerDNSSearches, "controller-manager-dns-search", nil, "DNS search domain of the controller manager, at most 6")
+	c.Flags().StringSliceVar(&ic.ControllerManagerDNSOptions, "controller-manager-dns-option", nil, "Resolver option of the controller manager, as <name>[=<value>], e.g. ndots=2")
+	c.Flags().StringVar(&ic.ControllerManagerProxy, "controller-manager-proxy", "", "URL of the egress proxy the controller manager calls the brokers outside the cluster through, e.g. http://proxy.corp.example.com:3128")
+	c.Flags().StringSliceVar(&ic.ControllerManagerNoProxy, "controller-manager-no-proxy", nil, "Hosts, domains (.example.com) and CIDRs the controller manager calls directly, in addition to the Kubernetes API and the cluster services")
+	c.Flags().StringVar(&ic.ControllerManagerProxyCAFile, "controller-manager-proxy-ca-file", "", "PEM file of the CAs of the egress proxy, e.g. one inspecting TLS, trusted by the controller manager in addition to the public CAs")
+	c.Flags().StringVar(&ic.IPFamily, "ip-family", ipFamilyIPv4, "IP family of the cluster: ipv4, ipv6 (IPv6-only) or dual (dual-stack)")
+	c.Flags().StringVar(&ic.Mesh, "mesh", "", "Service mesh injecting sidecars into the namespace: istio annotates the pods to inject the sidecar into the api server and controller manager only, with the etcd and TLS ports bypassing it")
+	c.Flags().BoolVar(&ic.Apply.ServerSide, "server-side", false, "Apply the objects with server-side apply as field manager "+fieldManager+", merging with the fields set by autoscalers and admission mutators. Requires Kubernetes 1.16 or later")
+	c.Flags().BoolVar(&ic.Apply.ForceConflicts, "force-conflicts", false, "With --server-side, take over the fields owned by other field managers instead of failing")
+	c.Flags().StringVar(&ic.Version, "version", "", "Service Catalog version, defaults to the default version of --catalog-version")
+	c.Flags().StringVar(&ic.CatalogVersion, "catalog-version", "", "Template set of the Service Catalog minor version to install: "+strings.Join(catalogVersionNames(), ", ")+". Defaults to the one of --version, or "+catalogVersionSets[0].Name)
+	c.Flags().StringVar(&ic.KeyAlgorithm, "key-algorithm", "rsa", "Algorithm of the generated private keys: rsa or ecdsa")
+	c.Flags().BoolVar(&ic.FIPS, "fips", false, "Restrict generated keys and certificates to FIPS-approved algorithms and sizes (rsa 2048, 3072; ecdsa 256, 384) and deploy the FIPS variants of the images")
+	c.Flags().IntVar(&ic.KeySize, "key-size", 0, "Size in bits of generated RSA keys (2048, 3072, 4096) or curve size of ECDSA keys (256, 384, 521). Defaults to 2048 for rsa and 256 for ecdsa")
+	c.Flags().DurationVar(&ic.CertValidity, "cert-validity", defaultCertValidity, "Validity of the generated certificates")
+	c.Flags().BoolVar(&ic.StoreCAKey, "store-ca-key", false, "Store the CA, including its private key, in a secret and reuse it on later installs")
+	c.Flags().BoolVar(&ic.ReuseCerts, "reuse-certs", false, "Reuse the api server certificate of a previous install if it is valid for the api server service, signed by the CA of the APIService and not about to expire")
+	c.Flags().StringVar(&ic.KMSKey, "kms-key", "", "Cloud KMS key (projects/.../cryptoKeys/...) to encrypt the stored CA private key with. Requires --store-ca-key")
+}
+
+func installServiceCatalog(ic *InstallConfig) (err error) {
+	phases := tracePhases("install")
+	defer func() { phases.end(err) }()
+	phases.next("preflight")
+	if err := ic.Validate(); err != nil {
+		return err
+	}
+
+	if ic.FIPS && !boringCrypto {
+		fmt.Println("WARNING: " + messages.Sprintf(messages.NotBoringCrypto))
+	}
+
 	if err := checkDependencies(); err != nil {
 		return err
 	}
 
-	backupStorageClassExists, err := storageClassExists(ic.EtcdBackupStorageClass)
-	if err != nil {
+	if err := probeCluster("create", ic.Namespace); err != nil {
+		return err
+	}
+
+	detectCapabilities(ic)
+
+	if err := detectArchitectures(ic); err != nil {
 		return err
 	}
 
-	if !backupStorageClassExists {
-		return fmt.Errorf("storageclass for etcd backup does not exist. " +
-			"Use --etcd-backup-storageclass option to specify an existing storageclass")
+	if (ic.EtcdBackup || (ic.EtcdMaintenance && ic.EtcdMaintenanceBucket == "")) && !ic.SkipEtcd {
+		backupStorageClassExists, err := storageClassExists(ic.EtcdBackupStorageClass)
+		if err != nil {
+			return err
+		}
+
+		if !backupStorageClassExists {
+			return messages.Errorf(messages.StorageClassNotFound, ic.EtcdBackupStorageClass)
+		}
 	}
 
-	dir, err := generateDeploymentConfigs(ic)
+	phases.next("generate")
+	// The workspace holds the private keys of the certificates, it is only
+	// kept when it holds files handed over to the user: the manifests of a
+	// client dry run or the Secrets left out of the GitOps manifests.
+	handoff := ic.DryRun == dryRunClient || (ic.GitOps.isSet() && !ic.GitOps.IncludeSecrets)
+	ws, err := createWorkspace(ic.Namespace, "install", workspace.Options{Exclusive: true, Cleanup: workspace.Never, Sensitive: !handoff})
 	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+	dir := ws.Dir
+
+	// Only installs keep the data readable, uninstall deletes it.
+	if err := loadEncryptionKeys(ic); err != nil {
+		return err
+	}
+	if !ic.SkipEtcd && ic.MigratedEtcdServers == "" {
+		if ic.MigratedEtcdServers, err = installedEtcdServers(ic.Namespace); err != nil {
+			return err
+		}
+	}
+	if err := generateDeploymentConfigs(dir, ic); err != nil {
 		return fmt.Errorf("error generating YAML files: %v", err)
 	}
 
-	fmt.Printf("generated service catalog deployment config in dir: %s \n", dir)
+	if handoff {
+		fmt.Printf("generated service catalog deployment config in dir: %s \n", dir)
+	}
+	if err := injectFailure(ic.Apply.FailAt, phaseGenerate); err != nil {
+		return err
+	}
 
-	if ic.CleanupTempDirOnSuccess {
-		defer os.RemoveAll(dir)
+	if ic.ValidateManifests {
+		if err := validateManifests(dir); err != nil {
+			return err
+		}
 	}
 
-	if ic.DryRun {
+	if ic.DryRun == dryRunClient {
 		return nil
 	}
 
+	phases.next("checks")
+	// GitOps handoffs leave the apply, and the lock, to the tool.
+	if ic.DryRun == dryRunNone && !ic.GitOps.isSet() {
+		unlock, err := lockCluster(ic.Namespace, "install", ic.ForceUnlock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
 	err = isAPIServerCompatible()
 	if err != nil {
 		return err
 	}
 
-	err = deployConfig(dir)
+	if ic.Apply.ServerSide {
+		if err := checkServerSideApply(); err != nil {
+			return err
+		}
+	}
+
+	adopted, err := checkPreexistingCatalog(ic.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if ic.SkipRBAC {
+		if err := checkSkippedRBAC(dir); err != nil {
+			return err
+		}
+	}
+
+	if err := checkServiceAccounts(ic); err != nil {
+		return err
+	}
+
+	if err := checkConflicts(dir, ic.ForceAdopt && ic.DryRun == dryRunNone); err != nil {
+		return err
+	}
+
+	if ic.MaxInstancesPerNamespace > 0 || ic.PlanPolicyFile != "" || ic.BindingSecretPolicyFile != "" {
+		if err := checkGatekeeperInstalled(ic.MaxInstancesPerNamespace > 0, ic.BindingSecretPolicyFile != ""); err != nil {
+			return err
+		}
+	}
+
+	if ic.GitOps.isSet() {
+		phases.next("gitops")
+		return handOffGitOps(dir, ic)
+	}
+
+	if ic.DryRun == dryRunServer {
+		phases.next("dry-run")
+		if err := checkServerDryRun(); err != nil {
+			return err
+		}
+		return dryRunManifests(dir, ic.Apply)
+	}
+
+	if ic.ReadyTimeout > 0 {
+		stop, err := watchWarningEvents(ic.Namespace)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	// Record the applied manifests for sc rollback, failed or not.
+	defer func() { recordInstallRevision(ic, dir, err) }()
+	phases.next("deploy")
+	err = deployConfig(dir, ic.Apply)
 	if err != nil {
 		if strings.Contains(err.Error(), "\"etcd-operator\" is forbidden: attempt to grant extra privileges") {
-			fmt.Println("WARNING: Please run `kubectl create clusterrolebinding cluster-admin-binding --clusterrole=cluster-admin --user=$(gcloud config get-value account)` before `sc install`.")
+			fmt.Println("WARNING: " + messages.Sprintf(messages.ClusterAdminHint))
 		}
 
-		return fmt.Errorf("error deploying YAML files: %v", err)
+		return messages.Errorf(messages.DeployFailed, err)
+	}
+
+	if adopted != nil {
+		if err := replaceAdopted(dir, ic.Namespace, adopted); err != nil {
+			return err
+		}
 	}
 
 	// Delete the pods for the Service Catalog controller-manager and API
 	// server, to ensure that they have up-to-date certs (can get
 	// out-of-date during back-to-back `sc install`s)
+	phases.next("restart")
 	err = restartServiceCatalogPods(ic)
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	if ic.ReadyTimeout > 0 {
+		phases.next("wait-ready")
+		if err := waitForCatalogReady(ic.Namespace, ic.ReadyTimeout, !ic.SkipAPIRegistration); err != nil {
+			return err
+		}
+	}
+	phases.next("verify")
+	if ic.VerifyAs.isSet() {
+		if err := verifyAsIdentity(ic.VerifyAs); err != nil {
+			return err
+		}
+	}
 
-// generateDeploymentConfigs create configuration files for all the service
-// catalog resources in a temporary directory under /tmp. It returns absolute
-// path to the temporary directory containing the config.
-func generateDeploymentConfigs(ic *InstallConfig) (string, error) {
+	if ic.SkipAPIRegistration {
+		fmt.Printf("the APIService %s was not registered, register it with %s\n",
+			scAPIService, filepath.Join(dir, skippedDir, "api-registration.yaml"))
+	}
 
-	// create temporary directory for k8s artifacts and other temporary files
-	dir, err := ioutil.TempDir("/tmp", "service-catalog")
-	if err != nil {
-		return "", fmt.Errorf("error creating temporary dir: %v", err)
+	if ic.FIPS {
+		if err := checkFIPSImages(ic.Namespace); err != nil {
+			return err
+		}
 	}
 
+	return nil
+}
+
+// generateDeploymentConfigs create configuration files for all the service
+// catalog resources in dir, the workspace of the operation.
+func generateDeploymentConfigs(dir string, ic *InstallConfig) error {
 	sslArtifacts, err := generateSSLArtifacts(dir, ic)
 	if err != nil {
-		return dir, fmt.Errorf("error generating SSL artifacts : %v", err)
+		return fmt.Errorf("error generating SSL artifacts : %v", err)
 	}
 
 	ca, err := base64FileContent(sslArtifacts.CAFile)
 	if err != nil {
-		return dir, err
+		return err
 	}
 	apiServerCert, err := base64FileContent(sslArtifacts.APIServerCertFile)
 	if err != nil {
-		return dir, err
+		return err
 	}
 	apiServerPK, err := base64FileContent(sslArtifacts.APIServerPrivateKeyFile)
 	if err != nil {
-		return dir, err
+		return err
 	}
 
-	// TODO(mkibbe): Hard-code the default version of Service Catalog to a
-	// known good one for now. We cannot guarantee that the "latest"-tagged
-	// Service Catalog version is compatible with our templates. Later,
-	// flesh out the upgrade story to be able to dynamically install the
-	// latest version at an explicit versioned tag.
-	imageTag := "v0.1.11-gke.0"
-	if ic.Version != "" {
-		imageTag = "v" + ic.Version
+	var caPK string
+	if ic.StoreCAKey && sslArtifacts.CAPrivateKeyFile != "" {
+		caPK, err = base64FileContent(sslArtifacts.CAPrivateKeyFile)
+		if err != nil {
+			return err
+		}
 	}
-	svcCatalogImage := "gcr.io/gcp-services/service-catalog:" + imageTag
 
-	data := map[string]interface{}{
-		"CAPublicKey":            ca,
-		"APIServicePublicKey":    apiServerCert,
-		"APIServicePrivateKey":   apiServerPK,
-		"EtcdClusterSize":        ic.EtcdClusterSize,
-		"EtcdBackupStorageClass": ic.EtcdBackupStorageClass,
-		"ServiceCatalogImage":    svcCatalogImage,
-		"Version":                version.GetVersion(),
-	}
+	return renderServiceCatalog(dir, ic, map[string]string{
+		"CAPublicKey":          ca,
+		"CAPrivateKey":         caPK,
+		"APIServicePublicKey":  apiServerCert,
+		"APIServicePrivateKey": apiServerPK,
+	})
+}
 
-	for _, f := range svcCatalogFileNames {
-		err = generateFileFromTmpl(filepath.Join(dir, f.name+".yaml"), "templates/sc/"+f.name+".yaml.tmpl", data)
+// renderServiceCatalog renders the service catalog manifests into dir. certs
+// holds the base64 encoded CA and api server certificates and keys.
+func renderServiceCatalog(dir string, ic *InstallConfig, certs map[string]string) error {
+	data, err := catalogTemplateData(ic, certs)
+	if err != nil {
+		return err
+	}
+	for _, f := range svcCatalogFiles(ic) {
+		err := generateFileFromTmpl(filepath.Join(dir, f+".yaml"), "templates/sc/"+f+".yaml.tmpl", data)
 		if err != nil {
-			return dir, err
+			return err
 		}
 	}
-	return dir, nil
+	return renderSkipped(dir, ic, data)
+}
+
+// catalogTemplateData returns the data of the service catalog templates
+// rendering ic with certs.
+func catalogTemplateData(ic *InstallConfig, certs map[string]string) (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"Namespace":                   ic.Namespace,
+		"NamePrefix":                  ic.NamePrefix,
+		"NameSuffix":                  ic.NameSuffix,
+		"CAKMSKey":                    ic.KMSKey,
+		"EtcdClusterSize":             ic.EtcdClusterSize,
+		"EtcdBackup":                  ic.EtcdBackup,
+		"EtcdBackupStorageClass":      ic.EtcdBackupStorageClass,
+		"APIServerReplicas":           ic.APIServerReplicas,
+		"ControllerManagerReplicas":   ic.ControllerManagerReplicas,
+		"LeaderElect":                 ic.ControllerManagerReplicas > 1,
+		"PodDisruptionBudgets":        ic.PodDisruptionBudgets,
+		"Monitoring":                  ic.Monitoring,
+		"NetworkPolicies":             ic.NetworkPolicies,
+		"PriorityClass":               ic.PriorityClass,
+		"ServiceMonitor":              ic.ServiceMonitor,
+		"MaxInstancesPerNamespace":    ic.MaxInstancesPerNamespace,
+		"APIServerWatchCache":         !ic.APIServerDisableWatchCache,
+		"DefaultWatchCacheSize":       ic.APIServerDefaultWatchCacheSize,
+		"WatchCacheSizes":             strings.Join(ic.APIServerWatchCacheSizes, ","),
+		"BrokerRelistInterval":        ic.BrokerRelistInterval.String(),
+		"OSBAPITimeout":               ic.OSBAPITimeout.String(),
+		"ResyncInterval":              ic.ResyncInterval.String(),
+		"OperationPollingMaxBackoff":  ic.OperationPollingMaxBackoff.String(),
+		"ReconciliationRetryDuration": ic.ReconciliationRetryDuration.String(),
+		"ControllerManagerLogLevel":   ic.ControllerManagerLogLevel,
+		"OSBAPIVersion":               ic.OSBAPIVersion,
+		"IPFamily":                    ic.IPFamily,
+		"ListenAddress":               listenAddress(ic),
+		"EtcdServers":                 etcdServers(ic),
+		"SkipEtcd":                    ic.SkipEtcd,
+		"SkippedComponents":           strings.Join(skippedComponents(ic), ","),
+		"ServiceCatalogImage":         serviceCatalogImage(ic),
+		"EtcdOperatorImage":           etcdOperatorImage,
+		"EtcdClusterVersion":          etcdClusterVersion,
+		"EtcdImage":                   etcdImage,
+		"NodeArchitectures":           ic.NodeArchitectures,
+		"Version":                     version.GetVersion(),
+		"APIVersions":                 installAPIVersions(ic),
+	}
+	for k, v := range certs {
+		data[k] = v
+	}
+	for k, v := range pdbData(ic) {
+		data[k] = v
+	}
+	for k, v := range secretSyncData(ic) {
+		data[k] = v
+	}
+	for k, v := range etcdMaintenanceData(ic) {
+		data[k] = v
+	}
+	for k, v := range meshData(ic) {
+		data[k] = v
+	}
+	for k, v := range serviceAccountData(ic) {
+		data[k] = v
+	}
+	set, err := catalogVersionSetOf(ic)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range set.templateData() {
+		data[k] = v
+	}
+	hpa, err := hpaData(ic)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range hpa {
+		data[k] = v
+	}
+	dns, err := dnsData(ic)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range dns {
+		data[k] = v
+	}
+	proxy, err := proxyData(ic)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range proxy {
+		data[k] = v
+	}
+	encryption, err := encryptionData(ic)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range encryption {
+		data[k] = v
+	}
+	authn, err := authnData(ic)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range authn {
+		data[k] = v
+	}
+	if ic.APIServerRequestTimeout > 0 {
+		data["APIServerRequestTimeout"] = ic.APIServerRequestTimeout.String()
+	}
+	policy, err := planPolicyData(ic)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range policy {
+		data[k] = v
+	}
+	bindingPolicy, err := bindingSecretPolicyData(ic)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range bindingPolicy {
+		data[k] = v
+	}
+	rbac, err := rbacMappingData(ic)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range rbac {
+		data[k] = v
+	}
+
+	return data, nil
 }
 
-// this function assumes kubectl executable already exists in PATH.
-func deployConfig(dir string) error {
-	for _, f := range svcCatalogFileNames {
-		if f.dependsOnAPI != "" {
-			for {
-				available, err := isAPIAvailable(f.dependsOnAPI)
-				if err != nil {
-					return fmt.Errorf("failed to check API availability : %v", err)
-				}
-				if available {
-					break
-				}
+// deployConfig creates or updates all the objects found in the manifests in
+// dir, as configured by opts, ordered by kind. Before an object is created, deployConfig waits for its
+// API to be served, e.g. for a custom resource whose definition is
+// registered by an operator created earlier.
+// It assumes kubectl executable already exists in PATH.
+func deployConfig(dir string, opts applyOptions) error {
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		return err
+	}
+
+	fail := failureIndex(objs, opts.FailAt)
+	var available map[string]bool
+	for i, o := range objs {
+		if i == fail {
+			return injectedFailure(opts.FailAt)
+		}
+		for !available[o.APIVersion] {
+			if available != nil {
 				time.Sleep(2 * time.Second)
 			}
+			available, err = servedAPIVersions()
+			if err != nil {
+				return fmt.Errorf("failed to check API availability : %v", err)
+			}
 		}
-		output, err := exec.Command("kubectl", "apply", "-f", filepath.Join(dir, f.name+".yaml")).CombinedOutput()
-		// TODO(droot): cleanup
-		if err != nil {
-			return fmt.Errorf("deploy failed with output: %s :%v", err, string(output))
+
+		if err := applyObject(o, opts); err != nil {
+			return err
 		}
 	}
+	if fail == len(objs) {
+		return injectedFailure(opts.FailAt)
+	}
 	return nil
 }
 
+// kindRegistrationTimeout is how long applyObject retries objects whose kind
+// is not known yet, e.g. when a CustomResourceDefinition is created
+// asynchronously in an already served API group.
+const kindRegistrationTimeout = time.Minute
+
+// fieldManager is the field manager the installer applies objects as with
+// server-side apply.
+const fieldManager = "sc-installer"
+
+// applyOptions configures how kubectl applies objects.
+type applyOptions struct {
+	// ServerSide applies with server-side apply as fieldManager, so that
+	// fields set by others, e.g. the replicas set by an autoscaler or the
+	// defaults of admission mutators, are merged instead of overwritten.
+	ServerSide bool
+
+	// ForceConflicts takes over the fields owned by other field managers
+	// instead of failing with a conflict.
+	ForceConflicts bool
+
+	// FailAt injects a failure in the deploy at this phase of
+	// failPhases, for testing.
+	FailAt string
+}
+
+// args returns the kubectl apply arguments for opts.
+func (opts applyOptions) args() []string {
+	args := []string{"apply", "-f", "-"}
+	if opts.ServerSide {
+		args = append(args, "--server-side", "--field-manager="+fieldManager)
+		if opts.ForceConflicts {
+			args = append(args, "--force-conflicts")
+		}
+	}
+	return args
+}
+
+func applyObject(o *manifest.Object, opts applyOptions) error {
+	deadline := time.Now().Add(kindRegistrationTimeout)
+	for {
+		cmd := kubectlCommand(opts.args()...)
+		cmd.Stdin = bytes.NewReader(o.JSON)
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			return nil
+		}
+		if opts.ServerSide && strings.Contains(string(output), "Apply failed with") {
+			return messages.Errorf(messages.ApplyConflict, o, o.File, string(output))
+		}
+		if !strings.Contains(string(output), "no matches for kind") || !time.Now().Before(deadline) {
+			return fmt.Errorf("deploy of %s from %s failed with output: %s :%v", o, o.File, string(output), err)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
 // sslArtifacts contains SSL artifacts needed
 type sslArtifacts struct {
 	// CA related SSL files
-	CAFile           string
+	CAFile string
+	// CAPrivateKeyFile is encrypted with Cloud KMS if a KMS key is
+	// configured, and empty if the certificates are reused.
 	CAPrivateKeyFile string
 
 	// API Server related SSL files
@@ -273,16 +1086,138 @@ type sslArtifacts struct {
 	APIServerPrivateKeyFile string
 }
 
+// defaultCertValidity is the default validity of generated certificates,
+// five years.
+const defaultCertValidity = 43800 * time.Hour
+
+// defaultNamespace is the namespace Service Catalog is installed in without
+// a name prefix or suffix.
+const defaultNamespace = "service-catalog"
+
+// catalogNamespace returns the namespace Service Catalog is installed in
+// with the name prefix and suffix.
+func catalogNamespace(prefix, suffix string) string {
+	return prefix + defaultNamespace + suffix
+}
+
+// affixedNamespace returns the namespace Service Catalog is installed in
+// given the --namespace ns and the name prefix and suffix. Without affixes
+// it is ns, with them it is derived from them, and an explicit ns must
+// match.
+func affixedNamespace(ns, prefix, suffix string) (string, error) {
+	if prefix == "" && suffix == "" {
+		return ns, nil
+	}
+	affixed := catalogNamespace(prefix, suffix)
+	if ns != "" && ns != defaultNamespace && ns != affixed {
+		return "", fmt.Errorf("namespace %s conflicts with the name prefix %q and suffix %q, which install into %s", ns, prefix, suffix, affixed)
+	}
+	return affixed, nil
+}
+
+// applyNameAffixes derives the namespace of ic from its name prefix and
+// suffix.
+func applyNameAffixes(ic *InstallConfig) error {
+	ns, err := affixedNamespace(ic.Namespace, ic.NamePrefix, ic.NameSuffix)
+	if err != nil {
+		return err
+	}
+	ic.Namespace = ns
+	return nil
+}
+
+// addNameAffixFlags adds the flags of the name prefix and suffix of an
+// existing installation to c.
+func addNameAffixFlags(c *cobra.Command, prefix, suffix *string) {
+	c.Flags().StringVar(prefix, "name-prefix", "", "Name prefix Service Catalog was installed with")
+	c.Flags().StringVar(suffix, "name-suffix", "", "Name suffix Service Catalog was installed with")
+}
+
+// defaultReadyTimeout is how long install waits for the components to be
+// ready by default.
+const defaultReadyTimeout = 10 * time.Minute
+
+// Defaults of the controller manager options.
+const (
+	defaultBrokerRelistInterval      = 24 * time.Hour
+	defaultOSBAPITimeout             = 60 * time.Second
+	defaultResyncInterval            = 5 * time.Minute
+	defaultControllerManagerLogLevel = 10
+
+	// The controller manager backs off polling up to 20m by default, so
+	// that a CloudSQL instance provisioned in 10m is only seen ready
+	// long after. Cap the backoff lower.
+	defaultOperationPollingMaxBackoff  = 5 * time.Minute
+	defaultReconciliationRetryDuration = 7 * 24 * time.Hour
+)
+
+// supportedKeySizes lists the key sizes allowed for each key algorithm. The
+// first size is the default.
+var supportedKeySizes = map[string][]int{
+	"rsa":   {2048, 3072, 4096},
+	"ecdsa": {256, 384, 521},
+}
+
+// keySpec returns the key algorithm and size to use for generated keys,
+// applying defaults for unset values.
+func keySpec(ic *InstallConfig) (algo string, size int, err error) {
+	algo = ic.KeyAlgorithm
+	if algo == "" {
+		algo = "rsa"
+	}
+	sizes, ok := keySizes(ic)[algo]
+	if !ok {
+		return "", 0, fmt.Errorf("unsupported key algorithm %q, must be rsa or ecdsa", algo)
+	}
+
+	if ic.KeySize == 0 {
+		return algo, sizes[0], nil
+	}
+	for _, s := range sizes {
+		if ic.KeySize == s {
+			return algo, s, nil
+		}
+	}
+	if ic.FIPS {
+		return "", 0, fmt.Errorf("unsupported key size %d for %s keys with --fips, must be one of %v", ic.KeySize, algo, sizes)
+	}
+	return "", 0, fmt.Errorf("unsupported key size %d for %s keys, must be one of %v", ic.KeySize, algo, sizes)
+}
+
+// certValidity returns the validity of generated certificates.
+func certValidity(ic *InstallConfig) time.Duration {
+	if ic.CertValidity <= 0 {
+		return defaultCertValidity
+	}
+	return ic.CertValidity
+}
+
+// apiServerHosts returns the host names the api server certificate must be
+// valid for.
+func apiServerHosts(ic *InstallConfig) []string {
+	host := fmt.Sprintf("%s.%s", ic.APIServerServiceName, ic.Namespace)
+	return []string{host, host + ".svc"}
+}
+
 // generateCertConfig generates config files required for generating CA and
 // SSL certificates for API Server.
 func generateCertConfig(dir string, ic *InstallConfig) (caCSRFilepath, certConfigFilePath string, err error) {
-	host1 := fmt.Sprintf("%s.%s", ic.APIServerServiceName, ic.Namespace)
-	host2 := host1 + ".svc"
+	hosts := apiServerHosts(ic)
+	host1, host2 := hosts[0], hosts[1]
+
+	keyAlgo, keySize, err := keySpec(ic)
+	if err != nil {
+		return
+	}
 
 	data := map[string]interface{}{
 		"Host1":          host1,
 		"Host2":          host2,
 		"APIServiceName": ic.APIServerServiceName,
+		"KeyAlgorithm":   keyAlgo,
+		"KeySize":        keySize,
+		"IPSANs":         loopbackIPs(ic),
+		"CertValidity":   certValidity(ic).String(),
 	}
 
 	caCSRFilepath = filepath.Join(dir, "ca_csr.json")
@@ -300,6 +1235,28 @@ func generateCertConfig(dir string, ic *InstallConfig) (caCSRFilepath, certConfi
 }
 
 func generateSSLArtifacts(dir string, ic *InstallConfig) (result *sslArtifacts, err error) {
+	if ic.RestoredCertsDir != "" {
+		return restoredCerts(ic.RestoredCertsDir), nil
+	}
+	if ic.ReuseCerts {
+		// The CA private key isn't known, the stored CA, if any, is
+		// left as is.
+		result, err = reuseExistingCerts(dir, ic.Namespace, apiServerHosts(ic))
+		if err != nil {
+			return
+		}
+		if result != nil && ic.FIPS {
+			if fipsErr := checkFIPSCertFile(result.APIServerCertFile); fipsErr != nil {
+				fmt.Printf("not reusing the api server certificate in secret %s/%s: %v\n", ic.Namespace, apiServerCertSecretName, fipsErr)
+				result = nil
+			}
+		}
+		if result != nil {
+			fmt.Printf("reusing the api server certificate in secret %s/%s\n", ic.Namespace, apiServerCertSecretName)
+			return
+		}
+	}
+
 	csrInputJSON, certGenJSON, err := generateCertConfig(dir, ic)
 	if err != nil {
 		err = fmt.Errorf("error generating cert config :%v", err)
@@ -307,40 +1264,73 @@ func generateSSLArtifacts(dir string, ic *InstallConfig) (result *sslArtifacts,
 	}
 
 	certConfigFilePath := filepath.Join(dir, "ca_config.json")
-	err = generateFile("templates/sc/ca_config.json", certConfigFilePath)
+	err = generateFileFromTmpl(certConfigFilePath, "templates/sc/ca_config.json.tmpl", map[string]interface{}{
+		"CertValidity": certValidity(ic).String(),
+	})
 	if err != nil {
 		err = fmt.Errorf("error generating ca config: %v", err)
 		return
 	}
 
-	genKeyCmd := exec.Command("cfssl", "genkey", "--initca", csrInputJSON)
-
 	caFilePath := filepath.Join(dir, "ca")
-	cmd2 := exec.Command("cfssljson", "-bare", caFilePath)
+	if ic.KMSKey != "" {
+		// Only the encrypted CA private key may outlive signing.
+		defer os.Remove(caFilePath + "-key.pem")
+	}
 
-	out, outErr, err := Pipeline(genKeyCmd, cmd2)
-	if err != nil {
-		err = fmt.Errorf("error generating ca: stdout: %v stderr: %v err: %v", string(out), string(outErr), err)
-		return
+	restored := false
+	if ic.StoreCAKey {
+		restored, err = restoreStoredCA(ic.Namespace, caFilePath, ic.KMSKey)
+		if err != nil {
+			err = fmt.Errorf("error reading stored ca: %v", err)
+			return
+		}
+	}
+
+	if restored && ic.FIPS {
+		if err = checkFIPSCertFile(caFilePath + ".pem"); err != nil {
+			err = fmt.Errorf("the ca stored in secret %s/%s can't be used with --fips: %v", ic.Namespace, caSecretName, err)
+			return
+		}
+	}
+	if restored {
+		fmt.Printf("reusing the ca stored in secret %s/%s\n", ic.Namespace, caSecretName)
+	} else {
+		genKeyCmd := execx.Command("cfssl", "genkey", "--initca", csrInputJSON)
+		cmd2 := execx.Command("cfssljson", "-bare", caFilePath)
+
+		if _, pipeErr := execx.Run(getContext(), genKeyCmd, cmd2); pipeErr != nil {
+			err = fmt.Errorf("error generating ca: %v", pipeErr)
+			return
+		}
 	}
 
-	certGenCmd := exec.Command("cfssl", "gencert",
+	certGenCmd := execx.Command("cfssl", "gencert",
 		"-ca", caFilePath+".pem",
 		"-ca-key", caFilePath+"-key.pem",
 		"-config", certConfigFilePath, certGenJSON)
 
 	apiServerCertFilePath := filepath.Join(dir, "apiserver")
-	certSignCmd := exec.Command("cfssljson", "-bare", apiServerCertFilePath)
+	certSignCmd := execx.Command("cfssljson", "-bare", apiServerCertFilePath)
 
-	_, _, err = Pipeline(certGenCmd, certSignCmd)
+	_, err = execx.Run(getContext(), certGenCmd, certSignCmd)
 	if err != nil {
 		err = fmt.Errorf("error signing api server cert: %v", err)
 		return
 	}
 
+	caPrivateKeyFile := caFilePath + "-key.pem"
+	if ic.KMSKey != "" {
+		caPrivateKeyFile, err = encryptCAKey(ic.KMSKey, caPrivateKeyFile)
+		if err != nil {
+			err = fmt.Errorf("error encrypting ca private key: %v", err)
+			return
+		}
+	}
+
 	result = &sslArtifacts{
 		CAFile:                  caFilePath + ".pem",
-		CAPrivateKeyFile:        caFilePath + "-key.pem",
+		CAPrivateKeyFile:        caPrivateKeyFile,
 		APIServerPrivateKeyFile: apiServerCertFilePath + "-key.pem",
 		APIServerCertFile:       apiServerCertFilePath + ".pem",
 	}
@@ -348,11 +1338,7 @@ func generateSSLArtifacts(dir string, ic *InstallConfig) (result *sslArtifacts,
 }
 
 func generateFileFromTmpl(dst, src string, data map[string]interface{}) error {
-	b, err := Asset(src)
-	if err != nil {
-		return err
-	}
-	tp, err := template.New("").Parse(string(b))
+	tp, err := parseTemplate(src)
 	if err != nil {
 		return err
 	}
@@ -370,14 +1356,6 @@ func generateFileFromTmpl(dst, src string, data map[string]interface{}) error {
 	return nil
 }
 
-func generateFile(src, dst string) error {
-	b, err := Asset(src)
-	if err != nil {
-		return err
-	}
-	return ioutil.WriteFile(dst, b, 0644)
-}
-
 func base64FileContent(filePath string) (encoded string, err error) {
 	b, err := ioutil.ReadFile(filePath)
 	if err != nil {
@@ -387,21 +1365,149 @@ func base64FileContent(filePath string) (encoded string, err error) {
 	return
 }
 
+// deleteConfig deletes all the objects found in the manifests in dir in the
+// reverse order of their creation. It carries on past failures and returns
+// an error describing all of them.
 func deleteConfig(dir string) error {
-	// delete the service catalog artifacts in reverse order
-	for i := len(svcCatalogFileNames) - 1; i >= 0; i-- {
-		f := svcCatalogFileNames[i]
-		output, err := exec.Command("kubectl", "delete", "-f", filepath.Join(dir, f.name+".yaml")).CombinedOutput()
-		if err != nil {
-			fmt.Errorf("error deleting resources in file: %v :: %v", f.name, string(output))
-			// TODO(droot): ignore failures and continue with deleting
+	_, err := deleteObjects(dir)
+	return err
+}
+
+// Outcomes of deleting an object.
+const (
+	deleteDeleted  = "deleted"
+	deleteNotFound = "not found"
+	deleteSkipped  = "skipped"
+	deleteFailed   = "failed"
+)
+
+// deleteResult is the outcome of deleting an object.
+type deleteResult struct {
+	Object  *manifest.Object
+	Outcome string
+	Detail  string
+}
+
+// deleteObjects is deleteConfig returning the outcome of every object.
+func deleteObjects(dir string) ([]deleteResult, error) {
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := servedAPIVersions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check API availability : %v", err)
+	}
+
+	var results []deleteResult
+	var failed []string
+	for _, o := range manifest.DeleteOrder(objs) {
+		if !available[o.APIVersion] {
+			// Nothing of this kind can exist, e.g. the optional
+			// integration this object belongs to is not installed.
+			results = append(results, deleteResult{o, deleteSkipped, o.APIVersion + " is not served"})
 			continue
-			// return fmt.Errorf("deploy failed with output: %s :%v", err, output)
 		}
+		cmd := kubectlCommand("delete", "-f", "-", "--ignore-not-found")
+		cmd.Stdin = bytes.NewReader(o.JSON)
+		output, err := cmd.CombinedOutput()
+		out := strings.TrimSpace(string(output))
+		switch {
+		case err != nil:
+			failed = append(failed, fmt.Sprintf("%s: %s", o, out))
+			results = append(results, deleteResult{o, deleteFailed, out})
+		case out == "":
+			// --ignore-not-found prints nothing for missing objects.
+			results = append(results, deleteResult{o, deleteNotFound, ""})
+		default:
+			results = append(results, deleteResult{o, deleteDeleted, ""})
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("error deleting resources:\n  %s", strings.Join(failed, "\n  "))
+	}
+	return results, nil
+}
+
+// installationLabel labels the objects an install creates outside the
+// catalog namespace, e.g. the Roles of the secret sync or the roles and
+// bindings of the RBAC mapping, with the catalog namespace. Uninstall finds
+// them by the label, it doesn't know their namespaces or names.
+const installationLabel = "servicecatalog.k8s.io/installation"
+
+// installationKinds are the kinds of the objects labeled with
+// installationLabel but the ClusterRoles, the bindings first so that none is
+// left referencing a deleted role.
+var installationKinds = []string{"rolebindings", "clusterrolebindings", "roles"}
+
+// deleteInstallationObjects deletes the objects labeled as created by the
+// installation in namespace ns. The labeled ClusterRoles still referenced by
+// other bindings, e.g. the edit role bound by sc onboard-namespace, are kept
+// and unlabeled.
+func deleteInstallationObjects(ns string) error {
+	selector := installationLabel + "=" + ns
+	output, err := kubectlCommand("delete", strings.Join(installationKinds, ","), "--all-namespaces",
+		"-l", selector, "--ignore-not-found").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deleting the objects of the installation in other namespaces: %s", strings.TrimSpace(string(output)))
+	}
+
+	output, err = kubectlCommand("get", "clusterroles", "-l", selector,
+		"-o", "jsonpath={.items[*].metadata.name}").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error listing the cluster roles of the installation: %s", strings.TrimSpace(string(output)))
+	}
+	roles := strings.Fields(string(output))
+	if len(roles) == 0 {
+		return nil
+	}
+	bound, err := boundClusterRoles()
+	if err != nil {
+		return err
+	}
+	var unbound []string
+	for _, role := range roles {
+		if binding, ok := bound[role]; ok {
+			fmt.Printf("keeping ClusterRole %s, it is referenced by %s\n", role, binding)
+			if output, err := kubectlCommand("label", "clusterrole", role, installationLabel+"-").CombinedOutput(); err != nil {
+				return fmt.Errorf("error unlabeling ClusterRole %s: %s", role, strings.TrimSpace(string(output)))
+			}
+			continue
+		}
+		unbound = append(unbound, role)
+	}
+	if len(unbound) == 0 {
+		return nil
+	}
+	args := append([]string{"delete", "clusterroles"}, unbound...)
+	if output, err := kubectlCommand(append(args, "--ignore-not-found")...).CombinedOutput(); err != nil {
+		return fmt.Errorf("error deleting the cluster roles of the installation: %s", strings.TrimSpace(string(output)))
 	}
 	return nil
 }
 
+// boundClusterRoles returns the names of the ClusterRoles referenced by a
+// RoleBinding or ClusterRoleBinding, mapped to one of those bindings.
+func boundClusterRoles() (map[string]string, error) {
+	output, err := kubectlCommand("get", "rolebindings,clusterrolebindings", "--all-namespaces", "-o",
+		`jsonpath={range .items[*]}{.roleRef.kind} {.roleRef.name} {.kind}/{.metadata.name}{"\n"}{end}`).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing the role bindings: %s", strings.TrimSpace(string(output)))
+	}
+	bound := map[string]string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] != "ClusterRole" {
+			continue
+		}
+		if _, ok := bound[fields[1]]; !ok {
+			bound[fields[1]] = fields[2]
+		}
+	}
+	return bound, nil
+}
+
 func isServiceCatalogInstalled() (bool, error) {
 	scAPI := "servicecatalog.k8s.io"
 
@@ -417,7 +1523,7 @@ func isServiceCatalogInstalled() (bool, error) {
 // isAPIAvailable is a helper function to determine if an API is available in
 // given Kubernetes cluster.
 func isAPIAvailable(api string) (bool, error) {
-	out, err := exec.Command("kubectl", "api-versions").Output()
+	out, err := kubectlCommand("api-versions").Output()
 	if err != nil {
 		return false, err
 	}
@@ -425,62 +1531,222 @@ func isAPIAvailable(api string) (bool, error) {
 	return strings.Contains(string(out), api), nil
 }
 
+// validateManifests validates the YAML files in dir against the OpenAPI
+// schema of the cluster and returns an error listing all violations.
+func validateManifests(dir string) error {
+	output, err := kubectlCommand("get", "--raw", "/openapi/v2").Output()
+	if err != nil {
+		fmt.Printf("WARNING: skipping validation of the YAML files, error fetching the OpenAPI schema: %v\n", err)
+		return nil
+	}
+	schema, err := manifest.LoadSchema(output)
+	if err != nil {
+		return err
+	}
+
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var violations []string
+	for _, o := range objs {
+		for _, err := range schema.Validate(o) {
+			violations = append(violations, err.Error())
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("generated YAML files in %s are invalid:\n  %s", dir, strings.Join(violations, "\n  "))
+	}
+	return nil
+}
+
+// servedAPIVersions returns the set of API versions, in group/version form,
+// served by the Kubernetes cluster.
+func servedAPIVersions() (map[string]bool, error) {
+	out, err := kubectlCommand("api-versions").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]bool)
+	for _, v := range strings.Fields(string(out)) {
+		versions[v] = true
+	}
+	return versions, nil
+}
+
 func NewServiceCatalogUnInstallCmd() *cobra.Command {
+	var cascade, yes, forceUnlock bool
+	var strategy, reportDir, prefix, suffix string
 	c := &cobra.Command{
 		Use:   "uninstall",
 		Short: "uninstalls Service Catalog in Kubernetes cluster",
 		Long: `uninstalls Service Catalog in Kubernetes cluster.
-assumes kubectl is configured to connect to the Kubernetes cluster.`,
+assumes kubectl is configured to connect to the Kubernetes cluster.
+
+--strategy decides what happens to the ServiceInstances: safe fails if any
+would be orphaned, graceful deprovisions them through their brokers first,
+and fast deletes everything without contacting the brokers, leaving the
+resources they provisioned behind. Before deprovisioning, graceful lists the
+external resources the brokers will destroy, e.g. CloudSQL databases, and
+asks to confirm.`,
 		// Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ns := "service-catalog"
-			if err := uninstallServiceCatalog(ns); err != nil {
-				fmt.Println("Service Catalog could not be installed")
+			s, err := uninstallStrategy(strategy, cascade)
+			if err != nil {
+				return err
+			}
+			if err := uninstallServiceCatalog(prefix, suffix, s, yes, forceUnlock, reportDir); err != nil {
+				messages.Println(messages.UninstallFailed)
 				return err
 			}
 			return nil
 		},
 	}
+	c.Flags().StringVar(&strategy, "strategy", uninstallStrategySafe, "What to do with the ServiceInstances: safe fails if they would be orphaned, graceful deprovisions them first, fast deletes everything without contacting the brokers")
+	c.Flags().BoolVar(&cascade, "cascade", false, "Delete all ServiceInstances and their bindings before uninstalling, deprovisioning their resources")
+	c.Flags().MarkDeprecated("cascade", "use --strategy graceful instead")
+	c.Flags().BoolVar(&yes, "yes", false, "Deprovision without asking to confirm, with --strategy graceful")
+	c.Flags().StringVar(&reportDir, "report-dir", ".", "Directory to write the uninstall report to, as JSON and text. Empty skips the report")
+	addNameAffixFlags(c, &prefix, &suffix)
+	c.Flags().BoolVar(&forceUnlock, "force-unlock", false, "Take over the lock of the namespace held by another sc operation, e.g. a stale one, instead of failing")
 	return c
 }
 
-func uninstallServiceCatalog(ns string) error {
+func uninstallServiceCatalog(prefix, suffix, strategy string, yes, forceUnlock bool, reportDir string) (err error) {
+	ns := catalogNamespace(prefix, suffix)
+	phases := tracePhases("uninstall")
+	defer func() { phases.end(err) }()
+	phases.next("preflight")
+	r := newUninstallReport(ns, strategy)
+	if reportDir != "" {
+		defer func() {
+			r.finish(err)
+			if werr := writeUninstallReport(reportDir, r); werr != nil {
+				fmt.Printf("WARNING: error writing the uninstall report: %v\n", werr)
+			}
+		}()
+	}
+
 	if err := checkDependencies(); err != nil {
 		return err
 	}
 
-	ic := &InstallConfig{
-		Namespace: ns,
-		// Following fields are not used during installation, they are needed
-		// for generating the DeploymentConfigs.
-		EtcdClusterSize:        3,
-		EtcdBackupStorageClass: "standard",
+	if err := probeCluster("delete", ns); err != nil {
+		return err
 	}
 
-	dir, err := generateDeploymentConfigs(ic)
+	unlock, err := lockCluster(ns, "uninstall", forceUnlock)
 	if err != nil {
-		return fmt.Errorf("error generating YAML files: %v", err)
+		return err
+	}
+	defer unlock()
+
+	// The workspace holds the lock of the namespace while the instances
+	// are deprovisioned.
+	ws, err := createWorkspace(ns, "uninstall", workspace.Options{Exclusive: true, Cleanup: workspace.Always})
+	if err != nil {
+		return err
+	}
+	defer func() { ws.Done(err) }()
+
+	// Record the instances the fast strategy orphans before they are
+	// deleted.
+	phases.next("prepare")
+	if strategy == uninstallStrategyFast {
+		if err := r.detectOrphans(); err != nil {
+			return err
+		}
 	}
 
-	defer os.RemoveAll(dir)
+	if err := prepareUninstall(ns, strategy, yes, r); err != nil {
+		return err
+	}
+
+	if strategy != uninstallStrategyFast {
+		if err := r.detectOrphans(); err != nil {
+			return err
+		}
+	}
+
+	phases.next("generate")
+	ic := uninstallConfig(prefix, suffix)
+	// Leave the components managed separately alone.
+	skipped, err := installedSkippedComponents(ns)
+	if err != nil {
+		return err
+	}
+	setSkippedComponents(ic, skipped)
+	r.addSkippedComponents(skipped)
+
+	dir := ws.Dir
+	if err := generateDeploymentConfigs(dir, ic); err != nil {
+		return fmt.Errorf("error generating YAML files: %v", err)
+	}
 
 	// It might take a while to delete the configs, so we want
 	fmt.Println("deleting service catalog configs...")
-	err = deleteConfig(dir)
+	phases.next("delete")
+	results, err := deleteObjects(dir)
+	r.addDeleteResults(results)
 	if err != nil {
 		return fmt.Errorf("error undeploying YAML files: %v", err)
 	}
+	if err := deleteInstallationObjects(ns); err != nil {
+		return err
+	}
 
 	// Namespaces are deleted asynchronuously and we need to make sure the
 	// deletion is actually done before printing the success message.
-	waitOnNSDeletion()
+	phases.next("wait-namespace")
+	waitOnNSDeletion(ns)
 
-	fmt.Println("Service Catalog uninstalled successfully.")
+	messages.Println(messages.Uninstalled)
 	return nil
 }
 
-// waitOnNSDeletion keeps checking whether namespace "service-catalog" is deleted.
-func waitOnNSDeletion() {
+// uninstallConfig returns the configuration rendering every object an
+// install with the name prefix and suffix may have created.
+func uninstallConfig(prefix, suffix string) *InstallConfig {
+	return &InstallConfig{
+		Namespace:  catalogNamespace(prefix, suffix),
+		NamePrefix: prefix,
+		NameSuffix: suffix,
+		// Following fields are not used during installation, they are needed
+		// for generating the DeploymentConfigs.
+		EtcdClusterSize:             3,
+		EtcdBackup:                  true,
+		EtcdBackupStorageClass:      "standard",
+		APIServerReplicas:           1,
+		ControllerManagerReplicas:   1,
+		PDBMaxUnavailable:           defaultPDBMaxUnavailable,
+		BrokerRelistInterval:        defaultBrokerRelistInterval,
+		OSBAPITimeout:               defaultOSBAPITimeout,
+		ResyncInterval:              defaultResyncInterval,
+		OperationPollingMaxBackoff:  defaultOperationPollingMaxBackoff,
+		ReconciliationRetryDuration: defaultReconciliationRetryDuration,
+		IPFamily:                    ipFamilyIPv4,
+		OSBAPIVersion:               defaultOSBAPIVersion,
+		// Render the optional resources so that they are deleted too.
+		PodDisruptionBudgets:     true,
+		NetworkPolicies:          true,
+		PriorityClass:            true,
+		ServiceMonitor:           true,
+		SecretSync:               true,
+		SecretSyncBackend:        secretSyncGCPSecretManager,
+		SecretSyncMode:           secretSyncMirror,
+		EtcdMaintenance:          true,
+		EtcdMaintenanceSchedule:  defaultEtcdMaintenanceSchedule,
+		EtcdMaintenanceKeep:      defaultEtcdMaintenanceKeep,
+		MaxInstancesPerNamespace: 1,
+		PlanPolicy:               &planPolicy{},
+		BindingSecretPolicy:      &bindingSecretPolicy{},
+	}
+}
+
+// waitOnNSDeletion keeps checking whether namespace ns is deleted.
+func waitOnNSDeletion(ns string) {
 	baseDelay := 100 * time.Millisecond
 	maxDelay := 6 * time.Second
 	retries := 0
@@ -492,28 +1758,28 @@ func waitOnNSDeletion() {
 		}
 		time.Sleep(delay)
 
-		if _, err := exec.Command("kubectl", "get", "namespace", "service-catalog" /* Namespace for GCP broker*/).CombinedOutput(); err != nil {
+		if _, err := kubectlCommand("get", "namespace", ns).CombinedOutput(); err != nil {
 			// TODO(maqiuyujoyce): Check whether the error is a not found error.
 			return
 		}
 
 		retries++
 	}
-	return
 }
 
 func NewCheckDependenciesCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "check",
 		Short: "performs a dependency check",
-		Long: `This utility requires cfssl, gcloud, kubectl binaries to be 
-present in PATH. This command performs the dependency check.`,
+		Long: `This utility requires cfssl, kubectl binaries to be present in
+PATH. gcloud is optional, the GCP commands call the GCP APIs with the
+Application Default Credentials. This command performs the dependency check.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := checkDependencies(); err != nil {
-				fmt.Println("Dependency check failed")
+				messages.Println(messages.DependencyCheckFailed)
 				return err
 			}
-			fmt.Println("Dependency check passed. You are good to go.")
+			messages.Println(messages.DependenciesSatisfied)
 			return nil
 		},
 	}
@@ -524,18 +1790,24 @@ present in PATH. This command performs the dependency check.`,
 // TODO(droot): enhance it to perform connectivity check with Kubernetes Cluster
 // and user permissions etc.
 func checkDependencies() error {
-	requiredCmds := []string{GcloudBinaryName, KubectlBinaryName, CfsslBinaryName, CfssljsonBinaryName}
-
-	var missingCmds []string
-	for _, cmd := range requiredCmds {
-		_, err := exec.LookPath(cmd)
+	if c, ok := loadInClusterConfig(); ok {
+		// The API server is called with the in-cluster config of the
+		// pod, gcloud isn't needed to install Service Catalog. kubectl
+		// applies the manifests with the same service account.
+		fmt.Printf("running in-cluster as service account: %s\n", c.serviceAccount())
+		v, err := c.serverVersion()
 		if err != nil {
-			missingCmds = append(missingCmds, cmd)
+			return err
 		}
+		fmt.Printf("Kubernetes version: %s\n", v)
+		return checkCommands(KubectlBinaryName, CfsslBinaryName, CfssljsonBinaryName)
 	}
 
-	if len(missingCmds) > 0 {
-		return fmt.Errorf("%s commands not found in the PATH", strings.Join(missingCmds, ","))
+	if err := checkCommands(KubectlBinaryName, CfsslBinaryName, CfssljsonBinaryName); err != nil {
+		return err
+	}
+	if checkCommands(GcloudBinaryName) != nil {
+		return nil
 	}
 
 	// Also print out current account, project and zone information.
@@ -551,6 +1823,23 @@ func checkDependencies() error {
 	return nil
 }
 
+// checkCommands returns an error listing the commands not found in the PATH.
+func checkCommands(requiredCmds ...string) error {
+
+	var missingCmds []string
+	for _, cmd := range requiredCmds {
+		_, err := execx.LookPath(cmd)
+		if err != nil {
+			missingCmds = append(missingCmds, cmd)
+		}
+	}
+
+	if len(missingCmds) > 0 {
+		return messages.Errorf(messages.CommandsNotFound, strings.Join(missingCmds, ","))
+	}
+	return nil
+}
+
 func getValueFromConfigMap(section, property string, configs map[string]interface{}) string {
 	switch s := configs[section].(type) {
 	case map[string]interface{}:
@@ -567,7 +1856,7 @@ func getValueFromConfigMap(section, property string, configs map[string]interfac
 }
 
 func storageClassExists(name string) (bool, error) {
-	output, err := exec.Command(KubectlBinaryName, "get", "storageclass", name).CombinedOutput()
+	output, err := kubectlCommand("get", "storageclass", name).CombinedOutput()
 	if err != nil {
 		outputStr := string(output)
 		if strings.Contains(outputStr, "NotFound") {
@@ -589,13 +1878,30 @@ func isAPIServerCompatible() error {
 
 	ver17 := semver.MustParse("1.7.0")
 	if v.LessThan(ver17) {
-		return fmt.Errorf("Service Catalog requires Kubernetes v1.7+.")
+		return messages.Errorf(messages.KubernetesTooOld, v)
+	}
+	return nil
+}
+
+// checkServerSideApply returns an error if the cluster doesn't support
+// server-side apply, which is beta since Kubernetes 1.16.
+func checkServerSideApply() error {
+	v, err := getServerVersion()
+	if err != nil {
+		return err
+	}
+	if v.LessThan(semver.MustParse("1.16.0")) {
+		return messages.Errorf(messages.ServerSideApplyTooOld, v)
 	}
 	return nil
 }
 
 func getServerVersion() (*semver.Version, error) {
-	output, err := exec.Command(KubectlBinaryName, "version", "-o", "json").CombinedOutput()
+	if c, ok := loadInClusterConfig(); ok {
+		return c.serverVersion()
+	}
+
+	output, err := kubectlCommand("version", "-o", "json").CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("error fetching Kubernetes version :%v", string(output))
 	}
@@ -620,7 +1926,7 @@ type k8sVersion struct {
 }
 
 func restartServiceCatalogPods(ic *InstallConfig) error {
-	output, err := exec.Command(KubectlBinaryName, "delete", "pods", "-l", "app in (service-catalog-apiserver, service-catalog-controller-manager)", "--namespace", ic.Namespace).CombinedOutput()
+	output, err := kubectlCommand("delete", "pods", "-l", "app in (service-catalog-apiserver, service-catalog-controller-manager)", "--namespace", ic.Namespace).CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("error restarting Service Catalog pods: %v", string(output))
 	}
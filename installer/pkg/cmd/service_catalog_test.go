@@ -0,0 +1,136 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestApplyOptionsArgs tests that server-side apply uses the installer field
+// manager and only forces conflicts when asked to.
+func TestApplyOptionsArgs(t *testing.T) {
+	for _, tc := range []struct {
+		opts applyOptions
+		want []string
+	}{
+		{applyOptions{}, []string{"apply", "-f", "-"}},
+		{applyOptions{ServerSide: true}, []string{"apply", "-f", "-", "--server-side", "--field-manager=sc-installer"}},
+		{applyOptions{ServerSide: true, ForceConflicts: true}, []string{"apply", "-f", "-", "--server-side", "--field-manager=sc-installer", "--force-conflicts"}},
+	} {
+		if got := tc.opts.args(); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("Arguments for %+v do not match: got %v; want %v", tc.opts, got, tc.want)
+		}
+	}
+}
+
+// TestNameAffixes tests that the name prefix and suffix are added to the
+// namespace, the certificate hosts and the names of the cluster-scoped
+// objects and kube-system roles, except those whose names are fixed by their
+// API.
+func TestNameAffixes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "affixes")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ic := uninstallConfig("blue-", "-v2")
+	ic.APIServerServiceName = "service-catalog-api"
+	ic.Profile = defaultProfile
+	ic.DryRun = dryRunNone
+	ic.KeyAlgorithm = "rsa"
+	ic.CertValidity = defaultCertValidity
+	ic.ControllerManagerLogLevel = defaultControllerManagerLogLevel
+	ic.SecretSyncProject = "my-project"
+	ic.SecretSyncImage = "registry.example.com/service-catalog-secret-sync:v0.1.0"
+	ic.SecretSyncNamespaces = []string{"payments"}
+	if err := ic.Validate(); err != nil {
+		t.Fatalf("Unexpected error validating config: %v", err)
+	}
+	if got, want := apiServerHosts(ic), []string{"service-catalog-api.blue-service-catalog-v2", "service-catalog-api.blue-service-catalog-v2.svc"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Hosts do not match: got %v; want %v", got, want)
+	}
+
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+	objs, err := manifest.ParseDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing manifests: %v", err)
+	}
+	fixed := map[string]bool{
+		"APIService":         true,
+		"ConstraintTemplate": true,
+	}
+	for _, o := range objs {
+		switch {
+		case o.Kind == "Namespace":
+			if o.Name != "blue-service-catalog-v2" {
+				t.Fatalf("Namespace does not match: got %s; want blue-service-catalog-v2", o.Name)
+			}
+		case o.ClusterScoped() || o.Namespace == "kube-system":
+			if !fixed[o.Kind] && (!strings.HasPrefix(o.Name, "blue-") || !strings.HasSuffix(o.Name, "-v2")) {
+				t.Fatalf("Name of %s is not prefixed and suffixed", o)
+			}
+		case o.Namespace == "payments":
+			// The secret sync Roles of the namespaces of its secrets.
+			if !strings.HasPrefix(o.Name, "blue-") || !strings.HasSuffix(o.Name, "-v2") {
+				t.Fatalf("Name of %s is not prefixed and suffixed", o)
+			}
+		case o.Namespace != "blue-service-catalog-v2":
+			t.Fatalf("Namespace of %s does not match: got %s; want blue-service-catalog-v2", o, o.Namespace)
+		}
+	}
+}
+
+// TestAffixedNamespace tests that the name prefix and suffix determine the
+// namespace and that a conflicting --namespace is rejected.
+func TestAffixedNamespace(t *testing.T) {
+	testCases := []struct {
+		ns, prefix, suffix string
+		want               string
+		wantErr            bool
+	}{
+		{ns: defaultNamespace, want: defaultNamespace},
+		{ns: "catalog", want: "catalog"},
+		{ns: defaultNamespace, prefix: "blue-", want: "blue-service-catalog"},
+		{ns: "", suffix: "-v2", want: "service-catalog-v2"},
+		{ns: "blue-service-catalog-v2", prefix: "blue-", suffix: "-v2", want: "blue-service-catalog-v2"},
+		{ns: "catalog", prefix: "blue-", wantErr: true},
+	}
+	for _, tc := range testCases {
+		got, err := affixedNamespace(tc.ns, tc.prefix, tc.suffix)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("Expected an error for %+v, got namespace %s", tc, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error for %+v: %v", tc, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Namespace for %+v does not match: got %s; want %s", tc, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+)
+
+// Components that can be skipped because they are managed separately.
+const (
+	// componentEtcd is the etcd cluster, replaced by an external etcd.
+	componentEtcd = "etcd"
+
+	// componentRBAC are the roles and bindings of the service accounts,
+	// e.g. pre-provisioned by a security team.
+	componentRBAC = "rbac"
+
+	// componentAPIRegistration is the APIService registering Service
+	// Catalog with the main API server, e.g. registered manually.
+	componentAPIRegistration = "api-registration"
+)
+
+// skippedComponentsAnnotation is the annotation of the namespace recording
+// the components skipped by the install, so that uninstall leaves them
+// alone and verify-install reports them.
+const skippedComponentsAnnotation = "servicecatalog.k8s.io/sc-skipped-components"
+
+// skippedDir is the subdirectory of the deployment config dir the manifests
+// of skipped components are rendered into, for reference. They are not
+// deployed.
+const skippedDir = "skipped"
+
+// componentFiles are the templates of each component that can be skipped.
+var componentFiles = map[string][]string{
+	componentEtcd:            {"etcd-operator", "etcd-cluster-with-backup", "etcd-svc", "etcd", "etcd-maintenance"},
+	componentRBAC:            {"rbac", "secret-sync-rbac"},
+	componentAPIRegistration: {"api-registration"},
+}
+
+// skippedComponents returns the components ic skips.
+func skippedComponents(ic *InstallConfig) []string {
+	var components []string
+	for _, c := range []struct {
+		name string
+		skip bool
+	}{
+		{componentEtcd, ic.SkipEtcd},
+		{componentRBAC, ic.SkipRBAC},
+		{componentAPIRegistration, ic.SkipAPIRegistration},
+	} {
+		if c.skip {
+			components = append(components, c.name)
+		}
+	}
+	return components
+}
+
+// setSkippedComponents sets the skip settings of ic from the list of
+// skipped components.
+func setSkippedComponents(ic *InstallConfig, components []string) {
+	ic.SkipEtcd = containsString(components, componentEtcd)
+	ic.SkipRBAC = containsString(components, componentRBAC)
+	ic.SkipAPIRegistration = containsString(components, componentAPIRegistration)
+}
+
+// skipFiles returns files without the templates of the components ic
+// skips.
+func skipFiles(ic *InstallConfig, files []string) []string {
+	var skipped []string
+	for _, c := range skippedComponents(ic) {
+		skipped = append(skipped, componentFiles[c]...)
+	}
+	var result []string
+	for _, f := range files {
+		if !containsString(skipped, f) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// renderSkipped renders the manifests of the skipped RBAC and API
+// registration into the skippedDir subdirectory of dir, for the teams
+// managing them to review and apply. The external etcd is not rendered.
+func renderSkipped(dir string, ic *InstallConfig, data map[string]interface{}) error {
+	var files []string
+	for _, c := range skippedComponents(ic) {
+		if c != componentEtcd {
+			files = append(files, componentFiles[c]...)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, skippedDir), 0755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		err := generateFileFromTmpl(filepath.Join(dir, skippedDir, f+".yaml"), "templates/sc/"+f+".yaml.tmpl", data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSkippedRBAC returns an error listing the roles and bindings that the
+// install would have created with RBAC enabled and that do not exist, since
+// the service catalog pods cannot run without them.
+func checkSkippedRBAC(dir string) error {
+	objs, err := manifest.ParseDir(filepath.Join(dir, skippedDir))
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, o := range objs {
+		if o.File != "rbac.yaml" {
+			continue
+		}
+		args := []string{"get", strings.ToLower(o.Kind), o.Name, "-o", "name"}
+		if o.Namespace != "" {
+			args = append(args, "--namespace", o.Namespace)
+		}
+		output, err := kubectlCommand(args...).CombinedOutput()
+		if err == nil {
+			continue
+		}
+		if !strings.Contains(string(output), "NotFound") {
+			return fmt.Errorf("error getting %s: %s", o, strings.TrimSpace(string(output)))
+		}
+		missing = append(missing, o.String())
+	}
+	if len(missing) > 0 {
+		return messages.Errorf(messages.SkippedObjectsMissing, "--skip-rbac",
+			strings.Join(missing, ", "), filepath.Join(dir, skippedDir, "rbac.yaml"))
+	}
+	return nil
+}
+
+// installedSkippedComponents returns the components skipped by the install
+// into namespace ns, read from the namespace annotation.
+func installedSkippedComponents(ns string) ([]string, error) {
+	output, err := kubectlCommand("get", "namespace", ns, "--ignore-not-found",
+		"-o", "jsonpath={.metadata.annotations."+strings.Replace(skippedComponentsAnnotation, ".", `\.`, -1)+"}").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting namespace %s: %s", ns, strings.TrimSpace(string(output)))
+	}
+	value := strings.TrimSpace(string(output))
+	if value == "" {
+		return nil, nil
+	}
+	return strings.Split(value, ","), nil
+}
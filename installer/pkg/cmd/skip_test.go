@@ -0,0 +1,84 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRenderSkipped tests that the skipped components are not rendered for
+// deployment, that the manifests of the skipped RBAC and API registration
+// are rendered apart and that the namespace records the skipped components.
+func TestRenderSkipped(t *testing.T) {
+	dir, err := ioutil.TempDir("", "skip")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ic := validInstallConfig()
+	ic.SkipEtcd = true
+	ic.EtcdServers = "https://etcd.example.com:2379"
+	ic.SkipRBAC = true
+	ic.SkipAPIRegistration = true
+	if err := ic.Validate(); err != nil {
+		t.Fatalf("Unexpected error validating config: %v", err)
+	}
+	if err := renderServiceCatalog(dir, ic, nil); err != nil {
+		t.Fatalf("Unexpected error rendering manifests: %v", err)
+	}
+
+	for _, f := range []string{"etcd-operator", "etcd-cluster-with-backup", "rbac", "api-registration"} {
+		if _, err := os.Stat(filepath.Join(dir, f+".yaml")); !os.IsNotExist(err) {
+			t.Fatalf("Skipped manifest %s.yaml must not be rendered for deployment", f)
+		}
+	}
+	for _, f := range []string{"rbac", "api-registration"} {
+		if _, err := os.Stat(filepath.Join(dir, skippedDir, f+".yaml")); err != nil {
+			t.Fatalf("Skipped manifest %s.yaml must be rendered in %s: %v", f, skippedDir, err)
+		}
+	}
+
+	for file, want := range map[string]string{
+		"namespace.yaml":            skippedComponentsAnnotation + `: "etcd,rbac,api-registration"`,
+		"apiserver-deployment.yaml": ic.EtcdServers,
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("Unexpected error reading %s: %v", file, err)
+		}
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("%s does not contain %q:\n%s", file, want, b)
+		}
+	}
+}
+
+// TestSetSkippedComponents tests that the skipped components recorded by an
+// install configure a later uninstall the same way.
+func TestSetSkippedComponents(t *testing.T) {
+	ic := &InstallConfig{SkipRBAC: true, SkipAPIRegistration: true}
+	got := uninstallConfig("", "")
+	setSkippedComponents(got, skippedComponents(ic))
+	if got.SkipEtcd || !got.SkipRBAC || !got.SkipAPIRegistration {
+		t.Fatalf("Skipped components do not match: got etcd %v, rbac %v, api-registration %v; want false, true, true",
+			got.SkipEtcd, got.SkipRBAC, got.SkipAPIRegistration)
+	}
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/color"
+	"github.com/spf13/cobra"
+)
+
+// Overall health of an installation, with the exit code of
+// sc status --exit-code.
+const (
+	healthHealthy      = "healthy"
+	healthDegraded     = "degraded"
+	healthNotInstalled = "not installed"
+
+	exitCodeDegraded     = 2
+	exitCodeNotInstalled = 3
+)
+
+// statusConfig contains the status configuration.
+type statusConfig struct {
+	// Namespace Service Catalog is installed in.
+	Namespace string
+
+	// ExitCode encodes the health in the exit code: 0 healthy, 2
+	// degraded and 3 not installed.
+	ExitCode bool
+}
+
+// componentStatus is the readiness of a component of the catalog.
+type componentStatus struct {
+	Name    string
+	Ready   int32
+	Desired int32
+
+	// Missing is true if the component does not exist.
+	Missing bool
+}
+
+func (c componentStatus) String() string {
+	if c.Missing {
+		return c.Name + ": missing"
+	}
+	return fmt.Sprintf("%s: %d/%d ready", c.Name, c.Ready, c.Desired)
+}
+
+// colored returns c as a line colored by its readiness: green if ready, red
+// if missing and yellow while rolling out.
+func (c componentStatus) colored() string {
+	switch {
+	case c.Missing:
+		return color.Red(c.String())
+	case c.healthy():
+		return color.Green(c.String())
+	default:
+		return color.Yellow(c.String())
+	}
+}
+
+func (c componentStatus) healthy() bool {
+	return !c.Missing && c.Desired > 0 && c.Ready >= c.Desired
+}
+
+func NewStatusCmd() *cobra.Command {
+	sc := &statusConfig{}
+	c := &cobra.Command{
+		Use:   "status",
+		Short: "shows the health of a Service Catalog installation",
+		Long: `shows the readiness of the components of a Service Catalog
+installation, of its APIService, whether the caBundle of the APIService
+matches the api server certificate, of the reconcile loops of the
+controller manager, according to its metrics, the size, object counts and
+compaction of etcd, warning when its database is near its quota, the
+brokers, instances and bindings using deprecated API versions or fields, and
+whether it is healthy, degraded or not installed. With --exit-code the exit
+code encodes the health, 0 for healthy, 2 for degraded and 3 for not
+installed, for scripts and readiness gates. Errors reading the cluster exit with 1.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			health, err := showStatus(sc.Namespace)
+			if err != nil {
+				return err
+			}
+			if !sc.ExitCode {
+				return nil
+			}
+			switch health {
+			case healthDegraded:
+				return &ExitError{Code: exitCodeDegraded, Err: fmt.Errorf("Service Catalog is degraded")}
+			case healthNotInstalled:
+				return &ExitError{Code: exitCodeNotInstalled, Err: fmt.Errorf("Service Catalog is not installed")}
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVar(&sc.Namespace, "namespace", "service-catalog", "Namespace Service Catalog is installed in")
+	c.Flags().BoolVar(&sc.ExitCode, "exit-code", false, "Exit with 2 if the installation is degraded and 3 if it is not installed")
+	return c
+}
+
+// showStatus prints the status of the installation in namespace ns and
+// returns its health.
+func showStatus(ns string) (string, error) {
+	output, err := kubectlCommand("get", "namespace", ns, "--ignore-not-found", "-o", "name").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting namespace %s: %s", ns, strings.TrimSpace(string(output)))
+	}
+	nsExists := strings.TrimSpace(string(output)) != ""
+
+	info, err := getAPIServiceInfo()
+	if err != nil {
+		return "", err
+	}
+	if !nsExists && info == nil {
+		fmt.Printf("status: %s\n", coloredHealth(healthNotInstalled))
+		return healthNotInstalled, nil
+	}
+
+	var components []componentStatus
+	if nsExists {
+		if components, err = catalogComponents(ns); err != nil {
+			return "", err
+		}
+	}
+	health, lines := catalogHealth(components, info)
+	if nsExists && info != nil {
+		certs, err := getAPIServerCerts(ns)
+		if err != nil {
+			return "", err
+		}
+		if certs != nil {
+			if reason := certs.caBundleMismatch(); reason != "" {
+				lines = append(lines, "APIService caBundle: "+reason+", run sc rotate-certs")
+				health = healthDegraded
+			} else {
+				lines = append(lines, "APIService caBundle: matches the api server certificate")
+			}
+		}
+	}
+	if nsExists {
+		// The controller manager of a degraded installation may not
+		// serve metrics, they are only shown if it does.
+		m, err := controllerManagerMetrics(ns)
+		switch {
+		case err != nil:
+			lines = append(lines, "reconcile loops: metrics unavailable: "+err.Error())
+		case m.problem() != "":
+			lines = append(lines, "reconcile loops: "+m.String()+": "+m.problem())
+			health = healthDegraded
+		default:
+			lines = append(lines, "reconcile loops: "+m.String())
+		}
+	}
+	for _, c := range components {
+		if c.Name != "etcd" || c.Ready == 0 {
+			continue
+		}
+		u, err := getEtcdUsage(ns)
+		switch {
+		case err != nil:
+			lines = append(lines, "etcd usage unavailable: "+err.Error())
+		case u != nil:
+			usage, degraded := u.lines(defaultNearLimit)
+			lines = append(lines, usage...)
+			if degraded {
+				health = healthDegraded
+			}
+		}
+	}
+	if info != nil && apiServiceUnavailable(info) == "" {
+		// Deprecated API usage does not degrade the installation, but
+		// breaks upgrades to versions dropping these APIs.
+		findings, err := findDeprecations()
+		switch {
+		case err != nil:
+			lines = append(lines, "deprecated API usage unavailable: "+err.Error())
+		case len(findings) > 0:
+			for _, l := range deprecationReport(findings) {
+				lines = append(lines, color.Yellow(l))
+			}
+		default:
+			lines = append(lines, deprecationReport(nil)...)
+		}
+	}
+	for _, l := range lines {
+		fmt.Println(l)
+	}
+	fmt.Printf("status: %s\n", coloredHealth(health))
+	return health, nil
+}
+
+// coloredHealth returns health colored green if healthy, yellow if degraded
+// and red if not installed.
+func coloredHealth(health string) string {
+	switch health {
+	case healthHealthy:
+		return color.Green(health)
+	case healthDegraded:
+		return color.Yellow(health)
+	default:
+		return color.Red(health)
+	}
+}
+
+// catalogHealth returns the health of an installation with components and
+// APIService info, nil if it does not exist, and a line describing each of
+// them.
+func catalogHealth(components []componentStatus, info *apiServiceInfo) (string, []string) {
+	health := healthHealthy
+	var lines []string
+	for _, c := range components {
+		lines = append(lines, c.colored())
+		if !c.healthy() {
+			health = healthDegraded
+		}
+	}
+	switch {
+	case info == nil:
+		lines = append(lines, "APIService "+scAPIService+": missing")
+		health = healthDegraded
+	case apiServiceUnavailable(info) != "":
+		lines = append(lines, "APIService "+scAPIService+": unavailable: "+apiServiceUnavailable(info))
+		health = healthDegraded
+	default:
+		lines = append(lines, "APIService "+scAPIService+": available")
+	}
+	return health, lines
+}
+
+// catalogComponents returns the status of the api server, the controller
+// manager and, unless it is managed separately, etcd in namespace ns.
+func catalogComponents(ns string) ([]componentStatus, error) {
+	var components []componentStatus
+	for _, d := range []string{"apiserver", "controller-manager"} {
+		output, err := kubectlCommand("get", "deployment", d, "--namespace", ns,
+			"--ignore-not-found", "-o", "json").CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("error getting deployment %s: %s", d, strings.TrimSpace(string(output)))
+		}
+		c := componentStatus{Name: d}
+		if strings.TrimSpace(string(output)) == "" {
+			c.Missing = true
+		} else {
+			var ds deploymentStatus
+			if err := json.Unmarshal(output, &ds); err != nil {
+				return nil, fmt.Errorf("error unmarshalling deployment %s: %v", d, err)
+			}
+			c.Ready, c.Desired = ds.Status.ReadyReplicas, ds.Spec.Replicas
+		}
+		components = append(components, c)
+	}
+
+	skipped, err := installedSkippedComponents(ns)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range skipped {
+		if s == componentEtcd {
+			return components, nil
+		}
+	}
+	etcd, err := etcdStatus(ns)
+	if err != nil {
+		return nil, err
+	}
+	return append(components, etcd), nil
+}
+
+// etcdStatus returns the status of the members of the etcd of the api
+// server in namespace ns.
+func etcdStatus(ns string) (componentStatus, error) {
+	c := componentStatus{Name: "etcd"}
+	selector, err := catalogEtcdSelector(ns)
+	if err != nil {
+		return c, err
+	}
+	output, err := kubectlCommand("get", "pods", "--namespace", ns, "-l", selector, "-o", "json").CombinedOutput()
+	if err != nil {
+		return c, fmt.Errorf("error listing etcd pods: %s", strings.TrimSpace(string(output)))
+	}
+	var pods struct {
+		Items []struct {
+			Status struct {
+				Conditions []struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+				} `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return c, fmt.Errorf("error unmarshalling etcd pods: %v", err)
+	}
+	c.Desired = int32(len(pods.Items))
+	c.Missing = c.Desired == 0
+	for _, p := range pods.Items {
+		for _, cond := range p.Status.Conditions {
+			if cond.Type == "Ready" && cond.Status == "True" {
+				c.Ready++
+			}
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/color"
+)
+
+// TestCatalogHealth tests that an installation is healthy only if all its
+// components are ready and its APIService is available.
+func TestCatalogHealth(t *testing.T) {
+	apiService := func(available string) *apiServiceInfo {
+		var info apiServiceInfo
+		if err := json.Unmarshal([]byte(`{"status":{"conditions":[{"type":"Available","status":"`+available+`","reason":"FailedDiscoveryCheck"}]}}`), &info); err != nil {
+			t.Fatalf("Unexpected error unmarshalling APIService: %v", err)
+		}
+		return &info
+	}
+	ready := []componentStatus{{Name: "apiserver", Ready: 2, Desired: 2}, {Name: "controller-manager", Ready: 1, Desired: 1}}
+	defer color.SetEnabled(color.Enabled())
+	color.SetEnabled(false)
+
+	health, lines := catalogHealth(ready, apiService("True"))
+	if health != healthHealthy {
+		t.Fatalf("Health does not match: got %s; want %s", health, healthHealthy)
+	}
+	want := []string{"apiserver: 2/2 ready", "controller-manager: 1/1 ready", "APIService v1beta1.servicecatalog.k8s.io: available"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("Lines do not match: got %q; want %q", lines, want)
+	}
+
+	for _, tc := range []struct {
+		components []componentStatus
+		info       *apiServiceInfo
+	}{
+		{[]componentStatus{{Name: "apiserver", Ready: 1, Desired: 2}}, apiService("True")},
+		{[]componentStatus{{Name: "apiserver", Missing: true}}, apiService("True")},
+		// A paused deployment runs no replica.
+		{[]componentStatus{{Name: "controller-manager"}}, apiService("True")},
+		{ready, apiService("False")},
+		{ready, nil},
+	} {
+		if health, lines := catalogHealth(tc.components, tc.info); health != healthDegraded {
+			t.Fatalf("Health of %v does not match: got %s; want %s", lines, health, healthDegraded)
+		}
+	}
+
+	// On terminals, ready components are green, rolling out ones yellow
+	// and missing ones red.
+	color.SetEnabled(true)
+	_, lines = catalogHealth([]componentStatus{ready[0], {Name: "controller-manager", Ready: 0, Desired: 1}, {Name: "etcd", Missing: true}}, apiService("True"))
+	want = []string{color.Green("apiserver: 2/2 ready"), color.Yellow("controller-manager: 0/1 ready"), color.Red("etcd: missing")}
+	if !reflect.DeepEqual(lines[:3], want) {
+		t.Fatalf("Colored lines do not match: got %q; want %q", lines[:3], want)
+	}
+}
+
+// TestExitCode tests that ExitError sets the exit code and other errors
+// exit with 1.
+func TestExitCode(t *testing.T) {
+	if got := ExitCode(&ExitError{Code: exitCodeNotInstalled, Err: fmt.Errorf("not installed")}); got != 3 {
+		t.Fatalf("Exit code does not match: got %d; want 3", got)
+	}
+	if got := ExitCode(fmt.Errorf("failed")); got != 1 {
+		t.Fatalf("Exit code does not match: got %d; want 1", got)
+	}
+}
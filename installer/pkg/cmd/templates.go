@@ -1,21 +1,37 @@
 // Code generated by go-bindata.
 // sources:
+// templates/sc/affinity.tmpl
 // templates/sc/api-registration.yaml.tmpl
+// templates/sc/apiserver-authn-ca.yaml.tmpl
 // templates/sc/apiserver-deployment.yaml.tmpl
-// templates/sc/ca_config.json
+// templates/sc/apiserver-encryption-config.yaml.tmpl
+// templates/sc/binding-secret-policy.yaml.tmpl
+// templates/sc/ca-secret.yaml.tmpl
+// templates/sc/ca_config.json.tmpl
 // templates/sc/ca_csr.json.tmpl
+// templates/sc/catalog-rbac.yaml.tmpl
+// templates/sc/controller-manager-config.yaml.tmpl
 // templates/sc/controller-manager-deployment.yaml.tmpl
+// templates/sc/controller-manager-proxy-ca.yaml.tmpl
 // templates/sc/etcd-cluster-with-backup.yaml.tmpl
-// templates/sc/etcd-operator-deployment.yaml.tmpl
-// templates/sc/etcd-operator-rbac-binding.yaml.tmpl
-// templates/sc/etcd-operator-rbac.yaml.tmpl
-// templates/sc/etcd-operator-service-account.yaml.tmpl
+// templates/sc/etcd-maintenance.yaml.tmpl
+// templates/sc/etcd-migration.yaml.tmpl
+// templates/sc/etcd-operator.yaml.tmpl
 // templates/sc/etcd-svc.yaml.tmpl
 // templates/sc/etcd.yaml.tmpl
 // templates/sc/gencert_config.json.tmpl
+// templates/sc/hpa.yaml.tmpl
+// templates/sc/instance-quota.yaml.tmpl
 // templates/sc/namespace.yaml.tmpl
+// templates/sc/network-policy.yaml.tmpl
+// templates/sc/pdb.yaml.tmpl
+// templates/sc/plan-policy.yaml.tmpl
+// templates/sc/priority-class.yaml.tmpl
 // templates/sc/rbac.yaml.tmpl
+// templates/sc/secret-sync-rbac.yaml.tmpl
+// templates/sc/secret-sync.yaml.tmpl
 // templates/sc/service-accounts.yaml.tmpl
+// templates/sc/service-monitor.yaml.tmpl
 // templates/sc/service.yaml.tmpl
 // templates/sc/tls-cert-secret.yaml.tmpl
 // templates/gcp/gcp-broker.yaml.tmpl
@@ -26,6 +42,12 @@
 // templates/gcp/service-account-secret.yaml.tmpl
 // templates/gcp-deprecated/google-oauth-deployment.yaml.tmpl
 // templates/gcp-deprecated/service-account-secret.yaml.tmpl
+// templates/installer/bootstrap-job.yaml.tmpl
+// templates/installer/gitops-argocd.yaml.tmpl
+// templates/installer/gitops-flux.yaml.tmpl
+// templates/installer/loadtest-broker.yaml.tmpl
+// templates/onboard/namespace.yaml.tmpl
+// Gopkg.lock
 // DO NOT EDIT!
 
 package cmd
@@ -93,7 +115,27 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _templatesScApiRegistrationYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x93\x41\x6f\xe3\xb6\x13\xc5\xef\xfa\x14\x0f\xf1\xe5\xff\x07\x1c\xd9\xc9\xa5\x85\x7a\x72\xbc\x69\x2b\x24\xb5\x8d\xc8\xe9\x22\xa7\xc5\x98\x1a\xcb\x83\x50\x24\x4b\x52\xf6\x0a\x8b\xfd\xee\x05\x65\x39\xdd\x60\xdb\x53\xab\xe3\xcc\x9b\x99\x1f\xdf\x8c\x26\xff\xfa\xcb\x26\x58\x5a\xd7\x7b\x69\x0e\x11\xb7\xf3\x9b\x1f\xf0\x8b\xb5\x8d\x66\x94\x46\xe5\x59\x4a\x3f\x8a\x62\x13\xb8\x46\x67\x6a\xf6\x88\x07\xc6\xc2\x91\x3a\xf0\x25\x33\xc5\xef\xec\x83\x58\x83\xdb\x7c\x8e\xff\x25\xc1\xd5\x98\xba\xfa\xff\x4f\xd9\x04\xbd\xed\xd0\x52\x0f\x63\x23\xba\xc0\x88\x07\x09\xd8\x8b\x66\xf0\x67\xc5\x2e\x42\x0c\x94\x6d\x9d\x16\x32\x8a\x71\x92\x78\x18\xc6\x8c\x4d\xf2\x6c\x82\x97\xb1\x85\xdd\x45\x12\x03\x82\xb2\xae\x87\xdd\x7f\xab\x03\xc5\x01\x38\x7d\x87\x18\x5d\x28\x66\xb3\xd3\xe9\x94\xd3\x40\x9b\x5b\xdf\xcc\xf4\x59\x19\x66\x8f\xe5\xf2\x7e\x55\xdd\x5f\xdf\xe6\xf3\xa1\xe6\xd9\x68\x0e\x01\x9e\xff\xe8\xc4\x73\x8d\x5d\x0f\x72\x4e\x8b\xa2\x9d\x66\x68\x3a\xc1\x7a\x50\xe3\x99\x6b\x44\x9b\x80\x4f\x5e\xa2\x98\x66\x8a\x60\xf7\xf1\x44\x9e\xb3\x09\x6a\x09\xd1\xcb\xae\x8b\xef\xdc\xba\xe0\x49\x78\x27\xb0\x06\x64\x70\xb5\xa8\x50\x56\x57\xb8\x5b\x54\x65\x35\xcd\x26\xf8\x58\x6e\x7f\x5d\x3f\x6f\xf1\x71\xf1\xf4\xb4\x58\x6d\xcb\xfb\x0a\xeb\x27\x2c\xd7\xab\x0f\xe5\xb6\x5c\xaf\x2a\xac\x7f\xc6\x62\xf5\x82\x87\x72\xf5\x61\x0a\x96\x78\x60\x0f\xfe\xec\x7c\xe2\xb7\x1e\x92\x7c\xe4\x3a\x99\x56\x31\xbf\x03\xd8\xdb\x33\x50\x70\xac\x64\x2f\x0a\x9a\x4c\xd3\x51\xc3\x68\xec\x91\xbd\x11\xd3\xc0\xb1\x6f\x25\xa4\x6d\x06\x90\xa9\xb3\x09\xb4\xb4\x12\x29\x0e\x91\xef\x1e\x75\x3e\x91\x6d\xba\x89\x4d\x99\x9c\xf1\xdc\x48\x88\xec\x53\x71\xc2\xb2\xe1\x9b\x85\xb6\x24\x66\x46\x4d\xe3\xb9\xa1\x64\x41\xaa\x09\xec\x8f\xec\x13\xae\xa2\xbb\xce\xd4\x9a\xd1\x76\x21\x62\xc7\x20\x44\x6e\x9d\x1e\xa4\x47\xf2\x92\x76\x31\x1d\x1a\x8b\x09\xec\x53\xb8\xee\x0d\xb5\xa2\x48\xeb\xfe\x8c\xb2\x5c\x7c\xda\x3c\xdf\x3d\x96\xcb\x4f\x0f\xf7\x2f\x05\x94\x16\x36\x11\x8a\x7d\x4c\x2f\xa6\xc8\xa0\x2e\x1e\xac\x97\xd8\xc3\x75\x3b\x2d\x0a\xaf\xdc\xa7\xb3\x4c\x6f\x4d\x0e\xb5\x5d\xec\x48\x63\xfb\x58\x9d\xc1\x13\xf4\x14\xff\x44\x9d\xfd\x07\xbf\x20\x39\x19\xff\xa0\x02\xe4\xe4\x6c\xa1\x1f\x2c\xcf\x5f\x7f\x0c\xb9\xd8\xd9\xf1\x66\xc7\x91\x6e\xb2\x57\x31\x75\x91\x08\x2a\xf6\x47\x51\x9c\xb5\x1c\xa9\xa6\x48\x45\x06\x18\x6a\xb9\xc0\x28\xcd\xc3\x59\xa1\x28\x92\xb6\xcd\xd8\x28\x4b\xbb\x4f\xda\xc6\xdb\xce\x15\xf8\x7b\x11\x70\xbc\xf0\x5c\x06\x03\xce\xcb\x60\x5b\x81\xdb\xf9\xfc\xd2\x61\x33\x06\x7f\x13\x23\x6d\xd7\x0e\xb9\xf9\x5f\xf5\x9b\xb7\x9a\x9b\x14\x1d\xa7\xa5\xf9\x17\xda\x31\x74\x3d\x12\x5c\x93\x93\xb7\x6c\x70\xa4\xbe\x97\x64\x78\xbb\x94\x02\x5f\xbe\x20\x5f\x2e\x36\xc3\x22\x1f\xb8\xc7\xd7\xaf\xd9\x9f\x01\x00\x00\xff\xff\xc8\x63\x1a\x0e\x14\x05\x00\x00")
+var _templatesScAffinityTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x53\xc1\x4e\xdb\x40\x10\xbd\xf3\x15\x23\xc3\xa1\x95\x8c\x03\xa8\x07\x94\xaa\x07\x17\xd2\xd6\x02\x25\x12\x36\x20\x84\x38\xac\xed\xb1\xb3\xc2\xd9\x75\x77\xd7\x98\x28\xf2\xbf\x77\xc6\x76\xa2\x20\x84\x7a\xc0\x17\xcf\xce\xcc\xbe\x79\xf3\x66\xf6\xf0\xf0\xb3\xdf\xc1\x21\x5c\xe8\x7a\x6d\x64\xb9\x74\x70\x76\x72\x7a\x0e\xbf\xb5\x2e\x2b\x84\x48\x65\xc1\x01\x87\xaf\x65\x86\xca\x62\x0e\x8d\xca\xd1\x80\x5b\x22\x84\xb5\xc8\xe8\x37\x46\x7c\xb8\x43\x63\xa5\x56\x70\x16\x9c\xc0\x17\x4e\xf0\xc6\x90\xf7\xf5\x3b\x21\xac\x75\x03\x2b\xb1\x06\xa5\x1d\x34\x16\x09\x42\x5a\x28\x24\x15\xc1\xd7\x0c\x6b\x07\x52\x41\xa6\x57\x75\x25\x85\xca\x10\x5a\xe9\x96\x7d\x99\x11\x84\x68\xc0\xc3\x08\xa1\x53\x27\x28\x5b\x50\x7e\x4d\xa7\x62\x3f\x0f\x84\xeb\x09\xf3\xb7\x74\xae\xb6\xd3\xc9\xa4\x6d\xdb\x40\xf4\x6c\x03\x6d\xca\x49\x35\x64\xda\xc9\x75\x74\x31\x9b\xc7\xb3\x63\x62\xdc\xdf\xb9\x55\x15\x5a\x0b\x06\xff\x36\xd2\x50\xaf\xe9\x1a\x44\x4d\x84\x32\x91\x12\xcd\x4a\xb4\xa0\x0d\x88\xd2\x20\xc5\x9c\x66\xc2\xad\x91\x4e\xaa\xd2\x07\xab\x0b\xd7\x0a\x83\x84\x92\x4b\xeb\x8c\x4c\x1b\xf7\x46\xad\x2d\x3d\x6a\x7a\x3f\x81\xf4\x12\x0a\xbc\x30\x86\x28\xf6\xe0\x67\x18\x47\xb1\x4f\x18\xf7\x51\xf2\x67\x71\x9b\xc0\x7d\x78\x73\x13\xce\x93\x68\x16\xc3\xe2\x06\x2e\x16\xf3\xcb\x28\x89\x16\x73\x3a\xfd\x82\x70\xfe\x00\x57\xd1\xfc\xd2\x07\x24\xad\xa8\x0c\xbe\xd6\x86\xf9\x13\x49\xc9\x3a\x62\xce\xa2\xc5\x88\x6f\x08\x14\x7a\x20\x64\x6b\xcc\x64\x21\x33\xea\x4b\x95\x8d\x28\x11\x4a\xfd\x82\x46\x51\x3b\x50\xa3\x59\x49\xcb\xd3\xb4\x44\x2f\x27\x94\x4a\xae\xa4\x13\xae\xf7\xbc\x6b\x6a\x58\x91\x04\xa9\xa8\x70\x68\xc1\x2e\xc5\x28\x1f\x27\xb9\x9d\x9f\x27\xb5\x3d\x4c\xec\xb8\x59\xc2\x64\xcb\xb0\x28\xa4\x92\x6e\x3d\xed\x2f\x28\x9d\xd3\x18\x47\x97\x0f\x82\x38\x40\x51\xe9\x96\x66\x5f\xd7\xbd\xda\xe3\xc8\x6b\x9d\x5b\x1e\x04\xd9\xfd\xc4\xf9\xa2\xdd\x06\x19\x57\x3a\xcc\x5c\x43\xa2\x80\x74\xac\x7c\x26\xaa\x8a\x88\xf1\x6e\x05\x90\x8c\xa5\x08\xde\x20\x61\x3b\xda\x0f\x26\xdd\x43\x31\xc2\x73\x93\x92\x1e\x48\x64\x03\xa9\x27\x8c\x47\x5a\xa5\x58\x91\xc0\x3e\xa4\x48\x42\x22\x5c\xed\x72\xe0\x34\x38\xfd\xe6\xef\xb8\xa4\xe8\x44\xf0\x11\x42\xdf\xf9\xe7\x5f\xed\x66\x73\x0c\x39\x92\x4e\xf4\xd6\xf6\x65\xf4\xe0\xb8\xeb\x0e\x36\x1e\x77\xb7\xf3\x4d\x61\xe3\x6d\x37\xfb\xb2\x31\xa4\x63\xcc\x0d\x37\x15\x59\x51\xa9\xf4\xce\x3d\x7b\xc5\xac\xe1\x49\xf7\x57\x18\x23\xc6\x8a\x74\xd4\x26\xa1\xb5\xb0\xe4\x7d\xdc\x78\xbd\x5c\xb3\x61\xe1\x78\x29\x06\xef\x33\x72\x1d\xef\x7d\xdb\x9e\x0f\x9e\xa6\xad\x12\x84\xc2\x19\x91\x62\xcf\x8b\xa8\x1a\xe4\xab\x9b\xcd\x6e\x31\x86\x4e\xee\x86\x08\x04\xd0\x75\xdd\x53\xe7\xc3\x7f\x2a\x7e\xa0\xf6\x27\xcb\x3e\x75\x2c\x23\x89\x8c\x2a\x87\xd1\xdc\xd7\x7b\x9b\xce\x6a\x3f\x12\x98\xa1\x97\x84\x70\x24\x7d\x38\x12\x30\xfd\xd1\xc3\x90\x5b\x16\xe4\x23\xd3\xe7\x82\x03\x94\x47\x16\xe5\x0c\xc6\xe0\x7a\xda\xaf\xf4\x0f\x81\xb0\x4f\xd4\xd3\x05\x00\x00")
+
+func templatesScAffinityTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScAffinityTmpl,
+		"templates/sc/affinity.tmpl",
+	)
+}
+
+func templatesScAffinityTmpl() (*asset, error) {
+	bytes, err := templatesScAffinityTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/affinity.tmpl", size: 1491, mode: os.FileMode(420), modTime: time.Unix(1792032658, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScApiRegistrationYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x93\x41\x6f\xe3\xb6\x13\xc5\xef\xfa\x14\x0f\xf1\xe5\xff\x07\x1c\xd9\xc9\xa5\x85\x7b\x52\xbc\x69\x2b\x24\xb5\x8d\xc8\xe9\x22\xa7\xc5\x98\x1a\xcb\x83\x50\x24\x4b\x52\xf6\x0a\x8b\x7c\xf7\x82\xb2\xbc\xbb\x41\xdb\x4b\xab\x93\x38\x33\x1c\xfe\xf8\xde\x70\xf2\x9f\xbf\x6c\x82\xa5\x75\xbd\x97\xe6\x10\x71\x3b\xbf\xf9\x01\xbf\x58\xdb\x68\x46\x69\x54\x9e\x4d\xb2\x09\x1e\x45\xb1\x09\x5c\xa3\x33\x35\x7b\xc4\x03\xa3\x70\xa4\x0e\x7c\xc9\x4c\xf1\x3b\xfb\x20\xd6\xe0\x36\x9f\xe3\x7f\xa9\xe0\x6a\x4c\x5d\xfd\xff\xa7\x6c\x82\xde\x76\x68\xa9\x87\xb1\x11\x5d\x60\xc4\x83\x04\xec\x45\x33\xf8\xb3\x62\x17\x21\x06\xca\xb6\x4e\x0b\x19\xc5\x38\x49\x3c\x20\x7e\xeb\x9f\x67\x13\xbc\x8c\x2d\xec\x2e\x92\x18\x10\x94\x75\x3d\xec\xfe\xfb\x3a\x50\x1c\x80\x01\xe0\x10\xa3\x0b\x8b\xd9\xec\x74\x3a\xe5\x34\xd0\xe6\xd6\x37\x33\x7d\xae\x0c\xb3\xc7\x72\x79\xbf\xaa\xee\xaf\x6f\xf3\xf9\xb0\xe7\xd9\x68\x0e\x01\x9e\xff\xe8\xc4\x73\x8d\x5d\x0f\x72\x4e\x8b\xa2\x9d\x66\x68\x3a\xc1\x7a\x50\xe3\x99\x6b\x44\x9b\x80\x4f\x5e\xa2\x98\x66\x8a\x60\xf7\xf1\x44\x9e\xb3\x09\x6a\x09\xd1\xcb\xae\x8b\xef\xd4\xba\xe0\x49\x78\x57\x60\x0d\xc8\xe0\xaa\xa8\x50\x56\x57\xb8\x2b\xaa\xb2\x9a\x66\x13\x7c\x2c\xb7\xbf\xae\x9f\xb7\xf8\x58\x3c\x3d\x15\xab\x6d\x79\x5f\x61\xfd\x84\xe5\x7a\xf5\xa1\xdc\x96\xeb\x55\x85\xf5\xcf\x28\x56\x2f\x78\x28\x57\x1f\xa6\x60\x89\x07\xf6\xe0\xcf\xce\x27\x7e\xeb\x21\x49\x47\xae\x93\x68\x15\xf3\x3b\x80\xbd\x3d\xdb\x17\x1c\x2b\xd9\x8b\x82\x26\xd3\x74\xd4\x30\x1a\x7b\x64\x6f\xc4\x34\x70\xec\x5b\x09\xc9\xcd\x00\x32\x75\x36\x81\x96\x56\x22\xc5\x21\xf2\x97\x4b\x9d\x47\x64\x9b\x66\x62\x53\x26\x65\x3c\x37\x12\x22\xfb\xb4\x39\x61\xd9\xf0\x9d\xa1\x2d\x89\x99\x51\xd3\x78\x6e\x28\x69\x54\x6c\x4a\x04\xf6\x47\xf6\x09\x57\xd1\x5d\x67\x6a\xcd\x68\xbb\x10\xb1\x63\x10\x22\xb7\x4e\x0f\xa5\x47\xf2\x92\xbc\x98\x0e\x8d\xc5\x04\xf6\x29\x5c\xf7\x86\x5a\x51\xa4\x75\x7f\x46\x59\x16\x9f\x36\xcf\x77\x8f\xe5\xf2\xd3\xc3\xfd\xcb\x02\x4a\x0b\x9b\x08\xc5\x3e\xa6\x1b\x53\x64\x50\x17\x0f\xd6\x4b\xec\xe1\xba\x9d\x16\x85\x57\xee\xd3\x58\xa6\xbb\x26\x85\xda\x2e\x76\xa4\xb1\x7d\xac\xce\xe0\x09\x7a\x8a\x7f\xa2\xce\x2e\x0f\xe9\xdf\x7f\x19\x39\x19\x5f\xd0\x02\x5f\xbe\x20\x2f\x36\xe5\xb8\x0e\xe9\xff\x69\x90\xd4\x0f\x16\xe0\xed\x2d\x7b\x15\x53\x2f\x12\x45\xc5\xfe\x28\x8a\xb3\x96\x23\xd5\x14\x69\x91\x01\x86\x5a\x5e\xe0\x78\xb3\xe3\x48\x37\x79\x12\x57\x14\x2b\x8a\xa4\x6d\x93\xbf\xfe\x18\x72\xb1\x59\xf2\x3f\xd5\x36\xde\x76\x6e\x81\xbf\x2f\x02\x8e\x17\xa6\xb1\x5b\x06\x38\x2f\x83\x74\x0b\xdc\xce\xe7\x97\x0e\x9b\x31\xf8\x9b\x18\x69\xbb\x76\xc8\xcd\xbf\xed\xbf\xa4\x17\xb8\x49\xd1\xf1\xb4\x74\xfe\x85\x76\x0c\x5d\x8f\x04\xd7\xe4\xe4\x6b\x36\x38\x52\x7c\x96\x65\x75\x59\x26\x11\xf0\x75\x5c\xce\xc9\x65\xb1\x19\xdc\x7c\xe0\x1e\x6f\x6f\xd9\x9f\x03\x00\x87\x91\xd2\xfc\x19\x05\x00\x00")
 
 func templatesScApiRegistrationYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -108,12 +150,32 @@ func templatesScApiRegistrationYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/api-registration.yaml.tmpl", size: 1300, mode: os.FileMode(416), modTime: time.Unix(1509023713, 0)}
+	info := bindataFileInfo{name: "templates/sc/api-registration.yaml.tmpl", size: 1305, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScApiserverAuthnCaYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x53\xc1\x8e\xdb\x36\x10\xbd\xf3\x2b\x1e\x56\x97\x16\xb0\xb4\x9b\x3d\x15\xee\x49\x75\xb6\x8d\xd0\xd4\x06\x56\x9b\x06\x39\x8e\xa9\x91\x34\xa8\x4c\x32\x24\xb5\x5e\xc3\xf0\xbf\x17\x94\x6c\x64\x9d\xed\xa9\xe1\xc5\x16\xf9\xf8\xe6\xbd\x99\xc7\xec\x87\x97\xca\xb0\xb2\xee\xe0\xa5\xeb\x23\xee\xef\xde\xfd\x82\x3f\xac\xed\x06\x46\x65\x74\xa1\x32\x95\xe1\xa3\x68\x36\x81\x1b\x8c\xa6\x61\x8f\xd8\x33\x4a\x47\xba\xe7\xcb\xc9\x02\x7f\xb3\x0f\x62\x0d\xee\x8b\x3b\xfc\x94\x00\x37\xe7\xa3\x9b\x9f\x7f\x55\x19\x0e\x76\xc4\x8e\x0e\x30\x36\x62\x0c\x8c\xd8\x4b\x40\x2b\x03\x83\x5f\x34\xbb\x08\x31\xd0\x76\xe7\x06\x21\xa3\x19\x7b\x89\x3d\xe2\x37\xfe\x42\x65\xf8\x72\xa6\xb0\xdb\x48\x62\x40\xd0\xd6\x1d\x60\xdb\xd7\x38\x50\x9c\x04\x03\x40\x1f\xa3\x0b\xcb\xdb\xdb\xfd\x7e\x5f\xd0\xa4\xb6\xb0\xbe\xbb\x1d\x66\x64\xb8\xfd\x58\xad\x1e\xd6\xf5\x43\x7e\x5f\xdc\x4d\x77\x3e\x99\x81\x43\x80\xe7\xaf\xa3\x78\x6e\xb0\x3d\x80\x9c\x1b\x44\xd3\x76\x60\x0c\xb4\x87\xf5\xa0\xce\x33\x37\x88\x36\x09\xde\x7b\x89\x62\xba\x05\x82\x6d\xe3\x9e\x3c\xab\x0c\x8d\x84\xe8\x65\x3b\xc6\xab\x6e\x5d\xe4\x49\xb8\x02\x58\x03\x32\xb8\x29\x6b\x54\xf5\x0d\x7e\x2b\xeb\xaa\x5e\xa8\x0c\x9f\xab\xa7\x0f\x9b\x4f\x4f\xf8\x5c\x3e\x3e\x96\xeb\xa7\xea\xa1\xc6\xe6\x11\xab\xcd\xfa\x7d\xf5\x54\x6d\xd6\x35\x36\xbf\xa3\x5c\x7f\xc1\x9f\xd5\xfa\xfd\x02\x2c\xb1\x67\x0f\x7e\x71\x3e\xe9\xb7\x1e\x92\xfa\xc8\x4d\x6a\x5a\xcd\x7c\x25\xa0\xb5\xf3\xf8\x82\x63\x2d\xad\x68\x0c\x64\xba\x91\x3a\x46\x67\x9f\xd9\x1b\x31\x1d\x1c\xfb\x9d\x84\x34\xcd\x00\x32\x8d\xca\x30\xc8\x4e\x22\xc5\x69\xe7\x8d\xa9\x39\x22\xab\x12\xdb\xd1\x34\x03\x87\x89\x9f\x9c\x20\xb0\x7f\x66\x0f\x1a\x63\xcf\x26\x8a\xa6\xc8\x01\xad\xb7\x26\xe6\xce\xdb\x97\x43\x22\x87\x1e\x84\x4d\x54\x19\x34\xfb\x98\x14\x4d\xa8\x34\xff\x05\x3a\x79\x66\x33\x67\x21\xcf\xd3\x5c\x38\xc4\x9e\xa9\x61\x9f\xcf\xd7\x72\x4d\xf9\x14\xa2\x59\x66\xfe\xfd\xb6\x98\x10\x99\x9a\x14\x12\x9f\x7e\x5b\x6f\x77\x49\x9e\xca\xc0\x2f\x91\x4d\xf2\x98\x93\x93\x59\x69\xfe\x4a\x69\x8a\xf2\xca\x9a\x56\xba\xbf\xc8\x4d\x0e\x2f\xaf\xe5\x7f\x2f\x75\x3c\x42\x5a\x58\x8f\xe2\x71\xf6\xf2\x61\xf2\xb2\x2a\x51\xac\x26\xdd\xab\x12\xa7\x93\x22\x27\xe7\xc7\xb4\xc4\xf3\x3b\xf5\x8f\x98\x66\x89\x9a\xb5\xe7\xa8\xe2\xc1\xf1\x12\x1b\x47\x5f\x47\x56\x3b\x8e\xd4\x50\xa4\xa5\x02\x0c\xed\x78\x89\x6b\x2b\x26\xd7\x74\x3e\x0a\x8e\x34\x2f\x71\x3c\xa2\x58\x5f\x3e\x53\x2d\x60\xa0\x2d\x0f\x21\x51\x20\xc5\x7d\x39\x4d\x4d\x34\xe7\x9a\x22\x0d\xb6\xfb\xd6\x1e\x35\xd7\x3a\x1e\xf3\x64\xe3\x8d\x87\x89\xed\xbb\x21\x51\xa1\x7d\x9c\xcb\xfe\x07\x3c\x31\xb1\x69\x2e\x7f\xa5\xbd\xee\x03\xce\xd9\xb8\xa2\x79\x0d\xb8\xba\x0f\x36\x0d\x4e\x27\xf5\xef\x00\x08\x9e\x32\x9e\x0f\x05\x00\x00")
+
+func templatesScApiserverAuthnCaYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScApiserverAuthnCaYamlTmpl,
+		"templates/sc/apiserver-authn-ca.yaml.tmpl",
+	)
+}
+
+func templatesScApiserverAuthnCaYamlTmpl() (*asset, error) {
+	bytes, err := templatesScApiserverAuthnCaYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/apiserver-authn-ca.yaml.tmpl", size: 1295, mode: os.FileMode(420), modTime: time.Unix(1792005199, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScApiserverDeploymentYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x54\x41\x6f\xdb\x46\x13\xbd\xf3\x57\x0c\xa4\xcb\xf7\x01\xa6\x24\xcb\x41\xd3\xb2\x27\xd5\x4e\x13\x21\x8e\x24\x44\x4a\x83\x1c\x57\xcb\x11\x39\xf0\x72\x97\x9d\x1d\x4a\x66\x83\xfc\xf7\x62\x49\x49\x26\x6d\x27\x6d\x91\x43\xbb\x07\xc3\x98\x37\xfb\xf4\xe6\xf1\xed\x0c\xbf\xfb\x44\x43\xb8\x76\x65\xcd\x94\xe5\x02\xd3\xc9\xe5\x4b\x78\xed\x5c\x66\x10\xe6\x56\x8f\xa2\x00\xdf\x92\x46\xeb\x31\x85\xca\xa6\xc8\x20\x39\xc2\xac\x54\x3a\xc7\x13\x72\x01\xbf\x21\x7b\x72\x16\xa6\xa3\x09\xfc\x2f\x34\x0c\x8e\xd0\xe0\xff\x3f\x47\x43\xa8\x5d\x05\x85\xaa\xc1\x3a\x81\xca\x23\x48\x4e\x1e\x76\x64\x10\xf0\x5e\x63\x29\x40\x16\xb4\x2b\x4a\x43\xca\x6a\x84\x03\x49\xde\xfc\xcc\x91\x64\x14\x0d\xe1\xd3\x91\xc2\x6d\x45\x91\x05\x05\xda\x95\x35\xb8\x5d\xb7\x0f\x94\x34\x82\xc3\xc9\x45\x4a\x9f\x8c\xc7\x87\xc3\x61\xa4\x1a\xb5\x23\xc7\xd9\xd8\xb4\x9d\x7e\x7c\x3b\xbf\x7e\xb5\x58\xbf\x8a\xa7\xa3\x49\x73\xe7\x83\x35\xe8\x3d\x30\xfe\x5e\x11\x63\x0a\xdb\x1a\x54\x59\x1a\xd2\x6a\x6b\x10\x8c\x3a\x80\x63\x50\x19\x23\xa6\x20\x2e\x08\x3e\x30\x09\xd9\xec\x02\xbc\xdb\xc9\x41\x31\x46\x43\x48\xc9\x0b\xd3\xb6\x92\x9e\x5b\x27\x79\xe4\x7b\x0d\xce\x82\xb2\x30\x98\xad\x61\xbe\x1e\xc0\x2f\xb3\xf5\x7c\x7d\x11\x0d\xe1\xe3\x7c\xf3\x66\xf9\x61\x03\x1f\x67\xef\xdf\xcf\x16\x9b\xf9\xab\x35\x2c\xdf\xc3\xf5\x72\x71\x33\xdf\xcc\x97\x8b\x35\x2c\x7f\x85\xd9\xe2\x13\xbc\x9d\x2f\x6e\x2e\x00\x49\x72\x64\xc0\xfb\x92\x83\x7e\xc7\x40\xc1\x47\x4c\x83\x69\x6b\xc4\x9e\x80\x9d\x6b\x05\xf9\x12\x35\xed\x48\x83\x51\x36\xab\x54\x86\x90\xb9\x3d\xb2\x25\x9b\x41\x89\x5c\x90\x0f\x5f\xd3\x83\xb2\x69\x34\x04\x43\x05\x89\x92\xa6\xf2\x64\xa8\x36\x22\x37\x58\x1a\x57\x17\x68\xa5\xf9\x0d\x8f\xbc\x27\x8d\xa0\x95\x28\xe3\x32\x50\x25\x35\x35\xe4\x11\x6c\x0e\x0e\xb6\x64\x15\x13\x7a\x50\x8c\xc0\x95\x05\xb2\xd1\xb0\x4d\x45\x7a\x66\x4a\x9e\xa3\x69\x59\x82\x30\x40\xd1\xe9\x28\xfc\x0d\xbe\x72\x15\x18\x9a\xe0\xa8\x30\x82\x27\x2f\x41\xcd\xde\x99\xaa\x68\x45\x7e\xff\x4b\xb9\x23\x9b\x26\x9d\x59\x23\x55\xd2\x31\xf9\x09\xe0\xbd\xa0\x6d\x6c\x1b\xef\x2f\xb7\x28\xea\x32\x2a\x50\x54\xaa\x44\x25\x11\x80\x55\x05\x26\x0f\x13\x1c\x2b\xbe\x54\x1a\xcf\x83\xc6\xc7\x41\x23\x00\xa3\xb6\x68\x7c\xb8\x08\x21\x87\x4f\x5a\xe2\x07\xa6\xf0\x31\x43\x23\x63\x13\x57\x9f\xc0\x65\x04\xe0\xd1\xa0\x16\xc7\x2d\x45\xa1\x44\xe7\xb7\x1d\xce\xbf\x64\x05\x10\x2c\x4a\xa3\x04\x8f\x0c\x9d\x59\xc2\x31\x3d\xb2\xbf\x41\x07\x70\x12\xda\xfc\xdf\x76\xce\xb4\x76\x95\x95\x45\x63\xce\xe0\xdc\x3e\x38\x76\x69\x67\xc3\x6b\x47\x3e\xff\x50\xfc\x8c\x91\xed\xa1\x42\x65\x98\xc0\xe7\xcf\x30\x5a\xb7\xe4\xd7\xad\x8a\x79\x00\xe0\xcb\x97\x7e\xe7\xaa\x32\x66\xe5\x0c\xe9\x3a\x81\xf9\x6e\xe1\x64\xc5\xe8\xc3\x37\x3d\x75\x31\x7a\x57\xb1\xc6\xce\x8c\xd0\xac\x07\xf4\xd2\xab\x01\xe8\xb2\x4a\xe0\x72\x32\x29\x7a\xd5\x02\x0b\xc7\x75\x02\xd3\xc9\x3b\xea\x00\xcd\x6b\xfa\x47\x04\x57\x5d\x02\xc5\x59\xe7\x72\xfc\x8c\x11\x31\xc4\xb1\x4a\x8f\x6f\x38\x0e\x16\xb2\x33\x1d\x74\xf0\xb6\xda\x22\x5b\x14\xf4\x8b\x53\x04\x6f\x69\x87\xba\xd6\x06\x07\x3d\x1a\x8f\xba\x62\x8c\x4b\xc7\xd2\x25\xf8\xf1\xc5\x8b\xab\x47\x8d\xe2\x58\x65\x18\x4b\x5d\x62\x07\x08\x4f\xb4\xd7\x17\x0a\x71\xab\xd7\x77\x80\xb0\xac\x93\xf1\xb8\x41\xb5\xa9\xbc\x20\xc7\xda\x50\x58\x02\xd3\xab\x97\x3f\x75\x29\xf6\x5d\x21\x3f\x3c\xa8\x08\x1a\x7b\xc6\x9c\xb3\xb3\x72\x2c\x09\x04\xcd\x67\xb4\xdd\x0a\xef\x42\xf4\x7a\x77\x1e\x65\x2b\xd6\xd8\x19\x1c\xa0\x08\x17\x56\x4a\xf2\x04\xc6\x7b\xc5\x63\xae\xec\xf8\xee\x6c\x66\xfc\xf4\x15\x9f\x0e\xa3\x4a\x97\xd6\xd4\x09\x08\x57\x18\x75\xcb\x64\xd1\xfb\x15\xbb\x2d\x76\x23\x11\x0c\x79\x8d\xd2\x4f\x49\xf9\x74\x90\xa6\xdc\x0a\xca\x51\x19\xc9\xff\xe8\x41\x5e\xe7\x18\x26\x7a\xb3\xd9\xac\xd6\x1d\x64\xa7\xc8\x54\x8c\x9b\x9c\xd1\xe7\xce\xa4\xed\xca\x38\x1d\xb2\x24\xa4\xcc\x0d\x1a\x55\xaf\x51\x3b\x9b\x86\x9d\x32\xe9\x74\x94\xc8\xe4\xd2\xe7\x31\x5f\x69\x8d\xde\x7f\x85\x5b\xa8\x40\x57\xc9\xf9\xea\xf4\x8c\x19\xda\xe3\x7f\xc3\x8b\xab\x7f\xd9\x8b\x36\x9d\x5f\x5f\x79\xfd\x58\x7a\xd4\xdc\x77\xa7\xad\x2c\xbe\x9d\x65\x12\x2c\x7a\x5b\x28\x86\x3b\x0c\x01\x35\x7e\xa4\x7b\x9d\x27\x57\xcf\x54\x8f\xf0\xce\xc5\x3b\xac\xbf\x79\x31\xe0\xd1\x9f\x01\x00\x00\xff\xff\xba\x8a\x0f\x08\xb3\x0a\x00\x00")
+var _templatesScApiserverDeploymentYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x58\x4b\x73\x22\x39\x12\xbe\xfb\x57\x28\xca\x87\xd9\x8d\x40\xe0\xe9\xee\xd9\x99\x60\xa3\x0f\x0c\xee\x07\xd1\x36\x26\x0c\x3d\x1d\x73\x14\x55\x09\x68\x2d\xa4\x1a\x49\x65\xcc\x38\xfa\xbf\x6f\xa6\x54\x14\x55\x50\xd8\xed\xe9\xdd\xd8\xe5\x60\x1b\x65\xea\xd3\x97\xa9\x7c\xc9\xe7\xe7\xdf\xfb\x39\x3b\x67\x43\x93\x6f\xad\x5c\xae\x3c\x7b\x75\xf1\xe3\xcf\xec\x83\x31\x4b\x05\x6c\xa4\xd3\xee\x19\x89\xaf\x64\x0a\xda\x41\xc6\x0a\x9d\x81\x65\x7e\x05\x6c\x90\x8b\x14\x7f\x95\x92\x0e\xfb\x0d\xac\x93\x46\xb3\x57\xdd\x0b\xf6\x37\x52\x48\x4a\x51\xf2\xf7\x7f\x22\xc2\xd6\x14\x6c\x2d\xb6\x4c\x1b\xcf\x0a\x07\x08\x21\x1d\x5b\x48\x3c\x04\x1e\x52\xc8\x3d\x93\x9a\xa5\x66\x9d\x2b\x29\x74\x0a\x6c\x23\xfd\x2a\x1c\x53\x82\x20\x0d\xf6\x7b\x09\x61\xe6\x5e\xa0\xb6\x40\xfd\x1c\xbf\x2d\xea\x7a\x4c\xf8\x40\x98\x3e\x2b\xef\x73\xd7\xef\xf5\x36\x9b\x4d\x57\x04\xb6\x5d\x63\x97\x3d\x15\x35\x5d\xef\x6a\x34\x7c\x37\x9e\xbe\xe3\xc8\x38\xec\xf9\xac\x15\x38\xc7\x2c\xfc\x51\x48\x8b\xb6\xce\xb7\x4c\xe4\x48\x28\x15\x73\xa4\xa9\xc4\x86\x19\xcb\xc4\xd2\x02\xca\xbc\x21\xc2\x1b\x2b\xbd\xd4\xcb\x0e\x73\x66\xe1\x37\xc2\x02\xa2\x64\xd2\x79\x2b\xe7\x85\x6f\x78\x6b\x47\x0f\x8d\xae\x2b\xa0\xbf\x84\x66\xc9\x60\xca\x46\xd3\x84\xfd\x3a\x98\x8e\xa6\x1d\xc4\xf8\x32\x9a\x7d\xbc\xf9\x3c\x63\x5f\x06\xb7\xb7\x83\xf1\x6c\xf4\x6e\xca\x6e\x6e\xd9\xf0\x66\x7c\x39\x9a\x8d\x6e\xc6\xf8\xed\x3d\x1b\x8c\x7f\x67\x9f\x46\xe3\xcb\x0e\x03\xf4\x15\x1e\x03\x0f\xb9\x25\xfe\x48\x52\x92\x1f\x21\x23\xa7\x4d\x01\x1a\x04\x16\x26\x12\x72\x39\xa4\x72\x21\x53\xb4\x4b\x2f\x0b\xb1\x04\xb6\x34\xf7\x60\x35\x9a\xc3\x72\xb0\x6b\xe9\xe8\x36\x1d\xd2\xcb\x10\x45\xc9\xb5\xf4\xc2\x87\x95\x23\xa3\x62\x88\x5c\x42\xae\xcc\x76\x0d\xda\x87\x33\x1c\xd8\x7b\x14\xb3\x54\x78\xa1\xcc\x12\x3d\x29\xc3\x1a\xd8\x2e\x9b\x6d\x0c\x9b\x4b\x2d\xac\x04\x3c\xc0\x02\xb3\x85\x46\x77\x22\x48\x88\x8a\xac\x42\xea\xb7\xc1\x44\x14\x22\xc6\xc0\xa7\x59\x97\x7e\x92\x5f\x11\x04\x11\x42\xe0\x08\x32\xc1\xa1\x9f\x89\xcd\xbd\x51\xc5\x3a\x92\xfc\xfe\x4c\xb9\x93\x3a\xeb\xd7\x6c\x3d\x43\x42\x65\xe4\xf7\xd9\xe3\x23\xeb\x0e\x26\xa3\xf2\xbb\xeb\xd6\x5c\xf2\xf5\xeb\xd9\x1a\xbc\xc8\xd0\x8c\xfe\x19\x63\x5a\xac\xa1\xbf\x37\xa6\x5c\x71\x18\xa4\x10\x61\xc6\xbb\xaf\xb4\x93\xe1\x25\xcd\x41\x39\xda\xc9\x28\x26\x2b\xbf\xf0\xd2\x2f\x7c\x0f\x45\x17\x4b\x8a\x18\x45\xe4\x0a\xce\x41\x53\xfc\xee\x35\xf8\x2a\x17\xe1\xfa\x44\xe1\x8d\x4b\x85\x42\x67\x9a\x0d\x5e\x2c\xad\x59\x08\x01\x8f\xa9\x55\x68\xdf\x3d\x7b\x7c\xe4\x4c\x2e\x42\xd2\x92\x65\xd3\x00\xf0\x71\x32\x88\xac\x4a\x65\x57\x59\x1e\xe5\xb7\xe5\x32\x29\x11\x00\xe0\x4d\x05\x7d\x07\x0a\x52\x6f\x6c\xb4\x63\x2d\x7c\xba\xba\xaa\x19\xf6\xac\x69\x8c\x79\xc0\xc8\x16\x1e\x4a\x84\x9a\x47\xe9\xa3\x1a\x60\xcf\xc2\x95\xc6\x75\x47\x0e\x03\x3b\x32\x0c\xbb\x34\x9a\x1b\x63\x7d\x0f\xe5\x64\x06\xa9\xb0\x5d\x49\xba\x5d\x69\x7a\x52\xff\x0b\x6d\xe9\xb3\xc4\xdb\x02\x92\x4a\xef\x9c\xcd\xd0\x8b\x77\xc5\xbc\xe6\xf0\x0e\x33\x85\x27\x80\xe0\x61\xbc\xd7\x55\x07\xe3\x59\xa9\xe8\xf1\x7d\x62\x84\xd8\xad\x21\x91\x74\x76\x35\xdd\x55\xb8\xd2\xbf\x68\x4b\xa7\x0a\x7e\x0a\x7a\x17\xf6\xe1\x11\x18\xf6\x3b\x9a\x15\x8a\xb7\x62\x81\x59\xde\x3d\xe2\x8f\x55\x57\x15\x19\x8c\xf4\x1c\x6f\x3a\x9b\x18\xeb\xf1\x1a\x93\x5f\xde\xbc\x79\x9d\x54\x8e\xb9\x46\xaa\xef\xf0\x94\x20\xdd\x3b\xe8\x79\xd4\x9b\xc2\x37\x60\x29\x3c\x0e\xc1\x92\x66\x6c\xec\x8c\x9e\x7a\x61\x3d\xd6\xc5\x34\x7a\xab\x3c\x81\xe5\xd6\x3c\x50\xb5\xa0\xb5\xd2\x77\x26\x7c\xf9\x84\xae\xb6\x1a\x3c\xca\xd0\x41\x7b\xc3\x69\xc3\x76\x4f\x2c\x35\x7a\x21\x97\x7d\xf6\xc3\x63\xb2\x32\x2a\x1b\xc4\xba\x4e\x97\xfc\x59\x7b\xa9\x26\xa4\x1d\x8e\x76\x49\x9f\xd1\x95\x7e\xfd\xe1\x90\xdf\x2e\xb1\xc2\xdf\xf1\x22\x06\x69\xc8\x93\x71\xc8\xe6\xa4\x91\x04\xd3\x86\x46\x65\x2f\xb9\x75\x62\xa5\xc1\xc6\xb1\x1d\x2a\xe1\x6a\x6e\xcd\xeb\xcb\x11\x72\x57\x09\x26\x16\x16\xf2\x01\x55\x0f\x82\x79\x27\x9f\x16\x8b\x28\xaf\x73\xde\x1d\x37\x36\x19\x0c\x6c\xba\x92\x1e\x03\xb6\xc0\x16\x51\x0b\x75\xdc\xa6\xf1\xc8\x70\xd2\x2e\xb7\x58\x22\x50\x7b\x50\x8a\x92\x13\x00\x47\x97\x87\x1e\xa6\xb6\x8c\xb5\x6f\xe7\x24\xde\x52\xe6\xe2\x47\xae\xb1\xe1\x44\xf3\x4a\x3f\x0d\xa3\x41\x23\x12\xd4\x03\x22\x68\x4e\x0a\xa5\x26\x06\x2f\x0c\x89\x8e\x16\x63\xe3\xd1\x1f\x8e\x8a\xef\x4e\x0b\xbf\x9a\xc2\xa6\x50\x4b\x58\x16\xfa\x38\x38\xdf\x58\x43\x9a\x79\xd1\x67\x3f\x5e\x5c\xac\x1b\xab\x6b\x58\x1b\x8b\xe8\xaf\x2e\xae\x65\x4d\x10\xda\xde\x8b\x00\x5e\xd7\x01\x84\x5d\xd6\x36\xf3\x16\x47\xf0\x58\x39\xb3\xb2\xdd\xbe\x57\x62\x59\xb7\x9e\x97\x41\xb5\x93\x4f\x54\xb1\x94\x9a\xfc\x8f\xcb\x74\xb7\xbf\x62\x3b\xc2\x8e\x3d\x85\xd4\x82\x8f\x2e\x42\x61\xe7\xba\xa0\x02\xa6\x97\xd5\xc6\x2f\x30\x5f\x19\x73\x87\xbb\xe2\x9d\x95\xdb\xaf\xc5\xc3\x48\x3b\x4f\x13\x97\x9b\x80\xad\x37\x9d\xce\x6f\x42\xc9\xec\x69\x94\xa4\x46\x94\x73\x07\x29\x06\x07\xcf\x31\xbf\xeb\x06\xc4\x92\xd2\x50\xc4\x16\x80\xb7\xca\xfd\x36\x87\x9a\x80\xea\x59\x43\x8f\x16\x78\x74\x98\x3b\xf0\x18\x15\x92\x98\x67\xae\x16\xea\x1a\xb0\x98\x4f\xde\x8b\xb5\x54\x5b\x96\xc8\xfc\xfe\x4d\xd2\xf4\x26\xe7\x38\x76\x64\x5c\x64\x19\x4d\x4a\x75\x96\xfd\x7e\xd2\x96\x3b\xb7\x31\x86\x3e\x82\xc0\x99\x67\x38\x38\x44\x2b\x43\x6c\x15\xc4\x3c\xc5\x99\x4b\x7b\xcc\x4d\x4e\x73\xed\xd1\x25\x16\x7e\xa5\x87\x83\x4b\x69\x11\xa5\x77\xb0\x53\x74\x53\xeb\x9f\x67\x30\x50\xca\x6c\x20\x0b\xf7\xf4\x34\x17\x11\x35\x79\x18\x2b\x0e\xa9\x3c\x85\xd9\x4a\x62\x18\x2c\x3b\xb6\xff\x25\x16\x57\xba\x27\x4d\x25\x7d\x54\x29\xeb\xf2\xf4\x4e\xe6\x57\x18\x6d\x45\x7e\x78\xaa\x68\xe8\x71\x87\x8a\x5c\x05\xcd\x56\xd4\xfd\x58\x12\xac\x9e\xc9\x35\x50\xb3\x6c\x77\x1f\xf7\x51\x7c\x64\xcb\x49\x94\x36\x53\x9a\xf3\xd2\x17\x1a\x74\x86\xe1\xb5\x74\x70\xe8\x86\x24\xe8\x12\x14\xbd\x5d\x08\xe5\xa0\xcd\x80\x4b\x58\x88\x42\xf9\x3d\xca\x54\xfe\x79\x84\x94\x45\xa5\x3a\x22\x77\xa8\x77\x68\xc7\x29\xb0\xd6\x0b\x69\x6a\xb9\x27\xd8\x87\xb3\x8e\xe2\xec\x78\x7f\xeb\x31\x94\xcc\xef\x74\x6a\xb7\x39\xdd\x67\xf3\x94\x90\xec\x95\x6c\x18\xba\xf8\x89\x2a\x79\xa8\x56\xc6\x5d\x9b\xe8\x13\x6c\x4f\x4d\x20\x68\xd8\x7d\x1d\xf9\x1f\xfb\xda\x95\x87\x69\xa6\x26\xac\x5a\x1e\xcd\x34\x7d\x46\x95\xae\x92\xc6\x57\xc7\x35\x35\xff\xc6\x9e\x83\x96\xc8\x53\xa8\x95\x4b\xec\x23\xb4\x61\x22\xfc\xaa\xcf\x7a\xf7\xc2\xf6\x70\xbe\xeb\xdd\x55\x23\x0e\x3f\xe8\xff\x8d\x6e\x27\xb2\x1b\xad\xb6\x71\x7a\xd9\xf9\x16\x1f\x62\x47\x35\xec\x44\x36\x1f\x12\xa3\x2c\xd3\x78\x50\x3b\xb9\xa3\xfc\x7e\x9a\xca\x0b\x6e\xfc\x90\x07\x54\x7a\x3c\x0e\x71\xa7\x09\xb5\x47\xc0\x4b\x98\xc1\x1f\x47\xe4\x92\xbb\xb5\x4b\xda\x28\xe2\x3a\xcf\x43\x43\xe6\xce\xa4\x77\xe0\x4f\x13\xfb\x74\x3d\x8d\xad\x7b\x1a\x14\x4b\x5e\x2d\xd1\x47\x04\x31\xa0\x9c\xc3\x81\x74\x0e\xf5\xc1\x83\xfe\x99\xf1\x01\x7c\x73\x16\xc9\x8f\xe3\x2e\x2c\xc7\xf8\xc1\x56\xa0\xfc\xea\xcf\x86\xc8\x61\x32\x12\xf9\x8f\xb3\xd9\x64\x5a\x93\x2c\x84\x54\xd8\xbd\x67\x2b\x6c\x8c\x34\x20\xe3\x88\x53\x93\xd2\x24\x28\x85\xba\x04\x25\xb6\x38\x6b\x18\x9d\x39\x9a\x81\x6a\x1a\xf8\xde\x96\x26\x6b\x97\xb9\x22\xc5\xf9\xc2\x9d\xc0\x2e\x0b\x6e\xb5\xf5\xd5\xd9\x7e\xfa\xba\x87\xff\x0f\x5f\xbc\xfe\x9f\xf9\xe2\x9b\xe3\xf2\x9c\x95\xb2\xf8\x46\xa2\x67\x31\xdb\xa7\x0e\xbb\x83\xad\xdb\x3d\x24\x0f\x5e\x9c\x6c\xa8\x4c\x91\x31\x8c\xd1\x4e\x85\x25\x22\xca\x87\xe1\xa4\xfa\x0f\x8c\x28\x5f\x32\x25\x48\x6e\xb2\xee\xd9\xa9\x84\x68\x1b\xf4\x9b\xf4\xab\x8c\xf8\x8b\x13\xff\xe1\x68\xcd\x39\x5a\xc8\x0b\x2b\xdf\xb6\x9e\x15\x0b\x7e\x43\x9f\x02\x83\x7b\xc3\x0b\x2d\x1f\xca\x04\x7e\x7b\x32\x57\x7b\x51\xa1\x4b\xbf\x1a\x28\x58\x84\xbd\x71\x1e\x2b\xab\xfd\x8e\xd7\xc8\x7f\xe9\x31\xf2\x53\x1d\xe0\xe9\x86\xf4\x9f\x2f\x66\xf1\xbc\xd3\x8f\xc1\x66\xe7\x73\xe1\x05\x53\x37\x2e\xae\x8c\x9f\x6e\x97\xf8\x24\x5d\x37\x5c\xc2\x29\xd2\xb1\xbc\x2b\x47\x63\x66\x4b\x25\xa8\xa0\x0e\xe4\xb5\x8d\xf8\xc7\x93\x1b\x49\xfe\xb2\xfe\xfa\x0d\xdd\xf5\x5b\x1d\x50\x6d\x7c\x41\x5b\x7d\x49\x53\xfd\x56\x1e\xc7\x08\x7f\xa5\x9b\x3e\x1f\x7e\xb0\xce\xfd\x16\x63\x0c\x63\xaf\x11\x64\xff\x06\x55\x09\x58\x98\xf6\x18\x00\x00")
 
 func templatesScApiserverDeploymentYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -128,32 +190,92 @@ func templatesScApiserverDeploymentYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/apiserver-deployment.yaml.tmpl", size: 2739, mode: os.FileMode(416), modTime: time.Unix(1527273967, 0)}
+	info := bindataFileInfo{name: "templates/sc/apiserver-deployment.yaml.tmpl", size: 6390, mode: os.FileMode(420), modTime: time.Unix(1792032658, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScCa_configJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x44\xcc\xb1\x0a\xc2\x30\x18\x04\xe0\x3d\x4f\x71\xdc\xdc\xa1\xa0\x83\xe4\x55\xc4\xa1\xe8\x99\x06\x35\x94\xff\x6f\xc5\x52\xfa\xee\x92\x80\x38\xdc\xf2\x1d\x77\x5b\x00\xe8\x39\x95\x5c\x12\x23\x36\x04\x00\xe0\x4d\xf7\x61\x79\xce\x55\x1a\x00\xd4\x67\xca\xb6\x32\x82\xc7\xc3\xa9\xef\x47\x76\xbf\x66\xf1\x21\xc9\x19\x71\xfe\x5f\x75\xe0\x43\x2b\x54\xae\x79\x1a\x65\x2f\x95\xb9\x9a\xcb\xde\x32\xe2\xd2\xb6\x7b\xa8\xd9\xc3\x37\x00\x00\xff\xff\x80\xfc\xeb\xe4\x85\x00\x00\x00")
+var _templatesScApiserverEncryptionConfigYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x53\xc1\x6e\xdb\x30\x0c\xbd\xfb\x2b\x88\xe4\xb2\x01\x71\xd2\xf6\x34\xa4\xa7\x34\xcd\x36\xa3\x85\x03\xd4\xe9\x8a\x1e\x15\x99\x76\x88\x3a\x92\x2a\xc9\x71\x8d\xa2\xff\x3e\xca\x76\xba\x74\xc3\x4e\xf5\xc5\x92\xf9\xf8\xf8\x1e\x49\x8f\xc7\x9f\x7d\xa2\x31\x2c\xb5\x69\x2d\x95\x3b\x0f\x17\x67\xe7\xdf\xe0\x87\xd6\x65\x85\x90\x28\x39\x8d\x42\xf8\x96\x24\x2a\x87\x39\xd4\x2a\x47\x0b\x7e\x87\xb0\x30\x42\xf2\x6b\x88\x4c\xe0\x17\x5a\x47\x5a\xc1\xc5\xf4\x0c\xbe\x04\xc0\x68\x08\x8d\xbe\x5e\x32\x43\xab\x6b\xd8\x8b\x16\x94\xf6\x50\x3b\x64\x0a\x72\x50\x10\x17\xc1\x17\x89\xc6\x03\x29\x90\x7a\x6f\x2a\x12\x4a\x22\x34\xe4\x77\x5d\x99\x81\x84\x65\xc0\xe3\x40\xa1\xb7\x5e\x30\x5a\x30\xde\xf0\xad\x38\xc5\x81\xf0\x9d\xe0\xf0\xec\xbc\x37\x6e\x3e\x9b\x35\x4d\x33\x15\x9d\xda\xa9\xb6\xe5\xac\xea\x91\x6e\x76\x9b\x2c\x57\x69\xb6\x8a\x59\x71\x97\x73\xaf\x2a\x74\x0e\x2c\x3e\xd7\x64\xd9\xeb\xb6\x05\x61\x58\x90\x14\x5b\x96\x59\x89\x06\xb4\x05\x51\x5a\xe4\x98\xd7\x41\x70\x63\xc9\x93\x2a\x27\xe0\x74\xe1\x1b\x61\x91\x59\x72\x72\xde\xd2\xb6\xf6\x1f\xba\x75\x94\xc7\xa6\x4f\x01\xdc\x2f\xa1\x60\xb4\xc8\x20\xc9\x46\x70\xb5\xc8\x92\x6c\xc2\x1c\x0f\xc9\xe6\xe7\xfa\x7e\x03\x0f\x8b\xbb\xbb\x45\xba\x49\x56\x19\xac\xef\x60\xb9\x4e\xaf\x93\x4d\xb2\x4e\xf9\xf6\x1d\x16\xe9\x23\xdc\x24\xe9\xf5\x04\x90\x7b\xc5\x65\xf0\xc5\xd8\xa0\x9f\x45\x52\xe8\x23\xe6\xa1\x69\x19\xe2\x07\x01\x85\xee\x05\x39\x83\x92\x0a\x92\xec\x4b\x95\xb5\x28\x11\x4a\x7d\x40\xab\xd8\x0e\x18\xb4\x7b\x72\x61\x9a\x8e\xe5\xe5\xcc\x52\xd1\x9e\xbc\xf0\xdd\x97\x7f\x4c\xf5\x2b\xb2\x52\xd2\xb6\x26\x40\x78\x2e\xaa\xa0\xb2\xb6\x5d\xc2\x71\x40\xc2\x10\x38\xb4\x5c\x63\x02\x25\x1d\x50\x75\x33\xe6\xc4\x38\x46\x2f\xf3\x18\xdf\xf3\xd9\xd1\x70\x66\x2d\x21\x95\x94\xf3\x61\x2b\x3a\x35\xb0\x25\x95\x73\xc4\x85\x01\x84\xcc\xe0\x72\x13\x16\x72\x95\xc5\xcb\xab\x25\x3c\x61\xcb\x40\x8b\x7c\xe0\xb5\x12\xd2\x6a\xe7\x7a\x8a\xaa\x72\x9d\xd6\xcf\xff\x30\xaf\xaf\x40\x05\x4c\x57\x5c\xfe\x8f\xef\x65\x67\x1b\xde\xde\x22\xf6\x3a\xfc\x0e\x73\x38\x9c\x47\x4f\xac\x78\xce\x93\x90\x16\x7d\xe4\x5b\x83\x73\x58\x1b\xf1\x5c\x63\xb4\x47\x2f\x72\xe1\xc5\x3c\x02\x50\x62\xcf\x01\x4e\xed\xbb\x74\xd2\x90\xb8\x6f\xe8\x80\x71\xbc\xca\x0c\x64\x09\xd3\xf4\x78\x0d\x45\x81\x47\xb9\xc5\xca\x05\x2e\x08\x9b\x3b\xef\xfa\xcd\x33\x8a\x25\x97\xa8\x74\x19\xbf\x93\x47\xc7\xa2\x81\xe5\x6f\x07\x37\xd8\x32\x5f\x5f\xe1\x7f\x0e\x39\x86\x3c\x0b\x3e\xfd\x06\x2e\x4b\xdc\x8e\x7f\x04\x00\x00")
 
-func templatesScCa_configJsonBytes() ([]byte, error) {
+func templatesScApiserverEncryptionConfigYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_templatesScCa_configJson,
-		"templates/sc/ca_config.json",
+		_templatesScApiserverEncryptionConfigYamlTmpl,
+		"templates/sc/apiserver-encryption-config.yaml.tmpl",
 	)
 }
 
-func templatesScCa_configJson() (*asset, error) {
-	bytes, err := templatesScCa_configJsonBytes()
+func templatesScApiserverEncryptionConfigYamlTmpl() (*asset, error) {
+	bytes, err := templatesScApiserverEncryptionConfigYamlTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/ca_config.json", size: 133, mode: os.FileMode(416), modTime: time.Unix(1507129340, 0)}
+	info := bindataFileInfo{name: "templates/sc/apiserver-encryption-config.yaml.tmpl", size: 1151, mode: os.FileMode(420), modTime: time.Unix(1792026031, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScCa_csrJsonTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3c\xcc\xb1\x0a\xc2\x30\x10\xc6\xf1\xf9\xee\x29\x8e\x6f\x2e\xa2\xc5\x41\xb2\x76\x71\x10\x1c\x6a\xe9\x20\x1d\x22\x44\xad\xad\x0d\x34\x75\xd0\x90\x77\x97\x2b\xa5\xe3\xef\xcf\x7d\x17\x99\xf0\xf4\x61\x0a\x30\x72\x15\xc4\x28\x9b\xa3\x0f\xd3\x4e\x52\x42\xb6\x32\x57\x4a\x93\x31\xa1\x73\x5f\x18\x89\x4c\x04\xdb\x3f\x3c\x8c\x60\x0c\x16\x99\x86\xd0\xfe\x1c\x8c\xe4\xdb\xfd\x81\x29\xe9\xf5\x60\xdf\x6e\x7e\xcd\x44\xba\x21\x14\xba\xa8\xca\x79\x40\x38\xa9\x82\x1d\xe4\xe5\x83\x5b\xda\x59\x5b\xf7\xb9\xad\xae\x34\xd4\x75\xbd\xb8\xbc\xa8\x0b\xdb\xb7\x77\x3f\x0e\xad\x05\x13\x25\xa6\x86\x13\xff\x03\x00\x00\xff\xff\x8a\x02\x37\x24\xce\x00\x00\x00")
+var _templatesScBindingSecretPolicyYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x55\xc1\x6e\xdb\x38\x10\xbd\xfb\x2b\x06\x4a\x0e\x2d\x60\xc9\x4d\x4f\x85\x16\x7b\x50\xd3\x6c\x56\x68\x6a\x07\x91\xbb\x41\xb1\xd8\x03\x2d\x8d\x25\xc2\x12\xa9\x92\x94\x1d\xa3\xe8\xbf\xef\x0c\x45\x7b\xed\x04\x08\x16\x28\x2f\xa6\xc8\xc7\x37\x6f\xde\x0c\xe9\x8b\x8b\x5f\x1d\x93\x0b\xb8\xd6\xfd\xde\xc8\xba\x71\xf0\xfe\xdd\xd5\x07\xb8\xd5\xba\x6e\x11\x72\x55\x26\x13\xde\xbe\x93\x25\x2a\x8b\x15\x0c\xaa\x42\x03\xae\x41\xc8\x7a\x51\xd2\x4f\xd8\x99\xc2\x5f\x68\xac\xd4\x0a\xde\x27\xef\xe0\x0d\x03\xa2\xb0\x15\xbd\xfd\x8d\x18\xf6\x7a\x80\x4e\xec\x41\x69\x07\x83\x45\xa2\x90\x16\xd6\x92\x82\xe0\x53\x89\xbd\x03\xa9\xa0\xd4\x5d\xdf\x4a\xa1\x4a\x84\x9d\x74\x8d\x0f\x13\x48\x48\x06\x7c\x0b\x14\x7a\xe5\x04\xa1\x05\xe1\x7b\xfa\x5a\x9f\xe2\x40\x38\x2f\x98\x47\xe3\x5c\x6f\xd3\xd9\x6c\xb7\xdb\x25\xc2\xab\x4d\xb4\xa9\x67\xed\x88\xb4\xb3\xbb\xfc\xfa\x66\x5e\xdc\xc4\xa4\xd8\x9f\xf9\xaa\x5a\xb4\x16\x0c\x7e\x1f\xa4\xa1\x5c\x57\x7b\x10\x3d\x09\x2a\xc5\x8a\x64\xb6\x62\x07\xda\x80\xa8\x0d\xd2\x9e\xd3\x2c\x78\x67\xa4\x93\xaa\x9e\x82\xd5\x6b\xb7\x13\x06\x89\xa5\x92\xd6\x19\xb9\x1a\xdc\x99\x5b\x07\x79\x94\xf4\x29\x80\xfc\x12\x0a\xa2\xac\x80\xbc\x88\xe0\x63\x56\xe4\xc5\x94\x38\x1e\xf3\xe5\x9f\x8b\xaf\x4b\x78\xcc\x1e\x1e\xb2\xf9\x32\xbf\x29\x60\xf1\x00\xd7\x8b\xf9\xa7\x7c\x99\x2f\xe6\xf4\xf5\x07\x64\xf3\x6f\xf0\x39\x9f\x7f\x9a\x02\x92\x57\x14\x06\x9f\x7a\xc3\xfa\x49\xa4\x64\x1f\xb1\x62\xd3\x0a\xc4\x33\x01\x6b\x3d\x0a\xb2\x3d\x96\x72\x2d\x4b\xca\x4b\xd5\x83\xa8\x11\x6a\xbd\x45\xa3\x28\x1d\xe8\xd1\x74\xd2\x72\x35\x2d\xc9\xab\x88\xa5\x95\x9d\x74\xc2\xf9\x95\x17\x49\x8d\x2d\xb2\xb8\xcf\xe0\x56\x38\xdc\x20\xd2\x79\xe8\x06\xc2\x6b\x63\xc1\xa2\x63\x8f\xc6\xa0\x58\x1a\x74\x4b\x23\x94\x25\x1d\x9d\x0d\xc5\xf3\x32\xcd\x96\xd8\x3e\x4a\x55\x11\xd8\x4e\xc9\x19\x64\x1d\x60\x06\xf2\x3e\xd4\xb8\xd7\x54\x8c\x3d\xf4\xc2\x72\x2b\x72\x8b\xd0\xc9\x38\x5e\x8d\x87\xe2\x91\x3d\x1e\x51\xc9\xa1\x9c\x7c\x70\x24\xa1\xf4\x8c\xac\xd0\x2f\x75\x1c\xa1\xdd\x87\xe8\x81\xe1\x3f\xb1\x4a\xbf\xd4\x5a\xa3\x23\xb8\xb6\x47\x39\xcc\x9a\xc0\xd2\x33\xd8\x51\x3f\x94\xc2\x89\x56\xd7\xd4\x38\xd2\xaf\x51\x06\xa5\x68\xdb\x51\xc6\x89\x3d\x3b\x5c\x35\x5a\x6f\x68\xd9\xe8\xa1\x6e\x82\x8e\x2f\x6c\x1a\xc5\xcf\xaa\xe0\xff\x63\x80\x89\xc3\x02\xf4\xed\x50\x4b\xe5\x2d\xff\xf5\x7b\xff\xe3\x07\xc8\x35\x24\xc1\xf5\xc2\x67\x7c\x3f\x9a\xfc\xf3\x27\xed\xc6\x40\xe9\x53\x6b\x9c\x23\x1e\x28\x6f\xcb\x80\x38\x8e\x27\x94\x68\xb8\xf9\xe9\x58\x73\xee\x91\xa4\x3e\x66\x9a\xd8\x66\xb6\xbd\x5a\xa1\x13\x57\x93\x0d\xb1\xa4\x90\x51\x22\xb5\x9a\x74\xb4\x54\x91\x5b\xe9\x04\x40\x89\x0e\x53\x20\x35\x97\xc9\x9c\xa6\xf7\x06\xd7\xf2\x89\x22\x04\x57\xe3\xe0\xea\xf3\x4a\xd3\x01\x8f\x27\xe4\xf1\x6c\x31\xac\xc7\xb3\x13\xee\x70\x26\xe7\x3b\xbc\x5f\x6a\x9e\xc6\x50\x93\xdd\xf4\x28\xc0\xdf\x51\xe0\x0e\xd4\xc9\xe6\x83\x4d\xa4\x8e\xfe\x99\xf0\xcb\xc1\x42\x3d\xe8\xbc\x2d\xc3\xee\x76\xcc\xd7\x03\x42\x6a\x7e\xa7\x13\xae\x6c\x52\x0f\xb1\xf4\x34\x51\x46\x2c\xc8\xd2\xcb\x83\xd5\x09\xad\x9f\xc6\xdc\x21\xb7\xff\x4b\xcc\x6b\x72\xb8\x44\x5c\xc1\x63\x20\x5f\x17\x3e\xa3\x8e\x2b\xe9\x69\x21\x5f\x02\x63\x88\xd8\x47\xfa\x8c\x3c\x10\x55\x75\x28\xfe\xc9\xf4\x2c\x48\x81\x2d\x96\x74\xb9\x5f\xc4\x3a\x6c\xa4\x41\xb8\x77\xe4\x4e\xac\xb0\x3d\x53\x71\xb9\x99\xc2\xe5\x16\xd2\xdf\x5f\xe1\xe4\xc1\xc2\x2e\x37\xac\x2c\x1d\xe7\xdb\x57\x54\x02\xb4\xba\xf4\xed\x47\x60\xae\x7d\xf2\xfc\x06\x47\x84\xe9\x85\xa1\x88\x8e\x2a\x98\x1e\xf2\xe2\x7f\xa4\x64\x11\x1e\x87\x43\xf8\x5e\xb8\x66\x89\xd6\x1d\xcb\x65\x87\xd5\x3d\xad\xbd\xc6\xcd\xa3\xd4\x54\x9a\x51\xc4\x97\xc1\xba\xb9\x76\x37\x4f\xf4\xde\x9f\x0b\xa5\x9e\xf4\x77\xe0\x60\xd3\x56\xb4\xc3\xd8\xff\xc9\xc9\x7b\xf3\xbc\x08\x87\xd9\xbf\x8f\x8d\xd0\x98\xf0\x07\x00\x00")
+
+func templatesScBindingSecretPolicyYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScBindingSecretPolicyYamlTmpl,
+		"templates/sc/binding-secret-policy.yaml.tmpl",
+	)
+}
+
+func templatesScBindingSecretPolicyYamlTmpl() (*asset, error) {
+	bytes, err := templatesScBindingSecretPolicyYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/binding-secret-policy.yaml.tmpl", size: 2032, mode: os.FileMode(420), modTime: time.Unix(1792026279, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScCaSecretYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x94\x41\x6f\xdb\x38\x10\x85\xef\xfa\x15\x0f\xd1\x65\x17\xb0\x95\x34\xa7\x85\xf7\xa4\xba\xde\x5d\xc1\x89\x1d\x44\x4e\x83\x9c\x8a\x31\x35\x96\x88\xd0\x24\x4b\x52\x71\x05\xc3\xff\x7d\x41\x59\x76\xe2\xf6\x58\xdd\x38\x1c\xbe\xf9\xde\xcc\xd8\xe9\x6f\x7f\x49\x8a\xa9\xb1\x9d\x93\x75\x13\x70\x7b\xf3\xe9\x2f\xfc\x6b\x4c\xad\x18\x85\x16\x59\x92\x26\x29\xee\xa4\x60\xed\xb9\x42\xab\x2b\x76\x08\x0d\x23\xb7\x24\x1a\x3e\xdd\x8c\xf0\x95\x9d\x97\x46\xe3\x36\xbb\xc1\x1f\x31\xe1\x6a\xb8\xba\xfa\xf3\xef\x24\x45\x67\x5a\x6c\xa9\x83\x36\x01\xad\x67\x84\x46\x7a\x6c\xa4\x62\xf0\x0f\xc1\x36\x40\x6a\x08\xb3\xb5\x4a\x92\x16\x8c\x9d\x0c\x0d\xc2\xbb\x7e\x96\xa4\x78\x19\x24\xcc\x3a\x90\xd4\x20\x08\x63\x3b\x98\xcd\xc7\x3c\x50\xe8\x81\x01\xa0\x09\xc1\xfa\xc9\xf5\xf5\x6e\xb7\xcb\xa8\xa7\xcd\x8c\xab\xaf\xd5\x31\xd3\x5f\xdf\x15\xd3\xd9\xa2\x9c\x8d\x6f\xb3\x9b\xfe\xcd\x93\x56\xec\x3d\x1c\x7f\x6f\xa5\xe3\x0a\xeb\x0e\x64\xad\x92\x82\xd6\x8a\xa1\x68\x07\xe3\x40\xb5\x63\xae\x10\x4c\x04\xde\x39\x19\xa4\xae\x47\xf0\x66\x13\x76\xe4\x38\x49\x51\x49\x1f\x9c\x5c\xb7\xe1\xa2\x5b\x27\x3c\xe9\x2f\x12\x8c\x06\x69\x5c\xe5\x25\x8a\xf2\x0a\x9f\xf3\xb2\x28\x47\x49\x8a\xe7\x62\xf5\xdf\xf2\x69\x85\xe7\xfc\xf1\x31\x5f\xac\x8a\x59\x89\xe5\x23\xa6\xcb\xc5\x97\x62\x55\x2c\x17\x25\x96\xff\x20\x5f\xbc\x60\x5e\x2c\xbe\x8c\xc0\x32\x34\xec\xc0\x3f\xac\x8b\xfc\xc6\x41\xc6\x3e\x72\x15\x9b\x56\x32\x5f\x00\x6c\xcc\x71\x7c\xde\xb2\x90\x1b\x29\xa0\x48\xd7\x2d\xd5\x8c\xda\xbc\xb1\xd3\x52\xd7\xb0\xec\xb6\xd2\xc7\x69\x7a\x90\xae\x92\x14\x4a\x6e\x65\xa0\xd0\x47\x7e\x31\x75\x5c\x91\x69\x8e\xd0\x50\x80\x97\xb5\x8e\x0d\x6a\x18\x9e\xdd\x9b\x14\x0c\x41\x81\x94\xa9\x41\x56\xf6\x31\x76\x10\xec\x42\x2c\x4f\x81\x47\xf0\xc1\x38\x8e\x65\xc8\xc3\xb3\x70\x1c\xe0\x0d\x14\x05\x76\x90\xda\x07\x52\xca\x43\x90\xee\xb5\xa1\x79\xd7\xab\x44\xd2\x0f\x32\x3e\x49\xfb\xad\x31\x6d\x80\x63\xab\x48\xc4\x84\x88\x19\xc9\x5c\xeb\xe3\x40\xd6\x5d\x0f\x96\x3f\x14\x65\x54\x10\x9c\x61\xa9\x55\x07\xc7\xd1\x54\xcf\x10\x35\x30\x1e\xf7\x4c\x63\x41\xe3\x57\xee\x32\x3c\x1f\x83\xaf\x5b\x1f\xcf\xbd\x86\x75\xf2\x8d\x02\x23\x9e\xa5\x7f\xf7\xc0\x5a\xb8\xce\xc6\x62\xbd\xd2\x54\x99\xb6\xc2\xfc\xbe\x04\x45\x0f\x59\x94\x7b\xdd\xfa\xde\x17\x53\x15\xd7\x77\x88\x0e\x5d\xfc\xf6\xf0\xf4\xf9\xae\x98\x7e\x9b\xcf\x5e\x26\x98\xe6\x1f\x2d\x0e\xf7\x8f\xc5\xd7\x7c\x35\x3b\x27\x7c\x00\x19\xc1\x1a\xef\xe5\x5a\x75\xef\x18\xc7\x47\xf3\xfb\x72\x78\x70\xe6\x39\x19\xb9\x94\x80\xf4\x3f\x59\x88\x5c\xbf\xfd\x25\xfb\x3d\xe4\x06\xd9\x34\x7f\x38\xd6\x9a\x73\x87\xc3\x21\x21\x2b\x87\x3f\x8e\x09\xde\x3e\x25\xaf\x52\x57\x13\x94\xfd\x0e\x24\xa1\xb3\x3c\xc1\xd2\xd2\xf7\x96\x93\x2d\x07\xaa\x28\xd0\x24\x01\x34\x6d\x79\x72\xda\xae\xf1\xb0\x5d\x63\x41\xc3\x95\xb7\x24\x78\x82\xfd\x1e\xd9\xe2\x74\x8c\xb5\x00\x45\x6b\x56\x3e\x4a\x20\xfe\xb4\x7f\xd5\x20\x2b\x63\x88\x5d\x72\xaa\x25\x28\x13\x2e\x1c\xd5\xa6\xf9\x43\xbb\x56\x52\x0c\xec\xc0\xd9\xd4\xfc\xbe\x3c\x06\xdf\x27\xbc\xdf\x83\x95\xe7\x73\x2c\x9e\x75\x85\xc3\xe1\xac\x75\xd1\x88\xfd\x1e\xac\x2b\x1c\x0e\xc9\xff\x03\x00\xeb\x74\x73\xe1\xd0\x05\x00\x00")
+
+func templatesScCaSecretYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScCaSecretYamlTmpl,
+		"templates/sc/ca-secret.yaml.tmpl",
+	)
+}
+
+func templatesScCaSecretYamlTmpl() (*asset, error) {
+	bytes, err := templatesScCaSecretYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/ca-secret.yaml.tmpl", size: 1488, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScCa_configJsonTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x44\xcc\x31\x0a\x02\x31\x14\x84\xe1\x3e\xa7\x18\xa6\x5e\x3c\x40\x5a\xef\x60\x23\x16\xc1\x1d\xe3\xc3\x35\x2c\x49\x56\x0c\x21\x77\x17\x17\xc4\xf6\x63\xe6\xef\x0e\x60\xb1\x98\x2c\x45\x7a\x74\x38\x00\xe0\xac\x5b\xd8\x96\xfa\x95\x1d\x00\xea\xbd\x5a\x6e\xf4\x60\xef\x38\x1c\x95\xeb\x29\x2c\x36\x5b\x6d\x18\x83\xd3\x6f\xb6\x95\x10\x55\xe8\x71\xfe\x77\x27\xf0\xa1\x06\xa5\xab\xad\x77\xe5\xa7\x52\xe5\x04\x16\xe5\x97\x32\x71\xd9\xbf\xc3\x01\xc3\x0d\xf7\x19\x00\xe0\x78\x00\xc0\x92\x00\x00\x00")
+
+func templatesScCa_configJsonTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScCa_configJsonTmpl,
+		"templates/sc/ca_config.json.tmpl",
+	)
+}
+
+func templatesScCa_configJsonTmpl() (*asset, error) {
+	bytes, err := templatesScCa_configJsonTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/ca_config.json.tmpl", size: 146, mode: os.FileMode(420), modTime: time.Unix(1791998193, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScCa_csrJsonTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3c\x8e\x31\x4f\xc3\x30\x10\x85\xe7\xbb\x5f\x71\xba\x39\x42\x82\x31\x1b\xca\x82\x04\x12\x43\x28\x19\x50\x07\x03\x47\x7b\xd4\x8d\x91\x6d\x24\x5c\xcb\xff\x1d\x5d\x9b\x66\xfc\x9e\xdf\x7d\xcf\x15\x81\xf7\x21\xe5\xc4\x3d\xbd\x11\xd7\x4a\x37\x0f\x21\xe5\x5b\x6a\x8d\xbb\x15\xef\x0c\x69\xdb\x21\xf0\x41\x0a\xf7\x54\x11\x80\x9d\xdf\x05\xee\x2f\x47\x8f\x52\xee\xfd\x2e\x44\xcd\xfb\xa3\x95\x3b\x2b\x24\x3d\x89\x95\x2f\xef\xa3\x9e\x84\x5a\x43\x68\xe6\xf9\x70\x57\x8d\xfc\xfd\x68\x2c\x57\xd1\x20\x31\xbf\x3a\xaf\x9f\x9a\x8b\x89\x96\xfa\xec\x8e\x72\xfe\x23\x02\xd8\x38\xf0\x60\x17\x9b\xf1\xbc\x04\xfc\x64\x94\xdc\x4c\xdf\x21\xc9\x92\x3d\x5b\x76\xf8\x7d\x5f\x79\x63\xc1\x34\x4d\x0b\x8f\x2f\xc6\x83\xf3\xfa\x15\xe2\xac\x8e\x11\xa0\x21\x6c\xb1\xe1\xff\x00\x6f\x2e\x2d\x4d\x17\x01\x00\x00")
 
 func templatesScCa_csrJsonTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -168,12 +290,52 @@ func templatesScCa_csrJsonTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/ca_csr.json.tmpl", size: 206, mode: os.FileMode(416), modTime: time.Unix(1507129340, 0)}
+	info := bindataFileInfo{name: "templates/sc/ca_csr.json.tmpl", size: 279, mode: os.FileMode(420), modTime: time.Unix(1791998193, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScControllerManagerDeploymentYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x55\x41\x8f\xd3\x48\x13\xbd\xe7\x57\x94\x92\xcb\xf7\x49\xe3\x24\x33\x3b\x68\x91\x57\x1c\xc2\xcc\x00\x11\x90\x44\xe3\xb0\x88\xd3\xaa\xd3\xae\xd8\xa5\x69\x77\x7b\xab\xcb\x09\x5e\xc4\x7f\x5f\xb5\x9d\x04\x9b\xb0\x23\x10\x87\xdd\x3e\x45\xf5\xaa\x5e\xbf\x2a\xbf\xae\x8c\x7e\xfa\x0c\x46\x70\xe3\xca\x9a\x29\xcb\x05\xae\xa6\x97\xbf\xc2\x4b\xe7\x32\x83\x30\xb7\x7a\x3c\x08\xf0\x1b\xd2\x68\x3d\xa6\x50\xd9\x14\x19\x24\x47\x98\x95\x4a\xe7\x78\x44\x2e\xe0\x77\x64\x4f\xce\xc2\xd5\x78\x0a\xff\x0b\x09\xc3\x03\x34\xfc\xff\x6f\x83\x11\xd4\xae\x82\x42\xd5\x60\x9d\x40\xe5\x11\x24\x27\x0f\x5b\x32\x08\xf8\x51\x63\x29\x40\x16\xb4\x2b\x4a\x43\xca\x6a\x84\x3d\x49\xde\x5c\x73\x20\x19\x0f\x46\xf0\xe1\x40\xe1\x36\xa2\xc8\x82\x02\xed\xca\x1a\xdc\xb6\x9b\x07\x4a\x1a\xc1\xe1\xe4\x22\xa5\x8f\x27\x93\xfd\x7e\x3f\x56\x8d\xda\xb1\xe3\x6c\x62\xda\x4c\x3f\x79\x33\xbf\xb9\x5b\x24\x77\xd1\xd5\x78\xda\xd4\xbc\xb3\x06\xbd\x07\xc6\x3f\x2b\x62\x4c\x61\x53\x83\x2a\x4b\x43\x5a\x6d\x0c\x82\x51\x7b\x70\x0c\x2a\x63\xc4\x14\xc4\x05\xc1\x7b\x26\x21\x9b\x5d\x80\x77\x5b\xd9\x2b\xc6\xc1\x08\x52\xf2\xc2\xb4\xa9\xa4\x37\xad\xa3\x3c\xf2\xbd\x04\x67\x41\x59\x18\xce\x12\x98\x27\x43\x78\x3e\x4b\xe6\xc9\xc5\x60\x04\xef\xe7\xeb\x57\xcb\x77\x6b\x78\x3f\xbb\xbf\x9f\x2d\xd6\xf3\xbb\x04\x96\xf7\x70\xb3\x5c\xdc\xce\xd7\xf3\xe5\x22\x81\xe5\x0b\x98\x2d\x3e\xc0\xeb\xf9\xe2\xf6\x02\x90\x24\x47\x06\xfc\x58\x72\xd0\xef\x18\x28\xcc\x11\xd3\x30\xb4\x04\xb1\x27\x60\xeb\x5a\x41\xbe\x44\x4d\x5b\xd2\x60\x94\xcd\x2a\x95\x21\x64\x6e\x87\x6c\xc9\x66\x50\x22\x17\xe4\xc3\xd7\xf4\xa0\x6c\x3a\x18\x81\xa1\x82\x44\x49\x13\x39\x6b\xaa\xb5\xc8\x2d\x96\xc6\xd5\x05\x5a\x69\xee\xf0\xc8\x3b\xd2\x08\x5a\x89\x32\x2e\x03\xed\xac\xb0\x33\x06\x19\x0a\x65\x55\x86\xdc\x94\xfd\xbc\x77\x1f\xc8\xa6\x71\xe7\xf6\x81\x2a\xe9\xe0\xc5\x18\xf0\xa3\xa0\x6d\x1a\x99\xec\x2e\x37\x28\xea\x72\x50\xa0\xa8\x54\x89\x8a\x07\x00\x56\x15\x18\x77\xa4\x45\x07\x69\x07\xc8\x97\x4a\x63\x7c\x6c\x25\x3a\xb4\x32\x00\x30\x6a\x83\xc6\x07\x06\x08\x16\x39\x4b\x89\xbe\x41\x19\x06\x1e\x2a\x18\x1b\x4b\xf9\x18\x2e\x07\x00\x1e\x0d\x6a\x71\xdc\x72\x15\x4a\x74\xfe\xa6\x43\xfe\xfd\xf4\x00\x82\x45\x69\x94\xe0\x81\xaa\xd3\x66\x38\xa6\xc7\xfa\x23\xbc\x00\x47\xe9\xcd\xef\xb6\x64\xa6\xb5\xab\xac\x2c\x9a\x01\x0e\xcf\xeb\x86\x87\xf4\x80\x28\xb2\xc8\xa7\xab\xa3\xc7\xa6\xde\x1e\x2a\x54\x86\x31\x7c\xfa\x04\xe3\xa4\xbd\xee\xa6\x15\x38\x0f\x00\x7c\xfe\xdc\xcf\x5c\x55\xc6\xac\x9c\x21\x5d\xc7\x30\xdf\x2e\x9c\xac\x18\x7d\x70\xc2\x31\x8b\xd1\xbb\x8a\x35\x76\xda\x87\xe6\x99\xa3\x97\x5e\x0c\x40\x97\x55\x0c\x97\xd3\x69\xd1\x8b\x16\x58\x38\xae\x63\xb8\x9a\xbe\xa5\x0e\xd0\xbc\x8a\x1f\x22\x78\xd2\x25\x40\xbb\xfb\x52\x7b\x1c\xcb\xeb\xa7\xc9\x1f\x8b\xd9\xdb\xbb\x64\x35\xbb\xb9\xeb\x70\xec\x94\xa9\xf0\x05\xbb\xa2\x7f\xdd\x96\xd0\xa4\xf7\xb8\xed\x47\x0f\xf1\x95\x92\x3c\x3e\x39\x61\x7c\xb2\xf4\x17\x13\x70\xe6\xbb\x12\x1e\xf9\x26\x11\x44\x91\x47\x5d\x31\x46\xa5\x63\xe9\xc4\x87\x4f\xaf\xaf\xaf\x87\xdd\x40\x14\x19\x54\x29\x72\xd4\x98\xfb\xd9\x56\x19\x8f\xdd\x84\x68\xd7\xcd\xbe\x9c\xf6\xb0\x88\xd1\xd7\x56\x47\x64\x05\x79\xa7\x4c\x07\x7b\x52\xf4\x12\x37\xec\x1e\x90\x23\x46\x43\x5e\xbe\x95\x7f\x75\x9d\xf7\x0a\xb6\xa8\x24\x34\x90\x29\x41\xdf\x41\x96\x4c\x19\x59\x15\x56\xf9\x3c\x45\x2b\x24\xf5\x33\xe1\x0a\xbf\xab\x78\x16\xd4\x3e\x27\x9b\x92\xcd\x96\x25\x72\xbb\x25\xfb\xf5\x61\x62\x67\x83\x6e\x9e\xc5\xca\xb1\xc4\x10\x26\x78\x42\x77\xce\x54\x05\xbe\x0d\xcf\xcb\x9f\xfb\xe3\xec\xc9\x62\xe7\x63\x00\x14\xa1\xac\xfd\xee\x93\x9d\xe2\x09\x57\x76\xf2\x50\x6d\x90\x2d\x0a\xfa\xe8\x7c\x95\x1d\x0f\xa3\x4a\x97\xd6\xd4\x31\xf4\x94\x87\x30\x59\xf4\x7e\xc5\x6e\x83\x5d\x97\x85\x7f\xd7\x97\x28\x7d\xe3\x95\xe7\xed\x34\xe1\x56\x50\x8e\xca\x48\xfe\x57\x0f\xf2\x3a\xc7\xd0\xd7\xab\xf5\x7a\x95\x74\x90\xad\x22\x53\x31\xae\x73\x46\x9f\x3b\x93\xb6\xeb\xf2\x78\xc8\x92\x90\x32\xb7\x68\x54\x9d\xa0\x76\x36\x0d\xfb\x74\xda\xc9\x28\x91\xc9\xa5\xdf\xc6\x7c\xa5\x35\x7a\xff\x0f\xdc\x42\x05\xba\x4a\x4e\xa5\x57\x27\xcc\xd0\x0e\xff\x1b\xb3\xf8\xe5\x5f\x9e\x45\xeb\xd1\xb3\x9d\xfe\xa8\x39\x3d\x6a\xee\xcf\xa8\x8d\xb4\xff\x21\xaa\xa4\x50\x8d\xfc\xb5\xa3\x49\xb0\xe8\xed\xd8\x08\x1e\x30\xd8\xd4\xf8\xb1\xee\x65\x1e\x67\x7b\xa2\xfa\x0a\xef\x14\x3e\x60\xfd\x68\x61\xc0\xff\x0e\x00\x00\xff\xff\x49\x54\xdb\x52\x58\x0b\x00\x00")
+var _templatesScCatalogRbacYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd5\x56\x4d\x73\xdb\x36\x10\xbd\xeb\x57\xec\xc8\x3e\xb4\x1d\x91\x4a\x73\xca\x30\xd3\x83\xac\xb8\x29\xa7\xa9\x9c\x91\x94\x64\x32\x9d\x1c\x40\x70\x45\xa1\x86\x08\x16\x00\x2d\x3b\x9e\xfc\xf7\xee\x82\x1f\x91\x22\x39\x13\x37\x9e\xb4\xe1\x85\xf8\xd8\x7d\xfb\x76\xf7\x11\xe0\xc9\xc9\xd7\x3e\x83\x13\x98\x9a\xea\xc6\xaa\x62\xed\xe1\xf1\xa3\x9f\x9f\xc0\x73\x63\x0a\x8d\x90\x96\x32\x1e\xf0\xf6\x0b\x25\xb1\x74\x98\x43\x5d\xe6\x68\xc1\xaf\x11\x26\x95\x90\xf4\x6a\x77\x46\xf0\x1a\xad\x53\xa6\x84\xc7\xf1\x23\xf8\x81\x0d\x86\xed\xd6\xf0\xc7\xa7\x84\x70\x63\x6a\xd8\x88\x1b\x28\x8d\x87\xda\x21\x41\x28\x07\x2b\x45\x41\xf0\x5a\x62\xe5\x41\x95\x20\xcd\xa6\xd2\x4a\x94\x12\x61\xab\xfc\x3a\x84\x69\x41\x88\x06\xbc\x6d\x21\x4c\xe6\x05\x59\x0b\xb2\xaf\x68\xb6\xda\xb5\x03\xe1\x03\x61\x7e\xd6\xde\x57\x2e\x19\x8f\xb7\xdb\x6d\x2c\x02\xdb\xd8\xd8\x62\xac\x1b\x4b\x37\x7e\x91\x4e\xcf\x67\x8b\xf3\x88\x18\x07\x9f\x57\xa5\x46\xe7\xc0\xe2\xdf\xb5\xb2\x94\x6b\x76\x03\xa2\x22\x42\x52\x64\x44\x53\x8b\x2d\x18\x0b\xa2\xb0\x48\x7b\xde\x30\xe1\xad\x55\x5e\x95\xc5\x08\x9c\x59\xf9\xad\xb0\x48\x28\xb9\x72\xde\xaa\xac\xf6\x7b\xd5\xea\xe8\x51\xd2\xbb\x06\x54\x2f\x51\xc2\x70\xb2\x80\x74\x31\x84\xb3\xc9\x22\x5d\x8c\x08\xe3\x4d\xba\xfc\xed\xe2\xd5\x12\xde\x4c\xe6\xf3\xc9\x6c\x99\x9e\x2f\xe0\x62\x0e\xd3\x8b\xd9\xb3\x74\x99\x5e\xcc\x68\xf6\x2b\x4c\x66\x6f\xe1\xf7\x74\xf6\x6c\x04\x48\xb5\xa2\x30\x78\x5d\x59\xe6\x4f\x24\x15\xd7\x11\x73\x2e\xda\x02\x71\x8f\xc0\xca\x34\x84\x5c\x85\x52\xad\x94\xa4\xbc\xca\xa2\x16\x05\x42\x61\xae\xd0\x96\x94\x0e\x54\x68\x37\xca\x71\x37\x1d\xd1\xcb\x09\x45\xab\x8d\xf2\xc2\x87\x95\x83\xa4\x1a\x89\x2c\x69\x41\xe4\x1b\x55\x12\xa1\x5c\x79\x76\x84\x2b\x85\x5b\xb0\x86\xca\xda\xb5\x49\x0a\x2f\xb4\x29\xa8\xc8\xce\xd4\x56\x62\x08\xc0\x3b\x84\x90\xa9\x32\xa7\xf0\xbd\x6d\x61\x4d\x5d\xf5\xb3\xf9\xd9\x64\x4a\xed\xaf\xaa\xc0\x50\x38\x56\x23\xab\x84\x1c\xa3\xc8\x66\x42\x46\xed\x66\x42\xca\x98\xea\xda\x79\xb4\x73\x0a\x7d\xd6\xa0\x72\x52\x60\x6b\x4d\x4a\xe5\x2e\xc2\x27\x5b\x84\x52\x8a\x0d\x3a\x92\x09\x76\x11\xd9\x3a\x0e\x79\x85\x84\x38\x0f\x58\x0b\xd7\xef\xf5\xd4\x4c\xc9\x3e\x04\xe1\x24\x8d\x33\x23\x6c\x1e\xf5\x68\x31\x4c\xb4\x06\xd2\x06\x55\x3a\x43\xdd\xb2\xde\x2b\x46\x6f\x9b\x10\x46\x4d\x2d\x70\xb4\xae\x21\x27\x6b\x8f\x4d\xbc\xce\xd7\x64\x7f\xa1\xf4\x6e\x04\xa4\x1f\xb8\x44\xac\x5a\x3a\xa1\xc6\xce\x2b\xad\x09\xc2\xe2\x0a\x2d\xd2\x67\x14\x34\x6c\x82\x3a\xba\xe2\x52\x77\xe2\x22\x26\x27\xe3\x42\xa2\x47\x29\x73\x43\xbf\xfe\x54\xb9\xbd\x05\xb5\x82\x98\x1b\xf7\x47\xdb\xb7\x0f\x1f\x06\xa2\x52\xed\x51\x91\x00\x59\xc4\x93\x97\x69\x3b\x77\xc1\x94\x6d\x2e\x89\x6c\xb2\xdb\xc4\xc1\x06\xbd\xc8\xa9\x5c\xc9\x00\x42\xb9\x12\x18\xb2\xf3\x8c\x86\x2f\x29\x5d\x75\x4d\x6e\x0e\xed\x15\x29\xb2\xad\x6a\x7c\xf9\xc4\xc5\xca\x24\x41\x93\x9d\xed\xa2\x5e\x35\xb6\x43\xc2\x09\x35\x75\x8c\x08\x70\xd4\x77\xdc\x76\x22\x08\xbf\x89\x78\x1a\x60\x1a\x99\x30\x4a\xd0\x41\x32\x88\xe8\xa4\x50\xcf\x83\x5e\x13\xf8\x73\x78\x14\x6d\xf8\x8e\x22\xf5\xba\x67\xb3\x9f\xc2\x12\x7d\x75\x19\x4d\xf9\x69\x96\xa2\x28\xfa\xe6\x55\x62\x85\xff\x3f\x8b\xd4\x9a\x85\x28\x24\x69\x37\x1c\x41\xb7\xd6\x69\xfa\xb0\x8c\xd2\xa2\xf0\xc8\xa6\xcd\x47\xf4\x71\x24\x8d\xd6\xf4\x05\x11\x57\x5e\x2b\xd0\xf3\x4b\xd3\x91\xcc\xef\x4a\x78\xb9\xe6\x41\x5d\xe5\xad\xff\x36\x2c\xbd\x7b\x20\xf2\x63\x7a\xfb\xfa\x58\x0e\xdd\xce\x41\x2a\x9f\x50\x7c\x10\x3e\x99\x35\x97\xa4\xa6\x1d\x1a\x52\xf3\x89\xba\xbb\x52\xd1\xc5\x10\xe6\xb2\x11\xd8\xa1\xeb\xfe\xc6\x0e\xc2\xfe\x46\x03\xf4\xc5\x79\xfd\x07\xe2\xe7\x6b\xea\xbb\x39\x21\xee\xa8\xdb\xed\x6d\x04\x96\xae\x72\x6c\x0e\xdc\x79\xb8\x9f\xa8\x4e\xbc\x7e\xca\x1c\x20\xf9\x05\xe2\x6e\x85\xcf\xe5\x9e\x66\x6f\xd7\xfa\xef\x6f\x1c\x69\xc8\xe9\x67\x3a\xb2\x73\xaf\xde\xd1\x91\xd3\x2f\x69\x49\x77\x97\xb3\x7d\xb8\x87\xd9\x87\xac\x7b\xff\xbd\x3e\x7d\xbc\x40\x83\x5e\x98\xcd\x03\x35\x8f\xb2\x99\xe3\x8a\x41\xba\xf6\x25\xc0\x3f\x1b\xb1\xa8\xe9\x0a\xb5\xea\x7d\x70\x6e\x01\xc9\xea\x50\x96\xf7\x4e\xbd\x4f\x99\xdd\xef\x48\xd9\xd5\xcd\x7f\x40\xb2\xd3\xb7\xc6\xa9\x91\x58\x68\xdc\xbd\x28\x07\xc3\xfd\x2f\x27\x44\x62\x7c\xa4\x5f\xb4\x56\x22\xbb\x43\xed\xf0\x5f\x08\xe4\xf0\xd7\xec\x81\x74\xf2\x79\x89\x7c\xc7\x6a\xb8\xaf\x10\xbe\xa5\x06\xba\x61\x37\xfa\x07\xb6\xe5\x0b\xd1\x8d\x0e\x00\x00")
+
+func templatesScCatalogRbacYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScCatalogRbacYamlTmpl,
+		"templates/sc/catalog-rbac.yaml.tmpl",
+	)
+}
+
+func templatesScCatalogRbacYamlTmpl() (*asset, error) {
+	bytes, err := templatesScCatalogRbacYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/catalog-rbac.yaml.tmpl", size: 3725, mode: os.FileMode(420), modTime: time.Unix(1792030899, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScControllerManagerConfigYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x53\x4d\x6f\xdb\x46\x10\xbd\xf3\x57\x3c\x88\x97\x16\x10\x65\xc7\xa7\x42\x3d\xc9\xb2\xdb\x12\xb5\xa5\x40\x54\x1a\xe4\x96\xd1\x72\x48\x0e\xbc\xdc\x65\x77\x97\x92\x85\x40\xff\xbd\xe0\x97\x63\x3b\xb7\x86\xb7\xdd\x79\xf3\xde\xdb\x37\xc3\xf8\xa7\xbf\x28\xc6\xda\x36\x67\x27\x65\x15\x70\x73\xfd\xe1\x37\xfc\x69\x6d\xa9\x19\xa9\x51\x8b\x28\x8e\x62\x3c\x88\x62\xe3\x39\x47\x6b\x72\x76\x08\x15\x63\xd5\x90\xaa\x78\xaa\xcc\xf1\x0f\x3b\x2f\xd6\xe0\x66\x71\x8d\x5f\x3a\xc0\x6c\x2c\xcd\x7e\xfd\x3d\x8a\x71\xb6\x2d\x6a\x3a\xc3\xd8\x80\xd6\x33\x42\x25\x1e\x85\x68\x06\x3f\x2b\x6e\x02\xc4\x40\xd9\xba\xd1\x42\x46\x31\x4e\x12\x2a\x84\xef\xfc\x8b\x28\xc6\x97\x91\xc2\x1e\x02\x89\x01\x41\xd9\xe6\x0c\x5b\xbc\xc6\x81\x42\x6f\x18\x00\xaa\x10\x1a\xbf\xbc\xba\x3a\x9d\x4e\x0b\xea\xdd\x2e\xac\x2b\xaf\xf4\x80\xf4\x57\x0f\xe9\xfa\x7e\x93\xdd\x27\x37\x8b\xeb\xbe\xe7\x93\xd1\xec\x3d\x1c\xff\xdb\x8a\xe3\x1c\x87\x33\xa8\x69\xb4\x28\x3a\x68\x86\xa6\x13\xac\x03\x95\x8e\x39\x47\xb0\x9d\xe1\x93\x93\x20\xa6\x9c\xc3\xdb\x22\x9c\xc8\x71\x14\x23\x17\x1f\x9c\x1c\xda\xf0\x26\xad\xc9\x9e\xf8\x37\x00\x6b\x40\x06\xb3\x55\x86\x34\x9b\xe1\x76\x95\xa5\xd9\x3c\x8a\xf1\x39\xdd\xff\xb5\xfd\xb4\xc7\xe7\xd5\x6e\xb7\xda\xec\xd3\xfb\x0c\xdb\x1d\xd6\xdb\xcd\x5d\xba\x4f\xb7\x9b\x0c\xdb\x3f\xb0\xda\x7c\xc1\xdf\xe9\xe6\x6e\x0e\x96\x50\xb1\x03\x3f\x37\xae\xf3\x6f\x1d\xa4\xcb\x91\xf3\x2e\xb4\x8c\xf9\x8d\x81\xc2\x0e\xe3\xf3\x0d\x2b\x29\x44\x41\x93\x29\x5b\x2a\x19\xa5\x3d\xb2\x33\x62\x4a\x34\xec\x6a\xf1\xdd\x34\x3d\xc8\xe4\x51\x0c\x2d\xb5\x04\x0a\xfd\xcd\x0f\x8f\x1a\x56\x64\xdf\x9a\x3e\x27\xcf\xa1\xcb\xc4\x4f\x83\x51\xd6\x04\x67\xb5\x66\x87\x9a\x0c\x95\xec\xe6\x70\x4c\x39\x0a\x67\x6b\x48\xf0\x60\x73\x14\x67\x4d\xcd\x26\x74\x8e\xd7\x15\x99\xb2\x37\x5d\x0f\x6b\xf0\xd5\x2b\x38\x56\xd6\x14\x52\xb6\x8e\xbf\xce\x71\xaa\x44\x55\x70\xec\x03\xb9\xe0\xdf\xc9\x44\xf1\x77\x21\x31\x3e\x74\x5a\xb6\x80\xe3\xee\x40\x5a\x8b\x29\x7b\xc7\xd3\xf6\xff\xef\x2f\x7a\x12\x93\x2f\xb1\xee\x8d\x3d\x52\x13\x51\x23\xe3\x5f\xb0\xc4\xf1\x43\x54\x73\xa0\x9c\x02\x2d\x23\xc0\x50\xcd\xcb\x57\x26\x93\xd1\x62\x32\x3c\x6b\x44\xf8\x86\x14\x2f\xf1\xed\x1b\x16\x9b\xe9\x88\xcb\x25\x02\x34\x1d\x58\xfb\x8e\x09\xdd\x52\x2e\xe1\xd9\x1d\x45\x71\xa2\x28\x90\xb6\x65\xf2\x23\x75\x34\x69\x1f\x9c\x7d\x62\x97\x38\xd6\xe2\x43\x22\x26\xb0\x3b\x92\x5e\x62\xd6\xe9\xdc\xf6\xc5\x5d\x5f\x4b\xc7\x12\x2e\x97\x59\x04\x58\x7f\x48\xa8\x91\x24\x48\xcd\xb6\x0d\x63\xc3\x36\xbb\x5d\x7d\x4c\xf7\xc3\xdd\x88\x74\xec\xcf\x46\xbd\xa7\xde\xf5\xb7\xef\x49\x1b\x76\xfd\x22\x25\x8d\xed\x67\x91\xd4\xf4\x9c\x1c\x48\x3d\xd9\xa2\x98\x24\x26\xcc\xc7\x01\xf2\x48\xcf\xb7\x03\xe0\x45\x4f\x59\xa3\x44\xcb\xc0\xe4\x38\xb8\x73\x92\xb7\x43\xd3\x8b\xfa\x6b\xcc\xae\x83\xdc\x8d\x88\x91\xa5\x8b\x4d\xf3\x91\x27\xbf\xeb\x97\x08\x1f\x87\xe1\x3c\xd8\xf2\x81\x8f\xac\x71\xb9\xcc\xa2\xff\x06\x00\xf9\x47\x63\x94\x67\x05\x00\x00")
+
+func templatesScControllerManagerConfigYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScControllerManagerConfigYamlTmpl,
+		"templates/sc/controller-manager-config.yaml.tmpl",
+	)
+}
+
+func templatesScControllerManagerConfigYamlTmpl() (*asset, error) {
+	bytes, err := templatesScControllerManagerConfigYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/controller-manager-config.yaml.tmpl", size: 1383, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScControllerManagerDeploymentYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x58\x5b\x6f\xe3\xb6\x12\x7e\xcf\xaf\x20\x94\x02\xed\x02\x91\x93\x4d\xf7\xa0\x85\x0f\xfa\xa0\x38\xce\xae\x10\xc7\x36\x2c\xef\xb6\x79\x32\x68\x69\x6c\xb3\x91\x44\x95\xa4\xec\xf8\x04\xfb\xdf\x3b\x24\x25\x47\x37\xa7\xd9\xd3\x02\xa9\x5f\x12\x91\x73\xf9\x66\x38\x37\xf2\xf4\xf4\xef\xfe\x4e\x4e\xc9\x80\x67\x7b\xc1\xd6\x1b\x45\x2e\x2f\xde\xff\x44\x3e\x72\xbe\x8e\x81\xf8\x69\xd8\x3b\xd1\xdb\x23\x16\x42\x2a\x21\x22\x79\x1a\x81\x20\x6a\x03\xc4\xcb\x68\x88\x7f\x8a\x9d\x33\xf2\x05\x84\x64\x3c\x25\x97\xbd\x0b\xf2\x83\x26\x70\x8a\x2d\xe7\xdd\x7f\x51\xc2\x9e\xe7\x24\xa1\x7b\x92\x72\x45\x72\x09\x28\x82\x49\xb2\x62\xa8\x04\x1e\x43\xc8\x14\x61\x29\x09\x79\x92\xc5\x8c\xa6\x21\x90\x1d\x53\x1b\xa3\xa6\x10\x82\x30\xc8\x7d\x21\x82\x2f\x15\x45\x6a\x8a\xf4\x19\x7e\xad\xaa\x74\x84\x2a\x03\x58\xff\x36\x4a\x65\xb2\x7f\x7e\xbe\xdb\xed\x7a\xd4\xa0\xed\x71\xb1\x3e\x8f\x2d\xa5\x3c\x1f\xf9\x83\xe1\x38\x18\xba\x88\xd8\xf0\x7c\x4e\x63\x90\x92\x08\xf8\x23\x67\x02\x6d\x5d\xee\x09\xcd\x10\x50\x48\x97\x08\x33\xa6\x3b\xc2\x05\xa1\x6b\x01\xb8\xa7\xb8\x06\xbc\x13\x4c\xb1\x74\x7d\x46\x24\x5f\xa9\x1d\x15\x80\x52\x22\x26\x95\x60\xcb\x5c\xd5\xbc\x55\xc2\x43\xa3\xab\x04\xe8\x2f\x9a\x12\xc7\x0b\x88\x1f\x38\xe4\xca\x0b\xfc\xe0\x0c\x65\xfc\xea\xcf\x3f\x4d\x3e\xcf\xc9\xaf\xde\x6c\xe6\x8d\xe7\xfe\x30\x20\x93\x19\x19\x4c\xc6\xd7\xfe\xdc\x9f\x8c\xf1\xeb\x86\x78\xe3\x7b\x72\xeb\x8f\xaf\xcf\x08\xa0\xaf\x50\x0d\x3c\x66\x42\xe3\x47\x90\x4c\xfb\x11\x22\xed\xb4\x00\xa0\x06\x60\xc5\x2d\x20\x99\x41\xc8\x56\x2c\x44\xbb\xd2\x75\x4e\xd7\x40\xd6\x7c\x0b\x22\x45\x73\x48\x06\x22\x61\x52\x9f\xa6\x44\x78\x11\x4a\x89\x59\xc2\x14\x55\x66\xa5\x65\x94\x0d\x91\x6b\xc8\x62\xbe\x4f\x20\x55\x46\x87\x04\xb1\xc5\x6d\x12\x52\x45\x63\xbe\xc6\xb3\x4a\x95\xe0\x71\x8c\xac\x09\x4d\x51\x9f\x30\x6c\x7f\x3f\x76\x1f\x58\x1a\xf5\x2b\xda\x4f\x68\xc6\x8a\x58\xec\x93\xa7\x27\xd2\xf3\xa6\x7e\xf1\x2d\x7b\x15\x90\x5f\xbf\x9e\x24\xa0\x68\x84\xf8\xfa\x27\x84\xa4\x34\x81\x7e\x05\xa5\x5b\xa0\x2c\xb6\x24\xc6\x0f\x58\x79\xe3\xf2\x53\x8b\x20\xe8\xbf\x25\xc4\x52\x8b\x20\x3a\x5c\xfa\xa5\xe5\x6e\x61\xb9\xdb\x21\x53\x3b\x5f\x73\x08\x30\xe1\x25\xad\xe0\xc1\x81\xf0\xce\xd2\xcd\x8a\x6d\xab\x48\x42\x0c\xa1\xe2\xc2\xaa\x4a\xa8\x0a\x37\xa3\x8a\xee\xd7\x6b\x27\x44\x01\x06\x08\x55\x50\x88\xaa\xb8\x41\xff\xe2\x9a\xd4\xd7\xcb\x7d\x7a\x72\x09\x5b\xe9\xf8\xeb\xdd\xf1\x94\x21\x54\x1d\x4c\x3d\x5f\x62\xdc\x58\x13\x8c\xb4\x14\x4b\x80\x0d\xa5\x7e\xc9\x52\xa5\x3f\x10\x12\x92\x09\x8e\xd8\x36\x90\xcb\x1e\xe3\xe7\x32\x14\x34\xc3\x33\x70\x94\xc8\xc1\x39\x42\x94\x71\xa1\x90\xe4\xe7\x0f\x1f\x3e\x38\x47\xe5\x6c\x40\x9f\xb5\x29\x0f\x06\x01\xa4\x91\x56\x5b\x82\x69\x00\x26\xe4\x94\xcc\x31\xda\x97\x82\x3f\x60\x1c\x11\x4c\x73\x8c\x6a\xb4\x1c\xab\xc0\x46\xf0\x7c\x6d\x0b\x95\x64\x11\x84\x54\x9c\x91\xdd\x46\x57\x35\xbd\xf4\x90\xa3\x23\x41\x55\xe4\x60\x36\x91\xe9\x01\x8d\x11\x63\x99\x21\xcc\x51\xac\x46\x6f\x4b\x1f\x53\x98\xc8\xbb\x94\xcc\x47\x41\xef\xc0\x5f\xa8\xe8\x31\x0d\x50\x9b\xc2\xd2\xdf\x31\x24\x5a\x2e\x51\x82\xae\x30\xb5\x7b\x2d\x7a\x2c\xb5\x71\x1e\x81\x9f\x2e\x39\x66\xf1\x14\xb5\xc9\x96\xb3\xb0\x64\x28\x8a\x30\xb8\x2e\xc3\x15\xc3\xb4\x17\x1f\x19\x48\xb3\xa6\x81\x4b\x5d\x04\xf5\xc7\x2d\x9a\x29\x52\x50\xb8\x87\xb9\xd6\xab\xba\xfd\x71\xff\xac\x1c\x23\x66\xc5\xd6\x7d\xf2\xfd\x93\xb3\xe1\x71\xe4\xd9\xd2\xaa\x03\xe1\x73\xaa\x58\x3c\xd5\xd4\x46\xb5\x74\xfa\x44\x1b\xf4\xf5\xfb\xe6\xe9\x14\xff\x1a\x5f\x14\x29\x64\xfe\xb7\xb1\xe9\x85\x21\x9a\xa5\xc6\x26\x93\x9d\xce\x8c\x0a\x6a\x94\x28\xcb\x39\x9c\x7a\x8b\xf6\x7a\x1c\x4c\x39\x42\xdc\x3f\x87\x42\x94\x4a\xbb\x74\x24\x61\x6b\x2c\x0d\xec\xe6\x58\x3b\x59\x06\xc6\x31\x35\x2d\x76\xe9\x39\x3f\x4c\xcd\x41\xec\x3a\xfe\x2a\x81\x99\x3e\x2f\x5b\x5a\x81\xc5\x1c\x8e\x92\xbb\xd6\x2b\x07\xb3\xdb\x9e\x2d\xf5\x05\x40\x05\xa6\x49\x8d\x5b\x16\x6b\x35\x4d\x5d\x84\xaf\x57\x33\xc9\x6c\x4b\xa9\x30\xf3\xac\x52\x1a\x0a\x25\x1d\x64\x6e\x51\xb0\x9d\xb2\x22\xd7\xce\xf2\x0b\x8d\x73\xa8\x92\x13\xb2\xd5\x4b\x05\x7d\xb9\xfd\x12\xbc\xe3\xa0\x5b\x47\xf8\x89\x4b\xe5\xe1\xdc\x22\xab\x6e\x38\x25\x33\x90\x3c\xde\x16\x09\x53\x16\x0f\x93\x3c\x71\x2e\x15\xf6\x41\x3c\x7a\x12\x71\x24\xc0\x82\x58\x66\xcd\xe6\x59\x56\xcd\x05\xaf\x53\x8a\x00\xb3\xc2\x46\x7f\x6a\x0c\x2c\xcd\xd7\x72\x4d\xb4\xd4\xa4\x7e\x2a\x57\xbf\xfd\xf8\x1a\x4e\x99\x0a\x86\xe5\x5b\xed\x07\x31\x95\x15\x69\x59\x75\xd9\x26\x66\x79\x60\x53\x01\x2b\xf6\x88\xa4\x8d\xce\x52\xee\x07\xf9\xca\xee\x77\xa9\x1b\xf3\x08\x3c\x0c\x3c\xa6\xb0\xfe\x61\xe5\xac\xa8\xd4\xa5\x0f\x9b\x89\x4d\xd1\xb2\xd1\x11\x47\x87\xa9\x57\x6c\x39\x47\x04\x34\x4a\x0c\x31\xe3\x00\x8e\x9a\x26\xbd\xea\x71\xd7\xd9\x56\xed\x8f\x25\xf8\x69\xed\x2c\xca\xcd\xc0\x5a\xe6\xeb\x8d\xaa\xa7\x0d\xe5\x34\x8f\xe3\xb2\xa8\xf8\xab\x31\x57\xe8\x18\xa9\xe7\x98\x92\x0a\x3f\x79\x2e\x42\xa8\x74\x64\x62\x86\x54\x90\xaa\xb6\x86\x78\xb3\xbc\x4f\xde\x5f\x5c\x24\xb5\xd5\x04\x12\x2e\x50\xfa\xe5\xc5\x1d\xab\x6c\x98\x99\xee\x9b\x04\xfc\xa7\x2a\x00\xd2\x6d\xbf\x95\x8e\xb7\x3f\x07\x8b\xb1\x77\x37\x0c\xa6\xde\x60\xd8\xcc\xbe\x1b\x6c\x7c\x75\x75\x2b\x06\x71\x34\x83\x55\x7d\xb5\x58\x9f\x52\xb5\xe9\x1f\x86\x93\xde\x61\x0a\x6b\x29\xbd\x9a\x4d\x6e\x87\xb3\xc5\x6c\x38\xf2\x83\xf9\xc2\x1f\xcf\x87\xb3\x2f\xde\xe8\xaf\xb5\xdb\x86\x74\x47\xb3\x5b\xd8\x77\x80\x38\x76\xd0\xae\xe5\x6b\x50\x3f\x00\x3a\xc8\x66\xb9\x2b\x20\xc6\xae\xe7\xb2\x14\xb3\x1c\x75\xb7\x00\x4f\x82\xab\x05\x76\xca\xc5\xdc\xbf\x1b\xe2\x98\xff\x16\x48\xb9\x5c\xba\x38\x28\xbb\x8a\x25\xc0\x73\xd5\x82\x38\x1b\x06\xf7\xe3\xc1\x9b\x3a\x13\xe3\x7e\x9f\x86\x2f\x78\x71\x3a\x9c\x79\xfa\x3a\xb4\x98\x4e\x46\x23\x7f\xfc\x71\x71\xe7\xfd\xb6\xb8\xf2\x06\xb7\x93\x9b\x9b\x37\xf1\x29\xde\x9b\xcc\x3c\xe3\x66\xc8\x82\xb3\x2c\xf2\x3c\xba\x4b\x1a\x3e\xf0\xd5\xaa\xc3\xc3\x78\x9f\x1b\xf8\x23\xdf\xda\x30\x1b\xce\x67\xf7\x8b\xeb\xcf\xd6\xa4\xb7\xf1\x37\xee\x85\x0c\x7b\x8a\xb1\x41\x80\x12\x7b\x37\xca\xad\x49\x2d\xf8\xa3\xc9\xc7\xc5\x68\xf8\x65\xf8\x26\xa1\xa1\x2f\x20\x31\x6c\x21\x3e\xde\x94\xcd\x38\x79\x7c\x88\xc7\x98\xd7\x73\x6d\xad\x1f\x1f\x19\xec\xed\x18\xdb\x72\xc0\xa7\xf9\x7c\x1a\x2c\xa6\xb3\xc9\x6f\xf7\x47\xc6\x8c\xa3\x98\x9c\x4e\x61\xff\x94\xac\xf1\xe4\xdb\x24\x8d\xf9\xb3\xac\xd7\x8c\x3b\x86\x7a\xe0\x75\x4d\x63\x41\x30\x5a\x0c\x86\xb3\xf9\xe2\xda\x9f\xb5\xd5\x6b\xed\x05\xf3\x35\x13\x9d\x0d\x17\xdb\xb7\x58\xcb\x6a\x5f\x79\xa1\xd1\xba\xc4\x75\xed\xc5\xc9\xd5\x17\xa7\xea\x00\x63\xaf\x34\x85\x09\x29\xe8\x71\xe8\x86\x26\x2c\xde\x13\x87\x65\xdb\x0f\x4e\x1d\xbc\xeb\x2e\x59\x1a\xb9\x34\x8a\xf4\x03\x4a\x55\x4e\xbf\xef\x74\x81\xc4\x1d\x17\x03\x90\x46\x88\xca\xdc\xca\x7f\xd1\xb6\x8d\xcc\xc2\x50\x7f\xd7\x6f\x16\x36\xbe\xfc\xeb\x41\x99\x08\x4d\xf5\x45\x04\xba\x2c\x2a\x82\x3e\xa1\x99\xdb\xd5\xf0\x9c\xef\x7e\xa8\xb5\xd8\x77\x47\xe0\xb9\xdb\x3a\xd3\x21\x5b\xdf\x39\x35\xc5\xc7\x6b\x2c\x32\x35\x7a\x40\x83\xf5\x2f\x7b\x1d\x4a\xe8\xee\xcc\x0d\x41\xc7\x5b\x11\x4a\x68\xb4\xca\x26\x6b\x57\xc5\x65\x49\x9e\x94\x55\xb7\xab\x7e\x69\xa9\x2f\xb5\x8e\x96\x8b\x5e\x57\x16\x8d\xc3\x5e\x28\xe9\x0d\xb1\x2b\xa0\x7a\xee\x74\xd7\x38\x9d\x56\x43\x6e\x22\xd8\x9a\xa5\x54\x3f\x2b\xfa\x11\x8e\x81\x38\xae\xfe\xa2\x2f\xc3\xaf\x62\xf6\xf4\x69\x5e\x61\x2c\x23\xf7\xa4\xf4\x8d\xd4\xc1\x09\x7f\xe0\x35\x2a\xb8\x7a\x7e\x0b\x23\xce\x65\xef\x7d\x3d\x11\x32\xf3\x1e\xd0\xc8\x3d\x33\xfe\x4e\xcd\xab\x8a\x4e\xaa\xc3\xee\x96\xc7\x79\x02\x77\xfa\x1a\x2d\xdb\x73\x60\xeb\xb5\x08\x2a\xf9\x89\x03\xa5\x66\xb3\xf3\xdd\xf9\x96\x8a\x73\x91\xa7\xe7\x0f\x87\x57\x04\xb7\xc1\x5d\x1b\x7b\x69\x34\x49\xe3\xbd\x7d\x20\xf8\xbf\x6a\x94\x29\xe7\x28\xba\x1b\x4e\xbb\x46\xbd\xa8\xbc\x91\x78\x9a\x02\xfd\x25\x25\x8a\x58\x42\xb5\xd1\xe9\xc7\xa6\x8f\xa0\xea\xbd\x2f\x6b\xbb\xd5\x2c\x5b\xc7\x6c\x80\xc6\x6a\xf3\xbf\xda\x56\xf9\x76\x65\x9a\x4f\x65\x67\x45\x59\x8c\x01\x31\xdf\x60\x42\xeb\xc7\x15\x1c\xe2\x2b\xbb\xfa\xd2\xc3\x68\x7c\x0d\x31\xdd\x07\x3a\x9c\x23\xa9\xa7\xfc\x0a\x05\xc6\x0a\xe3\x51\xf7\x9e\xcc\x43\xbc\x7c\xc8\x23\xb2\x8b\xac\x3d\xb0\x5e\x9e\x3c\xdf\x2f\xb6\xf0\xef\xf0\xc5\x8f\x6f\xec\x0b\x9b\x2b\xad\x3b\xe4\x8b\x49\x82\x8d\x4d\xd4\x7d\x64\x57\xec\x25\x1a\xeb\xa5\x7d\xdf\x69\x66\x16\xde\x67\x93\xda\x9d\xce\xb5\x13\x93\x8a\x65\x2f\xac\x51\x96\xbe\x3d\x88\x6a\xec\x57\x18\xf1\x9f\x17\x19\xf5\xfe\xeb\x53\xf1\x68\x22\xbe\x6c\x72\xc7\x80\x78\x90\x50\x49\xc5\x3f\x01\xf0\xf3\xee\xcb\x0e\x1b\x00\x00")
 
 func templatesScControllerManagerDeploymentYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -188,112 +350,112 @@ func templatesScControllerManagerDeploymentYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/controller-manager-deployment.yaml.tmpl", size: 2904, mode: os.FileMode(416), modTime: time.Unix(1527273967, 0)}
+	info := bindataFileInfo{name: "templates/sc/controller-manager-deployment.yaml.tmpl", size: 6926, mode: os.FileMode(420), modTime: time.Unix(1792032658, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScEtcdClusterWithBackupYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x90\xcd\x8e\xda\x40\x10\x84\xef\x7e\x8a\x8a\x73\x0d\x0e\x3f\x89\x14\xf9\x08\xca\x81\x43\xa4\x48\x44\xdc\x9b\x71\x07\x46\xd8\xd3\xa3\xe9\xb6\xb5\x0b\xe2\xdd\x57\x63\x7b\x77\xb9\x76\x55\x7d\x53\x35\x14\xfd\x91\x93\x7a\x09\x35\x4a\x36\xd7\x54\x0d\x19\x9d\x48\xb9\x72\x92\x58\xb4\x72\xd2\x7d\x1f\x56\x27\x36\x5a\x97\xc5\xd5\x87\xa6\x46\xf9\xdb\x5c\xb3\x6b\x7b\x35\x4e\x65\xd1\xb1\x51\x0e\xd5\x05\x10\xa8\xe3\x19\xb4\x70\xef\x86\xe9\xac\x91\x5c\xd6\x94\xd3\xe0\x1d\x2f\x1c\x19\xb5\x72\x2e\x0b\x8d\xec\x72\x56\xfd\x8d\x6b\xdc\xef\xa8\x9e\xf0\x07\x7f\x63\x3c\x1e\x05\x30\x7c\xd4\xdc\x54\xab\xea\x57\xc6\x9e\xc8\x5d\xfb\x98\xb3\xc0\x57\xe8\x45\x92\x41\x03\x45\xbd\x88\xc1\x07\xe3\x34\x50\x8b\xff\x92\x60\xac\xe6\xc3\xf9\x1b\x1a\x41\x10\x43\xaf\x0c\xbb\x78\x85\x0f\x88\x49\x9a\xde\x99\x97\xf0\x65\x24\x4d\xd4\xfd\x1c\xdf\x87\x03\x3b\xc9\xab\x37\xcb\x25\x46\x43\x47\x2f\xdb\xd1\xa3\x35\x7e\x8e\x17\x35\x49\x74\xe6\x7f\xaf\x31\x2f\xfc\x9b\x9b\xaa\x71\xb0\xa3\xb4\x7d\xc7\xe5\xe8\x89\xc3\x54\x14\x18\xc6\x6b\x5e\xb6\x0f\x7f\xb6\x35\x56\xcb\xf5\x8f\x59\x9a\x41\xbb\x96\x54\x3f\xff\x62\x7a\xed\xf0\xa4\xe5\x2f\x79\x0b\x00\x00\xff\xff\x29\xb2\xaf\x3a\xbb\x01\x00\x00")
+var _templatesScControllerManagerProxyCaYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x53\xcd\x6e\xdb\x30\x0c\xbe\xfb\x29\x88\xf4\xb2\x01\xb1\xd3\xf6\x34\x64\x27\x2f\xed\x36\x63\x9d\x33\xc4\xe9\x8a\x1e\x69\x99\x71\x84\xda\x92\x2a\xc9\x71\x83\xa2\xef\x5e\xca\x71\xb6\x16\x1b\x7a\xa9\x2f\x36\x45\xf2\xfb\xa1\xe8\x93\x93\xf7\x3e\xd1\x09\x2c\xb4\xd9\x5b\x59\x6f\x3d\x9c\x9f\x9e\x7d\x82\x6f\x5a\xd7\x0d\x41\xa6\x44\x12\x85\xf4\x95\x14\xa4\x1c\x55\xd0\xa9\x8a\x2c\xf8\x2d\x41\x6a\x50\xf0\x6b\xcc\x4c\xe1\x37\x59\x27\xb5\x82\xf3\xe4\x14\x3e\x84\x82\xc9\x98\x9a\x7c\xfc\xcc\x08\x7b\xdd\x41\x8b\x7b\x50\xda\x43\xe7\x88\x21\xa4\x83\x8d\x64\x12\x7a\x10\x64\x3c\x48\x05\x42\xb7\xa6\x91\xa8\x04\x41\x2f\xfd\x76\xa0\x19\x41\x58\x06\xdc\x8e\x10\xba\xf4\xc8\xd5\xc8\xf5\x86\xa3\xcd\xcb\x3a\x40\x3f\x08\x0e\xcf\xd6\x7b\xe3\xe6\xb3\x59\xdf\xf7\x09\x0e\x6a\x13\x6d\xeb\x59\x73\xa8\x74\xb3\xab\x6c\x71\x99\x17\x97\x31\x2b\x1e\x7a\xae\x55\x43\xce\x81\xa5\xfb\x4e\x5a\xf6\x5a\xee\x01\x0d\x0b\x12\x58\xb2\xcc\x06\x7b\xd0\x16\xb0\xb6\xc4\x39\xaf\x83\xe0\xde\x4a\x2f\x55\x3d\x05\xa7\x37\xbe\x47\x4b\x8c\x52\x49\xe7\xad\x2c\x3b\xff\x6a\x5a\x47\x79\x6c\xfa\x65\x01\xcf\x0b\x15\x4c\xd2\x02\xb2\x62\x02\x5f\xd2\x22\x2b\xa6\x8c\x71\x93\xad\xbf\x2f\xaf\xd7\x70\x93\xae\x56\x69\xbe\xce\x2e\x0b\x58\xae\x60\xb1\xcc\x2f\xb2\x75\xb6\xcc\x39\xfa\x0a\x69\x7e\x0b\x3f\xb2\xfc\x62\x0a\xc4\xb3\x62\x1a\x7a\x30\x36\xe8\x67\x91\x32\xcc\x91\xaa\x30\xb4\x82\xe8\x95\x80\x8d\x3e\x08\x72\x86\x84\xdc\x48\xc1\xbe\x54\xdd\x61\x4d\x50\xeb\x1d\x59\xc5\x76\xc0\x90\x6d\xa5\x0b\xb7\xe9\x58\x5e\xc5\x28\x8d\x6c\xa5\x47\x3f\x9c\xfc\x63\xea\xb0\x22\x8b\x14\x4a\x4e\xf1\xa4\xc6\x1b\xa1\x7a\x90\x63\xac\x7e\xd8\x0f\x07\x42\x2b\x6f\x75\xd3\x70\x77\x8b\x8a\x29\x2d\x08\x6c\x1a\x17\x92\x0c\x50\x5a\x7d\xc7\x3b\xc4\x91\xd5\x5d\xbd\x9d\x42\x2d\x77\xa4\x0e\x9b\x10\xc7\x7f\x9b\xe3\xb1\x39\x1e\x90\x63\x81\x71\x58\xa3\x41\xc4\xfb\xff\x84\xc7\x47\x90\x1b\x48\x16\x7f\xd8\x7e\x1e\xc8\x7e\x05\x2e\xb6\xf8\xf4\x14\xa1\x91\xe3\xae\xcf\x61\x77\x16\xdd\x49\x55\xcd\x79\xcc\xc2\x92\x8f\xfc\xde\xd0\x1c\x96\x06\xef\x3b\x8a\x5a\xf2\x58\xa1\xc7\x79\x04\xa0\xb0\xe5\xc4\x1b\x26\xc6\x1a\xc7\x7b\xca\x85\x2c\x23\xc9\x8f\x61\x20\x05\xbe\xa7\x92\x1a\x17\xb0\x20\xac\xe5\x1c\x1c\xd9\x1d\x5f\x00\xf7\x7a\x6c\x74\xfd\x9f\x09\x45\x47\xf6\x23\x49\x22\xac\x3f\x80\xbf\x65\x90\xf3\xa4\xaa\xf0\xf5\x0c\x56\x68\xfd\x9c\x5b\x04\x00\x00")
 
-func templatesScEtcdClusterWithBackupYamlTmplBytes() ([]byte, error) {
+func templatesScControllerManagerProxyCaYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_templatesScEtcdClusterWithBackupYamlTmpl,
-		"templates/sc/etcd-cluster-with-backup.yaml.tmpl",
+		_templatesScControllerManagerProxyCaYamlTmpl,
+		"templates/sc/controller-manager-proxy-ca.yaml.tmpl",
 	)
 }
 
-func templatesScEtcdClusterWithBackupYamlTmpl() (*asset, error) {
-	bytes, err := templatesScEtcdClusterWithBackupYamlTmplBytes()
+func templatesScControllerManagerProxyCaYamlTmpl() (*asset, error) {
+	bytes, err := templatesScControllerManagerProxyCaYamlTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/etcd-cluster-with-backup.yaml.tmpl", size: 443, mode: os.FileMode(416), modTime: time.Unix(1513698304, 0)}
+	info := bindataFileInfo{name: "templates/sc/controller-manager-proxy-ca.yaml.tmpl", size: 1115, mode: os.FileMode(420), modTime: time.Unix(1792025871, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScEtcdOperatorDeploymentYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x91\xcf\x4e\xf3\x30\x10\xc4\xef\x7e\x8a\x7d\x81\xa6\x5f\x2e\xdf\xc1\xb7\x8a\x96\x5b\x4b\x04\x12\x12\xa7\x6a\xeb\x4c\x8b\x85\xff\x61\x6f\x22\xfa\xf6\x28\xd0\x84\xf6\x10\x4e\xec\x69\x3d\xde\x99\x9f\xe5\xe5\x64\x9f\x91\x8b\x8d\x41\x13\x3e\x04\x61\x68\xcb\xb2\xaf\x0f\x10\xae\xd5\x9b\x0d\xad\xa6\x35\x92\x8b\x67\x8f\x20\xca\x43\xb8\x65\x61\xad\x88\x02\x7b\x68\x82\x98\x76\x11\x13\x32\x4b\xcc\x17\xb5\x24\x36\xd0\x54\x90\x7b\x6b\xb0\x30\x2c\xec\xe2\x49\x95\x04\x33\x18\x33\x92\xb3\x86\x8b\xa6\x5a\x11\x09\x7c\x72\x2c\x18\x6e\x88\xae\x01\x43\x39\x3e\xc0\x95\xf1\x34\x07\x25\x1a\xb3\xbf\xfa\x6f\xee\xca\x98\xd8\x05\xd9\xcd\x38\x88\x4c\x0c\xc2\x36\x20\x4f\xf9\x8b\xd9\xfc\xa1\xac\xe7\x13\x34\xbd\x77\x7c\xae\x6c\x5c\x9a\x98\x11\xcb\xf2\x66\x56\xf7\xff\xaa\xff\x55\x3d\x59\x10\xfa\x9f\xb7\x8f\xe9\xdb\x97\x7d\xf3\xb0\xde\xef\x56\xdb\xcd\x53\xb3\xba\xdb\x4c\x03\x44\x3d\xbb\x0e\xf7\x39\x7a\x7d\x25\x12\x1d\x2d\x5c\xfb\x88\xe3\xad\x7a\xd1\x1b\x96\x57\x3d\x7d\x5c\x35\x2d\xe0\x37\xee\xdf\x23\xd5\x67\x00\x00\x00\xff\xff\xdd\x5d\x0f\xb6\x4b\x02\x00\x00")
+var _templatesScEtcdClusterWithBackupYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x52\xc1\x6e\xdb\x30\x0c\xbd\xe7\x2b\x38\xf7\xd2\x02\xa9\x9b\x74\xeb\xc5\xb7\xb6\xd8\x21\xc0\x36\x14\x48\xd1\x3b\x2d\x33\xb1\x56\x5b\xd2\x44\x39\x6d\x17\xf4\xdf\x47\x49\x76\x16\x64\x43\x4f\x96\xc9\xc7\xc7\xf7\x48\xa2\xd3\x4f\xe4\x59\x5b\x53\x41\x41\x41\x35\x65\x83\x01\x6b\x64\x2a\x95\xf5\x64\x59\x3e\xfd\xd5\x6e\x59\x53\xc0\xeb\x62\xf6\xac\x4d\x23\xc0\xaf\x02\xbc\xef\x06\x0e\xe4\x8b\x59\x2f\xa9\x58\x54\xcd\x00\x0c\xf6\x34\x12\x5d\xaa\x09\x90\xc3\xec\x50\xc5\xdc\x7e\x0f\xe5\x8f\xe9\x1f\xde\xdf\x8b\x19\x3b\x52\xb1\x98\xf5\x6f\x01\xc4\xfc\x11\xff\x5a\x82\x82\x92\xf4\xee\xa0\xf3\x04\x32\x1a\x48\x5c\xfb\xfd\x25\xe8\x0d\x58\x0f\xe7\xf4\x0b\xce\x3b\x32\xd2\xcd\x36\x74\xeb\x55\xab\x03\xa9\x30\x78\xe2\x0b\x58\x5e\x40\xb9\xe2\xa0\x6d\xa6\x76\xb6\xa9\xa6\xd2\x0f\xcb\x32\x5c\x0c\x49\x6e\x4d\x9d\x24\xac\xaf\x52\x04\x92\xf0\xdb\x87\xd5\xa8\x86\xcb\x58\xfb\x0d\x6b\xea\xa4\x28\x8b\x96\xe1\xd1\xeb\x7f\x88\x61\x71\xd0\x4e\xa6\x89\x3d\x46\x2d\xc7\x1a\x01\xd0\x18\x1b\x30\x44\xf2\xa9\xe7\x19\x3c\xb6\x04\x3d\xf5\xb5\x74\x05\x85\x5d\x07\x84\xaa\x05\x1b\x5a\xf2\x50\xbf\x81\x7c\xb5\x8f\xfe\xf2\x0e\xe6\xf0\xd2\x6a\xc9\x4b\x58\xc6\xdd\x90\x42\x7f\x60\x6a\xac\x28\x91\x0e\xe0\xed\x10\x68\x2e\xed\x9a\x84\x43\xa7\x81\xc9\xcb\xf8\x53\x03\x8e\xc1\x5e\xb8\x1d\x32\x6b\xb3\x05\x1d\x18\xec\x8b\x29\x47\xa2\x91\xb6\xd4\x51\x7a\xa9\xed\x95\x36\x3f\xc5\xa7\x4c\x60\x83\x1d\xd3\x3f\x36\x4f\x1c\xc7\xbd\xde\xa1\x7a\x1e\x5c\xb6\x5d\xa7\x77\xf6\x7b\x06\xdc\x5a\x1f\x80\x0d\x3a\x79\x05\x19\xa8\xac\x7f\x87\x1d\x6c\x64\xe1\x81\xa4\xa3\xd9\xce\xc5\x48\xb2\x31\x30\x89\x54\xcd\x82\x02\xe7\x6d\x33\xa8\x38\xba\x4f\x89\x29\xb3\xae\xc6\xf2\x95\x59\x93\xb2\xf1\xb2\x3f\x2f\x16\x90\x00\x3d\xbe\x66\x15\x5c\xc1\x4d\x8a\xb0\xac\x1a\xb7\xf4\xf8\xe6\xe2\x15\x3f\xc4\x2d\xcb\xf1\x99\xf0\x64\xbb\xa1\xa7\x22\x61\xdc\x6e\x5a\xcc\x2e\x45\xe3\xf1\xae\xcc\xf7\xbb\x0a\x96\x8b\xeb\x2f\xd3\x80\x32\xd1\x7d\x27\xf3\xfb\x7b\xee\xb9\xdb\xfa\x28\x77\x32\xa0\x3f\xe6\x6c\xc5\xa5\xaa\x03\x00\x00")
 
-func templatesScEtcdOperatorDeploymentYamlTmplBytes() ([]byte, error) {
+func templatesScEtcdClusterWithBackupYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_templatesScEtcdOperatorDeploymentYamlTmpl,
-		"templates/sc/etcd-operator-deployment.yaml.tmpl",
+		_templatesScEtcdClusterWithBackupYamlTmpl,
+		"templates/sc/etcd-cluster-with-backup.yaml.tmpl",
 	)
 }
 
-func templatesScEtcdOperatorDeploymentYamlTmpl() (*asset, error) {
-	bytes, err := templatesScEtcdOperatorDeploymentYamlTmplBytes()
+func templatesScEtcdClusterWithBackupYamlTmpl() (*asset, error) {
+	bytes, err := templatesScEtcdClusterWithBackupYamlTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/etcd-operator-deployment.yaml.tmpl", size: 587, mode: os.FileMode(416), modTime: time.Unix(1513698306, 0)}
+	info := bindataFileInfo{name: "templates/sc/etcd-cluster-with-backup.yaml.tmpl", size: 938, mode: os.FileMode(420), modTime: time.Unix(1792032658, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScEtcdOperatorRbacBindingYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\xcd\x31\x4e\xc5\x30\x0c\xc6\xf1\x3d\xa7\xf0\x05\x5a\xd4\x0d\x65\x03\x06\xf6\x22\xb1\xbb\x8e\x29\xa6\x69\x1c\x39\x4e\x07\x4e\x8f\x50\xdf\xf6\xd4\xb7\xda\xfa\x7d\x7f\xac\xf2\xc9\xd6\x44\x4b\x04\x5b\x90\x46\xec\xfe\xad\x26\xbf\xe8\xa2\x65\xdc\x9e\xdb\x28\xfa\x74\x4c\x0b\x3b\x4e\x61\x93\x92\x22\xbc\xe5\xde\x9c\x6d\xd6\xcc\xaf\x52\x92\x94\x35\xec\xec\x98\xd0\x31\x06\x80\x82\x3b\x47\x60\xa7\x34\x68\x65\x43\x57\x0b\xa6\x99\x67\xfe\xfa\x7f\x63\x95\x77\xd3\x5e\x1f\xf4\x02\xc0\x5d\xe9\x62\xb8\xf5\xe5\x87\xc9\x5b\x0c\xc3\xcd\x7c\xb0\x1d\x42\xfc\x42\xa4\xbd\xf8\x05\x3b\xaf\xad\x22\x71\x84\x76\x8a\x81\xd0\x31\xeb\x1a\xfe\x02\x00\x00\xff\xff\x98\x26\x1f\x98\x14\x01\x00\x00")
+var _templatesScEtcdMaintenanceYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xbd\x57\x6d\x6f\xdb\x36\x10\xfe\x9e\x5f\x71\x70\x1a\xac\x5d\x23\xb9\x2f\x18\x06\x78\xd8\x07\xd7\x79\xa9\xd6\xc4\x36\x62\xb7\x45\x31\x0c\x05\x45\x51\x36\x6b\x89\xd4\x48\x2a\xae\x91\xe6\xbf\xef\x4e\x2f\xb6\xe4\x28\x5b\x86\x6c\x55\x3f\x34\xd2\x1d\x8f\x0f\x9f\x7b\xee\x8e\x3e\x3c\x7c\xec\x73\x70\x08\x23\x9d\x6d\x8c\x5c\x2c\x1d\xbc\x7a\xf1\xf2\x67\x38\xd7\x7a\x91\x08\x08\x14\xf7\x0f\xc8\x7c\x21\xb9\x50\x56\x44\x90\xab\x48\x18\x70\x4b\x01\xc3\x8c\x71\xfc\xaf\xb2\x1c\xc3\x07\x61\xac\xd4\x0a\x5e\xf9\x2f\xe0\x29\x39\xf4\x2a\x53\xef\xd9\x2f\x18\x61\xa3\x73\x48\xd9\x06\x94\x76\x90\x5b\x81\x21\xa4\x85\x58\xe2\x26\xe2\x2b\x17\x99\x03\xa9\x80\xeb\x34\x4b\x24\x53\x5c\xc0\x5a\xba\x65\xb1\x4d\x15\x04\x61\xc0\xa7\x2a\x84\x0e\x1d\x43\x6f\x86\xfe\x19\xbe\xc5\x4d\x3f\x60\xae\x00\x4c\xcf\xd2\xb9\xcc\x0e\xfa\xfd\xf5\x7a\xed\xb3\x02\xad\xaf\xcd\xa2\x9f\x94\x9e\xb6\x7f\x11\x8c\x4e\xc7\xb3\x53\x0f\x11\x17\x6b\xde\xab\x44\x58\x0b\x46\xfc\x99\x4b\x83\x67\x0d\x37\xc0\x32\x04\xc4\x59\x88\x30\x13\xb6\x06\x6d\x80\x2d\x8c\x40\x9b\xd3\x04\x78\x6d\xa4\x93\x6a\x71\x0c\x56\xc7\x6e\xcd\x8c\xc0\x28\x91\xb4\xce\xc8\x30\x77\x2d\xb6\x6a\x78\x78\xe8\xa6\x03\xf2\xc5\x14\xf4\x86\x33\x08\x66\x3d\x78\x33\x9c\x05\xb3\x63\x8c\xf1\x31\x98\xbf\x9d\xbc\x9f\xc3\xc7\xe1\xd5\xd5\x70\x3c\x0f\x4e\x67\x30\xb9\x82\xd1\x64\x7c\x12\xcc\x83\xc9\x18\xdf\xce\x60\x38\xfe\x04\xef\x82\xf1\xc9\x31\x08\xe4\x0a\xb7\x11\x5f\x33\x43\xf8\x11\xa4\x24\x1e\x45\x44\xa4\xcd\x84\x68\x01\x88\x75\x09\xc8\x66\x82\xcb\x58\x72\x3c\x97\x5a\xe4\x6c\x21\x60\xa1\xaf\x85\x51\x78\x1c\xc8\x84\x49\xa5\xa5\x6c\x5a\x84\x17\x61\x94\x44\xa6\xd2\x31\x57\x7c\xb9\x73\xa8\x52\x22\x23\xa3\xd5\x6f\x3a\x84\x48\xc4\x86\x2d\x52\xa1\x88\x99\xc2\x4d\x38\x1e\x41\x2a\xd2\x10\x15\x42\xf1\xc0\xb2\x6b\xb2\x31\xb0\x8a\x65\x76\x89\x8a\xc0\x24\x92\x17\x9d\x3d\xcf\x12\xcd\xa2\x92\xe2\xf3\xd1\xac\x54\x82\xe7\x91\xd9\x4b\x31\xef\x4e\x28\x52\x88\x17\xe6\x7c\x25\x1c\x9d\x76\x45\xf2\x21\x26\x09\xb8\x45\x76\x71\x6f\x0c\x74\xad\x93\x3c\x15\xa0\x89\x9c\xb5\x44\x98\x30\x51\xc9\x06\xd3\x4b\xf8\x31\x7e\x1d\x58\x51\x7a\xef\xc4\x2f\x0e\xf5\xf8\xca\xba\xb9\x01\x19\x83\x7f\x8a\xd1\x2f\x77\xc1\xe1\xf6\xf6\x80\x65\xb2\x2a\x99\x01\x5c\xbf\x3c\x58\x49\x15\x0d\x30\x5b\xe6\x1a\x49\x1d\x72\xae\x73\x3c\x44\x2a\x1c\x8b\x98\x63\x83\x03\x00\xc5\x52\x31\x80\x7d\x98\x95\xc1\xa2\xbc\xd1\x8a\xbb\xf9\xe3\xfa\x95\x36\x01\x4c\x6f\x28\x12\x4b\x01\x80\xd4\xdc\x11\xe1\xe6\xc6\xeb\xc2\x78\x3e\x9a\xb6\xd1\x94\xf1\x98\xc2\x0a\x2e\x95\x50\x06\x95\x2c\xf5\x17\x2b\xe1\x4b\xdd\x5f\xf0\xcc\xb3\xe5\x1a\x8f\x95\x8b\x06\xd0\x23\x50\x0f\x88\xdd\x2b\x80\x60\x72\x68\x9f\x0a\x13\x35\x8b\xfd\xb5\x6f\xca\xbc\xa3\x93\xe7\x79\xdd\x2c\x4e\xb7\x32\xf8\x50\x68\x60\x94\x30\x99\x3e\x80\x4c\xaf\xd6\xa3\xfd\x0f\x68\xed\xa0\xaa\x54\xa4\x1f\x22\x10\x7f\x95\x63\x39\x28\xe1\x84\x25\xe6\xac\xd3\x58\x34\xc2\xe3\x09\xb3\xb6\xc1\xd9\x1b\xc6\x57\x79\x36\x2b\xad\x23\x32\x16\x4c\x51\xf1\x52\x48\x24\x19\x2b\xfe\x52\x47\x02\x17\xfd\x0e\xbd\x2b\xc1\xa2\x8f\xd8\x93\xc4\x04\x11\xf4\xe0\x0f\x74\xc1\x96\xa0\x73\x83\x6e\x25\x04\x6a\x6e\xc2\xba\xea\x0d\xa0\xda\x78\x00\x2f\xcf\x65\x33\x01\xfb\xdc\x12\xa0\xe1\x34\xa8\xde\xad\x5f\x57\x3b\xba\x96\x9c\x57\x1f\xbe\x8f\x64\xeb\xf3\x5b\xec\xe9\x51\x9e\x88\x6e\x95\xcd\x2a\x6b\x41\x19\xc0\x21\x9c\xb4\x5a\x53\x98\x68\xbe\xb2\x45\x87\xaa\x9a\xd3\x31\x28\x81\x3d\x10\x4c\xae\xc0\xad\x51\x9d\x38\x4d\xb0\xb1\x50\x33\x00\x1c\x36\x8a\xe7\x06\xbb\x07\xdf\x4c\x35\x0e\x85\xcd\x00\xce\xb4\x09\x65\x44\x38\xf2\x22\x11\x71\x9e\x20\x05\xf6\xad\x24\x56\x37\x17\xd4\x33\x07\xf0\x1a\xed\x31\xc3\x41\x17\x75\xdb\xbe\xe8\x70\x2e\xb0\x5f\x33\x27\xca\x23\xd7\x87\xa3\x27\xc4\xfc\xeb\x38\xae\xdc\x5f\x55\x5f\x5d\xcb\x9f\x9e\x26\xeb\xf5\xd3\xa4\xb1\x7e\xfe\xbe\x03\x04\x16\xc5\x5a\xe6\x60\xbb\x60\x5f\xc3\xf5\x73\x08\x43\xb0\x32\x12\x9c\x19\x58\xeb\x3c\x89\xb0\x0d\x8b\xac\x60\x13\x4f\x84\xa3\xdd\xe8\xb4\x1c\xe8\x82\xd8\xf6\x5b\x8b\xab\x85\xbe\xa4\x0d\x49\xff\x52\x7d\x11\x9c\x9a\x45\xcc\x12\xbc\x31\x34\x95\xb8\x5d\xd3\xa0\xa5\x78\x6f\x35\x90\xf1\x7d\x52\xdb\xe1\x9d\xd7\xd0\x34\x8d\x01\xce\x92\xa4\x9c\x4c\x34\x91\x70\xd0\x34\x11\xb2\xdc\xe9\x94\xc2\xb6\xbb\xd4\x5c\xaf\x04\xd6\x42\x81\xb1\xe1\x8d\x25\xe6\x98\x71\xb5\x2a\x26\xea\x0c\xd3\x9d\x9b\x1d\xaf\x63\x2c\xd0\xa1\xe1\x4b\x2c\x4c\xee\xd0\x60\xf7\x38\x8e\x63\xa9\xa4\xdb\x14\xc5\x50\xe7\x16\x7a\x0c\x57\x0c\x2b\x53\xef\x9e\x20\x1d\x3c\x61\x47\xc6\x15\x23\xad\xe8\x92\x84\xa2\x1e\xb4\x48\xd8\x95\x40\x4b\xf9\xc8\x49\x21\x76\x06\x4e\xa6\x78\x99\x43\x93\xa2\x31\x8d\xbb\xec\xa6\x74\x93\x21\xaf\x2a\xee\x72\xda\x6f\x1b\x67\x2b\xcb\x32\x2d\x3a\x4b\x57\x69\x06\x64\xaa\xea\x72\x6f\xc1\x34\x4f\x92\x9a\xca\x20\x1e\x6b\x37\xc5\xb3\xd2\x44\x6f\x7a\x0a\x75\xdd\x96\x63\x8d\xe7\x74\x3e\x3a\x19\xcd\x2f\x3e\x63\xaf\x6a\xd9\xb1\xf9\xb2\x24\x27\x34\xaf\x7b\xf7\x2e\xfc\x7c\x3a\x3e\x99\x4e\x82\xf1\xfc\x9e\xa5\xf5\x41\x48\x15\x44\xda\x3e\x7e\x94\x7b\x8a\x6a\xda\x47\xd6\x0f\xa5\xea\xdb\xe5\xde\x57\x4f\xf0\xbd\x2f\x55\x2e\x7e\x7d\xf2\x94\x74\xc9\x5d\x52\xdc\x4e\xa2\x4c\x4b\xca\x56\xef\x49\x0b\x62\xaf\x72\xc7\xab\x99\x75\xf0\x0d\x78\xee\xc0\x8b\x8e\xc1\x8b\x7f\xc2\x37\x67\xf0\x05\x7e\xc0\x7f\xdf\x20\x63\x38\x0a\xc1\xb3\x64\xc4\xab\x78\xfb\x68\xdd\x3b\x55\x48\x7a\x55\x7a\x1f\xb6\x68\x0f\xde\xf6\x6e\x47\x32\x82\xfe\x76\xb4\xf6\x8b\x22\x7d\xf2\x34\x22\x91\x7b\x39\x3c\x3f\xfa\x74\x94\x1e\x45\xde\xd1\xdb\xa3\xcb\xa3\xd9\x33\x3f\x0a\x5b\xbb\x95\x33\xf3\x92\xca\xcf\x76\xa7\xbc\x39\xb4\x9b\x4f\x51\xc0\x53\xe6\x96\x83\xc6\xee\x07\xcd\x6c\xed\x4a\xe4\x9e\x3b\xd0\xee\xae\xd1\xac\xa1\xf7\xc5\x0d\xb5\xac\x9f\x3a\x70\x51\x32\xf5\x95\x93\x2e\xf9\x02\xfb\x5e\x7d\xc9\x24\x53\xa6\xa3\xae\xfa\x29\x6f\xbb\x0f\x2d\x9b\x72\xe7\xc7\x16\xcf\x23\x65\xba\xb0\xb9\x93\x09\xf0\xac\x99\xd4\x1f\x31\x6f\x9d\x90\xb7\x14\xf6\xab\xae\x8e\xbd\x73\x9f\xd0\x77\x38\x39\x4a\x3a\x95\x58\x63\x33\xdd\xa5\xb4\x8b\xb2\x0c\xc7\xb3\xf8\x5e\x8d\xe6\x91\x5c\x25\x16\x3c\x77\x47\xfc\x05\x59\x58\xa4\x38\x26\xc0\x53\xf0\xbc\x03\xf7\x94\x0e\x79\x46\x33\xf4\xf6\x16\x5d\xbf\x32\xb3\xc0\x5f\xa6\x29\x96\x77\x77\xcf\xff\x3f\x0b\xa5\x8c\xdc\x0a\x7a\x37\xe4\xbf\xa8\x20\x6c\x20\x69\xe6\x36\x27\xd2\xe0\xc4\xbb\xbd\x47\x15\xd0\xf8\x31\xd7\xb8\xc5\x0f\xf6\xb0\x73\xfa\x38\xfe\xa7\xdb\x7c\xeb\x97\x45\xfd\xd7\x5f\xb7\x18\x6a\xe5\x38\x11\x00\x00")
 
-func templatesScEtcdOperatorRbacBindingYamlTmplBytes() ([]byte, error) {
+func templatesScEtcdMaintenanceYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_templatesScEtcdOperatorRbacBindingYamlTmpl,
-		"templates/sc/etcd-operator-rbac-binding.yaml.tmpl",
+		_templatesScEtcdMaintenanceYamlTmpl,
+		"templates/sc/etcd-maintenance.yaml.tmpl",
 	)
 }
 
-func templatesScEtcdOperatorRbacBindingYamlTmpl() (*asset, error) {
-	bytes, err := templatesScEtcdOperatorRbacBindingYamlTmplBytes()
+func templatesScEtcdMaintenanceYamlTmpl() (*asset, error) {
+	bytes, err := templatesScEtcdMaintenanceYamlTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/etcd-operator-rbac-binding.yaml.tmpl", size: 276, mode: os.FileMode(416), modTime: time.Unix(1507818057, 0)}
+	info := bindataFileInfo{name: "templates/sc/etcd-maintenance.yaml.tmpl", size: 4408, mode: os.FileMode(420), modTime: time.Unix(1792032658, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScEtcdOperatorRbacYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x91\xb1\x4e\xed\x30\x0c\x86\xf7\x3c\x45\x74\xc6\x2b\x9d\x5e\x9d\xed\xaa\xeb\x1d\xd8\x19\xd8\xdd\xe4\x07\xac\xd3\xc4\x91\xed\x54\xc0\xd3\xa3\xd2\x22\x21\x15\x74\x18\x6d\x7f\xf9\xfe\x38\xa1\xc6\x0f\x50\x63\xa9\x63\xd4\x89\xd2\x40\xdd\x9f\x45\xf9\x8d\x9c\xa5\x0e\xd7\x7f\x36\xb0\xfc\x5d\x2e\x13\x9c\x2e\xe1\xca\x35\x8f\xf1\xff\xdc\xcd\xa1\xf7\x32\x23\x14\x38\x65\x72\x1a\x43\x8c\x95\x0a\xc6\x08\x4f\xf9\x2c\x0d\x4a\x2e\x1a\xb4\xcf\xb0\x31\x9c\x23\x35\xbe\x53\xe9\xcd\x56\xf2\xfc\x41\x0d\xeb\xc1\x89\x0c\x43\x12\x85\xd8\x90\xa4\x84\x18\x15\x26\x5d\x13\xbe\x90\x69\x4b\xb4\x10\xe3\x02\x9d\xf6\xc9\xe9\xcf\xe9\x28\xa6\xc6\x78\x71\xd4\x75\x25\xdb\xef\x7f\x94\xa6\x6e\x2e\xe5\xb3\x99\xf1\xc8\x95\xd7\x85\x7f\x93\x60\x2e\x4a\x4f\xf8\xd1\xbd\xcf\xd3\x4c\x66\xb8\x21\x8c\x5b\xf7\x74\xb4\x34\xc9\xb6\xe9\xa0\x0b\x27\x6c\x05\x6a\x6e\xc2\xd5\xb7\xaa\xad\x3f\x67\x8e\xea\x8b\xcc\xbd\xac\x91\x5c\x76\x70\xc1\x46\xdd\x7e\xaf\x66\xc7\xf4\x8c\x36\xcb\x6b\xf9\xde\xf1\x1e\x00\x00\xff\xff\x6f\xf0\x8e\x6c\x34\x02\x00\x00")
+var _templatesScEtcdMigrationYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x57\x4b\x73\xdb\x36\x10\xbe\xfb\x57\xec\x28\x97\x76\x46\xa4\x62\xf7\xd0\x56\x99\x1e\x54\x3b\x4d\x35\x71\x64\x8d\xa5\x34\x93\xe9\x64\x1a\x88\x5c\x8a\xa8\x41\x80\x01\x40\x2b\xaa\xc7\xff\xbd\xbb\xe0\x43\x90\xec\x9c\xc2\x8b\x05\x60\xf7\xdb\x6f\x9f\x80\x5f\xbc\xf8\xde\xef\xec\x05\x5c\x9a\x7a\x6f\xe5\xb6\xf4\x70\xf1\xf2\xfc\x17\x78\x63\xcc\x56\x21\xcc\x75\x96\x9e\xf1\xf1\xb5\xcc\x50\x3b\xcc\xa1\xd1\x39\x5a\xf0\x25\xc2\xac\x16\x19\xfd\xe9\x4e\xc6\xf0\x17\x5a\x27\x8d\x86\x8b\xf4\x25\xfc\xc0\x02\xa3\xee\x68\xf4\xe3\x2b\x42\xd8\x9b\x06\x2a\xb1\x07\x6d\x3c\x34\x0e\x09\x42\x3a\x28\x24\x19\xc1\xaf\x19\xd6\x1e\xa4\x86\xcc\x54\xb5\x92\x42\x67\x08\x3b\xe9\xcb\x60\xa6\x03\x21\x1a\xf0\xb1\x83\x30\x1b\x2f\x48\x5a\x90\x7c\x4d\xab\x22\x96\x03\xe1\x03\x61\xfe\x4a\xef\x6b\x37\x9d\x4c\x76\xbb\x5d\x2a\x02\xdb\xd4\xd8\xed\x44\xb5\x92\x6e\x72\x3d\xbf\x7c\xbd\x58\xbd\x4e\x88\x71\xd0\x79\xaf\x15\x3a\x07\x16\xbf\x34\xd2\x92\xaf\x9b\x3d\x88\x9a\x08\x65\x62\x43\x34\x95\xd8\x81\xb1\x20\xb6\x16\xe9\xcc\x1b\x26\xbc\xb3\xd2\x4b\xbd\x1d\x83\x33\x85\xdf\x09\x8b\x84\x92\x4b\xe7\xad\xdc\x34\xfe\x28\x5a\x3d\x3d\x72\x3a\x16\xa0\x78\x09\x0d\xa3\xd9\x0a\xe6\xab\x11\xfc\x3e\x5b\xcd\x57\x63\xc2\xf8\x30\x5f\xff\x79\xf3\x7e\x0d\x1f\x66\xb7\xb7\xb3\xc5\x7a\xfe\x7a\x05\x37\xb7\x70\x79\xb3\xb8\x9a\xaf\xe7\x37\x0b\x5a\xfd\x01\xb3\xc5\x47\x78\x3b\x5f\x5c\x8d\x01\x29\x56\x64\x06\xbf\xd6\x96\xf9\x13\x49\xc9\x71\xc4\x9c\x83\xb6\x42\x3c\x22\x50\x98\x96\x90\xab\x31\x93\x85\xcc\xc8\x2f\xbd\x6d\xc4\x16\x61\x6b\xee\xd1\x6a\x72\x07\x6a\xb4\x95\x74\x9c\x4d\x47\xf4\x72\x42\x51\xb2\x92\x5e\xf8\xb0\xf3\xc4\xa9\xb6\x44\xd0\x67\x79\xd8\xcd\x85\x17\x7d\x56\xb2\xc6\x5a\xd4\xbe\x3d\x24\xdf\x2b\xb9\xb5\xc2\xb7\xf1\xdb\x95\xa8\xa1\xa9\x69\x23\x67\xa3\xad\xbe\x21\x24\x01\x1a\x77\x24\xaa\x89\xea\x7d\x5b\x55\x29\xac\x09\xad\xc2\x6a\x43\xa6\x77\x42\x7a\x17\x1c\xf9\xec\x32\x42\x20\x83\xe4\x0f\xda\x7b\xa2\x93\x64\x64\x5d\x99\x2d\xc1\x24\x09\x43\x26\x1d\xc2\x67\x36\x49\xf1\xf1\xc6\xb6\x01\x71\x5a\xd4\xae\xa4\x6a\x7c\x96\xaa\xe6\x04\x93\x15\x76\x26\x24\xd5\xc2\x06\x0b\xd6\x95\x1e\x9c\x17\xd6\xbb\x14\xe6\x1e\xb6\x48\x42\xac\x4e\xd0\xa6\xb1\x19\xba\x31\x95\x78\xce\x7c\x14\x66\x9e\x0b\x46\xe7\x20\x8a\x42\x6a\xe9\xf7\x9c\x5b\x4c\xb7\x29\x73\x61\x25\x96\x74\x3d\x01\x61\xb3\x52\x7a\x52\x6a\xec\x61\x53\x56\x94\x1a\xc2\x3c\x26\xd9\x85\x7b\xdc\x82\xb7\x11\xbf\x37\xaa\xa9\x90\x05\x3b\xc7\xd9\x55\x52\x4e\x9c\xfc\xef\xb9\xed\x4c\x09\xe7\x98\x50\xc8\x19\x16\xa2\x51\x1e\xc2\xe6\x60\x4c\x35\xce\x53\xbc\x65\x11\xba\xd6\xa1\x0f\x99\xfe\xfe\x71\x23\x6a\xd9\x4d\x8b\x29\xdc\x9f\x9f\xdd\x49\x9d\x4f\xa9\x50\x43\x02\xcf\x2a\xf4\x82\x1d\x9a\x9e\x01\x68\x51\xe1\x14\x1e\x1e\x20\x5d\xd0\x2f\x78\x7c\xec\xf6\x1c\x75\x73\x74\x10\x96\xed\xa9\x12\x1b\x54\x8e\x75\x81\x9b\xf7\x58\x99\x6b\x9e\x8f\x6a\x43\xe9\xe3\x1f\x49\xf8\x39\x85\x8b\x9f\x7e\xfe\x35\xa8\xb4\x06\x39\x4e\x61\x49\x79\xa6\x04\x2f\x63\x99\x3e\xaf\xdf\x30\x91\x24\xc9\x91\x7b\x7c\x36\x5b\xce\xbb\xb5\x4b\x57\xd4\x45\x58\x34\x6a\x85\x9e\xc5\x3b\xd7\x0f\x9b\xdf\xe5\x7e\xef\x5f\xd7\x0b\x8b\xa0\x3e\x8a\xf4\x47\x74\x68\x31\x8c\x34\x37\x85\xf3\x27\xee\x54\xc2\x67\xe5\x75\x14\xc2\x67\x3c\xa4\xa8\x20\x8d\x17\x62\xdc\xe9\x44\x84\xf9\x53\x47\xea\xcf\x02\xb4\x1f\xcf\x26\x36\xde\x4e\x5a\x2e\xb8\xd0\x7a\x1a\xfd\xce\xd8\x3b\xca\x0c\xd1\xdc\x8f\x81\x06\x65\x28\x3f\x92\x09\xf5\x6b\x68\x3a\x09\x62\x1c\xe1\x74\x23\xad\x2d\xef\xfb\xac\xf7\x7f\x4c\x23\x46\x66\x25\x08\xe5\x4c\xe7\x68\xcb\x86\x05\xd3\x41\x9f\x57\xff\x74\xb5\xde\x9e\x25\xdd\xaa\x0f\x81\x26\xfb\xed\xf0\x9b\x46\x56\xaf\xe5\x1d\x1e\x68\xd3\x58\xe0\xc8\x8a\x30\x01\xe8\x6a\x18\xf2\xe4\xda\xbb\x4c\x34\xde\x50\x78\x69\xe0\x3a\x99\x63\x26\x62\xfe\x52\xff\x4b\xdc\x08\x7f\xdc\x4e\x87\xa0\x90\x24\x04\x50\xfe\x46\x97\x85\x34\xe3\xd6\x86\x6d\x74\x8b\x66\x28\x26\xa2\x07\x3a\x78\xd2\x6f\x04\x9d\x54\x9a\x49\x0b\x4c\x35\x50\x50\x0c\x70\x14\x04\xfb\x22\x69\x4d\x07\xdc\xdc\x10\x4b\x8e\x71\x26\x94\x0a\x2e\xbd\x6d\x68\xd0\x52\x26\x68\x9f\xaa\xb7\xb7\x10\x7c\x30\x8d\xf6\x5d\xaf\xce\xb2\x8c\x57\x6b\x73\x87\x54\xea\xc1\x46\x27\xe9\xf9\x06\xd1\x21\x66\x6f\x2c\x05\x61\x89\x56\x9a\x7c\x85\x99\xd1\x39\x57\xde\xcb\xb3\x87\x87\x84\x07\x4b\xba\xa0\x11\xb8\xea\x67\xe5\x50\x1d\x3a\xda\xed\xaa\xe7\x44\x8e\xf5\x91\xa6\x5f\xf7\x93\xa1\x66\xdd\x94\x3d\xc0\xf4\x73\xb7\xeb\xc3\xe8\x3c\x52\x6f\x45\x89\x19\xbf\x2a\xa8\x4f\xfb\xe0\x24\xa7\xd3\x80\xbf\x30\x8f\xbb\xa6\x9a\xf3\xef\xae\xab\xa2\xd3\x65\xa3\xd4\x32\x14\xef\x14\xe6\xc5\xc2\xf8\x25\xcd\x73\x1e\xda\x3d\xcf\xdb\xfe\xaa\x88\xbb\x61\xb8\x3f\x5a\xae\x47\x32\x4f\xc8\x32\xdd\xaa\xa2\xe1\x7f\xa8\xc7\x04\x26\x1b\xa9\x27\xae\x8c\x76\x92\x2c\x5a\x50\xa2\xa4\x82\xbf\x21\x29\x82\x81\x2b\x6a\xd9\x2b\xc9\xa1\x9c\x74\xb7\x62\x0e\x9f\x5e\x51\x29\x80\x53\x88\x35\x9c\xf3\x6f\x8d\xaf\x06\x04\x6a\x94\xaf\x98\xb5\x05\x93\x24\xdc\xf1\x09\xdf\x8a\x27\x58\xe1\xbe\x3c\xa8\x24\x89\xa2\x62\x44\x4d\x1d\x25\x29\x04\x49\x63\x95\x83\x11\x3f\xcc\xe8\x5d\xc6\xaa\xd7\xe1\x78\x96\xe7\xe1\xe9\xf2\xf8\x38\xe5\x31\x3b\x3a\x42\x10\x39\x5d\xe1\x5e\x3a\x3c\x02\x89\x30\xba\xc1\x92\x9e\xce\xc3\x94\x66\xc1\x74\x18\xed\xfc\x0d\x83\xbf\x0f\xca\x90\xf5\xe5\xf1\x3d\xc0\x5f\x7b\xa1\xbe\xe3\x0a\x3f\xd2\x39\x54\xc5\x10\x85\x88\x6f\xe8\x8f\xa5\xf0\xe5\xf4\x24\x34\x51\xa6\xf9\xc1\x43\xee\x2e\xad\xd9\xe0\x34\xd2\x65\x9f\xde\xa0\x8f\xb7\xe0\xf4\x86\x1a\xb6\x83\x89\x49\x89\x42\xf9\x32\x3a\x29\x84\x54\xf4\x80\x58\x97\x14\xd0\xd2\xa8\xbc\x1d\xf3\x43\x81\x52\xfd\x4b\xa1\xae\x50\x89\x7d\xdc\x8d\x11\xee\x93\x4e\x3d\x9c\xb9\x26\xa3\x8a\x74\xdf\xc0\xf6\xb2\x42\x1a\x4c\x83\xea\xc5\x59\x1f\xc3\x4b\x25\x64\xb5\xee\x6e\x8d\xee\xe2\x3d\xbd\x36\xbe\x15\xd5\x78\x5c\x89\x60\xfe\x1d\x3f\x9a\xa6\x54\xc9\xa3\x5b\x8a\xe4\x07\x7a\x82\xe3\x0d\xfd\xcb\x30\x82\x4f\x43\x87\xad\xda\x37\xce\x65\x78\xcd\x0c\xa1\x77\xd1\x6e\x74\x37\x9e\x08\x8f\x9e\xb6\xdb\xa1\x37\xa3\x24\x7e\x69\xa8\x6d\x5c\x9c\xab\x0e\xff\x18\x76\xc5\xaf\x2f\x46\xfd\x1f\x75\x7d\x41\x88\xa7\x0d\x00\x00")
 
-func templatesScEtcdOperatorRbacYamlTmplBytes() ([]byte, error) {
+func templatesScEtcdMigrationYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_templatesScEtcdOperatorRbacYamlTmpl,
-		"templates/sc/etcd-operator-rbac.yaml.tmpl",
+		_templatesScEtcdMigrationYamlTmpl,
+		"templates/sc/etcd-migration.yaml.tmpl",
 	)
 }
 
-func templatesScEtcdOperatorRbacYamlTmpl() (*asset, error) {
-	bytes, err := templatesScEtcdOperatorRbacYamlTmplBytes()
+func templatesScEtcdMigrationYamlTmpl() (*asset, error) {
+	bytes, err := templatesScEtcdMigrationYamlTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/etcd-operator-rbac.yaml.tmpl", size: 564, mode: os.FileMode(416), modTime: time.Unix(1507818057, 0)}
+	info := bindataFileInfo{name: "templates/sc/etcd-migration.yaml.tmpl", size: 3495, mode: os.FileMode(420), modTime: time.Unix(1792032658, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScEtcdOperatorServiceAccountYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2c\xcb\xb1\x0d\x02\x31\x0c\x05\xd0\x3e\x53\x78\x81\x2b\x68\xdd\x31\x03\x12\xfd\x97\xf3\x85\x22\x38\x3b\x72\x4c\xe6\xa7\xe0\xda\x27\x3d\xcc\xf1\x64\xae\x11\xae\xb2\x6f\xed\x3d\xbc\xab\x3c\x98\x7b\x18\xef\x66\xf1\xf5\x6a\x27\x0b\x1d\x05\x6d\x22\x8e\x93\x2a\x2c\xeb\x47\x4c\x26\x2a\xf2\xd2\x35\x61\x54\x59\xff\x7b\x18\x0a\x9f\x78\xb5\x5f\x00\x00\x00\xff\xff\xec\x17\x5b\x98\x61\x00\x00\x00")
+var _templatesScEtcdOperatorYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb5\x56\x4d\x73\xdb\x36\x10\xbd\xeb\x57\xec\xc8\x97\xb6\x23\xd1\x71\x4e\x19\xf6\x44\xcb\x4a\xca\x69\x22\x79\x24\xa7\x19\x9f\x32\x10\xb8\x92\xd0\x90\x04\x0b\x80\x92\x15\x4f\xfe\x7b\x1f\x40\xd2\x92\x2a\xdb\xed\xc4\x29\x2f\x12\x76\x17\x6f\xdf\x7e\x01\x38\x3b\x7b\xe9\xd7\x3b\xa3\x91\xae\x76\x46\xad\xd6\x8e\x5e\xbf\xba\x78\x43\xef\xb4\x5e\xe5\x4c\x69\x29\xa3\x9e\x57\xbf\x57\x92\x4b\xcb\x19\xd5\x65\xc6\x86\xdc\x9a\x29\xa9\x84\xc4\x4f\xab\x19\xd0\x1f\x6c\xac\xd2\x25\xbd\x8e\x5e\xd1\x4f\xde\xa0\xdf\xaa\xfa\x3f\xff\x0a\x84\x9d\xae\xa9\x10\x3b\x2a\xb5\xa3\xda\x32\x20\x94\xa5\xa5\x82\x13\xbe\x93\x5c\x39\x52\x25\x49\x5d\x54\xb9\x12\xa5\x64\xda\x2a\xb7\x0e\x6e\x5a\x10\xd0\xa0\xdb\x16\x42\x2f\x9c\x80\xb5\x80\x7d\x85\xd5\xf2\xd0\x8e\x84\x0b\x84\xfd\xb7\x76\xae\xb2\xf1\xf9\xf9\x76\xbb\x8d\x44\x60\x1b\x69\xb3\x3a\xcf\x1b\x4b\x7b\xfe\x3e\x1d\x8d\x27\xf3\xf1\x10\x8c\xc3\x9e\x8f\x65\xce\xd6\x92\xe1\xbf\x6a\x65\x10\xeb\x62\x47\xa2\x02\x21\x29\x16\xa0\x99\x8b\x2d\x69\x43\x62\x65\x18\x3a\xa7\x3d\xe1\xad\x51\x4e\x95\xab\x01\x59\xbd\x74\x5b\x61\x18\x28\x99\xb2\xce\xa8\x45\xed\x8e\xb2\xd5\xd1\x43\xd0\x87\x06\xc8\x97\x28\xa9\x9f\xcc\x29\x9d\xf7\xe9\x32\x99\xa7\xf3\x01\x30\x3e\xa5\x37\xbf\x4d\x3f\xde\xd0\xa7\x64\x36\x4b\x26\x37\xe9\x78\x4e\xd3\x19\x8d\xa6\x93\xab\xf4\x26\x9d\x4e\xb0\x7a\x4b\xc9\xe4\x96\x7e\x4f\x27\x57\x03\x62\xe4\x0a\x6e\xf8\xae\x32\x9e\x3f\x48\x2a\x9f\x47\xce\x7c\xd2\xe6\xcc\x47\x04\x96\xba\x21\x64\x2b\x96\x6a\xa9\x24\xe2\x2a\x57\xb5\x58\x31\xad\xf4\x86\x4d\x89\x70\xa8\x62\x53\x28\xeb\xab\x69\x41\x2f\x03\x4a\xae\x0a\xe5\x84\x0b\x92\x93\xa0\x9a\x16\x61\x27\x11\x0f\xb6\x0a\xa7\xcd\x80\xb6\x6b\x25\xd7\x28\x57\x09\x68\x1b\xac\x83\x81\xcc\x6b\xeb\xb0\x7d\x21\xe4\x17\xef\x2a\x50\x61\xb3\x01\x12\x30\xa4\x70\x22\xd7\x2b\xa4\x5d\x05\x29\x9b\x98\x94\xb3\x9d\x05\x09\x29\x75\x5d\xba\x01\xcd\x2e\x93\x91\xa7\x46\x19\x57\xb9\xde\x15\x5c\xba\xc0\xe2\xe5\xa3\x00\xd7\x6d\x27\xc7\xb4\xb9\xe8\x81\x64\x16\x23\x89\xc1\x7f\xd2\xb8\xef\x15\xec\x44\x06\xaa\x71\x8f\xa8\x14\x05\xc7\x21\xb6\x61\x17\x7c\x2b\xb5\x68\x39\xa8\xee\xef\x29\x9a\x74\x4b\xfa\xf6\xad\x37\x1c\x0e\x8f\xbc\x78\x83\xe4\x3a\x6d\xd7\x36\x0a\xb1\xc1\xae\x71\x3d\x6a\x12\x36\xd3\x39\x3f\xe2\xb7\x03\xbf\x36\xbc\x54\x77\xd8\x75\x44\xa4\xd3\xce\xeb\x65\xa3\xed\x99\x1a\x2d\x1e\xf7\x86\x3e\xc3\xef\x8c\xae\x31\x1f\xc0\x1a\x06\xfe\x91\x87\x5e\x08\xd4\x53\x6a\xc3\xda\xe2\xa7\x80\x12\x3d\xa5\x6b\x23\xf9\xc0\xb2\x2d\xa2\x85\x00\x25\x5a\xb4\x9a\xfe\x2f\xfd\x53\x60\x2c\xf9\xce\xa1\x4b\x42\x68\x5f\xde\xd8\x48\xe9\x53\x50\x09\x3c\x5d\x74\xc2\x0c\xb1\x94\x2a\x34\xdb\x7f\xf0\x80\x9d\x06\x3d\xf6\x24\x76\xab\x97\xb9\xb0\x18\xfa\xe7\x01\xa9\x91\xf6\x4f\x51\x2a\x9d\xd9\x06\xae\x69\x85\x66\xc1\x65\x56\x69\x55\xba\x66\x55\xf9\x0a\x22\x31\xa5\xdb\xe8\xbc\x2e\xbc\x4b\x55\xb4\x86\x1b\x6e\xac\xfe\x3d\x5f\x95\x3d\xf5\xbe\xef\xf2\xc7\x30\xbe\xb7\x9f\x2e\x21\xc0\x0c\xfe\x88\xb6\x02\xda\x8c\x97\x1e\xa0\x8b\x27\x26\x83\x19\x8f\x44\xed\xd6\xda\xa8\xaf\xe1\xec\xd8\xd7\xe8\xb4\xb7\xbf\xd7\xb5\xad\x17\x7f\xb2\x74\xa1\xa9\x1f\x1d\xd6\xff\x63\x44\xaf\x1e\xea\xb1\x4f\xec\x5e\xf6\xc2\xf3\xc1\x9f\xcc\x71\xe8\x81\x70\xf7\xa0\x2b\x2f\xb0\xb2\x9c\x23\x4c\x6d\xbc\x86\x70\xae\x3a\xb9\x7e\x2f\x16\x9c\xdb\x46\xf0\x94\x23\xc7\xb8\x0a\x84\xe3\x76\xdb\x01\x31\xff\xe5\x47\x08\x8f\x63\xdc\xdf\x0f\x49\x2d\x29\x4a\x2d\x4a\xe8\xf9\x35\xa6\xa2\xc4\x4d\xde\xdc\x08\xfb\xfd\x67\x74\x83\xf3\xbc\xdb\x8a\xe3\x3c\xcf\x0f\xce\xfe\x82\x8b\x05\x32\xd8\xdd\x0d\xa6\x2e\xc3\x35\xaf\x6b\x87\xbb\xdc\xaa\x8c\xa5\x30\xd1\x03\x56\x27\x50\xde\x2f\xba\xe6\x5c\x95\xbe\xd0\x31\xf5\x97\x22\xc7\xab\x22\x10\xc3\x00\x76\x94\xba\xb4\x85\xff\x47\x2d\x30\x79\x26\xaa\x89\xce\x38\x31\x72\xad\x1c\xb0\x6b\x8c\xdd\x41\x84\xcb\x70\x0c\xed\x42\x89\xba\x3c\x52\x5f\xc0\x3a\x69\x55\xfd\x27\x00\xfe\x41\x8d\xf0\x52\x29\xfd\x9b\x05\xd1\x77\x14\x87\x4f\x14\xac\xf9\x54\x81\x23\xab\xe9\x8d\x31\x0c\xa6\xad\x3e\xf5\xe2\x3d\x2a\xc1\xc9\x66\x9f\xfd\x0e\xf2\xc3\xed\xe7\xeb\xe9\xd5\xe7\x49\xf2\x61\x3c\xbf\x4e\x46\xe3\x07\x03\x1c\x1d\x22\xaf\xf9\xad\xd1\x45\x7c\x20\x24\xbc\xc3\x38\xcf\xda\x01\x3e\x91\x5f\x0b\xb7\x8e\x1f\x5a\x27\x7a\x68\xdd\xe7\xfc\xfe\x78\x97\xbd\xbf\x01\x89\xcc\x41\x5e\xe7\x0a\x00\x00")
 
-func templatesScEtcdOperatorServiceAccountYamlTmplBytes() ([]byte, error) {
+func templatesScEtcdOperatorYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
-		_templatesScEtcdOperatorServiceAccountYamlTmpl,
-		"templates/sc/etcd-operator-service-account.yaml.tmpl",
+		_templatesScEtcdOperatorYamlTmpl,
+		"templates/sc/etcd-operator.yaml.tmpl",
 	)
 }
 
-func templatesScEtcdOperatorServiceAccountYamlTmpl() (*asset, error) {
-	bytes, err := templatesScEtcdOperatorServiceAccountYamlTmplBytes()
+func templatesScEtcdOperatorYamlTmpl() (*asset, error) {
+	bytes, err := templatesScEtcdOperatorYamlTmplBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/etcd-operator-service-account.yaml.tmpl", size: 97, mode: os.FileMode(416), modTime: time.Unix(1507818057, 0)}
+	info := bindataFileInfo{name: "templates/sc/etcd-operator.yaml.tmpl", size: 2791, mode: os.FileMode(420), modTime: time.Unix(1792032658, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScEtcdSvcYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x52\x4d\x6f\xdb\x48\x0c\xbd\xeb\x57\x3c\x58\x97\x5d\xc0\x1f\x89\xf7\x10\xac\x7a\x72\x93\xb4\x15\x1a\xd8\x45\xe4\x34\xc8\x91\x1e\xd1\x12\xd1\xf1\xcc\x74\x86\xb6\xe2\x7f\x5f\x48\x76\xd0\xa4\x3d\x86\xa7\x19\xbe\x47\xf2\xf1\x23\x7f\xb7\x65\x39\xae\x7d\x38\x46\x69\x5a\xc5\xfc\xe2\xf2\x0a\x9f\xbd\x6f\x2c\xa3\x74\x66\x9a\xf5\xf0\x9d\x18\x76\x89\x6b\xec\x5d\xcd\x11\xda\x32\x16\x81\x4c\xcb\x2f\xc8\x18\xdf\x39\x26\xf1\x0e\xf3\xe9\x05\xfe\xe9\x09\xa3\x33\x34\xfa\xf7\x43\x96\xe3\xe8\xf7\xd8\xd1\x11\xce\x2b\xf6\x89\xa1\xad\x24\x6c\xc5\x32\xf8\xd9\x70\x50\x88\x83\xf1\xbb\x60\x85\x9c\x61\x74\xa2\xed\x50\xe6\x9c\x64\x9a\xe5\x78\x3a\xa7\xf0\x1b\x25\x71\x20\x18\x1f\x8e\xf0\xdb\xd7\x3c\x90\x0e\x82\x7b\x6b\x55\x43\x2a\x66\xb3\xae\xeb\xa6\x34\xa8\x9d\xfa\xd8\xcc\xec\x89\x99\x66\x77\xe5\xf5\xed\xb2\xba\x9d\xcc\xa7\x17\x43\xcc\x83\xb3\x9c\x12\x22\xff\xdc\x4b\xe4\x1a\x9b\x23\x28\x04\x2b\x86\x36\x96\x61\xa9\x83\x8f\xa0\x26\x32\xd7\x50\xdf\x0b\xee\xa2\xa8\xb8\x66\x8c\xe4\xb7\xda\x51\xe4\x2c\x47\x2d\x49\xa3\x6c\xf6\xfa\x66\x5a\x2f\xf2\x24\xbd\x21\x78\x07\x72\x18\x2d\x2a\x94\xd5\x08\x1f\x17\x55\x59\x8d\xb3\x1c\x8f\xe5\xfa\xcb\xea\x61\x8d\xc7\xc5\xfd\xfd\x62\xb9\x2e\x6f\x2b\xac\xee\x71\xbd\x5a\xde\x94\xeb\x72\xb5\xac\xb0\xfa\x84\xc5\xf2\x09\x5f\xcb\xe5\xcd\x18\x2c\xda\x72\x04\x3f\x87\xd8\xeb\xf7\x11\xd2\xcf\x91\xeb\x7e\x68\x15\xf3\x1b\x01\x5b\x7f\x12\x94\x02\x1b\xd9\x8a\x81\x25\xd7\xec\xa9\x61\x34\xfe\xc0\xd1\x89\x6b\x10\x38\xee\x24\xf5\xdb\x4c\x20\x57\x67\x39\xac\xec\x44\x49\x07\xcf\x5f\x4d\x0d\x27\xf2\xfe\x23\xa4\x20\xe7\x1b\x2a\x70\xb8\xcc\x7e\x88\xab\x0b\x54\x1c\x0f\x62\x38\xdb\xb1\x52\x4d\x4a\x45\x06\x38\xda\x71\x01\x56\x53\x4f\xd2\xc1\x9c\x1d\x29\x90\xe1\x02\xe9\xc4\x9f\x18\x52\xb2\xbe\xc9\x00\x4b\x1b\xb6\xa9\x8f\x43\xbf\xce\x53\x60\xd6\xb7\xdf\xfb\x82\x8f\x3a\x80\x93\xe1\x59\x60\xfe\xdf\xd5\xff\x03\xf7\x77\x95\xe1\xab\x14\x1b\xd6\x6f\xaf\x39\x89\x2d\x1b\xf5\xf1\xcf\xdc\xbf\x02\x00\x00\xff\xff\xdf\x90\xf7\x0c\x91\x03\x00\x00")
+var _templatesScEtcdSvcYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x52\x4d\x6f\xda\x40\x10\xbd\xef\xaf\x78\xc2\x97\x56\x02\x93\xd0\x2a\x51\xdd\x13\xcd\x47\x6b\x35\x02\x14\x93\x46\x39\x2e\xeb\xc1\x8c\xb2\xec\x6e\x76\xd7\x10\x84\xf2\xdf\x2b\x1b\x50\xbe\xd4\x1e\xda\x3d\x79\x66\xde\x3c\xbf\x79\x33\xc9\x7f\x3f\x91\xe0\xcc\xba\x8d\xe7\x6a\x11\x31\x38\x3a\x3e\xc5\x77\x6b\x2b\x4d\xc8\x8d\x4a\x45\x22\x12\x5c\xb1\x22\x13\xa8\x44\x6d\x4a\xf2\x88\x0b\xc2\xd0\x49\xb5\xa0\x43\xa5\x8b\x5f\xe4\x03\x5b\x83\x41\x7a\x84\x0f\x0d\xa0\xb3\x2f\x75\x3e\x7e\x15\x09\x36\xb6\xc6\x52\x6e\x60\x6c\x44\x1d\x08\x71\xc1\x01\x73\xd6\x04\x7a\x54\xe4\x22\xd8\x40\xd9\xa5\xd3\x2c\x8d\x22\xac\x39\x2e\x10\x9f\xf9\x53\x91\xe0\x6e\x4f\x61\x67\x51\xb2\x81\x84\xb2\x6e\x03\x3b\x7f\x89\x83\x8c\xad\x60\x00\x58\xc4\xe8\x42\xd6\xef\xaf\xd7\xeb\x54\xb6\x6a\x53\xeb\xab\xbe\xde\x21\x43\xff\x2a\x3f\xbb\x18\x15\x17\xbd\x41\x7a\xd4\xf6\xdc\x18\x4d\x21\xc0\xd3\x43\xcd\x9e\x4a\xcc\x36\x90\xce\x69\x56\x72\xa6\x09\x5a\xae\x61\x3d\x64\xe5\x89\x4a\x44\xdb\x08\x5e\x7b\x8e\x6c\xaa\x2e\x82\x9d\xc7\xb5\xf4\x24\x12\x94\x1c\xa2\xe7\x59\x1d\x5f\xb9\x75\x90\xc7\xe1\x15\xc0\x1a\x48\x83\xce\xb0\x40\x5e\x74\xf0\x6d\x58\xe4\x45\x57\x24\xb8\xcd\xa7\x3f\xc6\x37\x53\xdc\x0e\xaf\xaf\x87\xa3\x69\x7e\x51\x60\x7c\x8d\xb3\xf1\xe8\x3c\x9f\xe6\xe3\x51\x81\xf1\x25\x86\xa3\x3b\xfc\xcc\x47\xe7\x5d\x10\xc7\x05\x79\xd0\xa3\xf3\x8d\x7e\xeb\xc1\x8d\x8f\x54\x36\xa6\x15\x44\xaf\x04\xcc\xed\x6e\x7d\xc1\x91\xe2\x39\x2b\x68\x69\xaa\x5a\x56\x84\xca\xae\xc8\x1b\x36\x15\x1c\xf9\x25\x87\x66\x9b\x01\xd2\x94\x22\x81\xe6\x25\x47\x19\xdb\xcc\xbb\xa1\xda\x13\x39\xdc\xd2\x3f\x3f\x21\x1d\xef\x6f\x28\xc3\xea\x58\xdc\xb3\x29\x33\x14\xe4\x57\xac\x48\x2c\x29\xca\x52\x46\x99\x09\xc0\xc8\x25\x65\xa0\xa8\xca\x5e\x58\xa9\x7d\x22\x38\xa9\x28\xc3\x76\x8b\x74\x74\x08\xf1\xf4\x24\x00\x2d\x67\xa4\x43\xd3\x88\x66\x9f\xbb\x4e\xd1\xcc\x9f\x89\xed\xb6\x07\x9e\x83\x1e\x90\xe6\x93\x4b\xb9\x64\xbd\x41\x87\xdd\xea\xa4\xb3\xeb\x65\xb7\x4b\x4e\xac\x66\xb5\xc9\x50\xb0\xa9\x34\x15\x51\xaa\xfb\xe7\x2a\x53\xcb\xde\x43\x3e\x59\x9d\xb4\x94\xa4\x03\xbd\xe7\x2d\x6b\xa9\xff\xc0\x3b\xf1\x34\x27\x7f\x5e\x4b\xfd\x17\xee\xcf\x6f\x7e\x62\xca\x1d\x99\xb3\x3e\xee\x51\xcd\x67\x86\xc1\xa7\xd3\x2f\xed\xb8\xcf\x4e\xb5\x61\x94\xbe\xa2\x38\x79\x89\x09\xa4\x49\x45\xeb\xdf\xda\xf3\x7b\x00\xbe\x29\x1a\xd3\x55\x04\x00\x00")
 
 func templatesScEtcdSvcYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -308,12 +470,12 @@ func templatesScEtcdSvcYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/etcd-svc.yaml.tmpl", size: 913, mode: os.FileMode(416), modTime: time.Unix(1507129340, 0)}
+	info := bindataFileInfo{name: "templates/sc/etcd-svc.yaml.tmpl", size: 1109, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScEtcdYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x55\x4d\x6f\xe3\x46\x0c\xbd\xeb\x57\x10\xf6\xa5\x05\x22\xc9\x71\x0e\x41\xd5\x93\x1b\xa7\xa9\xd1\x8d\x6d\xc4\xde\x06\x8b\xa2\x58\xd0\x23\x5a\x22\x76\x34\xa3\xce\x50\xf6\xfa\xdf\x17\x23\x7f\x44\xda\x6c\x0a\x14\x0b\x74\xe9\x93\x86\x8f\xe4\xe3\x1b\x0e\x3d\xfc\x66\x8b\x86\x70\x67\xeb\x83\xe3\xa2\x14\x18\x8f\xae\x6f\xe1\xc1\xda\x42\x13\xcc\x8c\x4a\xa2\xe0\x7e\xc7\x8a\x8c\xa7\x1c\x1a\x93\x93\x03\x29\x09\x26\x35\xaa\x92\xce\x9e\x2b\xf8\x83\x9c\x67\x6b\x60\x9c\x8c\xe0\x87\x00\x18\x9c\x5c\x83\x1f\x7f\x8e\x86\x70\xb0\x0d\x54\x78\x00\x63\x05\x1a\x4f\x20\x25\x7b\xd8\xb2\x26\xa0\xcf\x8a\x6a\x01\x36\xa0\x6c\x55\x6b\x46\xa3\x08\xf6\x2c\x65\x5b\xe6\x94\x24\x89\x86\xf0\xe1\x94\xc2\x6e\x04\xd9\x00\x82\xb2\xf5\x01\xec\xb6\x8b\x03\x94\x96\x70\xb0\x52\xa4\xf6\x59\x9a\xee\xf7\xfb\x04\x5b\xb6\x89\x75\x45\xaa\x8f\x48\x9f\xbe\x9b\xdd\xdd\xcf\x57\xf7\xf1\x38\x19\xb5\x31\xef\x8d\x26\xef\xc1\xd1\xdf\x0d\x3b\xca\x61\x73\x00\xac\x6b\xcd\x0a\x37\x9a\x40\xe3\x1e\xac\x03\x2c\x1c\x51\x0e\x62\x03\xe1\xbd\x63\x61\x53\x5c\x81\xb7\x5b\xd9\xa3\xa3\x68\x08\x39\x7b\x71\xbc\x69\xa4\xa7\xd6\x99\x1e\xfb\x1e\xc0\x1a\x40\x03\x83\xc9\x0a\x66\xab\x01\xfc\x32\x59\xcd\x56\x57\xd1\x10\x9e\x67\xeb\xdf\x16\xef\xd7\xf0\x3c\x79\x7a\x9a\xcc\xd7\xb3\xfb\x15\x2c\x9e\xe0\x6e\x31\x9f\xce\xd6\xb3\xc5\x7c\x05\x8b\x5f\x61\x32\xff\x00\xbf\xcf\xe6\xd3\x2b\x20\x96\x92\x1c\xd0\xe7\xda\x05\xfe\xd6\x01\x07\x1d\x29\x0f\xa2\xad\x88\x7a\x04\xb6\xf6\x48\xc8\xd7\xa4\x78\xcb\x0a\x34\x9a\xa2\xc1\x82\xa0\xb0\x3b\x72\x86\x4d\x01\x35\xb9\x8a\x7d\xb8\x4d\x0f\x68\xf2\x68\x08\x9a\x2b\x16\x94\xf6\xe4\x55\x53\xed\x88\x7c\xfb\x10\x62\xcd\xa7\x19\xca\x82\xee\x3e\xdd\x5d\x6f\x48\xf0\x3a\xfa\xc4\x26\xcf\x60\x25\x28\xb4\x6d\xf4\x8a\x24\xaa\x48\x30\x47\xc1\x2c\x02\x30\x58\x51\x06\x24\x2a\x3f\x7d\xf8\x1a\x15\x65\xe0\xc9\xed\x58\x51\xac\x50\x50\xdb\x22\x0a\x0d\x07\xfc\xe9\x7c\xde\x86\x0d\x42\xdc\x20\x02\x70\xd4\x5e\xb4\xcf\xe0\x3a\x02\x10\xaa\x6a\x8d\x42\x01\x0f\xd0\xad\x16\x4c\xe3\x86\xb4\x3f\x7f\x41\x20\x7b\x21\x00\x70\xae\x13\x4c\x82\x90\xa6\xd5\xed\xc1\xa1\xa2\x25\x39\xb6\xf9\x8a\x94\x35\x79\x28\x35\x3a\xe1\x94\x35\x61\xa4\xc9\x5d\xb2\xc6\xfd\xbe\x8e\xc6\x15\x16\x94\x41\xa1\x5c\xc2\x36\x2d\xda\x37\x1a\xbf\xc4\xa6\x01\x9c\xdd\x24\xa3\xe4\xfa\xb6\x1f\xb3\x6c\xb4\x5e\x5a\xcd\xea\x90\xc1\x6c\x3b\xb7\xb2\x74\xe4\xc9\xc8\x05\xe5\xc8\xdb\xc6\x29\xea\x74\x05\xed\x43\x20\x2f\xbd\x33\x00\x55\x37\x81\xf9\xa8\xea\x9d\x56\x54\x59\x77\xc8\x60\x3c\x7a\xe4\x8e\xa3\x9d\x9b\xff\x94\xe0\xa6\x9b\x80\xcc\xee\x25\xf6\x2c\xc9\xfd\xfa\x6e\xfa\x71\x3a\x59\x4f\x3e\x4e\x67\x4f\x9d\x1c\x3b\xd4\x0d\x65\xd0\xaa\x10\x87\xfb\x8a\x73\x76\x17\xbf\xb2\x55\x85\x26\xef\xa6\x4b\x1b\xef\x52\x6d\x15\xea\x74\xc3\x26\xed\x49\x1d\x43\x1c\x6b\xf6\x42\x26\x56\x9a\xc9\x48\xdc\x38\xed\x3b\xee\xb0\x5c\xb2\x34\x1d\x25\xed\x2f\x1b\xdf\xdc\xfe\xd4\x0b\xc6\x7c\x47\x4e\xd8\xd3\xbf\xc7\xb7\xe5\x4b\xeb\xa5\x9f\xa1\xb6\xae\x2b\x5b\xfc\x32\x21\x4b\xeb\x24\x83\x1e\x78\x67\x75\x53\xd1\xa3\x6d\x4c\x3f\xe6\x65\x82\x5e\xcb\x01\x50\x05\xfc\x12\xa5\x7c\x53\x32\x47\x98\xb3\x21\xef\x97\xce\x6e\xa8\x7b\x89\x81\xfc\x03\x49\xff\x5e\xeb\xd7\xcc\xda\xe3\x63\x89\x92\x50\x4b\xd9\xf1\x6c\x91\x75\xe3\x68\x5d\x3a\xf2\xa5\xd5\xf9\xf1\xe5\x9d\x8d\x0d\x0b\xa3\x9e\x92\xc6\xc3\xeb\xf7\xd2\xe6\x7d\xe3\x2d\x05\xf3\x8d\x52\xe4\xfd\x1b\xb9\x85\x2b\xb2\x8d\x5c\x42\xc7\x17\x9f\xe6\x1d\xfd\x8f\x0d\xdf\x7c\xc7\x86\x8f\x43\x73\xa7\x91\xab\xf5\x69\xdb\xb5\xc3\x13\x7f\xb9\xee\xde\x9c\x22\x34\xc6\x9e\xfe\x13\xb2\x2f\x66\x31\x09\x8b\x3b\xf9\xd4\x6c\xc8\x19\x12\xf2\x61\x5d\x79\xb1\x0e\x8b\xf0\x1c\xd0\xfb\x0c\xbc\xa0\xc9\xd1\xbd\xde\x99\xd8\x76\xf2\x68\x73\xf2\x19\xfc\x09\x83\x27\xc2\xfc\xd9\xb1\xd0\xc2\x28\x1a\xc0\x5f\x27\xd8\x57\x36\xd6\x57\xf6\xd5\xa9\x68\x50\xeb\x81\xa3\x7f\x02\x00\x00\xff\xff\xd4\x14\x16\x24\x2a\x09\x00\x00")
+var _templatesScEtcdYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xc5\x56\xd1\x6e\xdb\x36\x14\x7d\xf7\x57\x5c\x38\x2f\x1b\x10\xc9\x69\x3a\xac\x98\xf7\xa4\x25\x59\x26\x34\x75\x8c\xd8\x5d\x51\x0c\x43\x41\x4b\xd7\x36\x57\x8a\xd4\x48\xca\xae\x11\xe4\xdf\x77\x48\xc9\x8e\x9c\x34\x0f\x43\x81\x4d\x2f\x09\x79\x2f\x0f\xcf\x3d\x3c\xbc\xf4\xc9\xc9\xb7\x7e\x83\x13\xba\x30\xf5\xce\xca\xd5\xda\xd3\xf9\xd9\xab\x37\x74\x6d\xcc\x4a\x31\xe5\xba\x48\x07\x21\x7c\x23\x0b\xd6\x8e\x4b\x6a\x74\xc9\x96\xfc\x9a\x29\xab\x45\x81\x3f\x5d\xe4\x94\x7e\x67\xeb\xa4\xd1\x74\x9e\x9e\xd1\x77\x21\x61\xd8\x85\x86\xdf\xff\x0c\x84\x9d\x69\xa8\x12\x3b\xd2\xc6\x53\xe3\x18\x10\xd2\xd1\x52\x62\x13\xfe\x52\x70\xed\x49\x6a\x2a\x4c\x55\x2b\x29\x74\xc1\xb4\x95\x7e\x1d\xb7\xe9\x40\x40\x83\x3e\x76\x10\x66\xe1\x05\xb2\x05\xf2\x6b\x8c\x96\xfd\x3c\x12\x3e\x12\x0e\xdf\xda\xfb\xda\x8d\x47\xa3\xed\x76\x9b\x8a\xc8\x36\x35\x76\x35\x52\x6d\xa6\x1b\xdd\xe4\x17\x57\x93\xd9\x55\x02\xc6\x71\xcd\x7b\xad\xd8\x39\xb2\xfc\x77\x23\x2d\x6a\x5d\xec\x48\xd4\x20\x54\x88\x05\x68\x2a\xb1\x25\x63\x49\xac\x2c\x23\xe6\x4d\x20\xbc\xb5\xd2\x4b\xbd\x3a\x25\x67\x96\x7e\x2b\x2c\x03\xa5\x94\xce\x5b\xb9\x68\xfc\x91\x5a\x7b\x7a\x28\xba\x9f\x00\xbd\x84\xa6\x61\x36\xa3\x7c\x36\xa4\x5f\xb2\x59\x3e\x3b\x05\xc6\x87\x7c\xfe\xdb\xed\xfb\x39\x7d\xc8\xee\xee\xb2\xc9\x3c\xbf\x9a\xd1\xed\x1d\x5d\xdc\x4e\x2e\xf3\x79\x7e\x3b\xc1\xe8\x57\xca\x26\x1f\xe9\x6d\x3e\xb9\x3c\x25\x86\x56\xd8\x86\xbf\xd4\x36\xf0\x07\x49\x19\x74\xe4\x32\x88\x36\x63\x3e\x22\xb0\x34\x2d\x21\x57\x73\x21\x97\xb2\x40\x5d\x7a\xd5\x88\x15\xd3\xca\x6c\xd8\x6a\x94\x43\x35\xdb\x4a\xba\x70\x9a\x0e\xf4\x4a\xa0\x28\x59\x49\x2f\x7c\x9c\x79\x56\x54\x6b\x91\x19\x56\x42\xa6\x8a\xab\x45\x20\xe3\x8b\xf2\x34\x1c\x74\x09\x9d\x9c\x67\x51\x86\x83\x0a\xb3\x89\x01\xbc\xf0\xa0\x81\xe2\xf3\xe9\xe6\xc7\xc4\x68\xb5\x03\x40\xa1\x1a\x24\x5a\x47\x4e\x06\x07\x3c\xcd\x55\x3b\xb0\x40\x02\x18\xb4\x0b\x7f\x88\xfb\x7e\xbb\xf9\x45\x2d\x3b\xef\x8e\xe9\xfe\x9e\xd2\x6c\x9a\x77\x63\x97\xce\x50\x34\x2f\x1b\x35\x63\x4f\x0f\x0f\x83\xcf\x52\x97\x63\xea\x4d\x0e\x2a\xf6\xa2\x14\x5e\x8c\x07\x44\x5a\x54\x3c\x8e\xbc\xbb\x81\x83\xe9\xb8\x05\x9d\xec\x87\x01\x26\x68\x1f\x16\x38\xb6\x1b\x48\x38\x89\xeb\x86\x61\xe1\x10\xb3\x96\xa3\xe7\xdc\x98\x5e\xc5\x1c\xc5\x05\x14\x08\xf9\x04\xfb\xfb\x62\x7d\x23\x16\xac\x5c\x3b\x41\xc1\xa2\x87\x4d\x3d\xe3\xe0\x41\xae\x4b\xee\x71\x0b\x9f\x3a\x5a\x77\xbc\xb2\xfd\xc2\xe0\x53\x77\x0e\x6d\x28\xe9\x46\x83\xfb\xfb\x84\xe4\x92\xd2\xdc\xc1\x05\xa1\x88\x0e\x43\xe3\x42\xb7\xc6\x78\x04\x3e\xa1\x39\xdc\x01\x5d\x63\x85\x70\x43\x21\x94\x72\x11\x0f\x97\xaa\x16\xb0\x16\x5c\x26\x7d\x38\xea\x92\x0b\x61\xd3\xc3\xd2\xfd\x84\x0c\xdb\xa4\xd2\x8c\xa4\xfe\x0b\xf5\x43\x9e\xa5\x50\xe8\x25\x91\x07\xeb\x72\xcf\x60\x2f\x65\xfc\xbf\x95\x33\x2b\x0a\xd3\x68\xdf\xa9\x1a\xc4\xbf\xc2\xc6\xb3\xa3\x20\x96\x0f\x07\x7b\xb2\x91\x57\x69\xd8\xc5\xe6\x14\xb8\x46\x77\xbf\x6d\x60\x64\xcd\x1e\xf3\xb0\xc4\x9e\xa2\x68\xbc\xa9\x02\xc4\x31\xe0\xdc\x7c\x66\xf8\x27\x92\xec\x32\x7d\xb8\x45\x3a\x6a\x73\x6d\x71\xf2\x53\xb6\xd2\x80\x47\x61\x74\x19\x0e\xf7\xec\xa0\xe9\xc4\x94\x9c\xd9\x62\x2d\x3d\x4a\x6d\x70\x89\x7b\xfa\x2e\x97\x52\x4b\xbf\x8b\x2e\xda\x1f\x2f\x0d\x05\xb2\xb3\x2e\x34\x7c\x01\xe0\x89\x52\x84\x76\xa9\x43\xe3\x84\xb7\xf7\x8a\x25\xc7\x9e\x6d\x3f\x59\xa1\x1b\xb4\xae\x0d\xc2\xe5\x61\xf8\x08\xd2\xc5\xa7\x8d\x52\x53\x03\x9f\x82\x59\xbe\x9c\x18\x3f\xc5\xae\xac\xfd\x21\x0b\x43\xd3\xd8\x82\x7b\xb6\xa0\xd8\x5a\xd9\xf9\xa3\x39\xf0\xaa\x9b\x20\xc7\x59\x75\x34\x8b\x46\x62\x2c\xd0\xcf\xcf\xde\xc9\x5e\x20\x76\xa2\x7f\x05\xf0\xba\x0f\xc0\x7a\xf3\xb8\x76\x5f\xfe\xd5\xfc\xe2\xf2\xd3\x65\x36\xcf\x3e\x5d\xe6\x77\x3d\x8c\x8d\x50\x0d\xc2\xa3\x78\x11\xc2\x4d\x4a\x4a\x69\x0f\x71\xbc\x56\x15\xba\x63\x1f\x6e\xd4\x38\x3b\x52\x06\x16\x1a\x2d\xa4\x1e\x1d\xc9\x9a\x50\x92\xb4\x0d\x0c\x77\x4a\x42\xaa\xa4\xb1\xca\xf5\xc2\xc3\xf0\x5e\xe1\xb9\x0a\xc2\xdf\xc4\xc4\xac\x2c\x63\x47\x7f\x78\x18\x9f\xbf\x7e\xf3\xd3\xf0\x08\x4b\x94\xb8\x59\x5e\x3a\x7e\x01\xae\x43\x8b\xdc\xdd\xa6\x48\x9f\x76\xa1\x14\x93\x11\xf6\xb0\xa6\x36\xb6\xaf\x6c\xf2\x68\x98\x29\x22\x38\x89\x7e\xf2\xc6\xa8\xa6\xe2\x77\xc1\xfb\xee\xb9\xa2\x5f\x57\x0c\x67\x12\xf2\xa7\xc2\xaf\x5f\x54\xd5\xe2\xa9\xc0\x96\xce\x4d\xad\x59\x70\xff\x9c\x43\x41\xd7\xec\x8f\x8f\xbe\x7e\xce\x2c\x4e\xb7\x5b\xac\x59\x28\xbf\xee\x45\x96\x42\x2a\xdc\x8f\xf9\x1a\xba\xae\x8d\x2a\xdb\x1e\x7b\x70\x36\xae\x93\x14\xea\x92\x95\xd8\xf5\xef\x69\x0f\xf7\xd9\x1d\x7e\x8c\xb9\xa6\x80\xdd\xdd\x0b\xd8\x5e\x56\x6c\x1a\x7f\x58\x7a\x3e\x78\x74\xf4\x86\xff\xc3\x82\x5f\xff\x8f\x05\xb7\xa6\xb9\x50\x42\x56\xf3\xae\x97\x45\xf3\x24\x4f\xdf\xaa\x17\x5d\xf4\xd5\xf7\xa6\x85\x4d\x17\xc0\x48\x3f\x1f\xba\x76\x78\x3d\x1c\x5e\x4e\x74\x2b\x5c\x11\x3c\x3a\x63\x72\x1e\x17\x56\xd8\xf2\xd9\xd3\x21\x62\x25\xef\xd0\x46\x91\xf5\x07\x0d\xef\xe0\xc2\x0f\xf8\x71\xc7\xb7\xf8\x29\x32\xa4\x3f\x07\x2f\x36\xb5\xaf\xb4\xb4\x6e\xd3\xa0\xd6\xb5\x1c\xfc\x03\x2b\x4c\x59\x6b\x9f\x0b\x00\x00")
 
 func templatesScEtcdYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -328,12 +490,12 @@ func templatesScEtcdYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/etcd.yaml.tmpl", size: 2346, mode: os.FileMode(416), modTime: time.Unix(1527273967, 0)}
+	info := bindataFileInfo{name: "templates/sc/etcd.yaml.tmpl", size: 2975, mode: os.FileMode(420), modTime: time.Unix(1792033150, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScGencert_configJsonTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xaa\xe6\x52\x72\xf6\x53\xb2\x52\x50\xaa\xae\x56\xd0\x73\x0c\xf0\x0c\x4e\x2d\x2a\xcb\x4c\x4e\xf5\x4b\xcc\x4d\x55\xa8\xad\x55\xd2\xe1\x52\xca\xc8\x2f\x2e\x29\x56\xb2\x52\x88\x86\xa8\xf1\xc8\x2f\x2e\x31\x04\x4b\xc1\xb9\x46\x20\xae\x42\xac\x0e\x97\x52\x76\x6a\xa5\x92\x95\x42\x35\x97\x82\x82\x52\x62\x4e\x7a\x3e\xc8\xdc\xa2\xe2\x44\x25\x1d\x90\x40\x71\x66\x55\xaa\x92\x95\x82\x91\x81\x89\x05\x57\x2d\x57\x2d\x17\x20\x00\x00\xff\xff\x0b\x41\x2e\x03\x7a\x00\x00\x00")
+var _templatesScGencert_configJsonTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3c\xcd\xc1\x0a\x82\x40\x10\xc6\xf1\xfb\x3c\xc5\xc7\x9c\x45\xa8\xa3\x37\xe9\x92\x04\x22\x78\x8c\x0e\x52\x83\x2e\xa9\x0b\xae\x04\x3a\xcc\xbb\xc7\xb6\xd5\xf1\xe3\xff\x1b\x46\x89\x4f\x35\x17\x60\x55\xe4\x65\x53\xb5\xb2\xbc\xdc\x5d\xea\x6e\x12\x98\x71\x46\x3c\xf8\xb0\x06\x2e\x70\x4d\xe6\xec\xc3\x7a\xf8\xa4\xff\x3c\xc6\xa9\x8a\xa5\x9b\x7b\x41\x5e\x35\x6d\x59\x07\x98\x25\xf1\x8d\x32\x3f\x60\x86\x5b\x46\xfc\x94\x8d\x0b\x28\x01\xdc\x8d\xbd\xff\x7d\xbf\xc8\x56\x8e\xbd\x5f\xdc\x3a\x4c\xf1\x28\x8b\x20\xb8\x5d\x22\x4e\xbd\x75\xbb\xc0\x8c\x8c\x8c\xde\x03\x00\x5f\x21\xc8\x14\xba\x00\x00\x00")
 
 func templatesScGencert_configJsonTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -348,12 +510,52 @@ func templatesScGencert_configJsonTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/gencert_config.json.tmpl", size: 122, mode: os.FileMode(416), modTime: time.Unix(1507129340, 0)}
+	info := bindataFileInfo{name: "templates/sc/gencert_config.json.tmpl", size: 186, mode: os.FileMode(420), modTime: time.Unix(1791999859, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScHpaYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x54\x51\x4f\xdb\x30\x10\x7e\xef\xaf\x38\x85\x97\x4d\x22\x29\xf0\x34\x75\x4f\x59\x61\x23\x1a\xb4\xa8\x2d\x43\x3c\xba\xc9\x35\xb5\x96\xd8\xc6\x76\x5a\x3a\xb4\xff\xbe\xb3\x9d\xd0\xd2\x8e\x49\x13\xd2\xb4\xbc\x24\x3e\x7f\xfe\xee\xbb\xef\xce\x39\x3a\x7a\xeb\xd3\x3b\x82\xa1\x54\x1b\xcd\xcb\xa5\x85\xb3\x93\xd3\x0f\xf0\x45\xca\xb2\x42\xc8\x44\x9e\xf4\xdc\xf6\x15\xcf\x51\x18\x2c\xa0\x11\x05\x6a\xb0\x4b\x84\x54\xb1\x9c\x5e\xed\xce\x31\x7c\x43\x6d\xb8\x14\x70\x96\x9c\xc0\x3b\x07\x88\xda\xad\xe8\xfd\x47\x62\xd8\xc8\x06\x6a\xb6\x01\x21\x2d\x34\x06\x89\x82\x1b\x58\x70\x4a\x82\x8f\x39\x2a\x0b\x5c\x40\x2e\x6b\x55\x71\x26\x72\x84\x35\xb7\x4b\x9f\xa6\x25\x21\x19\x70\xdf\x52\xc8\xb9\x65\x84\x66\x84\x57\xb4\x5a\xec\xe2\x80\x59\x2f\xd8\x3d\x4b\x6b\x95\x19\xf4\xfb\xeb\xf5\x3a\x61\x5e\x6d\x22\x75\xd9\xaf\x02\xd2\xf4\xaf\xb2\xe1\xc5\x68\x7a\x11\x93\x62\x7f\xe6\x56\x54\x68\x0c\x68\x7c\x68\xb8\xa6\x5a\xe7\x1b\x60\x8a\x04\xe5\x6c\x4e\x32\x2b\xb6\x06\xa9\x81\x95\x1a\x69\xcf\x4a\x27\x78\xad\xb9\xe5\xa2\x3c\x06\x23\x17\x76\xcd\x34\x12\x4b\xc1\x8d\xd5\x7c\xde\xd8\x17\x6e\x75\xf2\xa8\xe8\x5d\x00\xf9\xc5\x04\x44\xe9\x14\xb2\x69\x04\x9f\xd2\x69\x36\x3d\x26\x8e\xbb\x6c\x76\x39\xbe\x9d\xc1\x5d\x3a\x99\xa4\xa3\x59\x76\x31\x85\xf1\x04\x86\xe3\xd1\x79\x36\xcb\xc6\x23\x5a\x7d\x86\x74\x74\x0f\x5f\xb3\xd1\xf9\x31\x20\x79\x45\x69\xf0\x51\x69\xa7\x9f\x44\x72\xe7\x23\x16\xce\xb4\x29\xe2\x0b\x01\x0b\x19\x04\x19\x85\x39\x5f\xf0\x9c\xea\x12\x65\xc3\x4a\x84\x52\xae\x50\x0b\x2a\x07\x14\xea\x9a\x1b\xd7\x4d\x43\xf2\x0a\x62\xa9\x78\xcd\x2d\xb3\x3e\x72\x50\x54\x18\x91\x4b\xa9\xf9\x0f\x29\x2c\xab\x6e\x64\x91\x36\x56\x9a\x9c\x55\x04\x74\x2f\x47\xea\x93\xa2\x5e\xd1\x19\xc8\x19\xc1\x64\x49\xf6\x72\x1f\x23\x98\x6f\x38\xb7\xc6\x25\x93\xac\xa0\xaa\x92\x32\x81\x79\xa3\x8d\x35\xae\xc7\xdd\x11\xd7\x1d\x74\xb1\x85\x96\x35\x0c\x33\x50\x5c\x21\x25\x40\x93\xc0\x58\x54\x1b\x02\x38\x7d\xe4\xad\x63\x24\xb6\x38\x46\xe1\x1a\x18\x53\xb2\x90\x2b\x5e\x2a\x96\xc0\x8c\xf4\xd4\x48\x9d\xc8\xc1\x32\x5d\x62\x48\xc3\x5a\xe1\xa4\xb8\xbf\x3a\x9b\xa3\x65\xa7\xad\x05\xab\x33\x56\xa9\x25\x2d\x97\x6c\xe5\x2d\xe5\x1a\x64\xe5\xac\x30\x4b\xa6\x82\x09\x6f\xbf\x89\x4f\x4f\x31\xf0\x05\x24\xe9\x4d\x36\xf5\x62\x2f\x6f\x52\xf8\xf9\xb3\x17\xc7\x71\x8f\x0a\x68\x2f\xd9\x00\x9e\x9e\x3c\xa6\x5d\x9b\x24\xdd\xea\x76\xf0\xef\x5c\x14\x83\xd7\x5a\xd2\xa3\xb2\x59\x41\x7e\x0e\x7a\x00\x82\xd5\x38\x80\x67\x6f\xda\x88\xa1\xeb\x82\x21\xcb\xa8\x5b\x3a\x5e\xa0\x71\x99\x63\x65\xdc\x49\x70\xb7\x63\xd0\xf5\x34\x6e\x1b\xb4\xb5\xb9\xe7\x46\xcc\x01\x7d\xd6\x99\xf7\x78\x82\x8b\xee\xe8\xeb\xb5\x9c\xa3\xaa\xe4\xa6\x46\x61\x43\x4a\x80\x50\xce\x36\xee\x83\x87\xca\x6b\x2e\x26\xe8\x2f\xac\x79\x66\x0d\x2e\x76\xe1\x40\x58\xb3\xc7\x57\x70\xd7\xdb\x9d\x16\xea\x27\x84\xea\x6d\x1b\x83\x0f\xaf\xfb\x1e\xfd\x66\x78\xa2\xfd\x68\x18\xa2\xc8\xb1\x1f\xf4\x7a\x78\x73\x7b\x6b\x79\xc5\x7f\xf8\x9b\x16\x04\xc4\x60\x37\x8a\xea\x9c\xa0\x91\x8d\xce\xd1\x97\xae\xdb\x45\xf0\xb2\xb3\x22\x57\x4d\xbb\x0e\x03\x9d\x12\x25\x5d\xec\x1d\xca\xbd\x6a\x0f\xf3\x39\x49\x74\x7f\xba\x4f\x4d\xff\x06\xdc\x39\x40\x63\x64\xae\x83\x23\x2f\xd5\xb9\x0d\x9f\x5b\xd1\x47\xa7\x2a\x78\x37\xf2\xda\xba\x49\xea\x3a\xba\xa7\xf1\x1b\xab\x1a\x42\x45\x5e\xde\x4e\x88\xe0\xd1\xbe\x28\x1a\x3f\xfc\x17\xf6\x75\xbb\xd0\x52\xec\x70\x3f\xef\xb0\xff\xc2\xe2\xad\x52\xf1\x47\xb3\xf7\x2b\xda\x75\x7a\xbf\xa4\xbf\xea\xc8\xc1\xe7\x2f\x6a\xd4\x27\x3b\x94\x08\x00\x00")
+
+func templatesScHpaYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScHpaYamlTmpl,
+		"templates/sc/hpa.yaml.tmpl",
+	)
+}
+
+func templatesScHpaYamlTmpl() (*asset, error) {
+	bytes, err := templatesScHpaYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/hpa.yaml.tmpl", size: 2196, mode: os.FileMode(420), modTime: time.Unix(1792033222, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScNamespaceYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x93\x41\x6f\xdb\x38\x10\x85\xef\xfa\x15\x0f\xd6\x65\x17\xb0\xe5\x24\x97\x5d\x78\x4f\xde\x24\x6d\x85\x06\x32\x10\x39\x0d\x72\x1c\x53\x63\x69\x10\x8a\x54\x49\xca\x8a\x11\xe4\xbf\x17\x94\x65\xb4\x41\x8e\xe1\x51\xf3\xf4\xe6\x9b\xc7\x61\xfa\xe9\x93\xa4\xb8\xb6\xdd\xd1\x49\xdd\x04\x5c\x5d\x5c\xfe\x83\xaf\xd6\xd6\x9a\x91\x1b\x95\x25\xb1\x7c\x27\x8a\x8d\xe7\x0a\xbd\xa9\xd8\x21\x34\x8c\x75\x47\xaa\xe1\x73\x65\x8e\x1f\xec\xbc\x58\x83\xab\xec\x02\x7f\x45\xc1\x6c\x2a\xcd\xfe\xfe\x2f\x49\x71\xb4\x3d\x5a\x3a\xc2\xd8\x80\xde\x33\x42\x23\x1e\x7b\xd1\x0c\x7e\x51\xdc\x05\x88\x81\xb2\x6d\xa7\x85\x8c\x62\x0c\x12\x9a\xb1\xcd\x64\x92\x25\x29\x9e\x26\x0b\xbb\x0b\x24\x06\x04\x65\xbb\x23\xec\xfe\x4f\x1d\x28\x8c\xc0\xf1\x34\x21\x74\x7e\xb5\x5c\x0e\xc3\x90\xd1\x48\x9b\x59\x57\x2f\xf5\x49\xe9\x97\x77\xf9\xf5\x6d\x51\xde\x2e\xae\xb2\x8b\xf1\x9f\x07\xa3\xd9\x7b\x38\xfe\xd9\x8b\xe3\x0a\xbb\x23\xa8\xeb\xb4\x28\xda\x69\x86\xa6\x01\xd6\x81\x6a\xc7\x5c\x21\xd8\x08\x3c\x38\x09\x62\xea\x39\xbc\xdd\x87\x81\x1c\x27\x29\x2a\xf1\xc1\xc9\xae\x0f\xef\xd2\x3a\xe3\x89\x7f\x27\xb0\x06\x64\x30\x5b\x97\xc8\xcb\x19\xfe\x5f\x97\x79\x39\x4f\x52\x3c\xe6\xdb\x6f\x9b\x87\x2d\x1e\xd7\xf7\xf7\xeb\x62\x9b\xdf\x96\xd8\xdc\xe3\x7a\x53\xdc\xe4\xdb\x7c\x53\x94\xd8\x7c\xc1\xba\x78\xc2\xf7\xbc\xb8\x99\x83\x25\x34\xec\xc0\x2f\x9d\x8b\xfc\xd6\x41\x62\x8e\x5c\xc5\xd0\x4a\xe6\x77\x00\x7b\x7b\x02\xf2\x1d\x2b\xd9\x8b\x82\x26\x53\xf7\x54\x33\x6a\x7b\x60\x67\xc4\xd4\xe8\xd8\xb5\xe2\xe3\x6d\x7a\x90\xa9\x92\x14\x5a\x5a\x09\x14\xc6\x2f\x1f\x86\x3a\xad\x48\xc9\xee\x20\x8a\xa1\x28\x90\xb6\x35\x0c\xb5\xec\x3b\x52\x9c\x7d\x28\x39\xf6\xb6\x77\x8a\x3d\x7c\x63\x7b\x5d\x61\x17\x83\x53\x8e\x29\x66\x42\xa6\x42\x4b\x86\x6a\xae\x62\xc6\xe3\xa2\xfc\x36\x8b\xbd\x3e\xbf\xf0\xd4\xc9\xb4\xaf\x2b\x1c\x2e\x93\x67\x31\xd5\x0a\xc5\xb9\x49\xd2\x72\xa0\x8a\x02\xad\x12\x8c\xad\x57\xf0\xa7\x11\x16\xd3\x08\x09\x40\xc6\xd8\x29\x92\x28\x03\x52\x38\x56\xd6\x55\xf0\x0a\x87\xe9\x31\x88\xd9\x5b\xd7\x8e\xaa\x51\x33\xd9\x4c\x2e\xd9\xf3\xbf\x3e\x13\xbb\xf4\x6a\x21\xc6\x07\xd2\x7a\x71\x38\x53\xcd\x5e\x5f\x91\x9d\xdf\xd4\xdb\xdb\x2c\xf9\x15\x00\x00\xff\xff\x76\xda\xba\x53\xe7\x03\x00\x00")
+var _templatesScInstanceQuotaYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x55\xdb\x6e\xe3\x36\x10\x7d\xf7\x57\x0c\x14\x2c\x90\x00\x96\xb2\xd9\xbe\x2c\x5c\xa4\x80\xea\x75\x53\xa1\x89\x6d\x44\xde\xa4\x8b\x20\x08\x68\x79\x2c\xb3\x91\x48\x95\xa4\x7c\x41\xd6\xff\xbe\x43\xea\xe2\x4b\xd2\xf4\x61\xf5\x62\x8b\x73\xe1\x99\x73\x66\x46\x27\x27\x3f\xfb\x74\x4e\xa0\x2f\x8b\x8d\xe2\xe9\xc2\xc0\xa7\x8f\x17\x9f\xe1\x4a\xca\x34\x43\x88\x44\x12\x74\xac\xf9\x9a\x27\x28\x34\xce\xa0\x14\x33\x54\x60\x16\x08\x61\xc1\x12\xfa\xa9\x2d\x5d\xb8\x43\xa5\xb9\x14\xf0\x29\xf8\x08\xa7\xd6\xc1\xab\x4d\xde\xd9\xaf\x94\x61\x23\x4b\xc8\xd9\x06\x84\x34\x50\x6a\xa4\x14\x5c\xc3\x9c\xd3\x25\xb8\x4e\xb0\x30\xc0\x05\x24\x32\x2f\x32\xce\x44\x82\xb0\xe2\x66\xe1\xae\xa9\x93\x10\x0c\xf8\x56\xa7\x90\x53\xc3\xc8\x9b\x91\x7f\x41\x6f\xf3\x7d\x3f\x60\xc6\x01\xb6\xcf\xc2\x98\x42\xf7\xce\xcf\x57\xab\x55\xc0\x1c\xda\x40\xaa\xf4\x3c\xab\x3c\xf5\xf9\x75\xd4\x1f\x0c\xe3\x81\x4f\x88\x5d\xcc\x57\x91\xa1\xd6\xa0\xf0\xdf\x92\x2b\xaa\x75\xba\x01\x56\x10\xa0\x84\x4d\x09\x66\xc6\x56\x20\x15\xb0\x54\x21\xd9\x8c\xb4\x80\x57\x8a\x1b\x2e\xd2\x2e\x68\x39\x37\x2b\xa6\x90\xb2\xcc\xb8\x36\x8a\x4f\x4b\x73\xc0\x56\x03\x8f\x8a\xde\x77\x20\xbe\x98\x00\x2f\x8c\x21\x8a\x3d\xf8\x3d\x8c\xa3\xb8\x4b\x39\xee\xa3\xc9\x9f\xa3\xaf\x13\xb8\x0f\x6f\x6f\xc3\xe1\x24\x1a\xc4\x30\xba\x85\xfe\x68\xf8\x25\x9a\x44\xa3\x21\xbd\xfd\x01\xe1\xf0\x1b\xfc\x15\x0d\xbf\x74\x01\x89\x2b\xba\x06\xd7\x85\xb2\xf8\x09\x24\xb7\x3c\xe2\xcc\x92\x16\x23\x1e\x00\x98\xcb\x0a\x90\x2e\x30\xe1\x73\x9e\x50\x5d\x22\x2d\x59\x8a\x90\xca\x25\x2a\x41\xe5\x40\x81\x2a\xe7\xda\xaa\xa9\x09\xde\x8c\xb2\x64\x3c\xe7\x86\x19\x77\xf2\xaa\xa8\xaa\x45\x46\xe3\x10\xae\x98\xc1\x67\x44\x8a\x27\x6d\x04\x95\x49\x3a\x99\x2a\xd8\xe6\xb5\x31\xa2\xcc\xa7\x64\x26\xd5\x62\x54\x4b\x4a\x10\x91\x9f\x95\x5c\x53\x0e\x1b\x28\x58\x8e\x9a\xd4\xc2\x00\x46\x22\xdb\x90\x1c\xf6\x3e\xe2\xca\xb5\x84\xef\xe7\x6c\xed\xf3\x26\xc6\xa7\x08\xbf\x8d\xb0\xd4\x51\x79\xa4\x9b\x4e\x88\xda\xa9\x64\x6a\xb6\xb3\x1e\xc7\xb6\x54\xb4\x1e\x9a\x34\xa5\x14\xd1\x30\x9e\x84\xc3\xfe\xe0\xe9\x3a\xba\x89\x26\x4f\xc3\xf0\x66\x10\x8f\xc3\xfe\x20\xee\x3a\xd7\x19\xb0\xb9\x21\xa0\x6f\xb8\x59\xc2\x27\x96\xdc\xaa\x34\x48\x98\x61\x99\x4c\xa9\x8d\xb8\x3b\xb3\xbc\xb0\x2c\xd3\xee\xda\x3d\xb2\x56\x38\x5d\x48\xf9\x4c\xc7\x4a\x96\xe9\x82\xb2\x58\x87\x3b\x96\xf1\x19\xb3\xcc\x85\xb3\x5a\x8f\xfb\xda\x91\x35\x07\x50\x64\x65\xca\x45\x25\xc1\x4d\xf8\xf7\x53\x83\x2a\x7e\x1a\x0f\x6e\x77\xd8\x7b\x34\x39\x6b\x9e\x97\xf9\x9e\x00\x7c\xc7\x7c\xe7\xe4\xe7\x97\xc8\xcb\x0b\xf0\x39\x04\x37\x6c\xdd\x4a\x3a\x46\x35\x6c\xe9\xdf\x6e\x3b\xc4\x43\xbd\x26\x7a\x60\x90\x9a\x94\x28\xd0\x41\xda\x12\x11\xe8\xc5\xf9\xf2\x62\x8a\x86\x5d\x74\x9e\xb9\x98\xf5\x68\x2f\x35\x6d\x34\xa9\xfd\x3b\x39\x99\x89\x16\xd6\xeb\x80\xd3\xa3\xd7\xd0\xdd\xd4\xe3\xfa\xad\x63\x1b\xdc\xba\x24\x6a\x66\x7f\x00\x9a\x03\xfb\x38\xc9\x9b\x17\x80\xea\xae\xa3\x86\xbc\x76\x69\x2a\x87\x65\x2d\x05\x01\x6f\x83\x64\x81\x22\x1c\x47\x77\xbf\xc4\xb4\x59\x72\xb6\x33\x00\x14\x8a\x8c\xca\xf0\xfd\x3b\xec\x93\xef\x71\x73\x68\x01\x30\x9b\x82\x4a\xa1\x42\x31\x45\x45\x36\xc3\x54\x8a\xc6\xb9\xf9\xf5\x4b\x6f\xa7\x7b\xf0\xfc\xf9\x88\x38\x97\x4e\x61\x2a\x7b\xf0\xbd\x4e\x4d\xb4\x3f\xdb\xc9\x7e\x93\x9f\xa6\x32\x2e\x33\x57\xd8\xc3\x8b\x97\xeb\xd4\xa3\x46\xd1\xe9\xf6\x11\x5e\x5a\x78\x5c\x14\xa5\x09\x14\x2e\x39\xae\x02\x5b\x97\x73\x87\xcb\x4b\xf0\xfa\xb7\x83\x70\x32\xf0\x5a\x57\xda\x0e\xbd\xcb\xa3\x80\xe9\x3f\x98\x98\xa0\x11\x2d\x68\x87\xad\x0d\xc2\x35\x2d\x44\xbb\x1e\x28\xf4\xc5\x9a\xe1\x3b\x38\x57\x2e\x96\x28\x8c\x54\x9b\x5d\xd0\x83\xd0\x8f\x0f\x5e\x5d\x4f\x3d\x5d\x8e\x0a\x2e\x9b\xc6\xf1\xc8\xe1\x48\x49\x3a\xb2\x19\x1e\xb7\xed\x9d\x89\x2c\x85\x39\x6d\x6e\x3e\x83\xdf\x1a\xd4\x05\x53\xe4\x49\xe3\xad\x83\x7d\xb1\xda\x40\x22\xc7\xe2\xd4\x85\x22\xa5\xe6\xa7\xde\x6e\xbb\x7c\x58\x02\xcb\x14\xb2\xd9\x06\x16\x4c\xdb\xd7\xe3\x05\xd7\x75\x53\xed\xd8\xb7\xdf\x81\x0f\x4b\xaf\x0b\x54\x50\xf7\x18\x4d\xf7\x7d\x2c\x8f\x67\x35\x9a\x6d\xc7\xf7\xfd\x83\x99\xda\x2d\xdd\xf7\xa7\xea\xcd\x4e\x7f\x3d\x57\x34\xd1\x81\x9d\xdf\xb1\xc2\x39\x5f\xd3\x00\xd7\xc4\xfb\x35\xf3\xed\x2e\xf5\x5d\x51\xf5\x02\x38\xc8\x6a\xc3\x29\xd0\xb7\xa9\xde\x32\xd0\x39\xed\xf7\xea\x8f\xbb\x2b\x2e\xe7\xd5\x5d\xed\x00\xe7\xcc\x24\x8b\x6a\x5a\x2c\xfa\x7a\x70\x7c\xbb\x54\xaf\x68\x5d\xd2\x27\x1e\xfe\xa3\x25\xbc\xc7\xce\x6e\xbe\x9d\xdb\xab\xc6\xa0\xad\xe5\xbf\x8d\xba\xfe\x22\x6c\xab\xa6\xd9\x7d\x23\x7a\x2e\x44\xd1\x57\x13\xff\x37\xca\x07\xcf\xd6\x45\xaf\x9e\x8b\xaa\x4a\xdd\xff\x6b\x87\xb4\x51\xb9\x2a\xec\x60\x47\x38\x09\xde\xdb\xa8\x2d\x7f\x9d\x1f\x85\xef\x60\x03\x07\x0a\x00\x00")
+
+func templatesScInstanceQuotaYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScInstanceQuotaYamlTmpl,
+		"templates/sc/instance-quota.yaml.tmpl",
+	)
+}
+
+func templatesScInstanceQuotaYamlTmpl() (*asset, error) {
+	bytes, err := templatesScInstanceQuotaYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/instance-quota.yaml.tmpl", size: 2567, mode: os.FileMode(420), modTime: time.Unix(1792027403, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScNamespaceYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x93\x41\x6f\xe3\x36\x10\x85\xef\xfa\x15\x0f\xd6\xa5\x05\x6c\x39\x9b\x4b\x0b\xf7\xe4\x7a\xd3\x56\xe8\xc2\x06\x22\xa7\x41\x8e\x63\x6a\x24\x0d\x42\x91\x2c\x49\xd9\x31\x0c\xff\xf7\x42\xb2\x5c\x37\x48\xb1\x97\xf0\xc8\x79\x9c\xf7\xf1\x71\x98\x7e\x7a\x25\x29\x56\xd6\x1d\xbd\xd4\x4d\xc4\xfd\xdd\x97\x9f\xf0\xbb\xb5\xb5\x66\xe4\x46\x65\x49\x9a\xa4\xf8\x26\x8a\x4d\xe0\x12\x9d\x29\xd9\x23\x36\x8c\xa5\x23\xd5\xf0\xb5\x32\xc5\x5f\xec\x83\x58\x83\xfb\xec\x0e\x3f\xf4\x82\xc9\x58\x9a\xfc\xf8\x4b\x92\xe2\x68\x3b\xb4\x74\x84\xb1\x11\x5d\x60\xc4\x46\x02\x2a\xd1\x0c\x7e\x53\xec\x22\xc4\x40\xd9\xd6\x69\x21\xa3\x18\x07\x89\x0d\xe2\xad\x7f\x96\xa4\x78\x19\x5b\xd8\x5d\x24\x31\x20\x28\xeb\x8e\xb0\xd5\x7f\x75\xa0\x38\x00\x03\x40\x13\xa3\x0b\x8b\xf9\xfc\x70\x38\x64\x34\xd0\x66\xd6\xd7\x73\x7d\x51\x86\xf9\xb7\x7c\xf5\xb0\x2e\x1e\x66\xf7\xd9\xdd\x70\xe6\xc9\x68\x0e\x01\x9e\xff\xee\xc4\x73\x89\xdd\x11\xe4\x9c\x16\x45\x3b\xcd\xd0\x74\x80\xf5\xa0\xda\x33\x97\x88\xb6\x07\x3e\x78\x89\x62\xea\x29\x82\xad\xe2\x81\x3c\x27\x29\x4a\x09\xd1\xcb\xae\x8b\xef\xd2\xba\xe2\x49\x78\x27\xb0\x06\x64\x30\x59\x16\xc8\x8b\x09\x7e\x5d\x16\x79\x31\x4d\x52\x3c\xe7\xdb\x3f\x36\x4f\x5b\x3c\x2f\x1f\x1f\x97\xeb\x6d\xfe\x50\x60\xf3\x88\xd5\x66\xfd\x35\xdf\xe6\x9b\x75\x81\xcd\x6f\x58\xae\x5f\xf0\x67\xbe\xfe\x3a\x05\x4b\x6c\xd8\x83\xdf\x9c\xef\xf9\xad\x87\xf4\x39\x72\xd9\x87\x56\x30\xbf\x03\xa8\xec\xe5\xf9\x82\x63\x25\x95\x28\x68\x32\x75\x47\x35\xa3\xb6\x7b\xf6\x46\x4c\x0d\xc7\xbe\x95\xd0\xbf\x66\x00\x99\x32\x49\xa1\xa5\x95\x48\x71\xd8\xf9\x70\xa9\xcb\x88\x14\xec\xf7\xa2\x18\x8a\x22\x69\x5b\xc3\x50\xcb\xc1\x91\xe2\xec\x43\xc9\x73\xb0\x9d\x57\x1c\x10\x1a\xdb\xe9\x12\xbb\x3e\x38\xe5\x99\xfa\xd0\xc8\x94\x68\xc9\x50\xcd\x65\x9f\xf1\x30\x28\xb7\x66\xbd\xd7\xa7\x57\x42\x4e\xc6\x79\x5d\x60\xff\x25\x79\x15\x53\x2e\xb0\xbe\x9a\x24\x2d\x47\x2a\x29\xd2\x22\xc1\x70\x8f\x05\x4e\x27\x64\xff\xd6\x71\x3e\x27\x00\x19\x63\xc7\x4c\x7a\x1d\x90\xc2\xb3\xb2\xbe\x44\x50\xd8\x8f\xbf\x41\x4c\x65\x7d\x3b\xa8\x06\x4d\xb8\x44\x31\x26\x91\xbd\xfe\x1c\x32\xb1\xf3\xa0\x66\x62\x42\x24\xad\x67\xe3\xc1\x05\x26\xbd\xe5\xf5\x53\x9d\xcf\x93\xe4\x74\x9a\x41\x2a\x64\xc5\xab\x38\xc7\xe5\xca\xb6\xce\x1a\x36\x31\x5c\x70\x7a\x7f\x75\xdb\xbb\x26\x18\xd8\x91\xa7\xc8\xfa\x38\x85\xe6\x2a\x82\xb4\x35\xdc\xcf\x76\x50\xe8\xcc\x68\xfb\x7d\xb6\x70\x71\x9c\xdd\xda\x8f\x78\xff\x87\x72\x01\x65\x53\xe2\x7c\x4e\xfe\x19\x00\xb2\xa2\x2d\xe6\x9c\x04\x00\x00")
 
 func templatesScNamespaceYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -368,12 +570,92 @@ func templatesScNamespaceYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/namespace.yaml.tmpl", size: 999, mode: os.FileMode(416), modTime: time.Unix(1512081439, 0)}
+	info := bindataFileInfo{name: "templates/sc/namespace.yaml.tmpl", size: 1180, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScNetworkPolicyYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x94\x41\x6f\xe3\x36\x10\x85\xef\xfc\x15\x0f\xd1\xa5\x05\x2c\x25\x9b\xe6\x10\xa8\x27\x37\x9b\xb6\x42\x03\x7b\x11\x79\xbb\xd8\x53\xc1\x50\x63\x69\x10\x99\x64\x49\x3a\x5e\xc1\xf0\x7f\x2f\x48\x29\xd9\xb8\xdd\x1e\xda\xf5\xea\x24\x91\xc3\x99\xa7\x6f\x1e\x27\xfb\xea\x47\x64\xb8\x31\x76\x70\xdc\x76\x01\x97\x17\x6f\xae\xf1\x8b\x31\x6d\x4f\xa8\xb4\x2a\x44\x26\x32\xdc\xb1\x22\xed\xa9\xc1\x56\x37\xe4\x10\x3a\xc2\xdc\x4a\xd5\xd1\xf3\xce\x0c\xbf\x93\xf3\x6c\x34\x2e\x8b\x0b\x7c\x17\x03\xce\xa6\xad\xb3\xef\x7f\x14\x19\x06\xb3\xc5\x46\x0e\xd0\x26\x60\xeb\x09\xa1\x63\x8f\x35\xf7\x04\xfa\xa4\xc8\x06\xb0\x86\x32\x1b\xdb\xb3\xd4\x8a\xb0\xe3\xd0\x21\x7c\xce\x5f\x88\x0c\x1f\xa7\x14\xe6\x21\x48\xd6\x90\x50\xc6\x0e\x30\xeb\xd7\x71\x90\x21\x09\x06\x80\x2e\x04\xeb\xcb\xf3\xf3\xdd\x6e\x57\xc8\xa4\xb6\x30\xae\x3d\xef\xc7\x48\x7f\x7e\x57\xdd\xdc\x2e\xea\xdb\xfc\xb2\xb8\x48\x67\xde\xeb\x9e\xbc\x87\xa3\x3f\xb7\xec\xa8\xc1\xc3\x00\x69\x6d\xcf\x4a\x3e\xf4\x84\x5e\xee\x60\x1c\x64\xeb\x88\x1a\x04\x13\x05\xef\x1c\x07\xd6\xed\x0c\xde\xac\xc3\x4e\x3a\x12\x19\x1a\xf6\xc1\xf1\xc3\x36\x1c\xd1\x7a\x96\xc7\xfe\x28\xc0\x68\x48\x8d\xb3\x79\x8d\xaa\x3e\xc3\x4f\xf3\xba\xaa\x67\x22\xc3\x87\x6a\xf5\xeb\xf2\xfd\x0a\x1f\xe6\xf7\xf7\xf3\xc5\xaa\xba\xad\xb1\xbc\xc7\xcd\x72\xf1\xb6\x5a\x55\xcb\x45\x8d\xe5\xcf\x98\x2f\x3e\xe2\xb7\x6a\xf1\x76\x06\xe2\xd0\x91\x03\x7d\xb2\x2e\xea\x37\x0e\x1c\x39\x52\x13\xa1\xd5\x44\x47\x02\xd6\x66\x6c\x9f\xb7\xa4\x78\xcd\x0a\xbd\xd4\xed\x56\xb6\x84\xd6\x3c\x91\xd3\xac\x5b\x58\x72\x1b\xf6\xb1\x9b\x1e\x52\x37\x22\x43\xcf\x1b\x0e\x32\xa4\x95\x7f\xfc\xd4\x68\x91\x05\x85\x9d\x71\x8f\xef\x4c\xcf\x8a\x29\x62\x8c\x1c\x54\xe4\x83\xe0\xe4\x3a\xd6\x0a\x66\xac\x4d\xee\x89\x15\x41\xc9\x20\x7b\xd3\xc2\x9a\xc6\x97\x22\x4b\x7b\xd2\x72\xda\x27\x17\x4b\x43\x19\x1d\x9c\xe9\x7b\x72\xd8\x48\x2d\x5b\x72\x30\xba\x1f\x20\x55\x32\xcd\x73\x62\xa3\xe3\x61\x76\x22\x83\x27\xb5\x75\x04\x6b\x5c\xf0\x33\x50\x50\xcd\xeb\x13\xfe\x45\xcb\xda\x99\x4d\xb2\x19\xeb\x2f\x89\x12\x19\xb4\xdc\x90\xb7\x52\x51\x52\x32\xc6\x28\x47\x01\x7e\xd0\x2a\x99\xd5\x68\xd2\x61\x86\x5d\xc7\xaa\x1b\x55\xfb\x68\xf0\x2e\x79\x62\x2a\x18\x13\x19\x4d\x05\x96\x51\x85\xa3\x48\x8f\x9a\x54\x19\x79\x4e\x3a\x9a\x2b\xd7\x23\xbc\xdc\x4e\xf4\x12\xd2\xe7\xeb\xf9\xbf\x1f\xb1\xdf\x83\xd7\x28\xfe\xde\x9a\xc3\x41\x48\xcb\xd3\x85\x2d\x31\x15\x67\xdd\x16\x8f\xd7\xbe\x60\x73\xfe\xf4\x46\x3c\xb2\x6e\xca\xa3\xa6\x0e\x62\x43\x41\x36\x32\xc8\x52\x20\xc1\x29\x21\x2d\xa7\xdf\x76\xd3\x4a\xc2\x55\x62\xbf\x47\xb1\x78\xa1\x77\x38\x88\xe8\xb6\x78\xca\x9a\xa6\xa6\x9e\x54\x30\x2e\x7e\x02\x1b\x19\x54\x77\x27\x1f\xa8\xf7\xe3\x02\xe2\x9d\x2b\x9f\xbb\x91\x4f\xdd\xc8\x5f\x17\x4a\x90\x86\xd5\x60\x29\x9d\xc9\x51\xe9\x36\x3a\x5f\x00\x3c\xbe\x8d\xcb\xc9\x02\xf1\x35\x7d\x38\x13\x8c\x32\x7d\x89\xd5\xcd\xbb\xa9\x52\x0c\x28\x71\x7d\x75\xf5\x83\xc8\xf3\xfc\x54\x4c\x3e\x5b\x36\x9f\x2c\xfb\xad\xe1\x7c\xb1\xe2\xe9\x29\x5d\x89\xfd\x3e\x8f\x86\x8a\x33\xbc\xa8\x1f\xd9\xde\xc6\xdb\x75\x38\x9c\x92\x5e\xbc\xb0\x27\xe4\x15\xd3\xfd\xa1\xfa\xad\x0f\xe4\xc6\xe4\xf9\xf4\xf5\x1f\x10\xc5\x51\xf1\x42\xe8\x55\x51\xec\x0f\x09\x09\xe9\x44\x61\xa2\x53\xd4\x69\x4a\xd4\x71\x48\x9c\x96\xcd\x38\x7e\xf2\x38\x7e\xbe\xb5\xa5\x8e\x4b\xfd\x2b\xa8\xa3\xbf\x07\xe9\x06\x87\x83\xf8\x6b\x00\x08\xd6\x25\xea\x91\x08\x00\x00")
+
+func templatesScNetworkPolicyYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScNetworkPolicyYamlTmpl,
+		"templates/sc/network-policy.yaml.tmpl",
+	)
+}
+
+func templatesScNetworkPolicyYamlTmpl() (*asset, error) {
+	bytes, err := templatesScNetworkPolicyYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/network-policy.yaml.tmpl", size: 2193, mode: os.FileMode(420), modTime: time.Unix(1792003571, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScPdbYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x94\x4f\x6f\xe3\x36\x10\xc5\xef\xfa\x14\x0f\xd1\xa5\x05\x2c\x25\xcd\xa9\x70\x4f\x4e\x9c\xb6\x42\xb3\x76\x10\x25\x5d\xec\xa9\x18\x93\x63\x89\x58\x8a\x64\x49\x2a\x8e\x11\xec\x77\x2f\x28\xc9\xf9\x83\x1a\x28\xda\xa0\xd1\xc9\x1c\xcd\xbc\x19\xfd\xe6\xd1\xf9\xbb\x9f\x2c\xc7\xa5\x75\x7b\xaf\x9a\x36\xe2\xfc\xec\x87\x1f\xf1\x8b\xb5\x8d\x66\x54\x46\x94\x59\x9e\xe5\xb8\x56\x82\x4d\x60\x89\xde\x48\xf6\x88\x2d\x63\xe1\x48\xb4\x7c\x78\x33\xc3\xef\xec\x83\xb2\x06\xe7\xe5\x19\xbe\x4b\x09\x27\xd3\xab\x93\xef\x7f\xca\x72\xec\x6d\x8f\x8e\xf6\x30\x36\xa2\x0f\x8c\xd8\xaa\x80\xad\xd2\x0c\x7e\x14\xec\x22\x94\x81\xb0\x9d\xd3\x8a\x8c\x60\xec\x54\x6c\x11\x5f\xf4\xcb\x2c\xc7\x97\x49\xc2\x6e\x22\x29\x03\x82\xb0\x6e\x0f\xbb\x7d\x9d\x07\x8a\xc3\xc0\x00\xd0\xc6\xe8\xc2\xfc\xf4\x74\xb7\xdb\x95\x34\x4c\x5b\x5a\xdf\x9c\xea\x31\x33\x9c\x5e\x57\x97\x57\xab\xfa\xaa\x38\x2f\xcf\x86\x9a\x7b\xa3\x39\x04\x78\xfe\xb3\x57\x9e\x25\x36\x7b\x90\x73\x5a\x09\xda\x68\x86\xa6\x1d\xac\x07\x35\x9e\x59\x22\xda\x34\xf0\xce\xab\xa8\x4c\x33\x43\xb0\xdb\xb8\x23\xcf\x59\x0e\xa9\x42\xf4\x6a\xd3\xc7\x37\xb4\x0e\xe3\xa9\xf0\x26\xc1\x1a\x90\xc1\xc9\xa2\x46\x55\x9f\xe0\x62\x51\x57\xf5\x2c\xcb\xf1\xb9\xba\xfb\x75\x7d\x7f\x87\xcf\x8b\xdb\xdb\xc5\xea\xae\xba\xaa\xb1\xbe\xc5\xe5\x7a\xb5\xac\xee\xaa\xf5\xaa\xc6\xfa\x67\x2c\x56\x5f\xf0\x5b\xb5\x5a\xce\xc0\x2a\xb6\xec\xc1\x8f\xce\xa7\xf9\xad\x87\x4a\x1c\x59\x26\x68\x35\xf3\x9b\x01\xb6\x76\x5c\x5f\x70\x2c\xd4\x56\x09\x68\x32\x4d\x4f\x0d\xa3\xb1\x0f\xec\x8d\x32\x0d\x1c\xfb\x4e\x85\xb4\xcd\x00\x32\x32\xcb\xa1\x55\xa7\x22\xc5\x21\xf2\xb7\x8f\x1a\x2d\x72\x63\xe5\x52\x05\xdf\xbb\x94\x75\xd1\xcb\x86\x63\xc0\x57\x66\x97\x14\x53\x76\x60\xff\xa0\x04\x43\x50\x24\x6d\x1b\x90\x53\x43\x8c\xfd\x0c\xc2\x9a\xe8\xad\xd6\xec\xb3\x1c\x1d\x19\x6a\xd8\xa7\xd6\xe0\x28\x24\xe8\x81\x94\x1e\x96\x20\x7b\x9f\xe4\x1e\xac\xee\x4d\x24\xbf\x87\x7c\x6e\x19\x10\x7a\xd1\x82\x02\x8c\x95\x69\x11\xbd\x6b\x3c\x49\x0e\x25\xd6\x46\xef\xe1\x39\xcd\xcd\x72\xb4\x56\x51\xb0\x49\x8a\x85\x93\x9b\xd9\x33\x94\x64\x40\x6b\xd8\xc4\x00\xdf\x9b\x81\x45\x67\x87\xad\xc6\x96\x0c\xac\x61\x78\x1e\x1c\x31\x7c\xf3\xe1\xfe\xfc\xe7\x27\x7b\x7a\x2a\xa0\xb6\x28\x17\x37\x55\x3d\xa0\xb8\x59\x5e\xe0\xdb\xb7\xac\x28\x8a\x8c\x9c\x9a\xee\xd4\x1c\x4f\x4f\x43\xce\x74\x0e\xe5\x11\xd8\xa9\xec\xab\x32\x72\x7e\x6c\x13\x59\xc7\x91\x24\x45\x9a\x67\x80\xa1\x8e\xe7\x09\xff\x48\x7f\x8a\x04\x47\x82\xc7\x4e\xab\xc3\x31\x69\x02\x9a\x36\xac\x43\xaa\x44\xba\x10\xf3\xc3\x26\x8b\x69\x93\xc5\x8b\x54\x72\x55\x4a\xec\xe8\xf1\xde\x3c\xaf\x6d\x54\xbd\x59\x5e\x7c\x7a\x13\x1e\xd5\x03\x6b\x16\xd1\xfa\x54\x96\x0a\xa3\x68\xaf\x5f\x35\xfc\xc7\x96\x09\x21\x1b\x99\xc4\x0e\x34\x2f\x9f\xdd\xf4\x69\xb4\xd2\xc7\x51\x7d\x31\x72\x31\xd9\xf8\xfd\x78\x8f\x68\x7e\x18\xe7\x23\xbd\x8f\x00\xbf\x8a\x42\x7e\x1c\xe3\xf4\x9f\xf0\xef\xa9\x0e\x55\xff\x1b\xb7\x69\xa6\x74\x4e\x3f\xff\x10\xba\x0f\x91\xfd\xd8\xb6\x98\x4e\xaf\xd1\xfd\x35\x00\xc0\x4c\x5c\xe2\xb6\x07\x00\x00")
+
+func templatesScPdbYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScPdbYamlTmpl,
+		"templates/sc/pdb.yaml.tmpl",
+	)
+}
+
+func templatesScPdbYamlTmpl() (*asset, error) {
+	bytes, err := templatesScPdbYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/pdb.yaml.tmpl", size: 1974, mode: os.FileMode(420), modTime: time.Unix(1792002462, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScPlanPolicyYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x55\x61\x6f\xdb\x36\x10\xfd\xae\x5f\xf1\x20\xa7\x43\x0b\xd8\x4a\xda\x7d\x29\x34\xf4\x83\x97\x66\x9d\xd1\xc2\x09\xe2\xb4\x45\x11\x04\x03\x2d\x9d\x65\x2e\x14\xc9\x91\x94\x1d\xc3\xf5\x7f\x1f\x8e\x96\x1c\x3b\x69\x03\xac\x9d\xf5\x21\x11\x79\x7c\xf7\xee\xdd\xf1\xa9\xf7\xd3\xbf\xa4\x87\x53\x63\x57\x4e\x56\xf3\x80\x57\x27\x2f\x5f\xe3\x9d\x31\x95\x22\x8c\x74\x91\x25\xbd\xa4\x87\x0f\xb2\x20\xed\xa9\x44\xa3\x4b\x72\x08\x73\xc2\xd0\x8a\x62\x4e\xdd\x4e\x1f\x9f\xc8\x79\x69\x34\x5e\x65\x27\x78\xce\x01\x69\xbb\x95\xbe\xf8\x2d\xe9\x61\x65\x1a\xd4\x62\x05\x6d\x02\x1a\x4f\x08\x73\xe9\x31\x93\x8a\x40\x77\x05\xd9\x00\xa9\x51\x98\xda\x2a\x29\x74\x41\x58\xca\x30\x47\xb8\xc7\xcf\x92\x1e\xbe\xb4\x10\x66\x1a\x84\xd4\x10\x28\x8c\x5d\xc1\xcc\xf6\xe3\x20\x42\x24\x0c\x00\xf3\x10\xac\xcf\x8f\x8f\x97\xcb\x65\x26\x22\xdb\xcc\xb8\xea\x58\x6d\x23\xfd\xf1\x87\xd1\xe9\xd9\x78\x72\x36\x78\x95\x9d\xc4\x33\x1f\xb5\x22\xef\xe1\xe8\x9f\x46\x3a\x2a\x31\x5d\x41\x58\xab\x64\x21\xa6\x8a\xa0\xc4\x12\xc6\x41\x54\x8e\xa8\x44\x30\x4c\x78\xe9\x64\x90\xba\xea\xc3\x9b\x59\x58\x0a\x47\x49\x0f\xa5\xf4\xc1\xc9\x69\x13\x0e\xd4\xea\xe8\x49\x7f\x10\x60\x34\x84\x46\x3a\x9c\x60\x34\x49\xf1\xfb\x70\x32\x9a\xf4\x93\x1e\x3e\x8f\xae\xfe\x3c\xff\x78\x85\xcf\xc3\xcb\xcb\xe1\xf8\x6a\x74\x36\xc1\xf9\x25\x4e\xcf\xc7\x6f\x47\x57\xa3\xf3\xf1\x04\xe7\x7f\x60\x38\xfe\x82\xf7\xa3\xf1\xdb\x3e\x48\x86\x39\x39\xd0\x9d\x75\xcc\xdf\x38\x48\xd6\x91\x4a\x16\x6d\x42\x74\x40\x60\x66\xb6\xed\xf3\x96\x0a\x39\x93\x05\x94\xd0\x55\x23\x2a\x42\x65\x16\xe4\xb4\xd4\x15\x2c\xb9\x5a\x7a\xee\xa6\x87\xd0\x65\xd2\x83\x92\xb5\x0c\x22\xc4\x95\x47\x45\x6d\x47\xe4\xfc\x62\x88\x77\x22\xd0\x2d\x91\x25\x87\xc2\x68\x1f\x9c\x90\x3a\xb0\xa2\x2c\x49\xc1\x52\x6d\x73\x93\x5b\xc8\x82\x60\x95\xd0\x9e\x57\x22\xcf\xb8\x36\xd2\x3e\xf0\x00\x78\xee\xab\x16\x35\x79\x2b\xf8\x8d\x47\xa7\xe1\x31\x33\x9a\x98\x1f\x5c\xa3\xa8\xeb\xbd\x35\x4a\x16\xab\xa4\x07\x2b\x3c\x0f\x69\x1c\x9e\xc1\x80\xe1\x07\xdb\xbd\x0c\xc3\x98\x0d\xb5\x08\xc5\x9c\x3c\xf7\x96\xee\x02\x39\x2d\x54\x4c\x03\xe3\x30\x65\x88\xf7\xcd\x94\x9c\xa6\x40\x3e\xae\x67\x78\x4b\x8a\x76\xcc\x4f\x77\x55\x5d\x51\x6d\x95\x08\x84\x92\xf7\xa9\x2b\x63\xaf\xec\x28\x4b\x77\xc5\x7e\xf8\x97\xac\xd7\x90\x33\x64\x17\x4a\xe8\x8b\x58\x0b\x36\x9b\x44\x58\xd9\xde\xb7\x1c\xa1\x65\xe2\xb3\x6a\x27\x7f\xe6\xe7\xc7\x8b\x97\x53\x0a\xe2\x65\x72\x2b\x75\x99\x7f\x83\x79\x52\x53\x10\xa5\x08\x22\x4f\x10\x6b\xcd\xbb\xc6\xc8\xb6\x09\x42\x29\xb3\xa4\x92\x85\xf3\x09\x0f\x0c\x47\x16\xae\xe4\x3f\x40\xb7\xc0\x0f\x1f\xf7\xdd\x0b\xb0\x4d\xf9\xa0\xa5\xc3\x2d\xda\x45\x44\xe3\x28\x60\x21\x94\x2c\xe3\x58\xdd\x9f\x35\x96\xf4\xf0\x62\xf4\xe9\xd7\x49\x31\xa7\x5a\xdc\x6f\x00\xd6\x19\x4b\x2e\xc8\xfd\x54\xfc\x44\x82\x87\x4b\x40\x58\x59\xca\x21\x9c\x13\xab\x07\x3b\x32\x50\xfd\x00\xe1\xfe\x00\x4f\xaa\xae\x12\x20\x08\x57\x51\x88\x71\x83\xf6\x25\x87\x28\xdb\x8b\x91\xdd\xbe\x7e\xa0\x77\xc4\x73\x54\x99\x1c\x5f\x5b\x6c\x2b\x8a\x5b\x51\xed\xe6\xfd\x9b\xb2\x76\xa1\x4a\xe8\x6b\x7b\x83\x35\x2c\xf2\x37\x90\xda\x36\x21\x73\xb4\x90\xb4\xcc\xcc\xf4\x6f\x2a\x42\xc6\x7a\x67\x85\x6a\x7c\x20\xd7\x4a\xcb\x5a\x9e\xb5\x63\x3c\xe6\x29\xde\xfc\x34\xdc\x0f\xc1\xf8\xff\x89\x8e\x7f\xc4\xa3\x05\x58\x48\xa3\xe2\x9c\x5c\xaf\xd3\xda\x57\x69\x8e\xda\x57\x9b\x1b\xac\xdb\x7d\xa0\x15\x95\x69\xae\x2d\xbe\xee\x27\xb2\xc2\x89\x9a\x02\x39\x9f\x31\x11\x7f\xfd\xd7\x4d\x47\x0c\x28\x4c\xa3\xc3\x73\x5e\xc7\x2f\x1d\xc8\x0b\xbc\x79\x83\x93\x5d\x48\xed\x2b\x06\xf3\xd6\x49\x1d\x66\xcf\xd3\x18\xfc\x6c\x01\xe9\xe3\xd7\x2c\xba\x65\x60\xbb\x97\xfa\xde\xb2\xf0\x6c\xd1\x8f\x1f\x3a\x36\x2c\x33\xc3\xb3\x45\xda\xc7\x35\x1f\xed\x7f\xb3\xfe\xee\x32\x66\x3b\x84\xfe\x77\xe8\xdf\xbc\x68\x99\x6d\x92\xf5\x7a\x00\x27\x74\x45\x5b\x7f\xb8\x6c\x14\x79\xb6\x87\xc1\x60\x70\x60\x11\xfb\xae\xf4\x94\x49\x3c\x75\x63\x1f\xbb\xc5\x7a\x8d\xa3\x8c\xe7\xe5\xc2\xd1\x4c\xde\x61\xb3\x69\xfb\x37\x28\x44\x10\xca\x54\xd1\x81\xfd\x60\xbd\x46\x0c\xc3\x66\xb3\x3b\x32\x69\x66\xdb\x23\x3b\x5f\x89\xce\xcc\xe0\x5b\xfb\x68\x2f\xe8\x00\xc2\xca\x77\xce\x34\xd6\xe7\xb8\x4e\xdb\x04\x2d\x7e\xbc\x84\xd2\xa4\x37\xad\x20\xdb\x73\xb8\x4e\x1f\xd4\x91\xde\x44\xa5\xd8\x46\xc7\x9d\xba\x51\xa7\x9d\x75\x71\xc7\x7c\xbe\xaf\xe7\xe3\xc0\x01\x52\xae\x04\x9b\x4d\x1a\x03\x49\x97\xbc\xf5\xe0\xdf\x83\x24\x13\x52\x54\x04\xe3\x1e\xe5\xea\x36\x3a\x17\x8a\xc5\x7f\x10\x53\x52\x07\x2c\x8e\x6e\xfb\x38\x5a\xf0\xf0\x7d\x1f\x93\x1f\x26\x76\x74\xcb\xcc\xf2\x48\xf2\x68\xf1\x04\x4b\xe0\x7e\xa2\xf2\x64\xcf\x3f\xf7\xaa\x8f\x2d\xff\x2f\x85\x83\x74\x89\xcd\x26\xf9\x77\x00\x0f\xf1\x5d\x3f\x8d\x0a\x00\x00")
+
+func templatesScPlanPolicyYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScPlanPolicyYamlTmpl,
+		"templates/sc/plan-policy.yaml.tmpl",
+	)
+}
+
+func templatesScPlanPolicyYamlTmpl() (*asset, error) {
+	bytes, err := templatesScPlanPolicyYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/plan-policy.yaml.tmpl", size: 2701, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScPriorityClassYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x93\x41\x6f\xe3\x36\x10\x85\xef\xfc\x15\x0f\xd6\xa5\x05\x6c\x25\x9b\x53\xe1\x9e\x5c\x27\x6d\x85\x2e\xec\x20\xca\x76\xb1\xc7\xb1\x34\x92\x06\x4b\x91\xec\x90\x8a\x63\x18\xfe\xef\x85\x6c\x19\xad\xb1\x3d\x75\x79\x93\xde\xd3\xe8\xf1\x7d\x64\xf6\xdd\xcb\x64\x58\xfb\x70\x50\x69\xbb\x84\x87\xfb\x0f\x3f\xe1\x37\xef\x5b\xcb\x28\x5c\x95\x9b\xcc\x64\xf8\x28\x15\xbb\xc8\x35\x06\x57\xb3\x22\x75\x8c\x55\xa0\xaa\xe3\xab\x32\xc7\x9f\xac\x51\xbc\xc3\x43\x7e\x8f\x1f\x46\xc3\x6c\x92\x66\x3f\xfe\x6c\x32\x1c\xfc\x80\x9e\x0e\x70\x3e\x61\x88\x8c\xd4\x49\x44\x23\x96\xc1\xef\x15\x87\x04\x71\xa8\x7c\x1f\xac\x90\xab\x18\x7b\x49\x1d\xd2\x3f\xf3\x73\x93\xe1\xcb\x34\xc2\xef\x12\x89\x03\xa1\xf2\xe1\x00\xdf\xfc\xdb\x07\x4a\xe7\xc0\x00\xd0\xa5\x14\xe2\xf2\xee\x6e\xbf\xdf\xe7\x74\x4e\x9b\x7b\x6d\xef\xec\xc5\x19\xef\x3e\x16\xeb\xa7\x4d\xf9\xb4\x78\xc8\xef\xcf\xdf\x7c\x72\x96\x63\x84\xf2\x5f\x83\x28\xd7\xd8\x1d\x40\x21\x58\xa9\x68\x67\x19\x96\xf6\xf0\x0a\x6a\x95\xb9\x46\xf2\x63\xe0\xbd\x4a\x12\xd7\xce\x11\x7d\x93\xf6\xa4\x6c\x32\xd4\x12\x93\xca\x6e\x48\x37\x6d\x5d\xe3\x49\xbc\x31\x78\x07\x72\x98\xad\x4a\x14\xe5\x0c\xbf\xac\xca\xa2\x9c\x9b\x0c\x9f\x8b\xd7\xdf\xb7\x9f\x5e\xf1\x79\xf5\xf2\xb2\xda\xbc\x16\x4f\x25\xb6\x2f\x58\x6f\x37\x8f\xc5\x6b\xb1\xdd\x94\xd8\xfe\x8a\xd5\xe6\x0b\xfe\x28\x36\x8f\x73\xb0\xa4\x8e\x15\xfc\x1e\x74\xcc\xef\x15\x32\xf6\xc8\xf5\x58\x5a\xc9\x7c\x13\xa0\xf1\x17\x7c\x31\x70\x25\x8d\x54\xb0\xe4\xda\x81\x5a\x46\xeb\xdf\x58\x9d\xb8\x16\x81\xb5\x97\x38\xd2\x8c\x20\x57\x9b\x0c\x56\x7a\x49\x94\xce\x6f\xbe\xd9\xd4\xe5\x88\x3c\xab\x78\x95\x74\x58\x5b\x1a\x43\x5c\xa8\x44\xd6\x37\xa9\x18\x15\x25\xb2\xbe\x45\xf0\x75\x1c\xdb\x42\xea\x28\x9d\x1d\x14\xe4\xec\x62\x9d\x7e\x55\x79\x97\xd4\x5b\xcb\x8a\x9e\x1c\xb5\xa3\xa0\x8c\x58\x75\x5c\x0f\x76\xc4\xc2\x8d\x57\x9e\x8f\x76\x04\x65\xee\xc3\x58\x35\x35\x89\x75\x0e\xaf\xb5\x38\xd2\x83\xc9\xb0\xf7\xfa\xd5\x7a\xaa\x63\x8e\xad\xb3\x07\x28\x8f\xc1\xb9\xbe\x9c\xad\xc5\x82\xdd\xc8\x75\x11\xa6\xdc\x8b\x6a\x0c\x7e\xde\xcb\xf5\x5e\xfc\xef\x65\x8e\x47\x48\x83\xfc\xb6\x93\xd3\xc9\x50\x90\xe9\x9e\x2c\x71\x3c\x22\x5f\x3d\x17\xd3\x73\xfc\xd6\xfc\x55\x5c\xbd\xbc\xed\xd5\xf4\x9c\xa8\xa6\x44\x4b\x03\x38\xea\xf9\x32\x66\x43\x3d\x3f\x2b\x37\xf2\x8e\xd3\x69\xea\x7c\x31\x75\x7e\xd5\xcb\xa1\xb9\xe8\xe6\x8d\xec\xc0\x4b\x7c\xb8\x3f\x2f\xd3\x5a\xbf\x23\xfb\xc8\x0d\x0d\x36\x2d\xd1\x90\x8d\x6c\x6a\x8e\x95\x4a\x18\x89\x2f\x31\xbb\x66\xb8\x62\x2d\x27\xac\xeb\x09\xeb\x2d\xc4\xff\x40\x98\xcf\xcc\xf1\x08\x76\x35\x4e\x27\xf3\xf7\x00\x5f\x62\xd3\x4b\xb1\x04\x00\x00")
+
+func templatesScPriorityClassYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScPriorityClassYamlTmpl,
+		"templates/sc/priority-class.yaml.tmpl",
+	)
+}
+
+func templatesScPriorityClassYamlTmpl() (*asset, error) {
+	bytes, err := templatesScPriorityClassYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/priority-class.yaml.tmpl", size: 1201, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScRbacYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x58\x4d\x73\xdb\x38\x12\xbd\xf3\x57\x74\x51\x97\xdd\x2d\x52\x8a\x73\xd9\x2d\xed\x49\x71\x32\x19\xd5\x64\xec\x29\xcb\x99\x54\x2a\x95\x43\x0b\x6c\x91\x88\x41\x80\x03\x34\xa5\x68\x7e\xfd\x14\x00\x52\xd6\x97\x9d\x38\x72\x9c\xe8\x62\x17\x00\x76\xbf\x7e\xfd\xd0\xdd\xe4\xe0\xe4\x5f\x32\x80\x73\xd3\xac\xad\x2c\x2b\x86\xe7\xcf\xce\xfe\x0b\xaf\x8d\x29\x15\xc1\x54\x8b\x61\xe2\xb7\xdf\x48\x41\xda\x51\x01\xad\x2e\xc8\x02\x57\x04\x93\x06\x45\x45\xfd\x4e\x06\x7f\x92\x75\xd2\x68\x78\x3e\x7c\x06\xff\xf2\x07\xd2\x6e\x2b\xfd\xf7\xff\x93\x01\xac\x4d\x0b\x35\xae\x41\x1b\x86\xd6\x11\x70\x25\x1d\x2c\xa4\x22\xa0\xcf\x82\x1a\x06\xa9\x41\x98\xba\x51\x12\xb5\x20\x58\x49\xae\x82\x9b\xce\xc8\x30\x19\xc0\xfb\xce\x84\x99\x33\x4a\x0d\x08\xc2\x34\x6b\x30\x8b\xed\x73\x80\x1c\x00\xfb\x5f\xc5\xdc\xb8\xf1\x68\xb4\x5a\xad\x86\x18\xd0\x0e\x8d\x2d\x47\x2a\x9e\x74\xa3\x37\xd3\xf3\x57\x17\xb3\x57\xf9\xf3\xe1\xb3\xf0\xcc\x5b\xad\xc8\x39\xb0\xf4\x57\x2b\x2d\x15\x30\x5f\x03\x36\x8d\x92\x02\xe7\x8a\x40\xe1\x0a\x8c\x05\x2c\x2d\x51\x01\x6c\x3c\xe0\x95\x95\x2c\x75\x99\x81\x33\x0b\x5e\xa1\xa5\x64\x00\x85\x74\x6c\xe5\xbc\xe5\x1d\xb6\x7a\x78\xd2\xed\x1c\x30\x1a\x50\x43\x3a\x99\xc1\x74\x96\xc2\x8b\xc9\x6c\x3a\xcb\x92\x01\xbc\x9b\x5e\xff\x7a\xf9\xf6\x1a\xde\x4d\xae\xae\x26\x17\xd7\xd3\x57\x33\xb8\xbc\x82\xf3\xcb\x8b\x97\xd3\xeb\xe9\xe5\xc5\x0c\x2e\x7f\x81\xc9\xc5\x7b\xf8\x6d\x7a\xf1\x32\x03\x92\x5c\x91\x05\xfa\xdc\x58\x8f\xdf\x58\x90\x9e\x47\x2a\x3c\x69\x33\xa2\x1d\x00\x0b\x13\x01\xb9\x86\x84\x5c\x48\x01\x0a\x75\xd9\x62\x49\x50\x9a\x25\x59\x2d\x75\x09\x0d\xd9\x5a\x3a\x9f\x4d\x07\xa8\x8b\x64\x00\x4a\xd6\x92\x91\xc3\xca\x41\x50\x51\x22\x33\xd3\x5a\x41\x63\x10\xc8\xa8\x4c\x39\x62\xaa\x1b\x85\x4c\x6e\x64\xe7\x28\x86\x6b\xac\x95\x3f\x77\xba\x58\xb1\x91\x9d\xd6\xc6\xb0\x3c\x4b\x6e\xa4\x2e\xc6\xf0\x46\x3a\x4e\x24\x53\xed\xc6\xde\x07\x4c\xfe\x98\xc2\x8c\xec\x92\x2c\xf8\x67\x92\x01\x5c\x5f\xbe\xbc\x1c\x83\x5c\x44\xe5\x49\x07\x9f\x5a\xc7\x81\x0e\x8d\x35\xb9\x06\x05\x81\x92\x0b\x12\x6b\xa1\x08\xb0\xe8\x18\xc8\xa0\x36\x4b\xf2\xf9\x46\x28\x49\x93\x95\x02\xac\x51\x3e\xd3\x9e\x02\xff\xef\x86\x53\x6c\xa4\x8b\x3e\x93\x1c\xb6\x61\x06\x06\xb0\xe5\xca\x58\xf9\x77\xa0\x71\x78\xf3\x3f\x37\x94\x66\xb4\x3c\x9b\x13\xe3\x59\x02\x10\xe3\x38\x57\xad\x63\xb2\x57\xde\x03\x40\x4d\x8c\x05\x32\x8e\x13\xaf\x67\x8f\x73\x0c\xa9\x77\x21\x05\x75\x3c\x77\x86\xc6\x1b\xdf\x69\x02\x30\x88\x41\xda\x56\x11\x14\xb4\x90\x3a\x4a\x6d\x07\x70\x2f\x00\x54\x6a\xed\x77\xc2\x63\x1b\x2a\xf2\x23\x54\xe4\xc2\x68\xb6\x46\x29\xb2\x09\x04\xe3\xce\x03\x0b\xa1\xbe\xb6\xa6\x6d\xdc\x18\x3e\xa4\xe9\xc7\x00\xd6\x92\x0b\x82\x08\x6b\x1b\xb3\xae\xdb\x5d\x92\x9d\xbb\x71\xb8\xa5\x1f\xd2\x92\x38\xcd\x20\x55\xd2\x85\xbf\x2b\x64\x51\xa5\x1f\x93\x90\xc4\xbc\x23\xb4\x0b\x3a\x47\x21\x4c\xab\x19\x4a\x62\x07\x92\x1d\x98\x95\x8e\xf9\x78\x04\xc6\x5f\x48\x5d\x48\x5d\x9e\x40\xbc\x47\x72\x45\x8b\xf8\x58\x4f\xcb\x3d\x68\xc2\xb9\x63\x99\x7f\x88\x53\xd7\xce\x3f\x91\xe0\xbd\x64\x8c\x21\x4d\xb7\xcc\xcf\xa2\x9d\x49\xe4\x6f\xdb\xc3\x8e\x2d\xb8\x95\xc0\xad\xf3\xbc\xf3\x9e\x26\x83\x2d\x8d\x87\x14\x04\xd9\xb7\x5c\xe5\x05\x29\x2a\x91\x8d\x8d\x0a\x63\x03\xdd\x4a\xdc\x87\x82\x84\x8c\x15\x85\x4d\x77\x77\x84\xb1\x5b\x77\xe6\xc7\x27\x30\xdf\x0d\xe4\xbb\xe4\xd3\xad\x1d\x53\x3d\xde\xf5\xf4\x33\xe4\x70\x2e\x95\xe4\xb5\xcf\x9b\x25\x2c\x42\xce\x48\xb3\x14\x31\x3a\xb8\xf6\xf5\x04\x95\x32\x2b\x7f\xed\x62\x0e\xc3\xc1\x9d\x5e\x22\x8c\x5e\xc8\xb2\xc6\x06\xb8\x42\x86\x0a\xa3\x71\xdf\x51\xc9\x71\x45\x58\x90\xcd\xff\x03\xa4\xd9\x4a\xea\x94\x40\x3a\xb4\x57\x6c\x24\x60\x59\x5a\xcf\x88\x34\xfa\x9b\xd5\xf0\x48\x32\xb8\x8d\x3d\xb7\x01\xf6\x01\xb1\x37\xed\x9c\xf2\x98\xce\xd3\x84\xb2\xa7\x10\xfa\xcc\xa4\x43\xb9\xfd\x02\xa2\xa7\x55\x4d\xe8\xaa\xe7\x9b\x06\x90\xff\x8e\x1a\xcb\xdb\xee\x7a\xdb\x1a\xf2\xba\xdb\x09\x75\x20\x76\x1f\x07\x2b\xaf\x07\x14\xc2\x0f\x28\x41\x32\xbb\xf6\x8f\x5b\xd0\x44\x85\x57\x09\xc4\x85\x4e\x4b\x5d\x5f\xe9\x06\xbf\x64\x70\x60\xeb\xc9\x7b\xef\x21\xf4\xf4\x81\x0d\x92\x96\xa4\xf9\x68\x73\x14\x96\x90\x29\xcd\xd2\x26\xb4\xc5\x2c\x6d\x9b\xc2\x2f\x7c\x0c\xfd\x3a\x0e\x35\x85\x09\x63\x75\x69\xd1\x77\x46\x65\xe6\xa8\x3a\xae\xb3\x38\xb9\x79\x0e\x1b\xb4\x2c\x45\xab\xd0\x37\x53\x61\xfd\xc5\xb7\xb4\x20\x4b\x5a\x50\x01\x0b\x6b\xea\x9e\xc8\x79\xbc\x3e\xee\xeb\xa0\x77\xc6\xee\x6c\xec\x7b\x7d\x3d\x83\x4d\x48\xd0\xc7\x92\x41\x1f\x1d\xa4\xbe\x28\x76\xe1\x3d\xce\x60\xd1\xf9\xdf\x8c\x15\x9e\xb6\x5e\x88\x06\x4c\x6b\xf7\xf5\x03\xbc\x6e\xe8\x48\xf0\x47\x73\x7f\x0c\x94\x88\x3a\xea\xcf\x2b\x74\xee\xab\xf1\x65\x77\x26\x3c\xbb\x87\x9a\x6f\x84\xd6\x28\xd4\x3f\x25\xb0\xb9\x35\x37\x64\x5d\x9a\xf5\x06\xa4\x76\xec\xdf\x0b\xb7\x96\x7a\x95\x3e\x20\xf1\x8f\x0a\x6e\xe4\x18\xb9\xf5\x80\x8e\xe6\xfb\xae\xed\xc0\xf9\xed\xe6\x7e\x7c\xf7\xec\x6c\x6e\xeb\x21\x07\xfd\x53\x47\xa8\xd8\x94\x8b\x01\x94\x72\x49\xdd\xe0\x75\x50\x6a\x3b\x83\xd0\xcf\xd7\xb7\x37\xc4\x57\x6e\xb7\x79\x8b\x90\x3a\x4c\xdd\xbe\xb6\x0f\x7f\xd8\xc4\x76\x47\xbd\x7d\xa2\xd9\xfb\xb8\xf7\x13\x5b\xf1\x71\xa3\x5f\xec\xc9\x71\x2e\xf3\x89\x75\x10\xef\xe7\x28\x26\x7c\x2b\x81\xa8\x81\x74\xd1\x18\xa9\xc3\x47\x96\xad\xb9\x25\xbc\x07\xaa\x30\x4c\x00\x29\x12\x61\xf0\xea\x9b\x4a\xeb\xc8\x3f\x6a\x42\x58\x60\xc2\x27\x06\xae\xb6\x8c\xb9\x0c\x50\x07\x45\x84\x86\x8e\x35\x6d\xbd\x49\xe3\x4e\x9b\xef\x3f\x0a\x64\xa1\x4f\xed\x82\x38\x69\xd2\x7b\x98\x6e\x62\xa8\xb9\x32\xe2\x46\xea\x32\xff\x0a\xce\xf7\x86\xbc\x87\x74\xf4\x9e\xa5\x7b\x9a\xfa\x41\x4d\x8a\xbb\x87\xe6\xe2\xfa\xbe\xc9\xfe\xc0\x85\xc7\xbb\x55\xd0\x7a\x81\x1c\x0b\xf0\x00\xcc\x76\xc1\xdc\xd4\x8a\xef\x37\x7c\x7f\x19\xe2\x53\xcd\xd9\xa7\x68\xe4\x47\x5c\xf6\x7f\x02\x00\x00\xff\xff\xc9\x56\xbd\x7b\xe7\x15\x00\x00")
+var _templatesScRbacYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x59\x4b\x73\xdb\x36\x10\xbe\xfb\x57\xec\xd0\x97\xb6\x23\x4a\x49\x2e\xed\xa8\x27\x59\x4e\x53\x4d\x13\xd9\x63\x39\xcd\x64\x32\x39\x40\xe4\x8a\x42\x4c\x01\x2c\x00\x5a\x56\x3d\xf9\xef\x5d\x3c\x48\x51\x0f\xab\x96\x2d\xc7\xd6\xd8\x23\x12\x00\x17\xdf\xee\x7e\xfb\x00\x75\x7c\xfc\xd8\xcf\xd1\x31\xf4\x65\xb1\x50\x3c\x9b\x1a\x78\xf3\xea\xf5\xaf\xf0\x4e\xca\x2c\x47\x18\x88\xa4\x7d\x64\xa7\xdf\xf3\x04\x85\xc6\x14\x4a\x91\xa2\x02\x33\x45\xe8\x15\x2c\xa1\xaf\x30\xd3\x82\xbf\x51\x69\x2e\x05\xbc\x69\xbf\x82\x9f\xec\x82\x28\x4c\x45\x3f\xff\x4e\x12\x16\xb2\x84\x19\x5b\x80\x90\x06\x4a\x8d\x24\x82\x6b\x98\x70\xda\x04\x6f\x12\x2c\x0c\x70\x01\x89\x9c\x15\x39\x67\x22\x41\x98\x73\x33\x75\xdb\x04\x21\x04\x03\x3e\x07\x11\x72\x6c\x18\xad\x66\xb4\xbe\xa0\xbb\x49\x73\x1d\x30\xe3\x00\xdb\xcf\xd4\x98\x42\x77\x3b\x9d\xf9\x7c\xde\x66\x0e\x6d\x5b\xaa\xac\x93\xfb\x95\xba\xf3\x7e\xd0\x7f\x3b\x1c\xbd\x8d\x09\xb1\x7b\xe6\xa3\xc8\x51\x6b\x50\xf8\x4f\xc9\x15\xe9\x3a\x5e\x00\x2b\x08\x50\xc2\xc6\x04\x33\x67\x73\x90\x0a\x58\xa6\x90\xe6\x8c\xb4\x80\xe7\x8a\x1b\x2e\xb2\x16\x68\x39\x31\x73\xa6\x90\xa4\xa4\x5c\x1b\xc5\xc7\xa5\x59\xb1\x56\x05\x8f\x94\x6e\x2e\x20\x7b\x31\x01\x51\x6f\x04\x83\x51\x04\x27\xbd\xd1\x60\xd4\x22\x19\x9f\x06\x97\x7f\x9e\x7d\xbc\x84\x4f\xbd\x8b\x8b\xde\xf0\x72\xf0\x76\x04\x67\x17\xd0\x3f\x1b\x9e\x0e\x2e\x07\x67\x43\xba\xfb\x03\x7a\xc3\xcf\xf0\xd7\x60\x78\xda\x02\x24\x5b\xd1\x36\x78\x53\x28\x8b\x9f\x40\x72\x6b\x47\x4c\xad\xd1\x46\x88\x2b\x00\x26\xd2\x03\xd2\x05\x26\x7c\xc2\x13\xd2\x4b\x64\x25\xcb\x10\x32\x79\x8d\x4a\x90\x3a\x50\xa0\x9a\x71\x6d\xbd\xa9\x09\x5e\x4a\x52\x72\x3e\xe3\x86\x19\x37\xb2\xa1\x94\xa7\xc8\x48\x96\x2a\xc1\x2e\x24\xcc\xb0\x5c\x66\x1d\x83\x04\x82\x19\xb2\xb3\x1a\xb3\xa4\xbd\x60\xb3\xdc\xae\x7b\x3c\x59\x59\xc1\x03\xd7\xba\x70\xfd\xfa\xe8\x8a\x8b\xb4\x4b\x50\xb4\x39\xe2\xb4\xa7\xee\xda\x3d\xa0\x77\x3e\x20\xd5\x15\x69\x04\xf6\x19\xc2\x77\x79\x76\x7a\xd6\x05\x3e\xf1\xcc\xa3\xbf\x6f\xa5\x36\xce\x1c\x82\xcd\x50\x13\x3f\xc8\xc7\x7c\x82\xc9\x22\x21\x6f\xb3\x34\x58\xa0\x05\x33\xb2\x8b\xf5\x37\x83\x0c\x05\x2a\x32\x99\x92\xb9\xf5\xb4\x35\x81\xbd\xac\x6d\x4a\xc8\xb4\xdf\xf3\x28\x86\x26\xcc\xdb\x5b\x68\x13\xa2\x70\xaf\xdb\x17\x27\xbd\x3e\x7c\xff\x7e\x04\xe0\xd1\xf7\x73\xc2\x82\xea\xc2\xca\x05\x98\xa1\x61\x29\x59\xb1\x7b\x64\x59\x6c\xd1\x75\x21\xb2\x22\x86\x74\x79\xae\x70\xc2\x6f\xe8\x61\xbb\x13\xd9\x3f\x98\xbb\x7d\xf5\x9b\x6e\x73\xd9\xad\x21\x54\xeb\x47\xe5\xc4\xaf\x8f\x48\xda\xb1\x57\x5e\x95\x04\x3a\x25\x39\xc2\x53\x70\x45\x91\x8a\x18\x2c\xcf\x17\x76\xc6\x3d\x56\x9b\x28\xde\x62\xa2\x38\x91\xc2\xd0\xf3\x39\x2a\x5a\x6c\x85\x6b\x0b\xdd\x99\xe0\x9d\x92\x25\x05\x21\x7c\x89\xa2\xaf\x4e\x1d\xa2\xa8\x23\x8a\x1b\xab\xc5\xea\x30\x4b\xc0\xc7\x34\x63\x3f\x5f\xa2\x0c\x4d\xd4\x82\x28\x27\xd7\xda\xef\x39\x33\xc9\x94\xd6\xdd\xde\xc6\xd6\x8d\x04\xb5\x7d\x42\xd6\x23\xc2\x8e\x30\x51\x68\xce\x25\x85\xea\x02\xda\x1f\xd8\xcd\x40\x68\x63\xd3\x88\x3e\x47\x35\xac\xbd\xeb\x0c\xee\xdd\x36\xc7\xf1\x54\xca\xab\xa5\x0e\x50\xe4\x65\xc6\x85\xa6\x68\x12\x36\xdc\xd3\xda\xa9\xef\x88\xc3\x57\x88\x14\x13\x50\xd8\x1d\x38\xea\x96\x93\xe3\xf0\xac\x48\x23\x3b\x4c\x78\x56\x2a\x1f\x28\x9b\x26\xa8\x77\x53\x98\xd9\x24\xe0\xd6\x05\xcf\x6d\x33\xcf\xac\xb4\x31\x27\xb2\x20\x7f\x55\xbc\x35\xc9\x35\xcb\x79\xba\x63\xc9\x7e\x46\x45\x91\x5a\x1b\xb9\xe0\x89\x03\x91\x03\xcb\x62\x96\x24\xb2\x14\x86\x42\xc0\x50\xf0\xd0\xbf\x9c\x0b\x1f\x07\x0f\x66\x7a\x70\xde\x93\x12\xde\x22\xbc\xc0\x89\x17\x5c\xf9\xa2\x0b\x2e\x23\xb1\xd2\x4c\xa5\xe2\xff\x36\xbd\xe0\xd6\x6d\x8b\xc9\xc3\xc2\xd2\xe5\xf8\x1b\x26\x66\x2d\x4c\x48\x7a\xd4\x00\x30\xf2\x82\x7b\xde\xf2\xeb\x18\xc8\xc8\x3e\xbf\xad\x2e\x0b\x1b\xc0\x32\x62\x1b\x98\xab\x30\x88\xc8\xc7\xcb\x5c\xe5\x5c\xea\xd2\x17\x19\x24\x4e\x31\xc7\x8c\x19\x22\xbf\xcb\x08\x94\xf7\xc2\x88\x9f\xa7\xbb\x84\xfb\xca\x60\x64\xc8\x81\x89\x54\x8d\xdc\xf7\x62\x08\x11\xaf\xea\xf3\x63\xf8\xa1\x17\x34\x38\xeb\xae\x6e\xfd\x72\x3d\x3e\xe6\x39\x37\x0b\xeb\x65\x85\x2c\x75\x1e\x46\x61\x28\xf9\x3b\x9d\xe1\xd2\x56\x0b\x2a\x04\x72\x6e\x83\xde\x7b\xdc\x2d\x5c\xe9\x20\x7c\xd2\x99\xb1\x82\x86\x99\x81\x29\xf3\xc2\x6d\x1f\x85\x9a\xae\x18\xf5\x0b\xf1\x2f\x94\x5e\xa8\xed\xc1\xc0\x1b\x9f\x65\x2d\x26\xea\xa8\xa8\xa5\xca\xdc\x8e\x7b\x72\xe7\xa9\x48\xb3\x34\x41\xac\x1c\xfa\xad\xdc\x59\xb1\xf8\x55\x39\xc6\xd8\x3b\xff\x71\xb4\x5a\xe3\x13\xde\x18\x6a\xb1\x2c\x92\xff\x81\xf8\x12\x38\xe6\x3a\xaf\x7e\xdd\x0c\xc4\x1f\x98\xa0\xc6\xb2\xee\xc0\x96\x6d\x42\x3c\x0b\x33\x2e\xc7\xf8\x4e\x44\xc3\xdc\xb2\x87\x0a\x8d\x6d\x62\x1d\xc1\x42\xed\x09\xbe\xda\x2e\x41\x50\x37\x6e\x39\x05\x7e\x20\x30\x2f\x14\xd1\x70\x38\xa0\x27\xd7\x65\x3d\x57\x7f\xb6\xa9\xc1\xf6\xbc\xb4\x47\x13\x85\xd7\x44\x86\xad\xb5\x9e\xba\x22\xca\xdb\x51\x2b\x2a\x5c\x95\x6f\x45\x65\x91\xda\x81\xaf\xae\x7f\xf1\x0d\x71\x2a\xdd\x91\x2c\x53\xcc\x56\xf7\x5c\x8e\x59\x1e\x7c\xd0\xf2\x5d\xbf\xb5\x6d\xc1\x14\xd1\xad\xcc\x99\x6d\x08\x6c\xaf\x65\x4f\x49\x13\x54\x48\x3d\x16\x75\x4a\x4a\xce\x2a\x03\x8f\x7d\x38\xea\xfb\x41\x0f\xc2\xee\xec\x53\xd6\xda\x14\xba\xa8\x54\x82\x4a\x17\xba\x2a\xaa\x49\x9b\x70\x83\x7a\x87\x69\x3e\xc3\xfe\x75\x97\x64\xcd\x56\x11\x54\x02\x89\x5a\xe7\x15\x98\x45\x81\x5b\x94\xdf\x4a\x86\x6d\xa0\x12\xcf\xb4\x6a\x7d\xce\xb4\xbe\x37\xbe\xd6\x9d\x0e\x6f\xed\x30\xcd\x03\xa1\xd1\xd9\x4e\xbc\x48\x60\x63\x25\xaf\xe8\x86\x44\x87\x01\x5e\x1d\x06\x96\x43\x15\x4b\xf7\x70\xfc\x41\xc1\x75\x08\x91\x29\x2d\xa0\xad\xfe\xbe\x6b\xda\xd9\x7c\x39\xb9\xae\xdf\x8e\x99\x3a\x5a\x37\x6d\x50\x3d\xb5\xc5\x14\x75\xba\x38\x86\x8c\x5f\x63\x68\xf6\x36\x52\x70\x10\x08\xd5\x19\x61\x19\x21\x36\xa3\xeb\xfa\xa4\xc9\x85\x3b\x39\xd8\x9c\xdf\x7e\xee\x2e\xf1\xbe\x79\xf8\xb9\xce\x0f\xf7\xc4\x77\x80\x92\xbf\xac\xd7\xa1\x5c\x3f\xb0\xf4\xfb\x66\xd1\xf2\x44\x83\x0f\xf7\x8e\xe7\x4f\x83\x0f\x4c\xd8\x43\x66\x21\xb9\x70\xef\xfb\x1a\x4d\x93\x3b\x6e\xe7\xae\x93\x01\xca\x08\x89\xeb\x06\xab\x1a\x65\xdf\x17\xd2\xa3\xd2\xe9\x0a\xd2\xbd\xed\xa2\x56\x73\x29\x8c\x6a\x15\x13\x8e\x60\xae\x6f\x20\x64\x8d\x97\x3a\x6c\xa5\x9b\xa8\xde\x4f\xb5\x5c\xd9\x5b\x05\xf1\x80\xf6\xf3\x91\x34\xf4\x1a\xc7\xb9\x4c\x48\x64\x16\xdf\xd3\xeb\xbb\x3a\xcf\x7d\x1a\x87\xca\x7a\x3b\x7a\x87\x8d\xd4\xe7\x67\x37\xc5\xf9\xf1\x75\x91\xd5\x02\x47\x96\x46\xde\xac\xea\xe5\x16\x8d\x37\xc1\x34\xf3\x72\x9d\x92\x0e\x7d\x52\xb8\xcb\x61\x3b\x90\x6e\xf8\xe6\x47\x1d\x0a\x9e\x96\x5d\x2f\x26\xa7\x84\x17\x7d\xed\x90\x49\x07\xa7\x7d\x77\xd0\xfc\x40\x07\x4d\xb2\xb5\xcb\x38\xcd\x7a\x04\x75\x3d\xa2\x23\x37\xfa\xa0\x1f\x9c\x56\xbf\x0f\x84\x4a\x5a\xe5\x88\x70\x1b\x73\x31\x91\xee\xe7\x8f\x4a\x32\x2d\xb7\x45\xaa\x46\xe6\x33\x8b\xfb\xd9\x41\xe4\x8b\x70\x4c\xa6\x91\x90\xdb\xdc\x89\x57\x8a\xbd\x0b\xda\xae\xdc\x71\x47\x45\x68\x20\x8e\xeb\x23\xf7\x7d\xec\xb8\x5f\x56\xa8\x45\x1f\x30\x2d\x6c\xc8\xdc\xc8\x0b\x4d\xed\x9e\x29\x03\x3c\x85\xdd\x0f\x17\xf2\xfb\x82\x7b\x51\x51\x1c\xde\x2c\xff\x07\x8e\x52\xe7\xf4\xaa\x1c\x00\x00")
 
 func templatesScRbacYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -388,12 +670,52 @@ func templatesScRbacYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/rbac.yaml.tmpl", size: 5607, mode: os.FileMode(416), modTime: time.Unix(1513783806, 0)}
+	info := bindataFileInfo{name: "templates/sc/rbac.yaml.tmpl", size: 7338, mode: os.FileMode(420), modTime: time.Unix(1792032966, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScServiceAccountsYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x92\x4f\x4f\xdc\x30\x10\xc5\xef\xf9\x14\x4f\x9b\x4b\x2b\xed\x1f\xe0\x52\x29\x3d\xa5\x40\xdb\xa8\x68\x57\x22\x4b\x11\xc7\x59\x67\x36\x19\xd5\xb1\x5d\xdb\x21\xec\xb7\xaf\x92\x0d\x14\x4a\x0f\x95\xa8\x6f\x99\x99\x3c\xff\xe6\xf9\xa5\x6f\x3e\x49\x8a\x73\xeb\x0e\x5e\xea\x26\xe2\xec\xe4\xf4\x03\xbe\x58\x5b\x6b\x46\x61\xd4\x32\x19\xda\x57\xa2\xd8\x04\xae\xd0\x99\x8a\x3d\x62\xc3\xc8\x1d\xa9\x86\x1f\x3b\x73\x7c\x67\x1f\xc4\x1a\x9c\x2d\x4f\xf0\x6e\x18\x98\x4d\xad\xd9\xfb\x8f\x49\x8a\x83\xed\xd0\xd2\x01\xc6\x46\x74\x81\x11\x1b\x09\xd8\x8b\x66\xf0\x83\x62\x17\x21\x06\xca\xb6\x4e\x0b\x19\xc5\xe8\x25\x36\xe3\x35\x93\xc8\x32\x49\x71\x37\x49\xd8\x5d\x24\x31\x20\x28\xeb\x0e\xb0\xfb\xe7\x73\xa0\x38\x02\x0f\xa7\x89\xd1\x85\x6c\xb5\xea\xfb\x7e\x49\x23\xed\xd2\xfa\x7a\xa5\x8f\x93\x61\x75\x55\x9c\x5f\xae\xcb\xcb\xc5\xd9\xf2\x64\xfc\xe7\xc6\x68\x0e\x01\x9e\x7f\x76\xe2\xb9\xc2\xee\x00\x72\x4e\x8b\xa2\x9d\x66\x68\xea\x61\x3d\xa8\xf6\xcc\x15\xa2\x1d\x80\x7b\x2f\x51\x4c\x3d\x47\xb0\xfb\xd8\x93\xe7\x24\x45\x25\x21\x7a\xd9\x75\xf1\x85\x5b\x8f\x78\x12\x5e\x0c\x58\x03\x32\x98\xe5\x25\x8a\x72\x86\x4f\x79\x59\x94\xf3\x24\xc5\x6d\xb1\xfd\xba\xb9\xd9\xe2\x36\xbf\xbe\xce\xd7\xdb\xe2\xb2\xc4\xe6\x1a\xe7\x9b\xf5\x45\xb1\x2d\x36\xeb\x12\x9b\xcf\xc8\xd7\x77\xf8\x56\xac\x2f\xe6\x60\x89\x0d\x7b\xf0\x83\xf3\x03\xbf\xf5\x90\xc1\x47\xae\x06\xd3\x4a\xe6\x17\x00\x7b\x7b\x04\x0a\x8e\x95\xec\x45\x41\x93\xa9\x3b\xaa\x19\xb5\xbd\x67\x6f\xc4\xd4\x70\xec\x5b\x09\xc3\x6b\x06\x90\xa9\x92\x14\x5a\x5a\x89\x14\xc7\xca\xab\xa5\x8e\x11\x29\xd9\xdf\x8b\x62\x90\x52\xb6\x33\x31\x8c\x37\x85\xa9\xa8\x28\x92\xb6\x35\xc8\xc9\x58\x63\x3f\x08\x43\x59\x13\xbd\xd5\x9a\x7d\x92\xa2\x25\x43\x35\xfb\x51\xed\xed\x91\x26\x27\x53\x22\x33\xdc\x9f\x26\x3f\xc4\x54\x19\xae\x24\xc4\x44\x22\xb7\x21\x4b\x80\x14\xdb\x86\x51\xe6\x4f\x9e\x90\x93\x23\x5c\x02\x2c\xf0\x87\xc2\x90\xa8\xa3\xca\xb4\x69\x7e\x5c\x74\x6c\xb4\x1c\xa9\xa2\x48\xd9\xf8\x05\x18\x6a\x39\xc3\xec\x49\x70\xf6\xac\x1e\x1c\x29\xce\x1e\xad\x59\x4c\xd6\xfc\x8d\xe7\xb7\x3d\x8b\xc9\x9c\xff\x05\xf6\x5a\xf9\x1f\x08\x7f\x05\x00\x00\xff\xff\x28\xcc\xbc\x42\x69\x04\x00\x00")
+var _templatesScSecretSyncRbacYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x55\x51\x6f\xd3\x30\x10\x7e\xef\xaf\x38\x65\x7b\x00\xa9\x49\x61\x4f\x53\x78\xca\xba\x01\x11\xa8\x9b\x9a\x01\x42\x88\x07\x27\xb9\xa4\x66\xa9\x1d\x6c\x67\x5d\x99\xf8\xef\x9c\x1d\x97\x65\x5b\xd9\x60\x0c\x89\xbe\xc4\x89\xcf\xdf\x7d\xf7\xdd\x7d\xee\xce\xce\xdf\xfe\x46\x3b\x30\x95\xed\x5a\xf1\x7a\x61\x60\xef\xd9\xf3\x7d\x78\x25\x65\xdd\x20\xa4\xa2\x88\x46\x76\xfb\x2d\x2f\x50\x68\x2c\xa1\x13\x25\x2a\x30\x0b\x84\xa4\x65\x05\x3d\xfc\xce\x18\xde\xa3\xd2\x5c\x0a\xd8\x8b\x9e\xc1\x13\x1b\x10\xf8\xad\xe0\xe9\x0b\x42\x58\xcb\x0e\x96\x6c\x0d\x42\x1a\xe8\x34\x12\x04\xd7\x50\x71\x4a\x82\x17\x05\xb6\x06\xb8\x80\x42\x2e\xdb\x86\x33\x51\x20\xac\xb8\x59\xb8\x34\x1e\x84\x68\xc0\x47\x0f\x21\x73\xc3\x28\x9a\x51\x7c\x4b\x6f\xd5\x30\x0e\x98\x71\x84\xed\x6f\x61\x4c\xab\xe3\xc9\x64\xb5\x5a\x45\xcc\xb1\x8d\xa4\xaa\x27\x4d\x1f\xa9\x27\x6f\xd3\xe9\xd1\x2c\x3b\x0a\x89\xb1\x3b\xf3\x4e\x34\xa8\x35\x28\xfc\xda\x71\x45\xb5\xe6\x6b\x60\x2d\x11\x2a\x58\x4e\x34\x1b\xb6\x02\xa9\x80\xd5\x0a\x69\xcf\x48\x4b\x78\xa5\xb8\xe1\xa2\x1e\x83\x96\x95\x59\x31\x85\x84\x52\x72\x6d\x14\xcf\x3b\x73\x4d\xad\x0d\x3d\x2a\x7a\x18\x40\x7a\x31\x01\x41\x92\x41\x9a\x05\x70\x90\x64\x69\x36\x26\x8c\x0f\xe9\xe9\xeb\xe3\x77\xa7\xf0\x21\x99\xcf\x93\xd9\x69\x7a\x94\xc1\xf1\x1c\xa6\xc7\xb3\xc3\xf4\x34\x3d\x9e\xd1\xdb\x4b\x48\x66\x1f\xe1\x4d\x3a\x3b\x1c\x03\x92\x56\x94\x06\x2f\x5a\x65\xf9\x13\x49\x6e\x75\xc4\xd2\x8a\x96\x21\x5e\x23\x50\xc9\x9e\x90\x6e\xb1\xe0\x15\x2f\xa8\x2e\x51\x77\xac\x46\xa8\xe5\x39\x2a\x41\xe5\x40\x8b\x6a\xc9\xb5\xed\xa6\x26\x7a\x25\xa1\x34\x7c\xc9\x0d\x33\xee\xcb\xad\xa2\xfa\x11\x99\x4b\x52\x6f\xd3\x0d\x8d\x85\x42\x03\x7a\x2d\x0a\xd7\x55\x29\x50\x98\x18\xb8\x81\x15\x33\xd4\x08\xed\xa2\x32\x54\xe7\x04\x71\xc0\x45\x49\x69\x37\xb9\x14\xb2\x52\x8f\xe9\x61\xd5\x45\xf7\x95\x5e\xb4\x91\xaa\x3f\xc6\x95\x87\xd7\xb6\x05\x16\x47\xb0\x25\x6a\x6a\xb0\xcb\x4f\x08\x61\xd8\x07\x84\x36\x7f\x38\xdc\x15\xcd\x7a\xdc\xcf\x16\x73\x84\x2d\x02\xd2\x64\x8c\x49\x86\x1c\x1b\xea\xc8\x66\xf0\x08\xa6\x60\x86\x35\xb2\xbe\x82\x77\xe2\x75\x24\x91\xa6\x8d\xc6\xce\x40\x45\xd4\x23\x38\x61\xca\x6c\x2a\x9f\x1f\x24\x53\xd0\x67\xbc\x6d\x3d\x58\xcf\x87\x3e\x84\x2a\x67\xbd\x9d\xfe\xde\xb0\x97\x97\xc0\x2b\x88\x32\x57\x66\x66\x55\xfe\xfe\x7d\xc4\x5a\xee\x4d\x18\x03\x05\x44\xc9\x49\xea\xdf\x75\xe4\x68\x51\xcc\x19\x11\x8e\x61\xda\x74\xda\xa0\xb2\x0a\x8c\x96\x68\x58\x49\x95\xc6\x23\x70\x95\xc6\x10\xd8\xc3\x33\x5a\x9e\x28\xac\xf8\x05\x1d\xd3\x7d\xa3\xbc\x20\xd1\xd9\xbe\x8e\xb8\x8c\x07\x22\x6f\x4e\x64\x5d\xd5\x9f\x08\x46\xaa\xa3\x79\x88\x47\x21\x79\x88\xbf\x52\xb2\x23\x2b\xc2\xa7\x60\x2b\x52\xf0\x99\x72\x53\x77\x65\xa7\xa8\x4b\x83\xb0\xdc\x4f\x86\x0b\xa0\xe9\xcc\xdd\x66\x8d\x26\x18\x43\xd0\x90\x8f\xec\xd3\x4d\x14\x45\x84\x61\xf8\x30\x09\xfc\xfc\xfd\x3b\x25\x28\xc7\x1c\x2b\x0b\xbb\xd1\x22\x06\x37\x0c\xac\x33\x0b\xa9\xf8\x37\xe7\x2c\x0f\x46\x51\xb7\x9b\xf4\xb8\x84\x74\x97\x7f\xc1\xc2\xb8\xee\xf4\xb9\xbc\x13\x93\xa2\x90\x9d\x30\x3f\xd3\x0d\x60\xfc\x37\xe7\x83\x01\x8f\xde\x17\x16\xf4\xf2\x32\x04\x45\x77\x09\x0e\xc7\x72\x76\xe5\x3d\x52\x7e\x4b\x87\x76\xef\x68\xd1\x1d\xe3\xb9\xfb\xe7\x22\xec\xde\x54\xe1\x5a\x41\xb6\x1e\x9b\x19\xfa\x8b\x40\xdb\x74\x00\x5b\x71\x27\xde\xff\xae\x69\x43\x3a\x57\x5a\x6c\x9f\xfd\x6d\x63\xee\xee\xb1\xdf\x18\x6f\x5a\x50\x28\x33\x68\x57\x5d\x5b\xfa\x55\xbb\x99\xfd\xfb\x32\xe1\x39\x5d\xc0\x37\x12\x5d\x01\xb6\xbf\xb6\xd0\x7d\x0d\xba\xdb\x3c\xff\x7b\x9f\x1e\xe6\xcc\x5b\x96\x7c\x8c\x32\x1f\xc7\x94\xbb\x5b\x5c\x89\xf4\x07\x4a\x92\xd1\xae\x5f\xfd\x00\xf8\x37\x28\xe3\x32\x0a\x00\x00")
+
+func templatesScSecretSyncRbacYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScSecretSyncRbacYamlTmpl,
+		"templates/sc/secret-sync-rbac.yaml.tmpl",
+	)
+}
+
+func templatesScSecretSyncRbacYamlTmpl() (*asset, error) {
+	bytes, err := templatesScSecretSyncRbacYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/secret-sync-rbac.yaml.tmpl", size: 2610, mode: os.FileMode(420), modTime: time.Unix(1792030641, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScSecretSyncYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xbd\x56\xdf\x6f\xdb\x36\x10\x7e\xcf\x5f\x71\x50\x1f\xba\x01\x91\x9c\x74\xcb\x30\x78\xd8\x83\xea\x76\xad\xd0\xc4\x31\xe2\xa4\x45\x1f\x69\xea\x2c\x73\xa1\x48\x95\xa4\xe2\x18\x5d\xff\xf7\x1d\x49\xc9\x96\x1d\xb7\xd8\xd0\x61\x7e\x49\xc8\xfb\xf8\xdd\x77\x3f\x78\xd4\xb3\x67\xdf\xfb\x3b\x79\x06\x13\xdd\x6c\x8c\xa8\x56\x0e\x5e\x9c\x9d\xff\x0a\x6f\xb4\xae\x24\x42\xa1\x78\x76\xe2\xcd\x97\x82\xa3\xb2\x58\x42\xab\x4a\x34\xe0\x56\x08\x79\xc3\x38\xfd\xe9\x2c\xa7\xf0\x1e\x8d\x15\x5a\xc1\x8b\xec\x0c\x7e\xf0\x80\xa4\x33\x25\x3f\xfe\x46\x0c\x1b\xdd\x42\xcd\x36\xa0\xb4\x83\xd6\x22\x51\x08\x0b\x4b\x41\x4e\xf0\x91\x63\xe3\x40\x28\xe0\xba\x6e\xa4\x60\x8a\x23\xac\x85\x5b\x05\x37\x1d\x09\xc9\x80\x8f\x1d\x85\x5e\x38\x46\x68\x46\xf8\x86\x56\xcb\x21\x0e\x98\x0b\x82\xfd\x6f\xe5\x5c\x63\xc7\xa3\xd1\x7a\xbd\xce\x58\x50\x9b\x69\x53\x8d\x64\x44\xda\xd1\x65\x31\x79\x3d\x9d\xbf\x4e\x49\x71\x38\x73\xa7\x24\x5a\x0b\x06\x3f\xb5\xc2\x50\xac\x8b\x0d\xb0\x86\x04\x71\xb6\x20\x99\x92\xad\x41\x1b\x60\x95\x41\xb2\x39\xed\x05\xaf\x8d\x70\x42\x55\xa7\x60\xf5\xd2\xad\x99\x41\x62\x29\x85\x75\x46\x2c\x5a\xb7\x97\xad\x5e\x1e\x05\x3d\x04\x50\xbe\x98\x82\x24\x9f\x43\x31\x4f\xe0\x65\x3e\x2f\xe6\xa7\xc4\xf1\xa1\xb8\x7d\x7b\x7d\x77\x0b\x1f\xf2\x9b\x9b\x7c\x7a\x5b\xbc\x9e\xc3\xf5\x0d\x4c\xae\xa7\xaf\x8a\xdb\xe2\x7a\x4a\xab\x3f\x20\x9f\x7e\x84\x77\xc5\xf4\xd5\x29\x20\xe5\x8a\xdc\xe0\x63\x63\xbc\x7e\x12\x29\x7c\x1e\xb1\xf4\x49\x9b\x23\xee\x09\x58\xea\x28\xc8\x36\xc8\xc5\x52\x70\x8a\x4b\x55\x2d\xab\x10\x2a\xfd\x80\x46\x51\x38\xd0\xa0\xa9\x85\xf5\xd5\xb4\x24\xaf\x24\x16\x29\x6a\xe1\x98\x0b\x3b\x4f\x82\x8a\x2d\x32\xa1\xe2\x69\x85\xca\x81\xdd\x28\xee\x69\x3c\x64\x8e\xe6\x81\x50\x2f\x85\x2a\xfd\x96\x45\x6e\xd0\x59\x9f\xbe\xb7\xcc\xae\xc4\x44\x9b\x06\xde\xb3\x56\x3a\x2f\xfb\xcd\x64\x16\x14\x7b\x0c\x5c\x31\x45\xaa\xcc\x69\x50\xcc\x65\x6b\x1d\x35\x18\x71\x32\x07\x35\x2d\x42\x23\xdd\x23\x36\x20\xb5\xaa\x52\x29\x1e\x28\x9d\x74\xb0\x24\x05\x82\x49\x4b\xe5\x21\xae\x46\xfa\x4e\x89\x8c\x36\x83\x6b\x25\x37\x54\x5f\x1f\x00\xa1\x43\x8f\xa5\x29\x2a\x5f\xdf\x34\x4a\x4b\xbd\xf8\x53\x10\xa4\xd1\x68\x6a\x07\xa0\xa2\x46\xaa\x81\x3d\x35\x0b\xc6\xb3\x0d\xab\x65\x08\xfd\xfb\xef\xdf\xe7\xcf\x20\x96\x90\x45\x9d\x73\xf2\x00\x5f\xbe\x9c\xb0\x46\x74\x77\x6a\x0c\x0f\xe7\x27\xf7\x94\xc2\x71\x9f\xcf\x9c\x73\xdd\x2a\x77\x52\xa3\x63\x25\x73\x6c\x7c\x02\xa0\x58\x8d\xe3\xa1\xcc\x6e\xcf\x52\xeb\x93\x81\x7c\x64\xd3\x7e\xe9\xf9\x81\x4a\xbf\x40\x69\xfd\x59\xf0\x9d\xee\x0f\x07\xf6\x94\x13\xa5\xd4\xd5\x30\x27\xa4\x31\x3d\x10\x49\xe5\xda\x97\x13\x59\x99\xa2\xd2\xc4\x5e\x89\xd4\x82\xd5\x59\x75\x8f\x99\xd0\xa3\x8a\x37\x69\xef\x84\xc5\x43\x63\x48\xbc\xb4\x6f\xd3\x26\xc1\x3d\x15\xce\xbb\x48\xd3\xf4\x78\x72\x26\x5a\x2d\x45\x75\xc5\x9a\x6f\xe7\x25\xe5\x01\xf7\xdf\xa6\xa7\xf7\x16\xb9\x43\x73\x8c\xe1\xaf\x70\x96\x9a\xe5\x1e\xbd\xbe\x83\x40\x5f\xc6\xfd\x10\x9e\xc7\xd5\xba\xc4\x27\xa0\x2b\xda\xdc\x22\xb6\x7a\x49\x95\x4f\x88\xa1\xbb\x8b\x43\xf4\x36\x02\x1b\x43\x00\x48\x23\xe1\x61\x0e\xbb\x6a\xe2\xa7\x63\x82\x92\x07\x7f\x23\x93\x9e\x22\xac\x62\x1a\x28\x11\x65\xe9\xa7\xcc\x13\x9d\xe1\x12\xe7\xd1\xb8\xd5\x0b\xe1\x12\x1d\xc7\xde\x90\x65\x00\x6c\x98\x5b\x1d\x07\xce\xc8\xb2\x93\x2f\x2d\xf6\xba\xa8\x99\x22\xb4\x1b\x15\xbd\xc4\xc6\xe8\x3f\x91\x3f\x6d\xac\x59\xdc\x3f\xd6\x4e\xb1\x7f\x5e\x61\x23\xf5\xa6\xa6\x09\xb2\xd7\x5f\x9e\x25\x9f\x15\xdd\xda\x66\x3b\x98\x3f\xfe\x3f\x5e\x41\x3f\xb3\x3d\xd4\x60\x78\x95\xa8\x08\xe7\xb4\xb2\x28\x29\x2a\xdd\x85\x5f\x33\xc7\x57\x97\x03\xd6\x7f\xc0\x0b\xe0\x90\x5e\x0c\xe6\xb0\xe3\x18\x84\xe4\x7f\x72\x8f\xee\x5f\xcc\x8a\xc2\xd2\x18\xe8\xcb\x75\x64\x32\xf8\x9f\x15\x25\x72\x66\x32\xe1\xb1\x7e\x46\x08\xd5\x55\xcf\x99\x16\x93\x2d\x8e\x1e\x06\xc7\x0c\xbd\x13\xfe\xf3\x20\xbc\x60\xf1\xa0\x2f\xf7\xa3\x40\x1b\xf6\x38\x93\x32\xbc\x2e\x7e\xf1\xae\x5d\xd0\x83\x86\x8e\x6c\x54\xbe\x6c\xcb\xe4\x0f\x6c\x76\xfe\xe2\x8d\x1d\xc3\xf3\xcf\xc9\x4a\xcb\x32\x8f\x4f\xbe\x57\x79\x47\x4f\x89\x9c\x79\x74\x70\x6d\x93\x31\x78\x4d\x5f\x9e\x0f\xdb\x27\xc4\xd0\x15\x26\xfc\xbf\x37\xbb\xa6\x4f\x3a\xa2\xcf\xcd\xcc\x08\x4d\x9f\x0f\x9b\x89\x64\xd6\xee\x72\xd4\x0c\xb7\xe3\xe9\xbe\x6d\x66\x06\x97\xe2\x91\xa0\x07\xa9\xef\xed\xf3\x76\x19\xed\x47\x2e\x7a\x36\xa5\x39\x92\x1b\xbe\x12\x8e\x92\xdb\x1a\x1c\xb8\x64\x74\x4c\x91\xcb\xe0\xa9\xef\x04\x48\x18\xa1\xf3\xce\x94\x7c\x85\xe0\x20\x0f\x3e\x7a\xde\x06\xf9\x5a\x39\x7c\x74\xbb\x32\x9b\x56\xe5\x76\xaa\xd5\x8d\xd6\x2e\xa6\x71\xdf\x74\x47\x41\x8d\xe1\x97\x8b\x8b\x9f\x7e\xee\x0c\x54\x17\xff\x9d\x47\xb7\xae\x67\x49\x8f\x5e\xb0\xf8\x13\x35\x8d\x80\x98\xab\xdd\x8d\x2f\xfc\xe6\x4e\x5c\x87\x9a\xb5\x52\xce\x34\x15\x99\x22\x2e\x96\x53\xed\x28\xb1\xd6\x5f\xfa\x6d\x7f\x9b\x6a\xd0\xa0\x29\x7d\x23\xc4\x1e\xf9\x7d\x84\x8e\x8f\x06\xde\x47\x83\x69\xbf\x0b\x07\xad\x6e\x4d\x98\xd1\x00\xbb\xcd\x4f\x2d\x5a\xb7\xb7\x47\x21\x36\xed\x18\x2e\xce\xea\xbd\xcd\x1a\x6b\x6d\x48\xda\x8b\xb3\x2b\x31\x30\x84\x4f\xb0\x63\xe7\xcf\xcf\xbe\x42\x70\x31\x24\x78\xd0\xb2\xad\xf1\xca\xf7\xe4\x5e\x6c\x31\xa3\xdb\x17\x71\xcb\xe1\x81\xb3\x30\x93\x0f\x83\xde\x8b\x8a\x95\xfe\x9b\x6a\xaf\xa0\xd1\xd3\x93\xa2\x1d\xb8\xe0\xfd\x5b\x3d\x8c\xe8\xab\x4f\x35\x95\xb5\x6b\xb3\xbf\x01\x47\x15\xa9\xfe\xee\x0c\x00\x00")
+
+func templatesScSecretSyncYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScSecretSyncYamlTmpl,
+		"templates/sc/secret-sync.yaml.tmpl",
+	)
+}
+
+func templatesScSecretSyncYamlTmpl() (*asset, error) {
+	bytes, err := templatesScSecretSyncYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/secret-sync.yaml.tmpl", size: 3310, mode: os.FileMode(420), modTime: time.Unix(1792032658, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScServiceAccountsYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x53\xcd\x8e\xe3\x44\x10\xbe\xfb\x29\x3e\xc5\x1c\x40\x4a\x3c\xb3\x73\x41\x0a\x08\x29\xcc\x06\xb0\x18\x25\xd2\x38\xcb\x6a\x85\x38\x54\xda\x65\xbb\xb4\xed\x6e\xd3\x5d\x9e\x4c\x64\xe5\xdd\x91\x9d\x04\x26\x20\x71\x80\xbe\x75\x55\x75\x7f\x3f\x55\x95\xfe\xef\x93\xa4\x78\xf4\xdd\x31\x48\xdd\x28\x1e\xee\xdf\x7d\x8d\x1f\xbd\xaf\x2d\x23\x77\x26\x4b\xd2\x24\xc5\x93\x18\x76\x91\x4b\xf4\xae\xe4\x00\x6d\x18\xab\x8e\x4c\xc3\xd7\xcc\x1c\xbf\x70\x88\xe2\x1d\x1e\xb2\x7b\x7c\x39\x16\xcc\x2e\xa9\xd9\x57\xdf\x24\x29\x8e\xbe\x47\x4b\x47\x38\xaf\xe8\x23\x43\x1b\x89\xa8\xc4\x32\xf8\xd5\x70\xa7\x10\x07\xe3\xdb\xce\x0a\x39\xc3\x38\x88\x36\xd0\xbf\xfe\xcf\x92\x14\x9f\x2e\x5f\xf8\xbd\x92\x38\x10\x8c\xef\x8e\xf0\xd5\xdb\x3a\x90\x4e\x84\x01\xa0\x51\xed\xe2\xf2\xee\xee\x70\x38\x64\x34\xb1\xcd\x7c\xa8\xef\xec\xb9\x32\xde\x3d\xe5\x8f\xeb\x4d\xb1\x5e\x3c\x64\xf7\xd3\x9b\x0f\xce\x72\x8c\x08\xfc\x7b\x2f\x81\x4b\xec\x8f\xa0\xae\xb3\x62\x68\x6f\x19\x96\x0e\xf0\x01\x54\x07\xe6\x12\xea\x47\xc2\x87\x20\x2a\xae\x9e\x23\xfa\x4a\x0f\x14\x38\x49\x51\x4a\xd4\x20\xfb\x5e\x6f\xdc\xba\xd2\x93\x78\x53\xe0\x1d\xc8\x61\xb6\x2a\x90\x17\x33\x7c\xbf\x2a\xf2\x62\x9e\xa4\xf8\x98\xef\x7e\xda\x7e\xd8\xe1\xe3\xea\xf9\x79\xb5\xd9\xe5\xeb\x02\xdb\x67\x3c\x6e\x37\xef\xf3\x5d\xbe\xdd\x14\xd8\xfe\x80\xd5\xe6\x13\x7e\xce\x37\xef\xe7\x60\xd1\x86\x03\xf8\xb5\x0b\x23\x7f\x1f\x20\xa3\x8f\x5c\x8e\xa6\x15\xcc\x37\x04\x2a\x7f\x6e\x5f\xec\xd8\x48\x25\x06\x96\x5c\xdd\x53\xcd\xa8\xfd\x0b\x07\x27\xae\x46\xc7\xa1\x95\x38\x76\x33\x82\x5c\x99\xa4\xb0\xd2\x8a\x92\x4e\x91\x7f\x88\x3a\x8f\x48\xc1\xe1\x45\x0c\x83\x8c\xf1\xbd\xd3\x78\x6d\x4c\xbc\xc4\x0d\x29\x59\x5f\x83\x3a\x99\x62\x1c\xe6\x30\xde\x69\xf0\xd6\x72\x40\x4b\x8e\x6a\x0e\x49\x3a\x22\x82\xd5\x94\x28\x94\x94\xab\xde\x16\xac\x73\xec\x7b\x85\x36\x3e\x32\xba\xc0\x0b\x13\x98\x46\x83\xaf\x63\x92\xa4\x58\x2c\xbe\x1d\xe7\xc7\x3b\x76\xfa\xdd\xe2\x82\xba\xb8\xb0\x41\x65\xa9\x8e\x19\xd6\x64\x1a\x48\x84\x77\xf6\x88\xbd\xef\xdd\xd4\xc9\x91\x66\xf0\x96\x47\xce\x49\x0a\xd1\x88\x3f\xbf\x9a\xc4\x5d\x17\xe5\x3f\x9f\x84\x3a\xb9\x2c\xc8\x12\x2f\xef\x92\xcf\xe2\xca\x25\x9e\x24\x6a\x22\xca\x6d\x5c\x0e\x03\xa4\x9a\x96\x23\x7b\x3c\x6b\xbb\xf8\xb9\xba\xda\x79\x3a\xe1\xd7\xdf\x86\x01\xec\x4a\x9c\x4e\xc9\x30\x2c\x10\xc8\xd5\xfc\x2f\x0f\x12\x60\x81\xbf\x21\x8f\x8b\x71\x46\xbf\xad\x9f\x12\x2d\x2b\x95\xa4\xb4\x9c\x6e\x80\xa3\x96\x97\x98\x0d\x03\xb2\x0d\xb5\x8c\xd3\x69\xf6\x26\x13\x3b\x32\xbc\xc4\x30\xe0\x8b\x29\x3d\xdd\xaf\xdc\xae\x6a\x76\xfe\x33\xbb\x31\x38\x3e\xa3\x5e\x7d\x3b\xa2\xdd\x62\x4f\x35\x4b\x54\x64\x23\x4f\xc2\xde\x68\x64\x57\xe2\x74\x4a\xfe\x18\x00\x82\x13\xa4\x52\xe5\x04\x00\x00")
 
 func templatesScServiceAccountsYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -408,12 +730,32 @@ func templatesScServiceAccountsYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/service-accounts.yaml.tmpl", size: 1129, mode: os.FileMode(416), modTime: time.Unix(1507129340, 0)}
+	info := bindataFileInfo{name: "templates/sc/service-accounts.yaml.tmpl", size: 1253, mode: os.FileMode(420), modTime: time.Unix(1792005087, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScServiceYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x52\xcd\x6e\xdb\x3c\x10\xbc\xeb\x29\x06\xd6\xe5\xfb\x00\xff\x24\x69\x80\x16\xea\xc9\x75\xd2\x56\x68\x60\x07\x91\xd3\x20\xc7\x35\xb5\x96\x17\xa5\x49\x96\x5c\x47\xf1\xdb\x17\x92\xed\xa2\x41\x7a\x28\x10\xde\x38\x33\x9c\x1d\xee\x6e\xfe\xe6\x93\xe5\x98\xf9\xb0\x8f\xd2\x6c\x14\x17\x67\xe7\xef\xf1\xc5\xfb\xc6\x32\x4a\x67\xc6\x59\x47\xdf\x88\x61\x97\xb8\xc6\xce\xd5\x1c\xa1\x1b\xc6\x34\x90\xd9\xf0\x89\x19\xe2\x3b\xc7\x24\xde\xe1\x62\x7c\x86\xff\x3a\xc1\xe0\x48\x0d\xfe\xff\x98\xe5\xd8\xfb\x1d\xb6\xb4\x87\xf3\x8a\x5d\x62\xe8\x46\x12\xd6\x62\x19\xfc\x6c\x38\x28\xc4\xc1\xf8\x6d\xb0\x42\xce\x30\x5a\xd1\x4d\x5f\xe6\x68\x32\xce\x72\x3c\x1e\x2d\xfc\x4a\x49\x1c\x08\xc6\x87\x3d\xfc\xfa\x4f\x1d\x48\xfb\xc0\xdd\xd9\xa8\x86\x54\x4c\x26\x6d\xdb\x8e\xa9\x4f\x3b\xf6\xb1\x99\xd8\x83\x32\x4d\x6e\xca\xd9\xf5\xbc\xba\x1e\x5d\x8c\xcf\xfa\x37\xf7\xce\x72\x4a\x88\xfc\x73\x27\x91\x6b\xac\xf6\xa0\x10\xac\x18\x5a\x59\x86\xa5\x16\x3e\x82\x9a\xc8\x5c\x43\x7d\x17\xb8\x8d\xa2\xe2\x9a\x21\x92\x5f\x6b\x4b\x91\xb3\x1c\xb5\x24\x8d\xb2\xda\xe9\x8b\x6e\x9d\xe2\x49\x7a\x21\xf0\x0e\xe4\x30\x98\x56\x28\xab\x01\x3e\x4d\xab\xb2\x1a\x66\x39\x1e\xca\xe5\xd7\xc5\xfd\x12\x0f\xd3\xbb\xbb\xe9\x7c\x59\x5e\x57\x58\xdc\x61\xb6\x98\x5f\x95\xcb\x72\x31\xaf\xb0\xf8\x8c\xe9\xfc\x11\xdf\xca\xf9\xd5\x10\x2c\xba\xe1\x08\x7e\x0e\xb1\xcb\xef\x23\xa4\xeb\x23\xd7\x5d\xd3\x2a\xe6\x17\x01\xd6\xfe\x10\x28\x05\x36\xb2\x16\x03\x4b\xae\xd9\x51\xc3\x68\xfc\x13\x47\x27\xae\x41\xe0\xb8\x95\xd4\x4d\x33\x81\x5c\x9d\xe5\xb0\xb2\x15\x25\xed\x91\x57\x9f\x3a\xac\x48\xc5\xf1\x49\x0c\xc3\x90\x92\xf5\x0d\xd2\xe1\xde\x93\x6f\xdf\xd0\x1f\xe2\xea\xe2\x54\x23\xa3\x20\xc7\x75\x2b\xf0\x74\x9e\x6d\x59\xa9\x26\xa5\x22\x03\x1c\x6d\xb9\x38\x15\x1f\x1d\xc3\x8c\x28\xc8\x91\x4b\x81\xcc\x6b\x41\x06\x58\x5a\xb1\x4d\x9d\x05\xba\xb1\xff\xd5\xa3\x83\x38\x66\x5d\xef\x3a\x61\x0e\xdd\x07\x2e\x30\xf7\x35\xdf\xfa\xa8\x19\x90\xd8\xb2\x51\x1f\xff\xc9\x07\x08\x3e\x6a\x5f\x73\xf4\x3b\xb8\xd9\x45\xee\x1f\x87\xe8\xd5\x1b\x6f\x0b\x2c\x67\xb7\x07\xc4\x47\x2d\x70\x79\xf9\xae\xbf\x29\xc5\x86\xf5\xb6\xc7\x3e\x74\x60\xf6\x2b\x00\x00\xff\xff\xdb\x5e\xc8\x70\x0a\x04\x00\x00")
+var _templatesScServiceMonitorYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x94\x41\x6f\xe3\x36\x10\x85\xef\xfc\x15\x0f\xd1\xa5\x05\x22\x39\x09\x72\x58\xa8\x27\xd7\x9b\xb6\x42\x53\x3b\x88\xbc\xbb\xd8\x23\x4d\x8d\xa5\xc1\x52\x1c\x95\xa4\xe2\x35\x0c\xff\xf7\x42\xb2\x8c\xc4\xdd\xcb\xb6\x45\x75\x23\x39\x7c\x7c\xf3\xbd\xb1\x93\xff\xfc\xa9\x04\x0b\xe9\xf6\x9e\xeb\x26\xe2\xee\xe6\xf6\x1d\x7e\x15\xa9\x2d\xa1\x70\x26\x53\x89\x4a\xf0\xc8\x86\x5c\xa0\x0a\xbd\xab\xc8\x23\x36\x84\x79\xa7\x4d\x43\xe7\x93\x6b\x7c\x24\x1f\x58\x1c\xee\xb2\x1b\xfc\x30\x14\x5c\x4d\x47\x57\x3f\xfe\xa4\x12\xec\xa5\x47\xab\xf7\x70\x12\xd1\x07\x42\x6c\x38\x60\xcb\x96\x40\x5f\x0d\x75\x11\xec\x60\xa4\xed\x2c\x6b\x67\x08\x3b\x8e\x0d\xe2\xab\x7e\xa6\x12\x7c\x9e\x24\x64\x13\x35\x3b\x68\x18\xe9\xf6\x90\xed\xdb\x3a\xe8\x38\x1a\x06\x80\x26\xc6\x2e\xe4\xb3\xd9\x6e\xb7\xcb\xf4\xe8\x36\x13\x5f\xcf\xec\xa9\x32\xcc\x1e\x8b\xc5\xc3\xb2\x7c\x48\xef\xb2\x9b\xf1\xce\x07\x67\x29\x04\x78\xfa\xb3\x67\x4f\x15\x36\x7b\xe8\xae\xb3\x6c\xf4\xc6\x12\xac\xde\x41\x3c\x74\xed\x89\x2a\x44\x19\x0c\xef\x3c\x47\x76\xf5\x35\x82\x6c\xe3\x4e\x7b\x52\x09\x2a\x0e\xd1\xf3\xa6\x8f\x17\xb4\xce\xf6\x38\x5c\x14\x88\x83\x76\xb8\x9a\x97\x28\xca\x2b\xfc\x3c\x2f\x8b\xf2\x5a\x25\xf8\x54\xac\x7f\x5b\x7d\x58\xe3\xd3\xfc\xf9\x79\xbe\x5c\x17\x0f\x25\x56\xcf\x58\xac\x96\xef\x8b\x75\xb1\x5a\x96\x58\xfd\x82\xf9\xf2\x33\x7e\x2f\x96\xef\xaf\x41\x1c\x1b\xf2\xa0\xaf\x9d\x1f\xfc\x8b\x07\x0f\x1c\xa9\x1a\xa0\x95\x44\x17\x06\xb6\x72\x8a\x2f\x74\x64\x78\xcb\x06\x56\xbb\xba\xd7\x35\xa1\x96\x17\xf2\x8e\x5d\x8d\x8e\x7c\xcb\x61\x48\x33\x40\xbb\x4a\x25\xb0\xdc\x72\xd4\x71\xdc\xf9\xa6\xa9\xd3\x88\x3c\x79\x69\x29\x36\xd4\x07\xac\x3a\xf2\x3a\x8a\x47\x49\xfe\x85\x0d\xfd\x21\x8e\x87\x65\x30\x5e\x77\xc3\x03\xc3\xe5\x96\xa2\x67\x13\xce\xf9\x19\x71\xd1\x8b\xb5\xe4\x55\x82\x56\x3b\x5d\x8f\xe4\xbc\xf4\x75\x03\x8d\x86\x74\x35\xa6\x13\x4e\x92\x19\x56\xce\xee\xe1\x69\x30\x43\xd5\x38\x2f\x2a\x41\x9a\x92\x1b\xd2\x4a\xa7\xb2\xb4\x3d\x3d\x3d\x5a\x3c\x8f\xfb\xbf\xfe\xd4\xe1\x00\xde\x22\xfb\x5b\x5b\xc7\xa3\xfa\xc2\xae\xca\xcf\xed\x2a\xdd\xf1\xf4\x6b\xc8\xf1\x72\xab\x5a\x8a\xba\xd2\x51\xe7\x0a\x70\xba\xa5\xfc\x4d\xb3\xe9\xd4\x6a\x3a\xe1\x98\x4a\x42\xa7\x0d\xe5\x38\x1c\x90\x2d\xcf\x4b\x1c\x8f\x0a\xb0\x7a\x43\x36\x0c\x52\x18\xa6\x33\xc7\xb9\x53\xa3\xa3\xb6\x52\xa7\xdf\x6a\xab\x21\xeb\xe1\x86\xb1\x7d\x88\xe4\x8b\xa7\x1c\x4b\x71\xa4\x80\x40\x96\x4c\x14\xff\xcf\xf4\x80\x4e\x7c\x1c\x4d\xa4\x53\x47\xaf\xf6\x81\xce\x4b\x14\x23\x36\xc7\x7a\xf1\x34\x0a\x0f\xe5\x39\xde\xdd\xdf\xdf\x8f\xcb\xa8\x7d\x4d\xf1\xe9\x75\x33\x4d\xd3\x0b\x68\x53\x6a\xec\xea\xcc\x88\x27\x09\x99\x91\x76\xf6\x72\x7b\xc9\x79\xe2\xff\x5d\x7c\xff\x57\xae\x97\x14\x5b\x1d\x4d\xf3\xf8\x46\xee\xfb\x05\x01\x72\x55\x27\xec\xce\x70\x4f\xe0\xde\xc2\x0d\xa6\xa1\x61\x84\xc6\x3f\xb7\x51\x3e\xda\xb0\x10\xb7\xe5\xfa\xfc\x1a\xbb\x40\xa6\xf7\x54\x7e\xe1\xee\x23\x79\xde\xee\x73\x44\xdf\x93\x3a\x1c\x40\xae\xc2\xf1\xa8\xfe\x1a\x00\xba\x45\x3d\xf0\x34\x06\x00\x00")
+
+func templatesScServiceMonitorYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesScServiceMonitorYamlTmpl,
+		"templates/sc/service-monitor.yaml.tmpl",
+	)
+}
+
+func templatesScServiceMonitorYamlTmpl() (*asset, error) {
+	bytes, err := templatesScServiceMonitorYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/sc/service-monitor.yaml.tmpl", size: 1588, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesScServiceYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x92\x4f\x6f\xdb\x46\x10\xc5\xef\xfb\x29\x1e\xc4\x4b\x0b\x58\xb4\xe3\x08\x69\xc1\x9e\x54\xdb\x69\x89\x06\x94\x60\x2a\x0d\x72\x1c\x2d\x47\xd4\x20\xab\xdd\xcd\xee\x52\x0a\x61\xe4\xbb\x17\x24\x25\xa4\x6e\xda\xa2\x68\x78\x9b\x3f\xfb\x9b\xc7\x37\x93\x7d\xf3\xa7\x32\xdc\x39\xdf\x07\x69\xf7\x09\xb7\x37\x2f\x7e\xc0\x2f\xce\xb5\x86\x51\x5a\x9d\xab\x4c\x65\x78\x23\x9a\x6d\xe4\x06\x9d\x6d\x38\x20\xed\x19\x4b\x4f\x7a\xcf\x97\xca\x15\x7e\xe7\x10\xc5\x59\xdc\xe6\x37\xf8\x6e\x68\x98\x9d\x4b\xb3\xef\x7f\x52\x19\x7a\xd7\xe1\x40\x3d\xac\x4b\xe8\x22\x23\xed\x25\x62\x27\x86\xc1\x9f\x34\xfb\x04\xb1\xd0\xee\xe0\x8d\x90\xd5\x8c\x93\xa4\x3d\xd2\x17\x7e\xae\x32\xbc\x3f\x23\xdc\x36\x91\x58\x10\xb4\xf3\x3d\xdc\xee\xcf\x7d\xa0\x34\x0a\x06\x80\x7d\x4a\x3e\x16\xd7\xd7\xa7\xd3\x29\xa7\x51\x6d\xee\x42\x7b\x6d\xa6\xce\x78\xfd\xa6\xbc\x7b\xa8\xea\x87\xf9\x6d\x7e\x33\xbe\x79\x6b\x0d\xc7\x88\xc0\x1f\x3b\x09\xdc\x60\xdb\x83\xbc\x37\xa2\x69\x6b\x18\x86\x4e\x70\x01\xd4\x06\xe6\x06\xc9\x0d\x82\x4f\x41\x92\xd8\xf6\x0a\xd1\xed\xd2\x89\x02\xab\x0c\x8d\xc4\x14\x64\xdb\xa5\x67\x6e\x5d\xe4\x49\x7c\xd6\xe0\x2c\xc8\x62\xb6\xac\x51\xd6\x33\xfc\xbc\xac\xcb\xfa\x4a\x65\x78\x57\x6e\x7e\x5d\xbd\xdd\xe0\xdd\xf2\xf1\x71\x59\x6d\xca\x87\x1a\xab\x47\xdc\xad\xaa\xfb\x72\x53\xae\xaa\x1a\xab\xd7\x58\x56\xef\xf1\x5b\x59\xdd\x5f\x81\x25\xed\x39\x80\x3f\xf9\x30\xe8\x77\x01\x32\xf8\xc8\xcd\x60\x5a\xcd\xfc\x4c\xc0\xce\x4d\xeb\x8b\x9e\xb5\xec\x44\xc3\x90\x6d\x3b\x6a\x19\xad\x3b\x72\xb0\x62\x5b\x78\x0e\x07\x89\xc3\x36\x23\xc8\x36\x2a\x83\x91\x83\x24\x4a\x63\xe6\xab\x9f\x9a\x4e\xa4\xe6\x70\x14\xcd\xd0\x94\xc8\xb8\x16\x71\x8a\xc7\xe2\xe5\xd0\xfe\xf7\xa7\x3e\x88\x6d\x8a\xcb\x0c\x45\x5e\xce\xe7\x56\xe0\xf8\x42\x1d\x38\x51\x43\x89\x0a\x05\x58\x3a\x70\x71\x19\x3e\x3f\x8b\x99\x93\x97\x73\x2d\x7a\xd2\x5c\xe0\xe9\x09\x79\x75\x09\xf1\xf9\xb3\x02\x0c\x6d\xd9\xc4\x81\x81\x61\xef\x7f\x0b\x19\x52\x1c\xd4\x60\xde\xd0\x98\x21\xf5\x9e\x0b\x54\xae\xe1\xb5\x0b\x49\x3d\x3d\xcd\x21\x3b\xf0\x47\xe4\xe5\xfa\x35\x1d\xc4\xf4\x98\x89\x3f\xbe\x9a\x4d\x33\xc4\x4f\xc9\xb5\x33\xa2\xfb\x02\xb5\xd8\xd6\x70\x9d\x48\x7f\xf8\x52\x15\x1e\x55\xcc\x51\xae\x8f\xaf\x46\x24\x9b\xc8\x5f\x73\x9b\x8e\xcc\x3f\x70\xd7\x81\x77\x1c\xee\x3b\x32\xff\xc2\x5e\xfc\x65\x88\x6d\x26\x58\x64\xc3\x3a\xb9\xf0\x9f\xac\x00\xbc\x0b\xe9\x0c\xbd\x98\xaf\xbb\xc0\xe3\x63\x1f\x5c\x72\xda\x99\x02\x9b\xbb\xf5\x94\x71\x21\x15\x58\x2c\x5e\x8e\x51\xa2\xd0\x72\x1a\xac\x2b\xf0\xe3\x62\xf1\x52\xa9\x3f\x06\x00\x53\xc6\x51\xb1\xce\x04\x00\x00")
 
 func templatesScServiceYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -428,12 +770,12 @@ func templatesScServiceYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/service.yaml.tmpl", size: 1034, mode: os.FileMode(416), modTime: time.Unix(1507129340, 0)}
+	info := bindataFileInfo{name: "templates/sc/service.yaml.tmpl", size: 1230, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesScTlsCertSecretYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x53\xc1\x6e\xe3\x36\x10\xbd\xeb\x2b\x1e\xe2\x4b\x0b\x38\x72\x36\x97\x02\xee\x49\xeb\x75\x5b\x21\x81\x1d\x44\x4e\x16\x39\x2d\xc6\xd4\x58\x1e\x84\x22\x59\x92\x8a\x57\x58\xec\xbf\x17\x94\x94\xb4\x81\xd1\x53\x78\xe4\xbc\x79\xf3\xde\xe3\x70\xf6\xe1\x93\xcd\xb0\xb2\xae\xf7\xd2\x1c\x23\xae\xaf\x3e\xfd\x86\x3f\xad\x6d\x34\xa3\x34\x2a\xcf\x52\xf9\x56\x14\x9b\xc0\x35\x3a\x53\xb3\x47\x3c\x32\x0a\x47\xea\xc8\xaf\x95\x39\x1e\xd9\x07\xb1\x06\xd7\xf9\x15\x7e\x49\x80\x8b\xa9\x74\xf1\xeb\xef\xd9\x0c\xbd\xed\xd0\x52\x0f\x63\x23\xba\xc0\x88\x47\x09\x38\x88\x66\xf0\x77\xc5\x2e\x42\x0c\x94\x6d\x9d\x16\x32\x8a\x71\x92\x78\x1c\xc6\x4c\x24\x79\x36\xc3\xd3\x44\x61\xf7\x91\xc4\x80\xa0\xac\xeb\x61\x0f\xff\xc5\x81\xe2\x20\x38\x9d\x63\x8c\x2e\x2c\x17\x8b\xd3\xe9\x94\xd3\xa0\x36\xb7\xbe\x59\xe8\x11\x19\x16\xb7\xe5\x6a\xbd\xa9\xd6\x97\xd7\xf9\xd5\xd0\xf3\x60\x34\x87\x00\xcf\x7f\x77\xe2\xb9\xc6\xbe\x07\x39\xa7\x45\xd1\x5e\x33\x34\x9d\x60\x3d\xa8\xf1\xcc\x35\xa2\x4d\x82\x4f\x5e\xa2\x98\x66\x8e\x60\x0f\xf1\x44\x9e\xb3\x19\x6a\x09\xd1\xcb\xbe\x8b\xef\xd2\x7a\x95\x27\xe1\x1d\xc0\x1a\x90\xc1\x45\x51\xa1\xac\x2e\xf0\xb9\xa8\xca\x6a\x9e\xcd\xf0\xb5\xdc\xfd\xb5\x7d\xd8\xe1\x6b\x71\x7f\x5f\x6c\x76\xe5\xba\xc2\xf6\x1e\xab\xed\xe6\x4b\xb9\x2b\xb7\x9b\x0a\xdb\x3f\x50\x6c\x9e\x70\x53\x6e\xbe\xcc\xc1\x12\x8f\xec\xc1\xdf\x9d\x4f\xfa\xad\x87\xa4\x1c\xb9\x4e\xa1\x55\xcc\xef\x04\x1c\xec\x28\x28\x38\x56\x72\x10\x05\x4d\xa6\xe9\xa8\x61\x34\xf6\x85\xbd\x11\xd3\xc0\xb1\x6f\x25\xa4\xd7\x0c\x20\x53\x67\x33\x68\x69\x25\x52\x1c\x6e\xce\x4c\x8d\x2b\x72\xd7\xed\xb5\xa8\xc5\x9d\x97\x17\x8a\x8c\x67\xee\xe1\x48\xfc\x30\x30\xb0\x7f\x11\xc5\x50\x14\x49\xdb\x06\xe4\x64\xb8\x63\x3f\x47\x88\x36\x85\x4d\x21\x9b\x21\xb0\xf2\x1c\x73\x3c\x84\x31\xe2\xc0\xaa\xf3\xac\xfb\xb4\x19\x6d\x67\x44\x25\xe6\xb7\xd5\x68\x49\xcc\x82\x9a\xc6\x73\x43\x29\x4c\x72\x32\x70\x24\xde\x51\x53\xf5\xb8\xfa\x76\xf7\xf0\xf9\xb6\x5c\x7d\xbb\x59\x3f\x2d\xcf\x74\xb8\x41\x73\xd2\xfa\x0a\xbe\x2f\x1f\x8b\xdd\xfa\x7f\xd0\xff\x5a\x4b\xec\x1f\xff\x76\xe4\x64\xfa\x35\x4b\xbc\x7c\xca\x9e\xc5\xd4\x4b\x54\x43\x06\x59\xec\x1d\x2f\xf1\xdc\xed\xd9\x1b\x8e\x1c\x72\xb1\x8b\xa8\x43\xd6\x72\xa4\x9a\x22\x2d\x33\xc0\x50\xcb\xcb\x64\x7b\xf4\x7c\xa9\xd8\xc7\xe9\x3a\x38\x52\xfc\x66\xe1\x72\xb2\x90\x01\x9a\xf6\xac\x43\xea\x46\xda\xee\x33\xc8\xe5\x1b\x5d\xf6\x3a\x26\xea\x90\x2b\x1f\x97\xf8\xf1\x03\x79\x71\x57\x56\x63\xc7\xf8\xe0\x37\xdc\xe3\xe7\xcf\x09\xf5\xcc\xfd\x19\x6a\x0c\x6d\x82\xfd\x13\x00\x00\xff\xff\x84\x88\x6f\x8c\xac\x04\x00\x00")
+var _templatesScTlsCertSecretYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x93\x41\x6f\xdb\x38\x10\x85\xef\xfc\x15\x0f\xd1\x65\x17\x70\xe4\x34\x97\x05\xbc\x27\xd7\xf5\xee\x0a\x09\xec\x20\x72\x52\xe4\x54\x8c\xa9\xb1\x3c\x08\x4d\x72\x49\x2a\xae\x50\xe4\xbf\x2f\x28\xd9\x6d\x83\x60\x4f\xd5\x8d\xe4\xe3\xe3\x37\x6f\x46\xc5\x2f\x7f\xaa\xc0\xc2\xf9\x3e\x48\xbb\x4f\xb8\xbe\xfa\xf0\x07\xfe\x76\xae\x35\x8c\xca\xea\x52\x15\xaa\xc0\xad\x68\xb6\x91\x1b\x74\xb6\xe1\x80\xb4\x67\xcc\x3d\xe9\x3d\x9f\x4f\x26\x78\xe4\x10\xc5\x59\x5c\x97\x57\xf8\x2d\x0b\x2e\x4e\x47\x17\xbf\xff\xa9\x0a\xf4\xae\xc3\x81\x7a\x58\x97\xd0\x45\x46\xda\x4b\xc4\x4e\x0c\x83\xbf\x6a\xf6\x09\x62\xa1\xdd\xc1\x1b\x21\xab\x19\x47\x49\x7b\xa4\x1f\xfe\xa5\x2a\xf0\x74\xb2\x70\xdb\x44\x62\x41\xd0\xce\xf7\x70\xbb\x9f\x75\xa0\x34\x00\x03\xc0\x3e\x25\x1f\x67\xd3\xe9\xf1\x78\x2c\x69\xa0\x2d\x5d\x68\xa7\x66\x54\xc6\xe9\x6d\xb5\x58\xae\xea\xe5\xe5\x75\x79\x35\xdc\x79\xb0\x86\x63\x44\xe0\x7f\x3b\x09\xdc\x60\xdb\x83\xbc\x37\xa2\x69\x6b\x18\x86\x8e\x70\x01\xd4\x06\xe6\x06\xc9\x65\xe0\x63\x90\x24\xb6\x9d\x20\xba\x5d\x3a\x52\x60\x55\xa0\x91\x98\x82\x6c\xbb\xf4\x26\xad\x33\x9e\xc4\x37\x02\x67\x41\x16\x17\xf3\x1a\x55\x7d\x81\x8f\xf3\xba\xaa\x27\xaa\xc0\xe7\x6a\xf3\xcf\xfa\x61\x83\xcf\xf3\xfb\xfb\xf9\x6a\x53\x2d\x6b\xac\xef\xb1\x58\xaf\x3e\x55\x9b\x6a\xbd\xaa\xb1\xfe\x0b\xf3\xd5\x13\x6e\xaa\xd5\xa7\x09\x58\xd2\x9e\x03\xf8\xab\x0f\x99\xdf\x05\x48\xce\x91\x9b\x1c\x5a\xcd\xfc\x06\x60\xe7\xc6\xf6\x45\xcf\x5a\x76\xa2\x61\xc8\xb6\x1d\xb5\x8c\xd6\xbd\x70\xb0\x62\x5b\x78\x0e\x07\x89\xb9\x9b\x11\x64\x1b\x55\xc0\xc8\x41\x12\xa5\x61\xe7\x5d\x51\xe3\x88\xdc\x75\x5b\x23\x7a\x7a\x17\xe4\x85\x12\xe3\x99\x7b\x78\x92\x30\x3c\x18\x39\xbc\x88\x66\x68\x4a\x64\x5c\x0b\xf2\x32\xec\x71\x98\x20\x26\x97\xc3\xa6\xa8\x0a\x44\xd6\x81\x53\x89\x87\x3c\x6a\xc9\xe5\x75\x17\xd8\xf4\x79\x32\x0e\x9d\x15\x4d\xe9\xa7\xd1\x38\x90\xd8\x29\xb5\x6d\xe0\x96\x72\xda\xe4\x65\xf0\xc8\xbe\x23\x53\xfd\xb8\xf8\x72\xf7\xf0\xf1\xb6\x5a\x7c\xb9\x59\x3e\xcd\xde\x71\xf8\x81\x39\xb3\x9e\xc5\xf7\xd5\xe3\x7c\xb3\xfc\x1f\xf5\x8f\xd2\xb2\xfb\x2f\x7f\x8a\xbc\x9c\xfe\x9a\x19\x5e\x3e\xa8\x67\xb1\xcd\x0c\xf5\x90\x81\x4a\xbd\xe7\x19\x9e\xbb\x2d\x07\xcb\x89\x63\x29\x6e\x9a\x4c\x54\x07\x4e\xd4\x50\xa2\x99\x02\x2c\x1d\x78\x96\xc3\xcc\xa4\x1c\x2e\x35\x87\x74\xda\x8e\x9e\x34\xcf\xf0\xed\x1b\xca\xd5\x79\x89\xd7\x57\x05\x18\xda\xb2\x89\xf9\x3a\xf2\x78\x7f\x2f\xf3\xf2\x54\xe6\xe5\x77\x3f\x75\x7e\x27\x99\x58\xea\x90\x46\xbb\xf9\x5d\x55\x8f\x31\x8e\x1d\xbf\xe1\x7e\x34\xce\xaa\x67\xee\xdf\xa9\xc6\xd4\x6e\xb8\xc7\xeb\xab\xfa\x6f\x00\xea\x42\x56\x72\xad\x04\x00\x00")
 
 func templatesScTlsCertSecretYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -448,12 +790,12 @@ func templatesScTlsCertSecretYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/sc/tls-cert-secret.yaml.tmpl", size: 1196, mode: os.FileMode(416), modTime: time.Unix(1525803557, 0)}
+	info := bindataFileInfo{name: "templates/sc/tls-cert-secret.yaml.tmpl", size: 1197, mode: os.FileMode(420), modTime: time.Unix(1792002248, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesGcpGcpBrokerYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x54\xc1\x6e\xe3\x36\x10\xbd\xeb\x2b\x1e\xa2\x4b\x0b\xd8\x52\x92\x4b\x0b\xef\xc9\xeb\xa4\xa9\xd0\xc0\x09\xe2\xa4\xc1\x1e\xc7\xd4\x58\x62\x4d\x93\x2a\x87\xb2\xd7\x58\xec\xbf\x17\x14\xe5\x4d\x8c\x45\x4f\xbb\x3e\x18\x02\xe7\x71\xe6\xcd\xbc\x37\xcc\x7f\xf8\x97\xe5\x58\xb8\xee\xe8\x75\xd3\x06\x5c\x5f\x5e\xfd\x86\x3b\xe7\x1a\xc3\xa8\xac\x2a\xb2\x18\xbe\xd7\x8a\xad\x70\x8d\xde\xd6\xec\x11\x5a\xc6\xbc\x23\xd5\xf2\x29\x32\xc1\xdf\xec\x45\x3b\x8b\xeb\xe2\x12\xbf\x44\xc0\xc5\x18\xba\xf8\xf5\x43\x96\xe3\xe8\x7a\xec\xe8\x08\xeb\x02\x7a\x61\x84\x56\x0b\x36\xda\x30\xf8\xb3\xe2\x2e\x40\x5b\x28\xb7\xeb\x8c\x26\xab\x18\x07\x1d\xda\xa1\xcc\x98\xa4\xc8\x72\x7c\x1a\x53\xb8\x75\x20\x6d\x41\x50\xae\x3b\xc2\x6d\xde\xe3\x40\x61\x20\x1c\x7f\x6d\x08\x9d\xcc\xca\xf2\x70\x38\x14\x34\xb0\x2d\x9c\x6f\x4a\x93\x90\x52\xde\x57\x8b\xdb\xe5\xea\x76\x7a\x5d\x5c\x0e\x77\x5e\xac\x61\x11\x78\xfe\xb7\xd7\x9e\x6b\xac\x8f\xa0\xae\x33\x5a\xd1\xda\x30\x0c\x1d\xe0\x3c\xa8\xf1\xcc\x35\x82\x8b\x84\x0f\x5e\x07\x6d\x9b\x09\xc4\x6d\xc2\x81\x3c\x67\x39\x6a\x2d\xc1\xeb\x75\x1f\xce\xa6\x75\xa2\xa7\xe5\x0c\xe0\x2c\xc8\xe2\x62\xbe\x42\xb5\xba\xc0\xc7\xf9\xaa\x5a\x4d\xb2\x1c\xaf\xd5\xf3\x9f\x0f\x2f\xcf\x78\x9d\x3f\x3d\xcd\x97\xcf\xd5\xed\x0a\x0f\x4f\x58\x3c\x2c\x6f\xaa\xe7\xea\x61\xb9\xc2\xc3\x1f\x98\x2f\x3f\xe1\xaf\x6a\x79\x33\x01\xeb\xd0\xb2\x07\x7f\xee\x7c\xe4\xef\x3c\x74\x9c\x23\xd7\x71\x68\x2b\xe6\x33\x02\x1b\x97\x08\x49\xc7\x4a\x6f\xb4\x82\x21\xdb\xf4\xd4\x30\x1a\xb7\x67\x6f\xb5\x6d\xd0\xb1\xdf\x69\x89\x6a\x0a\xc8\xd6\x59\x0e\xa3\x77\x3a\x50\x18\x4e\xbe\x6b\x2a\x59\xe4\x45\xe2\xd5\x21\x35\x2b\xcf\xe1\x5b\x25\x32\x9e\xa9\x3e\x42\x79\xa6\xd8\xb3\xb0\xdf\x6b\xc5\x20\xa5\x5c\x6f\xc3\x04\xca\x59\xcb\x2a\x08\x82\xcb\x72\xdc\x2d\x1e\xb1\xf6\x6e\xcb\x1e\x14\x86\x04\x2f\x4f\xf7\x05\x5e\xb5\x31\x68\x38\x9d\x18\x2d\x21\x0a\x3f\xa6\x92\xe1\xf0\xed\x62\x96\xa3\xf3\x6e\xaf\x6b\x96\x02\xf3\x4d\x60\x9f\x8a\x27\x82\x3a\x4a\x2c\xae\xf7\x8a\x27\xa0\x68\x46\x0f\x69\x5d\x6f\x6a\xac\x19\x83\xd6\x03\x11\xe9\x95\x62\x91\x4d\x6f\xcc\x31\x55\x0c\x2d\x0b\xbf\x15\x8d\x1e\x9d\x0d\x5e\xdb\xf6\x6b\x56\x21\xf1\x1b\xc3\xca\x90\x08\x4b\x1c\xcd\x8f\xef\x27\x75\x7a\x5c\xaf\xd9\xb7\xfc\x14\xc8\xb8\xa6\xd8\xfe\x2e\x85\x76\xe5\xfe\x6a\xcd\x81\xae\xb2\xad\xb6\xf5\x0c\x0b\xd3\x4b\x60\xbf\x4a\xd0\x8f\x69\x28\x3b\x0e\x54\x53\xa0\x59\x06\x58\xda\xf1\x0c\x8d\xea\xa6\xe3\xc4\xa2\x1d\x62\x20\x8f\xd3\x8e\xee\x1e\x45\x89\x9f\xc3\x64\x53\x92\x62\x80\x0c\x7f\x8f\xde\xd5\xbd\x8a\x96\x38\x51\x1a\x55\x9b\x3f\x56\x1f\x86\x35\x97\x40\x8d\xb6\xcd\x09\xfd\x0f\xab\x10\xe9\x93\x95\xe9\x81\xcc\x36\xb4\xde\xf5\x4d\x3b\x81\x71\x8a\x4c\xd2\xa1\x4b\xb0\xc9\x69\x09\x05\xda\xea\x40\x66\xd4\xcf\xd9\xa8\xfa\x5e\xfb\xd0\x93\x39\xb9\x64\xcc\x83\xc5\x7d\x95\xe8\xf5\xde\xcc\xde\xb6\xff\x8c\x5c\xd1\x0c\x0f\x1b\x75\x5a\x0a\xe5\x76\xe5\xfe\x8a\x4c\xd7\xd2\x55\x39\x16\x96\xf2\x3b\x7e\x65\xba\x29\xe5\xbb\x69\x01\xf9\x79\x57\x31\x76\xf2\xc5\x04\x87\x56\xab\x36\xae\x7a\x2f\xe3\x33\x62\x4c\x81\x25\x73\x1d\x3d\x1e\x5d\xf6\xbe\xd9\x9f\x40\xfa\x7d\xf9\xff\xe7\x9b\x4a\x7c\xf9\x82\xe2\x6e\xf1\x98\xf4\x8c\x62\x7f\xfd\x3a\x10\xb8\x61\x51\x5e\xaf\xc7\x65\x1a\x77\x38\x75\xa2\x9c\x8d\x2f\xee\x18\x69\x9d\x0f\x53\xa3\xf7\xb1\x35\x26\x1f\x15\x70\x5b\xb6\x19\x40\x7d\x68\x2b\xbb\x71\xd1\x48\x18\x83\xe9\x1b\x63\xc2\x27\xde\x9c\x0e\xde\x9b\x50\xf6\x6a\x3a\x3e\x07\xd3\x04\x3c\x03\x49\x47\x2a\x22\x87\x31\x4c\x5d\x2c\x93\xfd\x17\x00\x00\xff\xff\x59\x57\x2b\x57\xf6\x06\x00\x00")
+var _templatesGcpGcpBrokerYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x54\x41\xaf\xda\x46\x10\xbe\xfb\x57\x7c\xc2\x97\x56\x02\x93\x97\x4b\x2b\x72\x22\x24\x4d\xad\x3e\xc1\x13\xbc\xd7\x28\xc7\x61\x3d\xb6\xb7\x2c\xbb\xee\xce\x1a\x82\x10\xff\xbd\x5a\xdb\xe4\x81\xa2\xaa\x87\x94\x03\xb2\x66\x66\x67\xbe\x99\xef\x9b\x49\x7f\xf8\x97\xa4\x58\xb8\xe6\xe4\x75\x55\x07\xbc\x7d\xf3\xf0\x0b\x3e\x39\x57\x19\x46\x6e\x55\x96\xa4\x49\x8a\x47\xad\xd8\x0a\x17\x68\x6d\xc1\x1e\xa1\x66\xcc\x1b\x52\x35\x5f\x3d\x63\xfc\xc9\x5e\xb4\xb3\x78\x9b\xbd\xc1\x4f\x31\x60\x34\xb8\x46\x3f\xbf\x4b\x52\x9c\x5c\x8b\x3d\x9d\x60\x5d\x40\x2b\x8c\x50\x6b\x41\xa9\x0d\x83\xbf\x2a\x6e\x02\xb4\x85\x72\xfb\xc6\x68\xb2\x8a\x71\xd4\xa1\x46\x78\xcd\x9f\x25\x29\xbe\x0c\x29\xdc\x36\x90\xb6\x20\x28\xd7\x9c\xe0\xca\xdb\x38\x50\xe8\x00\x03\x40\x1d\x42\x23\xb3\xe9\xf4\x78\x3c\x66\xd4\xa1\xcd\x9c\xaf\xa6\xa6\x8f\x94\xe9\x63\xbe\xf8\xb8\xdc\x7c\x9c\xbc\xcd\xde\x74\x6f\x5e\xac\x61\x11\x78\xfe\xbb\xd5\x9e\x0b\x6c\x4f\xa0\xa6\x31\x5a\xd1\xd6\x30\x0c\x1d\xe1\x3c\xa8\xf2\xcc\x05\x82\x8b\x80\x8f\x5e\x07\x6d\xab\x31\xc4\x95\xe1\x48\x9e\x93\x14\x85\x96\xe0\xf5\xb6\x0d\x77\xd3\xba\xc2\xd3\x72\x17\xe0\x2c\xc8\x62\x34\xdf\x20\xdf\x8c\xf0\x7e\xbe\xc9\x37\xe3\x24\xc5\xe7\xfc\xf9\xf7\xd5\xcb\x33\x3e\xcf\xd7\xeb\xf9\xf2\x39\xff\xb8\xc1\x6a\x8d\xc5\x6a\xf9\x21\x7f\xce\x57\xcb\x0d\x56\xbf\x61\xbe\xfc\x82\x3f\xf2\xe5\x87\x31\x58\x87\x9a\x3d\xf8\x6b\xe3\x23\x7e\xe7\xa1\xe3\x1c\xb9\x88\x43\xdb\x30\xdf\x01\x28\x5d\x4f\x9f\x34\xac\x74\xa9\x15\x0c\xd9\xaa\xa5\x8a\x51\xb9\x03\x7b\xab\x6d\x85\x86\xfd\x5e\x4b\x64\x53\x40\xb6\x48\x52\x18\xbd\xd7\x81\x42\x67\xf9\xae\xa9\x5e\x22\x2f\x12\x9f\x46\xb3\xb0\xf2\x1c\xbe\x55\x22\xe3\x99\x8a\x13\x94\x67\x8a\x43\x11\xf6\x07\xad\x18\xa4\x94\x6b\x6d\x18\x43\x39\x6b\x59\x05\x41\x70\x49\x8a\x4f\x8b\x27\x6c\xbd\xdb\xb1\x07\x85\x2e\xc1\xcb\xfa\x31\xc3\x67\x6d\x0c\x2a\xee\x2d\x46\x4b\x88\xc4\x0f\xa9\xa4\x33\xbe\x3e\x4c\x52\x34\xde\x1d\x74\xc1\x92\x61\x5e\x06\xf6\x7d\xf1\x1e\xa0\x8e\x14\x8b\x6b\xbd\xe2\x31\x28\x8a\xd1\x43\x6a\xd7\x9a\x02\x5b\x46\xc7\x75\x07\x44\x5a\xa5\x58\xa4\x6c\x8d\x39\xf5\x15\x43\xcd\xc2\xaf\x45\xa3\x46\x67\x49\x0a\x60\xd7\x6e\x59\x85\x1e\xdf\xe0\x56\x86\x44\x58\xe2\x68\x7e\xf8\x97\x50\xa3\x87\xf5\x9a\x7d\xcb\x4f\x81\x8c\xab\xb2\xdd\xaf\x92\x69\x37\x3d\x3c\x6c\x39\xd0\x43\xb2\xd3\xb6\x98\x61\x61\x5a\x09\xec\x37\x7d\xe8\xfb\x6e\x9a\xc9\x9e\x03\x15\x14\x68\x96\x00\x96\xf6\x3c\x43\xa5\x9a\xc9\x30\xb1\x28\x87\xe8\x48\xf1\xb2\x7e\x8c\xea\x1e\x48\x89\x9f\x1d\x25\x7d\x92\xac\x0b\xe9\xfe\x9e\xbc\x2b\x5a\x15\x25\x71\x85\x34\xb0\x36\x7f\xca\xdf\x75\x6b\x2e\x81\x2a\x6d\xab\x6b\xf4\x5f\xac\x42\x84\x4f\x56\x26\x47\x32\xbb\x50\x7b\xd7\x56\xf5\x18\xc6\x29\x32\x3d\x0f\x4d\x1f\x36\xbe\x2e\xa1\x40\x5b\x1d\xc8\x0c\xfc\x39\x1b\x59\x3f\x68\x1f\x5a\x32\x57\x95\x0c\x79\xb0\x78\xcc\x7b\x78\xad\x37\xb3\xd7\xed\xbf\x03\x97\x55\xdd\x61\xa3\x46\x4b\xa6\xdc\x7e\x7a\x78\x20\xd3\xd4\xf4\x30\x1d\x0a\xcb\xf4\x3b\x7c\xd3\xbe\x8c\x4c\x6f\xa6\x05\xa4\xf7\x5d\x45\xdf\x50\x48\xc6\x38\xd6\x5a\xd5\xd0\x12\x7b\xea\xcf\x88\x31\x19\x96\xcc\x85\xc4\x79\x6e\xf9\xae\xd9\xff\x01\xf4\x6d\xf9\x7f\xc7\xdb\x97\x38\x9f\x91\x7d\x5a\x3c\xf5\x7c\x46\xb2\x2f\x97\x0e\xc0\x07\x16\xe5\xf5\x96\xe5\x76\x87\xfb\x4e\x94\xb3\xf1\xe2\x0e\x9e\xda\xf9\x30\x31\xfa\xc0\x71\x5f\xc8\xc7\x03\xe7\x76\x6c\x13\x80\xda\x50\xe7\xb6\x74\x51\x48\x18\x9c\xfd\x37\x86\x84\x6b\x2e\xaf\x86\x5b\x11\xca\x41\x4d\x86\x73\x30\xe9\x03\xef\x82\xa4\x21\x15\xe5\xda\x71\x37\x71\xb1\x4c\x72\x3e\x4f\xa0\x4b\x64\x83\xc6\x17\x71\xdd\xd6\x1c\x4f\x6f\xa7\x49\xb9\xb6\xb5\xb2\xe6\x34\x74\xd4\x05\x62\x58\x4c\xec\x29\xa8\x3a\x5e\x04\x32\xa6\x0b\x18\x24\xb7\x67\x1b\x04\xe4\x19\xae\x2c\xd9\xc7\x2b\x0a\x0c\xdb\x76\x5b\xa0\xef\x63\xc8\xda\x95\x9f\x75\xa0\x3c\xd9\x8a\xff\x0b\x17\x30\xc1\x28\x32\x81\xcb\x65\xd4\x3d\x63\x5b\x44\xd7\xf9\x3c\x01\xdb\x02\x97\x4b\xf2\xcf\x00\xf2\xcf\xc5\x91\xd8\x07\x00\x00")
 
 func templatesGcpGcpBrokerYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -468,12 +810,12 @@ func templatesGcpGcpBrokerYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/gcp/gcp-broker.yaml.tmpl", size: 1782, mode: os.FileMode(416), modTime: time.Unix(1525803557, 0)}
+	info := bindataFileInfo{name: "templates/gcp/gcp-broker.yaml.tmpl", size: 2008, mode: os.FileMode(420), modTime: time.Unix(1792001836, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesGcpGoogleOauthDeploymentYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x54\xcd\x72\xe3\x36\x13\xbc\xf3\x29\xba\xa4\xc3\xf7\xa5\xca\xfa\xb1\x77\x2b\x49\x31\x27\xc5\x76\x36\x4a\x1c\xc9\x65\x69\xb3\xb5\xc7\x11\x38\x22\x51\x06\x01\x06\x18\x5a\xd6\xdb\xa7\x00\x51\x32\x99\xcd\x61\x53\x8b\x8b\x2d\x4c\x4f\x4f\xa3\xd1\xe0\xf8\x9b\x57\x36\xc6\xad\x6b\x8e\x5e\x97\x95\xe0\x66\x7e\xfd\x03\x3e\x38\x57\x1a\xc6\xd2\xaa\x69\x16\xcb\x0f\x5a\xb1\x0d\x5c\xa0\xb5\x05\x7b\x48\xc5\x58\x34\xa4\x2a\x3e\x57\xae\xf0\x27\xfb\xa0\x9d\xc5\xcd\x74\x8e\xff\x47\xc0\xa8\x2b\x8d\xbe\xfb\x29\x1b\xe3\xe8\x5a\xd4\x74\x84\x75\x82\x36\x30\xa4\xd2\x01\x7b\x6d\x18\xfc\xaa\xb8\x11\x68\x0b\xe5\xea\xc6\x68\xb2\x8a\x71\xd0\x52\xa5\x31\x1d\xc9\x34\x1b\xe3\x73\x47\xe1\x76\x42\xda\x82\xa0\x5c\x73\x84\xdb\xf7\x71\x20\x49\x82\xe3\xaa\x44\x9a\x90\xcf\x66\x87\xc3\x61\x4a\x49\xed\xd4\xf9\x72\x66\x4e\xc8\x30\x7b\x58\xde\xde\xaf\x36\xf7\x93\x9b\xe9\x3c\xf5\x7c\xb4\x86\x43\x80\xe7\xbf\x5a\xed\xb9\xc0\xee\x08\x6a\x1a\xa3\x15\xed\x0c\xc3\xd0\x01\xce\x83\x4a\xcf\x5c\x40\x5c\x14\x7c\xf0\x5a\xb4\x2d\xaf\x10\xdc\x5e\x0e\xe4\x39\x1b\xa3\xd0\x41\xbc\xde\xb5\x32\x70\xeb\x2c\x4f\x87\x01\xc0\x59\x90\xc5\x68\xb1\xc1\x72\x33\xc2\xcf\x8b\xcd\x72\x73\x95\x8d\xf1\x69\xb9\xfd\x75\xfd\x71\x8b\x4f\x8b\xa7\xa7\xc5\x6a\xbb\xbc\xdf\x60\xfd\x84\xdb\xf5\xea\x6e\xb9\x5d\xae\x57\x1b\xac\x7f\xc1\x62\xf5\x19\xbf\x2f\x57\x77\x57\x60\x2d\x15\x7b\xf0\x6b\xe3\xa3\x7e\xe7\xa1\xa3\x8f\x5c\x44\xd3\x36\xcc\x03\x01\x7b\x77\x12\x14\x1a\x56\x7a\xaf\x15\x0c\xd9\xb2\xa5\x92\x51\xba\x17\xf6\x56\xdb\x12\x0d\xfb\x5a\x87\x78\x9b\x01\x64\x8b\x6c\x0c\xa3\x6b\x2d\x24\x69\xe7\x8b\x43\x9d\x22\x72\xc7\x8d\x71\xc7\x9a\xad\xa4\x19\xa7\x04\xfd\x2f\xc0\x51\x2b\x15\x6a\x57\xb4\x86\xa7\xd8\x7a\xb2\x61\xef\x7c\x1d\x40\x08\xec\x5f\xb4\x62\x90\x52\xae\xb5\x12\xe2\x20\x67\xcb\x89\xd1\x2f\x5c\xe0\xb7\xcd\x7a\x85\x67\x3e\x46\xaf\x09\xa1\x72\x5e\xba\xca\x8e\xc9\x47\x09\xee\x99\x6d\xac\x0a\x99\xe7\xf4\xb7\x62\x7c\xb8\x7d\xcc\xc6\xd8\x79\xf7\xcc\x7e\x8a\x6d\xcc\xe1\xc4\x8e\x40\xbe\x8c\xde\x47\x84\xa5\x9a\x43\x43\x8a\x63\x4b\x60\xf2\xaa\x7a\x73\x85\x95\x67\xc9\xc6\x50\xce\xc6\x90\x45\x37\xe2\x7e\x4f\xd6\x3f\x44\x5f\x64\x9e\x86\x15\xbc\xa7\xd6\x44\x86\xb7\x31\x3a\x60\x54\x26\x3b\x26\xc9\x8c\x51\x84\xea\x80\x9d\xb6\xe4\x8f\x10\xaf\xf9\xa4\x4c\xce\xe6\x24\xa7\xb3\x71\x8a\x87\x31\x9d\xaa\x10\x13\x97\x9e\xcd\x85\x3a\x39\xff\xed\xcf\xff\x59\xdb\x22\xef\x5d\x60\x46\x8d\xee\x9e\x73\x0e\x7e\x15\xb6\x29\x0b\xb3\x97\xeb\x1d\x0b\x5d\x67\x35\x0b\x15\x24\x94\x67\x48\x5a\x72\xf4\x8f\xd7\x6d\x26\x81\x5f\x54\x0c\xed\xd8\x84\xd8\x88\xf8\xb8\xf2\xb3\x9d\x13\x45\x42\xc6\x95\x93\x01\x3e\x86\x34\x62\x3d\xa7\x67\x18\x72\x5c\x67\x40\x60\xc3\x4a\x9c\x3f\xb1\xd4\x24\xaa\x7a\xe8\xd1\x7e\x0d\x31\x20\x5c\x37\x86\x84\x3b\x92\xde\x89\xe2\x32\x03\xbe\xaf\x63\x04\xce\x72\xd3\xff\x27\xf0\xe2\x94\x92\x55\x72\x69\x98\x82\x0e\xd8\x25\x8d\xfd\x65\xdc\xa4\x33\xf5\x3c\xe7\x6d\x40\x5c\xba\xa6\x32\xfa\xaa\xfc\x54\xbb\x59\xa9\x9a\x49\x37\x2a\xcc\x06\x0d\x79\x3c\x5d\x90\x61\xdf\x63\x6b\xcc\xa3\x33\x5a\x1d\x73\x2c\xcc\x81\x8e\xe1\x52\xf7\x1c\x5c\xeb\x15\xf7\x4e\x8d\xf4\x2d\xe4\x20\x83\x3d\x40\x35\x6d\x8e\xeb\xf9\xbc\x1e\xec\xd6\x5c\x3b\x7f\xcc\x71\x33\xff\x43\xf7\x0a\xe9\xd3\xf1\x9f\x08\xde\xf5\x09\xc8\x97\xbd\xe6\x09\x26\xb6\xf7\xe3\x5f\x0d\x4d\xa8\x97\x3e\xea\xfb\x41\x89\x4c\x70\xc6\x95\xe2\x82\x14\xec\xfd\xa5\xd4\x38\x2f\x83\x51\x97\x9b\x79\x74\x5e\x72\xfc\xf8\xfe\xfd\xbb\xec\xef\x00\x00\x00\xff\xff\xb1\x37\xd6\x5d\x6d\x07\x00\x00")
+var _templatesGcpGoogleOauthDeploymentYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x54\xc1\x6e\xdb\x46\x10\xbd\xf3\x2b\x1e\xc4\x43\x5b\xc0\xa4\x64\x27\x68\x0b\xf6\xa4\xda\x6e\xca\xd6\x95\x0c\x4b\x69\x90\xe3\x68\x39\x22\x17\x5a\xee\x32\xbb\x4b\x2b\x44\x90\x7f\x2f\x96\xa2\x64\xb2\xe9\x21\x6d\xc5\x83\xc0\x9d\x99\x37\x6f\xde\xbe\x61\xfc\xbf\x7f\x51\x8c\x5b\xd3\x74\x56\x96\x95\xc7\xcd\xe2\xfa\x07\xbc\x31\xa6\x54\x8c\x5c\x8b\x34\x8a\xa3\x18\x0f\x52\xb0\x76\x5c\xa0\xd5\x05\x5b\xf8\x8a\xb1\x6c\x48\x54\x7c\x8e\x5c\xe1\x4f\xb6\x4e\x1a\x8d\x9b\x74\x81\x6f\x43\xc2\x6c\x08\xcd\xbe\xfb\x29\x8a\xd1\x99\x16\x35\x75\xd0\xc6\xa3\x75\x0c\x5f\x49\x87\xbd\x54\x0c\xfe\x28\xb8\xf1\x90\x1a\xc2\xd4\x8d\x92\xa4\x05\xe3\x28\x7d\x05\xff\x82\x9f\x46\x31\xde\x0f\x10\x66\xe7\x49\x6a\x10\x84\x69\x3a\x98\xfd\x38\x0f\xe4\x7b\xc2\x00\x50\x79\xdf\xb8\x6c\x3e\x3f\x1e\x8f\x29\xf5\x6c\x53\x63\xcb\xb9\x3a\x65\xba\xf9\x43\x7e\x7b\xbf\xda\xdc\x27\x37\xe9\xa2\xaf\x79\xab\x15\x3b\x07\xcb\x1f\x5a\x69\xb9\xc0\xae\x03\x35\x8d\x92\x82\x76\x8a\xa1\xe8\x08\x63\x41\xa5\x65\x2e\xe0\x4d\x20\x7c\xb4\xd2\x4b\x5d\x5e\xc1\x99\xbd\x3f\x92\xe5\x28\x46\x21\x9d\xb7\x72\xd7\xfa\x89\x5a\x67\x7a\xd2\x4d\x12\x8c\x06\x69\xcc\x96\x1b\xe4\x9b\x19\x7e\x5e\x6e\xf2\xcd\x55\x14\xe3\x5d\xbe\xfd\x75\xfd\x76\x8b\x77\xcb\xa7\xa7\xe5\x6a\x9b\xdf\x6f\xb0\x7e\xc2\xed\x7a\x75\x97\x6f\xf3\xf5\x6a\x83\xf5\x2f\x58\xae\xde\xe3\xf7\x7c\x75\x77\x05\x96\xbe\x62\x0b\xfe\xd8\xd8\xc0\xdf\x58\xc8\xa0\x23\x17\x41\xb4\x0d\xf3\x84\xc0\xde\x9c\xae\xcf\x35\x2c\xe4\x5e\x0a\x28\xd2\x65\x4b\x25\xa3\x34\xcf\x6c\xb5\xd4\x25\x1a\xb6\xb5\x74\xe1\x36\x1d\x48\x17\x51\x0c\x25\x6b\xe9\xc9\xf7\x27\x5f\x0c\x75\xb2\xc8\x1d\x37\xca\x74\x35\x6b\xdf\xf7\x38\x39\xe8\x1b\x07\x43\xad\xaf\x50\x9b\xa2\x55\x9c\x62\x6b\x49\xbb\xbd\xb1\xb5\x03\xc1\xb1\x7d\x96\x82\x41\x42\x98\x56\x7b\x17\x1a\x19\x5d\x26\x4a\x3e\x73\x81\xdf\x36\xeb\x15\x0e\xdc\x05\xad\x09\xae\x32\xd6\x0f\x91\x1d\x93\x0d\xba\x9a\x03\xeb\x10\xf5\xa4\x0e\xfd\x7f\xc5\x78\x73\xfb\x18\xc5\xd8\x59\x73\x60\x9b\x62\x1b\x7c\x98\xe8\x19\xc8\x96\x90\xae\x9f\x5c\x53\xcd\xae\x21\xc1\xa1\xc4\x31\x59\x51\xbd\xa8\xc2\xc2\xb2\x8f\x62\x08\xa3\x83\xc9\x82\x1a\xa1\x66\x44\xeb\x6f\xa4\x2f\x34\x4f\xcd\x0a\xde\x53\xab\x02\xc2\x4b\x1b\xe9\x30\x2b\x7b\x39\x92\x5e\x8c\x59\x48\x95\x0e\x3b\xa9\xc9\x76\xf0\x56\xf2\x89\x99\x3f\x8b\xd3\x2b\x1d\xc5\xbd\x3d\x94\x1a\x58\xb9\xe0\xb8\x7e\x6d\x2e\xd0\xbd\xf2\xe7\x2d\xfe\xcf\xbf\xe8\x20\x75\x91\x8d\x2e\x30\xa2\x46\x0e\xeb\x9c\xe1\xd3\x27\xa4\xcb\xc7\x7c\x78\x77\xe9\xe8\x9e\x3f\x7f\x8e\x6a\xf6\x54\x90\xa7\x2c\x42\x4f\x2b\xc3\x78\xd2\xe1\xb0\xe7\xfa\x45\x44\xd1\x8e\x95\x0b\x85\x08\x7b\x96\x9d\xed\x90\x08\xf2\xa4\x4c\x99\x4c\xf2\x83\x5f\x43\xae\xe5\x7e\x23\x5d\x86\xeb\x08\x70\xac\x58\x78\x63\x43\x04\xa8\xc9\x8b\xea\x61\x04\xfb\x35\xc0\x80\xe7\xba\x51\xe4\x79\x00\x19\x4d\x04\x4c\x69\x7e\x2d\x22\x70\xa6\x1b\x9e\x21\x79\x79\x72\xf9\xaa\x57\x69\x6a\x88\x21\x71\x30\x1d\xdb\x4b\xbb\x64\x10\xf5\xdc\xe7\xa5\x41\x78\x64\x4d\x25\x67\x28\x85\x4d\xa5\x99\x97\xa2\x49\x86\x56\x6e\x3e\x29\xc8\xc2\x74\xce\x4f\xeb\x1e\x5b\xa5\x1e\x8d\x92\xa2\xcb\xb0\x54\x47\xea\xdc\x25\x6e\xd9\x99\xd6\x0a\x1e\x4d\x1d\x84\xff\xd0\xb2\xf3\x93\x33\x40\x34\x6d\x86\xeb\xc5\xa2\x9e\x9c\xd6\x5c\x1b\xdb\x65\xb8\x59\xfc\x21\x47\x81\xfe\x2b\xf2\xaf\x00\x5e\x8d\x01\xc8\x96\xa3\xe2\x04\x89\x1e\xbd\xfc\xa3\xa0\x40\x82\xe4\x79\x9c\xf5\xfd\x24\x44\xca\x19\x65\x4a\x6f\x9c\x2f\xd8\xda\x4b\xa8\x31\x76\x3c\x68\x72\xfe\x1c\xb0\x7d\x34\xd6\x67\xf8\xf1\xf5\xeb\x57\xd1\x5f\x03\x00\x74\x64\x86\xfb\x78\x07\x00\x00")
 
 func templatesGcpGoogleOauthDeploymentYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -488,12 +830,12 @@ func templatesGcpGoogleOauthDeploymentYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/gcp/google-oauth-deployment.yaml.tmpl", size: 1901, mode: os.FileMode(416), modTime: time.Unix(1525803557, 0)}
+	info := bindataFileInfo{name: "templates/gcp/google-oauth-deployment.yaml.tmpl", size: 1912, mode: os.FileMode(420), modTime: time.Unix(1791998600, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesGcpGoogleOauthRbacYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x53\x4d\x8f\xdb\x36\x10\xbd\xeb\x57\x3c\x48\x97\x16\xf0\xca\xbb\x7b\x69\xa1\x9e\x9c\xdd\x6d\x2a\x74\x61\x03\x96\xd3\x20\x08\x72\xa0\xa8\xb1\x34\x0d\x4d\xaa\xe4\xc8\x8a\xfb\xeb\x0b\xd1\x32\xe0\x6d\x8a\x7e\x60\xcb\x13\xc1\x19\xbe\xf7\xf8\xe6\x31\x7b\xf5\x4a\x32\x3c\xb8\xfe\xe4\xb9\xed\x04\xf7\xb7\x77\xdf\xe1\xad\x73\xad\x21\x94\x56\xe7\xc9\x54\x7e\x66\x4d\x36\x50\x83\xc1\x36\xe4\x21\x1d\x61\xd5\x2b\xdd\xd1\xa5\xb2\xc0\x2f\xe4\x03\x3b\x8b\xfb\xfc\x16\xdf\x4c\x0d\xe9\x5c\x4a\xbf\xfd\x21\xc9\x70\x72\x03\x0e\xea\x04\xeb\x04\x43\x20\x48\xc7\x01\x7b\x36\x04\xfa\xa2\xa9\x17\xb0\x85\x76\x87\xde\xb0\xb2\x9a\x30\xb2\x74\x91\x66\x06\xc9\x93\x0c\x1f\x66\x08\x57\x8b\x62\x0b\x05\xed\xfa\x13\xdc\xfe\xba\x0f\x4a\xa2\xe0\x69\x75\x22\x7d\x28\x96\xcb\x71\x1c\x73\x15\xd5\xe6\xce\xb7\x4b\x73\xee\x0c\xcb\xe7\xf2\xe1\x69\x5d\x3d\xdd\xdc\xe7\xb7\xf1\xce\x3b\x6b\x28\x04\x78\xfa\x6d\x60\x4f\x0d\xea\x13\x54\xdf\x1b\xd6\xaa\x36\x04\xa3\x46\x38\x0f\xd5\x7a\xa2\x06\xe2\x26\xc1\xa3\x67\x61\xdb\x2e\x10\xdc\x5e\x46\xe5\x29\xc9\xd0\x70\x10\xcf\xf5\x20\x2f\xdc\xba\xc8\xe3\xf0\xa2\xc1\x59\x28\x8b\x74\x55\xa1\xac\x52\xbc\x59\x55\x65\xb5\x48\x32\xbc\x2f\x77\x3f\x6d\xde\xed\xf0\x7e\xb5\xdd\xae\xd6\xbb\xf2\xa9\xc2\x66\x8b\x87\xcd\xfa\xb1\xdc\x95\x9b\x75\x85\xcd\x8f\x58\xad\x3f\xe0\xe7\x72\xfd\xb8\x00\xb1\x74\xe4\x41\x5f\x7a\x3f\xe9\x77\x1e\x3c\xf9\x48\xcd\x64\x5a\x45\xf4\x42\xc0\xde\x9d\x05\x85\x9e\x34\xef\x59\xc3\x28\xdb\x0e\xaa\x25\xb4\xee\x48\xde\xb2\x6d\xd1\x93\x3f\x70\x98\xa6\x19\xa0\x6c\x93\x64\x30\x7c\x60\x51\x12\x4f\xbe\x7a\x54\x8c\xc8\xeb\x43\xa8\x7a\x9e\x33\x54\xe0\x78\x97\x7c\x66\xdb\x14\x78\xe6\x20\x09\x0b\x1d\x42\x91\xdc\xe0\xba\xc5\xd7\x4a\xe7\x6a\x90\xce\x79\xfe\x3d\x4a\xcb\x3f\x7f\x1f\x72\x76\xcb\xe3\x5d\x4d\xa2\xee\x12\xe0\x8c\xf1\x60\x86\x20\xe4\xb7\xce\x50\x02\x1c\x48\x54\xa3\x44\x15\xc9\x94\x11\xab\x0e\x54\x20\x0d\xe4\x8f\xac\x49\x2b\x51\xc6\xb5\x33\x50\xd1\xc6\x5f\x70\xe3\x26\x96\x34\x01\xfc\x60\x28\x4c\xf7\x32\xec\x36\x8f\x9b\x02\x8d\x8b\x79\x6e\xbd\xb2\x82\xd6\xb8\x5a\x19\x28\xad\x29\x84\x04\x88\x72\xdf\x7a\x37\xf4\xa1\xc0\xc7\x34\xfd\x14\x09\x3d\x05\x37\x78\x4d\xf1\x2c\x90\xf6\x24\x61\x2e\x1d\xc9\xd7\xa1\x88\xd1\xfd\x98\xb6\x24\xe9\x22\x35\x1c\x24\x5d\x20\x1d\x95\xe8\x6e\xda\x68\x4f\x4a\x68\xda\x0d\x7d\x33\xef\xfa\x4b\xb1\x21\x43\x42\x11\xed\x5f\x90\x4f\x4f\x0f\xbd\xd2\xf4\x8f\xfc\x33\xfd\xa7\x24\x43\xcb\xc7\x73\x9e\xae\xbd\xc1\x6c\xdf\xf4\x76\x37\x58\x99\x3d\x98\x3e\xc9\xd8\x29\x09\x68\x68\xcf\x96\x9a\xe9\xcb\xb0\x04\x78\x67\x28\xff\x3f\xc6\xf9\x86\x6d\xc3\xb6\x7d\xe5\x54\x9d\xa1\x2d\xed\xcf\x37\x2f\xae\xfd\x8d\xa0\xd8\xf7\x57\xc9\xfa\x8f\xbc\x61\xa8\x7f\x25\x2d\x31\x50\x37\x57\xc4\x69\x7a\xc5\x50\x9d\xa1\x56\x67\x63\xaf\x49\xfe\x0c\x77\x2e\xc4\x81\x7e\x55\xfd\x23\x00\x00\xff\xff\xaa\xf6\x78\x7e\x23\x06\x00\x00")
+var _templatesGcpGoogleOauthRbacYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x53\x4d\x8f\xdb\x36\x10\xbd\xf3\x57\x3c\x48\x97\x16\xf0\x6a\x37\x7b\x69\xa1\x9e\xb4\x1f\x4d\x85\x2e\xec\xc2\x72\x1a\x04\xc1\x1e\x68\x6a\x2c\x4d\x43\x93\x2a\x39\xb2\xe2\x06\xf9\xef\x85\x64\x6d\xeb\x6d\x0a\xb4\x49\x74\xa2\x38\x8f\xf3\x86\xef\x3d\xa6\x5f\xfd\xa9\x14\xb7\xbe\x3b\x06\x6e\x5a\xc1\xf5\xd5\x8b\xef\xf0\xd2\xfb\xc6\x12\x4a\x67\x32\x95\xaa\x14\x0f\x6c\xc8\x45\xaa\xd1\xbb\x9a\x02\xa4\x25\x14\x9d\x36\x2d\x3d\x55\x16\xf8\x95\x42\x64\xef\x70\x9d\x5d\xe1\x9b\x11\x90\xcc\xa5\xe4\xdb\x1f\x54\x8a\xa3\xef\xb1\xd7\x47\x38\x2f\xe8\x23\x41\x5a\x8e\xd8\xb1\x25\xd0\x7b\x43\x9d\x80\x1d\x8c\xdf\x77\x96\xb5\x33\x84\x81\xa5\x85\xfc\xdd\x3f\x53\x29\xde\xcc\x2d\xfc\x56\x34\x3b\x68\x18\xdf\x1d\xe1\x77\xe7\x38\x68\x99\x06\x06\x80\x56\xa4\x8b\xf9\xe5\xe5\x30\x0c\x99\x9e\xa6\xcd\x7c\x68\x2e\xed\x09\x19\x2f\x1f\xca\xdb\xfb\x65\x75\x7f\x71\x9d\x5d\x4d\x67\x5e\x39\x4b\x31\x22\xd0\xef\x3d\x07\xaa\xb1\x3d\x42\x77\x9d\x65\xa3\xb7\x96\x60\xf5\x00\x1f\xa0\x9b\x40\x54\x43\xfc\x38\xf0\x10\x58\xd8\x35\x0b\x44\xbf\x93\x41\x07\x52\x29\x6a\x8e\x12\x78\xdb\xcb\x33\xb5\x9e\xc6\xe3\xf8\x0c\xe0\x1d\xb4\x43\x52\x54\x28\xab\x04\x37\x45\x55\x56\x0b\x95\xe2\x75\xb9\xf9\x69\xf5\x6a\x83\xd7\xc5\x7a\x5d\x2c\x37\xe5\x7d\x85\xd5\x1a\xb7\xab\xe5\x5d\xb9\x29\x57\xcb\x0a\xab\x1f\x51\x2c\xdf\xe0\xe7\x72\x79\xb7\x00\xb1\xb4\x14\x40\xef\xbb\x30\xce\xef\x03\x78\xd4\x91\xea\x51\xb4\x8a\xe8\xd9\x00\x3b\x7f\xb2\x2f\x76\x64\x78\xc7\x06\x56\xbb\xa6\xd7\x0d\xa1\xf1\x07\x0a\x8e\x5d\x83\x8e\xc2\x9e\xe3\xe8\x66\x84\x76\xb5\x4a\x61\x79\xcf\xa2\x65\xda\xf9\xe4\x52\x53\x44\x9e\xb2\xf4\xc5\x9f\xd2\x1d\xcf\x19\xca\x71\x78\xa1\xde\xb1\xab\x73\x3c\x70\x14\xc5\x42\xfb\x98\xab\x0b\x9c\x43\x3e\x7c\x40\x56\xfc\x52\xce\xff\x31\x5b\xdf\x14\xb7\xf8\xf8\x51\x01\xa7\x93\xb7\xb6\x8f\x42\x61\xed\x2d\x29\x60\x4f\xa2\x6b\x2d\x3a\x57\x63\x32\x9c\xde\x53\x8e\x24\x52\x38\xb0\x21\xa3\x45\x5b\xdf\x64\xef\xbe\x8f\x19\xfb\xbc\x99\xb2\x7f\xe1\x75\x2f\x6d\xa2\x80\xd0\x5b\x8a\xe3\xb9\x14\x9b\xd5\xdd\x2a\x47\xed\xa7\x14\x37\x41\x3b\x41\x63\xfd\x56\x5b\x68\x63\x28\x46\x05\x4c\x43\xbe\x0c\xbe\xef\x62\x8e\xb7\x49\xf2\x38\x11\x06\x8a\xbe\x0f\x86\xa6\xbd\x48\x26\x90\xc4\xb9\x74\xa0\xb0\x8d\xf9\x08\xc2\xdb\xa4\x21\x49\x16\x89\xe5\x28\xc9\x02\xc9\xa0\xc5\xb4\xe3\xc2\x04\xd2\x42\xe3\xaa\xef\xea\x79\xd5\x3d\x15\x6b\xb2\x24\x94\x3c\xfe\x3f\xf2\xf1\xea\xb1\xd3\x86\xfe\x93\x7f\xa6\x7f\x54\x29\x1a\x3e\x9c\x52\x74\xae\x0d\x66\xf9\xc6\xbb\xfb\xde\xc9\xac\xc1\xf8\x34\x86\x56\x4b\x44\x4d\x3b\x76\x54\x8f\x0f\x85\x25\x22\x78\x4b\xd9\x97\x9b\x78\xc3\xae\x66\xd7\x7c\xa5\x97\xde\xd2\x9a\x76\xa3\x9b\xf8\x4b\xab\x1c\x61\xab\x4d\x36\x62\x7c\xe0\x3f\xa6\x98\xcf\x0d\x26\xdc\xbf\xe5\xe9\x33\x79\x63\xbf\xfd\x8d\x8c\xc4\xfc\x99\x49\x39\x92\xe4\x8c\xa1\x3a\xb5\x2a\x4e\x72\x9e\x93\xfc\xb3\xdd\x89\x7d\xb2\xf1\x93\xea\x9f\x03\x00\x6e\x5c\xea\x7c\x0f\x06\x00\x00")
 
 func templatesGcpGoogleOauthRbacYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -508,7 +850,7 @@ func templatesGcpGoogleOauthRbacYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/gcp/google-oauth-rbac.yaml.tmpl", size: 1571, mode: os.FileMode(416), modTime: time.Unix(1525803557, 0)}
+	info := bindataFileInfo{name: "templates/gcp/google-oauth-rbac.yaml.tmpl", size: 1551, mode: os.FileMode(420), modTime: time.Unix(1791998600, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -573,7 +915,7 @@ func templatesGcpServiceAccountSecretYamlTmpl() (*asset, error) {
 	return a, nil
 }
 
-var _templatesGcpDeprecatedGoogleOauthDeploymentYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x54\x41\x73\xdb\x36\x13\xbd\xf3\x57\xbc\x91\x0e\xdf\xd7\x19\x53\x92\x9d\x4c\xdb\x61\x4f\xaa\xed\xa6\x6a\x5d\xc9\x63\x29\xcd\xe4\xb8\x02\x57\x24\xc6\x20\xc0\x2e\x40\xcb\xfc\xf7\x1d\x50\x94\x4c\x26\x97\x74\x82\x8b\x2d\xec\xdb\xb7\x0f\x0f\x0f\x9c\x7e\xf7\x4a\xa6\xb8\x75\x75\x2b\xba\x28\x03\x6e\x16\xd7\x3f\xe1\x83\x73\x85\x61\xac\xac\x9a\x25\xb1\xfc\xa0\x15\x5b\xcf\x39\x1a\x9b\xb3\x20\x94\x8c\x65\x4d\xaa\xe4\x73\xe5\x0a\x7f\xb3\x78\xed\x2c\x6e\x66\x0b\xfc\x3f\x02\x26\x7d\x69\xf2\xc3\x2f\xc9\x14\xad\x6b\x50\x51\x0b\xeb\x02\x1a\xcf\x08\xa5\xf6\x38\x68\xc3\xe0\x57\xc5\x75\x80\xb6\x50\xae\xaa\x8d\x26\xab\x18\x47\x1d\xca\x6e\x4c\x4f\x32\x4b\xa6\xf8\xdc\x53\xb8\x7d\x20\x6d\x41\x50\xae\x6e\xe1\x0e\x43\x1c\x28\x74\x82\xe3\x2a\x43\xa8\x7d\x36\x9f\x1f\x8f\xc7\x19\x75\x6a\x67\x4e\x8a\xb9\x39\x21\xfd\xfc\x61\x75\x7b\xbf\xde\xde\xa7\x37\xb3\x45\xd7\xf3\xd1\x1a\xf6\x1e\xc2\xff\x34\x5a\x38\xc7\xbe\x05\xd5\xb5\xd1\x8a\xf6\x86\x61\xe8\x08\x27\xa0\x42\x98\x73\x04\x17\x05\x1f\x45\x07\x6d\x8b\x2b\x78\x77\x08\x47\x12\x4e\xa6\xc8\xb5\x0f\xa2\xf7\x4d\x18\xb9\x75\x96\xa7\xfd\x08\xe0\x2c\xc8\x62\xb2\xdc\x62\xb5\x9d\xe0\xd7\xe5\x76\xb5\xbd\x4a\xa6\xf8\xb4\xda\xfd\xbe\xf9\xb8\xc3\xa7\xe5\xd3\xd3\x72\xbd\x5b\xdd\x6f\xb1\x79\xc2\xed\x66\x7d\xb7\xda\xad\x36\xeb\x2d\x36\xbf\x61\xb9\xfe\x8c\x3f\x57\xeb\xbb\x2b\xb0\x0e\x25\x0b\xf8\xb5\x96\xa8\xdf\x09\x74\xf4\x91\xf3\x68\xda\x96\x79\x24\xe0\xe0\x4e\x82\x7c\xcd\x4a\x1f\xb4\x82\x21\x5b\x34\x54\x30\x0a\xf7\xc2\x62\xb5\x2d\x50\xb3\x54\xda\xc7\xdb\xf4\x20\x9b\x27\x53\x18\x5d\xe9\x40\xa1\xdb\xf9\xea\x50\xa7\x88\xdc\x71\x6d\x5c\x5b\xb1\x0d\xdd\x8c\x53\x82\xfe\xe7\xe1\xa8\x09\x25\x2a\x97\x37\x86\x67\xd8\x09\x59\x7f\x70\x52\x79\x10\x3c\xcb\x8b\x56\x0c\x52\xca\x35\x36\xf8\x38\xc8\xd9\x22\x35\xfa\x85\x73\xfc\xb1\xdd\xac\xf1\xcc\x6d\xf4\x9a\xe0\x4b\x27\xa1\xaf\xec\x99\x24\x4a\x70\xcf\x6c\x63\x35\x90\x79\xee\xfe\x96\x8c\x0f\xb7\x8f\xc9\x14\x7b\x71\xcf\x2c\x33\xec\x62\x0e\x53\x3b\x01\x49\x11\xbd\x8f\x08\x4b\x15\xfb\x9a\x14\xc7\x16\xcf\x24\xaa\x7c\x73\x85\x95\x70\x48\xa6\x50\xce\xc6\x90\x45\x37\xe2\xfe\x40\xd6\x17\xa2\x2f\x32\x4f\xc3\x72\x3e\x50\x63\x22\xc3\xdb\x18\xed\x31\x29\x3a\x3b\xd2\xce\x8c\x49\x84\x6a\x8f\xbd\xb6\x24\x2d\x82\x68\x3e\x29\x0b\x67\x73\x3a\xa7\x93\x69\x17\x0f\x63\x7a\x55\x3e\x26\xae\x7b\x36\x17\xea\xce\xf9\xef\x7f\xfe\xcf\xda\xe6\xd9\xe0\x02\x13\xaa\x75\xff\x9c\x33\xf0\x6b\x60\xdb\x65\x61\xfe\x72\xbd\xe7\x40\xd7\x49\xc5\x81\x72\x0a\x94\x25\xe8\xb4\x64\x18\x1e\xaf\xdf\xec\x04\x66\x67\xbb\x52\x45\x81\x8c\x2b\x12\xc0\xd0\x9e\x8d\x8f\xbd\x88\xef\xeb\x2b\x48\x3a\x22\x8b\x39\x8d\x58\xe1\xee\x25\xfa\x0c\xd7\x09\xe0\xd9\xb0\x0a\x4e\x4e\x2c\x15\x05\x55\x3e\x0c\x68\xbf\x85\x18\x08\x5c\xd5\x86\x02\xf7\x24\x83\x43\xc5\x65\x46\x7c\xdf\xc6\x08\x9c\xe5\x76\xff\x9f\xc0\xcb\x53\x50\xd6\x9d\x51\x93\x18\x2c\x71\xc6\xb0\xa4\x15\x59\x2a\x58\x26\x3d\xbc\x8f\x1c\xcb\x65\x68\xda\xbb\x7b\x9e\xf6\x36\x26\x2e\x5d\x51\x11\xad\x57\x32\xd3\x6e\x5e\xa8\x3a\xed\x07\xfa\xf9\xa8\x21\x8b\x67\xf4\x61\xdc\xf7\xd8\x18\xf3\xe8\x8c\x56\x6d\x86\xa5\x39\x52\xeb\x2f\x75\x61\xef\x1a\x51\x3c\x38\x3b\xba\x8f\x22\xfb\x30\xda\x03\x54\xdd\x64\xb8\x5e\x2c\xaa\xd1\x6e\xc5\x95\x93\x36\xc3\xcd\xe2\x2f\x3d\x28\x74\xdf\x90\xff\x44\xf0\x6e\x48\x40\x52\x0c\x9a\x53\xa4\x76\xf0\x63\xf2\xc5\xc5\x4c\x86\xc0\x97\x21\xf0\xc7\x51\x89\x8c\x77\xc6\x15\xc1\xf9\x90\xb3\xc8\xa5\x54\x3b\x09\xa3\x69\x97\xcb\x79\x74\x12\x32\xfc\xfc\xfe\xfd\xbb\xe4\xdf\x00\x00\x00\xff\xff\x2a\xdf\x9e\x4c\x79\x07\x00\x00")
+var _templatesGcpDeprecatedGoogleOauthDeploymentYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x54\xc1\x6e\xdb\x38\x10\xbd\xfb\x2b\x06\xce\x61\x77\x81\xc8\x76\xd3\xa2\x2d\xd4\x93\x37\xc9\x76\xb5\xcd\xca\x46\xec\xb6\xe8\x91\x96\xc6\x32\x11\x8a\xd4\x92\x54\x54\xa3\xe8\xbf\xef\xa3\x24\x3b\x52\x7b\x69\x51\x5e\x04\x71\x86\x6f\xde\xbc\x79\xe4\xc5\xc5\xaf\xae\xc9\x05\x5d\x9b\xea\x68\x65\x71\xf0\x74\xb5\x78\xf6\x8a\xde\x1a\x53\x28\xa6\x44\x67\xb3\x49\x08\xdf\xc9\x8c\xb5\xe3\x9c\x6a\x9d\xb3\x25\x7f\x60\x5a\x56\x22\xc3\xa7\x8f\x5c\xd2\x07\xb6\x4e\x1a\x4d\x57\xb3\x05\xfd\x1e\x12\xa6\x7d\x68\xfa\xc7\x1b\x20\x1c\x4d\x4d\xa5\x38\x92\x36\x9e\x6a\xc7\x80\x90\x8e\xf6\x12\x45\xf8\x73\xc6\x95\x27\xa9\x29\x33\x65\xa5\xa4\xd0\x19\x53\x23\xfd\xa1\x2d\xd3\x83\x80\x06\x7d\xea\x21\xcc\xce\x0b\x64\x0b\xe4\x57\xf8\xdb\x0f\xf3\x48\xf8\x96\x70\x58\x07\xef\x2b\x17\xcf\xe7\x4d\xd3\xcc\x44\xcb\x76\x66\x6c\x31\x57\x5d\xa6\x9b\xdf\x25\xd7\xb7\xe9\xe6\x36\x02\xe3\xf6\xcc\x7b\xad\xd8\x39\xb2\xfc\x5f\x2d\x2d\x7a\xdd\x1d\x49\x54\x20\x94\x89\x1d\x68\x2a\xd1\x90\xb1\x24\x0a\xcb\x88\x79\x13\x08\x37\x56\x7a\xa9\x8b\x4b\x72\x66\xef\x1b\x61\x19\x28\xb9\x74\xde\xca\x5d\xed\x47\x6a\x9d\xe8\xa1\xe9\x61\x02\xf4\x12\x9a\xa6\xcb\x0d\x25\x9b\x29\xfd\xb9\xdc\x24\x9b\x4b\x60\x7c\x4c\xb6\x7f\xaf\xde\x6f\xe9\xe3\xf2\xfe\x7e\x99\x6e\x93\xdb\x0d\xad\xee\xe9\x7a\x95\xde\x24\xdb\x64\x95\xe2\xef\x2f\x5a\xa6\x9f\xe8\x5d\x92\xde\x5c\x12\x43\x2b\x94\xe1\xcf\x95\x0d\xfc\x41\x52\x06\x1d\x39\x0f\xa2\x6d\x98\x47\x04\xf6\xa6\x23\xe4\x2a\xce\xe4\x5e\x66\xe8\x4b\x17\xb5\x28\x98\x0a\xf3\xc8\x56\xa3\x1d\xaa\xd8\x96\xd2\x85\x69\x3a\xd0\xcb\x81\xa2\x64\x29\xbd\xf0\xed\xce\x77\x4d\x75\x16\xb9\xe1\x4a\x99\x63\xc9\xda\xb7\x35\x3a\x07\xfd\x06\x3a\xa2\xc6\x28\x4b\x93\xd7\x8a\x67\xb4\xb5\x42\x3b\xc4\x4b\x20\x93\x63\xfb\x08\x08\x12\x59\x66\x6a\xed\x5d\x28\x64\x74\x11\x29\xf9\x08\x6d\xfe\xd9\xac\x52\x7a\xe0\x63\xd0\x1a\xb9\x07\x63\x7d\x1f\xd9\x31\xa4\x06\x05\xf3\xc0\x3a\x44\xbd\x50\x0f\xed\x17\x9c\xde\x5e\xaf\x01\xb3\xb3\x88\x59\x94\x0b\x3e\x8c\xf4\x94\x84\x2d\x82\xf6\x21\x43\x8b\x92\x1d\xec\xc0\xe1\x88\x03\x54\x76\x78\x52\x85\x33\xcb\x70\x10\xac\xa5\x83\xc9\x82\x1a\x61\x7f\x40\xeb\x1b\xd2\x67\x9a\x5d\xb1\x9c\xf7\xa2\x56\x01\xe1\xa9\x0c\xea\x4e\x8b\x56\x8e\xa8\x15\x63\x1a\x52\xb1\xb9\x93\x5a\x58\xb4\x67\x25\x77\xcc\xfc\x49\x9c\x56\x69\x60\x04\x7b\x28\xd5\xb3\x72\xc1\x71\xed\xb5\x39\x43\xb7\xca\xff\xfa\xf5\x7f\x90\x3a\x8f\x07\x03\x9c\x88\x4a\xf6\xd7\x39\xa6\x2f\x5f\x68\xb6\x5c\x27\xfd\xbf\x9b\x0d\xe6\xfc\xf5\xeb\xa4\x64\x2f\x72\xe1\x45\x3c\xa1\x96\x56\x4c\xc3\x4e\xfb\xcd\x96\x6b\x87\x94\x9e\x55\xc1\x61\x82\xf9\x76\xac\x5c\x38\x4c\xe1\xae\xc5\x27\x75\xa3\x0c\x98\xca\x14\xd1\x08\x2d\x78\x36\xe4\x5a\x6e\x6f\xa5\x8b\xe9\x19\xfe\x1c\x2b\xce\xbc\xb1\x1d\x0a\xb4\xcb\x0e\x77\x03\xd8\x1f\x01\x26\xf2\x8c\x2b\x23\x3c\xf7\x20\x83\xae\xc2\x52\x23\xbc\x1f\x43\x24\x3a\xd1\x0d\xeb\x82\x52\x3c\x7c\x61\xc8\xc1\x59\xd6\x28\x05\x03\x97\x42\xe3\xe2\x59\x8c\x19\xef\x67\x73\x90\x30\xe2\xc1\xa8\xbc\x33\x03\x72\x60\x8b\xee\x75\x3b\x83\x7c\x7f\x78\xd6\xc7\x7a\x36\xcb\xce\x95\x69\x3b\x8a\xb1\xeb\xfa\xc4\xde\xd9\x18\xe7\x89\x5b\xd4\x4f\xee\xd4\xc8\x53\x07\x61\xc9\x12\x65\x30\xd6\xcc\xce\xa4\x99\x17\x59\x15\xf5\xa5\xdc\x7c\x74\x20\x0e\xf2\x39\x3f\x3e\xb7\xae\x95\x5a\x1b\x0c\xeb\x18\xd3\x52\x35\xe2\xe8\xce\x71\xbc\x55\xa6\xb6\x80\x79\x92\x95\xda\xb7\x17\x20\xa3\x3d\x50\xae\x6a\x8c\x7a\xb1\x28\x47\xbb\x25\x97\xc6\x02\xf7\x6a\xf1\xaf\x1c\x04\xda\xa7\xea\xa7\x00\x9e\x0f\x01\xf0\x50\x0c\x0e\x47\x14\xe9\xc1\xcf\xf4\x5b\x0b\x4f\x87\x99\x8f\xc3\xcc\x97\xa3\x90\x50\xce\x40\x29\x6f\x9c\xc7\xe3\x69\xcf\xa1\x0a\x8f\xda\xa8\xdc\x79\x3a\x6b\x44\x62\x7a\xfd\xe2\xc5\xf3\xc9\xff\x75\x68\x09\xba\xe1\x07\x00\x00")
 
 func templatesGcpDeprecatedGoogleOauthDeploymentYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -588,12 +930,12 @@ func templatesGcpDeprecatedGoogleOauthDeploymentYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/gcp-deprecated/google-oauth-deployment.yaml.tmpl", size: 1913, mode: os.FileMode(420), modTime: time.Unix(1525803557, 0)}
+	info := bindataFileInfo{name: "templates/gcp-deprecated/google-oauth-deployment.yaml.tmpl", size: 2017, mode: os.FileMode(420), modTime: time.Unix(1792032966, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _templatesGcpDeprecatedServiceAccountSecretYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x54\xc1\x72\xdb\x36\x10\xbd\xf3\x2b\xde\x48\x97\x76\xc6\x92\x6c\xb9\x69\x27\x6a\x92\x19\xd9\x71\x1d\x39\x1a\xaa\x23\x3a\xb6\x94\x4e\x0f\x20\xb0\x22\x61\x81\x00\x03\x80\x62\x98\x34\xff\xde\x01\x49\x37\x56\x33\x39\x05\x27\x89\xfb\x76\xf1\xf6\xbd\x5d\x0c\x7f\xf8\x44\x43\x5c\x9a\xb2\xb1\x32\xcb\x3d\xa6\xa7\x67\xbf\xe1\xda\x98\x4c\x11\x16\x9a\x8f\xa3\x10\x5e\x4a\x4e\xda\x91\x40\xa5\x05\x59\xf8\x9c\x30\x2f\x19\xcf\xe9\x31\x72\x82\x3b\xb2\x4e\x1a\x8d\xe9\xf8\x14\x3f\x05\xc0\xa0\x0f\x0d\x7e\xfe\x3d\x1a\xa2\x31\x15\x0a\xd6\x40\x1b\x8f\xca\x11\x7c\x2e\x1d\x76\x52\x11\xe8\x23\xa7\xd2\x43\x6a\x70\x53\x94\x4a\x32\xcd\x09\xb5\xf4\x79\x7b\x4d\x5f\x64\x1c\x0d\xb1\xed\x4b\x98\xd4\x33\xa9\xc1\xc0\x4d\xd9\xc0\xec\x9e\xe2\xc0\x7c\x4b\x38\x9c\xdc\xfb\xd2\xcd\x26\x93\xba\xae\xc7\xac\x65\x3b\x36\x36\x9b\xa8\x0e\xe9\x26\xcb\xc5\xe5\x55\x9c\x5c\x8d\xa6\xe3\xd3\x36\xe7\x9d\x56\xe4\x1c\x2c\x7d\xa8\xa4\x25\x81\xb4\x01\x2b\x4b\x25\x39\x4b\x15\x41\xb1\x1a\xc6\x82\x65\x96\x48\xc0\x9b\x40\xb8\xb6\xd2\x4b\x9d\x9d\xc0\x99\x9d\xaf\x99\xa5\x68\x08\x21\x9d\xb7\x32\xad\xfc\x91\x5a\x8f\xf4\xa4\x3b\x02\x18\x0d\xa6\x31\x98\x27\x58\x24\x03\x5c\xcc\x93\x45\x72\x12\x0d\x71\xbf\xb8\x7d\xb3\x7a\x77\x8b\xfb\xf9\x7a\x3d\x8f\x6f\x17\x57\x09\x56\x6b\x5c\xae\xe2\xd7\x8b\xdb\xc5\x2a\x4e\xb0\xfa\x03\xf3\x78\x8b\xb7\x8b\xf8\xf5\x09\x48\xfa\x9c\x2c\xe8\x63\x69\x03\x7f\x63\x21\x83\x8e\x24\x82\x68\x09\xd1\x11\x81\x9d\xe9\x08\xb9\x92\xb8\xdc\x49\x0e\xc5\x74\x56\xb1\x8c\x90\x99\x03\x59\x2d\x75\x86\x92\x6c\x21\x5d\x70\xd3\x81\x69\x11\x0d\xa1\x64\x21\x3d\xf3\xed\x97\x6f\x9a\xea\x46\x24\x21\x6e\xc9\xc3\x79\x63\x43\x0d\x65\x74\x36\x52\xf2\x40\x02\xd7\x97\x7f\xc2\x91\x3d\x48\x4e\x60\x9c\x9b\x4a\x7b\x3c\x38\xa3\xb1\xa7\x66\x8c\xdb\x5c\xba\x70\xc3\x57\xfc\x9e\x1a\xd4\x52\x29\xa4\x04\x6e\xb4\x97\xba\x62\x4a\x35\xf0\x96\x69\xb7\x33\xb6\xe8\xf4\x67\x70\xb9\xb1\xbe\x4b\x8a\x86\x48\x89\xd9\x40\xcc\xec\x49\xb7\xde\x69\xd3\xea\x92\x4a\xcd\x6c\x73\x12\x3a\x69\xd9\x91\x08\xd6\xb5\x13\xea\x5a\xce\x31\x2b\x68\x10\x0d\xdb\x99\xfb\x36\xe2\x4a\xc6\x69\x10\x78\xd2\x63\x17\x23\xce\x3c\x53\x26\xeb\x58\x56\x2e\xd8\xde\xce\xf3\x13\x42\xc7\x74\x02\xdd\xca\xe7\xa4\xbd\xe4\xcc\x3f\x19\xef\xa0\x4d\x6a\xcd\x9e\x6c\x30\x6b\xa1\x61\x6c\xab\xae\x41\xca\x1c\xfd\xfa\x0b\x48\x73\x23\x08\x61\x64\x74\xe6\x50\x5a\x53\x92\x55\x4d\xbf\x43\xc1\x4f\xa5\x4c\x2d\x75\x16\x0d\xc3\xfe\x14\x4c\x8b\x59\xbf\x01\xc4\x73\x83\x91\xc6\x8b\x2e\x79\xe4\xcd\xa8\xab\xf6\x0a\xff\x3c\x96\x6f\xa1\x17\xed\xef\x3e\x28\xd0\xf5\x0e\xc1\x3c\x0b\x75\xf6\xd4\xcc\xf0\xe2\x7b\x06\xbe\x0a\x10\xc7\x4d\x49\x6e\x86\xbf\x06\x4f\x77\x2e\x6b\x1f\x11\x56\x4a\x37\xe6\xa6\x98\x04\x01\x26\x5c\x99\x4a\x8c\x4a\xc5\x7c\x30\x72\xf0\x77\x9b\xfd\x9f\xd6\x33\x64\xbc\x1c\xb9\x03\x1f\xf5\xd7\x8c\xba\xd8\x31\xaa\x75\x64\xf6\x7f\x33\x42\x27\x3f\xfe\x12\xb2\x52\xf6\x0f\xd9\x0c\x87\xb3\x68\x2f\xb5\x98\xf5\xa3\x1d\x15\xe4\x59\x10\x65\x16\x01\xba\x65\x6b\x42\x4f\xfd\xbf\xef\xb0\xf2\x4d\x49\x33\xac\x4a\xf6\xa1\xa2\xe8\x31\xbb\x95\xf4\xf3\x67\x8c\x93\x03\x9f\x77\x9d\xbe\xa5\x06\x5f\xbe\x44\x5f\xb5\xbc\x6f\x6e\x8c\x78\xb3\xae\xf9\x27\x73\x58\x9e\x8b\x73\xd1\x3c\xd3\xe9\xf4\xb9\x4e\xaf\xef\x72\x7e\xad\x3e\x2d\x8b\xf8\x90\x26\xcf\x73\xb1\x59\x9b\xe5\x34\x76\xe9\xf9\xdd\x7e\xb9\xb9\x70\xdb\xcd\xba\x48\xcf\x6f\xfc\x42\x9d\xbe\x8c\x8e\xc5\x7d\x3f\x8d\xeb\xe5\x26\x9e\x6e\x9b\xb3\x7c\x3b\x8d\x0f\xe2\xfe\xd9\xe9\x72\x13\xab\xed\xf9\x8d\x12\xf3\x97\xc7\xf0\xbe\x1f\x3e\xbd\x6b\x44\xa1\x1e\xde\x27\x67\x0f\xdb\xcd\x3a\x4f\xaf\x9f\xeb\xe8\xdf\x00\x00\x00\xff\xff\xc4\x9a\x94\x48\x73\x06\x00\x00")
+var _templatesGcpDeprecatedServiceAccountSecretYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x54\x4d\x73\xdb\x36\x10\xbd\xeb\x57\xec\xc8\x97\x76\x46\x94\x6c\xa9\x49\xc7\x6a\x93\x19\xd9\x71\x1d\x3a\x1a\x2a\x23\x2a\xb6\x95\x4e\x0f\x20\xb8\x22\x61\x83\x00\x03\x80\x62\x98\x34\xff\x3d\x0b\x92\x4e\xa4\x66\x7a\x0a\x2f\x12\x88\xdd\x87\xf7\xb1\xe0\xc9\xc9\xcf\x3e\x83\x13\xb8\xd4\x65\x63\x44\x96\x3b\x98\x9e\x9e\xfd\x0e\xd7\x5a\x67\x12\x21\x54\x7c\x3c\xf0\xdb\x4b\xc1\x51\x59\x4c\xa1\x52\x29\x1a\x70\x39\xc2\xa2\x64\x9c\x7e\xfa\x9d\x11\xdc\xa2\xb1\x42\x2b\x98\x8e\x4f\xe1\x17\x5f\x30\xec\xb7\x86\xbf\xfe\x41\x08\x8d\xae\xa0\x60\x0d\x28\xed\xa0\xb2\x48\x10\xc2\xc2\x4e\xd0\x21\xf8\x91\x63\xe9\x40\x28\xe0\xba\x28\xa5\x60\x8a\x23\xd4\xc2\xe5\xed\x31\x3d\x08\xd1\x80\x6d\x0f\xa1\x13\xc7\xa8\x9a\x51\x7d\x49\xab\xdd\x61\x1d\x30\xd7\x12\xf6\x4f\xee\x5c\x69\xe7\x93\x49\x5d\xd7\x63\xd6\xb2\x1d\x6b\x93\x4d\x64\x57\x69\x27\xcb\xf0\xf2\x2a\x8a\xaf\x02\x62\xdc\xf6\xbc\x53\x12\xad\x05\x83\x1f\x2a\x61\x48\x6b\xd2\x00\x2b\x89\x10\x67\x09\xd1\x94\xac\x06\x6d\x80\x65\x06\x69\xcf\x69\x4f\xb8\x36\xc2\x09\x95\x8d\xc0\xea\x9d\xab\x99\x41\x42\x49\x85\x75\x46\x24\x95\x3b\x72\xeb\x89\x1e\x89\x3e\x2c\x20\xbf\x98\x82\xe1\x22\x86\x30\x1e\xc2\xc5\x22\x0e\xe3\x11\x61\xdc\x85\x9b\xd7\xab\x77\x1b\xb8\x5b\xac\xd7\x8b\x68\x13\x5e\xc5\xb0\x5a\xc3\xe5\x2a\x7a\x15\x6e\xc2\x55\x44\xab\xbf\x60\x11\x6d\xe1\x4d\x18\xbd\x1a\x01\x92\x57\x74\x0c\x7e\x2c\x8d\xe7\x4f\x24\x85\xf7\x11\x53\x6f\x5a\x8c\x78\x44\x60\xa7\x3b\x42\xb6\x44\x2e\x76\x82\x93\x2e\x95\x55\x2c\x43\xc8\xf4\x1e\x8d\x22\x39\x50\xa2\x29\x84\xf5\x69\x5a\xa2\x97\x12\x8a\x14\x85\x70\xcc\xb5\x6f\x7e\x10\xd5\x8d\x48\x8c\xdc\xa0\x03\xeb\xb4\xf1\x18\x52\xab\x2c\x90\x62\x4f\x22\xaf\x2f\xdf\x82\x45\xb3\xa7\x6a\x60\x9c\xeb\x4a\x39\x78\xb0\x24\xfd\x11\x9b\x31\x6c\x68\x10\xfc\x09\xdf\xeb\xe9\x35\xe5\x2f\x25\x24\x48\x11\x2b\x72\xb8\x62\x52\x36\xe0\x0c\x53\x96\xf8\x17\x9d\xff\x0c\x6c\xae\x8d\xeb\x9a\x08\x21\x41\x0a\x80\x88\xe9\x47\x54\x6d\x76\x34\x6a\xde\x97\x44\x28\x66\x9a\x91\x57\xd2\xb2\xa3\x6e\x8a\xae\x9d\x50\xdb\x72\x8e\x58\x81\x43\x02\xf0\x33\xf7\xe3\x8e\xa5\xc9\xc1\xa1\xe7\x89\x4f\x2a\x02\xce\x1c\x93\x3a\xeb\x58\xd2\x34\x53\x73\x3b\xcf\x07\x84\x8e\xe9\x78\xba\x15\x01\x93\x18\xea\x3d\x18\x6f\xef\x4d\x62\xa8\xc6\xf8\xb0\x42\x45\xe9\xb5\xee\x6a\x48\x98\xc5\xe7\xbf\x01\x2a\xae\x53\x3a\xd9\x79\x57\x2d\x94\x46\x53\x3a\x64\x46\x77\x87\x7c\x9e\x52\xea\x9a\xf6\xa8\x9d\xee\x4f\x41\x2a\xe7\xfd\x0d\x40\x9e\x6b\x08\x14\xfc\xd9\x35\x07\x4e\x07\x1d\xda\x4b\xf8\xf7\x09\xbe\x2d\xbd\x68\xff\xf7\x9b\x64\x52\x97\x64\x4a\x22\x3d\x0e\xc5\x31\x27\x8c\xff\x09\xf0\xa5\x2f\xb1\x74\x13\xd1\xce\xe1\xef\xe1\xe1\x9d\xcb\xda\x8f\x08\x2b\x85\x1d\x13\xb3\x89\x37\x60\xc2\xa5\xae\xd2\xa0\x94\xcc\xf9\x20\x87\xff\xb4\xdd\xdf\xbc\x9e\x43\xc6\xcb\xc0\xee\x79\xd0\x1f\x13\x74\x7b\xc7\x55\x6d\x22\xf3\xff\x86\xe1\x95\xfc\xfc\x97\x90\xd8\xf6\x1f\xb2\x39\xec\xcf\x06\x8f\x82\xec\xec\x47\x7b\x50\xa0\x63\xde\x94\xf9\x00\x40\xb5\x6c\xb5\xd7\xd4\xaf\x7a\x56\x9f\x3f\xc3\xf8\x1b\x49\xf8\xf2\x65\xe0\x9a\x92\x5e\xaf\x4a\xf6\xa1\xc2\xc1\x53\x7b\xeb\xa9\x2f\x8d\xf7\x7c\xd1\x49\x7d\x43\x53\x4f\xe5\xdf\xcd\xbc\x6b\x6e\x74\xfa\x7a\x5d\xf3\x4f\x7a\xbf\x9c\xa5\xb3\xb4\x79\xa6\x92\xe9\xb9\x4a\xae\x6f\x73\x7e\x2d\x3f\x2d\x8b\x68\x9f\xc4\xe7\x79\x7a\xbf\xd6\xcb\x69\x64\x93\xd9\xed\xe3\xf2\xfe\xc2\x6e\xef\xd7\x45\x32\xbb\x71\xa1\x3c\x7d\x31\x38\x76\xf7\xfd\x34\xaa\x97\xf7\xd1\x74\xdb\x9c\xe5\xdb\x69\xb4\x4f\xef\x9e\x9d\xd2\x5a\x6e\x67\x37\x32\x5d\xbc\x38\x2e\xef\x05\xf1\xe9\x6d\x93\x16\xf2\xe1\x7d\x7c\xf6\x40\xd0\x79\x72\x7d\xae\x06\x5f\x01\x55\x90\xfd\x9f\x74\x06\x00\x00")
 
 func templatesGcpDeprecatedServiceAccountSecretYamlTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -608,7 +950,127 @@ func templatesGcpDeprecatedServiceAccountSecretYamlTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "templates/gcp-deprecated/service-account-secret.yaml.tmpl", size: 1651, mode: os.FileMode(420), modTime: time.Unix(1525803557, 0)}
+	info := bindataFileInfo{name: "templates/gcp-deprecated/service-account-secret.yaml.tmpl", size: 1652, mode: os.FileMode(420), modTime: time.Unix(1792032886, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesInstallerBootstrapJobYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x55\x51\x6f\xdb\x36\x10\x7e\xf7\xaf\x38\x38\x18\xb0\x01\x96\xdc\xe6\xa9\xd0\x9e\x14\xc7\xcb\xb4\x25\x4a\x61\x39\x2b\xfa\x16\x8a\xa2\x64\x36\x32\xa9\x92\x54\x54\x37\xe8\x7f\xdf\x1d\x25\x39\x76\xe2\x6d\xc0\xc2\x17\x9b\xbc\xe3\x77\xdf\xdd\x77\x3c\x9d\x9d\xbd\x75\x4d\xce\x60\xa1\x9b\x9d\x91\xd5\xc6\xc1\xf9\xbb\xf7\x1f\xe0\x4a\xeb\xaa\x16\x90\x28\x1e\x4e\xc8\x7c\x2d\xb9\x50\x56\x14\xd0\xaa\x42\x18\x70\x1b\x01\x71\xc3\x38\xfe\x0c\x96\x19\xfc\x25\x8c\x95\x5a\xc1\x79\xf8\x0e\x7e\x26\x87\xe9\x60\x9a\xfe\xf2\x2b\x22\xec\x74\x0b\x5b\xb6\x03\xa5\x1d\xb4\x56\x20\x84\xb4\x50\x4a\x0c\x22\xbe\x71\xd1\x38\x90\x0a\xb8\xde\x36\xb5\x64\x8a\x0b\xe8\xa4\xdb\xf8\x30\x03\x08\xd2\x80\xcf\x03\x84\xce\x1d\x43\x6f\x86\xfe\x0d\xee\xca\x43\x3f\x60\xce\x13\xa6\xb5\x71\xae\xb1\xd1\x7c\xde\x75\x5d\xc8\x3c\xdb\x50\x9b\x6a\x5e\xf7\x9e\x76\x7e\x9d\x2c\x96\x69\xb6\x0c\x90\xb1\xbf\x73\xa7\x6a\x61\x2d\x18\xf1\xb5\x95\x06\x73\xcd\x77\xc0\x1a\x24\xc4\x59\x8e\x34\x6b\xd6\x81\x36\xc0\x2a\x23\xd0\xe6\x34\x11\xee\x8c\x74\x52\x55\x33\xb0\xba\x74\x1d\x33\x02\x51\x0a\x69\x9d\x91\x79\xeb\x8e\xaa\x35\xd2\xc3\xa4\x0f\x1d\xb0\x5e\x4c\xc1\x34\xce\x20\xc9\xa6\x70\x11\x67\x49\x36\x43\x8c\x4f\xc9\xfa\xf7\xdb\xbb\x35\x7c\x8a\x57\xab\x38\x5d\x27\xcb\x0c\x6e\x57\xb0\xb8\x4d\x2f\x93\x75\x72\x9b\xe2\xee\x37\x88\xd3\xcf\xf0\x67\x92\x5e\xce\x40\x60\xad\x30\x8c\xf8\xd6\x18\xe2\x8f\x24\x25\xd5\x51\x14\x54\xb4\x4c\x88\x23\x02\xa5\xee\x09\xd9\x46\x70\x59\x4a\x8e\x79\xa9\xaa\x65\x95\x80\x4a\x3f\x0a\xa3\x30\x1d\x68\x84\xd9\x4a\x4b\x6a\x5a\xa4\x57\x20\x4a\x2d\xb7\xd2\x31\xe7\x4f\x5e\x25\xd5\xb7\xc8\x1f\x3a\x07\xd3\x2a\x0f\x40\x46\xa9\xac\x63\x75\x8d\xae\xf8\x4f\x16\x3d\x0b\x5e\xb7\xd6\x09\x33\x03\xd6\xe2\x56\x39\xac\x2d\x95\x81\x59\x90\xce\x22\x88\x15\xe6\x11\x41\x81\x71\xae\x5b\xe5\xa8\xb0\x78\x8f\xb9\xf1\xa2\xf5\x8d\xa1\x5b\x87\xd9\xe2\x56\xb1\x1a\x16\xd7\x09\xb9\x53\xe6\x9c\x29\xc4\x18\x02\x63\xe6\x3d\xd6\x82\xe1\x56\x57\x7d\x4b\xdd\x3f\xb4\xb9\xe0\xae\xf6\xca\xee\x20\x28\xef\x7b\xf6\x69\x7c\xb3\xcc\x3e\xc6\x8b\x65\x04\x8a\x6d\x85\xc5\x76\x11\x2f\xd2\xf8\xd2\x27\x88\x54\x29\x4a\x72\x13\x5f\xa1\xf3\x41\x96\x5b\x2c\x22\x1a\xe2\xd5\x55\x76\x78\xce\x4c\xd5\x6e\x31\x55\x4a\x6f\x71\x7d\x97\xad\x97\xab\xf8\xf2\x26\x49\x23\xe8\x36\xc2\x0b\xf7\xa2\x5a\x16\x72\xcc\xdd\xb7\xd8\x90\x75\xc0\x8a\xad\x0f\xba\xba\x88\x17\x11\x68\xba\xd5\x49\x7a\x74\x74\xd5\x68\x6c\x5b\x7a\x06\xf7\x96\x43\x25\x94\x30\x58\xd3\x67\xc0\xc0\xe4\x8c\xdf\xef\x75\x1f\xce\xfd\x13\xc1\x3b\xc4\x96\x0a\xf0\xf6\x19\xc2\x1a\x39\x8c\x80\x08\x1e\xdf\x4f\x1e\xa4\x2a\x22\x48\xc7\x5a\x4e\xb6\xc2\xb1\x02\x95\x88\x26\xe0\x2b\x1c\xc1\xd3\x13\x84\x7b\x3b\xfc\xf8\x31\x09\x82\xe0\x34\xca\xa0\x64\xdc\x37\xc5\x09\x28\xcb\x83\x7d\xbe\xc3\xa1\x47\x3d\x11\xe4\xe9\x29\x00\x59\x42\xb8\xe8\x4b\x1b\x53\x65\xc7\xe0\x67\xb0\x3e\x92\x82\x1b\x81\xa5\xb4\xa3\x0c\x18\x46\x37\xd8\xad\xf8\xca\x74\x6b\xb0\xe3\x66\xe8\x8b\xb6\x82\x5a\x9e\xa4\x41\x00\x2f\x86\x6f\x5b\xe9\x40\xe1\xa4\xb0\xa4\x63\x2e\x68\x5a\x0d\x28\x5e\xcc\xf0\x28\x53\x92\x28\xa4\x27\xa1\x8d\xfc\xee\x9f\x59\xf8\xf0\xc1\x86\x52\xcf\xf7\x35\x18\xf8\xae\x10\xff\x02\x0f\x30\xe4\x7f\xd5\x81\xa8\xac\x44\x49\x56\x8c\x75\x65\x74\xdb\xfc\x4b\x24\xf4\x7a\x15\x68\x8f\x7b\xdc\x87\xb6\xcd\xbf\xe0\x1b\xb2\xd1\x24\x80\x93\x02\xfd\x4f\x59\x44\x6d\xc5\x3f\x49\x81\xaf\xa2\x32\x4c\xd1\xb0\xe8\x68\x1e\x1c\xb4\xf2\x28\xd3\x6c\xd4\xc7\xd1\xcc\xa0\xa7\xa8\x7b\x9e\x34\xc3\x9e\x83\xdb\xf1\x8b\x31\x5c\x0f\x27\x44\x86\xe4\xdb\xf3\x40\xf7\x13\xed\x98\x33\xc7\x37\xcf\x82\xe0\xbc\x7b\x5b\x27\xd2\x04\xa6\x8b\xa8\xc8\x83\x2e\xcb\x6b\x1a\xb1\x11\x9c\xe3\x89\x13\x38\xbf\x31\x25\xb2\x02\x1c\x06\xa1\x55\xb3\x1c\x2b\x35\xee\x48\xdc\xe6\x55\x5c\x80\x11\xdd\xff\x3f\x92\x27\x3d\x45\x94\x16\xf6\xb5\x63\xc6\x7d\xd4\xf8\xc9\xdb\xe1\xdb\x15\x8f\x7b\x13\xd7\x8a\xbe\xb9\x58\x8a\x11\x33\x38\x9d\x70\xbf\xfc\x2c\xec\x53\x4e\xe8\x2f\xa5\xbb\xa7\x6b\x2a\x04\xa1\x3a\xa3\x9e\x68\x0a\x63\x3c\x38\x74\x08\xe8\x5e\x63\xa4\x72\x25\x4c\x7f\xfa\x3a\x85\xf0\x85\x30\x7f\x03\x64\xb3\x2a\xe4\xf1\x08\x00\x00")
+
+func templatesInstallerBootstrapJobYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesInstallerBootstrapJobYamlTmpl,
+		"templates/installer/bootstrap-job.yaml.tmpl",
+	)
+}
+
+func templatesInstallerBootstrapJobYamlTmpl() (*asset, error) {
+	bytes, err := templatesInstallerBootstrapJobYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/installer/bootstrap-job.yaml.tmpl", size: 2289, mode: os.FileMode(420), modTime: time.Unix(1792032474, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesInstallerGitopsArgocdYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa5\x54\x4d\x6f\xe3\x36\x10\xbd\xfb\x57\x0c\x92\x4b\x0b\x38\xca\x47\x51\xa0\x50\xb1\x07\xc5\x71\x37\x42\x53\xdb\xb0\x9d\x5d\xec\xa9\xa0\xa4\x91\xcc\x8d\x44\xaa\x24\x65\x47\x58\xec\x7f\xef\x23\x25\xbb\xde\x6c\x6f\xab\x8b\xcd\x99\xc7\x37\x6f\x1e\x87\xbc\xbc\xfc\xd1\x6f\x72\x49\x33\xdd\xf6\x46\x56\x3b\x47\x77\x37\xb7\xbf\xd1\x7b\xad\xab\x9a\x29\x55\x79\x34\xf1\xe9\x27\x99\xb3\xb2\x5c\x50\xa7\x0a\x36\xe4\x76\x4c\x49\x2b\x72\xfc\x8c\x99\x29\x7d\x60\x63\xa5\x56\x74\x17\xdd\xd0\x4f\x1e\x70\x31\xa6\x2e\x7e\xfe\x1d\x0c\xbd\xee\xa8\x11\x3d\x29\xed\xa8\xb3\x0c\x0a\x69\xa9\x94\x28\xc2\xaf\x39\xb7\x8e\xa4\xa2\x5c\x37\x6d\x2d\x85\xca\x99\x0e\xd2\xed\x42\x99\x91\x04\x32\xe8\xd3\x48\xa1\x33\x27\x80\x16\xc0\xb7\x58\x95\xe7\x38\x12\x2e\x08\xf6\xdf\xce\xb9\xd6\xc6\xd7\xd7\x87\xc3\x21\x12\x41\x6d\xa4\x4d\x75\x5d\x0f\x48\x7b\xfd\x94\xce\xe6\x8b\xcd\xfc\x0a\x8a\xc3\x9e\x67\x55\xb3\xb5\x64\xf8\x9f\x4e\x1a\xf4\x9a\xf5\x24\x5a\x08\xca\x45\x06\x99\xb5\x38\x90\x36\x24\x2a\xc3\xc8\x39\xed\x05\x1f\x8c\x74\x52\x55\x53\xb2\xba\x74\x07\x61\x18\x2c\x85\xb4\xce\xc8\xac\x73\xdf\xb8\x75\x94\x87\xa6\xcf\x01\xf0\x4b\x28\xba\x48\x36\x94\x6e\x2e\xe8\x3e\xd9\xa4\x9b\x29\x38\x3e\xa6\xdb\xc7\xe5\xf3\x96\x3e\x26\xeb\x75\xb2\xd8\xa6\xf3\x0d\x2d\xd7\x34\x5b\x2e\x1e\xd2\x6d\xba\x5c\x60\xf5\x07\x25\x8b\x4f\xf4\x67\xba\x78\x98\x12\xc3\x2b\x94\xe1\xd7\xd6\x78\xfd\x10\x29\xbd\x8f\x5c\x78\xd3\x36\xcc\xdf\x08\x28\xf5\x20\xc8\xb6\x9c\xcb\x52\xe6\xe8\x4b\x55\x9d\xa8\x98\x2a\xbd\x67\xa3\xd0\x0e\xb5\x6c\x1a\x69\xfd\x69\x5a\xc8\x2b\xc0\x52\xcb\x46\x3a\xe1\x42\xe4\xbb\xa6\x86\x11\x49\x4c\xa5\x69\xf6\x80\xb9\x08\x96\x79\x2c\xd9\x5e\xe5\x9e\xd0\x83\x1b\xa1\x64\xc9\xd6\x59\xb2\x39\x3c\xf6\x24\x16\x1e\xc2\x48\x64\xb1\xdf\x70\xab\xad\x74\xda\xf4\x11\x6d\x81\xd7\xd9\x67\xce\x81\x86\xab\x10\x81\xb1\x11\xde\xb0\xe3\x5c\x48\x13\xc8\xe9\x20\xf6\x98\x3d\x8e\xaa\x68\xa4\x49\x56\xe9\x86\xcd\x1e\xca\x28\x63\x34\x3b\x74\x9f\x19\xfd\x12\xea\x39\xb2\xc8\xb2\x9d\xfa\xc6\x4e\x9a\x0f\x42\xa2\xd2\x68\x0d\x48\x76\x2c\x6a\xd4\xc1\x68\x31\xc6\xe6\x4d\x11\x32\xba\xae\x75\xe7\x8e\x93\x57\x70\x5b\xeb\xbe\x61\xe5\xc0\x3a\xd4\x04\xc5\x79\xeb\x8a\x5f\xdd\x60\xd2\x22\xf9\x6b\x1e\x93\x12\x0d\x1f\x77\x9f\xd9\x35\x0d\x01\xfc\x17\xb5\xae\x02\xc8\x62\x6a\x87\xa6\x56\x7f\xfb\xad\x9b\x55\x32\x1b\xf7\x87\x94\x27\x19\x7b\x00\x6a\x3d\x5f\x2d\x63\x7a\x5e\x3f\x1d\xb9\xdf\xa3\xdd\xff\x6c\x05\xe2\x1e\xd3\x34\x7b\x8c\x61\x07\x2e\xd9\xee\x08\x3b\x9d\x0c\x10\xab\x64\x8b\x7c\x81\xf9\xcf\xfd\x9e\xef\x20\x7e\xea\x83\x07\xe7\xb4\x67\xd2\xfe\x47\xfd\xe4\xf2\xc7\x1f\x28\xd1\xca\xf1\x7d\x89\x31\x10\x95\x6e\x8d\xfe\x1c\x49\x7d\xbd\xbf\x15\x75\xbb\x13\xb7\x93\x17\xa9\x8a\xf8\xdc\xcc\x49\xc3\x4e\x14\x50\x13\x4f\x28\xa8\x89\xe9\xcb\x17\x8a\x16\xde\xfa\xaf\x5f\xc7\x58\x50\x38\x24\xb0\x75\x71\xb2\x35\x00\x6a\x91\x71\x6d\xfd\x76\xf2\xef\x40\xf4\xd2\x65\xb8\x1f\xec\xd8\xfa\xca\x70\x04\x97\xa6\xb8\xca\xfa\x18\x03\x3d\xf1\xd7\xc9\x43\xbd\x30\x58\x07\x0b\xb9\x14\x5d\xed\x10\xb2\xba\x33\xa8\x12\x78\xbc\x6d\x38\xa0\xa1\xe4\x1a\x8b\xa1\x14\x91\x43\x57\xec\xd6\xbc\x97\x43\x93\x3e\x7f\x3f\x9c\xd2\x88\x68\x85\xdb\x0d\xf1\x15\xfe\x0d\xd1\x02\x47\x22\x55\xe8\x77\xe0\x0f\xc3\x6d\xe2\xd3\xd3\x77\xa6\x79\x14\x14\xd9\x7d\x1e\xa0\x6f\xfa\x7f\xd3\xbc\x1f\xdf\x95\x86\x99\xfd\x68\x40\xe7\x74\xe3\x6f\xe0\xb0\xf4\x8d\x76\x0a\x5b\x9d\xe9\x78\x8c\x58\xae\xcb\x47\x5c\x9c\xb3\xa0\x67\x59\xb6\xe1\xd9\x18\xf6\x5d\xd1\xcc\x30\x68\x4e\xd5\xde\x9d\xb0\x86\x9d\xe9\x8f\xec\xe1\xbd\x89\xe9\xd7\x71\x99\x89\xfc\x45\x97\xe5\x31\x8b\xc6\x3b\x33\x74\x4d\xb7\x37\xf6\x14\x2d\x85\x1f\xda\x98\xee\x4e\x91\x46\xbc\x3e\x9c\xa0\xbf\x34\x93\x7f\x01\x15\x0b\xc8\xd4\x2c\x07\x00\x00")
+
+func templatesInstallerGitopsArgocdYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesInstallerGitopsArgocdYamlTmpl,
+		"templates/installer/gitops-argocd.yaml.tmpl",
+	)
+}
+
+func templatesInstallerGitopsArgocdYamlTmpl() (*asset, error) {
+	bytes, err := templatesInstallerGitopsArgocdYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/installer/gitops-argocd.yaml.tmpl", size: 1836, mode: os.FileMode(420), modTime: time.Unix(1792028497, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesInstallerGitopsFluxYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x54\xc1\x6e\xdb\x46\x10\xbd\xeb\x2b\x06\x76\x0e\x2d\x20\x51\x76\x80\x02\x05\x7b\xa2\x65\x25\x21\xe2\x52\x82\x68\xc7\xc8\xa9\x58\x92\x43\x6a\x2b\x72\x97\xdd\x5d\x8a\x56\x8c\xfc\x7b\x67\xb8\x94\x2a\xa5\x45\x81\x22\xd5\x45\xd8\x99\x37\x6f\xdf\xbc\x99\xe5\xf5\xf5\xf7\xfe\x26\xd7\xb0\xd0\xed\xc1\xc8\x6a\xeb\xe0\xed\xcd\xed\xcf\xf0\x5e\xeb\xaa\x46\x88\x55\x1e\x4c\x38\xfd\x20\x73\x54\x16\x0b\xe8\x54\x81\x06\xdc\x16\x21\x6a\x45\x4e\x7f\x63\x66\x0a\x9f\xd0\x58\xa9\x15\xbc\x0d\x6e\xe0\x07\x06\x5c\x8d\xa9\xab\x1f\x7f\x21\x86\x83\xee\xa0\x11\x07\x50\xda\x41\x67\x91\x28\xa4\x85\x52\xd2\x25\xf8\x92\x63\xeb\x40\x2a\xc8\x75\xd3\xd6\x52\xa8\x1c\xa1\x97\x6e\x3b\x5c\x33\x92\x90\x0c\xf8\x3c\x52\xe8\xcc\x09\x42\x0b\xc2\xb7\x74\x2a\xcf\x71\x20\xdc\x20\x98\x7f\x5b\xe7\x5a\x1b\xce\xe7\x7d\xdf\x07\x62\x50\x1b\x68\x53\xcd\x6b\x8f\xb4\xf3\x87\x78\xb1\x4c\xd2\xe5\x8c\x14\x0f\x35\x4f\xaa\x46\x6b\xc1\xe0\x1f\x9d\x34\xd4\x6b\x76\x00\xd1\x92\xa0\x5c\x64\x24\xb3\x16\x3d\x68\x03\xa2\x32\x48\x39\xa7\x59\x70\x6f\xa4\x93\xaa\x9a\x82\xd5\xa5\xeb\x85\x41\x62\x29\xa4\x75\x46\x66\x9d\xbb\x70\xeb\x28\x8f\x9a\x3e\x07\x90\x5f\x42\xc1\x55\x94\x42\x9c\x5e\xc1\x5d\x94\xc6\xe9\x94\x38\x9e\xe3\xc7\x0f\xab\xa7\x47\x78\x8e\x36\x9b\x28\x79\x8c\x97\x29\xac\x36\xb0\x58\x25\xf7\xf1\x63\xbc\x4a\xe8\xf4\x0e\xa2\xe4\x33\x7c\x8c\x93\xfb\x29\x20\x79\x45\xd7\xe0\x4b\x6b\x58\x3f\x89\x94\xec\x23\x16\x6c\x5a\x8a\x78\x21\xa0\xd4\x5e\x90\x6d\x31\x97\xa5\xcc\xa9\x2f\x55\x75\xa2\x42\xa8\xf4\x1e\x8d\xa2\x76\xa0\x45\xd3\x48\xcb\xd3\xb4\x24\xaf\x20\x96\x5a\x36\xd2\x09\x37\x44\xfe\xd6\x94\x5f\x91\x77\x75\xf7\x02\xef\xa5\xdb\x60\xab\xad\x74\xda\x1c\xb8\x16\x3e\x76\xd6\xe9\x46\x7e\x19\x8b\xed\x41\xe5\x7c\x05\x97\x37\x42\xc9\x12\xad\xa3\x68\x4e\x04\x06\x99\xd8\x92\xaf\x64\x2e\xe7\xcd\x89\x69\x4a\x46\xe1\x25\x15\x8b\x1c\xd8\xa0\x17\x7b\x5a\x3f\x0c\xaa\x80\xab\x88\x28\x5a\xc7\x29\x9a\x3d\x89\x83\x0c\xa9\x5f\x6f\x40\x66\xf4\x6e\xa0\x77\x60\x29\x8b\x36\x80\x25\xed\xc4\x37\xac\xbd\x90\xa4\x87\x8a\x88\x87\xab\xb6\x28\x6a\xda\x44\x5a\x32\x8e\xeb\xec\x77\xcc\x9d\xfd\xeb\x36\x30\xba\xae\x75\xe7\x8e\x5b\x58\x60\x5b\xeb\x43\x83\x8a\x41\xde\x3a\x0a\x51\x63\x96\x47\xcd\x08\x6e\x64\x04\xd3\xb8\xf6\x52\x77\x76\xe8\xc0\x9b\x98\x44\xbf\x2e\x43\x50\xa2\x39\x81\x2e\x1c\x9d\x0e\xa1\x5c\x38\x51\xeb\x6a\x80\x59\xda\x6b\xdf\xf3\xfa\x37\x2e\x4e\xd7\xd1\x62\x64\x18\x52\x4c\xc3\x93\x21\xc8\x66\xb9\x5e\x85\xf0\xb4\x79\x38\xa3\x3e\xf3\x98\x10\x77\xb4\x6c\x8b\x0f\x21\x59\x45\x6f\x70\x7b\x84\x9d\xc6\xc4\x8b\x19\x7d\x5a\xa6\x9e\x7e\x0a\xad\x20\x67\x78\xc6\xbe\x45\x54\xf9\xe9\x35\x5e\x9a\x4a\x41\x64\xab\xb9\x4f\x6e\xf3\xfb\x3f\x58\xa2\x95\xe3\xf7\x26\xa4\xb7\xd7\x99\x1c\x03\xa7\x75\xbd\x93\x2e\x28\xa9\xdb\xbc\x08\xa4\x9e\xef\x6f\x27\x3b\xa9\x8a\xf0\xd2\xc3\x49\x83\x4e\x14\x64\x61\x38\x81\xa1\x91\x10\x5e\x5f\x21\x48\xd8\xf3\xaf\x5f\xc7\xd8\xe0\x9d\x4f\x44\x6d\x9b\x9c\xdc\x1c\x00\xb5\xc8\xb0\xb6\x5c\x0e\xfc\x81\x08\x76\x5d\x46\x0f\x07\x1d\xad\x14\xdd\x4a\x76\xd1\x6b\x2a\x66\xd9\x21\xe4\xbd\xe6\x77\xc6\x50\xda\x6a\xda\x3a\x51\x87\xf0\x53\x43\xc7\xce\xd4\x9e\x9e\x85\x79\x5a\x83\xa5\xe7\xf4\xf6\xfb\xf4\x9d\x1f\x05\x01\x5e\x5f\x67\x40\x07\x7a\xa8\xc1\x33\xf9\x68\x39\x36\x9b\xcd\x2e\xac\xd8\x8d\xb6\xff\xab\x1b\x17\xb3\xf9\x8f\x6e\xbc\xf9\xff\xed\xb8\xbd\x61\x3f\xfc\x10\x37\x47\x0b\xfe\x69\x6e\x1c\x3f\x29\x7c\x73\x26\x91\xf7\x30\x84\x60\xce\xc2\xd7\xbc\x93\x43\x94\xfd\x92\x25\x04\xf7\xfe\xfd\xad\x94\x0f\x17\xc7\x23\x5f\x34\x3b\x6b\xf9\x1b\x1c\x97\xd3\x79\xbc\xc1\x74\x8a\x60\xce\x74\x48\x27\xfe\x46\x9c\x0e\x4e\x36\x48\x1f\x00\xdf\xc7\x59\xd1\x9f\x90\x0a\xdd\xe4\x96\x07\x00\x00")
+
+func templatesInstallerGitopsFluxYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesInstallerGitopsFluxYamlTmpl,
+		"templates/installer/gitops-flux.yaml.tmpl",
+	)
+}
+
+func templatesInstallerGitopsFluxYamlTmpl() (*asset, error) {
+	bytes, err := templatesInstallerGitopsFluxYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/installer/gitops-flux.yaml.tmpl", size: 1942, mode: os.FileMode(420), modTime: time.Unix(1792028497, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesInstallerLoadtestBrokerYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x56\xdb\x6e\xdb\x38\x10\x7d\xf7\x57\x0c\x9c\x97\x5d\xc0\x92\x9d\x62\xb1\x08\xb4\x4f\x8a\xe3\xed\x0a\x4d\xec\xc0\x72\xb7\xe8\x23\x45\x8d\x6d\xae\x25\x51\x4b\x52\x76\x8d\x20\xff\xde\x21\x75\x89\x94\x4b\x11\xa0\xd5\x8b\x2d\x72\xe6\xcc\x99\x33\x17\xfb\xe2\xe2\x67\x9f\xd1\x05\xcc\x65\x79\x56\x62\xb7\x37\xf0\x61\x76\x79\x05\x1f\xa5\xdc\x65\x08\x51\xc1\xfd\x91\xbd\xbe\x15\x1c\x0b\x8d\x29\x54\x45\x8a\x0a\xcc\x1e\x21\x2c\x19\xa7\x8f\xe6\x66\x02\xff\xa2\xd2\x42\x16\xf0\xc1\x9f\xc1\x6f\xd6\x60\xdc\x5c\x8d\x7f\xff\x8b\x10\xce\xb2\x82\x9c\x9d\xa1\x90\x06\x2a\x8d\x04\x21\x34\x6c\x05\x05\xc1\x6f\x1c\x4b\x03\xa2\x00\x2e\xf3\x32\x13\xac\xe0\x08\x27\x61\xf6\x2e\x4c\x03\x42\x34\xe0\x6b\x03\x21\x13\xc3\xc8\x9a\x91\x7d\x49\x6f\xdb\xbe\x1d\x30\xe3\x08\xdb\x67\x6f\x4c\xa9\x83\xe9\xf4\x74\x3a\xf9\xcc\xb1\xf5\xa5\xda\x4d\xb3\xda\x52\x4f\x6f\xa3\xf9\x62\x19\x2f\x3c\x62\xec\x7c\x3e\x17\x19\x6a\x0d\x0a\xff\xaf\x84\xa2\x5c\x93\x33\xb0\x92\x08\x71\x96\x10\xcd\x8c\x9d\x40\x2a\x60\x3b\x85\x74\x67\xa4\x25\x7c\x52\xc2\x88\x62\x37\x01\x2d\xb7\xe6\xc4\x14\x12\x4a\x2a\xb4\x51\x22\xa9\xcc\x40\xad\x96\x1e\x25\xdd\x37\x20\xbd\x58\x01\xe3\x30\x86\x28\x1e\xc3\x75\x18\x47\xf1\x84\x30\xbe\x44\x9b\x7f\x56\x9f\x37\xf0\x25\x5c\xaf\xc3\xe5\x26\x5a\xc4\xb0\x5a\xc3\x7c\xb5\xbc\x89\x36\xd1\x6a\x49\x6f\x7f\x43\xb8\xfc\x0a\x9f\xa2\xe5\xcd\x04\x90\xb4\xa2\x30\xf8\xad\x54\x96\x3f\x91\x14\x56\x47\x4c\xad\x68\x31\xe2\x80\xc0\x56\xd6\x84\x74\x89\x5c\x6c\x05\xa7\xbc\x8a\x5d\xc5\x76\x08\x3b\x79\x44\x55\x50\x3a\x50\xa2\xca\x85\xb6\xd5\xd4\x44\x2f\x25\x94\x4c\xe4\xc2\x30\xe3\x4e\x5e\x24\x55\xb7\xc8\x52\x7a\xb2\x84\x44\xc9\x03\xdd\x52\x51\x34\x41\x4b\x96\x1a\xd4\x66\xe2\xac\xa9\xea\xca\x2b\x95\x3c\x8a\x94\x32\xa7\x97\x23\xb9\xf7\x1c\xc8\x86\x60\xda\x73\xce\x0c\xcb\x24\x71\x51\xf2\x3f\xe4\x04\x71\xda\x0b\xbe\x07\xe7\xdf\x31\x83\x44\x14\xa9\x06\x7d\x2e\xf8\x5e\xc9\x42\x56\x3a\x3b\x13\x86\x6d\x1e\x59\x19\xe0\x0a\x99\xad\x0f\x99\x9e\xa9\xdf\xea\x42\x51\x1c\x66\x1c\xa1\x8c\x19\x2c\xb8\x40\x0d\x39\x32\x5d\xd9\x92\x53\x09\xe9\x4a\x6a\xcb\xa4\x69\xac\x96\x08\x97\x85\x51\x32\x83\x92\x04\x6b\x53\x0e\xef\x16\xf1\x7d\x38\x5f\x04\x50\xb0\x1c\x35\xf5\x18\xb6\x6e\x4d\x5e\x96\x64\x73\x62\xd5\x00\x2b\x07\x75\x8e\x36\xb6\xcb\xb5\x1d\xbc\xf5\x22\xdc\x2c\x7a\x48\xa7\x3d\xba\x72\x0e\x5d\x5c\x2a\xc4\xd4\x9e\x76\xb1\xc8\xfd\x7a\xbd\xfa\xb4\x58\xd7\xf1\x87\xa1\x27\x20\x8c\x86\x1b\x2c\x33\x79\xce\xb1\x30\x8e\x4a\x5c\xab\x4b\x8e\xd1\x5d\xf8\x91\xa2\x35\x34\x45\x4e\x1d\x60\xe7\x3c\xbc\x5e\xdc\x06\xa4\x4c\x82\x59\x0b\x27\x13\x5b\x00\xfd\x3a\xa1\x09\xa4\x98\xa1\xa9\xa7\x45\xb8\xd9\xfb\xf9\x75\xf4\xf0\xe0\x81\xd8\x82\x3f\x77\x31\x96\x9d\xb4\x8f\x8f\x23\x56\x8a\x66\xd1\x04\x70\xbc\x1c\x1d\xa8\xfe\x01\x74\x16\xa3\x1c\x0d\x4b\xa9\x60\xc1\x08\x9c\x24\x01\x3c\x3c\x80\x3f\x40\x80\x3a\x3b\x6d\x4d\xc0\x0e\xb8\x7f\xa8\x12\x6a\x7c\x4a\x42\xfb\x42\x4e\x73\x56\x90\x16\xa9\x97\x9c\x03\xea\x61\x67\x64\x31\x6e\x9d\x24\x8f\x8f\x01\x8c\x8d\xaa\x70\x3c\xf2\x3c\xcf\x11\x45\x92\xf5\x19\x31\x6b\x1f\xde\x47\xcd\xbb\xf6\x7b\x45\x20\xcb\x9a\xf3\xd3\xd9\x1b\xa4\xaf\xeb\xca\x38\xc6\x5d\xc5\xdf\x93\xcf\x4b\xf7\x37\x32\xb0\x2b\xc0\x7a\x29\x74\x4b\x4e\x07\x70\x49\x6f\x9a\xea\xc9\x8d\x54\x35\x5e\xce\x0c\xdf\xdf\xf6\x02\xbc\x11\xc2\x20\xad\x1c\x2a\x56\xe3\xd5\xcb\xc8\x3e\xd9\x00\xe0\x4d\x96\x2d\x21\xfb\xd8\x71\xa3\x2d\x4f\x12\xb6\x27\x5e\x23\x4e\xdd\xb2\x1d\x96\x6b\xdd\x1a\x2d\xb2\x5f\x5b\x30\x17\x47\xed\x7a\x51\x3d\xf0\xbc\x52\x2a\xd3\x3b\x18\x5f\xcd\xae\x66\xe3\xbe\x05\xcb\xb4\xa4\x71\x37\x52\x1b\x5a\x73\x4f\x61\xac\xe3\x00\xab\x23\x78\x4f\x37\x01\x58\xa0\xee\x96\xfa\x36\xa5\x2b\xad\xef\x95\x4c\xf0\xc9\x8b\x74\xe2\x65\x2c\xf9\x01\x4d\xff\xb0\x46\x7f\x81\xa1\x65\xa5\x68\x49\xf4\x2d\xed\x6f\x13\x4d\x9f\x1e\x7a\xf3\xb2\xa2\xda\xcd\x66\xf9\xe0\x34\xc7\x5c\x2a\x6a\xe2\x3f\xff\xb8\x13\xae\x5b\x5f\x9d\x9d\x76\x23\xfc\xca\x26\xfc\x61\xb7\x0d\xfb\xeb\xd5\x4e\xe8\xb4\xf6\x3a\x61\x9c\xb1\xa1\x7a\xa2\xe9\xe9\xfd\x3c\xab\xe6\xc7\xa3\x59\xd9\xfe\xe1\xca\x4d\xf4\xf1\x32\xa1\xe4\xda\x84\xe7\x59\xa5\x0d\xaa\x26\xef\x3a\xec\x3b\xb2\x7f\x77\x7e\x95\xca\x02\xf7\xd7\x83\xfe\x79\x0c\x40\xfc\xe7\xaa\xf9\xfa\xc8\x7d\x5e\xd3\xf1\x33\xc9\x59\x36\xfa\x0e\x30\x40\x89\x46\xcc\x09\x00\x00")
+
+func templatesInstallerLoadtestBrokerYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesInstallerLoadtestBrokerYamlTmpl,
+		"templates/installer/loadtest-broker.yaml.tmpl",
+	)
+}
+
+func templatesInstallerLoadtestBrokerYamlTmpl() (*asset, error) {
+	bytes, err := templatesInstallerLoadtestBrokerYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/installer/loadtest-broker.yaml.tmpl", size: 2508, mode: os.FileMode(420), modTime: time.Unix(1792030117, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesOnboardNamespaceYamlTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xdd\x56\xc1\x6e\xdb\x38\x10\xbd\xfb\x2b\x06\xca\x65\x17\xb0\xe4\xb6\xa7\x42\x3d\x39\x6e\xb6\x2b\xb4\x70\x16\xb6\xbb\x45\x51\xf4\x40\x49\x63\x99\x1b\x4a\xd4\x92\x54\x5c\x6f\xd0\x7f\xdf\x19\x8a\x4a\xe4\x3a\x87\x04\xe9\x16\xc5\xea\x22\x91\x9c\x79\xf3\x66\xe6\x0d\xed\xb3\xb3\xa7\x3e\x93\x33\x58\xe8\xf6\x60\x64\xb5\x73\xf0\xe2\xd9\xf3\x97\xf0\x46\xeb\x4a\x21\x64\x4d\x91\x4c\xf8\xf8\x9d\x2c\xb0\xb1\x58\x42\xd7\x94\x68\xc0\xed\x10\xe6\xad\x28\xe8\x15\x4e\xa6\xf0\x27\x1a\x2b\x75\x03\x2f\x92\x67\xf0\x0b\x1b\x44\xe1\x28\xfa\xf5\x15\x21\x1c\x74\x07\xb5\x38\x40\xa3\x1d\x74\x16\x09\x42\x5a\xd8\x4a\x0a\x82\x5f\x0a\x6c\x1d\xc8\x06\x0a\x5d\xb7\x4a\x8a\xa6\x40\xd8\x4b\xb7\xf3\x61\x02\x08\xd1\x80\x8f\x01\x42\xe7\x4e\x90\xb5\x20\xfb\x96\x56\xdb\xb1\x1d\x08\xe7\x09\xf3\xb3\x73\xae\xb5\xe9\x6c\xb6\xdf\xef\x13\xe1\xd9\x26\xda\x54\x33\xd5\x5b\xda\xd9\xbb\x6c\x71\xb1\x5c\x5f\xc4\xc4\xd8\xfb\xbc\x6f\x14\x5a\x0b\x06\xff\xee\xa4\xa1\x5c\xf3\x03\x88\x96\x08\x15\x22\x27\x9a\x4a\xec\x41\x1b\x10\x95\x41\x3a\x73\x9a\x09\xef\x8d\x74\xb2\xa9\xa6\x60\xf5\xd6\xed\x85\x41\x42\x29\xa5\x75\x46\xe6\x9d\x3b\xaa\xd6\x40\x8f\x92\x1e\x1b\x50\xbd\x44\x03\xd1\x7c\x0d\xd9\x3a\x82\xf3\xf9\x3a\x5b\x4f\x09\xe3\x43\xb6\xf9\xfd\xf2\xfd\x06\x3e\xcc\x57\xab\xf9\x72\x93\x5d\xac\xe1\x72\x05\x8b\xcb\xe5\xeb\x6c\x93\x5d\x2e\x69\xf5\x1b\xcc\x97\x1f\xe1\x6d\xb6\x7c\x3d\x05\xa4\x5a\x51\x18\xfc\xd2\x1a\xe6\x4f\x24\x25\xd7\x11\x4b\x2e\xda\x1a\xf1\x88\xc0\x56\xf7\x84\x6c\x8b\x85\xdc\xca\x82\xf2\x6a\xaa\x4e\x54\x08\x95\xbe\x46\xd3\x50\x3a\xd0\xa2\xa9\xa5\xe5\x6e\x5a\xa2\x57\x12\x8a\x92\xb5\x74\xc2\xf9\x9d\x93\xa4\x7a\x89\x2c\x45\x8d\x96\xaa\x8c\xdc\x11\x01\x25\x5e\xa3\xd2\x2d\x5b\xa2\xa8\x29\xd1\x5c\x0b\x53\x52\xca\xbe\xb3\xb6\x18\x76\xe2\x66\xf0\xe3\xc4\x29\x9a\x47\x5e\x9d\xcf\x17\xa0\xd0\x71\x75\xfd\x86\x07\xe9\x75\x43\xdc\xd1\x5c\x53\x68\x28\x84\x13\x4a\x57\xdc\x09\xe9\x52\xc0\x52\x52\xf3\xbd\xc5\xba\xb7\xc8\x1a\xeb\x58\x4e\x3e\x8d\x61\xf3\x5c\x36\x25\xc1\xda\x41\x39\xb7\x04\xbc\x91\x41\x51\x06\x90\xdc\xe8\x2b\x12\xf5\x14\x0a\x25\xac\x0d\x20\x2d\xd5\xcb\xf2\x31\x89\xc3\x20\xb4\x46\x5f\x4b\xae\x14\x65\xb6\x35\xba\x4e\x60\xb3\x63\x15\x2c\x54\x67\x1d\x9a\x95\x56\xec\x47\x86\x76\x27\x06\x4d\x29\x35\x2a\xc7\x6d\x74\xfb\xca\x07\xe5\x24\xc0\x90\x1b\x49\x25\xf0\x20\x70\xe6\x1a\xc7\x26\x17\x45\x5c\x93\x26\x89\xbe\x2f\xfa\xd3\x27\x5f\xb4\x32\x0c\x6e\x0a\xd7\xcf\x27\x57\x54\x9b\xf4\xae\x95\x93\x1a\x9d\x28\xa9\xca\xe9\x04\x3c\xd3\x14\x6e\x6e\x20\xb9\x6b\xf5\xd7\xaf\x74\xa0\x44\x8e\xca\xb2\x09\xf0\xc0\x24\x57\x5d\x4e\x42\x42\x87\x36\x91\x7a\x56\x8b\x86\xd4\x55\xc6\xf9\x21\xa5\xb6\x4f\xe2\x38\x3e\x0a\xca\x78\xf3\x3f\xb2\xb0\xb6\x89\xef\x3c\xc1\xf6\x4c\x46\x65\xbc\x87\x4b\x14\x84\x10\x74\x90\x5c\xbd\xe4\x88\x29\x97\x30\x9a\x98\x8e\x4a\x9f\x4e\x62\xa2\x24\xdf\x18\xdd\xd1\x55\x00\x9f\xee\xf7\x88\x3e\x13\x24\xcd\x8e\xee\x0c\xf5\x61\x64\x26\x07\xfd\x44\xd3\xdb\x60\x79\x90\x8f\x77\xa2\x89\xc9\xc9\x81\x9f\x4f\x51\x41\xda\x71\xc8\xa6\x25\x92\x76\x47\x5f\x85\x56\x0a\x0b\x1e\x1f\xde\xab\xd0\xf1\x4b\xd1\x3d\xc0\xef\x56\xb8\x62\xc7\x1f\x5d\x5b\x06\xff\xbd\xdf\xfa\xfc\x9d\xc8\xcf\xe8\xed\xba\xfb\x72\x18\x4e\x4e\x52\xf9\x86\xe2\x77\xe1\x13\xc6\x69\x44\x23\xcc\xd5\x68\xc7\x4f\x17\xaf\x8b\xbe\xf1\xa7\xae\xc7\x07\x23\x84\xe3\x83\x1e\xe8\xc1\x79\x3d\x4a\x94\xac\xc6\x70\x89\x3c\x56\x94\x70\x37\xef\xf7\x8c\x12\x8f\xfd\x0a\xb7\x8c\x35\xd4\x39\x05\x1e\xfa\x44\x74\x6e\xa7\x8d\xfc\xc7\x5f\xc1\x01\x92\xac\x4e\x87\xe4\x41\x2c\x6c\x97\xff\x45\x72\xa4\xe9\xb8\xb9\x89\xc1\xd0\x0f\x00\x42\xb2\x0e\x9b\x4c\x24\x0e\xc8\xcc\xf0\x2d\x7d\xf5\x73\x1e\x90\x07\xda\xb4\x19\x79\x00\xb9\x3d\xb9\x12\x46\x59\x46\xdf\xa6\xd9\x3b\xd1\x8d\x11\x6c\x1f\x92\xaa\xf7\xe8\x79\x8c\x3e\x7f\xc0\x5d\x42\xca\xdb\xd3\x5f\x98\xa7\xdd\x26\x3f\xb3\x98\x47\x55\x79\xb4\xa6\xfb\xe2\xa4\xa7\x0d\xfe\xef\x85\x3c\xf4\xe5\xff\x22\xe5\x7f\x01\x73\x3b\x43\x05\xc5\x0b\x00\x00")
+
+func templatesOnboardNamespaceYamlTmplBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesOnboardNamespaceYamlTmpl,
+		"templates/onboard/namespace.yaml.tmpl",
+	)
+}
+
+func templatesOnboardNamespaceYamlTmpl() (*asset, error) {
+	bytes, err := templatesOnboardNamespaceYamlTmplBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/onboard/namespace.yaml.tmpl", size: 3013, mode: os.FileMode(420), modTime: time.Unix(1792027512, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _gopkgLock = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x96\xcd\x72\xdb\x36\x10\xc7\xef\x7e\x0a\x8e\x7a\xc8\x25\xa6\xf0\xfd\x31\x9d\x3c\x42\x6f\xbd\x79\x32\x99\xc5\x62\x41\x31\xa1\x08\x0e\x09\xb9\x76\x9f\xbe\xa0\x3c\xaa\x5d\x53\x49\x95\x69\x4f\xe2\x62\xa9\xc5\x0f\x8b\xff\xee\xf2\x97\xe6\xf7\x43\xbf\x34\xa9\x1f\xa8\xa9\xbf\x70\x2a\xb9\xa3\x91\x66\x28\x14\x3f\x36\x31\x37\x63\x2e\x0d\xc5\xbe\xfc\xda\xe0\x01\xc6\x8e\x96\xe6\x08\xcf\x4d\xa0\xe6\x34\xc6\x3c\x52\x13\x9e\x9b\x72\xa0\x66\xa4\xa7\xd2\x7c\x88\x34\x35\x34\x2e\xa7\x99\x3e\xb4\x77\x77\x77\x0f\x0f\xd3\x9c\xbf\x12\x96\xe5\xf3\xe7\xbb\xa6\x19\xe1\x48\xcd\xa7\x66\x87\x43\x3e\xc5\xb6\xcb\xb9\x1b\xa8\xc5\x7c\xdc\x77\x79\x57\xdd\x13\xe0\x37\x58\x37\xf8\xd4\x3c\xec\xea\xf2\x74\x2a\xb4\x3f\x52\x81\x08\x05\x76\x6b\x80\x99\x1e\xfb\xa5\xcf\xe3\x1a\xc4\x1a\x8b\x8a\x45\x03\x82\x98\x76\xca\x49\x14\x3a\x01\xf7\x92\xbc\x91\x20\x44\x04\x6e\x85\x34\x71\x8d\xfc\x48\xf3\xe5\x6f\x8f\xac\xe5\xae\x65\xbb\xef\xc2\x75\x7d\x39\x9c\xc2\x19\xeb\x37\x58\x0a\xcd\xc7\x7e\x8c\xcb\x7e\xa1\x63\x8d\xb2\xc1\x6c\x37\x5c\x5c\x47\xa7\x24\x83\xe0\x8c\xf2\x16\x35\xb2\x08\x4e\xd8\x60\x95\x73\x42\x7a\xa5\x89\x63\xe2\xc4\xdf\x73\xf1\x56\xdd\x88\xd5\x1d\x72\x5c\x96\xfd\x33\x1c\x87\x1b\x78\x18\x82\x27\xd0\x31\x69\xa5\x79\x4a\x31\x71\xa7\x82\x52\xc2\xa1\x67\xc2\x2a\x8b\x02\x39\xc7\x68\xb7\x3c\xec\x1a\x4f\x98\x61\xc4\xc3\xfa\xc6\xf1\x9c\x9d\xdd\x77\x18\xf3\x50\xc5\xb2\xef\x86\xdc\xdd\xc0\x28\x64\xa4\xa4\xc8\x20\xaf\x68\x35\x5d\x80\x82\xa2\x63\xcc\x4a\x69\x03\x6a\x0a\xba\x3e\x7b\xef\x6e\x4b\xcf\xcb\xd6\xf5\xb5\x92\xc3\x29\x6d\xb6\x3f\x3b\x36\x08\x5e\x68\xad\xb8\x16\x1e\x79\x02\xe3\x04\x8f\x15\x48\x29\x24\x51\x55\x24\xb9\xa7\x20\x02\x59\xe3\x6e\x4c\xd3\x15\xae\x7e\xc4\x3c\x2e\x87\xba\x29\x41\x18\xaa\xb2\xf3\x69\xa1\x32\xc3\x74\x43\x82\xac\x31\xc2\x00\x79\xf4\x1c\x55\x12\xc0\x59\x92\x0a\x21\x3a\x24\x27\x09\x94\x40\x2f\x43\xb0\xfa\x0a\xdd\x4d\x6c\xcb\x94\xb8\xdc\x63\xae\xb7\x7b\x0b\x4d\x10\xa8\x01\x7d\x42\xa6\x52\xbd\x9e\x04\x29\x18\x66\xeb\x1d\x71\x19\x55\x4d\x1f\xf3\xc1\x5a\x1b\xb6\xa5\x57\x8b\xef\x27\x78\xa6\x34\xc0\x2d\xf2\x21\x6d\x49\x12\x05\x29\x93\xf5\x5a\x30\x85\x1c\x41\xea\xa4\x0d\x2a\x95\x9c\x14\xc2\xa2\x21\x63\xfe\x0f\x89\x9f\xc5\xd5\xe6\xb9\xdb\x3f\xed\x47\x2a\xef\xe9\xaa\xd9\xd4\x06\x97\xc7\x52\xfb\xe1\xee\xe3\x3f\xcc\x3d\x96\xa7\x43\x29\xe7\xfb\xde\x74\x0d\x59\x1b\x99\x46\xa1\x54\x94\x8c\x7b\xe5\x04\xd4\xa3\x44\x2b\x1c\xe7\xb5\x4c\x5d\x34\x01\x98\x07\xf5\x1f\x68\x73\xed\xed\x07\x71\x1d\xb8\xbd\xa0\xbe\x34\xe5\x8b\xd5\x57\xee\x79\x84\xe1\x62\x7f\xfd\x63\x79\x7d\x2c\xd7\xce\xa1\x95\x24\x69\x1d\x17\x89\x33\x54\x06\x8d\xa8\xd2\xd4\x56\x43\x8a\x16\xa2\x10\x49\x54\xf1\x8a\x00\x3f\x50\xc1\xcb\x58\x78\x83\x0e\xd3\x44\x63\xd7\x8f\xf4\x2f\xec\xef\x69\x2f\xf6\x1a\xe0\x4b\x1f\x69\x2c\x7d\x79\xde\x38\x03\x2c\x9b\xf3\xee\xd7\x89\xb3\x94\x3c\x6f\x3d\x6b\x43\x7b\xbf\x76\xcc\xf1\x34\xd0\xb2\x59\x9f\xe9\x98\x0b\x7d\x81\xa9\xdf\xb8\x4e\xf3\x90\xa8\xe0\xe1\xe2\xf8\xdb\xbe\xa6\x0d\xcd\x22\x6a\x0b\x3c\x28\x29\xc9\xd4\x2e\x55\xe7\x8b\x08\xb1\x8e\xbf\x60\x42\x70\x50\x0b\x50\x72\x09\x3f\xdb\x9a\xf2\xf4\xad\x6b\xfb\xf1\x3c\x48\xda\x47\x71\x43\xa1\x69\x25\x18\xb8\x60\xec\x2a\xd3\xc0\xa4\xd2\x10\x84\x97\xc9\x24\x0c\x75\xf4\xa2\xb7\x2e\x71\xee\xe4\x7b\x12\xd1\x8a\x97\xc2\x5f\xf2\xf0\x48\xf7\xeb\x4c\x5f\x43\x43\xcd\xc4\xf3\x9f\x34\xdf\x5f\x90\xea\xe7\xc3\xee\xed\xfa\x6b\x0c\x5e\x97\xfb\xb1\x7e\x11\x2c\xf7\xb1\xaf\x80\x65\x7d\xbd\x4e\x8c\x20\x2c\x07\x9e\x7c\x04\x29\xea\xa4\xd0\xde\x4a\x67\x6a\xf9\x38\x83\x68\x45\xaa\x02\xac\x9d\x3b\x22\x32\xd0\x92\x58\x2d\xa5\x9a\x29\x6e\xea\x28\x31\x75\xc0\xac\x7b\x9d\x89\x5e\x09\xba\x69\xb9\xc7\x88\xc3\x1b\xd7\x5b\x88\xbf\x00\xec\xf1\xc7\xd6\x2e\x09\x00\x00")
+
+func gopkgLockBytes() ([]byte, error) {
+	return bindataRead(
+		_gopkgLock,
+		"Gopkg.lock",
+	)
+}
+
+func gopkgLock() (*asset, error) {
+	bytes, err := gopkgLockBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "Gopkg.lock", size: 2350, mode: os.FileMode(420), modTime: time.Unix(1792030753, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -665,22 +1127,38 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
+	"templates/sc/affinity.tmpl":                                 templatesScAffinityTmpl,
 	"templates/sc/api-registration.yaml.tmpl":                    templatesScApiRegistrationYamlTmpl,
+	"templates/sc/apiserver-authn-ca.yaml.tmpl":                  templatesScApiserverAuthnCaYamlTmpl,
 	"templates/sc/apiserver-deployment.yaml.tmpl":                templatesScApiserverDeploymentYamlTmpl,
-	"templates/sc/ca_config.json":                                templatesScCa_configJson,
+	"templates/sc/apiserver-encryption-config.yaml.tmpl":         templatesScApiserverEncryptionConfigYamlTmpl,
+	"templates/sc/binding-secret-policy.yaml.tmpl":               templatesScBindingSecretPolicyYamlTmpl,
+	"templates/sc/ca-secret.yaml.tmpl":                           templatesScCaSecretYamlTmpl,
+	"templates/sc/ca_config.json.tmpl":                           templatesScCa_configJsonTmpl,
 	"templates/sc/ca_csr.json.tmpl":                              templatesScCa_csrJsonTmpl,
+	"templates/sc/catalog-rbac.yaml.tmpl":                        templatesScCatalogRbacYamlTmpl,
+	"templates/sc/controller-manager-config.yaml.tmpl":           templatesScControllerManagerConfigYamlTmpl,
 	"templates/sc/controller-manager-deployment.yaml.tmpl":       templatesScControllerManagerDeploymentYamlTmpl,
+	"templates/sc/controller-manager-proxy-ca.yaml.tmpl":         templatesScControllerManagerProxyCaYamlTmpl,
 	"templates/sc/etcd-cluster-with-backup.yaml.tmpl":            templatesScEtcdClusterWithBackupYamlTmpl,
-	"templates/sc/etcd-operator-deployment.yaml.tmpl":            templatesScEtcdOperatorDeploymentYamlTmpl,
-	"templates/sc/etcd-operator-rbac-binding.yaml.tmpl":          templatesScEtcdOperatorRbacBindingYamlTmpl,
-	"templates/sc/etcd-operator-rbac.yaml.tmpl":                  templatesScEtcdOperatorRbacYamlTmpl,
-	"templates/sc/etcd-operator-service-account.yaml.tmpl":       templatesScEtcdOperatorServiceAccountYamlTmpl,
+	"templates/sc/etcd-maintenance.yaml.tmpl":                    templatesScEtcdMaintenanceYamlTmpl,
+	"templates/sc/etcd-migration.yaml.tmpl":                      templatesScEtcdMigrationYamlTmpl,
+	"templates/sc/etcd-operator.yaml.tmpl":                       templatesScEtcdOperatorYamlTmpl,
 	"templates/sc/etcd-svc.yaml.tmpl":                            templatesScEtcdSvcYamlTmpl,
 	"templates/sc/etcd.yaml.tmpl":                                templatesScEtcdYamlTmpl,
 	"templates/sc/gencert_config.json.tmpl":                      templatesScGencert_configJsonTmpl,
+	"templates/sc/hpa.yaml.tmpl":                                 templatesScHpaYamlTmpl,
+	"templates/sc/instance-quota.yaml.tmpl":                      templatesScInstanceQuotaYamlTmpl,
 	"templates/sc/namespace.yaml.tmpl":                           templatesScNamespaceYamlTmpl,
+	"templates/sc/network-policy.yaml.tmpl":                      templatesScNetworkPolicyYamlTmpl,
+	"templates/sc/pdb.yaml.tmpl":                                 templatesScPdbYamlTmpl,
+	"templates/sc/plan-policy.yaml.tmpl":                         templatesScPlanPolicyYamlTmpl,
+	"templates/sc/priority-class.yaml.tmpl":                      templatesScPriorityClassYamlTmpl,
 	"templates/sc/rbac.yaml.tmpl":                                templatesScRbacYamlTmpl,
+	"templates/sc/secret-sync-rbac.yaml.tmpl":                    templatesScSecretSyncRbacYamlTmpl,
+	"templates/sc/secret-sync.yaml.tmpl":                         templatesScSecretSyncYamlTmpl,
 	"templates/sc/service-accounts.yaml.tmpl":                    templatesScServiceAccountsYamlTmpl,
+	"templates/sc/service-monitor.yaml.tmpl":                     templatesScServiceMonitorYamlTmpl,
 	"templates/sc/service.yaml.tmpl":                             templatesScServiceYamlTmpl,
 	"templates/sc/tls-cert-secret.yaml.tmpl":                     templatesScTlsCertSecretYamlTmpl,
 	"templates/gcp/gcp-broker.yaml.tmpl":                         templatesGcpGcpBrokerYamlTmpl,
@@ -691,6 +1169,12 @@ var _bindata = map[string]func() (*asset, error){
 	"templates/gcp/service-account-secret.yaml.tmpl":             templatesGcpServiceAccountSecretYamlTmpl,
 	"templates/gcp-deprecated/google-oauth-deployment.yaml.tmpl": templatesGcpDeprecatedGoogleOauthDeploymentYamlTmpl,
 	"templates/gcp-deprecated/service-account-secret.yaml.tmpl":  templatesGcpDeprecatedServiceAccountSecretYamlTmpl,
+	"templates/installer/bootstrap-job.yaml.tmpl":                templatesInstallerBootstrapJobYamlTmpl,
+	"templates/installer/gitops-argocd.yaml.tmpl":                templatesInstallerGitopsArgocdYamlTmpl,
+	"templates/installer/gitops-flux.yaml.tmpl":                  templatesInstallerGitopsFluxYamlTmpl,
+	"templates/installer/loadtest-broker.yaml.tmpl":              templatesInstallerLoadtestBrokerYamlTmpl,
+	"templates/onboard/namespace.yaml.tmpl":                      templatesOnboardNamespaceYamlTmpl,
+	"Gopkg.lock":                                                 gopkgLock,
 }
 
 // AssetDir returns the file names below a certain
@@ -734,6 +1218,7 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
+	"Gopkg.lock": &bintree{gopkgLock, map[string]*bintree{}},
 	"templates": &bintree{nil, map[string]*bintree{
 		"gcp": &bintree{nil, map[string]*bintree{
 			"gcp-broker.yaml.tmpl":                   &bintree{templatesGcpGcpBrokerYamlTmpl, map[string]*bintree{}},
@@ -747,23 +1232,48 @@ var _bintree = &bintree{nil, map[string]*bintree{
 			"google-oauth-deployment.yaml.tmpl": &bintree{templatesGcpDeprecatedGoogleOauthDeploymentYamlTmpl, map[string]*bintree{}},
 			"service-account-secret.yaml.tmpl":  &bintree{templatesGcpDeprecatedServiceAccountSecretYamlTmpl, map[string]*bintree{}},
 		}},
+		"installer": &bintree{nil, map[string]*bintree{
+			"bootstrap-job.yaml.tmpl":   &bintree{templatesInstallerBootstrapJobYamlTmpl, map[string]*bintree{}},
+			"gitops-argocd.yaml.tmpl":   &bintree{templatesInstallerGitopsArgocdYamlTmpl, map[string]*bintree{}},
+			"gitops-flux.yaml.tmpl":     &bintree{templatesInstallerGitopsFluxYamlTmpl, map[string]*bintree{}},
+			"loadtest-broker.yaml.tmpl": &bintree{templatesInstallerLoadtestBrokerYamlTmpl, map[string]*bintree{}},
+		}},
+		"onboard": &bintree{nil, map[string]*bintree{
+			"namespace.yaml.tmpl": &bintree{templatesOnboardNamespaceYamlTmpl, map[string]*bintree{}},
+		}},
 		"sc": &bintree{nil, map[string]*bintree{
+			"affinity.tmpl":                           &bintree{templatesScAffinityTmpl, map[string]*bintree{}},
 			"api-registration.yaml.tmpl":              &bintree{templatesScApiRegistrationYamlTmpl, map[string]*bintree{}},
+			"apiserver-authn-ca.yaml.tmpl":            &bintree{templatesScApiserverAuthnCaYamlTmpl, map[string]*bintree{}},
 			"apiserver-deployment.yaml.tmpl":          &bintree{templatesScApiserverDeploymentYamlTmpl, map[string]*bintree{}},
-			"ca_config.json":                          &bintree{templatesScCa_configJson, map[string]*bintree{}},
+			"apiserver-encryption-config.yaml.tmpl":   &bintree{templatesScApiserverEncryptionConfigYamlTmpl, map[string]*bintree{}},
+			"binding-secret-policy.yaml.tmpl":         &bintree{templatesScBindingSecretPolicyYamlTmpl, map[string]*bintree{}},
+			"ca-secret.yaml.tmpl":                     &bintree{templatesScCaSecretYamlTmpl, map[string]*bintree{}},
+			"ca_config.json.tmpl":                     &bintree{templatesScCa_configJsonTmpl, map[string]*bintree{}},
 			"ca_csr.json.tmpl":                        &bintree{templatesScCa_csrJsonTmpl, map[string]*bintree{}},
+			"catalog-rbac.yaml.tmpl":                  &bintree{templatesScCatalogRbacYamlTmpl, map[string]*bintree{}},
+			"controller-manager-config.yaml.tmpl":     &bintree{templatesScControllerManagerConfigYamlTmpl, map[string]*bintree{}},
 			"controller-manager-deployment.yaml.tmpl": &bintree{templatesScControllerManagerDeploymentYamlTmpl, map[string]*bintree{}},
+			"controller-manager-proxy-ca.yaml.tmpl":   &bintree{templatesScControllerManagerProxyCaYamlTmpl, map[string]*bintree{}},
 			"etcd-cluster-with-backup.yaml.tmpl":      &bintree{templatesScEtcdClusterWithBackupYamlTmpl, map[string]*bintree{}},
-			"etcd-operator-deployment.yaml.tmpl":      &bintree{templatesScEtcdOperatorDeploymentYamlTmpl, map[string]*bintree{}},
-			"etcd-operator-rbac-binding.yaml.tmpl":    &bintree{templatesScEtcdOperatorRbacBindingYamlTmpl, map[string]*bintree{}},
-			"etcd-operator-rbac.yaml.tmpl":            &bintree{templatesScEtcdOperatorRbacYamlTmpl, map[string]*bintree{}},
-			"etcd-operator-service-account.yaml.tmpl": &bintree{templatesScEtcdOperatorServiceAccountYamlTmpl, map[string]*bintree{}},
+			"etcd-maintenance.yaml.tmpl":              &bintree{templatesScEtcdMaintenanceYamlTmpl, map[string]*bintree{}},
+			"etcd-migration.yaml.tmpl":                &bintree{templatesScEtcdMigrationYamlTmpl, map[string]*bintree{}},
+			"etcd-operator.yaml.tmpl":                 &bintree{templatesScEtcdOperatorYamlTmpl, map[string]*bintree{}},
 			"etcd-svc.yaml.tmpl":                      &bintree{templatesScEtcdSvcYamlTmpl, map[string]*bintree{}},
 			"etcd.yaml.tmpl":                          &bintree{templatesScEtcdYamlTmpl, map[string]*bintree{}},
 			"gencert_config.json.tmpl":                &bintree{templatesScGencert_configJsonTmpl, map[string]*bintree{}},
+			"hpa.yaml.tmpl":                           &bintree{templatesScHpaYamlTmpl, map[string]*bintree{}},
+			"instance-quota.yaml.tmpl":                &bintree{templatesScInstanceQuotaYamlTmpl, map[string]*bintree{}},
 			"namespace.yaml.tmpl":                     &bintree{templatesScNamespaceYamlTmpl, map[string]*bintree{}},
+			"network-policy.yaml.tmpl":                &bintree{templatesScNetworkPolicyYamlTmpl, map[string]*bintree{}},
+			"pdb.yaml.tmpl":                           &bintree{templatesScPdbYamlTmpl, map[string]*bintree{}},
+			"plan-policy.yaml.tmpl":                   &bintree{templatesScPlanPolicyYamlTmpl, map[string]*bintree{}},
+			"priority-class.yaml.tmpl":                &bintree{templatesScPriorityClassYamlTmpl, map[string]*bintree{}},
 			"rbac.yaml.tmpl":                          &bintree{templatesScRbacYamlTmpl, map[string]*bintree{}},
+			"secret-sync-rbac.yaml.tmpl":              &bintree{templatesScSecretSyncRbacYamlTmpl, map[string]*bintree{}},
+			"secret-sync.yaml.tmpl":                   &bintree{templatesScSecretSyncYamlTmpl, map[string]*bintree{}},
 			"service-accounts.yaml.tmpl":              &bintree{templatesScServiceAccountsYamlTmpl, map[string]*bintree{}},
+			"service-monitor.yaml.tmpl":               &bintree{templatesScServiceMonitorYamlTmpl, map[string]*bintree{}},
 			"service.yaml.tmpl":                       &bintree{templatesScServiceYamlTmpl, map[string]*bintree{}},
 			"tls-cert-secret.yaml.tmpl":               &bintree{templatesScTlsCertSecretYamlTmpl, map[string]*bintree{}},
 		}},
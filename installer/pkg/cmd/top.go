@@ -0,0 +1,257 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// defaultNearLimit is the default fraction of a limit above which a
+// container is flagged as near its limit.
+const defaultNearLimit = 0.8
+
+type topConfig struct {
+	// Namespace of the Service Catalog pods.
+	Namespace string
+
+	// NearLimit is the fraction of a limit above which a container is
+	// flagged.
+	NearLimit float64
+}
+
+// containerUsage is the resource usage of a container next to its
+// requests and limits, CPU in cores and memory in bytes. Unset requests
+// and limits are zero.
+type containerUsage struct {
+	Pod       string
+	Container string
+
+	CPU, CPURequest, CPULimit          float64
+	Memory, MemoryRequest, MemoryLimit float64
+}
+
+// nearLimit returns the resources whose usage is above fraction of their
+// limit.
+func (u *containerUsage) nearLimit(fraction float64) []string {
+	var near []string
+	if u.CPULimit > 0 && u.CPU >= fraction*u.CPULimit {
+		near = append(near, "cpu")
+	}
+	if u.MemoryLimit > 0 && u.Memory >= fraction*u.MemoryLimit {
+		near = append(near, "memory")
+	}
+	return near
+}
+
+func NewTopCmd() *cobra.Command {
+	tc := &topConfig{}
+	c := &cobra.Command{
+		Use:   "top",
+		Short: "shows the resource usage of the Service Catalog pods",
+		Long: `shows the CPU and memory usage of the Service Catalog pods, as reported by
+metrics-server, next to their requests and limits, and flags the containers
+near their limits.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			usage, err := serviceCatalogUsage(tc.Namespace)
+			if err != nil {
+				messages.Println(messages.TopFailed)
+				return err
+			}
+			printUsage(os.Stdout, usage, tc.NearLimit)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&tc.Namespace, "namespace", "service-catalog", "Namespace of the Service Catalog pods")
+	c.Flags().Float64Var(&tc.NearLimit, "near-limit", defaultNearLimit, "Fraction of a limit above which a container is flagged")
+	return c
+}
+
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Containers []struct {
+			Name  string            `json:"name"`
+			Usage map[string]string `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+type podResourcesList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Containers []struct {
+				Name      string `json:"name"`
+				Resources struct {
+					Requests map[string]string `json:"requests"`
+					Limits   map[string]string `json:"limits"`
+				} `json:"resources"`
+			} `json:"containers"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+func serviceCatalogUsage(ns string) ([]*containerUsage, error) {
+	output, err := kubectlCommand("get", "--raw",
+		"/apis/metrics.k8s.io/v1beta1/namespaces/"+ns+"/pods").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting pod metrics, is metrics-server installed? %s", strings.TrimSpace(string(output)))
+	}
+	var metrics podMetricsList
+	if err := json.Unmarshal(output, &metrics); err != nil {
+		return nil, fmt.Errorf("error unmarshalling pod metrics: %v", err)
+	}
+
+	output, err = kubectlCommand("get", "pods", "--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting pods: %s", string(output))
+	}
+	var pods podResourcesList
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return nil, fmt.Errorf("error unmarshalling pods: %v", err)
+	}
+
+	return joinUsage(&metrics, &pods)
+}
+
+// joinUsage joins the container metrics with the container resources,
+// sorted by pod and container. Containers without metrics are skipped.
+func joinUsage(metrics *podMetricsList, pods *podResourcesList) ([]*containerUsage, error) {
+	byName := make(map[string]*containerUsage)
+	var usage []*containerUsage
+	for _, p := range metrics.Items {
+		for _, c := range p.Containers {
+			u := &containerUsage{Pod: p.Metadata.Name, Container: c.Name}
+			var err error
+			if u.CPU, err = parseQuantity(c.Usage["cpu"]); err != nil {
+				return nil, err
+			}
+			if u.Memory, err = parseQuantity(c.Usage["memory"]); err != nil {
+				return nil, err
+			}
+			byName[u.Pod+"/"+u.Container] = u
+			usage = append(usage, u)
+		}
+	}
+
+	for _, p := range pods.Items {
+		for _, c := range p.Spec.Containers {
+			u, ok := byName[p.Metadata.Name+"/"+c.Name]
+			if !ok {
+				continue
+			}
+			r := c.Resources
+			for _, q := range []struct {
+				dst *float64
+				s   string
+			}{
+				{&u.CPURequest, r.Requests["cpu"]},
+				{&u.CPULimit, r.Limits["cpu"]},
+				{&u.MemoryRequest, r.Requests["memory"]},
+				{&u.MemoryLimit, r.Limits["memory"]},
+			} {
+				v, err := parseQuantity(q.s)
+				if err != nil {
+					return nil, err
+				}
+				*q.dst = v
+			}
+		}
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Pod != usage[j].Pod {
+			return usage[i].Pod < usage[j].Pod
+		}
+		return usage[i].Container < usage[j].Container
+	})
+	return usage, nil
+}
+
+func printUsage(out io.Writer, usage []*containerUsage, nearLimit float64) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "POD\tCONTAINER\tCPU\tREQUEST\tLIMIT\tMEMORY\tREQUEST\tLIMIT\t")
+	for _, u := range usage {
+		var flag string
+		if near := u.nearLimit(nearLimit); len(near) > 0 {
+			flag = "near " + strings.Join(near, " and ") + " limit"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", u.Pod, u.Container,
+			formatCPU(u.CPU), formatCPU(u.CPURequest), formatCPU(u.CPULimit),
+			formatMemory(u.Memory), formatMemory(u.MemoryRequest), formatMemory(u.MemoryLimit), flag)
+	}
+	w.Flush()
+}
+
+func formatCPU(cores float64) string {
+	if cores == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0fm", cores*1000)
+}
+
+func formatMemory(bytes float64) string {
+	if bytes == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0fMi", bytes/(1<<20))
+}
+
+// quantitySuffixes are the multipliers of the Kubernetes resource quantity
+// suffixes, longest first.
+var quantitySuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40}, {"Pi", 1 << 50}, {"Ei", 1 << 60},
+	{"n", 1e-9}, {"u", 1e-6}, {"m", 1e-3},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12}, {"P", 1e15}, {"E", 1e18},
+}
+
+// parseQuantity parses a Kubernetes resource quantity such as 100m or 50Mi.
+// The empty quantity is zero.
+func parseQuantity(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	number, multiplier := s, 1.0
+	for _, q := range quantitySuffixes {
+		if strings.HasSuffix(s, q.suffix) {
+			number, multiplier = strings.TrimSuffix(s, q.suffix), q.multiplier
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	return v * multiplier, nil
+}
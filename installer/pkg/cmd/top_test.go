@@ -0,0 +1,102 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestParseQuantity tests that resource quantities are converted to cores
+// and bytes.
+func TestParseQuantity(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		want float64
+	}{
+		{"", 0},
+		{"2", 2},
+		{"100m", 0.1},
+		{"2500000n", 0.0025},
+		{"50Mi", 50 << 20},
+		{"1Gi", 1 << 30},
+		{"128974848", 128974848},
+		{"1e3", 1000},
+		{"1M", 1e6},
+	} {
+		got, err := parseQuantity(tc.s)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing %q: %v", tc.s, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Quantity %q does not match: got %v; want %v", tc.s, got, tc.want)
+		}
+	}
+
+	if _, err := parseQuantity("lots"); err == nil {
+		t.Fatalf("Expected an error parsing an invalid quantity")
+	}
+}
+
+// TestJoinUsage tests that metrics are joined with the container resources
+// and that containers near their limits are flagged.
+func TestJoinUsage(t *testing.T) {
+	var metrics podMetricsList
+	if err := json.Unmarshal([]byte(`{"items": [
+		{"metadata": {"name": "controller-manager-1"}, "containers": [
+			{"name": "controller-manager", "usage": {"cpu": "95m", "memory": "20Mi"}}
+		]},
+		{"metadata": {"name": "apiserver-1"}, "containers": [
+			{"name": "apiserver", "usage": {"cpu": "10m", "memory": "30Mi"}}
+		]}
+	]}`), &metrics); err != nil {
+		t.Fatalf("Unexpected error unmarshalling metrics: %v", err)
+	}
+	var pods podResourcesList
+	if err := json.Unmarshal([]byte(`{"items": [
+		{"metadata": {"name": "controller-manager-1"}, "spec": {"containers": [
+			{"name": "controller-manager", "resources": {"requests": {"cpu": "100m", "memory": "20Mi"}, "limits": {"cpu": "100m", "memory": "50Mi"}}}
+		]}},
+		{"metadata": {"name": "apiserver-1"}, "spec": {"containers": [
+			{"name": "apiserver", "resources": {}}
+		]}}
+	]}`), &pods); err != nil {
+		t.Fatalf("Unexpected error unmarshalling pods: %v", err)
+	}
+
+	usage, err := joinUsage(&metrics, &pods)
+	if err != nil {
+		t.Fatalf("Unexpected error joining usage: %v", err)
+	}
+	want := []*containerUsage{
+		{Pod: "apiserver-1", Container: "apiserver", CPU: 0.01, Memory: 30 << 20},
+		{Pod: "controller-manager-1", Container: "controller-manager",
+			CPU: 0.095, CPURequest: 0.1, CPULimit: 0.1,
+			Memory: 20 << 20, MemoryRequest: 20 << 20, MemoryLimit: 50 << 20},
+	}
+	if !reflect.DeepEqual(usage, want) {
+		t.Fatalf("Usage does not match: got %+v; want %+v", usage, want)
+	}
+
+	if got := usage[0].nearLimit(defaultNearLimit); len(got) != 0 {
+		t.Fatalf("Containers without limits must not be flagged: got %v", got)
+	}
+	if got, want := usage[1].nearLimit(defaultNearLimit), []string{"cpu"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Resources near limit do not match: got %v; want %v", got, want)
+	}
+}
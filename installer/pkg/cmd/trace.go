@@ -0,0 +1,179 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/trace"
+	"github.com/spf13/cobra"
+)
+
+// Names of the flags tracing the external commands and API calls of sc.
+const (
+	TraceFlagName             = "trace"
+	TraceOTLPEndpointFlagName = "trace-otlp-endpoint"
+)
+
+// traceState is the trace of the running command.
+type traceState struct {
+	// recorder is the trace file, nil if the phases are only exported.
+	recorder *trace.Recorder
+	exporter *trace.Exporter
+	name     string
+	start    time.Time
+
+	// transport makes the calls without tracing them, e.g. the export.
+	transport http.RoundTripper
+
+	// phases are the phases traced without a trace file.
+	mu     sync.Mutex
+	phases []*trace.Span
+}
+
+// record records the span of a phase.
+func (t *traceState) record(s *trace.Span) {
+	if t.recorder != nil {
+		t.recorder.Record(s)
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.phases = append(t.phases, s)
+}
+
+// spans returns the spans of the trace, sorted by start.
+func (t *traceState) spans() ([]*trace.Span, error) {
+	if t.recorder != nil {
+		return trace.Read(t.recorder.Path)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := append([]*trace.Span(nil), t.phases...)
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+	return spans, nil
+}
+
+// tracing is the trace of the running command, nil if it is not traced.
+var tracing *traceState
+
+// SetTrace traces the external commands, API calls and phases of the
+// command into the trace file of the trace flag of c, if set, and only the
+// phases if only the OpenTelemetry collector is set, by the flag or the
+// OTEL_EXPORTER_OTLP_ENDPOINT environment variable.
+func SetTrace(c *cobra.Command) error {
+	path, err := c.Flags().GetString(TraceFlagName)
+	if err != nil {
+		return err
+	}
+	endpoint, err := c.Flags().GetString(TraceOTLPEndpointFlagName)
+	if err != nil {
+		return err
+	}
+	exporter, err := trace.NewExporter(endpoint, "sc", os.Getenv)
+	if err != nil {
+		return fmt.Errorf("--%s: %v", TraceOTLPEndpointFlagName, err)
+	}
+	if path == "" && exporter == nil {
+		return nil
+	}
+	tracing = &traceState{exporter: exporter, name: c.CommandPath(), start: time.Now(), transport: http.DefaultTransport}
+	if path == "" {
+		return nil
+	}
+	r, err := trace.NewRecorder(path)
+	if err != nil {
+		return fmt.Errorf("--%s: %v", TraceFlagName, err)
+	}
+	e, err := trace.NewExecutor(execx.DefaultExecutor, r)
+	if err != nil {
+		return fmt.Errorf("--%s: %v", TraceFlagName, err)
+	}
+	execx.DefaultExecutor = e
+	http.DefaultTransport = &trace.Transport{Base: tracing.transport, Recorder: r}
+	tracing.recorder = r
+	return nil
+}
+
+// FinishTrace prints the timing breakdown of the traced command, which
+// failed if err is not nil, and exports its trace if an OpenTelemetry
+// collector was given. It does nothing if the command was not traced.
+func FinishTrace(err error) {
+	if tracing == nil {
+		return
+	}
+	elapsed := time.Since(tracing.start)
+	spans, rerr := tracing.spans()
+	if rerr != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: error reading trace: %v\n", rerr)
+		return
+	}
+	if tracing.recorder != nil {
+		// The breakdown goes to stderr, leaving the output of the command
+		// alone.
+		trace.Summary(os.Stderr, spans, elapsed)
+	}
+	if tracing.exporter != nil {
+		if eerr := tracing.exporter.Export(tracing.transport, tracing.name, tracing.start, tracing.start.Add(elapsed), err != nil, spans); eerr != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: %v\n", eerr)
+		}
+	}
+	if tracing.recorder != nil {
+		fmt.Fprintf(os.Stderr, "trace written to %s\n", tracing.recorder.Path)
+	}
+}
+
+// phaseTracer traces the phases of an operation, one after the other. It
+// does nothing if the command is not traced.
+type phaseTracer struct {
+	operation string
+	current   *trace.Span
+}
+
+// tracePhases returns the tracer of the phases of operation, e.g. install.
+func tracePhases(operation string) *phaseTracer {
+	return &phaseTracer{operation: operation}
+}
+
+// next ends the current phase, which succeeded, and starts phase name.
+func (p *phaseTracer) next(name string) {
+	p.end(nil)
+	if tracing == nil {
+		return
+	}
+	p.current = &trace.Span{Kind: trace.Phase, Name: p.operation + "/" + name, Start: time.Now()}
+}
+
+// end ends the current phase, which failed if err is not nil.
+func (p *phaseTracer) end(err error) {
+	s := p.current
+	if s == nil {
+		return
+	}
+	p.current = nil
+	s.Duration = time.Since(s.Start)
+	if err != nil {
+		s.Error = err.Error()
+	}
+	tracing.record(s)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/trace"
+	"github.com/spf13/cobra"
+)
+
+// TestSetTrace tests that with --trace the commands of sc are run by the
+// tracing executor, over the executor they were run by, and the API calls
+// and phases are recorded in the trace file. The recording of the commands
+// is tested in package trace.
+func TestSetTrace(t *testing.T) {
+	s, restore := stubExecutor(nil)
+	defer restore()
+	transport := http.DefaultTransport
+	defer func() {
+		http.DefaultTransport = transport
+		tracing = nil
+	}()
+
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "trace.jsonl")
+
+	c := traceCommand()
+	c.Flags().Set(TraceFlagName, path)
+	if err := SetTrace(c); err != nil {
+		t.Fatalf("Unexpected error setting the trace: %v", err)
+	}
+	if e, ok := execx.DefaultExecutor.(*trace.Executor); !ok || e.Base != s || e.Recorder.Path != path {
+		t.Fatalf("Expected the commands to be traced in %s, got %#v", path, execx.DefaultExecutor)
+	}
+	if tr, ok := http.DefaultTransport.(*trace.Transport); !ok || tr.Base != transport {
+		t.Fatalf("Expected the API calls to be traced, got %#v", http.DefaultTransport)
+	}
+	phases := tracePhases("install")
+	phases.next("deploy")
+	phases.end(errors.New("deploy failed"))
+	FinishTrace(nil)
+
+	spans, err := trace.Read(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading trace: %v", err)
+	}
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if s := spans[0]; s.Kind != trace.Phase || s.Name != "install/deploy" || s.Error != "deploy failed" {
+		t.Fatalf("Phase span does not match: got %+v", s)
+	}
+}
+
+// TestSetTraceFlagErrors tests that commands without the trace flags fail
+// instead of running untraced.
+func TestSetTraceFlagErrors(t *testing.T) {
+	defer func() { tracing = nil }()
+	if err := SetTrace(&cobra.Command{Use: "install"}); err == nil {
+		t.Fatalf("Expected an error without the trace flags")
+	}
+}
+
+// traceCommand returns a command with the trace flags.
+func traceCommand() *cobra.Command {
+	c := &cobra.Command{Use: "install"}
+	c.Flags().String(TraceFlagName, "", "")
+	c.Flags().String(TraceOTLPEndpointFlagName, "", "")
+	return c
+}
+
+// TestTracePhases tests that with only an OpenTelemetry collector the
+// phases are exported, failed with the error of the operation, and the
+// commands are not traced.
+func TestTracePhases(t *testing.T) {
+	executor := execx.DefaultExecutor
+	defer func() { tracing = nil }()
+	var names []string
+	var failed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []struct {
+						Name   string
+						Status struct {
+							Code    int
+							Message string
+						}
+					}
+				}
+			}
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+		for _, s := range body.ResourceSpans[0].ScopeSpans[0].Spans {
+			names = append(names, s.Name)
+			if s.Status.Code != 0 {
+				failed = append(failed, s.Name+": "+s.Status.Message)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	c := traceCommand()
+	c.Flags().Set(TraceOTLPEndpointFlagName, srv.URL)
+	if err := SetTrace(c); err != nil {
+		t.Fatalf("Unexpected error setting the trace: %v", err)
+	}
+	if execx.DefaultExecutor != executor {
+		t.Fatalf("Expected the commands not to be traced")
+	}
+	err := errors.New("deploy failed")
+	phases := tracePhases("install")
+	phases.next("preflight")
+	phases.next("deploy")
+	phases.end(err)
+	phases.end(nil)
+	FinishTrace(err)
+
+	if got, want := strings.Join(names, ","), "install,install/preflight,install/deploy"; got != want {
+		t.Fatalf("Spans do not match: got %q; want %q", got, want)
+	}
+	if got, want := strings.Join(failed, ","), "install: ,install/deploy: deploy failed"; got != want {
+		t.Fatalf("Failed spans do not match: got %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// uninstallReport is the record of an uninstall, written for change
+// tickets.
+type uninstallReport struct {
+	Namespace  string    `json:"namespace"`
+	Strategy   string    `json:"strategy"`
+	Cascade    bool      `json:"cascade"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Succeeded  bool      `json:"succeeded"`
+	Error      string    `json:"error,omitempty"`
+
+	Deleted  []reportObject `json:"deleted"`
+	NotFound []reportObject `json:"notFound"`
+	Skipped  []reportObject `json:"skipped"`
+	Failed   []reportObject `json:"failed"`
+
+	// Orphaned are the instances left when the catalog was deleted, whose
+	// external services the brokers were not asked to deprovision.
+	Orphaned []orphanedInstance `json:"orphaned"`
+
+	// Deprovisioned are the instances deleted with the graceful strategy,
+	// with the external resources their brokers destroyed.
+	Deprovisioned []externalResource `json:"deprovisioned"`
+
+	// Brokers were registered with the catalog and may run outside the
+	// cluster.
+	Brokers []reportBroker `json:"brokers"`
+
+	FollowUps []string `json:"followUps"`
+}
+
+// reportObject is an object of the report, with why it was skipped or
+// could not be deleted.
+type reportObject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func (o reportObject) String() string {
+	s := o.Kind + "/" + o.Name
+	if o.Namespace != "" {
+		s += " in " + o.Namespace
+	}
+	if o.Reason != "" {
+		s += ": " + o.Reason
+	}
+	return s
+}
+
+type orphanedInstance struct {
+	Instance    string   `json:"instance"`
+	Provisioned bool     `json:"provisioned"`
+	Bindings    []string `json:"bindings,omitempty"`
+}
+
+type reportBroker struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func newUninstallReport(ns, strategy string) *uninstallReport {
+	return &uninstallReport{
+		Namespace: ns,
+		Strategy:  strategy,
+		Cascade:   strategy == uninstallStrategyGraceful,
+		StartedAt: time.Now().UTC(),
+	}
+}
+
+// detectOrphans records the instances and brokers still registered with the
+// catalog, which are forgotten when it is deleted.
+func (r *uninstallReport) detectOrphans() error {
+	installed, err := isServiceCatalogInstalled()
+	if err != nil || !installed {
+		return err
+	}
+	instances, err := listInstances("")
+	if err != nil {
+		return err
+	}
+	for _, i := range instances {
+		r.Orphaned = append(r.Orphaned, orphanedInstance{Instance: i.String(), Provisioned: i.Provisioned, Bindings: i.Bindings})
+	}
+
+	output, err := kubectlCommand("get",
+		"clusterservicebrokers.servicecatalog.k8s.io,servicebrokers.servicecatalog.k8s.io",
+		"--all-namespaces", "-o", "json").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error listing brokers: %s", strings.TrimSpace(string(output)))
+	}
+	var brokers struct {
+		Items []struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Spec struct {
+				URL string `json:"url"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &brokers); err != nil {
+		return fmt.Errorf("error unmarshalling brokers: %v", err)
+	}
+	for _, b := range brokers.Items {
+		name := b.Metadata.Name
+		if b.Metadata.Namespace != "" {
+			name = b.Metadata.Namespace + "/" + name
+		}
+		r.Brokers = append(r.Brokers, reportBroker{Kind: b.Kind, Name: name, URL: b.Spec.URL})
+	}
+	return nil
+}
+
+// addSkippedComponents records the components left alone because they are
+// managed separately.
+func (r *uninstallReport) addSkippedComponents(components []string) {
+	for _, c := range components {
+		r.Skipped = append(r.Skipped, reportObject{Kind: "Component", Name: c, Reason: "managed separately, skipped at install"})
+	}
+}
+
+// addDeleteResults records the outcome of deleting the objects.
+func (r *uninstallReport) addDeleteResults(results []deleteResult) {
+	for _, res := range results {
+		o := reportObject{Kind: res.Object.Kind, Name: res.Object.Name, Namespace: res.Object.Namespace, Reason: res.Detail}
+		switch res.Outcome {
+		case deleteDeleted:
+			r.Deleted = append(r.Deleted, o)
+		case deleteNotFound:
+			r.NotFound = append(r.NotFound, o)
+		case deleteSkipped:
+			r.Skipped = append(r.Skipped, o)
+		default:
+			r.Failed = append(r.Failed, o)
+		}
+	}
+}
+
+// componentFollowUps are the manual actions left by skipped components.
+var componentFollowUps = map[string]string{
+	componentEtcd:            "delete the Service Catalog data from the external etcd",
+	componentRBAC:            "delete the roles and bindings of the service catalog service accounts, they were created separately",
+	componentAPIRegistration: "delete the APIService " + scAPIService + ", it was registered manually",
+}
+
+// finish records the end of the uninstall, failed with err if it is not
+// nil, and the follow-up actions it leaves.
+func (r *uninstallReport) finish(err error) {
+	r.FinishedAt = time.Now().UTC()
+	r.Succeeded = err == nil
+	r.FollowUps = nil
+	if err != nil {
+		r.Error = err.Error()
+		r.FollowUps = append(r.FollowUps, "fix the error and run sc uninstall again")
+	}
+	for _, o := range r.Failed {
+		r.FollowUps = append(r.FollowUps, fmt.Sprintf("delete %s/%s manually", o.Kind, o.Name))
+	}
+	for _, i := range r.Orphaned {
+		r.FollowUps = append(r.FollowUps, fmt.Sprintf("check with its broker that instance %s holds no external resources, it was not deprovisioned", i.Instance))
+	}
+	for _, b := range r.Brokers {
+		r.FollowUps = append(r.FollowUps, fmt.Sprintf("remove broker %s (%s) if it runs outside the cluster and is no longer needed", b.Name, b.URL))
+	}
+	for _, o := range r.Skipped {
+		if f, ok := componentFollowUps[o.Name]; ok && o.Kind == "Component" {
+			r.FollowUps = append(r.FollowUps, f)
+		}
+	}
+}
+
+// writeText writes r to w in a human readable form.
+func (r *uninstallReport) writeText(w io.Writer) {
+	fmt.Fprintf(w, "Service Catalog uninstall report\n\n")
+	fmt.Fprintf(w, "Namespace: %s\n", r.Namespace)
+	fmt.Fprintf(w, "Strategy:  %s\n", r.Strategy)
+	fmt.Fprintf(w, "Started:   %s\n", r.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "Finished:  %s\n", r.FinishedAt.Format(time.RFC3339))
+	if r.Succeeded {
+		fmt.Fprintf(w, "Result:    succeeded\n")
+	} else {
+		fmt.Fprintf(w, "Result:    failed: %s\n", r.Error)
+	}
+
+	section := func(title string, lines []string) {
+		fmt.Fprintf(w, "\n%s (%d):\n", title, len(lines))
+		for _, l := range lines {
+			fmt.Fprintf(w, "  %s\n", l)
+		}
+	}
+	objects := func(objs []reportObject) []string {
+		var lines []string
+		for _, o := range objs {
+			lines = append(lines, o.String())
+		}
+		return lines
+	}
+	section("Deleted", objects(r.Deleted))
+	section("Not found", objects(r.NotFound))
+	section("Skipped", objects(r.Skipped))
+	section("Failed", objects(r.Failed))
+
+	var orphaned []string
+	for _, i := range r.Orphaned {
+		line := i.Instance
+		if i.Provisioned {
+			line += ", provisioned"
+		}
+		if len(i.Bindings) > 0 {
+			line += ", bindings " + strings.Join(i.Bindings, ", ")
+		}
+		orphaned = append(orphaned, line)
+	}
+	section("Orphaned instances", orphaned)
+
+	var deprovisioned []string
+	for _, d := range r.Deprovisioned {
+		deprovisioned = append(deprovisioned, d.String())
+	}
+	section("Deprovisioned instances", deprovisioned)
+
+	var brokers []string
+	for _, b := range r.Brokers {
+		brokers = append(brokers, fmt.Sprintf("%s/%s at %s", b.Kind, b.Name, b.URL))
+	}
+	section("Brokers", brokers)
+
+	var followUps []string
+	for _, f := range r.FollowUps {
+		followUps = append(followUps, "- "+f)
+	}
+	section("Follow-up actions", followUps)
+}
+
+// writeUninstallReport writes r to dir as uninstall-report-<time>.json and
+// .txt.
+func writeUninstallReport(dir string, r *uninstallReport) error {
+	base := filepath.Join(dir, "uninstall-report-"+r.StartedAt.Format("20060102T150405Z"))
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(base+".json", append(b, '\n'), 0644); err != nil {
+		return err
+	}
+
+	f, err := os.Create(base + ".txt")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r.writeText(f)
+	fmt.Printf("wrote the uninstall report to %s.json and %s.txt\n", base, base)
+	return nil
+}
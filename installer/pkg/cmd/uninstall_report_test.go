@@ -0,0 +1,89 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+)
+
+// TestUninstallReport tests that the outcome of each object is recorded and
+// that the follow-up actions cover failures, orphans and skipped
+// components.
+func TestUninstallReport(t *testing.T) {
+	r := newUninstallReport("service-catalog", uninstallStrategySafe)
+	r.StartedAt = time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	r.Orphaned = []orphanedInstance{{Instance: "default/db", Bindings: []string{"db-binding"}}}
+	r.Deprovisioned = []externalResource{{Instance: "default/orders", Service: "Cloud SQL", Plan: "small", Resources: []string{"database orders"}}}
+	r.Brokers = []reportBroker{{Kind: "ClusterServiceBroker", Name: "gcp-broker", URL: "https://broker.example.com"}}
+	r.addSkippedComponents([]string{componentRBAC})
+	r.addDeleteResults([]deleteResult{
+		{&manifest.Object{Kind: "Deployment", Name: "apiserver", Namespace: "service-catalog"}, deleteDeleted, ""},
+		{&manifest.Object{Kind: "NetworkPolicy", Name: "etcd", Namespace: "service-catalog"}, deleteNotFound, ""},
+		{&manifest.Object{Kind: "Constraint", Name: "max-instances"}, deleteSkipped, "constraints.gatekeeper.sh/v1beta1 is not served"},
+		{&manifest.Object{Kind: "APIService", Name: scAPIService}, deleteFailed, "forbidden"},
+	})
+	r.finish(errors.New("error undeploying YAML files"))
+	r.FinishedAt = r.StartedAt.Add(time.Minute)
+
+	var b bytes.Buffer
+	r.writeText(&b)
+	want := `Service Catalog uninstall report
+
+Namespace: service-catalog
+Strategy:  safe
+Started:   2018-06-01T10:00:00Z
+Finished:  2018-06-01T10:01:00Z
+Result:    failed: error undeploying YAML files
+
+Deleted (1):
+  Deployment/apiserver in service-catalog
+
+Not found (1):
+  NetworkPolicy/etcd in service-catalog
+
+Skipped (2):
+  Component/rbac: managed separately, skipped at install
+  Constraint/max-instances: constraints.gatekeeper.sh/v1beta1 is not served
+
+Failed (1):
+  APIService/v1beta1.servicecatalog.k8s.io: forbidden
+
+Orphaned instances (1):
+  default/db, bindings db-binding
+
+Deprovisioned instances (1):
+  default/orders: Cloud SQL, plan small: database orders
+
+Brokers (1):
+  ClusterServiceBroker/gcp-broker at https://broker.example.com
+
+Follow-up actions (5):
+  - fix the error and run sc uninstall again
+  - delete APIService/v1beta1.servicecatalog.k8s.io manually
+  - check with its broker that instance default/db holds no external resources, it was not deprovisioned
+  - remove broker gcp-broker (https://broker.example.com) if it runs outside the cluster and is no longer needed
+  - delete the roles and bindings of the service catalog service accounts, they were created separately
+`
+	if got := b.String(); got != want {
+		t.Fatalf("Report does not match: got\n%s\nwant\n%s", got, want)
+	}
+}
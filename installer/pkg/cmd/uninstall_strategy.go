@@ -0,0 +1,148 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Strategies of uninstall for the ServiceInstances left in the catalog.
+const (
+	// uninstallStrategySafe fails if instances have bindings or
+	// provisioned resources, which would be orphaned.
+	uninstallStrategySafe = "safe"
+
+	// uninstallStrategyGraceful deletes the bindings and instances first,
+	// waiting for the brokers to unbind and deprovision them, e.g. on
+	// clusters holding real cloud resources.
+	uninstallStrategyGraceful = "graceful"
+
+	// uninstallStrategyFast stops the controller manager and deletes
+	// everything without contacting the brokers, stripping the
+	// finalizers, e.g. on dev clusters.
+	uninstallStrategyFast = "fast"
+)
+
+// clusterCatalogResources are the cluster-scoped Service Catalog resources
+// that may carry catalogFinalizer.
+var clusterCatalogResources = []string{
+	"clusterservicebrokers.servicecatalog.k8s.io",
+	"clusterserviceclasses.servicecatalog.k8s.io",
+	"clusterserviceplans.servicecatalog.k8s.io",
+}
+
+// uninstallStrategy returns the strategy of the --strategy and deprecated
+// --cascade flags.
+func uninstallStrategy(strategy string, cascade bool) (string, error) {
+	switch strategy {
+	case uninstallStrategySafe, uninstallStrategyGraceful, uninstallStrategyFast:
+	default:
+		return "", fmt.Errorf("--strategy must be safe, graceful or fast, got %q", strategy)
+	}
+	if !cascade {
+		return strategy, nil
+	}
+	if strategy == uninstallStrategyFast {
+		return "", fmt.Errorf("--cascade deprovisions the instances through their brokers, it can't be combined with --strategy fast")
+	}
+	return uninstallStrategyGraceful, nil
+}
+
+// prepareUninstall handles the instances of the catalog in namespace ns
+// before its objects are deleted, as strategy says. The graceful strategy
+// first shows the external resources the brokers will destroy and asks to
+// confirm, unless yes is set, recording them in r.
+func prepareUninstall(ns, strategy string, yes bool, r *uninstallReport) error {
+	if strategy == uninstallStrategySafe {
+		return checkInstancesBeforeUninstall(false)
+	}
+	installed, err := isServiceCatalogInstalled()
+	if err != nil || !installed {
+		return err
+	}
+	if strategy == uninstallStrategyFast {
+		return forceDeleteCatalogResources(ns)
+	}
+
+	resources, err := listExternalResources()
+	if err != nil {
+		return err
+	}
+	if err := confirmDeprovision(resources, yes, os.Stdin, os.Stdout); err != nil {
+		return err
+	}
+	r.Deprovisioned = resources
+	return checkInstancesBeforeUninstall(true)
+}
+
+// forceDeleteCatalogResources deletes all the Service Catalog resources
+// without deprovisioning: it stops the controller manager in namespace ns,
+// so that it does not call the brokers, deletes the resources and removes
+// the catalog finalizer from them. The broker resources of the instances
+// are left behind. If the catalog API is not served, e.g. its api server is
+// already gone, there is nothing it can delete.
+func forceDeleteCatalogResources(ns string) error {
+	fmt.Println("stopping the controller manager...")
+	output, err := kubectlCommand("scale", "deployment", "controller-manager",
+		"--namespace", ns, "--replicas", "0").CombinedOutput()
+	if err != nil && !strings.Contains(string(output), "NotFound") {
+		return fmt.Errorf("error stopping the controller manager: %s", strings.TrimSpace(string(output)))
+	}
+
+	info, err := getAPIServiceInfo()
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		fmt.Printf("APIService %s does not exist, no catalog resource to delete\n", scAPIService)
+		return nil
+	}
+	if reason := apiServiceUnavailable(info); reason != "" {
+		fmt.Printf("APIService %s is unavailable, no catalog resource can be deleted: %s\n", scAPIService, reason)
+		return nil
+	}
+
+	resources := append(append([]string{}, namespacedCatalogResources...), clusterCatalogResources...)
+	for _, r := range resources {
+		fmt.Printf("deleting all %s...\n", r)
+		output, err := kubectlCommand("delete", r, "--all", "--all-namespaces",
+			"--wait=false", "--ignore-not-found").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error deleting %s: %s", r, strings.TrimSpace(string(output)))
+		}
+
+		output, err = kubectlCommand("get", r, "--all-namespaces", "-o", "json").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error listing %s: %s", r, strings.TrimSpace(string(output)))
+		}
+		finalized, err := finalizedResources(output)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", r, err)
+		}
+		for _, res := range finalized {
+			name := strings.TrimPrefix(res.Namespace+"/"+res.Name, "/")
+			fmt.Printf("removing finalizer %s from %s %s\n", catalogFinalizer, r, name)
+			output, err := removeCatalogFinalizer(r, res)
+			if err != nil && !strings.Contains(string(output), "NotFound") {
+				return fmt.Errorf("error removing the finalizer of %s %s: %s", r, name, strings.TrimSpace(string(output)))
+			}
+		}
+	}
+	return nil
+}
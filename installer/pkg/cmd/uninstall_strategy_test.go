@@ -0,0 +1,45 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "testing"
+
+// TestUninstallStrategy tests that --cascade selects the graceful strategy
+// and that invalid combinations are rejected.
+func TestUninstallStrategy(t *testing.T) {
+	for _, tc := range []struct {
+		strategy string
+		cascade  bool
+		want     string
+		wantErr  bool
+	}{
+		{uninstallStrategySafe, false, uninstallStrategySafe, false},
+		{uninstallStrategySafe, true, uninstallStrategyGraceful, false},
+		{uninstallStrategyGraceful, true, uninstallStrategyGraceful, false},
+		{uninstallStrategyFast, false, uninstallStrategyFast, false},
+		{uninstallStrategyFast, true, "", true},
+		{"slow", false, "", true},
+	} {
+		got, err := uninstallStrategy(tc.strategy, tc.cascade)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("Unexpected error for --strategy %s --cascade=%v: %v", tc.strategy, tc.cascade, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Strategy for --strategy %s --cascade=%v does not match: got %q; want %q", tc.strategy, tc.cascade, got, tc.want)
+		}
+	}
+}
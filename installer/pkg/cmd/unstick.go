@@ -0,0 +1,333 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+// catalogFinalizer is the finalizer the controller manager puts on the
+// Service Catalog resources it manages.
+const catalogFinalizer = "kubernetes-incubator/service-catalog"
+
+// namespacedCatalogResources are the namespaced Service Catalog resources
+// that may carry catalogFinalizer.
+var namespacedCatalogResources = []string{
+	"servicebindings.servicecatalog.k8s.io",
+	"serviceinstances.servicecatalog.k8s.io",
+	"servicebrokers.servicecatalog.k8s.io",
+}
+
+// unstickConfig contains the unstick-namespace configuration.
+type unstickConfig struct {
+	// CatalogNamespace is the namespace of the controller manager.
+	CatalogNamespace string
+
+	// NamePrefix and NameSuffix are the name affixes Service Catalog was
+	// installed with, which determine CatalogNamespace.
+	NamePrefix string
+	NameSuffix string
+
+	// Timeout is how long to wait for the namespace deletion to complete
+	// after the fixes.
+	Timeout time.Duration
+
+	// Force finalizes the namespace if it is still terminating after
+	// Timeout, leaving its remaining content in etcd.
+	Force bool
+}
+
+// namespaceInfo is the subset of a Namespace read by unstick-namespace.
+type namespaceInfo struct {
+	Spec struct {
+		Finalizers []string `json:"finalizers"`
+	} `json:"spec"`
+	Status struct {
+		Phase      string `json:"phase"`
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// problems returns the messages of the conditions blocking the deletion of
+// the namespace.
+func (n *namespaceInfo) problems() []string {
+	var problems []string
+	for _, c := range n.Status.Conditions {
+		if c.Status == "True" {
+			problems = append(problems, c.Type+": "+c.Message)
+		}
+	}
+	return problems
+}
+
+func NewUnstickNamespaceCmd() *cobra.Command {
+	uc := &unstickConfig{}
+	c := &cobra.Command{
+		Use:   "unstick-namespace [namespace]",
+		Short: "completes the deletion of a namespace stuck Terminating",
+		Long: `completes the deletion of a namespace, the Service Catalog one by default,
+stuck Terminating after an uninstall. It deletes the Service Catalog APIService if
+it is unavailable, which blocks the discovery the namespace deletion needs,
+and removes the Service Catalog finalizers from the remaining resources of
+the namespace if no controller manager is left to process them. It then
+waits for the deletion to complete. With --force, a namespace still stuck
+is finalized, leaving any remaining content behind.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cns, err := affixedNamespace(uc.CatalogNamespace, uc.NamePrefix, uc.NameSuffix)
+			if err != nil {
+				return err
+			}
+			uc.CatalogNamespace = cns
+			ns := cns
+			if len(args) == 1 {
+				ns = args[0]
+			}
+			if err := unstickNamespace(ns, uc); err != nil {
+				messages.Println(messages.UnstickFailed, ns)
+				return err
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVar(&uc.CatalogNamespace, "catalog-namespace", defaultNamespace, "Namespace of the Service Catalog controller manager")
+	addNameAffixFlags(c, &uc.NamePrefix, &uc.NameSuffix)
+	c.Flags().DurationVar(&uc.Timeout, "timeout", 2*time.Minute, "How long to wait for the namespace deletion to complete")
+	c.Flags().BoolVar(&uc.Force, "force", false, "Finalize the namespace if it is still terminating after --timeout")
+	return c
+}
+
+// getNamespace returns the namespace ns, or nil if it does not exist.
+func getNamespace(ns string) (*namespaceInfo, error) {
+	output, err := kubectlCommand("get", "namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "NotFound") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting namespace %s: %s", ns, strings.TrimSpace(string(output)))
+	}
+	var n namespaceInfo
+	if err := json.Unmarshal(output, &n); err != nil {
+		return nil, fmt.Errorf("error unmarshalling namespace %s: %v", ns, err)
+	}
+	return &n, nil
+}
+
+func unstickNamespace(ns string, uc *unstickConfig) error {
+	n, err := getNamespace(ns)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		fmt.Printf("namespace %s does not exist, nothing to do\n", ns)
+		return nil
+	}
+	if n.Status.Phase != "Terminating" {
+		return fmt.Errorf("namespace %s is %s, not Terminating", ns, n.Status.Phase)
+	}
+	for _, p := range n.problems() {
+		fmt.Printf("namespace %s: %s\n", ns, p)
+	}
+
+	info, err := getAPIServiceInfo()
+	if err != nil {
+		return err
+	}
+	if info != nil {
+		if reason := apiServiceUnavailable(info); reason != "" {
+			// The namespace controller can't list the Service
+			// Catalog resources, which are gone with the unavailable
+			// api server, until the APIService is deleted.
+			fmt.Printf("deleting APIService %s: %s\n", scAPIService, reason)
+			output, err := kubectlCommand("delete", "apiservice", scAPIService).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("error deleting APIService %s: %s", scAPIService, strings.TrimSpace(string(output)))
+			}
+		} else if err := clearCatalogFinalizers(ns, uc.CatalogNamespace); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("waiting for namespace %s to be deleted\n", ns)
+	deadline := time.Now().Add(uc.Timeout)
+	for {
+		if n, err = getNamespace(ns); err != nil {
+			return err
+		}
+		if n == nil {
+			fmt.Printf("namespace %s deleted\n", ns)
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Second)
+	}
+
+	if !uc.Force {
+		return fmt.Errorf("namespace %s is still terminating after %v:\n  %s\n"+
+			"use --force to finalize it, leaving its remaining content behind", ns, uc.Timeout, strings.Join(n.problems(), "\n  "))
+	}
+	return finalizeNamespace(ns)
+}
+
+// apiServiceUnavailable returns why the APIService info is unavailable, or
+// "" if it is available.
+func apiServiceUnavailable(info *apiServiceInfo) string {
+	for _, c := range info.Status.Conditions {
+		if c.Type == "Available" {
+			if c.Status == "True" {
+				return ""
+			}
+			return strings.TrimSpace(c.Reason + " " + c.Message)
+		}
+	}
+	return "no Available condition"
+}
+
+// clearCatalogFinalizers removes the Service Catalog finalizer from the
+// resources of namespace ns if the controller manager in namespace
+// catalogNS, which would remove it after deprovisioning, is gone. The broker
+// resources of the instances are left behind.
+func clearCatalogFinalizers(ns, catalogNS string) error {
+	output, err := kubectlCommand("get", "deployment", "controller-manager",
+		"--namespace", catalogNS, "-o", "jsonpath={.status.availableReplicas}").CombinedOutput()
+	if err == nil && strings.TrimSpace(string(output)) != "" && strings.TrimSpace(string(output)) != "0" {
+		fmt.Printf("the controller manager is running, leaving the Service Catalog finalizers in namespace %s to it\n", ns)
+		return nil
+	}
+
+	for _, r := range namespacedCatalogResources {
+		output, err := kubectlCommand("get", r, "--namespace", ns, "-o", "json").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error listing %s in namespace %s: %s", r, ns, strings.TrimSpace(string(output)))
+		}
+		resources, err := finalizedResources(output)
+		if err != nil {
+			return fmt.Errorf("error reading %s in namespace %s: %v", r, ns, err)
+		}
+		for _, res := range resources {
+			fmt.Printf("removing finalizer %s from %s %s/%s\n", catalogFinalizer, r, ns, res.Name)
+			if output, err := removeCatalogFinalizer(r, res); err != nil {
+				return fmt.Errorf("error removing the finalizer of %s %s/%s: %s", r, ns, res.Name, strings.TrimSpace(string(output)))
+			}
+		}
+	}
+	return nil
+}
+
+// finalizedResource is a resource holding catalogFinalizer.
+type finalizedResource struct {
+	Namespace  string
+	Name       string
+	Finalizers []string
+}
+
+// finalizedResources returns the resources holding catalogFinalizer in the
+// list output of kubectl get -o json.
+func finalizedResources(output []byte) ([]finalizedResource, error) {
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Namespace  string   `json:"namespace"`
+				Name       string   `json:"name"`
+				Finalizers []string `json:"finalizers"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, err
+	}
+	var resources []finalizedResource
+	for _, item := range list.Items {
+		if containsString(item.Metadata.Finalizers, catalogFinalizer) {
+			resources = append(resources, finalizedResource{item.Metadata.Namespace, item.Metadata.Name, item.Metadata.Finalizers})
+		}
+	}
+	return resources, nil
+}
+
+// removeFinalizerPatch returns the JSON patch removing catalogFinalizer
+// from finalizers, and only it. Each removal is preceded by a test of the
+// finalizer at its index, so that the patch fails rather than removes
+// another finalizer if the list changed since it was read.
+func removeFinalizerPatch(finalizers []string) string {
+	var ops []map[string]string
+	for i := len(finalizers) - 1; i >= 0; i-- {
+		if finalizers[i] != catalogFinalizer {
+			continue
+		}
+		path := fmt.Sprintf("/metadata/finalizers/%d", i)
+		ops = append(ops,
+			map[string]string{"op": "test", "path": path, "value": catalogFinalizer},
+			map[string]string{"op": "remove", "path": path})
+	}
+	b, _ := json.Marshal(ops)
+	return string(b)
+}
+
+// removeCatalogFinalizer removes catalogFinalizer from res, a resource of
+// kind r, and returns the output of kubectl.
+func removeCatalogFinalizer(r string, res finalizedResource) ([]byte, error) {
+	args := []string{"patch", r, res.Name}
+	if res.Namespace != "" {
+		args = append(args, "--namespace", res.Namespace)
+	}
+	args = append(args, "--type", "json", "-p", removeFinalizerPatch(res.Finalizers))
+	return kubectlCommand(args...).CombinedOutput()
+}
+
+// finalizeNamespace removes the finalizers of namespace ns through the
+// finalize subresource, so that it is deleted without waiting for its
+// content.
+func finalizeNamespace(ns string) error {
+	output, err := kubectlCommand("get", "namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error getting namespace %s: %s", ns, strings.TrimSpace(string(output)))
+	}
+	var n map[string]interface{}
+	if err := json.Unmarshal(output, &n); err != nil {
+		return fmt.Errorf("error unmarshalling namespace %s: %v", ns, err)
+	}
+	if spec, ok := n["spec"].(map[string]interface{}); ok {
+		spec["finalizers"] = []string{}
+	}
+	b, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("finalizing namespace %s\n", ns)
+	cmd := kubectlCommand("replace", "--raw", "/api/v1/namespaces/"+ns+"/finalize", "-f", "-")
+	cmd.Stdin = bytes.NewReader(b)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error finalizing namespace %s: %s", ns, strings.TrimSpace(string(output)))
+	}
+	fmt.Printf("namespace %s finalized\n", ns)
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestFinalizedResources tests that only the resources holding the Service
+// Catalog finalizer are returned, with all their finalizers.
+func TestFinalizedResources(t *testing.T) {
+	output := `{"items":[
+{"metadata":{"namespace":"team","name":"db","finalizers":["other/finalizer","kubernetes-incubator/service-catalog"]}},
+{"metadata":{"namespace":"team","name":"cache"}},
+{"metadata":{"namespace":"team","name":"queue","finalizers":["other/finalizer"]}}]}`
+	got, err := finalizedResources([]byte(output))
+	if err != nil {
+		t.Fatalf("Unexpected error reading resources: %v", err)
+	}
+	want := []finalizedResource{{"team", "db", []string{"other/finalizer", catalogFinalizer}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Resources do not match: got %v; want %v", got, want)
+	}
+}
+
+// TestRemoveFinalizerPatch tests that the patch only removes the Service
+// Catalog finalizer, at the index it was read at.
+func TestRemoveFinalizerPatch(t *testing.T) {
+	got := removeFinalizerPatch([]string{"other/finalizer", catalogFinalizer, "last/finalizer"})
+	want := `[{"op":"test","path":"/metadata/finalizers/1","value":"kubernetes-incubator/service-catalog"},{"op":"remove","path":"/metadata/finalizers/1"}]`
+	if got != want {
+		t.Fatalf("Patch does not match:\ngot  %s\nwant %s", got, want)
+	}
+}
+
+// TestAPIServiceUnavailable tests that the reason of an unavailable
+// APIService is returned, and nothing for an available one.
+func TestAPIServiceUnavailable(t *testing.T) {
+	for _, tc := range []struct {
+		status string
+		want   string
+	}{
+		{`{"conditions":[{"type":"Available","status":"True","reason":"Passed"}]}`, ""},
+		{`{"conditions":[{"type":"Available","status":"False","reason":"ServiceNotFound","message":"service/service-catalog-api is not present"}]}`,
+			"ServiceNotFound service/service-catalog-api is not present"},
+		{`{}`, "no Available condition"},
+	} {
+		var info apiServiceInfo
+		if err := json.Unmarshal([]byte(`{"status":`+tc.status+`}`), &info); err != nil {
+			t.Fatalf("Unexpected error unmarshalling APIService: %v", err)
+		}
+		if got := apiServiceUnavailable(&info); got != tc.want {
+			t.Fatalf("Reason does not match: got %q; want %q", got, tc.want)
+		}
+	}
+}
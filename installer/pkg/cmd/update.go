@@ -17,18 +17,28 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/color"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/manifest"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 )
 
 func NewUpdateCmd() *cobra.Command {
 	c := &cobra.Command{
-		Use:   "update",
-		Short: "updates Service Catalog components in Kubernetes cluster",
-		Long:  "updates Service Catalog components in Kubernetes cluster",
-		Args:  cobra.MinimumNArgs(1),
+		Use:     "update",
+		Aliases: []string{"upgrade"},
+		Short:   "updates Service Catalog components in Kubernetes cluster",
+		Long:    "updates Service Catalog components in Kubernetes cluster",
+		Args:    cobra.MinimumNArgs(1),
 	}
 	// add all update sub-commands
 	c.AddCommand(
@@ -40,7 +50,45 @@ func NewUpdateCmd() *cobra.Command {
 
 // scUpdateArgs contains Service Catalog update Arguments.
 type scUpdateArgs struct {
+	// Namespace Service Catalog is installed in.
+	Namespace string
+
+	// NamePrefix and NameSuffix are the name affixes Service Catalog was
+	// installed with, which determine Namespace.
+	NamePrefix string
+	NameSuffix string
+
 	Version string
+
+	// Canary runs the new api server version as a canary next to the
+	// current one before updating all components.
+	Canary bool
+
+	// CanaryReplicas is the number of canary api server replicas.
+	CanaryReplicas int32
+
+	// BakeTime is how long the canary must stay healthy.
+	BakeTime time.Duration
+
+	// Etcd is the etcd version to migrate the data of the catalog to
+	// before updating the components, if it is a new minor version, and
+	// the settings of the new etcd.
+	Etcd etcdMigrationConfig
+
+	// SkipEtcdMigration keeps the current etcd when upgrading, even if
+	// sc runs a newer etcd minor version.
+	SkipEtcdMigration bool
+
+	// CheckOnly checks that the installation can be upgraded to Version
+	// and prints a go/no-go report, without changing anything.
+	CheckOnly bool
+
+	// ForceUnlock takes over the lease of the namespace another sc
+	// operation holds instead of failing.
+	ForceUnlock bool
+
+	// HistoryMax is the number of revisions kept, 0 records none.
+	HistoryMax int
 }
 
 func newServiceCatalogUpdateCmd() *cobra.Command {
@@ -48,20 +96,71 @@ func newServiceCatalogUpdateCmd() *cobra.Command {
 	c := &cobra.Command{
 		Use: "service-catalog",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ns, err := affixedNamespace(uargs.Namespace, uargs.NamePrefix, uargs.NameSuffix)
+			if err != nil {
+				return err
+			}
+			uargs.Namespace = ns
+			if uargs.CheckOnly {
+				if err := checkServiceCatalogUpgrade(ns, uargs.Version); err != nil {
+					messages.Println(messages.UpgradeCheckFailed, uargs.Version)
+					return err
+				}
+				messages.Println(messages.UpgradeCompatible, uargs.Version)
+				return nil
+			}
 			if err := updateServiceCatalog(uargs); err != nil {
-				fmt.Println("failed to update service catalog components")
+				messages.Println(messages.UpdateFailed)
 				return err
 			}
-			fmt.Println("Service Catalog updated successfully.")
+			messages.Println(messages.Updated)
 			return nil
 		},
 	}
+	c.Flags().StringVar(&uargs.Namespace, "namespace", defaultNamespace, "Namespace Service Catalog is installed in")
+	addNameAffixFlags(c, &uargs.NamePrefix, &uargs.NameSuffix)
 	c.Flags().StringVar(&uargs.Version, "version", "", "Service Catalog Version")
+	c.Flags().BoolVar(&uargs.Canary, "canary", false, "Verify the new api server version as a canary before updating, roll back if it is unhealthy")
+	c.Flags().Int32Var(&uargs.CanaryReplicas, "canary-replicas", 1, "Number of canary api server replicas")
+	c.Flags().DurationVar(&uargs.BakeTime, "bake-time", 5*time.Minute, "How long the canary api server must stay healthy")
+	c.Flags().StringVar(&uargs.Etcd.Version, "etcd-version", "", "etcd 3.x version to migrate the catalog data to, verified and rolled back on failure. With --version, defaults to the etcd version of sc if it is a newer minor version")
+	c.Flags().DurationVar(&uargs.Etcd.Timeout, "etcd-migration-timeout", 15*time.Minute, "How long the etcd data migration may take")
+	c.Flags().BoolVar(&uargs.SkipEtcdMigration, "skip-etcd-migration", false, "Keep the current etcd when upgrading with --version, even if sc runs a newer etcd minor version")
+	addEtcdStorageFlags(c, &uargs.Etcd)
+	c.Flags().BoolVar(&uargs.ForceUnlock, "force-unlock", false, "Take over the lock of the namespace held by another sc operation, e.g. a stale one, instead of failing")
+	c.Flags().IntVar(&uargs.HistoryMax, "history-max", defaultHistoryMax, "Number of revisions of the applied manifests to keep for sc rollback, 0 records none")
+	c.Flags().BoolVar(&uargs.CheckOnly, "check-only", false, "Check the cluster version, storage backend, resource API versions and feature gates against --version and print a go/no-go report, without changing anything")
 	return c
 }
 
-func updateServiceCatalog(args *scUpdateArgs) error {
-	if args.Version == "" {
+// checkServiceCatalogUpgrade prints the go/no-go report of upgrading the
+// installation in namespace ns to version and fails if it is no-go.
+func checkServiceCatalogUpgrade(ns, version string) error {
+	if version == "" {
+		return fmt.Errorf("--check-only requires --version")
+	}
+	found, err := isServiceCatalogInstalled()
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("service catalog is not installed")
+	}
+	c, err := readUpgradeCluster(ns)
+	if err != nil {
+		return err
+	}
+	if !printUpgradeReport(os.Stdout, checkUpgrade(version, c)) {
+		return fmt.Errorf("the installation is not compatible with Service Catalog %s", version)
+	}
+	return nil
+}
+
+func updateServiceCatalog(args *scUpdateArgs) (err error) {
+	phases := tracePhases("upgrade")
+	defer func() { phases.end(err) }()
+	phases.next("preflight")
+	if args.Version == "" && args.Etcd.Version == "" {
 		return fmt.Errorf("version paramter is empty")
 	}
 
@@ -74,13 +173,72 @@ func updateServiceCatalog(args *scUpdateArgs) error {
 		return fmt.Errorf("service catalog is not installed")
 	}
 
+	ns := args.Namespace
+	unlock, err := lockCluster(ns, "upgrade", args.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if args.Version != "" && args.Etcd.Version == "" && !args.SkipEtcdMigration {
+		phases.next("etcd-version")
+		if args.Etcd.Version, err = upgradeEtcdVersion(ns); err != nil {
+			return err
+		}
+		if args.Etcd.Version != "" {
+			fmt.Printf("sc runs etcd %s, migrating the catalog data to it, use --skip-etcd-migration to keep the current etcd\n", args.Etcd.Version)
+		}
+	}
+	if args.Etcd.Version != "" {
+		phases.next("etcd-migration")
+		if err := migrateEtcd(ns, &args.Etcd); err != nil {
+			return err
+		}
+	}
+	if args.Version == "" {
+		return nil
+	}
+
+	// Warn of the objects the new version may no longer accept.
+	phases.next("deprecations")
+	if findings, err := findDeprecations(); err != nil {
+		fmt.Printf("WARNING: deprecated API usage could not be checked: %v\n", err)
+	} else if len(findings) > 0 {
+		report := deprecationReport(findings)
+		report[0] = "WARNING: " + report[0]
+		for _, l := range report {
+			fmt.Println(color.Yellow(l))
+		}
+	}
+
 	scImage := "quay.io/kubernetes-service-catalog/service-catalog:v" + args.Version
-	ns := "service-catalog"
 
+	if args.Canary {
+		if args.CanaryReplicas < 1 {
+			return fmt.Errorf("--canary-replicas must be at least 1")
+		}
+		phases.next("canary")
+		if err := canaryUpgradeAPIServer(ns, scImage, args.CanaryReplicas, args.BakeTime); err != nil {
+			return err
+		}
+		// Keep the canary serving until the stable api server is updated.
+		defer deleteCanaryDeployment(ns)
+	}
+
+	// The revision the upgrade starts from, for sc rollback.
+	var base map[string]string
+	if args.HistoryMax > 0 {
+		if base, err = upgradeBaseManifests(ns, args.HistoryMax); err != nil {
+			return err
+		}
+		defer func() { recordUpgradeRevision(ns, base, args, scImage, err) }()
+	}
+
+	phases.next("update-images")
 	cmds := []*exec.Cmd{
-		exec.Command("kubectl", "set", "image", "deployments/apiserver",
+		kubectlCommand("set", "image", "deployments/apiserver",
 			"apiserver="+scImage, "-n", ns),
-		exec.Command("kubectl", "set", "image", "deployments/controller-manager",
+		kubectlCommand("set", "image", "deployments/controller-manager",
 			"controller-manager="+scImage, "-n", ns),
 	}
 
@@ -91,26 +249,201 @@ func updateServiceCatalog(args *scUpdateArgs) error {
 			return fmt.Errorf("error updating service catalog :%v", string(o))
 		}
 	}
+
+	if args.Canary {
+		phases.next("rollout")
+		o, err := kubectlCommand("rollout", "status", "deployments/apiserver", "-n", ns).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error waiting for the api server update :%v", string(o))
+		}
+	}
 	return nil
 }
 
+// upgradedContainers are the containers of the deployments the upgrade sets
+// the image of, by deployment.
+var upgradedContainers = map[string]string{
+	"apiserver":          "apiserver",
+	"controller-manager": "controller-manager",
+}
+
+// upgradeBaseManifests returns the manifests of the deployed revision of
+// the installation in namespace ns. Without one, e.g. when the history was
+// disabled, the upgraded deployments are recorded as a revision first, so
+// the upgrade can be rolled back.
+func upgradeBaseManifests(ns string, max int) (map[string]string, error) {
+	revisions, err := listRevisions(ns)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].Status == revisionDeployed {
+			return revisions[i].Manifests, nil
+		}
+	}
+
+	var deployments []string
+	for d := range upgradedContainers {
+		deployments = append(deployments, "deployments/"+d)
+	}
+	sort.Strings(deployments)
+	args := append([]string{"get", "--namespace", ns, "-o", "json"}, deployments...)
+	output, err := kubectlCommand(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading the deployments to upgrade: %v", err)
+	}
+	var list struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the deployments to upgrade: %v", err)
+	}
+	var docs []string
+	for _, d := range list.Items {
+		y, err := yaml.Marshal(snapshotObject(d))
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, string(y))
+	}
+	sort.Strings(docs)
+	manifests := map[string]string{"upgrade-snapshot.yaml": strings.Join(docs, "---\n")}
+	// The version is only shown, the manifests are recorded without it.
+	version, _ := apiServerVersion(ns)
+	r, err := recordRevision(ns, manifests, version, "snapshot before upgrade", nil, max)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("recorded the deployments as revision %d\n", r.Number)
+	return manifests, nil
+}
+
+// snapshotObject returns the object o read from the cluster without its
+// status and the metadata set by the cluster, to be applied again.
+func snapshotObject(o map[string]interface{}) map[string]interface{} {
+	metadata, _ := o["metadata"].(map[string]interface{})
+	kept := map[string]interface{}{}
+	for _, k := range []string{"name", "namespace", "labels"} {
+		if v, ok := metadata[k]; ok {
+			kept[k] = v
+		}
+	}
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		delete(annotations, "deployment.kubernetes.io/revision")
+		if len(annotations) > 0 {
+			kept["annotations"] = annotations
+		}
+	}
+	return map[string]interface{}{
+		"apiVersion": o["apiVersion"],
+		"kind":       o["kind"],
+		"metadata":   kept,
+		"spec":       o["spec"],
+	}
+}
+
+// recordUpgradeRevision records the manifests of base, with the images of
+// the upgraded containers set to image, as the revision of the upgrade of
+// args, which failed with err if not nil. Failing to record it only warns.
+func recordUpgradeRevision(ns string, base map[string]string, args *scUpdateArgs, image string, err error) {
+	manifests, rerr := setManifestImages(base, image)
+	if rerr == nil {
+		var r *revision
+		if r, rerr = recordRevision(ns, manifests, args.Version, "upgrade to "+args.Version, err, args.HistoryMax); rerr == nil {
+			fmt.Printf("recorded revision %d\n", r.Number)
+			return
+		}
+	}
+	fmt.Printf("WARNING: the upgrade could not be recorded for sc rollback: %v\n", rerr)
+}
+
+// setManifestImages returns manifests with the images of the upgraded
+// containers of the deployments set to image.
+func setManifestImages(manifests map[string]string, image string) (map[string]string, error) {
+	result := make(map[string]string, len(manifests))
+	for name, m := range manifests {
+		objs, err := manifest.Parse(name, []byte(m))
+		if err != nil {
+			return nil, err
+		}
+		changed := false
+		var docs []string
+		for _, o := range objs {
+			j := o.JSON
+			if container, ok := upgradedContainers[o.Name]; ok && o.Kind == "Deployment" {
+				if j, err = setContainerImage(j, container, image); err != nil {
+					return nil, fmt.Errorf("error setting the image of %s: %v", o, err)
+				}
+				changed = true
+			}
+			y, err := yaml.JSONToYAML(j)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, string(y))
+		}
+		// Keep the files as applied where nothing changed.
+		if !changed {
+			result[name] = m
+			continue
+		}
+		result[name] = strings.Join(docs, "---\n")
+	}
+	return result, nil
+}
+
+// setContainerImage returns the deployment j with the image of container
+// set to image.
+func setContainerImage(j []byte, container, image string) ([]byte, error) {
+	var o map[string]interface{}
+	if err := json.Unmarshal(j, &o); err != nil {
+		return nil, err
+	}
+	spec, _ := o["spec"].(map[string]interface{})
+	template, _ := spec["template"].(map[string]interface{})
+	podSpec, _ := template["spec"].(map[string]interface{})
+	containers, _ := podSpec["containers"].([]interface{})
+	for _, c := range containers {
+		if c, ok := c.(map[string]interface{}); ok && c["name"] == container {
+			c["image"] = image
+		}
+	}
+	return json.Marshal(o)
+}
+
 func newAuthManagerUpdateCmd() *cobra.Command {
 	uargs := &authManagerUpdateArgs{}
 	c := &cobra.Command{
 		Use: "auth-manager",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := updateAuthManager(uargs); err != nil {
+			ns, err := affixedNamespace(uargs.Namespace, uargs.NamePrefix, uargs.NameSuffix)
+			if err == nil {
+				uargs.Namespace = ns
+				err = updateAuthManager(uargs)
+			}
+			if err != nil {
 				fmt.Printf("failed to update auth-manager :%v \n", err)
 				return
 			}
-			fmt.Println("updated auth manager successfully.")
+			messages.Println(messages.AuthManagerUpdated)
 		},
 	}
+	c.Flags().StringVar(&uargs.Namespace, "namespace", defaultNamespace, "Namespace Service Catalog is installed in")
+	addNameAffixFlags(c, &uargs.NamePrefix, &uargs.NameSuffix)
 	c.Flags().StringVar(&uargs.Image, "authmanager.image", "", "AuthManager Image")
 	return c
 }
 
 type authManagerUpdateArgs struct {
+	// Namespace Service Catalog is installed in.
+	Namespace string
+
+	// NamePrefix and NameSuffix are the name affixes Service Catalog was
+	// installed with, which determine Namespace.
+	NamePrefix string
+	NameSuffix string
+
 	Image string
 }
 
@@ -127,9 +460,8 @@ func updateAuthManager(args *authManagerUpdateArgs) error {
 		return fmt.Errorf("empty image arguments for auth manager")
 	}
 
-	ns := "service-catalog"
-	out, err := exec.Command("kubectl", "set", "image", "deployments/google-oauth",
-		"catalog-oauth="+args.Image, "-n", ns).CombinedOutput()
+	out, err := kubectlCommand("set", "image", "deployments/google-oauth",
+		"catalog-oauth="+args.Image, "-n", args.Namespace).CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("error updating auth manager :%v", string(out))
 	}
@@ -0,0 +1,107 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestSetManifestImages tests that only the images of the upgraded
+// containers change, and the files without them are kept as applied.
+func TestSetManifestImages(t *testing.T) {
+	manifests := map[string]string{
+		"apiserver-deployment.yaml": revisionManifest + "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: apiserver\n",
+		"namespace.yaml":            "# Namespace\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: service-catalog\n",
+	}
+	got, err := setManifestImages(manifests, "quay.io/kubernetes-service-catalog/service-catalog:v0.1.13")
+	if err != nil {
+		t.Fatalf("Unexpected error setting images: %v", err)
+	}
+	if got["namespace.yaml"] != manifests["namespace.yaml"] {
+		t.Fatalf("Expected namespace.yaml to be kept, got\n%s", got["namespace.yaml"])
+	}
+	images, err := manifestImages(got)
+	if err != nil {
+		t.Fatalf("Unexpected error reading images: %v", err)
+	}
+	if want := "busybox:1.28 quay.io/kubernetes-service-catalog/service-catalog:v0.1.13"; strings.Join(images, " ") != want {
+		t.Fatalf("Images do not match: got %v; want %s", images, want)
+	}
+	if !strings.Contains(got["apiserver-deployment.yaml"], "kind: Service") {
+		t.Fatalf("Expected the Service to be kept, got\n%s", got["apiserver-deployment.yaml"])
+	}
+}
+
+// TestUpgradeBaseManifests tests that without a deployed revision the
+// deployments are recorded, without the fields set by the cluster, before
+// they are upgraded.
+func TestUpgradeBaseManifests(t *testing.T) {
+	deployment := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":            "apiserver",
+			"namespace":       "service-catalog",
+			"resourceVersion": "42",
+			"annotations": map[string]interface{}{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"owner": "sc",
+			},
+		},
+		"spec":   map[string]interface{}{"replicas": 1},
+		"status": map[string]interface{}{"readyReplicas": 1},
+	}
+	list, _ := json.Marshal(map[string]interface{}{"items": []interface{}{deployment}})
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch {
+		case args[0] == "get" && args[1] == "secrets":
+			return execx.Response{Stdout: `{"items": []}`}
+		case args[0] == "get" && containsString(args, "deployments/apiserver"):
+			return execx.Response{Stdout: string(list)}
+		case args[0] == "get":
+			return execx.Response{Stdout: "quay.io/kubernetes-service-catalog/service-catalog:v0.1.12"}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	manifests, err := upgradeBaseManifests("service-catalog", 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m := manifests["upgrade-snapshot.yaml"]
+	for _, want := range []string{"name: apiserver", "owner: sc", "replicas: 1"} {
+		if !strings.Contains(m, want) {
+			t.Fatalf("Expected the snapshot to contain %q, got\n%s", want, m)
+		}
+	}
+	for _, unwanted := range []string{"resourceVersion", "last-applied-configuration", "status"} {
+		if strings.Contains(m, unwanted) {
+			t.Fatalf("Expected the snapshot not to contain %q, got\n%s", unwanted, m)
+		}
+	}
+	if got := kubectlCalls(s, "create"); len(got) != 1 {
+		t.Fatalf("Expected the snapshot to be recorded once, got %v", got)
+	}
+	if got, want := kubectlCalls(s, "get")[1], "get --namespace service-catalog -o json deployments/apiserver deployments/controller-manager"; got != want {
+		t.Fatalf("Deployments read do not match: got %s; want %s", got, want)
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/color"
+	"github.com/Masterminds/semver"
+)
+
+// upgradeCluster is the state of an installation an upgrade is checked
+// against.
+type upgradeCluster struct {
+	// Kubernetes is the version of the cluster.
+	Kubernetes *semver.Version
+
+	// Version is the Service Catalog version of the api server.
+	Version string
+
+	// StorageType is the --storage-type of the api server.
+	StorageType string
+
+	// FeatureGates are the feature gates the api server and the controller
+	// manager set, by name.
+	FeatureGates map[string]string
+
+	// Deprecations are the deprecated API versions and fields the catalog
+	// objects use.
+	Deprecations []deprecationFinding
+}
+
+// upgradeCheck is the outcome of a compatibility check of an upgrade.
+type upgradeCheck struct {
+	name string
+
+	// problem is why the upgrade is not compatible, empty if it is.
+	problem string
+
+	// warning is set for problems that do not block the upgrade.
+	warning bool
+}
+
+// readUpgradeCluster reads the state of the installation in namespace ns.
+func readUpgradeCluster(ns string) (*upgradeCluster, error) {
+	c := &upgradeCluster{StorageType: "etcd", FeatureGates: make(map[string]string)}
+	var err error
+	if c.Kubernetes, err = getServerVersion(); err != nil {
+		return nil, err
+	}
+	if c.Version, err = apiServerVersion(ns); err != nil {
+		return nil, err
+	}
+	for _, d := range []string{"apiserver", "controller-manager"} {
+		args, err := deploymentArgs(ns, d)
+		if err != nil {
+			return nil, err
+		}
+		for i, a := range args {
+			var value string
+			switch {
+			case strings.HasPrefix(a, "--feature-gates="):
+				value = strings.TrimPrefix(a, "--feature-gates=")
+			case a == "--feature-gates" && i+1 < len(args):
+				value = args[i+1]
+			case d == "apiserver" && a == "--storage-type" && i+1 < len(args):
+				c.StorageType = args[i+1]
+			case d == "apiserver" && strings.HasPrefix(a, "--storage-type="):
+				c.StorageType = strings.TrimPrefix(a, "--storage-type=")
+			}
+			for _, g := range strings.Split(value, ",") {
+				if kv := strings.SplitN(g, "=", 2); len(kv) == 2 {
+					c.FeatureGates[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+				}
+			}
+		}
+	}
+	if c.Deprecations, err = findDeprecations(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// deploymentArgs returns the args of the container named after deployment
+// name in namespace ns.
+func deploymentArgs(ns, name string) ([]string, error) {
+	output, err := kubectlCommand("get", "deployment", name, "--namespace", ns, "-o", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error getting deployment %s: %s", name, strings.TrimSpace(string(output)))
+	}
+	var d struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						Name string   `json:"name"`
+						Args []string `json:"args"`
+					} `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(output, &d); err != nil {
+		return nil, fmt.Errorf("error unmarshalling deployment %s: %v", name, err)
+	}
+	for _, c := range d.Spec.Template.Spec.Containers {
+		if c.Name == name {
+			return c.Args, nil
+		}
+	}
+	return nil, fmt.Errorf("deployment %s has no %s container", name, name)
+}
+
+// checkUpgrade checks that the installation c can be upgraded to Service
+// Catalog version target.
+func checkUpgrade(target string, c *upgradeCluster) []upgradeCheck {
+	tv, err := semver.NewVersion(target)
+	if err != nil {
+		return []upgradeCheck{{name: "target version", problem: fmt.Sprintf("%q is not a semantic version such as 0.2.3", target)}}
+	}
+	s, err := catalogVersionSetOf(&InstallConfig{Version: target})
+	if err != nil {
+		return []upgradeCheck{{name: "target version", problem: err.Error()}}
+	}
+	checks := []upgradeCheck{{name: fmt.Sprintf("sc has templates for Service Catalog %s (%s)", target, s.Name)}}
+
+	check := upgradeCheck{name: fmt.Sprintf("Service Catalog %s is not a downgrade of %s", target, c.Version)}
+	if cv, err := semver.NewVersion(c.Version); err != nil {
+		check.problem, check.warning = fmt.Sprintf("the current version %q is not a semantic version", c.Version), true
+	} else if tv.LessThan(cv) {
+		check.problem = "downgrades are not supported, the catalog data may not be readable by older versions"
+	}
+	checks = append(checks, check)
+
+	check = upgradeCheck{name: fmt.Sprintf("%s requires Kubernetes v%s+, the cluster runs v%s", s.Name, s.MinKubernetes, c.Kubernetes)}
+	if c.Kubernetes.LessThan(semver.MustParse(s.MinKubernetes)) {
+		check.problem = "upgrade the cluster first"
+	}
+	checks = append(checks, check)
+
+	check = upgradeCheck{name: fmt.Sprintf("%s supports the %s storage of the api server", s.Name, c.StorageType)}
+	if !containsString(s.StorageTypes, c.StorageType) {
+		check.problem = fmt.Sprintf("%s only supports %s storage, migrate the catalog data first", s.Name, strings.Join(s.StorageTypes, ", "))
+	}
+	checks = append(checks, check)
+
+	// The templates of sc only render the etcd storage, updating a catalog
+	// on the CRD storage would switch it to an empty etcd.
+	check = upgradeCheck{name: fmt.Sprintf("sc installs the %s storage of the api server", c.StorageType)}
+	if c.StorageType != "etcd" {
+		check.problem = "sc only installs etcd storage, migrate the catalog data first"
+	}
+	checks = append(checks, check)
+
+	var gates, unsupported []string
+	for g := range c.FeatureGates {
+		gates = append(gates, g)
+		if !containsString(s.FeatureGates, g) {
+			unsupported = append(unsupported, g+"="+c.FeatureGates[g])
+		}
+	}
+	sort.Strings(gates)
+	sort.Strings(unsupported)
+	check = upgradeCheck{name: fmt.Sprintf("%s supports the feature gates in use: %s", s.Name, strings.Join(gates, ", "))}
+	if len(gates) == 0 {
+		check.name = "no feature gates are set"
+	}
+	if len(unsupported) > 0 {
+		check.problem = fmt.Sprintf("%s does not know %s, remove them from the deployments first", s.Name, strings.Join(unsupported, ", "))
+	}
+	checks = append(checks, check)
+
+	check = upgradeCheck{name: "the catalog objects use no deprecated API versions or fields"}
+	if len(c.Deprecations) > 0 {
+		var lines []string
+		for _, f := range c.Deprecations {
+			lines = append(lines, f.String())
+		}
+		check.problem, check.warning = "\n  "+strings.Join(lines, "\n  "), true
+	}
+	return append(checks, check)
+}
+
+// printUpgradeReport writes the go/no-go report of checks to w and returns
+// whether the upgrade can go ahead.
+func printUpgradeReport(w io.Writer, checks []upgradeCheck) bool {
+	ok := true
+	for _, c := range checks {
+		switch {
+		case c.problem == "":
+			fmt.Fprintln(w, color.Green("PASS: "+c.name))
+		case c.warning:
+			fmt.Fprintln(w, color.Yellow("WARN: "+c.name+": "+c.problem))
+		default:
+			fmt.Fprintln(w, color.Red("FAIL: "+c.name+": "+c.problem))
+			ok = false
+		}
+	}
+	if ok {
+		fmt.Fprintln(w, "upgrade: go")
+	} else {
+		fmt.Fprintln(w, "upgrade: no-go")
+	}
+	return ok
+}
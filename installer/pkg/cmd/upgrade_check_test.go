@@ -0,0 +1,112 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"github.com/Masterminds/semver"
+)
+
+// TestReadUpgradeCluster tests that the storage type and the feature gates
+// are read from the args of the deployments in both flag forms.
+func TestReadUpgradeCluster(t *testing.T) {
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch {
+		case args[0] == "version":
+			return execx.Response{Stdout: `{"serverVersion": {"gitVersion": "v1.11.2"}}`}
+		case args[0] == "get" && args[1] == "deployment" && len(args) > 4 && args[len(args)-1] == "json":
+			c := args[2]
+			a := `["apiserver", "--storage-type", "crd", "--feature-gates=PodPreset=true"]`
+			if c == "controller-manager" {
+				a = `["--feature-gates", "OriginatingIdentity=true", "--feature-gates", "AsyncBindingOperations=false"]`
+			}
+			return execx.Response{Stdout: `{"spec": {"template": {"spec": {"containers": [{"name": "` + c + `", "args": ` + a + `}]}}}}`}
+		case args[0] == "get" && args[1] == "deployment":
+			return execx.Response{Stdout: "quay.io/kubernetes-service-catalog/service-catalog:v0.1.11-gke.0"}
+		case args[0] == "get":
+			return execx.Response{Stdout: `{"items": []}`}
+		}
+		return execx.Response{}
+	})
+	defer restore()
+
+	c, err := readUpgradeCluster("service-catalog")
+	if err != nil {
+		t.Fatalf("Unexpected error reading the cluster: %v", err)
+	}
+	if c.Kubernetes.String() != "1.11.2" || c.Version != "0.1.11-gke.0" || c.StorageType != "crd" {
+		t.Fatalf("Cluster does not match: got %s, %s, %s", c.Kubernetes, c.Version, c.StorageType)
+	}
+	want := map[string]string{"PodPreset": "true", "OriginatingIdentity": "true", "AsyncBindingOperations": "false"}
+	if !reflect.DeepEqual(c.FeatureGates, want) {
+		t.Fatalf("Feature gates do not match: got %v; want %v", c.FeatureGates, want)
+	}
+}
+
+// TestCheckUpgrade tests that incompatible clusters, storage backends and
+// feature gates are no-go and deprecated API usage only warns.
+func TestCheckUpgrade(t *testing.T) {
+	cluster := func() *upgradeCluster {
+		return &upgradeCluster{
+			Kubernetes:   semver.MustParse("1.11.2"),
+			Version:      "0.1.11-gke.0",
+			StorageType:  "etcd",
+			FeatureGates: map[string]string{"OriginatingIdentity": "true"},
+		}
+	}
+	ok := func(c *upgradeCluster) bool {
+		var b bytes.Buffer
+		return printUpgradeReport(&b, checkUpgrade("0.2.3", c))
+	}
+
+	if c := cluster(); !ok(c) {
+		t.Fatalf("Expected the upgrade to be compatible")
+	}
+	c := cluster()
+	c.Deprecations = []deprecationFinding{{Object: "ServiceInstance prod/db", Problem: "spec.externalClusterServiceClassName is deprecated"}}
+	if !ok(c) {
+		t.Fatalf("Expected deprecated API usage to only warn")
+	}
+
+	for _, tc := range []struct {
+		change func(*upgradeCluster)
+		want   string
+	}{
+		{func(c *upgradeCluster) { c.Kubernetes = semver.MustParse("1.9.6") }, "FAIL: v0.2 requires Kubernetes v1.10.0+"},
+		{func(c *upgradeCluster) { c.StorageType = "crd" }, "FAIL: v0.2 supports the crd storage"},
+		{func(c *upgradeCluster) { c.StorageType = "crd" }, "FAIL: sc installs the crd storage"},
+		{func(c *upgradeCluster) { c.FeatureGates["PodPreset"] = "true" }, "v0.2 does not know PodPreset=true"},
+		{func(c *upgradeCluster) { c.Version = "0.2.4" }, "downgrades are not supported"},
+	} {
+		c := cluster()
+		tc.change(c)
+		var b bytes.Buffer
+		if printUpgradeReport(&b, checkUpgrade("0.2.3", c)) || !strings.Contains(b.String(), tc.want) || !strings.HasSuffix(b.String(), "upgrade: no-go\n") {
+			t.Fatalf("Expected a no-go report containing %q, got:\n%s", tc.want, b.String())
+		}
+	}
+
+	var b bytes.Buffer
+	if printUpgradeReport(&b, checkUpgrade("0.3.0", cluster())) || !strings.Contains(b.String(), "no templates for Service Catalog 0.3.0") {
+		t.Fatalf("Expected a version without templates to be no-go, got:\n%s", b.String())
+	}
+}
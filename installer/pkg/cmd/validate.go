@@ -0,0 +1,281 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/gcp"
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/Masterminds/semver"
+)
+
+// dns1123LabelRE matches RFC 1123 labels, which namespace and service names
+// must be.
+var dns1123LabelRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// dns1123SubdomainRE matches RFC 1123 subdomains, which the names of most
+// objects, e.g. service accounts, must be.
+var dns1123SubdomainRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// watchCacheSizeRE matches the watch cache size of a resource, e.g.
+// serviceinstances.servicecatalog.k8s.io#5000.
+var watchCacheSizeRE = regexp.MustCompile(`^[a-z]+(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*#[0-9]+$`)
+
+// Validate checks the install configuration without side effects. It
+// returns an error listing all the violations found.
+func (ic *InstallConfig) Validate() error {
+	var violations []string
+	addf := func(format string, args ...interface{}) {
+		violations = append(violations, fmt.Sprintf(format, args...))
+	}
+
+	for _, n := range []struct{ what, name string }{
+		{"namespace", ic.Namespace},
+		{"api server service name", ic.APIServerServiceName},
+	} {
+		if len(n.name) > 63 || !dns1123LabelRE.MatchString(n.name) {
+			addf("%s %q must be a DNS-1123 label: at most 63 lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character", n.what, n.name)
+		}
+	}
+
+	validVersion := true
+	if ic.Version != "" {
+		if strings.HasPrefix(ic.Version, "v") {
+			addf("--version %q must not start with v, use %s", ic.Version, strings.TrimPrefix(ic.Version, "v"))
+			validVersion = false
+		} else if _, err := semver.NewVersion(ic.Version); err != nil {
+			addf("--version %q is not a semantic version such as 0.1.11-gke.0", ic.Version)
+			validVersion = false
+		}
+	}
+	if _, err := catalogVersionSetOf(ic); err != nil && validVersion {
+		addf("%v", err)
+	}
+
+	// storage options
+	if ic.SkipEtcd {
+		if ic.EtcdServers == "" {
+			addf("--skip-etcd requires --etcd-servers")
+		}
+		for _, s := range strings.Split(ic.EtcdServers, ",") {
+			if u, err := url.Parse(s); s != "" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+				addf("--etcd-servers %q must be a comma separated list of http or https URLs", ic.EtcdServers)
+				break
+			}
+		}
+	} else {
+		if ic.EtcdServers != "" {
+			addf("--etcd-servers requires --skip-etcd")
+		}
+		if ic.EtcdClusterSize < 1 || ic.EtcdClusterSize%2 == 0 {
+			addf("--etcd-cluster-size must be odd and at least 1 for etcd to keep a quorum, got %d", ic.EtcdClusterSize)
+		}
+		if ic.EtcdBackup && ic.EtcdBackupStorageClass == "" {
+			addf("--etcd-backup requires --etcd-backup-storageclass")
+		}
+	}
+
+	if ic.APIServerReplicas < 1 {
+		addf("--apiserver-replicas must be at least 1, got %d", ic.APIServerReplicas)
+	}
+	if ic.ControllerManagerReplicas < 1 {
+		addf("--controller-manager-replicas must be at least 1, got %d", ic.ControllerManagerReplicas)
+	}
+	if ic.APIServerHPA {
+		// The autoscaler scales the api server between
+		// --apiserver-replicas and --apiserver-max-replicas.
+		if ic.APIServerMaxReplicas < ic.APIServerReplicas {
+			addf("--apiserver-max-replicas must be at least --apiserver-replicas (%d), got %d", ic.APIServerReplicas, ic.APIServerMaxReplicas)
+		}
+		if ic.APIServerCPUUtilization < 0 {
+			addf("--apiserver-hpa-cpu-utilization must not be negative, got %d", ic.APIServerCPUUtilization)
+		}
+		if metrics, err := parsePodsMetrics(ic.APIServerPodsMetrics); err != nil {
+			addf("%v", err)
+		} else if ic.APIServerCPUUtilization == 0 && len(metrics) == 0 {
+			addf("--enable-apiserver-hpa requires a CPU utilization or pods metric target")
+		}
+	}
+	if err := validatePDB(ic); err != nil {
+		addf("%v", err)
+	}
+	if err := validateSecretSync(ic); err != nil {
+		addf("%v", err)
+	}
+	if err := validateEtcdMaintenance(ic); err != nil {
+		addf("%v", err)
+	}
+	if err := validateMesh(ic); err != nil {
+		addf("%v", err)
+	}
+	if err := validateDNS(ic); err != nil {
+		addf("%v", err)
+	}
+	if err := validateProxy(ic); err != nil {
+		addf("%v", err)
+	}
+	if err := validateEncryption(ic); err != nil {
+		addf("%v", err)
+	}
+	if err := validateServiceAccounts(ic); err != nil {
+		addf("%v", err)
+	}
+	if err := validateAuthn(ic); err != nil {
+		addf("%v", err)
+	}
+	for _, a := range ic.NodeArchitectures {
+		if !architectureRE.MatchString(a) {
+			addf("--node-architectures must be a list of architectures such as amd64 or arm64, got %q", a)
+		}
+	}
+	if ic.MaxInstancesPerNamespace < 0 {
+		addf("--max-instances-per-namespace must not be negative, got %d", ic.MaxInstancesPerNamespace)
+	}
+	if ic.PlanPolicyFile != "" {
+		if _, err := loadPlanPolicy(ic.PlanPolicyFile); err != nil {
+			addf("--plan-policy: %v", err)
+		}
+	}
+	if ic.BindingSecretPolicyFile != "" {
+		if _, err := loadBindingSecretPolicy(ic.BindingSecretPolicyFile); err != nil {
+			addf("--binding-secret-policy: %v", err)
+		}
+	}
+	if ic.RBACMappingFile != "" {
+		if _, err := loadRBACMapping(ic.RBACMappingFile); err != nil {
+			addf("--rbac-mapping: %v", err)
+		}
+	}
+	if ic.APIServerRequestTimeout < 0 {
+		addf("--apiserver-request-timeout must not be negative, got %v", ic.APIServerRequestTimeout)
+	}
+	if ic.APIServerDefaultWatchCacheSize < 0 {
+		addf("--apiserver-default-watch-cache-size must not be negative, got %d", ic.APIServerDefaultWatchCacheSize)
+	}
+	for _, size := range ic.APIServerWatchCacheSizes {
+		if !watchCacheSizeRE.MatchString(size) {
+			addf("--apiserver-watch-cache-sizes must be a list of resource[.group]#size such as serviceinstances#5000, got %q", size)
+		}
+	}
+	if ic.BrokerRelistInterval <= 0 {
+		addf("--broker-relist-interval must be positive, got %v", ic.BrokerRelistInterval)
+	}
+	if ic.OSBAPITimeout <= 0 {
+		addf("--osb-api-timeout must be positive, got %v", ic.OSBAPITimeout)
+	}
+	if ic.ResyncInterval <= 0 {
+		addf("--resync-interval must be positive, got %v", ic.ResyncInterval)
+	}
+	if ic.OperationPollingMaxBackoff <= 0 {
+		addf("--operation-polling-max-backoff must be positive, got %v", ic.OperationPollingMaxBackoff)
+	}
+	if ic.ReconciliationRetryDuration <= 0 {
+		addf("--reconciliation-retry-duration must be positive, got %v", ic.ReconciliationRetryDuration)
+	}
+	if ic.ControllerManagerLogLevel < 0 {
+		addf("--controller-manager-log-level must not be negative, got %d", ic.ControllerManagerLogLevel)
+	}
+	if err := validateOSBAPIVersion(ic); err != nil {
+		addf("%v", err)
+	}
+
+	switch ic.DryRun {
+	case dryRunNone, dryRunClient, dryRunServer:
+	default:
+		addf("--dry-run must be none, client or server, got %q", ic.DryRun)
+	}
+
+	if ic.GitOps.isSet() {
+		for _, p := range ic.GitOps.validate() {
+			addf("%s", p)
+		}
+		if ic.DryRun != dryRunNone {
+			addf("--gitops-repo and --gitops-dir cannot be combined with --dry-run")
+		}
+		if ic.ForceAdopt {
+			addf("--force-adopt changes the cluster, it cannot be combined with --gitops-repo and --gitops-dir")
+		}
+	}
+
+	if !validFailPhase(ic.Apply.FailAt) {
+		addf("--fail-at must be one of %s, got %q", failPhasesUsage(), ic.Apply.FailAt)
+	}
+	if ic.Apply.ForceConflicts && !ic.Apply.ServerSide {
+		addf("--force-conflicts requires --server-side")
+	}
+
+	switch ic.IPFamily {
+	case ipFamilyIPv4, ipFamilyDual:
+	case ipFamilyIPv6:
+		// etcd-operator, which backs up and scales etcd, only listens
+		// on IPv4.
+		if ic.SkipEtcd {
+			break
+		}
+		if ic.EtcdBackup {
+			addf("--ip-family=ipv6 does not support etcd backups, use --etcd-backup=false")
+		}
+		if ic.EtcdClusterSize != 1 {
+			addf("--ip-family=ipv6 only supports a single member etcd, use --etcd-cluster-size=1")
+		}
+	default:
+		addf("--ip-family must be ipv4, ipv6 or dual, got %q", ic.IPFamily)
+	}
+
+	if ic.VerifyAs.isSet() {
+		if ic.ReadyTimeout == 0 {
+			addf("--verify-kubeconfig and --verify-context require waiting for the installation, --ready-timeout must not be 0")
+		}
+		if ic.SkipAPIRegistration {
+			addf("--verify-kubeconfig and --verify-context require the APIService, --skip-api-registration must not be set")
+		}
+		if ic.VerifyAs.Kubeconfig != "" {
+			if _, err := os.Stat(ic.VerifyAs.Kubeconfig); err != nil {
+				addf("--verify-kubeconfig: %v", err)
+			}
+		}
+	}
+
+	// certificate options
+	if _, _, err := keySpec(ic); err != nil {
+		addf("%v", err)
+	}
+	if ic.CertValidity <= 0 {
+		addf("--cert-validity must be positive, got %v", ic.CertValidity)
+	}
+	if ic.ReuseCerts && ic.StoreCAKey {
+		addf("--reuse-certs and --store-ca-key are mutually exclusive, the CA private key of reused certificates is unknown")
+	}
+	if ic.KMSKey != "" {
+		if !ic.StoreCAKey {
+			addf("--kms-key requires --store-ca-key")
+		}
+		if err := gcp.ValidateKMSKeyName(ic.KMSKey); err != nil {
+			addf("%v", err)
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return messages.Errorf(messages.InvalidInstallConfig, "\n  - "+strings.Join(violations, "\n  - "))
+}
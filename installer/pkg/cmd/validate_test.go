@@ -0,0 +1,114 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validInstallConfig() *InstallConfig {
+	return &InstallConfig{
+		Namespace:                   "service-catalog",
+		APIServerServiceName:        "service-catalog-api",
+		DryRun:                      dryRunNone,
+		Version:                     "0.1.11-gke.0",
+		EtcdClusterSize:             3,
+		EtcdBackup:                  true,
+		EtcdBackupStorageClass:      "standard",
+		APIServerReplicas:           1,
+		ControllerManagerReplicas:   1,
+		KeyAlgorithm:                "rsa",
+		CertValidity:                defaultCertValidity,
+		BrokerRelistInterval:        defaultBrokerRelistInterval,
+		OSBAPITimeout:               defaultOSBAPITimeout,
+		ResyncInterval:              defaultResyncInterval,
+		OperationPollingMaxBackoff:  defaultOperationPollingMaxBackoff,
+		ReconciliationRetryDuration: defaultReconciliationRetryDuration,
+		IPFamily:                    ipFamilyIPv4,
+		OSBAPIVersion:               defaultOSBAPIVersion,
+	}
+}
+
+// TestValidate tests that a valid configuration passes and that all the
+// violations of an invalid one are reported at once.
+func TestValidate(t *testing.T) {
+	if err := validInstallConfig().Validate(); err != nil {
+		t.Fatalf("Unexpected error validating a valid config: %v", err)
+	}
+
+	ic := validInstallConfig()
+	ic.Namespace = "Service_Catalog"
+	ic.Version = "v0.1.11"
+	ic.EtcdClusterSize = 4
+	ic.ReuseCerts = true
+	ic.StoreCAKey = true
+	ic.KMSKey = "my-key"
+	ic.DryRun = "yes"
+	ic.EtcdServers = "etcd:2379"
+	ic.VerifyAs = verifyIdentity{Kubeconfig: "/nonexistent/kubeconfig"}
+	ic.ReadyTimeout = 0
+	ic.OSBAPIVersion = "2.12"
+	ic.APIServerWatchCacheSizes = []string{"serviceinstances=5000"}
+
+	err := ic.Validate()
+	if err == nil {
+		t.Fatalf("Expected an error validating an invalid config")
+	}
+	for _, want := range []string{
+		`namespace "Service_Catalog" must be a DNS-1123 label`,
+		`--version "v0.1.11" must not start with v`,
+		"--etcd-cluster-size must be odd",
+		"--reuse-certs and --store-ca-key are mutually exclusive",
+		`invalid Cloud KMS key "my-key"`,
+		`--dry-run must be none, client or server, got "yes"`,
+		"--etcd-servers requires --skip-etcd",
+		"--ready-timeout must not be 0",
+		"--verify-kubeconfig: stat /nonexistent/kubeconfig",
+		`--osb-api-version must be one of 2.13, 2.14, got "2.12"`,
+		`--apiserver-watch-cache-sizes must be a list of resource[.group]#size such as serviceinstances#5000, got "serviceinstances=5000"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Error does not report a violation: got %q; want it to contain %q", err, want)
+		}
+	}
+}
+
+// TestValidateCertValidity tests that the certificate validity must be
+// positive.
+func TestValidateCertValidity(t *testing.T) {
+	for _, tc := range []struct {
+		validity time.Duration
+		valid    bool
+	}{
+		{time.Hour, true},
+		{time.Nanosecond, true},
+		{0, false},
+		{-time.Hour, false},
+	} {
+		ic := validInstallConfig()
+		ic.CertValidity = tc.validity
+		err := ic.Validate()
+		switch {
+		case tc.valid && err != nil:
+			t.Fatalf("%v: unexpected error: %v", tc.validity, err)
+		case !tc.valid && (err == nil || !strings.Contains(err.Error(), "--cert-validity must be positive, got "+tc.validity.String())):
+			t.Fatalf("%v: expected a --cert-validity error, got %v", tc.validity, err)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// scAPIService is the name of the APIService registering Service
+	// Catalog with the main API server.
+	scAPIService = "v1beta1.servicecatalog.k8s.io"
+
+	// scAPIVersion is the Service Catalog API group and version.
+	scAPIVersion = "servicecatalog.k8s.io/v1beta1"
+
+	// verifyBrokerName is the name of the broker created and deleted
+	// while verifying an installation.
+	verifyBrokerName = "sc-verify-install"
+)
+
+// reconcileMetricsInterval is how long apart the two scrapes of the
+// controller manager metrics compared by verify-install are.
+var reconcileMetricsInterval = 15 * time.Second
+
+// verifyConfig contains the verify-install configuration.
+type verifyConfig struct {
+	// Namespace Service Catalog is installed in.
+	Namespace string
+
+	// JUnitXML is the path of the JUnit XML report to write, if any.
+	JUnitXML string
+
+	// As is the identity to also verify the installation as, if set.
+	As verifyIdentity
+}
+
+// verifyCheck is a single assertion made about a Service Catalog
+// installation.
+type verifyCheck struct {
+	name string
+	run  func() error
+}
+
+// verifyResult is the outcome of running a verifyCheck.
+type verifyResult struct {
+	name     string
+	err      error
+	duration time.Duration
+
+	// skipped is why the check was not run, if it was not.
+	skipped string
+}
+
+func NewVerifyInstallCmd() *cobra.Command {
+	vc := &verifyConfig{}
+	c := &cobra.Command{
+		Use:   "verify-install",
+		Short: "verifies a Service Catalog installation",
+		Long: `verifies a Service Catalog installation by running a set of
+conformance style checks against the Kubernetes cluster.
+assumes kubectl is configured to connect to the Kubernetes cluster.
+With --verify-kubeconfig or --verify-context, it also checks, as that
+identity, that API discovery and reading the catalog work for normal users.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := verifyInstall(vc); err != nil {
+				messages.Println(messages.VerifyFailed)
+				return err
+			}
+			messages.Println(messages.Verified)
+			return nil
+		},
+	}
+	c.Flags().StringVar(&vc.Namespace, "namespace", "service-catalog", "Namespace Service Catalog is installed in")
+	c.Flags().StringVar(&vc.JUnitXML, "junit-xml", "", "Write the results as a JUnit XML report to this file")
+	addVerifyIdentityFlags(c, &vc.As)
+	return c
+}
+
+func verifyInstall(vc *verifyConfig) error {
+	skipped, err := installedSkippedComponents(vc.Namespace)
+	if err != nil {
+		return err
+	}
+	var results []verifyResult
+	for _, c := range skipped {
+		r := verifyResult{name: c + " is installed by sc", skipped: "managed separately, skipped at install"}
+		fmt.Printf("SKIP: %s: %s\n", r.name, r.skipped)
+		results = append(results, r)
+	}
+	results = append(results, runVerifyChecks(verifyChecks(vc.Namespace))...)
+	if vc.As.isSet() {
+		asResults, err := runIdentityVerifyChecks(vc.As)
+		if err != nil {
+			return err
+		}
+		results = append(results, asResults...)
+	}
+
+	if vc.JUnitXML != "" {
+		if err := writeJUnitReport(vc.JUnitXML, results); err != nil {
+			return fmt.Errorf("error writing JUnit report: %v", err)
+		}
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// verifyChecks returns the checks run by verify-install of the installation
+// in namespace ns, in order.
+func verifyChecks(ns string) []verifyCheck {
+	checks := []verifyCheck{
+		{name: "APIService is available", run: checkAPIServiceAvailable},
+		{name: "discovery lists the servicecatalog.k8s.io group", run: checkCatalogDiscovery},
+	}
+	checks = append(checks, discoveryVerifyChecks()...)
+	return append(checks, []verifyCheck{
+		{name: "a broker can be created and deleted", run: checkBrokerLifecycle},
+		{name: "current user has admin access to catalog resources", run: checkAdminRBAC},
+		{name: "controller manager reconcile loops run without errors", run: func() error { return checkReconcileMetrics(ns) }},
+	}...)
+}
+
+// skipCheck is returned by the checks that cannot run on the cluster, as
+// why.
+type skipCheck string
+
+func (s skipCheck) Error() string {
+	return string(s)
+}
+
+func runVerifyChecks(checks []verifyCheck) []verifyResult {
+	var results []verifyResult
+	for _, c := range checks {
+		start := time.Now()
+		err := c.run()
+		if skip, ok := err.(skipCheck); ok {
+			results = append(results, verifyResult{name: c.name, skipped: string(skip)})
+			fmt.Printf("SKIP: %s: %s\n", c.name, skip)
+			continue
+		}
+		results = append(results, verifyResult{name: c.name, err: err, duration: time.Since(start)})
+		if err != nil {
+			fmt.Printf("FAIL: %s: %v\n", c.name, err)
+		} else {
+			fmt.Printf("PASS: %s\n", c.name)
+		}
+	}
+	return results
+}
+
+func checkAPIServiceAvailable() error {
+	output, err := kubectlCommand("get", "apiservice", scAPIService,
+		"-o", `jsonpath={.status.conditions[?(@.type=="Available")].status}`).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error getting APIService %s: %s", scAPIService, strings.TrimSpace(string(output)))
+	}
+	if status := strings.TrimSpace(string(output)); status != "True" {
+		return fmt.Errorf("APIService %s is not available, Available condition is %q", scAPIService, status)
+	}
+	return nil
+}
+
+func checkCatalogDiscovery() error {
+	versions, err := servedAPIVersions()
+	if err != nil {
+		return fmt.Errorf("error listing API versions: %v", err)
+	}
+	if !versions[scAPIVersion] {
+		return fmt.Errorf("%s is not listed by API discovery", scAPIVersion)
+	}
+	return nil
+}
+
+func checkBrokerLifecycle() error {
+	broker := fmt.Sprintf(`apiVersion: %s
+kind: ClusterServiceBroker
+metadata:
+  name: %s
+spec:
+  url: http://%s.invalid
+`, scAPIVersion, verifyBrokerName, verifyBrokerName)
+
+	cmd := kubectlCommand("create", "-f", "-")
+	cmd.Stdin = strings.NewReader(broker)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error creating broker %s: %s", verifyBrokerName, strings.TrimSpace(string(output)))
+	}
+
+	output, err := kubectlCommand("delete", "clusterservicebroker", verifyBrokerName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deleting broker %s: %s", verifyBrokerName, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// checkReconcileMetrics checks the work queue metrics of the controller
+// manager. The broker created by checkBrokerLifecycle has been queued by
+// then, so the loops ran. Its URL doesn't resolve and its reconciles were
+// retried until it was deleted; only the retries between two scrapes taken
+// after that count, which also leaves out those of past errors.
+func checkReconcileMetrics(ns string) error {
+	before, err := controllerManagerMetrics(ns)
+	if err != nil {
+		return err
+	}
+	time.Sleep(reconcileMetricsInterval)
+	after, err := controllerManagerMetrics(ns)
+	if err != nil {
+		return err
+	}
+	if after.Adds == 0 {
+		return fmt.Errorf("no reconcile loop has run")
+	}
+	if problem := after.since(before).retried(); problem != "" {
+		return fmt.Errorf("%s in the last %v", problem, reconcileMetricsInterval)
+	}
+	return nil
+}
+
+func checkAdminRBAC() error {
+	resources := []string{"clusterservicebrokers", "serviceinstances", "servicebindings"}
+	var denied []string
+	for _, r := range resources {
+		for _, verb := range []string{"create", "delete"} {
+			output, err := kubectlCommand("auth", "can-i", verb, r+".servicecatalog.k8s.io").CombinedOutput()
+			answer := strings.TrimSpace(string(output))
+			if answer == "yes" {
+				continue
+			}
+			if err != nil && answer != "no" {
+				return fmt.Errorf("error checking access to %s: %s", r, answer)
+			}
+			denied = append(denied, verb+" "+r)
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("current user is not allowed to %s", strings.Join(denied, ", "))
+	}
+	return nil
+}
+
+// junitTestSuite is the root element of a JUnit XML report.
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    string        `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+	Skipped *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func writeJUnitReport(path string, results []verifyResult) error {
+	suite := junitTestSuite{Name: "sc verify-install", Tests: len(results)}
+	var total time.Duration
+	for _, r := range results {
+		tc := junitTestCase{Name: r.name, Time: junitSeconds(r.duration)}
+		if r.err != nil {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: r.err.Error()}
+		}
+		if r.skipped != "" {
+			suite.Skipped++
+			tc.Skipped = &junitSkipped{Message: r.skipped}
+		}
+		total += r.duration
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	suite.Time = junitSeconds(total)
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	enc := xml.NewEncoder(&b)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return err
+	}
+	b.WriteString("\n")
+	return ioutil.WriteFile(path, b.Bytes(), 0644)
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
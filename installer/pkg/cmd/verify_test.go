@@ -0,0 +1,160 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// TestVerifyInstall tests that verify-install runs all the checks, reports
+// the components skipped at install and writes the results as a JUnit XML
+// report.
+func TestVerifyInstall(t *testing.T) {
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		switch {
+		case args[0] == "get" && args[1] == "namespace":
+			return execx.Response{Stdout: "etcd"}
+		case args[0] == "get" && args[1] == "apiservice":
+			return execx.Response{Stdout: "True"}
+		case args[0] == "api-versions":
+			return execx.Response{Stdout: "v1\n" + scAPIVersion + "\n"}
+		case args[0] == "create", args[0] == "delete":
+			return execx.Response{}
+		case args[0] == "auth":
+			return execx.Response{Stdout: "yes\n"}
+		}
+		return execx.Response{Stderr: "Error from server (Forbidden)", ExitCode: 1}
+	})
+	defer restore()
+
+	dir, err := ioutil.TempDir("", "verify")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	vc := &verifyConfig{Namespace: "catalog", JUnitXML: filepath.Join(dir, "report.xml")}
+	err = verifyInstall(vc)
+
+	b, rerr := ioutil.ReadFile(vc.JUnitXML)
+	if rerr != nil {
+		t.Fatalf("Expected a JUnit report: %v", rerr)
+	}
+	var suite junitTestSuite
+	if err := xml.Unmarshal(b, &suite); err != nil {
+		t.Fatalf("Unexpected error unmarshalling the JUnit report: %v\n%s", err, b)
+	}
+	if want := fmt.Sprintf("%d of %d checks failed", suite.Failures, suite.Tests); err == nil || err.Error() != want {
+		t.Fatalf("Expected error %q, got %v", want, err)
+	}
+	if suite.Tests != len(verifyChecks("catalog"))+1 || suite.Skipped != 1 {
+		t.Fatalf("Expected the checks and the skipped etcd, got %d tests, %d skipped", suite.Tests, suite.Skipped)
+	}
+
+	results := make(map[string]junitTestCase)
+	for _, tc := range suite.TestCases {
+		results[tc.Name] = tc
+	}
+	for name, want := range map[string]string{
+		"etcd is installed by sc":                               "skipped",
+		"APIService is available":                               "passed",
+		"discovery lists the servicecatalog.k8s.io group":       "passed",
+		"a broker can be created and deleted":                   "passed",
+		"current user has admin access to catalog resources":    "passed",
+		"controller manager reconcile loops run without errors": "failed",
+		"kubectl api-resources lists the catalog resources":     "failed",
+	} {
+		tc, ok := results[name]
+		got := "passed"
+		switch {
+		case !ok:
+			got = "missing"
+		case tc.Skipped != nil:
+			got = "skipped"
+		case tc.Failure != nil:
+			got = "failed"
+		}
+		if got != want {
+			t.Fatalf("Expected check %q to be %s, got %s", name, want, got)
+		}
+	}
+	calls := kubectlCalls(s, "create", "delete")
+	if len(calls) != 2 || calls[0] != "create -f -" || calls[1] != "delete clusterservicebroker "+verifyBrokerName {
+		t.Fatalf("Unexpected broker calls: %q", calls)
+	}
+}
+
+// TestCheckBrokerLifecycle tests that a broker that could not be created is
+// not deleted.
+func TestCheckBrokerLifecycle(t *testing.T) {
+	s, restore := stubExecutor(func(name string, args []string) execx.Response {
+		return execx.Response{Stderr: "the server could not find the requested resource", ExitCode: 1}
+	})
+	defer restore()
+
+	if err := checkBrokerLifecycle(); err == nil || !strings.Contains(err.Error(), "error creating broker") {
+		t.Fatalf("Expected an error creating the broker, got %v", err)
+	}
+	if calls := kubectlCalls(s, "delete"); len(calls) != 0 {
+		t.Fatalf("Expected no delete, got %q", calls)
+	}
+}
+
+// TestCheckAdminRBAC tests that the denied verbs are listed and that errors
+// checking the access are told apart from denials.
+func TestCheckAdminRBAC(t *testing.T) {
+	_, restore := stubExecutor(func(name string, args []string) execx.Response {
+		if args[2] == "delete" || args[3] == "servicebindings.servicecatalog.k8s.io" {
+			return execx.Response{Stdout: "no\n", ExitCode: 1}
+		}
+		return execx.Response{Stdout: "yes\n"}
+	})
+	err := checkAdminRBAC()
+	restore()
+	want := "current user is not allowed to delete clusterservicebrokers, delete serviceinstances, create servicebindings, delete servicebindings"
+	if err == nil || err.Error() != want {
+		t.Fatalf("Expected error %q, got %v", want, err)
+	}
+
+	_, restore = stubExecutor(func(name string, args []string) execx.Response {
+		return execx.Response{Stderr: "Unable to connect to the server", ExitCode: 1}
+	})
+	defer restore()
+	if err := checkAdminRBAC(); err == nil || !strings.Contains(err.Error(), "error checking access to clusterservicebrokers") {
+		t.Fatalf("Expected an error checking the access, got %v", err)
+	}
+}
+
+// TestRunVerifyChecks tests that checks returning a skipCheck are skipped
+// and the others pass or fail.
+func TestRunVerifyChecks(t *testing.T) {
+	results := runVerifyChecks([]verifyCheck{
+		{name: "passes", run: func() error { return nil }},
+		{name: "fails", run: func() error { return fmt.Errorf("broken") }},
+		{name: "skips", run: func() error { return skipCheck("not visible") }},
+	})
+	if len(results) != 3 || results[0].err != nil || results[1].err == nil || results[2].err != nil || results[2].skipped != "not visible" {
+		t.Fatalf("Unexpected results: %+v", results)
+	}
+}
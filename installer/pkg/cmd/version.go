@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/messages"
 	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/version"
 	"github.com/spf13/cobra"
 )
@@ -30,7 +31,7 @@ func NewVersionCmd() *cobra.Command {
 		Long:  "prints version of commandline tool 'sc'",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := printVersion(); err != nil {
-				fmt.Println("failed to print 'sc' version")
+				messages.Println(messages.VersionFailed)
 				return err
 			}
 			return nil
@@ -0,0 +1,75 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// Names of the flags configuring the workspaces of sc.
+const (
+	WorkspaceDirFlagName     = "workspace-dir"
+	WorkspaceCleanupFlagName = "workspace-cleanup"
+)
+
+// workspaces creates the directories the operations of sc generate their
+// files in.
+var workspaces = workspace.NewManager(workspace.DefaultRoot(), "")
+
+// SetWorkspaces configures the workspaces from the workspace flags of c. An
+// empty cleanup policy keeps the policies of the operations.
+func SetWorkspaces(c *cobra.Command) error {
+	dir, err := c.Flags().GetString(WorkspaceDirFlagName)
+	if err != nil {
+		return nil
+	}
+	cleanup, err := c.Flags().GetString(WorkspaceCleanupFlagName)
+	if err != nil {
+		return nil
+	}
+	if dir == "" {
+		dir = workspace.DefaultRoot()
+	}
+	var policy workspace.Policy
+	if cleanup != "" {
+		if policy, err = workspace.ParsePolicy(cleanup); err != nil {
+			return fmt.Errorf("--%s: %v", WorkspaceCleanupFlagName, err)
+		}
+	}
+	workspaces = workspace.NewManager(dir, policy)
+	return nil
+}
+
+// createWorkspace creates the workspace of operation on namespace ns of the
+// cluster of the current kubectl context.
+func createWorkspace(ns, operation string, opts workspace.Options) (*workspace.Workspace, error) {
+	return workspaces.Create(workspace.Scope(currentKubeContext(), ns), operation, opts)
+}
+
+// currentKubeContext returns the current kubectl context, empty if there is
+// none.
+func currentKubeContext() string {
+	out, err := kubectlCommand("config", "current-context").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package color colors the status output of sc, green for ready, red for
+// failed and yellow for progressing, when it is printed to a terminal.
+//
+// Output to pipes and files, e.g. logs and CI, is not colored, nor is it
+// if the NO_COLOR environment variable is set, see https://no-color.org, or
+// TERM is dumb.
+package color
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// ANSI escape codes of the colors.
+const (
+	green  = "\x1b[32m"
+	red    = "\x1b[31m"
+	yellow = "\x1b[33m"
+	reset  = "\x1b[0m"
+)
+
+// enabled is 1 if the output is colored.
+var enabled int32
+
+func init() {
+	SetEnabled(Detect(os.Stdout, os.Getenv))
+}
+
+// Detect returns whether output to f is colored, if f is a terminal and
+// getenv neither sets NO_COLOR nor a dumb TERM.
+func Detect(f *os.File, getenv func(string) string) bool {
+	if getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(f)
+}
+
+// IsTerminal returns whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// SetEnabled enables or disables the colors.
+func SetEnabled(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&enabled, v)
+}
+
+// Enabled returns whether the output is colored.
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// Green returns s colored green, the color of ready and succeeded.
+func Green(s string) string {
+	return colorize(green, s)
+}
+
+// Red returns s colored red, the color of missing and failed.
+func Red(s string) string {
+	return colorize(red, s)
+}
+
+// Yellow returns s colored yellow, the color of progressing and degraded.
+func Yellow(s string) string {
+	return colorize(yellow, s)
+}
+
+func colorize(code, s string) string {
+	if !Enabled() || s == "" {
+		return s
+	}
+	return code + s + reset
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package color
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// TestDetect tests that files which are not terminals, NO_COLOR and a dumb
+// TERM disable the colors.
+func TestDetect(t *testing.T) {
+	f, err := ioutil.TempFile("", "color")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if Detect(f, func(string) string { return "" }) {
+		t.Fatalf("Expected no colors for a file")
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("No terminal: %v", err)
+	}
+	defer tty.Close()
+	for _, tc := range []struct {
+		env  map[string]string
+		want bool
+	}{
+		{map[string]string{"TERM": "xterm-256color"}, true},
+		{map[string]string{"TERM": "xterm", "NO_COLOR": "1"}, false},
+		{map[string]string{"TERM": "dumb"}, false},
+	} {
+		if got := Detect(tty, func(k string) string { return tc.env[k] }); got != tc.want {
+			t.Fatalf("Detect with %v does not match: got %v; want %v", tc.env, got, tc.want)
+		}
+	}
+}
+
+// TestColorize tests that strings are only colored if enabled.
+func TestColorize(t *testing.T) {
+	defer SetEnabled(Enabled())
+
+	SetEnabled(false)
+	if got := Green("ready"); got != "ready" {
+		t.Fatalf("Expected no colors when disabled, got %q", got)
+	}
+	SetEnabled(true)
+	if got, want := Red("missing"), "\x1b[31mmissing\x1b[0m"; got != want {
+		t.Fatalf("Colored string does not match: got %q; want %q", got, want)
+	}
+	if got := Yellow(""); got != "" {
+		t.Fatalf("Expected empty strings to stay empty, got %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package execx
+
+import "os/exec"
+
+// Executor creates the commands of the external programs sc runs, so that
+// tests can stub them.
+type Executor interface {
+	// Command returns the command running program name with args, see
+	// exec.Command.
+	Command(name string, args ...string) *exec.Cmd
+
+	// LookPath returns the path of program file, see exec.LookPath.
+	LookPath(file string) (string, error)
+}
+
+// OS is the Executor running the programs of the PATH.
+type OS struct{}
+
+func (OS) Command(name string, args ...string) *exec.Cmd {
+	return exec.Command(name, args...)
+}
+
+func (OS) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
+
+// DefaultExecutor is the Executor of Command and LookPath. Tests replace it
+// with a *Stub to run without the programs installed.
+var DefaultExecutor Executor = OS{}
+
+// Command returns the command running program name with args from
+// DefaultExecutor.
+func Command(name string, args ...string) *exec.Cmd {
+	return DefaultExecutor.Command(name, args...)
+}
+
+// LookPath returns the path of program file from DefaultExecutor.
+func LookPath(file string) (string, error) {
+	return DefaultExecutor.LookPath(file)
+}
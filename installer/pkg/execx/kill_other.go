@@ -0,0 +1,42 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package execx
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// killGracePeriod is how long Kill lets a process stop on SIGTERM before
+// killing it.
+var killGracePeriod = 5 * time.Second
+
+// Kill stops the process of cmd and its children. The process is sent
+// SIGTERM, which wrappers, e.g. of traced commands, forward to the
+// programs they run, and killed if it still runs after killGracePeriod.
+func Kill(cmd *exec.Cmd) error {
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		return cmd.Process.Kill()
+	}
+	// Killing a process that was waited for fails without effect.
+	time.AfterFunc(killGracePeriod, func() { cmd.Process.Kill() })
+	return nil
+}
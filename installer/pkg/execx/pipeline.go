@@ -0,0 +1,212 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package execx runs pipelines of external commands, such as cfssl, gcloud
+// or etcdctl, the way a Unix shell would.
+package execx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxOutput is the default limit in bytes of the standard output and
+// of the standard error of each command kept by Pipeline.
+const DefaultMaxOutput = 1 << 20
+
+// waitDelay bounds how long the output of a command is read after it exits.
+// Processes it started, e.g. by a wrapper script, may hold its output open.
+const waitDelay = 5 * time.Second
+
+// Result is the result of a pipeline.
+type Result struct {
+	// Stdout is the standard output of the last command.
+	Stdout []byte
+
+	// Stderr is the standard error of each command, in pipeline order.
+	Stderr [][]byte
+
+	// Truncated is true if any output exceeded the output limit and was
+	// cut.
+	Truncated bool
+}
+
+// Error is the error of a failed pipeline command.
+type Error struct {
+	// Args are the command line of the failed command.
+	Args []string
+
+	// Stderr is the standard error of the failed command.
+	Stderr []byte
+
+	// Err is the error returned by the command.
+	Err error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %v", strings.Join(e.Args, " "), e.Err)
+	if stderr := strings.TrimSpace(string(e.Stderr)); stderr != "" {
+		msg += ": " + stderr
+	}
+	return msg
+}
+
+// Pipeline is a pipeline of commands.
+type Pipeline struct {
+	// Cmds are the commands of the pipeline. Each command's standard output
+	// is connected to the standard input of the next command. To provide
+	// input to the pipeline, assign an io.Reader to the first's Stdin.
+	Cmds []*exec.Cmd
+
+	// MaxOutput is the limit in bytes of the standard output and of the
+	// standard error of each command kept in the Result. Zero means
+	// DefaultMaxOutput. Output past the limit is discarded, the commands
+	// are not blocked.
+	MaxOutput int
+}
+
+// Run runs cmds as a pipeline with the default output limit. See
+// Pipeline.Run.
+func Run(ctx context.Context, cmds ...*exec.Cmd) (*Result, error) {
+	p := &Pipeline{Cmds: cmds}
+	return p.Run(ctx)
+}
+
+// Run starts the commands and waits for all of them to complete. The
+// commands are killed when ctx is done, in which case the error is
+// ctx.Err(). Otherwise the error is an *Error for the first command that
+// failed, if any. The Result holds the output collected so far even if an
+// error is returned.
+func (p *Pipeline) Run(ctx context.Context) (*Result, error) {
+	res := &Result{}
+	if len(p.Cmds) == 0 {
+		return res, nil
+	}
+	max := p.MaxOutput
+	if max <= 0 {
+		max = DefaultMaxOutput
+	}
+
+	stdout := &limitedBuffer{max: max}
+	stderrs := make([]*limitedBuffer, len(p.Cmds))
+	for i := range stderrs {
+		stderrs[i] = &limitedBuffer{max: max}
+	}
+	defer func() {
+		res.Stdout, res.Truncated = stdout.buf.Bytes(), stdout.truncated
+		res.Stderr = make([][]byte, len(stderrs))
+		for i, b := range stderrs {
+			res.Stderr[i] = b.buf.Bytes()
+			res.Truncated = res.Truncated || b.truncated
+		}
+	}()
+
+	last := len(p.Cmds) - 1
+	for i, cmd := range p.Cmds[:last] {
+		var err error
+		if p.Cmds[i+1].Stdin, err = cmd.StdoutPipe(); err != nil {
+			return res, err
+		}
+	}
+	p.Cmds[last].Stdout = stdout
+	for i, cmd := range p.Cmds {
+		cmd.Stderr = stderrs[i]
+		if cmd.WaitDelay == 0 {
+			cmd.WaitDelay = waitDelay
+		}
+	}
+
+	var started []*exec.Cmd
+	// Kill the started commands when ctx is done.
+	var mu sync.Mutex
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			defer mu.Unlock()
+			for _, cmd := range started {
+				Kill(cmd)
+			}
+		case <-done:
+		}
+	}()
+
+	var firstErr error
+	for i, cmd := range p.Cmds {
+		if err := ctx.Err(); err != nil {
+			firstErr = err
+			break
+		}
+		mu.Lock()
+		err := cmd.Start()
+		if err == nil {
+			started = append(started, cmd)
+		}
+		mu.Unlock()
+		if err != nil {
+			firstErr = &Error{Args: cmd.Args, Err: err}
+			// Close the pipe the command would have read, so that
+			// the previous commands don't block writing to it.
+			if i > 0 {
+				if c, ok := cmd.Stdin.(interface{ Close() error }); ok {
+					c.Close()
+				}
+			}
+			break
+		}
+	}
+
+	// Wait for all the started commands, not only up to the first
+	// failure, so that none is left running.
+	for i, cmd := range started {
+		if err := cmd.Wait(); err != nil && firstErr == nil {
+			firstErr = &Error{Args: cmd.Args, Stderr: stderrs[i].buf.Bytes(), Err: err}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return res, err
+	}
+	return res, firstErr
+}
+
+// limitedBuffer keeps the first max bytes written to it and discards the
+// rest.
+type limitedBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(p)
+	if room := b.max - b.buf.Len(); n > room {
+		p = p[:room]
+		b.truncated = true
+	}
+	b.buf.Write(p)
+	return n, nil
+}
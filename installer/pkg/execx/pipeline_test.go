@@ -0,0 +1,176 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package execx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// helper returns a command running TestHelperProcess with actions, so that
+// the tests don't depend on the commands of a Unix shell:
+//
+//	stderr=<s>  writes s and a newline to the standard error
+//	stdout=<s>  writes s and a newline to the standard output
+//	upper       copies the standard input to the standard output in upper case
+//	cat         copies the standard input to the standard output
+//	discard     reads the standard input
+//	zeros=<n>   writes n zero bytes to the standard output
+//	sleep=<d>   sleeps for duration d
+//	exit=<n>    exits with status n
+func helper(actions ...string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], append([]string{"-test.run=TestHelperProcess", "--"}, actions...)...)
+	cmd.Env = append(os.Environ(), "EXECX_HELPER_PROCESS=1")
+	return cmd
+}
+
+// TestHelperProcess is the command run by helper.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("EXECX_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	for _, a := range args[1:] {
+		key, value := a, ""
+		if i := strings.Index(a, "="); i >= 0 {
+			key, value = a[:i], a[i+1:]
+		}
+		switch key {
+		case "stderr":
+			fmt.Fprintln(os.Stderr, value)
+		case "stdout":
+			fmt.Println(value)
+		case "upper":
+			b, _ := ioutil.ReadAll(os.Stdin)
+			os.Stdout.Write(bytes.ToUpper(b))
+		case "cat":
+			io.Copy(os.Stdout, os.Stdin)
+		case "discard":
+			io.Copy(ioutil.Discard, os.Stdin)
+		case "zeros":
+			n, _ := strconv.Atoi(value)
+			os.Stdout.Write(make([]byte, n))
+		case "sleep":
+			d, _ := time.ParseDuration(value)
+			time.Sleep(d)
+		case "exit":
+			n, _ := strconv.Atoi(value)
+			os.Exit(n)
+		}
+	}
+	os.Exit(0)
+}
+
+// TestRun tests that the output of each command is piped into the next and
+// that the standard error of each command is kept separately.
+func TestRun(t *testing.T) {
+	res, err := Run(context.Background(),
+		helper("stderr=one", "stdout=hello"),
+		helper("stderr=two", "upper"),
+		helper("cat"))
+	if err != nil {
+		t.Fatalf("Unexpected error running pipeline: %v", err)
+	}
+	if got, want := string(res.Stdout), "HELLO\n"; got != want {
+		t.Fatalf("Stdout does not match: got %q; want %q", got, want)
+	}
+	if len(res.Stderr) != 3 {
+		t.Fatalf("Unexpected number of stderr outputs: got %d; want 3", len(res.Stderr))
+	}
+	for i, want := range []string{"one\n", "two\n", ""} {
+		if got := string(res.Stderr[i]); got != want {
+			t.Fatalf("Stderr of command %d does not match: got %q; want %q", i, got, want)
+		}
+	}
+	if res.Truncated {
+		t.Fatalf("Output must not be truncated")
+	}
+}
+
+// TestRunError tests that the error names the failed command and holds its
+// standard error.
+func TestRunError(t *testing.T) {
+	failing := helper("discard", "stderr=oops", "exit=3")
+	res, err := Run(context.Background(), helper("stdout=ok"), failing)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Unexpected error type: got %T (%v); want *Error", err, err)
+	}
+	if got, want := strings.Join(e.Args, " "), strings.Join(failing.Args, " "); got != want {
+		t.Fatalf("Failed command does not match: got %q; want %q", got, want)
+	}
+	if got, want := string(e.Stderr), "oops\n"; got != want {
+		t.Fatalf("Stderr does not match: got %q; want %q", got, want)
+	}
+	if !strings.HasSuffix(err.Error(), ": oops") {
+		t.Fatalf("Error message must end with the stderr: got %q", err.Error())
+	}
+	if got, want := string(res.Stderr[1]), "oops\n"; got != want {
+		t.Fatalf("Result stderr does not match: got %q; want %q", got, want)
+	}
+
+	if _, err := Run(context.Background(), helper("stdout=ok"), exec.Command("/nonexistent/command")); err == nil {
+		t.Fatalf("Expected an error starting a nonexistent command")
+	}
+}
+
+// TestRunMaxOutput tests that output beyond the limit is discarded without
+// blocking the commands.
+func TestRunMaxOutput(t *testing.T) {
+	p := &Pipeline{
+		Cmds:      []*exec.Cmd{helper("zeros=100000"), helper("cat")},
+		MaxOutput: 10,
+	}
+	res, err := p.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error running pipeline: %v", err)
+	}
+	if len(res.Stdout) != 10 {
+		t.Fatalf("Stdout length does not match: got %d; want 10", len(res.Stdout))
+	}
+	if !res.Truncated {
+		t.Fatalf("Output must be truncated")
+	}
+}
+
+// TestRunContext tests that the commands are killed when the context is
+// done.
+func TestRunContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := Run(ctx, helper("sleep=10s"), helper("cat"))
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Error does not match: got %v; want %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("Pipeline was not killed: ran for %v", d)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package execx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// stubEnv holds the Response the executable started by a Stub writes.
+const stubEnv = "EXECX_STUB_RESPONSE"
+
+// Call is a command created by a Stub.
+type Call struct {
+	Name string
+	Args []string
+}
+
+func (c Call) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// Response is the output and exit status of a stubbed command.
+type Response struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Stub is an Executor recording the commands it creates, which write the
+// Response of Respond instead of running the programs. The commands run the
+// current executable, which must call StubMain first, e.g. in the TestMain
+// of the tests using a Stub. It is safe for concurrent use.
+type Stub struct {
+	// Respond returns the response of the command name with args. A nil
+	// Respond makes all the commands succeed without output.
+	Respond func(name string, args []string) Response
+
+	// Missing are the programs LookPath does not find, all others are
+	// found.
+	Missing []string
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (s *Stub) Command(name string, args ...string) *exec.Cmd {
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Name: name, Args: args})
+	s.mu.Unlock()
+
+	var r Response
+	if s.Respond != nil {
+		r = s.Respond(name, args)
+	}
+	b, _ := json.Marshal(r)
+	// The test pattern matches no test, so an executable not calling
+	// StubMain exits right away.
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), stubEnv+"="+string(b))
+	return cmd
+}
+
+func (s *Stub) LookPath(file string) (string, error) {
+	for _, m := range s.Missing {
+		if m == file {
+			return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+		}
+	}
+	return file, nil
+}
+
+// Calls returns the commands created so far, in order.
+func (s *Stub) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// StubMain writes the Response and exits if the executable was started by
+// a Stub, and returns otherwise.
+func StubMain() {
+	v, ok := os.LookupEnv(stubEnv)
+	if !ok {
+		return
+	}
+	var r Response
+	if err := json.Unmarshal([]byte(v), &r); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid stub response: %v\n", err)
+		os.Exit(2)
+	}
+	// Read the input like the real command would, so that writers don't
+	// fail on a closed pipe.
+	io.Copy(ioutil.Discard, os.Stdin)
+	io.WriteString(os.Stdout, r.Stdout)
+	io.WriteString(os.Stderr, r.Stderr)
+	os.Exit(r.ExitCode)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package execx
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	StubMain()
+	os.Exit(m.Run())
+}
+
+// TestStub tests that the commands of a Stub are recorded and write the
+// response for their command line.
+func TestStub(t *testing.T) {
+	s := &Stub{
+		Respond: func(name string, args []string) Response {
+			if len(args) > 0 && args[0] == "fail" {
+				return Response{Stderr: "oops", ExitCode: 3}
+			}
+			return Response{Stdout: strings.Join(args, ",")}
+		},
+	}
+
+	output, err := s.Command("kubectl", "get", "pods").Output()
+	if err != nil {
+		t.Fatalf("Unexpected error running stubbed command: %v", err)
+	}
+	if got, want := string(output), "get,pods"; got != want {
+		t.Fatalf("Output does not match: got %q; want %q", got, want)
+	}
+
+	cmd := s.Command("cfssl", "fail")
+	cmd.Stdin = strings.NewReader("input")
+	output, err = cmd.CombinedOutput()
+	if ee, ok := err.(*exec.ExitError); !ok || ee.ExitCode() != 3 {
+		t.Fatalf("Error does not match: got %v; want exit status 3", err)
+	}
+	if got, want := string(output), "oops"; got != want {
+		t.Fatalf("Output does not match: got %q; want %q", got, want)
+	}
+
+	want := []Call{{"kubectl", []string{"get", "pods"}}, {"cfssl", []string{"fail"}}}
+	if got := s.Calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Calls do not match: got %v; want %v", got, want)
+	}
+}
+
+// TestStubPipeline tests that stubbed commands run in pipelines.
+func TestStubPipeline(t *testing.T) {
+	s := &Stub{
+		Respond: func(name string, args []string) Response {
+			return Response{Stdout: name}
+		},
+	}
+	res, err := Run(context.Background(), s.Command("cfssl", "genkey"), s.Command("cfssljson", "-bare"))
+	if err != nil {
+		t.Fatalf("Unexpected error running pipeline: %v", err)
+	}
+	if got, want := string(res.Stdout), "cfssljson"; got != want {
+		t.Fatalf("Stdout does not match: got %q; want %q", got, want)
+	}
+}
+
+// TestStubLookPath tests that a Stub finds all the programs but the missing
+// ones.
+func TestStubLookPath(t *testing.T) {
+	s := &Stub{Missing: []string{"gcloud"}}
+	if _, err := s.LookPath("kubectl"); err != nil {
+		t.Fatalf("Unexpected error looking up kubectl: %v", err)
+	}
+	if _, err := s.LookPath("gcloud"); err == nil {
+		t.Fatalf("Expected an error looking up a missing program")
+	}
+}
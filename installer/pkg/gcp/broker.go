@@ -16,25 +16,35 @@ limitations under the License.
 package gcp
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"os/exec"
-	"strconv"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
 
 const (
-	// The old and new command group name, and the version that made this change.
-	// See https://cloud.google.com/sdk/docs/release-notes#18800_2018-02-07 for more details.
-	oldSMCommandGroup = "service-management"
-	newSMCommandGroup = "services"
-	smChangeVersion   = "188.0.0"
+	serviceUsageEndpoint = "https://serviceusage.googleapis.com/v1/"
+	iamEndpoint          = "https://iam.googleapis.com/v1/"
+
+	// policyUpdateAttempts is how many times a project IAM policy is read
+	// and written back when it is concurrently modified.
+	policyUpdateAttempts = 5
+)
+
+var (
+	// operationPollInterval is how often the operation enabling an API is
+	// read, and operationTimeout how long it may take.
+	operationPollInterval = 2 * time.Second
+	operationTimeout      = 5 * time.Minute
 )
 
-// EnableAPIs enables given APIs in user's GCP project.
-func EnableAPIs(apis []string) error {
-	existingAPIs, err := enabledAPIs()
+// EnableAPIs enables given APIs in project projectID.
+func EnableAPIs(client *http.Client, projectID string, apis []string) error {
+	existingAPIs, err := EnabledAPIs(client, projectID)
 	if err != nil {
 		return err
 	}
@@ -43,7 +53,7 @@ func EnableAPIs(apis []string) error {
 		if _, found := existingAPIs[api]; !found {
 			// Each enableAPI() can take more than a minute, so we want to show the status per API.
 			fmt.Printf("enabling a GCP API: %s\n", api)
-			err = enableAPI(api)
+			err = EnableAPI(client, projectID, api)
 			if err != nil {
 				return err
 			}
@@ -53,140 +63,201 @@ func EnableAPIs(apis []string) error {
 	return nil
 }
 
-// enabledAPIs returned set of enabled GCP APIs.
-func enabledAPIs() (map[string]bool, error) {
-	cg, err := getCommandGroupByVersion(smChangeVersion, oldSMCommandGroup, newSMCommandGroup)
-	if err != nil {
-		return nil, fmt.Errorf("error retrieving command group for Service Management: %v", err)
-	}
-
-	cmd := exec.Command("gcloud", cg, "list", "--format", "json")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve enabled GCP APIs : %v", err)
-	}
-
-	var apis []gcpAPI
-	err = json.Unmarshal(output, &apis)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse enabled API response : %v", err)
-	}
-
-	m := make(map[string]bool, len(apis))
-	for _, api := range apis {
-		m[api.ServiceName] = true
+// EnabledAPIs returns the set of enabled GCP APIs of project projectID.
+func EnabledAPIs(client *http.Client, projectID string) (map[string]bool, error) {
+	m := make(map[string]bool)
+	pageToken := ""
+	for {
+		var resp struct {
+			Services []struct {
+				Config struct {
+					Name string `json:"name"`
+				} `json:"config"`
+			} `json:"services"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		u := serviceUsageEndpoint + "projects/" + projectID + "/services?filter=state:ENABLED&pageSize=200"
+		if pageToken != "" {
+			u += "&pageToken=" + url.QueryEscape(pageToken)
+		}
+		if err := callJSON(client, http.MethodGet, u, nil, &resp); err != nil {
+			return nil, fmt.Errorf("failed to retrieve enabled GCP APIs : %v", err)
+		}
+		for _, s := range resp.Services {
+			m[s.Config.Name] = true
+		}
+		if resp.NextPageToken == "" {
+			return m, nil
+		}
+		pageToken = resp.NextPageToken
 	}
-
-	return m, nil
 }
 
-type gcpAPI struct {
-	ServiceName string `json:"serviceName"`
+// operation is a long-running operation of a GCP API.
+type operation struct {
+	Name  string `json:"name"`
+	Done  bool   `json:"done"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
 }
 
-// enableAPI enables a GCP API.
-func enableAPI(api string) error {
-	cg, err := getCommandGroupByVersion(smChangeVersion, oldSMCommandGroup, newSMCommandGroup)
-	if err != nil {
-		return fmt.Errorf("error retrieving command group for Service Management: %v", err)
-	}
-
-	cmd := exec.Command("gcloud", cg, "enable", api)
-	_, err = cmd.CombinedOutput()
-	if err != nil {
+// EnableAPI enables a GCP API in project projectID and waits until it is
+// enabled.
+func EnableAPI(client *http.Client, projectID, api string) error {
+	var op operation
+	u := serviceUsageEndpoint + "projects/" + projectID + "/services/" + api + ":enable"
+	if err := callJSON(client, http.MethodPost, u, struct{}{}, &op); err != nil {
 		return fmt.Errorf("failed to enable API %s : %v", api, err)
 	}
 
-	return nil
-}
-
-// getCommandGroupByVersion picks the command group (CG) name based on the comparison between the
-// given version and the current version.
-func getCommandGroupByVersion(version, oldCG, newCG string) (string, error) {
-	cmd := exec.Command("gcloud", "version", "--format=json")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to retrieve Google Cloud SDK version: %s : %v", string(output), err)
+	deadline := time.Now().Add(operationTimeout)
+	for !op.Done {
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("failed to enable API %s : not enabled after %v", api, operationTimeout)
+		}
+		time.Sleep(operationPollInterval)
+		if err := callJSON(client, http.MethodGet, serviceUsageEndpoint+op.Name, nil, &op); err != nil {
+			return fmt.Errorf("failed to enable API %s : %v", api, err)
+		}
 	}
-
-	versionMap := make(map[string]interface{})
-	err = json.Unmarshal(output, &versionMap)
-	if err != nil {
-		return "", fmt.Errorf("error unmarshalling version result: %s : %v", string(output), err)
+	if op.Error != nil {
+		return fmt.Errorf("failed to enable API %s : %s", api, op.Error.Message)
 	}
+	return nil
+}
 
-	// Get the version of Google Cloud SDK.
-	currentVersion := versionMap["Google Cloud SDK"].(string)
-
-	// Parse the given version and the target version to integers.
-	cv, err := parseVersionToInt(currentVersion)
-	if err != nil {
-		return "", fmt.Errorf("error parsing version string to integer: %v", err)
+// CreateServiceAccount creates service account name in project projectID.
+func CreateServiceAccount(client *http.Client, projectID, name, displayName string) error {
+	req := map[string]interface{}{
+		"accountId":      name,
+		"serviceAccount": map[string]string{"displayName": displayName},
 	}
-	v, err := parseVersionToInt(version)
-	if err != nil {
-		return "", fmt.Errorf("error parsing version string to integer: %v", err)
+	var sa ServiceAccount
+	if err := callJSON(client, http.MethodPost, iamEndpoint+"projects/"+projectID+"/serviceAccounts", req, &sa); err != nil {
+		return fmt.Errorf("failed to create service account : %v", err)
 	}
+	return nil
+}
 
-	// Compare the versions. If the current version is older than the given version, return the oldCG,
-	// otherwise, return the newCG.
-	if cv < v {
-		return oldCG, nil
+// GetServiceAccount returns the service account email. It returns nil if it
+// does not exist.
+func GetServiceAccount(client *http.Client, email string) (*ServiceAccount, error) {
+	var sa ServiceAccount
+	if err := callJSON(client, http.MethodGet, serviceAccountURL(email), nil, &sa); err != nil {
+		if se, ok := err.(*StatusError); ok && se.Code == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve service account : %v", err)
 	}
-
-	return newCG, nil
+	return &sa, nil
 }
 
-func parseVersionToInt(version string) (int, error) {
-	version = strings.Replace(version, ".", "", -1)
-	return strconv.Atoi(version)
+// serviceAccountURL returns the URL of service account email, in whichever
+// project it is.
+func serviceAccountURL(email string) string {
+	return iamEndpoint + "projects/-/serviceAccounts/" + email
 }
 
-func CreateServiceAccount(name, displayName string) error {
-	cmd := exec.Command("gcloud", "iam", "service-accounts", "create",
-		name,
-		"--display-name", displayName,
-		"--format", "json")
-	output, err := cmd.CombinedOutput()
+// AddServiceAccountPerms grants role to service account email on project
+// projectID.
+func AddServiceAccountPerms(client *http.Client, projectID, email, role string) error {
+	member := "serviceAccount:" + email
+	err := updateProjectPolicy(client, projectID, func(p *iamPolicy) bool {
+		for i, b := range p.Bindings {
+			if b.Role == role && b.Condition == nil {
+				for _, m := range b.Members {
+					if m == member {
+						return false
+					}
+				}
+				p.Bindings[i].Members = append(b.Members, member)
+				return true
+			}
+		}
+		p.Bindings = append(p.Bindings, iamBinding{Role: role, Members: []string{member}})
+		return true
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create service account : %v %s", err, string(output))
+		return fmt.Errorf("failed to add service account permissions: %v", err)
 	}
-
-	return err
+	return nil
 }
 
-func GetServiceAccount(email string) (*ServiceAccount, error) {
-	cmd := exec.Command("gcloud", "iam", "service-accounts", "describe", email, "--format", "json")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve service account : %v:%v", err, string(output))
-	}
-
-	var sa ServiceAccount
-	err = json.Unmarshal(output, &sa)
+// RemoveServiceAccountPerms revokes role from service account email on
+// project projectID.
+func RemoveServiceAccountPerms(client *http.Client, projectID, email, role string) error {
+	member := "serviceAccount:" + email
+	err := updateProjectPolicy(client, projectID, func(p *iamPolicy) bool {
+		changed := false
+		bindings := p.Bindings[:0]
+		for _, b := range p.Bindings {
+			if b.Role == role && b.Condition == nil {
+				members := b.Members[:0]
+				for _, m := range b.Members {
+					if m == member {
+						changed = true
+						continue
+					}
+					members = append(members, m)
+				}
+				if len(members) == 0 {
+					continue
+				}
+				b.Members = members
+			}
+			bindings = append(bindings, b)
+		}
+		p.Bindings = bindings
+		return changed
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse service account API response : %v", err)
+		return fmt.Errorf("failed to remove service account permissions: %v", err)
 	}
+	return nil
+}
 
-	return &sa, nil
+// iamPolicy is the subset of an IAM policy written back by
+// updateProjectPolicy. The other fields are left unchanged by setIamPolicy.
+type iamPolicy struct {
+	Version  int          `json:"version,omitempty"`
+	Etag     string       `json:"etag,omitempty"`
+	Bindings []iamBinding `json:"bindings,omitempty"`
 }
 
-func AddServiceAccountPerms(projectID, email, roles string) error {
-	cmd := exec.Command("gcloud", "projects", "add-iam-policy-binding", projectID, "--member", "serviceAccount:"+email, "--role", roles, "--format", "json")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to add service account permissions: %v %s", string(output), err)
-	}
-	return nil
+type iamBinding struct {
+	Role    string   `json:"role"`
+	Members []string `json:"members"`
+	// Condition is kept as is, conditional bindings are never modified.
+	Condition *json.RawMessage `json:"condition,omitempty"`
 }
 
-func RemoveServiceAccountPerms(projectID, email, roles string) error {
-	cmd := exec.Command("gcloud", "projects", "remove-iam-policy-binding", projectID, "--member", "serviceAccount:"+email, "--role", roles, "--format", "json")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to remove service account permissions: %v %s", string(output), err)
+// updateProjectPolicy applies update to the IAM policy of project
+// projectID, and writes it back if update returns true. It retries if the
+// policy was modified concurrently.
+func updateProjectPolicy(client *http.Client, projectID string, update func(*iamPolicy) bool) error {
+	getReq := map[string]interface{}{
+		"options": map[string]int{"requestedPolicyVersion": 3},
 	}
-	return nil
+	var err error
+	for attempt := 0; attempt < policyUpdateAttempts; attempt++ {
+		var p iamPolicy
+		if err = callJSON(client, http.MethodPost, resourceManagerEndpoint+projectID+":getIamPolicy", getReq, &p); err != nil {
+			return err
+		}
+		if !update(&p) {
+			return nil
+		}
+		err = callJSON(client, http.MethodPost, resourceManagerEndpoint+projectID+":setIamPolicy",
+			map[string]interface{}{"policy": p}, &p)
+		// The etag does not match if the policy was modified since it was
+		// read.
+		if se, ok := err.(*StatusError); !ok || se.Code != http.StatusConflict {
+			return err
+		}
+	}
+	return err
 }
 
 type ServiceAccount struct {
@@ -195,11 +266,21 @@ type ServiceAccount struct {
 	DisplayName string `json:"displayName"`
 }
 
-func CreateServiceAccountKey(email, keyFilepath string) error {
-	cmd := exec.Command("gcloud", "iam", "service-accounts", "keys", "create", "--iam-account", email, keyFilepath)
-	out, err := cmd.CombinedOutput()
+// CreateServiceAccountKey creates a key for service account email and
+// writes its JSON key file to keyFilepath.
+func CreateServiceAccountKey(client *http.Client, email, keyFilepath string) error {
+	var key struct {
+		PrivateKeyData string `json:"privateKeyData"`
+	}
+	if err := callJSON(client, http.MethodPost, serviceAccountURL(email)+"/keys", struct{}{}, &key); err != nil {
+		return fmt.Errorf("failed to create service account key: %v", err)
+	}
+	b, err := base64.StdEncoding.DecodeString(key.PrivateKeyData)
 	if err != nil {
-		return fmt.Errorf("failed to create service account key: %s : %v", string(out), err)
+		return fmt.Errorf("failed to decode service account key: %v", err)
+	}
+	if err := ioutil.WriteFile(keyFilepath, b, 0600); err != nil {
+		return fmt.Errorf("failed to write service account key: %v", err)
 	}
 	return nil
 }
@@ -212,20 +293,15 @@ type saKey struct {
 }
 
 // RemoveAllServiceAccountKeys removes all the keys associated with the service account.
-func RemoveAllServiceAccountKeys(email string) error {
-	cmd := exec.Command("gcloud", "iam", "service-accounts", "keys", "list", "--iam-account", email, "--format=json")
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to list service account keys: %s : %v", string(out), err)
+func RemoveAllServiceAccountKeys(client *http.Client, email string) error {
+	var resp struct {
+		Keys []saKey `json:"keys"`
 	}
-
-	var keys []saKey
-	err = json.Unmarshal(out, &keys)
-	if err != nil {
-		return fmt.Errorf("failed to unmarshal service account keys: %s : %v", string(out), err)
+	if err := callJSON(client, http.MethodGet, serviceAccountURL(email)+"/keys", nil, &resp); err != nil {
+		return fmt.Errorf("failed to list service account keys: %v", err)
 	}
 
-	for _, k := range keys {
+	for _, k := range resp.Keys {
 		// Check the life ("ValidBeforeTime" - "ValidAfterTime") of it because we only need to delete the keys generated
 		// by the user. Those keys should be living for 3650 days. Here we check whether the life is more than a year.
 		// The service accounts also have some robot keys, but those keys are only alive for a couple of days.
@@ -242,7 +318,7 @@ func RemoveAllServiceAccountKeys(email string) error {
 		life := et.Sub(bt)
 		if life > 365*24*time.Hour {
 			keyID := strings.Split(k.Name, "/")[strings.Count(k.Name, "/")]
-			RemoveServiceAccountKey(email, keyID)
+			RemoveServiceAccountKey(client, email, keyID)
 		}
 	}
 
@@ -250,39 +326,15 @@ func RemoveAllServiceAccountKeys(email string) error {
 }
 
 // RemoveServiceAccountKey removes the given key from the service account.
-func RemoveServiceAccountKey(email, keyID string) {
-	cmd := exec.Command("gcloud", "iam", "service-accounts", "keys", "delete", keyID, "--iam-account", email, "--quiet" /*disable interactive mode*/)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		fmt.Printf("failed to delete service account key: %s : %v\n", string(out), err)
+func RemoveServiceAccountKey(client *http.Client, email, keyID string) {
+	var resp struct{}
+	if err := callJSON(client, http.MethodDelete, serviceAccountURL(email)+"/keys/"+keyID, nil, &resp); err != nil {
+		fmt.Printf("failed to delete service account key: %v\n", err)
 		fmt.Printf("WARNING: Please clean up the key from service account %s", email)
 	}
 }
 
-// GetConfigValue returns a property value from given section of gcloud's
-// default config.
-func GetConfigValue(section, property string) (string, error) {
-	cmd := exec.Command("gcloud", "config", "get-value", section+"/"+property)
-	value, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to retrieve config-value : %v", err)
-	}
-	return strings.Trim(string(value), "\n"), nil
-}
-
 // GetConfigMap returns all the gcloud config in a JSON struct.
 func GetConfigMap() (map[string]interface{}, error) {
-	cmd := exec.Command("gcloud", "config", "list", "--format=json")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list gcloud config : %v", err)
-	}
-
-	var result map[string]interface{}
-	err = json.Unmarshal(output, &result)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal gcloud config: %s : %v", string(output), err)
-	}
-
-	return result, nil
+	return DefaultResolver.Config()
 }
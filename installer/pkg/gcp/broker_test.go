@@ -0,0 +1,272 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakePolicy serves the IAM policy of project p, whose setIamPolicy calls
+// fail with 409 Conflict the first conflicts times, as if the policy was
+// modified concurrently.
+type fakePolicy struct {
+	policy    iamPolicy
+	conflicts int
+	gets      int
+	sets      []iamPolicy
+}
+
+func (f *fakePolicy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v1/projects/p:getIamPolicy":
+		f.gets++
+		json.NewEncoder(w).Encode(f.policy)
+	case "/v1/projects/p:setIamPolicy":
+		var req struct {
+			Policy iamPolicy `json:"policy"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.sets = append(f.sets, req.Policy)
+		if f.conflicts > 0 || req.Policy.Etag != f.policy.Etag {
+			f.conflicts--
+			// Another writer changed the policy.
+			f.policy.Etag += "+"
+			http.Error(w, "etag mismatch", http.StatusConflict)
+			return
+		}
+		f.policy = req.Policy
+		f.policy.Etag += "+"
+		json.NewEncoder(w).Encode(f.policy)
+	default:
+		http.Error(w, "unexpected request "+r.URL.String(), http.StatusNotFound)
+	}
+}
+
+// TestAddServiceAccountPerms tests that the member is added to the binding
+// of the role, not to a conditional one, and that the policy is read again
+// and written back with its new etag after a conflict.
+func TestAddServiceAccountPerms(t *testing.T) {
+	condition := json.RawMessage(`{"title":"expires"}`)
+	f := &fakePolicy{
+		policy: iamPolicy{Version: 3, Etag: "e", Bindings: []iamBinding{
+			{Role: "roles/editor", Members: []string{"user:a@example.com"}, Condition: &condition},
+			{Role: "roles/editor", Members: []string{"user:b@example.com"}},
+		}},
+		conflicts: 2,
+	}
+	client, stop := testClient(t, f)
+	defer stop()
+
+	if err := AddServiceAccountPerms(client, "p", "sa@p.iam.gserviceaccount.com", "roles/editor"); err != nil {
+		t.Fatalf("Unexpected error adding permissions: %v", err)
+	}
+	if f.gets != 3 || len(f.sets) != 3 {
+		t.Fatalf("Expected 3 reads and writes of the policy, got %d and %d", f.gets, len(f.sets))
+	}
+	if got, want := f.sets[2].Etag, "e++"; got != want {
+		t.Fatalf("Etag of the last write does not match: got %q; want %q", got, want)
+	}
+	want := []iamBinding{
+		{Role: "roles/editor", Members: []string{"user:a@example.com"}, Condition: &condition},
+		{Role: "roles/editor", Members: []string{"user:b@example.com", "serviceAccount:sa@p.iam.gserviceaccount.com"}},
+	}
+	if !reflect.DeepEqual(f.policy.Bindings, want) {
+		t.Fatalf("Bindings do not match: got %+v; want %+v", f.policy.Bindings, want)
+	}
+
+	// Granting the role again leaves the policy alone.
+	if err := AddServiceAccountPerms(client, "p", "sa@p.iam.gserviceaccount.com", "roles/editor"); err != nil {
+		t.Fatalf("Unexpected error adding permissions again: %v", err)
+	}
+	if len(f.sets) != 3 {
+		t.Fatalf("Unexpected write of an unchanged policy: %+v", f.sets[3:])
+	}
+}
+
+// TestRemoveServiceAccountPerms tests that the member is removed from the
+// binding of the role, which is dropped once empty, and that the update
+// fails after too many conflicts.
+func TestRemoveServiceAccountPerms(t *testing.T) {
+	f := &fakePolicy{policy: iamPolicy{Etag: "e", Bindings: []iamBinding{
+		{Role: "roles/viewer", Members: []string{"serviceAccount:sa@p.iam.gserviceaccount.com"}},
+		{Role: "roles/editor", Members: []string{"user:b@example.com"}},
+	}}}
+	client, stop := testClient(t, f)
+	defer stop()
+
+	if err := RemoveServiceAccountPerms(client, "p", "sa@p.iam.gserviceaccount.com", "roles/viewer"); err != nil {
+		t.Fatalf("Unexpected error removing permissions: %v", err)
+	}
+	if want := []iamBinding{{Role: "roles/editor", Members: []string{"user:b@example.com"}}}; !reflect.DeepEqual(f.policy.Bindings, want) {
+		t.Fatalf("Bindings do not match: got %+v; want %+v", f.policy.Bindings, want)
+	}
+
+	f.conflicts = policyUpdateAttempts
+	err := RemoveServiceAccountPerms(client, "p", "user:b@example.com", "roles/editor")
+	if err != nil {
+		t.Fatalf("Unexpected error removing a missing member: %v", err)
+	}
+	err = AddServiceAccountPerms(client, "p", "sa@p.iam.gserviceaccount.com", "roles/editor")
+	if err == nil || !strings.Contains(err.Error(), "409") {
+		t.Fatalf("Expected a conflict error after %d attempts, got %v", policyUpdateAttempts, err)
+	}
+}
+
+// TestCreateServiceAccountKey tests that the decoded key file is written
+// readable only by the user.
+func TestCreateServiceAccountKey(t *testing.T) {
+	client, stop := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/projects/-/serviceAccounts/sa@p.iam.gserviceaccount.com/keys" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"privateKeyData": base64.StdEncoding.EncodeToString([]byte(`{"type":"service_account"}`))})
+	}))
+	defer stop()
+	dir, err := ioutil.TempDir("", "sa-key")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	key := filepath.Join(dir, "key.json")
+	if err := CreateServiceAccountKey(client, "sa@p.iam.gserviceaccount.com", key); err != nil {
+		t.Fatalf("Unexpected error creating the key: %v", err)
+	}
+	b, err := ioutil.ReadFile(key)
+	if err != nil {
+		t.Fatalf("Unexpected error reading the key: %v", err)
+	}
+	if string(b) != `{"type":"service_account"}` {
+		t.Fatalf("Key does not match: got %s", b)
+	}
+	if fi, err := os.Stat(key); err != nil || fi.Mode().Perm() != 0600 {
+		t.Fatalf("Expected the key to be readable only by the user, got %v, %v", fi.Mode(), err)
+	}
+
+	if err := CreateServiceAccountKey(client, "other@p.iam.gserviceaccount.com", key); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("Expected a not found error, got %v", err)
+	}
+}
+
+// setOperationParameters makes the operations be polled right away, and
+// returns the function restoring the defaults.
+func setOperationParameters(timeout time.Duration) func() {
+	interval, deadline := operationPollInterval, operationTimeout
+	operationPollInterval, operationTimeout = time.Millisecond, timeout
+	return func() { operationPollInterval, operationTimeout = interval, deadline }
+}
+
+// TestEnableAPI tests that the operation enabling an API is polled until
+// it is done, and that its error is returned.
+func TestEnableAPI(t *testing.T) {
+	defer setOperationParameters(time.Minute)()
+	for _, tc := range []struct {
+		api     string
+		polls   int
+		opError string
+	}{
+		{api: "pubsub.googleapis.com", polls: 3},
+		{api: "spanner.googleapis.com", polls: 0},
+		{api: "bigquery.googleapis.com", polls: 1, opError: "permission denied"},
+	} {
+		polls := 0
+		client, stop := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op := map[string]interface{}{"name": "operations/enable-1"}
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/v1/projects/p/services/"+tc.api+":enable":
+			case r.Method == http.MethodGet && r.URL.Path == "/v1/operations/enable-1":
+				polls++
+			default:
+				http.Error(w, "unexpected request "+r.URL.String(), http.StatusNotFound)
+				return
+			}
+			if polls >= tc.polls {
+				op["done"] = true
+				if tc.opError != "" {
+					op["error"] = map[string]interface{}{"code": 7, "message": tc.opError}
+				}
+			}
+			json.NewEncoder(w).Encode(op)
+		}))
+		err := EnableAPI(client, "p", tc.api)
+		stop()
+		if tc.opError != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.opError) {
+				t.Fatalf("%s: expected error %q, got %v", tc.api, tc.opError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.api, err)
+		}
+		if polls != tc.polls {
+			t.Fatalf("%s: polls do not match: got %d; want %d", tc.api, polls, tc.polls)
+		}
+	}
+}
+
+// TestEnableAPITimeout tests that an operation not done in time fails.
+func TestEnableAPITimeout(t *testing.T) {
+	defer setOperationParameters(10 * time.Millisecond)()
+	client, stop := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "operations/enable-1"})
+	}))
+	defer stop()
+
+	if err := EnableAPI(client, "p", "pubsub.googleapis.com"); err == nil || !strings.Contains(err.Error(), "not enabled after") {
+		t.Fatalf("Expected a timeout, got %v", err)
+	}
+}
+
+// TestEnabledAPIs tests that all the pages of the enabled services are
+// read.
+func TestEnabledAPIs(t *testing.T) {
+	client, stop := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("filter") != "state:ENABLED" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			w.Write([]byte(`{"services":[{"config":{"name":"iam.googleapis.com"}}],"nextPageToken":"next"}`))
+		case "next":
+			w.Write([]byte(`{"services":[{"config":{"name":"pubsub.googleapis.com"}}]}`))
+		}
+	}))
+	defer stop()
+
+	got, err := EnabledAPIs(client, "p")
+	if err != nil {
+		t.Fatalf("Unexpected error listing the APIs: %v", err)
+	}
+	if want := map[string]bool{"iam.googleapis.com": true, "pubsub.googleapis.com": true}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("APIs do not match: got %v; want %v", got, want)
+	}
+}
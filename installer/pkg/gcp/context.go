@@ -0,0 +1,208 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// accessTokenLifetime is how long a gcloud access token is reused,
+	// they expire after an hour.
+	accessTokenLifetime = 30 * time.Minute
+
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// DefaultResolver is the Resolver of the sc commands calling GCP APIs.
+var DefaultResolver = NewResolver()
+
+// Resolver resolves the GCP context of the GCP API calls, the project, the
+// account and their credentials. gcloud is optional, the gcloud config is
+// read once so that repeated broker and IAM operations don't each pay the
+// startup of gcloud. It is safe for concurrent use.
+type Resolver struct {
+	// Project overrides the project of the gcloud config and of the
+	// Application Default Credentials.
+	Project string
+
+	// Account, if set, is the gcloud account whose credentials are used
+	// instead of the Application Default Credentials.
+	Account string
+
+	mu     sync.Mutex
+	config []byte
+
+	// run runs gcloud with args and returns its standard output.
+	run func(args ...string) ([]byte, error)
+	// lookPath returns an error if gcloud is not installed.
+	lookPath func() error
+	now      func() time.Time
+}
+
+// NewResolver returns a Resolver reading the gcloud config.
+func NewResolver() *Resolver {
+	return &Resolver{
+		run: func(args ...string) ([]byte, error) {
+			return execx.Command("gcloud", args...).Output()
+		},
+		lookPath: func() error {
+			_, err := execx.LookPath("gcloud")
+			return err
+		},
+		now: time.Now,
+	}
+}
+
+// gcloud returns the output of gcloud with args.
+func (r *Resolver) gcloud(args ...string) ([]byte, error) {
+	output, err := r.run(args...)
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("gcloud %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, fmt.Errorf("gcloud %s: %v", strings.Join(args, " "), err)
+	}
+	return output, nil
+}
+
+// Config returns the gcloud config, by section and property.
+func (r *Resolver) Config() (map[string]interface{}, error) {
+	output, err := r.configJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gcloud config : %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal gcloud config: %s : %v", string(output), err)
+	}
+	if r.Project != "" || r.Account != "" {
+		core, _ := result["core"].(map[string]interface{})
+		if core == nil {
+			core = make(map[string]interface{})
+			result["core"] = core
+		}
+		if r.Project != "" {
+			core["project"] = r.Project
+		}
+		if r.Account != "" {
+			core["account"] = r.Account
+		}
+	}
+	return result, nil
+}
+
+// configJSON returns the gcloud config as JSON, it is read once.
+func (r *Resolver) configJSON() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.config == nil {
+		output, err := r.gcloud("config", "list", "--format=json")
+		if err != nil {
+			return nil, err
+		}
+		r.config = output
+	}
+	return r.config, nil
+}
+
+// ConfigValue returns a property of a section of the gcloud config, "" if
+// it is not set.
+func (r *Resolver) ConfigValue(section, property string) (string, error) {
+	config, err := r.Config()
+	if err != nil {
+		return "", err
+	}
+	s, _ := config[section].(map[string]interface{})
+	v, _ := s[property].(string)
+	return v, nil
+}
+
+// ProjectID returns the project to call the GCP APIs on: Project, or the
+// project of the gcloud config if gcloud is installed, or the project of the
+// Application Default Credentials.
+func (r *Resolver) ProjectID() (string, error) {
+	if r.Project != "" {
+		return r.Project, nil
+	}
+	if r.lookPath() == nil {
+		project, err := r.ConfigValue("core", "project")
+		if err != nil || project != "" {
+			return project, err
+		}
+	}
+	creds, err := google.FindDefaultCredentials(context.Background(), cloudPlatformScope)
+	if err == nil && creds.ProjectID != "" {
+		return creds.ProjectID, nil
+	}
+	return "", fmt.Errorf("no project is set, use --project or run gcloud config set project")
+}
+
+// Client returns a client of the GCP APIs authenticated with the gcloud
+// credentials of Account if it is set, and with the Application Default
+// Credentials otherwise.
+func (r *Resolver) Client(ctx context.Context) (*http.Client, error) {
+	if r.Account == "" {
+		return google.DefaultClient(ctx, cloudPlatformScope)
+	}
+	if err := r.lookPath(); err != nil {
+		return nil, fmt.Errorf("--account requires gcloud: %v", err)
+	}
+	// The transport asks for the token on each request, it is reused until
+	// it expires.
+	return &http.Client{Transport: &oauth2.Transport{Source: oauth2.ReuseTokenSource(nil, accountTokenSource{r})}}, nil
+}
+
+type accountTokenSource struct {
+	r *Resolver
+}
+
+func (s accountTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.r.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: token, TokenType: "Bearer", Expiry: s.r.now().Add(accessTokenLifetime)}, nil
+}
+
+// AccessToken returns a gcloud access token of the account of the
+// gcloud config, or of Account.
+func (r *Resolver) AccessToken() (string, error) {
+	output, err := r.gcloud(append([]string{"auth", "print-access-token"}, r.accountArgs()...)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get an access token: %v", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (r *Resolver) accountArgs() []string {
+	if r.Account == "" {
+		return nil
+	}
+	return []string{"--account", r.Account}
+}
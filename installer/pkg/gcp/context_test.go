@@ -0,0 +1,140 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGcloud is a gcloud answering the config and access token lookups,
+// recording its calls.
+type fakeGcloud struct {
+	config string
+	tokens int
+	calls  []string
+}
+
+func (f *fakeGcloud) run(args ...string) ([]byte, error) {
+	f.calls = append(f.calls, strings.Join(args, " "))
+	switch args[0] {
+	case "config":
+		return []byte(f.config), nil
+	case "auth":
+		f.tokens++
+		return []byte(fmt.Sprintf("token-%d\n", f.tokens)), nil
+	}
+	return nil, fmt.Errorf("unexpected gcloud call %v", args)
+}
+
+// testResolver returns a Resolver running f as gcloud.
+func testResolver(f *fakeGcloud) *Resolver {
+	r := NewResolver()
+	r.run = f.run
+	r.lookPath = func() error { return nil }
+	return r
+}
+
+// TestResolverConfig tests that the gcloud config is read once and that
+// --project and --account override its project and account.
+func TestResolverConfig(t *testing.T) {
+	f := &fakeGcloud{config: `{"core":{"project":"p","account":"a@example.com","disable_usage_reporting":"True"}}`}
+	r := testResolver(f)
+
+	for i := 0; i < 2; i++ {
+		project, err := r.ProjectID()
+		if err != nil || project != "p" {
+			t.Fatalf("Project does not match: got %q, %v; want p", project, err)
+		}
+	}
+	r.Project, r.Account = "q", "b@example.com"
+	for _, tc := range []struct{ property, want string }{
+		{"project", "q"},
+		{"account", "b@example.com"},
+		{"disable_usage_reporting", "True"},
+		{"unset", ""},
+	} {
+		if got, err := r.ConfigValue("core", tc.property); err != nil || got != tc.want {
+			t.Fatalf("Property %s does not match: got %q, %v; want %q", tc.property, got, err, tc.want)
+		}
+	}
+	if want := []string{"config list --format=json"}; !reflect.DeepEqual(f.calls, want) {
+		t.Fatalf("gcloud calls do not match: got %v; want %v", f.calls, want)
+	}
+
+	// The project of --project does not need gcloud.
+	r = testResolver(f)
+	r.Project = "q"
+	r.lookPath = func() error { return fmt.Errorf("gcloud not found") }
+	if project, err := r.ProjectID(); err != nil || project != "q" {
+		t.Fatalf("Project does not match: got %q, %v; want q", project, err)
+	}
+}
+
+// TestResolverAccountClient tests that the client of --account sends the
+// access token of the account, reused until it expires.
+func TestResolverAccountClient(t *testing.T) {
+	var auths []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auths = append(auths, r.Header.Get("Authorization"))
+	}))
+	defer s.Close()
+
+	get := func(r *Resolver, n int) {
+		client, err := r.Client(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error creating client: %v", err)
+		}
+		for i := 0; i < n; i++ {
+			resp, err := client.Get(s.URL)
+			if err != nil {
+				t.Fatalf("Unexpected error calling the server: %v", err)
+			}
+			resp.Body.Close()
+		}
+	}
+
+	f := &fakeGcloud{}
+	r := testResolver(f)
+	r.Account = "b@example.com"
+	get(r, 2)
+	if want := []string{"Bearer token-1", "Bearer token-1"}; !reflect.DeepEqual(auths, want) {
+		t.Fatalf("Authorizations do not match: got %v; want %v", auths, want)
+	}
+	if want := []string{"auth print-access-token --account b@example.com"}; !reflect.DeepEqual(f.calls, want) {
+		t.Fatalf("gcloud calls do not match: got %v; want %v", f.calls, want)
+	}
+
+	// Expired tokens are replaced.
+	auths = nil
+	r.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	get(r, 2)
+	if want := []string{"Bearer token-2", "Bearer token-3"}; !reflect.DeepEqual(auths, want) {
+		t.Fatalf("Authorizations do not match: got %v; want %v", auths, want)
+	}
+
+	r.lookPath = func() error { return fmt.Errorf("gcloud not found") }
+	if _, err := r.Client(context.Background()); err == nil || !strings.Contains(err.Error(), "--account requires gcloud") {
+		t.Fatalf("Error does not match: got %v; want --account requires gcloud", err)
+	}
+}
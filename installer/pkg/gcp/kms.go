@@ -0,0 +1,75 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package gcp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+const kmsEndpoint = "https://cloudkms.googleapis.com/v1/"
+
+// kmsKeyRE matches the resource name of a Cloud KMS crypto key.
+var kmsKeyRE = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+
+// ValidateKMSKeyName returns an error if name is not the resource name of a
+// Cloud KMS crypto key, i.e.
+// projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>.
+func ValidateKMSKeyName(name string) error {
+	if !kmsKeyRE.MatchString(name) {
+		return fmt.Errorf("invalid Cloud KMS key %q: expected projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>", name)
+	}
+	return nil
+}
+
+// KMSEncrypt encrypts plaintext with the Cloud KMS crypto key keyName.
+func KMSEncrypt(client *http.Client, keyName string, plaintext []byte) ([]byte, error) {
+	var resp struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	req := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(plaintext)}
+	if err := kmsCall(client, keyName, "encrypt", req, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Ciphertext)
+}
+
+// KMSDecrypt decrypts ciphertext previously encrypted with the Cloud KMS
+// crypto key keyName.
+func KMSDecrypt(client *http.Client, keyName string, ciphertext []byte) ([]byte, error) {
+	var resp struct {
+		Plaintext string `json:"plaintext"`
+	}
+	req := map[string]string{"ciphertext": base64.StdEncoding.EncodeToString(ciphertext)}
+	if err := kmsCall(client, keyName, "decrypt", req, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// kmsCall calls the Cloud KMS method on keyName with the JSON request req and
+// decodes the JSON response into resp.
+func kmsCall(client *http.Client, keyName, method string, req, resp interface{}) error {
+	if err := ValidateKMSKeyName(keyName); err != nil {
+		return err
+	}
+	if err := callJSON(client, http.MethodPost, kmsEndpoint+keyName+":"+method, req, resp); err != nil {
+		return fmt.Errorf("error calling Cloud KMS %s with key %s: %v", method, keyName, err)
+	}
+	return nil
+}
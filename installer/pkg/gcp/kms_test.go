@@ -0,0 +1,107 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testKMSKey = "projects/p/locations/global/keyRings/sc/cryptoKeys/ca"
+
+// fakeKMS serves the encrypt and decrypt methods of testKMSKey, encrypting
+// by reversing the plaintext.
+func fakeKMS() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := "/v1/" + testKMSKey + ":"
+		if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, prefix) {
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		in, out := "plaintext", "ciphertext"
+		if strings.TrimPrefix(r.URL.Path, prefix) == "decrypt" {
+			in, out = out, in
+		}
+		b, err := base64.StdEncoding.DecodeString(req[in])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+		json.NewEncoder(w).Encode(map[string]string{out: base64.StdEncoding.EncodeToString(b)})
+	})
+}
+
+// TestKMSEncryptDecrypt tests that the plaintext is encrypted and decrypted
+// with the crypto key.
+func TestKMSEncryptDecrypt(t *testing.T) {
+	client, stop := testClient(t, fakeKMS())
+	defer stop()
+
+	ciphertext, err := KMSEncrypt(client, testKMSKey, []byte("ca key"))
+	if err != nil {
+		t.Fatalf("Unexpected error encrypting: %v", err)
+	}
+	if string(ciphertext) != "yek ac" {
+		t.Fatalf("Unexpected ciphertext %q", ciphertext)
+	}
+	plaintext, err := KMSDecrypt(client, testKMSKey, ciphertext)
+	if err != nil {
+		t.Fatalf("Unexpected error decrypting: %v", err)
+	}
+	if string(plaintext) != "ca key" {
+		t.Fatalf("Unexpected plaintext %q", plaintext)
+	}
+}
+
+// TestKMSErrors tests that invalid key names are rejected without a call
+// and that failed calls name the method and the key.
+func TestKMSErrors(t *testing.T) {
+	calls := 0
+	client, stop := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, `{"error": {"message": "Permission denied"}}`, http.StatusForbidden)
+	}))
+	defer stop()
+
+	for _, name := range []string{"my-key", "projects/p/locations/global/keyRings/sc", testKMSKey + "/cryptoKeyVersions/1"} {
+		if err := ValidateKMSKeyName(name); err == nil {
+			t.Fatalf("Expected %q to be rejected", name)
+		}
+		if _, err := KMSEncrypt(client, name, []byte("key")); err == nil || !strings.Contains(err.Error(), "invalid Cloud KMS key") {
+			t.Fatalf("Expected %q to be rejected, got %v", name, err)
+		}
+	}
+	if calls != 0 {
+		t.Fatalf("Expected no call with invalid keys, got %d", calls)
+	}
+
+	_, err := KMSDecrypt(client, testKMSKey, []byte("ciphertext"))
+	if err == nil || !strings.Contains(err.Error(), "error calling Cloud KMS decrypt with key "+testKMSKey) || !strings.Contains(err.Error(), "Permission denied") {
+		t.Fatalf("Expected a decrypt error naming the key, got %v", err)
+	}
+}
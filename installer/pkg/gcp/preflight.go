@@ -0,0 +1,82 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	resourceManagerEndpoint = "https://cloudresourcemanager.googleapis.com/v1/projects/"
+	billingEndpoint         = "https://cloudbilling.googleapis.com/v1/projects/"
+)
+
+// Project is a GCP project.
+type Project struct {
+	ProjectID      string `json:"projectId"`
+	ProjectNumber  string `json:"projectNumber"`
+	LifecycleState string `json:"lifecycleState"`
+}
+
+// GetProject returns the project projectID. It returns nil if the project
+// does not exist or the caller may not see it, GCP does not tell apart the
+// two.
+func GetProject(client *http.Client, projectID string) (*Project, error) {
+	var p Project
+	if err := callJSON(client, http.MethodGet, resourceManagerEndpoint+projectID, nil, &p); err != nil {
+		if se, ok := err.(*StatusError); ok && (se.Code == http.StatusForbidden || se.Code == http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting project %s: %v", projectID, err)
+	}
+	return &p, nil
+}
+
+// BillingEnabled returns whether billing is enabled for project projectID.
+func BillingEnabled(client *http.Client, projectID string) (bool, error) {
+	var resp struct {
+		BillingEnabled bool `json:"billingEnabled"`
+	}
+	if err := callJSON(client, http.MethodGet, billingEndpoint+projectID+"/billingInfo", nil, &resp); err != nil {
+		return false, fmt.Errorf("error getting billing info of project %s: %v", projectID, err)
+	}
+	return resp.BillingEnabled, nil
+}
+
+// MissingPermissions returns the permissions on project projectID the caller
+// does not have.
+func MissingPermissions(client *http.Client, projectID string, permissions []string) ([]string, error) {
+	var resp struct {
+		Permissions []string `json:"permissions"`
+	}
+	req := map[string][]string{"permissions": permissions}
+	if err := callJSON(client, http.MethodPost, resourceManagerEndpoint+projectID+":testIamPermissions", req, &resp); err != nil {
+		return nil, fmt.Errorf("error testing permissions on project %s: %v", projectID, err)
+	}
+
+	granted := make(map[string]bool, len(resp.Permissions))
+	for _, p := range resp.Permissions {
+		granted[p] = true
+	}
+	var missing []string
+	for _, p := range permissions {
+		if !granted[p] {
+			missing = append(missing, p)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeProject serves the project p, its billing info and the permissions
+// testing of the Resource Manager and Cloud Billing APIs. The requests fail
+// with status if it is set.
+type fakeProject struct {
+	project *Project
+	billing bool
+	granted []string
+	status  int
+}
+
+func (f *fakeProject) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.status != 0 {
+		http.Error(w, http.StatusText(f.status), f.status)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/projects/p" && f.project != nil:
+		json.NewEncoder(w).Encode(f.project)
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/projects/p/billingInfo":
+		json.NewEncoder(w).Encode(map[string]interface{}{"billingEnabled": f.billing})
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/projects/p:testIamPermissions":
+		var req struct {
+			Permissions []string `json:"permissions"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var granted []string
+		for _, p := range req.Permissions {
+			for _, g := range f.granted {
+				if p == g {
+					granted = append(granted, p)
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string][]string{"permissions": granted})
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+// TestGetProject tests that projects the caller can't see are reported as
+// missing, and other errors returned.
+func TestGetProject(t *testing.T) {
+	for _, tc := range []struct {
+		fake    *fakeProject
+		want    *Project
+		wantErr string
+	}{
+		{fake: &fakeProject{project: &Project{ProjectID: "p", ProjectNumber: "42", LifecycleState: "ACTIVE"}}, want: &Project{ProjectID: "p", ProjectNumber: "42", LifecycleState: "ACTIVE"}},
+		{fake: &fakeProject{}},
+		{fake: &fakeProject{status: http.StatusForbidden}},
+		{fake: &fakeProject{status: http.StatusInternalServerError}, wantErr: "error getting project p: request failed with status 500"},
+	} {
+		client, stop := testClient(t, tc.fake)
+		got, err := GetProject(client, "p")
+		stop()
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("Unexpected error getting the project: %v", err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("Expected an error containing %q, got %v", tc.wantErr, err)
+		case !reflect.DeepEqual(got, tc.want):
+			t.Fatalf("Expected the project %+v, got %+v", tc.want, got)
+		}
+	}
+}
+
+// TestBillingEnabled tests reading whether billing is enabled.
+func TestBillingEnabled(t *testing.T) {
+	for _, tc := range []struct {
+		fake    *fakeProject
+		want    bool
+		wantErr string
+	}{
+		{fake: &fakeProject{billing: true}, want: true},
+		{fake: &fakeProject{billing: false}, want: false},
+		{fake: &fakeProject{status: http.StatusForbidden}, wantErr: "error getting billing info of project p: request failed with status 403"},
+	} {
+		client, stop := testClient(t, tc.fake)
+		got, err := BillingEnabled(client, "p")
+		stop()
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("Unexpected error getting the billing info: %v", err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("Expected an error containing %q, got %v", tc.wantErr, err)
+		case got != tc.want:
+			t.Fatalf("Expected billing enabled %v, got %v", tc.want, got)
+		}
+	}
+}
+
+// TestMissingPermissions tests that the permissions not granted are
+// returned in the order they were asked for.
+func TestMissingPermissions(t *testing.T) {
+	permissions := []string{"iam.serviceAccounts.create", "serviceusage.services.enable", "serviceusage.services.list"}
+	for _, tc := range []struct {
+		fake    *fakeProject
+		want    []string
+		wantErr string
+	}{
+		{fake: &fakeProject{granted: permissions}},
+		{fake: &fakeProject{granted: []string{"serviceusage.services.list"}}, want: []string{"iam.serviceAccounts.create", "serviceusage.services.enable"}},
+		{fake: &fakeProject{}, want: permissions},
+		{fake: &fakeProject{status: http.StatusNotFound}, wantErr: "error testing permissions on project p: request failed with status 404"},
+	} {
+		client, stop := testClient(t, tc.fake)
+		got, err := MissingPermissions(client, "p", permissions)
+		stop()
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("Unexpected error testing the permissions: %v", err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("Expected an error containing %q, got %v", tc.wantErr, err)
+		case !reflect.DeepEqual(got, tc.want):
+			t.Fatalf("Expected the missing permissions %v, got %v", tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// StatusError is the error of a request that failed with a status other
+// than 200 OK.
+type StatusError struct {
+	Code   int
+	Status string
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request failed with status %s: %s", e.Status, e.Body)
+}
+
+// callJSON sends the JSON encoded req, if any, to url and decodes the JSON
+// response into resp. The response must have status 200 OK, otherwise the
+// error is a *StatusError.
+func callJSON(client *http.Client, method, url string, req, resp interface{}) error {
+	var body io.Reader
+	if req != nil {
+		b, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	r, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	if req != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := client.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return &StatusError{Code: res.StatusCode, Status: res.Status, Body: string(b)}
+	}
+
+	if err := json.Unmarshal(b, resp); err != nil {
+		return fmt.Errorf("error unmarshalling response: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package gcp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+const secretManagerEndpoint = "https://secretmanager.googleapis.com/v1/"
+
+var (
+	// secretRE matches the resource name of a Secret Manager secret.
+	secretRE = regexp.MustCompile(`^projects/[^/]+/secrets/[^/]+$`)
+	// secretVersionRE matches the resource name of a Secret Manager secret
+	// version.
+	secretVersionRE = regexp.MustCompile(`^projects/[^/]+/secrets/[^/]+/versions/[^/]+$`)
+)
+
+// SecretVersionName returns the resource name of the secret version name
+// refers to. name is either a secret version, or a secret in which case its
+// latest version is used.
+func SecretVersionName(name string) (string, error) {
+	switch {
+	case secretVersionRE.MatchString(name):
+		return name, nil
+	case secretRE.MatchString(name):
+		return name + "/versions/latest", nil
+	}
+	return "", fmt.Errorf("invalid Secret Manager secret %q: expected projects/<project>/secrets/<secret>[/versions/<version>]", name)
+}
+
+// AccessSecret returns the payload of the Secret Manager secret version name
+// refers to, see SecretVersionName.
+func AccessSecret(client *http.Client, name string) ([]byte, error) {
+	version, err := SecretVersionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := callJSON(client, http.MethodGet, secretManagerEndpoint+version+":access", nil, &resp); err != nil {
+		return nil, fmt.Errorf("error accessing secret %s: %v", version, err)
+	}
+	return base64.StdEncoding.DecodeString(resp.Payload.Data)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeSecretManager serves the access method of the secret versions in
+// payloads, failing the secrets in denied with 403 and the others with 404.
+func fakeSecretManager(payloads map[string]string, denied ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, "/v1/") || !strings.HasSuffix(r.URL.Path, ":access") {
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusBadRequest)
+			return
+		}
+		version := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/"), ":access")
+		for _, d := range denied {
+			if strings.HasPrefix(version, d+"/") {
+				http.Error(w, "Permission 'secretmanager.versions.access' denied", http.StatusForbidden)
+				return
+			}
+		}
+		payload, ok := payloads[version]
+		if !ok {
+			http.Error(w, "Secret Version ["+version+"] not found", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":    version,
+			"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte(payload))},
+		})
+	})
+}
+
+// TestSecretVersionName tests that secrets refer to their latest version.
+func TestSecretVersionName(t *testing.T) {
+	for _, tc := range []struct {
+		name, want string
+	}{
+		{"projects/p/secrets/s", "projects/p/secrets/s/versions/latest"},
+		{"projects/p/secrets/s/versions/3", "projects/p/secrets/s/versions/3"},
+		{"projects/p/secrets/s/versions/latest", "projects/p/secrets/s/versions/latest"},
+	} {
+		got, err := SecretVersionName(tc.name)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+
+	for _, bad := range []string{"", "s", "projects/p/secrets", "projects/p/secrets/s/versions", "projects/p/secrets/s/versions/3/x"} {
+		if _, err := SecretVersionName(bad); err == nil || !strings.Contains(err.Error(), "invalid Secret Manager secret") {
+			t.Fatalf("%q: expected an invalid secret error, got %v", bad, err)
+		}
+	}
+}
+
+// TestAccessSecret tests that the payload of the secret version is
+// returned, and the errors of missing versions and denied access.
+func TestAccessSecret(t *testing.T) {
+	client, stop := testClient(t, fakeSecretManager(map[string]string{
+		"projects/p/secrets/s/versions/latest": "latest payload",
+		"projects/p/secrets/s/versions/1":      "first payload",
+	}, "projects/p/secrets/denied"))
+	defer stop()
+
+	for _, tc := range []struct {
+		name, want, wantErr string
+	}{
+		{name: "projects/p/secrets/s", want: "latest payload"},
+		{name: "projects/p/secrets/s/versions/1", want: "first payload"},
+		{name: "projects/p/secrets/s/versions/2", wantErr: "error accessing secret projects/p/secrets/s/versions/2: request failed with status 404 Not Found"},
+		{name: "projects/p/secrets/denied", wantErr: "error accessing secret projects/p/secrets/denied/versions/latest: request failed with status 403 Forbidden"},
+		{name: "secrets/s", wantErr: "invalid Secret Manager secret"},
+	} {
+		got, err := AccessSecret(client, tc.name)
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Fatalf("%s: expected an error containing %q, got %v", tc.name, tc.wantErr, err)
+		case string(got) != tc.want:
+			t.Fatalf("%s: expected the payload %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	storageEndpoint       = "https://storage.googleapis.com/storage/v1/"
+	storageUploadEndpoint = "https://storage.googleapis.com/upload/storage/v1/"
+)
+
+// gcsURLRE matches gs://<bucket>[/<path>] URLs.
+var gcsURLRE = regexp.MustCompile(`^gs://([a-z0-9][-_.a-z0-9]{1,61}[a-z0-9])(/[^\s"]*)?$`)
+
+// ParseGCSURL returns the bucket and the object name prefix of the
+// gs://<bucket>[/<path>] URL u. The prefix is empty or ends with a slash.
+func ParseGCSURL(u string) (bucket, prefix string, err error) {
+	m := gcsURLRE.FindStringSubmatch(u)
+	if m == nil {
+		return "", "", fmt.Errorf("invalid GCS URL %q: expected gs://<bucket>[/<path>]", u)
+	}
+	prefix = strings.Trim(m[2], "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return m[1], prefix, nil
+}
+
+// Object is a GCS object.
+type Object struct {
+	Name     string            `json:"name"`
+	Size     int64             `json:"size,string"`
+	Created  time.Time         `json:"timeCreated"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// ListObjects returns the objects of bucket whose names start with prefix.
+func ListObjects(client *http.Client, bucket, prefix string) ([]Object, error) {
+	var objects []Object
+	pageToken := ""
+	for {
+		q := url.Values{"prefix": {prefix}}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		var resp struct {
+			Items         []Object `json:"items"`
+			NextPageToken string   `json:"nextPageToken"`
+		}
+		u := storageEndpoint + "b/" + url.PathEscape(bucket) + "/o?" + q.Encode()
+		if err := callJSON(client, http.MethodGet, u, nil, &resp); err != nil {
+			return nil, fmt.Errorf("error listing gs://%s/%s: %v", bucket, prefix, err)
+		}
+		objects = append(objects, resp.Items...)
+		if resp.NextPageToken == "" {
+			return objects, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// uploadChunkSize is the size of the chunks UploadObject sends, a multiple
+// of the 256 KiB GCS requires.
+var uploadChunkSize = 8 << 20
+
+// uploadRetries is how many times a chunk is resent after a transient
+// error, waiting uploadRetryDelay, doubled every time, in between.
+var (
+	uploadRetries    = 5
+	uploadRetryDelay = time.Second
+)
+
+// UploadObject uploads the content of r to the object name of bucket with
+// metadata, in a resumable upload: the content is sent in chunks and a chunk
+// failing with a transient error is resent from the bytes GCS persisted, so
+// large snapshots survive a dropped connection.
+func UploadObject(client *http.Client, bucket, name string, metadata map[string]string, r io.Reader) (*Object, error) {
+	session, err := startUpload(client, bucket, name, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading gs://%s/%s: %v", bucket, name, err)
+	}
+	buf := make([]byte, uploadChunkSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(r, buf)
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			return nil, fmt.Errorf("error reading the content of gs://%s/%s: %v", bucket, name, err)
+		}
+		o, err := putChunk(client, session, buf[:n], offset, last)
+		if err != nil {
+			return nil, fmt.Errorf("error uploading gs://%s/%s: %v", bucket, name, err)
+		}
+		if last {
+			return o, nil
+		}
+		offset += int64(n)
+	}
+}
+
+// startUpload starts a resumable upload of the object name of bucket with
+// metadata and returns its session URL.
+func startUpload(client *http.Client, bucket, name string, metadata map[string]string) (string, error) {
+	meta, err := json.Marshal(map[string]interface{}{"name": name, "metadata": metadata})
+	if err != nil {
+		return "", err
+	}
+	u := storageUploadEndpoint + "b/" + url.PathEscape(bucket) + "/o?uploadType=resumable"
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(meta))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(res.Body)
+		return "", &StatusError{Code: res.StatusCode, Status: res.Status, Body: string(b)}
+	}
+	session := res.Header.Get("Location")
+	if session == "" {
+		return "", fmt.Errorf("no upload session URL in response")
+	}
+	return session, nil
+}
+
+// putChunk sends chunk, starting at offset of the content, to the upload
+// session. After a transient error, it asks the session how much it
+// persisted and resends the rest. It returns the object once the last chunk
+// is uploaded.
+func putChunk(client *http.Client, session string, chunk []byte, offset int64, last bool) (*Object, error) {
+	total := "*"
+	if last {
+		total = strconv.FormatInt(offset+int64(len(chunk)), 10)
+	}
+	end := offset + int64(len(chunk))
+	sent := offset
+	delay := uploadRetryDelay
+	for retries := 0; ; {
+		rest := chunk[sent-offset:]
+		contentRange := "bytes */" + total
+		if len(rest) > 0 {
+			contentRange = fmt.Sprintf("bytes %d-%d/%s", sent, end-1, total)
+		}
+		o, persisted, err := putUpload(client, session, rest, contentRange)
+		if err == nil {
+			if o != nil {
+				return o, nil
+			}
+			if persisted == end && !last {
+				return nil, nil
+			}
+			if persisted > sent && persisted <= end {
+				// GCS persisted part of the chunk, send the rest.
+				sent = persisted
+				continue
+			}
+			err = fmt.Errorf("upload session persisted %d bytes, sent up to %d", persisted, end)
+		} else if !isTransient(err) {
+			return nil, err
+		}
+		if retries == uploadRetries {
+			return nil, err
+		}
+		retries++
+		time.Sleep(delay)
+		delay *= 2
+
+		// Ask the session where to resume, the request may have been
+		// persisted in part.
+		o, persisted, err = putUpload(client, session, nil, "bytes */"+total)
+		switch {
+		case err != nil && !isTransient(err):
+			return nil, err
+		case err != nil:
+			continue
+		case o != nil:
+			return o, nil
+		case persisted < offset || persisted > end:
+			return nil, fmt.Errorf("upload session persisted %d bytes, expected %d to %d", persisted, offset, end)
+		}
+		sent = persisted
+	}
+}
+
+// putUpload sends body with contentRange to the upload session. It returns
+// the object if the upload is complete, else the number of bytes of the
+// content the session persisted.
+func putUpload(client *http.Client, session string, body []byte, contentRange string) (*Object, int64, error) {
+	req, err := http.NewRequest(http.MethodPut, session, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Range", contentRange)
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		var o Object
+		if err := json.Unmarshal(b, &o); err != nil {
+			return nil, 0, fmt.Errorf("error unmarshalling response: %v", err)
+		}
+		return &o, 0, nil
+	case http.StatusPermanentRedirect:
+		// Range is bytes=0-<last persisted byte>, absent if none is.
+		r := res.Header.Get("Range")
+		if r == "" {
+			return nil, 0, nil
+		}
+		i := strings.LastIndex(r, "-")
+		last, err := strconv.ParseInt(r[i+1:], 10, 64)
+		if i < 0 || err != nil {
+			return nil, 0, fmt.Errorf("invalid Range %q in response", r)
+		}
+		return nil, last + 1, nil
+	}
+	return nil, 0, &StatusError{Code: res.StatusCode, Status: res.Status, Body: string(b)}
+}
+
+// isTransient returns whether err, returned by a request, may succeed when
+// retried: connection errors, and server errors and throttling.
+func isTransient(err error) bool {
+	if se, ok := err.(*StatusError); ok {
+		return se.Code >= 500 || se.Code == http.StatusTooManyRequests
+	}
+	_, ok := err.(*url.Error)
+	return ok
+}
+
+// DeleteObject deletes the object name of bucket, if it exists.
+func DeleteObject(client *http.Client, bucket, name string) error {
+	u := storageEndpoint + "b/" + url.PathEscape(bucket) + "/o/" + url.PathEscape(name)
+	req, err := http.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error deleting gs://%s/%s: %v", bucket, name, err)
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusNoContent, http.StatusOK, http.StatusNotFound:
+	default:
+		b, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("error deleting gs://%s/%s: %v", bucket, name, &StatusError{Code: res.StatusCode, Status: res.Status, Body: string(b)})
+	}
+	return nil
+}
+
+// GetObject returns the object name of bucket, without its content.
+func GetObject(client *http.Client, bucket, name string) (*Object, error) {
+	var o Object
+	u := storageEndpoint + "b/" + url.PathEscape(bucket) + "/o/" + url.PathEscape(name)
+	if err := callJSON(client, http.MethodGet, u, nil, &o); err != nil {
+		return nil, fmt.Errorf("error getting gs://%s/%s: %v", bucket, name, err)
+	}
+	return &o, nil
+}
+
+// DownloadObject returns the content of the object name of bucket, which
+// the caller must close.
+func DownloadObject(client *http.Client, bucket, name string) (io.ReadCloser, error) {
+	u := storageEndpoint + "b/" + url.PathEscape(bucket) + "/o/" + url.PathEscape(name) + "?alt=media"
+	res, err := client.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading gs://%s/%s: %v", bucket, name, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		b, _ := ioutil.ReadAll(res.Body)
+		return nil, fmt.Errorf("error downloading gs://%s/%s: %v", bucket, name, &StatusError{Code: res.StatusCode, Status: res.Status, Body: string(b)})
+	}
+	return res.Body, nil
+}
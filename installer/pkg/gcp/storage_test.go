@@ -0,0 +1,240 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testClient returns a client sending the requests of the GCP endpoints to
+// handler instead, and the function stopping the server.
+func testClient(t *testing.T, handler http.Handler) (*http.Client, func()) {
+	s := httptest.NewServer(handler)
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing server URL: %v", err)
+	}
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		redirected := *r
+		ru := *r.URL
+		ru.Scheme, ru.Host = u.Scheme, u.Host
+		redirected.URL = &ru
+		return http.DefaultTransport.RoundTrip(&redirected)
+	})}, s.Close
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// fakeUpload serves a GCS resumable upload session. failAt is the PUT with
+// data that only persists its first two bytes and fails with 503.
+type fakeUpload struct {
+	mu       sync.Mutex
+	name     string
+	metadata map[string]string
+	data     []byte
+	puts     int
+	failAt   int
+	status   int
+}
+
+func (f *fakeUpload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	if r.Method == http.MethodPost {
+		if r.URL.Path != "/upload/storage/v1/b/bucket/o" || r.URL.Query().Get("uploadType") != "resumable" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		var meta struct {
+			Name     string            `json:"name"`
+			Metadata map[string]string `json:"metadata"`
+		}
+		json.Unmarshal(body, &meta)
+		f.name, f.metadata = meta.Name, meta.Metadata
+		w.Header().Set("Location", "https://storage.googleapis.com/upload/session/1")
+		return
+	}
+
+	// Content-Range is bytes <first>-<last>/<total> or bytes */<total>.
+	var rng, total string
+	fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %s", &rng)
+	parts := strings.SplitN(rng, "/", 2)
+	rng, total = parts[0], parts[1]
+	if rng != "*" {
+		f.puts++
+		var first int
+		fmt.Sscanf(rng, "%d-", &first)
+		if first != len(f.data) {
+			http.Error(w, fmt.Sprintf("got offset %d, persisted %d", first, len(f.data)), http.StatusBadRequest)
+			return
+		}
+		if f.status != 0 {
+			http.Error(w, "denied", f.status)
+			return
+		}
+		if f.puts == f.failAt {
+			f.data = append(f.data, body[:2]...)
+			http.Error(w, "backend error", http.StatusServiceUnavailable)
+			return
+		}
+		f.data = append(f.data, body...)
+	}
+	if total != "*" && total == strconv.Itoa(len(f.data)) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": f.name, "size": total, "metadata": f.metadata})
+		return
+	}
+	if len(f.data) > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(f.data)-1))
+	}
+	w.WriteHeader(http.StatusPermanentRedirect)
+}
+
+// setUploadParameters sets small chunks and no retry delay, and returns the
+// function restoring them.
+func setUploadParameters() func() {
+	chunk, retries, delay := uploadChunkSize, uploadRetries, uploadRetryDelay
+	uploadChunkSize, uploadRetries, uploadRetryDelay = 4, 2, 0
+	return func() { uploadChunkSize, uploadRetries, uploadRetryDelay = chunk, retries, delay }
+}
+
+// TestUploadObject tests that the content is uploaded in chunks, the last
+// one empty when the content ends on a chunk boundary, and that a chunk
+// failing in part is resumed from the bytes the session persisted.
+func TestUploadObject(t *testing.T) {
+	defer setUploadParameters()()
+	for _, content := range []string{"hello world!", "hello world", ""} {
+		f := &fakeUpload{failAt: 2}
+		client, stop := testClient(t, f)
+		o, err := UploadObject(client, "bucket", "sc/backup.db", map[string]string{"k": "v"}, strings.NewReader(content))
+		stop()
+		if err != nil {
+			t.Fatalf("Unexpected error uploading %q: %v", content, err)
+		}
+		if string(f.data) != content {
+			t.Fatalf("Uploaded content does not match: got %q; want %q", f.data, content)
+		}
+		want := &Object{Name: "sc/backup.db", Size: int64(len(content)), Metadata: map[string]string{"k": "v"}}
+		if !reflect.DeepEqual(o, want) {
+			t.Fatalf("Object does not match: got %+v; want %+v", o, want)
+		}
+	}
+}
+
+// TestUploadObjectErrors tests that permanent errors fail the upload at
+// once, and that transient ones fail it after the retries.
+func TestUploadObjectErrors(t *testing.T) {
+	defer setUploadParameters()()
+
+	f := &fakeUpload{status: http.StatusForbidden}
+	client, stop := testClient(t, f)
+	defer stop()
+	if _, err := UploadObject(client, "bucket", "backup.db", nil, strings.NewReader("hello")); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Expected a 403 error, got %v", err)
+	}
+	if f.puts != 1 {
+		t.Fatalf("Permanent error was retried: %d requests", f.puts)
+	}
+
+	f = &fakeUpload{status: http.StatusServiceUnavailable}
+	client, stop = testClient(t, f)
+	defer stop()
+	if _, err := UploadObject(client, "bucket", "backup.db", nil, strings.NewReader("hello")); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("Expected a 503 error, got %v", err)
+	}
+	if f.puts != 1+uploadRetries {
+		t.Fatalf("Requests do not match: got %d; want %d", f.puts, 1+uploadRetries)
+	}
+}
+
+// TestListObjects tests that all the pages of the listing are read.
+func TestListObjects(t *testing.T) {
+	client, stop := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/v1/b/bucket/o" || r.URL.Query().Get("prefix") != "sc/" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			fmt.Fprint(w, `{"items":[{"name":"sc/a.db","size":"3"}],"nextPageToken":"2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"name":"sc/b.db","size":"5"}]}`)
+	}))
+	defer stop()
+	objects, err := ListObjects(client, "bucket", "sc/")
+	if err != nil {
+		t.Fatalf("Unexpected error listing objects: %v", err)
+	}
+	want := []Object{{Name: "sc/a.db", Size: 3}, {Name: "sc/b.db", Size: 5}}
+	if !reflect.DeepEqual(objects, want) {
+		t.Fatalf("Objects do not match: got %+v; want %+v", objects, want)
+	}
+}
+
+// TestDeleteObject tests that deleting a missing object succeeds and that
+// other errors are returned.
+func TestDeleteObject(t *testing.T) {
+	client, stop := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/storage/v1/b/bucket/o/sc/gone.db":
+			http.NotFound(w, r)
+		case "/storage/v1/b/bucket/o/sc/locked.db":
+			http.Error(w, "retention policy", http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer stop()
+	for name, fails := range map[string]bool{"sc/a.db": false, "sc/gone.db": false, "sc/locked.db": true} {
+		if err := DeleteObject(client, "bucket", name); (err != nil) != fails {
+			t.Fatalf("Error deleting %s does not match: got %v; want error %v", name, err, fails)
+		}
+	}
+}
+
+// TestParseGCSURL tests the bucket and prefix of gs:// URLs.
+func TestParseGCSURL(t *testing.T) {
+	for _, tc := range []struct {
+		url, bucket, prefix string
+	}{
+		{"gs://my-bucket", "my-bucket", ""},
+		{"gs://my-bucket/", "my-bucket", ""},
+		{"gs://my-bucket/sc/backups/", "my-bucket", "sc/backups/"},
+	} {
+		bucket, prefix, err := ParseGCSURL(tc.url)
+		if err != nil || bucket != tc.bucket || prefix != tc.prefix {
+			t.Fatalf("%s: got %q, %q, %v; want %q, %q", tc.url, bucket, prefix, err, tc.bucket, tc.prefix)
+		}
+	}
+	if _, _, err := ParseGCSURL("https://storage.googleapis.com/my-bucket"); err == nil {
+		t.Fatalf("Expected an error for a non gs:// URL")
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package manifest parses rendered Kubernetes manifests and orders the
+// objects they contain for creation and deletion.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Object is a single Kubernetes object read from a rendered manifest.
+type Object struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+
+	// File is the manifest file the object was read from.
+	File string
+
+	// Line is the first line of the document containing the object in
+	// File, not counting leading comments.
+	Line int
+
+	// JSON is the full object encoded as JSON, which kubectl accepts as
+	// input in place of YAML.
+	JSON []byte
+}
+
+// String returns a kubectl-style reference to the object, e.g.
+// "Deployment/apiserver".
+func (o *Object) String() string {
+	return fmt.Sprintf("%s/%s", o.Kind, o.Name)
+}
+
+// Location returns the file and line the object was read from, e.g.
+// "apiserver-deployment.yaml:16".
+func (o *Object) Location() string {
+	return fmt.Sprintf("%s:%d", o.File, o.Line)
+}
+
+// clusterScopedKinds are the built-in and Service Catalog kinds of objects
+// that don't belong to a namespace.
+var clusterScopedKinds = map[string]bool{
+	"APIService":                     true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"ClusterServiceBroker":           true,
+	"ClusterServiceClass":            true,
+	"ClusterServicePlan":             true,
+	"CustomResourceDefinition":       true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"PersistentVolume":               true,
+	"PodSecurityPolicy":              true,
+	"PriorityClass":                  true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+}
+
+// clusterScopedGroups are API groups whose kinds are all cluster-scoped,
+// e.g. Gatekeeper constraint templates and the constraints they define.
+var clusterScopedGroups = map[string]bool{
+	"constraints.gatekeeper.sh": true,
+	"templates.gatekeeper.sh":   true,
+}
+
+// ClusterScoped returns true if the object doesn't belong to a namespace.
+func (o *Object) ClusterScoped() bool {
+	group := ""
+	if i := strings.Index(o.APIVersion, "/"); i >= 0 {
+		group = o.APIVersion[:i]
+	}
+	return clusterScopedKinds[o.Kind] || clusterScopedGroups[group]
+}
+
+// kindPriority determines the order in which objects are created. Objects
+// are deleted in the reverse order. Kinds that are not listed here, which
+// are usually custom resources, are created after everything else.
+var kindPriority = map[string]int{
+	"Namespace": 0,
+
+	"CustomResourceDefinition": 1,
+
+	"ServiceAccount":     2,
+	"ClusterRole":        2,
+	"ClusterRoleBinding": 2,
+	"Role":               2,
+	"RoleBinding":        2,
+	"PriorityClass":      2,
+
+	"Secret":        3,
+	"ConfigMap":     3,
+	"NetworkPolicy": 3,
+
+	"Service": 4,
+
+	// PodDisruptionBudgets protect the pods from their creation on, and
+	// until they are deleted.
+	"PodDisruptionBudget": 4,
+
+	"Deployment":  5,
+	"StatefulSet": 5,
+	"DaemonSet":   5,
+	"ReplicaSet":  5,
+	"Job":         5,
+	"CronJob":     5,
+	"Pod":         5,
+
+	"HorizontalPodAutoscaler": 5,
+
+	"APIService": 6,
+}
+
+const unknownKindPriority = 7
+
+func priority(kind string) int {
+	if p, ok := kindPriority[kind]; ok {
+		return p
+	}
+	return unknownKindPriority
+}
+
+// Priority returns the creation priority of the objects of kind, lower
+// first, e.g. their sync wave in a GitOps tool.
+func Priority(kind string) int {
+	return priority(kind)
+}
+
+// header holds the fields of an object that are needed to identify and
+// order it.
+type header struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Items []json.RawMessage `json:"items"`
+}
+
+// Parse parses the YAML manifest b and returns the objects it contains.
+// The manifest may contain multiple documents separated by "---" lines and
+// objects of kind List are expanded into their items. file is recorded on
+// every returned object for error reporting.
+func Parse(file string, b []byte) ([]*Object, error) {
+	var objs []*Object
+	line := 1
+	for i, doc := range SplitDocuments(b) {
+		start := line + leadingCommentLines(doc)
+		// Skip the document and its trailing separator line.
+		line += bytes.Count(doc, []byte("\n")) + 1
+
+		j, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing document %d of %s: %v", i+1, file, err)
+		}
+		if string(j) == "null" {
+			// The document is empty or contains only comments.
+			continue
+		}
+		o, err := parseJSON(file, j)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range o {
+			obj.Line = start
+		}
+		objs = append(objs, o...)
+	}
+	return objs, nil
+}
+
+// SplitDocuments splits a multi-document YAML stream into its documents.
+// Documents are separated by lines starting with "---".
+func SplitDocuments(b []byte) [][]byte {
+	var docs [][]byte
+	var cur []byte
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		if isDocumentSeparator(line) {
+			docs = append(docs, cur)
+			cur = nil
+			continue
+		}
+		cur = append(cur, line...)
+	}
+	return append(docs, cur)
+}
+
+// leadingCommentLines returns the number of blank and comment lines at the
+// start of doc.
+func leadingCommentLines(doc []byte) int {
+	n := 0
+	for _, line := range bytes.SplitAfter(doc, []byte("\n")) {
+		l := bytes.TrimSpace(line)
+		if len(l) > 0 && l[0] != '#' {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+func isDocumentSeparator(line []byte) bool {
+	if !bytes.HasPrefix(line, []byte("---")) {
+		return false
+	}
+	rest := bytes.TrimSpace(line[3:])
+	return len(rest) == 0 || rest[0] == '#'
+}
+
+func parseJSON(file string, j []byte) ([]*Object, error) {
+	var h header
+	if err := json.Unmarshal(j, &h); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", file, err)
+	}
+
+	if h.Kind == "List" {
+		var objs []*Object
+		for _, item := range h.Items {
+			o, err := parseJSON(file, item)
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, o...)
+		}
+		return objs, nil
+	}
+
+	if h.APIVersion == "" || h.Kind == "" {
+		return nil, fmt.Errorf("error parsing %s: object is missing apiVersion or kind", file)
+	}
+
+	return []*Object{{
+		APIVersion: h.APIVersion,
+		Kind:       h.Kind,
+		Name:       h.Metadata.Name,
+		Namespace:  h.Metadata.Namespace,
+		File:       file,
+		JSON:       j,
+	}}, nil
+}
+
+// ParseDir parses every *.yaml file in dir and returns all the objects
+// found, in creation order.
+func ParseDir(dir string) ([]*Object, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var objs []*Object
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		o, err := Parse(filepath.Base(f), b)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, o...)
+	}
+
+	Sort(objs)
+	return objs, nil
+}
+
+// Sort sorts objects in the order they should be created. Objects of the
+// same priority keep their relative order.
+func Sort(objs []*Object) {
+	sort.SliceStable(objs, func(i, j int) bool {
+		return priority(objs[i].Kind) < priority(objs[j].Kind)
+	})
+}
+
+// DeleteOrder returns a copy of objects, which must be in creation order,
+// reversed so that dependents are deleted before what they depend on.
+func DeleteOrder(objs []*Object) []*Object {
+	r := make([]*Object, len(objs))
+	for i, o := range objs {
+		r[len(objs)-1-i] = o
+	}
+	return r
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func names(objs []*Object) []string {
+	var r []string
+	for _, o := range objs {
+		r = append(r, o.String())
+	}
+	return r
+}
+
+// TestParseList tests that objects of kind List are expanded into their items.
+func TestParseList(t *testing.T) {
+	b := []byte(`
+# A comment that should be ignored.
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ServiceAccount
+  metadata:
+    name: apiserver
+    namespace: service-catalog
+- apiVersion: v1
+  kind: ServiceAccount
+  metadata:
+    name: controller-manager
+    namespace: service-catalog
+`)
+	objs, err := Parse("service-accounts.yaml", b)
+	if err != nil {
+		t.Fatalf("Unexpected error from Parse: %v", err)
+	}
+
+	want := []string{"ServiceAccount/apiserver", "ServiceAccount/controller-manager"}
+	if got := names(objs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Objects do not match: got %v; want %v", got, want)
+	}
+	for _, o := range objs {
+		if o.Namespace != "service-catalog" || o.File != "service-accounts.yaml" {
+			t.Errorf("Unexpected namespace or file for %v: %q, %q", o, o.Namespace, o.File)
+		}
+	}
+}
+
+// TestParseMultipleDocuments tests that every document of a multi-document
+// manifest is parsed and that empty documents are skipped.
+func TestParseMultipleDocuments(t *testing.T) {
+	b := []byte(`---
+# The service account.
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: etcd-operator
+--- # an empty document
+---
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: etcd-operator
+spec:
+  template:
+    spec:
+      containers:
+      - args: ["---not-a-separator"]
+`)
+	objs, err := Parse("etcd-operator.yaml", b)
+	if err != nil {
+		t.Fatalf("Unexpected error from Parse: %v", err)
+	}
+
+	want := []string{"ServiceAccount/etcd-operator", "Deployment/etcd-operator"}
+	if got := names(objs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Objects do not match: got %v; want %v", got, want)
+	}
+}
+
+// TestParseEmpty tests that a manifest with only comments has no objects.
+func TestParseEmpty(t *testing.T) {
+	objs, err := Parse("empty.yaml", []byte("# nothing to see here\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error from Parse: %v", err)
+	}
+	if len(objs) != 0 {
+		t.Fatalf("Expected no objects, got %v", names(objs))
+	}
+}
+
+// TestParseMissingKind tests that objects without a kind are rejected.
+func TestParseMissingKind(t *testing.T) {
+	_, err := Parse("bad.yaml", []byte("apiVersion: v1\nmetadata:\n  name: x\n"))
+	if err == nil {
+		t.Fatal("Expected an error for an object without kind")
+	}
+}
+
+// TestSortAndDeleteOrder tests that objects are ordered by kind priority,
+// that objects of the same kind keep their relative order and that the
+// delete order is the reverse of the creation order.
+func TestSortAndDeleteOrder(t *testing.T) {
+	objs := []*Object{
+		{Kind: "EtcdCluster", Name: "etcd-cluster"},
+		{Kind: "APIService", Name: "v1beta1.servicecatalog.k8s.io"},
+		{Kind: "Deployment", Name: "apiserver"},
+		{Kind: "Deployment", Name: "controller-manager"},
+		{Kind: "Service", Name: "service-catalog-api"},
+		{Kind: "PodDisruptionBudget", Name: "apiserver"},
+		{Kind: "Secret", Name: "apiserver-cert"},
+		{Kind: "ClusterRole", Name: "etcd-operator"},
+		{Kind: "CustomResourceDefinition", Name: "etcdclusters.etcd.database.coreos.com"},
+		{Kind: "Namespace", Name: "service-catalog"},
+	}
+	Sort(objs)
+
+	want := []string{
+		"Namespace/service-catalog",
+		"CustomResourceDefinition/etcdclusters.etcd.database.coreos.com",
+		"ClusterRole/etcd-operator",
+		"Secret/apiserver-cert",
+		"Service/service-catalog-api",
+		"PodDisruptionBudget/apiserver",
+		"Deployment/apiserver",
+		"Deployment/controller-manager",
+		"APIService/v1beta1.servicecatalog.k8s.io",
+		"EtcdCluster/etcd-cluster",
+	}
+	if got := names(objs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Creation order does not match: got %v; want %v", got, want)
+	}
+
+	var reversed []string
+	for i := len(want) - 1; i >= 0; i-- {
+		reversed = append(reversed, want[i])
+	}
+	if got := names(DeleteOrder(objs)); !reflect.DeepEqual(got, reversed) {
+		t.Fatalf("Delete order does not match: got %v; want %v", got, reversed)
+	}
+}
+
+// TestClusterScoped tests that built-in cluster-scoped kinds and Gatekeeper
+// kinds are cluster-scoped.
+func TestClusterScoped(t *testing.T) {
+	for _, tc := range []struct {
+		apiVersion, kind string
+		want             bool
+	}{
+		{"apiregistration.k8s.io/v1beta1", "APIService", true},
+		{"rbac.authorization.k8s.io/v1", "ClusterRole", true},
+		{"constraints.gatekeeper.sh/v1beta1", "K8sMaxServiceInstances", true},
+		{"rbac.authorization.k8s.io/v1", "RoleBinding", false},
+		{"v1", "Service", false},
+	} {
+		o := &Object{APIVersion: tc.apiVersion, Kind: tc.kind}
+		if got := o.ClusterScoped(); got != tc.want {
+			t.Fatalf("ClusterScoped of %s %s does not match: got %v; want %v", tc.apiVersion, tc.kind, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// quantityDefinition is the definition of resource quantities, which are
+// strings that may also be written as numbers.
+const quantityDefinition = "io.k8s.apimachinery.pkg.api.resource.Quantity"
+
+// Schema is the OpenAPI v2 schema of a Kubernetes cluster, as served at
+// /openapi/v2, used to structurally validate objects before applying
+// them.
+type Schema struct {
+	definitions map[string]*schemaDefinition
+	// byKind maps "group/version/kind" to definition names.
+	byKind map[string]string
+}
+
+type schemaDefinition struct {
+	Ref                  string                       `json:"$ref"`
+	Type                 string                       `json:"type"`
+	Format               string                       `json:"format"`
+	Required             []string                     `json:"required"`
+	Properties           map[string]*schemaDefinition `json:"properties"`
+	Items                *schemaDefinition            `json:"items"`
+	AdditionalProperties json.RawMessage              `json:"additionalProperties"`
+
+	GroupVersionKind []struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	} `json:"x-kubernetes-group-version-kind"`
+}
+
+// LoadSchema parses an OpenAPI v2 schema.
+func LoadSchema(b []byte) (*Schema, error) {
+	var doc struct {
+		Definitions map[string]*schemaDefinition `json:"definitions"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing OpenAPI schema: %v", err)
+	}
+
+	s := &Schema{definitions: doc.Definitions, byKind: make(map[string]string)}
+	for name, d := range doc.Definitions {
+		for _, gvk := range d.GroupVersionKind {
+			s.byKind[gvkKey(gvk.Group, gvk.Version, gvk.Kind)] = name
+		}
+	}
+	return s, nil
+}
+
+func gvkKey(group, version, kind string) string {
+	return group + "/" + version + "/" + kind
+}
+
+// Validate validates o against the schema. It returns all the violations
+// found, or nothing if the schema does not know the kind of o, e.g. for
+// custom resources.
+func (s *Schema) Validate(o *Object) []error {
+	group, version := "", o.APIVersion
+	if i := strings.Index(o.APIVersion, "/"); i >= 0 {
+		group, version = o.APIVersion[:i], o.APIVersion[i+1:]
+	}
+	name, ok := s.byKind[gvkKey(group, version, o.Kind)]
+	if !ok {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(o.JSON, &v); err != nil {
+		return []error{fmt.Errorf("%s: %s: %v", o.Location(), o, err)}
+	}
+
+	var violations []string
+	s.validate(&violations, o.Kind, v, &schemaDefinition{Ref: "#/definitions/" + name})
+
+	var errs []error
+	for _, msg := range violations {
+		errs = append(errs, fmt.Errorf("%s: %s: %s", o.Location(), o, msg))
+	}
+	return errs
+}
+
+func (s *Schema) validate(violations *[]string, path string, v interface{}, d *schemaDefinition) {
+	ref := ""
+	for d.Ref != "" {
+		ref = strings.TrimPrefix(d.Ref, "#/definitions/")
+		next, ok := s.definitions[ref]
+		if !ok {
+			// Be lenient with schemas referencing missing definitions.
+			return
+		}
+		d = next
+	}
+	addf := func(format string, args ...interface{}) {
+		*violations = append(*violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if v == nil {
+		// Explicit nulls are treated as unset fields.
+		return
+	}
+
+	switch d.Type {
+	case "object", "":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			if d.Type == "object" {
+				addf("expected an object, got %s", jsonType(v))
+			}
+			return
+		}
+		for _, r := range d.Required {
+			if _, found := m[r]; !found {
+				addf("missing required field %q", r)
+			}
+		}
+		var additional *schemaDefinition
+		if len(d.AdditionalProperties) > 0 {
+			additional = &schemaDefinition{}
+			if err := json.Unmarshal(d.AdditionalProperties, additional); err != nil {
+				// additionalProperties: true allows any value.
+				additional = &schemaDefinition{}
+			}
+		}
+		for _, k := range sortedKeys(m) {
+			p, ok := d.Properties[k]
+			if !ok {
+				p = additional
+			}
+			if p == nil {
+				if d.Properties != nil {
+					addf("unknown field %q", k)
+				}
+				continue
+			}
+			s.validate(violations, path+"."+k, m[k], p)
+		}
+	case "array":
+		a, ok := v.([]interface{})
+		if !ok {
+			addf("expected an array, got %s", jsonType(v))
+			return
+		}
+		if d.Items == nil {
+			return
+		}
+		for i, item := range a {
+			s.validate(violations, fmt.Sprintf("%s[%d]", path, i), item, d.Items)
+		}
+	case "string":
+		if _, ok := v.(string); ok {
+			return
+		}
+		if _, ok := v.(float64); ok && (d.Format == "int-or-string" || ref == quantityDefinition) {
+			return
+		}
+		addf("expected a string, got %s", jsonType(v))
+	case "integer":
+		if f, ok := v.(float64); !ok || f != float64(int64(f)) {
+			addf("expected an integer, got %s", jsonType(v))
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			addf("expected a number, got %s", jsonType(v))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			addf("expected a boolean, got %s", jsonType(v))
+		}
+	}
+}
+
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case float64:
+		return fmt.Sprintf("number %v", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
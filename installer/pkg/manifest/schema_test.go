@@ -0,0 +1,134 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testSchema = `{"definitions": {
+	"io.k8s.api.core.v1.Service": {
+		"x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "Service"}],
+		"properties": {
+			"apiVersion": {"type": "string"},
+			"kind": {"type": "string"},
+			"metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+			"spec": {"$ref": "#/definitions/io.k8s.api.core.v1.ServiceSpec"}
+		}
+	},
+	"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+		"properties": {
+			"name": {"type": "string"},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+		}
+	},
+	"io.k8s.api.core.v1.ServiceSpec": {
+		"properties": {
+			"ports": {"type": "array", "items": {"$ref": "#/definitions/io.k8s.api.core.v1.ServicePort"}},
+			"selector": {"type": "object", "additionalProperties": {"type": "string"}}
+		}
+	},
+	"io.k8s.api.core.v1.ServicePort": {
+		"required": ["port"],
+		"properties": {
+			"port": {"type": "integer", "format": "int32"},
+			"targetPort": {"type": "string", "format": "int-or-string"}
+		}
+	}
+}}`
+
+func validate(t *testing.T, manifest string) []string {
+	s, err := LoadSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Unexpected error from LoadSchema: %v", err)
+	}
+	objs, err := Parse("service.yaml", []byte(manifest))
+	if err != nil {
+		t.Fatalf("Unexpected error from Parse: %v", err)
+	}
+	var got []string
+	for _, o := range objs {
+		for _, err := range s.Validate(o) {
+			got = append(got, err.Error())
+		}
+	}
+	return got
+}
+
+// TestValidateValid tests that a valid object has no violations, including
+// integers given for int-or-string fields.
+func TestValidateValid(t *testing.T) {
+	got := validate(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: service-catalog-api
+  labels:
+    app: service-catalog-apiserver
+spec:
+  ports:
+  - port: 443
+    targetPort: 8443
+`)
+	if len(got) != 0 {
+		t.Fatalf("Unexpected violations: %v", got)
+	}
+}
+
+// TestValidateViolations tests that unknown fields, type mismatches and
+// missing required fields are all reported with their location.
+func TestValidateViolations(t *testing.T) {
+	got := validate(t, `# The service.
+apiVersion: v1
+kind: Service
+metadata:
+  name: service-catalog-api
+  labels:
+    app: 1
+spec:
+  prots: []
+  ports:
+  - targetPort: secure
+  - port: "443"
+`)
+	want := []string{
+		`service.yaml:2: Service/service-catalog-api: Service.metadata.labels.app: expected a string, got number 1`,
+		`service.yaml:2: Service/service-catalog-api: Service.spec.ports[0]: missing required field "port"`,
+		`service.yaml:2: Service/service-catalog-api: Service.spec.ports[1].port: expected an integer, got string "443"`,
+		`service.yaml:2: Service/service-catalog-api: Service.spec: unknown field "prots"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Violations do not match: got %q; want %q", got, want)
+	}
+}
+
+// TestValidateUnknownKind tests that objects of kinds missing from the
+// schema, e.g. custom resources, are not validated.
+func TestValidateUnknownKind(t *testing.T) {
+	got := validate(t, `
+apiVersion: etcd.database.coreos.com/v1beta2
+kind: EtcdCluster
+metadata:
+  name: etcd-cluster
+spec:
+  size: three
+`)
+	if len(got) != 0 {
+		t.Fatalf("Unexpected violations: %v", got)
+	}
+}
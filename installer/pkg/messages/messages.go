@@ -0,0 +1,349 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package messages is the catalog of the user-facing messages of sc. Every
+// message has a stable code, e.g. SC-1001, that documentation and support
+// can refer to, and its text can be overridden, e.g. to translate or rebrand
+// it, with a file of codes to texts.
+package messages
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/color"
+	"github.com/ghodss/yaml"
+)
+
+// Code identifies a message.
+type Code string
+
+// Results of commands, printed without their code.
+const (
+	Installed             Code = "SC-0001"
+	Uninstalled           Code = "SC-0002"
+	Verified              Code = "SC-0003"
+	Adopted               Code = "SC-0004"
+	Paused                Code = "SC-0005"
+	Resumed               Code = "SC-0006"
+	Updated               Code = "SC-0007"
+	AuthManagerUpdated    Code = "SC-0008"
+	BrokerAdded           Code = "SC-0009"
+	BrokerRemoved         Code = "SC-0010"
+	DependenciesSatisfied Code = "SC-0011"
+	DryRunSucceeded       Code = "SC-0012"
+	Reconfigured          Code = "SC-0013"
+	Healthy               Code = "SC-0014"
+	BrokersApplied        Code = "SC-0015"
+	CertsRotated          Code = "SC-0016"
+	SelfUpdated           Code = "SC-0017"
+	UpgradeCompatible     Code = "SC-0018"
+	RolledBack            Code = "SC-0019"
+	NamespaceOnboarded    Code = "SC-0020"
+	LoadTestCompleted     Code = "SC-0021"
+	HandedOff             Code = "SC-0022"
+)
+
+// Failures of commands.
+const (
+	InstallFailed         Code = "SC-1001"
+	UninstallFailed       Code = "SC-1002"
+	VerifyFailed          Code = "SC-1003"
+	AdoptFailed           Code = "SC-1004"
+	PauseFailed           Code = "SC-1005"
+	ResumeFailed          Code = "SC-1006"
+	UpdateFailed          Code = "SC-1007"
+	BrokerAddFailed       Code = "SC-1008"
+	BrokerRemoveFailed    Code = "SC-1009"
+	DependencyCheckFailed Code = "SC-1010"
+	BindingInjectFailed   Code = "SC-1011"
+	FootprintFailed       Code = "SC-1012"
+	EnableAPIsFailed      Code = "SC-1013"
+	MarketplaceFailed     Code = "SC-1014"
+	PruneFailed           Code = "SC-1015"
+	TopFailed             Code = "SC-1016"
+	VersionFailed         Code = "SC-1017"
+	UnstickFailed         Code = "SC-1018"
+	ReconfigureFailed     Code = "SC-1019"
+	DoctorFailed          Code = "SC-1020"
+	BrokerApplyFailed     Code = "SC-1021"
+	RotateCertsFailed     Code = "SC-1022"
+	SelfUpdateFailed      Code = "SC-1023"
+	BrokerLintFailed      Code = "SC-1024"
+	ManifestFailed        Code = "SC-1025"
+	UpgradeCheckFailed    Code = "SC-1026"
+	RollbackFailed        Code = "SC-1027"
+	HistoryFailed         Code = "SC-1028"
+	OnboardFailed         Code = "SC-1029"
+	LoadTestFailed        Code = "SC-1030"
+)
+
+// Errors found before anything is changed.
+const (
+	CommandsNotFound         Code = "SC-2001"
+	ClusterUnreachable       Code = "SC-2002"
+	PermissionDenied         Code = "SC-2003"
+	InvalidInstallConfig     Code = "SC-2004"
+	StorageClassNotFound     Code = "SC-2005"
+	GatekeeperNotInstalled   Code = "SC-2006"
+	ServerSideApplyTooOld    Code = "SC-2007"
+	KubernetesTooOld         Code = "SC-2008"
+	NotBoringCrypto          Code = "SC-2009"
+	ServerDryRunTooOld       Code = "SC-2010"
+	DryRunRejected           Code = "SC-2011"
+	SkippedObjectsMissing    Code = "SC-2012"
+	ObjectConflict           Code = "SC-2013"
+	UnsupportedArchitectures Code = "SC-2014"
+	ServiceAccountsMissing   Code = "SC-2015"
+)
+
+// Errors deploying Service Catalog.
+const (
+	DeployFailed     Code = "SC-3001"
+	ApplyConflict    Code = "SC-3002"
+	NotReady         Code = "SC-3003"
+	ClusterAdminHint Code = "SC-3004"
+	CABundleMismatch Code = "SC-3005"
+)
+
+// entry is a message of the catalog.
+type entry struct {
+	// format is the text, a fmt format.
+	format string
+
+	// failure messages are printed with their code.
+	failure bool
+}
+
+// defaults are the English texts of the messages.
+var defaults = map[Code]entry{
+	Installed:             {"Service Catalog installed successfully.", false},
+	Uninstalled:           {"Service Catalog uninstalled successfully.", false},
+	Verified:              {"Service Catalog installation verified successfully.", false},
+	Adopted:               {"Service Catalog adopted successfully.", false},
+	Paused:                {"Service Catalog paused. Run `sc resume` to resume it.", false},
+	Resumed:               {"Service Catalog resumed.", false},
+	Updated:               {"Service Catalog updated successfully.", false},
+	AuthManagerUpdated:    {"Auth manager updated successfully.", false},
+	BrokerAdded:           {"The Service Broker has been added successfully.", false},
+	BrokerRemoved:         {"The Service Broker has been removed successfully.", false},
+	DependenciesSatisfied: {"Dependency check passed. You are good to go.", false},
+	DryRunSucceeded:       {"Dry run completed, nothing was changed in the cluster.", false},
+	Reconfigured:          {"The controller manager has been reconfigured.", false},
+	Healthy:               {"No known problems found.", false},
+	BrokersApplied:        {"The brokers have been registered and are ready.", false},
+	CertsRotated:          {"The api server certificate and the APIService caBundle have been rotated.", false},
+	SelfUpdated:           {"sc has been updated to %s.", false},
+	UpgradeCompatible:     {"Service Catalog can be upgraded to %s.", false},
+	RolledBack:            {"Service Catalog rolled back to revision %d.", false},
+	NamespaceOnboarded:    {"Namespace %s has been onboarded.", false},
+	LoadTestCompleted:     {"The load test provisioned and bound %d instances.", false},
+	HandedOff:             {"The manifests have been handed off, Service Catalog is installed once they are synced.", false},
+
+	InstallFailed:         {"Service Catalog could not be installed.", true},
+	UninstallFailed:       {"Service Catalog could not be uninstalled.", true},
+	VerifyFailed:          {"Service Catalog installation could not be verified.", true},
+	AdoptFailed:           {"Service Catalog could not be adopted.", true},
+	PauseFailed:           {"Service Catalog could not be paused.", true},
+	ResumeFailed:          {"Service Catalog could not be resumed.", true},
+	UpdateFailed:          {"Service Catalog components could not be updated.", true},
+	BrokerAddFailed:       {"The Service Broker could not be configured.", true},
+	BrokerRemoveFailed:    {"The Service Broker could not be removed.", true},
+	DependencyCheckFailed: {"Dependency check failed.", true},
+	BindingInjectFailed:   {"The binding could not be injected.", true},
+	FootprintFailed:       {"The install footprint could not be computed.", true},
+	EnableAPIsFailed:      {"The GCP APIs could not be enabled.", true},
+	MarketplaceFailed:     {"The marketplace could not be listed.", true},
+	PruneFailed:           {"Instances could not be pruned.", true},
+	TopFailed:             {"Resource usage could not be retrieved.", true},
+	VersionFailed:         {"The sc version could not be printed.", true},
+	UnstickFailed:         {"Namespace %s could not be deleted.", true},
+	ReconfigureFailed:     {"The controller manager could not be reconfigured.", true},
+	DoctorFailed:          {"Problems were found, see the suggestions above.", true},
+	BrokerApplyFailed:     {"The brokers could not be registered.", true},
+	RotateCertsFailed:     {"The certificates could not be rotated.", true},
+	SelfUpdateFailed:      {"sc could not be updated.", true},
+	BrokerLintFailed:      {"The broker catalog violates the Open Service Broker API.", true},
+	ManifestFailed:        {"The manifest could not be rendered.", true},
+	UpgradeCheckFailed:    {"Service Catalog cannot be upgraded to %s, see the checks above.", true},
+	RollbackFailed:        {"Service Catalog could not be rolled back.", true},
+	HistoryFailed:         {"The revision history could not be read.", true},
+	OnboardFailed:         {"The namespace could not be onboarded.", true},
+	LoadTestFailed:        {"The load test failed.", true},
+
+	CommandsNotFound:         {"commands not found in the PATH: %s", true},
+	ClusterUnreachable:       {"cannot reach the Kubernetes cluster%s", true},
+	PermissionDenied:         {"not allowed to %s %s%s", true},
+	InvalidInstallConfig:     {"invalid install configuration:%s", true},
+	StorageClassNotFound:     {"storageclass %s for etcd backup does not exist. Use --etcd-backup-storageclass to specify an existing storageclass", true},
+	GatekeeperNotInstalled:   {"--max-instances-per-namespace, --plan-policy and --binding-secret-policy require OPA Gatekeeper, %s is not served", true},
+	ServerSideApplyTooOld:    {"--server-side requires Kubernetes v1.16+, the cluster runs v%s", true},
+	KubernetesTooOld:         {"Service Catalog requires Kubernetes v1.7+, the cluster runs v%s", true},
+	NotBoringCrypto:          {"sc is not built with BoringCrypto, its own connections are not restricted to FIPS-approved settings. Build it with `make build-fips`.", true},
+	ServerDryRunTooOld:       {"--dry-run=server requires Kubernetes v1.13+, the cluster runs v%s", true},
+	DryRunRejected:           {"the api server rejected %d objects:\n  %s", true},
+	SkippedObjectsMissing:    {"%s requires the objects it skips to exist, missing: %s. Their manifests are in %s", true},
+	ObjectConflict:           {"These objects already exist and are managed by other tools:\n%s\nUse --force-adopt to take them over with sc, or --name-prefix or --name-suffix to create objects with other names", true},
+	UnsupportedArchitectures: {"no node can run the service catalog images, the nodes run %s and\n%s\nAdd nodes of an architecture the images are built for, or use images built for the nodes with --version", true},
+	ServiceAccountsMissing:   {"the pre-created service accounts are missing in namespace %s: %s. Create them, or unset the flags for the install to create its own", true},
+
+	DeployFailed:     {"error deploying YAML files: %v", true},
+	ApplyConflict:    {"deploy of %s from %s conflicts with fields owned by other field managers, e.g. an autoscaler, retry with --force-conflicts to take them over: %s", true},
+	NotReady:         {"%s is not ready after %v: %s", true},
+	CABundleMismatch: {"APIService %s can't reach the api server, %s. Run `sc rotate-certs` to issue a new certificate and caBundle", true},
+	ClusterAdminHint: {"Please run `kubectl create clusterrolebinding cluster-admin-binding --clusterrole=cluster-admin --user=$(gcloud config get-value account)` before `sc install`.", true},
+}
+
+var (
+	mu        sync.RWMutex
+	overrides = map[Code]string{}
+)
+
+// Sprintf returns the text of message c formatted with args, prefixed with
+// the code for failures, e.g. "SC-1001: Service Catalog could not be
+// installed.".
+func Sprintf(c Code, args ...interface{}) string {
+	e, ok := defaults[c]
+	if !ok {
+		return fmt.Sprintf("%s: %s", c, fmt.Sprint(args...))
+	}
+	mu.RLock()
+	format, ok := overrides[c]
+	mu.RUnlock()
+	if !ok {
+		format = e.format
+	}
+	text := fmt.Sprintf(format, args...)
+	if e.failure {
+		return string(c) + ": " + text
+	}
+	return text
+}
+
+// Println prints message c formatted with args to stdout, colored red for
+// failures and green for results.
+func Println(c Code, args ...interface{}) {
+	text := Sprintf(c, args...)
+	if defaults[c].failure {
+		text = color.Red(text)
+	} else {
+		text = color.Green(text)
+	}
+	fmt.Println(text)
+}
+
+// Error is an error with a message code.
+type Error struct {
+	Code Code
+	Args []interface{}
+}
+
+func (e *Error) Error() string {
+	return Sprintf(e.Code, e.Args...)
+}
+
+// Errorf returns the error of message c formatted with args.
+func Errorf(c Code, args ...interface{}) error {
+	return &Error{Code: c, Args: args}
+}
+
+// CodeOf returns the code of err, or "" if it has none.
+func CodeOf(err error) Code {
+	if e, ok := err.(*Error); ok {
+		return e.Code
+	}
+	return ""
+}
+
+// verbRE matches the verbs of fmt formats.
+var verbRE = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// verbs returns the fmt verbs of format, without %%.
+func verbs(format string) []string {
+	var v []string
+	for _, m := range verbRE.FindAllString(format, -1) {
+		if m != "%%" {
+			v = append(v, m)
+		}
+	}
+	return v
+}
+
+// Load reads overrides of the message texts from the YAML or JSON file path,
+// a map of codes to texts. A text must have the same fmt verbs as the
+// default one, in the same order.
+func Load(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var texts map[Code]string
+	if err := yaml.Unmarshal(b, &texts); err != nil {
+		return fmt.Errorf("error parsing messages file %s: %v", path, err)
+	}
+
+	for c, text := range texts {
+		e, ok := defaults[c]
+		if !ok {
+			return fmt.Errorf("error in messages file %s: unknown message code %s", path, c)
+		}
+		if want, got := verbs(e.format), verbs(text); strings.Join(want, " ") != strings.Join(got, " ") {
+			return fmt.Errorf("error in messages file %s: message %s must have the verbs %v, got %v", path, c, want, got)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for c, text := range texts {
+		overrides[c] = text
+	}
+	return nil
+}
+
+// Write writes the current texts of all the messages to w as YAML, in the
+// format read by Load.
+func Write(w io.Writer) error {
+	mu.RLock()
+	texts := make(map[string]string, len(defaults))
+	for c, e := range defaults {
+		texts[string(c)] = e.format
+		if o, ok := overrides[c]; ok {
+			texts[string(c)] = o
+		}
+	}
+	mu.RUnlock()
+
+	codes := make([]string, 0, len(texts))
+	for c := range texts {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	for _, c := range codes {
+		b, err := yaml.Marshal(map[string]string{c: texts[c]})
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package messages
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// reset removes the overrides loaded by a test.
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	overrides = map[Code]string{}
+}
+
+// TestSprintf tests that failures are prefixed with their code and results
+// are not.
+func TestSprintf(t *testing.T) {
+	for _, tc := range []struct {
+		code Code
+		args []interface{}
+		want string
+	}{
+		{Installed, nil, "Service Catalog installed successfully."},
+		{InstallFailed, nil, "SC-1001: Service Catalog could not be installed."},
+		{UnstickFailed, []interface{}{"ns"}, "SC-1018: Namespace ns could not be deleted."},
+		{StorageClassNotFound, []interface{}{"fast"}, "SC-2005: " + fmt.Sprintf(defaults[StorageClassNotFound].format, "fast")},
+	} {
+		if got := Sprintf(tc.code, tc.args...); got != tc.want {
+			t.Fatalf("Message %s does not match: got %q; want %q", tc.code, got, tc.want)
+		}
+	}
+}
+
+// TestDefaults tests that the codes of failures and results are in the
+// ranges their kind reserves.
+func TestDefaults(t *testing.T) {
+	for c, e := range defaults {
+		if result := strings.HasPrefix(string(c), "SC-0"); result == e.failure {
+			t.Fatalf("Message %s has the wrong kind: failure is %v", c, e.failure)
+		}
+	}
+}
+
+// TestLoad tests that overrides replace the default texts and that texts
+// with other verbs or unknown codes are rejected.
+func TestLoad(t *testing.T) {
+	defer reset()
+	dir, err := ioutil.TempDir("", "messages")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(content string) string {
+		path := filepath.Join(dir, "messages.yaml")
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error writing messages file: %v", err)
+		}
+		return path
+	}
+
+	if err := Load(write("SC-0001: Catalogue installé.\nSC-1018: \"Le namespace %s reste bloqué.\"\n")); err != nil {
+		t.Fatalf("Unexpected error loading messages: %v", err)
+	}
+	if got, want := Sprintf(Installed), "Catalogue installé."; got != want {
+		t.Fatalf("Overridden message does not match: got %q; want %q", got, want)
+	}
+	if got, want := Sprintf(UnstickFailed, "ns"), "SC-1018: Le namespace ns reste bloqué."; got != want {
+		t.Fatalf("Overridden failure does not match: got %q; want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf); err != nil {
+		t.Fatalf("Unexpected error writing messages: %v", err)
+	}
+	if !strings.Contains(buf.String(), "SC-0001: Catalogue installé.\n") {
+		t.Fatalf("Written messages do not contain the override:\n%s", buf.String())
+	}
+
+	for _, content := range []string{
+		"SC-1018: Le namespace reste bloqué.\n",
+		"SC-1018: \"%d reste bloqué.\"\n",
+		"SC-9999: inconnu\n",
+	} {
+		if err := Load(write(content)); err == nil {
+			t.Fatalf("Expected an error loading %q", content)
+		}
+	}
+	if got, want := Sprintf(UnstickFailed, "ns"), "SC-1018: Le namespace ns reste bloqué."; got != want {
+		t.Fatalf("Rejected file changed a message: got %q; want %q", got, want)
+	}
+}
+
+// TestErrorf tests that errors carry their code.
+func TestErrorf(t *testing.T) {
+	err := Errorf(KubernetesTooOld, "1.6")
+	if got, want := CodeOf(err), KubernetesTooOld; got != want {
+		t.Fatalf("Code does not match: got %v; want %v", got, want)
+	}
+	if !strings.HasPrefix(err.Error(), string(KubernetesTooOld)+": ") {
+		t.Fatalf("Error must start with its code: got %q", err.Error())
+	}
+	if got := CodeOf(fmt.Errorf("plain")); got != "" {
+		t.Fatalf("Code of a plain error does not match: got %v; want none", got)
+	}
+}
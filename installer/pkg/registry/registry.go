@@ -0,0 +1,329 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry reads the platforms, i.e. the OS and architecture,
+// container images are built for from their registries, with the Docker
+// Registry HTTP API V2 that the OCI distribution spec is based on.
+package registry
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Media types of the manifests read.
+const (
+	mediaTypeDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeOCIIndex       = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// dockerHub is the registry of the images without a registry host, e.g.
+// busybox.
+const dockerHub = "registry-1.docker.io"
+
+// Platform is a platform an image is built for.
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+func (p Platform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// Reference is a parsed image reference.
+type Reference struct {
+	// Registry is the host of the registry, e.g. gcr.io.
+	Registry string
+
+	// Repository is the repository in the registry, e.g.
+	// gcp-services/service-catalog.
+	Repository string
+
+	// Reference is the tag or the digest of the image.
+	Reference string
+}
+
+// ParseReference parses image, e.g. gcr.io/gcp-services/service-catalog:v0.1.11
+// or busybox@sha256:..., defaulting to Docker Hub and the latest tag.
+func ParseReference(image string) (*Reference, error) {
+	if image == "" || strings.ContainsAny(image, " \t") {
+		return nil, fmt.Errorf("invalid image reference %q", image)
+	}
+	r := &Reference{Registry: dockerHub, Reference: "latest"}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, r.Reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i:], "/") {
+		name, r.Reference = name[:i], name[i+1:]
+	}
+	if i := strings.Index(name, "/"); i >= 0 {
+		if host := name[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			r.Registry, name = host, name[i+1:]
+		}
+	}
+	if r.Registry == dockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	if name == "" || r.Reference == "" {
+		return nil, fmt.Errorf("invalid image reference %q", image)
+	}
+	r.Repository = name
+	return r, nil
+}
+
+// Client reads images from registries.
+type Client struct {
+	// HTTP is the client the requests are made with.
+	HTTP *http.Client
+
+	// Scheme is the scheme of the registry URLs, https if empty.
+	Scheme string
+}
+
+// Platforms returns the platforms image is built for: those of its index if
+// it is a multi-platform image, or that of its config.
+func (c *Client) Platforms(image string) ([]Platform, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	var manifest struct {
+		MediaType string `json:"mediaType"`
+		Manifests []struct {
+			Platform Platform `json:"platform"`
+		} `json:"manifests"`
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	}
+	accept := strings.Join([]string{mediaTypeDockerList, mediaTypeOCIIndex, mediaTypeDockerManifest, mediaTypeOCIManifest}, ", ")
+	b, header, err := c.get(ref, "manifests/"+ref.Reference, accept)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the manifest of %s: %v", image, err)
+	}
+	if manifest.MediaType == "" {
+		manifest.MediaType = header.Get("Content-Type")
+	}
+
+	switch manifest.MediaType {
+	case mediaTypeDockerList, mediaTypeOCIIndex:
+		var platforms []Platform
+		for _, m := range manifest.Manifests {
+			// Attestations are indexed with an unknown platform.
+			if m.Platform.Architecture != "" && m.Platform.Architecture != "unknown" {
+				platforms = append(platforms, m.Platform)
+			}
+		}
+		return platforms, nil
+	case mediaTypeDockerManifest, mediaTypeOCIManifest:
+		if manifest.Config.Digest == "" {
+			return nil, fmt.Errorf("the manifest of %s has no config", image)
+		}
+		b, _, err := c.get(ref, "blobs/"+manifest.Config.Digest, "")
+		if err != nil {
+			return nil, err
+		}
+		var p Platform
+		if err := json.Unmarshal(b, &p); err != nil {
+			return nil, fmt.Errorf("error unmarshalling the config of %s: %v", image, err)
+		}
+		return []Platform{p}, nil
+	default:
+		return nil, fmt.Errorf("unsupported manifest type %q of %s", manifest.MediaType, image)
+	}
+}
+
+// Digest returns the digest of image, e.g. sha256:..., that of its index if
+// it is a multi-platform image. It is the Docker-Content-Digest the
+// registry reports, verified against the manifest, or the digest of the
+// manifest if the registry reports none. Images referenced by digest are
+// not read.
+func (c *Client) Digest(image string) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(ref.Reference, "sha256:") {
+		return ref.Reference, nil
+	}
+	accept := strings.Join([]string{mediaTypeDockerList, mediaTypeOCIIndex, mediaTypeDockerManifest, mediaTypeOCIManifest}, ", ")
+	b, header, err := c.get(ref, "manifests/"+ref.Reference, accept)
+	if err != nil {
+		return "", err
+	}
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" {
+		// Manifests are addressed by the digest of their content.
+		sum := sha256.Sum256(b)
+		return "sha256:" + hex.EncodeToString(sum[:]), nil
+	}
+	if err := verifyDigest(digest, b); err != nil {
+		return "", fmt.Errorf("the manifest of %s does not match its digest: %v", image, err)
+	}
+	return digest, nil
+}
+
+// verifyDigest returns an error unless digest, e.g. sha256:..., is the
+// digest of b.
+func verifyDigest(digest string, b []byte) error {
+	var h hash.Hash
+	switch {
+	case strings.HasPrefix(digest, "sha256:"):
+		h = sha256.New()
+	case strings.HasPrefix(digest, "sha512:"):
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported digest %q", digest)
+	}
+	h.Write(b)
+	got := digest[:strings.Index(digest, ":")+1] + hex.EncodeToString(h.Sum(nil))
+	if got != digest {
+		return fmt.Errorf("got %s, the registry reported %s", got, digest)
+	}
+	return nil
+}
+
+// get reads path of the repository of ref, e.g. manifests/latest,
+// authenticating with an anonymous token if the registry asks for one. It
+// returns the body and the headers of the response.
+func (c *Client) get(ref *Reference, path, accept string) ([]byte, http.Header, error) {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", scheme, ref.Registry, ref.Repository, path)
+	token := ""
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error fetching %s: %v", u, err)
+		}
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("error fetching %s: %v", u, err)
+		}
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return b, resp.Header, nil
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			if token, err = c.anonymousToken(resp.Header.Get("WWW-Authenticate")); err != nil {
+				return nil, nil, fmt.Errorf("error authenticating to %s: %v", ref.Registry, err)
+			}
+		default:
+			return nil, nil, fmt.Errorf("error fetching %s: %s", u, resp.Status)
+		}
+	}
+}
+
+// anonymousToken fetches a token for the Bearer challenge of a registry,
+// e.g. Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/busybox:pull".
+func (c *Client) anonymousToken(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported challenge %q", challenge)
+	}
+	params := parseChallenge(strings.TrimPrefix(challenge, "Bearer "))
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid realm in challenge %q", challenge)
+	}
+	q := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if v, ok := params[k]; ok {
+			q.Set(k, v)
+		}
+	}
+	realm.RawQuery = q.Encode()
+
+	resp, err := c.HTTP.Get(realm.String())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error fetching a token from %s: %s", realm.Host, resp.Status)
+	}
+	var t struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return "", err
+	}
+	if t.Token != "" {
+		return t.Token, nil
+	}
+	return t.AccessToken, nil
+}
+
+// parseChallenge parses the comma separated key="value" parameters of an
+// authentication challenge.
+func parseChallenge(s string) map[string]string {
+	params := make(map[string]string)
+	for s != "" {
+		eq := strings.Index(s, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.TrimSpace(s[:eq])
+		s = s[eq+1:]
+		var value string
+		if strings.HasPrefix(s, "\"") {
+			end := strings.Index(s[1:], "\"")
+			if end < 0 {
+				value, s = s[1:], ""
+			} else {
+				value, s = s[1:end+1], s[end+2:]
+			}
+		} else if comma := strings.Index(s, ","); comma >= 0 {
+			value, s = s[:comma], s[comma:]
+		} else {
+			value, s = s, ""
+		}
+		params[key] = value
+		s = strings.TrimPrefix(strings.TrimSpace(s), ",")
+	}
+	return params
+}
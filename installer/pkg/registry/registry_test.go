@@ -0,0 +1,162 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseReference tests that the registry, repository and tag or digest
+// of image references are parsed with the Docker Hub defaults.
+func TestParseReference(t *testing.T) {
+	for _, tc := range []struct {
+		image string
+		want  Reference
+	}{
+		{"busybox", Reference{dockerHub, "library/busybox", "latest"}},
+		{"prom/prometheus:v2.3.0", Reference{dockerHub, "prom/prometheus", "v2.3.0"}},
+		{"gcr.io/gcp-services/service-catalog:v0.1.11", Reference{"gcr.io", "gcp-services/service-catalog", "v0.1.11"}},
+		{"localhost:5000/etcd", Reference{"localhost:5000", "etcd", "latest"}},
+		{"quay.io/coreos/etcd@sha256:abc", Reference{"quay.io", "coreos/etcd", "sha256:abc"}},
+	} {
+		got, err := ParseReference(tc.image)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing %q: %v", tc.image, err)
+		}
+		if *got != tc.want {
+			t.Fatalf("Reference of %q does not match: got %+v; want %+v", tc.image, *got, tc.want)
+		}
+	}
+	for _, image := range []string{"", "busybox:", "bad image"} {
+		if _, err := ParseReference(image); err == nil {
+			t.Fatalf("Expected an error parsing %q", image)
+		}
+	}
+}
+
+// newTestRegistry returns a registry serving the bodies by path, with their
+// content type and, if given, Docker-Content-Digest, that requires the
+// anonymous token of its /token endpoint.
+func newTestRegistry(t *testing.T, bodies map[string][]string) (*httptest.Server, *Client) {
+	var s *httptest.Server
+	s = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if got := r.URL.Query().Get("scope"); !strings.HasPrefix(got, "repository:") {
+				t.Errorf("Unexpected token scope %q", got)
+			}
+			w.Write([]byte(`{"token": "anonymous"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+s.URL+`/token",service="test",scope="repository:app:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		b, ok := bodies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", b[0])
+		if len(b) > 2 {
+			w.Header().Set("Docker-Content-Digest", b[2])
+		}
+		w.Write([]byte(b[1]))
+	}))
+	return s, &Client{HTTP: s.Client(), Scheme: "http"}
+}
+
+// TestPlatforms tests that the platforms of multi-platform images are read
+// from their index, skipping attestations, and those of single-platform
+// images from their config.
+func TestPlatforms(t *testing.T) {
+	s, c := newTestRegistry(t, map[string][]string{
+		"/v2/multi/manifests/v1": {mediaTypeOCIIndex, `{"manifests": [
+			{"platform": {"os": "linux", "architecture": "amd64"}},
+			{"platform": {"os": "linux", "architecture": "arm64", "variant": "v8"}},
+			{"platform": {"os": "unknown", "architecture": "unknown"}}]}`},
+		"/v2/single/manifests/v1":    {mediaTypeDockerManifest, `{"config": {"digest": "sha256:c0"}}`},
+		"/v2/single/blobs/sha256:c0": {"application/octet-stream", `{"os": "linux", "architecture": "amd64"}`},
+	})
+	defer s.Close()
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	for _, tc := range []struct {
+		image string
+		want  []Platform
+	}{
+		{host + "/multi:v1", []Platform{{"linux", "amd64", ""}, {"linux", "arm64", "v8"}}},
+		{host + "/single:v1", []Platform{{"linux", "amd64", ""}}},
+	} {
+		got, err := c.Platforms(tc.image)
+		if err != nil {
+			t.Fatalf("Unexpected error reading the platforms of %s: %v", tc.image, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("Platforms of %s do not match: got %v; want %v", tc.image, got, tc.want)
+		}
+	}
+
+	if _, err := c.Platforms(host + "/missing:v1"); err == nil {
+		t.Fatalf("Expected an error reading the platforms of a missing image")
+	}
+}
+
+// TestDigest tests that the digest of an image is the one the registry
+// reports, if it matches the manifest, or that of its manifest, and that
+// images referenced by digest are not read.
+func TestDigest(t *testing.T) {
+	index := `{"manifests": []}`
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(index)))
+	s, c := newTestRegistry(t, map[string][]string{
+		"/v2/multi/manifests/v1":    {mediaTypeOCIIndex, index},
+		"/v2/reported/manifests/v1": {mediaTypeOCIIndex, index, digest},
+		"/v2/tampered/manifests/v1": {mediaTypeOCIIndex, index, "sha256:" + strings.Repeat("1", 64)},
+		"/v2/unknown/manifests/v1":  {mediaTypeOCIIndex, index, "md5:00"},
+	})
+	defer s.Close()
+	host := strings.TrimPrefix(s.URL, "http://")
+
+	for _, image := range []string{"multi:v1", "reported:v1"} {
+		got, err := c.Digest(host + "/" + image)
+		if err != nil {
+			t.Fatalf("Unexpected error reading the digest of %s: %v", image, err)
+		}
+		if got != digest {
+			t.Fatalf("Digest of %s does not match: got %q; want %q", image, got, digest)
+		}
+	}
+	for _, image := range []string{"tampered:v1", "unknown:v1"} {
+		if _, err := c.Digest(host + "/" + image); err == nil || !strings.Contains(err.Error(), "does not match its digest") {
+			t.Fatalf("Expected an error verifying the digest of %s, got %v", image, err)
+		}
+	}
+
+	pinned := "sha256:" + strings.Repeat("0", 64)
+	if got, err := c.Digest(host + "/missing@" + pinned); err != nil || got != pinned {
+		t.Fatalf("Expected the digest of the reference, got %q, %v", got, err)
+	}
+	if _, err := c.Digest(host + "/missing:v1"); err == nil {
+		t.Fatalf("Expected an error reading the digest of a missing image")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+// execArg is the first argument of the sc processes tracing a command,
+// followed by the trace file, the program and the number of its arguments,
+// the arguments, and the command line the Base of the Executor runs them
+// with.
+const execArg = "__sc-trace-exec"
+
+// sensitiveRE matches the arguments of the commands whose output may hold
+// credentials, e.g. kubectl get secret or gcloud auth print-access-token.
+var sensitiveRE = regexp.MustCompile(`(?i)secret|token|password|credential|key`)
+
+// Executor is an execx.Executor tracing the commands of Base in the trace
+// file of its Recorder.
+type Executor struct {
+	// Base creates the commands and looks the programs up.
+	Base execx.Executor
+
+	// Recorder is the trace file the spans are appended to.
+	Recorder *Recorder
+
+	// self is the sc executable, which runs the traced commands.
+	self string
+}
+
+// NewExecutor returns the Executor tracing the commands of base in r.
+func NewExecutor(base execx.Executor, r *Recorder) (*Executor, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("error finding the sc executable: %v", err)
+	}
+	return &Executor{Base: base, Recorder: r, self: self}, nil
+}
+
+// Command returns the command of Base running program name with args
+// through the sc executable, which records its span.
+func (e *Executor) Command(name string, args ...string) *exec.Cmd {
+	base := e.Base.Command(name, args...)
+	a := append([]string{execArg, e.Recorder.Path, name, strconv.Itoa(len(args))}, args...)
+	a = append(append(a, base.Path), base.Args[1:]...)
+	cmd := exec.Command(e.self, a...)
+	cmd.Env = base.Env
+	cmd.Dir = base.Dir
+	return cmd
+}
+
+func (e *Executor) LookPath(file string) (string, error) {
+	return e.Base.LookPath(file)
+}
+
+// Main runs the traced command and exits if the executable was started by
+// an Executor, and returns otherwise. The sc executable must call it first.
+func Main() {
+	if len(os.Args) < 5 || os.Args[1] != execArg {
+		return
+	}
+	n, err := strconv.Atoi(os.Args[4])
+	if err != nil || n < 0 || len(os.Args) < 6+n {
+		fmt.Fprintf(os.Stderr, "invalid traced command %q\n", os.Args[2:])
+		os.Exit(127)
+	}
+	os.Exit(run(os.Args[2], os.Args[3], os.Args[5:5+n], os.Args[5+n:]))
+}
+
+// run runs command line cmdline, passing the input and output of the
+// process through, records it in the trace file path as program name with
+// args and returns the exit code of the process.
+func run(path, name string, args, cmdline []string) int {
+	s := &Span{Kind: Exec, Name: name, Args: args, Start: time.Now()}
+	var stdout, stderr capture
+	cmd := exec.Command(cmdline[0], cmdline[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	// Stop the command with the process: execx.Kill sends SIGTERM, and on
+	// Linux the command is also killed if the process is.
+	setParentDeathSignal(cmd)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	err := cmd.Start()
+	if err == nil {
+		done := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case sig := <-signals:
+					cmd.Process.Signal(sig)
+				case <-done:
+					return
+				}
+			}
+		}()
+		err = cmd.Wait()
+		close(done)
+	}
+	s.Duration = time.Since(s.Start)
+
+	code := 0
+	switch e := err.(type) {
+	case nil:
+	case *exec.ExitError:
+		s.ExitCode = e.ExitCode()
+		code = s.ExitCode
+		if code < 0 {
+			// Killed by a signal.
+			code = 1
+		}
+	default:
+		fmt.Fprintln(os.Stderr, err)
+		s.ExitCode = -1
+		s.Error, _ = truncate([]byte(err.Error()))
+		code = 127
+	}
+
+	if sensitive(args) {
+		s.Redacted = true
+	} else {
+		s.Stdout, s.Stderr = string(stdout.b), string(stderr.b)
+		s.Truncated = stdout.truncated || stderr.truncated
+	}
+	if b, err := json.Marshal(s); err == nil {
+		// The output of the command must not change, errors of the trace
+		// only lose its span.
+		appendLine(path, b)
+	}
+	return code
+}
+
+// sensitive returns whether the output of a command with args may hold
+// credentials.
+func sensitive(args []string) bool {
+	for _, a := range args {
+		if sensitiveRE.MatchString(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// capture keeps the first MaxOutput bytes written to it.
+type capture struct {
+	b         []byte
+	truncated bool
+}
+
+func (c *capture) Write(p []byte) (int, error) {
+	if n := MaxOutput - len(c.b); n < len(p) {
+		c.truncated = true
+		if n > 0 {
+			c.b = append(c.b, p[:n]...)
+		}
+		return len(p), nil
+	}
+	c.b = append(c.b, p...)
+	return len(p), nil
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTLP span kinds and status codes, see
+// https://github.com/open-telemetry/opentelemetry-proto.
+const (
+	otlpKindInternal = 1
+	otlpKindClient   = 3
+	otlpStatusError  = 2
+)
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+func intAttribute(key string, value int) otlpAttribute {
+	v := strconv.Itoa(value)
+	return otlpAttribute{Key: key, Value: otlpValue{IntValue: &v}}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// randomID returns n random bytes in hex.
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// defaultExportTimeout is the timeout of an export without
+// OTEL_EXPORTER_OTLP_TIMEOUT, that of the OpenTelemetry SDKs.
+const defaultExportTimeout = 10 * time.Second
+
+// Exporter exports traces to an OpenTelemetry collector with the OTLP/HTTP
+// JSON protocol, configured like the OpenTelemetry SDKs by the OTEL_*
+// environment variables, see
+// https://opentelemetry.io/docs/specs/otel/protocol/exporter/.
+type Exporter struct {
+	// URL is the URL the traces are posted to, e.g.
+	// http://localhost:4318/v1/traces.
+	URL string
+
+	// Headers are the headers of the requests, e.g. credentials.
+	Headers map[string]string
+
+	// Gzip compresses the requests.
+	Gzip bool
+
+	// Timeout bounds each export.
+	Timeout time.Duration
+
+	// Resource are the attributes of the resource of the spans, its
+	// service.name first.
+	Resource []otlpAttribute
+
+	// TraceID and ParentSpanID are those of the parent of the root span,
+	// from the TRACEPARENT of the process, e.g. the span of the pipeline
+	// step running sc, or empty.
+	TraceID, ParentSpanID string
+
+	// rootCAs are the CAs of the collector certificate, the system ones
+	// if nil.
+	rootCAs *x509.CertPool
+}
+
+// NewExporter returns the exporter of the traces of service to the
+// collector at endpoint, e.g. http://localhost:4318, or if it is empty at
+// the OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT of
+// getenv. It returns nil if neither is set, or the environment disables
+// the traces with OTEL_SDK_DISABLED or OTEL_TRACES_EXPORTER=none.
+func NewExporter(endpoint, service string, getenv func(string) string) (*Exporter, error) {
+	// The variables specific to the traces take precedence.
+	env := func(name string) string {
+		if v := getenv("OTEL_EXPORTER_OTLP_TRACES_" + name); v != "" {
+			return v
+		}
+		return getenv("OTEL_EXPORTER_OTLP_" + name)
+	}
+	e := &Exporter{Timeout: defaultExportTimeout}
+	switch {
+	case endpoint != "":
+		e.URL = strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+	case strings.EqualFold(getenv("OTEL_SDK_DISABLED"), "true") || getenv("OTEL_TRACES_EXPORTER") == "none":
+		return nil, nil
+	case getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "":
+		// The signal specific endpoint is the URL of the traces.
+		e.URL = getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	case getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "":
+		e.URL = strings.TrimSuffix(getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "/") + "/v1/traces"
+	default:
+		return nil, nil
+	}
+	if _, err := url.Parse(e.URL); err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %v", e.URL, err)
+	}
+	if p := env("PROTOCOL"); p != "" && p != "http/json" {
+		return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_PROTOCOL %q is not supported, sc exports traces with http/json", p)
+	}
+
+	var err error
+	if e.Headers, err = parseKeyValues(env("HEADERS")); err != nil {
+		return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_HEADERS: %v", err)
+	}
+	switch c := env("COMPRESSION"); c {
+	case "", "none":
+	case "gzip":
+		e.Gzip = true
+	default:
+		return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_COMPRESSION %q, expected gzip or none", c)
+	}
+	if t := env("TIMEOUT"); t != "" {
+		ms, err := strconv.Atoi(t)
+		if err != nil || ms < 0 {
+			return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_TIMEOUT %q, expected milliseconds", t)
+		}
+		e.Timeout = time.Duration(ms) * time.Millisecond
+	}
+	if f := env("CERTIFICATE"); f != "" {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_CERTIFICATE: %v", err)
+		}
+		e.rootCAs = x509.NewCertPool()
+		if !e.rootCAs.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_CERTIFICATE: no PEM certificates in %s", f)
+		}
+	}
+
+	attributes, err := parseKeyValues(getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTEL_RESOURCE_ATTRIBUTES: %v", err)
+	}
+	if n := attributes["service.name"]; n != "" {
+		service = n
+	}
+	if n := getenv("OTEL_SERVICE_NAME"); n != "" {
+		service = n
+	}
+	delete(attributes, "service.name")
+	e.Resource = []otlpAttribute{stringAttribute("service.name", service)}
+	var keys []string
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e.Resource = append(e.Resource, stringAttribute(k, attributes[k]))
+	}
+
+	// An invalid traceparent is ignored, like the W3C Trace Context
+	// requires.
+	e.TraceID, e.ParentSpanID = parseTraceparent(getenv("TRACEPARENT"))
+	return e, nil
+}
+
+// parseKeyValues parses the comma separated key=value pairs of s, with
+// percent-encoded values, e.g. of OTEL_EXPORTER_OTLP_HEADERS.
+func parseKeyValues(s string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, kv := range strings.Split(s, ",") {
+		if strings.TrimSpace(kv) == "" {
+			continue
+		}
+		parts := strings.SplitN(kv, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, fmt.Errorf("%q is not a key=value pair", kv)
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s: %v", key, err)
+		}
+		m[key] = value
+	}
+	return m, nil
+}
+
+// parseTraceparent returns the trace and parent span IDs of the W3C
+// traceparent header v, e.g.
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01, or empty IDs if
+// v is not valid.
+func parseTraceparent(v string) (string, string) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || !isHex(parts[0], 2) || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return "", ""
+	}
+	traceID, spanID := parts[1], parts[2]
+	if !isHex(traceID, 32) || traceID == strings.Repeat("0", 32) || !isHex(spanID, 16) || spanID == strings.Repeat("0", 16) || !isHex(parts[3], 2) {
+		return "", ""
+	}
+	return traceID, spanID
+}
+
+// isHex returns whether s is n lowercase hex digits.
+func isHex(s string, n int) bool {
+	if len(s) != n || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// otlpRequest returns the OTLP/HTTP JSON request of e exporting spans as
+// the children of the root span name, lasting from start to end, and the
+// commands and API calls of a phase as its children.
+func (e *Exporter) otlpRequest(name string, start, end time.Time, failed bool, spans []*Span) map[string]interface{} {
+	traceID := e.TraceID
+	if traceID == "" {
+		traceID = randomID(16)
+	}
+	root := otlpSpan{
+		TraceID:           traceID,
+		SpanID:            randomID(8),
+		ParentSpanID:      e.ParentSpanID,
+		Name:              name,
+		Kind:              otlpKindInternal,
+		StartTimeUnixNano: unixNano(start),
+		EndTimeUnixNano:   unixNano(end),
+	}
+	if failed {
+		root.Status.Code = otlpStatusError
+	}
+	// The calls are the children of the phase they started in, if any.
+	ids := make(map[*Span]string)
+	var phases []*Span
+	for _, s := range spans {
+		ids[s] = randomID(8)
+		if s.Kind == Phase {
+			phases = append(phases, s)
+		}
+	}
+	out := []otlpSpan{root}
+	for _, s := range spans {
+		o := otlpSpan{
+			TraceID:           traceID,
+			SpanID:            ids[s],
+			ParentSpanID:      root.SpanID,
+			Name:              s.Name,
+			Kind:              otlpKindInternal,
+			StartTimeUnixNano: unixNano(s.Start),
+			EndTimeUnixNano:   unixNano(s.end()),
+		}
+		if s.Kind != Phase {
+			for _, p := range phases {
+				if p.contains(s) {
+					o.ParentSpanID = ids[p]
+				}
+			}
+		}
+		switch s.Kind {
+		case HTTP:
+			o.Kind = otlpKindClient
+			o.Attributes = []otlpAttribute{
+				stringAttribute("http.request.method", strings.SplitN(s.Name, " ", 2)[0]),
+				stringAttribute("url.path", strings.Join(s.Args, " ")),
+				intAttribute("http.response.status_code", s.ExitCode),
+			}
+		case Phase:
+			o.Attributes = []otlpAttribute{stringAttribute("sc.phase", s.Name)}
+		default:
+			o.Attributes = []otlpAttribute{
+				stringAttribute("process.executable.name", s.Name),
+				stringAttribute("process.command_args", strings.Join(s.Args, " ")),
+				intAttribute("process.exit.code", s.ExitCode),
+			}
+		}
+		if s.failed() {
+			o.Status = otlpStatus{Code: otlpStatusError, Message: s.Error}
+		}
+		out = append(out, o)
+	}
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": e.Resource,
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]string{"name": "sc"},
+				"spans": out,
+			}},
+		}},
+	}
+}
+
+// Export sends spans to the collector of e as the children of the root span
+// name, lasting from start to end and failed if failed is set. The requests
+// are sent with transport, e.g. one not tracing them.
+func (e *Exporter) Export(transport http.RoundTripper, name string, start, end time.Time, failed bool, spans []*Span) error {
+	b, err := json.Marshal(e.otlpRequest(name, start, end, failed, spans))
+	if err != nil {
+		return err
+	}
+	if e.Gzip {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+	}
+	req, err := http.NewRequest(http.MethodPost, e.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+	if e.rootCAs != nil {
+		transport = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: &tls.Config{RootCAs: e.rootCAs}}
+	}
+	client := &http.Client{Transport: transport, Timeout: e.Timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error exporting the trace: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, MaxOutput))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("error exporting the trace to %s: %s: %s", e.URL, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
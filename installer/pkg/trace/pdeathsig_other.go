@@ -0,0 +1,27 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import "os/exec"
+
+// setParentDeathSignal does nothing, only Linux kills the children of
+// exiting processes on request. Elsewhere the commands are only stopped by
+// the signals the process forwards, e.g. the SIGTERM of execx.Kill.
+func setParentDeathSignal(cmd *exec.Cmd) {}
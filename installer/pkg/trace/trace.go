@@ -0,0 +1,277 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package trace records the external commands and API calls of sc, with
+// their duration, exit code or status and truncated output, and the phases
+// of its operations, e.g. the deploy of an install, in a trace file of JSON
+// lines, so that it can be seen where a slow install spends its time. The
+// trace can also be exported to an OpenTelemetry collector.
+//
+// Commands are traced by running them through the sc executable itself,
+// which must call Main first: the traced process runs the command, copies
+// its input and output and appends the span of the command to the trace
+// file once it exits. This keeps the exec.Cmd API of the callers, which run
+// the commands as they see fit.
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// MaxOutput is the number of bytes of the standard output and error of a
+// command, and of the start of an error, kept in its span.
+const MaxOutput = 2048
+
+// Span kinds.
+const (
+	Exec  = "exec"
+	HTTP  = "http"
+	Phase = "phase"
+)
+
+// Span is a traced command, API call or phase of an operation.
+type Span struct {
+	// Kind is Exec, HTTP or Phase.
+	Kind string `json:"kind"`
+
+	// Name is the program of a command, e.g. kubectl, the method and host
+	// of an API call, or the operation and phase, e.g. install/deploy.
+	Name string `json:"name"`
+
+	// Args are the arguments of a command, or the path of an API call.
+	Args []string `json:"args,omitempty"`
+
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+
+	// ExitCode is the exit code of a command, -1 if it did not start, or
+	// the status code of an API call, 0 if it got no response.
+	ExitCode int `json:"exitCode"`
+
+	// Stdout and Stderr are the start of the output of a command, unless
+	// it is redacted.
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+
+	// Truncated is true if output was cut at MaxOutput.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Redacted is true if the output was not recorded since it may hold
+	// credentials, e.g. of secrets.
+	Redacted bool `json:"redacted,omitempty"`
+
+	// Error is the error of a command which did not start, of an API
+	// call which got no response, or of a failed phase.
+	Error string `json:"error,omitempty"`
+}
+
+// failed returns whether s failed.
+func (s *Span) failed() bool {
+	switch s.Kind {
+	case HTTP:
+		return s.ExitCode == 0 || s.ExitCode >= 400
+	case Phase:
+		return s.Error != ""
+	}
+	return s.ExitCode != 0
+}
+
+// end returns the end of s.
+func (s *Span) end() time.Time {
+	return s.Start.Add(s.Duration)
+}
+
+// contains returns whether c started during s.
+func (s *Span) contains(c *Span) bool {
+	return !c.Start.Before(s.Start) && c.Start.Before(s.end())
+}
+
+// String returns the command line of s, or the method and URL of an API
+// call.
+func (s *Span) String() string {
+	return strings.Join(append([]string{s.Name}, s.Args...), " ")
+}
+
+// Recorder appends spans to a trace file. It is safe for concurrent use,
+// and processes append to the same file with whole lines.
+type Recorder struct {
+	// Path is the trace file.
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewRecorder returns the Recorder of the trace file path, creating it
+// empty. Only the user may read it.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace file: %v", err)
+	}
+	f.Close()
+	return &Recorder{Path: path}, nil
+}
+
+// Record appends s to the trace file.
+func (r *Recorder) Record(s *Span) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return appendLine(r.Path, b)
+}
+
+// appendLine appends b and a newline to file path with a single write, so
+// that the lines of processes appending at once do not interleave.
+func appendLine(path string, b []byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the spans of the trace file path, sorted by start.
+func Read(path string) ([]*Span, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var spans []*Span
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		s := &Span{}
+		if err := json.Unmarshal(sc.Bytes(), s); err != nil {
+			return nil, fmt.Errorf("error parsing trace file %s: %v", path, err)
+		}
+		spans = append(spans, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+	return spans, nil
+}
+
+// slowest is the number of slowest spans listed by Summary.
+const slowest = 10
+
+// Summary writes the timing breakdown of spans to w: the duration of the
+// phases, the number, total duration and failures of the calls of each
+// program or API host, and the slowest calls. Calls running at once are
+// each counted in full, so the totals may add up to more than elapsed, the
+// duration of the traced command.
+func Summary(w io.Writer, spans []*Span, elapsed time.Duration) {
+	var phases, calls []*Span
+	for _, s := range spans {
+		if s.Kind == Phase {
+			phases = append(phases, s)
+		} else {
+			calls = append(calls, s)
+		}
+	}
+	if len(phases) > 0 {
+		fmt.Fprintln(w, "phases:")
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, p := range phases {
+			status := "ok"
+			if p.failed() {
+				status = "failed"
+			}
+			fmt.Fprintf(tw, "%v\t%s\t%s\n", p.Duration.Round(time.Millisecond), status, p.Name)
+		}
+		tw.Flush()
+	}
+	spans = calls
+
+	type total struct {
+		name     string
+		calls    int
+		failed   int
+		duration time.Duration
+	}
+	byName := make(map[string]*total)
+	var totals []*total
+	for _, s := range spans {
+		t, ok := byName[s.Name]
+		if !ok {
+			t = &total{name: s.Name}
+			byName[s.Name] = t
+			totals = append(totals, t)
+		}
+		t.calls++
+		t.duration += s.Duration
+		if s.failed() {
+			t.failed++
+		}
+	}
+	sort.SliceStable(totals, func(i, j int) bool { return totals[i].duration > totals[j].duration })
+
+	fmt.Fprintf(w, "traced %d calls in %v\n", len(spans), elapsed.Round(time.Millisecond))
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CALLS\tFAILED\tTIME\tSHARE\t")
+	for _, t := range totals {
+		share := 0.0
+		if elapsed > 0 {
+			share = 100 * float64(t.duration) / float64(elapsed)
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%v\t%.0f%%\t%s\n", t.calls, t.failed, t.duration.Round(time.Millisecond), share, t.name)
+	}
+	tw.Flush()
+
+	sorted := append([]*Span(nil), spans...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Duration > sorted[j].Duration })
+	if len(sorted) > slowest {
+		sorted = sorted[:slowest]
+	}
+	if len(sorted) > 0 {
+		fmt.Fprintln(w, "slowest calls:")
+	}
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, s := range sorted {
+		line := s.String()
+		if len(line) > 100 {
+			line = line[:97] + "..."
+		}
+		fmt.Fprintf(tw, "%v\t%d\t%s\n", s.Duration.Round(time.Millisecond), s.ExitCode, line)
+	}
+	tw.Flush()
+}
+
+// truncate returns the first MaxOutput bytes of b, and whether it was cut.
+func truncate(b []byte) (string, bool) {
+	if len(b) > MaxOutput {
+		return string(b[:MaxOutput]), true
+	}
+	return string(b), false
+}
@@ -0,0 +1,412 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-service-catalog/installer/pkg/execx"
+)
+
+func TestMain(m *testing.M) {
+	Main()
+	os.Exit(m.Run())
+}
+
+// TestHelperProcess is the program traced by the tests.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("TRACE_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	for _, a := range args[1:] {
+		key, value := a, ""
+		if i := strings.Index(a, "="); i >= 0 {
+			key, value = a[:i], a[i+1:]
+		}
+		switch key {
+		case "stdout":
+			fmt.Println(value)
+		case "stderr":
+			fmt.Fprintln(os.Stderr, value)
+		case "zeros":
+			n, _ := strconv.Atoi(value)
+			os.Stdout.Write(make([]byte, n))
+		case "exit":
+			n, _ := strconv.Atoi(value)
+			os.Exit(n)
+		case "sleep":
+			d, _ := time.ParseDuration(value)
+			time.Sleep(d)
+		}
+	}
+	os.Exit(0)
+}
+
+func newTestRecorder(t *testing.T) (*Recorder, func()) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	r, err := NewRecorder(filepath.Join(dir, "trace.jsonl"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating recorder: %v", err)
+	}
+	return r, func() { os.RemoveAll(dir) }
+}
+
+// helper returns the command running the helper process with actions
+// through e.
+func helper(e *Executor, actions ...string) *exec.Cmd {
+	cmd := e.Command(os.Args[0], append([]string{"-test.run=TestHelperProcess", "--"}, actions...)...)
+	cmd.Env = append(os.Environ(), "TRACE_HELPER_PROCESS=1")
+	return cmd
+}
+
+// TestExecutor tests that traced commands keep their output and exit code
+// and that their spans are recorded, with output cut at MaxOutput and
+// redacted for sensitive commands.
+func TestExecutor(t *testing.T) {
+	r, cleanup := newTestRecorder(t)
+	defer cleanup()
+	e, err := NewExecutor(execx.OS{}, r)
+	if err != nil {
+		t.Fatalf("Unexpected error creating executor: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := helper(e, "stdout=pods", "stderr=warning", "exit=3")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err = cmd.Run()
+	if ee, ok := err.(*exec.ExitError); !ok || ee.ExitCode() != 3 {
+		t.Fatalf("Expected exit code 3, got %v", err)
+	}
+	if stdout.String() != "pods\n" || !strings.Contains(stderr.String(), "warning\n") {
+		t.Fatalf("Output does not match: got %q, %q", stdout.String(), stderr.String())
+	}
+	if out, err := helper(e, "zeros=5000").Output(); err != nil || len(out) != 5000 {
+		t.Fatalf("Expected 5000 bytes of output, got %d, %v", len(out), err)
+	}
+	if out, err := helper(e, "get", "secret", "stdout=hunter2").Output(); err != nil || string(out) != "hunter2\n" {
+		t.Fatalf("Expected the output of the sensitive command, got %q, %v", out, err)
+	}
+	if err := e.Command("sc-trace-missing").Run(); err == nil {
+		t.Fatalf("Expected an error running a missing program")
+	}
+
+	spans, err := Read(r.Path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading trace: %v", err)
+	}
+	if len(spans) != 4 {
+		t.Fatalf("Expected 4 spans, got %d", len(spans))
+	}
+	if s := spans[0]; s.Kind != Exec || s.Name != os.Args[0] || s.ExitCode != 3 || s.Stdout != "pods\n" || !strings.Contains(s.Stderr, "warning\n") || s.Duration <= 0 {
+		t.Fatalf("First span does not match: got %+v", s)
+	}
+	if s := spans[1]; len(s.Stdout) != MaxOutput || !s.Truncated {
+		t.Fatalf("Expected output cut at %d bytes, got %d, %v", MaxOutput, len(s.Stdout), s.Truncated)
+	}
+	if s := spans[2]; !s.Redacted || s.Stdout != "" {
+		t.Fatalf("Expected redacted output, got %+v", s)
+	}
+	if s := spans[3]; s.ExitCode != -1 || s.Error == "" {
+		t.Fatalf("Expected a start error, got %+v", s)
+	}
+}
+
+// TestTransport tests that API calls are recorded with their status code,
+// without their query.
+func TestTransport(t *testing.T) {
+	r, cleanup := newTestRecorder(t)
+	defer cleanup()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{Recorder: r}}
+	resp, err := client.Get(srv.URL + "/v1/projects?access_token=x")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	spans, err := Read(r.Path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading trace: %v", err)
+	}
+	host := strings.TrimPrefix(srv.URL, "http://")
+	if len(spans) != 1 || spans[0].Kind != HTTP || spans[0].Name != "GET "+host || spans[0].String() != "GET "+host+" /v1/projects" || spans[0].ExitCode != 403 {
+		t.Fatalf("Spans do not match: got %+v", spans)
+	}
+	if !spans[0].failed() {
+		t.Fatalf("Expected a failed call")
+	}
+}
+
+// TestSummary tests that the phases are listed, the calls totalled by name,
+// slowest first, and the slowest calls listed.
+func TestSummary(t *testing.T) {
+	start := time.Now()
+	spans := []*Span{
+		{Kind: Exec, Name: "kubectl", Args: []string{"apply"}, Start: start, Duration: 2 * time.Second},
+		{Kind: Exec, Name: "gcloud", Args: []string{"services", "enable"}, Start: start, Duration: 5 * time.Second},
+		{Kind: Exec, Name: "kubectl", Args: []string{"get", "pods"}, Start: start, Duration: 4 * time.Second, ExitCode: 1},
+		{Kind: Phase, Name: "install/deploy", Start: start, Duration: 9 * time.Second, Error: "failed"},
+	}
+	var out bytes.Buffer
+	Summary(&out, spans, 10*time.Second)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		"phases:",
+		"9s  failed  install/deploy",
+		"traced 3 calls in 10s",
+		"CALLS  FAILED  TIME  SHARE",
+		"2      1       6s    60%    kubectl",
+		"1      0       5s    50%    gcloud",
+		"slowest calls:",
+		"5s  0  gcloud services enable",
+		"4s  1  kubectl get pods",
+		"2s  0  kubectl apply",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Summary does not match: got\n%s", out.String())
+	}
+	for i := range want {
+		if strings.TrimSpace(lines[i]) != want[i] {
+			t.Fatalf("Line %d does not match: got %q; want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+// TestExecutorKill tests that killing a traced command stops the program
+// it runs, which the span records, instead of leaving it running.
+func TestExecutorKill(t *testing.T) {
+	r, cleanup := newTestRecorder(t)
+	defer cleanup()
+	e, err := NewExecutor(execx.OS{}, r)
+	if err != nil {
+		t.Fatalf("Unexpected error creating executor: %v", err)
+	}
+
+	cmd := helper(e, "stdout=started", "sleep=1m")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Unexpected error starting the command: %v", err)
+	}
+	// The program holds the pipe open until it is stopped.
+	line := make([]byte, len("started\n"))
+	if _, err := io.ReadFull(stdout, line); err != nil {
+		t.Fatalf("Unexpected error reading the output: %v", err)
+	}
+	start := time.Now()
+	execx.Kill(cmd)
+	ioutil.ReadAll(stdout)
+	cmd.Wait()
+	if d := time.Since(start); d > 4*time.Second {
+		t.Fatalf("Expected the program to stop on the SIGTERM, took %v", d)
+	}
+
+	spans, err := Read(r.Path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading trace: %v", err)
+	}
+	if len(spans) != 1 || spans[0].ExitCode != -1 {
+		t.Fatalf("Expected the span of the stopped program, got %+v", spans)
+	}
+}
+
+// TestExport tests that the spans are sent as the children of a root span,
+// itself the child of the TRACEPARENT span, and the calls of a phase as its
+// children, with the OTLP/HTTP JSON protocol and the headers and
+// compression of the environment.
+func TestExport(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/traces" || req.Header.Get("Content-Type") != "application/json" ||
+			req.Header.Get("Authorization") != "Bearer t=k" || req.Header.Get("Content-Encoding") != "gzip" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		r, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r).Decode(&got)
+	}))
+	defer srv.Close()
+	env := map[string]string{
+		"OTEL_EXPORTER_OTLP_HEADERS":     "Authorization=Bearer%20t%3Dk",
+		"OTEL_EXPORTER_OTLP_COMPRESSION": "gzip",
+		"OTEL_RESOURCE_ATTRIBUTES":       "service.name=deploy,ci.job=42",
+		"TRACEPARENT":                    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+	e, err := NewExporter(srv.URL+"/", "sc", func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("Unexpected error creating the exporter: %v", err)
+	}
+
+	start := time.Unix(100, 0)
+	spans := []*Span{
+		{Kind: Phase, Name: "install/deploy", Start: start, Duration: 2 * time.Second, Error: "failed"},
+		{Kind: Exec, Name: "kubectl", Args: []string{"apply", "-f", "dir"}, Start: start.Add(time.Second), Duration: time.Second, ExitCode: 1},
+		{Kind: HTTP, Name: "GET example.com", Args: []string{"/v1"}, Start: start.Add(2 * time.Second), Duration: time.Second, ExitCode: 200},
+	}
+	if err := e.Export(http.DefaultTransport, "install", start, start.Add(time.Minute), true, spans); err != nil {
+		t.Fatalf("Unexpected error exporting: %v", err)
+	}
+
+	b, _ := json.Marshal(got)
+	var req struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []otlpAttribute `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []otlpSpan `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(b, &req); err != nil || len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Unexpected request: %s", b)
+	}
+	out := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(out) != 4 {
+		t.Fatalf("Expected 4 spans, got %d", len(out))
+	}
+	root, phase, cmd, call := out[0], out[1], out[2], out[3]
+	if root.Name != "install" || root.ParentSpanID != "00f067aa0ba902b7" || root.Status.Code != otlpStatusError || root.StartTimeUnixNano != "100000000000" || root.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("Root span does not match: got %+v", root)
+	}
+	if phase.ParentSpanID != root.SpanID || phase.Status.Message != "failed" || phase.Attributes[0].Key != "sc.phase" {
+		t.Fatalf("Phase span does not match: got %+v", phase)
+	}
+	if cmd.TraceID != root.TraceID || cmd.ParentSpanID != phase.SpanID || cmd.Name != "kubectl" || cmd.EndTimeUnixNano != "102000000000" || cmd.Status.Code != otlpStatusError {
+		t.Fatalf("Command span does not match: got %+v", cmd)
+	}
+	if call.ParentSpanID != root.SpanID || call.Kind != otlpKindClient || call.Status.Code != 0 || len(call.Attributes) != 3 || *call.Attributes[2].Value.IntValue != "200" {
+		t.Fatalf("API call span does not match: got %+v", call)
+	}
+
+	var resource []string
+	for _, a := range req.ResourceSpans[0].Resource.Attributes {
+		resource = append(resource, a.Key+"="+*a.Value.StringValue)
+	}
+	if got, want := strings.Join(resource, ","), "service.name=deploy,ci.job=42"; got != want {
+		t.Fatalf("Resource does not match: got %q; want %q", got, want)
+	}
+
+	srv.Close()
+	if err := e.Export(http.DefaultTransport, "install", start, start, false, nil); err == nil {
+		t.Fatalf("Expected an error exporting to a closed collector")
+	}
+}
+
+// TestNewExporter tests that the endpoint of the flag takes precedence over
+// the environment, and that the OTEL_* variables specific to the traces
+// take precedence over the general ones.
+func TestNewExporter(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		endpoint string
+		env      map[string]string
+		wantURL  string
+		wantErr  string
+	}{
+		{name: "not set"},
+		{name: "flag", endpoint: "http://flag:4318", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://env:4318"}, wantURL: "http://flag:4318/v1/traces"},
+		{name: "env", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://env:4318/"}, wantURL: "http://env:4318/v1/traces"},
+		{name: "traces env", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://env:4318", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://traces:4318/otlp"}, wantURL: "http://traces:4318/otlp"},
+		{name: "disabled", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://env:4318", "OTEL_SDK_DISABLED": "true"}},
+		{name: "no exporter", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://env:4318", "OTEL_TRACES_EXPORTER": "none"}},
+		{name: "grpc", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://env:4317", "OTEL_EXPORTER_OTLP_PROTOCOL": "grpc"}, wantErr: "not supported"},
+		{name: "bad header", endpoint: "http://flag:4318", env: map[string]string{"OTEL_EXPORTER_OTLP_HEADERS": "token"}, wantErr: "OTEL_EXPORTER_OTLP_HEADERS"},
+		{name: "bad timeout", endpoint: "http://flag:4318", env: map[string]string{"OTEL_EXPORTER_OTLP_TRACES_TIMEOUT": "5s"}, wantErr: "OTEL_EXPORTER_OTLP_TIMEOUT"},
+	} {
+		e, err := NewExporter(tc.endpoint, "sc", func(k string) string { return tc.env[k] })
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("%s: expected an error containing %q, got %v", tc.name, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if tc.wantURL == "" {
+			if e != nil {
+				t.Fatalf("%s: expected no exporter, got %+v", tc.name, e)
+			}
+			continue
+		}
+		if e == nil || e.URL != tc.wantURL {
+			t.Fatalf("%s: URL does not match: got %+v; want %q", tc.name, e, tc.wantURL)
+		}
+	}
+
+	env := map[string]string{"OTEL_EXPORTER_OTLP_TIMEOUT": "1000", "OTEL_EXPORTER_OTLP_TRACES_TIMEOUT": "250", "OTEL_SERVICE_NAME": "pipeline"}
+	e, err := NewExporter("http://flag:4318", "sc", func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e.Timeout != 250*time.Millisecond || *e.Resource[0].Value.StringValue != "pipeline" {
+		t.Fatalf("Exporter does not match: got timeout %v, resource %+v", e.Timeout, e.Resource)
+	}
+}
+
+// TestParseTraceparent tests that only valid W3C traceparents are used.
+func TestParseTraceparent(t *testing.T) {
+	for _, tc := range []struct {
+		value, traceID, spanID string
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future", "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"},
+		{"", "", ""},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", "", ""},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "", ""},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", "", ""},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", "", ""},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", "", ""},
+	} {
+		traceID, spanID := parseTraceparent(tc.value)
+		if traceID != tc.traceID || spanID != tc.spanID {
+			t.Fatalf("IDs of %q do not match: got %q, %q; want %q, %q", tc.value, traceID, spanID, tc.traceID, tc.spanID)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"net/http"
+	"time"
+)
+
+// Transport is an http.RoundTripper tracing the API calls of Base in the
+// trace file of Recorder. The duration of a call ends with the headers of
+// the response, before its body is read.
+type Transport struct {
+	// Base makes the calls, http.DefaultTransport if nil.
+	Base http.RoundTripper
+
+	// Recorder is the trace file the spans are appended to.
+	Recorder *Recorder
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	// The query is left out, it may hold credentials.
+	s := &Span{Kind: HTTP, Name: req.Method + " " + req.URL.Host, Args: []string{req.URL.Path}, Start: time.Now()}
+	resp, err := base.RoundTrip(req)
+	s.Duration = time.Since(s.Start)
+	if err != nil {
+		s.Error, _ = truncate([]byte(err.Error()))
+	} else {
+		s.ExitCode = resp.StatusCode
+	}
+	t.Recorder.Record(s)
+	return resp, err
+}
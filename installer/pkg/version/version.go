@@ -24,6 +24,11 @@ import (
 // version of 'sc'
 const scVersion = "0.1.1"
 
+// Version returns the version number of 'sc', e.g. "0.1.1".
+func Version() string {
+	return scVersion
+}
+
 func GetVersion() string {
 	return fmt.Sprintf("sc version %s %s/%s", scVersion, runtime.GOOS, runtime.GOARCH)
 }
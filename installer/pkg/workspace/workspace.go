@@ -0,0 +1,265 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workspace manages the directories the operations of sc generate
+// their files in, e.g. the manifests and certificates of an install.
+//
+// The workspaces of an operation on a cluster live under the directory of
+// its scope, the kubectl context and namespace the operation changes:
+//
+//	<root>/<context>/<namespace>/<operation>-<time>-<pid>-<n>
+//
+// so that operations running side by side, e.g. installs into many
+// clusters, never share files, and the files of an operation are found by
+// the cluster, namespace and time it ran. Exclusive workspaces hold the
+// lock of their scope, so that two operations changing the same namespace
+// do not run at once.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Policy decides whether a workspace is removed when its operation is done.
+type Policy string
+
+// Cleanup policies.
+const (
+	// Always removes the workspace.
+	Always Policy = "always"
+
+	// OnSuccess removes the workspace if the operation succeeded and keeps
+	// it for troubleshooting otherwise.
+	OnSuccess Policy = "on-success"
+
+	// Never keeps the workspace.
+	Never Policy = "never"
+)
+
+// Policies are the valid cleanup policies.
+var Policies = []Policy{Always, OnSuccess, Never}
+
+// ParsePolicy returns the cleanup policy named s.
+func ParsePolicy(s string) (Policy, error) {
+	for _, p := range Policies {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid cleanup policy %q, must be always, on-success or never", s)
+}
+
+// lockName is the name of the lock file of a scope.
+const lockName = ".lock"
+
+// ErrLocked is the error of locking a scope another process holds the lock
+// of.
+var ErrLocked = errors.New("locked by another process")
+
+// seq numbers the workspaces of the process, so that its managers never
+// share one.
+var seq uint64
+
+// unsafeRE matches the characters not kept in the names of the directories
+// of a scope.
+var unsafeRE = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Scope returns the scope of the operations changing namespace ns of the
+// cluster of kubectl context kubeContext, the relative directory of their
+// workspaces and lock. Operations on cluster-scoped objects, e.g. brokers,
+// pass an empty namespace.
+func Scope(kubeContext, ns string) string {
+	return filepath.Join(segment(kubeContext), segment(ns))
+}
+
+// segment returns s as the name of a directory, e.g. replacing the colons
+// and slashes of EKS context names.
+func segment(s string) string {
+	s = strings.Trim(unsafeRE.ReplaceAllString(s, "_"), ".")
+	if s == "" {
+		return "_"
+	}
+	return s
+}
+
+// Options configure a workspace.
+type Options struct {
+	// Exclusive holds the lock of the scope while the workspace is in
+	// use, failing if another process holds it. The workspaces of a
+	// Manager share its locks, so operations may nest.
+	Exclusive bool
+
+	// Cleanup is the cleanup policy of the workspace, unless the Manager
+	// overrides it.
+	Cleanup Policy
+
+	// Sensitive workspaces hold credentials, e.g. service account keys,
+	// and are always removed.
+	Sensitive bool
+}
+
+// Manager creates the workspaces of the operations of sc. It is safe for
+// concurrent use.
+type Manager struct {
+	// Root is the directory holding the workspaces.
+	Root string
+
+	// Cleanup overrides the cleanup policies of the workspaces which are
+	// not sensitive, if set.
+	Cleanup Policy
+
+	mu    sync.Mutex
+	locks map[string]*lock
+	now   func() time.Time
+}
+
+// lock is a lock of a scope held by the process.
+type lock struct {
+	f     *os.File
+	count int
+}
+
+// DefaultRoot is the default directory of the workspaces, under /tmp or
+// %TEMP%.
+func DefaultRoot() string {
+	return filepath.Join(os.TempDir(), "sc-workspaces")
+}
+
+// NewManager returns a Manager of the workspaces under root, overriding
+// their cleanup policies with cleanup if not empty.
+func NewManager(root string, cleanup Policy) *Manager {
+	return &Manager{Root: root, Cleanup: cleanup, locks: make(map[string]*lock), now: time.Now}
+}
+
+// Workspace is the directory of an operation.
+type Workspace struct {
+	// Dir is the absolute path of the directory.
+	Dir string
+
+	m      *Manager
+	scope  string
+	policy Policy
+	locked bool
+	once   sync.Once
+}
+
+// Create creates the workspace of operation in scope.
+func (m *Manager) Create(scope, operation string, opts Options) (*Workspace, error) {
+	root, err := filepath.Abs(m.Root)
+	if err != nil {
+		return nil, err
+	}
+	parent := filepath.Join(root, scope)
+	// The workspaces hold private keys, only the user may read them.
+	if err := os.MkdirAll(parent, 0700); err != nil {
+		return nil, fmt.Errorf("error creating workspace dir: %v", err)
+	}
+
+	w := &Workspace{m: m, scope: parent, policy: opts.Cleanup}
+	if m.Cleanup != "" {
+		w.policy = m.Cleanup
+	}
+	if opts.Sensitive || w.policy == "" {
+		w.policy = Always
+	}
+	if opts.Exclusive {
+		if err := m.lock(parent); err != nil {
+			return nil, fmt.Errorf("another sc operation is running in %s: %v", scope, err)
+		}
+		w.locked = true
+	}
+
+	n := atomic.AddUint64(&seq, 1)
+	name := fmt.Sprintf("%s-%s-%d-%d", segment(operation), m.now().UTC().Format("20060102-150405"), os.Getpid(), n)
+	w.Dir = filepath.Join(parent, name)
+	if err := os.Mkdir(w.Dir, 0700); err != nil {
+		if w.locked {
+			m.unlock(parent)
+		}
+		return nil, fmt.Errorf("error creating workspace dir: %v", err)
+	}
+	return w, nil
+}
+
+// Done ends the operation of w, which failed if err is not nil: it removes
+// w as its cleanup policy says and releases the lock of its scope. Later
+// calls do nothing.
+func (w *Workspace) Done(err error) {
+	w.once.Do(func() {
+		switch {
+		case w.policy == Always, w.policy == OnSuccess && err == nil:
+			os.RemoveAll(w.Dir)
+		}
+		if w.locked {
+			w.m.unlock(w.scope)
+		}
+	})
+}
+
+// lock locks the scope in dir, or counts one more holder if the process
+// holds its lock already.
+func (m *Manager) lock(dir string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l, ok := m.locks[dir]; ok {
+		l.count++
+		return nil
+	}
+	path := filepath.Join(dir, lockName)
+	f, err := lockFile(path)
+	if err == ErrLocked {
+		if b, rerr := ioutil.ReadFile(path); rerr == nil && len(b) > 0 {
+			return fmt.Errorf("locked by process %s", strings.TrimSpace(string(b)))
+		}
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("error locking %s: %v", path, err)
+	}
+	// Name the holder in the errors of the other processes.
+	f.Truncate(0)
+	f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	m.locks[dir] = &lock{f: f, count: 1}
+	return nil
+}
+
+// unlock releases a hold of the lock of the scope in dir, unlocking it with
+// the last one.
+func (m *Manager) unlock(dir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l, ok := m.locks[dir]
+	if !ok {
+		return
+	}
+	if l.count--; l.count > 0 {
+		return
+	}
+	delete(m.locks, dir)
+	l.f.Truncate(0)
+	// Closing the file releases the lock.
+	l.f.Close()
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tempRoot(t *testing.T) string {
+	root, err := ioutil.TempDir("", "workspace")
+	if err != nil {
+		t.Fatalf("Unexpected error creating temp dir: %v", err)
+	}
+	return root
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// TestCreate tests that workspaces are laid out by scope, operation and
+// time, and never shared.
+func TestCreate(t *testing.T) {
+	root := tempRoot(t)
+	defer os.RemoveAll(root)
+	m := NewManager(root, "")
+	m.now = func() time.Time { return time.Date(2018, 5, 1, 12, 30, 0, 0, time.UTC) }
+
+	scope := Scope("arn:aws:eks:us-east-1:123:cluster/prod", "catalog")
+	if want := filepath.Join("arn_aws_eks_us-east-1_123_cluster_prod", "catalog"); scope != want {
+		t.Fatalf("Scope does not match: got %q; want %q", scope, want)
+	}
+	if got, want := Scope("", ""), filepath.Join("_", "_"); got != want {
+		t.Fatalf("Empty scope does not match: got %q; want %q", got, want)
+	}
+
+	a, err := m.Create(scope, "install", Options{Cleanup: Never})
+	if err != nil {
+		t.Fatalf("Unexpected error creating workspace: %v", err)
+	}
+	b, err := m.Create(scope, "install", Options{Cleanup: Never})
+	if err != nil {
+		t.Fatalf("Unexpected error creating workspace: %v", err)
+	}
+	prefix := filepath.Join(root, scope, fmt.Sprintf("install-20180501-123000-%d-", os.Getpid()))
+	if !strings.HasPrefix(a.Dir, prefix) {
+		t.Fatalf("Dir does not match: got %q; want prefix %q", a.Dir, prefix)
+	}
+	if a.Dir == b.Dir {
+		t.Fatalf("Expected distinct dirs, got %q twice", a.Dir)
+	}
+	fi, err := os.Stat(a.Dir)
+	if err != nil || fi.Mode().Perm() != 0700 {
+		t.Fatalf("Expected a dir only the user may read, got %v, %v", fi, err)
+	}
+}
+
+// TestDone tests that the cleanup policies, overridden by the Manager
+// except for sensitive workspaces, decide which workspaces are removed.
+func TestDone(t *testing.T) {
+	root := tempRoot(t)
+	defer os.RemoveAll(root)
+	failed := errors.New("failed")
+
+	for _, tc := range []struct {
+		override Policy
+		opts     Options
+		err      error
+		kept     bool
+	}{
+		{"", Options{}, nil, false},
+		{"", Options{Cleanup: Always}, failed, false},
+		{"", Options{Cleanup: OnSuccess}, nil, false},
+		{"", Options{Cleanup: OnSuccess}, failed, true},
+		{"", Options{Cleanup: Never}, nil, true},
+		{Never, Options{Cleanup: Always}, nil, true},
+		{Always, Options{Cleanup: Never}, failed, false},
+		{Never, Options{Cleanup: Never, Sensitive: true}, failed, false},
+	} {
+		m := NewManager(root, tc.override)
+		w, err := m.Create("scope", "op", tc.opts)
+		if err != nil {
+			t.Fatalf("Unexpected error creating workspace: %v", err)
+		}
+		w.Done(tc.err)
+		if got := exists(w.Dir); got != tc.kept {
+			t.Fatalf("%s %+v %v: kept does not match: got %v; want %v", tc.override, tc.opts, tc.err, got, tc.kept)
+		}
+	}
+}
+
+// TestLock tests that exclusive workspaces of a scope nest in a Manager,
+// fail in another one, as in another process, until the last is done, and
+// leave other scopes alone.
+func TestLock(t *testing.T) {
+	root := tempRoot(t)
+	defer os.RemoveAll(root)
+	m := NewManager(root, "")
+	other := NewManager(root, "")
+
+	outer, err := m.Create("ctx/catalog", "dr-restore", Options{Exclusive: true})
+	if err != nil {
+		t.Fatalf("Unexpected error creating workspace: %v", err)
+	}
+	inner, err := m.Create("ctx/catalog", "install", Options{Exclusive: true})
+	if err != nil {
+		t.Fatalf("Unexpected error creating nested workspace: %v", err)
+	}
+	if _, err := other.Create("ctx/catalog", "install", Options{Exclusive: true}); err == nil || !strings.Contains(err.Error(), fmt.Sprintf("locked by process %d", os.Getpid())) {
+		t.Fatalf("Expected an error naming the holder, got %v", err)
+	}
+	if _, err := other.Create("ctx/catalog", "footprint", Options{}); err != nil {
+		t.Fatalf("Unexpected error creating shared workspace: %v", err)
+	}
+	if _, err := other.Create("ctx/team", "install", Options{Exclusive: true}); err != nil {
+		t.Fatalf("Unexpected error locking another scope: %v", err)
+	}
+
+	inner.Done(nil)
+	if _, err := other.Create("ctx/catalog", "install", Options{Exclusive: true}); err == nil {
+		t.Fatalf("Expected the outer workspace to hold the lock")
+	}
+	outer.Done(nil)
+	outer.Done(nil)
+	if _, err := other.Create("ctx/catalog", "install", Options{Exclusive: true}); err != nil {
+		t.Fatalf("Unexpected error locking the released scope: %v", err)
+	}
+}
+
+// TestParsePolicy tests that only the known policies are accepted.
+func TestParsePolicy(t *testing.T) {
+	if p, err := ParsePolicy("on-success"); err != nil || p != OnSuccess {
+		t.Fatalf("Expected on-success, got %q, %v", p, err)
+	}
+	if _, err := ParsePolicy("sometimes"); err == nil {
+		t.Fatalf("Expected an error for an unknown policy")
+	}
+}